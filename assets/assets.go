@@ -0,0 +1,331 @@
+// Package assets bundles local files referenced by a planning document, such
+// as wireframes and architecture diagrams, with generated output.
+//
+// Documents usually reference diagrams by a path relative to the JSON source.
+// Once output is written elsewhere those paths break. Bundle resolves each
+// local reference against the source directory and either copies the file
+// into an asset directory next to the output or inlines it as a base64 data
+// URI, rewriting the reference in place so the renderer emits a working link.
+// Remote URLs are left untouched; missing files are reported.
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Mode selects how local assets are bundled.
+type Mode string
+
+const (
+	// ModeCopy copies assets into a directory next to the output.
+	ModeCopy Mode = "copy"
+	// ModeInline embeds assets as base64 data URIs, producing a single
+	// self-contained file. Intended for output that is converted to HTML.
+	ModeInline Mode = "inline"
+)
+
+// DefaultDir is the asset directory used by ModeCopy, relative to the output.
+const DefaultDir = "assets"
+
+// Ref is a reference to a possibly local file within a document.
+type Ref struct {
+	// Field is the JSON path of the reference, used in reports.
+	Field string
+
+	// Value points at the document field holding the path or URL, so that
+	// Bundle can rewrite it.
+	Value *string
+}
+
+// Options configure Bundle.
+type Options struct {
+	Mode Mode
+
+	// SourceDir is the directory relative references are resolved against,
+	// normally the directory of the input document.
+	SourceDir string
+
+	// OutputDir is the directory the generated output is written to.
+	OutputDir string
+
+	// Dir is the asset directory within OutputDir for ModeCopy. Defaults to
+	// DefaultDir.
+	Dir string
+
+	// Converter, if set, exports draw.io and Excalidraw sources to images
+	// that are bundled in place of the source file.
+	Converter *Converter
+}
+
+// Asset is a bundled file.
+type Asset struct {
+	Field  string `json:"field"`
+	Source string `json:"source"` // Resolved source path
+	Link   string `json:"link"`   // Rewritten reference (relative path or data URI)
+
+	// Converted is true if the source was a diagram exported to an image.
+	Converted bool `json:"converted,omitempty"`
+}
+
+// Missing is a local reference whose file does not exist.
+type Missing struct {
+	Field string `json:"field"`
+	Path  string `json:"path"`
+}
+
+// ConversionError is a diagram source that could not be exported. The
+// source file is bundled unconverted.
+type ConversionError struct {
+	Field string `json:"field"`
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// Result reports the outcome of Bundle.
+type Result struct {
+	Bundled          []Asset           `json:"bundled,omitempty"`
+	Missing          []Missing         `json:"missing,omitempty"`
+	ConversionErrors []ConversionError `json:"conversionErrors,omitempty"`
+}
+
+// IsLocal reports whether ref is a file path rather than a URL or fragment.
+func IsLocal(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "//") {
+		return false
+	}
+	if i := strings.Index(ref, ":"); i > 1 {
+		// A URL scheme such as https: or data:. Single-letter prefixes are
+		// Windows drive letters.
+		scheme := ref[:i]
+		for _, r := range scheme {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.') {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Check returns the local references in refs whose files do not exist,
+// resolving relative paths against sourceDir. URLs are not checked.
+func Check(refs []Ref, sourceDir string) []Missing {
+	var missing []Missing
+	for _, ref := range refs {
+		if ref.Value == nil || !IsLocal(*ref.Value) {
+			continue
+		}
+		info, err := os.Stat(resolve(sourceDir, *ref.Value))
+		if err != nil || info.IsDir() {
+			missing = append(missing, Missing{Field: ref.Field, Path: *ref.Value})
+		}
+	}
+	return missing
+}
+
+// resolve returns the file path of the local reference ref, relative to
+// sourceDir unless absolute.
+func resolve(sourceDir, ref string) string {
+	if !filepath.IsAbs(ref) {
+		ref = filepath.Join(sourceDir, filepath.FromSlash(ref))
+	}
+	return filepath.Clean(ref)
+}
+
+// Bundle copies or inlines every local file referenced by refs and rewrites
+// the references. Missing files are reported in the Result and their
+// references left unchanged. An error is returned only for I/O failures.
+func Bundle(refs []Ref, opts Options) (*Result, error) {
+	if opts.Mode != ModeCopy && opts.Mode != ModeInline {
+		return nil, fmt.Errorf("unknown asset mode %q (use %s or %s)", opts.Mode, ModeCopy, ModeInline)
+	}
+	dir := opts.Dir
+	if dir == "" {
+		dir = DefaultDir
+	}
+
+	b := &bundler{opts: opts, dir: dir, links: map[string]string{}, names: map[string]string{}}
+	result := &Result{}
+	for _, ref := range refs {
+		if ref.Value == nil || !IsLocal(*ref.Value) {
+			continue
+		}
+		src := resolve(opts.SourceDir, *ref.Value)
+		info, err := os.Stat(src)
+		if errors.Is(err, os.ErrNotExist) || (err == nil && info.IsDir()) {
+			result.Missing = append(result.Missing, Missing{Field: ref.Field, Path: *ref.Value})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading asset %s: %w", src, err)
+		}
+
+		file, name, converted := src, filepath.Base(src), false
+		if opts.Converter != nil && DiagramSourceKind(src) != "" {
+			image, err := opts.Converter.Convert(src)
+			if err != nil {
+				result.ConversionErrors = append(result.ConversionErrors,
+					ConversionError{Field: ref.Field, Path: *ref.Value, Error: err.Error()})
+			} else {
+				file, converted = image, true
+				name = diagramBaseName(name) + "." + opts.Converter.Format()
+			}
+		}
+
+		link, err := b.link(file, name)
+		if err != nil {
+			return nil, err
+		}
+		*ref.Value = link
+		result.Bundled = append(result.Bundled, Asset{Field: ref.Field, Source: src, Link: link, Converted: converted})
+	}
+	return result, nil
+}
+
+type bundler struct {
+	opts  Options
+	dir   string
+	links map[string]string // source path -> link
+	names map[string]string // asset file name -> source path
+}
+
+// link returns the rewritten reference for src, bundling it under the given
+// file name on first use.
+func (b *bundler) link(src, name string) (string, error) {
+	if link, ok := b.links[src]; ok {
+		return link, nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("reading asset %s: %w", src, err)
+	}
+
+	var link string
+	if b.opts.Mode == ModeInline {
+		link = "data:" + mediaType(src, data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+	} else {
+		name = b.fileName(src, name, data)
+		dst := filepath.Join(b.opts.OutputDir, b.dir, name)
+		if err := writeIfChanged(dst, data); err != nil {
+			return "", err
+		}
+		link = path.Join(filepath.ToSlash(b.dir), name)
+	}
+	b.links[src] = link
+	return link, nil
+}
+
+// fileName returns the normalized name for src within the asset directory.
+// Distinct sources with the same normalized name are disambiguated with a
+// content hash.
+func (b *bundler) fileName(src, name string, data []byte) string {
+	name = Normalize(name)
+	if prev, ok := b.names[name]; ok && prev != src {
+		sum := sha256.Sum256(data)
+		ext := path.Ext(name)
+		name = strings.TrimSuffix(name, ext) + "-" + hex.EncodeToString(sum[:4]) + ext
+	}
+	b.names[name] = src
+	return name
+}
+
+// Normalize converts a file name to lowercase, replacing runs of characters
+// other than letters, digits, dots, and hyphens with a single hyphen, so that
+// bundled asset links need no escaping.
+func Normalize(name string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			sb.WriteRune(r)
+			hyphen = r == '-'
+			continue
+		}
+		if !hyphen && sb.Len() > 0 {
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	s := strings.Trim(sb.String(), "-")
+	if s == "" || strings.Trim(s, ".") == "" {
+		return "asset"
+	}
+	return s
+}
+
+// diagramBaseName strips the diagram source extension from a file name.
+func diagramBaseName(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".drawio.xml", ".excalidraw.json", ".drawio", ".dio", ".excalidraw"} {
+		if strings.HasSuffix(lower, ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// IsImage reports whether ref, a path, URL, or data URI, refers to an image
+// that can be embedded in rendered output.
+func IsImage(ref string) bool {
+	if strings.HasPrefix(ref, "data:") {
+		return strings.HasPrefix(ref, "data:image/")
+	}
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	switch strings.ToLower(path.Ext(ref)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp":
+		return true
+	}
+	return false
+}
+
+// Markdown returns a markdown image for image references and a link
+// otherwise.
+func Markdown(title, ref string) string {
+	if IsImage(ref) {
+		return fmt.Sprintf("![%s](%s)", title, ref)
+	}
+	return fmt.Sprintf("[%s](%s)", title, ref)
+}
+
+func mediaType(name string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".svg":
+		return "image/svg+xml"
+	case ".drawio":
+		return "application/vnd.jgraph.mxfile"
+	case ".excalidraw":
+		return "application/json"
+	}
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// writeIfChanged writes data to dst unless dst already has that content.
+func writeIfChanged(dst string, data []byte) error {
+	if existing, err := os.ReadFile(dst); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return fmt.Errorf("creating asset directory: %w", err)
+	}
+	if err := os.WriteFile(dst, data, 0600); err != nil {
+		return fmt.Errorf("writing asset %s: %w", dst, err)
+	}
+	return nil
+}
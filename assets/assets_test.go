@@ -0,0 +1,149 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsLocal(t *testing.T) {
+	tests := map[string]bool{
+		"diagrams/arch.png":          true,
+		"./arch.svg":                 true,
+		"/abs/arch.svg":              true,
+		`C:\docs\arch.png`:           true,
+		"my notes: draft.png":        true,
+		"https://example.com/a.png":  false,
+		"data:image/png;base64,AAAA": false,
+		"mailto:a@example.com":       false,
+		"//cdn.example.com/a.png":    false,
+		"#section":                   false,
+		"":                           false,
+	}
+	for ref, want := range tests {
+		if got := IsLocal(ref); got != want {
+			t.Errorf("IsLocal(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"System Diagram.PNG":   "system-diagram.png",
+		"flow__v2 (final).svg": "flow-v2-final-.svg",
+		"already-ok.drawio":    "already-ok.drawio",
+		"???":                  "asset",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBundleCopy(t *testing.T) {
+	src := t.TempDir()
+	out := t.TempDir()
+	writeFile(t, filepath.Join(src, "img", "Arch Diagram.svg"), "<svg/>")
+	writeFile(t, filepath.Join(src, "other", "arch diagram.svg"), "<svg>2</svg>")
+
+	a := "img/Arch Diagram.svg"
+	b := "img/Arch Diagram.svg"
+	c := "other/arch diagram.svg"
+	remote := "https://example.com/x.png"
+	missing := "img/missing.png"
+	refs := []Ref{
+		{Field: "a", Value: &a},
+		{Field: "b", Value: &b},
+		{Field: "c", Value: &c},
+		{Field: "remote", Value: &remote},
+		{Field: "missing", Value: &missing},
+	}
+
+	result, err := Bundle(refs, Options{Mode: ModeCopy, SourceDir: src, OutputDir: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a != "assets/arch-diagram.svg" || b != a {
+		t.Errorf("rewritten links = %q, %q", a, b)
+	}
+	if c == a || !strings.HasPrefix(c, "assets/arch-diagram-") {
+		t.Errorf("colliding name not disambiguated: %q", c)
+	}
+	if remote != "https://example.com/x.png" || missing != "img/missing.png" {
+		t.Errorf("non-local references changed: %q, %q", remote, missing)
+	}
+	if len(result.Bundled) != 3 {
+		t.Errorf("Bundled = %+v", result.Bundled)
+	}
+	if len(result.Missing) != 1 || result.Missing[0].Field != "missing" {
+		t.Errorf("Missing = %+v", result.Missing)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(c)))
+	if err != nil || string(data) != "<svg>2</svg>" {
+		t.Errorf("copied asset = %q, %v", data, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "img", "home.png"), "png")
+
+	found := "img/home.png"
+	missing := "img/checkout.png"
+	dir := "img"
+	remote := "https://example.com/x.png"
+	refs := []Ref{
+		{Field: "found", Value: &found},
+		{Field: "missing", Value: &missing},
+		{Field: "dir", Value: &dir},
+		{Field: "remote", Value: &remote},
+	}
+
+	got := Check(refs, src)
+	if len(got) != 2 || got[0].Field != "missing" || got[1].Field != "dir" {
+		t.Errorf("Check = %+v, want the missing file and the directory", got)
+	}
+}
+
+func TestBundleInline(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "a.svg"), "<svg/>")
+
+	ref := "a.svg"
+	if _, err := Bundle([]Ref{{Field: "a", Value: &ref}}, Options{Mode: ModeInline, SourceDir: src}); err != nil {
+		t.Fatal(err)
+	}
+	if ref != "data:image/svg+xml;base64,PHN2Zy8+" {
+		t.Errorf("inlined ref = %q", ref)
+	}
+
+	if _, err := Bundle(nil, Options{Mode: "zip"}); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	if got := Markdown("Arch", "assets/arch.png"); got != "![Arch](assets/arch.png)" {
+		t.Errorf("Markdown(image) = %q", got)
+	}
+	if got := Markdown("Board", "https://miro.com/board/123"); got != "[Board](https://miro.com/board/123)" {
+		t.Errorf("Markdown(link) = %q", got)
+	}
+	if got := Markdown("Flow", "data:image/png;base64,AAAA"); !strings.HasPrefix(got, "![") {
+		t.Errorf("Markdown(data URI) = %q", got)
+	}
+}
@@ -0,0 +1,210 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SourceKind identifies an editable diagram source format.
+type SourceKind string
+
+const (
+	SourceDrawio     SourceKind = "drawio"
+	SourceExcalidraw SourceKind = "excalidraw"
+)
+
+// Default converter commands. Draw.io desktop and excalidraw-brute-export-cli
+// both export a single file from the command line.
+const (
+	DefaultDrawioCommand     = "drawio"
+	DefaultExcalidrawCommand = "excalidraw-brute-export-cli"
+)
+
+// DiagramSourceKind returns the diagram source kind of a file name, or ""
+// if it is not an editable diagram source.
+func DiagramSourceKind(name string) SourceKind {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".drawio"), strings.HasSuffix(lower, ".drawio.xml"), strings.HasSuffix(lower, ".dio"):
+		return SourceDrawio
+	case strings.HasSuffix(lower, ".excalidraw"), strings.HasSuffix(lower, ".excalidraw.json"):
+		return SourceExcalidraw
+	}
+	return ""
+}
+
+// ConvertOptions configure a Converter.
+type ConvertOptions struct {
+	// Format is the output image format, "svg" (default) or "png".
+	Format string
+
+	// CacheDir holds converted images keyed by source content, so unchanged
+	// diagrams are not re-exported. Defaults to splan/diagrams under the
+	// user cache directory.
+	CacheDir string
+
+	// DrawioCommand and ExcalidrawCommand override the export CLIs.
+	DrawioCommand     string
+	ExcalidrawCommand string
+
+	// Endpoint is the base URL of a Kroki-compatible export server, such as
+	// https://kroki.io. When set, diagrams are converted by the server
+	// instead of local CLIs.
+	Endpoint string
+
+	// Timeout limits each conversion. Defaults to one minute.
+	Timeout time.Duration
+}
+
+// Converter exports draw.io and Excalidraw sources to SVG or PNG.
+type Converter struct {
+	opts   ConvertOptions
+	client *http.Client
+
+	// run executes an export command; replaced in tests.
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// NewConverter returns a Converter with defaults applied.
+func NewConverter(opts ConvertOptions) (*Converter, error) {
+	if opts.Format == "" {
+		opts.Format = "svg"
+	}
+	if opts.Format != "svg" && opts.Format != "png" {
+		return nil, fmt.Errorf("unsupported diagram format %q (use svg or png)", opts.Format)
+	}
+	if opts.CacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating cache directory: %w", err)
+		}
+		opts.CacheDir = filepath.Join(dir, "splan", "diagrams")
+	}
+	if opts.DrawioCommand == "" {
+		opts.DrawioCommand = DefaultDrawioCommand
+	}
+	if opts.ExcalidrawCommand == "" {
+		opts.ExcalidrawCommand = DefaultExcalidrawCommand
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = time.Minute
+	}
+	return &Converter{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+		run:    runCommand,
+	}, nil
+}
+
+// Format returns the output image format.
+func (c *Converter) Format() string {
+	return c.opts.Format
+}
+
+// Convert exports the diagram source at src and returns the path of the
+// cached image. Cached images are reused while the source is unchanged.
+func (c *Converter) Convert(src string) (string, error) {
+	kind := DiagramSourceKind(src)
+	if kind == "" {
+		return "", fmt.Errorf("%s is not a draw.io or Excalidraw file", src)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("reading diagram %s: %w", src, err)
+	}
+
+	sum := sha256.Sum256(append([]byte(string(kind)+"\x00"+c.opts.Format+"\x00"), data...))
+	out := filepath.Join(c.opts.CacheDir, hex.EncodeToString(sum[:16])+"."+c.opts.Format)
+	if info, err := os.Stat(out); err == nil && info.Size() > 0 {
+		return out, nil
+	}
+	if err := os.MkdirAll(c.opts.CacheDir, 0750); err != nil {
+		return "", fmt.Errorf("creating diagram cache: %w", err)
+	}
+
+	// Export to a temporary file first so a failed export never leaves a
+	// partial image in the cache.
+	tmp := out + ".tmp"
+	defer os.Remove(tmp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.Timeout)
+	defer cancel()
+	if c.opts.Endpoint != "" {
+		err = c.convertRemote(ctx, kind, data, tmp)
+	} else {
+		err = c.convertLocal(ctx, kind, src, tmp)
+	}
+	if err != nil {
+		return "", fmt.Errorf("converting %s: %w", src, err)
+	}
+	if info, err := os.Stat(tmp); err != nil || info.Size() == 0 {
+		return "", fmt.Errorf("converting %s: exporter produced no output", src)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		return "", fmt.Errorf("caching converted diagram: %w", err)
+	}
+	return out, nil
+}
+
+func (c *Converter) convertLocal(ctx context.Context, kind SourceKind, src, out string) error {
+	switch kind {
+	case SourceDrawio:
+		return c.run(ctx, c.opts.DrawioCommand, "--export", "--format", c.opts.Format, "--output", out, src)
+	case SourceExcalidraw:
+		return c.run(ctx, c.opts.ExcalidrawCommand, "--input", src, "--format", c.opts.Format, "--output", out)
+	}
+	return fmt.Errorf("unsupported diagram kind %q", kind)
+}
+
+// convertRemote posts the source to a Kroki-compatible server, which names
+// draw.io diagrams "diagramsnet".
+func (c *Converter) convertRemote(ctx context.Context, kind SourceKind, data []byte, out string) error {
+	diagramType := string(kind)
+	if kind == SourceDrawio {
+		diagramType = "diagramsnet"
+	}
+	url := strings.TrimRight(c.opts.Endpoint, "/") + "/" + diagramType + "/" + c.opts.Format
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("export server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return os.WriteFile(out, body, 0600)
+}
+
+func runCommand(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiagramSourceKind(t *testing.T) {
+	tests := map[string]SourceKind{
+		"arch.drawio":            SourceDrawio,
+		"arch.drawio.xml":        SourceDrawio,
+		"Arch.DIO":               SourceDrawio,
+		"sketch.excalidraw":      SourceExcalidraw,
+		"sketch.excalidraw.json": SourceExcalidraw,
+		"arch.svg":               "",
+	}
+	for name, want := range tests {
+		if got := DiagramSourceKind(name); got != want {
+			t.Errorf("DiagramSourceKind(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// fakeExporter records export commands and writes placeholder output.
+type fakeExporter struct {
+	calls [][]string
+	err   error
+}
+
+func (f *fakeExporter) run(_ context.Context, name string, args ...string) error {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if f.err != nil {
+		return f.err
+	}
+	for i, arg := range args {
+		if arg == "--output" {
+			return os.WriteFile(args[i+1], []byte("<svg>converted</svg>"), 0600)
+		}
+	}
+	return errors.New("no --output argument")
+}
+
+func TestConverterCachesExports(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "flow.drawio")
+	writeFile(t, src, "<mxfile/>")
+
+	c, err := NewConverter(ConvertOptions{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeExporter{}
+	c.run = fake.run
+
+	first, err := c.Convert(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Convert(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || len(fake.calls) != 1 {
+		t.Errorf("expected one export reused from cache, got %d calls (%s, %s)", len(fake.calls), first, second)
+	}
+	if fake.calls[0][0] != DefaultDrawioCommand || fake.calls[0][1] != "--export" {
+		t.Errorf("unexpected command %v", fake.calls[0])
+	}
+
+	// Changing the source invalidates the cache.
+	writeFile(t, src, "<mxfile>v2</mxfile>")
+	if _, err := c.Convert(src); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.calls) != 2 {
+		t.Errorf("expected re-export after source change, got %d calls", len(fake.calls))
+	}
+}
+
+func TestConverterEndpoint(t *testing.T) {
+	var gotPath, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		_, _ = w.Write([]byte("<svg>remote</svg>"))
+	}))
+	defer server.Close()
+
+	src := filepath.Join(t.TempDir(), "sketch.excalidraw")
+	writeFile(t, src, `{"type":"excalidraw"}`)
+
+	c, err := NewConverter(ConvertOptions{CacheDir: t.TempDir(), Endpoint: server.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := c.Convert(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(out)
+	if gotPath != "/excalidraw/svg" || gotBody != `{"type":"excalidraw"}` || string(data) != "<svg>remote</svg>" {
+		t.Errorf("path %q, body %q, output %q", gotPath, gotBody, data)
+	}
+
+	if _, err := NewConverter(ConvertOptions{Format: "pdf"}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestBundleConvertsDiagrams(t *testing.T) {
+	src := t.TempDir()
+	out := t.TempDir()
+	writeFile(t, filepath.Join(src, "Login Flow.drawio"), "<mxfile/>")
+	writeFile(t, filepath.Join(src, "broken.excalidraw"), "{}")
+
+	c, err := NewConverter(ConvertOptions{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeExporter{}
+	c.run = func(ctx context.Context, name string, args ...string) error {
+		if name == DefaultExcalidrawCommand {
+			return errors.New("not installed")
+		}
+		return fake.run(ctx, name, args...)
+	}
+
+	flow, broken := "Login Flow.drawio", "broken.excalidraw"
+	result, err := Bundle([]Ref{{Field: "flow", Value: &flow}, {Field: "broken", Value: &broken}},
+		Options{Mode: ModeCopy, SourceDir: src, OutputDir: out, Converter: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if flow != "assets/login-flow.svg" || !result.Bundled[0].Converted {
+		t.Errorf("converted link = %q, %+v", flow, result.Bundled[0])
+	}
+	if broken != "assets/broken.excalidraw" || result.Bundled[1].Converted {
+		t.Errorf("unconverted diagram should be bundled as is: %q", broken)
+	}
+	if len(result.ConversionErrors) != 1 || result.ConversionErrors[0].Field != "broken" {
+		t.Errorf("ConversionErrors = %+v", result.ConversionErrors)
+	}
+	if data, err := os.ReadFile(filepath.Join(out, "assets", "login-flow.svg")); err != nil || string(data) != "<svg>converted</svg>" {
+		t.Errorf("converted asset = %q, %v", data, err)
+	}
+}
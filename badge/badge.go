@@ -0,0 +1,159 @@
+// Package badge renders shields.io-style SVG badges of a document's
+// quality, so repositories can show the score or completeness grade of
+// their PRDs in READMEs and rollup dashboards without calling an external
+// badge service.
+package badge
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Badge colors, as named by shields.io.
+const (
+	ColorBrightGreen = "#4c1"
+	ColorGreen       = "#97ca00"
+	ColorYellow      = "#dfb317"
+	ColorOrange      = "#fe7d37"
+	ColorRed         = "#e05d44"
+	ColorGrey        = "#9f9f9f"
+)
+
+// Metrics a PRD badge can show.
+const (
+	MetricScore = "score"
+	MetricGrade = "grade"
+)
+
+// Badge is a two-part badge: a gray label on the left and a colored
+// message on the right.
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// PRD returns a badge of the document's weighted quality score, colored by
+// the scoring decision, or of its completeness grade, colored by grade.
+func PRD(doc *prd.Document, metric string) (Badge, error) {
+	switch strings.ToLower(metric) {
+	case "", MetricScore:
+		result := prd.Score(doc)
+		return Badge{
+			Label:   "PRD score",
+			Message: fmt.Sprintf("%.1f/10", result.WeightedScore),
+			Color:   decisionColor(result.Decision),
+		}, nil
+	case MetricGrade:
+		report := doc.CheckCompleteness()
+		return Badge{
+			Label:   "PRD grade",
+			Message: fmt.Sprintf("%s (%.0f%%)", report.Grade, report.OverallScore),
+			Color:   gradeColor(report.Grade),
+		}, nil
+	}
+	return Badge{}, fmt.Errorf("unknown badge metric %q (use score or grade)", metric)
+}
+
+func decisionColor(decision string) string {
+	switch decision {
+	case "approve":
+		return ColorBrightGreen
+	case "revise":
+		return ColorYellow
+	case "human_review":
+		return ColorOrange
+	case "reject":
+		return ColorRed
+	}
+	return ColorGrey
+}
+
+func gradeColor(grade string) string {
+	switch grade {
+	case "A":
+		return ColorBrightGreen
+	case "B":
+		return ColorGreen
+	case "C":
+		return ColorYellow
+	case "D":
+		return ColorOrange
+	case "F":
+		return ColorRed
+	}
+	return ColorGrey
+}
+
+// Text returns the badge as "label: message", its accessible name.
+func (b Badge) Text() string {
+	return b.Label + ": " + b.Message
+}
+
+// SVG returns the badge in the flat shields.io style.
+func (b Badge) SVG() string {
+	color := b.Color
+	if color == "" {
+		color = ColorGrey
+	}
+	lw := textWidth(b.Label) + 10
+	mw := textWidth(b.Message) + 10
+	w := lw + mw
+	label, message, text := html.EscapeString(b.Label), html.EscapeString(b.Message), html.EscapeString(b.Text())
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`+"\n", w, text))
+	sb.WriteString(fmt.Sprintf("  <title>%s</title>\n", text))
+	sb.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	sb.WriteString(fmt.Sprintf(`  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", w))
+	sb.WriteString(`  <g clip-path="url(#r)">` + "\n")
+	sb.WriteString(fmt.Sprintf(`    <rect width="%d" height="20" fill="#555"/>`+"\n", lw))
+	sb.WriteString(fmt.Sprintf(`    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", lw, mw, html.EscapeString(color)))
+	sb.WriteString(fmt.Sprintf(`    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", w))
+	sb.WriteString("  </g>\n")
+	sb.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	for _, t := range []struct {
+		x    int
+		text string
+	}{{lw / 2, label}, {lw + mw/2, message}} {
+		sb.WriteString(fmt.Sprintf(`    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", t.x, t.text))
+		sb.WriteString(fmt.Sprintf(`    <text x="%d" y="14">%s</text>`+"\n", t.x, t.text))
+	}
+	sb.WriteString("  </g>\n")
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// Markdown returns a Markdown image of the badge at imageURL, linked to
+// link if it is set.
+func (b Badge) Markdown(imageURL, link string) string {
+	alt := strings.NewReplacer("[", "", "]", "").Replace(b.Text())
+	img := fmt.Sprintf("![%s](%s)", alt, imageURL)
+	if link == "" {
+		return img
+	}
+	return fmt.Sprintf("[%s](%s)", img, link)
+}
+
+// textWidth estimates the width in pixels of s in 11px Verdana, which
+// badges are drawn in, from the rough widths of narrow, wide, and capital
+// letters.
+func textWidth(s string) int {
+	var w float64
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("iIjl.,:;|!'()[] ", r):
+			w += 4
+		case strings.ContainsRune("mwMW%@", r):
+			w += 10.5
+		case r >= 'A' && r <= 'Z':
+			w += 7.5
+		default:
+			w += 6.8
+		}
+	}
+	return int(w + 0.5)
+}
@@ -0,0 +1,67 @@
+package badge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func TestSVG(t *testing.T) {
+	b := Badge{Label: "PRD score", Message: "8.4/10", Color: ColorBrightGreen}
+	svg := b.SVG()
+	for _, want := range []string{
+		`aria-label="PRD score: 8.4/10"`,
+		`<title>PRD score: 8.4/10</title>`,
+		`fill="#4c1"`,
+		`>PRD score</text>`,
+		`>8.4/10</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q:\n%s", want, svg)
+		}
+	}
+
+	svg = Badge{Label: "a<b", Message: `"x" & y`}.SVG()
+	if strings.Contains(svg, "a<b") || !strings.Contains(svg, "a&lt;b") || !strings.Contains(svg, "&amp; y") {
+		t.Errorf("SVG text not escaped:\n%s", svg)
+	}
+	if !strings.Contains(svg, `fill="`+ColorGrey+`"`) {
+		t.Errorf("SVG without a color should be grey:\n%s", svg)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	b := Badge{Label: "PRD grade", Message: "B (81%)"}
+	if got, want := b.Markdown("badge.svg", ""), "![PRD grade: B (81%)](badge.svg)"; got != want {
+		t.Errorf("Markdown = %q, want %q", got, want)
+	}
+	if got, want := b.Markdown("badge.svg", "prd.md"), "[![PRD grade: B (81%)](badge.svg)](prd.md)"; got != want {
+		t.Errorf("Markdown = %q, want %q", got, want)
+	}
+}
+
+func TestPRD(t *testing.T) {
+	doc := &prd.Document{}
+
+	b, err := PRD(doc, MetricScore)
+	if err != nil {
+		t.Fatalf("PRD(score): %v", err)
+	}
+	result := prd.Score(doc)
+	if b.Label != "PRD score" || !strings.HasSuffix(b.Message, "/10") || b.Color != decisionColor(result.Decision) {
+		t.Errorf("score badge = %+v, decision %s", b, result.Decision)
+	}
+
+	b, err = PRD(doc, MetricGrade)
+	if err != nil {
+		t.Fatalf("PRD(grade): %v", err)
+	}
+	if b.Label != "PRD grade" || !strings.HasPrefix(b.Message, "F (") || b.Color != ColorRed {
+		t.Errorf("grade badge of an empty PRD = %+v", b)
+	}
+
+	if _, err := PRD(doc, "stars"); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
@@ -0,0 +1,136 @@
+// Package batch runs a per-file command over many planning documents in
+// parallel, for the directory and glob arguments of the validate, check,
+// and score commands.
+package batch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Options configures Run.
+type Options struct {
+	// Jobs is the number of files processed at once. Zero uses the number
+	// of CPUs.
+	Jobs int
+
+	// FailFast stops starting new files after the first failure. Files
+	// already running finish; the rest are skipped.
+	FailFast bool
+
+	// OnResult, if set, is called with each result in file order, as soon
+	// as it and every result before it are done.
+	OnResult func(Result)
+}
+
+// Result is the outcome of running a command on one file.
+type Result struct {
+	File    string
+	Output  []byte // everything the command wrote
+	Err     error
+	Skipped bool // not run because of FailFast
+}
+
+// Run calls fn for each file, with up to opts.Jobs calls at once, and
+// returns the results in file order. fn writes its output to w, which is
+// buffered per file so that output from parallel runs is not interleaved.
+func Run(files []string, opts Options, fn func(file string, w io.Writer) error) []Result {
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	results := make([]Result, len(files))
+	done := make([]chan struct{}, len(files))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	var (
+		mu     sync.Mutex
+		failed bool
+	)
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := range files {
+			next <- i
+		}
+	}()
+	for range min(jobs, max(len(files), 1)) {
+		go func() {
+			for i := range next {
+				results[i].File = files[i]
+				mu.Lock()
+				skip := opts.FailFast && failed
+				mu.Unlock()
+				if skip {
+					results[i].Skipped = true
+					close(done[i])
+					continue
+				}
+				var buf bytes.Buffer
+				err := fn(files[i], &buf)
+				results[i].Output, results[i].Err = buf.Bytes(), err
+				if err != nil {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+				close(done[i])
+			}
+		}()
+	}
+
+	for i := range results {
+		<-done[i]
+		if opts.OnResult != nil {
+			opts.OnResult(results[i])
+		}
+	}
+	return results
+}
+
+// Summary counts the outcomes of a run.
+type Summary struct {
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// Summarize counts results by outcome.
+func Summarize(results []Result) Summary {
+	var s Summary
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			s.Skipped++
+		case r.Err != nil:
+			s.Failed++
+		default:
+			s.Passed++
+		}
+	}
+	return s
+}
+
+// Total returns the number of files.
+func (s Summary) Total() int {
+	return s.Passed + s.Failed + s.Skipped
+}
+
+// String returns e.g. "8 files: 6 passed, 1 failed, 1 skipped".
+func (s Summary) String() string {
+	parts := []string{fmt.Sprintf("%d passed", s.Passed), fmt.Sprintf("%d failed", s.Failed)}
+	if s.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", s.Skipped))
+	}
+	noun := "files"
+	if s.Total() == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("%d %s: %s", s.Total(), noun, strings.Join(parts, ", "))
+}
@@ -0,0 +1,121 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"a.prd.json",
+		"a.mrd.json",
+		"docs/b.prd.json",
+		"docs/deep/c.prd.yaml",
+		"docs/deep/d.prd.json",
+		"docs/.drafts/e.prd.json",
+	} {
+		writeFile(t, dir, name)
+	}
+	isPRD := func(p string) bool { return strings.Contains(filepath.Base(p), ".prd.") }
+	j := func(names ...string) []string {
+		var out []string
+		for _, n := range names {
+			out = append(out, filepath.Join(dir, filepath.FromSlash(n)))
+		}
+		return out
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{j("a.mrd.json"), j("a.mrd.json")},
+		{j("."), j("a.prd.json", "docs/b.prd.json", "docs/deep/c.prd.yaml", "docs/deep/d.prd.json")},
+		{j("docs/**/*.prd.json"), j("docs/b.prd.json", "docs/deep/d.prd.json")},
+		{j("**/*.json"), j("a.mrd.json", "a.prd.json", "docs/b.prd.json", "docs/deep/d.prd.json")},
+		{j("docs/*/*"), j("docs/deep/c.prd.yaml", "docs/deep/d.prd.json")},
+		{j("a.prd.json", "*.prd.json", "docs"), j("a.prd.json", "docs/b.prd.json", "docs/deep/c.prd.yaml", "docs/deep/d.prd.json")},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.args, isPRD)
+		if err != nil {
+			t.Errorf("Expand(%v): %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expand(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+
+	for _, args := range [][]string{j("missing.prd.json"), j("*.trd.json"), j("missing/**/*.json"), j("[")} {
+		if got, err := Expand(args, isPRD); err == nil {
+			t.Errorf("Expand(%v) = %v, want error", args, got)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	files := []string{"a", "b", "c", "d", "e"}
+	var order []string
+	results := Run(files, Options{Jobs: 3, OnResult: func(r Result) { order = append(order, r.File) }},
+		func(file string, w io.Writer) error {
+			fmt.Fprintf(w, "checked %s", file)
+			if file == "c" {
+				return errors.New("bad")
+			}
+			return nil
+		})
+	if !reflect.DeepEqual(order, files) {
+		t.Errorf("OnResult order = %v", order)
+	}
+	for i, r := range results {
+		if r.File != files[i] || string(r.Output) != "checked "+files[i] || (r.Err != nil) != (r.File == "c") {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+	if s := Summarize(results); s != (Summary{Passed: 4, Failed: 1}) || s.String() != "5 files: 4 passed, 1 failed" {
+		t.Errorf("Summarize = %+v %q", s, s)
+	}
+}
+
+func TestRunFailFast(t *testing.T) {
+	var calls atomic.Int32
+	results := Run([]string{"a", "b", "c", "d"}, Options{Jobs: 1, FailFast: true}, func(file string, w io.Writer) error {
+		calls.Add(1)
+		if file == "b" {
+			return errors.New("bad")
+		}
+		return nil
+	})
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+	if s := Summarize(results); s != (Summary{Passed: 1, Failed: 1, Skipped: 2}) || s.String() != "4 files: 1 passed, 1 failed, 2 skipped" {
+		t.Errorf("Summarize = %+v %q", s, s)
+	}
+}
+
+func TestRunEmpty(t *testing.T) {
+	if results := Run(nil, Options{}, func(string, io.Writer) error { return nil }); len(results) != 0 {
+		t.Errorf("Run(nil) = %v", results)
+	}
+}
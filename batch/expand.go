@@ -0,0 +1,143 @@
+package batch
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Expand returns the files named by args, in argument order without
+// duplicates. An argument is a file, a directory, or a glob pattern.
+// Directories are searched recursively for files accepted by match,
+// skipping hidden directories. In patterns, "**" matches any number of
+// directories, so "docs/**/*.prd.json" finds PRDs at any depth under docs.
+// Files named directly are returned whether or not match accepts them.
+func Expand(args []string, match func(path string) bool) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(paths ...string) {
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				files = append(files, p)
+			}
+		}
+	}
+
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil {
+			if !info.IsDir() {
+				add(arg)
+				continue
+			}
+			found, err := walk(arg, match)
+			if err != nil {
+				return nil, err
+			}
+			add(found...)
+			continue
+		}
+		if !IsPattern(arg) {
+			return nil, fmt.Errorf("file not found: %s", arg)
+		}
+		found, err := glob(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no files match %s", arg)
+		}
+		add(found...)
+	}
+	return files, nil
+}
+
+// IsPattern reports whether arg contains glob metacharacters.
+func IsPattern(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+func glob(pattern string) ([]string, error) {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	root := patternRoot(pattern)
+	if _, err := os.Stat(root); err != nil {
+		return nil, nil
+	}
+	return walk(filepath.FromSlash(root), func(p string) bool {
+		return matchSegments(strings.Split(pattern, "/"), strings.Split(filepath.ToSlash(p), "/"))
+	})
+}
+
+// patternRoot returns the directories of pattern before its first
+// metacharacter, where the search starts.
+func patternRoot(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	var root []string
+	for _, s := range segments[:len(segments)-1] {
+		if IsPattern(s) {
+			break
+		}
+		root = append(root, s)
+	}
+	switch {
+	case len(root) == 0:
+		return "."
+	case len(root) == 1 && root[0] == "":
+		return "/"
+	}
+	return strings.Join(root, "/")
+}
+
+// matchSegments matches a slash-separated path against a pattern, one
+// segment at a time, with "**" matching zero or more segments.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+func walk(root string, match func(path string) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if match(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching %s: %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
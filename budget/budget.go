@@ -0,0 +1,457 @@
+// Package budget rolls up cost estimates from planning documents by roadmap
+// phase and calendar quarter for finance reviews.
+//
+// Costs are attached to roadmap phases, components, and technology choices
+// as common.Cost values. A build cost is one-time and is spread evenly over
+// the phase it belongs to. Run (monthly) and licensing (annual) costs are
+// recurring: they start when their phase starts and accrue until the end of
+// the roadmap. Costs whose phase is unknown or undated are reported as
+// unscheduled, with only their build cost counted toward spend.
+package budget
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+// Item kinds.
+const (
+	KindPhase      = "phase"
+	KindComponent  = "component"
+	KindTechnology = "technology"
+)
+
+// Item is a cost estimate from a document.
+type Item struct {
+	Kind string      `json:"kind"`
+	ID   string      `json:"id,omitempty"`
+	Name string      `json:"name"`
+	Cost common.Cost `json:"cost"`
+}
+
+// Line is a row of the rollup for a phase, quarter, or total.
+type Line struct {
+	Period string `json:"period"`         // Phase ID or quarter such as "2026-Q1"
+	Name   string `json:"name,omitempty"` // Phase name
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+
+	// Build is the one-time cost incurred in the period. For quarters it
+	// is the share of each phase's build cost falling in the quarter.
+	Build float64 `json:"build"`
+
+	// Run and Licensing are the recurring monthly and annual costs that
+	// start in the period.
+	Run       float64 `json:"run"`
+	Licensing float64 `json:"licensing"`
+
+	// Spend is the total cost incurred in the period, including recurring
+	// costs started in earlier periods.
+	Spend float64 `json:"spend"`
+}
+
+// Report is a budget rollup.
+type Report struct {
+	Currency string `json:"currency"`
+	Items    []Item `json:"items"`
+
+	// Phases has one line per dated phase. Phases may overlap, so their
+	// spend need not sum to the total.
+	Phases []Line `json:"phases,omitempty"`
+
+	// Quarters has one line per calendar quarter from the first phase start
+	// to the roadmap end.
+	Quarters []Line `json:"quarters,omitempty"`
+
+	// Unscheduled sums costs with no dated phase.
+	Unscheduled *Line `json:"unscheduled,omitempty"`
+
+	Total Line `json:"total"`
+
+	// Warnings lists items excluded from the rollup or referencing
+	// undefined phases.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// HasCosts reports whether any item has a non-zero cost.
+func (r *Report) HasCosts() bool {
+	return len(r.Items) > 0
+}
+
+// PhaseItems returns cost items for roadmap phases that have a cost. A
+// phase's cost defaults to that phase.
+func PhaseItems(phases []roadmap.Phase) []Item {
+	var items []Item
+	for _, p := range phases {
+		if p.Cost.IsZero() {
+			continue
+		}
+		c := *p.Cost
+		if c.PhaseID == "" {
+			c.PhaseID = p.ID
+		}
+		items = append(items, Item{Kind: KindPhase, ID: p.ID, Name: p.Name, Cost: c})
+	}
+	return items
+}
+
+const daysPerMonth = 365.25 / 12
+
+// window is a half-open time interval.
+type window struct{ start, end time.Time }
+
+func (w window) days() float64 {
+	return w.end.Sub(w.start).Hours() / 24
+}
+
+func (w window) overlap(o window) float64 {
+	start, end := w.start, w.end
+	if o.start.After(start) {
+		start = o.start
+	}
+	if o.end.Before(end) {
+		end = o.end
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Hours() / 24
+}
+
+// phaseWindow returns a phase's dates as a half-open interval, treating the
+// end date as inclusive.
+func phaseWindow(p *roadmap.Phase) (window, bool) {
+	if !p.HasWindow() || p.EndDate.Before(*p.StartDate) {
+		return window{}, false
+	}
+	return window{start: *p.StartDate, end: p.EndDate.AddDate(0, 0, 1)}, true
+}
+
+type scheduled struct {
+	item  Item
+	phase window
+}
+
+// Rollup totals items by phase and quarter. Items in a currency other than
+// the most common one are excluded with a warning.
+func Rollup(items []Item, phases []roadmap.Phase) *Report {
+	r := &Report{Currency: reportCurrency(items)}
+
+	windows := map[string]window{}
+	known := map[string]bool{}
+	var horizon time.Time
+	for i := range phases {
+		known[phases[i].ID] = true
+		if w, ok := phaseWindow(&phases[i]); ok {
+			windows[phases[i].ID] = w
+			if w.end.After(horizon) {
+				horizon = w.end
+			}
+		}
+	}
+
+	var sched []scheduled
+	unscheduled := Line{Period: "unscheduled", Name: "Unscheduled"}
+	for _, it := range items {
+		if it.Cost.IsZero() {
+			continue
+		}
+		if cur := it.Cost.CurrencyCode(); cur != r.Currency {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s %s is in %s and is excluded from the %s totals",
+				it.Kind, itemLabel(it), cur, r.Currency))
+			continue
+		}
+		r.Items = append(r.Items, it)
+
+		pid := it.Cost.PhaseID
+		if pid != "" && !known[pid] {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s %s references undefined phase: %s", it.Kind, itemLabel(it), pid))
+		}
+		if w, ok := windows[pid]; ok {
+			sched = append(sched, scheduled{item: it, phase: w})
+			continue
+		}
+		unscheduled.Build += it.Cost.Build
+		unscheduled.Run += it.Cost.Run
+		unscheduled.Licensing += it.Cost.Licensing
+		unscheduled.Spend += it.Cost.Build
+	}
+
+	for i := range phases {
+		p := &phases[i]
+		w, ok := windows[p.ID]
+		if !ok {
+			continue
+		}
+		line := Line{Period: p.ID, Name: p.Name, Start: formatDate(w.start), End: formatDate(p.EndDate.UTC())}
+		for _, s := range sched {
+			if s.item.Cost.PhaseID == p.ID {
+				line.Build += s.item.Cost.Build
+				line.Run += s.item.Cost.Run
+				line.Licensing += s.item.Cost.Licensing
+				line.Spend += s.item.Cost.Build
+			}
+			line.Spend += recurring(s, w, horizon)
+		}
+		r.Phases = append(r.Phases, line)
+	}
+
+	if len(sched) > 0 {
+		r.Quarters = quarterLines(sched, horizon)
+	}
+	if unscheduled.Build != 0 || unscheduled.Run != 0 || unscheduled.Licensing != 0 {
+		r.Unscheduled = &unscheduled
+	}
+
+	r.Total = Line{Period: "total", Name: "Total"}
+	for _, it := range r.Items {
+		r.Total.Build += it.Cost.Build
+		r.Total.Run += it.Cost.Run
+		r.Total.Licensing += it.Cost.Licensing
+	}
+	for _, q := range r.Quarters {
+		r.Total.Spend += q.Spend
+	}
+	r.Total.Spend += unscheduled.Spend
+	return r
+}
+
+func quarterLines(sched []scheduled, horizon time.Time) []Line {
+	first := sched[0].phase.start
+	for _, s := range sched {
+		if s.phase.start.Before(first) {
+			first = s.phase.start
+		}
+	}
+
+	var lines []Line
+	for q := quarterStart(first); q.Before(horizon); q = q.AddDate(0, 3, 0) {
+		w := window{start: q, end: q.AddDate(0, 3, 0)}
+		line := Line{
+			Period: fmt.Sprintf("%d-Q%d", q.Year(), (int(q.Month())-1)/3+1),
+			Start:  formatDate(w.start),
+			End:    formatDate(w.end.AddDate(0, 0, -1)),
+		}
+		for _, s := range sched {
+			if days := s.phase.days(); days > 0 {
+				build := s.item.Cost.Build * s.phase.overlap(w) / days
+				line.Build += build
+				line.Spend += build
+			}
+			if !s.phase.start.Before(w.start) && s.phase.start.Before(w.end) {
+				line.Run += s.item.Cost.Run
+				line.Licensing += s.item.Cost.Licensing
+			}
+			line.Spend += recurring(s, w, horizon)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// recurring returns the run and licensing cost of s accrued during w. The
+// cost accrues from the start of its phase to the roadmap horizon.
+func recurring(s scheduled, w window, horizon time.Time) float64 {
+	monthly := s.item.Cost.Run + s.item.Cost.Licensing/12
+	if monthly == 0 {
+		return 0
+	}
+	active := window{start: s.phase.start, end: horizon}
+	return monthly * active.overlap(w) / daysPerMonth
+}
+
+func quarterStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), time.Month((int(t.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// reportCurrency returns the most common currency among items with costs,
+// preferring common.DefaultCurrency on a tie.
+func reportCurrency(items []Item) string {
+	counts := map[string]int{}
+	for _, it := range items {
+		if !it.Cost.IsZero() {
+			counts[it.Cost.CurrencyCode()]++
+		}
+	}
+	best := common.DefaultCurrency
+	for cur, n := range counts {
+		if n > counts[best] || (n == counts[best] && best != common.DefaultCurrency && cur < best) {
+			best = cur
+		}
+	}
+	return best
+}
+
+// ToMarkdown renders the rollup as markdown subsections (level 3 headings)
+// for embedding under a Budget section.
+func (r *Report) ToMarkdown() string {
+	var sb strings.Builder
+	if !r.HasCosts() {
+		sb.WriteString("*No cost estimates are recorded.*\n\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("*Amounts in %s. Run costs are monthly and licensing costs annual; recurring costs accrue from the phase they start in to the end of the roadmap.*\n\n", r.Currency))
+
+	if len(r.Phases) > 0 {
+		sb.WriteString("### By Phase\n\n")
+		sb.WriteString("| Phase | Dates | Build | Run (monthly) | Licensing (annual) | Spend |\n")
+		sb.WriteString("|-------|-------|------:|--------------:|-------------------:|------:|\n")
+		for _, l := range r.Phases {
+			name := l.Name
+			if name == "" {
+				name = l.Period
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s to %s | %s | %s | %s | %s |\n", escape(name), l.Start, l.End,
+				FormatAmount(l.Build), FormatAmount(l.Run), FormatAmount(l.Licensing), FormatAmount(l.Spend)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("### By Quarter\n\n")
+	sb.WriteString("| Quarter | Build | New Run (monthly) | New Licensing (annual) | Spend |\n")
+	sb.WriteString("|---------|------:|------------------:|-----------------------:|------:|\n")
+	for _, l := range r.Quarters {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", l.Period,
+			FormatAmount(l.Build), FormatAmount(l.Run), FormatAmount(l.Licensing), FormatAmount(l.Spend)))
+	}
+	if u := r.Unscheduled; u != nil {
+		sb.WriteString(fmt.Sprintf("| Unscheduled | %s | %s | %s | %s |\n",
+			FormatAmount(u.Build), FormatAmount(u.Run), FormatAmount(u.Licensing), FormatAmount(u.Spend)))
+	}
+	t := r.Total
+	sb.WriteString(fmt.Sprintf("| **Total** | **%s** | **%s** | **%s** | **%s** |\n\n",
+		FormatAmount(t.Build), FormatAmount(t.Run), FormatAmount(t.Licensing), FormatAmount(t.Spend)))
+
+	sb.WriteString("### Line Items\n\n")
+	sb.WriteString("| Item | Type | Phase | Build | Run (monthly) | Licensing (annual) | Notes |\n")
+	sb.WriteString("|------|------|-------|------:|--------------:|-------------------:|-------|\n")
+	for _, it := range r.sortedItems() {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n", escape(itemLabel(it)), it.Kind, it.Cost.PhaseID,
+			FormatAmount(it.Cost.Build), FormatAmount(it.Cost.Run), FormatAmount(it.Cost.Licensing), escape(it.Cost.Notes)))
+	}
+	sb.WriteString("\n")
+
+	if len(r.Warnings) > 0 {
+		sb.WriteString("**Warnings:**\n\n")
+		for _, w := range r.Warnings {
+			sb.WriteString("- " + w + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// WriteCSV writes the phase, quarter, unscheduled, and total lines followed
+// by the line items. Amounts are unformatted decimals.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Section", "Period", "Name", "Start", "End", "Build", "Run (monthly)", "Licensing (annual)", "Spend", "Currency"}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	row := func(section string, l Line, spend bool) []string {
+		s := ""
+		if spend {
+			s = csvAmount(l.Spend)
+		}
+		return []string{section, l.Period, l.Name, l.Start, l.End,
+			csvAmount(l.Build), csvAmount(l.Run), csvAmount(l.Licensing), s, r.Currency}
+	}
+	var rows [][]string
+	for _, l := range r.Phases {
+		rows = append(rows, row("phase", l, true))
+	}
+	for _, l := range r.Quarters {
+		rows = append(rows, row("quarter", l, true))
+	}
+	if r.Unscheduled != nil {
+		rows = append(rows, row("unscheduled", *r.Unscheduled, true))
+	}
+	rows = append(rows, row("total", r.Total, true))
+	for _, it := range r.sortedItems() {
+		rows = append(rows, row("item", Line{
+			Period: it.Cost.PhaseID, Name: it.Kind + ": " + itemLabel(it),
+			Build: it.Cost.Build, Run: it.Cost.Run, Licensing: it.Cost.Licensing,
+		}, false))
+	}
+
+	for _, rec := range rows {
+		if err := cw.Write(rec); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// sortedItems returns items ordered by phase, with unscheduled items last.
+func (r *Report) sortedItems() []Item {
+	order := map[string]int{}
+	for i, l := range r.Phases {
+		order[l.Period] = i
+	}
+	rank := func(it Item) int {
+		if i, ok := order[it.Cost.PhaseID]; ok {
+			return i
+		}
+		return len(order)
+	}
+	items := append([]Item(nil), r.Items...)
+	sort.SliceStable(items, func(i, j int) bool { return rank(items[i]) < rank(items[j]) })
+	return items
+}
+
+// FormatAmount formats an amount with thousands separators, rounded to a
+// whole unit, or "-" for zero.
+func FormatAmount(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	n := int64(math.Round(math.Abs(v)))
+	s := strconv.FormatInt(n, 10)
+	var sb strings.Builder
+	if v < 0 {
+		sb.WriteByte('-')
+	}
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+func csvAmount(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', 2, 64)
+}
+
+func itemLabel(it Item) string {
+	switch {
+	case it.ID != "" && it.Name != "" && it.ID != it.Name:
+		return it.ID + " " + it.Name
+	case it.Name != "":
+		return it.Name
+	}
+	return it.ID
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,144 @@
+package budget
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+func date(s string) *time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func testPhases() []roadmap.Phase {
+	return []roadmap.Phase{
+		{ID: "p1", Name: "Build", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"),
+			Cost: &common.Cost{Build: 90000}},
+		{ID: "p2", Name: "Launch", StartDate: date("2026-04-01"), EndDate: date("2026-09-30"),
+			Cost: &common.Cost{Build: 60000, Run: 1000}},
+		{ID: "p3", Name: "Later"},
+	}
+}
+
+func TestRollup(t *testing.T) {
+	phases := testPhases()
+	items := append(PhaseItems(phases),
+		Item{Kind: KindTechnology, Name: "Search", Cost: common.Cost{Licensing: 12000, PhaseID: "p1"}},
+		Item{Kind: KindComponent, ID: "C1", Name: "Gateway", Cost: common.Cost{Build: 5000}},
+		Item{Kind: KindTechnology, Name: "Vault", Cost: common.Cost{Build: 700, Currency: "EUR"}},
+		Item{Kind: KindTechnology, Name: "CDN", Cost: common.Cost{Run: 50, PhaseID: "p9"}},
+	)
+	r := Rollup(items, phases)
+
+	if r.Currency != "USD" {
+		t.Errorf("Currency = %q, want USD", r.Currency)
+	}
+	wantWarnings := []string{
+		"technology Vault is in EUR and is excluded from the USD totals",
+		"technology CDN references undefined phase: p9",
+	}
+	if strings.Join(r.Warnings, "\n") != strings.Join(wantWarnings, "\n") {
+		t.Errorf("Warnings = %q, want %q", r.Warnings, wantWarnings)
+	}
+
+	var quarters []string
+	for _, q := range r.Quarters {
+		quarters = append(quarters, q.Period)
+	}
+	if got := strings.Join(quarters, ","); got != "2026-Q1,2026-Q2,2026-Q3" {
+		t.Fatalf("Quarters = %s", got)
+	}
+	// Build is spread over the phase: half of p2's build falls in Q2.
+	if got := r.Quarters[1].Build; math.Abs(got-60000*91.0/183.0) > 0.01 {
+		t.Errorf("Q2 build = %.2f", got)
+	}
+	if got := r.Quarters[0].Licensing; got != 12000 {
+		t.Errorf("Q1 new licensing = %.2f, want 12000", got)
+	}
+
+	// Quarters and unscheduled spend partition the total.
+	var sum float64
+	for _, q := range r.Quarters {
+		sum += q.Spend
+	}
+	if r.Unscheduled == nil || r.Unscheduled.Build != 5000 || r.Unscheduled.Run != 50 {
+		t.Fatalf("Unscheduled = %+v", r.Unscheduled)
+	}
+	sum += r.Unscheduled.Spend
+	if math.Abs(sum-r.Total.Spend) > 0.01 {
+		t.Errorf("quarter spend %.2f != total %.2f", sum, r.Total.Spend)
+	}
+
+	// 155,000 build plus nine months of licensing and six of run.
+	want := 155000 + 9*1000.0 + 6*1000.0
+	if math.Abs(r.Total.Spend-want) > 100 {
+		t.Errorf("Total.Spend = %.2f, want about %.0f", r.Total.Spend, want)
+	}
+	if r.Total.Build != 155000 || r.Total.Run != 1050 || r.Total.Licensing != 12000 {
+		t.Errorf("Total = %+v", r.Total)
+	}
+
+	if len(r.Phases) != 2 || r.Phases[0].Build != 90000 || r.Phases[1].Run != 1000 {
+		t.Errorf("Phases = %+v", r.Phases)
+	}
+}
+
+func TestRollupNoPhases(t *testing.T) {
+	r := Rollup([]Item{{Kind: KindComponent, Name: "API", Cost: common.Cost{Build: 1000, Run: 10}}}, nil)
+	if len(r.Quarters) != 0 || len(r.Phases) != 0 {
+		t.Errorf("expected no phase or quarter lines, got %+v %+v", r.Phases, r.Quarters)
+	}
+	if r.Total.Spend != 1000 || r.Total.Run != 10 {
+		t.Errorf("Total = %+v", r.Total)
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	phases := testPhases()
+	r := Rollup(PhaseItems(phases), phases)
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"*Amounts in USD.",
+		"| Build | 2026-01-01 to 2026-03-31 | 90,000 | - | - | 90,000 |",
+		"| **Total** | **150,000** | **1,000** | **-** | **156,",
+		"| p2 Launch | phase | p2 | 60,000 | 1,000 | - |  |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q:\n%s", want, md)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "Section,Period,Name,Start,End,Build,Run (monthly),Licensing (annual),Spend,Currency" {
+		t.Errorf("CSV header = %s", lines[0])
+	}
+	if lines[1] != "phase,p1,Build,2026-01-01,2026-03-31,90000.00,0.00,0.00,90000.00,USD" {
+		t.Errorf("CSV row = %s", lines[1])
+	}
+	if want := "item,p1,phase: p1 Build,,,90000.00,0.00,0.00,,USD"; !strings.Contains(buf.String(), want) {
+		t.Errorf("CSV missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := map[float64]string{0: "-", 999.6: "1,000", 1234567: "1,234,567", -4500: "-4,500", 12: "12"}
+	for v, want := range tests {
+		if got := FormatAmount(v); got != want {
+			t.Errorf("FormatAmount(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
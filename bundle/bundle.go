@@ -0,0 +1,277 @@
+// Package bundle packages a planning document with everything generated
+// from it, such as its Markdown, HTML, slides, referenced assets, and score
+// report, into a single zip archive for record-keeping and handoffs.
+//
+// Every archive holds its files under one timestamped directory, together
+// with a manifest.json listing each file's kind, size, and SHA-256 digest
+// and the outputs that could not be produced.
+package bundle
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestFile is the name of the manifest within the archive directory.
+const ManifestFile = "manifest.json"
+
+// TimestampFormat is the layout of the timestamp in archive and directory
+// names.
+const TimestampFormat = "20060102T150405Z"
+
+// File kinds.
+const (
+	KindSource   = "source"
+	KindMarkdown = "markdown"
+	KindHTML     = "html"
+	KindDOCX     = "docx"
+	KindPDF      = "pdf"
+	KindSlides   = "slides"
+	KindAsset    = "asset"
+	KindScore    = "score"
+
+	// Kinds of a workspace archive.
+	KindWorkspaceManifest = "manifest"
+	KindDocument          = "document"
+	KindShared            = "shared"
+)
+
+// MaxFileSize is the largest file Read accepts.
+const MaxFileSize = 256 << 20
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	Source    string    `json:"source"`
+	Type      string    `json:"type"`
+	ID        string    `json:"id,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Files     []Entry   `json:"files"`
+
+	// Skipped lists the outputs that were not produced and why, such as a
+	// PDF when Pandoc is not installed.
+	Skipped []Skipped `json:"skipped,omitempty"`
+}
+
+// Entry is a file in the archive.
+type Entry struct {
+	Path   string `json:"path"` // Relative to the archive directory
+	Kind   string `json:"kind"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Skipped is an output left out of the archive.
+type Skipped struct {
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+// Bundle collects the files of an archive.
+type Bundle struct {
+	Manifest Manifest
+	data     map[string][]byte
+}
+
+// New returns an empty bundle for the document at source, of the given
+// type, created at the given time.
+func New(source, docType string, createdAt time.Time) *Bundle {
+	return &Bundle{
+		Manifest: Manifest{
+			Source:    filepath.Base(source),
+			Type:      docType,
+			CreatedAt: createdAt.UTC().Truncate(time.Second),
+			Files:     []Entry{},
+		},
+		data: map[string][]byte{},
+	}
+}
+
+// Add adds a file at a slash-separated path relative to the archive
+// directory. Adding a path twice replaces the earlier file.
+func (b *Bundle) Add(kind, name string, data []byte) error {
+	clean, err := cleanPath(name)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	entry := Entry{Path: clean, Kind: kind, Size: len(data), SHA256: hex.EncodeToString(sum[:])}
+	if _, ok := b.data[clean]; ok {
+		for i, e := range b.Manifest.Files {
+			if e.Path == clean {
+				b.Manifest.Files[i] = entry
+			}
+		}
+	} else {
+		b.Manifest.Files = append(b.Manifest.Files, entry)
+	}
+	b.data[clean] = data
+	return nil
+}
+
+// cleanPath returns name as a clean slash-separated relative path, or an
+// error if it leaves the archive directory or names the manifest.
+func cleanPath(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid bundle path %q", name)
+	}
+	if clean == ManifestFile {
+		return "", fmt.Errorf("bundle path %q is reserved for the manifest", name)
+	}
+	return clean, nil
+}
+
+// File returns the contents of the file at path.
+func (b *Bundle) File(path string) ([]byte, bool) {
+	data, ok := b.data[path]
+	return data, ok
+}
+
+// Skip records an output that was not produced.
+func (b *Bundle) Skip(kind, reason string) {
+	b.Manifest.Skipped = append(b.Manifest.Skipped, Skipped{Kind: kind, Reason: reason})
+}
+
+// Name returns the source file name without its extension, e.g.
+// "checkout.prd", the base name of the generated files.
+func (b *Bundle) Name() string {
+	name := b.Manifest.Source
+	switch ext := filepath.Ext(name); strings.ToLower(ext) {
+	case ".json", ".yaml", ".yml":
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// Dir returns the name of the archive's top-level directory, Name followed
+// by the creation time, e.g. "checkout.prd-20261017T093000Z".
+func (b *Bundle) Dir() string {
+	return b.Name() + "-" + b.Manifest.CreatedAt.Format(TimestampFormat)
+}
+
+// FileName returns the default archive file name, Dir with a .zip
+// extension.
+func (b *Bundle) FileName() string {
+	return b.Dir() + ".zip"
+}
+
+// Write writes the archive. Files are written in path order, after the
+// manifest, with the creation time as their modification time, so the
+// same bundle always produces the same archive.
+func (b *Bundle) Write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding bundle manifest: %w", err)
+	}
+
+	names := make([]string, 0, len(b.data))
+	for name := range b.data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	dir := b.Dir()
+	write := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     dir + "/" + name,
+			Method:   zip.Deflate,
+			Modified: b.Manifest.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	if err := write(ManifestFile, append(manifest, '\n')); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	for _, name := range names {
+		if err := write(name, b.data[name]); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	return nil
+}
+
+// Read reads an archive written by Write. Every file must be listed in the
+// manifest with a matching size and digest, so a damaged or altered archive
+// is rejected.
+func Read(r io.ReaderAt, size int64) (*Bundle, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	var dir string
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		top, name, ok := strings.Cut(f.Name, "/")
+		if !ok || name == "" || (dir != "" && top != dir) {
+			return nil, fmt.Errorf("reading bundle: unexpected entry %q outside the bundle directory", f.Name)
+		}
+		dir = top
+		if f.UncompressedSize64 > MaxFileSize {
+			return nil, fmt.Errorf("reading bundle: %s is larger than %d bytes", name, MaxFileSize)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, MaxFileSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle %s: %w", name, err)
+		}
+		if len(data) > MaxFileSize {
+			return nil, fmt.Errorf("reading bundle: %s is larger than %d bytes", name, MaxFileSize)
+		}
+		files[name] = data
+	}
+
+	manifest, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("reading bundle: no %s", ManifestFile)
+	}
+	b := &Bundle{data: map[string][]byte{}}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("reading bundle manifest: %w", err)
+	}
+	delete(files, ManifestFile)
+	for _, e := range b.Manifest.Files {
+		name, err := cleanPath(e.Path)
+		if err != nil || name != e.Path {
+			return nil, fmt.Errorf("reading bundle: invalid path %q in the manifest", e.Path)
+		}
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("reading bundle: %s is listed in the manifest but missing", name)
+		}
+		if sum := sha256.Sum256(data); len(data) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+			return nil, fmt.Errorf("reading bundle: %s does not match its digest in the manifest", name)
+		}
+		b.data[name] = data
+		delete(files, name)
+	}
+	for name := range files {
+		return nil, fmt.Errorf("reading bundle: %s is not listed in the manifest", name)
+	}
+	return b, nil
+}
@@ -0,0 +1,125 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBundle(t *testing.T) {
+	created := time.Date(2026, 10, 17, 9, 30, 0, 0, time.FixedZone("PDT", -7*3600))
+	b := New("docs/checkout.prd.json", "prd", created)
+	b.Manifest.Title = "Checkout"
+
+	for _, f := range []struct{ kind, name, data string }{
+		{KindSource, "checkout.prd.json", `{"metadata": {}}`},
+		{KindMarkdown, "checkout.prd.md", "# Checkout"},
+		{KindAsset, "assets/flow.png", "PNG"},
+		{KindMarkdown, "checkout.prd.md", "# Checkout v2"},
+	} {
+		if err := b.Add(f.kind, f.name, []byte(f.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Skip(KindPDF, "pandoc not found")
+
+	for _, bad := range []string{"../x", "/etc/passwd", ".", ManifestFile} {
+		if err := b.Add(KindAsset, bad, nil); err == nil {
+			t.Errorf("Add(%q) succeeded", bad)
+		}
+	}
+
+	if got, want := b.FileName(), "checkout.prd-20261017T163000Z.zip"; got != want || b.Name() != "checkout.prd" {
+		t.Errorf("FileName = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	files := map[string]string{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	dir := "checkout.prd-20261017T163000Z/"
+	want := []string{dir + "manifest.json", dir + "assets/flow.png", dir + "checkout.prd.json", dir + "checkout.prd.md"}
+	if len(names) != len(want) {
+		t.Fatalf("entries = %v", names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, names[i], want[i])
+		}
+	}
+	if files[dir+"checkout.prd.md"] != "# Checkout v2" {
+		t.Errorf("replaced file = %q", files[dir+"checkout.prd.md"])
+	}
+
+	var m Manifest
+	if err := json.Unmarshal([]byte(files[dir+"manifest.json"]), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Source != "checkout.prd.json" || m.Title != "Checkout" || len(m.Files) != 3 || len(m.Skipped) != 1 {
+		t.Errorf("manifest = %+v", m)
+	}
+	if e := m.Files[1]; e.Path != "checkout.prd.md" || e.Size != 13 || len(e.SHA256) != 64 {
+		t.Errorf("markdown entry = %+v", e)
+	}
+
+	var again bytes.Buffer
+	if err := b.Write(&again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Error("writing the same bundle twice produced different archives")
+	}
+}
+
+func TestRead(t *testing.T) {
+	b := New("plans", "workspace", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	if err := b.Add(KindDocument, "specs/checkout.prd.json", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := got.File("specs/checkout.prd.json"); !ok || string(data) != "{}" || got.Manifest.Type != "workspace" {
+		t.Errorf("File = %q, %v; manifest = %+v", data, ok, got.Manifest)
+	}
+
+	// An archive whose file does not match the manifest is rejected.
+	var tampered bytes.Buffer
+	zw := zip.NewWriter(&tampered)
+	for _, f := range []struct{ name, data string }{
+		{"plans-20261017T000000Z/manifest.json", `{"files": [{"path": "a.json", "size": 2, "sha256": "00"}]}`},
+		{"plans-20261017T000000Z/a.json", `{}`},
+	} {
+		w, _ := zw.Create(f.name)
+		_, _ = w.Write([]byte(f.data))
+	}
+	_ = zw.Close()
+	if _, err := Read(bytes.NewReader(tampered.Bytes()), int64(tampered.Len())); err == nil {
+		t.Error("expected an error for a file that does not match its digest")
+	}
+}
@@ -0,0 +1,241 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Default planning cycle, in weeks relative to the period start.
+const (
+	DefaultDraftingWeeks = 6
+	DefaultApprovalWeeks = 1
+	DefaultCheckInWeeks  = 2
+)
+
+// DefaultReviews are the review dates of a cycle when none are configured.
+var DefaultReviews = []Review{
+	{Name: "Peer review", WeeksBefore: 4},
+	{Name: "Leadership review", WeeksBefore: 2},
+}
+
+// Config is the fiscal year and the planning cycle run for each period.
+// It is read from the "planning" section of the workspace manifest.
+type Config struct {
+	// FiscalYearStartMonth is the first month of the fiscal year, 1-12.
+	// Defaults to 1 (January).
+	FiscalYearStartMonth int `json:"fiscalYearStartMonth,omitempty"`
+
+	// NamedByStartYear names fiscal years after the calendar year they
+	// start in. By default a fiscal year is named after the year it ends
+	// in: with a February start, FY2026 runs from February 2025 to
+	// January 2026.
+	NamedByStartYear bool `json:"namedByStartYear,omitempty"`
+
+	// DraftingWeeks is how many weeks before the period starts drafting
+	// opens. Drafting closes at the first review.
+	DraftingWeeks int `json:"draftingWeeks,omitempty"`
+
+	// Reviews are the review meetings held before approval.
+	Reviews []Review `json:"reviews,omitempty"`
+
+	// ApprovalWeeks is how many weeks before the period starts plans must
+	// be approved. Zero defaults to DefaultApprovalWeeks; a negative value
+	// puts the deadline on the first day of the period.
+	ApprovalWeeks int `json:"approvalWeeks,omitempty"`
+
+	// CheckInWeeks is the check-in cadence during the period. Zero
+	// defaults to DefaultCheckInWeeks; a negative value disables
+	// check-ins.
+	CheckInWeeks int `json:"checkInWeeks,omitempty"`
+}
+
+// Review is a review meeting held a number of weeks before the period.
+type Review struct {
+	Name        string `json:"name"`
+	WeeksBefore int    `json:"weeksBefore"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.FiscalYearStartMonth == 0 {
+		c.FiscalYearStartMonth = 1
+	}
+	if c.DraftingWeeks == 0 {
+		c.DraftingWeeks = DefaultDraftingWeeks
+	}
+	if c.Reviews == nil {
+		c.Reviews = DefaultReviews
+	}
+	switch {
+	case c.ApprovalWeeks == 0:
+		c.ApprovalWeeks = DefaultApprovalWeeks
+	case c.ApprovalWeeks < 0:
+		c.ApprovalWeeks = 0
+	}
+	if c.CheckInWeeks == 0 {
+		c.CheckInWeeks = DefaultCheckInWeeks
+	}
+	return c
+}
+
+// Validate checks that the fiscal year start is a month and that the
+// cycle runs in order: drafting, then each review, then approval.
+func (c Config) Validate() error {
+	if c.FiscalYearStartMonth < 0 || c.FiscalYearStartMonth > 12 {
+		return fmt.Errorf("fiscalYearStartMonth must be 1-12, got %d", c.FiscalYearStartMonth)
+	}
+	c = c.withDefaults()
+	prev, prevName := c.DraftingWeeks, "drafting"
+	for _, r := range c.Reviews {
+		if r.Name == "" {
+			return fmt.Errorf("reviews need a name")
+		}
+		if r.WeeksBefore >= prev {
+			return fmt.Errorf("%s (%d weeks before) must come after %s (%d weeks before)", r.Name, r.WeeksBefore, prevName, prev)
+		}
+		prev, prevName = r.WeeksBefore, r.Name
+	}
+	if c.ApprovalWeeks >= prev {
+		return fmt.Errorf("approval (%d weeks before) must come after %s (%d weeks before)", c.ApprovalWeeks, prevName, prev)
+	}
+	return nil
+}
+
+// Event kinds.
+const (
+	EventDrafting = "drafting"
+	EventReview   = "review"
+	EventApproval = "approval"
+	EventStart    = "start"
+	EventCheckIn  = "check-in"
+	EventScoring  = "scoring"
+)
+
+// Event is a date or date range in a planning cycle.
+type Event struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Start and End are the first and last days of the event; they are
+	// equal for single-day events.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Document is a planning document written for a period.
+type Document struct {
+	Title string `json:"title"`
+	Kind  string `json:"kind"`
+	Owner string `json:"owner,omitempty"`
+	Path  string `json:"path"`
+}
+
+// Cycle is the planning cycle for one period.
+type Cycle struct {
+	Period    Period     `json:"period"`
+	Events    []Event    `json:"events"`
+	Documents []Document `json:"documents,omitempty"`
+}
+
+// Calendar is the planning cycles for a set of periods, in date order.
+type Calendar struct {
+	Config Config  `json:"config"`
+	Cycles []Cycle `json:"cycles"`
+}
+
+// New builds the calendar for periods. Documents are attached to the
+// cycle of their period; documents for periods not listed add a cycle.
+// Duplicate periods are merged.
+func New(c Config, periods []Period, docs map[string][]Document) (*Calendar, error) {
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid planning config: %w", err)
+	}
+	cal := &Calendar{Config: c}
+	index := map[string]int{}
+	add := func(p Period) int {
+		name := p.Name()
+		if i, ok := index[name]; ok {
+			return i
+		}
+		index[name] = len(cal.Cycles)
+		cal.Cycles = append(cal.Cycles, c.Cycle(p))
+		return len(cal.Cycles) - 1
+	}
+	for _, p := range periods {
+		add(p)
+	}
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p, err := c.ParsePeriod(name)
+		if err != nil {
+			return nil, err
+		}
+		i := add(p)
+		cal.Cycles[i].Documents = append(cal.Cycles[i].Documents, docs[name]...)
+	}
+	sort.SliceStable(cal.Cycles, func(i, j int) bool {
+		a, b := cal.Cycles[i].Period, cal.Cycles[j].Period
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.End.Before(b.End)
+	})
+	return cal, nil
+}
+
+// Cycle returns the planning events for a period. Dates that fall on a
+// weekend move to the Friday before.
+func (c Config) Cycle(p Period) Cycle {
+	c = c.withDefaults()
+	weeksBefore := func(n int) time.Time {
+		return workday(p.Start.AddDate(0, 0, -7*n))
+	}
+	single := func(kind, name string, d time.Time) Event {
+		return Event{Kind: kind, Name: name, Start: d, End: d}
+	}
+
+	approval := weeksBefore(c.ApprovalWeeks)
+	draftEnd := approval
+	if len(c.Reviews) > 0 {
+		draftEnd = weeksBefore(c.Reviews[0].WeeksBefore)
+	}
+	events := []Event{{
+		Kind:  EventDrafting,
+		Name:  "Drafting window",
+		Start: weeksBefore(c.DraftingWeeks),
+		End:   workday(draftEnd.AddDate(0, 0, -1)),
+	}}
+	for _, r := range c.Reviews {
+		events = append(events, single(EventReview, r.Name, weeksBefore(r.WeeksBefore)))
+	}
+	events = append(events,
+		single(EventApproval, "Approval deadline", approval),
+		single(EventStart, "Period starts", p.Start))
+
+	if c.CheckInWeeks > 0 {
+		var dates []time.Time
+		for d := p.Start.AddDate(0, 0, 7*c.CheckInWeeks); d.Before(p.End.AddDate(0, 0, -6)); d = d.AddDate(0, 0, 7*c.CheckInWeeks) {
+			dates = append(dates, workday(d))
+		}
+		for i, d := range dates {
+			events = append(events, single(EventCheckIn, fmt.Sprintf("Check-in %d of %d", i+1, len(dates)), d))
+		}
+	}
+	events = append(events, single(EventScoring, "Final scoring and retrospective", workday(p.End)))
+	return Cycle{Period: p, Events: events}
+}
+
+// workday moves a Saturday or Sunday to the Friday before.
+func workday(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, -2)
+	}
+	return d
+}
@@ -0,0 +1,187 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParsePeriod(t *testing.T) {
+	feb := Config{FiscalYearStartMonth: 2}
+	tests := []struct {
+		cfg        Config
+		in         string
+		name       string
+		start, end string
+	}{
+		{Config{}, "2026-Q1", "2026-Q1", "2026-01-01", "2026-03-31"},
+		{Config{}, "Q3 2026", "2026-Q3", "2026-07-01", "2026-09-30"},
+		{Config{}, "fy26 h2", "2026-H2", "2026-07-01", "2026-12-31"},
+		{Config{}, "FY2026", "FY2026", "2026-01-01", "2026-12-31"},
+		{feb, "FY2026-Q1", "FY2026-Q1", "2025-02-01", "2025-04-30"},
+		{feb, "Q4 FY2026", "FY2026-Q4", "2025-11-01", "2026-01-31"},
+		{feb, "FY2026", "FY2026", "2025-02-01", "2026-01-31"},
+		{Config{FiscalYearStartMonth: 7, NamedByStartYear: true}, "2026-H1", "FY2026-H1", "2026-07-01", "2026-12-31"},
+	}
+	for _, tt := range tests {
+		p, err := tt.cfg.ParsePeriod(tt.in)
+		if err != nil {
+			t.Errorf("ParsePeriod(%q): %v", tt.in, err)
+			continue
+		}
+		if p.Name() != tt.name || !p.Start.Equal(date(tt.start)) || !p.End.Equal(date(tt.end)) {
+			t.Errorf("ParsePeriod(%q) = %s %s..%s, want %s %s..%s", tt.in,
+				p.Name(), p.Start.Format(time.DateOnly), p.End.Format(time.DateOnly), tt.name, tt.start, tt.end)
+		}
+	}
+
+	for _, in := range []string{"", "Q5 2026", "2026-Q0", "next quarter"} {
+		if _, err := (Config{}).ParsePeriod(in); err == nil {
+			t.Errorf("ParsePeriod(%q) should fail", in)
+		}
+	}
+}
+
+func TestCycle(t *testing.T) {
+	p, _ := Config{}.ParsePeriod("2026-Q3") // Wed 2026-07-01
+	cy := Config{}.Cycle(p)
+
+	want := []struct{ kind, name, start, end string }{
+		{EventDrafting, "Drafting window", "2026-05-20", "2026-06-02"},
+		{EventReview, "Peer review", "2026-06-03", "2026-06-03"},
+		{EventReview, "Leadership review", "2026-06-17", "2026-06-17"},
+		{EventApproval, "Approval deadline", "2026-06-24", "2026-06-24"},
+		{EventStart, "Period starts", "2026-07-01", "2026-07-01"},
+	}
+	for i, w := range want {
+		e := cy.Events[i]
+		if e.Kind != w.kind || e.Name != w.name || !e.Start.Equal(date(w.start)) || !e.End.Equal(date(w.end)) {
+			t.Errorf("event %d = %s %q %s..%s, want %s %q %s..%s", i, e.Kind, e.Name,
+				e.Start.Format(time.DateOnly), e.End.Format(time.DateOnly), w.kind, w.name, w.start, w.end)
+		}
+	}
+
+	var checkIns []Event
+	for _, e := range cy.Events {
+		if e.Kind == EventCheckIn {
+			checkIns = append(checkIns, e)
+		}
+	}
+	if len(checkIns) != 6 || checkIns[5].Name != "Check-in 6 of 6" || !checkIns[0].Start.Equal(date("2026-07-15")) {
+		t.Errorf("check-ins = %+v", checkIns)
+	}
+	last := cy.Events[len(cy.Events)-1]
+	if last.Kind != EventScoring || !last.Start.Equal(date("2026-09-30")) {
+		t.Errorf("last event = %+v", last)
+	}
+
+	// Without reviews, drafting runs until approval.
+	p, _ = Config{}.ParsePeriod("2026-Q1") // Thu 2026-01-01
+	cy = Config{ApprovalWeeks: -1, CheckInWeeks: -1, Reviews: []Review{}}.Cycle(p)
+	if len(cy.Events) != 4 {
+		t.Fatalf("got %d events, want drafting, approval, start, scoring", len(cy.Events))
+	}
+	if !cy.Events[0].End.Equal(date("2025-12-31")) || !cy.Events[1].Start.Equal(date("2026-01-01")) {
+		t.Errorf("drafting should close the day before approval: %+v", cy.Events[:2])
+	}
+	if d := cy.Events[3].Start; !d.Equal(date("2026-03-31")) {
+		t.Errorf("scoring = %s", d.Format(time.DateOnly))
+	}
+
+	// Weekend dates move to the Friday before.
+	feb := Config{FiscalYearStartMonth: 2}
+	p, _ = feb.ParsePeriod("FY2026-Q1") // Sat 2025-02-01
+	if d := feb.Cycle(p).Events[0].Start; !d.Equal(date("2024-12-20")) {
+		t.Errorf("drafting opens %s, want Fri 2024-12-20", d.Format(time.DateOnly))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range []Config{
+		{FiscalYearStartMonth: 13},
+		{DraftingWeeks: 3, Reviews: []Review{{Name: "Review", WeeksBefore: 4}}},
+		{Reviews: []Review{{Name: "A", WeeksBefore: 2}, {Name: "B", WeeksBefore: 3}}},
+		{Reviews: []Review{{WeeksBefore: 2}}},
+		{ApprovalWeeks: 2},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%+v should be invalid", c)
+		}
+	}
+	if err := (Config{}).Validate(); err != nil {
+		t.Errorf("defaults: %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	cfg := Config{}
+	q3, _ := cfg.ParsePeriod("2026-Q3")
+	docs := map[string][]Document{
+		"2026-Q1": {{Title: "Payments, Q1", Kind: "okr", Owner: "Ana", Path: "payments.okr.json"}},
+		"2026-Q3": {{Title: "Org", Kind: "v2mom", Path: "org.v2mom.json"}},
+	}
+	cal, err := New(cfg, []Period{q3, q3}, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cal.Cycles) != 2 || cal.Cycles[0].Period.Name() != "2026-Q1" || len(cal.Cycles[1].Documents) != 1 {
+		t.Fatalf("cycles = %+v", cal.Cycles)
+	}
+
+	md := cal.ToMarkdown()
+	for _, want := range []string{
+		"## 2026-Q1 (Thu 2026-01-01 to Tue 2026-03-31)",
+		"- [Payments, Q1](payments.okr.json) (OKR, Ana)",
+		"| Wed 2026-05-20 to Tue 2026-06-02 | Drafting window |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := cal.WriteICS(&buf, date("2026-10-17")); err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:2026-q3-approval-4@structured-plan\r\n",
+		"DTSTAMP:20261017T000000Z\r\n",
+		"DTSTART;VALUE=DATE:20260624\r\nDTEND;VALUE=DATE:20260625\r\n",
+		"DESCRIPTION:Documents: Payments\\, Q1\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("ICS missing %q", want)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != len(cal.Cycles[0].Events)+len(cal.Cycles[1].Events) {
+		t.Errorf("got %d events", strings.Count(ics, "BEGIN:VEVENT"))
+	}
+
+	if _, err := New(cfg, nil, map[string][]Document{"soon": nil}); err == nil {
+		t.Error("expected an error for an unparseable period")
+	}
+}
+
+func TestFold(t *testing.T) {
+	s := "SUMMARY:" + strings.Repeat("é", 60)
+	for _, line := range strings.Split(fold(s), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets", len(line))
+		}
+	}
+	if got := strings.ReplaceAll(fold(s), "\r\n ", ""); got != s {
+		t.Errorf("unfolded = %q", got)
+	}
+}
@@ -0,0 +1,186 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ToMarkdown renders the calendar with a table of dates per cycle.
+func (cal *Calendar) ToMarkdown() string {
+	c := cal.Config.withDefaults()
+	var sb strings.Builder
+	sb.WriteString("# Planning Calendar\n\n")
+	fmt.Fprintf(&sb, "The fiscal year starts in %s. Drafting opens %d weeks before each period", time.Month(c.FiscalYearStartMonth), c.DraftingWeeks)
+	for _, r := range c.Reviews {
+		fmt.Fprintf(&sb, ", %s is %s", strings.ToLower(r.Name), weeks(r.WeeksBefore))
+	}
+	fmt.Fprintf(&sb, ", and plans must be approved %s.", weeks(c.ApprovalWeeks))
+	if c.CheckInWeeks > 0 {
+		fmt.Fprintf(&sb, " Progress is checked in every %s during the period.", plural(c.CheckInWeeks, "week"))
+	}
+	sb.WriteString(" Dates on a weekend move to the Friday before.\n\n")
+
+	if len(cal.Cycles) == 0 {
+		sb.WriteString("*No planning periods.*\n")
+		return sb.String()
+	}
+	for _, cy := range cal.Cycles {
+		fmt.Fprintf(&sb, "## %s (%s to %s)\n\n", cy.Period.Name(), formatDate(cy.Period.Start), formatDate(cy.Period.End))
+		if len(cy.Documents) > 0 {
+			sb.WriteString("Documents:\n\n")
+			for _, d := range cy.Documents {
+				fmt.Fprintf(&sb, "- [%s](%s) (%s", d.Title, d.Path, strings.ToUpper(d.Kind))
+				if d.Owner != "" {
+					fmt.Fprintf(&sb, ", %s", d.Owner)
+				}
+				sb.WriteString(")\n")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("| Date | Event |\n")
+		sb.WriteString("|------|-------|\n")
+		for _, e := range cy.Events {
+			when := formatDate(e.Start)
+			if !e.End.Equal(e.Start) {
+				when += " to " + formatDate(e.End)
+			}
+			fmt.Fprintf(&sb, "| %s | %s |\n", when, e.Name)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func formatDate(d time.Time) string {
+	return d.Format("Mon 2006-01-02")
+}
+
+func weeks(n int) string {
+	if n == 0 {
+		return "on the first day"
+	}
+	return plural(n, "week") + " before"
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// WriteICS writes the calendar as iCalendar (RFC 5545) with one all-day
+// event per date. Event UIDs are derived from the period and event, so
+// re-importing an updated calendar replaces the earlier events. stamp is
+// written as the DTSTAMP of every event.
+func (cal *Calendar) WriteICS(w io.Writer, stamp time.Time) error {
+	var events []ICSEvent
+	for _, cy := range cal.Cycles {
+		name := cy.Period.Name()
+		description := ""
+		if len(cy.Documents) > 0 {
+			titles := make([]string, len(cy.Documents))
+			for i, d := range cy.Documents {
+				titles[i] = d.Title
+			}
+			description = "Documents: " + strings.Join(titles, ", ")
+		}
+		for i, e := range cy.Events {
+			events = append(events, ICSEvent{
+				UID:         fmt.Sprintf("%s-%s-%d@structured-plan", strings.ToLower(name), e.Kind, i+1),
+				Summary:     name + " planning: " + e.Name,
+				Description: description,
+				Categories:  []string{"Planning", e.Kind},
+				Start:       e.Start,
+				End:         e.End,
+			})
+		}
+	}
+	return WriteICSEvents(w, "Planning Calendar", events, stamp)
+}
+
+// ICSEvent is an all-day iCalendar event.
+type ICSEvent struct {
+	// UID identifies the event across exports, so calendar clients update
+	// it instead of adding a copy.
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+
+	// Start and End are the first and last days of the event; they are
+	// equal for single-day events.
+	Start time.Time
+	End   time.Time
+}
+
+// WriteICSEvents writes events as an iCalendar (RFC 5545) calendar with
+// the given display name. stamp is written as the DTSTAMP of every event.
+func WriteICSEvents(w io.Writer, name string, events []ICSEvent, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		bw.WriteString(fold(s))
+		bw.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//grokify//structured-plan//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeText(name))
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + dtstamp)
+		line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + e.End.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				categories[i] = escapeText(c)
+			}
+			line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes an iCalendar TEXT value.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// fold splits a content line into lines of at most 75 octets, continued
+// with a leading space, without splitting UTF-8 sequences.
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var sb strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		sb.WriteString(s[:cut])
+		sb.WriteString("\r\n ")
+		s = s[cut:]
+		width = limit - 1
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
@@ -0,0 +1,152 @@
+// Package calendar generates the planning-cycle calendar for OKR and V2MOM
+// periods: when drafting opens, when drafts are reviewed, when plans must
+// be approved, and when progress is checked in during the period.
+//
+// Periods are fiscal: a fiscal year starts in a configurable month, and its
+// quarters and halves follow from that start. The calendar is written as
+// markdown for the planning docs and as iCalendar (ICS) for import into a
+// calendar application.
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period kinds.
+const (
+	KindQuarter = "quarter"
+	KindHalf    = "half"
+	KindAnnual  = "annual"
+)
+
+// Period is a fiscal quarter, half, or year.
+type Period struct {
+	FiscalYear int    `json:"fiscalYear"`
+	Kind       string `json:"kind"`
+	Index      int    `json:"index,omitempty"` // 1-4 for quarters, 1-2 for halves
+
+	// Start and End are the first and last days of the period.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Name returns the canonical name of the period, e.g. "FY2026-Q1" or
+// "FY2026". Quarters and halves of calendar fiscal years are named like
+// "2026-Q1", matching OKR period metadata.
+func (p Period) Name() string {
+	year := "FY" + strconv.Itoa(p.FiscalYear)
+	if p.Kind != KindAnnual && p.fiscalYearStart().Month() == time.January {
+		year = strconv.Itoa(p.FiscalYear)
+	}
+	switch p.Kind {
+	case KindQuarter:
+		return fmt.Sprintf("%s-Q%d", year, p.Index)
+	case KindHalf:
+		return fmt.Sprintf("%s-H%d", year, p.Index)
+	}
+	return year
+}
+
+// fiscalYearStart returns the first day of the period's fiscal year.
+func (p Period) fiscalYearStart() time.Time {
+	if p.Kind == KindAnnual {
+		return p.Start
+	}
+	return p.Start.AddDate(0, -monthsIn(p.Kind)*(p.Index-1), 0)
+}
+
+// monthsIn returns the length of a period kind in months.
+func monthsIn(kind string) int {
+	switch kind {
+	case KindQuarter:
+		return 3
+	case KindHalf:
+		return 6
+	}
+	return 12
+}
+
+// FiscalYearStart returns the first day of a fiscal year.
+func (c Config) FiscalYearStart(fiscalYear int) time.Time {
+	c = c.withDefaults()
+	year := fiscalYear
+	if c.FiscalYearStartMonth > 1 && !c.NamedByStartYear {
+		year--
+	}
+	return time.Date(year, time.Month(c.FiscalYearStartMonth), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NewPeriod returns a fiscal period. Index is ignored for annual periods.
+func (c Config) NewPeriod(fiscalYear int, kind string, index int) (Period, error) {
+	switch kind {
+	case KindQuarter:
+		if index < 1 || index > 4 {
+			return Period{}, fmt.Errorf("quarter must be 1-4, got %d", index)
+		}
+	case KindHalf:
+		if index < 1 || index > 2 {
+			return Period{}, fmt.Errorf("half must be 1-2, got %d", index)
+		}
+	case KindAnnual:
+		index = 1
+	default:
+		return Period{}, fmt.Errorf("unknown period kind %q (valid: quarter, half, annual)", kind)
+	}
+	months := monthsIn(kind)
+	start := c.FiscalYearStart(fiscalYear).AddDate(0, (index-1)*months, 0)
+	p := Period{
+		FiscalYear: fiscalYear,
+		Kind:       kind,
+		Start:      start,
+		End:        start.AddDate(0, months, -1),
+	}
+	if kind != KindAnnual {
+		p.Index = index
+	}
+	return p, nil
+}
+
+// Quarters returns the four quarters of a fiscal year.
+func (c Config) Quarters(fiscalYear int) []Period {
+	periods := make([]Period, 4)
+	for i := range periods {
+		periods[i], _ = c.NewPeriod(fiscalYear, KindQuarter, i+1)
+	}
+	return periods
+}
+
+var (
+	yearFirst = regexp.MustCompile(`^(?:FY)?(\d{4}|\d{2})(?:[-/ ]?([QH])([1-4]))?$`)
+	partFirst = regexp.MustCompile(`^([QH])([1-4])[-/ ]?(?:FY)?(\d{4}|\d{2})$`)
+)
+
+// ParsePeriod parses a period name such as "2026-Q1", "FY2026 Q3",
+// "Q2 FY26", "2026-H1", or "FY2026". Two-digit years are in the 2000s.
+func (c Config) ParsePeriod(s string) (Period, error) {
+	norm := strings.ToUpper(strings.Join(strings.Fields(s), " "))
+	var year, part, index string
+	if m := yearFirst.FindStringSubmatch(norm); m != nil {
+		year, part, index = m[1], m[2], m[3]
+	} else if m := partFirst.FindStringSubmatch(norm); m != nil {
+		part, index, year = m[1], m[2], m[3]
+	} else {
+		return Period{}, fmt.Errorf("unrecognized period %q (e.g. 2026-Q1, FY2026-H2, FY2026)", s)
+	}
+
+	fy, _ := strconv.Atoi(year)
+	if len(year) == 2 {
+		fy += 2000
+	}
+	n, _ := strconv.Atoi(index)
+	switch part {
+	case "Q":
+		return c.NewPeriod(fy, KindQuarter, n)
+	case "H":
+		return c.NewPeriod(fy, KindHalf, n)
+	}
+	return c.NewPeriod(fy, KindAnnual, 0)
+}
@@ -2,27 +2,82 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/agentplexus/structured-evaluation/evaluation"
+	"github.com/grokify/structured-plan/assets"
+	"github.com/grokify/structured-plan/badge"
+	"github.com/grokify/structured-plan/batch"
+	"github.com/grokify/structured-plan/budget"
+	"github.com/grokify/structured-plan/bundle"
+	"github.com/grokify/structured-plan/calendar"
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/conditions"
+	"github.com/grokify/structured-plan/diff"
+	"github.com/grokify/structured-plan/directory"
+	"github.com/grokify/structured-plan/goals"
 	"github.com/grokify/structured-plan/goals/okr"
-	okrrender "github.com/grokify/structured-plan/goals/okr/render"
-	okrmarp "github.com/grokify/structured-plan/goals/okr/render/marp"
 	"github.com/grokify/structured-plan/goals/v2mom"
 	v2momrender "github.com/grokify/structured-plan/goals/v2mom/render"
+	v2momhtml "github.com/grokify/structured-plan/goals/v2mom/render/html"
 	v2mommarp "github.com/grokify/structured-plan/goals/v2mom/render/marp"
+	"github.com/grokify/structured-plan/history"
+	"github.com/grokify/structured-plan/ide"
+	"github.com/grokify/structured-plan/ids"
+	"github.com/grokify/structured-plan/importer"
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/l10n"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/lint"
+	"github.com/grokify/structured-plan/merge"
+	"github.com/grokify/structured-plan/notify"
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/render"
+	_ "github.com/grokify/structured-plan/render/builtin"
+	"github.com/grokify/structured-plan/render/docx"
+	"github.com/grokify/structured-plan/render/profile"
 	"github.com/grokify/structured-plan/requirements/mrd"
 	"github.com/grokify/structured-plan/requirements/prd"
-	"github.com/grokify/structured-plan/requirements/prd/render/terminal"
+	"github.com/grokify/structured-plan/requirements/prd/editor"
+	"github.com/grokify/structured-plan/requirements/prd/export/github"
+	"github.com/grokify/structured-plan/requirements/prd/export/jira"
+	"github.com/grokify/structured-plan/requirements/prd/llmeval"
+	prdrender "github.com/grokify/structured-plan/requirements/prd/render"
+	prdhtml "github.com/grokify/structured-plan/requirements/prd/render/html"
+	prdmarp "github.com/grokify/structured-plan/requirements/prd/render/marp"
+	"github.com/grokify/structured-plan/requirements/prd/render/tui"
 	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/roadmap"
 	"github.com/grokify/structured-plan/schema"
+	"github.com/grokify/structured-plan/serve"
+	"github.com/grokify/structured-plan/snippets"
+	"github.com/grokify/structured-plan/trace"
+	"github.com/grokify/structured-plan/validation"
+	"github.com/grokify/structured-plan/wizard"
+	"github.com/grokify/structured-plan/workspace"
+	"github.com/grokify/structured-plan/xref"
+	"github.com/grokify/structured-plan/yamlconv"
 )
 
 // Set by GoReleaser ldflags
@@ -33,7 +88,9 @@ var (
 )
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	cmd, err := rootCmd.ExecuteC()
+	sendNotification(cmd, err)
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -50,26 +107,44 @@ Document types:
     - TRD (Technical Requirements Document)
 
   Goals:
-    - OKR (Objectives and Key Results) - coming soon
+    - OKR (Objectives and Key Results)
     - V2MOM (Vision, Values, Methods, Obstacles, Measures)
 
-  Roadmap (coming soon):
+  Roadmap:
     - Standalone roadmaps for portfolio/product planning
 
-It can convert document JSON files to markdown with Pandoc-compatible YAML
-frontmatter, generate Marp presentations, and validate files against their
-respective schemas.
+It can convert document JSON (or YAML) files to markdown with
+Pandoc-compatible YAML frontmatter, generate Marp presentations, and validate
+files against their respective schemas.
 
 Example usage:
   splan requirements prd generate myproduct.prd.json
   splan goals v2mom validate my-v2mom.json
   splan goals v2mom generate marp my-v2mom.json -o slides.md
+  splan roadmap generate platform.roadmap.json
   splan schema generate --type prd`,
 	Version: version,
 }
 
+var rootFlags struct {
+	lenient       bool
+	inputFormat   string
+	docProfile    string
+	renderProfile string
+	flags         []string
+	notifyWebhook string
+	notifySecret  string
+}
+
 func init() {
 	rootCmd.SetVersionTemplate("splan version {{.Version}} (commit: " + commit + ", built: " + date + ")\n")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.lenient, "lenient", false, "Recover from field type errors (bad dates, wrong-typed numbers) and report them instead of failing")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.inputFormat, "input-format", "auto", "Input document format: auto, json, yaml (auto detects .yaml/.yml)")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.renderProfile, "profile", "", "Render profile for generate: exec, engineering, sales, external, or a workspace renderProfiles entry")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.docProfile, "doc-profile", "", "Document profile for metadata.conditions, instead of metadata.profile")
+	rootCmd.PersistentFlags().StringSliceVar(&rootFlags.flags, "flag", nil, "Set a flag for metadata.conditions: name or name=false (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.notifyWebhook, "notify-webhook", "", "POST a JSON summary of validate, score, and generate runs to this URL (default: $SPLAN_NOTIFY_WEBHOOK)")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.notifySecret, "notify-secret", "", "Key for the webhook's HMAC-SHA256 signature header (default: $SPLAN_NOTIFY_SECRET)")
 }
 
 // ============================================================================
@@ -93,7 +168,29 @@ func init() {
 	rootCmd.AddCommand(requirementsCmd)
 	rootCmd.AddCommand(goalsCmd)
 	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(workspaceCmd)
+	rootCmd.AddCommand(scoreCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(previewCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(siteCmd)
+	rootCmd.AddCommand(l10nCmd)
+	rootCmd.AddCommand(evidenceCmd)
+	rootCmd.AddCommand(roadmapCmd)
+	rootCmd.AddCommand(simulateCmd)
+	rootCmd.AddCommand(traceCmd)
+	rootCmd.AddCommand(commentsCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(queryCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(peopleCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(lifecycleCmd)
 
 	// Add requirements subcommands
 	requirementsCmd.AddCommand(prdCmd)
@@ -111,6 +208,8 @@ func init() {
 
 var mergeFlags struct {
 	output string
+	stream bool
+	typed  string
 }
 
 var mergeCmd = &cobra.Command{
@@ -119,22 +218,56 @@ var mergeCmd = &cobra.Command{
 	Long: `Merge multiple JSON files into one.
 
 The files are merged in the order they are provided. For nested objects,
-values are recursively merged. For arrays, values are concatenated.`,
+values are recursively merged. For arrays, values are concatenated.
+
+YAML files, named .yaml or .yml or read with --input-format yaml, are
+merged as their JSON equivalents. The output is always JSON.
+
+For very large inputs, use --stream to spool array elements to a temporary
+file instead of holding them in memory, or --typed to decode each file into
+the concrete document type (prd, mrd, trd) and merge the structs. Typed
+merges only override fields with non-zero values.`,
 	Example: `  splan merge file1.json file2.json -o merged.json
-  splan merge base.prd.json overrides.json -o final.prd.json`,
+  splan merge base.prd.json overrides.json -o final.prd.json
+  splan merge --stream part-*.json -o aggregate.json
+  splan merge --typed prd team-a.prd.json team-b.prd.json -o combined.prd.json`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runMerge,
 }
 
 func init() {
 	mergeCmd.Flags().StringVarP(&mergeFlags.output, "output", "o", "merged.json", "Output file name")
+	mergeCmd.Flags().BoolVar(&mergeFlags.stream, "stream", false, "Stream array elements through a temporary file to limit memory use")
+	mergeCmd.Flags().StringVar(&mergeFlags.typed, "typed", "", "Merge via concrete document structs: prd, mrd, trd")
 }
 
 func runMerge(cmd *cobra.Command, args []string) error {
+	if mergeFlags.stream && mergeFlags.typed != "" {
+		return fmt.Errorf("--stream and --typed cannot be used together")
+	}
+
+	var err error
+	switch {
+	case mergeFlags.stream:
+		err = runMergeStream(args)
+	case mergeFlags.typed != "":
+		err = runMergeTyped(args)
+	default:
+		err = runMergeMaps(args)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully merged %d files into %s\n", len(args), mergeFlags.output)
+	return nil
+}
+
+func runMergeMaps(files []string) error {
 	var mergedData map[string]interface{}
 
-	for _, file := range args {
-		data, err := os.ReadFile(file)
+	for _, file := range files {
+		data, err := readSourceJSON(file)
 		if err != nil {
 			return fmt.Errorf("reading file %s: %w", file, err)
 		}
@@ -147,1772 +280,10609 @@ func runMerge(cmd *cobra.Command, args []string) error {
 		if mergedData == nil {
 			mergedData = currentData
 		} else {
-			mergedData = deepMerge(mergedData, currentData)
+			mergedData = merge.Maps(mergedData, currentData)
 		}
 	}
 
-	mergedJSON, err := json.MarshalIndent(mergedData, "", "  ")
+	return writeMergedJSON(mergedData)
+}
+
+// runMergeStream writes the merge to a temporary file next to the output
+// and renames it into place, so an output that is also an input is read
+// whole before it is replaced.
+func runMergeStream(files []string) error {
+	output := mergeFlags.output
+	f, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".*.tmp")
 	if err != nil {
-		return fmt.Errorf("marshaling merged data to json: %w", err)
+		return fmt.Errorf("creating output file %s: %w", output, err)
 	}
-
-	if err := os.WriteFile(mergeFlags.output, mergedJSON, 0600); err != nil {
-		return fmt.Errorf("writing merged json to file %s: %w", mergeFlags.output, err)
+	defer os.Remove(f.Name())
+	if err := mergeStream(f, files); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing merged json to file %s: %w", output, err)
+	}
+	if err := os.Rename(f.Name(), output); err != nil {
+		return fmt.Errorf("writing merged json to file %s: %w", output, err)
 	}
-
-	fmt.Printf("Successfully merged %d files into %s\n", len(args), mergeFlags.output)
 	return nil
 }
 
-func deepMerge(a, b map[string]interface{}) map[string]interface{} {
-	for k, v := range b {
-		if va, ok := a[k]; ok {
-			// Both have this key - check types for merging
-			switch vaTyped := va.(type) {
-			case map[string]interface{}:
-				// Both are maps - recursively merge
-				if vMap, ok := v.(map[string]interface{}); ok {
-					a[k] = deepMerge(vaTyped, vMap)
-					continue
-				}
-			case []interface{}:
-				// Both are arrays - concatenate them
-				if vSlice, ok := v.([]interface{}); ok {
-					a[k] = append(vaTyped, vSlice...)
-					continue
-				}
+// mergeStream merges files as merge.Stream does. YAML files are converted
+// to JSON first, so they are held in memory while they are merged.
+func mergeStream(w io.Writer, files []string) error {
+	s, err := merge.NewStreamer()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	for _, file := range files {
+		format, err := inputFormat(file)
+		if err != nil {
+			return err
+		}
+		if format == yamlconv.FormatYAML {
+			var data []byte
+			if data, err = readSourceJSON(file); err == nil {
+				err = s.Add(bytes.NewReader(data))
 			}
+		} else {
+			err = s.AddFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("merging file %s: %w", file, err)
 		}
-		// Key doesn't exist in a, or types don't match - use b's value
-		a[k] = v
 	}
-	return a
+	return s.Encode(w)
 }
 
-// ============================================================================
-// Goals Parent Command
-// ============================================================================
+func runMergeTyped(files []string) error {
+	var (
+		merged any
+		err    error
+	)
+	switch strings.ToLower(mergeFlags.typed) {
+	case "prd":
+		merged, err = mergeTypedFiles[prd.Document](files)
+	case "mrd":
+		merged, err = mergeTypedFiles[mrd.Document](files)
+	case "trd":
+		merged, err = mergeTypedFiles[trd.Document](files)
+	default:
+		return fmt.Errorf("unknown document type %q (valid: prd, mrd, trd)", mergeFlags.typed)
+	}
+	if err != nil {
+		return err
+	}
+	return writeMergedJSON(merged)
+}
 
-var goalsCmd = &cobra.Command{
-	Use:   "goals",
-	Short: "Work with goal frameworks (OKR, V2MOM)",
-	Long: `Commands for generating and validating goal framework documents.
+// mergeTypedFiles decodes each file into a T and merges them, in order, as
+// merge.TypedFiles does, reading YAML files as JSON.
+func mergeTypedFiles[T any](files []string) (*T, error) {
+	var merged T
+	for _, file := range files {
+		data, err := readSourceJSON(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading file %s: %w", file, err)
+		}
+		var doc T
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("unmarshaling json from file %s: %w", file, err)
+		}
+		if err := merge.Structs(&merged, &doc); err != nil {
+			return nil, fmt.Errorf("merging file %s: %w", file, err)
+		}
+	}
+	return &merged, nil
+}
 
-Supported frameworks:
-  - v2mom: Vision, Values, Methods, Obstacles, Measures
-  - okr: Objectives and Key Results (coming soon)`,
+func writeMergedJSON(v any) error {
+	mergedJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling merged data to json: %w", err)
+	}
+
+	if err := os.WriteFile(mergeFlags.output, mergedJSON, 0600); err != nil {
+		return fmt.Errorf("writing merged json to file %s: %w", mergeFlags.output, err)
+	}
+	return nil
 }
 
 // ============================================================================
-// V2MOM Commands
+// Workspace Commands
 // ============================================================================
 
-var v2momCmd = &cobra.Command{
-	Use:   "v2mom",
-	Short: "Work with V2MOM documents",
-	Long: `Commands for generating and validating V2MOM (Vision, Values, Methods, Obstacles, Measures) documents.
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Work with a directory of planning documents",
+	Long: `Commands that operate on every planning document in a directory tree.
 
-V2MOM supports:
-  - JSON validation against the V2MOM schema
-  - Marp markdown slide generation
-  - Both traditional flat V2MOM and OKR-aligned nested structures
-  - Multiple terminology modes (V2MOM, OKR, hybrid)`,
+Documents are recognized by filename suffix: *.prd.json, *.mrd.json,
+*.trd.json, *.okr.json, *.v2mom.json, and *.roadmap.json, or the same with
+.yaml or .yml. Hidden directories are skipped.`,
 }
 
-var v2momValidateFlags struct {
-	structure string
+var workspaceDashboardFlags struct {
+	output     string
+	agingDays  int
+	staleDays  int
+	jsonOutput bool
 }
 
-var v2momValidateCmd = &cobra.Command{
-	Use:   "validate FILE",
-	Short: "Validate a V2MOM JSON file",
-	Long: `Validate a V2MOM JSON file against the schema and structural rules.
-
-Structure modes:
-  flat    - Traditional V2MOM (measures/obstacles at V2MOM level only)
-  nested  - OKR-aligned (measures under Methods, global obstacles allowed)
-  hybrid  - Both levels allowed (default)
-
-Examples:
-  splan goals v2mom validate my-v2mom.json
-  splan goals v2mom validate my-v2mom.json --structure=nested`,
-	Args: cobra.ExactArgs(1),
-	RunE: runV2MOMValidate,
+var workspaceDashboardCmd = &cobra.Command{
+	Use:   "dashboard [dir]",
+	Short: "Generate an HTML health dashboard for all documents",
+	Long: `Generate a single sortable HTML page summarizing every planning document
+in a directory tree.
+
+For each document the dashboard shows:
+  - Score and grade (PRD completeness, OKR/V2MOM progress)
+  - PRD scoring decision
+  - Last updated date and freshness (current, aging, stale)
+  - Unresolved open items and open risks
+  - Roadmap phase (or V2MOM project) completion
+
+Documents that fail to load are listed with their error. Use --lenient to
+recover from field type errors instead.`,
+	Example: `  splan workspace dashboard -o dashboard.html
+  splan workspace dashboard docs/ --stale-days 60 -o dashboard.html
+  splan workspace dashboard docs/ --json -o workspace.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceDashboard,
 }
 
-var v2momGenerateCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "Generate output from V2MOM",
-	Long:  `Generate various output formats from a V2MOM JSON file.`,
-}
+func init() {
+	workspaceDashboardCmd.Flags().StringVarP(&workspaceDashboardFlags.output, "output", "o", "dashboard.html", "Output file")
+	workspaceDashboardCmd.Flags().IntVar(&workspaceDashboardFlags.agingDays, "aging-days", 30, "Days since last update before a document is aging")
+	workspaceDashboardCmd.Flags().IntVar(&workspaceDashboardFlags.staleDays, "stale-days", 90, "Days since last update before a document is stale")
+	workspaceDashboardCmd.Flags().BoolVar(&workspaceDashboardFlags.jsonOutput, "json", false, "Write the summary as JSON instead of HTML")
 
-var v2momGenerateMarpFlags struct {
-	output      string
-	theme       string
-	terminology string
+	workspaceCmd.AddCommand(workspaceDashboardCmd)
 }
 
-var v2momGenerateMarpCmd = &cobra.Command{
-	Use:   "marp FILE",
-	Short: "Generate Marp markdown slides",
-	Long: `Generate Marp markdown presentation slides from a V2MOM JSON file.
+func runWorkspaceDashboard(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
 
-Themes:
-  default   - Clean gradient theme (default)
-  corporate - Professional blue theme
-  minimal   - Simple grayscale theme
+	ws, err := workspace.Scan(root, workspace.Options{
+		AgingAfter: time.Duration(workspaceDashboardFlags.agingDays) * 24 * time.Hour,
+		StaleAfter: time.Duration(workspaceDashboardFlags.staleDays) * 24 * time.Hour,
+		Lenient:    rootFlags.lenient,
+	})
+	if err != nil {
+		return err
+	}
+	if len(ws.Documents) == 0 {
+		return fmt.Errorf("no planning documents found in %s", root)
+	}
 
-Terminology:
-  v2mom  - Use V2MOM terms: Methods, Measures, Obstacles (default)
-  okr    - Use OKR terms: Objectives, Key Results, Risks
-  hybrid - Show both: Methods (Objectives), Measures (Key Results)
+	var buf bytes.Buffer
+	if workspaceDashboardFlags.jsonOutput {
+		data, err := json.MarshalIndent(ws, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling workspace summary: %w", err)
+		}
+		buf.Write(data)
+	} else if err := ws.WriteHTML(&buf); err != nil {
+		return err
+	}
 
-Examples:
-  splan goals v2mom generate marp my-v2mom.json
-  splan goals v2mom generate marp my-v2mom.json -o slides.md
-  splan goals v2mom generate marp my-v2mom.json --theme=corporate --terminology=okr`,
-	Args: cobra.ExactArgs(1),
-	RunE: runV2MOMMarpGenerate,
-}
+	if err := os.WriteFile(workspaceDashboardFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
 
-var v2momInitFlags struct {
-	name      string
-	output    string
-	structure string
+	fmt.Printf("Generated: %s (%d documents)\n", workspaceDashboardFlags.output, len(ws.Documents))
+	return nil
 }
 
-var v2momInitCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Initialize a new V2MOM template",
-	Long: `Create a new V2MOM JSON template file with example content.
-
-Structure modes:
-  flat   - Traditional V2MOM with measures/obstacles at top level
-  nested - OKR-aligned with measures under methods (default)
-  hybrid - Both levels with examples
+var workspaceComplianceFlags struct {
+	output string
+	format string
+}
 
-Examples:
-  splan goals v2mom init
-  splan goals v2mom init --name "FY2026 Product Strategy"
-  splan goals v2mom init --name "Engineering Goals" -o engineering-v2mom.json --structure=nested`,
-	RunE: runV2MOMInit,
+var workspaceComplianceCmd = &cobra.Command{
+	Use:   "compliance [dir]",
+	Short: "Export a compliance control matrix across documents",
+	Long: `Export a control-by-framework matrix showing which documents claim which
+compliance controls, for audit preparation.
+
+Claims are collected from PRD securityModel.complianceControls and TRD
+securityDesign.compliance. TRDs list frameworks without individual controls,
+so their claims appear in a framework-level row. Framework names are
+normalized (e.g., "SOC 2 Type II" becomes SOC2).
+
+The output format is taken from --format, or inferred from the output file
+extension (.csv for CSV, markdown otherwise).`,
+	Example: `  splan workspace compliance -o compliance.md
+  splan workspace compliance docs/ -o compliance.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceCompliance,
 }
 
 func init() {
-	// V2MOM validate flags
-	v2momValidateCmd.Flags().StringVar(&v2momValidateFlags.structure, "structure", "", "Structure mode to validate against (flat, nested, hybrid)")
-
-	// V2MOM generate marp flags
-	v2momGenerateMarpCmd.Flags().StringVarP(&v2momGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
-	v2momGenerateMarpCmd.Flags().StringVar(&v2momGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
-	v2momGenerateMarpCmd.Flags().StringVar(&v2momGenerateMarpFlags.terminology, "terminology", "", "Display terminology (v2mom, okr, hybrid)")
-
-	// V2MOM init flags
-	v2momInitCmd.Flags().StringVar(&v2momInitFlags.name, "name", "My V2MOM", "Name for the V2MOM")
-	v2momInitCmd.Flags().StringVarP(&v2momInitFlags.output, "output", "o", "v2mom.json", "Output file path")
-	v2momInitCmd.Flags().StringVar(&v2momInitFlags.structure, "structure", "nested", "Structure mode (flat, nested, hybrid)")
+	workspaceComplianceCmd.Flags().StringVarP(&workspaceComplianceFlags.output, "output", "o", "compliance.md", "Output file")
+	workspaceComplianceCmd.Flags().StringVar(&workspaceComplianceFlags.format, "format", "", "Output format: markdown, csv (default: from output extension)")
 
-	// Add subcommands
-	v2momGenerateCmd.AddCommand(v2momGenerateMarpCmd)
-	v2momCmd.AddCommand(v2momValidateCmd)
-	v2momCmd.AddCommand(v2momGenerateCmd)
-	v2momCmd.AddCommand(v2momInitCmd)
+	workspaceCmd.AddCommand(workspaceComplianceCmd)
 }
 
-func runV2MOMValidate(cmd *cobra.Command, args []string) error {
-	filepath := args[0]
+func runWorkspaceCompliance(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
 
-	// Check file exists
-	if _, err := os.Stat(filepath); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", filepath)
+	format := strings.ToLower(workspaceComplianceFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.EqualFold(filepath.Ext(workspaceComplianceFlags.output), ".csv") {
+			format = "csv"
+		}
 	}
 
-	// Read and parse V2MOM
-	v, err := v2mom.ReadFile(filepath)
+	paths, err := workspace.Discover(root)
 	if err != nil {
-		return fmt.Errorf("reading V2MOM: %w", err)
+		return err
 	}
-
-	// Set up validation options
-	opts := v2mom.DefaultValidationOptions()
-	if v2momValidateFlags.structure != "" {
-		opts.Structure = v2momValidateFlags.structure
+	matrix, err := workspace.BuildComplianceMatrix(paths, workspace.Options{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
 	}
 
-	// Validate
-	errs := v.Validate(opts)
-
-	// Report results
-	errors := v2mom.Errors(errs)
-	warnings := v2mom.Warnings(errs)
-
-	if len(warnings) > 0 {
-		fmt.Println("Warnings:")
-		for _, w := range warnings {
-			fmt.Printf("  - %s\n", w)
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(matrix.ToMarkdown())
+	case "csv":
+		if err := matrix.WriteCSV(&buf); err != nil {
+			return err
 		}
-		fmt.Println()
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, csv)", workspaceComplianceFlags.format)
 	}
 
-	if len(errors) > 0 {
-		fmt.Println("Errors:")
-		for _, e := range errors {
-			fmt.Printf("  - %s\n", e)
-		}
-		return fmt.Errorf("validation failed with %d error(s)", len(errors))
+	if err := os.WriteFile(workspaceComplianceFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
 	}
 
-	// Print success info
-	fmt.Printf("Valid V2MOM: %s\n", filepath)
-	fmt.Printf("  Structure: %s\n", v.GetStructure())
-	fmt.Printf("  Methods: %d\n", len(v.Methods))
-	fmt.Printf("  Total Measures: %d\n", len(v.AllMeasures()))
-	fmt.Printf("  Total Obstacles: %d\n", len(v.AllObstacles()))
+	fmt.Printf("Generated: %s (%d controls, %d frameworks, %d documents)\n",
+		workspaceComplianceFlags.output, len(matrix.Controls), len(matrix.Frameworks), len(matrix.Documents))
+	return nil
+}
 
-	if v.Metadata != nil && v.Metadata.Name != "" {
-		fmt.Printf("  Name: %s\n", v.Metadata.Name)
-	}
+var workspaceLicensesFlags struct {
+	output string
+	format string
+}
 
-	return nil
+var workspaceLicensesCmd = &cobra.Command{
+	Use:   "licenses [dir]",
+	Short: "Report copyleft-licensed and single-vendor technologies across TRDs",
+	Long: `Report the technologies in the TRDs' technology stacks that need
+procurement or legal review:
+
+  - Copyleft licenses, such as GPL, AGPL, and SSPL, and weak copyleft
+    licenses such as LGPL and MPL
+  - Single-vendor dependencies: technologies with a vendor and a proprietary,
+    source-available, or unrecorded license
+  - Technologies whose license is missing or not recognized
+
+Licenses are read from each technology's "license" field as SPDX identifiers
+or expressions; "MIT OR GPL-3.0" counts as the less restrictive choice.
+The markdown report merges uses of the same technology across TRDs. The CSV
+lists one row per use.
+
+The output format is taken from --format, or inferred from the output file
+extension (.csv for CSV, markdown otherwise).`,
+	Example: `  splan workspace licenses -o licenses.md
+  splan workspace licenses docs/ -o licenses.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceLicenses,
 }
 
-func runV2MOMMarpGenerate(cmd *cobra.Command, args []string) error {
-	inputPath := args[0]
+func init() {
+	workspaceLicensesCmd.Flags().StringVarP(&workspaceLicensesFlags.output, "output", "o", "licenses.md", "Output file")
+	workspaceLicensesCmd.Flags().StringVar(&workspaceLicensesFlags.format, "format", "", "Output format: markdown, csv (default: from output extension)")
 
-	// Check file exists
-	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", inputPath)
-	}
+	workspaceCmd.AddCommand(workspaceLicensesCmd)
+}
 
-	// Read and parse V2MOM
-	v, err := v2mom.ReadFile(inputPath)
-	if err != nil {
-		return fmt.Errorf("reading V2MOM: %w", err)
+func runWorkspaceLicenses(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
 	}
 
-	// Create renderer and options
-	renderer := v2mommarp.New()
-	opts := v2momrender.DefaultOptions()
-	opts.Theme = v2momGenerateMarpFlags.theme
-	if v2momGenerateMarpFlags.terminology != "" {
-		opts.Terminology = v2momGenerateMarpFlags.terminology
+	format := strings.ToLower(workspaceLicensesFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.EqualFold(filepath.Ext(workspaceLicensesFlags.output), ".csv") {
+			format = "csv"
+		}
 	}
 
-	// Render
-	output, err := renderer.Render(v, opts)
+	paths, err := workspace.Discover(root)
 	if err != nil {
-		return fmt.Errorf("rendering Marp: %w", err)
+		return err
+	}
+	report, err := workspace.BuildLicenseReport(paths, workspace.Options{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
 	}
 
-	// Write output
-	if v2momGenerateMarpFlags.output != "" {
-		// Ensure output directory exists
-		dir := filepath.Dir(v2momGenerateMarpFlags.output)
-		if dir != "." && dir != "" {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("creating output directory: %w", err)
-			}
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "csv":
+		if err := report.WriteCSV(&buf); err != nil {
+			return err
 		}
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, csv)", workspaceLicensesFlags.format)
+	}
 
-		if err := os.WriteFile(v2momGenerateMarpFlags.output, output, 0600); err != nil {
-			return fmt.Errorf("writing output: %w", err)
-		}
-		fmt.Printf("Generated: %s\n", v2momGenerateMarpFlags.output)
-	} else {
-		// Write to stdout
-		fmt.Print(string(output))
+	if err := os.WriteFile(workspaceLicensesFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
 	}
 
+	fmt.Printf("Generated: %s (%d copyleft, %d single-vendor, %d unknown licenses in %d TRDs)\n",
+		workspaceLicensesFlags.output, len(report.Copyleft), len(report.SingleVendor), len(report.UnknownLicense), report.Documents)
 	return nil
 }
 
-func runV2MOMInit(cmd *cobra.Command, args []string) error {
-	// Check if file already exists
-	if _, err := os.Stat(v2momInitFlags.output); err == nil {
-		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", v2momInitFlags.output)
-	}
+var workspaceCalendarFlags struct {
+	output          string
+	format          string
+	periods         []string
+	fiscalYear      int
+	fiscalYearStart int
+}
 
-	// Create template based on structure
-	var template *v2mom.V2MOM
+var workspaceCalendarCmd = &cobra.Command{
+	Use:   "calendar [dir]",
+	Short: "Generate the planning-cycle calendar as markdown or ICS",
+	Long: `Generate the calendar that drives the planning process: for each period,
+when drafting opens, the review dates, the approval deadline, the check-ins
+during the period, and the final scoring.
+
+Periods are taken from the metadata of the OKRs ("period") and V2MOMs
+("fiscalYear" and "quarter") in the workspace, and from --period and
+--fiscal-year. Each period lists the documents written for it.
+
+The fiscal year and cycle are read from the "planning" section of the
+workspace manifest (splan.workspace.json):
+
+  "planning": {
+    "fiscalYearStartMonth": 2,
+    "draftingWeeks": 6,
+    "reviews": [
+      {"name": "Peer review", "weeksBefore": 4},
+      {"name": "Leadership review", "weeksBefore": 2}
+    ],
+    "approvalWeeks": 1,
+    "checkInWeeks": 2
+  }
+
+The values shown are the defaults, except that the fiscal year starts in
+January by default. Fiscal years are named after the calendar year they end
+in unless "namedByStartYear" is set.
+
+The output format is taken from --format, or inferred from the output file
+extension (.ics for iCalendar, markdown otherwise). ICS events are all-day
+and keep the same UIDs across runs, so re-importing updates them.`,
+	Example: `  splan workspace calendar -o calendar.md
+  splan workspace calendar goals/ -o planning.ics
+  splan workspace calendar --fiscal-year 2027 --fiscal-year-start 2 -o fy2027.ics
+  splan workspace calendar --period 2026-Q3 -o q3.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceCalendar,
+}
 
-	switch v2momInitFlags.structure {
-	case "flat":
-		template = createFlatV2MOMTemplate(v2momInitFlags.name)
-	case "hybrid":
-		template = createHybridV2MOMTemplate(v2momInitFlags.name)
-	default: // "nested"
-		template = createNestedV2MOMTemplate(v2momInitFlags.name)
-	}
+func init() {
+	workspaceCalendarCmd.Flags().StringVarP(&workspaceCalendarFlags.output, "output", "o", "calendar.md", "Output file")
+	workspaceCalendarCmd.Flags().StringVar(&workspaceCalendarFlags.format, "format", "", "Output format: markdown, ics (default: from output extension)")
+	workspaceCalendarCmd.Flags().StringSliceVar(&workspaceCalendarFlags.periods, "period", nil, "Period to plan, e.g. 2026-Q3 or FY2027 (repeatable)")
+	workspaceCalendarCmd.Flags().IntVar(&workspaceCalendarFlags.fiscalYear, "fiscal-year", 0, "Plan every quarter of this fiscal year")
+	workspaceCalendarCmd.Flags().IntVar(&workspaceCalendarFlags.fiscalYearStart, "fiscal-year-start", 0, "First month of the fiscal year, 1-12 (default: from manifest, or 1)")
+
+	workspaceCmd.AddCommand(workspaceCalendarCmd)
+}
+
+func runWorkspaceCalendar(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	format := strings.ToLower(workspaceCalendarFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.EqualFold(filepath.Ext(workspaceCalendarFlags.output), ".ics") {
+			format = "ics"
+		}
+	}
+
+	var cfg calendar.Config
+	manifest, err := workspace.FindManifest(root)
+	if err != nil {
+		return err
+	}
+	if manifest != nil && manifest.Planning != nil {
+		cfg = *manifest.Planning
+	}
+	if workspaceCalendarFlags.fiscalYearStart != 0 {
+		cfg.FiscalYearStartMonth = workspaceCalendarFlags.fiscalYearStart
+	}
+
+	var periods []calendar.Period
+	for _, s := range workspaceCalendarFlags.periods {
+		p, err := cfg.ParsePeriod(s)
+		if err != nil {
+			return err
+		}
+		periods = append(periods, p)
+	}
+	if workspaceCalendarFlags.fiscalYear != 0 {
+		periods = append(periods, cfg.Quarters(workspaceCalendarFlags.fiscalYear)...)
+	}
+
+	paths, err := workspace.Discover(root)
+	if err != nil {
+		return err
+	}
+	cal, err := workspace.BuildPlanningCalendar(paths, cfg, periods, workspace.Options{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
+	}
+	if len(cal.Cycles) == 0 {
+		return fmt.Errorf("no planning periods found: set a period in OKR or V2MOM metadata, or use --period or --fiscal-year")
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(cal.ToMarkdown())
+	case "ics", "ical":
+		if err := cal.WriteICS(&buf, time.Now()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, ics)", workspaceCalendarFlags.format)
+	}
+
+	if err := os.WriteFile(workspaceCalendarFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	docs := 0
+	for _, cy := range cal.Cycles {
+		docs += len(cy.Documents)
+	}
+	fmt.Printf("Generated: %s (%d periods, %d documents)\n", workspaceCalendarFlags.output, len(cal.Cycles), docs)
+	return nil
+}
+
+var workspaceDependenciesFlags struct {
+	output string
+	format string
+}
+
+var workspaceDependenciesCmd = &cobra.Command{
+	Use:   "dependencies [dir]",
+	Short: "Report external dependencies across documents by owning team",
+	Long: `Report the external dependencies declared across a workspace, grouped by
+the team that owns each deliverable, so a team can see everything other plans
+are waiting on.
+
+Dependencies are collected from PRD assumptions.dependencies and TRD
+dependencies. PRD dependencies are checked against the PRD roadmap: the
+neededBy phase must exist, the due date must not fall after the phase starts,
+and a phase in progress must not be waiting on an undelivered dependency.
+
+The output format is taken from --format, or inferred from the output file
+extension (.csv for CSV, .json for JSON, markdown otherwise).`,
+	Example: `  splan workspace dependencies -o dependencies.md
+  splan workspace dependencies docs/ -o dependencies.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceDependencies,
+}
+
+func init() {
+	workspaceDependenciesCmd.Flags().StringVarP(&workspaceDependenciesFlags.output, "output", "o", "dependencies.md", "Output file")
+	workspaceDependenciesCmd.Flags().StringVar(&workspaceDependenciesFlags.format, "format", "", "Output format: markdown, csv, json (default: from output extension)")
+
+	workspaceCmd.AddCommand(workspaceDependenciesCmd)
+}
+
+func runWorkspaceDependencies(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	format := strings.ToLower(workspaceDependenciesFlags.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(workspaceDependenciesFlags.output)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		default:
+			format = "markdown"
+		}
+	}
+
+	paths, err := workspace.Discover(root)
+	if err != nil {
+		return err
+	}
+	report, err := workspace.BuildDependencyReport(paths, workspace.Options{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "csv":
+		if err := report.WriteCSV(&buf); err != nil {
+			return err
+		}
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling dependency report: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, csv, json)", workspaceDependenciesFlags.format)
+	}
+
+	if err := os.WriteFile(workspaceDependenciesFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Generated: %s (%d dependencies, %d teams, %d need attention)\n",
+		workspaceDependenciesFlags.output, len(report.Entries), len(report.Teams), report.AtRisk())
+	return nil
+}
+
+var workspaceDuplicatesFlags struct {
+	output    string
+	format    string
+	threshold float64
+}
+
+var workspaceDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates [dir]",
+	Short: "Find objectives and key results defined in more than one document",
+	Long: `Compare the objectives and key results in PRD, OKR, and V2MOM documents
+across a workspace and report likely duplicates: goals in different documents
+that share an ID or have highly similar titles. V2MOM methods are compared as
+objectives and their measures as key results.
+
+Each duplicate names the goal to keep (preferring OKR, then V2MOM, then PRD
+documents) and suggests replacing the copy with an alignedWith link. Goals
+whose objectives already link to each other through parentId or alignedWith
+are not reported.
+
+The output format is taken from --format, or inferred from the output file
+extension (.json for JSON, markdown otherwise).`,
+	Example: `  splan workspace duplicates -o duplicates.md
+  splan workspace duplicates docs/ --threshold 0.7 -o duplicates.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceDuplicates,
+}
+
+func init() {
+	workspaceDuplicatesCmd.Flags().StringVarP(&workspaceDuplicatesFlags.output, "output", "o", "duplicates.md", "Output file")
+	workspaceDuplicatesCmd.Flags().StringVar(&workspaceDuplicatesFlags.format, "format", "", "Output format: markdown, json (default: from output extension)")
+	workspaceDuplicatesCmd.Flags().Float64Var(&workspaceDuplicatesFlags.threshold, "threshold", workspace.DefaultSimilarityThreshold, "Title similarity (0-1) at which goals are reported")
+
+	workspaceCmd.AddCommand(workspaceDuplicatesCmd)
+}
+
+func runWorkspaceDuplicates(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+	if t := workspaceDuplicatesFlags.threshold; t <= 0 || t > 1 {
+		return fmt.Errorf("invalid threshold %v (must be greater than 0 and at most 1)", t)
+	}
+
+	format := strings.ToLower(workspaceDuplicatesFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(workspaceDuplicatesFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+
+	paths, err := workspace.Discover(root)
+	if err != nil {
+		return err
+	}
+	report, err := workspace.BuildGoalDuplicateReport(paths, workspace.Options{Lenient: rootFlags.lenient}, workspaceDuplicatesFlags.threshold)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling duplicate report: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", workspaceDuplicatesFlags.format)
+	}
+
+	if err := os.WriteFile(workspaceDuplicatesFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Generated: %s (%d possible duplicates among %d goals in %d documents)\n",
+		workspaceDuplicatesFlags.output, len(report.Duplicates), report.Goals, report.Documents)
+	return nil
+}
+
+var workspaceExportFlags struct {
+	output string
+}
+
+var workspaceExportCmd = &cobra.Command{
+	Use:   "export [dir]",
+	Short: "Export the whole workspace as a single archive",
+	Long: `Package a workspace into one zip archive that can be imported elsewhere:
+the workspace manifest (splan.workspace.json), every planning document, the
+files listed under "shared" in the manifest, persona libraries
+(personas.json), the lint configuration (.splan.yaml), the people directory
+file, and the local images and attachments the PRDs and TRDs reference.
+
+The workspace root is the directory of the manifest found from dir, as
+go.mod is found, or dir itself if there is none. Files are stored at their
+path relative to the root. Files referenced from outside it are stored
+under external/, and the references in the manifest and documents are
+rewritten to match. Referenced files that do not exist are reported as
+warnings.
+
+The archive has the same layout as a bundle: one timestamped directory
+with a manifest.json listing the size and SHA-256 digest of every file.`,
+	Example: `  splan workspace export
+  splan workspace export plans/ -o plans.zip`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceExport,
+}
+
+func init() {
+	workspaceExportCmd.Flags().StringVarP(&workspaceExportFlags.output, "output", "o", "", "Output file (default: <workspace>-<timestamp>.zip)")
+
+	workspaceCmd.AddCommand(workspaceExportCmd)
+}
+
+func runWorkspaceExport(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	b, err := workspace.Export(root, workspace.ExportOptions{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
+	}
+	output := workspaceExportFlags.output
+	if output == "" {
+		output = b.FileName()
+	}
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	for _, s := range b.Manifest.Skipped {
+		fmt.Fprintf(os.Stderr, "Warning: %s not exported: %s\n", s.Kind, s.Reason)
+	}
+
+	documents := 0
+	for _, f := range b.Manifest.Files {
+		if f.Kind == bundle.KindDocument {
+			documents++
+		}
+	}
+	fmt.Printf("Generated: %s (%d documents, %d files)\n", output, documents, len(b.Manifest.Files))
+	return nil
+}
+
+var workspaceImportFlags struct {
+	force bool
+	merge bool
+}
+
+var workspaceImportCmd = &cobra.Command{
+	Use:   "import ARCHIVE [dir]",
+	Short: "Import a workspace archive made by workspace export",
+	Long: `Extract a workspace archive made by "splan workspace export" into dir,
+which defaults to a directory named after the exported workspace. The
+archive is checked against its manifest first, so a damaged or altered
+archive is rejected without writing anything.
+
+Existing files are not overwritten unless --force is given; if any would
+be, nothing is written.
+
+With --merge, the archive's documents and shared files are added to the
+manifest of the workspace dir belongs to instead of writing the archive's
+manifest, with their paths rewritten relative to it. Its defaults,
+technology policy, planning calendar, and people directory are not merged,
+since they would apply to the whole workspace; they are reported instead.`,
+	Example: `  splan workspace import plans-20261017T093000Z.zip
+  splan workspace import plans.zip teams/payments --merge`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runWorkspaceImport,
+}
+
+func init() {
+	workspaceImportCmd.Flags().BoolVar(&workspaceImportFlags.force, "force", false, "Overwrite existing files")
+	workspaceImportCmd.Flags().BoolVar(&workspaceImportFlags.merge, "merge", false, "Add the documents to the enclosing workspace manifest")
+
+	workspaceCmd.AddCommand(workspaceImportCmd)
+}
+
+func runWorkspaceImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+	b, err := bundle.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	dir := b.Name()
+	if len(args) > 1 {
+		dir = args[1]
+	}
+
+	result, err := workspace.Import(b, dir, workspace.ImportOptions{
+		Force: workspaceImportFlags.force,
+		Merge: workspaceImportFlags.merge,
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range result.NotMerged {
+		fmt.Fprintf(os.Stderr, "Warning: %q in the archive's manifest was not merged\n", name)
+	}
+	if workspaceImportFlags.merge && result.Manifest != "" {
+		fmt.Printf("Updated: %s\n", result.Manifest)
+	}
+	fmt.Printf("Imported: %s (%d documents, %d files)\n", dir, result.Documents, len(result.Files))
+	return nil
+}
+
+// ============================================================================
+// Score Commands
+// ============================================================================
+
+var scoreCmd = &cobra.Command{
+	Use:   "score",
+	Short: "Score documents across a workspace",
+	Long: `Commands for scoring many planning documents at once.
+
+To score a single PRD, use "splan requirements prd score".`,
+}
+
+var scorePortfolioFlags struct {
+	output string
+	format string
+	gaps   int
+}
+
+var scorePortfolioCmd = &cobra.Command{
+	Use:   "portfolio [dir]",
+	Short: "Rank every PRD in a directory by quality score",
+	Long: `Score every PRD (*.prd.json) under a directory and rank them by weighted
+quality score, with each product's grade, scoring decision, and biggest gaps:
+the lowest-scoring categories below 7.
+
+The report is printed to the terminal unless -o is given. The format is taken
+from --format, or inferred from the output file extension (.csv, .html,
+.json; terminal text otherwise).`,
+	Example: `  splan score portfolio ./plans
+  splan score portfolio ./plans -o portfolio.html
+  splan score portfolio ./plans --format csv > portfolio.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScorePortfolio,
+}
+
+func init() {
+	scorePortfolioCmd.Flags().StringVarP(&scorePortfolioFlags.output, "output", "o", "", "Output file (default: stdout)")
+	scorePortfolioCmd.Flags().StringVar(&scorePortfolioFlags.format, "format", "", "Output format: terminal, csv, html, json (default: from output extension)")
+	scorePortfolioCmd.Flags().IntVar(&scorePortfolioFlags.gaps, "gaps", workspace.DefaultPortfolioGaps, "Number of gaps to list per product")
+
+	scoreCmd.AddCommand(scorePortfolioCmd)
+}
+
+func runScorePortfolio(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	format := strings.ToLower(scorePortfolioFlags.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(scorePortfolioFlags.output)) {
+		case ".csv":
+			format = "csv"
+		case ".html", ".htm":
+			format = "html"
+		case ".json":
+			format = "json"
+		default:
+			format = "terminal"
+		}
+	}
+
+	paths, err := workspace.Discover(root)
+	if err != nil {
+		return err
+	}
+	portfolio := workspace.BuildPortfolio(root, paths, scorePortfolioFlags.gaps, workspace.Options{Lenient: rootFlags.lenient})
+
+	var buf bytes.Buffer
+	switch format {
+	case "terminal", "text":
+		buf.WriteString(portfolio.ToText())
+	case "csv":
+		if err := portfolio.WriteCSV(&buf); err != nil {
+			return err
+		}
+	case "html":
+		data, err := portfolio.Page().Render()
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	case "json":
+		data, err := json.MarshalIndent(portfolio, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling portfolio: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: terminal, csv, html, json)", scorePortfolioFlags.format)
+	}
+
+	if scorePortfolioFlags.output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(scorePortfolioFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d PRDs, average score %.1f)\n", scorePortfolioFlags.output, len(portfolio.Entries), portfolio.AverageScore())
+	return nil
+}
+
+// ============================================================================
+// Diff Commands
+// ============================================================================
+
+var diffFlags struct {
+	output string
+	format string
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.prd.json> <new.prd.json>",
+	Short: "Show what changed between two versions of a PRD",
+	Long: `Compare two versions of a PRD and list the requirements, user stories,
+personas, objectives, key results, and roadmap phases that were added,
+removed, or changed. Entities are matched by ID; changed entities list each
+changed field with its old and new value.
+
+The diff is printed as a markdown changelog unless --format json is given.
+Without --format, the format is inferred from the output file extension.`,
+	Example: `  splan diff v1.prd.json v2.prd.json
+  splan diff v1.prd.json v2.prd.json -o CHANGES.md
+  splan diff v1.prd.json v2.prd.json --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFlags.output, "output", "o", "", "Output file (default: stdout)")
+	diffCmd.Flags().StringVar(&diffFlags.format, "format", "", "Output format: markdown, json (default: from output extension)")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	docs := make([]*prd.Document, len(args))
+	for i, path := range args {
+		if kind, err := requirementsKind(path, ""); err != nil {
+			return err
+		} else if kind != "prd" {
+			return fmt.Errorf("%s is a %s: only PRDs can be compared", path, strings.ToUpper(kind))
+		}
+		var doc prd.Document
+		if err := readDocument(path, &doc); err != nil {
+			return err
+		}
+		docs[i] = &doc
+	}
+	report := diff.PRD(docs[0], docs[1])
+
+	format := strings.ToLower(diffFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(diffFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling diff: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", diffFlags.format)
+	}
+
+	if diffFlags.output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(diffFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d added, %d changed, %d removed)\n",
+		diffFlags.output, report.Count(diff.Added), report.Count(diff.Changed), report.Count(diff.Removed))
+	return nil
+}
+
+// ============================================================================
+// Preview Commands
+// ============================================================================
+
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Preview rendered documents",
+}
+
+var previewDiffFlags struct {
+	output  string
+	format  string
+	docType string
+	context int
+}
+
+var previewDiffCmd = &cobra.Command{
+	Use:   "diff <old.json> <new.json>",
+	Short: "Compare the rendered text of two document versions",
+	Long: `Render two versions of a document to markdown and compare the results side
+by side, with changed words highlighted in the generated prose and tables.
+Unlike splan diff, which lists changed entities and fields, this shows each
+change in context, for reviewers who don't read JSON.
+
+PRD, MRD, TRD, and roadmap documents are supported. The type is taken from
+the filename suffix, as in checkout.prd.json, or from --type.
+
+Output formats:
+  html - standalone page with the versions side by side (default)
+  text - word diff with [-removed-] and {+added+} words`,
+	Example: `  splan preview diff v1.prd.json v2.prd.json -o changes.html
+  splan preview diff v1.trd.json v2.trd.json --format text
+  splan preview diff old.json new.json --type roadmap --context -1 -o changes.html`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPreviewDiff,
+}
+
+func init() {
+	previewDiffCmd.Flags().StringVarP(&previewDiffFlags.output, "output", "o", "", "Output file (default: stdout)")
+	previewDiffCmd.Flags().StringVar(&previewDiffFlags.format, "format", "html", "Output format: html, text")
+	previewDiffCmd.Flags().StringVarP(&previewDiffFlags.docType, "type", "t", "", "Document type: prd, mrd, trd, roadmap (default: from the filename)")
+	previewDiffCmd.Flags().IntVar(&previewDiffFlags.context, "context", 3, "Unchanged lines shown around each change (-1 shows every line)")
+	previewCmd.AddCommand(previewDiffCmd)
+}
+
+func runPreviewDiff(cmd *cobra.Command, args []string) error {
+	docType := strings.ToLower(previewDiffFlags.docType)
+	if docType == "" {
+		docType = documentTypeFromPath(args[1])
+	}
+	texts := make([]string, len(args))
+	labels := make([]string, len(args))
+	var title string
+	for i, path := range args {
+		var version string
+		var err error
+		texts[i], title, version, err = renderPreviewMarkdown(path, docType)
+		if err != nil {
+			return err
+		}
+		labels[i] = path
+		if version != "" {
+			labels[i] += " (v" + strings.TrimPrefix(version, "v") + ")"
+		}
+	}
+
+	d := diff.Text(texts[0], texts[1], previewDiffFlags.context)
+	d.Title, d.OldLabel, d.NewLabel = title, labels[0], labels[1]
+
+	var output []byte
+	switch strings.ToLower(previewDiffFlags.format) {
+	case "html":
+		var err error
+		if output, err = d.HTML(); err != nil {
+			return err
+		}
+	case "text":
+		output = []byte(d.WordDiff())
+	default:
+		return fmt.Errorf("unknown format %q (valid: html, text)", previewDiffFlags.format)
+	}
+
+	if previewDiffFlags.output == "" {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+	if err := os.WriteFile(previewDiffFlags.output, output, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, previewDiffFlags.output)
+	changed, added, removed := d.Counts()
+	fmt.Printf("Generated: %s (%d changed, %d added, %d removed lines)\n", previewDiffFlags.output, changed, added, removed)
+	return nil
+}
+
+// renderPreviewMarkdown renders the document at path as markdown without
+// frontmatter or a table of contents, and returns it with the document's
+// title and version.
+func renderPreviewMarkdown(path, docType string) (markdown, title, version string, err error) {
+	switch docType {
+	case "prd":
+		var doc prd.Document
+		if err := readDocument(path, &doc); err != nil {
+			return "", "", "", err
+		}
+		opts := prd.DefaultMarkdownOptions()
+		opts.IncludeFrontmatter = false
+		noTOC := false
+		opts.IncludeTOC = &noTOC
+		return doc.ToMarkdown(opts), doc.Metadata.Title, doc.Metadata.Version, nil
+	case "mrd":
+		var doc mrd.Document
+		if err := readDocument(path, &doc); err != nil {
+			return "", "", "", err
+		}
+		opts := mrd.DefaultMarkdownOptions()
+		opts.IncludeFrontmatter = false
+		return doc.ToMarkdown(opts), doc.Metadata.Title, doc.Metadata.Version, nil
+	case "trd":
+		var doc trd.Document
+		if err := readDocument(path, &doc); err != nil {
+			return "", "", "", err
+		}
+		opts := trd.DefaultMarkdownOptions()
+		opts.IncludeFrontmatter = false
+		return doc.ToMarkdown(opts), doc.Metadata.Title, doc.Metadata.Version, nil
+	case "roadmap":
+		var doc roadmap.Document
+		if err := readDocument(path, &doc); err != nil {
+			return "", "", "", err
+		}
+		return doc.ToMarkdown(roadmap.DefaultMarkdownOptions()), doc.Metadata.Title, doc.Metadata.Version, nil
+	case "":
+		return "", "", "", fmt.Errorf("cannot tell the document type of %s: name it *.prd.json, *.mrd.json, *.trd.json, or *.roadmap.json, or use --type", path)
+	}
+	return "", "", "", fmt.Errorf("preview diff supports prd, mrd, trd, and roadmap documents, not %s", docType)
+}
+
+// ============================================================================
+// History Commands
+// ============================================================================
+
+var historyFlags struct {
+	output string
+	format string
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <file.prd.json | dir>",
+	Short: "Generate a PRD changelog from its earlier versions",
+	Long: `Build a per-version changelog of a PRD: the requirements, user stories,
+personas, objectives, and phases added, modified, or removed in each version,
+and their status transitions.
+
+Given a file, the versions are the file's commits in its git repository,
+following renames. Given a directory, the versions are the PRD files in it
+(*.prd.json, *.prd.yaml), ordered by metadata version.
+
+The changelog is printed as a markdown "Revision History" section unless
+--format json is given. To include it in generated markdown, use
+"splan requirements prd generate --history".`,
+	Example: `  splan history plans/checkout.prd.json
+  splan history plans/checkout-versions/ -o HISTORY.md
+  splan history plans/checkout.prd.json --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&historyFlags.output, "output", "o", "", "Output file (default: stdout)")
+	historyCmd.Flags().StringVar(&historyFlags.format, "format", "", "Output format: markdown, json (default: from output extension)")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	source := "git"
+	if info, err := os.Stat(args[0]); err != nil {
+		return err
+	} else if info.IsDir() {
+		source = args[0]
+	}
+	h, err := loadHistory(args[0], source)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(historyFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(historyFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(h.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(h, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling history: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", historyFlags.format)
+	}
+
+	if historyFlags.output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(historyFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d versions)\n", historyFlags.output, len(h.Entries))
+	return nil
+}
+
+// loadHistory builds the revision history of the PRD at path. source is
+// "git" for the file's commits, or a directory of versioned PRD files.
+// Versions that cannot be loaded are reported as warnings.
+func loadHistory(path, source string) (*history.History, error) {
+	versions, skipped, err := loadVersions(path, source)
+	if err != nil {
+		return nil, err
+	}
+	h := history.Build(versions)
+	h.Skipped = skipped
+	return h, nil
+}
+
+// loadVersions loads the versions of the PRD at path, from its commits if
+// source is "git" or else from the directory source, and reports the
+// versions that cannot be loaded as warnings.
+func loadVersions(path, source string) ([]history.Version, []string, error) {
+	opts := history.Options{Lenient: rootFlags.lenient}
+	var versions []history.Version
+	var skipped []string
+	var err error
+	if source == "git" {
+		versions, skipped, err = history.FromGit(context.Background(), path, opts)
+	} else {
+		versions, skipped, err = history.FromDir(source, opts)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipped version %s\n", s)
+	}
+	return versions, skipped, nil
+}
+
+var historyAgingFlags struct {
+	output      string
+	format      string
+	staleMonths int
+	churn       float64
+	minChanges  int
+	asOf        string
+}
+
+var historyAgingCmd = &cobra.Command{
+	Use:   "aging <file.prd.json | dir>",
+	Short: "Report stale and high-churn requirements across PRD versions",
+	Long: `Report the age and churn of each requirement, user story, persona,
+objective, key result, and phase of a PRD, derived from its earlier versions
+as in "splan history": the date each was added and last changed, and how
+many versions changed it.
+
+  - stale: functional and non-functional requirements and user stories
+    whose roadmap phase is not completed and that have not changed for
+    --stale-months (default 6), as of today or --as-of
+  - high churn: entities changed in at least --churn (default 0.5) of the
+    versions since they were added, and at least --min-changes times
+
+Versions without semantic changes, such as formatting-only commits, are not
+counted. Versioned files are dated by their metadata updatedAt or createdAt;
+entities of undated versions are never stale.
+
+The report is printed as markdown unless --format json is given.`,
+	Example: `  splan history aging plans/checkout.prd.json
+  splan history aging plans/checkout.prd.json --stale-months 3 --churn 0.75
+  splan history aging plans/checkout-versions/ -o aging.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryAging,
+}
+
+func init() {
+	f := historyAgingCmd.Flags()
+	f.StringVarP(&historyAgingFlags.output, "output", "o", "", "Output file (default: stdout)")
+	f.StringVar(&historyAgingFlags.format, "format", "", "Output format: markdown, json (default: from output extension)")
+	f.IntVar(&historyAgingFlags.staleMonths, "stale-months", history.DefaultStaleMonths, "Months unchanged before an unimplemented requirement is stale")
+	f.Float64Var(&historyAgingFlags.churn, "churn", history.DefaultChurnRatio, "Share of versions an entity must change in to be high churn (0-1]")
+	f.IntVar(&historyAgingFlags.minChanges, "min-changes", history.DefaultMinChanges, "Fewest changes of a high-churn entity")
+	f.StringVar(&historyAgingFlags.asOf, "as-of", "", "Date ages are measured to, YYYY-MM-DD (default: today)")
+
+	historyCmd.AddCommand(historyAgingCmd)
+}
+
+func runHistoryAging(cmd *cobra.Command, args []string) error {
+	if historyAgingFlags.staleMonths < 1 {
+		return fmt.Errorf("--stale-months must be at least 1, got %d", historyAgingFlags.staleMonths)
+	}
+	if historyAgingFlags.churn <= 0 || historyAgingFlags.churn > 1 {
+		return fmt.Errorf("--churn must be greater than 0 and at most 1, got %g", historyAgingFlags.churn)
+	}
+	if historyAgingFlags.minChanges < 1 {
+		return fmt.Errorf("--min-changes must be at least 1, got %d", historyAgingFlags.minChanges)
+	}
+	asOf := time.Now()
+	if historyAgingFlags.asOf != "" {
+		t, err := time.Parse("2006-01-02", historyAgingFlags.asOf)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of %q: expected YYYY-MM-DD", historyAgingFlags.asOf)
+		}
+		asOf = t
+	}
+
+	source := "git"
+	if info, err := os.Stat(args[0]); err != nil {
+		return err
+	} else if info.IsDir() {
+		source = args[0]
+	}
+	versions, skipped, err := loadVersions(args[0], source)
+	if err != nil {
+		return err
+	}
+	report := history.Aging(versions, history.AgingOptions{
+		AsOf:        asOf,
+		StaleMonths: historyAgingFlags.staleMonths,
+		ChurnRatio:  historyAgingFlags.churn,
+		MinChanges:  historyAgingFlags.minChanges,
+	})
+	report.Skipped = skipped
+
+	format := strings.ToLower(historyAgingFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(historyAgingFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", historyAgingFlags.format)
+	}
+
+	if historyAgingFlags.output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(historyAgingFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d stale, %d high churn)\n", historyAgingFlags.output, len(report.Stale()), len(report.HighChurn()))
+	return nil
+}
+
+// ============================================================================
+// Fix Commands
+// ============================================================================
+
+var fixFlags struct {
+	apply  bool
+	patch  string
+	output string
+}
+
+var fixCmd = &cobra.Command{
+	Use:   "fix <file.prd.json>",
+	Short: "Apply automatic fixes for mechanical PRD findings",
+	Long: `Fix the findings in a PRD that need no judgment:
+
+  - missing-id:     entities without an ID get the next free ID (FR-004)
+  - missing-status: the document status defaults to draft, phases to planned
+  - tags:           empty and duplicate tags are removed, and tags are
+                    rewritten to lowercase-hyphen form ("Mobile App" to
+                    "mobile-app")
+  - persona-link:   user stories without a persona are linked to the one
+                    persona whose name or role matches their "as a" role
+
+The fixes are a JSON Patch (RFC 6902). Without --apply, the patch is printed
+and the document is left unchanged. With --apply, it is applied and the
+document is written back, or to --output.
+
+"splan requirements prd check" and "score" write the same patch with
+--fix-patch; pass it with --patch to apply exactly what was reviewed. The
+patch tests the values it changes, so it fails if the document was edited
+since.`,
+	Example: `  splan fix plans/checkout.prd.json
+  splan fix plans/checkout.prd.json --apply
+  splan requirements prd score plans/checkout.prd.json --fix-patch fixes.json
+  splan fix plans/checkout.prd.json --apply --patch fixes.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFix,
+}
+
+func init() {
+	fixCmd.Flags().BoolVar(&fixFlags.apply, "apply", false, "Apply the fixes and write the document")
+	fixCmd.Flags().StringVar(&fixFlags.patch, "patch", "", "JSON Patch file to apply instead of computing the fixes")
+	fixCmd.Flags().StringVarP(&fixFlags.output, "output", "o", "", "Output file for --apply (default: overwrite the input)")
+}
+
+func runFix(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+	if kind, err := requirementsKind(inputFile, ""); err != nil {
+		return err
+	} else if kind != "prd" {
+		return fmt.Errorf("fix supports PRDs only, not %s", strings.ToUpper(kind))
+	}
+
+	var doc prd.Document
+	src, err := readSourceDocument(inputFile, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	var patch jsonpatch.Patch
+	if fixFlags.patch != "" {
+		data, err := os.ReadFile(fixFlags.patch)
+		if err != nil {
+			return fmt.Errorf("reading patch: %w", err)
+		}
+		if err := json.Unmarshal(data, &patch); err != nil {
+			return fmt.Errorf("parsing patch: %w", err)
+		}
+	} else {
+		fixes := doc.Fixes()
+		for _, f := range fixes {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", f.Rule, f.Message)
+		}
+		if patch, err = sourceFixPatch(src.expanded, fixes); err != nil {
+			return err
+		}
+	}
+
+	if !fixFlags.apply {
+		data, err := json.MarshalIndent(patch, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling patch: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(patch) == 0 {
+		fmt.Println("No fixes to apply")
+		return nil
+	}
+	output := fixFlags.output
+	if output == "" {
+		output = inputFile
+	}
+	if err := patchDocumentInPlace(output, src, patch); err != nil {
+		return err
+	}
+	fmt.Printf("Generated: %s (%d patch operations applied)\n", output, len(patch))
+	return nil
+}
+
+// sourceFixPatch returns the patch of fixes for the document source, the
+// JSON it was read from with its snippets expanded. The fixes are found on
+// the typed document, which has every member; tests of the members the
+// source leaves out are dropped so that the patch applies to the source.
+func sourceFixPatch(source []byte, fixes []prd.Fix) (jsonpatch.Patch, error) {
+	doc, err := decodeJSON(source)
+	if err != nil {
+		return nil, err
+	}
+	return prd.FixPatch(fixes).SkipMissingTests(doc), nil
+}
+
+// writeFixPatch writes the patch fixing the document's mechanical findings
+// to path, for "splan fix --patch", and says how many there are.
+func writeFixPatch(doc *prd.Document, inputFile, path string) error {
+	fixes := doc.Fixes()
+	source, err := readExpandedJSON(inputFile)
+	if err != nil {
+		return err
+	}
+	patch, err := sourceFixPatch(source, fixes)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(patch, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling fix patch: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing fix patch: %w", err)
+	}
+	if len(fixes) > 0 {
+		fmt.Fprintf(os.Stderr, "%d findings can be fixed automatically: splan fix %s --apply --patch %s\n", len(fixes), inputFile, path)
+	}
+	return nil
+}
+
+// ============================================================================
+// Serve Commands
+// ============================================================================
+
+var serveFlags struct {
+	addr string
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve <file-or-dir>",
+	Short: "Preview planning documents as HTML in a local web server",
+	Long: `Start a local HTTP server that renders planning documents as HTML.
+
+Every page has a sidebar listing the planning documents in the directory
+(*.prd.json, *.mrd.json, *.trd.json, *.okr.json, *.v2mom.json,
+*.roadmap.json, or their YAML forms, including subdirectories), and the documents the current one
+references or is referenced by, by metadata ID or relative path. Pages
+reload automatically when a document changes.
+
+Given a directory, the home page is the workspace dashboard. Given a file,
+the home page is that document.`,
+	Example: `  splan serve plans/
+  splan serve plans/checkout.prd.json
+  splan serve plans/ --addr :9000`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveFlags.addr, "addr", "localhost:8080", "Address to listen on")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	srv, err := serve.New(args[0], serve.Options{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go srv.Watch(ctx)
+
+	httpServer := &http.Server{
+		Addr:              serveFlags.addr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	listener, err := net.Listen("tcp", serveFlags.addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", serveFlags.addr, err)
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving %s at http://%s/ (Ctrl+C to stop)\n", args[0], listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ============================================================================
+// Site Commands
+// ============================================================================
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Publish planning documents as a static website",
+}
+
+var siteBuildFlags struct {
+	output string
+}
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build <dir>",
+	Short: "Build a static HTML site with full-text search",
+	Long: `Render every planning document in a directory as a static HTML site: the
+pages of splan serve, with relative links, so the site can be published on
+any web server or opened from disk.
+
+The site has index.html with the workspace dashboard and a page per
+document, at the document's path with .json replaced by .html. The sidebar
+has a search box over a full-text index of the documents, their sections,
+requirements, and glossary terms. The index is also written as
+search-index.json, a flat array of entries with an id field that can be
+loaded into lunr or imported into Meilisearch.`,
+	Example: `  splan site build plans/ -o site/
+  splan site build . -o public/ --lenient`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSiteBuild,
+}
+
+func init() {
+	siteBuildCmd.Flags().StringVarP(&siteBuildFlags.output, "output", "o", "site", "Output directory")
+	siteCmd.AddCommand(siteBuildCmd)
+}
+
+func runSiteBuild(cmd *cobra.Command, args []string) error {
+	if info, err := os.Stat(args[0]); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", args[0])
+	}
+	srv, err := serve.New(args[0], serve.Options{Lenient: rootFlags.lenient})
+	if err != nil {
+		return err
+	}
+	res, err := srv.Build(siteBuildFlags.output)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generated: %s (%d pages, %d search entries)\n", siteBuildFlags.output, res.Pages, res.Entries)
+	if res.Failed > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d documents could not be rendered; their pages show the error\n", res.Failed)
+	}
+	return nil
+}
+
+// ============================================================================
+// Localization Commands
+// ============================================================================
+
+var l10nCmd = &cobra.Command{
+	Use:   "l10n",
+	Short: "Extract and apply translations for planning documents",
+	Long: `Commands for localizing planning documents.
+
+extract pulls user-facing narrative text (titles, descriptions, user stories,
+goals, ...) into an XLIFF 1.2 or gettext PO file with stable keys. After
+translation, apply produces a translated copy of the document. Identifiers,
+statuses, dates, and other structured values are never translated.
+
+Keys use element IDs where available, such as
+requirements.functional[FR-001].description, so a translation file remains
+valid as requirements are added or reordered. Strings whose source text has
+changed since extraction are reported as stale and left untranslated.`,
+}
+
+var l10nExtractFlags struct {
+	output     string
+	sourceLang string
+	targetLang string
+}
+
+var l10nExtractCmd = &cobra.Command{
+	Use:   "extract <document.json>",
+	Short: "Extract translatable strings to XLIFF or PO",
+	Long: `Extract translatable strings from a planning document.
+
+The format is chosen by the output extension: .xliff or .xlf for XLIFF 1.2,
+.po for gettext PO.`,
+	Example: `  splan l10n extract doc.prd.json -o strings.xliff
+  splan l10n extract doc.prd.json -o fr.po --target-lang fr`,
+	Args: cobra.ExactArgs(1),
+	RunE: runL10nExtract,
+}
+
+var l10nApplyFlags struct {
+	output string
+}
+
+var l10nApplyCmd = &cobra.Command{
+	Use:   "apply <document.json> <translations>",
+	Short: "Produce a translated copy of a document",
+	Long: `Apply an XLIFF or PO translation file to a planning document and write
+the translated copy. Untranslated and stale strings keep their source text.`,
+	Example: `  splan l10n apply doc.prd.json strings.fr.xliff -o doc.fr.prd.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    runL10nApply,
+}
+
+func init() {
+	l10nExtractCmd.Flags().StringVarP(&l10nExtractFlags.output, "output", "o", "strings.xliff", "Output file (.xliff, .xlf, or .po)")
+	l10nExtractCmd.Flags().StringVar(&l10nExtractFlags.sourceLang, "source-lang", "en", "Source language tag")
+	l10nExtractCmd.Flags().StringVar(&l10nExtractFlags.targetLang, "target-lang", "", "Target language tag")
+
+	l10nApplyCmd.Flags().StringVarP(&l10nApplyFlags.output, "output", "o", "", "Output document path (required)")
+	_ = l10nApplyCmd.MarkFlagRequired("output")
+
+	l10nCmd.AddCommand(l10nExtractCmd)
+	l10nCmd.AddCommand(l10nApplyCmd)
+}
+
+func runL10nExtract(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	format, err := l10n.FormatFromPath(l10nExtractFlags.output)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+	units, err := l10n.Extract(data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	opts := l10n.FileOptions{
+		Original:   filepath.Base(inputFile),
+		SourceLang: l10nExtractFlags.sourceLang,
+		TargetLang: l10nExtractFlags.targetLang,
+	}
+	if err := l10n.Write(&buf, format, units, opts); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(l10nExtractFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Generated: %s (%d strings)\n", l10nExtractFlags.output, len(units))
+	return nil
+}
+
+func runL10nApply(cmd *cobra.Command, args []string) error {
+	inputFile, translationsFile := args[0], args[1]
+
+	format, err := l10n.FormatFromPath(translationsFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(translationsFile)
+	if err != nil {
+		return fmt.Errorf("reading translations file: %w", err)
+	}
+	units, err := l10n.Read(f, format)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+	translated, result, err := l10n.Apply(data, units)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(l10nApplyFlags.output, translated, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Generated: %s\n", l10nApplyFlags.output)
+	fmt.Printf("  Translated:   %d\n", result.Applied)
+	fmt.Printf("  Untranslated: %d\n", len(result.Untranslated))
+	if len(result.Stale) > 0 {
+		fmt.Printf("  Stale:        %d (source changed since extraction)\n", len(result.Stale))
+		for _, key := range result.Stale {
+			fmt.Printf("    - %s\n", key)
+		}
+	}
+	if len(result.Unknown) > 0 {
+		fmt.Printf("  Unknown keys: %d (not found in document)\n", len(result.Unknown))
+	}
+	return nil
+}
+
+// ============================================================================
+// Evidence Commands
+// ============================================================================
+
+var evidenceCmd = &cobra.Command{
+	Use:   "evidence",
+	Short: "Inspect and validate problem evidence in PRDs",
+	Long: `Commands for the evidence behind a PRD's problem definition.
+
+Evidence is read from problem.evidence and the evidence of secondary problems.`,
+}
+
+var evidenceFlags struct {
+	json       bool
+	maxAgeDays int
+}
+
+var evidenceListCmd = &cobra.Command{
+	Use:     "list <input.json>",
+	Short:   "List problem evidence",
+	Example: `  splan evidence list myproduct.prd.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runEvidenceList,
+}
+
+var evidenceValidateCmd = &cobra.Command{
+	Use:   "validate <input.json>",
+	Short: "Check evidence freshness and strength",
+	Long: `Validate problem evidence.
+
+Each item must have a source, a strength rating, and a collection date no older
+than --max-age-days. Survey, analytics, and support ticket evidence must report
+a sample size. The evidence base must include medium or high strength evidence
+and must not consist mostly of unvalidated assumptions.`,
+	Example: `  splan evidence validate myproduct.prd.json
+  splan evidence validate myproduct.prd.json --max-age-days 180`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEvidenceValidate,
+}
+
+func init() {
+	evidenceCmd.PersistentFlags().BoolVar(&evidenceFlags.json, "json", false, "Output as JSON")
+	evidenceCmd.PersistentFlags().IntVar(&evidenceFlags.maxAgeDays, "max-age-days", int(prd.DefaultEvidenceMaxAge.Hours()/24), "Age in days after which evidence is stale")
+
+	evidenceCmd.AddCommand(evidenceListCmd)
+	evidenceCmd.AddCommand(evidenceValidateCmd)
+}
+
+func checkEvidence(inputFile string) (*prd.EvidenceReport, error) {
+	var doc prd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return nil, err
+	}
+	return prd.CheckEvidence(&doc, prd.EvidenceOptions{
+		MaxAge: time.Duration(evidenceFlags.maxAgeDays) * 24 * time.Hour,
+	}), nil
+}
+
+func runEvidenceList(cmd *cobra.Command, args []string) error {
+	report, err := checkEvidence(args[0])
+	if err != nil {
+		return err
+	}
+
+	if evidenceFlags.json {
+		data, err := json.MarshalIndent(report.Items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling evidence: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(report.Items) == 0 {
+		fmt.Println("No evidence found")
+		return nil
+	}
+	for _, e := range report.Items {
+		id := e.ID
+		if id == "" {
+			id = e.Field
+		}
+		age := "undated"
+		if e.AgeDays >= 0 {
+			age = fmt.Sprintf("%s, %d days", e.Date, e.AgeDays)
+		}
+		fmt.Printf("%s [%s, %s] %s (%s)\n", id, e.Type, orDash(string(e.Strength)), e.Source, age)
+		if e.Summary != "" {
+			fmt.Printf("    %s\n", e.Summary)
+		}
+		if e.SampleSize > 0 {
+			fmt.Printf("    Sample size: %d\n", e.SampleSize)
+		}
+		for _, link := range e.Links {
+			fmt.Printf("    %s\n", link)
+		}
+	}
+	fmt.Printf("\n%d items: %d high, %d medium, %d low strength; %d stale\n", len(report.Items),
+		report.ByStrength[prd.StrengthHigh], report.ByStrength[prd.StrengthMedium], report.ByStrength[prd.StrengthLow], report.Stale)
+	return nil
+}
+
+func runEvidenceValidate(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+	report, err := checkEvidence(inputFile)
+	if err != nil {
+		return err
+	}
+
+	if evidenceFlags.json {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling evidence report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(report.Issues) > 0 {
+		fmt.Fprintf(os.Stderr, "Evidence issues in %s:\n", inputFile)
+		for _, issue := range report.Issues {
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", issue.Field, issue.Message)
+		}
+	}
+
+	if len(report.Issues) > 0 {
+		return fmt.Errorf("evidence validation failed with %d issues", len(report.Issues))
+	}
+	if !evidenceFlags.json {
+		fmt.Printf("Evidence valid: %s (%d items)\n", inputFile, len(report.Items))
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ============================================================================
+// Roadmap Commands
+// ============================================================================
+
+var roadmapCmd = &cobra.Command{
+	Use:   "roadmap",
+	Short: "Work with standalone roadmap documents",
+	Long: `Commands for creating, validating, and rendering standalone roadmaps.
+
+A roadmap document plans work at the portfolio or product level without a PRD:
+themes group epics, epics are scheduled into phases, and milestones,
+external dependencies, and staffing are tracked against those phases.`,
+}
+
+var roadmapValidateCmd = &cobra.Command{
+	Use:   "validate FILE...",
+	Short: "Validate a roadmap document",
+	Long: `Validate a roadmap document's structure and schedule.
+
+Missing IDs and references to undefined themes or phases, including phase
+dependencies, are errors. Schedule
+conflicts, dependency and staffing issues, and milestones outside their phase
+are reported as warnings.`,
+	Example: `  splan roadmap validate platform.roadmap.json
+  splan roadmap validate roadmaps/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRoadmapValidate,
+}
+
+var roadmapGenerateFlags struct {
+	output           string
+	noSwimlane       bool
+	swimlaneNoStatus bool
+	mermaid          bool
+}
+
+var roadmapGenerateCmd = &cobra.Command{
+	Use:   "generate FILE",
+	Short: "Generate markdown from a roadmap document",
+	Long: `Generate markdown from a roadmap document, with theme and deliverable
+swimlane views, phase details, milestones, dependencies, staffing, and risks.`,
+	Example: `  splan roadmap generate platform.roadmap.json
+  splan roadmap generate platform.roadmap.json -o roadmap.md --no-swimlane
+  splan roadmap generate platform.roadmap.json --mermaid`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRoadmapGenerate,
+}
+
+var roadmapInitFlags struct {
+	title  string
+	output string
+	level  string
+}
+
+var roadmapInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize a new roadmap template",
+	Long: `Create a new roadmap JSON template file with example content.
+
+Examples:
+  splan roadmap init
+  splan roadmap init --title "Platform Roadmap 2026" --level portfolio
+  splan roadmap init -o checkout.roadmap.json`,
+	RunE: runRoadmapInit,
+}
+
+func init() {
+	roadmapGenerateCmd.Flags().StringVarP(&roadmapGenerateFlags.output, "output", "o", "", "Output file (default: input with .md extension)")
+	roadmapGenerateCmd.Flags().BoolVar(&roadmapGenerateFlags.noSwimlane, "no-swimlane", false, "Disable the swimlane views")
+	roadmapGenerateCmd.Flags().BoolVar(&roadmapGenerateFlags.swimlaneNoStatus, "swimlane-no-status", false, "Hide status icons in swimlane tables")
+	roadmapGenerateCmd.Flags().BoolVar(&roadmapGenerateFlags.mermaid, "mermaid", false, "Add a Mermaid Gantt chart and dependency graph of the phases")
+
+	roadmapInitCmd.Flags().StringVar(&roadmapInitFlags.title, "title", "Product Roadmap", "Title for the roadmap")
+	roadmapInitCmd.Flags().StringVarP(&roadmapInitFlags.output, "output", "o", "roadmap.json", "Output file path")
+	roadmapInitCmd.Flags().StringVar(&roadmapInitFlags.level, "level", string(roadmap.LevelProduct), "Roadmap level (portfolio, product)")
+
+	roadmapCmd.AddCommand(roadmapValidateCmd)
+	roadmapCmd.AddCommand(roadmapGenerateCmd)
+	roadmapCmd.AddCommand(roadmapInitCmd)
+	roadmapCmd.AddCommand(roadmapBaselineCmd)
+}
+
+var roadmapBaselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Save a roadmap baseline and compare against it",
+	Long: `Save a snapshot of a roadmap as committed, then compare the roadmap against
+it later to report slippage for program reviews.`,
+}
+
+var roadmapBaselineSaveFlags struct {
+	output string
+	name   string
+}
+
+var roadmapBaselineSaveCmd = &cobra.Command{
+	Use:   "save FILE",
+	Short: "Save a baseline snapshot of a roadmap",
+	Long: `Save a snapshot of a roadmap's phases, deliverables, and milestones, with
+the time it was taken, to compare the roadmap against later.`,
+	Example: `  splan roadmap baseline save platform.roadmap.json --name "Q2 plan"
+  splan roadmap baseline save platform.roadmap.json -o baselines/2026-q2.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRoadmapBaselineSave,
+}
+
+var roadmapBaselineCompareFlags struct {
+	output string
+	format string
+	asOf   string
+}
+
+var roadmapBaselineCompareCmd = &cobra.Command{
+	Use:   "compare BASELINE FILE",
+	Short: "Report slippage of a roadmap against its baseline",
+	Long: `Compare a roadmap against a saved baseline and report:
+
+  - phases whose start or end moved, or whose status changed
+  - late phases: those whose end moved later, and those past their baseline
+    end as of today (or --as-of) without being completed or cancelled
+  - phases added or removed
+  - deliverables descoped, added, or retargeted to another phase or due date
+  - milestones whose date moved or that were removed
+
+Phases and milestones are matched by ID, deliverables by ID or else title.
+A deliverable without a due date is due at the end of its phase.
+
+The report is printed as markdown unless --format json is given. Without
+--format, the format is inferred from the output file extension.`,
+	Example: `  splan roadmap baseline compare platform.baseline.json platform.roadmap.json
+  splan roadmap baseline compare q2.json platform.roadmap.json -o slippage.md
+  splan roadmap baseline compare q2.json platform.roadmap.json --as-of 2026-06-30 --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRoadmapBaselineCompare,
+}
+
+func init() {
+	roadmapBaselineSaveCmd.Flags().StringVarP(&roadmapBaselineSaveFlags.output, "output", "o", "", "Output file (default: the input name with .baseline.json, e.g. platform.baseline.json)")
+	roadmapBaselineSaveCmd.Flags().StringVar(&roadmapBaselineSaveFlags.name, "name", "", "Baseline name, such as \"Q2 plan\"")
+	roadmapBaselineCompareCmd.Flags().StringVarP(&roadmapBaselineCompareFlags.output, "output", "o", "", "Output file (default: stdout)")
+	roadmapBaselineCompareCmd.Flags().StringVar(&roadmapBaselineCompareFlags.format, "format", "", "Output format: markdown, json (default: from output extension)")
+	roadmapBaselineCompareCmd.Flags().StringVar(&roadmapBaselineCompareFlags.asOf, "as-of", "", "Date lateness is judged at, YYYY-MM-DD (default: today)")
+
+	roadmapBaselineCmd.AddCommand(roadmapBaselineSaveCmd)
+	roadmapBaselineCmd.AddCommand(roadmapBaselineCompareCmd)
+}
+
+func runRoadmapBaselineSave(cmd *cobra.Command, args []string) error {
+	var doc roadmap.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	baseline, err := roadmap.NewBaseline(&doc, roadmapBaselineSaveFlags.name, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling baseline: %w", err)
+	}
+
+	output := roadmapBaselineSaveFlags.output
+	if output == "" {
+		output = strings.TrimSuffix(deriveOutputPathExt(args[0], ""), ".roadmap") + ".baseline.json"
+	}
+	if err := os.WriteFile(output, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing baseline: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Saved baseline: %s (%d phases, %d milestones)\n", output, len(doc.Phases), len(doc.Milestones))
+	return nil
+}
+
+func runRoadmapBaselineCompare(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading baseline: %w", err)
+	}
+	var baseline roadmap.Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return fmt.Errorf("parsing baseline %s: %w", args[0], err)
+	}
+	var doc roadmap.Document
+	if err := readDocument(args[1], &doc); err != nil {
+		return err
+	}
+	asOf := time.Now().Truncate(time.Second)
+	if roadmapBaselineCompareFlags.asOf != "" {
+		t, err := time.Parse("2006-01-02", roadmapBaselineCompareFlags.asOf)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of %q: expected YYYY-MM-DD", roadmapBaselineCompareFlags.asOf)
+		}
+		asOf = t
+	}
+	report := roadmap.CompareBaseline(&baseline, &doc, asOf)
+
+	format := strings.ToLower(roadmapBaselineCompareFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(roadmapBaselineCompareFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", roadmapBaselineCompareFlags.format)
+	}
+
+	if roadmapBaselineCompareFlags.output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(roadmapBaselineCompareFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, roadmapBaselineCompareFlags.output)
+	fmt.Printf("Generated: %s (%d late phase(s), %d deliverable(s) descoped)\n",
+		roadmapBaselineCompareFlags.output, report.LatePhases(), len(report.Descoped))
+	return nil
+}
+
+func runRoadmapValidate(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("roadmap"), validateRoadmapFile)
+}
+
+func validateRoadmapFile(inputFile string, stdout, stderr io.Writer) error {
+	var doc roadmap.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	errs := doc.Validate()
+	issues := make([]validation.Issue, 0, len(errs))
+	for _, e := range errs {
+		issues = append(issues, pathIssue("roadmap", e.Path, e.Message, e.IsError))
+	}
+	refIs, err := referenceIssues(inputFile, "roadmap", &doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIs...)
+	recordIssues(inputFile, "roadmap", issues)
+
+	errors := roadmap.Errors(errs)
+	warnings := roadmap.Warnings(errs)
+
+	if len(warnings) > 0 {
+		fmt.Fprintln(stdout, "Warnings:")
+		for _, w := range warnings {
+			fmt.Fprintf(stdout, "  - %s\n", w)
+		}
+		fmt.Fprintln(stdout)
+	}
+
+	if len(errors)+len(refIs) > 0 {
+		fmt.Fprintln(stdout, "Errors:")
+		for _, e := range errors {
+			fmt.Fprintf(stdout, "  - %s\n", e)
+		}
+		for _, is := range refIs {
+			fmt.Fprintf(stdout, "  - %s\n", is.Message)
+		}
+		return fmt.Errorf("validation failed with %d error(s)", len(errors)+len(refIs))
+	}
+
+	fmt.Fprintf(stdout, "Valid roadmap: %s\n", inputFile)
+	fmt.Fprintf(stdout, "  Title: %s\n", doc.Metadata.Title)
+	fmt.Fprintf(stdout, "  Themes: %d\n", len(doc.Themes))
+	fmt.Fprintf(stdout, "  Epics: %d\n", len(doc.Epics))
+	fmt.Fprintf(stdout, "  Phases: %d\n", len(doc.Phases))
+	fmt.Fprintf(stdout, "  Milestones: %d\n", len(doc.Milestones))
+	return nil
+}
+
+func runRoadmapGenerate(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	output := roadmapGenerateFlags.output
+	if output == "" {
+		output = deriveOutputPath(inputFile)
+	}
+
+	var doc roadmap.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return err
+	}
+
+	opts := roadmap.DefaultMarkdownOptions()
+	opts.IncludeSwimlaneTable = !roadmapGenerateFlags.noSwimlane
+	opts.Table.IncludeStatus = !roadmapGenerateFlags.swimlaneNoStatus
+	opts.IncludeDiagrams = roadmapGenerateFlags.mermaid
+	markdown := doc.ToMarkdown(opts)
+
+	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+func runRoadmapInit(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(roadmapInitFlags.output); err == nil {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", roadmapInitFlags.output)
+	}
+
+	level := roadmap.Level(strings.ToLower(roadmapInitFlags.level))
+	if level != roadmap.LevelPortfolio && level != roadmap.LevelProduct {
+		return fmt.Errorf("invalid level: %s (expected portfolio or product)", roadmapInitFlags.level)
+	}
+
+	now := time.Now()
+	quarter := time.Date(now.Year(), (now.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	q1End := quarter.AddDate(0, 3, -1)
+	q2Start := quarter.AddDate(0, 3, 0)
+	q2End := quarter.AddDate(0, 6, -1)
+	label := func(t time.Time) string {
+		return fmt.Sprintf("Q%d %d", (t.Month()-1)/3+1, t.Year())
+	}
+
+	template := &roadmap.Document{
+		Schema: "../schema/roadmap.schema.json",
+		Metadata: roadmap.Metadata{
+			ID:        fmt.Sprintf("roadmap-%s", now.Format("20060102")),
+			Title:     roadmapInitFlags.title,
+			Level:     level,
+			Owner:     "Your Name",
+			Version:   "1.0.0",
+			Status:    "Draft",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Vision: "Where do you want the product to be at the end of this roadmap?",
+		Themes: []roadmap.Theme{
+			{ID: "theme-1", Name: "First Theme", Description: "A strategic investment area"},
+		},
+		Epics: []roadmap.Epic{
+			{ID: "epic-1", Title: "First Epic", ThemeID: "theme-1", PhaseIDs: []string{"phase-1"}, Status: roadmap.DeliverableNotStarted},
+			{ID: "epic-2", Title: "Second Epic", ThemeID: "theme-1", PhaseIDs: []string{"phase-1", "phase-2"}, Status: roadmap.DeliverableNotStarted},
+		},
+		Phases: []roadmap.Phase{
+			{
+				ID:        "phase-1",
+				Name:      label(quarter),
+				Type:      roadmap.PhaseTypeQuarter,
+				StartDate: &quarter,
+				EndDate:   &q1End,
+				Goals:     []string{"What should be true at the end of this phase?"},
+				Deliverables: []roadmap.Deliverable{
+					{ID: "del-1", Title: "First deliverable", Type: roadmap.DeliverableFeature, Status: roadmap.DeliverableNotStarted},
+				},
+				SuccessCriteria: []string{"How will you know the phase succeeded?"},
+				Status:          roadmap.PhaseStatusPlanned,
+			},
+			{
+				ID:           "phase-2",
+				Name:         label(q2Start),
+				Type:         roadmap.PhaseTypeQuarter,
+				StartDate:    &q2Start,
+				EndDate:      &q2End,
+				Dependencies: []string{"phase-1"},
+				Deliverables: []roadmap.Deliverable{
+					{ID: "del-2", Title: "Second deliverable", Type: roadmap.DeliverableFeature, Status: roadmap.DeliverableNotStarted},
+				},
+				Status: roadmap.PhaseStatusPlanned,
+			},
+		},
+		Milestones: []roadmap.Milestone{
+			{ID: "ms-1", Title: "First release", Date: q1End.Format("2006-01-02"), PhaseID: "phase-1"},
+		},
+		Risks: []roadmap.Risk{
+			{
+				ID:          "risk-1",
+				Description: "What might prevent delivery?",
+				Probability: "Medium",
+				Impact:      "High",
+				Mitigation:  "How will you address this?",
+			},
+		},
+	}
+
+	if err := template.WriteFile(roadmapInitFlags.output); err != nil {
+		return fmt.Errorf("writing template: %w", err)
+	}
+
+	fmt.Printf("Created: %s\n", roadmapInitFlags.output)
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Edit the file to add your themes, epics, and phases")
+	fmt.Println("  2. Run 'splan roadmap validate " + roadmapInitFlags.output + "' to check the roadmap")
+	fmt.Println("  3. Run 'splan roadmap generate " + roadmapInitFlags.output + "' to create markdown")
+
+	return nil
+}
+
+// ============================================================================
+// Simulate Commands
+// ============================================================================
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Run what-if scenarios and schedule simulations",
+	Long: `Commands for exploring how scope changes would affect a PRD, and how
+likely a roadmap is to finish on time, without editing either.`,
+}
+
+var simulateDropCmd = &cobra.Command{
+	Use:   "drop FILE",
+	Short: "Simulate cutting tagged items from a PRD",
+	Long: `Compare a PRD with the same PRD after cutting every phase, deliverable,
+user story, requirement, objective, and key result tagged with any of --tags.
+
+Cutting a phase also cuts the user stories and requirements scheduled in it.
+The report compares roadmap size per phase, lists key result phase targets
+that lose contributing deliverables, and shows the change in score and
+completeness. The input file is not modified.
+
+Examples:
+  splan simulate drop my-product.prd.json --tags stretch
+  splan simulate drop my-product.prd.json --tags stretch,beta -o cut.md
+  splan simulate drop my-product.prd.json --tags stretch --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSimulateDrop,
+}
+
+var simulateDropFlags struct {
+	tags   []string
+	output string
+	format string
+}
+
+func init() {
+	simulateCmd.AddCommand(simulateDropCmd)
+
+	simulateDropCmd.Flags().StringSliceVar(&simulateDropFlags.tags, "tags", nil, "Tags of items to cut (comma-separated)")
+	simulateDropCmd.Flags().StringVarP(&simulateDropFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	simulateDropCmd.Flags().StringVarP(&simulateDropFlags.format, "format", "f", "", "Output format: markdown or json (default: from output extension, else markdown)")
+	_ = simulateDropCmd.MarkFlagRequired("tags")
+}
+
+func runSimulateDrop(cmd *cobra.Command, args []string) error {
+	for _, tag := range simulateDropFlags.tags {
+		if err := prd.ValidateTag(tag); err != nil {
+			return fmt.Errorf("invalid tag: %w", err)
+		}
+	}
+
+	var doc prd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	sim := doc.SimulateDrop(simulateDropFlags.tags...)
+
+	format := strings.ToLower(simulateDropFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(simulateDropFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString("# Scope Simulation: " + doc.Metadata.Title + "\n\n")
+		buf.WriteString(sim.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(sim, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling simulation: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", simulateDropFlags.format)
+	}
+
+	if simulateDropFlags.output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+	if err := os.WriteFile(simulateDropFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d items cut, score %.2f → %.2f)\n",
+		simulateDropFlags.output, len(sim.Dropped), sim.Score.Before, sim.Score.After)
+	return nil
+}
+
+var simulateScheduleCmd = &cobra.Command{
+	Use:   "schedule FILE",
+	Short: "Estimate completion-date probabilities of a roadmap",
+	Long: `Run a Monte Carlo simulation of the phases of a roadmap, or of a PRD's
+roadmap, and report the probability distribution of the completion date.
+
+Phases with an "estimate" of optimistic, likely, and pessimistic days take a
+random duration from a triangular distribution over them; other phases keep
+their planned length. A phase starts when its dependencies finish, but not
+before its start date. The report gives completion dates at P10 to P95, the
+probability of finishing by the latest phase end (or --deadline), and each
+phase's criticality and share of the schedule risk.
+
+Formats:
+  markdown  Tables and a Mermaid chart of the weekly distribution
+  json      The full result, including the weekly chart data
+  csv       The weekly chart data only
+
+Examples:
+  splan simulate schedule platform.roadmap.json
+  splan simulate schedule my-product.prd.json --deadline 2026-09-30
+  splan simulate schedule platform.roadmap.json -o risk.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSimulateSchedule,
+}
+
+var simulateScheduleFlags struct {
+	iterations int
+	seed       uint64
+	deadline   string
+	output     string
+	format     string
+}
+
+func init() {
+	simulateCmd.AddCommand(simulateScheduleCmd)
+
+	f := simulateScheduleCmd.Flags()
+	f.IntVarP(&simulateScheduleFlags.iterations, "iterations", "n", 10000, "Number of simulated schedules")
+	f.Uint64Var(&simulateScheduleFlags.seed, "seed", 1, "Random seed; the same seed gives the same result")
+	f.StringVar(&simulateScheduleFlags.deadline, "deadline", "", "Date to compute the on-time probability for, YYYY-MM-DD (default: latest phase end)")
+	f.StringVarP(&simulateScheduleFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	f.StringVarP(&simulateScheduleFlags.format, "format", "f", "", "Output format: markdown, json, or csv (default: from output extension, else markdown)")
+}
+
+func runSimulateSchedule(cmd *cobra.Command, args []string) error {
+	var title string
+	var phases []roadmap.Phase
+	if documentTypeFromPath(args[0]) == "prd" {
+		var doc prd.Document
+		if err := readDocument(args[0], &doc); err != nil {
+			return err
+		}
+		title, phases = doc.Metadata.Title, doc.Roadmap.Phases
+	} else {
+		var doc roadmap.Document
+		if err := readDocument(args[0], &doc); err != nil {
+			return err
+		}
+		title, phases = doc.Metadata.Title, doc.Phases
+	}
+
+	opts := roadmap.ScheduleSimulationOptions{
+		Iterations: simulateScheduleFlags.iterations,
+		Seed:       simulateScheduleFlags.seed,
+	}
+	if simulateScheduleFlags.deadline != "" {
+		t, err := time.Parse("2006-01-02", simulateScheduleFlags.deadline)
+		if err != nil {
+			return fmt.Errorf("invalid --deadline %q: expected YYYY-MM-DD", simulateScheduleFlags.deadline)
+		}
+		opts.Deadline = &t
+	}
+	sim, err := roadmap.SimulateSchedule(phases, opts)
+	if err != nil {
+		return fmt.Errorf("simulating schedule: %w", err)
+	}
+
+	format := strings.ToLower(simulateScheduleFlags.format)
+	if format == "" {
+		format = "markdown"
+		switch strings.ToLower(filepath.Ext(simulateScheduleFlags.output)) {
+		case ".json":
+			format = "json"
+		case ".csv":
+			format = "csv"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString("# Schedule Risk: " + title + "\n\n")
+		buf.WriteString(sim.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(sim, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling simulation: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	case "csv":
+		buf.WriteString(sim.ToCSV())
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json, csv)", simulateScheduleFlags.format)
+	}
+
+	if simulateScheduleFlags.output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+	if err := os.WriteFile(simulateScheduleFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	p50, _ := sim.Percentile(50)
+	fmt.Printf("Generated: %s (P50 %s, %.0f%% on time)\n",
+		simulateScheduleFlags.output, p50.Format("2006-01-02"), sim.OnTimeProbability*100)
+	return nil
+}
+
+// ============================================================================
+// Trace Commands
+// ============================================================================
+
+var traceCmd = &cobra.Command{
+	Use:   "trace PRD",
+	Short: "Build a traceability matrix across MRD, PRD, and TRD",
+	Long: `Trace each functional requirement of a PRD upstream to the market
+requirements it was derived from and downstream to its user stories, the TRD
+components that implement it, and the TRD test cases that verify it.
+
+Market requirements are linked through the PRD's provenance, as written by
+'splan requirements prd derive'. When --mrd is not given, the MRD recorded
+there is read if the file exists. Components link to requirements through
+their "requirements" field, and test cases through "requirements" or the
+components they exercise.
+
+Requirements missing a link are marked with gaps, and market requirements,
+user stories, components, and test cases that do not trace to any
+requirement are listed as orphans.
+
+Examples:
+  splan trace product.prd.json
+  splan trace product.prd.json --mrd market.mrd.json --trd product.trd.json -o trace.md
+  splan trace product.prd.json --trd product.trd.json -o trace.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+var traceFlags struct {
+	mrd    string
+	trd    string
+	output string
+	format string
+}
+
+func init() {
+	traceCmd.Flags().StringVar(&traceFlags.mrd, "mrd", "", "MRD file (default: the MRD in the PRD's provenance, if present)")
+	traceCmd.Flags().StringVar(&traceFlags.trd, "trd", "", "TRD file")
+	traceCmd.Flags().StringVarP(&traceFlags.output, "output", "o", "", "Output file (default: stdout)")
+	traceCmd.Flags().StringVar(&traceFlags.format, "format", "", "Output format: markdown, csv, json (default: from output extension)")
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	var p prd.Document
+	if err := readDocument(args[0], &p); err != nil {
+		return err
+	}
+
+	mrdPath := traceFlags.mrd
+	if pv := p.Provenance; mrdPath == "" && pv != nil && pv.Source.Type == "mrd" && pv.Source.Path != "" {
+		path := filepath.Join(filepath.Dir(args[0]), filepath.FromSlash(pv.Source.Path))
+		if _, err := os.Stat(path); err == nil {
+			mrdPath = path
+		}
+	}
+	var m *mrd.Document
+	if mrdPath != "" {
+		m = &mrd.Document{}
+		if err := readDocument(mrdPath, m); err != nil {
+			return err
+		}
+	}
+	var t *trd.Document
+	if traceFlags.trd != "" {
+		t = &trd.Document{}
+		if err := readDocument(traceFlags.trd, t); err != nil {
+			return err
+		}
+	}
+
+	mx := trace.Build(&p, m, t)
+
+	format := strings.ToLower(traceFlags.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(traceFlags.output)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		default:
+			format = "markdown"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString("# Traceability: " + p.Metadata.Title + "\n\n")
+		buf.WriteString(mx.ToMarkdown())
+	case "csv":
+		if err := mx.WriteCSV(&buf); err != nil {
+			return err
+		}
+	case "json":
+		data, err := json.MarshalIndent(mx, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling traceability matrix: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, csv, json)", traceFlags.format)
+	}
+
+	if traceFlags.output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+	if err := os.WriteFile(traceFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d requirements, %d with gaps, %d orphaned items)\n",
+		traceFlags.output, len(mx.Rows), mx.Gaps(), len(mx.Orphans))
+	for _, w := range mx.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return nil
+}
+
+// ============================================================================
+// Goals Parent Command
+// ============================================================================
+
+var goalsCmd = &cobra.Command{
+	Use:   "goals",
+	Short: "Work with goal frameworks (OKR, V2MOM)",
+	Long: `Commands for generating and validating goal framework documents.
+
+Supported frameworks:
+  - v2mom: Vision, Values, Methods, Obstacles, Measures
+  - okr: Objectives and Key Results`,
+}
+
+// ============================================================================
+// Comments Commands
+// ============================================================================
+
+var commentsCmd = &cobra.Command{
+	Use:   "comments",
+	Short: "Add, resolve, and list review comments in PRDs, MRDs, and TRDs",
+	Long: `Commands for review comments stored in a document's comments array.
+
+A comment records its author, creation time, text, and the path of the entity
+it is about, using the document's JSON field names:
+
+  executiveSummary.problemStatement
+  requirements.functional.FR-3
+  risks[2]
+
+Replies form a thread under the first comment; resolving any comment in a
+thread resolves the thread. add and resolve rewrite the file in place.
+
+The document type is taken from the file name (e.g. checkout.prd.json) or --kind.`,
+}
+
+var commentsFlags struct {
+	kind    string
+	path    string
+	text    string
+	author  string
+	replyTo string
+	all     bool
+	json    bool
+}
+
+var commentsAddCmd = &cobra.Command{
+	Use:   "add FILE",
+	Short: "Add a comment or reply",
+	Example: `  splan comments add checkout.prd.json --path requirements.functional.FR-3 --text "Is this a must for MVP?"
+  splan comments add checkout.prd.json --reply-to C-1 --text "Yes, legal requires it" --author dana`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCommentsAdd,
+}
+
+var commentsResolveCmd = &cobra.Command{
+	Use:     "resolve FILE ID",
+	Short:   "Resolve a comment thread",
+	Example: `  splan comments resolve checkout.prd.json C-1`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    runCommentsResolve,
+}
+
+var commentsListCmd = &cobra.Command{
+	Use:   "list FILE",
+	Short: "List comment threads",
+	Long:  `List unresolved comment threads, or every thread with --all. --path limits the list to paths that start with the given prefix.`,
+	Example: `  splan comments list checkout.prd.json
+  splan comments list checkout.prd.json --path requirements --all`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCommentsList,
+}
+
+func init() {
+	commentsCmd.PersistentFlags().StringVar(&commentsFlags.kind, "kind", "", "Document type: prd, mrd, trd (default: from file name)")
+
+	commentsAddCmd.Flags().StringVar(&commentsFlags.path, "path", "", "Path of the commented entity (not needed with --reply-to)")
+	commentsAddCmd.Flags().StringVar(&commentsFlags.text, "text", "", "Comment text")
+	commentsAddCmd.Flags().StringVar(&commentsFlags.author, "author", os.Getenv("USER"), "Comment author")
+	commentsAddCmd.Flags().StringVar(&commentsFlags.replyTo, "reply-to", "", "ID of the comment to reply to")
+	_ = commentsAddCmd.MarkFlagRequired("text")
+
+	commentsResolveCmd.Flags().StringVar(&commentsFlags.author, "by", os.Getenv("USER"), "Person resolving the thread")
+
+	commentsListCmd.Flags().StringVar(&commentsFlags.path, "path", "", "Only list comments whose path starts with this prefix")
+	commentsListCmd.Flags().BoolVar(&commentsFlags.all, "all", false, "Include resolved threads")
+	commentsListCmd.Flags().BoolVar(&commentsFlags.json, "json", false, "Output as JSON")
+
+	commentsCmd.AddCommand(commentsAddCmd)
+	commentsCmd.AddCommand(commentsResolveCmd)
+	commentsCmd.AddCommand(commentsListCmd)
+}
+
+// commentedDocument is a document loaded by the comments commands, with a
+// pointer to its comments so they can be edited in place.
+type commentedDocument struct {
+	doc      any
+	src      *sourceDocument
+	comments *[]common.Comment
+}
+
+// requirementsKind returns "prd", "mrd", or "trd" for path, using kind when
+// set and otherwise the second extension of the file name (checkout.prd.json).
+func requirementsKind(path, kind string) (string, error) {
+	kind = strings.ToLower(kind)
+	if kind == "" {
+		name := strings.TrimSuffix(strings.ToLower(filepath.Base(path)), filepath.Ext(path))
+		kind = strings.TrimPrefix(filepath.Ext(name), ".")
+	}
+	switch kind {
+	case "prd", "mrd", "trd":
+		return kind, nil
+	}
+	return "", fmt.Errorf("cannot tell the document type of %s: name it *.prd.json, *.mrd.json, or *.trd.json, or use --kind", path)
+}
+
+func readCommentedDocument(path string) (*commentedDocument, error) {
+	kind, err := requirementsKind(path, commentsFlags.kind)
+	if err != nil {
+		return nil, err
+	}
+	var cd commentedDocument
+	switch kind {
+	case "prd":
+		var d prd.Document
+		cd = commentedDocument{doc: &d, comments: &d.Comments}
+	case "mrd":
+		var d mrd.Document
+		cd = commentedDocument{doc: &d, comments: &d.Comments}
+	case "trd":
+		var d trd.Document
+		cd = commentedDocument{doc: &d, comments: &d.Comments}
+	}
+	if cd.src, err = readSourceDocument(path, cd.doc, os.Stderr); err != nil {
+		return nil, err
+	}
+	return &cd, nil
+}
+
+// writeDocumentInPlace writes doc back to path in the format of src, the
+// source readSourceDocument read it from. Only the changes made to doc
+// since then are written: members the document types do not model, key
+// order, and YAML comments are kept. Content expanded from snippets is
+// written as snippet references again, unless it was edited. If src is
+// nil, doc is written whole.
+func writeDocumentInPlace(path string, src *sourceDocument, doc any) error {
+	data, err := marshalJSON(doc)
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		return writeJSONDocument(path, data)
+	}
+	var base, changed any
+	if err := json.Unmarshal(src.typed, &base); err != nil {
+		return fmt.Errorf("decoding document: %w", err)
+	}
+	if err := json.Unmarshal(data, &changed); err != nil {
+		return fmt.Errorf("decoding document: %w", err)
+	}
+	expanded, err := decodeJSON(src.expanded)
+	if err != nil {
+		return err
+	}
+	return patchSource(path, src, jsonpatch.Rebase(expanded, base, changed), data)
+}
+
+// patchDocumentInPlace applies patch to src, the source a document was
+// read from by readSourceDocument, and writes the result to path. A patch
+// computed from the document's JSON form may test or set members the
+// source leaves out, such as an empty status; if it does not apply to the
+// source, it is applied to the JSON form and the changes carried over.
+func patchDocumentInPlace(path string, src *sourceDocument, patch jsonpatch.Patch) error {
+	expanded, err := decodeJSON(src.expanded)
+	if err != nil {
+		return err
+	}
+	if _, err := patch.Apply(expanded); err != nil {
+		base, derr := decodeJSON(src.typed)
+		if derr != nil {
+			return derr
+		}
+		changed, perr := patch.Apply(base)
+		if perr != nil {
+			return fmt.Errorf("applying patch: %w", err)
+		}
+		patch = jsonpatch.Rebase(expanded, base, changed)
+	}
+	return patchSource(path, src, patch, src.typed)
+}
+
+// patchSource applies patch, a patch of the document with its snippet
+// references expanded, to the source src of the document and writes the
+// result to path. Content expanded from snippets that the patch leaves
+// alone is written as snippet references again. The objects the patch adds
+// keep the member order of the same objects in order, if it is not nil.
+func patchSource(path string, src *sourceDocument, patch jsonpatch.Patch, order []byte) error {
+	expanded, err := decodeJSON(src.expanded)
+	if err != nil {
+		return err
+	}
+	patched, err := patch.Apply(expanded)
+	if err != nil {
+		return fmt.Errorf("applying patch: %w", err)
+	}
+	data, err := marshalJSON(patched)
+	if err != nil {
+		return err
+	}
+	if data, err = snippets.Collapse(data, src.refs); err != nil {
+		return fmt.Errorf("collapsing snippets: %w", err)
+	}
+	if yamlconv.FormatFromPath(path) != src.format {
+		return writeJSONDocument(path, data)
+	}
+	original, err := decodeJSON(src.json)
+	if err != nil {
+		return err
+	}
+	want, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	if data, err = yamlconv.Patch(src.data, src.format, jsonpatch.Diff(original, want), order); err != nil {
+		return fmt.Errorf("updating document: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	return nil
+}
+
+// writeJSONDocument writes the JSON document data to path, as YAML if the
+// path has a YAML extension.
+func writeJSONDocument(path string, data []byte) error {
+	var err error
+	if yamlconv.FormatFromPath(path) == yamlconv.FormatYAML {
+		data, err = yamlconv.FromJSON(data)
+	} else {
+		var buf bytes.Buffer
+		err = json.Indent(&buf, data, "", "  ")
+		data = append(buf.Bytes(), '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("marshaling document: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	return nil
+}
+
+// marshalJSON encodes v as compact JSON, leaving &, <, and > unescaped.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// decodeJSON decodes a JSON document into map[string]any, []any, and
+// scalar values.
+func decodeJSON(data []byte) (any, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	return v, nil
+}
+
+func runCommentsAdd(cmd *cobra.Command, args []string) error {
+	cd, err := readCommentedDocument(args[0])
+	if err != nil {
+		return err
+	}
+	if commentsFlags.author == "" {
+		return fmt.Errorf("--author is required when USER is not set")
+	}
+	if commentsFlags.replyTo == "" {
+		if commentsFlags.path == "" {
+			return fmt.Errorf("--path is required for a new thread")
+		}
+		if err := common.ValidateCommentPath(cd.doc, commentsFlags.path); err != nil {
+			return err
+		}
+	}
+	comments, c, err := common.AddComment(*cd.comments, common.Comment{
+		Author:    commentsFlags.author,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Path:      commentsFlags.path,
+		Text:      commentsFlags.text,
+		InReplyTo: commentsFlags.replyTo,
+	})
+	if err != nil {
+		return err
+	}
+	*cd.comments = comments
+	if err := writeDocumentInPlace(args[0], cd.src, cd.doc); err != nil {
+		return err
+	}
+	fmt.Printf("Added: %s on %s\n", c.ID, c.Path)
+	return nil
+}
+
+func runCommentsResolve(cmd *cobra.Command, args []string) error {
+	cd, err := readCommentedDocument(args[0])
+	if err != nil {
+		return err
+	}
+	id, err := common.ResolveComment(*cd.comments, args[1], commentsFlags.author, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return err
+	}
+	if err := writeDocumentInPlace(args[0], cd.src, cd.doc); err != nil {
+		return err
+	}
+	fmt.Printf("Resolved: %s\n", id)
+	return nil
+}
+
+func runCommentsList(cmd *cobra.Command, args []string) error {
+	cd, err := readCommentedDocument(args[0])
+	if err != nil {
+		return err
+	}
+	threads := common.UnresolvedThreads(*cd.comments)
+	if commentsFlags.all {
+		threads = common.CommentThreads(*cd.comments)
+	}
+	var matched []common.CommentThread
+	for _, t := range threads {
+		if strings.HasPrefix(t.Path, commentsFlags.path) {
+			matched = append(matched, t)
+		}
+	}
+
+	if commentsFlags.json {
+		data, err := json.MarshalIndent(matched, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling comments: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("No comments found")
+		return nil
+	}
+	for _, t := range matched {
+		state := "open"
+		if t.Resolved {
+			state = "resolved by " + t.ResolvedBy
+		}
+		fmt.Printf("%s [%s] %s\n", t.ID, state, t.Path)
+		fmt.Printf("  %s (%s): %s\n", t.Author, t.CreatedAt.Format("2006-01-02"), t.Text)
+		for _, r := range t.Replies {
+			fmt.Printf("  %s %s (%s): %s\n", r.ID, r.Author, r.CreatedAt.Format("2006-01-02"), r.Text)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Review Commands
+// ============================================================================
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [FILE.prd.json]",
+	Short: "Review a PRD interactively, or record reviewer sign-off on PRDs, MRDs, and TRDs",
+	Long: `Given a PRD, open an interactive review in the terminal: browse the scoring
+categories with their scores and findings, inspect the JSON each finding
+points at, and mark findings resolved. Press w to save the resolutions as
+resolved comment threads on the entities, so later reviews show them as
+resolved; press q to quit.
+
+The subcommands manage the reviews recorded in a document's metadata.
+
+Each reviewer has a state: pending, approved, or changes_requested. Requesting a
+review again resets the reviewer to pending. Roles listed in
+metadata.requiredReviewRoles must each have an approving review, with no
+reviewer in that role requesting changes, before the document status can be
+approved; validate reports documents that break this rule.
+
+Requesting a review moves a draft or approved document to in_review. The
+commands rewrite the file in place. The document type is taken from the file name
+(e.g. checkout.prd.json) or --kind.`,
+	Example: `  splan review plans/checkout.prd.json
+  splan review request plans/checkout.prd.json --reviewer alice --role security`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReviewInteractive,
+}
+
+var reviewFlags struct {
+	kind     string
+	author   string
+	reviewer string
+	email    string
+	role     string
+	required bool
+	note     string
+	json     bool
+}
+
+var reviewRequestCmd = &cobra.Command{
+	Use:   "request FILE",
+	Short: "Request a review",
+	Example: `  splan review request checkout.prd.json --reviewer alice --role security --required
+  splan review request checkout.prd.json --reviewer bob --role engineering`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReviewRequest,
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:     "approve FILE",
+	Short:   "Record an approving review",
+	Example: `  splan review approve checkout.prd.json --reviewer alice`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runReviewApprove,
+}
+
+var reviewRequestChangesCmd = &cobra.Command{
+	Use:     "request-changes FILE",
+	Short:   "Record a review that requests changes",
+	Example: `  splan review request-changes checkout.prd.json --reviewer bob --note "Add a rollback plan"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runReviewRequestChanges,
+}
+
+var reviewStatusCmd = &cobra.Command{
+	Use:   "status FILE",
+	Short: "Show reviewers and outstanding sign-offs",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runReviewStatus,
+}
+
+func init() {
+	reviewCmd.PersistentFlags().StringVar(&reviewFlags.kind, "kind", "", "Document type: prd, mrd, trd (default: from file name)")
+	reviewCmd.Flags().StringVar(&reviewFlags.author, "author", os.Getenv("USER"), "Person recorded as resolving findings")
+
+	reviewRequestCmd.Flags().StringVar(&reviewFlags.reviewer, "reviewer", "", "Reviewer name")
+	reviewRequestCmd.Flags().StringVar(&reviewFlags.email, "email", "", "Reviewer email")
+	reviewRequestCmd.Flags().StringVar(&reviewFlags.role, "role", "", "Role the reviewer signs off for (e.g. security, legal)")
+	reviewRequestCmd.Flags().BoolVar(&reviewFlags.required, "required", false, "Add --role to the roles required for approval")
+	_ = reviewRequestCmd.MarkFlagRequired("reviewer")
+
+	for _, c := range []*cobra.Command{reviewApproveCmd, reviewRequestChangesCmd} {
+		c.Flags().StringVar(&reviewFlags.reviewer, "reviewer", os.Getenv("USER"), "Reviewer name or email")
+		c.Flags().StringVar(&reviewFlags.note, "note", "", "Note to record with the review")
+	}
+
+	reviewStatusCmd.Flags().BoolVar(&reviewFlags.json, "json", false, "Output as JSON")
+
+	reviewCmd.AddCommand(reviewRequestCmd)
+	reviewCmd.AddCommand(reviewApproveCmd)
+	reviewCmd.AddCommand(reviewRequestChangesCmd)
+	reviewCmd.AddCommand(reviewStatusCmd)
+}
+
+// reviewedDocument is a document loaded by the review and lifecycle
+// commands, with pointers to the metadata fields they edit.
+type reviewedDocument struct {
+	doc           any
+	src           *sourceDocument
+	status        *common.Status
+	reviews       *[]common.Review
+	requiredRoles *[]string
+	approvers     *[]common.Approver
+	reviewedAt    **time.Time
+	supersededBy  *string
+	lifecycle     func() common.Lifecycle
+}
+
+func readReviewedDocument(path, kind string) (*reviewedDocument, error) {
+	kind, err := requirementsKind(path, kind)
+	if err != nil {
+		return nil, err
+	}
+	var rd reviewedDocument
+	switch kind {
+	case "prd":
+		var d prd.Document
+		m := &d.Metadata
+		rd = reviewedDocument{doc: &d, status: &m.Status, reviews: &m.Reviews, requiredRoles: &m.RequiredReviewRoles,
+			approvers: &m.Approvers, reviewedAt: &m.ReviewedAt, supersededBy: &m.SupersededBy,
+			lifecycle: func() common.Lifecycle { return m.Lifecycle() }}
+	case "mrd":
+		var d mrd.Document
+		m := &d.Metadata
+		rd = reviewedDocument{doc: &d, status: &m.Status, reviews: &m.Reviews, requiredRoles: &m.RequiredReviewRoles,
+			approvers: &m.Approvers, reviewedAt: &m.ReviewedAt, supersededBy: &m.SupersededBy,
+			lifecycle: func() common.Lifecycle { return m.Lifecycle() }}
+	case "trd":
+		var d trd.Document
+		m := &d.Metadata
+		rd = reviewedDocument{doc: &d, status: &m.Status, reviews: &m.Reviews, requiredRoles: &m.RequiredReviewRoles,
+			approvers: &m.Approvers, reviewedAt: &m.ReviewedAt, supersededBy: &m.SupersededBy,
+			lifecycle: func() common.Lifecycle { return m.Lifecycle() }}
+	}
+	if rd.src, err = readSourceDocument(path, rd.doc, os.Stderr); err != nil {
+		return nil, err
+	}
+	return &rd, nil
+}
+
+func runReviewInteractive(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	path := args[0]
+	if kind, err := requirementsKind(path, reviewFlags.kind); err != nil {
+		return err
+	} else if kind != "prd" {
+		return fmt.Errorf("the interactive review supports PRDs only, not %s", strings.ToUpper(kind))
+	}
+
+	var doc prd.Document
+	src, err := readSourceDocument(path, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	m, err := tui.New(prd.ScoreToEvaluationReport(&doc, path), &doc, doc.Comments)
+	if err != nil {
+		return err
+	}
+	save := func() error {
+		if reviewFlags.author == "" {
+			return fmt.Errorf("--author is required when USER is not set")
+		}
+		comments, added, reopened, err := m.ApplyResolutions(doc.Comments, reviewFlags.author, time.Now().UTC().Truncate(time.Second))
+		if err != nil {
+			return err
+		}
+		doc.Comments = comments
+		if err := writeDocumentInPlace(path, src, &doc); err != nil {
+			return err
+		}
+		m.Saved(fmt.Sprintf("Saved %s: %d resolved, %d reopened", path, added, reopened))
+		return nil
+	}
+	if err := tui.Run(os.Stdin, os.Stdout, m, save); err != nil {
+		if errors.Is(err, tui.ErrNotTerminal) {
+			return fmt.Errorf("%w; use \"splan requirements prd score\" for a static report", err)
+		}
+		return err
+	}
+	if m.Dirty() {
+		fmt.Fprintln(os.Stderr, "Discarded unsaved resolutions")
+	}
+	return nil
+}
+
+func runReviewRequest(cmd *cobra.Command, args []string) error {
+	rd, err := readReviewedDocument(args[0], reviewFlags.kind)
+	if err != nil {
+		return err
+	}
+	if reviewFlags.required {
+		if reviewFlags.role == "" {
+			return fmt.Errorf("--required needs --role")
+		}
+		if !slices.ContainsFunc(*rd.requiredRoles, func(r string) bool { return strings.EqualFold(r, reviewFlags.role) }) {
+			*rd.requiredRoles = append(*rd.requiredRoles, reviewFlags.role)
+		}
+	}
+	if *rd.status != common.StatusInReview {
+		if err := common.CheckTransition(*rd.status, common.StatusInReview); err != nil {
+			return fmt.Errorf("cannot request a review: %w", err)
+		}
+		*rd.status = common.StatusInReview
+	}
+	reviewer := common.Person{Name: reviewFlags.reviewer, Email: reviewFlags.email, Role: reviewFlags.role}
+	reviews, err := common.RequestReview(*rd.reviews, reviewer, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return err
+	}
+	*rd.reviews = reviews
+	if err := writeDocumentInPlace(args[0], rd.src, rd.doc); err != nil {
+		return err
+	}
+	fmt.Printf("Requested: review from %s\n", reviewFlags.reviewer)
+	return nil
+}
+
+func runReviewApprove(cmd *cobra.Command, args []string) error {
+	return setReviewState(args[0], common.ReviewStateApproved)
+}
+
+func runReviewRequestChanges(cmd *cobra.Command, args []string) error {
+	return setReviewState(args[0], common.ReviewStateChangesRequested)
+}
+
+func setReviewState(path string, state common.ReviewState) error {
+	rd, err := readReviewedDocument(path, reviewFlags.kind)
+	if err != nil {
+		return err
+	}
+	if reviewFlags.reviewer == "" {
+		return fmt.Errorf("--reviewer is required when USER is not set")
+	}
+	if err := common.SetReviewState(*rd.reviews, reviewFlags.reviewer, state, reviewFlags.note, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return err
+	}
+	if err := writeDocumentInPlace(path, rd.src, rd.doc); err != nil {
+		return err
+	}
+	fmt.Printf("Recorded: %s %s\n", reviewFlags.reviewer, state)
+
+	missing := common.MissingSignoffs(*rd.reviews, *rd.requiredRoles)
+	switch {
+	case len(missing) > 0:
+		fmt.Printf("Awaiting sign-off: %s\n", strings.Join(missing, ", "))
+	case *rd.status != common.StatusApproved:
+		fmt.Println("All required roles have signed off; approve the document with splan lifecycle approve")
+	}
+	return nil
+}
+
+func runReviewStatus(cmd *cobra.Command, args []string) error {
+	rd, err := readReviewedDocument(args[0], reviewFlags.kind)
+	if err != nil {
+		return err
+	}
+	missing := common.MissingSignoffs(*rd.reviews, *rd.requiredRoles)
+
+	if reviewFlags.json {
+		data, err := json.MarshalIndent(map[string]any{
+			"status":           *rd.status,
+			"reviews":          *rd.reviews,
+			"requiredRoles":    *rd.requiredRoles,
+			"missingSignoffs":  missing,
+			"readyForApproval": len(missing) == 0,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling reviews: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Status: %s\n", *rd.status)
+	if len(*rd.reviews) == 0 {
+		fmt.Println("No reviews requested")
+	}
+	for _, r := range *rd.reviews {
+		line := fmt.Sprintf("  %s [%s]", r.Name, r.State)
+		if r.Role != "" {
+			line += " " + r.Role
+		}
+		if r.Note != "" {
+			line += ": " + r.Note
+		}
+		fmt.Println(line)
+	}
+	if len(missing) > 0 {
+		fmt.Printf("Awaiting sign-off: %s\n", strings.Join(missing, ", "))
+	}
+	for _, problem := range common.ValidateReviews(*rd.status, *rd.reviews, *rd.requiredRoles) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", problem)
+	}
+	return nil
+}
+
+// ============================================================================
+// Edit Command
+// ============================================================================
+
+var editCmd = &cobra.Command{
+	Use:   "edit FILE.prd.json",
+	Short: "Edit a PRD in a terminal UI",
+	Long: `Open a PRD in a terminal editor. Browse the summary, personas, user stories,
+and functional and non-functional requirements; open an entry to edit its
+fields in a form, press a to add an entry with the next free ID, and press d
+twice to delete one. Validation issues are shown next to the fields they are
+about and refresh after every change.
+
+Lists such as goals and tags are edited on one line with items separated by
+";". Acceptance criteria are edited as their descriptions; criteria whose
+description is unchanged keep their IDs and Given/When/Then steps.
+Enumerated fields such as priorities step through their values on enter.
+
+Press w to write the document back to the file in its original format and q
+to quit. Fields the editor does not show keep their values; as with the other
+commands that rewrite a file, fields the PRD format does not define are
+dropped.`,
+	Example: `  splan edit plans/checkout.prd.json
+  splan edit plans/checkout.prd.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	if kind, err := requirementsKind(path, ""); err != nil {
+		return err
+	} else if kind != "prd" {
+		return fmt.Errorf("the editor supports PRDs only, not %s", strings.ToUpper(kind))
+	}
+
+	var doc prd.Document
+	src, err := readSourceDocument(path, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	m, err := editor.New(&doc)
+	if err != nil {
+		return err
+	}
+	save := func() error {
+		if err := writeDocumentInPlace(path, src, m.Doc); err != nil {
+			return err
+		}
+		m.Saved("Saved " + path)
+		return nil
+	}
+	if err := editor.Run(os.Stdin, os.Stdout, m, save); err != nil {
+		if errors.Is(err, editor.ErrNotTerminal) {
+			return fmt.Errorf("%w; edit the file directly and check it with \"splan requirements prd validate\"", err)
+		}
+		return err
+	}
+	if m.Dirty() {
+		fmt.Fprintln(os.Stderr, "Discarded unsaved changes")
+	}
+	return nil
+}
+
+// ============================================================================
+// Lint Command
+// ============================================================================
+
+var lintFlags struct {
+	config  string
+	docType string
+	strict  bool
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint FILE...",
+	Short: "Check documents against configurable style rules",
+	Long: `Check documents against style rules that schema validation does not
+cover. The rules apply to every document type:
+
+  id-format           IDs follow the naming convention of their collection,
+                      e.g. FR-001 for functional requirements (warning)
+  description-length  descriptions are at most 1000 characters (warning)
+  placeholder-text    no TBD, TODO, FIXME, or XXX left in the text (warning)
+  duplicate-id        no two items in the same scope share an ID (error)
+  broken-reference    reference fields such as phaseId and personaId name
+                      an ID defined in the document (error)
+
+Rules are configured in the "lint" section of .splan.yaml, found in the
+document's directory or a parent directory, or given with --config:
+
+  lint:
+    rules:
+      id-format:
+        severity: error
+        patterns:
+          prd:
+            $.requirements.functional[*].id: ^FR-[0-9]{3}$
+            $.userStories[*].id: ^US-[0-9]{3}$
+      description-length:
+        max: 500
+      placeholder-text:
+        terms: [TBD, TODO, FIXME, TBC]
+      broken-reference:
+        severity: off
+
+Each rule takes a severity of error, warning, or off. Only errors fail the
+command, unless --strict is set. Use --format json or sarif for a
+machine-readable report.
+
+Given a directory or a glob, every document found is linted in parallel,
+followed by a pass/fail summary.`,
+	Example: `  splan lint checkout.prd.json
+  splan lint docs/ --strict
+  splan lint docs/ --format sarif > lint.sarif
+  splan lint goals.json --type okr --config ci/.splan.yaml`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFlags.config, "config", "", "Project configuration file (default: nearest .splan.yaml)")
+	lintCmd.Flags().StringVar(&lintFlags.docType, "type", "", "Document type (default: from the filename)")
+	lintCmd.Flags().BoolVar(&lintFlags.strict, "strict", false, "Fail on warnings as well as errors")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd", "mrd", "trd", "okr", "v2mom", "roadmap"), lintFile)
+}
+
+func lintFile(inputFile string, stdout, stderr io.Writer) error {
+	docType := strings.ToLower(lintFlags.docType)
+	if docType == "" {
+		docType = documentTypeFromPath(inputFile)
+	}
+
+	cfg := lint.DefaultConfig()
+	configFile := lintFlags.config
+	if configFile == "" {
+		var err error
+		if configFile, err = lint.FindConfig(filepath.Dir(inputFile)); err != nil {
+			return err
+		}
+	}
+	if configFile != "" {
+		var err error
+		if cfg, err = lint.LoadConfig(configFile); err != nil {
+			return err
+		}
+	}
+	linter, err := lint.New(cfg)
+	if err != nil {
+		return fmt.Errorf("%s: %w", configFile, err)
+	}
+
+	format, err := inputFormat(inputFile)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+	jsonData := data
+	if format == yamlconv.FormatYAML {
+		if jsonData, err = yamlconv.ToJSON(data); err != nil {
+			return err
+		}
+	}
+	issues, err := linter.Lint(docType, jsonData)
+	if err != nil {
+		return err
+	}
+
+	var errCount, warnCount int
+	for i, is := range issues {
+		issues[i].Line, issues[i].Column = schema.Locate(data, format, is.Pointer)
+		if is.Severity == validation.SeverityError {
+			errCount++
+		} else {
+			warnCount++
+		}
+	}
+	recordIssues(inputFile, docType, issues)
+
+	for _, is := range issues {
+		fmt.Fprintf(stdout, "%s:%d:%d: %s: %s [%s]\n", inputFile, is.Line, is.Column, is.Severity, is.Message, is.RuleID)
+	}
+	if errCount > 0 || (lintFlags.strict && warnCount > 0) {
+		return fmt.Errorf("lint found %d error(s) and %d warning(s)", errCount, warnCount)
+	}
+	if warnCount > 0 {
+		fmt.Fprintf(stdout, "Lint passed: %s (%d warnings)\n", inputFile, warnCount)
+	} else {
+		fmt.Fprintf(stdout, "Lint passed: %s\n", inputFile)
+	}
+	return nil
+}
+
+// ============================================================================
+// People Commands
+// ============================================================================
+
+var peopleFlags struct {
+	directory string
+
+	// loaded is the directory given with --directory, read once for all
+	// files.
+	loaded *directory.Directory
+}
+
+var peopleCmd = &cobra.Command{
+	Use:   "people",
+	Short: "Check document owners against the people directory",
+	Long: `Check the owners, authors, reviewers, and approvers of documents against
+a people directory.
+
+The directory is set in the "directory" section of splan.workspace.json as
+a local YAML or JSON file, or as a command that prints the directory on
+stdout, such as a script that queries LDAP or Google Workspace:
+
+  "directory": {"file": "people.yaml"}
+  "directory": {"command": ["./scripts/ldap-people.sh", "--group", "eng"]}
+
+When a directory is set, generated documents are also enriched with the
+email and role of their authors, reviewers, and approvers.`,
+}
+
+var peopleCheckCmd = &cobra.Command{
+	Use:   "check FILE...",
+	Short: "Report owners who are unknown or have left",
+	Long: `Look up every owner, author, reviewer, and approver in the documents in the
+people directory. A person who has left the organization is an error
+(departed-owner), with their successor if the directory names one. A name
+that is neither a person nor a team in the directory is a warning
+(unknown-owner).
+
+The directory comes from the workspace manifest unless --directory is
+given. Use --format json or sarif for a machine-readable report.`,
+	Example: `  splan people check checkout.prd.json
+  splan people check docs/ --directory people.yaml
+  splan people check docs/ --format sarif > owners.sarif`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPeopleCheck,
+}
+
+func init() {
+	peopleCmd.AddCommand(peopleCheckCmd)
+	peopleCheckCmd.Flags().StringVar(&peopleFlags.directory, "directory", "", "People directory file (default: from splan.workspace.json)")
+}
+
+func runPeopleCheck(cmd *cobra.Command, args []string) error {
+	if peopleFlags.directory != "" {
+		d, err := directory.Load(peopleFlags.directory)
+		if err != nil {
+			return err
+		}
+		peopleFlags.loaded = d
+	}
+	return runFiles(args, documentMatcher("prd", "mrd", "trd", "okr", "v2mom", "roadmap"), checkPeopleFile)
+}
+
+func checkPeopleFile(inputFile string, stdout, stderr io.Writer) error {
+	dir := peopleFlags.loaded
+	if dir == nil {
+		var err error
+		if dir, err = workspace.DirectoryFor(inputFile); err != nil {
+			return err
+		}
+		if dir == nil {
+			return fmt.Errorf("no people directory: set \"directory\" in %s or use --directory", workspace.ManifestFile)
+		}
+	}
+
+	format, err := inputFormat(inputFile)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+	jsonData := data
+	if format == yamlconv.FormatYAML {
+		if jsonData, err = yamlconv.ToJSON(data); err != nil {
+			return err
+		}
+	}
+	issues, err := dir.Check(jsonData)
+	if err != nil {
+		return err
+	}
+
+	var errCount, warnCount int
+	for i, is := range issues {
+		issues[i].Line, issues[i].Column = schema.Locate(data, format, is.Pointer)
+		if is.Severity == validation.SeverityError {
+			errCount++
+		} else {
+			warnCount++
+		}
+	}
+	recordIssues(inputFile, documentTypeFromPath(inputFile), issues)
+
+	for _, is := range issues {
+		fmt.Fprintf(stdout, "%s:%d:%d: %s: %s [%s]\n", inputFile, is.Line, is.Column, is.Severity, is.Message, is.RuleID)
+	}
+	if errCount > 0 {
+		return fmt.Errorf("%d owner(s) have left the directory", errCount)
+	}
+	if warnCount > 0 {
+		fmt.Fprintf(stdout, "Owners checked: %s (%d not in the directory)\n", inputFile, warnCount)
+	} else {
+		fmt.Fprintf(stdout, "Owners checked: %s\n", inputFile)
+	}
+	return nil
+}
+
+// ============================================================================
+// Bundle Command
+// ============================================================================
+
+var bundleFlags struct {
+	output  string
+	docType string
+	redact  bool
+}
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle FILE",
+	Short: "Package a document and its generated outputs into a zip archive",
+	Long: `Package a PRD, MRD, or TRD into a single timestamped zip archive for
+record-keeping and handoffs. The archive holds:
+
+  - the source document, unchanged
+  - the generated Markdown, HTML, and DOCX
+  - a PDF, converted from the Markdown with Pandoc when it is installed
+  - Marp slides (PRDs)
+  - the score report as JSON and Markdown (PRDs)
+  - the local files the document references, such as wireframes and
+    diagrams, under assets/ with the generated outputs linking to them
+  - manifest.json, listing each file with its size and SHA-256 digest and
+    any output that could not be produced
+
+Files are stored under a directory named after the document and the time the
+bundle was made, e.g. checkout.prd-20261017T093000Z/. By default the archive
+is written next to the document with the same name and a .zip extension.`,
+	Example: `  splan bundle checkout.prd.json
+  splan bundle checkout.prd.json -o handoff/checkout.zip
+  splan bundle platform.trd.json --redact`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundle,
+}
+
+func init() {
+	bundleCmd.Flags().StringVarP(&bundleFlags.output, "output", "o", "", "Output archive path (default: <document>-<timestamp>.zip next to the document)")
+	bundleCmd.Flags().StringVar(&bundleFlags.docType, "type", "", "Document type: prd, mrd, or trd (default: from the filename)")
+	bundleCmd.Flags().BoolVar(&bundleFlags.redact, "redact", false, "Mask the sources and defaults of secret TRD configuration entries")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+	docType := strings.ToLower(bundleFlags.docType)
+	if docType == "" {
+		docType = documentTypeFromPath(inputFile)
+	}
+
+	source, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+	b := bundle.New(inputFile, docType, time.Now())
+	if err := b.Add(bundle.KindSource, filepath.Base(inputFile), source); err != nil {
+		return err
+	}
+
+	// Assets are copied to a staging directory, which rewrites the
+	// document's references to point at assets/ before it is rendered.
+	staging, err := os.MkdirTemp("", "splan-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	base := b.Name()
+	var markdown string
+	switch docType {
+	case "prd":
+		markdown, err = bundlePRD(b, inputFile, base, staging)
+	case "mrd":
+		markdown, err = bundleMRD(b, inputFile, base)
+	case "trd":
+		markdown, err = bundleTRD(b, inputFile, base, staging)
+	case "":
+		return fmt.Errorf("cannot tell the document type of %s: name it *.prd.json, *.mrd.json, or *.trd.json, or use --type", inputFile)
+	default:
+		return fmt.Errorf("cannot bundle a %s document (expected prd, mrd, or trd)", docType)
+	}
+	if err != nil {
+		return err
+	}
+	if err := addBundleAssets(b, staging); err != nil {
+		return err
+	}
+	bundlePDF(b, staging, base, markdown)
+
+	output := bundleFlags.output
+	if output == "" {
+		output = filepath.Join(filepath.Dir(inputFile), b.FileName())
+	}
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	for _, s := range b.Manifest.Skipped {
+		fmt.Fprintf(os.Stderr, "Warning: %s not bundled: %s\n", s.Kind, s.Reason)
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s (%d files)\n", output, len(b.Manifest.Files))
+	return nil
+}
+
+// stageAssets copies the local files referenced by a document into the
+// staging directory and rewrites the references. Missing files are
+// recorded as skipped.
+func stageAssets(b *bundle.Bundle, refs []assets.Ref, inputFile, staging string) error {
+	result, err := assets.Bundle(refs, assets.Options{
+		Mode:      assets.ModeCopy,
+		SourceDir: filepath.Dir(inputFile),
+		OutputDir: staging,
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range result.Missing {
+		b.Skip(bundle.KindAsset, fmt.Sprintf("%s: file not found: %s", m.Field, m.Path))
+	}
+	return nil
+}
+
+// addBundleAssets adds the files staged under assets/ to the bundle.
+func addBundleAssets(b *bundle.Bundle, staging string) error {
+	dir := filepath.Join(staging, assets.DefaultDir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("reading asset: %w", err)
+		}
+		if err := b.Add(bundle.KindAsset, assets.DefaultDir+"/"+e.Name(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bundlePRD(b *bundle.Bundle, inputFile, base, staging string) (string, error) {
+	var doc prd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return "", err
+	}
+	b.Manifest.ID, b.Manifest.Title, b.Manifest.Version = doc.Metadata.ID, doc.Metadata.Title, doc.Metadata.Version
+	if err := stageAssets(b, doc.AssetRefs(), inputFile, staging); err != nil {
+		return "", err
+	}
+
+	markdown := doc.ToMarkdown(prd.DefaultMarkdownOptions())
+	html, err := render.Default.Render("prd", render.FormatHTML, &doc, render.Options{})
+	if err != nil {
+		return "", err
+	}
+	docxData, err := docx.New().RenderPRD(&doc, prdrender.DefaultOptions(), &docx.Options{})
+	if err != nil {
+		return "", err
+	}
+	slides, err := render.Default.Render("prd", render.FormatMarp, &doc, render.Options{})
+	if err != nil {
+		return "", err
+	}
+	report := prd.ScoreToEvaluationReport(&doc, filepath.Base(inputFile))
+	scoreJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling report: %w", err)
+	}
+
+	for _, f := range []struct {
+		kind, name string
+		data       []byte
+	}{
+		{bundle.KindMarkdown, base + ".md", []byte(markdown)},
+		{bundle.KindHTML, base + ".html", html},
+		{bundle.KindDOCX, base + ".docx", docxData},
+		{bundle.KindSlides, base + ".slides.md", slides},
+		{bundle.KindScore, base + ".score.json", append(scoreJSON, '\n')},
+		{bundle.KindScore, base + ".score.md", []byte(formatEvaluationReportMarkdown(report))},
+	} {
+		if err := b.Add(f.kind, f.name, f.data); err != nil {
+			return "", err
+		}
+	}
+	return markdown, nil
+}
+
+func bundleMRD(b *bundle.Bundle, inputFile, base string) (string, error) {
+	var doc mrd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return "", err
+	}
+	b.Manifest.ID, b.Manifest.Title, b.Manifest.Version = doc.Metadata.ID, doc.Metadata.Title, doc.Metadata.Version
+
+	markdown := doc.ToMarkdown(mrd.DefaultMarkdownOptions())
+	html, err := render.Default.Render("mrd", render.FormatHTML, &doc, render.Options{})
+	if err != nil {
+		return "", err
+	}
+	docxData, err := docx.New().RenderMRD(&doc, &docx.Options{})
+	if err != nil {
+		return "", err
+	}
+	for _, f := range []struct {
+		kind, name string
+		data       []byte
+	}{
+		{bundle.KindMarkdown, base + ".md", []byte(markdown)},
+		{bundle.KindHTML, base + ".html", html},
+		{bundle.KindDOCX, base + ".docx", docxData},
+	} {
+		if err := b.Add(f.kind, f.name, f.data); err != nil {
+			return "", err
+		}
+	}
+	return markdown, nil
+}
+
+func bundleTRD(b *bundle.Bundle, inputFile, base, staging string) (string, error) {
+	var doc trd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return "", err
+	}
+	if bundleFlags.redact {
+		doc = doc.Redacted()
+	}
+	b.Manifest.ID, b.Manifest.Title, b.Manifest.Version = doc.Metadata.ID, doc.Metadata.Title, doc.Metadata.Version
+	if err := stageAssets(b, doc.AssetRefs(), inputFile, staging); err != nil {
+		return "", err
+	}
+
+	markdown := doc.ToMarkdown(trd.DefaultMarkdownOptions())
+	html, err := render.Default.Render("trd", render.FormatHTML, &doc, render.Options{})
+	if err != nil {
+		return "", err
+	}
+	docxData, err := docx.New().RenderTRD(&doc, &docx.Options{})
+	if err != nil {
+		return "", err
+	}
+	for _, f := range []struct {
+		kind, name string
+		data       []byte
+	}{
+		{bundle.KindMarkdown, base + ".md", []byte(markdown)},
+		{bundle.KindHTML, base + ".html", html},
+		{bundle.KindDOCX, base + ".docx", docxData},
+	} {
+		if err := b.Add(f.kind, f.name, f.data); err != nil {
+			return "", err
+		}
+	}
+	return markdown, nil
+}
+
+// bundlePDF converts the Markdown to PDF with Pandoc, run in the staging
+// directory so that asset links resolve. The PDF is recorded as skipped if
+// Pandoc is not installed or the conversion fails.
+func bundlePDF(b *bundle.Bundle, staging, base, markdown string) {
+	pandoc, err := exec.LookPath("pandoc")
+	if err != nil {
+		b.Skip(bundle.KindPDF, "pandoc is not installed")
+		return
+	}
+	if err := os.WriteFile(filepath.Join(staging, base+".md"), []byte(markdown), 0600); err != nil {
+		b.Skip(bundle.KindPDF, err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	run := exec.CommandContext(ctx, pandoc, base+".md", "-o", base+".pdf")
+	run.Dir = staging
+	if out, err := run.CombinedOutput(); err != nil {
+		b.Skip(bundle.KindPDF, strings.TrimSpace(fmt.Sprintf("pandoc: %v: %s", err, out)))
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(staging, base+".pdf"))
+	if err != nil {
+		b.Skip(bundle.KindPDF, err.Error())
+		return
+	}
+	if err := b.Add(bundle.KindPDF, base+".pdf", data); err != nil {
+		b.Skip(bundle.KindPDF, err.Error())
+	}
+}
+
+// ============================================================================
+// Query Command
+// ============================================================================
+
+var queryFlags struct {
+	output string
+	fields []string
+}
+
+var queryCmd = &cobra.Command{
+	Use:   "query EXPRESSION FILE...",
+	Short: "Select values from documents with JSONPath",
+	Long: `Select values from any planning document with a JSONPath expression
+(RFC 9535) and print them as JSON, CSV, or a table.
+
+The leading $ may be omitted, so "requirements.functional" and
+"$.requirements.functional" are the same query. Filters select array
+elements or object members by their fields:
+
+  [?@.priority == 'must']                  compare with == != < <= > >=
+  [?@.priority == 'must' && @.phase]       combine with && || ! and ( )
+  [?match(@.id, 'FR-0[0-9]+')]             regular expressions
+  [?length(@.acceptanceCriteria) == 0]     length(), count(), value()
+
+Use .. to search at any depth, e.g. "..risks[*]". Documents are queried as
+written: YAML is converted to JSON and workspace defaults are not applied.
+
+Output formats:
+  json   An array of the selected values (default)
+  csv    One row per value; objects become columns
+  table  Like csv, aligned for the terminal with long cells shortened
+
+With several files, CSV and table output start with a file column.`,
+	Example: `  splan query 'requirements.functional[?@.priority=="must"]' app.prd.json
+  splan query 'requirements.functional[*]' app.prd.json --output table --fields id,title,priority
+  splan query '..keyResults[?@.current]' okrs/*.okr.json --output csv
+  splan query 'metadata.version' docs/*.prd.json --output table`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().StringVar(&queryFlags.output, "output", query.FormatJSON, "Output format: json, csv, table")
+	queryCmd.Flags().StringSliceVar(&queryFlags.fields, "fields", nil, "Columns for csv and table output (dotted paths allowed, e.g. owner.name)")
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	path, err := query.Compile(args[0])
+	if err != nil {
+		return err
+	}
+	format := strings.ToLower(queryFlags.output)
+	switch format {
+	case query.FormatJSON, query.FormatCSV, query.FormatTable:
+	default:
+		return fmt.Errorf("unknown output format %q (valid: json, csv, table)", queryFlags.output)
+	}
+
+	files := args[1:]
+	var results []query.Result
+	for _, file := range files {
+		doc, err := readQueryDocument(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for _, v := range path.Values(doc) {
+			results = append(results, query.Result{File: file, Value: v})
+		}
+	}
+
+	return query.Write(os.Stdout, format, results, query.WriteOptions{
+		Fields:   queryFlags.fields,
+		WithFile: len(files) > 1,
+	})
+}
+
+// readQueryDocument decodes a JSON or YAML document for querying, keeping
+// the order of object members.
+func readQueryDocument(path string) (any, error) {
+	format, err := inputFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading input file: %w", err)
+	}
+	if format == yamlconv.FormatYAML {
+		if data, err = yamlconv.ToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	return query.Decode(data)
+}
+
+// ============================================================================
+// Lifecycle Commands
+// ============================================================================
+
+var lifecycleCmd = &cobra.Command{
+	Use:   "lifecycle",
+	Short: "Move PRDs, MRDs, and TRDs through their status lifecycle",
+	Long: `Commands that change a document's status under the lifecycle policy:
+
+  draft → in_review → approved → deprecated
+
+A document in review can go back to draft, and an approved document returns
+to in_review when it is revised. Deprecated is final. Each status requires
+some fields:
+
+  in_review   at least one reviewer (metadata.reviewers or metadata.reviews)
+  approved    approval from every listed approver, a review date
+              (metadata.reviewedAt), and sign-off from every required review role
+  deprecated  the ID of the successor document (metadata.supersededBy)
+
+Invalid transitions are rejected, and validate reports documents whose status
+is missing required fields. The commands rewrite the file in place.`,
+}
+
+var lifecycleFlags struct {
+	kind         string
+	by           string
+	comment      string
+	supersededBy string
+}
+
+var lifecycleSetCmd = &cobra.Command{
+	Use:   "set FILE STATUS",
+	Short: "Change the document status",
+	Example: `  splan lifecycle set checkout.prd.json in_review
+  splan lifecycle set checkout.prd.json deprecated --superseded-by PRD-023`,
+	Args: cobra.ExactArgs(2),
+	RunE: runLifecycleSet,
+}
+
+var lifecycleApproveCmd = &cobra.Command{
+	Use:   "approve FILE",
+	Short: "Record an approver's approval",
+	Long: `Record an approval from --by in metadata.approvers and set metadata.reviewedAt.
+The document must be in_review (or already approved, to add approvers). Once
+every requirement for approved is met, the status moves to approved.`,
+	Example: `  splan lifecycle approve checkout.prd.json --by "Pat Lee" --comment "Ship it"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runLifecycleApprove,
+}
+
+func init() {
+	lifecycleCmd.PersistentFlags().StringVar(&lifecycleFlags.kind, "kind", "", "Document type: prd, mrd, trd (default: from file name)")
+
+	lifecycleSetCmd.Flags().StringVar(&lifecycleFlags.supersededBy, "superseded-by", "", "ID of the document that replaces this one")
+
+	lifecycleApproveCmd.Flags().StringVar(&lifecycleFlags.by, "by", os.Getenv("USER"), "Approver name")
+	lifecycleApproveCmd.Flags().StringVar(&lifecycleFlags.comment, "comment", "", "Comment to record with the approval")
+
+	lifecycleCmd.AddCommand(lifecycleSetCmd)
+	lifecycleCmd.AddCommand(lifecycleApproveCmd)
+}
+
+func runLifecycleSet(cmd *cobra.Command, args []string) error {
+	rd, err := readReviewedDocument(args[0], lifecycleFlags.kind)
+	if err != nil {
+		return err
+	}
+	to := common.Status(strings.ToLower(args[1]))
+	if lifecycleFlags.supersededBy != "" {
+		if to != common.StatusDeprecated {
+			return fmt.Errorf("--superseded-by only applies when deprecating a document")
+		}
+		*rd.supersededBy = lifecycleFlags.supersededBy
+	}
+	if err := rd.lifecycle().Transition(to); err != nil {
+		return err
+	}
+	from := *rd.status
+	*rd.status = to
+	if err := writeDocumentInPlace(args[0], rd.src, rd.doc); err != nil {
+		return err
+	}
+	fmt.Printf("Status: %s → %s\n", from, to)
+	return nil
+}
+
+func runLifecycleApprove(cmd *cobra.Command, args []string) error {
+	rd, err := readReviewedDocument(args[0], lifecycleFlags.kind)
+	if err != nil {
+		return err
+	}
+	if lifecycleFlags.by == "" {
+		return fmt.Errorf("--by is required when USER is not set")
+	}
+	if *rd.status != common.StatusInReview && *rd.status != common.StatusApproved {
+		return fmt.Errorf("cannot approve a document in %s: request a review first", *rd.status)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	i := slices.IndexFunc(*rd.approvers, func(a common.Approver) bool { return strings.EqualFold(a.Name, lifecycleFlags.by) })
+	if i < 0 {
+		*rd.approvers = append(*rd.approvers, common.Approver{Person: common.Person{Name: lifecycleFlags.by}})
+		i = len(*rd.approvers) - 1
+	}
+	a := &(*rd.approvers)[i]
+	if a.Approved {
+		return fmt.Errorf("%s has already approved", a.Name)
+	}
+	a.Approved = true
+	a.ApprovedAt = &now
+	a.Comments = lifecycleFlags.comment
+	*rd.reviewedAt = &now
+
+	var blocked error
+	if *rd.status == common.StatusInReview {
+		if blocked = rd.lifecycle().Transition(common.StatusApproved); blocked == nil {
+			*rd.status = common.StatusApproved
+		}
+	}
+	if err := writeDocumentInPlace(args[0], rd.src, rd.doc); err != nil {
+		return err
+	}
+
+	fmt.Printf("Approved: %s\n", a.Name)
+	switch {
+	case blocked != nil:
+		fmt.Fprintf(os.Stderr, "Status remains in_review: %v\n", blocked)
+	case *rd.status == common.StatusApproved:
+		fmt.Printf("Status: %s\n", common.StatusApproved)
+	}
+	return nil
+}
+
+// ============================================================================
+// V2MOM Commands
+// ============================================================================
+
+var v2momCmd = &cobra.Command{
+	Use:   "v2mom",
+	Short: "Work with V2MOM documents",
+	Long: `Commands for generating and validating V2MOM (Vision, Values, Methods, Obstacles, Measures) documents.
+
+V2MOM supports:
+  - JSON validation against the V2MOM schema
+  - Marp markdown slide generation
+  - Both traditional flat V2MOM and OKR-aligned nested structures
+  - Multiple terminology modes (V2MOM, OKR, hybrid)`,
+}
+
+var v2momValidateFlags struct {
+	structure string
+}
+
+var v2momValidateCmd = &cobra.Command{
+	Use:   "validate FILE...",
+	Short: "Validate a V2MOM JSON file",
+	Long: `Validate a V2MOM JSON file against the schema and structural rules.
+
+Structure modes:
+  flat    - Traditional V2MOM (measures/obstacles at V2MOM level only)
+  nested  - OKR-aligned (measures under Methods, global obstacles allowed)
+  hybrid  - Both levels allowed (default)
+
+Projects must name a method that exists, and in a workspace the parentId of
+the metadata must name a V2MOM or other document of the workspace.
+
+Examples:
+  splan goals v2mom validate my-v2mom.json
+  splan goals v2mom validate my-v2mom.json --structure=nested
+  splan goals v2mom validate "plans/*.v2mom.json"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runV2MOMValidate,
+}
+
+var v2momGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate output from V2MOM",
+	Long:  `Generate various output formats from a V2MOM JSON file.`,
+}
+
+var v2momGenerateMarpFlags struct {
+	output      string
+	theme       string
+	terminology string
+}
+
+var v2momGenerateMarpCmd = &cobra.Command{
+	Use:   "marp FILE",
+	Short: "Generate Marp markdown slides",
+	Long: `Generate Marp markdown presentation slides from a V2MOM JSON file.
+
+Themes:
+  default   - Clean gradient theme (default)
+  corporate - Professional blue theme
+  minimal   - Simple grayscale theme
+
+Terminology:
+  v2mom  - Use V2MOM terms: Methods, Measures, Obstacles (default)
+  okr    - Use OKR terms: Objectives, Key Results, Risks
+  hybrid - Show both: Methods (Objectives), Measures (Key Results)
+
+Examples:
+  splan goals v2mom generate marp my-v2mom.json
+  splan goals v2mom generate marp my-v2mom.json -o slides.md
+  splan goals v2mom generate marp my-v2mom.json --theme=corporate --terminology=okr`,
+	Args: cobra.ExactArgs(1),
+	RunE: runV2MOMMarpGenerate,
+}
+
+var v2momGenerateTreeFlags struct {
+	output      string
+	format      string
+	obstacles   bool
+	terminology string
+}
+
+var v2momGenerateTreeCmd = &cobra.Command{
+	Use:   "tree FILE",
+	Short: "Generate a Vision→Methods→Measures tree diagram",
+	Long: `Generate the Vision→Methods→Measures hierarchy of a V2MOM as a Mermaid
+mindmap or a Graphviz DOT graph, for use in wikis and decks.
+
+Nodes are colored by status: green for on track and achieved, amber for at
+risk, red for behind and missed, blue for in progress, and gray for not
+started. Mindmaps show the color as a marker before the label; DOT graphs
+fill the node. Measures and obstacles at the V2MOM level are grouped under
+the vision.
+
+Formats:
+  mermaid - Mermaid mindmap (default)
+  dot     - Graphviz DOT; render with: dot -Tsvg tree.dot -o tree.svg
+
+Examples:
+  splan goals v2mom generate tree my-v2mom.json
+  splan goals v2mom generate tree my-v2mom.json --obstacles -o tree.mmd
+  splan goals v2mom generate tree my-v2mom.json --format dot -o tree.dot`,
+	Args: cobra.ExactArgs(1),
+	RunE: runV2MOMTreeGenerate,
+}
+
+var v2momInitFlags struct {
+	name        string
+	output      string
+	structure   string
+	interactive bool
+}
+
+var v2momInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize a new V2MOM template",
+	Long: `Create a new V2MOM JSON template file with example content.
+
+Structure modes:
+  flat   - Traditional V2MOM with measures/obstacles at top level
+  nested - OKR-aligned with measures under methods (default)
+  hybrid - Both levels with examples
+
+With --interactive, a wizard asks for the owner, vision, values, methods and
+their measures, and obstacles instead of writing example content.
+
+Examples:
+  splan goals v2mom init
+  splan goals v2mom init --name "FY2026 Product Strategy"
+  splan goals v2mom init --name "Engineering Goals" -o engineering-v2mom.json --structure=nested
+  splan goals v2mom init --interactive`,
+	RunE: runV2MOMInit,
+}
+
+var v2momScoreFlags struct {
+	format string
+}
+
+var v2momScoreCmd = &cobra.Command{
+	Use:   "score <file.json>...",
+	Short: "Score V2MOM quality",
+	Long: `Score a V2MOM for quality across four weighted categories:
+
+  measurable_key_results (30%) - measures have numeric targets and baselines
+  value_alignment        (25%) - methods link to the values they advance
+  obstacle_mitigation    (20%) - obstacles are rated and mitigated
+  smart_measures         (25%) - measures are specific, measurable,
+                                 achievable, relevant, and time-bound
+
+Methods name the values they advance in methods[].values. The weighted
+score and decision use the same thresholds as PRD scoring; the command exits
+non-zero when the V2MOM does not pass.
+
+Examples:
+  splan goals v2mom score my-v2mom.json
+  splan goals v2mom score my-v2mom.json --format json
+  splan goals v2mom score "plans/*.v2mom.json"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runV2MOMScore,
+}
+
+func init() {
+	// V2MOM validate flags
+	v2momValidateCmd.Flags().StringVar(&v2momValidateFlags.structure, "structure", "", "Structure mode to validate against (flat, nested, hybrid)")
+
+	// V2MOM generate marp flags
+	v2momGenerateMarpCmd.Flags().StringVarP(&v2momGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	v2momGenerateMarpCmd.Flags().StringVar(&v2momGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
+	v2momGenerateMarpCmd.Flags().StringVar(&v2momGenerateMarpFlags.terminology, "terminology", "", "Display terminology (v2mom, okr, hybrid)")
+
+	// V2MOM generate tree flags
+	v2momGenerateTreeCmd.Flags().StringVarP(&v2momGenerateTreeFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	v2momGenerateTreeCmd.Flags().StringVarP(&v2momGenerateTreeFlags.format, "format", "f", goals.TreeFormatMermaid, "Diagram format (mermaid, dot)")
+	v2momGenerateTreeCmd.Flags().BoolVar(&v2momGenerateTreeFlags.obstacles, "obstacles", false, "Include obstacles")
+	v2momGenerateTreeCmd.Flags().StringVar(&v2momGenerateTreeFlags.terminology, "terminology", "", "Display terminology for group labels (v2mom, okr, hybrid)")
+
+	// V2MOM init flags
+	v2momInitCmd.Flags().StringVar(&v2momInitFlags.name, "name", "My V2MOM", "Name for the V2MOM")
+	v2momInitCmd.Flags().StringVarP(&v2momInitFlags.output, "output", "o", "v2mom.json", "Output file path")
+	v2momInitCmd.Flags().StringVar(&v2momInitFlags.structure, "structure", "nested", "Structure mode (flat, nested, hybrid)")
+	v2momInitCmd.Flags().BoolVarP(&v2momInitFlags.interactive, "interactive", "i", false, "Ask for the V2MOM content instead of writing examples")
+
+	// V2MOM score flags
+	v2momScoreCmd.Flags().StringVarP(&v2momScoreFlags.format, "format", "f", "terminal", "Output format (terminal, json, markdown)")
+
+	// Add subcommands
+	v2momGenerateCmd.AddCommand(v2momGenerateMarpCmd)
+	v2momGenerateCmd.AddCommand(v2momGenerateTreeCmd)
+	v2momCmd.AddCommand(v2momValidateCmd)
+	v2momCmd.AddCommand(v2momGenerateCmd)
+	v2momCmd.AddCommand(v2momInitCmd)
+	v2momCmd.AddCommand(v2momScoreCmd)
+}
+
+func runV2MOMValidate(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("v2mom"), validateV2MOMFile)
+}
+
+func runV2MOMScore(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("v2mom"), scoreV2MOMFile)
+}
+
+func scoreV2MOMFile(file string, stdout, stderr io.Writer) error {
+	v, err := readV2MOMFile(file, stderr)
+	if err != nil {
+		return fmt.Errorf("reading V2MOM: %w", err)
+	}
+
+	report := v2mom.ScoreToEvaluationReport(v, file)
+
+	switch strings.ToLower(v2momScoreFlags.format) {
+	case "json":
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		fmt.Fprintln(stdout, string(output))
+
+	case "markdown":
+		fmt.Fprint(stdout, formatEvaluationReportMarkdown(report))
+
+	case "terminal", "":
+		output, err := render.Default.Render("evaluation", render.FormatTerminal, report, render.Options{})
+		if err != nil {
+			return fmt.Errorf("rendering report: %w", err)
+		}
+		fmt.Fprint(stdout, string(output))
+
+	default:
+		return fmt.Errorf("unknown format: %s (expected terminal, json, or markdown)", v2momScoreFlags.format)
+	}
+
+	recordScore(report.WeightedScore, report.Decision.Passed)
+
+	if !report.Decision.Passed {
+		return fmt.Errorf("V2MOM evaluation: %s", report.Decision.Rationale)
+	}
+	return nil
+}
+
+func validateV2MOMFile(file string, stdout, stderr io.Writer) error {
+	// Check file exists
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return fmt.Errorf("file not found: %s", file)
+	}
+
+	// Read and parse V2MOM
+	v, err := readV2MOMFile(file, stderr)
+	if err != nil {
+		return fmt.Errorf("reading V2MOM: %w", err)
+	}
+
+	// Set up validation options
+	opts := v2mom.DefaultValidationOptions()
+	if v2momValidateFlags.structure != "" {
+		opts.Structure = v2momValidateFlags.structure
+	}
+
+	// Validate
+	errs := v.Validate(opts)
+	issues := make([]validation.Issue, 0, len(errs))
+	for _, e := range errs {
+		issues = append(issues, pathIssue("v2mom", e.Path, e.Message, e.Severity == "error"))
+	}
+	refIs, err := referenceIssues(file, "v2mom", v, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIs...)
+	recordIssues(file, "v2mom", issues)
+
+	// Report results
+	errors := v2mom.Errors(errs)
+	warnings := v2mom.Warnings(errs)
+
+	if len(warnings) > 0 {
+		fmt.Fprintln(stdout, "Warnings:")
+		for _, w := range warnings {
+			fmt.Fprintf(stdout, "  - %s\n", w)
+		}
+		fmt.Fprintln(stdout)
+	}
+
+	if len(errors)+len(refIs) > 0 {
+		fmt.Fprintln(stdout, "Errors:")
+		for _, e := range errors {
+			fmt.Fprintf(stdout, "  - %s\n", e)
+		}
+		for _, is := range refIs {
+			fmt.Fprintf(stdout, "  - %s\n", is.Message)
+		}
+		return fmt.Errorf("validation failed with %d error(s)", len(errors)+len(refIs))
+	}
+
+	// Print success info
+	fmt.Fprintf(stdout, "Valid V2MOM: %s\n", file)
+	fmt.Fprintf(stdout, "  Structure: %s\n", v.GetStructure())
+	fmt.Fprintf(stdout, "  Methods: %d\n", len(v.Methods))
+	fmt.Fprintf(stdout, "  Total Measures: %d\n", len(v.AllMeasures()))
+	fmt.Fprintf(stdout, "  Total Obstacles: %d\n", len(v.AllObstacles()))
+
+	if v.Metadata != nil && v.Metadata.Name != "" {
+		fmt.Fprintf(stdout, "  Name: %s\n", v.Metadata.Name)
+	}
+
+	return nil
+}
+
+func runV2MOMMarpGenerate(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+
+	// Check file exists
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		return fmt.Errorf("file not found: %s", inputPath)
+	}
+
+	// Read and parse V2MOM
+	v, err := readV2MOMFile(inputPath, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading V2MOM: %w", err)
+	}
+
+	// Create renderer and options
+	renderer := v2mommarp.New()
+	opts := v2momrender.DefaultOptions()
+	opts.Theme = v2momGenerateMarpFlags.theme
+	if v2momGenerateMarpFlags.terminology != "" {
+		opts.Terminology = v2momGenerateMarpFlags.terminology
+	}
+
+	// Render
+	output, err := renderer.Render(v, opts)
+	if err != nil {
+		return fmt.Errorf("rendering Marp: %w", err)
+	}
+
+	// Write output
+	if v2momGenerateMarpFlags.output != "" {
+		// Ensure output directory exists
+		dir := filepath.Dir(v2momGenerateMarpFlags.output)
+		if dir != "." && dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("creating output directory: %w", err)
+			}
+		}
+
+		if err := os.WriteFile(v2momGenerateMarpFlags.output, output, 0600); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+		runReport.Outputs = append(runReport.Outputs, v2momGenerateMarpFlags.output)
+		fmt.Printf("Generated: %s\n", v2momGenerateMarpFlags.output)
+	} else {
+		// Write to stdout
+		fmt.Print(string(output))
+	}
+
+	return nil
+}
+
+func runV2MOMTreeGenerate(cmd *cobra.Command, args []string) error {
+	v, err := readV2MOMFile(args[0], os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading V2MOM: %w", err)
+	}
+	tree := goals.V2MOMTree(v, goals.TreeOptions{
+		Obstacles:   v2momGenerateTreeFlags.obstacles,
+		Terminology: v2momGenerateTreeFlags.terminology,
+	})
+	return writeGoalsTree(tree, v2momGenerateTreeFlags.format, v2momGenerateTreeFlags.output)
+}
+
+// writeGoalsTree renders a goals tree in format to the output file, or to
+// stdout if output is empty.
+func writeGoalsTree(tree *goals.TreeNode, format, output string) error {
+	out, err := tree.Render(format)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if dir := filepath.Dir(output); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(output, []byte(out), 0600); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+func runV2MOMInit(cmd *cobra.Command, args []string) error {
+	// Check if file already exists
+	if _, err := os.Stat(v2momInitFlags.output); err == nil {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", v2momInitFlags.output)
+	}
+
+	// Create template based on structure
+	var template *v2mom.V2MOM
+
+	switch {
+	case v2momInitFlags.interactive:
+		fmt.Println("Creating a V2MOM. Press Enter to accept the default in brackets or to skip a question.")
+		template = wizard.V2MOM(wizard.NewPrompter(os.Stdin, os.Stdout), v2momInitFlags.name, v2momInitFlags.structure)
+		fmt.Println()
+	case v2momInitFlags.structure == "flat":
+		template = createFlatV2MOMTemplate(v2momInitFlags.name)
+	case v2momInitFlags.structure == "hybrid":
+		template = createHybridV2MOMTemplate(v2momInitFlags.name)
+	default: // "nested"
+		template = createNestedV2MOMTemplate(v2momInitFlags.name)
+	}
+
+	// Write to file
+	if err := template.WriteFile(v2momInitFlags.output); err != nil {
+		return fmt.Errorf("writing template: %w", err)
+	}
+
+	fmt.Printf("Created: %s\n", v2momInitFlags.output)
+	fmt.Printf("  Structure: %s\n", v2momInitFlags.structure)
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Edit the file to add your vision, values, methods, and measures")
+	fmt.Println("  2. Run 'splan goals v2mom validate " + v2momInitFlags.output + "' to check your V2MOM")
+	fmt.Println("  3. Run 'splan goals v2mom generate marp " + v2momInitFlags.output + " -o slides.md' to create slides")
+
+	return nil
+}
+
+func createNestedV2MOMTemplate(name string) *v2mom.V2MOM {
+	now := time.Now()
+	return &v2mom.V2MOM{
+		Schema: "../schema/v2mom.schema.json",
+		Metadata: &v2mom.Metadata{
+			Name:        name,
+			Author:      "Your Name",
+			Team:        "Your Team",
+			FiscalYear:  fmt.Sprintf("FY%d", now.Year()),
+			Quarter:     "Q1",
+			Version:     "1.0.0",
+			Status:      v2mom.StatusDraft,
+			Structure:   v2mom.StructureNested,
+			Terminology: v2mom.TerminologyV2MOM,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		Vision: "Describe your vision here - what do you want to achieve?",
+		Values: []v2mom.Value{
+			{
+				Name:        "Value 1",
+				Description: "What's most important to you?",
+				Priority:    1,
+			},
+			{
+				Name:        "Value 2",
+				Description: "What's the second most important principle?",
+				Priority:    2,
+			},
+		},
+		Methods: []v2mom.Method{
+			{
+				ID:          "method-1",
+				Name:        "First Method/Objective",
+				Description: "How will you achieve your vision? What's the first major initiative?",
+				Priority:    v2mom.PriorityP0,
+				Status:      "Not Started",
+				Measures: []v2mom.Measure{
+					{
+						ID:       "m1-kr1",
+						Name:     "Key Result 1",
+						Target:   "Define your target",
+						Status:   "Not Started",
+						Progress: 0,
+					},
+					{
+						ID:       "m1-kr2",
+						Name:     "Key Result 2",
+						Target:   "Define your target",
+						Status:   "Not Started",
+						Progress: 0,
+					},
+				},
+				Obstacles: []v2mom.Obstacle{
+					{
+						Name:       "Method-specific obstacle",
+						Severity:   "Medium",
+						Mitigation: "How will you address this?",
+					},
+				},
+			},
+			{
+				ID:          "method-2",
+				Name:        "Second Method/Objective",
+				Description: "What's the second major initiative?",
+				Priority:    v2mom.PriorityP1,
+				Status:      "Not Started",
+				Measures: []v2mom.Measure{
+					{
+						ID:       "m2-kr1",
+						Name:     "Key Result 1",
+						Target:   "Define your target",
+						Status:   "Not Started",
+						Progress: 0,
+					},
+				},
+			},
+		},
+		Obstacles: []v2mom.Obstacle{
+			{
+				ID:          "obs-global-1",
+				Name:        "Global obstacle",
+				Description: "What's preventing success across multiple methods?",
+				Severity:    "High",
+				Likelihood:  "Medium",
+				Mitigation:  "How will you mitigate this risk?",
+				Status:      "Identified",
+			},
+		},
+	}
+}
+
+func createFlatV2MOMTemplate(name string) *v2mom.V2MOM {
+	now := time.Now()
+	return &v2mom.V2MOM{
+		Schema: "../schema/v2mom.schema.json",
+		Metadata: &v2mom.Metadata{
+			Name:        name,
+			Author:      "Your Name",
+			Team:        "Your Team",
+			FiscalYear:  fmt.Sprintf("FY%d", now.Year()),
+			Quarter:     "Q1",
+			Version:     "1.0.0",
+			Status:      v2mom.StatusDraft,
+			Structure:   v2mom.StructureFlat,
+			Terminology: v2mom.TerminologyV2MOM,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		Vision: "Describe your vision here - what do you want to achieve?",
+		Values: []v2mom.Value{
+			{
+				Name:        "Value 1",
+				Description: "What's most important to you?",
+				Priority:    1,
+			},
+			{
+				Name:        "Value 2",
+				Description: "What's the second most important principle?",
+				Priority:    2,
+			},
+		},
+		Methods: []v2mom.Method{
+			{
+				Name:        "First Method",
+				Description: "How will you achieve your vision?",
+				Priority:    v2mom.PriorityP0,
+			},
+			{
+				Name:        "Second Method",
+				Description: "What's the second major action?",
+				Priority:    v2mom.PriorityP1,
+			},
+		},
+		Obstacles: []v2mom.Obstacle{
+			{
+				Name:        "Obstacle 1",
+				Description: "What's preventing success?",
+				Severity:    "High",
+				Mitigation:  "How will you address this?",
+			},
+		},
+		Measures: []v2mom.Measure{
+			{
+				Name:   "Measure 1",
+				Target: "Define your target",
+				Status: "Not Started",
+			},
+			{
+				Name:   "Measure 2",
+				Target: "Define your target",
+				Status: "Not Started",
+			},
+		},
+	}
+}
+
+func createHybridV2MOMTemplate(name string) *v2mom.V2MOM {
+	now := time.Now()
+	return &v2mom.V2MOM{
+		Schema: "../schema/v2mom.schema.json",
+		Metadata: &v2mom.Metadata{
+			Name:        name,
+			Author:      "Your Name",
+			Team:        "Your Team",
+			FiscalYear:  fmt.Sprintf("FY%d", now.Year()),
+			Quarter:     "Q1",
+			Version:     "1.0.0",
+			Status:      v2mom.StatusDraft,
+			Structure:   v2mom.StructureHybrid,
+			Terminology: v2mom.TerminologyV2MOM,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		Vision: "Describe your vision here - what do you want to achieve?",
+		Values: []v2mom.Value{
+			{
+				Name:        "Value 1",
+				Description: "What's most important to you?",
+				Priority:    1,
+			},
+		},
+		Methods: []v2mom.Method{
+			{
+				ID:          "method-1",
+				Name:        "Method with nested measures",
+				Description: "This method has its own key results",
+				Priority:    v2mom.PriorityP0,
+				Status:      "Not Started",
+				Measures: []v2mom.Measure{
+					{
+						Name:   "Method-specific KR",
+						Target: "Define target",
+					},
+				},
+			},
+			{
+				ID:          "method-2",
+				Name:        "Method without nested measures",
+				Description: "This method uses global measures",
+				Priority:    v2mom.PriorityP1,
+			},
+		},
+		Obstacles: []v2mom.Obstacle{
+			{
+				Name:        "Global obstacle",
+				Description: "Affects multiple methods",
+				Severity:    "High",
+				Mitigation:  "Mitigation strategy",
+			},
+		},
+		Measures: []v2mom.Measure{
+			{
+				Name:        "North star metric",
+				Description: "Global measure spanning all methods",
+				Target:      "Define target",
+			},
+		},
+	}
+}
+
+// ============================================================================
+// OKR Commands
+// ============================================================================
+
+var okrCmd = &cobra.Command{
+	Use:   "okr",
+	Short: "Work with OKR documents",
+	Long: `Commands for generating and validating OKR (Objectives and Key Results) documents.
+
+OKR is a goal-setting framework that helps teams define objectives and track
+measurable key results. Originally developed at Intel and popularized by Google.`,
+}
+
+var okrValidateFlags struct {
+	strict bool
+}
+
+var okrValidateCmd = &cobra.Command{
+	Use:   "validate FILE...",
+	Short: "Validate an OKR JSON file",
+	Long: `Validate an OKR JSON file against the schema and structural rules.
+
+In a workspace (a splan.workspace.json in the file's directory or a parent),
+the alignedWith, parentId, parentOkrId, and companyOkrIds of objectives and
+the phaseId of phase targets must name an ID defined in the file or another
+document of the workspace.
+
+Examples:
+  splan goals okr validate my-okrs.json
+  splan goals okr validate my-okrs.json --strict
+  splan goals okr validate goals/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runOKRValidate,
+}
+
+var okrGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate output from OKR",
+	Long:  `Generate various output formats from an OKR JSON file.`,
+}
+
+var okrGenerateMarpFlags struct {
+	output string
+	theme  string
+}
+
+var okrGenerateMarpCmd = &cobra.Command{
+	Use:   "marp FILE",
+	Short: "Generate Marp markdown slides",
+	Long: `Generate Marp markdown presentation slides from an OKR JSON file.
+
+Themes:
+  default   - Clean gradient theme (default)
+  corporate - Professional blue theme
+  minimal   - Simple grayscale theme
+
+Examples:
+  splan goals okr generate marp my-okrs.json
+  splan goals okr generate marp my-okrs.json -o slides.md
+  splan goals okr generate marp my-okrs.json --theme=corporate`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOKRMarpGenerate,
+}
+
+var okrGenerateTreeFlags struct {
+	output string
+	format string
+	risks  bool
+}
+
+var okrGenerateTreeCmd = &cobra.Command{
+	Use:   "tree FILE",
+	Short: "Generate an Objectives→Key Results tree diagram",
+	Long: `Generate the Objectives→Key Results hierarchy of an OKR document as a
+Mermaid mindmap or a Graphviz DOT graph, for use in wikis and decks.
+
+Nodes are colored by status as in "splan goals v2mom generate tree".
+
+Formats:
+  mermaid - Mermaid mindmap (default)
+  dot     - Graphviz DOT
+
+Examples:
+  splan goals okr generate tree my-okrs.json
+  splan goals okr generate tree my-okrs.json --risks --format dot -o tree.dot`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOKRTreeGenerate,
+}
+
+var okrProgressFlags struct {
+	output string
+}
+
+var okrProgressCmd = &cobra.Command{
+	Use:   "progress FILE",
+	Short: "Report key result progress with progress bars",
+	Long: `Report the progress of each objective and key result as markdown.
+
+Progress is computed from each key result's baseline, target, and current
+values. Values may carry units: percentages ("42%"), currency ("$1.2M"),
+durations ("250ms", "1.5s"), and counts ("10k users"); the key result's
+"unit" applies to values without one. A target below the baseline, or
+"direction": "decrease", marks a metric where lower is better.
+
+Key results whose current value is worse than their baseline are flagged as
+regressed. Key results without measurable values use their score.
+
+Each key result is graded as missed, commit, or stretch: whether its current
+value has reached the commit "target" and, if set, the "stretchTarget".
+
+Examples:
+  splan goals okr progress my-okrs.json
+  splan goals okr progress my-okrs.json -o progress.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOKRProgress,
+}
+
+var okrInitFlags struct {
+	name   string
+	output string
+	period string
+}
+
+var okrInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize a new OKR template",
+	Long: `Create a new OKR JSON template file with example content.
+
+Examples:
+  splan goals okr init
+  splan goals okr init --name "Q1 2026 Engineering OKRs"
+  splan goals okr init --name "Product Team" -o product-okrs.json --period 2026-Q1`,
+	RunE: runOKRInit,
+}
+
+func init() {
+	// OKR validate flags
+	okrValidateCmd.Flags().BoolVar(&okrValidateFlags.strict, "strict", false, "Enable strict validation mode")
+
+	// OKR generate marp flags
+	okrGenerateMarpCmd.Flags().StringVarP(&okrGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	okrGenerateMarpCmd.Flags().StringVar(&okrGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
+
+	// OKR generate tree flags
+	okrGenerateTreeCmd.Flags().StringVarP(&okrGenerateTreeFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	okrGenerateTreeCmd.Flags().StringVarP(&okrGenerateTreeFlags.format, "format", "f", goals.TreeFormatMermaid, "Diagram format (mermaid, dot)")
+	okrGenerateTreeCmd.Flags().BoolVar(&okrGenerateTreeFlags.risks, "risks", false, "Include risks")
+
+	// OKR progress flags
+	okrProgressCmd.Flags().StringVarP(&okrProgressFlags.output, "output", "o", "", "Output file path (default: stdout)")
+
+	// OKR init flags
+	okrInitCmd.Flags().StringVar(&okrInitFlags.name, "name", "My OKRs", "Name for the OKR document")
+	okrInitCmd.Flags().StringVarP(&okrInitFlags.output, "output", "o", "okrs.json", "Output file path")
+	okrInitCmd.Flags().StringVar(&okrInitFlags.period, "period", "", "Period (e.g., 2026-Q1)")
+
+	// Add subcommands
+	okrGenerateCmd.AddCommand(okrGenerateMarpCmd)
+	okrGenerateCmd.AddCommand(okrGenerateTreeCmd)
+	okrCmd.AddCommand(okrValidateCmd)
+	okrCmd.AddCommand(okrGenerateCmd)
+	okrCmd.AddCommand(okrInitCmd)
+	okrCmd.AddCommand(okrProgressCmd)
+}
+
+func runOKRValidate(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("okr"), validateOKRFile)
+}
+
+func validateOKRFile(file string, stdout, stderr io.Writer) error {
+	// Check file exists
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return fmt.Errorf("file not found: %s", file)
+	}
+
+	// Read and parse OKR
+	doc, err := readOKRFile(file, stderr)
+	if err != nil {
+		return fmt.Errorf("reading OKR: %w", err)
+	}
+
+	// Set up validation options
+	opts := okr.DefaultValidationOptions()
+	if okrValidateFlags.strict {
+		opts = okr.StrictValidationOptions()
+	}
+
+	// Validate
+	errs := doc.Validate(opts)
+	issues := make([]validation.Issue, 0, len(errs))
+	for _, e := range errs {
+		issues = append(issues, pathIssue("okr", e.Path, e.Message, e.IsError))
+	}
+	refIs, err := referenceIssues(file, "okr", doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIs...)
+	recordIssues(file, "okr", issues)
+
+	// Report results
+	errors := okr.Errors(errs)
+	warnings := okr.Warnings(errs)
+
+	if len(warnings) > 0 {
+		fmt.Fprintln(stdout, "Warnings:")
+		for _, w := range warnings {
+			fmt.Fprintf(stdout, "  - %s\n", w)
+		}
+		fmt.Fprintln(stdout)
+	}
+
+	if len(errors)+len(refIs) > 0 {
+		fmt.Fprintln(stdout, "Errors:")
+		for _, e := range errors {
+			fmt.Fprintf(stdout, "  - %s\n", e)
+		}
+		for _, is := range refIs {
+			fmt.Fprintf(stdout, "  - %s\n", is.Message)
+		}
+		return fmt.Errorf("validation failed with %d error(s)", len(errors)+len(refIs))
+	}
+
+	// Print success info
+	fmt.Fprintf(stdout, "Valid OKR: %s\n", file)
+	fmt.Fprintf(stdout, "  Objectives: %d\n", len(doc.Objectives))
+	fmt.Fprintf(stdout, "  Total Key Results: %d\n", len(doc.AllKeyResults()))
+	fmt.Fprintf(stdout, "  Overall Progress: %.0f%%\n", doc.CalculateOverallProgress()*100)
+
+	if doc.Metadata != nil && doc.Metadata.Name != "" {
+		fmt.Fprintf(stdout, "  Name: %s\n", doc.Metadata.Name)
+	}
+
+	return nil
+}
+
+func runOKRProgress(cmd *cobra.Command, args []string) error {
+	doc, err := readOKRFile(args[0], os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading OKR: %w", err)
+	}
+
+	output := doc.ProgressMarkdown()
+	if okrProgressFlags.output == "" {
+		fmt.Print(output)
+		return nil
+	}
+	if err := os.WriteFile(okrProgressFlags.output, []byte(output), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s\n", okrProgressFlags.output)
+	return nil
+}
+
+func runOKRMarpGenerate(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+
+	// Check file exists
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		return fmt.Errorf("file not found: %s", inputPath)
+	}
+
+	// Read and parse OKR
+	doc, err := readOKRFile(inputPath, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading OKR: %w", err)
+	}
+
+	// Render
+	output, err := render.Default.Render("okr", render.FormatMarp, doc, render.Options{Theme: okrGenerateMarpFlags.theme})
+	if err != nil {
+		return fmt.Errorf("rendering Marp: %w", err)
+	}
+
+	// Write output
+	if okrGenerateMarpFlags.output != "" {
+		// Ensure output directory exists
+		dir := filepath.Dir(okrGenerateMarpFlags.output)
+		if dir != "." && dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("creating output directory: %w", err)
+			}
+		}
+
+		if err := os.WriteFile(okrGenerateMarpFlags.output, output, 0600); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+		runReport.Outputs = append(runReport.Outputs, okrGenerateMarpFlags.output)
+		fmt.Printf("Generated: %s\n", okrGenerateMarpFlags.output)
+	} else {
+		// Write to stdout
+		fmt.Print(string(output))
+	}
+
+	return nil
+}
+
+func runOKRTreeGenerate(cmd *cobra.Command, args []string) error {
+	doc, err := readOKRFile(args[0], os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading OKR: %w", err)
+	}
+	tree := goals.OKRTree(doc, goals.TreeOptions{Obstacles: okrGenerateTreeFlags.risks})
+	return writeGoalsTree(tree, okrGenerateTreeFlags.format, okrGenerateTreeFlags.output)
+}
+
+func runOKRInit(cmd *cobra.Command, args []string) error {
+	// Check if file already exists
+	if _, err := os.Stat(okrInitFlags.output); err == nil {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", okrInitFlags.output)
+	}
+
+	// Create template
+	now := time.Now()
+	period := okrInitFlags.period
+	if period == "" {
+		quarter := (now.Month()-1)/3 + 1
+		period = fmt.Sprintf("%d-Q%d", now.Year(), quarter)
+	}
+
+	template := &okr.OKRDocument{
+		Schema: "../schema/okr.schema.json",
+		Metadata: &okr.Metadata{
+			ID:         okr.GenerateID(),
+			Name:       okrInitFlags.name,
+			Owner:      "Your Name",
+			Team:       "Your Team",
+			Period:     period,
+			PeriodType: "quarter",
+			Version:    "1.0.0",
+			Status:     okr.StatusDraft,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+		Theme: "Achieve great results this quarter",
+		Objectives: []okr.Objective{
+			{
+				ID:          "obj-1",
+				Title:       "First Objective",
+				Description: "What do you want to achieve? Make it inspirational.",
+				Category:    "Product",
+				Owner:       "Your Name",
+				Status:      okr.StatusDraft,
+				KeyResults: []okr.KeyResult{
+					{
+						ID:          "kr-1-1",
+						Description: "First measurable key result",
+						Baseline:    "0",
+						Target:      "100",
+						Current:     "0",
+						Unit:        "%",
+						Confidence:  okr.ConfidenceMedium,
+					},
+					{
+						ID:          "kr-1-2",
+						Description: "Second measurable key result",
+						Baseline:    "0",
+						Target:      "50",
+						Current:     "0",
+						Unit:        "count",
+						Confidence:  okr.ConfidenceMedium,
+					},
+				},
+			},
+			{
+				ID:          "obj-2",
+				Title:       "Second Objective",
+				Description: "Another goal to achieve this quarter.",
+				Category:    "Engineering",
+				Owner:       "Your Name",
+				Status:      okr.StatusDraft,
+				KeyResults: []okr.KeyResult{
+					{
+						ID:          "kr-2-1",
+						Description: "Key result for objective 2",
+						Baseline:    "0",
+						Target:      "10",
+						Current:     "0",
+						Unit:        "features",
+						Confidence:  okr.ConfidenceHigh,
+					},
+				},
+			},
+		},
+		Risks: []okr.Risk{
+			{
+				ID:          "risk-1",
+				Description: "What might prevent success?",
+				Impact:      "High",
+				Likelihood:  "Medium",
+				Mitigation:  "How will you address this?",
+			},
+		},
+	}
 
 	// Write to file
-	if err := template.WriteFile(v2momInitFlags.output); err != nil {
+	if err := template.WriteFile(okrInitFlags.output); err != nil {
 		return fmt.Errorf("writing template: %w", err)
 	}
 
-	fmt.Printf("Created: %s\n", v2momInitFlags.output)
-	fmt.Printf("  Structure: %s\n", v2momInitFlags.structure)
-	fmt.Println("\nNext steps:")
-	fmt.Println("  1. Edit the file to add your vision, values, methods, and measures")
-	fmt.Println("  2. Run 'splan goals v2mom validate " + v2momInitFlags.output + "' to check your V2MOM")
-	fmt.Println("  3. Run 'splan goals v2mom generate marp " + v2momInitFlags.output + " -o slides.md' to create slides")
+	fmt.Printf("Created: %s\n", okrInitFlags.output)
+	fmt.Printf("  Period: %s\n", period)
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Edit the file to add your objectives and key results")
+	fmt.Println("  2. Run 'splan goals okr validate " + okrInitFlags.output + "' to check your OKRs")
+	fmt.Println("  3. Run 'splan goals okr generate marp " + okrInitFlags.output + " -o slides.md' to create slides")
+
+	return nil
+}
+
+// ============================================================================
+// Shared Types
+// ============================================================================
+
+// Shared generate flags
+type generateFlags struct {
+	output           string
+	margin           string
+	mainFont         string
+	sansFont         string
+	monoFont         string
+	fontFamily       string
+	noFrontmatter    bool
+	noTOC            bool
+	noSwimlane       bool
+	descLen          int
+	swimlaneNoStatus bool
+	mermaid          bool
+	assets           string
+	assetsDir        string
+	convertDiagrams  string
+	diagramEndpoint  string
+	history          string
+	redact           bool
+	icons            string
+	noGlossaryLinks  bool
+}
+
+// ============================================================================
+// PRD Commands
+// ============================================================================
+
+var prdCmd = &cobra.Command{
+	Use:   "prd",
+	Short: "Work with Product Requirements Documents",
+	Long:  `Commands for generating and validating Product Requirements Documents (PRD).`,
+}
+
+var prdGenerateFlags generateFlags
+
+var prdGenerateCmd = &cobra.Command{
+	Use:   "generate <input.json>",
+	Short: "Convert PRD JSON to markdown",
+	Long: `Generate markdown from a Product Requirements Document (PRD).
+
+The output includes YAML frontmatter compatible with Pandoc for PDF generation.
+By default, the output file has the same name as the input with a .md extension.
+
+When a splan.workspace.json manifest is found in the PRD's directory or a
+parent, the IDs of the TRD components and MRD market requirements it lists
+are linked to the generated TRD and MRD.
+
+--profile generates the variant for an audience: exec, engineering,
+sales, external, or one defined under "renderProfiles" in the workspace
+manifest. A profile selects sections, leaves out items tagged above its
+confidentiality level (public, internal, confidential, restricted), and sets
+the description length, icon set, table of contents, frontmatter, swimlane,
+diagrams, and output format, so a profile whose format is html or docx
+writes that format instead. Flags given explicitly override the profile.
+It does not change the document profile of metadata.conditions, which
+--doc-profile sets.`,
+	Example: `  splan requirements prd generate myproduct.prd.json
+  splan requirements prd generate myproduct.json -o output.md
+  splan requirements prd generate myproduct.json --no-frontmatter
+  splan requirements prd generate myproduct.json --mermaid
+  splan requirements prd generate myproduct.prd.json --profile exec -o exec.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDGenerate,
+}
+
+var prdValidateCmd = &cobra.Command{
+	Use:   "validate <input.json>...",
+	Short: "Validate PRD structure",
+	Long: `Validate a Product Requirements Document by parsing it and checking required fields.
+
+IDs must be unambiguous: the same ID twice in a section, such as two
+functional requirements numbered FR-003, is a duplicate, and the same ID in
+two sections, such as a persona and a user story, is a conflict. --fix
+renumbers the later of each pair and writes the document back before
+validating it.
+
+References must name an item that exists: a user story's personaId and
+phaseId, a requirement's userStoryIds, appendix references, and the like.
+Each dangling reference is an error at its JSON pointer. An objective's
+alignedWith and parentId may also name an item in another document of the
+workspace, and are checked when a splan.workspace.json is found.
+
+Given a directory, a glob such as "docs/**/*.prd.json", or several files,
+every matching document is checked in parallel, followed by a pass/fail
+summary.`,
+	Example: `  splan requirements prd validate myproduct.prd.json
+  splan requirements prd validate docs/
+  splan requirements prd validate "docs/**/*.prd.json" --fail-fast
+  splan requirements prd validate myproduct.prd.json --fix`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPRDValidate,
+}
+
+var prdCheckFlags struct {
+	json     bool
+	fixPatch string
+}
+
+var prdCheckCmd = &cobra.Command{
+	Use:   "check <input.json>...",
+	Short: "Check PRD completeness",
+	Long: `Analyze a Product Requirements Document for completeness and quality.
+
+This command evaluates each section of the PRD and provides:
+  - Overall completeness score (0-100%) and letter grade
+  - Per-section scores for required and optional sections
+  - Specific recommendations for improvement
+
+The check examines:
+  - Required sections: metadata, executive summary, objectives, personas,
+    user stories, requirements, and roadmap
+  - Optional sections: assumptions, out of scope, technical architecture,
+    UX requirements, risks, and glossary
+  - Quality indicators: depth of content, cross-references between sections,
+    acceptance criteria coverage, and NFR category coverage
+
+Given a directory, a glob such as "docs/**/*.prd.json", or several files,
+every matching document is checked in parallel, followed by a pass/fail
+summary.`,
+	Example: `  splan requirements prd check myproduct.prd.json
+  splan requirements prd check myproduct.prd.json --json
+  splan requirements prd check myproduct.prd.json --fix-patch fixes.json
+  splan requirements prd check docs/ --jobs 4`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPRDCheck,
+}
+
+var prdScoreFlags struct {
+	format    string
+	fixPatch  string
+	engine    string
+	provider  string
+	model     string
+	endpoint  string
+	llmWeight float64
+}
+
+var prdFilterFlags struct {
+	output      string
+	includeTags []string
+	excludeTags []string
+	matchAll    bool
+}
+
+var prdFilterCmd = &cobra.Command{
+	Use:   "filter <input.json>",
+	Short: "Filter PRD by tags and output filtered JSON",
+	Long: `Filter a Product Requirements Document by tags and output the filtered JSON.
+
+By default, uses OR logic (union) - includes entities with ANY of the specified tags.
+Use --all to require ALL tags (intersection).
+
+Entities that support tags: personas, user stories, requirements, roadmap phases,
+deliverables, OKRs (objectives and key results), and risks.`,
+	Example: `  # Include items with tag1 OR tag2 (union)
+  splan requirements prd filter input.json --include tag1,tag2
+
+  # Include items with tag1 AND tag2 (intersection)
+  splan requirements prd filter input.json --include tag1,tag2 --all
+
+  # Output to specific file
+  splan requirements prd filter input.json --include mvp -o filtered.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDFilter,
+}
+
+var prdScoreCmd = &cobra.Command{
+	Use:   "score <input.json>...",
+	Short: "Score PRD quality with actionable feedback",
+	Long: `Score a Product Requirements Document against 10 quality dimensions.
+
+This command provides an actionable workflow:
+  (a) Scores - weighted scores across 10 categories
+  (b) Gaps - specific missing elements per category
+  (c) Fix recommendations - what to improve with effort estimates
+  (d) Re-run instructions - command to verify fixes
+
+Output formats:
+  - terminal (default): Box-format terminal output with status icons
+  - json: Full JSON report for programmatic use
+  - markdown: Markdown report for documentation
+
+Quality categories (with weights):
+  - Problem Definition (20%)    - Solution Fit (15%)
+  - User Understanding (10%)    - Market Awareness (10%)
+  - Scope Discipline (10%)      - Requirements Quality (10%)
+  - Metrics Quality (10%)       - UX Coverage (5%)
+  - Technical Feasibility (5%)  - Risk Management (5%)
+
+Decision thresholds:
+  - Approve: >= 8.0
+  - Revise:  >= 6.5
+  - Reject:  < 3.0 (any blocker)
+
+With --engine=llm, each category is also scored by an LLM from the
+sections it covers, and the two scores are merged (--llm-weight sets the
+LLM share). LLM findings are at most medium severity, so they do not block
+approval on their own. Providers are openai, anthropic, and ollama; the API
+key is read from OPENAI_API_KEY or ANTHROPIC_API_KEY, and --endpoint points
+at a compatible server, e.g. a remote Ollama.
+
+Given a directory, a glob such as "docs/**/*.prd.json", or several files,
+every matching document is scored in parallel, followed by a pass/fail
+summary.`,
+	Example: `  splan requirements prd score myproduct.prd.json
+  splan requirements prd score myproduct.prd.json --format=json
+  splan requirements prd score myproduct.prd.json --format=markdown
+  splan requirements prd score myproduct.prd.json --fix-patch fixes.json
+  splan requirements prd score "docs/**/*.prd.json" --fail-fast
+  splan requirements prd score myproduct.prd.json --engine=llm --provider anthropic
+  splan requirements prd score myproduct.prd.json --engine=llm --provider ollama --model llama3.1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPRDScore,
+}
+
+func init() {
+	// PRD generate flags
+	prdGenerateCmd.Flags().StringVarP(&prdGenerateFlags.output, "output", "o", "", "Output markdown file path (default: input with .md extension)")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.margin, "margin", "2cm", "Page margin for Pandoc")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.mainFont, "mainfont", "Helvetica", "Main font family")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.sansFont, "sansfont", "Helvetica", "Sans-serif font family")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.monoFont, "monofont", "Courier New", "Monospace font family")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.fontFamily, "fontfamily", "helvet", "LaTeX font family")
+	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noFrontmatter, "no-frontmatter", false, "Disable YAML frontmatter generation")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.assets, "assets", "", "Bundle local diagram files: copy (next to output) or inline (base64)")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.assetsDir, "assets-dir", assets.DefaultDir, "Asset directory relative to the output for --assets=copy")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.convertDiagrams, "convert-diagrams", "", "Export .drawio/.excalidraw sources to svg or png (implies --assets=copy)")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.diagramEndpoint, "diagram-endpoint", "", "Kroki-compatible export server URL, used instead of local CLIs")
+	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noTOC, "no-toc", false, "Disable Table of Contents generation")
+	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noGlossaryLinks, "no-glossary-links", false, "Do not link the first use of each glossary term to its entry")
+	prdGenerateCmd.Flags().IntVar(&prdGenerateFlags.descLen, "desc-len", prd.DefaultDescriptionMaxLen, "Max length for description fields in tables (0 = no limit)")
+	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noSwimlane, "no-swimlane", false, "Disable swimlane table view in roadmap section")
+	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.swimlaneNoStatus, "swimlane-no-status", false, "Hide status icons in swimlane table")
+	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.mermaid, "mermaid", false, "Add a Mermaid Gantt chart and dependency graph of the roadmap")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.icons, "icons", profile.IconsEmoji, "Status icon set: emoji, text, or none")
+	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.history, "history", "", "Append a Revision History section: git (the input file's commits) or a directory of versioned PRDs")
+
+	prdCmd.AddCommand(prdGenerateCmd)
+	prdCmd.AddCommand(prdValidateCmd)
+	prdCmd.AddCommand(prdCheckCmd)
+	prdCmd.AddCommand(prdScoreCmd)
+	prdCmd.AddCommand(prdFilterCmd)
+
+	// PRD check flags
+	prdCheckCmd.Flags().BoolVar(&prdCheckFlags.json, "json", false, "Output report as JSON")
+	prdCheckCmd.Flags().StringVar(&prdCheckFlags.fixPatch, "fix-patch", "", "Write a JSON Patch fixing mechanical findings, for splan fix")
+
+	// PRD filter flags
+	prdFilterCmd.Flags().StringVarP(&prdFilterFlags.output, "output", "o", "", "Output JSON file path (default: stdout)")
+	prdFilterCmd.Flags().StringSliceVarP(&prdFilterFlags.includeTags, "include", "i", nil, "Tags to include (comma-separated)")
+	prdFilterCmd.Flags().BoolVarP(&prdFilterFlags.matchAll, "all", "a", false, "Require ALL tags (AND logic) instead of ANY (OR logic)")
+
+	// PRD score flags
+	prdScoreCmd.Flags().StringVarP(&prdScoreFlags.format, "format", "f", "terminal", "Output format (terminal, json, markdown)")
+	prdScoreCmd.Flags().StringVar(&prdScoreFlags.fixPatch, "fix-patch", "", "Write a JSON Patch fixing mechanical findings, for splan fix")
+	prdScoreCmd.Flags().StringVar(&prdScoreFlags.engine, "engine", "deterministic", "Scoring engine: deterministic, or llm to merge in LLM scores")
+	prdScoreCmd.Flags().StringVar(&prdScoreFlags.provider, "provider", "", "LLM provider for --engine=llm: openai, anthropic, or ollama")
+	prdScoreCmd.Flags().StringVar(&prdScoreFlags.model, "model", "", "LLM model (default: the provider's default)")
+	prdScoreCmd.Flags().StringVar(&prdScoreFlags.endpoint, "endpoint", "", "LLM API base URL (default: the provider's)")
+	prdScoreCmd.Flags().Float64Var(&prdScoreFlags.llmWeight, "llm-weight", llmeval.DefaultWeight, "Share of the LLM score in each merged category score (0-1)")
+}
+
+func runPRDGenerate(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	// Determine output file
+	output := prdGenerateFlags.output
+	if output == "" {
+		output = deriveOutputPath(inputFile)
+	}
+
+	rp, err := renderProfile(inputFile)
+	if err != nil {
+		return err
+	}
+	if rp != nil {
+		switch rp.Format {
+		case profile.FormatHTML:
+			prdHTMLFlags.output = prdGenerateFlags.output
+			prdHTMLFlags.noGlossaryLinks = prdGenerateFlags.noGlossaryLinks
+			return runPRDGenerateHTML(cmd, args)
+		case profile.FormatDOCX:
+			prdDOCXFlags.output = prdGenerateFlags.output
+			return runPRDGenerateDOCX(cmd, args)
+		}
+		applyRenderProfile(cmd, rp, &prdGenerateFlags)
+	}
+	switch prdGenerateFlags.icons {
+	case profile.IconsEmoji, profile.IconsText, profile.IconsNone:
+	default:
+		return fmt.Errorf("invalid --icons %q (use emoji, text, or none)", prdGenerateFlags.icons)
+	}
+
+	// Read input file
+	var doc prd.Document
+	if err := readProfiledDocument(inputFile, &doc, rp, os.Stderr); err != nil {
+		return err
+	}
+	fonts, err := generateFonts(cmd, inputFile, &prdGenerateFlags)
+	if err != nil {
+		return err
+	}
+
+	if err := bundleAssets(doc.AssetRefs(), inputFile, output, &prdGenerateFlags); err != nil {
+		return err
+	}
+
+	// Handle TOC option (default: enabled, disabled with --no-toc)
+	includeTOC := !prdGenerateFlags.noTOC
+	// Handle swimlane option (default: enabled, disabled with --no-swimlane)
+	includeSwimlane := !prdGenerateFlags.noSwimlane
+	linkGlossary := !prdGenerateFlags.noGlossaryLinks
+
+	opts := prd.MarkdownOptions{
+		IncludeFrontmatter:     !prdGenerateFlags.noFrontmatter,
+		Margin:                 prdGenerateFlags.margin,
+		MainFont:               fonts.MainFont,
+		SansFont:               fonts.SansFont,
+		MonoFont:               fonts.MonoFont,
+		FontFamily:             fonts.FontFamily,
+		DescriptionMaxLen:      prdGenerateFlags.descLen,
+		IncludeSwimlaneTable:   includeSwimlane,
+		IncludeRoadmapDiagrams: prdGenerateFlags.mermaid,
+		IncludeTOC:             &includeTOC,
+		Icons:                  prdGenerateFlags.icons,
+		LinkGlossary:           &linkGlossary,
+	}
+	if rp != nil {
+		for _, section := range prd.CoreSections {
+			if !rp.Shows(section) {
+				opts.OmitSections = append(opts.OmitSections, section)
+			}
+		}
+	}
+
+	// Configure swimlane table options
+	if includeSwimlane {
+		tableOpts := prd.DefaultRoadmapTableOptions()
+		tableOpts.IncludeStatus = !prdGenerateFlags.swimlaneNoStatus
+		opts.RoadmapTableOptions = &tableOpts
+	}
+
+	if prdGenerateFlags.history != "" {
+		h, err := loadHistory(inputFile, prdGenerateFlags.history)
+		if err != nil {
+			return fmt.Errorf("building revision history: %w", err)
+		}
+		opts.RevisionHistory = h.ToMarkdown()
+	}
+
+	markdown := doc.ToMarkdown(opts)
+
+	links, err := workspaceLinks(inputFile)
+	if err != nil {
+		return err
+	}
+	if links != nil {
+		markdown = links.Markdown(markdown, output)
+	}
+
+	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+// workspaceLinks returns the links to the MRD and TRD items of the
+// workspace that inputFile belongs to, or nil if there is no workspace
+// manifest.
+func workspaceLinks(inputFile string) (*xref.Links, error) {
+	m, err := workspace.FindManifest(filepath.Dir(inputFile))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return xref.Load(m, rootFlags.lenient)
+}
+
+func runPRDValidate(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd"), validatePRDFile)
+}
+
+func validatePRDFile(inputFile string, stdout, stderr io.Writer) error {
+	if validateFixFlags.fix {
+		if err := fixDuplicateIDs(inputFile, &prd.Document{}, stdout, stderr); err != nil {
+			return err
+		}
+	}
+	var doc prd.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	var issues []validation.Issue
+
+	if doc.Metadata.ID == "" {
+		issues = append(issues, requiredIssue("/metadata/id", "metadata.id is required"))
+	}
+	if doc.Metadata.Title == "" {
+		issues = append(issues, requiredIssue("/metadata/title", "metadata.title is required"))
+	}
+	if doc.Metadata.Version == "" {
+		issues = append(issues, requiredIssue("/metadata/version", "metadata.version is required"))
+	}
+	if len(doc.Metadata.Authors) == 0 {
+		issues = append(issues, requiredIssue("/metadata/authors", "metadata.authors is required (at least one author)"))
+	}
+	if doc.ExecutiveSummary.ProblemStatement == "" {
+		issues = append(issues, requiredIssue("/executiveSummary/problemStatement", "executive_summary.problem_statement is required"))
+	}
+	if doc.ExecutiveSummary.ProposedSolution == "" {
+		issues = append(issues, requiredIssue("/executiveSummary/proposedSolution", "executive_summary.proposed_solution is required"))
+	}
+	if len(doc.Personas) == 0 {
+		issues = append(issues, requiredIssue("/personas", "personas is required (at least one persona)"))
+	}
+	if len(doc.UserStories) == 0 {
+		issues = append(issues, requiredIssue("/userStories", "user_stories is required (at least one user story)"))
+	}
+	if len(doc.Roadmap.Phases) == 0 {
+		issues = append(issues, requiredIssue("/roadmap/phases", "roadmap.phases is required (at least one phase)"))
+	}
+	issues = append(issues, metadataIssues(
+		common.ValidateReviews(doc.Metadata.Status, doc.Metadata.Reviews, doc.Metadata.RequiredReviewRoles),
+		common.ValidateLifecycle(doc.Metadata.Lifecycle()))...)
+	for _, li := range doc.ValidateLinks() {
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleLink,
+			Severity: validation.SeverityError,
+			Pointer:  validation.PathPointer(li.Field),
+			Message:  linkMessage(li),
+		})
+	}
+	for _, m := range assets.Check(doc.AssetRefs(), filepath.Dir(inputFile)) {
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleAsset,
+			Severity: validation.SeverityError,
+			Pointer:  validation.PathPointer(m.Field),
+			Message:  fmt.Sprintf("%s: file not found: %s", m.Field, m.Path),
+		})
+	}
+	idIs, err := idIssues(&doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, idIs...)
+	refIs, err := referenceIssues(inputFile, "prd", &doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIs...)
+	warnings, err := sourceWarnings(inputFile)
+	if err != nil {
+		return err
+	}
+	recordIssues(inputFile, "prd", append(issues, warnings...))
+
+	if len(issues) > 0 {
+		errors := issueMessages(issues)
+		fmt.Fprintf(stderr, "Validation failed for %s:\n", inputFile)
+		for _, e := range errors {
+			fmt.Fprintf(stderr, "  - %s\n", e)
+		}
+		for _, w := range issueMessages(warnings) {
+			fmt.Fprintf(stderr, "  - warning: %s\n", w)
+		}
+		recordErrors(inputFile, errors)
+		return fmt.Errorf("validation failed with %d errors", len(errors))
+	}
+
+	fmt.Fprintf(stdout, "Valid PRD: %s\n", inputFile)
+	fmt.Fprintf(stdout, "  Title: %s\n", doc.Metadata.Title)
+	fmt.Fprintf(stdout, "  Version: %s\n", doc.Metadata.Version)
+	fmt.Fprintf(stdout, "  Personas: %d\n", len(doc.Personas))
+	fmt.Fprintf(stdout, "  User Stories: %d\n", len(doc.UserStories))
+	fmt.Fprintf(stdout, "  Functional Requirements: %d\n", len(doc.Requirements.Functional))
+	fmt.Fprintf(stdout, "  Non-Functional Requirements: %d\n", len(doc.Requirements.NonFunctional))
+	fmt.Fprintf(stdout, "  Phases: %d\n", len(doc.Roadmap.Phases))
+	for _, w := range issueMessages(warnings) {
+		fmt.Fprintf(stdout, "  Warning: %s\n", w)
+	}
+
+	return nil
+}
+
+func runPRDCheck(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd"), checkPRDFile)
+}
+
+func checkPRDFile(inputFile string, stdout, stderr io.Writer) error {
+	var doc prd.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	report := doc.CheckCompleteness()
+
+	if prdCheckFlags.json {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		fmt.Fprintln(stdout, string(output))
+	} else {
+		fmt.Fprint(stdout, report.FormatReport())
+	}
+
+	if prdCheckFlags.fixPatch != "" {
+		if err := writeFixPatch(&doc, inputFile, prdCheckFlags.fixPatch); err != nil {
+			return err
+		}
+	}
+
+	// Return non-zero exit code if PRD has critical issues
+	if report.Grade == "F" {
+		return fmt.Errorf("PRD completeness check failed (Grade: F)")
+	}
+
+	return nil
+}
+
+func runPRDScore(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd"), scorePRDFile)
+}
+
+func scorePRDFile(inputFile string, stdout, stderr io.Writer) error {
+	var doc prd.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	// Generate evaluation report from deterministic scoring
+	report := prd.ScoreToEvaluationReport(&doc, inputFile)
+
+	switch strings.ToLower(prdScoreFlags.engine) {
+	case "llm":
+		evaluator, err := newLLMEvaluator()
+		if err != nil {
+			return err
+		}
+		llm, err := evaluator.Evaluate(context.Background(), &doc, inputFile)
+		if err != nil {
+			return fmt.Errorf("LLM evaluation of %s: %w", inputFile, err)
+		}
+		report = llmeval.Merge(report, llm, evaluator.Weight)
+	case "deterministic", "":
+	default:
+		return fmt.Errorf("unknown engine: %s (expected deterministic or llm)", prdScoreFlags.engine)
+	}
+
+	switch strings.ToLower(prdScoreFlags.format) {
+	case "json":
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		fmt.Fprintln(stdout, string(output))
+
+	case "markdown":
+		fmt.Fprint(stdout, formatEvaluationReportMarkdown(report))
+
+	case "terminal", "":
+		output, err := render.Default.Render("evaluation", render.FormatTerminal, report, render.Options{})
+		if err != nil {
+			return fmt.Errorf("rendering report: %w", err)
+		}
+		fmt.Fprint(stdout, string(output))
+
+	default:
+		return fmt.Errorf("unknown format: %s (expected terminal, json, or markdown)", prdScoreFlags.format)
+	}
+
+	if prdScoreFlags.fixPatch != "" {
+		if err := writeFixPatch(&doc, inputFile, prdScoreFlags.fixPatch); err != nil {
+			return err
+		}
+	}
+
+	recordScore(report.WeightedScore, report.Decision.Passed)
+
+	// Return non-zero exit code if PRD has blocking issues
+	if !report.Decision.Passed {
+		return fmt.Errorf("PRD evaluation: %s", report.Decision.Rationale)
+	}
+
+	return nil
+}
+
+// newLLMEvaluator returns the evaluator configured by the prd score flags,
+// with the API key from the provider's environment variable.
+func newLLMEvaluator() (*llmeval.Evaluator, error) {
+	if prdScoreFlags.provider == "" {
+		return nil, fmt.Errorf("--engine=llm requires --provider (openai, anthropic, or ollama)")
+	}
+	if prdScoreFlags.llmWeight < 0 || prdScoreFlags.llmWeight > 1 {
+		return nil, fmt.Errorf("--llm-weight must be between 0 and 1, got %g", prdScoreFlags.llmWeight)
+	}
+	cfg := llmeval.Config{Model: prdScoreFlags.model, Endpoint: prdScoreFlags.endpoint}
+	keyVars := map[string]string{
+		llmeval.ProviderOpenAI:    "OPENAI_API_KEY",
+		llmeval.ProviderAnthropic: "ANTHROPIC_API_KEY",
+	}
+	if v := keyVars[strings.ToLower(prdScoreFlags.provider)]; v != "" {
+		if cfg.APIKey = os.Getenv(v); cfg.APIKey == "" {
+			return nil, fmt.Errorf("--provider %s requires the %s environment variable", prdScoreFlags.provider, v)
+		}
+	}
+	p, err := llmeval.NewProvider(prdScoreFlags.provider, cfg)
+	if err != nil {
+		return nil, err
+	}
+	e := llmeval.New(p)
+	e.Weight = prdScoreFlags.llmWeight
+	return e, nil
+}
+
+func runPRDFilter(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	// Validate filter tags
+	if len(prdFilterFlags.includeTags) > 0 {
+		for _, tag := range prdFilterFlags.includeTags {
+			if err := prd.ValidateTag(tag); err != nil {
+				return fmt.Errorf("invalid filter tag: %w", err)
+			}
+		}
+	}
+
+	// Read input file
+	var doc prd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return err
+	}
+
+	// Apply filter
+	var filtered prd.Document
+	if len(prdFilterFlags.includeTags) > 0 {
+		if prdFilterFlags.matchAll {
+			filtered = doc.FilterByTagsAll(prdFilterFlags.includeTags...)
+		} else {
+			filtered = doc.FilterByTags(prdFilterFlags.includeTags...)
+		}
+	} else {
+		filtered = doc
+	}
+
+	// Marshal output in the output file's format, or the input format when
+	// writing to stdout
+	format := yamlconv.FormatFromPath(prdFilterFlags.output)
+	if prdFilterFlags.output == "" {
+		var err error
+		if format, err = inputFormat(inputFile); err != nil {
+			return err
+		}
+	}
+	output, err := marshalDocument(filtered, format)
+	if err != nil {
+		return fmt.Errorf("marshaling filtered document: %w", err)
+	}
+
+	// Write output
+	if prdFilterFlags.output != "" {
+		if err := os.WriteFile(prdFilterFlags.output, output, 0600); err != nil {
+			return fmt.Errorf("writing output file: %w", err)
+		}
+		fmt.Printf("Filtered PRD written to: %s\n", prdFilterFlags.output)
+	} else {
+		fmt.Println(string(output))
+	}
+
+	return nil
+}
+
+func formatEvaluationReportMarkdown(report *evaluation.EvaluationReport) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("# %s Evaluation Report\n\n", strings.ToUpper(report.ReviewType)))
+	b.WriteString(fmt.Sprintf("**Document**: %s\n", report.Metadata.Document))
+	if report.Metadata.DocumentTitle != "" {
+		b.WriteString(fmt.Sprintf("**Title**: %s\n", report.Metadata.DocumentTitle))
+	}
+	b.WriteString(fmt.Sprintf("**Score**: %.1f / 10.0\n", report.WeightedScore))
+	b.WriteString(fmt.Sprintf("**Decision**: %s\n\n", strings.ToUpper(string(report.Decision.Status))))
+
+	// Category scores table
+	b.WriteString("## Category Scores\n\n")
+	b.WriteString("| Category | Score | Weight | Status |\n")
+	b.WriteString("|----------|-------|--------|--------|\n")
+	for _, cs := range report.Categories {
+		status := "✅"
+		if cs.Status == evaluation.ScoreStatusWarn {
+			status = "⚠️"
+		} else if cs.Status == evaluation.ScoreStatusFail {
+			status = "❌"
+		}
+		b.WriteString(fmt.Sprintf("| %s | %.1f | %.0f%% | %s |\n",
+			cs.Category, cs.Score, cs.Weight*100, status))
+	}
+	b.WriteString("\n")
+
+	// Entities that earned or lost points, attached by the scorer as
+	// category findings
+	explained := false
+	for _, cs := range report.Categories {
+		if len(cs.Findings) == 0 {
+			continue
+		}
+		if !explained {
+			b.WriteString("## Score Explanations\n\n")
+			explained = true
+		}
+		b.WriteString(fmt.Sprintf("### %s (%.1f)\n\n", cs.Category, cs.Score))
+		for _, f := range cs.Findings {
+			impact := "❌ Lost"
+			if f.Severity == evaluation.SeverityInfo {
+				impact = "✅ Earned"
+			}
+			line := fmt.Sprintf("- %s: %s", impact, f.Title)
+			if f.ID != "" {
+				line += fmt.Sprintf(" — **%s**", f.ID)
+			}
+			if f.Description != "" {
+				line += fmt.Sprintf(" %q", f.Description)
+			}
+			b.WriteString(line + fmt.Sprintf(" (`%s`)\n", f.Evidence))
+		}
+		b.WriteString("\n")
+	}
+
+	// Findings by severity
+	if len(report.Findings) > 0 {
+		b.WriteString("## Findings\n\n")
+		for _, sev := range evaluation.AllSeverities() {
+			sevFindings := []evaluation.Finding{}
+			for _, f := range report.Findings {
+				if f.Severity == sev {
+					sevFindings = append(sevFindings, f)
+				}
+			}
+			if len(sevFindings) > 0 {
+				b.WriteString(fmt.Sprintf("### %s %s\n\n", sev.Icon(), strings.ToUpper(string(sev))))
+				for _, f := range sevFindings {
+					b.WriteString(fmt.Sprintf("- **[%s]** %s\n", f.Category, f.Title))
+					if f.Recommendation != "" {
+						b.WriteString(fmt.Sprintf("  - Fix: %s\n", f.Recommendation))
+					}
+					if f.Owner != "" {
+						b.WriteString(fmt.Sprintf("  - Owner: %s\n", f.Owner))
+					}
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	// Next steps
+	b.WriteString("## Next Steps\n\n")
+	if len(report.NextSteps.Immediate) > 0 {
+		b.WriteString("### Immediate Actions\n\n")
+		for _, action := range report.NextSteps.Immediate {
+			b.WriteString(fmt.Sprintf("- [ ] 🔴 %s\n", action.Action))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(report.NextSteps.Recommended) > 0 {
+		b.WriteString("### Recommended\n\n")
+		for _, action := range report.NextSteps.Recommended {
+			b.WriteString(fmt.Sprintf("- [ ] %s\n", action.Action))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("### Re-run Evaluation\n\n")
+	b.WriteString(fmt.Sprintf("```bash\n%s\n```\n", report.NextSteps.RerunCommand))
+
+	return b.String()
+}
+
+var prdThreatModelFlags struct {
+	output string
+	format string
+}
+
+var prdThreatModelCmd = &cobra.Command{
+	Use:   "threatmodel <input.json>",
+	Short: "Export the PRD threat model to OTM or Threat Dragon",
+	Long: `Export the securityModel threat model (assets, threat actors, trust
+boundaries, and key threats with mitigations) for use in threat modeling tools.
+
+Formats:
+  otm            Open Threat Model JSON (IriusRisk, OTM-compatible tools)
+  threat-dragon  OWASP Threat Dragon v2 model
+
+By default, the output file is the input name with .otm.json or
+.threat-dragon.json in place of .json.`,
+	Example: `  splan requirements prd threatmodel myproduct.prd.json
+  splan requirements prd threatmodel myproduct.prd.json --format threat-dragon
+  splan requirements prd threatmodel myproduct.prd.json -o model.otm.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDThreatModel,
+}
+
+func init() {
+	prdThreatModelCmd.Flags().StringVarP(&prdThreatModelFlags.output, "output", "o", "", "Output file path (default: input with format extension)")
+	prdThreatModelCmd.Flags().StringVarP(&prdThreatModelFlags.format, "format", "f", "otm", "Export format (otm, threat-dragon)")
+
+	prdCmd.AddCommand(prdThreatModelCmd)
+}
+
+func runPRDThreatModel(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	var format string
+	switch strings.ToLower(prdThreatModelFlags.format) {
+	case "otm":
+		format = "otm"
+	case "threat-dragon", "threatdragon":
+		format = "threat-dragon"
+	default:
+		return fmt.Errorf("unknown format %q (valid: otm, threat-dragon)", prdThreatModelFlags.format)
+	}
+	renderer, err := render.Lookup("prd", format)
+	if err != nil {
+		return err
+	}
+
+	output := prdThreatModelFlags.output
+	if output == "" {
+		output = strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + renderer.FileExtension()
+	}
+
+	var doc prd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return err
+	}
+
+	data, err := renderer.Render(&doc, render.Options{})
+	if err != nil {
+		return fmt.Errorf("exporting threat model: %w", err)
+	}
+
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+var prdFeedbackFlags struct {
+	json bool
+}
+
+var prdFeedbackCmd = &cobra.Command{
+	Use:   "feedback <input.json>",
+	Short: "Report customer feedback linked to requirements",
+	Long: `Report the customer feedback references (support tickets, sales calls,
+interviews) linked to each requirement, with sentiment counts, and list the
+requirements that have no supporting feedback.
+
+Feedback can be attached to personas, problems, and requirements with a
+feedback array of {source, externalId, summary, sentiment} entries.`,
+	Example: `  splan requirements prd feedback my-product.prd.json
+  splan requirements prd feedback my-product.prd.json --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDFeedback,
+}
+
+func init() {
+	prdFeedbackCmd.Flags().BoolVar(&prdFeedbackFlags.json, "json", false, "Output report as JSON")
+
+	prdCmd.AddCommand(prdFeedbackCmd)
+}
+
+func runPRDFeedback(cmd *cobra.Command, args []string) error {
+	var doc prd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	report := prd.FeedbackCoverage(&doc)
+
+	if prdFeedbackFlags.json {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling feedback report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(report.Requirements) == 0 {
+		fmt.Println("No requirements found")
+		return nil
+	}
+	for _, rf := range report.Requirements {
+		fmt.Printf("%-10s %-6s %3d  %s%s\n", rf.ID, orDash(string(rf.Priority)), rf.Count, rf.Title, formatSentiment(rf.Sentiment))
+	}
+
+	if len(report.Unsupported) > 0 {
+		fmt.Printf("\nRequirements with no supporting feedback (%d):\n", len(report.Unsupported))
+		for _, id := range report.Unsupported {
+			fmt.Printf("  - %s\n", id)
+		}
+	}
+
+	supported := len(report.Requirements) - len(report.Unsupported)
+	fmt.Printf("\nFeedback coverage: %d/%d requirements (%.0f%%)\n", supported, len(report.Requirements), report.Coverage()*100)
+	fmt.Printf("Problem feedback: %d, persona feedback: %d\n", report.ProblemFeedback, report.PersonaFeedback)
+	if len(report.Sources) > 0 {
+		sources := make([]string, 0, len(report.Sources))
+		for source := range report.Sources {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		parts := make([]string, 0, len(sources))
+		for _, source := range sources {
+			parts = append(parts, fmt.Sprintf("%s %d", source, report.Sources[source]))
+		}
+		fmt.Printf("Sources: %s\n", strings.Join(parts, ", "))
+	}
+	return nil
+}
+
+// formatSentiment renders sentiment counts as " (2 negative, 1 positive)",
+// or "" when no feedback has a sentiment.
+func formatSentiment(counts map[prd.FeedbackSentiment]int) string {
+	var parts []string
+	for _, s := range []prd.FeedbackSentiment{prd.SentimentNegative, prd.SentimentMixed, prd.SentimentNeutral, prd.SentimentPositive} {
+		if n := counts[s]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, s))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// budgetFlags holds flags shared by the PRD and TRD budget commands.
+type budgetFlags struct {
+	output string
+	format string
+}
+
+var prdBudgetFlags budgetFlags
+
+var prdBudgetCmd = &cobra.Command{
+	Use:   "budget <input.json>",
+	Short: "Roll up cost estimates by phase and quarter",
+	Long: `Roll up the cost estimates on roadmap phases and technology stack entries
+into a budget by phase and calendar quarter, for finance reviews.
+
+Costs are objects of {build, run, licensing, currency, phaseId, notes}. Build
+is a one-time cost spread over its phase; run (monthly) and licensing (annual)
+are recurring costs that accrue from the phase they start in to the end of the
+roadmap. Technology costs need a phaseId to be scheduled.
+
+The report is written to stdout as markdown unless --output is given. The
+output format is taken from --format, or inferred from the output file
+extension (.csv for CSV, .json for JSON, markdown otherwise).`,
+	Example: `  splan requirements prd budget my-product.prd.json
+  splan requirements prd budget my-product.prd.json -o budget.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDBudget,
+}
+
+func init() {
+	prdBudgetCmd.Flags().StringVarP(&prdBudgetFlags.output, "output", "o", "", "Output file (default: stdout)")
+	prdBudgetCmd.Flags().StringVar(&prdBudgetFlags.format, "format", "", "Output format: markdown, csv, json (default: from output extension)")
+
+	prdCmd.AddCommand(prdBudgetCmd)
+}
+
+func runPRDBudget(cmd *cobra.Command, args []string) error {
+	var doc prd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	return writeBudget(doc.Budget(), doc.Metadata.Title, &prdBudgetFlags)
+}
+
+// writeBudget writes a budget report in the format selected by flags.
+func writeBudget(report *budget.Report, title string, flags *budgetFlags) error {
+	format := strings.ToLower(flags.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(flags.output)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		default:
+			format = "markdown"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString("# Budget: " + title + "\n\n")
+		buf.WriteString(report.ToMarkdown())
+	case "csv":
+		if err := report.WriteCSV(&buf); err != nil {
+			return err
+		}
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling budget report: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, csv, json)", flags.format)
+	}
+
+	if flags.output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+	if err := os.WriteFile(flags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s (%d cost items, total spend %s %s)\n",
+		flags.output, len(report.Items), budget.FormatAmount(report.Total.Spend), report.Currency)
+	for _, w := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return nil
+}
+
+var prdCoverageFlags struct {
+	output string
+	format string
+}
+
+var prdCoverageCmd = &cobra.Command{
+	Use:   "coverage <input.json>",
+	Short: "Heatmap of stories and requirements per persona and phase",
+	Long: `Count the user stories and functional requirements serving each persona in
+each roadmap phase, as a heatmap of personas by phase.
+
+A story serves its persona in its phase. A functional requirement serves the
+personas of its userStoryIds, in its own phaseId or else in the phases of
+those stories. Work with no phase, or a phase not on the roadmap, is counted
+under Unscheduled.
+
+The first half of the phases, rounded up, are early. Personas with no work
+in any early phase are flagged as neglected; they also cost a point in the
+user_understanding score of "prd score".
+
+The report is written to stdout as markdown unless --output is given. The
+output format is taken from --format, or inferred from the output file
+extension (.csv for CSV, .json for JSON, markdown otherwise). CSV has one row
+per persona and phase.`,
+	Example: `  splan requirements prd coverage my-product.prd.json
+  splan requirements prd coverage my-product.prd.json -o coverage.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDCoverage,
+}
+
+func init() {
+	prdCoverageCmd.Flags().StringVarP(&prdCoverageFlags.output, "output", "o", "", "Output file (default: stdout)")
+	prdCoverageCmd.Flags().StringVar(&prdCoverageFlags.format, "format", "", "Output format: markdown, csv, json (default: from output extension)")
+
+	prdCmd.AddCommand(prdCoverageCmd)
+}
+
+func runPRDCoverage(cmd *cobra.Command, args []string) error {
+	var doc prd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	cov := doc.PersonaCoverage()
+
+	format := strings.ToLower(prdCoverageFlags.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(prdCoverageFlags.output)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		default:
+			format = "markdown"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString("# Persona Coverage: " + doc.Metadata.Title + "\n\n")
+		buf.WriteString(cov.ToMarkdown())
+	case "csv":
+		if err := cov.WriteCSV(&buf); err != nil {
+			return err
+		}
+	case "json":
+		data, err := json.MarshalIndent(cov, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling persona coverage: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, csv, json)", prdCoverageFlags.format)
+	}
+
+	if prdCoverageFlags.output == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+	if err := os.WriteFile(prdCoverageFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, prdCoverageFlags.output)
+	fmt.Printf("Generated: %s (%d personas, %d neglected in early phases)\n",
+		prdCoverageFlags.output, len(cov.Personas), len(cov.Neglected()))
+	return nil
+}
+
+var prdDeriveFlags struct {
+	from   string
+	output string
+}
+
+var prdDeriveCmd = &cobra.Command{
+	Use:   "derive",
+	Short: "Create a starter PRD from an MRD",
+	Long: `Create a starter PRD derived from a Market Requirements Document.
+
+Buyer personas become personas, the positioning and success metrics become an
+objective with key results, and market requirements become functional
+requirement stubs; requirements prioritized "wont" become out-of-scope
+entries. Competitors, differentiators, risks, assumptions, and the glossary
+are carried over. Each derived item is linked back to its MRD ID under
+"provenance". Required fields the MRD cannot supply are filled with "TODO:"
+placeholders, which are listed when the file is written.
+
+By default the PRD is written next to the MRD, with .mrd replaced by .prd in
+the file name. Existing files are not overwritten.`,
+	Example: `  splan requirements prd derive --from market.mrd.json
+  splan requirements prd derive --from market.mrd.json -o product/myproduct.prd.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runPRDDerive,
+}
+
+func init() {
+	prdDeriveCmd.Flags().StringVar(&prdDeriveFlags.from, "from", "", "MRD file to derive from")
+	prdDeriveCmd.Flags().StringVarP(&prdDeriveFlags.output, "output", "o", "", "Output PRD file (default: MRD path with .mrd replaced by .prd)")
+	_ = prdDeriveCmd.MarkFlagRequired("from")
+
+	prdCmd.AddCommand(prdDeriveCmd)
+}
+
+func runPRDDerive(cmd *cobra.Command, args []string) error {
+	var m mrd.Document
+	if err := readDocument(prdDeriveFlags.from, &m); err != nil {
+		return err
+	}
+
+	output := prdDeriveFlags.output
+	if output == "" {
+		output = scaffoldOutputPath(prdDeriveFlags.from, ".mrd", ".prd")
+	}
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", output)
+	}
+
+	doc, todos := prd.DeriveFromMRD(&m)
+	if rel, err := filepath.Rel(filepath.Dir(output), prdDeriveFlags.from); err == nil {
+		doc.Provenance.Source.Path = filepath.ToSlash(rel)
+	}
+
+	data, err := marshalDocument(doc, yamlconv.FormatFromPath(output))
+	if err != nil {
+		return fmt.Errorf("marshaling PRD: %w", err)
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Created: %s\n", output)
+	fmt.Printf("Linked %d item(s) to %s\n", len(doc.Provenance.Links), doc.Provenance.Source.ID)
+	if len(todos) > 0 {
+		fmt.Printf("\n%d section(s) need input (search for %q):\n", len(todos), prd.TODOPrefix)
+		for _, path := range todos {
+			fmt.Printf("  - %s\n", path)
+		}
+	}
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Replace the TODO placeholders and write user stories and acceptance criteria")
+	fmt.Println("  2. Run 'splan requirements prd validate " + output + "' to check the PRD")
+	fmt.Println("  3. Run 'splan requirements prd generate " + output + "' to create markdown")
+	return nil
+}
+
+var prdImportFlags struct {
+	output       string
+	sourceFormat string
+	mapping      []string
+	interactive  bool
+}
+
+var prdImportCmd = &cobra.Command{
+	Use:   "import <legacy.md|.docx|.html>",
+	Short: "Convert a markdown, Word, or Google Docs PRD to a structured PRD",
+	Long: `Convert a legacy PRD into a structured PRD.
+
+Markdown, Word (.docx), and Google Docs exports are accepted. Download a
+Google Doc as "Microsoft Word (.docx)" or "Web page (.html, zipped)"; the
+zip can be passed as is. Word and HTML documents are converted to markdown
+first, keeping headings, paragraphs, lists, bold labels, and tables. The
+format is taken from --source-format or the file extension.
+
+Sections are recognized by their headings: executive summary, problem,
+solution, goals, personas, user stories, functional and non-functional
+requirements, out of scope, roadmap, risks, assumptions and constraints, and
+glossary. Tables are read by their column headers, and user stories also
+from "As a ..., I want ... so that ..." list items. YAML front matter and a
+Field | Value table under the title fill in the metadata. Markdown generated
+by "splan requirements prd generate" imports back.
+
+A heading that matches more than one section, such as "Risks and
+Assumptions", is mapped to the first match and reported. With --interactive,
+you are asked which section to use for these headings and for headings
+that match none. --map sets the section for a heading directly.
+
+Sections that cannot be mapped are listed with their line numbers and kept
+as custom sections, so a conversion can be finished by hand. Missing IDs are
+assigned and user stories linked to personas as "splan fix" would.
+
+By default the PRD is written next to the input file, with the extension
+replaced by .prd.json. Existing files are not overwritten.`,
+	Example: `  splan requirements prd import legacy.md
+  splan requirements prd import legacy.md -o legacy.prd.yaml
+  splan requirements prd import "Checkout PRD.docx" --interactive
+  splan requirements prd import checkout.zip --map "Launch Plan=roadmap" --map "FAQ=custom"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDImport,
+}
+
+func init() {
+	prdImportCmd.Flags().StringVarP(&prdImportFlags.output, "output", "o", "", "Output PRD file (default: input with its extension replaced by .prd.json)")
+	prdImportCmd.Flags().StringVar(&prdImportFlags.sourceFormat, "source-format", "", "Input format: markdown, docx, html (default: from file extension)")
+	prdImportCmd.Flags().StringArrayVar(&prdImportFlags.mapping, "map", nil, "Map a heading to a section, as HEADING=SECTION (repeatable)")
+	prdImportCmd.Flags().BoolVarP(&prdImportFlags.interactive, "interactive", "i", false, "Ask which section to use for ambiguous or unrecognized headings")
+
+	prdCmd.AddCommand(prdImportCmd)
+}
+
+func runPRDImport(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+	src, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("reading input file: %w", err)
+	}
+
+	format := importer.FormatFromPath(inputFile)
+	if prdImportFlags.sourceFormat != "" {
+		if format, err = importer.ParseFormat(prdImportFlags.sourceFormat); err != nil {
+			return err
+		}
+	}
+	opts, err := importOptions(prdImportFlags.mapping)
+	if err != nil {
+		return err
+	}
+	if prdImportFlags.interactive {
+		opts.Resolve = promptImportTarget(bufio.NewReader(os.Stdin), os.Stdout)
+	}
+
+	output := prdImportFlags.output
+	if output == "" {
+		output = strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + ".prd.json"
+	}
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", output)
+	}
+
+	doc, report, err := importer.PRD(src, format, opts)
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", inputFile, err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if doc.Metadata.CreatedAt.IsZero() {
+		doc.Metadata.CreatedAt = now
+	}
+	if doc.Metadata.UpdatedAt.IsZero() {
+		doc.Metadata.UpdatedAt = now
+	}
+
+	data, err := marshalDocument(doc, yamlconv.FormatFromPath(output))
+	if err != nil {
+		return fmt.Errorf("marshaling PRD: %w", err)
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Created: %s\n", output)
+	fmt.Printf("Imported %d persona(s), %d user story(ies), %d functional and %d non-functional requirement(s), %d risk(s)\n",
+		len(doc.Personas), len(doc.UserStories), len(doc.Requirements.Functional), len(doc.Requirements.NonFunctional), len(doc.Risks))
+	fmt.Print(report)
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Move the content of custom sections into structured fields")
+	fmt.Println("  2. Run 'splan requirements prd validate " + output + "' to check the PRD")
+	fmt.Println("  3. Run 'splan requirements prd score " + output + "' to find gaps")
+	return nil
+}
+
+var prdImportCSVFlags struct {
+	into    string
+	output  string
+	section string
+	strict  bool
+}
+
+var prdImportCSVCmd = &cobra.Command{
+	Use:   "csv <requirements.csv>",
+	Short: "Merge requirements from a CSV file into a PRD",
+	Long: `Merge the rows of a CSV file, such as a spreadsheet export, into the
+functional or non-functional requirements of an existing PRD.
+
+Columns are mapped by header, ignoring case and punctuation: ID, Title (or
+Summary), Description, Category, Priority, Phase, User Stories, Acceptance
+Criteria, Dependencies, Tags (or Labels), and Notes for functional
+requirements; ID, Title, Description, Category, Metric, Target, Measurement
+Method, Priority, Phase, Current Baseline, and Notes for non-functional ones.
+Other columns are ignored and listed.
+
+A row whose ID matches a requirement updates the fields of its non-empty
+cells. Other rows add a requirement, numbered after the highest ID if the
+row has none. New acceptance criteria are numbered after their requirement,
+as in FR-001-AC-1. Rows with an invalid priority (must, should, could,
+won't), a missing or unknown NFR category, or a phase or user story not in
+the PRD are rejected and reported with their line number. Phases may be
+given by ID or name.
+
+The PRD is written back in place, or to -o. With --strict, nothing is
+written if any row is rejected.`,
+	Example: `  splan requirements prd import csv reqs.csv --into checkout.prd.json
+  splan requirements prd import csv --section functional reqs.csv --into checkout.prd.json
+  splan requirements prd import csv nfrs.csv --section nfr --into checkout.prd.json -o merged.prd.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDImportCSV,
+}
+
+func init() {
+	prdImportCSVCmd.Flags().StringVar(&prdImportCSVFlags.into, "into", "", "PRD file to merge the requirements into (required)")
+	prdImportCSVCmd.Flags().StringVarP(&prdImportCSVFlags.output, "output", "o", "", "Output PRD file (default: the --into file)")
+	prdImportCSVCmd.Flags().StringVar(&prdImportCSVFlags.section, "section", string(prd.CSVFunctional), "Requirements section: functional or non-functional")
+	prdImportCSVCmd.Flags().BoolVar(&prdImportCSVFlags.strict, "strict", false, "Write nothing if any row is rejected")
+	_ = prdImportCSVCmd.MarkFlagRequired("into")
+
+	prdImportCmd.AddCommand(prdImportCSVCmd)
+}
+
+func runPRDImportCSV(cmd *cobra.Command, args []string) error {
+	section, err := prd.ParseCSVSection(prdImportCSVFlags.section)
+	if err != nil {
+		return err
+	}
+	var doc prd.Document
+	src, err := readSourceDocument(prdImportCSVFlags.into, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("reading CSV file: %w", err)
+	}
+	defer f.Close()
+	report, err := doc.ImportCSV(f, section)
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", args[0], err)
+	}
+	fmt.Print(report)
+	if prdImportCSVFlags.strict && len(report.Rejected) > 0 {
+		return fmt.Errorf("%d row(s) rejected; %s not written", len(report.Rejected), prdImportCSVFlags.into)
+	}
+	if len(report.Added)+len(report.Updated) == 0 {
+		return nil
+	}
+
+	output := prdImportCSVFlags.output
+	if output == "" {
+		output = prdImportCSVFlags.into
+	}
+	if err := writeDocumentInPlace(output, src, &doc); err != nil {
+		return err
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Updated: %s\n", output)
+	return nil
+}
+
+var prdImportChecklistFlags struct {
+	into   string
+	output string
+}
+
+var prdImportChecklistCmd = &cobra.Command{
+	Use:   "checklist <features.md>",
+	Short: "Add draft requirements from a Markdown checklist",
+	Long: `Add the items of a Markdown checklist or bulleted feature list to the
+functional requirements of an existing PRD as drafts.
+
+Each top-level item becomes a requirement with the next free FR ID, and the
+items nested under it become its acceptance criteria. Text before a colon or
+" - " is the title and the rest the description. The nearest heading above
+an item becomes its category.
+
+Priorities are guessed from markers such as (P0), [must], or (high), or a
+leading "P1:": P0, critical, and high are must; P1 and medium should; P2 and
+low could; P3 and beyond won't. Items without a marker are set to should and
+listed, so they can be reviewed. Items whose title is already a functional
+requirement are skipped, so a list can be imported again as it grows.
+
+The PRD is written back in place, or to -o.`,
+	Example: `  splan requirements prd import checklist features.md --into checkout.prd.json
+  splan requirements prd import checklist backlog.md --into checkout.prd.json -o draft.prd.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDImportChecklist,
+}
+
+func init() {
+	prdImportChecklistCmd.Flags().StringVar(&prdImportChecklistFlags.into, "into", "", "PRD file to add the requirements to (required)")
+	prdImportChecklistCmd.Flags().StringVarP(&prdImportChecklistFlags.output, "output", "o", "", "Output PRD file (default: the --into file)")
+	_ = prdImportChecklistCmd.MarkFlagRequired("into")
+
+	prdImportCmd.AddCommand(prdImportChecklistCmd)
+}
+
+func runPRDImportChecklist(cmd *cobra.Command, args []string) error {
+	var doc prd.Document
+	src, err := readSourceDocument(prdImportChecklistFlags.into, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("reading checklist: %w", err)
+	}
+	defer f.Close()
+	report, err := doc.ImportChecklist(f)
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", args[0], err)
+	}
+	fmt.Print(report)
+	if len(report.Added) == 0 {
+		return nil
+	}
+
+	output := prdImportChecklistFlags.output
+	if output == "" {
+		output = prdImportChecklistFlags.into
+	}
+	if err := writeDocumentInPlace(output, src, &doc); err != nil {
+		return err
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Updated: %s\n", output)
+	return nil
+}
+
+// importOptions parses --map values of the form HEADING=SECTION.
+func importOptions(mapping []string) (prd.ImportOptions, error) {
+	opts := prd.ImportOptions{Mapping: map[string]prd.ImportTarget{}}
+	for _, m := range mapping {
+		heading, target, ok := strings.Cut(m, "=")
+		if !ok || strings.TrimSpace(heading) == "" {
+			return opts, fmt.Errorf("invalid --map %q (expected HEADING=SECTION)", m)
+		}
+		t, err := parseImportTarget(strings.TrimSpace(target))
+		if err != nil {
+			return opts, err
+		}
+		opts.Mapping[strings.TrimSpace(heading)] = t
+	}
+	return opts, nil
+}
+
+func parseImportTarget(s string) (prd.ImportTarget, error) {
+	targets := prd.ImportTargets()
+	for _, t := range targets {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = string(t)
+	}
+	return "", fmt.Errorf("unknown section: %s (expected one of %s)", s, strings.Join(names, ", "))
+}
+
+// promptImportTarget returns an import resolver that asks on in which
+// section to map a heading to. The choices are the matching sections, or
+// every section when none match; an empty answer keeps the default.
+func promptImportTarget(in *bufio.Reader, out io.Writer) func(string, []prd.ImportTarget) prd.ImportTarget {
+	return func(heading string, candidates []prd.ImportTarget) prd.ImportTarget {
+		choices := candidates
+		if len(choices) == 0 {
+			fmt.Fprintf(out, "\n%q does not match a PRD section.\n", heading)
+			choices = prd.ImportTargets()
+		} else {
+			fmt.Fprintf(out, "\n%q matches more than one PRD section.\n", heading)
+			choices = append(slices.Clone(choices), prd.ImportCustom)
+		}
+		for i, c := range choices {
+			fmt.Fprintf(out, "  %d) %s\n", i+1, c)
+		}
+		def := prd.ImportCustom
+		if len(candidates) > 0 {
+			def = candidates[0]
+		}
+		for {
+			fmt.Fprintf(out, "Section [%s]: ", def)
+			line, err := in.ReadString('\n')
+			answer := strings.TrimSpace(line)
+			if answer == "" {
+				return def
+			}
+			if n, atoiErr := strconv.Atoi(answer); atoiErr == nil && n >= 1 && n <= len(choices) {
+				return choices[n-1]
+			}
+			if t, parseErr := parseImportTarget(answer); parseErr == nil {
+				return t
+			}
+			fmt.Fprintf(out, "Enter a number from 1 to %d or a section name.\n", len(choices))
+			if err != nil {
+				return def
+			}
+		}
+	}
+}
+
+// ============================================================================
+// MRD Commands
+// ============================================================================
+
+var mrdCmd = &cobra.Command{
+	Use:   "mrd",
+	Short: "Work with Market Requirements Documents",
+	Long:  `Commands for generating and validating Market Requirements Documents (MRD).`,
+}
+
+var mrdGenerateFlags generateFlags
+
+var mrdGenerateCmd = &cobra.Command{
+	Use:   "generate <input.json>",
+	Short: "Convert MRD JSON to markdown",
+	Long: `Generate markdown from a Market Requirements Document (MRD).
+
+The output includes YAML frontmatter compatible with Pandoc for PDF generation.
+By default, the output file has the same name as the input with a .md extension.`,
+	Example: `  splan requirements mrd generate market-analysis.mrd.json
+  splan requirements mrd generate market.json -o output.md
+  splan requirements mrd generate market.json --no-frontmatter`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMRDGenerate,
+}
+
+var mrdValidateCmd = &cobra.Command{
+	Use:   "validate <input.json>...",
+	Short: "Validate MRD structure",
+	Long: `Validate a Market Requirements Document by parsing it and checking required fields.
+
+Duplicate and conflicting IDs are errors; --fix renumbers them and writes the
+document back before validating it. References to IDs that are not defined
+in the document, such as relatedIds, are errors too.`,
+	Example: `  splan requirements mrd validate market-analysis.mrd.json
+  splan requirements mrd validate docs/
+  splan requirements mrd validate market-analysis.mrd.json --fix`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMRDValidate,
+}
+
+func init() {
+	// MRD generate flags
+	mrdGenerateCmd.Flags().StringVarP(&mrdGenerateFlags.output, "output", "o", "", "Output markdown file path (default: input with .md extension)")
+	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.margin, "margin", "2cm", "Page margin for Pandoc")
+	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.mainFont, "mainfont", "Helvetica", "Main font family")
+	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.sansFont, "sansfont", "Helvetica", "Sans-serif font family")
+	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.monoFont, "monofont", "Courier New", "Monospace font family")
+	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.fontFamily, "fontfamily", "helvet", "LaTeX font family")
+	mrdGenerateCmd.Flags().BoolVar(&mrdGenerateFlags.noFrontmatter, "no-frontmatter", false, "Disable YAML frontmatter generation")
+	mrdGenerateCmd.Flags().BoolVar(&mrdGenerateFlags.noGlossaryLinks, "no-glossary-links", false, "Do not link the first use of each glossary term to its entry")
+
+	mrdCmd.AddCommand(mrdGenerateCmd)
+	mrdCmd.AddCommand(mrdValidateCmd)
+}
+
+func runMRDGenerate(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	// Determine output file
+	output := mrdGenerateFlags.output
+	if output == "" {
+		output = deriveOutputPath(inputFile)
+	}
+
+	// Read input file
+	var doc mrd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return err
+	}
+	fonts, err := generateFonts(cmd, inputFile, &mrdGenerateFlags)
+	if err != nil {
+		return err
+	}
+
+	linkGlossary := !mrdGenerateFlags.noGlossaryLinks
+	opts := mrd.MarkdownOptions{
+		IncludeFrontmatter: !mrdGenerateFlags.noFrontmatter,
+		LinkGlossary:       &linkGlossary,
+		Margin:             mrdGenerateFlags.margin,
+		MainFont:           fonts.MainFont,
+		SansFont:           fonts.SansFont,
+		MonoFont:           fonts.MonoFont,
+		FontFamily:         fonts.FontFamily,
+	}
+	markdown := doc.ToMarkdown(opts)
+
+	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+func runMRDValidate(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("mrd"), validateMRDFile)
+}
+
+func validateMRDFile(inputFile string, stdout, stderr io.Writer) error {
+	if validateFixFlags.fix {
+		if err := fixDuplicateIDs(inputFile, &mrd.Document{}, stdout, stderr); err != nil {
+			return err
+		}
+	}
+	var doc mrd.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	var issues []validation.Issue
+
+	if doc.Metadata.ID == "" {
+		issues = append(issues, requiredIssue("/metadata/id", "metadata.id is required"))
+	}
+	if doc.Metadata.Title == "" {
+		issues = append(issues, requiredIssue("/metadata/title", "metadata.title is required"))
+	}
+	if doc.Metadata.Version == "" {
+		issues = append(issues, requiredIssue("/metadata/version", "metadata.version is required"))
+	}
+	if len(doc.Metadata.Authors) == 0 {
+		issues = append(issues, requiredIssue("/metadata/authors", "metadata.authors is required (at least one author)"))
+	}
+	if doc.ExecutiveSummary.MarketOpportunity == "" {
+		issues = append(issues, requiredIssue("/executiveSummary/marketOpportunity", "executive_summary.market_opportunity is required"))
+	}
+	if doc.ExecutiveSummary.ProposedOffering == "" {
+		issues = append(issues, requiredIssue("/executiveSummary/proposedOffering", "executive_summary.proposed_offering is required"))
+	}
+	if doc.MarketOverview.TAM.Value == "" {
+		issues = append(issues, requiredIssue("/marketOverview/tam/value", "market_overview.tam.value is required"))
+	}
+	if len(doc.TargetMarket.PrimarySegments) == 0 {
+		issues = append(issues, requiredIssue("/targetMarket/primarySegments", "target_market.primary_segments is required (at least one segment)"))
+	}
+	if len(doc.CompetitiveLandscape.Competitors) == 0 {
+		issues = append(issues, requiredIssue("/competitiveLandscape/competitors", "competitive_landscape.competitors is required (at least one competitor)"))
+	}
+	if len(doc.MarketRequirements) == 0 {
+		issues = append(issues, requiredIssue("/marketRequirements", "market_requirements is required (at least one requirement)"))
+	}
+	if doc.Positioning.Statement == "" {
+		issues = append(issues, requiredIssue("/positioning/statement", "positioning.statement is required"))
+	}
+	issues = append(issues, metadataIssues(
+		common.ValidateReviews(doc.Metadata.Status, doc.Metadata.Reviews, doc.Metadata.RequiredReviewRoles),
+		common.ValidateLifecycle(doc.Metadata.Lifecycle()))...)
+	idIs, err := idIssues(&doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, idIs...)
+	refIs, err := referenceIssues(inputFile, "mrd", &doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIs...)
+	warnings, err := sourceWarnings(inputFile)
+	if err != nil {
+		return err
+	}
+	recordIssues(inputFile, "mrd", append(issues, warnings...))
+
+	if len(issues) > 0 {
+		errors := issueMessages(issues)
+		fmt.Fprintf(stderr, "Validation failed for %s:\n", inputFile)
+		for _, e := range errors {
+			fmt.Fprintf(stderr, "  - %s\n", e)
+		}
+		for _, w := range issueMessages(warnings) {
+			fmt.Fprintf(stderr, "  - warning: %s\n", w)
+		}
+		recordErrors(inputFile, errors)
+		return fmt.Errorf("validation failed with %d errors", len(errors))
+	}
+
+	fmt.Fprintf(stdout, "Valid MRD: %s\n", inputFile)
+	fmt.Fprintf(stdout, "  Title: %s\n", doc.Metadata.Title)
+	fmt.Fprintf(stdout, "  Version: %s\n", doc.Metadata.Version)
+	fmt.Fprintf(stdout, "  TAM: %s\n", doc.MarketOverview.TAM.Value)
+	fmt.Fprintf(stdout, "  Primary Segments: %d\n", len(doc.TargetMarket.PrimarySegments))
+	fmt.Fprintf(stdout, "  Buyer Personas: %d\n", len(doc.TargetMarket.BuyerPersonas))
+	fmt.Fprintf(stdout, "  Competitors: %d\n", len(doc.CompetitiveLandscape.Competitors))
+	fmt.Fprintf(stdout, "  Market Requirements: %d\n", len(doc.MarketRequirements))
+	fmt.Fprintf(stdout, "  Success Metrics: %d\n", len(doc.SuccessMetrics))
+	for _, w := range issueMessages(warnings) {
+		fmt.Fprintf(stdout, "  Warning: %s\n", w)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// TRD Commands
+// ============================================================================
+
+var trdCmd = &cobra.Command{
+	Use:   "trd",
+	Short: "Work with Technical Requirements Documents",
+	Long:  `Commands for generating and validating Technical Requirements Documents (TRD).`,
+}
+
+var trdGenerateFlags generateFlags
+
+var trdGenerateCmd = &cobra.Command{
+	Use:   "generate <input.json>",
+	Short: "Convert TRD JSON to markdown",
+	Long: `Generate markdown from a Technical Requirements Document (TRD).
+
+The output includes YAML frontmatter compatible with Pandoc for PDF generation.
+By default, the output file has the same name as the input with a .md extension.`,
+	Example: `  splan requirements trd generate architecture.trd.json
+  splan requirements trd generate tech-spec.json -o output.md
+  splan requirements trd generate tech-spec.json --no-frontmatter
+  splan requirements trd generate tech-spec.json --redact -o external.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTRDGenerate,
+}
+
+var trdGenerateDiagramFlags struct {
+	output string
+	kind   string
+}
+
+var trdGenerateDiagramCmd = &cobra.Command{
+	Use:   "diagram <input.json>",
+	Short: "Generate a Mermaid diagram from a TRD",
+	Long: `Generate a Mermaid diagram from a Technical Requirements Document.
+
+Kinds:
+  components - The architecture components in a system box, with their
+               dependencies, data flows, and integrations (default)
+  erd        - The data model entities and relationships
+
+The diagram is written as Mermaid source, for a .mmd file or a Mermaid
+renderer. The generated markdown includes both diagrams.`,
+	Example: `  splan requirements trd generate diagram architecture.trd.json
+  splan requirements trd generate diagram architecture.trd.json -o components.mmd
+  splan requirements trd generate diagram architecture.trd.json --kind erd`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTRDGenerateDiagram,
+}
+
+var trdValidateFlags struct {
+	techPolicy string
+}
+
+var trdValidateCmd = &cobra.Command{
+	Use:   "validate <input.json>...",
+	Short: "Validate TRD structure",
+	Long: `Validate a Technical Requirements Document by parsing it and checking required fields.
+
+When a technology policy applies, the technology stack is checked against it.
+The policy is read from --tech-policy, or else from "technologyPolicy" in the
+workspace manifest (splan.workspace.json) found from the TRD's directory.
+Denied technologies and vendors, and technologies or vendors missing from a
+non-empty approved list, are errors. Versions older than a rule's minVersion
+or on its deprecatedVersions list are warnings. Each violation names the
+policy reference it breaks.
+
+Duplicate and conflicting IDs are errors; --fix renumbers them and writes the
+document back before validating it. References to IDs that are not defined
+in the document, such as relatedIds, are errors too.`,
+	Example: `  splan requirements trd validate architecture.trd.json
+  splan requirements trd validate docs/
+  splan requirements trd validate architecture.trd.json --fix
+  splan requirements trd validate architecture.trd.json --tech-policy tech-policy.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTRDValidate,
+}
+
+func init() {
+	// TRD generate flags
+	trdGenerateCmd.Flags().StringVarP(&trdGenerateFlags.output, "output", "o", "", "Output markdown file path (default: input with .md extension)")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.margin, "margin", "2cm", "Page margin for Pandoc")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.mainFont, "mainfont", "Helvetica", "Main font family")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.sansFont, "sansfont", "Helvetica", "Sans-serif font family")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.monoFont, "monofont", "Courier New", "Monospace font family")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.fontFamily, "fontfamily", "helvet", "LaTeX font family")
+	trdGenerateCmd.Flags().BoolVar(&trdGenerateFlags.noFrontmatter, "no-frontmatter", false, "Disable YAML frontmatter generation")
+	trdGenerateCmd.Flags().BoolVar(&trdGenerateFlags.noGlossaryLinks, "no-glossary-links", false, "Do not link the first use of each glossary term to its entry")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.assets, "assets", "", "Bundle local diagram files: copy (next to output) or inline (base64)")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.assetsDir, "assets-dir", assets.DefaultDir, "Asset directory relative to the output for --assets=copy")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.convertDiagrams, "convert-diagrams", "", "Export .drawio/.excalidraw sources to svg or png (implies --assets=copy)")
+	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.diagramEndpoint, "diagram-endpoint", "", "Kroki-compatible export server URL, used instead of local CLIs")
+	trdGenerateCmd.Flags().BoolVar(&trdGenerateFlags.redact, "redact", false, "Mask the sources and defaults of secret configuration entries for external sharing")
+
+	trdValidateCmd.Flags().StringVar(&trdValidateFlags.techPolicy, "tech-policy", "", "Technology policy file (JSON or YAML) to check the technology stack against (default: the workspace manifest's)")
+
+	trdGenerateDiagramCmd.Flags().StringVarP(&trdGenerateDiagramFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	trdGenerateDiagramCmd.Flags().StringVar(&trdGenerateDiagramFlags.kind, "kind", "components", "Diagram kind: components or erd")
+
+	trdGenerateCmd.AddCommand(trdGenerateDiagramCmd)
+	trdCmd.AddCommand(trdGenerateCmd)
+	trdCmd.AddCommand(trdValidateCmd)
+}
+
+var trdCheckFlags struct {
+	json     bool
+	minScore float64
+}
+
+var trdCheckCmd = &cobra.Command{
+	Use:   "check <input.json>...",
+	Short: "Check TRD operational readiness",
+	Long: `Check whether the system described by a TRD is ready to operate.
+
+The operationalReadiness section is scored against a checklist:
+  - An on-call owner and an escalation path
+  - At least one alert, each linked to an incident playbook
+  - At least one dashboard
+  - A capacity plan stating peak load and headroom or a scaling trigger
+  - At least one incident playbook with response steps
+
+The readiness score is the share of checks passed. The check fails when the
+score is below --min-score, so it can gate a launch on operability as well
+as architecture.
+
+Given a directory, a glob such as "docs/**/*.trd.json", or several files,
+every matching document is checked in parallel, followed by a pass/fail
+summary.`,
+	Example: `  splan requirements trd check architecture.trd.json
+  splan requirements trd check architecture.trd.json --min-score 100
+  splan requirements trd check docs/ --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTRDCheck,
+}
+
+func init() {
+	trdCheckCmd.Flags().BoolVar(&trdCheckFlags.json, "json", false, "Output report as JSON")
+	trdCheckCmd.Flags().Float64Var(&trdCheckFlags.minScore, "min-score", 60, "Minimum readiness score (0-100) to pass")
+
+	trdCmd.AddCommand(trdCheckCmd)
+}
+
+var trdAPIDiffFlags struct {
+	output         string
+	format         string
+	failOnBreaking bool
+}
+
+var trdAPIDiffCmd = &cobra.Command{
+	Use:   "api-diff <old.trd.json> <new.trd.json>",
+	Short: "Report API changes between two TRD versions for consumers",
+	Long: `Compare the API specifications of two versions of a TRD endpoint by
+endpoint, and classify each change as breaking or non-breaking for the
+teams that consume the APIs.
+
+APIs are matched by ID and endpoints by method and path. Breaking changes
+are removed APIs and endpoints, changed request or response schemas, and a
+changed base URL, authentication, protocol, or major version. Added APIs,
+endpoints, and error codes and changed descriptions are non-breaking.
+
+The report is printed as markdown unless --format json is given. Without
+--format, the format is inferred from the output file extension. With
+--fail-on-breaking, the command fails when there are breaking changes, so
+it can gate API reviews in CI.`,
+	Example: `  splan requirements trd api-diff v1.trd.json v2.trd.json
+  splan requirements trd api-diff v1.trd.json v2.trd.json -o API-CHANGES.md
+  splan requirements trd api-diff v1.trd.json v2.trd.json --format json --fail-on-breaking`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTRDAPIDiff,
+}
+
+func init() {
+	trdAPIDiffCmd.Flags().StringVarP(&trdAPIDiffFlags.output, "output", "o", "", "Output file (default: stdout)")
+	trdAPIDiffCmd.Flags().StringVar(&trdAPIDiffFlags.format, "format", "", "Output format: markdown, json (default: from output extension)")
+	trdAPIDiffCmd.Flags().BoolVar(&trdAPIDiffFlags.failOnBreaking, "fail-on-breaking", false, "Exit with an error when there are breaking changes")
+
+	trdCmd.AddCommand(trdAPIDiffCmd)
+}
+
+func runTRDAPIDiff(cmd *cobra.Command, args []string) error {
+	docs := make([]*trd.Document, len(args))
+	for i, path := range args {
+		var doc trd.Document
+		if err := readDocument(path, &doc); err != nil {
+			return err
+		}
+		docs[i] = &doc
+	}
+	report := diff.TRDAPI(docs[0], docs[1])
+
+	format := strings.ToLower(trdAPIDiffFlags.format)
+	if format == "" {
+		format = "markdown"
+		if strings.ToLower(filepath.Ext(trdAPIDiffFlags.output)) == ".json" {
+			format = "json"
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		buf.WriteString(report.ToMarkdown())
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling API diff: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json)", trdAPIDiffFlags.format)
+	}
+
+	if trdAPIDiffFlags.output == "" {
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	} else {
+		if err := os.WriteFile(trdAPIDiffFlags.output, buf.Bytes(), 0600); err != nil {
+			return fmt.Errorf("writing output file: %w", err)
+		}
+		fmt.Printf("Generated: %s (%d breaking, %d non-breaking)\n",
+			trdAPIDiffFlags.output, report.Count(diff.Breaking), report.Count(diff.NonBreaking))
+	}
+	if trdAPIDiffFlags.failOnBreaking && report.Breaking() {
+		return fmt.Errorf("%d breaking API changes", report.Count(diff.Breaking))
+	}
+	return nil
+}
+
+var trdBudgetFlags struct {
+	budgetFlags
+	prd string
+}
+
+var trdBudgetCmd = &cobra.Command{
+	Use:   "budget <input.json>",
+	Short: "Roll up component and technology cost estimates",
+	Long: `Roll up the cost estimates on components and technology stack entries
+into a budget for finance reviews.
+
+A TRD has no roadmap, so costs are unscheduled unless --prd names the
+companion PRD, whose roadmap phases are used to resolve each cost's phaseId
+and to total costs by phase and calendar quarter.
+
+The report is written to stdout as markdown unless --output is given. The
+output format is taken from --format, or inferred from the output file
+extension (.csv for CSV, .json for JSON, markdown otherwise).`,
+	Example: `  splan requirements trd budget architecture.trd.json
+  splan requirements trd budget architecture.trd.json --prd my-product.prd.json -o budget.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTRDBudget,
+}
+
+func init() {
+	trdBudgetCmd.Flags().StringVarP(&trdBudgetFlags.output, "output", "o", "", "Output file (default: stdout)")
+	trdBudgetCmd.Flags().StringVar(&trdBudgetFlags.format, "format", "", "Output format: markdown, csv, json (default: from output extension)")
+	trdBudgetCmd.Flags().StringVar(&trdBudgetFlags.prd, "prd", "", "PRD whose roadmap phases schedule the costs")
+
+	trdCmd.AddCommand(trdBudgetCmd)
+}
+
+var trdScaffoldFlags struct {
+	from   string
+	output string
+}
+
+var trdScaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Create a skeleton TRD from a PRD",
+	Long: `Create a skeleton TRD pre-populated from a PRD.
+
+The technical architecture overview, diagrams, integration points, and
+technology stack; performance, scalability, and availability NFRs; the
+security model; and assumptions, constraints, and external dependencies are
+carried over. Required fields the PRD cannot supply are filled with "TODO:"
+placeholders, which are listed when the file is written.
+
+By default the TRD is written next to the PRD, with .prd replaced by .trd in
+the file name. Existing files are not overwritten.`,
+	Example: `  splan requirements trd scaffold --from myproduct.prd.json
+  splan requirements trd scaffold --from myproduct.prd.json -o design/myproduct.trd.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runTRDScaffold,
+}
+
+func init() {
+	trdScaffoldCmd.Flags().StringVar(&trdScaffoldFlags.from, "from", "", "PRD file to scaffold from")
+	trdScaffoldCmd.Flags().StringVarP(&trdScaffoldFlags.output, "output", "o", "", "Output TRD file (default: PRD path with .prd replaced by .trd)")
+	_ = trdScaffoldCmd.MarkFlagRequired("from")
+
+	trdCmd.AddCommand(trdScaffoldCmd)
+}
+
+func runTRDScaffold(cmd *cobra.Command, args []string) error {
+	var p prd.Document
+	if err := readDocument(trdScaffoldFlags.from, &p); err != nil {
+		return err
+	}
+
+	output := trdScaffoldFlags.output
+	if output == "" {
+		output = scaffoldOutputPath(trdScaffoldFlags.from, ".prd", ".trd")
+	}
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", output)
+	}
+
+	doc, todos := trd.ScaffoldFromPRD(&p)
+	if rel, err := filepath.Rel(filepath.Dir(output), trdScaffoldFlags.from); err == nil {
+		doc.Metadata.RelatedDocuments[0].URL = filepath.ToSlash(rel)
+	}
+
+	data, err := marshalDocument(doc, yamlconv.FormatFromPath(output))
+	if err != nil {
+		return fmt.Errorf("marshaling TRD: %w", err)
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("Created: %s\n", output)
+	if len(todos) > 0 {
+		fmt.Printf("\n%d section(s) need input (search for %q):\n", len(todos), trd.TODOPrefix)
+		for _, path := range todos {
+			fmt.Printf("  - %s\n", path)
+		}
+	}
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Replace the TODO placeholders and add your components")
+	fmt.Println("  2. Run 'splan requirements trd validate " + output + "' to check the TRD")
+	fmt.Println("  3. Run 'splan requirements trd generate " + output + "' to create markdown")
+	return nil
+}
+
+// scaffoldOutputPath derives the path of a downstream document from its
+// source, e.g. "myproduct.prd.json" with from ".prd" and to ".trd" becomes
+// "myproduct.trd.json".
+func scaffoldOutputPath(srcPath, from, to string) string {
+	ext := filepath.Ext(srcPath)
+	base := strings.TrimSuffix(strings.TrimSuffix(srcPath, ext), from)
+	return base + to + ext
+}
+
+func runTRDBudget(cmd *cobra.Command, args []string) error {
+	var doc trd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+
+	var phases []roadmap.Phase
+	if trdBudgetFlags.prd != "" {
+		var p prd.Document
+		if err := readDocument(trdBudgetFlags.prd, &p); err != nil {
+			return err
+		}
+		phases = p.Roadmap.Phases
+	}
+	return writeBudget(doc.Budget(phases), doc.Metadata.Title, &trdBudgetFlags.budgetFlags)
+}
+
+func runTRDGenerate(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+
+	// Determine output file
+	output := trdGenerateFlags.output
+	if output == "" {
+		output = deriveOutputPath(inputFile)
+	}
+
+	// Read input file
+	var doc trd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return err
+	}
+	if err := redactTRD(&doc, trdGenerateFlags.redact, inputFile); err != nil {
+		return err
+	}
+	fonts, err := generateFonts(cmd, inputFile, &trdGenerateFlags)
+	if err != nil {
+		return err
+	}
+
+	if err := bundleAssets(doc.AssetRefs(), inputFile, output, &trdGenerateFlags); err != nil {
+		return err
+	}
 
+	linkGlossary := !trdGenerateFlags.noGlossaryLinks
+	opts := trd.MarkdownOptions{
+		IncludeFrontmatter: !trdGenerateFlags.noFrontmatter,
+		LinkGlossary:       &linkGlossary,
+		Margin:             trdGenerateFlags.margin,
+		MainFont:           fonts.MainFont,
+		SansFont:           fonts.SansFont,
+		MonoFont:           fonts.MonoFont,
+		FontFamily:         fonts.FontFamily,
+	}
+	markdown := doc.ToMarkdown(opts)
+
+	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
 	return nil
 }
 
-func createNestedV2MOMTemplate(name string) *v2mom.V2MOM {
-	now := time.Now()
-	return &v2mom.V2MOM{
-		Schema: "../schema/v2mom.schema.json",
-		Metadata: &v2mom.Metadata{
-			Name:        name,
-			Author:      "Your Name",
-			Team:        "Your Team",
-			FiscalYear:  fmt.Sprintf("FY%d", now.Year()),
-			Quarter:     "Q1",
-			Version:     "1.0.0",
-			Status:      v2mom.StatusDraft,
-			Structure:   v2mom.StructureNested,
-			Terminology: v2mom.TerminologyV2MOM,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		},
-		Vision: "Describe your vision here - what do you want to achieve?",
-		Values: []v2mom.Value{
-			{
-				Name:        "Value 1",
-				Description: "What's most important to you?",
-				Priority:    1,
-			},
-			{
-				Name:        "Value 2",
-				Description: "What's the second most important principle?",
-				Priority:    2,
-			},
-		},
-		Methods: []v2mom.Method{
-			{
-				ID:          "method-1",
-				Name:        "First Method/Objective",
-				Description: "How will you achieve your vision? What's the first major initiative?",
-				Priority:    v2mom.PriorityP0,
-				Status:      "Not Started",
-				Measures: []v2mom.Measure{
-					{
-						ID:       "m1-kr1",
-						Name:     "Key Result 1",
-						Target:   "Define your target",
-						Status:   "Not Started",
-						Progress: 0,
-					},
-					{
-						ID:       "m1-kr2",
-						Name:     "Key Result 2",
-						Target:   "Define your target",
-						Status:   "Not Started",
-						Progress: 0,
-					},
-				},
-				Obstacles: []v2mom.Obstacle{
-					{
-						Name:       "Method-specific obstacle",
-						Severity:   "Medium",
-						Mitigation: "How will you address this?",
-					},
-				},
-			},
-			{
-				ID:          "method-2",
-				Name:        "Second Method/Objective",
-				Description: "What's the second major initiative?",
-				Priority:    v2mom.PriorityP1,
-				Status:      "Not Started",
-				Measures: []v2mom.Measure{
-					{
-						ID:       "m2-kr1",
-						Name:     "Key Result 1",
-						Target:   "Define your target",
-						Status:   "Not Started",
-						Progress: 0,
-					},
-				},
-			},
-		},
-		Obstacles: []v2mom.Obstacle{
-			{
-				ID:          "obs-global-1",
-				Name:        "Global obstacle",
-				Description: "What's preventing success across multiple methods?",
-				Severity:    "High",
-				Likelihood:  "Medium",
-				Mitigation:  "How will you mitigate this risk?",
-				Status:      "Identified",
-			},
-		},
+func runTRDCheck(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("trd"), checkTRDFile)
+}
+
+func checkTRDFile(inputFile string, stdout, stderr io.Writer) error {
+	var doc trd.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	report := doc.CheckReadiness()
+
+	if trdCheckFlags.json {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		fmt.Fprintln(stdout, string(output))
+	} else {
+		fmt.Fprint(stdout, report.FormatReport())
+	}
+
+	if report.Score < trdCheckFlags.minScore {
+		return fmt.Errorf("TRD readiness check failed (score %.1f%% below %.0f%%)", report.Score, trdCheckFlags.minScore)
+	}
+	return nil
+}
+
+func runTRDValidate(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("trd"), validateTRDFile)
+}
+
+func validateTRDFile(inputFile string, stdout, stderr io.Writer) error {
+	if validateFixFlags.fix {
+		if err := fixDuplicateIDs(inputFile, &trd.Document{}, stdout, stderr); err != nil {
+			return err
+		}
+	}
+	var doc trd.Document
+	if err := readDocumentTo(inputFile, &doc, stderr); err != nil {
+		return err
+	}
+
+	var issues []validation.Issue
+
+	if doc.Metadata.ID == "" {
+		issues = append(issues, requiredIssue("/metadata/id", "metadata.id is required"))
+	}
+	if doc.Metadata.Title == "" {
+		issues = append(issues, requiredIssue("/metadata/title", "metadata.title is required"))
+	}
+	if doc.Metadata.Version == "" {
+		issues = append(issues, requiredIssue("/metadata/version", "metadata.version is required"))
+	}
+	if len(doc.Metadata.Authors) == 0 {
+		issues = append(issues, requiredIssue("/metadata/authors", "metadata.authors is required (at least one author)"))
+	}
+	if doc.ExecutiveSummary.Purpose == "" {
+		issues = append(issues, requiredIssue("/executiveSummary/purpose", "executive_summary.purpose is required"))
+	}
+	if doc.ExecutiveSummary.Scope == "" {
+		issues = append(issues, requiredIssue("/executiveSummary/scope", "executive_summary.scope is required"))
+	}
+	if doc.Architecture.Overview == "" {
+		issues = append(issues, requiredIssue("/architecture/overview", "architecture.overview is required"))
+	}
+	if len(doc.Architecture.Components) == 0 {
+		issues = append(issues, requiredIssue("/architecture/components", "architecture.components is required (at least one component)"))
+	}
+	if doc.SecurityDesign.Overview == "" {
+		issues = append(issues, requiredIssue("/securityDesign/overview", "security_design.overview is required"))
+	}
+	if len(doc.Performance.Requirements) == 0 {
+		issues = append(issues, requiredIssue("/performance/requirements", "performance.requirements is required (at least one requirement)"))
+	}
+	if len(doc.Deployment.Environments) == 0 {
+		issues = append(issues, requiredIssue("/deployment/environments", "deployment.environments is required (at least one environment)"))
+	}
+	issues = append(issues, metadataIssues(
+		common.ValidateReviews(doc.Metadata.Status, doc.Metadata.Reviews, doc.Metadata.RequiredReviewRoles),
+		common.ValidateLifecycle(doc.Metadata.Lifecycle()))...)
+	for _, di := range doc.ValidateDataModel() {
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleLink,
+			Severity: validation.SeverityError,
+			Pointer:  validation.PathPointer(di.Field),
+			Message:  di.Field + ": " + di.Message,
+		})
+	}
+	for _, mi := range doc.ValidateMigrationPlan() {
+		issues = append(issues, pathIssue("trd", mi.Field, mi.Field+": "+mi.Message, true))
+	}
+	for _, ri := range doc.ValidateReadiness() {
+		issues = append(issues, pathIssue("trd", ri.Field, ri.Field+": "+ri.Message, true))
+	}
+	for _, ci := range doc.ValidateConfiguration() {
+		issues = append(issues, pathIssue("trd", ci.Field, ci.Field+": "+ci.Message, true))
+	}
+	idIs, err := idIssues(&doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, idIs...)
+	refIs, err := referenceIssues(inputFile, "trd", &doc, issues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIs...)
+	policy, err := trdTechnologyPolicy(inputFile, stderr)
+	if err != nil {
+		return err
+	}
+	warnings, err := sourceWarnings(inputFile)
+	if err != nil {
+		return err
+	}
+	for _, v := range doc.CheckTechnologyPolicy(policy) {
+		message := v.Field + ": " + v.Message
+		if v.Reference != "" {
+			message += " (policy " + v.Reference + ")"
+		}
+		is := pathIssue("trd", v.Field, message, v.IsError())
+		if v.IsError() {
+			issues = append(issues, is)
+		} else {
+			warnings = append(warnings, is)
+		}
+	}
+	recordIssues(inputFile, "trd", append(issues, warnings...))
+
+	if len(issues) > 0 {
+		errors := issueMessages(issues)
+		fmt.Fprintf(stderr, "Validation failed for %s:\n", inputFile)
+		for _, e := range errors {
+			fmt.Fprintf(stderr, "  - %s\n", e)
+		}
+		for _, w := range issueMessages(warnings) {
+			fmt.Fprintf(stderr, "  - warning: %s\n", w)
+		}
+		recordErrors(inputFile, errors)
+		return fmt.Errorf("validation failed with %d errors", len(errors))
+	}
+
+	fmt.Fprintf(stdout, "Valid TRD: %s\n", inputFile)
+	fmt.Fprintf(stdout, "  Title: %s\n", doc.Metadata.Title)
+	fmt.Fprintf(stdout, "  Version: %s\n", doc.Metadata.Version)
+	fmt.Fprintf(stdout, "  Components: %d\n", len(doc.Architecture.Components))
+	fmt.Fprintf(stdout, "  APIs: %d\n", len(doc.APISpecifications))
+	fmt.Fprintf(stdout, "  Performance Requirements: %d\n", len(doc.Performance.Requirements))
+	fmt.Fprintf(stdout, "  Environments: %d\n", len(doc.Deployment.Environments))
+	fmt.Fprintf(stdout, "  Integrations: %d\n", len(doc.Integration))
+	for _, w := range issueMessages(warnings) {
+		fmt.Fprintf(stdout, "  Warning: %s\n", w)
+	}
+
+	return nil
+}
+
+// trdTechnologyPolicy returns the technology policy TRDs are validated
+// against: the file given with --tech-policy, or else the policy of the
+// workspace manifest found from the TRD's directory. A --lenient recovery
+// report for the --tech-policy file is written to stderr.
+func trdTechnologyPolicy(inputFile string, stderr io.Writer) (*trd.TechnologyPolicy, error) {
+	if trdValidateFlags.techPolicy == "" {
+		return workspace.TechnologyPolicyFor(inputFile)
+	}
+	var policy trd.TechnologyPolicy
+	if _, err := readSourceDocument(trdValidateFlags.techPolicy, &policy, stderr); err != nil {
+		return nil, fmt.Errorf("reading technology policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// ============================================================================
+// Init Commands
+// ============================================================================
+
+// requirementsInitFlags are the flags of the PRD, MRD, and TRD init commands.
+type requirementsInitFlags struct {
+	title       string
+	output      string
+	interactive bool
+}
+
+var (
+	prdInitFlags requirementsInitFlags
+	mrdInitFlags requirementsInitFlags
+	trdInitFlags requirementsInitFlags
+)
+
+var prdInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a new PRD",
+	Long: `Create a new PRD that passes validation.
+
+With --interactive, a wizard asks for the title, authors, problem and
+solution, personas, the first objective and key result, and the first
+roadmap phase. Press Enter to accept a default or skip a question.
+
+Anything not answered, and everything without --interactive, is filled with
+a "TODO:" placeholder. By default the file is named after the title, as in
+checkout-redesign.prd.json. Existing files are not overwritten.`,
+	Example: `  splan requirements prd init --interactive
+  splan requirements prd init --title "Checkout Redesign"
+  splan requirements prd init -i -o plans/checkout.prd.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRequirementsInit("prd", prdInitFlags, func(p *wizard.Prompter, title string) (any, string) {
+			doc := wizard.PRD(p, title)
+			return doc, doc.Metadata.Title
+		})
+	},
+}
+
+var mrdInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a new MRD",
+	Long: `Create a new MRD that passes validation.
+
+With --interactive, a wizard asks for the title, authors, market opportunity
+and offering, market size, primary segments, competitors, the first market
+requirement, and positioning. Press Enter to accept a default or skip a
+question.
+
+Anything not answered, and everything without --interactive, is filled with
+a "TODO:" placeholder. By default the file is named after the title, as in
+agent-platform.mrd.json. Existing files are not overwritten.`,
+	Example: `  splan requirements mrd init --interactive
+  splan requirements mrd init --title "Agent Platform" -o agent-platform.mrd.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRequirementsInit("mrd", mrdInitFlags, func(p *wizard.Prompter, title string) (any, string) {
+			doc := wizard.MRD(p, title)
+			return doc, doc.Metadata.Title
+		})
+	},
+}
+
+var trdInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a new TRD",
+	Long: `Create a new TRD that passes validation.
+
+With --interactive, a wizard asks for the title, authors, purpose and scope,
+the architecture and its components, the security overview, the first
+performance requirement, and the deployment environments. Press Enter to
+accept a default or skip a question.
+
+Anything not answered, and everything without --interactive, is filled with
+a "TODO:" placeholder. By default the file is named after the title, as in
+order-service.trd.json. Existing files are not overwritten. To start from a
+PRD instead, use splan requirements trd scaffold.`,
+	Example: `  splan requirements trd init --interactive
+  splan requirements trd init --title "Order Service" -o design/orders.trd.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRequirementsInit("trd", trdInitFlags, func(p *wizard.Prompter, title string) (any, string) {
+			doc := wizard.TRD(p, title)
+			return doc, doc.Metadata.Title
+		})
+	},
+}
+
+func init() {
+	for _, c := range []struct {
+		cmd    *cobra.Command
+		flags  *requirementsInitFlags
+		parent *cobra.Command
+	}{
+		{prdInitCmd, &prdInitFlags, prdCmd},
+		{mrdInitCmd, &mrdInitFlags, mrdCmd},
+		{trdInitCmd, &trdInitFlags, trdCmd},
+	} {
+		c.cmd.Flags().StringVar(&c.flags.title, "title", "", "Document title (the wizard's default)")
+		c.cmd.Flags().StringVarP(&c.flags.output, "output", "o", "", "Output file (default: from the title)")
+		c.cmd.Flags().BoolVarP(&c.flags.interactive, "interactive", "i", false, "Ask for the document content")
+		c.parent.AddCommand(c.cmd)
+	}
+}
+
+// runRequirementsInit builds a document with build, asking on the terminal
+// with --interactive and taking every default otherwise, and writes it.
+func runRequirementsInit(kind string, flags requirementsInitFlags, build func(*wizard.Prompter, string) (any, string)) error {
+	if flags.output != "" && fileExists(flags.output) {
+		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", flags.output)
+	}
+
+	p := wizard.NewPrompter(strings.NewReader(""), io.Discard)
+	if flags.interactive {
+		fmt.Printf("Creating a %s. Press Enter to accept the default in brackets or to skip a question.\n", strings.ToUpper(kind))
+		p = wizard.NewPrompter(os.Stdin, os.Stdout)
+	}
+	doc, title := build(p, flags.title)
+
+	output := flags.output
+	if output == "" {
+		output = wizard.FileName(title, kind)
+		// Don't lose the answers to a name clash: ask for another name.
+		for flags.interactive && output != "" && fileExists(output) {
+			output = p.Ask(fmt.Sprintf("\n%s already exists. Output file", output), "")
+		}
+		if output == "" || fileExists(output) {
+			return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", wizard.FileName(title, kind))
+		}
+	}
+	data, err := marshalDocument(doc, yamlconv.FormatFromPath(output))
+	if err != nil {
+		return fmt.Errorf("marshaling %s: %w", strings.ToUpper(kind), err)
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+
+	fmt.Printf("\nCreated: %s\n", output)
+	if n := bytes.Count(data, []byte(wizard.TODOPrefix)); n > 0 {
+		fmt.Printf("  %d %q placeholder(s) to fill in\n", n, wizard.TODOPrefix)
+	}
+	fmt.Println("\nNext steps:")
+	fmt.Println("  1. Replace the placeholders and add requirements")
+	fmt.Printf("  2. Run 'splan requirements %s validate %s' to check the document\n", kind, output)
+	fmt.Printf("  3. Run 'splan requirements %s generate %s' to create markdown\n", kind, output)
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ============================================================================
+// HTML Commands
+// ============================================================================
+
+// htmlFlags holds flags shared by the generate html commands.
+type htmlFlags struct {
+	output          string
+	css             string
+	katexURL        string
+	comments        bool
+	redact          bool
+	noGlossaryLinks bool
+}
+
+var (
+	prdHTMLFlags   htmlFlags
+	mrdHTMLFlags   htmlFlags
+	trdHTMLFlags   htmlFlags
+	v2momHTMLFlags struct {
+		htmlFlags
+		terminology string
+	}
+)
+
+const htmlLong = `The page is a single self-contained file: styles are embedded, each
+section is collapsible and has an anchor link, and a print stylesheet expands
+every section and hides navigation. Use --css to append your own stylesheet.
+Pages with math load KaTeX from a CDN with pinned integrity hashes; use
+--katex-url to load it from a local copy of its dist directory instead.
+
+By default, the output file has the same name as the input with a .html extension.`
+
+var prdGenerateHTMLCmd = &cobra.Command{
+	Use:   "html <input.json>",
+	Short: "Convert PRD JSON to a standalone HTML page",
+	Long:  "Generate a standalone HTML page from a Product Requirements Document (PRD).\n\n" + htmlLong,
+	Example: `  splan requirements prd generate html myproduct.prd.json
+  splan requirements prd generate html myproduct.prd.json -o site/prd.html --css brand.css`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDGenerateHTML,
+}
+
+var mrdGenerateHTMLCmd = &cobra.Command{
+	Use:     "html <input.json>",
+	Short:   "Convert MRD JSON to a standalone HTML page",
+	Long:    "Generate a standalone HTML page from a Market Requirements Document (MRD).\n\n" + htmlLong,
+	Example: `  splan requirements mrd generate html market-analysis.mrd.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runMRDGenerateHTML,
+}
+
+var trdGenerateHTMLCmd = &cobra.Command{
+	Use:   "html <input.json>",
+	Short: "Convert TRD JSON to a standalone HTML page",
+	Long:  "Generate a standalone HTML page from a Technical Requirements Document (TRD).\n\n" + htmlLong,
+	Example: `  splan requirements trd generate html architecture.trd.json
+  splan requirements trd generate html architecture.trd.json --redact`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTRDGenerateHTML,
+}
+
+var v2momGenerateHTMLCmd = &cobra.Command{
+	Use:   "html FILE",
+	Short: "Generate a standalone HTML page",
+	Long:  "Generate a standalone HTML page from a V2MOM JSON file.\n\n" + htmlLong,
+	Example: `  splan goals v2mom generate html my-v2mom.json
+  splan goals v2mom generate html my-v2mom.json --terminology=okr`,
+	Args: cobra.ExactArgs(1),
+	RunE: runV2MOMGenerateHTML,
+}
+
+func init() {
+	for _, c := range []struct {
+		cmd    *cobra.Command
+		flags  *htmlFlags
+		parent *cobra.Command
+	}{
+		{prdGenerateHTMLCmd, &prdHTMLFlags, prdGenerateCmd},
+		{mrdGenerateHTMLCmd, &mrdHTMLFlags, mrdGenerateCmd},
+		{trdGenerateHTMLCmd, &trdHTMLFlags, trdGenerateCmd},
+		{v2momGenerateHTMLCmd, &v2momHTMLFlags.htmlFlags, v2momGenerateCmd},
+	} {
+		c.cmd.Flags().StringVarP(&c.flags.output, "output", "o", "", "Output HTML file path (default: input with .html extension)")
+		c.cmd.Flags().StringVar(&c.flags.css, "css", "", "CSS file to embed after the built-in styles")
+		c.cmd.Flags().StringVar(&c.flags.katexURL, "katex-url", "", "Base URL of a local KaTeX dist directory for math (default: jsdelivr CDN)")
+		c.parent.AddCommand(c.cmd)
 	}
+	prdGenerateHTMLCmd.Flags().BoolVar(&prdHTMLFlags.comments, "comments", false, "Show unresolved review comments as margin notes")
+	mrdGenerateHTMLCmd.Flags().BoolVar(&mrdHTMLFlags.comments, "comments", false, "Show unresolved review comments as margin notes")
+	trdGenerateHTMLCmd.Flags().BoolVar(&trdHTMLFlags.comments, "comments", false, "Show unresolved review comments as margin notes")
+	for _, c := range []struct {
+		cmd   *cobra.Command
+		flags *htmlFlags
+	}{{prdGenerateHTMLCmd, &prdHTMLFlags}, {mrdGenerateHTMLCmd, &mrdHTMLFlags}, {trdGenerateHTMLCmd, &trdHTMLFlags}} {
+		c.cmd.Flags().BoolVar(&c.flags.noGlossaryLinks, "no-glossary-links", false, "Do not link the first use of each glossary term to its entry")
+	}
+	trdGenerateHTMLCmd.Flags().BoolVar(&trdHTMLFlags.redact, "redact", false, "Mask the sources and defaults of secret configuration entries for external sharing")
+	v2momGenerateHTMLCmd.Flags().StringVar(&v2momHTMLFlags.terminology, "terminology", "", "Display terminology (v2mom, okr, hybrid)")
 }
 
-func createFlatV2MOMTemplate(name string) *v2mom.V2MOM {
-	now := time.Now()
-	return &v2mom.V2MOM{
-		Schema: "../schema/v2mom.schema.json",
-		Metadata: &v2mom.Metadata{
-			Name:        name,
-			Author:      "Your Name",
-			Team:        "Your Team",
-			FiscalYear:  fmt.Sprintf("FY%d", now.Year()),
-			Quarter:     "Q1",
-			Version:     "1.0.0",
-			Status:      v2mom.StatusDraft,
-			Structure:   v2mom.StructureFlat,
-			Terminology: v2mom.TerminologyV2MOM,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		},
-		Vision: "Describe your vision here - what do you want to achieve?",
-		Values: []v2mom.Value{
-			{
-				Name:        "Value 1",
-				Description: "What's most important to you?",
-				Priority:    1,
-			},
-			{
-				Name:        "Value 2",
-				Description: "What's the second most important principle?",
-				Priority:    2,
-			},
-		},
-		Methods: []v2mom.Method{
-			{
-				Name:        "First Method",
-				Description: "How will you achieve your vision?",
-				Priority:    v2mom.PriorityP0,
-			},
-			{
-				Name:        "Second Method",
-				Description: "What's the second major action?",
-				Priority:    v2mom.PriorityP1,
-			},
-		},
-		Obstacles: []v2mom.Obstacle{
-			{
-				Name:        "Obstacle 1",
-				Description: "What's preventing success?",
-				Severity:    "High",
-				Mitigation:  "How will you address this?",
-			},
-		},
-		Measures: []v2mom.Measure{
-			{
-				Name:   "Measure 1",
-				Target: "Define your target",
-				Status: "Not Started",
-			},
-			{
-				Name:   "Measure 2",
-				Target: "Define your target",
-				Status: "Not Started",
-			},
-		},
+func runPRDGenerateHTML(cmd *cobra.Command, args []string) error {
+	rp, err := renderProfile(args[0])
+	if err != nil {
+		return err
+	}
+	var doc prd.Document
+	if err := readProfiledDocument(args[0], &doc, rp, os.Stderr); err != nil {
+		return err
 	}
+	linkGlossary := !prdHTMLFlags.noGlossaryLinks
+	opts := render.Options{IncludeComments: prdHTMLFlags.comments, LinkGlossary: &linkGlossary, KaTeXURL: prdHTMLFlags.katexURL}
+	if opts.CustomCSS, err = prdHTMLFlags.customCSS(); err != nil {
+		return err
+	}
+	output, err := render.Default.Render("prd", render.FormatHTML, &doc, opts)
+	if err != nil {
+		return err
+	}
+	links, err := workspaceLinks(args[0])
+	if err != nil {
+		return err
+	}
+	if links != nil {
+		output = links.HTML(output, htmlOutputPath(args[0], &prdHTMLFlags))
+	}
+	return writeHTML(args[0], &prdHTMLFlags, output)
 }
 
-func createHybridV2MOMTemplate(name string) *v2mom.V2MOM {
-	now := time.Now()
-	return &v2mom.V2MOM{
-		Schema: "../schema/v2mom.schema.json",
-		Metadata: &v2mom.Metadata{
-			Name:        name,
-			Author:      "Your Name",
-			Team:        "Your Team",
-			FiscalYear:  fmt.Sprintf("FY%d", now.Year()),
-			Quarter:     "Q1",
-			Version:     "1.0.0",
-			Status:      v2mom.StatusDraft,
-			Structure:   v2mom.StructureHybrid,
-			Terminology: v2mom.TerminologyV2MOM,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		},
-		Vision: "Describe your vision here - what do you want to achieve?",
-		Values: []v2mom.Value{
-			{
-				Name:        "Value 1",
-				Description: "What's most important to you?",
-				Priority:    1,
-			},
-		},
-		Methods: []v2mom.Method{
-			{
-				ID:          "method-1",
-				Name:        "Method with nested measures",
-				Description: "This method has its own key results",
-				Priority:    v2mom.PriorityP0,
-				Status:      "Not Started",
-				Measures: []v2mom.Measure{
-					{
-						Name:   "Method-specific KR",
-						Target: "Define target",
-					},
-				},
-			},
-			{
-				ID:          "method-2",
-				Name:        "Method without nested measures",
-				Description: "This method uses global measures",
-				Priority:    v2mom.PriorityP1,
-			},
-		},
-		Obstacles: []v2mom.Obstacle{
-			{
-				Name:        "Global obstacle",
-				Description: "Affects multiple methods",
-				Severity:    "High",
-				Mitigation:  "Mitigation strategy",
-			},
-		},
-		Measures: []v2mom.Measure{
-			{
-				Name:        "North star metric",
-				Description: "Global measure spanning all methods",
-				Target:      "Define target",
-			},
-		},
+func runMRDGenerateHTML(cmd *cobra.Command, args []string) error {
+	var doc mrd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	css, err := mrdHTMLFlags.customCSS()
+	if err != nil {
+		return err
+	}
+	linkGlossary := !mrdHTMLFlags.noGlossaryLinks
+	output, err := render.Default.Render("mrd", render.FormatHTML, &doc, render.Options{CustomCSS: css, KaTeXURL: mrdHTMLFlags.katexURL, IncludeComments: mrdHTMLFlags.comments, LinkGlossary: &linkGlossary})
+	if err != nil {
+		return err
+	}
+	return writeHTML(args[0], &mrdHTMLFlags, output)
+}
+
+func runTRDGenerateDiagram(cmd *cobra.Command, args []string) error {
+	var doc trd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+
+	var diagram string
+	switch trdGenerateDiagramFlags.kind {
+	case "components":
+		diagram = doc.Architecture.MermaidComponents(doc.Metadata.Title, doc.Integration)
+		if diagram == "" {
+			return fmt.Errorf("%s has no architecture components", args[0])
+		}
+	case "erd":
+		if doc.DataModel == nil || len(doc.DataModel.Entities) == 0 {
+			return fmt.Errorf("%s has no data model entities", args[0])
+		}
+		diagram = doc.DataModel.MermaidERD()
+	default:
+		return fmt.Errorf("invalid --kind %q: must be components or erd", trdGenerateDiagramFlags.kind)
+	}
+
+	output := trdGenerateDiagramFlags.output
+	if output == "" {
+		fmt.Print(diagram)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(diagram), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+func runTRDGenerateHTML(cmd *cobra.Command, args []string) error {
+	var doc trd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	if err := redactTRD(&doc, trdHTMLFlags.redact, args[0]); err != nil {
+		return err
+	}
+	css, err := trdHTMLFlags.customCSS()
+	if err != nil {
+		return err
+	}
+	linkGlossary := !trdHTMLFlags.noGlossaryLinks
+	output, err := render.Default.Render("trd", render.FormatHTML, &doc, render.Options{CustomCSS: css, KaTeXURL: trdHTMLFlags.katexURL, IncludeComments: trdHTMLFlags.comments, LinkGlossary: &linkGlossary})
+	if err != nil {
+		return err
+	}
+	return writeHTML(args[0], &trdHTMLFlags, output)
+}
+
+func runV2MOMGenerateHTML(cmd *cobra.Command, args []string) error {
+	v, err := readV2MOMFile(args[0], os.Stderr)
+	if err != nil {
+		return fmt.Errorf("reading V2MOM: %w", err)
+	}
+	opts := v2momrender.DefaultOptions()
+	opts.Terminology = v2momHTMLFlags.terminology
+	opts.KaTeXURL = v2momHTMLFlags.katexURL
+	if opts.CustomCSS, err = v2momHTMLFlags.customCSS(); err != nil {
+		return err
+	}
+	output, err := v2momhtml.New().Render(v, opts)
+	if err != nil {
+		return err
+	}
+	return writeHTML(args[0], &v2momHTMLFlags.htmlFlags, output)
+}
+
+// customCSS returns the contents of the --css file, or "" when it is unset.
+func (f *htmlFlags) customCSS() (string, error) {
+	if f.css == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(f.css)
+	if err != nil {
+		return "", fmt.Errorf("reading CSS file: %w", err)
+	}
+	return string(data), nil
+}
+
+// writeHTML writes a rendered page to --output, or next to the input file.
+func writeHTML(inputFile string, flags *htmlFlags, data []byte) error {
+	output := htmlOutputPath(inputFile, flags)
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+// htmlOutputPath returns the --output flag, defaulting to inputFile with a
+// .html extension.
+func htmlOutputPath(inputFile string, flags *htmlFlags) string {
+	if flags.output != "" {
+		return flags.output
 	}
+	return deriveOutputPathExt(inputFile, ".html")
 }
 
 // ============================================================================
-// OKR Commands
+// DOCX Commands
 // ============================================================================
 
-var okrCmd = &cobra.Command{
-	Use:   "okr",
-	Short: "Work with OKR documents",
-	Long: `Commands for generating and validating OKR (Objectives and Key Results) documents.
+// docxFlags holds flags shared by the generate docx commands.
+type docxFlags struct {
+	output   string
+	template string
+	redact   bool
+}
 
-OKR is a goal-setting framework that helps teams define objectives and track
-measurable key results. Originally developed at Intel and popularized by Google.`,
+var (
+	prdDOCXFlags docxFlags
+	mrdDOCXFlags docxFlags
+	trdDOCXFlags docxFlags
+)
+
+const docxLong = `Sections become Heading 1 and subheadings Heading 2, lists use List Bullet,
+and tables use Table Grid with a header row that repeats across pages, so the
+document picks up the look of your Word styles.
+
+Use --template with a reference .docx (for example your corporate template) to
+take its styles, theme, fonts, and page size and margins. Styles the output
+needs but the template lacks are added with built-in defaults.
+
+By default, the output file has the same name as the input with a .docx extension.`
+
+var prdGenerateDOCXCmd = &cobra.Command{
+	Use:   "docx <input.json>",
+	Short: "Convert PRD JSON to a Word document",
+	Long:  "Generate a Word (.docx) document from a Product Requirements Document (PRD).\n\n" + docxLong,
+	Example: `  splan requirements prd generate docx myproduct.prd.json
+  splan requirements prd generate docx myproduct.prd.json --template corporate.docx -o prd.docx`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDGenerateDOCX,
 }
 
-var okrValidateFlags struct {
-	strict bool
+var mrdGenerateDOCXCmd = &cobra.Command{
+	Use:     "docx <input.json>",
+	Short:   "Convert MRD JSON to a Word document",
+	Long:    "Generate a Word (.docx) document from a Market Requirements Document (MRD).\n\n" + docxLong,
+	Example: `  splan requirements mrd generate docx market-analysis.mrd.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runMRDGenerateDOCX,
 }
 
-var okrValidateCmd = &cobra.Command{
-	Use:   "validate FILE",
-	Short: "Validate an OKR JSON file",
-	Long: `Validate an OKR JSON file against the schema and structural rules.
+var trdGenerateDOCXCmd = &cobra.Command{
+	Use:     "docx <input.json>",
+	Short:   "Convert TRD JSON to a Word document",
+	Long:    "Generate a Word (.docx) document from a Technical Requirements Document (TRD).\n\n" + docxLong,
+	Example: `  splan requirements trd generate docx architecture.trd.json --template corporate.docx`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runTRDGenerateDOCX,
+}
 
-Examples:
-  splan goals okr validate my-okrs.json
-  splan goals okr validate my-okrs.json --strict`,
-	Args: cobra.ExactArgs(1),
-	RunE: runOKRValidate,
+func init() {
+	for _, c := range []struct {
+		cmd    *cobra.Command
+		flags  *docxFlags
+		parent *cobra.Command
+	}{
+		{prdGenerateDOCXCmd, &prdDOCXFlags, prdGenerateCmd},
+		{mrdGenerateDOCXCmd, &mrdDOCXFlags, mrdGenerateCmd},
+		{trdGenerateDOCXCmd, &trdDOCXFlags, trdGenerateCmd},
+	} {
+		c.cmd.Flags().StringVarP(&c.flags.output, "output", "o", "", "Output DOCX file path (default: input with .docx extension)")
+		c.cmd.Flags().StringVar(&c.flags.template, "template", "", "Reference .docx whose styles and page setup to use")
+		c.parent.AddCommand(c.cmd)
+	}
+	trdGenerateDOCXCmd.Flags().BoolVar(&trdDOCXFlags.redact, "redact", false, "Mask the sources and defaults of secret configuration entries for external sharing")
+}
+
+func runPRDGenerateDOCX(cmd *cobra.Command, args []string) error {
+	rp, err := renderProfile(args[0])
+	if err != nil {
+		return err
+	}
+	var doc prd.Document
+	if err := readProfiledDocument(args[0], &doc, rp, os.Stderr); err != nil {
+		return err
+	}
+	opts, err := prdDOCXFlags.options()
+	if err != nil {
+		return err
+	}
+	output, err := docx.New().RenderPRD(&doc, prdrender.DefaultOptions(), opts)
+	if err != nil {
+		return err
+	}
+	return writeDOCX(args[0], &prdDOCXFlags, output)
+}
+
+func runMRDGenerateDOCX(cmd *cobra.Command, args []string) error {
+	var doc mrd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	opts, err := mrdDOCXFlags.options()
+	if err != nil {
+		return err
+	}
+	output, err := docx.New().RenderMRD(&doc, opts)
+	if err != nil {
+		return err
+	}
+	return writeDOCX(args[0], &mrdDOCXFlags, output)
+}
+
+func runTRDGenerateDOCX(cmd *cobra.Command, args []string) error {
+	var doc trd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	if err := redactTRD(&doc, trdDOCXFlags.redact, args[0]); err != nil {
+		return err
+	}
+	opts, err := trdDOCXFlags.options()
+	if err != nil {
+		return err
+	}
+	output, err := docx.New().RenderTRD(&doc, opts)
+	if err != nil {
+		return err
+	}
+	return writeDOCX(args[0], &trdDOCXFlags, output)
+}
+
+// options returns the DOCX options, reading the --template file if set.
+func (f *docxFlags) options() (*docx.Options, error) {
+	if f.template == "" {
+		return &docx.Options{}, nil
+	}
+	data, err := os.ReadFile(f.template)
+	if err != nil {
+		return nil, fmt.Errorf("reading DOCX template: %w", err)
+	}
+	return &docx.Options{Template: data}, nil
+}
+
+// writeDOCX writes a rendered document to --output, or next to the input file.
+func writeDOCX(inputFile string, flags *docxFlags, data []byte) error {
+	output := flags.output
+	if output == "" {
+		output = deriveOutputPathExt(inputFile, ".docx")
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s\n", output)
+	return nil
 }
 
-var okrGenerateCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "Generate output from OKR",
-	Long:  `Generate various output formats from an OKR JSON file.`,
-}
+// ============================================================================
+// PRD Marp Command
+// ============================================================================
 
-var okrGenerateMarpFlags struct {
-	output string
-	theme  string
+var prdGenerateMarpFlags struct {
+	output   string
+	theme    string
+	sections []string
 }
 
-var okrGenerateMarpCmd = &cobra.Command{
-	Use:   "marp FILE",
+var prdGenerateMarpCmd = &cobra.Command{
+	Use:   "marp <input.json>",
 	Short: "Generate Marp markdown slides",
-	Long: `Generate Marp markdown presentation slides from an OKR JSON file.
+	Long: `Generate an executive Marp presentation from a PRD: the problem, solution,
+personas, OKRs, roadmap and its swimlane, and risks.
 
 Themes:
   default   - Clean gradient theme (default)
   corporate - Professional blue theme
   minimal   - Simple grayscale theme
 
-Examples:
-  splan goals okr generate marp my-okrs.json
-  splan goals okr generate marp my-okrs.json -o slides.md
-  splan goals okr generate marp my-okrs.json --theme=corporate`,
+Slides, in deck order, for --sections:
+  ` + strings.Join(prdmarp.Slides, ", ") + `
+
+Slides with nothing to show, such as risks for a PRD without risks, are
+left out.`,
+	Example: `  splan requirements prd generate marp checkout.prd.json -o checkout-slides.md
+  splan requirements prd generate marp checkout.prd.json --theme corporate
+  splan requirements prd generate marp checkout.prd.json --sections problem,solution,swimlane,risks`,
 	Args: cobra.ExactArgs(1),
-	RunE: runOKRMarpGenerate,
+	RunE: runPRDGenerateMarp,
 }
 
-var okrInitFlags struct {
-	name   string
-	output string
-	period string
+func init() {
+	prdGenerateMarpCmd.Flags().StringVarP(&prdGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	prdGenerateMarpCmd.Flags().StringVar(&prdGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
+	prdGenerateMarpCmd.Flags().StringSliceVar(&prdGenerateMarpFlags.sections, "sections", nil, "Slides to include, in deck order (default: all)")
+	prdGenerateCmd.AddCommand(prdGenerateMarpCmd)
 }
 
-var okrInitCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Initialize a new OKR template",
-	Long: `Create a new OKR JSON template file with example content.
+func runPRDGenerateMarp(cmd *cobra.Command, args []string) error {
+	rp, err := renderProfile(args[0])
+	if err != nil {
+		return err
+	}
+	var doc prd.Document
+	if err := readProfiledDocument(args[0], &doc, rp, os.Stderr); err != nil {
+		return err
+	}
 
-Examples:
-  splan goals okr init
-  splan goals okr init --name "Q1 2026 Engineering OKRs"
-  splan goals okr init --name "Product Team" -o product-okrs.json --period 2026-Q1`,
-	RunE: runOKRInit,
+	opts := render.Options{Theme: prdGenerateMarpFlags.theme, Sections: prdGenerateMarpFlags.sections}
+	output, err := render.Default.Render("prd", render.FormatMarp, &doc, opts)
+	if err != nil {
+		return fmt.Errorf("rendering Marp: %w", err)
+	}
+
+	return writeRendered(prdGenerateMarpFlags.output, output)
 }
 
-func init() {
-	// OKR validate flags
-	okrValidateCmd.Flags().BoolVar(&okrValidateFlags.strict, "strict", false, "Enable strict validation mode")
+// writeRendered writes rendered output to path, or to stdout if path is
+// empty.
+func writeRendered(path string, output []byte) error {
+	if path == "" {
+		fmt.Print(string(output))
+		return nil
+	}
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, output, 0600); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	runReport.Outputs = append(runReport.Outputs, path)
+	fmt.Printf("Generated: %s\n", path)
+	return nil
+}
 
-	// OKR generate marp flags
-	okrGenerateMarpCmd.Flags().StringVarP(&okrGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
-	okrGenerateMarpCmd.Flags().StringVar(&okrGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
+// ============================================================================
+// PRD Six-Pager Command
+// ============================================================================
 
-	// OKR init flags
-	okrInitCmd.Flags().StringVar(&okrInitFlags.name, "name", "My OKRs", "Name for the OKR document")
-	okrInitCmd.Flags().StringVarP(&okrInitFlags.output, "output", "o", "okrs.json", "Output file path")
-	okrInitCmd.Flags().StringVar(&okrInitFlags.period, "period", "", "Period (e.g., 2026-Q1)")
+var prdGenerateSixPagerFlags struct {
+	output   string
+	format   string
+	faq      []string
+	noQuotes bool
+	template string
+}
 
-	// Add subcommands
-	okrGenerateCmd.AddCommand(okrGenerateMarpCmd)
-	okrCmd.AddCommand(okrValidateCmd)
-	okrCmd.AddCommand(okrGenerateCmd)
-	okrCmd.AddCommand(okrInitCmd)
+var prdGenerateSixPagerCmd = &cobra.Command{
+	Use:   "sixpager <input.json>",
+	Short: "Generate an Amazon-style 6-pager",
+	Long: `Generate a 6-pager narrative from a PRD: press release, FAQ, customer
+problem, solution, success metrics, and timeline.
+
+The press release and FAQ are synthesized from the PRD. The FAQ has three
+groups, each of which --faq can select:
+
+  customer   - Questions from personas and the proposed solution
+  internal   - Assumptions, constraints, alternatives, out of scope, high risks
+  technical  - Architecture overview and integration points
+
+--faq none leaves the FAQ out. The press release quotes, from the first
+author and the primary persona, are written from templates; --no-quotes
+leaves them out.
+
+Formats are md (default), json (the 6-pager view), and docx. The format is
+inferred from the --output extension. Markdown and JSON are written to
+stdout without --output; DOCX to the input name with a .sixpager.docx
+extension.`,
+	Example: `  splan requirements prd generate sixpager myproduct.prd.json -o myproduct-6pager.md
+  splan requirements prd generate sixpager myproduct.prd.json --faq customer,internal --no-quotes
+  splan requirements prd generate sixpager myproduct.prd.json --format docx --template corporate.docx
+  splan requirements prd generate sixpager myproduct.prd.json --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDGenerateSixPager,
 }
 
-func runOKRValidate(cmd *cobra.Command, args []string) error {
-	filepath := args[0]
+func init() {
+	f := prdGenerateSixPagerCmd.Flags()
+	f.StringVarP(&prdGenerateSixPagerFlags.output, "output", "o", "", "Output file path (default: stdout, or input.sixpager.docx for docx)")
+	f.StringVarP(&prdGenerateSixPagerFlags.format, "format", "f", "", "Output format: md, json, or docx (default: from output extension, else md)")
+	f.StringSliceVar(&prdGenerateSixPagerFlags.faq, "faq", nil, "FAQ groups to synthesize: customer, internal, technical, or none (default: all)")
+	f.BoolVar(&prdGenerateSixPagerFlags.noQuotes, "no-quotes", false, "Leave out the template-generated press release quotes")
+	f.StringVar(&prdGenerateSixPagerFlags.template, "template", "", "Reference .docx whose styles and page setup to use (docx only)")
+	prdGenerateCmd.AddCommand(prdGenerateSixPagerCmd)
+}
 
-	// Check file exists
-	if _, err := os.Stat(filepath); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", filepath)
+func runPRDGenerateSixPager(cmd *cobra.Command, args []string) error {
+	flags := &prdGenerateSixPagerFlags
+	opts := prd.SixPagerOptions{OmitQuotes: flags.noQuotes}
+	if cmd.Flags().Changed("faq") {
+		opts.FAQGroups = faqGroups(flags.faq)
 	}
-
-	// Read and parse OKR
-	doc, err := okr.ReadFile(filepath)
-	if err != nil {
-		return fmt.Errorf("reading OKR: %w", err)
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid --faq: %w", err)
 	}
 
-	// Set up validation options
-	opts := okr.DefaultValidationOptions()
-	if okrValidateFlags.strict {
-		opts = okr.StrictValidationOptions()
+	format := strings.ToLower(flags.format)
+	if format == "" {
+		format = "md"
+		switch strings.ToLower(filepath.Ext(flags.output)) {
+		case ".json":
+			format = "json"
+		case ".docx":
+			format = "docx"
+		}
 	}
 
-	// Validate
-	errs := doc.Validate(opts)
-
-	// Report results
-	errors := okr.Errors(errs)
-	warnings := okr.Warnings(errs)
+	rp, err := renderProfile(args[0])
+	if err != nil {
+		return err
+	}
+	var doc prd.Document
+	if err := readProfiledDocument(args[0], &doc, rp, os.Stderr); err != nil {
+		return err
+	}
+	view := prd.GenerateSixPagerViewWithOptions(&doc, opts)
 
-	if len(warnings) > 0 {
-		fmt.Println("Warnings:")
-		for _, w := range warnings {
-			fmt.Printf("  - %s\n", w)
+	switch format {
+	case "md", "markdown":
+		return writeRendered(flags.output, []byte(prd.RenderSixPagerMarkdown(view)))
+	case "json":
+		data, err := json.MarshalIndent(view, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling 6-pager: %w", err)
 		}
-		fmt.Println()
+		return writeRendered(flags.output, append(data, '\n'))
+	case "docx":
+		df := docxFlags{output: flags.output, template: flags.template}
+		dopts, err := df.options()
+		if err != nil {
+			return err
+		}
+		output, err := docx.New().RenderSixPager(view, dopts)
+		if err != nil {
+			return err
+		}
+		if df.output == "" {
+			df.output = deriveOutputPathExt(args[0], ".sixpager.docx")
+		}
+		return writeDOCX(args[0], &df, output)
+	default:
+		return fmt.Errorf("unknown format %q (valid: md, json, docx)", flags.format)
 	}
+}
 
-	if len(errors) > 0 {
-		fmt.Println("Errors:")
-		for _, e := range errors {
-			fmt.Printf("  - %s\n", e)
+// faqGroups normalizes --faq values, where "none" selects no groups.
+func faqGroups(values []string) []string {
+	groups := []string{}
+	for _, g := range values {
+		if g = strings.ToLower(strings.TrimSpace(g)); g != "none" && g != "" {
+			groups = append(groups, g)
 		}
-		return fmt.Errorf("validation failed with %d error(s)", len(errors))
 	}
+	return groups
+}
 
-	// Print success info
-	fmt.Printf("Valid OKR: %s\n", filepath)
-	fmt.Printf("  Objectives: %d\n", len(doc.Objectives))
-	fmt.Printf("  Total Key Results: %d\n", len(doc.AllKeyResults()))
-	fmt.Printf("  Overall Progress: %.0f%%\n", doc.CalculateOverallProgress()*100)
+// ============================================================================
+// PRD PR/FAQ Command
+// ============================================================================
 
-	if doc.Metadata != nil && doc.Metadata.Name != "" {
-		fmt.Printf("  Name: %s\n", doc.Metadata.Name)
-	}
+var prdGeneratePRFAQFlags struct {
+	output      string
+	format      string
+	launchDate  string
+	quoteSource string
+	noQuotes    bool
+	faq         []string
+	faqTags     []string
+	template    string
+}
 
-	return nil
+var prdGeneratePRFAQCmd = &cobra.Command{
+	Use:   "prfaq <input.json>",
+	Short: "Generate an Amazon-style PR/FAQ",
+	Long: `Generate a working-backwards PR/FAQ from a PRD: a press release announcing
+the launch, followed by frequently asked questions. It is the first two
+sections of the 6-pager, for teams that use the PR/FAQ on its own.
+
+The press release is dated --launch-date, or else the latest end date on
+the roadmap. The customer quote is written from a template for the primary
+persona; with --quote-source evidence, it is a customer's own words from
+the feedback on the problem or a persona, or the strongest interview,
+survey, or support ticket evidence, and is left out if there is none.
+--no-quotes leaves out both quotes.
+
+The FAQ starts with the questions in the PRD's faqs array, then adds ones
+synthesized from personas, assumptions, constraints, alternatives, out of
+scope items, risks, and the architecture. --faq selects groups (customer,
+internal, technical, or none). --faq-tag keeps only the questions with one
+of the tags: authored FAQs carry their own tags, and synthesized ones carry
+those of the persona or risk they come from.
+
+Formats are md (default), json (the PR/FAQ view), and docx. The format is
+inferred from the --output extension. Markdown and JSON are written to
+stdout without --output; DOCX to the input name with a .prfaq.docx
+extension.`,
+	Example: `  splan requirements prd generate prfaq myproduct.prd.json -o myproduct-prfaq.md
+  splan requirements prd generate prfaq myproduct.prd.json --launch-date 2026-11-03 --quote-source evidence
+  splan requirements prd generate prfaq myproduct.prd.json --faq customer --faq-tag security,pricing
+  splan requirements prd generate prfaq myproduct.prd.json --format docx --template corporate.docx`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDGeneratePRFAQ,
 }
 
-func runOKRMarpGenerate(cmd *cobra.Command, args []string) error {
-	inputPath := args[0]
+func init() {
+	f := prdGeneratePRFAQCmd.Flags()
+	f.StringVarP(&prdGeneratePRFAQFlags.output, "output", "o", "", "Output file path (default: stdout, or input.prfaq.docx for docx)")
+	f.StringVarP(&prdGeneratePRFAQFlags.format, "format", "f", "", "Output format: md, json, or docx (default: from output extension, else md)")
+	f.StringVar(&prdGeneratePRFAQFlags.launchDate, "launch-date", "", "Press release date, YYYY-MM-DD (default: latest roadmap end date)")
+	f.StringVar(&prdGeneratePRFAQFlags.quoteSource, "quote-source", prd.QuoteSourcePersona, "Customer quote source: persona (template) or evidence")
+	f.BoolVar(&prdGeneratePRFAQFlags.noQuotes, "no-quotes", false, "Leave out the press release quotes")
+	f.StringSliceVar(&prdGeneratePRFAQFlags.faq, "faq", nil, "FAQ groups: customer, internal, technical, or none (default: all)")
+	f.StringSliceVar(&prdGeneratePRFAQFlags.faqTags, "faq-tag", nil, "Keep only FAQs with one of these tags")
+	f.StringVar(&prdGeneratePRFAQFlags.template, "template", "", "Reference .docx whose styles and page setup to use (docx only)")
+	prdGenerateCmd.AddCommand(prdGeneratePRFAQCmd)
+}
 
-	// Check file exists
-	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", inputPath)
+func runPRDGeneratePRFAQ(cmd *cobra.Command, args []string) error {
+	flags := &prdGeneratePRFAQFlags
+	opts := prd.PRFAQOptions{
+		QuoteSource: strings.ToLower(flags.quoteSource),
+		OmitQuotes:  flags.noQuotes,
+		FAQTags:     flags.faqTags,
 	}
-
-	// Read and parse OKR
-	doc, err := okr.ReadFile(inputPath)
-	if err != nil {
-		return fmt.Errorf("reading OKR: %w", err)
+	if flags.launchDate != "" {
+		t, err := time.Parse("2006-01-02", flags.launchDate)
+		if err != nil {
+			return fmt.Errorf("invalid --launch-date %q: expected YYYY-MM-DD", flags.launchDate)
+		}
+		opts.LaunchDate = t
 	}
-
-	// Create renderer and options
-	renderer := okrmarp.New()
-	opts := okrrender.DefaultOptions()
-	opts.Theme = okrGenerateMarpFlags.theme
-
-	// Render
-	output, err := renderer.Render(doc, opts)
-	if err != nil {
-		return fmt.Errorf("rendering Marp: %w", err)
+	if cmd.Flags().Changed("faq") {
+		opts.FAQGroups = faqGroups(flags.faq)
+	}
+	if err := opts.Validate(); err != nil {
+		return err
 	}
 
-	// Write output
-	if okrGenerateMarpFlags.output != "" {
-		// Ensure output directory exists
-		dir := filepath.Dir(okrGenerateMarpFlags.output)
-		if dir != "." && dir != "" {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("creating output directory: %w", err)
-			}
-		}
-
-		if err := os.WriteFile(okrGenerateMarpFlags.output, output, 0600); err != nil {
-			return fmt.Errorf("writing output: %w", err)
+	format := strings.ToLower(flags.format)
+	if format == "" {
+		format = "md"
+		switch strings.ToLower(filepath.Ext(flags.output)) {
+		case ".json":
+			format = "json"
+		case ".docx":
+			format = "docx"
 		}
-		fmt.Printf("Generated: %s\n", okrGenerateMarpFlags.output)
-	} else {
-		// Write to stdout
-		fmt.Print(string(output))
 	}
 
-	return nil
-}
-
-func runOKRInit(cmd *cobra.Command, args []string) error {
-	// Check if file already exists
-	if _, err := os.Stat(okrInitFlags.output); err == nil {
-		return fmt.Errorf("file already exists: %s (use -o to specify a different output path)", okrInitFlags.output)
+	rp, err := renderProfile(args[0])
+	if err != nil {
+		return err
 	}
-
-	// Create template
-	now := time.Now()
-	period := okrInitFlags.period
-	if period == "" {
-		quarter := (now.Month()-1)/3 + 1
-		period = fmt.Sprintf("%d-Q%d", now.Year(), quarter)
+	var doc prd.Document
+	if err := readProfiledDocument(args[0], &doc, rp, os.Stderr); err != nil {
+		return err
 	}
-
-	template := &okr.OKRDocument{
-		Schema: "../schema/okr.schema.json",
-		Metadata: &okr.Metadata{
-			ID:         okr.GenerateID(),
-			Name:       okrInitFlags.name,
-			Owner:      "Your Name",
-			Team:       "Your Team",
-			Period:     period,
-			PeriodType: "quarter",
-			Version:    "1.0.0",
-			Status:     okr.StatusDraft,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		},
-		Theme: "Achieve great results this quarter",
-		Objectives: []okr.Objective{
-			{
-				ID:          "obj-1",
-				Title:       "First Objective",
-				Description: "What do you want to achieve? Make it inspirational.",
-				Category:    "Product",
-				Owner:       "Your Name",
-				Status:      okr.StatusDraft,
-				KeyResults: []okr.KeyResult{
-					{
-						ID:          "kr-1-1",
-						Description: "First measurable key result",
-						Baseline:    "0",
-						Target:      "100",
-						Current:     "0",
-						Unit:        "%",
-						Confidence:  okr.ConfidenceMedium,
-					},
-					{
-						ID:          "kr-1-2",
-						Description: "Second measurable key result",
-						Baseline:    "0",
-						Target:      "50",
-						Current:     "0",
-						Unit:        "count",
-						Confidence:  okr.ConfidenceMedium,
-					},
-				},
-			},
-			{
-				ID:          "obj-2",
-				Title:       "Second Objective",
-				Description: "Another goal to achieve this quarter.",
-				Category:    "Engineering",
-				Owner:       "Your Name",
-				Status:      okr.StatusDraft,
-				KeyResults: []okr.KeyResult{
-					{
-						ID:          "kr-2-1",
-						Description: "Key result for objective 2",
-						Baseline:    "0",
-						Target:      "10",
-						Current:     "0",
-						Unit:        "features",
-						Confidence:  okr.ConfidenceHigh,
-					},
-				},
-			},
-		},
-		Risks: []okr.Risk{
-			{
-				ID:          "risk-1",
-				Description: "What might prevent success?",
-				Impact:      "High",
-				Likelihood:  "Medium",
-				Mitigation:  "How will you address this?",
-			},
-		},
+	view := prd.GeneratePRFAQViewWithOptions(&doc, opts)
+	if opts.QuoteSource == prd.QuoteSourceEvidence && !opts.OmitQuotes && view.PressRelease.CustomerQuote.Text == "" {
+		fmt.Fprintln(os.Stderr, "Warning: no customer feedback or interview, survey, or support ticket evidence to quote; customer quote left out")
 	}
 
-	// Write to file
-	if err := template.WriteFile(okrInitFlags.output); err != nil {
-		return fmt.Errorf("writing template: %w", err)
+	switch format {
+	case "md", "markdown":
+		return writeRendered(flags.output, []byte(prd.RenderPRFAQMarkdown(view)))
+	case "json":
+		data, err := json.MarshalIndent(view, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling PR/FAQ: %w", err)
+		}
+		return writeRendered(flags.output, append(data, '\n'))
+	case "docx":
+		df := docxFlags{output: flags.output, template: flags.template}
+		dopts, err := df.options()
+		if err != nil {
+			return err
+		}
+		output, err := docx.New().RenderPRFAQ(view, dopts)
+		if err != nil {
+			return err
+		}
+		if df.output == "" {
+			df.output = deriveOutputPathExt(args[0], ".prfaq.docx")
+		}
+		return writeDOCX(args[0], &df, output)
+	default:
+		return fmt.Errorf("unknown format %q (valid: md, json, docx)", flags.format)
 	}
-
-	fmt.Printf("Created: %s\n", okrInitFlags.output)
-	fmt.Printf("  Period: %s\n", period)
-	fmt.Println("\nNext steps:")
-	fmt.Println("  1. Edit the file to add your objectives and key results")
-	fmt.Println("  2. Run 'splan goals okr validate " + okrInitFlags.output + "' to check your OKRs")
-	fmt.Println("  3. Run 'splan goals okr generate marp " + okrInitFlags.output + " -o slides.md' to create slides")
-
-	return nil
 }
 
 // ============================================================================
-// Shared Types
+// PRD One-Pager Command
 // ============================================================================
 
-// Shared generate flags
-type generateFlags struct {
-	output           string
-	margin           string
-	mainFont         string
-	sansFont         string
-	monoFont         string
-	fontFamily       string
-	noFrontmatter    bool
-	noTOC            bool
-	noSwimlane       bool
-	descLen          int
-	swimlaneNoStatus bool
+var prdGenerateOnePagerFlags struct {
+	output string
+	format string
+}
+
+var prdGenerateOnePagerCmd = &cobra.Command{
+	Use:   "onepager <input.json>",
+	Short: "Generate a one-page executive brief",
+	Long: `Generate a one-page executive brief from a PRD for leadership reviews:
+the problem, the solution, the top three key results, the top three open
+risks, and the next milestone.
+
+Key results are taken in turn from each objective. Risks that are mitigated
+or closed are left out; the rest are ranked by impact, then probability.
+The next milestone is the end of the first roadmap phase that is neither
+completed nor cancelled.
+
+Formats are md (default), html, and json (the one-pager view). The format
+is inferred from the --output extension. Output goes to stdout without
+--output.`,
+	Example: `  splan requirements prd generate onepager myproduct.prd.json
+  splan requirements prd generate onepager myproduct.prd.json -o brief.html
+  splan requirements prd generate onepager myproduct.prd.json --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDGenerateOnePager,
+}
+
+func init() {
+	f := prdGenerateOnePagerCmd.Flags()
+	f.StringVarP(&prdGenerateOnePagerFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	f.StringVarP(&prdGenerateOnePagerFlags.format, "format", "f", "", "Output format: md, html, or json (default: from output extension, else md)")
+	prdGenerateCmd.AddCommand(prdGenerateOnePagerCmd)
+}
+
+func runPRDGenerateOnePager(cmd *cobra.Command, args []string) error {
+	flags := &prdGenerateOnePagerFlags
+	format := strings.ToLower(flags.format)
+	if format == "" {
+		format = "md"
+		switch strings.ToLower(filepath.Ext(flags.output)) {
+		case ".json":
+			format = "json"
+		case ".html", ".htm":
+			format = "html"
+		}
+	}
+
+	rp, err := renderProfile(args[0])
+	if err != nil {
+		return err
+	}
+	var doc prd.Document
+	if err := readProfiledDocument(args[0], &doc, rp, os.Stderr); err != nil {
+		return err
+	}
+	view := prd.GenerateOnePagerView(&doc)
+
+	switch format {
+	case "md", "markdown":
+		return writeRendered(flags.output, []byte(prd.RenderOnePagerMarkdown(view)))
+	case "html":
+		output, err := prdhtml.New().RenderOnePager(view, prdrender.DefaultOptions())
+		if err != nil {
+			return err
+		}
+		return writeRendered(flags.output, output)
+	case "json":
+		data, err := json.MarshalIndent(view, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling one-pager: %w", err)
+		}
+		return writeRendered(flags.output, append(data, '\n'))
+	default:
+		return fmt.Errorf("unknown format %q (valid: md, html, json)", flags.format)
+	}
 }
 
 // ============================================================================
-// PRD Commands
+// MRD and Roadmap Marp Commands
 // ============================================================================
 
-var prdCmd = &cobra.Command{
-	Use:   "prd",
-	Short: "Work with Product Requirements Documents",
-	Long:  `Commands for generating and validating Product Requirements Documents (PRD).`,
+var mrdGenerateMarpFlags struct {
+	output string
+	theme  string
 }
 
-var prdGenerateFlags generateFlags
+var mrdGenerateMarpCmd = &cobra.Command{
+	Use:   "marp <input.json>",
+	Short: "Generate Marp markdown slides",
+	Long: `Generate a Marp presentation from an MRD: the opportunity, market sizing
+(TAM/SAM/SOM), target segments, a competitive landscape table of competitors
+by threat level and category, positioning, market requirements, success
+metrics, and risks.
 
-var prdGenerateCmd = &cobra.Command{
-	Use:   "generate <input.json>",
-	Short: "Convert PRD JSON to markdown",
-	Long: `Generate markdown from a Product Requirements Document (PRD).
+Themes:
+  default   - Clean gradient theme (default)
+  corporate - Professional blue theme
+  minimal   - Simple grayscale theme
 
-The output includes YAML frontmatter compatible with Pandoc for PDF generation.
-By default, the output file has the same name as the input with a .md extension.`,
-	Example: `  splan requirements prd generate myproduct.prd.json
-  splan requirements prd generate myproduct.json -o output.md
-  splan requirements prd generate myproduct.json --no-frontmatter`,
+Slides with nothing to show are left out.`,
+	Example: `  splan requirements mrd generate marp agent-platform.mrd.json -o agent-platform-slides.md
+  splan requirements mrd generate marp agent-platform.mrd.json --theme corporate`,
 	Args: cobra.ExactArgs(1),
-	RunE: runPRDGenerate,
+	RunE: runMRDGenerateMarp,
 }
 
-var prdValidateCmd = &cobra.Command{
-	Use:     "validate <input.json>",
-	Short:   "Validate PRD structure",
-	Long:    `Validate a Product Requirements Document by parsing it and checking required fields.`,
-	Example: `  splan requirements prd validate myproduct.prd.json`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runPRDValidate,
+func init() {
+	mrdGenerateMarpCmd.Flags().StringVarP(&mrdGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	mrdGenerateMarpCmd.Flags().StringVar(&mrdGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
+	mrdGenerateCmd.AddCommand(mrdGenerateMarpCmd)
 }
 
-var prdCheckFlags struct {
-	json bool
+func runMRDGenerateMarp(cmd *cobra.Command, args []string) error {
+	var doc mrd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	output, err := render.Default.Render("mrd", render.FormatMarp, &doc, render.Options{Theme: mrdGenerateMarpFlags.theme})
+	if err != nil {
+		return fmt.Errorf("rendering Marp: %w", err)
+	}
+	return writeRendered(mrdGenerateMarpFlags.output, output)
 }
 
-var prdCheckCmd = &cobra.Command{
-	Use:   "check <input.json>",
-	Short: "Check PRD completeness",
-	Long: `Analyze a Product Requirements Document for completeness and quality.
+var roadmapGenerateMarpFlags struct {
+	output string
+	theme  string
+}
 
-This command evaluates each section of the PRD and provides:
-  - Overall completeness score (0-100%) and letter grade
-  - Per-section scores for required and optional sections
-  - Specific recommendations for improvement
+var roadmapGenerateMarpCmd = &cobra.Command{
+	Use:   "marp <input.json>",
+	Short: "Generate Marp markdown slides",
+	Long: `Generate a Marp presentation from a roadmap: the vision and themes, then one
+slide per calendar quarter with the phases active in it, their deliverables,
+and the milestones due in it. Phases without dates go on an "Unscheduled"
+slide, followed by the risks.
 
-The check examines:
-  - Required sections: metadata, executive summary, objectives, personas,
-    user stories, requirements, and roadmap
-  - Optional sections: assumptions, out of scope, technical architecture,
-    UX requirements, risks, and glossary
-  - Quality indicators: depth of content, cross-references between sections,
-    acceptance criteria coverage, and NFR category coverage`,
-	Example: `  splan requirements prd check myproduct.prd.json
-  splan requirements prd check myproduct.prd.json --json`,
+Themes:
+  default   - Clean gradient theme (default)
+  corporate - Professional blue theme
+  minimal   - Simple grayscale theme`,
+	Example: `  splan roadmap generate marp agent-platform.roadmap.json -o roadmap-slides.md
+  splan roadmap generate marp agent-platform.roadmap.json --theme minimal`,
 	Args: cobra.ExactArgs(1),
-	RunE: runPRDCheck,
+	RunE: runRoadmapGenerateMarp,
 }
 
-var prdScoreFlags struct {
-	format string
+func init() {
+	roadmapGenerateMarpCmd.Flags().StringVarP(&roadmapGenerateMarpFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	roadmapGenerateMarpCmd.Flags().StringVar(&roadmapGenerateMarpFlags.theme, "theme", "default", "Slide theme (default, corporate, minimal)")
+	roadmapGenerateCmd.AddCommand(roadmapGenerateMarpCmd)
 }
 
-var prdFilterFlags struct {
-	output      string
-	includeTags []string
-	excludeTags []string
-	matchAll    bool
+func runRoadmapGenerateMarp(cmd *cobra.Command, args []string) error {
+	var doc roadmap.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	output, err := render.Default.Render("roadmap", render.FormatMarp, &doc, render.Options{Theme: roadmapGenerateMarpFlags.theme})
+	if err != nil {
+		return fmt.Errorf("rendering Marp: %w", err)
+	}
+	return writeRendered(roadmapGenerateMarpFlags.output, output)
 }
 
-var prdFilterCmd = &cobra.Command{
-	Use:   "filter <input.json>",
-	Short: "Filter PRD by tags and output filtered JSON",
-	Long: `Filter a Product Requirements Document by tags and output the filtered JSON.
+// ============================================================================
+// Render Command
+// ============================================================================
 
-By default, uses OR logic (union) - includes entities with ANY of the specified tags.
-Use --all to require ALL tags (intersection).
+var renderFlags struct {
+	output          string
+	format          string
+	docType         string
+	theme           string
+	sections        []string
+	css             string
+	comments        bool
+	noGlossaryLinks bool
+	mermaid         bool
+	redact          bool
+	list            bool
+}
 
-Entities that support tags: personas, user stories, requirements, roadmap phases,
-deliverables, OKRs (objectives and key results), and risks.`,
-	Example: `  # Include items with tag1 OR tag2 (union)
-  splan requirements prd filter input.json --include tag1,tag2
+var renderCmd = &cobra.Command{
+	Use:   "render [file]",
+	Short: "Render any document type to any of its formats",
+	Long: `Render a document with the renderers registered for its type. The type is
+taken from the filename suffix, as in checkout.prd.json, or from --type.
+
+List the document types and their formats with --list. Options that do not
+apply to a format are ignored: --theme and --sections apply to slides,
+--css and --comments to HTML, and --mermaid to Markdown. --redact masks the
+secret configuration of a TRD in every format.`,
+	Example: `  splan render --list
+  splan render checkout.prd.json --format marp --theme corporate -o slides.md
+  splan render market.mrd.json --format html -o market.html
+  splan render platform.trd.json --format html --redact -o platform.html
+  splan render plan.json --type roadmap --format marp`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if renderFlags.list {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runRender,
+}
 
-  # Include items with tag1 AND tag2 (intersection)
-  splan requirements prd filter input.json --include tag1,tag2 --all
+func init() {
+	renderCmd.Flags().StringVarP(&renderFlags.output, "output", "o", "", "Output file path (default: stdout)")
+	renderCmd.Flags().StringVarP(&renderFlags.format, "format", "f", render.FormatMarkdown, "Output format (see --list)")
+	renderCmd.Flags().StringVarP(&renderFlags.docType, "type", "t", "", "Document type (default: from the filename)")
+	renderCmd.Flags().StringVar(&renderFlags.theme, "theme", render.ThemeDefault, "Slide theme ("+strings.Join(render.Themes(), ", ")+")")
+	renderCmd.Flags().StringSliceVar(&renderFlags.sections, "sections", nil, "Slides to include, by the names the format documents (default: all)")
+	renderCmd.Flags().StringVar(&renderFlags.css, "css", "", "CSS file added to the HTML stylesheet")
+	renderCmd.Flags().BoolVar(&renderFlags.comments, "comments", false, "Show unresolved review comments in HTML")
+	renderCmd.Flags().BoolVar(&renderFlags.noGlossaryLinks, "no-glossary-links", false, "Do not link the first use of each glossary term to its entry")
+	renderCmd.Flags().BoolVar(&renderFlags.mermaid, "mermaid", false, "Add Mermaid roadmap diagrams to Markdown")
+	renderCmd.Flags().BoolVar(&renderFlags.redact, "redact", false, "Mask the sources and defaults of secret TRD configuration entries")
+	renderCmd.Flags().BoolVar(&renderFlags.list, "list", false, "List the document types and their formats")
+	rootCmd.AddCommand(renderCmd)
+}
 
-  # Output to specific file
-  splan requirements prd filter input.json --include mvp -o filtered.json`,
+// renderDocuments returns a new, empty document of each type with
+// registered renderers.
+var renderDocuments = map[string]func() any{
+	"prd":     func() any { return &prd.Document{} },
+	"mrd":     func() any { return &mrd.Document{} },
+	"trd":     func() any { return &trd.Document{} },
+	"roadmap": func() any { return &roadmap.Document{} },
+	"v2mom":   func() any { return &v2mom.V2MOM{} },
+	"okr":     func() any { return &okr.OKRDocument{} },
+
+	"evaluation": func() any { return &evaluation.EvaluationReport{} },
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	if renderFlags.list {
+		for _, docType := range render.Default.DocTypes() {
+			fmt.Printf("%-10s %s\n", docType, strings.Join(render.Default.Formats(docType), ", "))
+		}
+		return nil
+	}
+
+	docType := strings.ToLower(renderFlags.docType)
+	if docType == "" {
+		docType = documentTypeFromPath(args[0])
+	}
+	newDoc, ok := renderDocuments[docType]
+	if !ok {
+		return fmt.Errorf("cannot tell the document type of %s: name it like checkout.prd.json or use --type (%s)",
+			args[0], strings.Join(render.Default.DocTypes(), ", "))
+	}
+	renderer, err := render.Lookup(docType, renderFlags.format)
+	if err != nil {
+		return err
+	}
+	linkGlossary := !renderFlags.noGlossaryLinks
+	opts := render.Options{
+		Theme:           renderFlags.theme,
+		Sections:        renderFlags.sections,
+		IncludeComments: renderFlags.comments,
+		LinkGlossary:    &linkGlossary,
+		Diagrams:        renderFlags.mermaid,
+	}
+	if renderFlags.css != "" {
+		css, err := os.ReadFile(renderFlags.css)
+		if err != nil {
+			return fmt.Errorf("reading CSS file: %w", err)
+		}
+		opts.CustomCSS = string(css)
+	}
+
+	doc := newDoc()
+	if err := readDocument(args[0], doc); err != nil {
+		return err
+	}
+	if d, ok := doc.(*trd.Document); ok {
+		if err := redactTRD(d, renderFlags.redact, args[0]); err != nil {
+			return err
+		}
+	}
+	output, err := renderer.Render(doc, opts)
+	if err != nil {
+		return fmt.Errorf("rendering %s: %w", renderer.Format(), err)
+	}
+	return writeRendered(renderFlags.output, output)
+}
+
+// ============================================================================
+// Export Commands
+// ============================================================================
+
+var prdExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export PRD stories, requirements, and roadmap to other tools",
+	Long: `Export user stories and functional requirements as issues in external
+trackers. The issue created for each entity is recorded in its externalRefs,
+so exporting again updates the same issues instead of creating duplicates.
+
+The roadmap can also be exported as an iCalendar file for calendar apps.`,
+}
+
+var prdExportJiraFlags struct {
+	output          string
+	format          string
+	url             string
+	user            string
+	token           string
+	project         string
+	storyType       string
+	requirementType string
+}
+
+var prdExportJiraCmd = &cobra.Command{
+	Use:   "jira <input.json>",
+	Short: "Export user stories and requirements as Jira issues",
+	Long: `Convert user stories and functional requirements into Jira issues. Priority
+maps to Jira's default priorities, acceptance criteria and the roadmap phase
+are written into the description, and tags become labels (the phase is added
+as a "phase:<id>" label).
+
+Without --url, the issues are written to a file: json is the request body of
+Jira's bulk create endpoint (POST /rest/api/2/issue/bulk), and csv is for
+Jira's CSV importer. By default, the output file is the input name with
+.jira.json or .jira.csv in place of .json.
+
+With --url, the issues are pushed through the Jira REST API. Entities with a
+Jira key in externalRefs update that issue; the others create one, and its key
+is written back to the PRD. The token is read from JIRA_API_TOKEN when
+--token is not given. With --user it is sent as basic auth (Jira Cloud API
+tokens); otherwise as a bearer personal access token (Jira Data Center).`,
+	Example: `  splan requirements prd export jira myproduct.prd.json --project PROJ
+  splan requirements prd export jira myproduct.prd.json --format csv
+  splan requirements prd export jira myproduct.prd.json --project PROJ \
+    --url https://acme.atlassian.net --user me@acme.com`,
 	Args: cobra.ExactArgs(1),
-	RunE: runPRDFilter,
+	RunE: runPRDExportJira,
 }
 
-var prdScoreCmd = &cobra.Command{
-	Use:   "score <input.json>",
-	Short: "Score PRD quality with actionable feedback",
-	Long: `Score a Product Requirements Document against 10 quality dimensions.
+func init() {
+	f := prdExportJiraCmd.Flags()
+	f.StringVarP(&prdExportJiraFlags.output, "output", "o", "", "Output file path (default: input with .jira.json or .jira.csv)")
+	f.StringVarP(&prdExportJiraFlags.format, "format", "f", "json", "Output format (json, csv)")
+	f.StringVar(&prdExportJiraFlags.url, "url", "", "Jira site URL; pushes issues through the REST API instead of writing a file")
+	f.StringVar(&prdExportJiraFlags.user, "user", "", "Jira account email for basic auth with an API token")
+	f.StringVar(&prdExportJiraFlags.token, "token", "", "Jira API token or personal access token (default: $JIRA_API_TOKEN)")
+	f.StringVar(&prdExportJiraFlags.project, "project", "", "Jira project key (required with --url)")
+	f.StringVar(&prdExportJiraFlags.storyType, "story-type", jira.DefaultStoryType, "Issue type for user stories")
+	f.StringVar(&prdExportJiraFlags.requirementType, "requirement-type", jira.DefaultRequirementType, "Issue type for functional requirements")
+
+	prdExportCmd.AddCommand(prdExportJiraCmd)
+	prdCmd.AddCommand(prdExportCmd)
+}
 
-This command provides an actionable workflow:
-  (a) Scores - weighted scores across 10 categories
-  (b) Gaps - specific missing elements per category
-  (c) Fix recommendations - what to improve with effort estimates
-  (d) Re-run instructions - command to verify fixes
+func runPRDExportJira(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+	var doc prd.Document
+	src, err := readSourceDocument(inputFile, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	opts := jira.Options{
+		ProjectKey:      prdExportJiraFlags.project,
+		StoryType:       prdExportJiraFlags.storyType,
+		RequirementType: prdExportJiraFlags.requirementType,
+	}
 
-Output formats:
-  - terminal (default): Box-format terminal output with status icons
-  - json: Full JSON report for programmatic use
-  - markdown: Markdown report for documentation
+	if prdExportJiraFlags.url != "" {
+		token := prdExportJiraFlags.token
+		if token == "" {
+			token = os.Getenv("JIRA_API_TOKEN")
+		}
+		client := jira.NewClient(prdExportJiraFlags.url, prdExportJiraFlags.user, token)
+		res, syncErr := client.Sync(cmd.Context(), &doc, opts)
+		// Save the keys of issues created before any failure, so a retry
+		// does not create them again.
+		if len(res.Created) > 0 {
+			if err := writeDocumentInPlace(inputFile, src, &doc); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Created %d and updated %d Jira issues\n", len(res.Created), len(res.Updated))
+		if syncErr != nil {
+			return fmt.Errorf("syncing with Jira: %w", syncErr)
+		}
+		return nil
+	}
 
-Quality categories (with weights):
-  - Problem Definition (20%)    - Solution Fit (15%)
-  - User Understanding (10%)    - Market Awareness (10%)
-  - Scope Discipline (10%)      - Requirements Quality (10%)
-  - Metrics Quality (10%)       - UX Coverage (5%)
-  - Technical Feasibility (5%)  - Risk Management (5%)
+	issues := jira.Issues(&doc, opts)
+	var data []byte
+	switch strings.ToLower(prdExportJiraFlags.format) {
+	case "json":
+		data, err = jira.MarshalJSON(issues)
+	case "csv":
+		data, err = jira.MarshalCSV(issues)
+	default:
+		return fmt.Errorf("unknown format %q (valid: json, csv)", prdExportJiraFlags.format)
+	}
+	if err != nil {
+		return err
+	}
 
-Decision thresholds:
-  - Approve: >= 8.0
-  - Revise:  >= 6.5
-  - Reject:  < 3.0 (any blocker)`,
-	Example: `  splan requirements prd score myproduct.prd.json
-  splan requirements prd score myproduct.prd.json --format=json
-  splan requirements prd score myproduct.prd.json --format=markdown`,
+	output := prdExportJiraFlags.output
+	if output == "" {
+		output = deriveOutputPathExt(inputFile, ".jira."+strings.ToLower(prdExportJiraFlags.format))
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s\n", output)
+	return nil
+}
+
+var prdExportGitHubFlags struct {
+	repo   string
+	token  string
+	apiURL string
+	dryRun bool
+}
+
+var prdExportGitHubCmd = &cobra.Command{
+	Use:   "github <input.json>",
+	Short: "Create or update GitHub issues for user stories and requirements",
+	Long: `Create one GitHub issue per user story and functional requirement. Tags
+become labels, and each roadmap phase becomes a milestone (created if missing,
+due at the phase end date).
+
+Each issue body ends with a hidden marker holding the PRD and entity IDs.
+Running the command again finds the issues by their markers, open or closed,
+and updates only those that changed, so no duplicates are created even from a
+fresh copy of the PRD. The issue URLs are written back to the PRD as
+externalRefs.github.
+
+The token is read from GITHUB_TOKEN when --token is not given. Use --api-url
+for GitHub Enterprise Server, and --dry-run to list the changes without
+making them.`,
+	Example: `  splan requirements prd export github myproduct.prd.json --repo acme/checkout
+  splan requirements prd export github myproduct.prd.json --repo acme/checkout --dry-run`,
 	Args: cobra.ExactArgs(1),
-	RunE: runPRDScore,
+	RunE: runPRDExportGitHub,
 }
 
 func init() {
-	// PRD generate flags
-	prdGenerateCmd.Flags().StringVarP(&prdGenerateFlags.output, "output", "o", "", "Output markdown file path (default: input with .md extension)")
-	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.margin, "margin", "2cm", "Page margin for Pandoc")
-	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.mainFont, "mainfont", "Helvetica", "Main font family")
-	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.sansFont, "sansfont", "Helvetica", "Sans-serif font family")
-	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.monoFont, "monofont", "Courier New", "Monospace font family")
-	prdGenerateCmd.Flags().StringVar(&prdGenerateFlags.fontFamily, "fontfamily", "helvet", "LaTeX font family")
-	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noFrontmatter, "no-frontmatter", false, "Disable YAML frontmatter generation")
-	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noTOC, "no-toc", false, "Disable Table of Contents generation")
-	prdGenerateCmd.Flags().IntVar(&prdGenerateFlags.descLen, "desc-len", prd.DefaultDescriptionMaxLen, "Max length for description fields in tables (0 = no limit)")
-	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.noSwimlane, "no-swimlane", false, "Disable swimlane table view in roadmap section")
-	prdGenerateCmd.Flags().BoolVar(&prdGenerateFlags.swimlaneNoStatus, "swimlane-no-status", false, "Hide status icons in swimlane table")
+	f := prdExportGitHubCmd.Flags()
+	f.StringVar(&prdExportGitHubFlags.repo, "repo", "", "Repository as owner/name (required)")
+	f.StringVar(&prdExportGitHubFlags.token, "token", "", "GitHub token (default: $GITHUB_TOKEN)")
+	f.StringVar(&prdExportGitHubFlags.apiURL, "api-url", github.DefaultBaseURL, "GitHub REST API URL")
+	f.BoolVar(&prdExportGitHubFlags.dryRun, "dry-run", false, "List the changes without making them")
+	_ = prdExportGitHubCmd.MarkFlagRequired("repo")
+
+	prdExportCmd.AddCommand(prdExportGitHubCmd)
+}
 
-	prdCmd.AddCommand(prdGenerateCmd)
-	prdCmd.AddCommand(prdValidateCmd)
-	prdCmd.AddCommand(prdCheckCmd)
-	prdCmd.AddCommand(prdScoreCmd)
-	prdCmd.AddCommand(prdFilterCmd)
+func runPRDExportGitHub(cmd *cobra.Command, args []string) error {
+	inputFile := args[0]
+	var doc prd.Document
+	src, err := readSourceDocument(inputFile, &doc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	token := prdExportGitHubFlags.token
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	client, err := github.NewClient(prdExportGitHubFlags.apiURL, token, prdExportGitHubFlags.repo)
+	if err != nil {
+		return err
+	}
+
+	res, syncErr := client.Sync(cmd.Context(), &doc, prdExportGitHubFlags.dryRun)
+	if prdExportGitHubFlags.dryRun {
+		for _, m := range res.Milestones {
+			fmt.Printf("create milestone %q\n", m)
+		}
+		for _, id := range res.Created {
+			fmt.Printf("create issue for %s\n", id)
+		}
+		for _, id := range res.Updated {
+			fmt.Printf("update issue for %s\n", id)
+		}
+		fmt.Printf("%d unchanged\n", len(res.Unchanged))
+		return syncErr
+	}
+	// Save the issue URLs recorded before any failure.
+	if len(res.Created)+len(res.Updated)+len(res.Unchanged) > 0 {
+		if err := writeDocumentInPlace(inputFile, src, &doc); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Created %d, updated %d, and left %d GitHub issues unchanged\n", len(res.Created), len(res.Updated), len(res.Unchanged))
+	if syncErr != nil {
+		return fmt.Errorf("syncing with GitHub: %w", syncErr)
+	}
+	return nil
+}
+
+var prdExportICSFlags struct {
+	output string
+}
 
-	// PRD check flags
-	prdCheckCmd.Flags().BoolVar(&prdCheckFlags.json, "json", false, "Output report as JSON")
+var prdExportICSCmd = &cobra.Command{
+	Use:   "ics <input.json>",
+	Short: "Export the roadmap as an iCalendar file",
+	Long: `Export the roadmap phases and deliverables as an iCalendar (RFC 5545) file,
+so the roadmap can be imported into or subscribed to from Google Calendar,
+Outlook, and other calendar apps.
+
+Each phase with a start and end date becomes an all-day event spanning the
+phase, with its goals and deliverables in the description. Each deliverable
+becomes an event on its dueDate, or on the end date of its phase if it has
+none; deliverables of type milestone are titled as milestones. Event UIDs are
+built from the PRD, phase, and deliverable IDs, so a calendar subscribed to a
+re-exported file updates the events instead of duplicating them.
+
+By default, the output file is the input name with .ics in place of .json.`,
+	Example: `  splan requirements prd export ics myproduct.prd.json
+  splan requirements prd export ics myproduct.prd.json -o public/roadmap.ics`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDExportICS,
+}
 
-	// PRD filter flags
-	prdFilterCmd.Flags().StringVarP(&prdFilterFlags.output, "output", "o", "", "Output JSON file path (default: stdout)")
-	prdFilterCmd.Flags().StringSliceVarP(&prdFilterFlags.includeTags, "include", "i", nil, "Tags to include (comma-separated)")
-	prdFilterCmd.Flags().BoolVarP(&prdFilterFlags.matchAll, "all", "a", false, "Require ALL tags (AND logic) instead of ANY (OR logic)")
+func init() {
+	prdExportICSCmd.Flags().StringVarP(&prdExportICSFlags.output, "output", "o", "", "Output file path (default: input with .ics)")
 
-	// PRD score flags
-	prdScoreCmd.Flags().StringVarP(&prdScoreFlags.format, "format", "f", "terminal", "Output format (terminal, json, markdown)")
+	prdExportCmd.AddCommand(prdExportICSCmd)
 }
 
-func runPRDGenerate(cmd *cobra.Command, args []string) error {
+func runPRDExportICS(cmd *cobra.Command, args []string) error {
 	inputFile := args[0]
-
-	// Determine output file
-	output := prdGenerateFlags.output
-	if output == "" {
-		output = deriveOutputPath(inputFile)
+	var doc prd.Document
+	if err := readDocument(inputFile, &doc); err != nil {
+		return err
+	}
+	events := doc.Roadmap.CalendarEvents(doc.Metadata.ID)
+	if len(events) == 0 {
+		return fmt.Errorf("%s: the roadmap has no dated phases or deliverables", inputFile)
 	}
 
-	// Read input file
-	data, err := os.ReadFile(inputFile)
-	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+	name := "Roadmap"
+	if doc.Metadata.Title != "" {
+		name = doc.Metadata.Title + " Roadmap"
+	}
+	var buf bytes.Buffer
+	if err := calendar.WriteICSEvents(&buf, name, events, time.Now()); err != nil {
+		return fmt.Errorf("writing calendar: %w", err)
 	}
 
-	var doc prd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+	output := prdExportICSFlags.output
+	if output == "" {
+		output = deriveOutputPathExt(inputFile, ".ics")
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
 	}
+	runReport.Outputs = append(runReport.Outputs, output)
+	fmt.Printf("Generated: %s (%d events)\n", output, len(events))
+	return nil
+}
 
-	// Handle TOC option (default: enabled, disabled with --no-toc)
-	includeTOC := !prdGenerateFlags.noTOC
-	// Handle swimlane option (default: enabled, disabled with --no-swimlane)
-	includeSwimlane := !prdGenerateFlags.noSwimlane
+// ============================================================================
+// ID Commands
+// ============================================================================
 
-	opts := prd.MarkdownOptions{
-		IncludeFrontmatter:   !prdGenerateFlags.noFrontmatter,
-		Margin:               prdGenerateFlags.margin,
-		MainFont:             prdGenerateFlags.mainFont,
-		SansFont:             prdGenerateFlags.sansFont,
-		MonoFont:             prdGenerateFlags.monoFont,
-		FontFamily:           prdGenerateFlags.fontFamily,
-		DescriptionMaxLen:    prdGenerateFlags.descLen,
-		IncludeSwimlaneTable: includeSwimlane,
-		IncludeTOC:           &includeTOC,
-	}
+var prdIDsCmd = &cobra.Command{
+	Use:   "ids",
+	Short: "Manage the IDs of PRD items",
+	Long: `Assign and renumber the IDs of personas, user stories, requirements,
+phases, and the other items of a PRD.`,
+}
 
-	// Configure swimlane table options
-	if includeSwimlane {
-		tableOpts := prd.DefaultRoadmapTableOptions()
-		tableOpts.IncludeStatus = !prdGenerateFlags.swimlaneNoStatus
-		opts.RoadmapTableOptions = &tableOpts
-	}
+var prdIDsAssignFlags struct {
+	renumber bool
+	dryRun   bool
+}
 
-	markdown := doc.ToMarkdown(opts)
+var prdIDsAssignCmd = &cobra.Command{
+	Use:   "assign <input.json>...",
+	Short: "Fill in missing IDs following configurable patterns",
+	Long: `Give the items of a PRD that have no ID one following the pattern for
+their kind, such as FR-001 for functional requirements. Existing IDs are
+kept, and numbering continues after the highest number in use.
+
+Patterns map a JSONPath expression to an ID pattern in the "ids" section of
+the .splan.yaml found from the document's directory:
+
+  ids:
+    patterns:
+      prd:
+        $.userStories[*]: US-{personaId:upper}-{n:2}
+        $.requirements.functional[*]: FR-{n:3}
+
+{n} is the item's number and {n:3} pads it to three digits. {parent} is the
+ID of the enclosing item, such as the user story of an acceptance criterion,
+and {field} is the value of a member of the item, with {field:upper} and
+{field:lower} changing its case. Setting the patterns of a type replaces the
+built-in ones.
+
+With --renumber, the IDs that follow their pattern are also renumbered from 1
+in document order, closing the gaps left by deleted items, and the references
+to them, such as the userStoryIds of requirements, are updated.`,
+	Example: `  splan requirements prd ids assign myproduct.prd.json
+  splan requirements prd ids assign myproduct.prd.json --renumber --dry-run
+  splan requirements prd ids assign specs/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPRDIDsAssign,
+}
 
-	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
-		return fmt.Errorf("writing output file: %w", err)
-	}
+func init() {
+	prdIDsAssignCmd.Flags().BoolVar(&prdIDsAssignFlags.renumber, "renumber", false, "Also renumber IDs that follow their pattern, closing gaps")
+	prdIDsAssignCmd.Flags().BoolVar(&prdIDsAssignFlags.dryRun, "dry-run", false, "Print the changes without writing the files")
 
-	fmt.Printf("Generated: %s\n", output)
-	return nil
+	prdIDsCmd.AddCommand(prdIDsAssignCmd)
+	prdCmd.AddCommand(prdIDsCmd)
 }
 
-func runPRDValidate(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
+func runPRDIDsAssign(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd"), assignPRDIDsFile)
+}
 
-	data, err := os.ReadFile(inputFile)
+func assignPRDIDsFile(inputFile string, stdout, stderr io.Writer) error {
+	cfg := ids.DefaultConfig()
+	configFile, err := lint.FindConfig(filepath.Dir(inputFile))
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return err
+	}
+	if configFile != "" {
+		if cfg, err = ids.LoadConfig(configFile); err != nil {
+			return err
+		}
 	}
 
 	var doc prd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+	src, err := readSourceDocument(inputFile, &doc, stderr)
+	if err != nil {
+		return err
+	}
+	a, err := ids.Assign(src.expanded, ids.AssignOptions{
+		Patterns:   cfg.Patterns["prd"],
+		Renumber:   prdIDsAssignFlags.renumber,
+		References: append(slices.Clone(ids.ReferenceFields["prd"]), ids.ExternalReferenceFields["prd"]...),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", inputFile, err)
+	}
+	if len(a.Changes) == 0 {
+		fmt.Fprintf(stdout, "%s: all IDs assigned\n", inputFile)
+		return nil
+	}
+	if !prdIDsAssignFlags.dryRun {
+		if err := patchDocumentInPlace(inputFile, src, a.Patch); err != nil {
+			return err
+		}
 	}
+	for _, c := range a.Changes {
+		fmt.Fprintf(stdout, "%s: %s\n", inputFile, c)
+	}
+	verb := "Updated"
+	if prdIDsAssignFlags.dryRun {
+		verb = "Would update"
+	}
+	fmt.Fprintf(stdout, "%s %d ID(s) and %d reference(s) in %s\n", verb, len(a.Changes), a.References, inputFile)
+	return nil
+}
 
-	var errors []string
+// ============================================================================
+// Snippet Commands
+// ============================================================================
 
-	if doc.Metadata.ID == "" {
-		errors = append(errors, "metadata.id is required")
+var snippetsCmd = &cobra.Command{
+	Use:   "snippets",
+	Short: "Reuse standard content across documents",
+	Long: `Manage the workspace snippet library: reusable content, such as a standard
+set of non-functional requirements, a security model, or boilerplate
+assumptions, that documents reference by ID.
+
+The library files are listed under "snippets" in the workspace manifest
+(splan.workspace.json). A document references a snippet with an object whose
+only member is "$snippet":
+
+  "nonFunctional": [{"$snippet": "standard-nfrs"}]
+
+References are expanded whenever a document is read, so validate, generate,
+and the other commands see the snippet content. In a list, a snippet whose
+content is a list adds its items in place of the reference.
+
+Each expansion is recorded in metadata.snippets with a digest of the content,
+so PRD, MRD, and TRD validate warn when expanded content is edited in the
+document or the snippet changes in the library.`,
+}
+
+var snippetsListCmd = &cobra.Command{
+	Use:   "list [dir]",
+	Short: "List the snippets of the workspace library",
+	Example: `  splan snippets list
+  splan snippets list specs/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSnippetsList,
+}
+
+var snippetsExpandCmd = &cobra.Command{
+	Use:   "expand <input.json>...",
+	Short: "Write snippet content into PRDs, MRDs, and TRDs in place",
+	Long: `Replace the snippet references of PRDs, MRDs, and TRDs with the snippet
+content and write the documents back, so they can be shared without the
+library. The expansions are recorded in metadata.snippets, so edits to the
+content are still reported.
+
+As with "splan fix", a document is written from its parsed form, so fields
+outside the schema are dropped.`,
+	Example: `  splan snippets expand checkout.prd.json
+  splan snippets expand specs/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSnippetsExpand,
+}
+
+var snippetsCheckCmd = &cobra.Command{
+	Use:   "check <input.json>...",
+	Short: "Report expanded snippet content that was edited or is out of date",
+	Long: `Compare the content PRDs, MRDs, and TRDs took from the snippet library
+with the digests recorded in metadata.snippets and with the library, and
+report content edited in the document (snippet-drift), snippets changed in
+the library since they were expanded (snippet-outdated), and snippets no
+longer in the library (snippet-unknown). Exits with an error if any are
+found.`,
+	Example: `  splan snippets check checkout.prd.json
+  splan snippets check specs/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSnippetsCheck,
+}
+
+func init() {
+	snippetsCmd.AddCommand(snippetsListCmd)
+	snippetsCmd.AddCommand(snippetsExpandCmd)
+	snippetsCmd.AddCommand(snippetsCheckCmd)
+	rootCmd.AddCommand(snippetsCmd)
+}
+
+func runSnippetsList(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
 	}
-	if doc.Metadata.Title == "" {
-		errors = append(errors, "metadata.title is required")
+	m, err := workspace.FindManifest(dir)
+	if err != nil {
+		return err
 	}
-	if doc.Metadata.Version == "" {
-		errors = append(errors, "metadata.version is required")
+	if m == nil {
+		return fmt.Errorf("no %s found in %s or its parent directories", workspace.ManifestFile, dir)
 	}
-	if len(doc.Metadata.Authors) == 0 {
-		errors = append(errors, "metadata.authors is required (at least one author)")
+	lib, err := m.SnippetLibrary()
+	if err != nil {
+		return err
 	}
-	if doc.ExecutiveSummary.ProblemStatement == "" {
-		errors = append(errors, "executive_summary.problem_statement is required")
+	if lib == nil || len(lib.Snippets) == 0 {
+		fmt.Println("No snippets.")
+		return nil
 	}
-	if doc.ExecutiveSummary.ProposedSolution == "" {
-		errors = append(errors, "executive_summary.proposed_solution is required")
+	for _, s := range lib.Snippets {
+		content := "value"
+		if v, err := query.Decode(s.Content); err == nil {
+			switch x := v.(type) {
+			case []any:
+				content = fmt.Sprintf("%d item(s)", len(x))
+			case *query.Object:
+				content = "object"
+			}
+		}
+		fmt.Printf("%s (%s)", s.ID, content)
+		if s.Title != "" {
+			fmt.Printf(": %s", s.Title)
+		}
+		fmt.Println()
 	}
-	if len(doc.Personas) == 0 {
-		errors = append(errors, "personas is required (at least one persona)")
+	return nil
+}
+
+func runSnippetsExpand(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd", "mrd", "trd"), expandSnippetsFile)
+}
+
+func expandSnippetsFile(inputFile string, stdout, stderr io.Writer) error {
+	data, err := readSourceJSON(inputFile)
+	if err != nil {
+		return err
 	}
-	if len(doc.UserStories) == 0 {
-		errors = append(errors, "user_stories is required (at least one user story)")
+	lib, err := workspace.SnippetsFor(inputFile)
+	if err != nil {
+		return err
 	}
-	if len(doc.Roadmap.Phases) == 0 {
-		errors = append(errors, "roadmap.phases is required (at least one phase)")
+	_, refs, err := snippets.Expand(data, lib)
+	if err != nil {
+		return fmt.Errorf("%s: %w", inputFile, err)
+	}
+	if len(refs) == 0 {
+		fmt.Fprintf(stdout, "%s: no snippet references\n", inputFile)
+		return nil
 	}
 
-	if len(errors) > 0 {
-		fmt.Fprintf(os.Stderr, "Validation failed for %s:\n", inputFile)
-		for _, e := range errors {
-			fmt.Fprintf(os.Stderr, "  - %s\n", e)
+	var doc any
+	switch documentTypeFromPath(inputFile) {
+	case "prd":
+		doc = &prd.Document{}
+	case "mrd":
+		doc = &mrd.Document{}
+	case "trd":
+		doc = &trd.Document{}
+	default:
+		return fmt.Errorf("%s: snippets can be expanded in place in PRDs, MRDs, and TRDs", inputFile)
+	}
+	src, err := readSourceDocument(inputFile, doc, stderr)
+	if err != nil {
+		return err
+	}
+	// Keep the expansions when writing the document back.
+	src.refs = nil
+	if err := writeDocumentInPlace(inputFile, src, doc); err != nil {
+		return err
+	}
+	for _, r := range refs {
+		where := r.Pointer
+		if r.Count > 0 {
+			where = fmt.Sprintf("%s (%d item(s))", r.Pointer, r.Count)
 		}
-		return fmt.Errorf("validation failed with %d errors", len(errors))
+		fmt.Fprintf(stdout, "Expanded %s: %q at %s\n", inputFile, r.ID, where)
 	}
-
-	fmt.Printf("Valid PRD: %s\n", inputFile)
-	fmt.Printf("  Title: %s\n", doc.Metadata.Title)
-	fmt.Printf("  Version: %s\n", doc.Metadata.Version)
-	fmt.Printf("  Personas: %d\n", len(doc.Personas))
-	fmt.Printf("  User Stories: %d\n", len(doc.UserStories))
-	fmt.Printf("  Functional Requirements: %d\n", len(doc.Requirements.Functional))
-	fmt.Printf("  Non-Functional Requirements: %d\n", len(doc.Requirements.NonFunctional))
-	fmt.Printf("  Phases: %d\n", len(doc.Roadmap.Phases))
-
 	return nil
 }
 
-func runPRDCheck(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
+func runSnippetsCheck(cmd *cobra.Command, args []string) error {
+	return runFiles(args, documentMatcher("prd", "mrd", "trd"), checkSnippetsFile)
+}
 
-	data, err := os.ReadFile(inputFile)
+func checkSnippetsFile(inputFile string, stdout, stderr io.Writer) error {
+	issues, err := snippetIssues(inputFile)
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return err
+	}
+	recordIssues(inputFile, documentTypeFromPath(inputFile), issues)
+	if len(issues) == 0 {
+		fmt.Fprintf(stdout, "%s: snippets up to date\n", inputFile)
+		return nil
+	}
+	for _, m := range issueMessages(issues) {
+		fmt.Fprintf(stderr, "%s: %s\n", inputFile, m)
 	}
+	return fmt.Errorf("%d snippet issue(s) in %s", len(issues), inputFile)
+}
 
-	var doc prd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+// ============================================================================
+// Analytics Commands
+// ============================================================================
+
+var analyticsFlags struct {
+	output string
+	format string
+	table  string
+	noGit  bool
+}
+
+var analyticsCmd = &cobra.Command{
+	Use:   "analytics [dir]",
+	Short: "Report authorship and activity analytics for planning retrospectives",
+	Long: `Compute authorship and activity analytics for the planning documents
+under a directory, from their metadata and git history:
+
+  - teams: documents per team, average health score, documents approved,
+    average and median days from draft to approved, commits, contributors
+  - authors: documents each person is named an author or owner of,
+    documents they committed to, commits, last activity
+  - documents: the same figures for each document
+
+A document's team is its metadata team, or the team of its first author in
+the workspace people directory. Names from metadata and commits are matched
+to the directory, so one person counts once. A document is approved from
+the first commit in which its status is approved (PRD, MRD, TRD) or active
+(OKR, V2MOM, roadmap), or from its reviewedAt without git history, and in
+draft from its createdAt or first commit, whichever is earlier.
+
+Everything is computed locally; nothing is sent anywhere. --no-git skips
+the history and uses the metadata only.
+
+The report is printed to the terminal unless -o is given. The format is taken
+from --format, or inferred from the output file extension (.csv, .html,
+.json; terminal text otherwise). CSV holds the table given by --table.`,
+	Example: `  splan analytics ./plans
+  splan analytics ./plans -o analytics.html
+  splan analytics ./plans --format csv --table documents > documents.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAnalytics,
+}
+
+func init() {
+	analyticsCmd.Flags().StringVarP(&analyticsFlags.output, "output", "o", "", "Output file (default: stdout)")
+	analyticsCmd.Flags().StringVar(&analyticsFlags.format, "format", "", "Output format: terminal, csv, html, json (default: from output extension)")
+	analyticsCmd.Flags().StringVar(&analyticsFlags.table, "table", workspace.TableTeams, "Table written as CSV: "+strings.Join(workspace.AnalyticsTables, ", "))
+	analyticsCmd.Flags().BoolVar(&analyticsFlags.noGit, "no-git", false, "Do not read git history")
+
+	rootCmd.AddCommand(analyticsCmd)
+}
+
+func runAnalytics(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
 	}
 
-	report := doc.CheckCompleteness()
+	format := strings.ToLower(analyticsFlags.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(analyticsFlags.output)) {
+		case ".csv":
+			format = "csv"
+		case ".html", ".htm":
+			format = "html"
+		case ".json":
+			format = "json"
+		default:
+			format = "terminal"
+		}
+	}
 
-	if prdCheckFlags.json {
-		output, err := json.MarshalIndent(report, "", "  ")
+	paths, err := workspace.Discover(root)
+	if err != nil {
+		return err
+	}
+	report, err := workspace.BuildAnalytics(cmd.Context(), root, paths, workspace.AnalyticsOptions{
+		Options: workspace.Options{Lenient: rootFlags.lenient},
+		Git:     !analyticsFlags.noGit,
+	})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "terminal", "text":
+		buf.WriteString(report.ToText())
+	case "csv":
+		if err := report.WriteCSV(&buf, analyticsFlags.table); err != nil {
+			return err
+		}
+	case "html":
+		data, err := report.Page().Render()
 		if err != nil {
-			return fmt.Errorf("marshaling report: %w", err)
+			return err
 		}
-		fmt.Println(string(output))
-	} else {
-		fmt.Print(report.FormatReport())
+		buf.Write(data)
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling analytics: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q (valid: terminal, csv, html, json)", analyticsFlags.format)
 	}
 
-	// Return non-zero exit code if PRD has critical issues
-	if report.Grade == "F" {
-		return fmt.Errorf("PRD completeness check failed (Grade: F)")
+	if analyticsFlags.output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
 	}
-
+	if err := os.WriteFile(analyticsFlags.output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	fmt.Printf("Generated: %s\n", analyticsFlags.output)
 	return nil
 }
 
-func runPRDScore(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
+// ============================================================================
+// Badge Commands
+// ============================================================================
 
-	data, err := os.ReadFile(inputFile)
+var badgeFlags struct {
+	output   string
+	metric   string
+	imageURL string
+	link     string
+}
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge <doc.prd.json>",
+	Short: "Generate a quality badge for a PRD",
+	Long: `Generate a shields.io-style SVG badge of a PRD's quality, so repositories
+can display it in their READMEs and rollup dashboards.
+
+Metrics:
+  score - weighted score out of 10, colored by the scoring decision:
+          green to approve, yellow to revise, orange for human review,
+          red to reject (default)
+  grade - completeness grade and percentage, green (A) to red (F)
+
+The badge is drawn locally; nothing is sent to shields.io. With -o, the SVG
+is written to the file and a Markdown snippet showing it is printed; the
+image URL is the output path unless --image-url is given. Without -o, the
+SVG is printed.`,
+	Example: `  splan badge product.prd.json -o badge.svg
+  splan badge product.prd.json -o docs/grade.svg --metric grade --link product.md
+  splan badge product.prd.json -o badge.svg --image-url https://example.com/badges/product.svg`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBadge,
+}
+
+func init() {
+	badgeCmd.Flags().StringVarP(&badgeFlags.output, "output", "o", "", "Output SVG file (default: stdout)")
+	badgeCmd.Flags().StringVar(&badgeFlags.metric, "metric", badge.MetricScore, "Metric shown: score, grade")
+	badgeCmd.Flags().StringVar(&badgeFlags.imageURL, "image-url", "", "Image URL in the Markdown snippet (default: the output path)")
+	badgeCmd.Flags().StringVar(&badgeFlags.link, "link", "", "Link target of the badge in the Markdown snippet")
+
+	rootCmd.AddCommand(badgeCmd)
+}
+
+func runBadge(cmd *cobra.Command, args []string) error {
+	var doc prd.Document
+	if err := readDocument(args[0], &doc); err != nil {
+		return err
+	}
+	b, err := badge.PRD(&doc, badgeFlags.metric)
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return err
 	}
 
-	var doc prd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+	if badgeFlags.output == "" {
+		fmt.Print(b.SVG())
+		return nil
+	}
+	if dir := filepath.Dir(badgeFlags.output); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(badgeFlags.output, []byte(b.SVG()), 0600); err != nil {
+		return fmt.Errorf("writing badge: %w", err)
 	}
+	runReport.Outputs = append(runReport.Outputs, badgeFlags.output)
+	fmt.Printf("Generated: %s (%s)\n", badgeFlags.output, b.Text())
 
-	// Generate evaluation report from deterministic scoring
-	report := prd.ScoreToEvaluationReport(&doc, inputFile)
+	imageURL := badgeFlags.imageURL
+	if imageURL == "" {
+		imageURL = filepath.ToSlash(badgeFlags.output)
+	}
+	fmt.Println()
+	fmt.Println(b.Markdown(imageURL, badgeFlags.link))
+	return nil
+}
+
+// ============================================================================
+// Editor Commands
+// ============================================================================
+
+var editorCmd = &cobra.Command{
+	Use:   "editor",
+	Short: "Set up code editors for hand-editing documents",
+}
+
+var editorSetupFlags struct {
+	vscode    bool
+	schemaDir string
+}
+
+var editorSetupCmd = &cobra.Command{
+	Use:   "setup [dir]",
+	Short: "Write schema mappings and snippets for an editor",
+	Long: `Set up an editor in a workspace (default: the current directory) so that
+planning documents are completed and checked as they are typed.
+
+With --vscode, it writes:
+
+  .vscode/schemas/*.schema.json  The JSON Schemas, generated by this version
+  .vscode/settings.json          json.schemas and yaml.schemas mappings from
+                                 *.prd.json, *.mrd.json, *.trd.json,
+                                 *.okr.json, *.v2mom.json, and *.roadmap.json
+                                 (and their .yaml and .yml forms) to the
+                                 schemas
+  .vscode/splan.code-snippets    Snippets for personas, user stories,
+                                 requirements, risks, phases, deliverables,
+                                 objectives, key results, and V2MOM methods,
+                                 measures, and obstacles; type "splan-" in a
+                                 JSON file to list them
+
+Other settings are kept. Settings with comments are left untouched and
+reported, since rewriting them would drop the comments; the schemas and
+snippets are still written. The YAML mappings need the Red Hat YAML
+extension.
+
+Files whose content is unchanged are not rewritten, so run it again after
+upgrading splan to refresh the schemas.`,
+	Example: `  splan editor setup --vscode
+  splan editor setup ~/plans --vscode
+  splan editor setup --vscode --schema-dir schema`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEditorSetup,
+}
+
+func init() {
+	editorSetupCmd.Flags().BoolVar(&editorSetupFlags.vscode, "vscode", false, "Set up Visual Studio Code")
+	editorSetupCmd.Flags().StringVar(&editorSetupFlags.schemaDir, "schema-dir", ide.DefaultVSCodeSchemaDir, "Schema directory, relative to the workspace")
+
+	editorCmd.AddCommand(editorSetupCmd)
+	rootCmd.AddCommand(editorCmd)
+}
+
+func runEditorSetup(cmd *cobra.Command, args []string) error {
+	if !editorSetupFlags.vscode {
+		return fmt.Errorf("choose an editor to set up: --vscode")
+	}
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	res, err := ide.SetupVSCode(ide.VSCodeOptions{Dir: dir, SchemaDir: editorSetupFlags.schemaDir})
+	if err != nil {
+		return err
+	}
+	for _, result := range res.Files {
+		printSchemaResult(result)
+		if result.Written {
+			runReport.Outputs = append(runReport.Outputs, result.Path)
+		}
+	}
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	return nil
+}
+
+// ============================================================================
+// Schema Commands
+// ============================================================================
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Generate JSON Schema files from Go types",
+	Long: `Commands for generating JSON Schema files from Go type definitions.
+
+This implements the Go-first approach where Go structs are the source of truth
+and JSON Schema is generated from them.`,
+}
+
+var schemaGenerateFlags struct {
+	output      string
+	docType     string
+	incremental bool
+}
+
+var schemaGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate JSON Schema from Go types",
+	Long: `Generate JSON Schema files from Go type definitions.
+
+By default, generates all schema files (PRD, MRD, TRD, OKR, V2MOM, roadmap) to the schema/ directory.
+Use --type to generate a specific document type's schema.
+
+With --incremental, files whose content would not change are left untouched,
+which keeps timestamps stable for build tools that watch the schema directory.`,
+	Example: `  splan schema generate
+  splan schema generate -o ./schema/
+  splan schema generate -o ./schema/ --incremental
+  splan schema generate --type prd -o prd.schema.json
+  splan schema generate --type trd -o trd.schema.json
+  splan schema generate --type okr -o okr.schema.json
+  splan schema generate --type v2mom -o v2mom.schema.json
+  splan schema generate --type roadmap -o roadmap.schema.json`,
+	RunE: runSchemaGenerate,
+}
+
+func init() {
+	schemaGenerateCmd.Flags().StringVarP(&schemaGenerateFlags.output, "output", "o", ".", "Output directory or file path")
+	schemaGenerateCmd.Flags().StringVarP(&schemaGenerateFlags.docType, "type", "t", "all", "Document type to generate (prd, okr, v2mom, roadmap, mrd, trd, or all)")
+	schemaGenerateCmd.Flags().BoolVar(&schemaGenerateFlags.incremental, "incremental", false, "Skip writing files whose content is unchanged")
+
+	schemaCmd.AddCommand(schemaGenerateCmd)
+}
 
-	switch strings.ToLower(prdScoreFlags.format) {
-	case "json":
-		output, err := json.MarshalIndent(report, "", "  ")
+func runSchemaGenerate(cmd *cobra.Command, args []string) error {
+	gen := schema.NewGenerator()
+	gen.Incremental = schemaGenerateFlags.incremental
+	output := schemaGenerateFlags.output
+	docType := strings.ToLower(schemaGenerateFlags.docType)
+
+	switch docType {
+	case "prd", "mrd", "trd", "okr", "v2mom", "roadmap":
+		// Single schema
+		path := output
+		if isDir(output) {
+			path = filepath.Join(output, docType+".schema.json")
+		}
+		result, err := gen.WriteSchema(docType, path)
 		if err != nil {
-			return fmt.Errorf("marshaling report: %w", err)
+			return fmt.Errorf("generating %s schema: %w", strings.ToUpper(docType), err)
 		}
-		fmt.Println(string(output))
-
-	case "markdown":
-		fmt.Print(formatEvaluationReportMarkdown(report))
+		printSchemaResult(result)
 
-	case "terminal", "":
-		renderer := terminal.New(os.Stdout)
-		if err := renderer.Render(report); err != nil {
-			return fmt.Errorf("rendering report: %w", err)
+	case "all":
+		// All schemas to directory
+		dir := output
+		if !isDir(dir) {
+			dir = filepath.Dir(output)
+		}
+		results, err := gen.GenerateAllFiles(dir)
+		if err != nil {
+			return fmt.Errorf("generating schemas: %w", err)
+		}
+		if gen.Incremental {
+			for _, result := range results {
+				printSchemaResult(result)
+			}
 		}
+		fmt.Printf("Generated schemas in: %s\n", dir)
 
 	default:
-		return fmt.Errorf("unknown format: %s (expected terminal, json, or markdown)", prdScoreFlags.format)
+		return fmt.Errorf("unknown document type: %s (expected prd, okr, v2mom, roadmap, mrd, trd, or all)", docType)
 	}
 
-	// Return non-zero exit code if PRD has blocking issues
-	if !report.Decision.Passed {
-		return fmt.Errorf("PRD evaluation: %s", report.Decision.Rationale)
+	return nil
+}
+
+func printSchemaResult(result schema.FileResult) {
+	if result.Written {
+		fmt.Printf("Generated: %s\n", result.Path)
+	} else {
+		fmt.Printf("Unchanged: %s\n", result.Path)
 	}
+}
 
-	return nil
+var validateFlags struct {
+	against string
+	docType string
+	json    bool
 }
 
-func runPRDFilter(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
+var validateCmd = &cobra.Command{
+	Use:   "validate FILE...",
+	Short: "Validate a document against its JSON Schema",
+	Long: `Validate a JSON or YAML document against a JSON Schema.
+
+This checks the whole document against the schema: types, enums, date-time
+formats, and unknown properties. It complements the structural checks of
+the per-document validate commands.
+
+The schema is the file given with --against, or the embedded schema for
+--type. Without either, the type is taken from the filename suffix, as in
+checkout.prd.json or team.okr.yaml. Embedded schemas exist for prd, mrd,
+trd, okr, v2mom, and roadmap documents.
+
+Each violation is reported with its line and column in the original file.
+
+Given a directory or a glob, every document of a type with an embedded
+schema (or of --type) is validated in parallel, followed by a pass/fail
+summary.`,
+	Example: `  splan validate checkout.prd.json
+  splan validate checkout.prd.yaml --against schema/prd.schema.json
+  splan validate goals.json --type okr --json
+  splan validate docs/ --fail-fast`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runValidate,
+}
 
-	// Validate filter tags
-	if len(prdFilterFlags.includeTags) > 0 {
-		for _, tag := range prdFilterFlags.includeTags {
-			if err := prd.ValidateTag(tag); err != nil {
-				return fmt.Errorf("invalid filter tag: %w", err)
-			}
+func init() {
+	validateCmd.Flags().StringVar(&validateFlags.against, "against", "", "JSON Schema file to validate against")
+	validateCmd.Flags().StringVarP(&validateFlags.docType, "type", "t", "", "Document type whose embedded schema to use (prd, mrd, trd, okr, v2mom, roadmap)")
+	validateCmd.Flags().BoolVar(&validateFlags.json, "json", false, "Output violations as JSON")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	types := []string{validateFlags.docType}
+	switch {
+	case validateFlags.against != "":
+		types = []string{"prd", "mrd", "trd", "okr", "v2mom", "roadmap"}
+	case validateFlags.docType == "":
+		types = []string{"prd", "mrd", "trd", "okr", "v2mom", "roadmap"}
+	}
+	return runFiles(args, documentMatcher(types...), validateSchemaFile)
+}
+
+func validateSchemaFile(inputFile string, stdout, stderr io.Writer) error {
+	var validator *schema.Validator
+	var schemaName string
+	if validateFlags.against != "" {
+		data, err := os.ReadFile(validateFlags.against)
+		if err != nil {
+			return fmt.Errorf("reading schema: %w", err)
+		}
+		if validator, err = schema.NewValidator(data); err != nil {
+			return err
+		}
+		schemaName = validateFlags.against
+	} else {
+		docType := strings.ToLower(validateFlags.docType)
+		if docType == "" {
+			docType = documentTypeFromPath(inputFile)
 		}
+		if docType == "" {
+			return fmt.Errorf("cannot infer document type from %s (use --type or --against)", inputFile)
+		}
+		var err error
+		if validator, err = schema.ValidatorFor(docType); err != nil {
+			return err
+		}
+		schemaName = docType + ".schema.json"
 	}
 
-	// Read input file
+	format, err := inputFormat(inputFile)
+	if err != nil {
+		return err
+	}
 	data, err := os.ReadFile(inputFile)
 	if err != nil {
 		return fmt.Errorf("reading input file: %w", err)
 	}
-
-	var doc prd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
-	}
-
-	// Apply filter
-	var filtered prd.Document
-	if len(prdFilterFlags.includeTags) > 0 {
-		if prdFilterFlags.matchAll {
-			filtered = doc.FilterByTagsAll(prdFilterFlags.includeTags...)
-		} else {
-			filtered = doc.FilterByTags(prdFilterFlags.includeTags...)
-		}
+	var violations []schema.Violation
+	if format == yamlconv.FormatYAML {
+		violations, err = validator.ValidateYAML(data)
 	} else {
-		filtered = doc
+		violations, err = validator.Validate(data)
 	}
-
-	// Marshal output
-	output, err := json.MarshalIndent(filtered, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling filtered JSON: %w", err)
+		return err
+	}
+	issues := make([]validation.Issue, 0, len(violations))
+	for _, v := range violations {
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleSchema,
+			Severity: validation.SeverityError,
+			Pointer:  v.Pointer,
+			Message:  v.Message,
+			Line:     v.Line,
+			Column:   v.Column,
+		})
 	}
+	recordIssues(inputFile, documentTypeFromPath(inputFile), issues)
 
-	// Write output
-	if prdFilterFlags.output != "" {
-		if err := os.WriteFile(prdFilterFlags.output, output, 0600); err != nil {
-			return fmt.Errorf("writing output file: %w", err)
+	if validateFlags.json {
+		if violations == nil {
+			violations = []schema.Violation{}
 		}
-		fmt.Printf("Filtered PRD written to: %s\n", prdFilterFlags.output)
+		out, err := json.MarshalIndent(violations, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling violations: %w", err)
+		}
+		fmt.Fprintln(stdout, string(out))
 	} else {
-		fmt.Println(string(output))
+		for _, v := range violations {
+			fmt.Fprintf(stdout, "%s:%s\n", inputFile, v)
+		}
 	}
 
+	if len(violations) > 0 {
+		return fmt.Errorf("schema validation failed with %d violation(s)", len(violations))
+	}
+	if !validateFlags.json {
+		fmt.Fprintf(stdout, "Valid: %s (schema: %s)\n", inputFile, schemaName)
+	}
 	return nil
 }
 
-func formatEvaluationReportMarkdown(report *evaluation.EvaluationReport) string {
-	var b strings.Builder
+// documentTypeFromPath returns the document type from a filename such as
+// "checkout.prd.json" or "team.okr.yaml", or "" if there is none.
+func documentTypeFromPath(path string) string {
+	name := strings.ToLower(filepath.Base(path))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
 
-	b.WriteString("# PRD Evaluation Report\n\n")
-	b.WriteString(fmt.Sprintf("**Document**: %s\n", report.Metadata.Document))
-	if report.Metadata.DocumentTitle != "" {
-		b.WriteString(fmt.Sprintf("**Title**: %s\n", report.Metadata.DocumentTitle))
+// ============================================================================
+// Batch Processing
+// ============================================================================
+
+var batchFlags struct {
+	failFast bool
+	jobs     int
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{
+		validateCmd, prdValidateCmd, prdCheckCmd, prdScoreCmd, mrdValidateCmd,
+		trdValidateCmd, okrValidateCmd, v2momValidateCmd, roadmapValidateCmd,
+	} {
+		cmd.Flags().BoolVar(&batchFlags.failFast, "fail-fast", false, "With several files, stop at the first failure and skip the rest")
+		cmd.Flags().IntVarP(&batchFlags.jobs, "jobs", "j", 0, "With several files, how many to process in parallel (default: number of CPUs)")
 	}
-	b.WriteString(fmt.Sprintf("**Score**: %.1f / 10.0\n", report.WeightedScore))
-	b.WriteString(fmt.Sprintf("**Decision**: %s\n\n", strings.ToUpper(string(report.Decision.Status))))
+}
 
-	// Category scores table
-	b.WriteString("## Category Scores\n\n")
-	b.WriteString("| Category | Score | Weight | Status |\n")
-	b.WriteString("|----------|-------|--------|--------|\n")
-	for _, cs := range report.Categories {
-		status := "✅"
-		if cs.Status == evaluation.ScoreStatusWarn {
-			status = "⚠️"
-		} else if cs.Status == evaluation.ScoreStatusFail {
-			status = "❌"
+// fileCommand runs a command on one file, writing its output to stdout and
+// stderr.
+type fileCommand func(file string, stdout, stderr io.Writer) error
+
+// batchRun is set while runFiles processes several files.
+var batchRun bool
+
+// runFiles runs fn on the files named by args. A single file runs as it
+// always has. Directories, glob patterns, and several arguments expand to
+// the files accepted by match, which run in parallel. Each file's output is
+// printed in turn under its name, followed by a pass/fail summary. With
+// --format json or sarif, validate commands write one structured report
+// instead.
+func runFiles(args []string, match func(string) bool, fn fileCommand) error {
+	if structuredValidation() {
+		return runValidationFiles(args, match, fn)
+	}
+	if len(args) == 1 && !batch.IsPattern(args[0]) {
+		if info, err := os.Stat(args[0]); err != nil || !info.IsDir() {
+			return fn(args[0], os.Stdout, os.Stderr)
 		}
-		b.WriteString(fmt.Sprintf("| %s | %.1f | %.0f%% | %s |\n",
-			cs.Category, cs.Score, cs.Weight*100, status))
 	}
-	b.WriteString("\n")
+	files, err := batch.Expand(args, match)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no documents found in %s", strings.Join(args, " "))
+	}
+	if prdCheckFlags.fixPatch != "" || prdScoreFlags.fixPatch != "" {
+		return fmt.Errorf("--fix-patch writes one patch and needs a single file, not %d", len(files))
+	}
 
-	// Findings by severity
-	if len(report.Findings) > 0 {
-		b.WriteString("## Findings\n\n")
-		for _, sev := range evaluation.AllSeverities() {
-			sevFindings := []evaluation.Finding{}
-			for _, f := range report.Findings {
-				if f.Severity == sev {
-					sevFindings = append(sevFindings, f)
-				}
+	batchRun = true
+	results := batch.Run(files, batch.Options{
+		Jobs:     batchFlags.jobs,
+		FailFast: batchFlags.failFast,
+		OnResult: func(r batch.Result) {
+			if r.Skipped {
+				return
 			}
-			if len(sevFindings) > 0 {
-				b.WriteString(fmt.Sprintf("### %s %s\n\n", sev.Icon(), strings.ToUpper(string(sev))))
-				for _, f := range sevFindings {
-					b.WriteString(fmt.Sprintf("- **[%s]** %s\n", f.Category, f.Title))
-					if f.Recommendation != "" {
-						b.WriteString(fmt.Sprintf("  - Fix: %s\n", f.Recommendation))
-					}
-					if f.Owner != "" {
-						b.WriteString(fmt.Sprintf("  - Owner: %s\n", f.Owner))
-					}
-				}
-				b.WriteString("\n")
+			fmt.Printf("==> %s <==\n", r.File)
+			_, _ = os.Stdout.Write(r.Output)
+			if r.Err != nil {
+				fmt.Printf("Error: %v\n", r.Err)
 			}
+			fmt.Println()
+		},
+	}, func(file string, w io.Writer) error {
+		return fn(file, w, w)
+	})
+
+	summary := batch.Summarize(results)
+	fmt.Printf("Summary: %s\n", summary)
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			fmt.Printf("  SKIP %s\n", r.File)
+		case r.Err != nil:
+			fmt.Printf("  FAIL %s: %v\n", r.File, r.Err)
 		}
 	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d files failed", summary.Failed, summary.Total())
+	}
+	return nil
+}
 
-	// Next steps
-	b.WriteString("## Next Steps\n\n")
-	if len(report.NextSteps.Immediate) > 0 {
-		b.WriteString("### Immediate Actions\n\n")
-		for _, action := range report.NextSteps.Immediate {
-			b.WriteString(fmt.Sprintf("- [ ] 🔴 %s\n", action.Action))
+// documentMatcher accepts JSON and YAML files of the given document types,
+// named like checkout.prd.json or team.okr.yaml.
+func documentMatcher(types ...string) func(string) bool {
+	return func(path string) bool {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".yaml", ".yml":
+			return slices.Contains(types, documentTypeFromPath(path))
 		}
-		b.WriteString("\n")
+		return false
 	}
+}
 
-	if len(report.NextSteps.Recommended) > 0 {
-		b.WriteString("### Recommended\n\n")
-		for _, action := range report.NextSteps.Recommended {
-			b.WriteString(fmt.Sprintf("- [ ] %s\n", action.Action))
+var runReportMu sync.Mutex
+
+// recordErrors adds the validation problems found in file to the
+// notification. With several files, each problem is prefixed with its file.
+func recordErrors(file string, problems []string) {
+	runReportMu.Lock()
+	defer runReportMu.Unlock()
+	for _, p := range problems {
+		if batchRun {
+			p = file + ": " + p
 		}
-		b.WriteString("\n")
+		runReport.Errors = append(runReport.Errors, p)
 	}
+}
 
-	b.WriteString("### Re-run Evaluation\n\n")
-	b.WriteString(fmt.Sprintf("```bash\n%s\n```\n", report.NextSteps.RerunCommand))
-
-	return b.String()
+// recordScore adds a score to the notification. With several files, the
+// lowest score is reported, and the run passes only if every file passed.
+func recordScore(score float64, passed bool) {
+	runReportMu.Lock()
+	defer runReportMu.Unlock()
+	if runReport.Score == nil || score < *runReport.Score {
+		runReport.Score = &score
+	}
+	if runReport.Passed == nil || !passed {
+		runReport.Passed = &passed
+	}
 }
 
 // ============================================================================
-// MRD Commands
+// Validation Output
 // ============================================================================
 
-var mrdCmd = &cobra.Command{
-	Use:   "mrd",
-	Short: "Work with Market Requirements Documents",
-	Long:  `Commands for generating and validating Market Requirements Documents (MRD).`,
+var validateOutput struct {
+	format string
 }
 
-var mrdGenerateFlags generateFlags
-
-var mrdGenerateCmd = &cobra.Command{
-	Use:   "generate <input.json>",
-	Short: "Convert MRD JSON to markdown",
-	Long: `Generate markdown from a Market Requirements Document (MRD).
-
-The output includes YAML frontmatter compatible with Pandoc for PDF generation.
-By default, the output file has the same name as the input with a .md extension.`,
-	Example: `  splan requirements mrd generate market-analysis.mrd.json
-  splan requirements mrd generate market.json -o output.md
-  splan requirements mrd generate market.json --no-frontmatter`,
-	Args: cobra.ExactArgs(1),
-	RunE: runMRDGenerate,
+func init() {
+	for _, cmd := range []*cobra.Command{
+		validateCmd, prdValidateCmd, mrdValidateCmd, trdValidateCmd,
+		okrValidateCmd, v2momValidateCmd, roadmapValidateCmd, lintCmd,
+		peopleCheckCmd,
+	} {
+		cmd.Flags().StringVar(&validateOutput.format, "format", "text", "Output format: text, json, sarif")
+	}
+	for _, cmd := range []*cobra.Command{prdValidateCmd, mrdValidateCmd, trdValidateCmd} {
+		cmd.Flags().BoolVar(&validateFixFlags.fix, "fix", false, "Renumber duplicate and conflicting IDs and write the document back before validating")
+	}
 }
 
-var mrdValidateCmd = &cobra.Command{
-	Use:     "validate <input.json>",
-	Short:   "Validate MRD structure",
-	Long:    `Validate a Market Requirements Document by parsing it and checking required fields.`,
-	Example: `  splan requirements mrd validate market-analysis.mrd.json`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runMRDValidate,
+var validateFixFlags struct {
+	fix bool
 }
 
-func init() {
-	// MRD generate flags
-	mrdGenerateCmd.Flags().StringVarP(&mrdGenerateFlags.output, "output", "o", "", "Output markdown file path (default: input with .md extension)")
-	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.margin, "margin", "2cm", "Page margin for Pandoc")
-	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.mainFont, "mainfont", "Helvetica", "Main font family")
-	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.sansFont, "sansfont", "Helvetica", "Sans-serif font family")
-	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.monoFont, "monofont", "Courier New", "Monospace font family")
-	mrdGenerateCmd.Flags().StringVar(&mrdGenerateFlags.fontFamily, "fontfamily", "helvet", "LaTeX font family")
-	mrdGenerateCmd.Flags().BoolVar(&mrdGenerateFlags.noFrontmatter, "no-frontmatter", false, "Disable YAML frontmatter generation")
-
-	mrdCmd.AddCommand(mrdGenerateCmd)
-	mrdCmd.AddCommand(mrdValidateCmd)
+// idIssues returns the duplicate and conflicting IDs of doc, leaving out
+// those a type-specific check already reported at the same pointer.
+func idIssues(doc any, reported []validation.Issue) ([]validation.Issue, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	found, err := ids.Check(data)
+	if err != nil {
+		return nil, err
+	}
+	return unreported(found, reported), nil
 }
 
-func runMRDGenerate(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
-
-	// Determine output file
-	output := mrdGenerateFlags.output
-	if output == "" {
-		output = deriveOutputPath(inputFile)
+// referenceIssues returns the references of doc, a document of docType read
+// from path, that name an ID defined neither in it nor, for references that
+// may cross documents, in its workspace. As with idIssues, pointers already
+// reported are left out.
+func referenceIssues(path, docType string, doc any, reported []validation.Issue) ([]validation.Issue, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
 	}
-
-	// Read input file
-	data, err := os.ReadFile(inputFile)
+	known, err := workspace.IDsFor(path)
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return nil, err
+	}
+	found, err := ids.CheckReferences(data, ids.ReferencesFor(docType, known))
+	if err != nil {
+		return nil, err
 	}
+	return unreported(found, reported), nil
+}
 
-	var doc mrd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+// unreported returns the issues of found at pointers no issue of reported
+// is at.
+func unreported(found, reported []validation.Issue) []validation.Issue {
+	seen := make(map[string]bool, len(reported))
+	for _, is := range reported {
+		seen[is.Pointer] = true
+	}
+	var issues []validation.Issue
+	for _, is := range found {
+		if !seen[is.Pointer] {
+			issues = append(issues, is)
+		}
 	}
+	return issues
+}
 
-	opts := mrd.MarkdownOptions{
-		IncludeFrontmatter: !mrdGenerateFlags.noFrontmatter,
-		Margin:             mrdGenerateFlags.margin,
-		MainFont:           mrdGenerateFlags.mainFont,
-		SansFont:           mrdGenerateFlags.sansFont,
-		MonoFont:           mrdGenerateFlags.monoFont,
-		FontFamily:         mrdGenerateFlags.fontFamily,
+// fixDuplicateIDs renumbers the duplicate and conflicting IDs of the
+// document at path, read into doc without workspace defaults, and writes
+// it back if any changed.
+func fixDuplicateIDs(path string, doc any, stdout, stderr io.Writer) error {
+	src, err := readSourceDocument(path, doc, stderr)
+	if err != nil {
+		return err
 	}
-	markdown := doc.ToMarkdown(opts)
+	patch, changes, err := ids.Renumber(src.expanded)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	if err := patchDocumentInPlace(path, src, patch); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		fmt.Fprintf(stdout, "Fixed %s: %s\n", path, c)
+	}
+	return nil
+}
 
-	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
-		return fmt.Errorf("writing output file: %w", err)
+// snippetIssues warns of the content the document at path took from the
+// workspace snippet library that was edited since, or whose snippet
+// changed or was removed.
+func snippetIssues(path string) ([]validation.Issue, error) {
+	data, err := readSourceJSON(path)
+	if err != nil {
+		return nil, err
+	}
+	lib, err := workspace.SnippetsFor(path)
+	if err != nil {
+		return nil, err
+	}
+	if data, _, err = snippets.Expand(data, lib); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
+	return snippets.Check(data, lib)
+}
 
-	fmt.Printf("Generated: %s\n", output)
-	return nil
+// sourceWarnings returns the snippet and condition warnings validate
+// reports for the document at path.
+func sourceWarnings(path string) ([]validation.Issue, error) {
+	issues, err := snippetIssues(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readExpandedJSON(path)
+	if err != nil {
+		return nil, err
+	}
+	conds, err := conditions.Check(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, conds...), nil
 }
 
-func runMRDValidate(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
+var (
+	validationMu    sync.Mutex
+	validationFiles = make(map[string]validation.File)
+)
 
-	data, err := os.ReadFile(inputFile)
+// structuredValidation reports whether validation results are written as
+// one JSON or SARIF document instead of the plaintext report.
+func structuredValidation() bool {
+	return validateOutput.format != "" && validateOutput.format != "text"
+}
+
+// runValidationFiles runs a validate command on the files named by args
+// and writes the issues found in all of them as one JSON or SARIF document
+// on stdout. Files that cannot be read or parsed are reported with a
+// document issue.
+func runValidationFiles(args []string, match func(string) bool, fn fileCommand) error {
+	format := strings.ToLower(validateOutput.format)
+	if format != "json" && format != "sarif" {
+		return fmt.Errorf("invalid --format %q (expected text, json, or sarif)", validateOutput.format)
+	}
+	files, err := batch.Expand(args, match)
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return err
 	}
-
-	var doc mrd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+	if len(files) == 0 {
+		return fmt.Errorf("no documents found in %s", strings.Join(args, " "))
 	}
 
-	var errors []string
-
-	if doc.Metadata.ID == "" {
-		errors = append(errors, "metadata.id is required")
+	batchRun = len(files) > 1
+	results := batch.Run(files, batch.Options{
+		Jobs:     batchFlags.jobs,
+		FailFast: batchFlags.failFast,
+	}, func(file string, w io.Writer) error {
+		return fn(file, io.Discard, io.Discard)
+	})
+
+	var reported []validation.File
+	for _, r := range results {
+		if r.Skipped {
+			continue
+		}
+		validationMu.Lock()
+		f, ok := validationFiles[r.File]
+		validationMu.Unlock()
+		if !ok {
+			msg := "validation did not run"
+			if r.Err != nil {
+				msg = r.Err.Error()
+			}
+			f = validation.NewFile(r.File, documentTypeFromPath(r.File), []validation.Issue{{
+				RuleID:   validation.RuleDocument,
+				Severity: validation.SeverityError,
+				Message:  msg,
+			}})
+		}
+		reported = append(reported, f)
 	}
-	if doc.Metadata.Title == "" {
-		errors = append(errors, "metadata.title is required")
+	report := validation.NewReport(reported)
+
+	var out []byte
+	if format == "sarif" {
+		out, err = report.SARIF(validation.Tool{
+			Name:           "splan",
+			Version:        version,
+			InformationURI: "https://github.com/grokify/structured-plan",
+		})
+	} else {
+		out, err = report.JSON()
 	}
-	if doc.Metadata.Version == "" {
-		errors = append(errors, "metadata.version is required")
+	if err != nil {
+		return err
 	}
-	if len(doc.Metadata.Authors) == 0 {
-		errors = append(errors, "metadata.authors is required (at least one author)")
+	fmt.Println(string(out))
+
+	invalid := 0
+	for _, f := range report.Files {
+		if !f.Valid {
+			invalid++
+		}
 	}
-	if doc.ExecutiveSummary.MarketOpportunity == "" {
-		errors = append(errors, "executive_summary.market_opportunity is required")
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files failed validation", invalid, len(report.Files))
 	}
-	if doc.ExecutiveSummary.ProposedOffering == "" {
-		errors = append(errors, "executive_summary.proposed_offering is required")
+	return nil
+}
+
+// recordIssues stores the issues found in file for JSON and SARIF output.
+// Issues without a position are located by their pointer.
+func recordIssues(file, docType string, issues []validation.Issue) {
+	if !structuredValidation() {
+		return
 	}
-	if doc.MarketOverview.TAM.Value == "" {
-		errors = append(errors, "market_overview.tam.value is required")
+	var data []byte
+	format, err := inputFormat(file)
+	if err == nil {
+		data, err = os.ReadFile(file)
 	}
-	if len(doc.TargetMarket.PrimarySegments) == 0 {
-		errors = append(errors, "target_market.primary_segments is required (at least one segment)")
+	for i, is := range issues {
+		if is.Line == 0 && err == nil {
+			issues[i].Line, issues[i].Column = schema.Locate(data, format, is.Pointer)
+		}
 	}
-	if len(doc.CompetitiveLandscape.Competitors) == 0 {
-		errors = append(errors, "competitive_landscape.competitors is required (at least one competitor)")
+	validationMu.Lock()
+	defer validationMu.Unlock()
+	validationFiles[file] = validation.NewFile(file, docType, issues)
+}
+
+// requiredIssue reports a missing required field at the JSON pointer ptr.
+func requiredIssue(ptr, message string) validation.Issue {
+	return validation.Issue{
+		RuleID:   validation.RuleRequiredField,
+		Severity: validation.SeverityError,
+		Pointer:  ptr,
+		Message:  message,
 	}
-	if len(doc.MarketRequirements) == 0 {
-		errors = append(errors, "market_requirements is required (at least one requirement)")
+}
+
+// metadataIssues reports the review and lifecycle problems of a PRD, MRD,
+// or TRD.
+func metadataIssues(reviews, lifecycle []string) []validation.Issue {
+	var issues []validation.Issue
+	for _, problem := range reviews {
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleReviews,
+			Severity: validation.SeverityError,
+			Pointer:  "/metadata/reviews",
+			Message:  "metadata.reviews: " + problem,
+		})
 	}
-	if doc.Positioning.Statement == "" {
-		errors = append(errors, "positioning.statement is required")
+	for _, problem := range lifecycle {
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleLifecycle,
+			Severity: validation.SeverityError,
+			Pointer:  "/metadata/status",
+			Message:  "metadata.status: " + problem,
+		})
 	}
+	return issues
+}
 
-	if len(errors) > 0 {
-		fmt.Fprintf(os.Stderr, "Validation failed for %s:\n", inputFile)
-		for _, e := range errors {
-			fmt.Fprintf(os.Stderr, "  - %s\n", e)
-		}
-		return fmt.Errorf("validation failed with %d errors", len(errors))
+// pathIssue converts a finding of the OKR, V2MOM, or roadmap validator,
+// which locate problems by dotted path, to an issue.
+func pathIssue(docType, path, message string, isError bool) validation.Issue {
+	severity := validation.SeverityWarning
+	if isError {
+		severity = validation.SeverityError
+	}
+	return validation.Issue{
+		RuleID:   validation.PathRule(docType, path),
+		Severity: severity,
+		Pointer:  validation.PathPointer(path),
+		Message:  message,
 	}
+}
 
-	fmt.Printf("Valid MRD: %s\n", inputFile)
-	fmt.Printf("  Title: %s\n", doc.Metadata.Title)
-	fmt.Printf("  Version: %s\n", doc.Metadata.Version)
-	fmt.Printf("  TAM: %s\n", doc.MarketOverview.TAM.Value)
-	fmt.Printf("  Primary Segments: %d\n", len(doc.TargetMarket.PrimarySegments))
-	fmt.Printf("  Buyer Personas: %d\n", len(doc.TargetMarket.BuyerPersonas))
-	fmt.Printf("  Competitors: %d\n", len(doc.CompetitiveLandscape.Competitors))
-	fmt.Printf("  Market Requirements: %d\n", len(doc.MarketRequirements))
-	fmt.Printf("  Success Metrics: %d\n", len(doc.SuccessMetrics))
+// linkMessage returns the plaintext report line for a PRD link issue.
+func linkMessage(li prd.LinkIssue) string {
+	if li.Field == "" {
+		return li.Message
+	}
+	return li.Field + ": " + li.Message
+}
 
-	return nil
+// issueMessages returns the messages of issues, for the plaintext report.
+func issueMessages(issues []validation.Issue) []string {
+	messages := make([]string, len(issues))
+	for i, is := range issues {
+		messages[i] = is.Message
+	}
+	return messages
 }
 
 // ============================================================================
-// TRD Commands
+// Notifications
 // ============================================================================
 
-var trdCmd = &cobra.Command{
-	Use:   "trd",
-	Short: "Work with Technical Requirements Documents",
-	Long:  `Commands for generating and validating Technical Requirements Documents (TRD).`,
+// runReport collects the details a validate, score, or generate command
+// reports, for the webhook notification sent when it finishes.
+var runReport notify.Event
+
+// notifies reports whether runs of cmd are sent to the notification webhook:
+// validate, score, and generate commands, including the generate
+// subcommands such as html and docx.
+func notifies(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "validate", "score", "generate":
+			return true
+		}
+	}
+	return false
 }
 
-var trdGenerateFlags generateFlags
-
-var trdGenerateCmd = &cobra.Command{
-	Use:   "generate <input.json>",
-	Short: "Convert TRD JSON to markdown",
-	Long: `Generate markdown from a Technical Requirements Document (TRD).
-
-The output includes YAML frontmatter compatible with Pandoc for PDF generation.
-By default, the output file has the same name as the input with a .md extension.`,
-	Example: `  splan requirements trd generate architecture.trd.json
-  splan requirements trd generate tech-spec.json -o output.md
-  splan requirements trd generate tech-spec.json --no-frontmatter`,
-	Args: cobra.ExactArgs(1),
-	RunE: runTRDGenerate,
-}
+// sendNotification posts the result of cmd to the webhook set with
+// --notify-webhook or SPLAN_NOTIFY_WEBHOOK. Delivery failures are reported
+// as warnings and do not change the exit status.
+func sendNotification(cmd *cobra.Command, runErr error) {
+	url := rootFlags.notifyWebhook
+	if url == "" {
+		url = os.Getenv("SPLAN_NOTIFY_WEBHOOK")
+	}
+	if url == "" || cmd == nil || !notifies(cmd) {
+		return
+	}
+	secret := rootFlags.notifySecret
+	if secret == "" {
+		secret = os.Getenv("SPLAN_NOTIFY_SECRET")
+	}
 
-var trdValidateCmd = &cobra.Command{
-	Use:     "validate <input.json>",
-	Short:   "Validate TRD structure",
-	Long:    `Validate a Technical Requirements Document by parsing it and checking required fields.`,
-	Example: `  splan requirements trd validate architecture.trd.json`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runTRDValidate,
+	e := runReport
+	e.Command = cmd.CommandPath()
+	e.Files = cmd.Flags().Args()
+	e.Time = time.Now().UTC()
+	e.Version = version
+	e.Status = notify.StatusSucceeded
+	if runErr != nil {
+		e.Status = notify.StatusFailed
+		e.Error = runErr.Error()
+	}
+	if err := notify.NewWebhook(url, secret).Send(context.Background(), e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 }
 
-func init() {
-	// TRD generate flags
-	trdGenerateCmd.Flags().StringVarP(&trdGenerateFlags.output, "output", "o", "", "Output markdown file path (default: input with .md extension)")
-	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.margin, "margin", "2cm", "Page margin for Pandoc")
-	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.mainFont, "mainfont", "Helvetica", "Main font family")
-	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.sansFont, "sansfont", "Helvetica", "Sans-serif font family")
-	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.monoFont, "monofont", "Courier New", "Monospace font family")
-	trdGenerateCmd.Flags().StringVar(&trdGenerateFlags.fontFamily, "fontfamily", "helvet", "LaTeX font family")
-	trdGenerateCmd.Flags().BoolVar(&trdGenerateFlags.noFrontmatter, "no-frontmatter", false, "Disable YAML frontmatter generation")
+// ============================================================================
+// Utility Functions
+// ============================================================================
 
-	trdCmd.AddCommand(trdGenerateCmd)
-	trdCmd.AddCommand(trdValidateCmd)
+// readDocument reads a JSON or YAML document into v, leaving out the
+// sections and items whose metadata condition is false for the document's
+// profile and flags or those of --doc-profile and --flag, and fills the empty
+// metadata of a PRD, MRD, or TRD from the workspace defaults.
+func readDocument(path string, v any) error {
+	return readDocumentTo(path, v, os.Stderr)
 }
 
-func runTRDGenerate(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
+// readDocumentTo reads a document as readDocument does, writing the
+// --lenient recovery report to stderr, such as the output of a batch job.
+func readDocumentTo(path string, v any, stderr io.Writer) error {
+	return readProfiledDocument(path, v, nil, stderr)
+}
 
-	// Determine output file
-	output := trdGenerateFlags.output
-	if output == "" {
-		output = deriveOutputPath(inputFile)
+// readProfiledDocument reads a document as readDocumentTo does, keeping
+// only the sections and items shown by the render profile rp, if it is not
+// nil.
+func readProfiledDocument(path string, v any, rp *profile.Profile, stderr io.Writer) error {
+	data, err := readExpandedJSON(path)
+	if err != nil {
+		return err
 	}
-
-	// Read input file
-	data, err := os.ReadFile(inputFile)
+	flags, err := conditions.ParseFlags(rootFlags.flags)
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return fmt.Errorf("invalid --flag: %w", err)
 	}
-
-	var doc trd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+	if data, _, err = conditions.Apply(data, conditions.Context{Profile: rootFlags.docProfile, Flags: flags}); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
-
-	opts := trd.MarkdownOptions{
-		IncludeFrontmatter: !trdGenerateFlags.noFrontmatter,
-		Margin:             trdGenerateFlags.margin,
-		MainFont:           trdGenerateFlags.mainFont,
-		SansFont:           trdGenerateFlags.sansFont,
-		MonoFont:           trdGenerateFlags.monoFont,
-		FontFamily:         trdGenerateFlags.fontFamily,
+	if rp != nil {
+		if data, err = rp.Apply(data); err != nil {
+			return fmt.Errorf("%s: render profile %q: %w", path, rootFlags.renderProfile, err)
+		}
 	}
-	markdown := doc.ToMarkdown(opts)
-
-	if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
-		return fmt.Errorf("writing output file: %w", err)
+	if err := unmarshalDocument(path, data, v, stderr); err != nil {
+		return err
 	}
+	return workspace.ApplyDefaults(path, v)
+}
 
-	fmt.Printf("Generated: %s\n", output)
+// redactTRD masks the secret configuration of doc if redact is set or the
+// render profile named by --profile redacts.
+func redactTRD(doc *trd.Document, redact bool, inputFile string) error {
+	if !redact {
+		rp, err := renderProfile(inputFile)
+		if err != nil {
+			return err
+		}
+		redact = rp != nil && rp.Redact
+	}
+	if redact {
+		*doc = doc.Redacted()
+	}
 	return nil
 }
 
-func runTRDValidate(cmd *cobra.Command, args []string) error {
-	inputFile := args[0]
-
-	data, err := os.ReadFile(inputFile)
+// renderProfile returns the render profile named by --profile, from
+// the workspace manifest of inputFile or the built-in profiles, or nil if
+// none is given. Render profiles are separate from the document profile
+// of metadata.conditions, which --doc-profile sets.
+func renderProfile(inputFile string) (*profile.Profile, error) {
+	if rootFlags.renderProfile == "" {
+		return nil, nil
+	}
+	configured, err := workspace.RenderProfilesFor(inputFile)
 	if err != nil {
-		return fmt.Errorf("reading input file: %w", err)
+		return nil, err
 	}
-
-	var doc trd.Document
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing JSON: %w", err)
+	p, ok := profile.Lookup(rootFlags.renderProfile, configured)
+	if !ok {
+		return nil, fmt.Errorf("unknown render profile %q: expected one of %s",
+			rootFlags.renderProfile, strings.Join(profile.Names(configured), ", "))
 	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("render profile %q: %w", rootFlags.renderProfile, err)
+	}
+	return &p, nil
+}
 
-	var errors []string
-
-	if doc.Metadata.ID == "" {
-		errors = append(errors, "metadata.id is required")
+// applyRenderProfile sets the generate flags that the render profile rp
+// sets and that were not given on the command line.
+func applyRenderProfile(cmd *cobra.Command, rp *profile.Profile, flags *generateFlags) {
+	set := func(name string) bool { return !cmd.Flags().Changed(name) }
+	if rp.DescriptionMaxLen != nil && set("desc-len") {
+		flags.descLen = *rp.DescriptionMaxLen
 	}
-	if doc.Metadata.Title == "" {
-		errors = append(errors, "metadata.title is required")
+	if rp.Icons != "" && set("icons") {
+		flags.icons = rp.Icons
 	}
-	if doc.Metadata.Version == "" {
-		errors = append(errors, "metadata.version is required")
+	if rp.TOC != nil && set("no-toc") {
+		flags.noTOC = !*rp.TOC
 	}
-	if len(doc.Metadata.Authors) == 0 {
-		errors = append(errors, "metadata.authors is required (at least one author)")
+	if rp.Frontmatter != nil && set("no-frontmatter") {
+		flags.noFrontmatter = !*rp.Frontmatter
 	}
-	if doc.ExecutiveSummary.Purpose == "" {
-		errors = append(errors, "executive_summary.purpose is required")
+	if rp.Swimlane != nil && set("no-swimlane") {
+		flags.noSwimlane = !*rp.Swimlane
 	}
-	if doc.ExecutiveSummary.Scope == "" {
-		errors = append(errors, "executive_summary.scope is required")
+	if rp.Mermaid != nil && set("mermaid") {
+		flags.mermaid = *rp.Mermaid
 	}
-	if doc.Architecture.Overview == "" {
-		errors = append(errors, "architecture.overview is required")
+}
+
+// readSourceDocument reads a JSON or YAML document into v as written,
+// without workspace defaults or conditions, for commands that write the
+// document back, and returns its source for writeDocumentInPlace. The
+// format comes from --input-format, or the file extension when it is auto.
+// With --lenient, field-level type errors are recovered and a recovery
+// report is written to stderr.
+func readSourceDocument(path string, v any, stderr io.Writer) (*sourceDocument, error) {
+	data, format, err := readSource(path)
+	if err != nil {
+		return nil, err
 	}
-	if len(doc.Architecture.Components) == 0 {
-		errors = append(errors, "architecture.components is required (at least one component)")
+	src := &sourceDocument{data: data, format: format, json: data}
+	if format == yamlconv.FormatYAML {
+		if src.json, err = yamlconv.ToJSON(data); err != nil {
+			return nil, err
+		}
 	}
-	if doc.SecurityDesign.Overview == "" {
-		errors = append(errors, "security_design.overview is required")
+	if src.expanded, src.refs, err = workspace.ExpandSnippets(path, src.json); err != nil {
+		return nil, err
 	}
-	if len(doc.Performance.Requirements) == 0 {
-		errors = append(errors, "performance.requirements is required (at least one requirement)")
+	if err := unmarshalDocument(path, src.expanded, v, stderr); err != nil {
+		return nil, err
 	}
-	if len(doc.Deployment.Environments) == 0 {
-		errors = append(errors, "deployment.environments is required (at least one environment)")
+	if src.typed, err = marshalJSON(v); err != nil {
+		return nil, err
 	}
+	return src, nil
+}
 
-	if len(errors) > 0 {
-		fmt.Fprintf(os.Stderr, "Validation failed for %s:\n", inputFile)
-		for _, e := range errors {
-			fmt.Fprintf(os.Stderr, "  - %s\n", e)
-		}
-		return fmt.Errorf("validation failed with %d errors", len(errors))
+// sourceDocument is a document file as readSourceDocument read it, so
+// that writing the document back changes only what the command changed.
+type sourceDocument struct {
+	data     []byte // File contents
+	format   yamlconv.Format
+	json     []byte // data as JSON
+	expanded []byte // json with its snippet references expanded
+	refs     []common.SnippetRef
+	typed    []byte // JSON encoding of the document as decoded
+}
+
+// readExpandedJSON reads the document at path as JSON with its snippet
+// references expanded.
+func readExpandedJSON(path string) ([]byte, error) {
+	data, err := readSourceJSON(path)
+	if err != nil {
+		return nil, err
 	}
+	data, _, err = workspace.ExpandSnippets(path, data)
+	return data, err
+}
 
-	fmt.Printf("Valid TRD: %s\n", inputFile)
-	fmt.Printf("  Title: %s\n", doc.Metadata.Title)
-	fmt.Printf("  Version: %s\n", doc.Metadata.Version)
-	fmt.Printf("  Components: %d\n", len(doc.Architecture.Components))
-	fmt.Printf("  APIs: %d\n", len(doc.APISpecifications))
-	fmt.Printf("  Performance Requirements: %d\n", len(doc.Performance.Requirements))
-	fmt.Printf("  Environments: %d\n", len(doc.Deployment.Environments))
-	fmt.Printf("  Integrations: %d\n", len(doc.Integration))
+// unmarshalDocument decodes the JSON data of the document at path into v,
+// leniently with --lenient, writing the recovery report to stderr.
+func unmarshalDocument(path string, data []byte, v any, stderr io.Writer) error {
+	if !rootFlags.lenient {
+		if err := json.Unmarshal(data, v); err != nil {
+			format, _ := inputFormat(path)
+			return fmt.Errorf("parsing %s: %w", strings.ToUpper(string(format)), err)
+		}
+		return nil
+	}
 
+	report, err := lenient.Unmarshal(data, v)
+	if err != nil {
+		return err
+	}
+	if report.HasIssues() {
+		fmt.Fprintf(stderr, "Lenient parse of %s\n%s\n", path, report)
+	}
 	return nil
 }
 
-// ============================================================================
-// Schema Commands
-// ============================================================================
-
-var schemaCmd = &cobra.Command{
-	Use:   "schema",
-	Short: "Generate JSON Schema files from Go types",
-	Long: `Commands for generating JSON Schema files from Go type definitions.
-
-This implements the Go-first approach where Go structs are the source of truth
-and JSON Schema is generated from them.`,
+// readSourceJSON reads the document at path as JSON, converting YAML, with
+// its snippet references unexpanded.
+func readSourceJSON(path string) ([]byte, error) {
+	data, format, err := readSource(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == yamlconv.FormatYAML {
+		if data, err = yamlconv.ToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
 }
 
-var schemaGenerateFlags struct {
-	output  string
-	docType string
+// readSource reads the document at path and returns it with its format.
+func readSource(path string) ([]byte, yamlconv.Format, error) {
+	format, err := inputFormat(path)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading input file: %w", err)
+	}
+	return data, format, nil
 }
 
-var schemaGenerateCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "Generate JSON Schema from Go types",
-	Long: `Generate JSON Schema files from Go type definitions.
+// inputFormat returns the format of the input document at path.
+func inputFormat(path string) (yamlconv.Format, error) {
+	format, err := yamlconv.ParseFormat(rootFlags.inputFormat)
+	if err != nil {
+		return "", fmt.Errorf("invalid --input-format: %w", err)
+	}
+	if format == "" {
+		format = yamlconv.FormatFromPath(path)
+	}
+	return format, nil
+}
 
-By default, generates all schema files (PRD, OKR, V2MOM) to the schema/ directory.
-Use --type to generate a specific document type's schema.`,
-	Example: `  splan schema generate
-  splan schema generate -o ./schema/
-  splan schema generate --type prd -o prd.schema.json
-  splan schema generate --type okr -o okr.schema.json
-  splan schema generate --type v2mom -o v2mom.schema.json`,
-	RunE: runSchemaGenerate,
+// marshalDocument encodes v as indented JSON or as YAML.
+func marshalDocument(v any, format yamlconv.Format) ([]byte, error) {
+	if format == yamlconv.FormatYAML {
+		return yamlconv.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", "  ")
 }
 
-func init() {
-	schemaGenerateCmd.Flags().StringVarP(&schemaGenerateFlags.output, "output", "o", ".", "Output directory or file path")
-	schemaGenerateCmd.Flags().StringVarP(&schemaGenerateFlags.docType, "type", "t", "all", "Document type to generate (prd, okr, v2mom, mrd, trd, or all)")
+func readV2MOMFile(path string, stderr io.Writer) (*v2mom.V2MOM, error) {
+	var v v2mom.V2MOM
+	if err := readDocumentTo(path, &v, stderr); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
 
-	schemaCmd.AddCommand(schemaGenerateCmd)
+func readOKRFile(path string, stderr io.Writer) (*okr.OKRDocument, error) {
+	var doc okr.OKRDocument
+	if err := readDocumentTo(path, &doc, stderr); err != nil {
+		return nil, err
+	}
+	return &doc, nil
 }
 
-func runSchemaGenerate(cmd *cobra.Command, args []string) error {
-	gen := schema.NewGenerator()
-	output := schemaGenerateFlags.output
-	docType := strings.ToLower(schemaGenerateFlags.docType)
+func deriveOutputPath(inputFile string) string {
+	return deriveOutputPathExt(inputFile, ".md")
+}
 
-	switch docType {
-	case "prd":
-		// Single PRD schema
-		path := output
-		if isDir(output) {
-			path = filepath.Join(output, "prd.schema.json")
-		}
-		if err := gen.WritePRDSchema(path); err != nil {
-			return fmt.Errorf("generating PRD schema: %w", err)
-		}
-		fmt.Printf("Generated: %s\n", path)
+// deriveOutputPathExt replaces a .json, .yaml, or .yml extension on
+// inputFile with outExt, or appends outExt for other inputs.
+func deriveOutputPathExt(inputFile, outExt string) string {
+	ext := filepath.Ext(inputFile)
+	switch strings.ToLower(ext) {
+	case ".json", ".yaml", ".yml":
+		return strings.TrimSuffix(inputFile, ext) + outExt
+	}
+	return inputFile + outExt
+}
 
-	case "all":
-		// All schemas to directory
-		dir := output
-		if !isDir(dir) {
-			dir = filepath.Dir(output)
-		}
-		if err := gen.GenerateAll(dir); err != nil {
-			return fmt.Errorf("generating schemas: %w", err)
+// generateFonts returns the fonts for generated Markdown: those set with
+// flags, then the workspace default fonts, then the flag defaults.
+func generateFonts(cmd *cobra.Command, inputFile string, flags *generateFlags) (workspace.Fonts, error) {
+	fonts := workspace.Fonts{
+		MainFont:   flags.mainFont,
+		SansFont:   flags.sansFont,
+		MonoFont:   flags.monoFont,
+		FontFamily: flags.fontFamily,
+	}
+	defaults, err := workspace.DefaultsFor(inputFile)
+	if err != nil || defaults == nil {
+		return fonts, err
+	}
+	for _, f := range []struct {
+		flag       string
+		value, def *string
+	}{
+		{"mainfont", &fonts.MainFont, &defaults.Fonts.MainFont},
+		{"sansfont", &fonts.SansFont, &defaults.Fonts.SansFont},
+		{"monofont", &fonts.MonoFont, &defaults.Fonts.MonoFont},
+		{"fontfamily", &fonts.FontFamily, &defaults.Fonts.FontFamily},
+	} {
+		if *f.def != "" && !cmd.Flags().Changed(f.flag) {
+			*f.value = *f.def
 		}
-		fmt.Printf("Generated schemas in: %s\n", dir)
+	}
+	return fonts, nil
+}
 
-	case "okr":
-		// Single OKR schema
-		path := output
-		if isDir(output) {
-			path = filepath.Join(output, "okr.schema.json")
-		}
-		if err := gen.WriteOKRSchema(path); err != nil {
-			return fmt.Errorf("generating OKR schema: %w", err)
-		}
-		fmt.Printf("Generated: %s\n", path)
+// bundleAssets copies or inlines the local files referenced by a document
+// when --assets is set, rewriting the references before rendering. Missing
+// files are reported as warnings.
+func bundleAssets(refs []assets.Ref, inputFile, output string, flags *generateFlags) error {
+	mode := flags.assets
+	if mode == "" && flags.convertDiagrams != "" {
+		mode = string(assets.ModeCopy)
+	}
+	if mode == "" {
+		return nil
+	}
 
-	case "v2mom":
-		// Single V2MOM schema
-		path := output
-		if isDir(output) {
-			path = filepath.Join(output, "v2mom.schema.json")
-		}
-		if err := gen.WriteV2MOMSchema(path); err != nil {
-			return fmt.Errorf("generating V2MOM schema: %w", err)
+	opts := assets.Options{
+		Mode:      assets.Mode(mode),
+		SourceDir: filepath.Dir(inputFile),
+		OutputDir: filepath.Dir(output),
+		Dir:       flags.assetsDir,
+	}
+	if flags.convertDiagrams != "" {
+		converter, err := assets.NewConverter(assets.ConvertOptions{
+			Format:   flags.convertDiagrams,
+			Endpoint: flags.diagramEndpoint,
+		})
+		if err != nil {
+			return err
 		}
-		fmt.Printf("Generated: %s\n", path)
-
-	case "mrd", "trd":
-		return fmt.Errorf("schema generation for %s is not yet implemented", docType)
-
-	default:
-		return fmt.Errorf("unknown document type: %s (expected prd, okr, v2mom, mrd, trd, or all)", docType)
+		opts.Converter = converter
 	}
 
-	return nil
-}
-
-// ============================================================================
-// Utility Functions
-// ============================================================================
-
-func deriveOutputPath(inputFile string) string {
-	ext := filepath.Ext(inputFile)
-	if ext == ".json" {
-		base := strings.TrimSuffix(inputFile, ext)
-		return base + ".md"
+	result, err := assets.Bundle(refs, opts)
+	if err != nil {
+		return err
 	}
-	return inputFile + ".md"
+	for _, m := range result.Missing {
+		fmt.Fprintf(os.Stderr, "Warning: %s: file not found: %s\n", m.Field, m.Path)
+	}
+	for _, e := range result.ConversionErrors {
+		fmt.Fprintf(os.Stderr, "Warning: %s: diagram not converted, source bundled as is: %s\n", e.Field, e.Error)
+	}
+	if len(result.Bundled) > 0 {
+		fmt.Printf("Bundled %d asset references (%s)\n", len(result.Bundled), mode)
+	}
+	return nil
 }
 
 func isDir(path string) bool {
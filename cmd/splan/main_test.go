@@ -0,0 +1,331 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func TestCommentsAddKeepsSnippetReferences(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("splan.workspace.json", `{"name": "plans", "snippets": ["snippets/nfrs.yaml"]}`)
+	write("snippets/nfrs.yaml", `
+snippets:
+  - id: standard-nfrs
+    content:
+      - {id: NFR-SEC-1, category: security, title: Encryption, description: All traffic is encrypted, priority: must}
+`)
+	write("x.prd.json", `{
+  "metadata": {"id": "PRD-1", "title": "X", "version": "1.0", "status": "draft", "authors": [{"name": "a"}]},
+  "requirements": {
+    "functional": [{"id": "FR-1", "title": "t", "description": "d", "priority": "must"}],
+    "nonFunctional": [{"$snippet": "standard-nfrs"}]
+  }
+}`)
+
+	path := filepath.Join(dir, "x.prd.json")
+	rootCmd.SetArgs([]string{"comments", "add", path, "--path", "requirements", "--text", "Needs review", "--author", "pat"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if !strings.Contains(got, `"$snippet": "standard-nfrs"`) || strings.Contains(got, "NFR-SEC-1") {
+		t.Errorf("snippet reference not kept:\n%s", got)
+	}
+	if strings.Contains(got, `"snippets"`) {
+		t.Errorf("collapsed expansion still recorded in metadata.snippets:\n%s", got)
+	}
+	if !strings.Contains(got, "Needs review") {
+		t.Errorf("comment not added:\n%s", got)
+	}
+}
+
+func TestCommentsAddKeepsSource(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		name, doc string
+		want      []string
+	}{
+		{"x.prd.json", `{
+    "metadata": {"id": "PRD-1", "title": "R&D <beta>", "version": "1.0", "status": "draft", "authors": [{"name": "a"}]},
+    "objectives": {"businessObjectives": ["Grow & retain"], "productGoals": ["Ship <v2>"]},
+    "requirements": {"functional": [{"id": "FR-1", "title": "t", "description": "d", "priority": "must", "weight": 1.50}]}
+}
+`, []string{
+			`"title": "R&D <beta>"`,
+			`"businessObjectives": [`,
+			`"Ship <v2>"`,
+			`"weight": 1.50`,
+			"\n    \"comments\": [\n        {\n            \"id\": \"C-1\",",
+		}},
+		{"x.prd.yaml", `# Owned by payments.
+metadata:
+  id: PRD-1
+  title: R&D <beta>
+  version: "1.0"
+  status: draft # bump after review
+  authors:
+    - name: a
+objectives:
+  productGoals: [Ship <v2>]
+requirements:
+  functional:
+    - {id: FR-1, title: t, description: d, priority: must}
+`, []string{
+			"# Owned by payments.",
+			"status: draft # bump after review",
+			"productGoals: [Ship <v2>]",
+			"comments:\n  - id: C-1",
+		}},
+	} {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+		rootCmd.SetArgs([]string{"comments", "add", path, "--path", "requirements", "--text", "Q&A <soon>", "--author", "pat"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(data)
+		for _, want := range append(tt.want, "Q&A <soon>") {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: missing %q:\n%s", tt.name, want, got)
+			}
+		}
+		if strings.Contains(got, `\u0026`) || strings.Contains(got, "okrs") {
+			t.Errorf("%s: escaped or added members:\n%s", tt.name, got)
+		}
+	}
+}
+
+func TestFixApplyKeepsSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.prd.json")
+	doc := `{
+  "metadata": {"id": "PRD-1", "title": "R&D", "version": "1.0", "authors": [{"name": "a"}]},
+  "objectives": {"businessObjectives": ["Grow & retain"], "productGoals": ["Ship <v2>"]},
+  "requirements": {"functional": [{"title": "t", "description": "d", "priority": "must"}]}
+}
+`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd.SetArgs([]string{"fix", path, "--apply"})
+	t.Cleanup(func() { fixFlags.apply = false })
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		`"status": "draft"`,
+		"{\n        \"id\": \"FR-001\",\n        \"title\": \"t\",",
+		`"businessObjectives": [`,
+		`"Ship <v2>"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestIDsAssignKeepsSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.prd.json")
+	doc := `{
+  "metadata": {"id": "PRD-1", "title": "X", "version": "1.0", "status": "draft", "authors": [{"name": "a"}]},
+  "objectives": {"businessObjectives": ["Grow & retain"]},
+  "requirements": {"functional": [{"title": "t", "description": "d", "priority": "must"}]}
+}
+`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd.SetArgs([]string{"requirements", "prd", "ids", "assign", path})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if !strings.Contains(got, `"id": "FR-001"`) || !strings.Contains(got, `"Grow & retain"`) {
+		t.Errorf("ID not assigned or source not kept:\n%s", got)
+	}
+}
+
+func TestValidateFixKeepsSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "x.prd.yaml")
+	doc := `# Checkout
+metadata: {id: PRD-1, title: X, version: "1.0", status: draft, authors: [{name: a}]}
+objectives:
+  businessObjectives: [Grow & retain] # legacy
+requirements:
+  functional:
+    - {id: FR-001, title: t, description: d, priority: must}
+    - {id: FR-001, title: u, description: d, priority: must}
+`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := fixDuplicateIDs(path, &prd.Document{}, io.Discard, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# Checkout", "businessObjectives: [Grow & retain] # legacy", "id: FR-002"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderProfileKeepsDocumentProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "x.prd.json")
+	doc := `{
+  "metadata": {"id": "PRD-1", "title": "X", "version": "1.0", "status": "draft", "authors": [{"name": "a"}],
+    "profile": "enterprise", "conditions": {"risks": "profile == enterprise"}},
+  "requirements": {"functional": [{"id": "FR-1", "title": "t", "description": "d", "priority": "must"}]},
+  "risks": [{"id": "R-1", "description": "Vendor lock-in", "probability": "low", "impact": "high", "mitigation": "m"}]
+}`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rootFlags.docProfile, rootFlags.renderProfile = "", "" })
+
+	for _, tt := range []struct {
+		docProfile, renderProfile string
+		wantRisks                 bool
+	}{
+		{"", "", true},
+		{"", "exec", true},
+		{"", "engineering", true},
+		{"smb", "engineering", false},
+	} {
+		rootFlags.docProfile, rootFlags.renderProfile = tt.docProfile, tt.renderProfile
+		rp, err := renderProfile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got prd.Document
+		if err := readProfiledDocument(path, &got, rp, io.Discard); err != nil {
+			t.Fatal(err)
+		}
+		if hasRisks := len(got.Risks) > 0; hasRisks != tt.wantRisks {
+			t.Errorf("--doc-profile %q --profile %q: risks kept = %v, want %v", tt.docProfile, tt.renderProfile, hasRisks, tt.wantRisks)
+		}
+	}
+
+	rootFlags.renderProfile = "unknown"
+	if _, err := renderProfile(path); err == nil {
+		t.Error("expected an error for an unknown render profile")
+	}
+
+	if err := rootCmd.PersistentFlags().Parse([]string{"--profile", "exec", "--doc-profile", "smb"}); err != nil {
+		t.Fatal(err)
+	}
+	if rootFlags.renderProfile != "exec" || rootFlags.docProfile != "smb" {
+		t.Errorf("--profile set %q and --doc-profile %q, want exec and smb", rootFlags.renderProfile, rootFlags.docProfile)
+	}
+}
+
+func TestMergeStreamIntoInput(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	if err := os.WriteFile(a, []byte(`{"items": [1], "name": "a"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`{"items": [2]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mergeFlags.output, mergeFlags.stream = "merged.json", false })
+
+	rootCmd.SetArgs([]string{"merge", a, b, "--stream", "-o", a})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(strings.Fields(string(data)), ""); !strings.Contains(got, `"items":[1,2]`) || !strings.Contains(got, `"name":"a"`) {
+		t.Errorf("merged output = %s", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("temporary file left in %s: %v", dir, entries)
+	}
+}
+
+func TestMergeYAML(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.prd.yaml"), filepath.Join(dir, "b.prd.json")
+	if err := os.WriteFile(a, []byte("metadata:\n  id: PRD-1\n  title: Checkout\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`{"metadata": {"version": "2.0.0"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "merged.json")
+	t.Cleanup(func() { mergeFlags.output, mergeFlags.stream, mergeFlags.typed = "merged.json", false, "" })
+
+	for _, flags := range [][]string{nil, {"--stream"}, {"--typed", "prd"}} {
+		mergeFlags.stream, mergeFlags.typed = false, ""
+		rootCmd.SetArgs(append([]string{"merge", a, b, "-o", out}, flags...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("merge %v: %v", flags, err)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := strings.Join(strings.Fields(string(data)), "")
+		for _, want := range []string{`"id":"PRD-1"`, `"title":"Checkout"`, `"version":"2.0.0"`} {
+			if !strings.Contains(got, want) {
+				t.Errorf("merge %v output missing %s: %s", flags, want, data)
+			}
+		}
+	}
+}
+
+func TestLenientReportGoesToJobOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.prd.json")
+	if err := os.WriteFile(file, []byte(`{"metadata": {"id": "PRD-1", "title": "A", "updatedAt": "2025-06-01"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rootFlags.lenient = true
+	t.Cleanup(func() { rootFlags.lenient = false })
+
+	var stdout, stderr strings.Builder
+	_ = validatePRDFile(file, &stdout, &stderr)
+	if !strings.Contains(stderr.String(), "Lenient parse of "+file) {
+		t.Errorf("job stderr = %q, want the lenient recovery report", stderr.String())
+	}
+}
@@ -0,0 +1,159 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comment is a review comment attached to part of a document.
+// Used across PRD, MRD, and TRD documents.
+//
+// Path identifies the commented entity with the document's JSON field names,
+// for example "executiveSummary.problemStatement", "risks[2]", or
+// "requirements.functional.FR-3". A reply sets InReplyTo to the ID of the
+// comment that starts its thread; resolving that comment resolves the thread.
+type Comment struct {
+	ID         string     `json:"id"`
+	Author     string     `json:"author"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	Path       string     `json:"path"`
+	Text       string     `json:"text"`
+	InReplyTo  string     `json:"inReplyTo,omitempty"`
+	Resolved   bool       `json:"resolved,omitempty"`
+	ResolvedBy string     `json:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// CommentThread is a top-level comment with its replies in order.
+type CommentThread struct {
+	Comment
+	Replies []Comment `json:"replies,omitempty"`
+}
+
+// AddComment appends c to comments, assigning the next "C-n" ID. A reply
+// takes its path from the thread it replies to.
+func AddComment(comments []Comment, c Comment) ([]Comment, Comment, error) {
+	if strings.TrimSpace(c.Text) == "" {
+		return comments, c, fmt.Errorf("comment text is required")
+	}
+	if c.InReplyTo != "" {
+		parent := findComment(comments, c.InReplyTo)
+		if parent == nil {
+			return comments, c, fmt.Errorf("comment not found: %s", c.InReplyTo)
+		}
+		if parent.InReplyTo != "" {
+			c.InReplyTo = parent.InReplyTo
+			if root := findComment(comments, parent.InReplyTo); root != nil {
+				parent = root
+			}
+		}
+		if parent.Resolved {
+			return comments, c, fmt.Errorf("comment %s is resolved", parent.ID)
+		}
+		c.Path = parent.Path
+	}
+	if c.Path == "" {
+		return comments, c, fmt.Errorf("comment path is required")
+	}
+	c.ID = nextCommentID(comments)
+	return append(comments, c), c, nil
+}
+
+// ResolveComment marks the thread containing id as resolved and returns the
+// ID of the comment that starts the thread.
+func ResolveComment(comments []Comment, id, by string, at time.Time) (string, error) {
+	c := findComment(comments, id)
+	if c == nil {
+		return "", fmt.Errorf("comment not found: %s", id)
+	}
+	if c.InReplyTo != "" {
+		if root := findComment(comments, c.InReplyTo); root != nil {
+			c = root
+		}
+	}
+	if c.Resolved {
+		return "", fmt.Errorf("comment %s is already resolved", c.ID)
+	}
+	c.Resolved = true
+	c.ResolvedBy = by
+	c.ResolvedAt = &at
+	return c.ID, nil
+}
+
+// CommentThreads groups comments into threads in the order they were
+// started. Replies to unknown comments start their own thread.
+func CommentThreads(comments []Comment) []CommentThread {
+	var threads []CommentThread
+	index := map[string]int{}
+	for _, c := range comments {
+		if i, ok := index[c.InReplyTo]; ok && c.InReplyTo != "" {
+			threads[i].Replies = append(threads[i].Replies, c)
+			continue
+		}
+		index[c.ID] = len(threads)
+		threads = append(threads, CommentThread{Comment: c})
+	}
+	return threads
+}
+
+// UnresolvedThreads returns the threads that are still open.
+func UnresolvedThreads(comments []Comment) []CommentThread {
+	var open []CommentThread
+	for _, t := range CommentThreads(comments) {
+		if !t.Resolved {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// CommentPathRoot returns the top-level field a comment path starts with,
+// e.g. "requirements" for "requirements.functional.FR-3".
+func CommentPathRoot(path string) string {
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// ValidateCommentPath checks that path starts with a JSON field of doc, which
+// must be a struct or a pointer to one.
+func ValidateCommentPath(doc any, path string) error {
+	root := CommentPathRoot(path)
+	if root == "" {
+		return fmt.Errorf("invalid comment path %q", path)
+	}
+	t := reflect.TypeOf(doc)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == root {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid comment path %q: the document has no %q field", path, root)
+}
+
+func findComment(comments []Comment, id string) *Comment {
+	for i := range comments {
+		if comments[i].ID == id {
+			return &comments[i]
+		}
+	}
+	return nil
+}
+
+func nextCommentID(comments []Comment) string {
+	highest := 0
+	for _, c := range comments {
+		if n, err := strconv.Atoi(strings.TrimPrefix(c.ID, "C-")); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return "C-" + strconv.Itoa(highest+1)
+}
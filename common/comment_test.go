@@ -0,0 +1,71 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommentThreads(t *testing.T) {
+	var comments []Comment
+	add := func(c Comment) Comment {
+		t.Helper()
+		var err error
+		comments, c, err = AddComment(comments, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	first := add(Comment{Author: "alice", Path: "requirements.functional.FR-3", Text: "Must for MVP?"})
+	add(Comment{Author: "bob", Path: "risks[0]", Text: "Who owns this?"})
+	reply := add(Comment{Author: "carol", InReplyTo: first.ID, Text: "Yes"})
+	nested := add(Comment{Author: "dana", InReplyTo: reply.ID, Text: "Agreed"})
+
+	if first.ID != "C-1" || reply.ID != "C-3" || reply.Path != first.Path {
+		t.Errorf("reply = %+v", reply)
+	}
+	if nested.InReplyTo != first.ID {
+		t.Errorf("a reply to a reply should join the thread, got InReplyTo %q", nested.InReplyTo)
+	}
+
+	threads := CommentThreads(comments)
+	if len(threads) != 2 || len(threads[0].Replies) != 2 {
+		t.Fatalf("threads = %+v", threads)
+	}
+
+	id, err := ResolveComment(comments, reply.ID, "erin", time.Now())
+	if err != nil || id != first.ID {
+		t.Fatalf("ResolveComment = %q, %v", id, err)
+	}
+	if open := UnresolvedThreads(comments); len(open) != 1 || open[0].ID != "C-2" {
+		t.Errorf("unresolved = %+v", open)
+	}
+	if _, err := ResolveComment(comments, first.ID, "erin", time.Now()); err == nil {
+		t.Error("resolving twice should fail")
+	}
+	if _, _, err := AddComment(comments, Comment{InReplyTo: first.ID, Text: "late"}); err == nil {
+		t.Error("replying to a resolved thread should fail")
+	}
+	if _, _, err := AddComment(comments, Comment{Path: "risks", Text: " "}); err == nil {
+		t.Error("empty text should fail")
+	}
+}
+
+func TestValidateCommentPath(t *testing.T) {
+	type doc struct {
+		Risks []Risk `json:"risks,omitempty"`
+	}
+	for path, ok := range map[string]bool{
+		"risks":         true,
+		"risks[0]":      true,
+		"risks.R-1":     true,
+		"requirements":  false,
+		"":              false,
+		".risks":        false,
+		"Risks[0].text": false,
+	} {
+		if err := ValidateCommentPath(&doc{}, path); (err == nil) != ok {
+			t.Errorf("ValidateCommentPath(%q) = %v", path, err)
+		}
+	}
+}
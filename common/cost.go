@@ -0,0 +1,41 @@
+package common
+
+// DefaultCurrency is the currency assumed when a cost does not specify one.
+const DefaultCurrency = "USD"
+
+// Cost is an estimate attached to a roadmap phase, component, or technology
+// choice. Recurring costs start in the phase identified by PhaseID and
+// continue to the end of the roadmap.
+// Used across PRD and TRD documents.
+type Cost struct {
+	// Build is the one-time cost to build, implement, or migrate.
+	Build float64 `json:"build,omitempty"`
+
+	// Run is the recurring monthly operating cost (hosting, support).
+	Run float64 `json:"run,omitempty"`
+
+	// Licensing is the recurring annual license or subscription cost.
+	Licensing float64 `json:"licensing,omitempty"`
+
+	// Currency is an ISO 4217 code. Defaults to DefaultCurrency.
+	Currency string `json:"currency,omitempty"`
+
+	// PhaseID is the roadmap phase in which the cost is incurred. Costs on
+	// a phase default to that phase.
+	PhaseID string `json:"phaseId,omitempty"`
+
+	Notes string `json:"notes,omitempty"`
+}
+
+// IsZero reports whether the cost has no amounts.
+func (c *Cost) IsZero() bool {
+	return c == nil || (c.Build == 0 && c.Run == 0 && c.Licensing == 0)
+}
+
+// CurrencyCode returns the currency, or DefaultCurrency if unset.
+func (c *Cost) CurrencyCode() string {
+	if c == nil || c.Currency == "" {
+		return DefaultCurrency
+	}
+	return c.Currency
+}
@@ -0,0 +1,91 @@
+package common
+
+import "strings"
+
+// DependencyStatus represents the delivery status of an external dependency.
+type DependencyStatus string
+
+const (
+	DependencyStatusPending   DependencyStatus = "pending"   // Requested, not yet committed
+	DependencyStatusCommitted DependencyStatus = "committed" // Owning team has committed to a date
+	DependencyStatusAtRisk    DependencyStatus = "at_risk"   // Committed but likely to slip
+	DependencyStatusBlocked   DependencyStatus = "blocked"   // Cannot proceed
+	DependencyStatusAvailable DependencyStatus = "available" // Delivered and usable
+)
+
+// Normalize returns the status in canonical form, so that "At Risk",
+// "at-risk", and "at_risk" compare equal. "delivered" and "done" map to
+// DependencyStatusAvailable.
+func (s DependencyStatus) Normalize() DependencyStatus {
+	n := DependencyStatus(strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(string(s)))))
+	switch n {
+	case "delivered", "done", "complete", "completed":
+		return DependencyStatusAvailable
+	}
+	return n
+}
+
+// IsResolved reports whether the dependency has been delivered.
+func (s DependencyStatus) IsResolved() bool {
+	return s.Normalize() == DependencyStatusAvailable
+}
+
+// IsAtRisk reports whether the dependency is blocked or likely to slip.
+func (s DependencyStatus) IsAtRisk() bool {
+	n := s.Normalize()
+	return n == DependencyStatusAtRisk || n == DependencyStatusBlocked
+}
+
+// Dependency represents work owned by another team, vendor, or service that
+// this plan relies on.
+// Used across PRD and TRD documents.
+type Dependency struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type,omitempty"` // API, Service, Team, Vendor
+
+	// Team is the team or organization that owns the deliverable.
+	Team string `json:"team,omitempty"`
+
+	// Deliverable is what the owning team must provide.
+	Deliverable string `json:"deliverable,omitempty"`
+
+	// NeededBy is the ID of the roadmap phase that requires the deliverable.
+	NeededBy string `json:"neededBy,omitempty"`
+
+	Owner   string           `json:"owner,omitempty"`   // Accountable person on this side
+	Contact string           `json:"contact,omitempty"` // Point of contact on the owning team
+	Status  DependencyStatus `json:"status,omitempty"`
+	DueDate string           `json:"dueDate,omitempty"` // Committed delivery date (YYYY-MM-DD)
+	Notes   string           `json:"notes,omitempty"`
+}
+
+// FormatDependencyTable renders dependencies as a markdown table. phaseNames
+// maps phase IDs to display names for the Needed By column; it may be nil.
+// Statuses are shown as written, in bold when the dependency is at risk.
+func FormatDependencyTable(deps []Dependency, phaseNames map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("| ID | Name | Type | Team | Deliverable | Needed By | Due | Status | Contact |\n")
+	sb.WriteString("|----|------|------|------|-------------|-----------|-----|--------|---------|\n")
+	for _, dep := range deps {
+		name := dep.Name
+		if name == "" {
+			name = dep.Description
+		}
+		neededBy := dep.NeededBy
+		if n := phaseNames[neededBy]; n != "" {
+			neededBy = n
+		}
+		status := string(dep.Status)
+		if dep.Status.IsAtRisk() {
+			status = "**" + status + "**"
+		}
+		cells := []string{dep.ID, name, dep.Type, dep.Team, dep.Deliverable, neededBy, dep.DueDate, status, dep.Contact}
+		for i, c := range cells {
+			cells[i] = strings.ReplaceAll(c, "|", "\\|")
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return sb.String()
+}
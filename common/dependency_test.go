@@ -0,0 +1,23 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDependencyTable(t *testing.T) {
+	deps := []Dependency{
+		{ID: "DEP-1", Name: "Payments API", Type: "Vendor", Owner: "Jane Doe", Status: "Available"},
+		{ID: "DEP-2", Name: "SSO", Type: "Team", Team: "Identity", NeededBy: "p1", Status: "At Risk"},
+	}
+	got := FormatDependencyTable(deps, map[string]string{"p1": "MVP"})
+	for _, want := range []string{
+		"| ID | Name | Type | Team |",
+		"| DEP-1 | Payments API | Vendor |  |  |  |  | Available |  |",
+		"| DEP-2 | SSO | Team | Identity |  | MVP |  | **At Risk** |  |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatDependencyTable missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"sort"
+	"strings"
+)
+
+// ExternalRefs maps an external system to the identifier of the same entity
+// in that system, e.g. {"jira": "PROJ-123", "figma": "https://figma.com/file/abc"}.
+// Exporters and importers record and look up refs here, so re-running a sync
+// updates the linked item rather than creating a duplicate.
+type ExternalRefs map[string]string
+
+// Well-known external systems. Any other key is allowed.
+const (
+	ExternalSystemJira       = "jira"
+	ExternalSystemGitHub     = "github"
+	ExternalSystemFigma      = "figma"
+	ExternalSystemConfluence = "confluence"
+)
+
+// Set records id as the entity's identifier in system, allocating the map if
+// needed. An empty id removes the ref.
+func (r *ExternalRefs) Set(system, id string) {
+	if id == "" {
+		delete(*r, system)
+		return
+	}
+	if *r == nil {
+		*r = ExternalRefs{}
+	}
+	(*r)[system] = id
+}
+
+// Systems returns the systems with a ref, sorted.
+func (r ExternalRefs) Systems() []string {
+	systems := make([]string, 0, len(r))
+	for s := range r {
+		systems = append(systems, s)
+	}
+	sort.Strings(systems)
+	return systems
+}
+
+// ExternalLink is an external ref prepared for display.
+type ExternalLink struct {
+	System string
+	Text   string
+	URL    string // empty when the ref cannot be linked
+}
+
+// Links returns r's refs sorted by system. A ref that is already an http(s)
+// URL links to itself and is labelled with its system name. Otherwise the
+// ref is an ID, labelled with itself and linked through urlTemplates[system],
+// where "{id}" is replaced by the ID, e.g.
+// "https://acme.atlassian.net/browse/{id}". An ID without a template is not
+// linked and is labelled "system ID".
+func (r ExternalRefs) Links(urlTemplates map[string]string) []ExternalLink {
+	links := make([]ExternalLink, 0, len(r))
+	for _, system := range r.Systems() {
+		id := r[system]
+		switch tmpl := urlTemplates[system]; {
+		case strings.HasPrefix(id, "https://"), strings.HasPrefix(id, "http://"):
+			links = append(links, ExternalLink{System: system, Text: system, URL: id})
+		case tmpl != "":
+			links = append(links, ExternalLink{System: system, Text: id, URL: strings.ReplaceAll(tmpl, "{id}", id)})
+		default:
+			links = append(links, ExternalLink{System: system, Text: system + " " + id})
+		}
+	}
+	return links
+}
+
+// FormatExternalRefsMarkdown formats r as comma-separated markdown links,
+// e.g. "[PROJ-123](https://acme.atlassian.net/browse/PROJ-123), [figma](https://...)".
+func FormatExternalRefsMarkdown(r ExternalRefs, urlTemplates map[string]string) string {
+	links := r.Links(urlTemplates)
+	parts := make([]string, len(links))
+	for i, l := range links {
+		if l.URL == "" {
+			parts[i] = l.Text
+		} else {
+			parts[i] = "[" + l.Text + "](" + l.URL + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FindByExternalRef returns the index of the first item whose refs map
+// system to id, or -1. refs returns an item's external refs.
+func FindByExternalRef[T any](items []T, refs func(*T) ExternalRefs, system, id string) int {
+	for i := range items {
+		if r := refs(&items[i]); r[system] == id && id != "" {
+			return i
+		}
+	}
+	return -1
+}
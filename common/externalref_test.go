@@ -0,0 +1,30 @@
+package common
+
+import "testing"
+
+func TestExternalRefs(t *testing.T) {
+	var refs ExternalRefs
+	refs.Set(ExternalSystemJira, "PROJ-123")
+	refs.Set(ExternalSystemFigma, "https://figma.com/file/abc")
+	refs.Set(ExternalSystemConfluence, "98765")
+
+	templates := map[string]string{"jira": "https://acme.atlassian.net/browse/{id}"}
+	want := "confluence 98765, [figma](https://figma.com/file/abc), [PROJ-123](https://acme.atlassian.net/browse/PROJ-123)"
+	if got := FormatExternalRefsMarkdown(refs, templates); got != want {
+		t.Errorf("FormatExternalRefsMarkdown = %q, want %q", got, want)
+	}
+
+	refs.Set(ExternalSystemConfluence, "")
+	if got := refs.Systems(); len(got) != 2 {
+		t.Errorf("Systems after removal = %v", got)
+	}
+
+	items := []struct{ Refs ExternalRefs }{{}, {Refs: refs}}
+	get := func(it *struct{ Refs ExternalRefs }) ExternalRefs { return it.Refs }
+	if got := FindByExternalRef(items, get, "jira", "PROJ-123"); got != 1 {
+		t.Errorf("FindByExternalRef = %d, want 1", got)
+	}
+	if got := FindByExternalRef(items, get, "jira", ""); got != -1 {
+		t.Errorf("FindByExternalRef with empty id = %d, want -1", got)
+	}
+}
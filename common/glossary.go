@@ -1,5 +1,13 @@
 package common
 
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
 // GlossaryTerm defines a glossary entry.
 // Used across PRD, MRD, and TRD documents.
 type GlossaryTerm struct {
@@ -9,3 +17,284 @@ type GlossaryTerm struct {
 	Context    string   `json:"context,omitempty"`
 	Related    []string `json:"related,omitempty"` // Related terms
 }
+
+// GlossaryAnchors returns the anchor of each term's glossary entry, such as
+// "term-service-level-objective". Duplicate terms get numbered anchors, so
+// each entry can still be linked.
+func GlossaryAnchors(terms []GlossaryTerm) []string {
+	anchors := make([]string, len(terms))
+	used := make(map[string]bool, len(terms))
+	for i, g := range terms {
+		slug := termSlug(g.Term)
+		if slug == "" {
+			slug = termSlug(g.Acronym)
+		}
+		base := "term-" + slug
+		if slug == "" {
+			base = "term"
+		}
+		anchor := base
+		for n := 2; used[anchor]; n++ {
+			anchor = base + "-" + strconv.Itoa(n)
+		}
+		used[anchor] = true
+		anchors[i] = anchor
+	}
+	return anchors
+}
+
+// termSlug lowercases s and joins its runs of letters and digits with
+// hyphens.
+func termSlug(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+			hyphen = false
+		case sb.Len() > 0 && !hyphen:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// GlossaryLinker finds the first use of each glossary term in the text it
+// is given, piece by piece in document order. Terms match whole words
+// regardless of case and acronyms match exactly, either with a plural "s".
+// When two entries share a term or acronym, the first one is linked.
+type GlossaryLinker struct {
+	anchors   []string
+	byTerm    map[string]int // lowercased term to entry
+	byAcronym map[string]int
+	pattern   *regexp.Regexp
+	linked    []bool
+	remaining int
+}
+
+// NewGlossaryLinker returns a linker for terms, or nil if there are none.
+func NewGlossaryLinker(terms []GlossaryTerm) *GlossaryLinker {
+	l := &GlossaryLinker{
+		anchors:   GlossaryAnchors(terms),
+		byTerm:    make(map[string]int),
+		byAcronym: make(map[string]int),
+		linked:    make([]bool, len(terms)),
+	}
+	type alt struct{ text, expr string }
+	var alts []alt
+	for i, g := range terms {
+		if t := strings.TrimSpace(g.Term); t != "" {
+			if _, ok := l.byTerm[strings.ToLower(t)]; !ok {
+				l.byTerm[strings.ToLower(t)] = i
+				alts = append(alts, alt{t, "(?i:" + wordPattern(t) + ")"})
+			}
+		}
+		if a := strings.TrimSpace(g.Acronym); len(a) > 1 {
+			if _, ok := l.byAcronym[a]; !ok {
+				l.byAcronym[a] = i
+				alts = append(alts, alt{a, wordPattern(a)})
+			}
+		}
+	}
+	if len(alts) == 0 {
+		return nil
+	}
+	// Longer terms first, so "Service Level Objective" wins over "Service".
+	sort.SliceStable(alts, func(i, j int) bool { return len(alts[i].text) > len(alts[j].text) })
+	exprs := make([]string, len(alts))
+	for i, a := range alts {
+		exprs[i] = a.expr
+	}
+	l.pattern = regexp.MustCompile(strings.Join(exprs, "|"))
+	entries := make(map[int]bool)
+	for _, i := range l.byTerm {
+		entries[i] = true
+	}
+	for _, i := range l.byAcronym {
+		entries[i] = true
+	}
+	l.remaining = len(entries)
+	return l
+}
+
+// wordPattern matches s as a whole word, with an optional plural "s".
+func wordPattern(s string) string {
+	expr := regexp.QuoteMeta(s)
+	if isWordByte(s[0]) {
+		expr = `\b` + expr
+	}
+	if isWordByte(s[len(s)-1]) {
+		expr += `s?\b`
+	}
+	return expr
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// lookup returns the entry a match refers to, or -1.
+func (l *GlossaryLinker) lookup(m string) int {
+	for _, s := range []string{m, strings.TrimSuffix(m, "s")} {
+		if i, ok := l.byAcronym[s]; ok {
+			return i
+		}
+		if i, ok := l.byTerm[strings.ToLower(s)]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// Done reports whether every entry has been linked.
+func (l *GlossaryLinker) Done() bool {
+	return l == nil || l.remaining == 0
+}
+
+// Link returns text with the first use of each entry not yet linked
+// replaced by link(match, anchor), where anchor is the entry's anchor.
+func (l *GlossaryLinker) Link(text string, link func(match, anchor string) string) string {
+	if l.Done() {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range l.pattern.FindAllStringIndex(text, -1) {
+		i := l.lookup(text[m[0]:m[1]])
+		if i < 0 || l.linked[i] {
+			continue
+		}
+		l.linked[i] = true
+		l.remaining--
+		sb.WriteString(text[last:m[0]])
+		sb.WriteString(link(text[m[0]:m[1]], l.anchors[i]))
+		last = m[1]
+	}
+	if last == 0 {
+		return text
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// markdownProtected matches the parts of a Markdown line that must not
+// get links: links and images, inline code, HTML tags, URLs, and math.
+var markdownProtected = regexp.MustCompile("!?\\[[^\\]]*\\]\\([^)]*\\)|`[^`]*`|<[^>]*>|https?://\\S+|\\$[^$\\n]+\\$|\\\\\\(.*?\\\\\\)|\\{#[^}]*\\}")
+
+// tagLine matches the tag lines and table rows of rendered documents, such
+// as "**Tags:** a, b" and "| **Tags** | a, b |".
+var tagLine = regexp.MustCompile(`^\|?\s*\*\*Tags:?\*\*`)
+
+// LinkGlossaryMarkdown links the first use of each glossary term in the
+// prose and tables of md to the term's entry, such as "[SLO](#term-slo)".
+// Frontmatter, the metadata table under the document title, tag lines,
+// headings, code blocks, display math, existing links, and the glossary
+// section itself are left alone, so terms are linked where they are first
+// used in the text. The glossary must give its entries the anchors of
+// GlossaryAnchors.
+func LinkGlossaryMarkdown(md string, terms []GlossaryTerm) string {
+	l := NewGlossaryLinker(terms)
+	if l.Done() {
+		return md
+	}
+	link := func(m, anchor string) string { return "[" + m + "](#" + anchor + ")" }
+
+	lines := strings.SplitAfter(md, "\n")
+	frontmatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	fence := ""
+	glossaryLevel := 0 // level of the glossary heading while in its section
+	headings := 0
+	metadataTable := false // below the document title, before its text
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case frontmatter:
+			if i > 0 && trimmed == "---" {
+				frontmatter = false
+			}
+			continue
+		case fence != "":
+			if strings.Contains(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			continue
+		case strings.HasPrefix(trimmed, "$$"):
+			if strings.Count(trimmed, "$$") == 1 {
+				fence = "$$"
+			}
+			continue
+		}
+		if level := headingLevel(line); level > 0 {
+			headings++
+			metadataTable = headings == 1 && level == 1
+			if glossaryLevel > 0 && level <= glossaryLevel {
+				glossaryLevel = 0
+			}
+			if level > 1 && isGlossaryHeading(line[level:]) {
+				glossaryLevel = level
+			}
+			continue
+		}
+		if glossaryLevel > 0 || trimmed == "" || tagLine.MatchString(trimmed) {
+			continue
+		}
+		if metadataTable {
+			if strings.HasPrefix(trimmed, "|") {
+				continue
+			}
+			metadataTable = false
+		}
+
+		var sb strings.Builder
+		last := 0
+		for _, m := range markdownProtected.FindAllStringIndex(line, -1) {
+			sb.WriteString(l.Link(line[last:m[0]], link))
+			sb.WriteString(line[m[0]:m[1]])
+			last = m[1]
+		}
+		sb.WriteString(l.Link(line[last:], link))
+		lines[i] = sb.String()
+		if l.Done() {
+			break
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// glossaryMarkup matches the anchors of glossary entries and the links of
+// LinkGlossaryMarkdown.
+var glossaryMarkup = regexp.MustCompile(`<a id="term-[^"]*"></a>|\[([^\]]*)\]\(#term-[^)]*\)`)
+
+// UnlinkGlossaryMarkdown removes the glossary anchors and term links from
+// md, leaving the linked text.
+func UnlinkGlossaryMarkdown(md string) string {
+	return glossaryMarkup.ReplaceAllString(md, "$1")
+}
+
+// headingLevel returns the level of an ATX heading line, or 0.
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && level < 6 && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level >= len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// isGlossaryHeading reports whether a heading's text, without any section
+// number or explicit ID, is "Glossary".
+func isGlossaryHeading(text string) bool {
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, "{#"); i >= 0 {
+		text = strings.TrimSpace(text[:i])
+	}
+	text = strings.TrimLeft(text, "0123456789. ")
+	return strings.EqualFold(text, "Glossary")
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGlossaryAnchors(t *testing.T) {
+	terms := []GlossaryTerm{{Term: "Service Level Objective", Acronym: "SLO"}, {Term: "API"}, {Term: "api"}, {Acronym: "TTL"}}
+	want := []string{"term-service-level-objective", "term-api", "term-api-2", "term-ttl"}
+	if got := GlossaryAnchors(terms); !slices.Equal(got, want) {
+		t.Errorf("GlossaryAnchors = %v, want %v", got, want)
+	}
+}
+
+func TestLinkGlossaryMarkdown(t *testing.T) {
+	terms := []GlossaryTerm{
+		{Term: "Service Level Objective", Acronym: "SLO"},
+		{Term: "Tenant"},
+		{Term: "Region", Acronym: "RG"},
+	}
+	md := "---\ntitle: SLO\n---\n# SLO Plan\n\n| Field | Value |\n|---|---|\n| **Tags** | slo, tenant |\n\n" +
+		"**Tags:** region\n\n" +
+		"See [SLO docs](https://x/slo) and `SLO`. Each SLO covers tenants; the service level objective is per tenant.\n\n" +
+		"```\nRegion\n```\n\n" +
+		"| ID | Description |\n|----|-------------|\n| FR-1 | Pick a region |\n\n" +
+		"## 9. Glossary\n\n| Term | Definition |\n|------|------------|\n| <a id=\"term-tenant\"></a>Tenant | A customer |\n"
+
+	got := LinkGlossaryMarkdown(md, terms)
+	want := "---\ntitle: SLO\n---\n# SLO Plan\n\n| Field | Value |\n|---|---|\n| **Tags** | slo, tenant |\n\n" +
+		"**Tags:** region\n\n" +
+		"See [SLO docs](https://x/slo) and `SLO`. Each [SLO](#term-service-level-objective) covers [tenants](#term-tenant); the service level objective is per tenant.\n\n" +
+		"```\nRegion\n```\n\n" +
+		"| ID | Description |\n|----|-------------|\n| FR-1 | Pick a [region](#term-region) |\n\n" +
+		"## 9. Glossary\n\n| Term | Definition |\n|------|------------|\n| <a id=\"term-tenant\"></a>Tenant | A customer |\n"
+	if got != want {
+		t.Errorf("LinkGlossaryMarkdown =\n%s\nwant\n%s", got, want)
+	}
+
+	if back := UnlinkGlossaryMarkdown(got); back != md[:len(md)-len("| <a id=\"term-tenant\"></a>Tenant | A customer |\n")]+"| Tenant | A customer |\n" {
+		t.Errorf("UnlinkGlossaryMarkdown =\n%s", back)
+	}
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// statusTransitions lists the statuses a document may move to from each
+// status. A document goes draft → in_review → approved → deprecated; review
+// can send it back to draft, and an approved document returns to review when
+// it is revised. Deprecated is final.
+var statusTransitions = map[Status][]Status{
+	StatusDraft:      {StatusInReview},
+	StatusInReview:   {StatusDraft, StatusApproved},
+	StatusApproved:   {StatusInReview, StatusDeprecated},
+	StatusDeprecated: nil,
+}
+
+// Lifecycle is the metadata the document lifecycle policy checks.
+// Used across PRD, MRD, and TRD documents.
+type Lifecycle struct {
+	Status              Status
+	Reviewers           []Person
+	Approvers           []Approver
+	Reviews             []Review
+	RequiredReviewRoles []string
+	ReviewedAt          *time.Time
+	SupersededBy        string
+}
+
+// CheckTransition returns an error unless a document may move from one
+// status to the other. An empty status is treated as draft.
+func CheckTransition(from, to Status) error {
+	if from == "" {
+		from = StatusDraft
+	}
+	next, known := statusTransitions[from]
+	if !known {
+		return fmt.Errorf("unknown status %q", from)
+	}
+	if _, known := statusTransitions[to]; !known {
+		return fmt.Errorf("unknown status %q", to)
+	}
+	if !slices.Contains(next, to) {
+		return fmt.Errorf("cannot move from %s to %s", from, to)
+	}
+	return nil
+}
+
+// Missing returns the fields l lacks for a document in status. An in_review
+// document needs a reviewer; an approved one needs approvals from every
+// listed approver and a review date; a deprecated one needs its successor.
+func (l Lifecycle) Missing(status Status) []string {
+	var missing []string
+	switch status {
+	case StatusInReview:
+		if len(l.Reviewers) == 0 && len(l.Reviews) == 0 {
+			missing = append(missing, "in_review requires at least one reviewer (metadata.reviewers or metadata.reviews)")
+		}
+	case StatusApproved:
+		var pending []string
+		for _, a := range l.Approvers {
+			if !a.Approved {
+				pending = append(pending, a.Name)
+			}
+		}
+		if len(l.Approvers) == 0 {
+			missing = append(missing, "approved requires at least one approver (metadata.approvers)")
+		} else if len(pending) > 0 {
+			missing = append(missing, "approved requires every approver to approve; pending: "+strings.Join(pending, ", "))
+		}
+		if l.ReviewedAt == nil {
+			missing = append(missing, "approved requires a review date (metadata.reviewedAt)")
+		}
+	case StatusDeprecated:
+		if l.SupersededBy == "" {
+			missing = append(missing, "deprecated requires the ID of its successor (metadata.supersededBy)")
+		}
+	}
+	return missing
+}
+
+// Transition checks that l may move to status: the transition must be legal,
+// l must have the fields the new status requires, and an approved document
+// needs sign-off from every required review role.
+func (l Lifecycle) Transition(to Status) error {
+	if err := CheckTransition(l.Status, to); err != nil {
+		return err
+	}
+	problems := l.Missing(to)
+	if to == StatusApproved {
+		if roles := MissingSignoffs(l.Reviews, l.RequiredReviewRoles); len(roles) > 0 {
+			problems = append(problems, "approved requires sign-off from: "+strings.Join(roles, ", "))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("cannot move to %s: %s", to, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ValidateLifecycle reports an unknown status, fields missing for the
+// current one, and a successor named by a document that is not deprecated.
+// Review sign-off is checked by ValidateReviews.
+func ValidateLifecycle(l Lifecycle) []string {
+	if l.Status == "" {
+		return nil
+	}
+	if _, known := statusTransitions[l.Status]; !known {
+		return []string{fmt.Sprintf("unknown status %q (want draft, in_review, approved, or deprecated)", l.Status)}
+	}
+	problems := l.Missing(l.Status)
+	if l.SupersededBy != "" && l.Status != StatusDeprecated {
+		problems = append(problems, fmt.Sprintf("superseded by %s but status is %s, not deprecated", l.SupersededBy, l.Status))
+	}
+	return problems
+}
+
+// SupersededNotice returns the banner shown at the top of a superseded
+// document, e.g. "This PRD is superseded by PRD-023.", or "" when
+// supersededBy is empty.
+func SupersededNotice(kind, supersededBy string) string {
+	if supersededBy == "" {
+		return ""
+	}
+	return fmt.Sprintf("This %s is superseded by %s.", kind, supersededBy)
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckTransition(t *testing.T) {
+	for _, tt := range []struct {
+		from, to Status
+		ok       bool
+	}{
+		{"", StatusInReview, true},
+		{StatusDraft, StatusApproved, false},
+		{StatusInReview, StatusDraft, true},
+		{StatusApproved, StatusDeprecated, true},
+		{StatusDeprecated, StatusDraft, false},
+		{StatusDraft, "published", false},
+	} {
+		if err := CheckTransition(tt.from, tt.to); (err == nil) != tt.ok {
+			t.Errorf("CheckTransition(%q, %q) = %v", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestLifecycleTransition(t *testing.T) {
+	l := Lifecycle{
+		Status:              StatusInReview,
+		Reviews:             []Review{{Person: Person{Name: "alice", Role: "security"}, State: ReviewStatePending}},
+		RequiredReviewRoles: []string{"security"},
+		Approvers:           []Approver{{Person: Person{Name: "pat"}}},
+	}
+	if err := l.Transition(StatusApproved); err == nil {
+		t.Fatal("approval without approvals, review date, or sign-off should fail")
+	}
+	if got := ValidateLifecycle(Lifecycle{Status: StatusApproved}); len(got) != 2 {
+		t.Errorf("ValidateLifecycle = %v", got)
+	}
+
+	now := time.Now()
+	l.Approvers[0].Approved = true
+	l.ReviewedAt = &now
+	l.Reviews[0].State = ReviewStateApproved
+	if err := l.Transition(StatusApproved); err != nil {
+		t.Errorf("Transition = %v", err)
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	l := Lifecycle{Status: StatusApproved, SupersededBy: "PRD-023"}
+	if got := ValidateLifecycle(l); len(got) != 3 {
+		t.Errorf("a superseded document should be deprecated, got %v", got)
+	}
+	l.Status = StatusDeprecated
+	if got := ValidateLifecycle(l); len(got) != 0 {
+		t.Errorf("ValidateLifecycle = %v", got)
+	}
+	l.SupersededBy = ""
+	if got := ValidateLifecycle(l); len(got) != 1 {
+		t.Errorf("a deprecated document should name its successor, got %v", got)
+	}
+	if got := SupersededNotice("PRD", "PRD-023"); got != "This PRD is superseded by PRD-023." {
+		t.Errorf("SupersededNotice = %q", got)
+	}
+}
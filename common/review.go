@@ -0,0 +1,132 @@
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ReviewState is where a reviewer is in reviewing a document.
+type ReviewState string
+
+const (
+	ReviewStatePending          ReviewState = "pending"
+	ReviewStateApproved         ReviewState = "approved"
+	ReviewStateChangesRequested ReviewState = "changes_requested"
+)
+
+// Review records a review requested from one person and its outcome.
+// Used across PRD, MRD, and TRD documents.
+//
+// The reviewer's Role is matched against the document's required review
+// roles: a document cannot be approved until every required role has an
+// approving review and no reviewer in that role has requested changes.
+type Review struct {
+	Person
+	State       ReviewState `json:"state"`
+	RequestedAt *time.Time  `json:"requestedAt,omitempty"`
+	UpdatedAt   *time.Time  `json:"updatedAt,omitempty"`
+	Note        string      `json:"note,omitempty"`
+}
+
+// RequestReview asks p to review the document. A reviewer who already
+// approved or requested changes is asked again, which resets their state to
+// pending, e.g. after the document has been revised.
+func RequestReview(reviews []Review, p Person, at time.Time) ([]Review, error) {
+	if strings.TrimSpace(p.Name) == "" {
+		return reviews, fmt.Errorf("reviewer name is required")
+	}
+	if r := findReview(reviews, p.Name); r != nil {
+		if r.State == ReviewStatePending {
+			return reviews, fmt.Errorf("review from %s is already pending", r.Name)
+		}
+		if p.Role != "" {
+			r.Role = p.Role
+		}
+		if p.Email != "" {
+			r.Email = p.Email
+		}
+		r.State = ReviewStatePending
+		r.RequestedAt = &at
+		r.UpdatedAt = nil
+		r.Note = ""
+		return reviews, nil
+	}
+	return append(reviews, Review{Person: p, State: ReviewStatePending, RequestedAt: &at}), nil
+}
+
+// SetReviewState records the outcome of the review requested from name.
+// Only approved and changes_requested can be set; a reviewer returns to
+// pending when their review is requested again.
+func SetReviewState(reviews []Review, name string, state ReviewState, note string, at time.Time) error {
+	if state != ReviewStateApproved && state != ReviewStateChangesRequested {
+		return fmt.Errorf("invalid review state %q", state)
+	}
+	r := findReview(reviews, name)
+	if r == nil {
+		return fmt.Errorf("no review requested from %s", name)
+	}
+	if r.State == state {
+		return fmt.Errorf("review from %s is already %s", r.Name, state)
+	}
+	r.State = state
+	r.UpdatedAt = &at
+	r.Note = note
+	return nil
+}
+
+// MissingSignoffs returns the required roles, in order, that do not have an
+// approving review or that have a reviewer who requested changes. Roles are
+// compared case-insensitively.
+func MissingSignoffs(reviews []Review, requiredRoles []string) []string {
+	var missing []string
+	for _, role := range requiredRoles {
+		approved, blocked := false, false
+		for _, r := range reviews {
+			if !strings.EqualFold(r.Role, role) {
+				continue
+			}
+			switch r.State {
+			case ReviewStateApproved:
+				approved = true
+			case ReviewStateChangesRequested:
+				blocked = true
+			}
+		}
+		if !approved || blocked {
+			missing = append(missing, role)
+		}
+	}
+	return missing
+}
+
+// ValidateReviews checks review states and that an approved document has
+// sign-off from every required role. Each problem is returned as a message.
+func ValidateReviews(status Status, reviews []Review, requiredRoles []string) []string {
+	var problems []string
+	valid := []ReviewState{ReviewStatePending, ReviewStateApproved, ReviewStateChangesRequested}
+	for i, r := range reviews {
+		if r.Name == "" {
+			problems = append(problems, fmt.Sprintf("reviews[%d]: reviewer name is required", i))
+		}
+		if !slices.Contains(valid, r.State) {
+			problems = append(problems, fmt.Sprintf("reviews[%d]: invalid state %q", i, r.State))
+		}
+	}
+	if status == StatusApproved {
+		if missing := MissingSignoffs(reviews, requiredRoles); len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("status is approved but required roles have not signed off: %s", strings.Join(missing, ", ")))
+		}
+	}
+	return problems
+}
+
+func findReview(reviews []Review, name string) *Review {
+	for i := range reviews {
+		if strings.EqualFold(reviews[i].Name, name) || (reviews[i].Email != "" && strings.EqualFold(reviews[i].Email, name)) {
+			return &reviews[i]
+		}
+	}
+	return nil
+}
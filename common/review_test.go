@@ -0,0 +1,51 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReviewStateMachine(t *testing.T) {
+	now := time.Now()
+	reviews, err := RequestReview(nil, Person{Name: "alice", Role: "Security"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviews, _ = RequestReview(reviews, Person{Name: "bob", Role: "engineering"}, now)
+	if _, err := RequestReview(reviews, Person{Name: "Alice"}, now); err == nil {
+		t.Error("requesting a pending review again should fail")
+	}
+
+	required := []string{"security", "engineering"}
+	if got := MissingSignoffs(reviews, required); len(got) != 2 {
+		t.Errorf("MissingSignoffs = %v", got)
+	}
+
+	if err := SetReviewState(reviews, "alice", ReviewStateApproved, "", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetReviewState(reviews, "alice", ReviewStateApproved, "", now); err == nil {
+		t.Error("approving twice should fail")
+	}
+	if err := SetReviewState(reviews, "bob", ReviewStateChangesRequested, "needs a rollback plan", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetReviewState(reviews, "carol", ReviewStateApproved, "", now); err == nil {
+		t.Error("approving without a request should fail")
+	}
+	if err := SetReviewState(reviews, "bob", ReviewStatePending, "", now); err == nil {
+		t.Error("pending is only set by a new request")
+	}
+	if got := ValidateReviews(StatusApproved, reviews, required); len(got) != 1 {
+		t.Errorf("ValidateReviews = %v", got)
+	}
+
+	reviews, err = RequestReview(reviews, Person{Name: "bob"}, now)
+	if err != nil || reviews[1].State != ReviewStatePending || reviews[1].Note != "" {
+		t.Fatalf("re-request = %+v, %v", reviews[1], err)
+	}
+	_ = SetReviewState(reviews, "bob", ReviewStateApproved, "", now)
+	if got := ValidateReviews(StatusApproved, reviews, required); len(got) != 0 {
+		t.Errorf("ValidateReviews after sign-off = %v", got)
+	}
+}
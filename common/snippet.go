@@ -0,0 +1,19 @@
+package common
+
+// SnippetRef records content a document took from a snippet library, so
+// that local edits to it and changes to the snippet can be detected.
+type SnippetRef struct {
+	// ID is the snippet's ID in the library.
+	ID string `json:"id"`
+
+	// Pointer is the JSON pointer to the content: the first of the list
+	// items it added, or the value it replaced.
+	Pointer string `json:"pointer"`
+
+	// Count is the number of list items the snippet added; 0 if it
+	// replaced a single value.
+	Count int `json:"count,omitempty"`
+
+	// Digest is the SHA-256 digest of the snippet content as expanded.
+	Digest string `json:"digest"`
+}
@@ -0,0 +1,269 @@
+// Package conditions includes or leaves out the sections and items of a
+// planning document depending on its profile and flags, so one master
+// document can serve several product archetypes.
+//
+// The metadata of a document names its profile and flags, and maps a
+// top-level section, such as "uxRequirements", or the ID of an item, such
+// as "NFR-SOC2", to the condition under which it applies:
+//
+//	"metadata": {
+//	  "profile": "enterprise",
+//	  "flags": {"has_ui": true},
+//	  "conditions": {
+//	    "uxRequirements": "has_ui",
+//	    "NFR-SOC2": "profile == enterprise || regulated"
+//	  }
+//	}
+//
+// A condition compares the profile with == or !=, names a flag that must be
+// true, and combines these with !, &&, ||, and parentheses. Unset flags are
+// false.
+package conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/validation"
+)
+
+// RuleCondition is the rule ID of the issues Check reports.
+const RuleCondition = "condition"
+
+func init() {
+	validation.DescribeRule(RuleCondition, "A metadata condition names no section or item of the document.")
+}
+
+// Context is what conditions are evaluated against.
+type Context struct {
+	Profile string
+	Flags   map[string]bool
+}
+
+// Override returns c with the profile replaced by o's, if set, and the
+// flags of o added.
+func (c Context) Override(o Context) Context {
+	out := Context{Profile: c.Profile, Flags: map[string]bool{}}
+	if o.Profile != "" {
+		out.Profile = o.Profile
+	}
+	for k, v := range c.Flags {
+		out.Flags[k] = v
+	}
+	for k, v := range o.Flags {
+		out.Flags[k] = v
+	}
+	return out
+}
+
+// ParseFlags parses flags given as "name" or "name=false".
+func ParseFlags(flags []string) (map[string]bool, error) {
+	out := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		name, value, hasValue := strings.Cut(f, "=")
+		name = strings.TrimSpace(name)
+		if !isName(name) {
+			return nil, fmt.Errorf("invalid flag %q", f)
+		}
+		on := true
+		if hasValue {
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "true", "yes", "1":
+			case "false", "no", "0":
+				on = false
+			default:
+				return nil, fmt.Errorf("invalid flag %q: value must be true or false", f)
+			}
+		}
+		out[name] = on
+	}
+	return out, nil
+}
+
+// metadata is the part of a document's metadata conditions use.
+type metadata struct {
+	Metadata struct {
+		Profile    string            `json:"profile"`
+		Flags      map[string]bool   `json:"flags"`
+		Conditions map[string]string `json:"conditions"`
+	} `json:"metadata"`
+}
+
+func readMetadata(data []byte) (*metadata, error) {
+	var m metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Apply returns the JSON document data without the sections and items
+// whose condition is false for the document's profile and flags, as
+// overridden by override, and the names and IDs left out, sorted. The
+// metadata is never left out. If the document has no conditions, data is
+// returned unchanged.
+func Apply(data []byte, override Context) ([]byte, []string, error) {
+	m, err := readMetadata(data)
+	if err != nil || len(m.Metadata.Conditions) == 0 {
+		return data, nil, nil
+	}
+	ctx := Context{Profile: m.Metadata.Profile, Flags: m.Metadata.Flags}.Override(override)
+
+	excluded := map[string]bool{}
+	for key, cond := range m.Metadata.Conditions {
+		e, err := Parse(cond)
+		if err != nil {
+			return nil, nil, fmt.Errorf("metadata.conditions[%q]: %w", key, err)
+		}
+		if !e.Eval(ctx) {
+			excluded[key] = true
+		}
+	}
+	if len(excluded) == 0 {
+		return data, nil, nil
+	}
+
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, ok := root.(*query.Object)
+	if !ok {
+		return data, nil, nil
+	}
+	removed := map[string]bool{}
+	keys := doc.Keys[:0]
+	for _, k := range doc.Keys {
+		if k != "metadata" && excluded[k] {
+			delete(doc.Fields, k)
+			removed[k] = true
+			continue
+		}
+		if k != "metadata" {
+			doc.Fields[k] = prune(doc.Fields[k], excluded, removed)
+		}
+		keys = append(keys, k)
+	}
+	doc.Keys = keys
+	if len(removed) == 0 {
+		return data, nil, nil
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	names := make([]string, 0, len(removed))
+	for k := range removed {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return out, names, nil
+}
+
+// prune returns v without the objects whose ID is excluded, recording the
+// IDs removed.
+func prune(v any, excluded, removed map[string]bool) any {
+	switch x := v.(type) {
+	case *query.Object:
+		keys := x.Keys[:0]
+		for _, k := range x.Keys {
+			if id := objectID(x.Fields[k]); id != "" && excluded[id] {
+				delete(x.Fields, k)
+				removed[id] = true
+				continue
+			}
+			x.Fields[k] = prune(x.Fields[k], excluded, removed)
+			keys = append(keys, k)
+		}
+		x.Keys = keys
+		return x
+	case []any:
+		out := make([]any, 0, len(x))
+		for _, e := range x {
+			if id := objectID(e); id != "" && excluded[id] {
+				removed[id] = true
+				continue
+			}
+			out = append(out, prune(e, excluded, removed))
+		}
+		return out
+	}
+	return v
+}
+
+func objectID(v any) string {
+	obj, ok := v.(*query.Object)
+	if !ok {
+		return ""
+	}
+	id, _ := obj.Fields["id"].(string)
+	return id
+}
+
+// Check reports the conditions of a JSON document that name neither a
+// top-level section nor the ID of an item, as warnings pointing at the
+// condition. Conditions that do not parse are left to Apply.
+func Check(data []byte) ([]validation.Issue, error) {
+	m, err := readMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Metadata.Conditions) == 0 {
+		return nil, nil
+	}
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	if doc, ok := root.(*query.Object); ok {
+		for _, k := range doc.Keys {
+			if k != "metadata" {
+				known[k] = true
+				collectIDs(doc.Fields[k], known)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(m.Metadata.Conditions))
+	for k := range m.Metadata.Conditions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var issues []validation.Issue
+	for _, key := range keys {
+		if known[key] {
+			continue
+		}
+		pointer := jsonpatch.Pointer("metadata", "conditions", key)
+		issues = append(issues, validation.Issue{
+			RuleID:   RuleCondition,
+			Severity: validation.SeverityWarning,
+			Pointer:  pointer,
+			Message:  fmt.Sprintf("%s: %q is neither a section nor an item ID of the document", pointer, key),
+		})
+	}
+	return issues, nil
+}
+
+func collectIDs(v any, ids map[string]bool) {
+	switch x := v.(type) {
+	case *query.Object:
+		if id := objectID(x); id != "" {
+			ids[id] = true
+		}
+		for _, k := range x.Keys {
+			collectIDs(x.Fields[k], ids)
+		}
+	case []any:
+		for _, e := range x {
+			collectIDs(e, ids)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package conditions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	ctx := Context{Profile: "Enterprise", Flags: map[string]bool{"has_ui": true, "regulated": false}}
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"has_ui", true},
+		{"regulated", false},
+		{"unset", false},
+		{"!regulated", true},
+		{"profile == enterprise", true},
+		{`profile == "smb"`, false},
+		{"profile != smb", true},
+		{"profile == smb || has_ui", true},
+		{"profile == enterprise && regulated", false},
+		{"!(regulated || profile == smb) && has_ui", true},
+		{"regulated || has_ui && profile == smb", false},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.cond)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.cond, err)
+			continue
+		}
+		if got := e.Eval(ctx); got != tt.want {
+			t.Errorf("Parse(%q).Eval() = %v, want %v", tt.cond, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "profile", "profile = x", "has_ui &&", "(has_ui", "has_ui has_ui", "a $ b"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	got, err := ParseFlags([]string{"has_ui", "regulated=false", "beta=yes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got["has_ui"] || got["regulated"] || !got["beta"] {
+		t.Errorf("ParseFlags() = %v", got)
+	}
+	for _, bad := range []string{"", "a b", "x=maybe"} {
+		if _, err := ParseFlags([]string{bad}); err == nil {
+			t.Errorf("ParseFlags(%q) succeeded", bad)
+		}
+	}
+}
+
+const testDoc = `{"metadata": {"id": "PRD-1", "profile": "smb", "flags": {"has_ui": true},
+	"conditions": {"uxRequirements": "has_ui", "risks": "profile == enterprise", "NFR-2": "regulated", "metadata": "false"}},
+	"uxRequirements": {"principles": ["Simple"]},
+	"requirements": {"nonFunctional": [{"id": "NFR-1"}, {"id": "NFR-2"}]},
+	"risks": [{"id": "R-1"}]}`
+
+func TestApply(t *testing.T) {
+	data, removed, err := Apply([]byte(testDoc), Context{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(removed, ","); got != "NFR-2,risks" {
+		t.Errorf("removed = %s, want NFR-2,risks", got)
+	}
+	got := string(data)
+	if strings.Contains(got, `"risks":[`) || strings.Contains(got, "NFR-2\"}") || !strings.Contains(got, `"uxRequirements"`) || !strings.HasPrefix(got, `{"metadata":`) {
+		t.Errorf("Apply() = %s", got)
+	}
+
+	data, removed, err = Apply([]byte(testDoc), Context{Profile: "enterprise", Flags: map[string]bool{"has_ui": false, "regulated": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(removed, ",") != "uxRequirements" || strings.Contains(string(data), `"principles"`) {
+		t.Errorf("Apply() with overrides removed %v: %s", removed, data)
+	}
+
+	same := `{"metadata": {"id": "PRD-1"}, "risks": []}`
+	if data, removed, err := Apply([]byte(same), Context{}); err != nil || removed != nil || string(data) != same {
+		t.Errorf("Apply() without conditions = %s, %v, %v", data, removed, err)
+	}
+	if _, _, err := Apply([]byte(`{"metadata": {"conditions": {"risks": "profile ="}}}`), Context{}); err == nil {
+		t.Error("expected an error for a condition that does not parse")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	issues, err := Check([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Pointer != "/metadata/conditions/metadata" {
+		t.Errorf("Check() = %v, want one issue for metadata", issues)
+	}
+}
@@ -0,0 +1,182 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed condition.
+type Expr interface {
+	// Eval reports whether the condition holds in ctx.
+	Eval(ctx Context) bool
+}
+
+type (
+	flagExpr    string
+	profileExpr struct {
+		value string
+		equal bool
+	}
+	notExpr struct{ x Expr }
+	andExpr struct{ x, y Expr }
+	orExpr  struct{ x, y Expr }
+)
+
+func (e flagExpr) Eval(ctx Context) bool { return ctx.Flags[string(e)] }
+func (e profileExpr) Eval(ctx Context) bool {
+	return strings.EqualFold(ctx.Profile, e.value) == e.equal
+}
+func (e notExpr) Eval(ctx Context) bool { return !e.x.Eval(ctx) }
+func (e andExpr) Eval(ctx Context) bool { return e.x.Eval(ctx) && e.y.Eval(ctx) }
+func (e orExpr) Eval(ctx Context) bool  { return e.x.Eval(ctx) || e.y.Eval(ctx) }
+
+// Parse parses a condition such as "profile == enterprise && !has_ui".
+// Profile values may be quoted.
+func Parse(s string) (Expr, error) {
+	p := &parser{tokens: tokenize(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	e, err := p.or()
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", s, err)
+	}
+	if t := p.peek(); t != "" {
+		return nil, fmt.Errorf("condition %q: unexpected %q", s, t)
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	if t != "" {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (Expr, error) {
+	x, err := p.and()
+	for err == nil && p.peek() == "||" {
+		p.next()
+		var y Expr
+		if y, err = p.and(); err == nil {
+			x = orExpr{x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) and() (Expr, error) {
+	x, err := p.unary()
+	for err == nil && p.peek() == "&&" {
+		p.next()
+		var y Expr
+		if y, err = p.unary(); err == nil {
+			x = andExpr{x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) unary() (Expr, error) {
+	switch t := p.next(); {
+	case t == "":
+		return nil, fmt.Errorf("unexpected end")
+	case t == "!":
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	case t == "(":
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		return x, nil
+	case t == "profile":
+		op := p.next()
+		if op != "==" && op != "!=" {
+			return nil, fmt.Errorf("profile must be compared with == or !=")
+		}
+		v := p.next()
+		if v == "" || !isName(strings.Trim(v, `"'`)) {
+			return nil, fmt.Errorf("expected a profile after %s", op)
+		}
+		return profileExpr{value: strings.Trim(v, `"'`), equal: op == "=="}, nil
+	case isName(t):
+		return flagExpr(t), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", t)
+	}
+}
+
+// tokenize splits s into names, quoted values, and the operators !, ==,
+// !=, &&, ||, and parentheses. Other characters are returned alone, so
+// the parser reports them.
+func tokenize(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(s[i:], "==") || strings.HasPrefix(s[i:], "!=") ||
+			strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, s[i:i+2])
+			i += 2
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				tokens = append(tokens, s[i:])
+				return tokens
+			}
+			tokens = append(tokens, s[i:i+end+2])
+			i += end + 2
+		case isNameRune(rune(c)):
+			j := i
+			for j < len(s) && isNameRune(rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			tokens = append(tokens, s[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
+
+func isNameRune(r rune) bool {
+	return r < unicode.MaxASCII && (r == '_' || r == '-' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isNameRune(r) {
+			return false
+		}
+	}
+	return true
+}
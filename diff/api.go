@@ -0,0 +1,233 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// Impact classifies an API change by its effect on existing consumers.
+type Impact string
+
+const (
+	// Breaking changes can fail requests from existing clients.
+	Breaking Impact = "breaking"
+	// NonBreaking changes are safe for existing clients.
+	NonBreaking Impact = "non-breaking"
+)
+
+// APIChange is an added, removed, or changed API or endpoint.
+type APIChange struct {
+	APIID    string        `json:"apiId,omitempty"`
+	API      string        `json:"api"`
+	Endpoint string        `json:"endpoint,omitempty"` // "METHOD /path"; empty for the API itself
+	Kind     Kind          `json:"kind"`
+	Impact   Impact        `json:"impact"`
+	Reason   string        `json:"reason,omitempty"` // Why a changed API or endpoint is breaking
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+// APIReport is the change-impact analysis of the APIs of two TRD versions.
+type APIReport struct {
+	Title      string      `json:"title,omitempty"`
+	OldVersion string      `json:"oldVersion,omitempty"`
+	NewVersion string      `json:"newVersion,omitempty"`
+	Changes    []APIChange `json:"changes"`
+}
+
+// Count returns the number of changes with impact i.
+func (r *APIReport) Count(i Impact) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Impact == i {
+			n++
+		}
+	}
+	return n
+}
+
+// Breaking reports whether any change breaks existing consumers.
+func (r *APIReport) Breaking() bool {
+	return r.Count(Breaking) > 0
+}
+
+// Empty reports whether the API specifications are unchanged.
+func (r *APIReport) Empty() bool {
+	return len(r.Changes) == 0
+}
+
+// breakingAPIFields are API fields whose change means existing clients must
+// be reconfigured: where they connect, how they authenticate, and the
+// protocol they speak.
+var breakingAPIFields = map[string]string{
+	"baseUrl": "base URL changed",
+	"auth":    "authentication changed",
+	"type":    "protocol changed",
+}
+
+// breakingEndpointFields are endpoint fields whose change invalidates the
+// payloads existing clients send or parse.
+var breakingEndpointFields = map[string]string{
+	"request":  "request schema changed",
+	"response": "response schema changed",
+}
+
+// TRDAPI compares the API specifications of two versions of a TRD. APIs are
+// matched by ID, or name when they have no ID, and endpoints by method and
+// path, so a moved endpoint shows as a removal and an addition.
+//
+// Removed APIs and endpoints, changed request or response schemas, and a
+// changed base URL, authentication, protocol, or major version are
+// breaking. Everything else, such as new endpoints, new error codes, and
+// reworded descriptions, is not.
+func TRDAPI(old, new *trd.Document) *APIReport {
+	r := &APIReport{
+		Title:      new.Metadata.Title,
+		OldVersion: old.Metadata.Version,
+		NewVersion: new.Metadata.Version,
+	}
+	newAPIs := make(map[string]trd.APISpec, len(new.APISpecifications))
+	for _, a := range new.APISpecifications {
+		newAPIs[apiKey(a.ID, a.Name)] = a
+	}
+	seen := make(map[string]bool, len(old.APISpecifications))
+
+	// Removed and changed APIs are reported in old order, each followed by
+	// its endpoint changes, then added APIs in new order.
+	for _, o := range old.APISpecifications {
+		k := apiKey(o.ID, o.Name)
+		seen[k] = true
+		n, ok := newAPIs[k]
+		if !ok {
+			r.Changes = append(r.Changes, APIChange{APIID: o.ID, API: o.Name, Kind: Removed, Impact: Breaking})
+			continue
+		}
+		if fields := fieldChanges(o, n, "endpoints"); len(fields) > 0 {
+			impact, reason := apiFieldImpact(fields)
+			r.Changes = append(r.Changes, APIChange{APIID: n.ID, API: n.Name, Kind: Changed, Impact: impact, Reason: reason, Fields: fields})
+		}
+		for _, c := range entities("Endpoints", o.Endpoints, n.Endpoints, endpointKey, "method") {
+			ec := APIChange{APIID: n.ID, API: n.Name, Endpoint: c.ID, Kind: c.Kind, Impact: NonBreaking, Fields: c.Fields}
+			switch c.Kind {
+			case Removed:
+				ec.Impact = Breaking
+			case Changed:
+				ec.Impact, ec.Reason = endpointFieldImpact(c.Fields)
+			}
+			r.Changes = append(r.Changes, ec)
+		}
+	}
+	for _, n := range new.APISpecifications {
+		if !seen[apiKey(n.ID, n.Name)] {
+			r.Changes = append(r.Changes, APIChange{APIID: n.ID, API: n.Name, Kind: Added, Impact: NonBreaking})
+		}
+	}
+	return r
+}
+
+func apiKey(id, name string) string {
+	if id != "" {
+		return "id:" + id
+	}
+	return "name:" + name
+}
+
+func endpointKey(e trd.APIEndpoint) (string, string) {
+	return strings.ToUpper(e.Method) + " " + e.Path, ""
+}
+
+func apiFieldImpact(fields []FieldChange) (Impact, string) {
+	var reasons []string
+	for _, f := range fields {
+		if reason, ok := breakingAPIFields[f.Field]; ok {
+			reasons = append(reasons, reason)
+		}
+		if f.Field == "version" && majorVersion(f.Old) != majorVersion(f.New) {
+			reasons = append(reasons, "major version changed")
+		}
+	}
+	if len(reasons) > 0 {
+		return Breaking, strings.Join(reasons, "; ")
+	}
+	return NonBreaking, ""
+}
+
+func endpointFieldImpact(fields []FieldChange) (Impact, string) {
+	var reasons []string
+	for _, f := range fields {
+		if reason, ok := breakingEndpointFields[f.Field]; ok {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) > 0 {
+		return Breaking, strings.Join(reasons, "; ")
+	}
+	return NonBreaking, ""
+}
+
+// majorVersion returns the major part of a version such as "v2", "2.1.0",
+// or "v1beta".
+func majorVersion(v string) string {
+	v = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "v")
+	if i := strings.IndexFunc(v, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		v = v[:i]
+	}
+	return v
+}
+
+// ToMarkdown renders the report for API consumers, with breaking changes
+// first.
+func (r *APIReport) ToMarkdown() string {
+	var sb strings.Builder
+	title := "API Changes"
+	if r.Title != "" {
+		title = "API Changes: " + r.Title
+	}
+	sb.WriteString("# " + title + "\n\n")
+	switch {
+	case r.OldVersion != "" && r.NewVersion != "" && r.OldVersion != r.NewVersion:
+		sb.WriteString(fmt.Sprintf("Version %s → %s\n\n", r.OldVersion, r.NewVersion))
+	case r.NewVersion != "":
+		sb.WriteString(fmt.Sprintf("Version %s\n\n", r.NewVersion))
+	}
+	if r.Empty() {
+		sb.WriteString("No API changes.\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%d breaking, %d non-breaking.\n", r.Count(Breaking), r.Count(NonBreaking)))
+
+	for _, section := range []struct {
+		impact  Impact
+		heading string
+	}{
+		{Breaking, "Breaking Changes"},
+		{NonBreaking, "Non-Breaking Changes"},
+	} {
+		if r.Count(section.impact) == 0 {
+			continue
+		}
+		sb.WriteString("\n## " + section.heading + "\n")
+		api := ""
+		for _, c := range r.Changes {
+			if c.Impact != section.impact {
+				continue
+			}
+			if c.API != api {
+				api = c.API
+				sb.WriteString("\n### " + api + "\n\n")
+			}
+			subject := "API"
+			if c.Endpoint != "" {
+				subject = "`" + c.Endpoint + "`"
+			}
+			sb.WriteString("- " + subject + " " + string(c.Kind))
+			if c.Reason != "" {
+				sb.WriteString(": " + c.Reason)
+			}
+			sb.WriteString("\n")
+			writeFields(&sb, c.Fields, "  ")
+		}
+	}
+	return sb.String()
+}
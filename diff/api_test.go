@@ -0,0 +1,97 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+func TestTRDAPI(t *testing.T) {
+	old := &trd.Document{
+		Metadata: trd.Metadata{Title: "Orders", Version: "1.0.0"},
+		APISpecifications: []trd.APISpec{
+			{ID: "orders", Name: "Orders API", Type: "REST", Version: "v1", BaseURL: "https://api.example.com",
+				Endpoints: []trd.APIEndpoint{
+					{Method: "GET", Path: "/orders", Response: "OrderList"},
+					{Method: "post", Path: "/orders", Request: "NewOrder", Response: "Order"},
+					{Method: "DELETE", Path: "/orders/{id}"},
+				}},
+			{ID: "legacy", Name: "Legacy API", Type: "SOAP"},
+		},
+	}
+	new := &trd.Document{
+		Metadata: trd.Metadata{Title: "Orders", Version: "1.1.0"},
+		APISpecifications: []trd.APISpec{
+			{ID: "orders", Name: "Orders API", Type: "REST", Version: "v1.1", BaseURL: "https://api.example.com",
+				Description: "Order management",
+				Endpoints: []trd.APIEndpoint{
+					{Method: "GET", Path: "/orders", Response: "OrderList", Errors: []string{"429"}},
+					{Method: "POST", Path: "/orders", Request: "NewOrderV2", Response: "Order"},
+					{Method: "GET", Path: "/orders/{id}", Response: "Order"},
+				}},
+			{ID: "events", Name: "Events API", Type: "Webhook"},
+		},
+	}
+
+	r := TRDAPI(old, new)
+	type row struct {
+		api, endpoint string
+		kind          Kind
+		impact        Impact
+	}
+	want := []row{
+		{"Orders API", "", Changed, NonBreaking},
+		{"Orders API", "GET /orders", Changed, NonBreaking},
+		{"Orders API", "POST /orders", Changed, Breaking},
+		{"Orders API", "DELETE /orders/{id}", Removed, Breaking},
+		{"Orders API", "GET /orders/{id}", Added, NonBreaking},
+		{"Legacy API", "", Removed, Breaking},
+		{"Events API", "", Added, NonBreaking},
+	}
+	if len(r.Changes) != len(want) {
+		t.Fatalf("changes = %+v", r.Changes)
+	}
+	for i, w := range want {
+		c := r.Changes[i]
+		if c.API != w.api || c.Endpoint != w.endpoint || c.Kind != w.kind || c.Impact != w.impact {
+			t.Errorf("change %d = %+v, want %+v", i, c, w)
+		}
+	}
+	if !r.Breaking() || r.Count(Breaking) != 3 {
+		t.Errorf("breaking = %d", r.Count(Breaking))
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"3 breaking, 4 non-breaking.",
+		"## Breaking Changes\n\n### Orders API\n\n- `POST /orders` changed: request schema changed\n  - `request`: \"NewOrder\" → \"NewOrderV2\"\n",
+		"- `DELETE /orders/{id}` removed\n\n### Legacy API\n\n- API removed\n",
+		"### Events API\n\n- API added\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if !TRDAPI(old, old).Empty() {
+		t.Error("a document compared with itself should have no API changes")
+	}
+}
+
+func TestAPIFieldImpact(t *testing.T) {
+	for _, tt := range []struct {
+		field, old, new string
+		want            Impact
+	}{
+		{"version", "v1", "v2", Breaking},
+		{"version", "1.2.0", "1.3.0", NonBreaking},
+		{"version", "v1beta", "v1", NonBreaking},
+		{"auth", "API Key", "OAuth2", Breaking},
+		{"rateLimit", "100/min", "200/min", NonBreaking},
+	} {
+		if got, _ := apiFieldImpact([]FieldChange{{Field: tt.field, Old: tt.old, New: tt.new}}); got != tt.want {
+			t.Errorf("%s %q → %q = %s, want %s", tt.field, tt.old, tt.new, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package diff compares two versions of a planning document entity by
+// entity, so reviewers see which requirements, personas, goals, and phases
+// were added, removed, or changed instead of reading a raw JSON diff.
+//
+// Entities are matched by ID, or by title when they have no ID. A changed
+// entity lists its changed top-level fields with the old and new values.
+//
+// Text compares two rendered versions of a document instead, line by line
+// and word by word, for side-by-side previews of the generated prose.
+//
+// TRDAPI compares the API specifications of two TRDs endpoint by endpoint
+// and classifies each change as breaking or non-breaking for consumers.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Kind is the kind of change made to an entity.
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// FieldChange is a changed field of an entity. Values are shown as text:
+// strings as they are, anything else as compact JSON.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Change is one added, removed, or changed entity.
+type Change struct {
+	Section string        `json:"section"` // e.g. "Functional Requirements"
+	ID      string        `json:"id,omitempty"`
+	Title   string        `json:"title,omitempty"`
+	Kind    Kind          `json:"kind"`
+	Fields  []FieldChange `json:"fields,omitempty"`
+}
+
+// Report is the semantic diff between two documents.
+type Report struct {
+	Title      string `json:"title,omitempty"`
+	OldVersion string `json:"oldVersion,omitempty"`
+	NewVersion string `json:"newVersion,omitempty"`
+
+	// Metadata lists changed document metadata fields other than the
+	// update time.
+	Metadata []FieldChange `json:"metadata,omitempty"`
+	Changes  []Change      `json:"changes"`
+}
+
+// Count returns the number of changes of kind k.
+func (r *Report) Count(k Kind) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Kind == k {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty reports whether the documents have no differences the report covers.
+func (r *Report) Empty() bool {
+	return len(r.Changes) == 0 && len(r.Metadata) == 0
+}
+
+// PRD compares two versions of a PRD: functional and non-functional
+// requirements, user stories, personas, objectives, key results, and
+// roadmap phases.
+func PRD(old, new *prd.Document) *Report {
+	r := &Report{
+		Title:      new.Metadata.Title,
+		OldVersion: old.Metadata.Version,
+		NewVersion: new.Metadata.Version,
+		Metadata:   fieldChanges(old.Metadata, new.Metadata, "updatedAt"),
+	}
+	add := func(changes []Change) { r.Changes = append(r.Changes, changes...) }
+	add(entities("Functional Requirements", old.Requirements.Functional, new.Requirements.Functional,
+		func(e prd.FunctionalRequirement) (string, string) { return e.ID, e.Title }))
+	add(entities("Non-Functional Requirements", old.Requirements.NonFunctional, new.Requirements.NonFunctional,
+		func(e prd.NonFunctionalRequirement) (string, string) { return e.ID, e.Title }))
+	add(entities("User Stories", old.UserStories, new.UserStories,
+		func(e prd.UserStory) (string, string) { return e.ID, e.Title }))
+	add(entities("Personas", old.Personas, new.Personas,
+		func(e prd.Persona) (string, string) { return e.ID, e.Name }))
+	add(entities("Objectives", objectives(old), objectives(new),
+		func(e okr.Objective) (string, string) { return e.ID, e.Title }, "keyResults"))
+	add(entities("Key Results", keyResults(old), keyResults(new),
+		func(e okr.KeyResult) (string, string) { return e.ID, e.Title }))
+	add(entities("Roadmap Phases", old.Roadmap.Phases, new.Roadmap.Phases,
+		func(e prd.Phase) (string, string) { return e.ID, e.Name }, "deliverables"))
+	return r
+}
+
+// objectives returns the PRD's objectives without their key results, which
+// are compared separately.
+func objectives(doc *prd.Document) []okr.Objective {
+	out := make([]okr.Objective, len(doc.Objectives.OKRs))
+	for i, o := range doc.Objectives.OKRs {
+		out[i] = o.Objective
+	}
+	return out
+}
+
+func keyResults(doc *prd.Document) []okr.KeyResult {
+	var out []okr.KeyResult
+	for _, o := range doc.Objectives.OKRs {
+		out = append(out, o.Objective.KeyResults...)
+		out = append(out, o.KeyResults...)
+	}
+	return out
+}
+
+// entities compares two lists of entities matched by the ID, or title when
+// the ID is empty, returned by key. Removed and changed entities are
+// reported in old order, then added ones in new order. Fields named in
+// ignore are not compared.
+func entities[T any](section string, old, new []T, key func(T) (id, title string), ignore ...string) []Change {
+	match := func(id, title string) string {
+		if id != "" {
+			return "id:" + id
+		}
+		return "title:" + title
+	}
+	newIndex := make(map[string]int, len(new))
+	for i, e := range new {
+		newIndex[match(key(e))] = i
+	}
+	seen := make(map[string]bool, len(old))
+
+	var changes []Change
+	for _, o := range old {
+		id, title := key(o)
+		k := match(id, title)
+		seen[k] = true
+		i, ok := newIndex[k]
+		if !ok {
+			changes = append(changes, Change{Section: section, ID: id, Title: title, Kind: Removed})
+			continue
+		}
+		if fields := fieldChanges(o, new[i], ignore...); len(fields) > 0 {
+			_, newTitle := key(new[i])
+			changes = append(changes, Change{Section: section, ID: id, Title: newTitle, Kind: Changed, Fields: fields})
+		}
+	}
+	for _, n := range new {
+		id, title := key(n)
+		if !seen[match(id, title)] {
+			changes = append(changes, Change{Section: section, ID: id, Title: title, Kind: Added})
+		}
+	}
+	return changes
+}
+
+// fieldChanges compares the JSON form of old and new field by field.
+func fieldChanges(old, new any, ignore ...string) []FieldChange {
+	a, b := toMap(old), toMap(new)
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []FieldChange
+	for _, k := range keys {
+		if slices.Contains(ignore, k) || reflect.DeepEqual(a[k], b[k]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: k, Old: format(a[k]), New: format(b[k])})
+	}
+	return changes
+}
+
+func toMap(v any) map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// maxValueLen limits how much of a changed value is shown.
+const maxValueLen = 120
+
+func format(v any) string {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(data)
+		}
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) > maxValueLen {
+		s = string([]rune(s)[:maxValueLen-1]) + "…"
+	}
+	return s
+}
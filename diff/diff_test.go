@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func TestPRD(t *testing.T) {
+	old := &prd.Document{
+		Metadata: prd.Metadata{Title: "Checkout", Version: "1.0.0"},
+		Personas: []prd.Persona{{ID: "guest", Name: "Guest Shopper"}},
+		Requirements: prd.Requirements{Functional: []prd.FunctionalRequirement{
+			{ID: "FR-1", Title: "One-page checkout", Priority: "should"},
+			{ID: "FR-2", Title: "Gift cards"},
+		}},
+	}
+	old.Objectives.OKRs = []okr.OKR{{Objective: okr.Objective{ID: "O1", Title: "Convert more",
+		KeyResults: []okr.KeyResult{{ID: "KR1", Title: "Conversion", Target: "3%"}}}}}
+
+	new := &prd.Document{
+		Metadata: prd.Metadata{Title: "Checkout", Version: "1.1.0"},
+		Requirements: prd.Requirements{Functional: []prd.FunctionalRequirement{
+			{ID: "FR-1", Title: "One-page checkout", Priority: "must"},
+			{ID: "FR-3", Title: "Passkeys"},
+		}},
+	}
+	new.Objectives.OKRs = []okr.OKR{{Objective: okr.Objective{ID: "O1", Title: "Convert more",
+		KeyResults: []okr.KeyResult{{ID: "KR1", Title: "Conversion", Target: "4%"}}}}}
+
+	r := PRD(old, new)
+	if r.Count(Added) != 1 || r.Count(Removed) != 2 || r.Count(Changed) != 2 {
+		t.Fatalf("changes = %+v", r.Changes)
+	}
+	for _, c := range r.Changes {
+		if c.Section == "Objectives" {
+			t.Errorf("objective reported as changed by its key result: %+v", c)
+		}
+	}
+	if len(r.Metadata) != 1 || r.Metadata[0].Field != "version" {
+		t.Errorf("metadata = %+v", r.Metadata)
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"Version 1.0.0 → 1.1.0",
+		"## Added\n\n### Functional Requirements\n\n- **FR-3** Passkeys\n",
+		"- **FR-1** One-page checkout\n  - `priority`: \"should\" → \"must\"\n",
+		"### Key Results\n\n- **KR1** Conversion\n  - `target`: \"3%\" → \"4%\"\n",
+		"### Personas\n\n- **guest** Guest Shopper\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if !PRD(old, old).Empty() {
+		t.Error("a document compared with itself should have no changes")
+	}
+}
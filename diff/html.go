@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// HTML renders the diff as a standalone page with the old and new versions
+// side by side. Changed words are highlighted within each changed line.
+func (d *TextDiff) HTML() ([]byte, error) {
+	changed, added, removed := d.Counts()
+	title := "Changes"
+	if d.Title != "" {
+		title = "Changes: " + d.Title
+	}
+	data := struct {
+		*TextDiff
+		PageTitle string
+		Summary   string
+	}{
+		TextDiff:  d,
+		PageTitle: title,
+		Summary: fmt.Sprintf("%s changed, %s added, %s removed",
+			plural(changed, "line"), plural(added, "line"), plural(removed, "line")),
+	}
+	if d.Empty() {
+		data.Summary = "No changes."
+	}
+	var buf bytes.Buffer
+	if err := textDiffTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering diff: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// lineClass styles markdown headings and table rows in the diff.
+func lineClass(l *Line) string {
+	text := strings.TrimSpace(l.Text())
+	switch {
+	case strings.HasPrefix(text, "#"):
+		return "heading"
+	case strings.HasPrefix(text, "|"):
+		return "table"
+	}
+	return ""
+}
+
+var textDiffTmpl = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"lineClass": lineClass,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.PageTitle}}</title>
+<style>
+body { margin: 0; padding: 1.5rem; color: #1f2933; font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+h1 { font-size: 1.6rem; margin: 0 0 0.25rem; }
+.summary { color: #616e7c; margin: 0 0 1rem; }
+table { width: 100%; border-collapse: collapse; table-layout: fixed; }
+th { position: sticky; top: 0; background: #f5f7fa; border-bottom: 1px solid #d9e2ec; padding: 0.4rem 0.6rem; text-align: left; overflow-wrap: anywhere; }
+td { vertical-align: top; padding: 0.1rem 0.6rem; white-space: pre-wrap; overflow-wrap: anywhere; }
+td.num { width: 3.5rem; color: #9aa5b1; text-align: right; font-size: 0.8rem; user-select: none; }
+th.num { width: 3.5rem; }
+td.old.changed { background: #fff5f5; }
+td.new.changed { background: #f0fff4; }
+td.empty { background: #f5f7fa; }
+del { background: #feb2b2; text-decoration: line-through; }
+ins { background: #9ae6b4; text-decoration: none; }
+.heading { font-weight: 700; }
+.table { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.85rem; }
+tr.skipped td { background: #f5f7fa; color: #616e7c; text-align: center; font-style: italic; padding: 0.25rem; }
+</style>
+</head>
+<body>
+<h1>{{.PageTitle}}</h1>
+<p class="summary">{{.Summary}}</p>
+<table>
+<thead><tr><th class="num"></th><th>{{or .OldLabel "Old"}}</th><th class="num"></th><th>{{or .NewLabel "New"}}</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+{{- if .Skipped}}
+<tr class="skipped"><td colspan="4">⋯ {{.Skipped}} unchanged line{{if ne .Skipped 1}}s{{end}}</td></tr>
+{{- else}}
+<tr>
+{{- $changed := .Changed}}
+{{- with .Old}}<td class="num">{{.Number}}</td><td class="old{{if $changed}} changed{{end}}{{with lineClass .}} {{.}}{{end}}">{{range .Segments}}{{if .Changed}}<del>{{.Text}}</del>{{else}}{{.Text}}{{end}}{{end}}</td>{{else}}<td class="num"></td><td class="empty"></td>{{end}}
+{{- with .New}}<td class="num">{{.Number}}</td><td class="new{{if $changed}} changed{{end}}{{with lineClass .}} {{.}}{{end}}">{{range .Segments}}{{if .Changed}}<ins>{{.Text}}</ins>{{else}}{{.Text}}{{end}}{{end}}</td>{{else}}<td class="num"></td><td class="empty"></td>{{end}}
+</tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMarkdown renders the report as a changelog entry with Added, Changed,
+// and Removed sections, in the style of Keep a Changelog.
+func (r *Report) ToMarkdown() string {
+	var sb strings.Builder
+	title := "Changes"
+	if r.Title != "" {
+		title = "Changes: " + r.Title
+	}
+	sb.WriteString("# " + title + "\n\n")
+	switch {
+	case r.OldVersion != "" && r.NewVersion != "" && r.OldVersion != r.NewVersion:
+		sb.WriteString(fmt.Sprintf("Version %s → %s\n\n", r.OldVersion, r.NewVersion))
+	case r.NewVersion != "":
+		sb.WriteString(fmt.Sprintf("Version %s\n\n", r.NewVersion))
+	}
+	if r.Empty() {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%d added, %d changed, %d removed.\n", r.Count(Added), r.Count(Changed), r.Count(Removed)))
+
+	if len(r.Metadata) > 0 {
+		sb.WriteString("\n## Metadata\n\n")
+		writeFields(&sb, r.Metadata, "")
+	}
+	for _, k := range []Kind{Added, Changed, Removed} {
+		if r.Count(k) == 0 {
+			continue
+		}
+		sb.WriteString("\n## " + strings.ToUpper(string(k[:1])) + string(k[1:]) + "\n")
+		section := ""
+		for _, c := range r.Changes {
+			if c.Kind != k {
+				continue
+			}
+			if c.Section != section {
+				section = c.Section
+				sb.WriteString("\n### " + section + "\n\n")
+			}
+			sb.WriteString("- " + c.label() + "\n")
+			writeFields(&sb, c.Fields, "  ")
+		}
+	}
+	return sb.String()
+}
+
+// label names the change as "**ID** Title", or whichever of the two is set.
+func (c Change) label() string {
+	switch {
+	case c.ID != "" && c.Title != "":
+		return "**" + c.ID + "** " + c.Title
+	case c.ID != "":
+		return "**" + c.ID + "**"
+	}
+	return c.Title
+}
+
+func writeFields(sb *strings.Builder, fields []FieldChange, indent string) {
+	for _, f := range fields {
+		sb.WriteString(indent + "- `" + f.Field + "`: ")
+		switch {
+		case f.Old == "":
+			sb.WriteString("set to " + quote(f.New))
+		case f.New == "":
+			sb.WriteString("removed (was " + quote(f.Old) + ")")
+		default:
+			sb.WriteString(quote(f.Old) + " → " + quote(f.New))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func quote(s string) string {
+	return "\"" + s + "\""
+}
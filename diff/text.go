@@ -0,0 +1,390 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TextDiff compares two rendered versions of a document line by line, for
+// reviewers who would rather read the generated prose and tables than the
+// JSON. Changed lines are paired side by side and compared word by word.
+type TextDiff struct {
+	Title    string `json:"title,omitempty"`
+	OldLabel string `json:"oldLabel,omitempty"`
+	NewLabel string `json:"newLabel,omitempty"`
+	Rows     []Row  `json:"rows"`
+}
+
+// Row is one row of a side-by-side diff. Old or New is nil where a line was
+// added or removed. A row with Skipped set stands for that many unchanged
+// lines left out of the diff.
+type Row struct {
+	Old     *Line `json:"old,omitempty"`
+	New     *Line `json:"new,omitempty"`
+	Skipped int   `json:"skipped,omitempty"`
+}
+
+// Changed reports whether the row is an added, removed, or changed line.
+func (r Row) Changed() bool {
+	return r.Skipped == 0 && (r.Old == nil || r.New == nil || r.Old.Text() != r.New.Text())
+}
+
+// Line is a line of one version, split into segments that are unchanged or
+// differ from the other version.
+type Line struct {
+	Number   int       `json:"number"` // 1-based
+	Segments []Segment `json:"segments"`
+}
+
+// Text returns the whole line.
+func (l *Line) Text() string {
+	var sb strings.Builder
+	for _, s := range l.Segments {
+		sb.WriteString(s.Text)
+	}
+	return sb.String()
+}
+
+// Segment is a run of text that is unchanged, or changed between versions.
+type Segment struct {
+	Text    string `json:"text"`
+	Changed bool   `json:"changed,omitempty"`
+}
+
+// Text compares two texts line by line. Unchanged lines more than context
+// lines away from a change are collapsed into skipped rows; a negative
+// context keeps every line.
+func Text(old, new string, context int) *TextDiff {
+	a, b := splitLines(old), splitLines(new)
+	d := &TextDiff{}
+	var dels, inss []int
+	flush := func() {
+		for i := 0; i < max(len(dels), len(inss)); i++ {
+			var row Row
+			switch {
+			case i < len(dels) && i < len(inss):
+				row.Old, row.New = words(a[dels[i]], b[inss[i]])
+				row.Old.Number, row.New.Number = dels[i]+1, inss[i]+1
+			case i < len(dels):
+				row.Old = &Line{Number: dels[i] + 1, Segments: []Segment{{Text: a[dels[i]], Changed: true}}}
+			default:
+				row.New = &Line{Number: inss[i] + 1, Segments: []Segment{{Text: b[inss[i]], Changed: true}}}
+			}
+			d.Rows = append(d.Rows, row)
+		}
+		dels, inss = nil, nil
+	}
+
+	i, j := 0, 0
+	for _, o := range editScript(a, b) {
+		switch o {
+		case opDelete:
+			dels = append(dels, i)
+			i++
+		case opInsert:
+			inss = append(inss, j)
+			j++
+		default:
+			flush()
+			d.Rows = append(d.Rows, Row{
+				Old: &Line{Number: i + 1, Segments: []Segment{{Text: a[i]}}},
+				New: &Line{Number: j + 1, Segments: []Segment{{Text: b[j]}}},
+			})
+			i++
+			j++
+		}
+	}
+	flush()
+	if context >= 0 {
+		d.Rows = collapse(d.Rows, context)
+	}
+	return d
+}
+
+// Counts returns the number of changed, added, and removed lines.
+func (d *TextDiff) Counts() (changed, added, removed int) {
+	for _, r := range d.Rows {
+		switch {
+		case !r.Changed():
+		case r.Old == nil:
+			added++
+		case r.New == nil:
+			removed++
+		default:
+			changed++
+		}
+	}
+	return changed, added, removed
+}
+
+// Empty reports whether the texts are the same.
+func (d *TextDiff) Empty() bool {
+	for _, r := range d.Rows {
+		if r.Changed() {
+			return false
+		}
+	}
+	return true
+}
+
+// WordDiff renders the diff as plain text in the style of
+// "git diff --word-diff=plain": removed words in [-...-], added words in
+// {+...+}, and unchanged lines as they are.
+func (d *TextDiff) WordDiff() string {
+	var sb strings.Builder
+	for _, r := range d.Rows {
+		switch {
+		case r.Skipped > 0:
+			sb.WriteString("@@ " + plural(r.Skipped, "unchanged line") + " @@\n")
+		case r.Old == nil:
+			sb.WriteString("{+" + r.New.Text() + "+}\n")
+		case r.New == nil:
+			sb.WriteString("[-" + r.Old.Text() + "-]\n")
+		case !r.Changed():
+			sb.WriteString(r.Old.Text() + "\n")
+		default:
+			a, b := tokenize(r.Old.Text()), tokenize(r.New.Text())
+			i, j := 0, 0
+			var del, ins strings.Builder
+			flush := func() {
+				if del.Len() > 0 {
+					sb.WriteString("[-" + del.String() + "-]")
+					del.Reset()
+				}
+				if ins.Len() > 0 {
+					sb.WriteString("{+" + ins.String() + "+}")
+					ins.Reset()
+				}
+			}
+			for _, o := range editScript(a, b) {
+				switch o {
+				case opDelete:
+					del.WriteString(a[i])
+					i++
+				case opInsert:
+					ins.WriteString(b[j])
+					j++
+				default:
+					flush()
+					sb.WriteString(a[i])
+					i++
+					j++
+				}
+			}
+			flush()
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// collapse replaces runs of unchanged rows more than context rows from a
+// change with a single skipped row.
+func collapse(rows []Row, context int) []Row {
+	keep := make([]bool, len(rows))
+	for i, r := range rows {
+		if r.Changed() {
+			for k := max(0, i-context); k <= min(len(rows)-1, i+context); k++ {
+				keep[k] = true
+			}
+		}
+	}
+	var out []Row
+	for i := 0; i < len(rows); {
+		if keep[i] {
+			out = append(out, rows[i])
+			i++
+			continue
+		}
+		n := 0
+		for i < len(rows) && !keep[i] {
+			n++
+			i++
+		}
+		out = append(out, Row{Skipped: n})
+	}
+	return out
+}
+
+// words compares two lines word by word.
+func words(old, new string) (*Line, *Line) {
+	a, b := tokenize(old), tokenize(new)
+	ol, nl := &Line{}, &Line{}
+	add := func(l *Line, text string, changed bool) {
+		if n := len(l.Segments); n > 0 && l.Segments[n-1].Changed == changed {
+			l.Segments[n-1].Text += text
+			return
+		}
+		l.Segments = append(l.Segments, Segment{Text: text, Changed: changed})
+	}
+	i, j := 0, 0
+	for _, o := range editScript(a, b) {
+		switch o {
+		case opDelete:
+			add(ol, a[i], true)
+			i++
+		case opInsert:
+			add(nl, b[j], true)
+			j++
+		default:
+			add(ol, a[i], false)
+			add(nl, b[j], false)
+			i++
+			j++
+		}
+	}
+	return ol, nl
+}
+
+// tokenize splits a line into words, runs of spaces, and single
+// punctuation characters.
+func tokenize(s string) []string {
+	var tokens []string
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		}
+		return 0
+	}
+	start, prev := 0, -1
+	for i, r := range s {
+		c := class(r)
+		if i > start && (c != prev || c == 0) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
+
+type op int
+
+const (
+	opEqual op = iota
+	opDelete
+	opInsert
+)
+
+// editScript returns a shortest sequence of operations turning a into b,
+// using Myers' algorithm. Each equal or delete consumes an element of a,
+// and each equal or insert an element of b.
+func editScript[T comparable](a, b []T) []op {
+	// Common prefixes and suffixes need no search.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	ops := make([]op, pre, len(a)+len(b))
+	ops = append(ops, myers(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for range suf {
+		ops = append(ops, opEqual)
+	}
+	return ops
+}
+
+func myers[T comparable](a, b []T) []op {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		ops := make([]op, 0, n+m)
+		for range n {
+			ops = append(ops, opDelete)
+		}
+		for range m {
+			ops = append(ops, opInsert)
+		}
+		return ops
+	}
+
+	// v[off+k] is the furthest x reached on diagonal k. trace[d] holds
+	// diagonals -d..d of v after d edits, for the backtrack.
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+	var trace [][]int
+	var d int
+search:
+	for d = 0; ; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+				break search
+			}
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+
+	// Walk back from (n, m), recording operations in reverse.
+	rev := make([]op, 0, n+m)
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d-1] // diagonals -(d-1)..d-1
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		var pk int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := at(pk)
+		py := px - pk
+		for x > px && y > py {
+			rev = append(rev, opEqual)
+			x--
+			y--
+		}
+		if x == px {
+			rev = append(rev, opInsert)
+			y--
+		} else {
+			rev = append(rev, opDelete)
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		rev = append(rev, opEqual)
+		x--
+		y--
+	}
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return rev
+}
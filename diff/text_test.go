@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditScript(t *testing.T) {
+	// apply replays ops, checking they turn a into b.
+	apply := func(a, b []byte, ops []op) bool {
+		var out []byte
+		i, j := 0, 0
+		for _, o := range ops {
+			switch o {
+			case opEqual:
+				if i >= len(a) || j >= len(b) || a[i] != b[j] {
+					return false
+				}
+				out = append(out, a[i])
+				i++
+				j++
+			case opDelete:
+				i++
+			case opInsert:
+				out = append(out, b[j])
+				j++
+			}
+		}
+		return i == len(a) && reflect.DeepEqual(out, append([]byte(nil), b...))
+	}
+	edits := func(ops []op) int {
+		n := 0
+		for _, o := range ops {
+			if o != opEqual {
+				n++
+			}
+		}
+		return n
+	}
+
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"abcabba", "cbabac", 5},
+		{"kitten", "sitting", 5},
+	}
+	for _, tt := range tests {
+		ops := editScript([]byte(tt.a), []byte(tt.b))
+		if !apply([]byte(tt.a), []byte(tt.b), ops) || edits(ops) != tt.edits {
+			t.Errorf("editScript(%q, %q) = %v, want %d edits", tt.a, tt.b, ops, tt.edits)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	random := func() []byte {
+		s := make([]byte, r.Intn(30))
+		for i := range s {
+			s[i] = "abc"[r.Intn(3)]
+		}
+		return s
+	}
+	for range 500 {
+		a, b := random(), random()
+		if ops := editScript(a, b); !apply(a, b, ops) {
+			t.Fatalf("editScript(%q, %q) = %v does not turn one into the other", a, b, ops)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	old := "# Checkout\n\nGuests can pay by card.\n| FR-1 | One-page checkout | should |\nSame 1\nSame 2\nSame 3\nSame 4\nSame 5\nGone\n"
+	new := "# Checkout\n\nGuests can pay by card or wallet.\n| FR-1 | One-page checkout | must |\nSame 1\nSame 2\nSame 3\nSame 4\nSame 5\nNew line\nAnother\n"
+
+	d := Text(old, new, 1)
+	if changed, added, removed := d.Counts(); changed != 3 || added != 1 || removed != 0 {
+		t.Errorf("Counts() = %d, %d, %d; want 3, 1, 0", changed, added, removed)
+	}
+	var skipped []int
+	for _, r := range d.Rows {
+		if r.Skipped > 0 {
+			skipped = append(skipped, r.Skipped)
+		}
+	}
+	if !reflect.DeepEqual(skipped, []int{1, 3}) {
+		t.Errorf("skipped rows = %v, want [1 3]", skipped)
+	}
+
+	row := d.Rows[2] // after the skipped heading and the blank line
+	if row.Old == nil || row.Old.Number != 3 || row.New.Number != 3 {
+		t.Fatalf("row = %+v", row)
+	}
+	if got := row.New.Segments; !reflect.DeepEqual(got, []Segment{{Text: "Guests can pay by card"}, {Text: " or wallet", Changed: true}, {Text: "."}}) {
+		t.Errorf("new segments = %+v", got)
+	}
+	if got := row.Old.Segments; !reflect.DeepEqual(got, []Segment{{Text: "Guests can pay by card."}}) {
+		t.Errorf("old segments = %+v", got)
+	}
+
+	want := `@@ 1 unchanged line @@
+
+Guests can pay by card{+ or wallet+}.
+| FR-1 | One-page checkout | [-should-]{+must+} |
+Same 1
+@@ 3 unchanged lines @@
+Same 5
+[-Gone-]{+New line+}
+{+Another+}
+`
+	if got := d.WordDiff(); got != want {
+		t.Errorf("WordDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	if all := Text(old, new, -1); len(all.Rows) != 11 {
+		t.Errorf("Text with no context limit has %d rows, want 11", len(all.Rows))
+	}
+	if same := Text(old, old, 3); !same.Empty() || len(same.Rows) != 1 || same.Rows[0].Skipped != 10 {
+		t.Errorf("Text(old, old) = %+v", same.Rows)
+	}
+}
+
+func TestTextHTML(t *testing.T) {
+	d := Text("Pay by <card>.\n", "Pay by <card> or wallet.\n", 3)
+	d.Title, d.OldLabel, d.NewLabel = "Checkout", "v1.prd.json", "v2.prd.json"
+	page, err := d.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<title>Changes: Checkout</title>",
+		"1 line changed, 0 lines added, 0 lines removed",
+		"<th>v1.prd.json</th>",
+		"Pay by &lt;card&gt;<ins> or wallet</ins>.",
+		`<td class="old changed">Pay by &lt;card&gt;.</td>`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package directory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/validation"
+)
+
+// Rule IDs of the issues reported by Check.
+const (
+	RuleUnknownOwner  = "unknown-owner"
+	RuleDepartedOwner = "departed-owner"
+)
+
+func init() {
+	validation.DescribeRule(RuleUnknownOwner, "An owner or author is not a person or team in the directory.")
+	validation.DescribeRule(RuleDepartedOwner, "An owner or author has left the organization.")
+}
+
+// ownerFields hold a person or team as a string.
+var ownerFields = map[string]bool{"owner": true, "author": true}
+
+// peopleFields hold lists of people as objects with a name and email.
+var peopleFields = map[string]bool{"authors": true, "reviewers": true, "approvers": true}
+
+// Reference is a person or team named in a document.
+type Reference struct {
+	Pointer string // JSON pointer to the value
+	Field   string // "owner", "author", "authors", "reviewers", or "approvers"
+	Value   string
+}
+
+// References returns the owners, authors, reviewers, and approvers named in
+// a JSON document, in document order.
+func References(data []byte) ([]Reference, error) {
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	var refs []Reference
+	var visit func(key string, v any, pointer string)
+	visit = func(key string, v any, pointer string) {
+		switch x := v.(type) {
+		case string:
+			if ownerFields[key] && strings.TrimSpace(x) != "" {
+				refs = append(refs, Reference{Pointer: pointer, Field: key, Value: x})
+			}
+		case *query.Object:
+			for _, k := range x.Keys {
+				visit(k, x.Fields[k], pointer+"/"+escapePointer(k))
+			}
+		case []any:
+			for i, e := range x {
+				p := pointer + "/" + strconv.Itoa(i)
+				if obj, ok := e.(*query.Object); ok && peopleFields[key] {
+					if ref, ok := personReference(obj, p, key); ok {
+						refs = append(refs, ref)
+					}
+				}
+				visit("", e, p)
+			}
+		}
+	}
+	visit("", root, "")
+	return refs, nil
+}
+
+// personReference returns the reference of a person object, preferring its
+// email to its name.
+func personReference(obj *query.Object, pointer, field string) (Reference, bool) {
+	for _, k := range []string{"email", "name"} {
+		if s, ok := obj.Fields[k].(string); ok && strings.TrimSpace(s) != "" {
+			return Reference{Pointer: pointer + "/" + k, Field: field, Value: s}, true
+		}
+	}
+	return Reference{}, false
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// Check looks up the people and teams named in a JSON document. People who
+// have left are reported as errors; names that are neither a person nor a
+// team in the directory are reported as warnings.
+func (d *Directory) Check(data []byte) ([]validation.Issue, error) {
+	refs, err := References(data)
+	if err != nil {
+		return nil, err
+	}
+	issues := []validation.Issue{}
+	for _, r := range refs {
+		if p, ok := d.Person(r.Value); ok {
+			if !p.Active() {
+				issues = append(issues, validation.Issue{
+					RuleID:   RuleDepartedOwner,
+					Severity: validation.SeverityError,
+					Pointer:  r.Pointer,
+					Message:  fmt.Sprintf("%s %q %s", r.Field, r.Value, d.departure(p)),
+				})
+			}
+			continue
+		}
+		if _, ok := d.Team(r.Value); ok {
+			continue
+		}
+		issues = append(issues, validation.Issue{
+			RuleID:   RuleUnknownOwner,
+			Severity: validation.SeverityWarning,
+			Pointer:  r.Pointer,
+			Message:  fmt.Sprintf("%s %q is not in the directory", r.Field, r.Value),
+		})
+	}
+	return issues, nil
+}
@@ -0,0 +1,193 @@
+// Package directory looks up the people and teams named in planning
+// documents in a people directory, so that owners and authors can be
+// checked against the organization, enriched with their email and role
+// when documents are rendered, and reported once they have left.
+//
+// A directory is read by a provider: a local YAML or JSON file, an
+// external command that prints the directory (for example a script that
+// queries LDAP or Google Workspace), or a provider registered with
+// Register.
+package directory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+// Person statuses. An empty status is active.
+const (
+	StatusActive   = "active"
+	StatusDeparted = "departed"
+)
+
+// Person is an entry in the directory.
+type Person struct {
+	// ID is the username or employee ID, e.g. "alopez".
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Team  string `json:"team,omitempty"`
+
+	// Aliases are other names the person is referred to by, such as a
+	// nickname or a chat handle.
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Status is "active" (the default) or "departed". A person with
+	// LeftAt set has departed whatever the status.
+	Status string `json:"status,omitempty"`
+	LeftAt string `json:"leftAt,omitempty"`
+
+	// Successor is the ID, email, or name of whoever took over the
+	// person's responsibilities.
+	Successor string `json:"successor,omitempty"`
+}
+
+// Active reports whether the person is still in the organization.
+func (p Person) Active() bool {
+	return p.LeftAt == "" && (p.Status == "" || strings.EqualFold(p.Status, StatusActive))
+}
+
+// Team is a team that can own documents and items.
+type Team struct {
+	Name    string   `json:"name"`
+	Email   string   `json:"email,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Directory is a set of people and teams.
+type Directory struct {
+	People []Person `json:"people"`
+	Teams  []Team   `json:"teams,omitempty"`
+
+	once   sync.Once
+	people map[string]int
+	teams  map[string]int
+}
+
+// index builds the lookup tables on first use.
+func (d *Directory) index() {
+	d.once.Do(d.buildIndex)
+}
+
+func (d *Directory) buildIndex() {
+	d.people = map[string]int{}
+	d.teams = map[string]int{}
+	for i, p := range d.People {
+		for _, key := range append([]string{p.ID, p.Name, p.Email}, p.Aliases...) {
+			if k := normalize(key); k != "" {
+				if _, dup := d.people[k]; !dup {
+					d.people[k] = i
+				}
+			}
+		}
+	}
+	for i, t := range d.Teams {
+		for _, key := range append([]string{t.Name, t.Email}, t.Aliases...) {
+			if k := normalize(key); k != "" {
+				if _, dup := d.teams[k]; !dup {
+					d.teams[k] = i
+				}
+			}
+		}
+	}
+}
+
+var angleEmail = regexp.MustCompile(`<([^<>\s]+@[^<>\s]+)>`)
+
+// normalize returns the lookup key for a name, email, or ID: lowercased,
+// with a leading @ and surrounding space removed and inner space collapsed.
+func normalize(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "@")
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// keys returns the lookup keys to try for a value from a document. A value
+// written as "Name <email>" is looked up by the email, then the name.
+func keys(s string) []string {
+	if m := angleEmail.FindStringSubmatchIndex(s); m != nil {
+		return []string{normalize(s[m[2]:m[3]]), normalize(s[:m[0]] + s[m[1]:])}
+	}
+	return []string{normalize(s)}
+}
+
+// Person returns the person a name, email, ID, or alias refers to.
+func (d *Directory) Person(s string) (*Person, bool) {
+	d.index()
+	for _, k := range keys(s) {
+		if i, ok := d.people[k]; ok {
+			return &d.People[i], true
+		}
+	}
+	return nil, false
+}
+
+// Team returns the team a name, email, or alias refers to.
+func (d *Directory) Team(s string) (*Team, bool) {
+	d.index()
+	for _, k := range keys(s) {
+		if i, ok := d.teams[k]; ok {
+			return &d.Teams[i], true
+		}
+	}
+	return nil, false
+}
+
+// Enrich fills the email and role of p, and its name if only the email is
+// given, from the directory. Values already set are kept.
+func (d *Directory) Enrich(p *common.Person) {
+	if d == nil {
+		return
+	}
+	key := p.Email
+	if key == "" {
+		key = p.Name
+	}
+	e, ok := d.Person(key)
+	if !ok {
+		return
+	}
+	if p.Name == "" {
+		p.Name = e.Name
+	}
+	if p.Email == "" {
+		p.Email = e.Email
+	}
+	if p.Role == "" {
+		p.Role = e.Role
+	}
+}
+
+// EnrichAll enriches each person in people.
+func (d *Directory) EnrichAll(people []common.Person) {
+	for i := range people {
+		d.Enrich(&people[i])
+	}
+}
+
+// EnrichApprovers enriches each approver in approvers.
+func (d *Directory) EnrichApprovers(approvers []common.Approver) {
+	for i := range approvers {
+		d.Enrich(&approvers[i].Person)
+	}
+}
+
+// departure describes when a person left and who took over, for messages.
+func (d *Directory) departure(p *Person) string {
+	msg := "left the directory"
+	if p.LeftAt != "" {
+		msg += " on " + p.LeftAt
+	}
+	if p.Successor != "" {
+		successor := p.Successor
+		if s, ok := d.Person(p.Successor); ok {
+			successor = s.Name
+		}
+		msg += fmt.Sprintf("; reassign to %s", successor)
+	}
+	return msg
+}
@@ -0,0 +1,153 @@
+package directory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+const testDirectory = `
+people:
+  - id: alopez
+    name: Ana Lopez
+    email: ana.lopez@example.com
+    role: Product Manager
+    aliases: ["@ana"]
+  - id: bkim
+    name: Ben Kim
+    email: ben.kim@example.com
+    role: Engineering Manager
+    leftAt: "2026-06-30"
+    successor: alopez
+  - name: Chris Ng
+    email: chris@example.com
+    status: departed
+teams:
+  - name: Payments
+    email: payments@example.com
+`
+
+func testDir(t *testing.T) *Directory {
+	t.Helper()
+	d, err := Parse([]byte(testDirectory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestLookup(t *testing.T) {
+	d := testDir(t)
+	for _, s := range []string{"alopez", "Ana Lopez", "ana  lopez", "ANA.LOPEZ@example.com", "@ana", "Ana L. <ana.lopez@example.com>"} {
+		if p, ok := d.Person(s); !ok || p.ID != "alopez" {
+			t.Errorf("Person(%q) = %v, %v", s, p, ok)
+		}
+	}
+	if _, ok := d.Person("Dana"); ok {
+		t.Error("found a person not in the directory")
+	}
+	if team, ok := d.Team("payments"); !ok || team.Email != "payments@example.com" {
+		t.Errorf("Team = %v, %v", team, ok)
+	}
+
+	for name, active := range map[string]bool{"alopez": true, "bkim": false, "Chris Ng": false} {
+		if p, _ := d.Person(name); p.Active() != active {
+			t.Errorf("%s active = %v", name, p.Active())
+		}
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	d := testDir(t)
+	people := []common.Person{
+		{Name: "Ana Lopez"},
+		{Email: "ben.kim@example.com", Role: "Reviewer"},
+		{Name: "Dana"},
+	}
+	d.EnrichAll(people)
+	if p := people[0]; p.Email != "ana.lopez@example.com" || p.Role != "Product Manager" {
+		t.Errorf("enriched by name = %+v", p)
+	}
+	if p := people[1]; p.Name != "Ben Kim" || p.Role != "Reviewer" {
+		t.Errorf("enriched by email = %+v", p)
+	}
+	if p := people[2]; p.Email != "" {
+		t.Errorf("unknown person enriched: %+v", p)
+	}
+
+	var none *Directory
+	none.Enrich(&people[2])
+}
+
+func TestCheck(t *testing.T) {
+	doc := `{
+		"metadata": {
+			"authors": [{"name": "Ana Lopez"}],
+			"reviewers": [{"name": "Ben Kim", "email": "ben.kim@example.com"}],
+			"approvers": [{"name": "Dana", "approved": false}]
+		},
+		"objectives": [
+			{"id": "O1", "owner": "Payments", "keyResults": [{"id": "KR1", "owner": "Chris Ng"}]}
+		]
+	}`
+	issues, err := testDir(t).Check([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, is := range issues {
+		got = append(got, string(is.Severity)+" "+is.RuleID+" "+is.Pointer)
+	}
+	want := []string{
+		"error departed-owner /metadata/reviewers/0/email",
+		"warning unknown-owner /metadata/approvers/0/name",
+		"error departed-owner /objectives/0/keyResults/0/owner",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if msg := issues[0].Message; !strings.Contains(msg, "on 2026-06-30; reassign to Ana Lopez") {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "people.yaml"), []byte(testDirectory), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Open(Config{File: "people.yaml"}, dir)
+	if err != nil || len(d.People) != 3 {
+		t.Fatalf("Open file = %v, %v", d, err)
+	}
+
+	d, err = Open(Config{Command: []string{"cat", "people.yaml"}}, dir)
+	if err != nil || len(d.Teams) != 1 {
+		t.Fatalf("Open command = %v, %v", d, err)
+	}
+	if _, err := Open(Config{Command: []string{"false"}}, dir); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+
+	Register("test", func(cfg Config, _ string) (*Directory, error) {
+		return &Directory{People: []Person{{Name: cfg.Options["name"]}}}, nil
+	})
+	d, err = Open(Config{Provider: "test", Options: map[string]string{"name": "Eve"}}, dir)
+	if err != nil || d.People[0].Name != "Eve" {
+		t.Fatalf("Open provider = %v, %v", d, err)
+	}
+	if _, err := Open(Config{Provider: "ldap"}, dir); err == nil {
+		t.Error("expected an error for an unregistered provider")
+	}
+	if _, err := Open(Config{File: "people.yaml", Provider: "test"}, dir); err == nil {
+		t.Error("expected an error when two providers are set")
+	}
+
+	if _, err := Parse([]byte(`people: [{role: Engineer}]`)); err == nil {
+		t.Error("expected an error for a person without a name")
+	}
+}
@@ -0,0 +1,155 @@
+package directory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// Config selects the provider of a directory. Exactly one of File,
+// Command, and Provider is set.
+type Config struct {
+	// File is a YAML or JSON directory file.
+	File string `json:"file,omitempty"`
+
+	// Command is a program and its arguments that print the directory as
+	// YAML or JSON on stdout, e.g. a script that queries LDAP.
+	Command []string `json:"command,omitempty"`
+
+	// Provider is the name of a provider registered with Register, and
+	// Options its settings.
+	Provider string            `json:"provider,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+}
+
+// CommandTimeout is how long a directory command may run.
+const CommandTimeout = 30 * time.Second
+
+// Provider loads a directory. dir is the directory relative paths in the
+// configuration are resolved against.
+type Provider func(cfg Config, dir string) (*Directory, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{}
+)
+
+// Register makes a provider available by name, e.g. for an LDAP or Google
+// Workspace integration built into a custom binary. Registering a name
+// twice replaces the earlier provider.
+func Register(name string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// Providers returns the names of the registered providers, sorted.
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open loads the directory cfg selects.
+func Open(cfg Config, dir string) (*Directory, error) {
+	set := 0
+	for _, ok := range []bool{cfg.File != "", len(cfg.Command) > 0, cfg.Provider != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("directory config must set exactly one of file, command, and provider")
+	}
+
+	switch {
+	case cfg.File != "":
+		path := cfg.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return Load(path)
+	case len(cfg.Command) > 0:
+		return runCommand(cfg.Command, dir)
+	}
+
+	providersMu.RLock()
+	p, ok := providers[cfg.Provider]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown directory provider %q (registered: %s)", cfg.Provider, strings.Join(Providers(), ", "))
+	}
+	d, err := p(cfg, dir)
+	if err != nil {
+		return nil, fmt.Errorf("directory provider %s: %w", cfg.Provider, err)
+	}
+	return d, nil
+}
+
+// Load reads a YAML or JSON directory file.
+func Load(path string) (*Directory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory: %w", err)
+	}
+	d, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing directory %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// Parse decodes a YAML or JSON directory.
+func Parse(data []byte) (*Directory, error) {
+	data, err := yamlconv.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var d Directory
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	for i, p := range d.People {
+		if p.Name == "" && p.ID == "" && p.Email == "" {
+			return nil, fmt.Errorf("people[%d] has no name, id, or email", i)
+		}
+	}
+	return &d, nil
+}
+
+// runCommand runs a directory command in dir and parses its output.
+func runCommand(command []string, dir string) (*Directory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec // configured by the workspace owner
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("directory command %s: %w: %s", command[0], err, msg)
+		}
+		return nil, fmt.Errorf("directory command %s: %w", command[0], err)
+	}
+	d, err := Parse(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("parsing output of directory command %s: %w", command[0], err)
+	}
+	return d, nil
+}
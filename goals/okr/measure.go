@@ -0,0 +1,128 @@
+package okr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Units key result values are normalized to.
+const (
+	UnitPercent      = "%"
+	UnitCurrency     = "$"
+	UnitCount        = "count"
+	UnitMilliseconds = "ms"
+)
+
+// Directions a key result metric moves in to reach its target.
+const (
+	DirectionIncrease = "increase" // Higher is better
+	DirectionDecrease = "decrease" // Lower is better, e.g. latency or churn
+)
+
+// Value is a key result value parsed into a number in a normalized unit.
+type Value struct {
+	Number float64
+	Unit   string // One of the Unit constants, or "" for a plain number
+}
+
+// String formats the value with its unit.
+func (v Value) String() string {
+	n := strconv.FormatFloat(v.Number, 'f', -1, 64)
+	switch v.Unit {
+	case UnitPercent:
+		return n + "%"
+	case UnitCurrency:
+		return "$" + n
+	case UnitMilliseconds:
+		return n + "ms"
+	}
+	return n
+}
+
+// unitScales maps unit words to a normalized unit and the factor that
+// converts a number in that unit to it.
+var unitScales = map[string]struct {
+	unit  string
+	scale float64
+}{
+	"%": {UnitPercent, 1}, "percent": {UnitPercent, 1}, "pct": {UnitPercent, 1},
+	"$": {UnitCurrency, 1}, "usd": {UnitCurrency, 1}, "dollar": {UnitCurrency, 1}, "dollars": {UnitCurrency, 1},
+	"us": {UnitMilliseconds, 0.001}, "µs": {UnitMilliseconds, 0.001},
+	"ms": {UnitMilliseconds, 1}, "millisecond": {UnitMilliseconds, 1}, "milliseconds": {UnitMilliseconds, 1},
+	"s": {UnitMilliseconds, 1e3}, "sec": {UnitMilliseconds, 1e3}, "secs": {UnitMilliseconds, 1e3},
+	"second": {UnitMilliseconds, 1e3}, "seconds": {UnitMilliseconds, 1e3},
+	"min": {UnitMilliseconds, 6e4}, "mins": {UnitMilliseconds, 6e4}, "minute": {UnitMilliseconds, 6e4}, "minutes": {UnitMilliseconds, 6e4},
+	"h": {UnitMilliseconds, 36e5}, "hr": {UnitMilliseconds, 36e5}, "hrs": {UnitMilliseconds, 36e5},
+	"hour": {UnitMilliseconds, 36e5}, "hours": {UnitMilliseconds, 36e5},
+	"d": {UnitMilliseconds, 864e5}, "day": {UnitMilliseconds, 864e5}, "days": {UnitMilliseconds, 864e5},
+}
+
+// magnitudes are the suffixes for thousands, millions, and billions.
+var magnitudes = map[string]float64{
+	"k": 1e3, "m": 1e6, "mm": 1e6, "b": 1e9, "bn": 1e9,
+}
+
+// ParseValue parses a baseline, target, or current value such as "42%",
+// "$1.2M", "250ms", "1.5 s", or "10k users". unit is the key result's Unit,
+// used when the value has none of its own.
+//
+// Durations are normalized to milliseconds. "k", "M", and "B" scale by a
+// thousand, million, and billion, so a bare "m" means million; write "min"
+// for minutes. Any other unit word, such as "users" or "tickets", counts
+// things. Leading comparison signs ("<200ms", ">= 99.9%") are ignored.
+func ParseValue(s, unit string) (Value, error) {
+	text := strings.TrimSpace(s)
+	text = strings.TrimLeft(text, "<>=≤≥~+ ")
+	if text == "" {
+		return Value{}, fmt.Errorf("empty value")
+	}
+
+	var v Value
+	if rest, ok := strings.CutPrefix(text, "$"); ok {
+		v.Unit = UnitCurrency
+		text = strings.TrimSpace(rest)
+	}
+
+	end := 0
+	for end < len(text) && (text[end] >= '0' && text[end] <= '9' || text[end] == '.' || text[end] == ',' ||
+		end == 0 && text[end] == '-') {
+		end++
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(text[:end], ",", ""), 64)
+	if err != nil {
+		return Value{}, fmt.Errorf("no number in %q", s)
+	}
+	v.Number = n
+
+	suffix := strings.TrimSpace(text[end:])
+	word, _, _ := strings.Cut(suffix, " ")
+	if scale, ok := magnitudes[strings.ToLower(word)]; ok {
+		v.Number *= scale
+		suffix = strings.TrimSpace(suffix[len(word):])
+	}
+	if suffix == "" {
+		suffix = strings.TrimSpace(unit)
+	}
+	if suffix == "" {
+		return v, nil
+	}
+
+	word, _, _ = strings.Cut(suffix, " ")
+	if u, ok := unitScales[strings.ToLower(word)]; ok {
+		if v.Unit != "" && v.Unit != u.unit {
+			return Value{}, fmt.Errorf("%q mixes units %s and %s", s, v.Unit, u.unit)
+		}
+		v.Number *= u.scale
+		v.Unit = u.unit
+	} else if v.Unit == "" {
+		v.Unit = UnitCount
+	}
+	return v, nil
+}
+
+// compatible reports whether two parsed values can be compared. A plain
+// number takes the unit of the value it is compared with.
+func compatible(a, b Value) bool {
+	return a.Unit == "" || b.Unit == "" || a.Unit == b.Unit
+}
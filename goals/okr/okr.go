@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/yamlconv"
 )
 
 // Status constants for OKR lifecycle.
@@ -98,9 +101,11 @@ type KeyResult struct {
 	Owner             string        `json:"owner,omitempty"`             // Person or team responsible
 	Metric            string        `json:"metric,omitempty"`            // What is being measured
 	Baseline          string        `json:"baseline,omitempty"`          // Starting value
-	Target            string        `json:"target,omitempty"`            // Target value to achieve
+	Target            string        `json:"target,omitempty"`            // Target value to achieve; the commit target when a stretch target is set
+	StretchTarget     string        `json:"stretchTarget,omitempty"`     // Ambitious target beyond the commit target
 	Current           string        `json:"current,omitempty"`           // Current value
 	Unit              string        `json:"unit,omitempty"`              // Unit of measurement
+	Direction         string        `json:"direction,omitempty"`         // increase or decrease; inferred from baseline and target if empty
 	MeasurementMethod string        `json:"measurementMethod,omitempty"` // How it's measured (from PRD)
 	Score             float64       `json:"score,omitempty"`             // 0.0-1.0 achievement score
 	Confidence        string        `json:"confidence,omitempty"`        // Low, Medium, High
@@ -113,11 +118,12 @@ type KeyResult struct {
 // PhaseTarget represents a Key Result target for a specific roadmap phase.
 // This enables alignment between OKRs and roadmap phases.
 type PhaseTarget struct {
-	PhaseID string `json:"phaseId"`          // Reference to roadmap phase
-	Target  string `json:"target"`           // Target value for this phase
-	Status  string `json:"status,omitempty"` // not_started, in_progress, achieved, missed
-	Actual  string `json:"actual,omitempty"` // Actual value achieved
-	Notes   string `json:"notes,omitempty"`  // Commentary on progress
+	PhaseID string `json:"phaseId"`           // Reference to roadmap phase
+	Target  string `json:"target"`            // Target value for this phase
+	DueDate string `json:"dueDate,omitempty"` // ISO 8601 date; must fall within the phase window
+	Status  string `json:"status,omitempty"`  // not_started, in_progress, achieved, missed
+	Actual  string `json:"actual,omitempty"`  // Actual value achieved
+	Notes   string `json:"notes,omitempty"`   // Commentary on progress
 }
 
 // Risk represents a challenge or risk to achieving objectives.
@@ -158,6 +164,18 @@ func Parse(data []byte) (*OKRDocument, error) {
 	return &doc, nil
 }
 
+// ParseLenient parses OKR JSON data, recovering from field-level type errors
+// such as malformed dates or quoted numbers. The returned report lists each
+// field that was coerced or dropped.
+func ParseLenient(data []byte) (*OKRDocument, *lenient.Report, error) {
+	var doc OKRDocument
+	report, err := lenient.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &doc, report, nil
+}
+
 // JSON returns the OKR document as formatted JSON.
 func (doc *OKRDocument) JSON() ([]byte, error) {
 	return json.MarshalIndent(doc, "", "  ")
@@ -175,15 +193,52 @@ func (doc *OKRDocument) WriteFile(filepath string) error {
 	return nil
 }
 
+// ReadFileYAML reads an OKR document from a YAML file. Field names are the
+// same as in JSON.
+func ReadFileYAML(filepath string) (*OKRDocument, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return ParseYAML(data)
+}
+
+// ParseYAML parses OKR YAML data.
+func ParseYAML(data []byte) (*OKRDocument, error) {
+	var doc OKRDocument
+	if err := yamlconv.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// YAML returns the OKR document as YAML.
+func (doc *OKRDocument) YAML() ([]byte, error) {
+	return yamlconv.Marshal(doc)
+}
+
+// WriteFileYAML writes the OKR document to a YAML file.
+func (doc *OKRDocument) WriteFileYAML(filepath string) error {
+	data, err := doc.YAML()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath, data, 0600); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
+
 // CalculateProgress calculates the overall progress of an Objective
-// based on its Key Results. Uses average scoring by default.
+// based on its Key Results. Uses average scoring by default. Key results
+// without a score count with the progress computed from their values.
 func (o *Objective) CalculateProgress() float64 {
 	if len(o.KeyResults) == 0 {
 		return 0
 	}
 	var total float64
 	for _, kr := range o.KeyResults {
-		total += kr.Score
+		total += kr.EffectiveScore()
 	}
 	return total / float64(len(o.KeyResults))
 }
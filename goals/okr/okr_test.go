@@ -350,3 +350,41 @@ func TestJSONRoundTrip(t *testing.T) {
 			original.Objectives[0].KeyResults[0].Score)
 	}
 }
+
+func TestParseLenient(t *testing.T) {
+	data := []byte(`{
+		"metadata": {"id": "OKR-1", "name": "Q1", "createdAt": "2025-01-15"},
+		"objectives": [{"id": "O1", "title": "Grow", "keyResults": [{"id": "KR1", "title": "Users", "score": "0.7"}]}]
+	}`)
+
+	if _, err := Parse(data); err == nil {
+		t.Fatal("expected strict Parse to fail")
+	}
+
+	doc, report, err := ParseLenient(data)
+	if err != nil {
+		t.Fatalf("ParseLenient() error = %v", err)
+	}
+	if len(doc.Objectives) != 1 || doc.Objectives[0].KeyResults[0].Score != 0.7 {
+		t.Errorf("unexpected document: %+v", doc.Objectives)
+	}
+	if !report.HasIssues() {
+		t.Error("expected recovery report to list coerced fields")
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(`{"metadata": {"id": "OKR-1"}, "objectives": [{"id": "O1", "keyResults": [{"id": "KR1", "score": 0.5}]}]}`))
+	f.Add([]byte(`{"objectives": [{"keyResults": [{"phaseTargets": [{"phaseId": "P1"}]}]}], "risks": [{}]}`))
+	f.Add([]byte(`{"metadata": null, "objectives": null}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if doc, err := Parse(data); err == nil {
+			_ = doc.Validate(StrictValidationOptions())
+			_ = doc.CalculateOverallProgress()
+		}
+		if doc, _, err := ParseLenient(data); err == nil {
+			_ = doc.Validate(DefaultValidationOptions())
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package okr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Attainment levels a key result is graded at against its commit target
+// and, if it has one, its stretch target.
+const (
+	AttainmentMissed  = "missed"  // Short of the commit target
+	AttainmentCommit  = "commit"  // Commit target reached
+	AttainmentStretch = "stretch" // Stretch target reached
+)
+
+// Progress is a key result's progress toward its target, computed from its
+// baseline, target, and current values.
+type Progress struct {
+	Baseline    Value
+	HasBaseline bool
+	Target      Value
+	Current     Value
+	Stretch     Value
+	HasStretch  bool
+	Direction   string // DirectionIncrease or DirectionDecrease
+
+	// Percent is the share of the distance from baseline to the commit
+	// target that has been covered, from 0.0 to 1.0.
+	Percent float64
+
+	// Attainment grades the current value against the commit and stretch
+	// targets: AttainmentMissed, AttainmentCommit, or AttainmentStretch.
+	Attainment string
+
+	// Regressed is set when the current value is worse than the baseline,
+	// e.g. latency that has grown while the target is to reduce it.
+	Regressed bool
+}
+
+// MetricDirection returns the direction the key result's metric should
+// move. An explicit Direction wins; otherwise a target below the baseline,
+// or a stretch target below the commit target, means lower is better.
+// Otherwise higher is assumed to be better.
+func (kr KeyResult) MetricDirection() string {
+	switch strings.ToLower(strings.TrimSpace(kr.Direction)) {
+	case DirectionIncrease:
+		return DirectionIncrease
+	case DirectionDecrease:
+		return DirectionDecrease
+	}
+	target, err := ParseValue(kr.Target, kr.Unit)
+	if err != nil {
+		return DirectionIncrease
+	}
+	if kr.Baseline != "" {
+		if baseline, err := ParseValue(kr.Baseline, kr.Unit); err == nil {
+			if target.Number < baseline.Number {
+				return DirectionDecrease
+			}
+			return DirectionIncrease
+		}
+	}
+	if kr.StretchTarget != "" {
+		if stretch, err := ParseValue(kr.StretchTarget, kr.Unit); err == nil && stretch.Number < target.Number {
+			return DirectionDecrease
+		}
+	}
+	return DirectionIncrease
+}
+
+// ComputeProgress parses the key result's values and computes its progress.
+// Target and current are required, and all values must be in compatible
+// units. A key result without a baseline is measured from zero; if its
+// metric decreases, progress is all or nothing.
+func (kr KeyResult) ComputeProgress() (*Progress, error) {
+	if strings.TrimSpace(kr.Target) == "" {
+		return nil, fmt.Errorf("target is not set")
+	}
+	if strings.TrimSpace(kr.Current) == "" {
+		return nil, fmt.Errorf("current is not set")
+	}
+	p := &Progress{Direction: kr.MetricDirection()}
+	var err error
+	if p.Target, err = ParseValue(kr.Target, kr.Unit); err != nil {
+		return nil, fmt.Errorf("parsing target: %w", err)
+	}
+	if p.Current, err = ParseValue(kr.Current, kr.Unit); err != nil {
+		return nil, fmt.Errorf("parsing current: %w", err)
+	}
+	if !compatible(p.Target, p.Current) {
+		return nil, fmt.Errorf("current is in %s but target is in %s", p.Current.Unit, p.Target.Unit)
+	}
+	if strings.TrimSpace(kr.Baseline) != "" {
+		if p.Baseline, err = ParseValue(kr.Baseline, kr.Unit); err != nil {
+			return nil, fmt.Errorf("parsing baseline: %w", err)
+		}
+		if !compatible(p.Baseline, p.Target) {
+			return nil, fmt.Errorf("baseline is in %s but target is in %s", p.Baseline.Unit, p.Target.Unit)
+		}
+		p.HasBaseline = true
+	}
+	if strings.TrimSpace(kr.StretchTarget) != "" {
+		if p.Stretch, err = ParseValue(kr.StretchTarget, kr.Unit); err != nil {
+			return nil, fmt.Errorf("parsing stretch target: %w", err)
+		}
+		if !compatible(p.Stretch, p.Target) {
+			return nil, fmt.Errorf("stretch target is in %s but target is in %s", p.Stretch.Unit, p.Target.Unit)
+		}
+		p.HasStretch = true
+	}
+
+	// sign turns a lower-is-better metric into a higher-is-better one.
+	sign := 1.0
+	if p.Direction == DirectionDecrease {
+		sign = -1
+	}
+	target, current := sign*p.Target.Number, sign*p.Current.Number
+
+	p.Attainment = AttainmentMissed
+	if current >= target {
+		p.Attainment = AttainmentCommit
+		if p.HasStretch && current >= sign*p.Stretch.Number {
+			p.Attainment = AttainmentStretch
+		}
+	}
+
+	switch {
+	case current >= target:
+		p.Percent = 1
+	case p.HasBaseline:
+		baseline := sign * p.Baseline.Number
+		if current < baseline {
+			p.Regressed = true
+		}
+		if target > baseline {
+			p.Percent = clamp((current - baseline) / (target - baseline))
+		}
+	case p.Direction == DirectionIncrease && target > 0:
+		p.Percent = clamp(current / target)
+	}
+	return p, nil
+}
+
+// TargetLabel returns the target for display, followed by the stretch
+// target if there is one, e.g. "200ms (stretch 150ms)".
+func (kr KeyResult) TargetLabel() string {
+	return FormatTarget(kr.Target, kr.StretchTarget)
+}
+
+// FormatTarget formats a commit target with an optional stretch target.
+func FormatTarget(target, stretch string) string {
+	switch {
+	case stretch == "":
+		return target
+	case target == "":
+		return "stretch " + stretch
+	}
+	return target + " (stretch " + stretch + ")"
+}
+
+// EffectiveScore returns the key result's score, or its computed progress
+// when no score has been set.
+func (kr KeyResult) EffectiveScore() float64 {
+	if kr.Score != 0 {
+		return kr.Score
+	}
+	if p, err := kr.ComputeProgress(); err == nil {
+		return p.Percent
+	}
+	return 0
+}
+
+// ProgressMarkdown renders the progress of each objective and key result
+// as markdown tables with text progress bars and the attainment of each
+// key result. Key results whose progress cannot be computed show their
+// score, if any.
+func (doc *OKRDocument) ProgressMarkdown() string {
+	var sb strings.Builder
+	title := "OKR Progress"
+	if doc.Metadata != nil && doc.Metadata.Name != "" {
+		title = doc.Metadata.Name + " Progress"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	overall := doc.CalculateOverallProgress()
+	fmt.Fprintf(&sb, "**Overall:** %s %.0f%%\n", ProgressBar(overall, 20), overall*100)
+
+	for i, obj := range doc.Objectives {
+		progress := obj.CalculateProgress()
+		fmt.Fprintf(&sb, "\n## O%d: %s\n\n", i+1, obj.Title)
+		fmt.Fprintf(&sb, "**Progress:** %s %.0f%%\n\n", ProgressBar(progress, 20), progress*100)
+		sb.WriteString("| KR | Key Result | Baseline | Target | Current | Progress | Attainment |\n")
+		sb.WriteString("|----|------------|----------|--------|---------|----------|------------|\n")
+		for j, kr := range obj.KeyResults {
+			cell, attainment := "-", "-"
+			if p, err := kr.ComputeProgress(); err == nil {
+				attainment = p.Attainment
+				cell = fmt.Sprintf("%s %.0f%%", ProgressBar(p.Percent, 10), p.Percent*100)
+				if p.Regressed {
+					cell += " ⚠ regressed"
+				}
+			} else if kr.Score != 0 {
+				cell = fmt.Sprintf("%s %.0f%% (scored)", ProgressBar(kr.Score, 10), kr.Score*100)
+			}
+			fmt.Fprintf(&sb, "| KR%d.%d | %s | %s | %s | %s | %s | %s |\n", i+1, j+1, kr.Title,
+				orDash(kr.Baseline), orDash(FormatTarget(withUnit(kr.Target, kr.Unit), withUnit(kr.StretchTarget, kr.Unit))), orDash(kr.Current), cell, attainment)
+		}
+	}
+	return sb.String()
+}
+
+func withUnit(value, unit string) string {
+	if value == "" || unit == "" {
+		return value
+	}
+	return value + " " + unit
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ProgressBar renders progress from 0.0 to 1.0 as a text bar of width
+// cells, e.g. "███████░░░".
+func ProgressBar(progress float64, width int) string {
+	filled := int(clamp(progress)*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func clamp(f float64) float64 {
+	return min(max(f, 0), 1)
+}
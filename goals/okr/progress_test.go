@@ -0,0 +1,190 @@
+package okr
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		in, unit string
+		want     Value
+	}{
+		{"42%", "", Value{42, UnitPercent}},
+		{"99.9 percent", "", Value{99.9, UnitPercent}},
+		{"$1.2M", "", Value{1.2e6, UnitCurrency}},
+		{"1,500", "USD", Value{1500, UnitCurrency}},
+		{"250ms", "", Value{250, UnitMilliseconds}},
+		{"1.5 s", "", Value{1500, UnitMilliseconds}},
+		{"2", "minutes", Value{120000, UnitMilliseconds}},
+		{"10k users", "", Value{10000, UnitCount}},
+		{"<200ms", "", Value{200, UnitMilliseconds}},
+		{"4.5", "", Value{4.5, ""}},
+		{"-3", "tickets", Value{-3, UnitCount}},
+	}
+	for _, tt := range tests {
+		got, err := ParseValue(tt.in, tt.unit)
+		if err != nil {
+			t.Errorf("ParseValue(%q, %q): %v", tt.in, tt.unit, err)
+			continue
+		}
+		if got.Unit != tt.want.Unit || math.Abs(got.Number-tt.want.Number) > 1e-9 {
+			t.Errorf("ParseValue(%q, %q) = %+v, want %+v", tt.in, tt.unit, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "happier", "$5ms"} {
+		if _, err := ParseValue(in, ""); err == nil {
+			t.Errorf("ParseValue(%q) should fail", in)
+		}
+	}
+}
+
+func TestComputeProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		kr        KeyResult
+		percent   float64
+		direction string
+		regressed bool
+	}{
+		{"increase", KeyResult{Baseline: "99.5%", Target: "99.9%", Current: "99.8%"}, 0.75, DirectionIncrease, false},
+		{"decrease inferred", KeyResult{Baseline: "400ms", Target: "200ms", Current: "300ms"}, 0.5, DirectionDecrease, false},
+		{"mixed duration units", KeyResult{Baseline: "2s", Target: "500ms", Current: "1.25s"}, 0.5, DirectionDecrease, false},
+		{"regressed", KeyResult{Baseline: "400ms", Target: "200ms", Current: "1.3s"}, 0, DirectionDecrease, true},
+		{"exceeded", KeyResult{Baseline: "$1M", Target: "$2M", Current: "$2.5M"}, 1, DirectionIncrease, false},
+		{"no baseline", KeyResult{Target: "200", Current: "50", Unit: "customers"}, 0.25, DirectionIncrease, false},
+		{"decrease without baseline", KeyResult{Target: "50", Current: "80", Direction: "decrease"}, 0, DirectionDecrease, false},
+		{"decrease met", KeyResult{Target: "50", Current: "40", Direction: "decrease"}, 1, DirectionDecrease, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.kr.ComputeProgress()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(p.Percent-tt.percent) > 1e-9 {
+				t.Errorf("Percent = %v, want %v", p.Percent, tt.percent)
+			}
+			if p.Direction != tt.direction {
+				t.Errorf("Direction = %s, want %s", p.Direction, tt.direction)
+			}
+			if p.Regressed != tt.regressed {
+				t.Errorf("Regressed = %v, want %v", p.Regressed, tt.regressed)
+			}
+		})
+	}
+
+	if _, err := (KeyResult{Target: "200ms", Current: "20%"}).ComputeProgress(); err == nil {
+		t.Error("expected an error for mismatched units")
+	}
+	if _, err := (KeyResult{Target: "200ms"}).ComputeProgress(); err == nil {
+		t.Error("expected an error without a current value")
+	}
+}
+
+func TestAttainment(t *testing.T) {
+	tests := []struct {
+		name string
+		kr   KeyResult
+		want string
+	}{
+		{"missed", KeyResult{Baseline: "100", Target: "200", StretchTarget: "300", Current: "150"}, AttainmentMissed},
+		{"commit", KeyResult{Baseline: "100", Target: "200", StretchTarget: "300", Current: "250"}, AttainmentCommit},
+		{"stretch", KeyResult{Baseline: "100", Target: "200", StretchTarget: "300", Current: "300"}, AttainmentStretch},
+		{"no stretch target", KeyResult{Target: "200", Current: "400"}, AttainmentCommit},
+		{"lower is better", KeyResult{Baseline: "400ms", Target: "200ms", StretchTarget: "150ms", Current: "160ms"}, AttainmentCommit},
+		{"direction from stretch", KeyResult{Target: "5%", StretchTarget: "2%", Current: "1.5%"}, AttainmentStretch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.kr.ComputeProgress()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Attainment != tt.want {
+				t.Errorf("Attainment = %s, want %s", p.Attainment, tt.want)
+			}
+		})
+	}
+
+	if _, err := (KeyResult{Target: "200ms", StretchTarget: "20%", Current: "100ms"}).ComputeProgress(); err == nil {
+		t.Error("expected an error for a stretch target in another unit")
+	}
+	if got := (KeyResult{Target: "200ms", StretchTarget: "150ms"}).TargetLabel(); got != "200ms (stretch 150ms)" {
+		t.Errorf("TargetLabel() = %q", got)
+	}
+}
+
+func TestEffectiveScore(t *testing.T) {
+	obj := Objective{KeyResults: []KeyResult{
+		{Title: "Scored", Score: 0.8, Baseline: "0", Target: "10", Current: "1"},
+		{Title: "Computed", Baseline: "0", Target: "10", Current: "4"},
+		{Title: "Unmeasured"},
+	}}
+	want := (0.8 + 0.4 + 0) / 3
+	if got := obj.CalculateProgress(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("CalculateProgress() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateKeyResultValues(t *testing.T) {
+	doc := &OKRDocument{Objectives: []Objective{{
+		Title: "Faster API",
+		KeyResults: []KeyResult{
+			{Title: "Cut latency", Baseline: "400ms", Target: "200ms", Current: "500ms"},
+			{Title: "Wrong way", Baseline: "10", Target: "20", Current: "15", Direction: "decrease"},
+			{Title: "Bad units", Target: "200ms", Current: "20%"},
+			{Title: "Sideways", Direction: "up"},
+			{Title: "Timid stretch", Baseline: "10", Target: "20", StretchTarget: "15"},
+		},
+	}}}
+	errs := doc.Validate(nil)
+
+	want := map[string]bool{
+		"objectives[0].keyResults[0].current":       false,
+		"objectives[0].keyResults[1].direction":     false,
+		"objectives[0].keyResults[2]":               false,
+		"objectives[0].keyResults[3].direction":     true,
+		"objectives[0].keyResults[4].stretchTarget": false,
+	}
+	for path, isError := range want {
+		found := false
+		for _, e := range errs {
+			if e.Path == path {
+				found = true
+				if e.IsError != isError {
+					t.Errorf("%s: IsError = %v, want %v (%s)", path, e.IsError, isError, e.Message)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected an issue at %s, got %v", path, errs)
+		}
+	}
+}
+
+func TestProgressMarkdown(t *testing.T) {
+	doc := &OKRDocument{
+		Metadata: &Metadata{Name: "Q3 OKRs"},
+		Objectives: []Objective{{
+			Title: "Faster API",
+			KeyResults: []KeyResult{
+				{Title: "Cut latency", Baseline: "400ms", Target: "200ms", Current: "300ms"},
+				{Title: "Grow usage", Baseline: "100", Target: "200", Current: "50"},
+			},
+		}},
+	}
+	md := doc.ProgressMarkdown()
+	for _, want := range []string{
+		"# Q3 OKRs Progress",
+		"## O1: Faster API",
+		"| KR1.1 | Cut latency | 400ms | 200ms | 300ms | █████░░░░░ 50% |",
+		"⚠ regressed",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
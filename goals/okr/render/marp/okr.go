@@ -288,7 +288,7 @@ var objectiveSlideTmpl = template.Must(template.New("objectiveSlide").Funcs(func
 | KR | Key Result | Target | Score | Status |
 |----|------------|--------|-------|--------|
 {{- range $i, $kr := .Objective.KeyResults}}
-| {{add $i 1}} | {{truncate $kr.Title 30}} | {{if $kr.Target}}{{$kr.Target}}{{else}}-{{end}} | {{scorePercent $kr.Score}} | {{if $kr.Status}}{{statusEmoji $kr.Status}}{{else}}-{{end}} |
+| {{add $i 1}} | {{truncate $kr.Title 30}} | {{if $kr.Target}}{{$kr.TargetLabel}}{{else}}-{{end}} | {{scorePercent $kr.EffectiveScore}} | {{if $kr.Status}}{{statusEmoji $kr.Status}}{{else}}-{{end}} |
 {{- end}}
 
 {{- if .Objective.Risks}}
@@ -310,7 +310,7 @@ var keyResultsSummaryTmpl = template.Must(template.New("keyResultsSummary").Func
 |-----------|------------|--------|-------|------------|
 {{- range $oi, $obj := .OKR.Objectives}}
 {{- range $ki, $kr := $obj.KeyResults}}
-| O{{add $oi 1}} | {{truncate $kr.Title 25}} | {{if $kr.Target}}{{$kr.Target}}{{else}}-{{end}} | {{scorePercent $kr.Score}} | {{if $kr.Confidence}}{{confidenceIcon $kr.Confidence}}{{else}}-{{end}} |
+| O{{add $oi 1}} | {{truncate $kr.Title 25}} | {{if $kr.Target}}{{$kr.TargetLabel}}{{else}}-{{end}} | {{scorePercent $kr.EffectiveScore}} | {{if $kr.Confidence}}{{confidenceIcon $kr.Confidence}}{{else}}-{{end}} |
 {{- end}}
 {{- end}}
 
@@ -2,19 +2,16 @@
 // to various output formats including Marp slides.
 package render
 
-import "github.com/grokify/structured-plan/goals/okr"
+import (
+	"github.com/grokify/structured-plan/goals/okr"
+	shared "github.com/grokify/structured-plan/render"
+)
 
-// Renderer defines the interface for output format renderers.
-type Renderer interface {
-	// Format returns the output format name (e.g., "marp").
-	Format() string
-	// FileExtension returns the file extension for this format (e.g., ".md").
-	FileExtension() string
-	// Render converts an OKR document to the output format.
-	Render(doc *okr.OKRDocument, opts *Options) ([]byte, error)
-}
+// Renderer defines the interface for OKR output format renderers.
+type Renderer = shared.TypedRenderer[okr.OKRDocument, Options]
 
-// Options contains rendering options common to all renderers.
+// Options contains the rendering options of OKR renderers. The registry
+// fills the fields that are also render.Options from the common options.
 type Options struct {
 	// Theme name (renderer-specific, e.g., "default", "corporate", "minimal")
 	Theme string
@@ -47,7 +44,7 @@ type Options struct {
 // DefaultOptions returns sensible default rendering options.
 func DefaultOptions() *Options {
 	return &Options{
-		Theme:            "default",
+		Theme:            shared.ThemeDefault,
 		IncludeRisks:     true,
 		IncludeStatus:    true,
 		ShowScoreGrades:  true,
@@ -61,7 +58,7 @@ func DefaultOptions() *Options {
 // ExecutiveOptions returns options for executive-focused slides (fewer details).
 func ExecutiveOptions() *Options {
 	return &Options{
-		Theme:            "corporate",
+		Theme:            shared.ThemeCorporate,
 		IncludeRisks:     true,
 		IncludeStatus:    true,
 		ShowScoreGrades:  true,
@@ -213,6 +213,79 @@ func validateKeyResult(kr KeyResult, path string, opts *ValidationOptions) []Val
 		})
 	}
 
+	errs = append(errs, validateKeyResultValues(kr, path)...)
+
+	return errs
+}
+
+// validateKeyResultValues checks that the baseline, target, and current
+// values can be compared, and flags metrics moving away from their target.
+func validateKeyResultValues(kr KeyResult, path string) []ValidationError {
+	var errs []ValidationError
+
+	direction := strings.ToLower(strings.TrimSpace(kr.Direction))
+	if direction != "" && direction != DirectionIncrease && direction != DirectionDecrease {
+		errs = append(errs, ValidationError{
+			Path:    path + ".direction",
+			Message: fmt.Sprintf("invalid direction: %s (expected: increase, decrease)", kr.Direction),
+			IsError: true,
+		})
+		return errs
+	}
+
+	if kr.StretchTarget != "" && strings.TrimSpace(kr.Target) == "" {
+		errs = append(errs, ValidationError{
+			Path:    path + ".target",
+			Message: "a commit target is required with a stretch target",
+			IsError: false, // Warning
+		})
+	}
+	if message := stretchBeyondCommit(kr); message != "" {
+		errs = append(errs, ValidationError{
+			Path:    path + ".stretchTarget",
+			Message: message,
+			IsError: false, // Warning
+		})
+	}
+
+	if strings.TrimSpace(kr.Target) == "" || strings.TrimSpace(kr.Current) == "" {
+		return errs
+	}
+	p, err := kr.ComputeProgress()
+	if err != nil {
+		if kr.Score != 0 {
+			return errs // Scored by hand
+		}
+		return append(errs, ValidationError{
+			Path:    path,
+			Message: "cannot compute progress: " + err.Error(),
+			IsError: false, // Warning - values may be qualitative
+		})
+	}
+
+	if direction != "" && p.HasBaseline {
+		if (direction == DirectionDecrease && p.Target.Number > p.Baseline.Number) ||
+			(direction == DirectionIncrease && p.Target.Number < p.Baseline.Number) {
+			errs = append(errs, ValidationError{
+				Path:    path + ".direction",
+				Message: fmt.Sprintf("direction is %s but target %s is on the other side of baseline %s", direction, kr.Target, kr.Baseline),
+				IsError: false, // Warning
+			})
+		}
+	}
+
+	if p.Regressed {
+		better := "higher"
+		if p.Direction == DirectionDecrease {
+			better = "lower"
+		}
+		errs = append(errs, ValidationError{
+			Path:    path + ".current",
+			Message: fmt.Sprintf("current %s is worse than baseline %s (%s is better)", kr.Current, kr.Baseline, better),
+			IsError: false, // Warning
+		})
+	}
+
 	return errs
 }
 
@@ -270,3 +343,23 @@ func Warnings(errs []ValidationError) []ValidationError {
 func IsValid(errs []ValidationError) bool {
 	return len(Errors(errs)) == 0
 }
+
+// stretchBeyondCommit describes why the stretch target does not lie beyond
+// the commit target in the metric's direction, or returns "" if it does or
+// the targets cannot be compared.
+func stretchBeyondCommit(kr KeyResult) string {
+	if strings.TrimSpace(kr.StretchTarget) == "" || strings.TrimSpace(kr.Target) == "" {
+		return ""
+	}
+	target, err1 := ParseValue(kr.Target, kr.Unit)
+	stretch, err2 := ParseValue(kr.StretchTarget, kr.Unit)
+	if err1 != nil || err2 != nil || !compatible(target, stretch) {
+		return ""
+	}
+	direction := kr.MetricDirection()
+	if (direction == DirectionIncrease && stretch.Number <= target.Number) ||
+		(direction == DirectionDecrease && stretch.Number >= target.Number) {
+		return fmt.Sprintf("stretch target %s is not beyond commit target %s (metric should %s)", kr.StretchTarget, kr.Target, direction)
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+package goals
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+)
+
+// Tree formats.
+const (
+	TreeFormatMermaid = "mermaid"
+	TreeFormatDOT     = "dot"
+)
+
+// Tree node kinds.
+const (
+	NodeRoot     = "root"
+	NodeGoal     = "goal"
+	NodeResult   = "result"
+	NodeObstacle = "obstacle"
+	NodeGroup    = "group"
+)
+
+// TreeNode is a node of a goals hierarchy, such as a V2MOM's vision with
+// its methods and measures, or an OKR document's objectives and key results.
+type TreeNode struct {
+	Kind     string
+	Label    string
+	Status   string
+	Children []*TreeNode
+}
+
+// TreeOptions configures how a goals document is turned into a tree.
+type TreeOptions struct {
+	// Obstacles adds obstacles, or risks, under their method or objective.
+	Obstacles bool
+
+	// Terminology is the V2MOM display terminology (v2mom, okr, hybrid)
+	// used for group labels. If empty, the document's own is used.
+	Terminology string
+}
+
+// V2MOMTree returns the Vision→Methods→Measures hierarchy of v. Measures
+// and obstacles kept at the V2MOM level are grouped under the vision.
+func V2MOMTree(v *v2mom.V2MOM, opts TreeOptions) *TreeNode {
+	mode := opts.Terminology
+	if mode == "" {
+		mode = v.GetTerminology()
+	}
+	terms := v2mom.GetTerminologyLabels(mode)
+
+	label := v.Vision
+	if label == "" && v.Metadata != nil {
+		label = v.Metadata.Name
+	}
+	if label == "" {
+		label = "Vision"
+	}
+	root := &TreeNode{Kind: NodeRoot, Label: label}
+	for _, m := range v.Methods {
+		method := &TreeNode{Kind: NodeGoal, Label: m.Name, Status: m.Status}
+		method.Children = append(method.Children, measureNodes(m.Measures)...)
+		if opts.Obstacles {
+			method.Children = append(method.Children, obstacleNodes(m.Obstacles)...)
+		}
+		root.Children = append(root.Children, method)
+	}
+	if len(v.Measures) > 0 {
+		root.Children = append(root.Children, &TreeNode{Kind: NodeGroup, Label: terms.Measures, Children: measureNodes(v.Measures)})
+	}
+	if opts.Obstacles && len(v.Obstacles) > 0 {
+		root.Children = append(root.Children, &TreeNode{Kind: NodeGroup, Label: terms.Obstacles, Children: obstacleNodes(v.Obstacles)})
+	}
+	return root
+}
+
+func measureNodes(measures []v2mom.Measure) []*TreeNode {
+	nodes := make([]*TreeNode, 0, len(measures))
+	for _, m := range measures {
+		nodes = append(nodes, &TreeNode{Kind: NodeResult, Label: m.Name, Status: m.Status})
+	}
+	return nodes
+}
+
+func obstacleNodes(obstacles []v2mom.Obstacle) []*TreeNode {
+	nodes := make([]*TreeNode, 0, len(obstacles))
+	for _, o := range obstacles {
+		nodes = append(nodes, &TreeNode{Kind: NodeObstacle, Label: o.Name, Status: o.Status})
+	}
+	return nodes
+}
+
+// OKRTree returns the Objectives→Key Results hierarchy of doc, under a
+// root named after the document or its theme. Cross-cutting risks are
+// grouped under the root.
+func OKRTree(doc *okr.OKRDocument, opts TreeOptions) *TreeNode {
+	label := doc.Theme
+	if doc.Metadata != nil && doc.Metadata.Name != "" {
+		label = doc.Metadata.Name
+	}
+	if label == "" {
+		label = "OKRs"
+	}
+	root := &TreeNode{Kind: NodeRoot, Label: label}
+	for _, o := range doc.Objectives {
+		obj := &TreeNode{Kind: NodeGoal, Label: o.Title, Status: o.Status}
+		for _, kr := range o.KeyResults {
+			obj.Children = append(obj.Children, &TreeNode{Kind: NodeResult, Label: kr.Title, Status: kr.Status})
+		}
+		if opts.Obstacles {
+			obj.Children = append(obj.Children, riskNodes(o.Risks)...)
+		}
+		root.Children = append(root.Children, obj)
+	}
+	if opts.Obstacles && len(doc.Risks) > 0 {
+		root.Children = append(root.Children, &TreeNode{Kind: NodeGroup, Label: "Risks", Children: riskNodes(doc.Risks)})
+	}
+	return root
+}
+
+func riskNodes(risks []okr.Risk) []*TreeNode {
+	nodes := make([]*TreeNode, 0, len(risks))
+	for _, r := range risks {
+		nodes = append(nodes, &TreeNode{Kind: NodeObstacle, Label: r.Title, Status: r.Status})
+	}
+	return nodes
+}
+
+// Render returns the tree in the given format, mermaid or dot.
+func (n *TreeNode) Render(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", TreeFormatMermaid:
+		return n.Mermaid(), nil
+	case TreeFormatDOT, "graphviz":
+		return n.DOT(), nil
+	}
+	return "", fmt.Errorf("unknown tree format %q (use mermaid or dot)", format)
+}
+
+// Mermaid returns the tree as a Mermaid mindmap. Mindmaps cannot be
+// styled inline, so each node's status is shown as a colored marker
+// before its label.
+func (n *TreeNode) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("mindmap\n")
+	id := 0
+	var walk func(node *TreeNode, depth int)
+	walk = func(node *TreeNode, depth int) {
+		id++
+		label := mindmapText(node.Label)
+		if marker := statusColor(node.Status).marker; marker != "" {
+			label = marker + " " + label
+		}
+		open, close := "[", "]"
+		switch node.Kind {
+		case NodeRoot:
+			open, close = "((", "))"
+		case NodeResult:
+			open, close = "(", ")"
+		case NodeObstacle:
+			open, close = "{{", "}}"
+		case NodeGroup:
+			open, close = ")", "("
+		}
+		sb.WriteString(fmt.Sprintf("%sn%d%s%s%s\n", strings.Repeat("  ", depth), id, open, label, close))
+		for _, c := range node.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(n, 1)
+	return sb.String()
+}
+
+// DOT returns the tree as a Graphviz digraph laid out left to right, with
+// each node filled by the color of its status and the status under its
+// label.
+func (n *TreeNode) DOT() string {
+	var nodes, edges strings.Builder
+	id := 0
+	var walk func(node *TreeNode) string
+	walk = func(node *TreeNode) string {
+		name := fmt.Sprintf("n%d", id)
+		id++
+		label := node.Label
+		if node.Status != "" {
+			label += "\n(" + node.Status + ")"
+		}
+		attrs := []string{"label=" + dotString(label)}
+		switch node.Kind {
+		case NodeRoot:
+			attrs = append(attrs, "shape=ellipse")
+		case NodeObstacle:
+			attrs = append(attrs, "shape=hexagon")
+		case NodeGroup:
+			attrs = append(attrs, "shape=folder")
+		}
+		if fill := statusColor(node.Status).fill; fill != "" {
+			attrs = append(attrs, "fillcolor="+dotString(fill))
+		}
+		nodes.WriteString(fmt.Sprintf("  %s [%s];\n", name, strings.Join(attrs, ", ")))
+		for _, c := range node.Children {
+			edges.WriteString(fmt.Sprintf("  %s -> %s;\n", name, walk(c)))
+		}
+		return name
+	}
+	walk(n)
+
+	var sb strings.Builder
+	sb.WriteString("digraph goals {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"white\", fontname=\"Helvetica\"];\n")
+	sb.WriteString(nodes.String())
+	sb.WriteString(edges.String())
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+type colors struct {
+	marker string
+	fill   string
+}
+
+// statusColor returns the marker and fill color of a goal, result, or
+// obstacle status: green for on track and done, amber for at risk, red
+// for behind or missed, blue for in progress, and gray for not started or
+// cancelled. Unknown and empty statuses are not colored.
+func statusColor(status string) colors {
+	s := strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSpace(status)))
+	switch s {
+	case "on track", "achieved", "completed", "done", "resolved":
+		return colors{"🟢", "#c8e6c9"}
+	case "at risk", "mitigating":
+		return colors{"🟡", "#ffe0b2"}
+	case "behind", "missed", "blocked", "off track":
+		return colors{"🔴", "#ffcdd2"}
+	case "in progress", "active":
+		return colors{"🔵", "#bbdefb"}
+	case "not started", "planning", "draft", "cancelled", "accepted":
+		return colors{"⚪", "#eeeeee"}
+	}
+	return colors{}
+}
+
+// mindmapText makes s safe for a mindmap node, whose shape brackets it
+// cannot contain.
+func mindmapText(s string) string {
+	s = strings.NewReplacer("(", "", ")", "", "[", "", "]", "", "{", "", "}", "", "\n", " ", "\r", " ").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// dotString quotes s as a Graphviz string, keeping line breaks.
+func dotString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
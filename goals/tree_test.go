@@ -0,0 +1,94 @@
+package goals
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+)
+
+func treeV2MOM() *v2mom.V2MOM {
+	return &v2mom.V2MOM{
+		Vision: "Be the best (globally)",
+		Methods: []v2mom.Method{
+			{
+				Name:   "Expand to new markets",
+				Status: "In Progress",
+				Measures: []v2mom.Measure{
+					{Name: "APAC revenue", Status: "On Track"},
+					{Name: "EMEA revenue", Status: "Behind"},
+				},
+				Obstacles: []v2mom.Obstacle{{Name: "Regulation", Status: "Mitigating"}},
+			},
+		},
+		Measures:  []v2mom.Measure{{Name: "NPS", Status: "At Risk"}},
+		Obstacles: []v2mom.Obstacle{{Name: "Hiring freeze"}},
+	}
+}
+
+func TestV2MOMTreeMermaid(t *testing.T) {
+	out := V2MOMTree(treeV2MOM(), TreeOptions{}).Mermaid()
+
+	for _, want := range []string{
+		"mindmap\n",
+		"  n1((Be the best globally))\n",
+		"    n2[🔵 Expand to new markets]\n",
+		"      n3(🟢 APAC revenue)\n",
+		"      n4(🔴 EMEA revenue)\n",
+		"    n5)Measures(\n",
+		"      n6(🟡 NPS)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mindmap missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Regulation") || strings.Contains(out, "Hiring freeze") {
+		t.Errorf("obstacles included without the option:\n%s", out)
+	}
+
+	out = V2MOMTree(treeV2MOM(), TreeOptions{Obstacles: true, Terminology: v2mom.TerminologyOKR}).Mermaid()
+	for _, want := range []string{"{{🟡 Regulation}}", ")Key Results(", ")Risks(", "{{Hiring freeze}}"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mindmap missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestOKRTreeDOT(t *testing.T) {
+	doc := &okr.OKRDocument{
+		Metadata: &okr.Metadata{Name: `Q1 "Growth"`},
+		Objectives: []okr.Objective{
+			{
+				Title:  "Grow revenue",
+				Status: okr.StatusActive,
+				KeyResults: []okr.KeyResult{
+					{Title: "ARR", Status: "Achieved"},
+				},
+				Risks: []okr.Risk{{Title: "Churn", Status: "Identified"}},
+			},
+		},
+	}
+	out, err := OKRTree(doc, TreeOptions{Obstacles: true}).Render(TreeFormatDOT)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{
+		"digraph goals {\n",
+		`n0 [label="Q1 \"Growth\"", shape=ellipse];`,
+		`n1 [label="Grow revenue\n(Active)", fillcolor="#bbdefb"];`,
+		`n2 [label="ARR\n(Achieved)", fillcolor="#c8e6c9"];`,
+		`n3 [label="Churn\n(Identified)", shape=hexagon];`,
+		"n0 -> n1;",
+		"n1 -> n2;",
+		"n1 -> n3;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := OKRTree(doc, TreeOptions{}).Render("svg"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
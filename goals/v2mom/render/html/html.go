@@ -0,0 +1,221 @@
+// Package html provides a standalone HTML renderer for V2MOM documents.
+package html
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/goals/v2mom/render"
+	"github.com/grokify/structured-plan/htmldoc"
+)
+
+// Renderer implements the render.Renderer interface for HTML output.
+type Renderer struct{}
+
+// New creates a new V2MOM HTML renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "html"
+}
+
+// FileExtension returns the file extension for HTML output.
+func (r *Renderer) FileExtension() string {
+	return ".html"
+}
+
+// Render converts a V2MOM to a standalone HTML page. Section names follow
+// the terminology from opts or the document metadata, so an OKR-style
+// document shows Objectives and Key Results.
+func (r *Renderer) Render(v *v2mom.V2MOM, opts *render.Options) ([]byte, error) {
+	return r.Page(v, opts).Render()
+}
+
+// Page lays out a V2MOM as a page without rendering it.
+func (r *Renderer) Page(v *v2mom.V2MOM, opts *render.Options) *htmldoc.Page {
+	if opts == nil {
+		opts = render.DefaultOptions()
+	}
+	terms := v2mom.GetTerminologyLabels(opts.GetTerminology(v))
+
+	page := htmldoc.NewPage("V2MOM", "V2MOM")
+	page.Summary = v.Vision
+	page.CustomCSS = opts.CustomCSS
+	page.KaTeXURL = opts.KaTeXURL
+	if m := v.Metadata; m != nil {
+		if m.Name != "" {
+			page.Title = m.Name
+		}
+		page.Meta = []htmldoc.Field{
+			{Label: "ID", Value: m.ID},
+			{Label: "Author", Value: m.Author},
+			{Label: "Team", Value: m.Team},
+			{Label: "Period", Value: strings.TrimSpace(m.FiscalYear + " " + m.Quarter)},
+			{Label: "Version", Value: m.Version},
+			{Label: "Status", Value: m.Status},
+			{Label: "Updated", Value: htmldoc.Date(m.UpdatedAt)},
+		}
+	}
+
+	vision := htmldoc.NewBuilder("vision")
+	vision.Paragraph(v.Vision)
+	page.AddSection("Vision", vision)
+	page.AddSection("Values", values(v))
+	page.AddSection(terms.Methods, methods(v, terms, opts))
+
+	obstacles := htmldoc.NewBuilder("obstacles")
+	obstacleTable(obstacles, v.Obstacles, opts.IncludeStatus)
+	page.AddSection(terms.Obstacles, obstacles)
+
+	measures := htmldoc.NewBuilder("measures")
+	if opts.FlattenMeasures {
+		measureTable(measures, v.AllMeasures(), terms, opts.IncludeStatus)
+	} else {
+		measureTable(measures, v.Measures, terms, opts.IncludeStatus)
+	}
+	page.AddSection(terms.Measures, measures)
+
+	if opts.IncludeProjects {
+		page.AddSection("Projects", projects(v, terms, opts.IncludeStatus))
+	}
+	return page
+}
+
+func values(v *v2mom.V2MOM) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("values")
+	vals := slices.Clone(v.Values)
+	slices.SortStableFunc(vals, func(a, b v2mom.Value) int {
+		return cmp.Compare(priorityRank(a.Priority), priorityRank(b.Priority))
+	})
+	items := make([]string, 0, len(vals))
+	for _, val := range vals {
+		if val.Description != "" {
+			items = append(items, val.Name+": "+val.Description)
+		} else {
+			items = append(items, val.Name)
+		}
+	}
+	b.List("", items)
+	return b
+}
+
+// priorityRank orders values by priority, with unranked values last.
+func priorityRank(p int) int {
+	if p <= 0 {
+		return math.MaxInt
+	}
+	return p
+}
+
+func methods(v *v2mom.V2MOM, terms v2mom.Terminology, opts *render.Options) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("method")
+	for i, m := range v.Methods {
+		b.Heading(strconv.Itoa(i+1) + ". " + m.Name)
+		b.Paragraph(m.Description)
+		fields := []htmldoc.Field{
+			{Label: "Priority", Value: m.Priority},
+			{Label: "Owner", Value: m.Owner},
+			{Label: "Dates", Value: dateRange(m.StartDate, m.EndDate)},
+		}
+		if opts.IncludeStatus {
+			fields = append(fields, htmldoc.Field{Label: "Status", Value: m.Status})
+		}
+		b.Fields(fields...)
+		if !opts.FlattenMeasures {
+			measureTable(b, m.Measures, terms, opts.IncludeStatus)
+		}
+		obstacleTable(b, m.Obstacles, opts.IncludeStatus)
+		if opts.IncludeProjects {
+			b.List("Projects", m.Projects)
+		}
+	}
+	return b
+}
+
+func measureTable(b *htmldoc.Builder, measures []v2mom.Measure, terms v2mom.Terminology, status bool) {
+	// Grade measures against their commit and stretch targets when any has one
+	stretch := slices.ContainsFunc(measures, func(m v2mom.Measure) bool { return m.StretchTarget != "" })
+	header := []string{terms.MeasureSingular, "Baseline", "Target", "Current", "Timeline"}
+	if stretch {
+		header = append(header, "Attainment")
+	}
+	if status {
+		header = append(header, "Status")
+	}
+	rows := make([][]string, 0, len(measures))
+	for _, m := range measures {
+		target := okr.FormatTarget(withUnit(m.Target, m.Unit), withUnit(m.StretchTarget, m.Unit))
+		row := []string{m.Name, m.Baseline, target, withUnit(m.Current, m.Unit), m.Timeline}
+		if stretch {
+			attainment := ""
+			if p, err := m.KeyResult().ComputeProgress(); err == nil {
+				attainment = p.Attainment
+			}
+			row = append(row, attainment)
+		}
+		if status {
+			row = append(row, m.Status)
+		}
+		rows = append(rows, row)
+	}
+	b.Table(header, rows)
+}
+
+func obstacleTable(b *htmldoc.Builder, obstacles []v2mom.Obstacle, status bool) {
+	header := []string{"Name", "Severity", "Likelihood", "Mitigation"}
+	if status {
+		header = append(header, "Status")
+	}
+	rows := make([][]string, 0, len(obstacles))
+	for _, o := range obstacles {
+		row := []string{o.Name, o.Severity, o.Likelihood, o.Mitigation}
+		if status {
+			row = append(row, o.Status)
+		}
+		rows = append(rows, row)
+	}
+	b.Table(header, rows)
+}
+
+func projects(v *v2mom.V2MOM, terms v2mom.Terminology, status bool) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("projects")
+	header := []string{"ID", "Project", terms.MethodSingular, "Priority", "Quarter"}
+	if status {
+		header = append(header, "Status")
+	}
+	rows := make([][]string, 0, len(v.Projects))
+	for _, p := range v.Projects {
+		row := []string{p.ID, p.Name, p.MethodID, p.Priority, p.Quarter}
+		if status {
+			row = append(row, p.Status)
+		}
+		rows = append(rows, row)
+	}
+	b.Table(header, rows)
+	return b
+}
+
+func withUnit(value, unit string) string {
+	if value == "" || unit == "" {
+		return value
+	}
+	return value + " " + unit
+}
+
+func dateRange(start, end string) string {
+	switch {
+	case start != "" && end != "":
+		return start + " to " + end
+	case end != "":
+		return "by " + end
+	}
+	return start
+}
@@ -0,0 +1,66 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/goals/v2mom/render"
+)
+
+func TestRender(t *testing.T) {
+	v := &v2mom.V2MOM{
+		Metadata: &v2mom.Metadata{Name: "FY26 Platform", Team: "Platform"},
+		Vision:   "Every team ships safely",
+		Values:   []v2mom.Value{{Name: "Speed", Priority: 2}, {Name: "Trust", Priority: 1}},
+		Methods: []v2mom.Method{{
+			Name:     "Harden CI",
+			Measures: []v2mom.Measure{{Name: "Build time", Target: "10", Unit: "min"}},
+		}},
+		Obstacles: []v2mom.Obstacle{{Name: "Legacy runners"}},
+	}
+	opts := render.DefaultOptions()
+	opts.Terminology = v2mom.TerminologyOKR
+	out, err := New().Render(v, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<h1>FY26 Platform</h1>",
+		`<section id="objectives">`,
+		"<th>Key Result</th>",
+		"<td>10 min</td>",
+		`<section id="risks">`,
+		"<li>Trust</li>\n<li>Speed</li>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestRenderStretchTargets(t *testing.T) {
+	v := &v2mom.V2MOM{
+		Metadata: &v2mom.Metadata{Name: "FY26 Platform"},
+		Measures: []v2mom.Measure{
+			{Name: "Build time", Baseline: "20", Target: "10", StretchTarget: "5", Current: "8", Unit: "min"},
+			{Name: "Deploys per day", Target: "10", StretchTarget: "20", Current: "4"},
+		},
+	}
+	out, err := New().Render(v, render.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<th>Attainment</th>",
+		"<td>10 min (stretch 5 min)</td>",
+		"<td>commit</td>",
+		"<td>missed</td>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
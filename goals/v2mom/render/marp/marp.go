@@ -270,7 +270,7 @@ var methodDetailTmpl = template.Must(template.New("methodDetail").Funcs(funcMap)
 | {{.Term.MeasureSingular}} | Target | Status | Progress |
 |---------|--------|--------|----------|
 {{range .Method.Measures -}}
-| {{.Name}} | {{if .Target}}{{.Target}}{{else}}-{{end}} | {{if .Status}}{{.Status}}{{else}}-{{end}} | {{if .Progress}}{{progressPercent .Progress}}{{else}}-{{end}} |
+| {{.Name}} | {{if .Target}}{{.TargetLabel}}{{else}}-{{end}} | {{if .Status}}{{.Status}}{{else}}-{{end}} | {{if .Progress}}{{progressPercent .Progress}}{{else}}-{{end}} |
 {{end}}
 {{end}}
 {{if .Method.Obstacles}}### {{.Term.Obstacles}}
@@ -307,7 +307,7 @@ var measuresSlideTmpl = template.Must(template.New("measuresSlide").Funcs(funcMa
 | {{.Term.MeasureSingular}} | Baseline | Target | Current | Status |
 |---------|----------|--------|---------|--------|
 {{range .V2MOM.Measures -}}
-| {{.Name}} | {{if .Baseline}}{{.Baseline}}{{else}}-{{end}} | {{if .Target}}{{.Target}}{{else}}-{{end}} | {{if .Current}}{{.Current}}{{else}}-{{end}} | {{if .Status}}{{.Status}}{{else}}-{{end}} |
+| {{.Name}} | {{if .Baseline}}{{.Baseline}}{{else}}-{{end}} | {{if .Target}}{{.TargetLabel}}{{else}}-{{end}} | {{if .Current}}{{.Current}}{{else}}-{{end}} | {{if .Status}}{{.Status}}{{else}}-{{end}} |
 {{end}}
 ---
 
@@ -318,7 +318,7 @@ var measuresDashboardTmpl = template.Must(template.New("measuresDashboard").Func
 | {{.Term.MeasureSingular}} | Target | Progress | Status |
 |---------|--------|----------|--------|
 {{range .AllMeasures -}}
-| {{.Name}} | {{if .Target}}{{.Target}}{{else}}-{{end}} | {{if .Progress}}[{{progressBar .Progress}}] {{progressPercent .Progress}}{{else}}-{{end}} | {{if .Status}}{{.Status}}{{else}}-{{end}} |
+| {{.Name}} | {{if .Target}}{{.TargetLabel}}{{else}}-{{end}} | {{if .Progress}}[{{progressBar .Progress}}] {{progressPercent .Progress}}{{else}}-{{end}} | {{if .Status}}{{.Status}}{{else}}-{{end}} |
 {{end}}
 ---
 
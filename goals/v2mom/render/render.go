@@ -4,19 +4,14 @@ package render
 
 import (
 	"github.com/grokify/structured-plan/goals/v2mom"
+	shared "github.com/grokify/structured-plan/render"
 )
 
-// Renderer defines the interface for output format renderers.
-type Renderer interface {
-	// Render converts a V2MOM to the target format.
-	Render(v *v2mom.V2MOM, opts *Options) ([]byte, error)
-	// Format returns the output format name (e.g., "marp", "confluence").
-	Format() string
-	// FileExtension returns the file extension for this format (e.g., ".md", ".html").
-	FileExtension() string
-}
+// Renderer defines the interface for V2MOM output format renderers.
+type Renderer = shared.TypedRenderer[v2mom.V2MOM, Options]
 
-// Options contains rendering options common to all renderers.
+// Options contains the rendering options of V2MOM renderers. The registry
+// fills the fields that are also render.Options from the common options.
 type Options struct {
 	// Theme name (renderer-specific, e.g., "default", "corporate", "minimal")
 	Theme string
@@ -39,6 +34,10 @@ type Options struct {
 	// Custom CSS (for Marp/HTML renderers)
 	CustomCSS string
 
+	// KaTeXURL is the base URL of a local KaTeX dist directory for math
+	// (HTML renderer; default: the jsdelivr CDN)
+	KaTeXURL string
+
 	// Additional metadata (renderer-specific)
 	Metadata map[string]string
 }
@@ -46,7 +45,7 @@ type Options struct {
 // DefaultOptions returns sensible default rendering options.
 func DefaultOptions() *Options {
 	return &Options{
-		Theme:           "default",
+		Theme:           shared.ThemeDefault,
 		Terminology:     v2mom.TerminologyV2MOM,
 		IncludeProjects: true,
 		IncludeStatus:   true,
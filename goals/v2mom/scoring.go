@@ -0,0 +1,416 @@
+package v2mom
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+)
+
+// CategoryWeight defines the weight for each scoring category.
+type CategoryWeight struct {
+	Category string
+	Weight   float64
+}
+
+// DefaultWeights returns the standard category weights. Weights sum to 1.0.
+func DefaultWeights() []CategoryWeight {
+	return []CategoryWeight{
+		{Category: "measurable_key_results", Weight: 0.30},
+		{Category: "value_alignment", Weight: 0.25},
+		{Category: "obstacle_mitigation", Weight: 0.20},
+		{Category: "smart_measures", Weight: 0.25},
+	}
+}
+
+// Thresholds for scoring decisions, as for PRDs.
+const (
+	ThresholdApprove = 8.0
+	ThresholdRevise  = 6.5
+	ThresholdBlocker = 3.0
+)
+
+// CategoryScore is the score for a single category.
+type CategoryScore struct {
+	Category       string  `json:"category"`
+	Weight         float64 `json:"weight"`
+	Score          float64 `json:"score"`
+	MaxScore       float64 `json:"maxScore"`
+	Justification  string  `json:"justification"`
+	Evidence       string  `json:"evidence"`
+	BelowThreshold bool    `json:"belowThreshold"`
+
+	// Gaps are the items that lost points, such as a measure without a
+	// target, as "path: reason".
+	Gaps []string `json:"gaps,omitempty"`
+}
+
+// ScoringResult is the complete scoring output.
+type ScoringResult struct {
+	CategoryScores []CategoryScore `json:"categoryScores"`
+	WeightedScore  float64         `json:"weightedScore"`
+	Decision       string          `json:"decision"`
+	Blockers       []string        `json:"blockers"`
+	Summary        string          `json:"summary"`
+}
+
+// Score evaluates the V2MOM for measurable key results, value-method
+// alignment, obstacle mitigation, and SMART measures.
+func Score(v *V2MOM) *ScoringResult {
+	result := &ScoringResult{CategoryScores: make([]CategoryScore, 0)}
+	var total, totalWeight float64
+	for _, w := range DefaultWeights() {
+		score := scoreCategory(v, w.Category)
+		score.Weight = w.Weight
+		score.MaxScore = 10.0
+		if score.Score <= ThresholdBlocker {
+			score.BelowThreshold = true
+			result.Blockers = append(result.Blockers, fmt.Sprintf("%s: %s", w.Category, score.Justification))
+		}
+		result.CategoryScores = append(result.CategoryScores, score)
+		total += score.Score * w.Weight
+		totalWeight += w.Weight
+	}
+	if totalWeight > 0 {
+		result.WeightedScore = total / totalWeight
+	}
+
+	switch {
+	case len(result.Blockers) > 0:
+		result.Decision = "reject"
+	case result.WeightedScore >= ThresholdApprove:
+		result.Decision = "approve"
+	case result.WeightedScore >= ThresholdRevise:
+		result.Decision = "revise"
+	default:
+		result.Decision = "human_review"
+	}
+	result.Summary = fmt.Sprintf("V2MOM scored %.1f/10 (%s) with %d blockers.",
+		result.WeightedScore, result.Decision, len(result.Blockers))
+	return result
+}
+
+func scoreCategory(v *V2MOM, category string) CategoryScore {
+	switch category {
+	case "measurable_key_results":
+		return scoreMeasurable(v)
+	case "value_alignment":
+		return scoreAlignment(v)
+	case "obstacle_mitigation":
+		return scoreObstacles(v)
+	case "smart_measures":
+		return scoreSMART(v)
+	}
+	return CategoryScore{Category: category, Score: 5.0, Justification: "Unknown category"}
+}
+
+// scoredMeasure is a measure with its JSON path and the method it belongs
+// to, if any.
+type scoredMeasure struct {
+	Measure
+	path   string
+	method *Method
+}
+
+func (v *V2MOM) scoredMeasures() []scoredMeasure {
+	var measures []scoredMeasure
+	for i := range v.Methods {
+		m := &v.Methods[i]
+		for j, ms := range m.Measures {
+			measures = append(measures, scoredMeasure{Measure: ms, path: fmt.Sprintf("methods[%d].measures[%d]", i, j), method: m})
+		}
+	}
+	for i, ms := range v.Measures {
+		measures = append(measures, scoredMeasure{Measure: ms, path: fmt.Sprintf("measures[%d]", i)})
+	}
+	return measures
+}
+
+// quantified reports whether s, such as a target, contains a number.
+func quantified(s string) bool {
+	return strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
+
+func scoreMeasurable(v *V2MOM) CategoryScore {
+	score := CategoryScore{Category: "measurable_key_results"}
+	measures := v.scoredMeasures()
+	if len(measures) == 0 {
+		score.Justification = "No measures defined"
+		return score
+	}
+
+	var points float64
+	withTarget, withBaseline := 0, 0
+	for _, m := range measures {
+		switch {
+		case quantified(m.Target):
+			withTarget++
+		case m.Target == "":
+			score.Gaps = append(score.Gaps, m.path+": no target")
+		default:
+			score.Gaps = append(score.Gaps, m.path+": target is not a number")
+		}
+		if m.Baseline != "" {
+			withBaseline++
+		}
+	}
+	points += 6 * float64(withTarget) / float64(len(measures))
+	points += 2 * float64(withBaseline) / float64(len(measures))
+
+	if len(v.Methods) > 0 && len(v.Measures) == 0 {
+		withMeasures := 0
+		for i, m := range v.Methods {
+			if len(m.Measures) > 0 {
+				withMeasures++
+			} else {
+				score.Gaps = append(score.Gaps, fmt.Sprintf("methods[%d]: no measures", i))
+			}
+		}
+		points += 2 * float64(withMeasures) / float64(len(v.Methods))
+	} else {
+		// Flat V2MOMs measure the whole plan rather than each method.
+		points += 2
+	}
+
+	score.Score = roundScore(points)
+	score.Evidence = fmt.Sprintf("%d of %d measures have a numeric target; %d have a baseline",
+		withTarget, len(measures), withBaseline)
+	score.Justification = justification("Measurable key results", score.Score)
+	return score
+}
+
+func scoreAlignment(v *V2MOM) CategoryScore {
+	score := CategoryScore{Category: "value_alignment"}
+	if len(v.Values) == 0 || len(v.Methods) == 0 {
+		score.Justification = "Values or methods are missing"
+		return score
+	}
+
+	values := make(map[string]bool, len(v.Values))
+	for _, val := range v.Values {
+		values[strings.ToLower(val.Name)] = true
+	}
+	served := make(map[string]bool)
+	linked := 0
+	for i, m := range v.Methods {
+		ok := false
+		for _, name := range m.Values {
+			if values[strings.ToLower(name)] {
+				served[strings.ToLower(name)] = true
+				ok = true
+			} else {
+				score.Gaps = append(score.Gaps, fmt.Sprintf("methods[%d].values: undefined value %q", i, name))
+			}
+		}
+		if ok {
+			linked++
+		} else if len(m.Values) == 0 {
+			score.Gaps = append(score.Gaps, fmt.Sprintf("methods[%d]: not linked to a value", i))
+		}
+	}
+	for i, val := range v.Values {
+		if !served[strings.ToLower(val.Name)] {
+			score.Gaps = append(score.Gaps, fmt.Sprintf("values[%d]: no method advances %q", i, val.Name))
+		}
+	}
+
+	points := 6*float64(linked)/float64(len(v.Methods)) + 4*float64(len(served))/float64(len(v.Values))
+	score.Score = roundScore(points)
+	score.Evidence = fmt.Sprintf("%d of %d methods linked to a value; %d of %d values advanced by a method",
+		linked, len(v.Methods), len(served), len(v.Values))
+	score.Justification = justification("Value alignment", score.Score)
+	return score
+}
+
+func scoreObstacles(v *V2MOM) CategoryScore {
+	score := CategoryScore{Category: "obstacle_mitigation"}
+	type pathObstacle struct {
+		Obstacle
+		path string
+	}
+	var obstacles []pathObstacle
+	for i, o := range v.Obstacles {
+		obstacles = append(obstacles, pathObstacle{o, fmt.Sprintf("obstacles[%d]", i)})
+	}
+	for i, m := range v.Methods {
+		for j, o := range m.Obstacles {
+			obstacles = append(obstacles, pathObstacle{o, fmt.Sprintf("methods[%d].obstacles[%d]", i, j)})
+		}
+	}
+	if len(obstacles) == 0 {
+		score.Score = 2
+		score.Justification = "No obstacles identified"
+		return score
+	}
+
+	// Severe obstacles count double, so an unmitigated critical risk costs
+	// more than an unmitigated minor one.
+	var weight, mitigated float64
+	rated := 0
+	for _, o := range obstacles {
+		w := 1.0
+		switch strings.ToLower(o.Severity) {
+		case "high", "critical":
+			w = 2
+		}
+		weight += w
+		if strings.TrimSpace(o.Mitigation) != "" {
+			mitigated += w
+		} else {
+			score.Gaps = append(score.Gaps, o.path+": no mitigation")
+		}
+		if o.Severity != "" && o.Likelihood != "" {
+			rated++
+		}
+	}
+	points := 2 + 6*mitigated/weight + 2*float64(rated)/float64(len(obstacles))
+	score.Score = roundScore(points)
+	score.Evidence = fmt.Sprintf("%d obstacles; %.0f%% severity-weighted mitigation coverage; %d rated for severity and likelihood",
+		len(obstacles), 100*mitigated/weight, rated)
+	score.Justification = justification("Obstacle mitigation", score.Score)
+	return score
+}
+
+// smartChecks are the SMART criteria applied to each measure.
+var smartChecks = []struct {
+	name  string
+	check func(m scoredMeasure) bool
+}{
+	{"specific", func(m scoredMeasure) bool {
+		return m.Description != "" || len(strings.Fields(m.Name)) >= 3
+	}},
+	{"measurable", func(m scoredMeasure) bool { return quantified(m.Target) }},
+	{"achievable", func(m scoredMeasure) bool { return m.Baseline != "" }},
+	{"relevant", func(m scoredMeasure) bool { return m.method != nil || m.Unit != "" }},
+	{"time-bound", func(m scoredMeasure) bool {
+		return m.Timeline != "" || m.method != nil && m.method.EndDate != ""
+	}},
+}
+
+func scoreSMART(v *V2MOM) CategoryScore {
+	score := CategoryScore{Category: "smart_measures"}
+	measures := v.scoredMeasures()
+	if len(measures) == 0 {
+		score.Justification = "No measures defined"
+		return score
+	}
+
+	passed := 0
+	counts := make([]int, len(smartChecks))
+	for _, m := range measures {
+		var missing []string
+		for i, c := range smartChecks {
+			if c.check(m) {
+				passed++
+				counts[i]++
+			} else {
+				missing = append(missing, c.name)
+			}
+		}
+		if len(missing) > 0 {
+			score.Gaps = append(score.Gaps, fmt.Sprintf("%s: not %s", m.path, strings.Join(missing, ", ")))
+		}
+	}
+	score.Score = roundScore(10 * float64(passed) / float64(len(measures)*len(smartChecks)))
+
+	var evidence []string
+	for i, c := range smartChecks {
+		evidence = append(evidence, fmt.Sprintf("%s %d/%d", c.name, counts[i], len(measures)))
+	}
+	score.Evidence = strings.Join(evidence, "; ")
+	score.Justification = justification("SMART measures", score.Score)
+	return score
+}
+
+func roundScore(points float64) float64 {
+	if points > 10 {
+		points = 10
+	}
+	return float64(int(points*10+0.5)) / 10
+}
+
+func justification(name string, score float64) string {
+	switch {
+	case score >= 8:
+		return name + " is strong"
+	case score >= 6:
+		return name + " is adequate but could be improved"
+	case score > ThresholdBlocker:
+		return name + " needs significant work"
+	}
+	return name + " is missing or inadequate"
+}
+
+// recommendations are the fixes suggested for low-scoring categories.
+var recommendations = map[string]string{
+	"measurable_key_results": "Give every method measures with numeric targets and baselines",
+	"value_alignment":        "Link each method to the values it advances with methods[].values",
+	"obstacle_mitigation":    "Identify obstacles, rate their severity and likelihood, and add mitigations",
+	"smart_measures":         "Make measures specific, with numeric targets, baselines, and timelines",
+}
+
+// ScoreToEvaluationReport scores the V2MOM and returns the result as an
+// evaluation report, the format of the PRD scorer.
+func ScoreToEvaluationReport(v *V2MOM, filename string) *evaluation.EvaluationReport {
+	result := Score(v)
+
+	report := evaluation.NewEvaluationReport("v2mom", filepath.Base(filename))
+	if v.Metadata != nil {
+		report.Metadata.DocumentID = v.Metadata.ID
+		report.Metadata.DocumentTitle = v.Metadata.Name
+		report.Metadata.DocumentVersion = v.Metadata.Version
+	}
+	report.Metadata.GeneratedAt = time.Now().UTC()
+	report.Metadata.GeneratedBy = "splan (deterministic)"
+
+	issue := 1
+	for _, cs := range result.CategoryScores {
+		category := evaluation.CategoryScore{
+			Category:      cs.Category,
+			Score:         cs.Score,
+			MaxScore:      cs.MaxScore,
+			Weight:        cs.Weight,
+			Justification: cs.Justification,
+			Evidence:      cs.Evidence,
+		}
+		for _, gap := range cs.Gaps {
+			path, reason, _ := strings.Cut(gap, ": ")
+			category.Findings = append(category.Findings, evaluation.Finding{
+				Category: cs.Category,
+				Severity: evaluation.SeverityLow,
+				Title:    reason,
+				Evidence: "/" + strings.NewReplacer("[", "/", "]", "", ".", "/").Replace(path),
+			})
+		}
+		category.ComputeStatus()
+		report.Categories = append(report.Categories, category)
+
+		if cs.Score >= 7.0 {
+			continue
+		}
+		severity := evaluation.SeverityMedium
+		switch {
+		case cs.Score <= ThresholdBlocker:
+			severity = evaluation.SeverityCritical
+		case cs.Score < 5.0:
+			severity = evaluation.SeverityHigh
+		}
+		report.Findings = append(report.Findings, evaluation.Finding{
+			ID:             fmt.Sprintf("REV-%d", issue),
+			Category:       cs.Category,
+			Severity:       severity,
+			Title:          cs.Justification,
+			Description:    cs.Evidence,
+			Recommendation: recommendations[cs.Category],
+			Effort:         "medium",
+		})
+		issue++
+	}
+
+	report.WeightedScore = result.WeightedScore
+	report.Finalize(fmt.Sprintf("splan goals v2mom score %s", filename))
+	return report
+}
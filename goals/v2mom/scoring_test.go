@@ -0,0 +1,96 @@
+package v2mom
+
+import (
+	"testing"
+)
+
+func scoringV2MOM() *V2MOM {
+	return &V2MOM{
+		Vision: "Be the easiest platform to adopt",
+		Values: []Value{{Name: "Simplicity"}, {Name: "Speed"}},
+		Methods: []Method{
+			{
+				Name:    "Self-service onboarding",
+				EndDate: "2025-03-31",
+				Values:  []string{"simplicity"},
+				Measures: []Measure{
+					{Name: "TTFV", Baseline: "14 days", Target: "2 days"},
+					{Name: "Activation", Description: "Accounts active in week one", Target: "high"},
+				},
+				Obstacles: []Obstacle{{Name: "Legacy auth", Severity: "high", Likelihood: "medium", Mitigation: "Migrate first"}},
+			},
+			{Name: "Integrations", Values: []string{"Scale"}},
+		},
+		Obstacles: []Obstacle{{Name: "Hiring"}},
+	}
+}
+
+func TestScore(t *testing.T) {
+	result := Score(scoringV2MOM())
+	want := map[string]float64{
+		"measurable_key_results": 5.0,
+		"value_alignment":        5.0,
+		"obstacle_mitigation":    7.0,
+		"smart_measures":         7.0,
+	}
+	gaps := make(map[string][]string)
+	for _, cs := range result.CategoryScores {
+		if cs.Score != want[cs.Category] {
+			t.Errorf("%s = %v, want %v (%s)", cs.Category, cs.Score, want[cs.Category], cs.Evidence)
+		}
+		gaps[cs.Category] = cs.Gaps
+	}
+	if result.Decision != "human_review" {
+		t.Errorf("Decision = %q, want human_review at %.2f", result.Decision, result.WeightedScore)
+	}
+
+	wantGaps := map[string][]string{
+		"measurable_key_results": {"methods[0].measures[1]: target is not a number", "methods[1]: no measures"},
+		"value_alignment":        {`methods[1].values: undefined value "Scale"`, `values[1]: no method advances "Speed"`},
+		"obstacle_mitigation":    {"obstacles[0]: no mitigation"},
+		"smart_measures":         {"methods[0].measures[0]: not specific", "methods[0].measures[1]: not measurable, achievable"},
+	}
+	for category, want := range wantGaps {
+		got := gaps[category]
+		if len(got) != len(want) {
+			t.Errorf("%s gaps = %q, want %q", category, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s gaps[%d] = %q, want %q", category, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestScoreToEvaluationReport(t *testing.T) {
+	v := scoringV2MOM()
+	v.Methods[1].Measures = nil
+	v.Values = nil
+
+	report := ScoreToEvaluationReport(v, "plans/fy25.v2mom.json")
+	if report.ReviewType != "v2mom" || report.Metadata.Document != "fy25.v2mom.json" {
+		t.Errorf("report = %q %q", report.ReviewType, report.Metadata.Document)
+	}
+	if report.Decision.Passed {
+		t.Error("expected a V2MOM without values to fail")
+	}
+	found := false
+	for _, f := range report.Findings {
+		if f.Category == "value_alignment" && f.Recommendation != "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("findings = %+v, want a value alignment recommendation", report.Findings)
+	}
+	for _, c := range report.Categories {
+		if c.Category != "measurable_key_results" {
+			continue
+		}
+		if len(c.Findings) == 0 || c.Findings[0].Evidence != "/methods/0/measures/1" {
+			t.Errorf("measurable_key_results findings = %+v", c.Findings)
+		}
+	}
+}
@@ -11,6 +11,10 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/yamlconv"
 )
 
 // Structure constants define V2MOM organizational styles.
@@ -113,6 +117,8 @@ type Method struct {
 	Obstacles []Obstacle `json:"obstacles,omitempty"`
 	// Linked project IDs
 	Projects []string `json:"projects,omitempty"`
+	// Names of the values this method advances
+	Values []string `json:"values,omitempty"`
 }
 
 // Obstacle represents a challenge or risk that could prevent success.
@@ -129,16 +135,17 @@ type Obstacle struct {
 // Measure represents a success metric or key result.
 // In OKR terminology, this corresponds to a Key Result.
 type Measure struct {
-	ID          string  `json:"id,omitempty"`
-	Name        string  `json:"name"`
-	Description string  `json:"description,omitempty"`
-	Baseline    string  `json:"baseline,omitempty"` // Starting value
-	Target      string  `json:"target,omitempty"`   // Target value
-	Current     string  `json:"current,omitempty"`  // Current value
-	Unit        string  `json:"unit,omitempty"`     // Unit of measurement
-	Progress    float64 `json:"progress,omitempty"` // 0.0-1.0 (OKR scoring)
-	Timeline    string  `json:"timeline,omitempty"` // Target timeline
-	Status      string  `json:"status,omitempty"`   // On Track, At Risk, Behind, Achieved, Missed
+	ID            string  `json:"id,omitempty"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description,omitempty"`
+	Baseline      string  `json:"baseline,omitempty"`      // Starting value
+	Target        string  `json:"target,omitempty"`        // Target value; the commit target when a stretch target is set
+	StretchTarget string  `json:"stretchTarget,omitempty"` // Ambitious target beyond the commit target
+	Current       string  `json:"current,omitempty"`       // Current value
+	Unit          string  `json:"unit,omitempty"`          // Unit of measurement
+	Progress      float64 `json:"progress,omitempty"`      // 0.0-1.0 (OKR scoring)
+	Timeline      string  `json:"timeline,omitempty"`      // Target timeline
+	Status        string  `json:"status,omitempty"`        // On Track, At Risk, Behind, Achieved, Missed
 }
 
 // Project represents a roadmap project linked to methods.
@@ -175,6 +182,18 @@ func Parse(data []byte) (*V2MOM, error) {
 	return &v, nil
 }
 
+// ParseLenient parses V2MOM JSON data, recovering from field-level type
+// errors instead of rejecting the document. The returned report lists each
+// field that was coerced or dropped.
+func ParseLenient(data []byte) (*V2MOM, *lenient.Report, error) {
+	var v V2MOM
+	report, err := lenient.Unmarshal(data, &v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &v, report, nil
+}
+
 // JSON returns the V2MOM as formatted JSON.
 func (v *V2MOM) JSON() ([]byte, error) {
 	return json.MarshalIndent(v, "", "  ")
@@ -192,6 +211,42 @@ func (v *V2MOM) WriteFile(filepath string) error {
 	return nil
 }
 
+// ReadFileYAML reads a V2MOM from a YAML file. Field names are the same as
+// in JSON.
+func ReadFileYAML(filepath string) (*V2MOM, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return ParseYAML(data)
+}
+
+// ParseYAML parses V2MOM YAML data.
+func ParseYAML(data []byte) (*V2MOM, error) {
+	var v V2MOM
+	if err := yamlconv.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// YAML returns the V2MOM as YAML.
+func (v *V2MOM) YAML() ([]byte, error) {
+	return yamlconv.Marshal(v)
+}
+
+// WriteFileYAML writes the V2MOM to a YAML file.
+func (v *V2MOM) WriteFileYAML(filepath string) error {
+	data, err := v.YAML()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath, data, 0600); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
+
 // AllMeasures returns all measures (global + nested), flattened.
 func (v *V2MOM) AllMeasures() []Measure {
 	all := make([]Measure, 0, len(v.Measures))
@@ -202,6 +257,29 @@ func (v *V2MOM) AllMeasures() []Measure {
 	return all
 }
 
+// KeyResult returns the measure as an OKR key result, so its progress and
+// its attainment of the commit and stretch targets can be computed.
+func (m Measure) KeyResult() okr.KeyResult {
+	return okr.KeyResult{
+		ID:            m.ID,
+		Title:         m.Name,
+		Description:   m.Description,
+		Baseline:      m.Baseline,
+		Target:        m.Target,
+		StretchTarget: m.StretchTarget,
+		Current:       m.Current,
+		Unit:          m.Unit,
+		Score:         m.Progress,
+		Status:        m.Status,
+	}
+}
+
+// TargetLabel returns the target for display, followed by the stretch
+// target if there is one.
+func (m Measure) TargetLabel() string {
+	return okr.FormatTarget(m.Target, m.StretchTarget)
+}
+
 // AllObstacles returns all obstacles (global + nested), flattened.
 func (v *V2MOM) AllObstacles() []Obstacle {
 	all := make([]Obstacle, 0, len(v.Obstacles))
@@ -0,0 +1,53 @@
+package v2mom
+
+import (
+	"testing"
+)
+
+func TestParseLenient(t *testing.T) {
+	data := []byte(`{
+		"metadata": {"name": "FY25", "createdAt": "2025-02-01"},
+		"vision": "Be great",
+		"values": [{"name": "Trust", "priority": "1"}],
+		"methods": [{"name": "Ship", "priority": 1}]
+	}`)
+
+	if _, err := Parse(data); err == nil {
+		t.Fatal("expected strict Parse to fail")
+	}
+
+	v, report, err := ParseLenient(data)
+	if err != nil {
+		t.Fatalf("ParseLenient() error = %v", err)
+	}
+	if v.Vision != "Be great" {
+		t.Errorf("expected vision to load, got %q", v.Vision)
+	}
+	if v.Values[0].Priority != 1 {
+		t.Errorf("expected value priority 1, got %d", v.Values[0].Priority)
+	}
+	if v.Methods[0].Priority != "1" {
+		t.Errorf("expected method priority %q, got %q", "1", v.Methods[0].Priority)
+	}
+	if len(report.Issues) != 3 {
+		t.Errorf("expected 3 recovered fields, got %d: %v", len(report.Issues), report.Issues)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(`{"vision": "V", "values": [{"name": "Trust", "priority": 1}], "methods": [{"name": "Ship"}]}`))
+	f.Add([]byte(`{"vision": "", "values": [], "methods": [{"measures": [{"progress": 2}]}]}`))
+	f.Add([]byte(`{"metadata": {"structure": "nested"}, "obstacles": [{}], "measures": [{}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if v, err := Parse(data); err == nil {
+			_ = v.Validate(DefaultValidationOptions())
+			_ = v.Validate(OKRValidationOptions())
+			_ = v.AllMeasures()
+			_ = v.AllObstacles()
+		}
+		if v, _, err := ParseLenient(data); err == nil {
+			_ = v.Validate(DefaultValidationOptions())
+		}
+	})
+}
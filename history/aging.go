@@ -0,0 +1,325 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/diff"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Aging defaults.
+const (
+	DefaultStaleMonths = 6
+	DefaultChurnRatio  = 0.5
+	DefaultMinChanges  = 2
+)
+
+// AgingOptions configures which entities are reported as stale or as high
+// churn.
+type AgingOptions struct {
+	// AsOf is the date ages are measured to. Zero means the date of the
+	// newest version.
+	AsOf time.Time
+
+	// StaleMonths is how many months a requirement or user story may go
+	// unchanged while unimplemented before it is stale. Zero defaults to
+	// DefaultStaleMonths.
+	StaleMonths int
+
+	// ChurnRatio is the share of versions since an entity was added in
+	// which it must have changed to count as high churn. Zero defaults to
+	// DefaultChurnRatio.
+	ChurnRatio float64
+
+	// MinChanges is the fewest changes a high-churn entity must have, so
+	// that an entity changed once in its only later version is not
+	// reported. Zero defaults to DefaultMinChanges.
+	MinChanges int
+}
+
+func (o AgingOptions) withDefaults() AgingOptions {
+	if o.StaleMonths == 0 {
+		o.StaleMonths = DefaultStaleMonths
+	}
+	if o.ChurnRatio == 0 {
+		o.ChurnRatio = DefaultChurnRatio
+	}
+	if o.MinChanges == 0 {
+		o.MinChanges = DefaultMinChanges
+	}
+	return o
+}
+
+// EntityAge is the activity of one entity of the newest version across the
+// versions of a PRD.
+type EntityAge struct {
+	Section string `json:"section"`
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	PhaseID string `json:"phaseId,omitempty"`
+
+	// Implemented is set for requirements and user stories whose roadmap
+	// phase is completed.
+	Implemented bool `json:"implemented"`
+
+	// Created is the date of the first version with the entity, and Updated
+	// that of the last version that changed it, or Created if none did.
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+
+	// Versions counts the versions the entity appears in, and Changes the
+	// versions after the first that modified it.
+	Versions int     `json:"versions"`
+	Changes  int     `json:"changes"`
+	Churn    float64 `json:"churn"`
+
+	Stale     bool `json:"stale,omitempty"`
+	HighChurn bool `json:"highChurn,omitempty"`
+}
+
+// AgingReport is the age and churn of the entities of a PRD.
+type AgingReport struct {
+	Title       string      `json:"title,omitempty"`
+	AsOf        time.Time   `json:"asOf"`
+	StaleMonths int         `json:"staleMonths"`
+	ChurnRatio  float64     `json:"churnRatio"`
+	Versions    int         `json:"versions"`
+	Entities    []EntityAge `json:"entities"`
+
+	// Skipped lists versions that could not be loaded, with the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Aging measures how long ago each entity of the newest version was added
+// and last changed, and how often it changed, across versions in
+// chronological order. As in Build, versions without semantic changes are
+// not counted.
+//
+// Functional and non-functional requirements and user stories that are
+// not implemented, because their roadmap phase is not completed, are stale
+// when unchanged for StaleMonths. Any entity that changed in at least
+// ChurnRatio of the versions since it was added, and at least MinChanges
+// times, is high churn. Versions without a date, such as files without
+// metadata dates, leave the dates of their entities unknown, and entities
+// without a known update date are never stale.
+func Aging(versions []Version, opts AgingOptions) *AgingReport {
+	opts = opts.withDefaults()
+	report := &AgingReport{StaleMonths: opts.StaleMonths, ChurnRatio: opts.ChurnRatio}
+	if len(versions) == 0 {
+		report.AsOf = opts.AsOf
+		return report
+	}
+
+	byKey := map[string]*EntityAge{}
+	var prev *prd.Document
+	for _, v := range versions {
+		var changed map[string]bool
+		if prev != nil {
+			r := diff.PRD(prev, v.Document)
+			if r.Empty() {
+				continue
+			}
+			changed = map[string]bool{}
+			for _, c := range r.Changes {
+				if c.Kind == diff.Changed {
+					changed[entityKey(c.Section, c.ID, c.Title)] = true
+				}
+			}
+		}
+		prev = v.Document
+		report.Versions++
+
+		for _, e := range ageEntities(v.Document) {
+			key := entityKey(e.Section, e.ID, e.Title)
+			a, ok := byKey[key]
+			if !ok {
+				e.Created, e.Updated = v.Date, v.Date
+				byKey[key] = &e
+				a = &e
+			} else if changed[key] {
+				a.Changes++
+				a.Updated = v.Date
+			}
+			a.Versions++
+		}
+	}
+
+	last := versions[len(versions)-1].Document
+	report.Title = last.Metadata.Title
+	report.AsOf = opts.AsOf
+	if report.AsOf.IsZero() {
+		report.AsOf = versions[len(versions)-1].Date
+	}
+	completed := map[string]bool{}
+	for _, p := range last.Roadmap.Phases {
+		if p.Status == prd.PhaseStatusCompleted {
+			completed[p.ID] = true
+		}
+	}
+
+	for _, e := range ageEntities(last) {
+		a := byKey[entityKey(e.Section, e.ID, e.Title)]
+		a.Title, a.PhaseID = e.Title, e.PhaseID
+		if a.Versions > 1 {
+			a.Churn = float64(a.Changes) / float64(a.Versions-1)
+		}
+		a.HighChurn = a.Changes >= opts.MinChanges && a.Churn >= opts.ChurnRatio
+		if agingSections[a.Section] {
+			a.Implemented = completed[a.PhaseID]
+			a.Stale = !a.Implemented && !a.Updated.IsZero() && !report.AsOf.IsZero() &&
+				!a.Updated.AddDate(0, opts.StaleMonths, 0).After(report.AsOf)
+		}
+		report.Entities = append(report.Entities, *a)
+	}
+	return report
+}
+
+// agingSections are the sections whose entities are implemented by their
+// roadmap phase, and so can go stale.
+var agingSections = map[string]bool{
+	"Functional Requirements":     true,
+	"Non-Functional Requirements": true,
+	"User Stories":                true,
+}
+
+// entityKey matches entities by ID, or title when the ID is empty, as
+// package diff does.
+func entityKey(section, id, title string) string {
+	if id != "" {
+		return section + "\x00id:" + id
+	}
+	return section + "\x00title:" + title
+}
+
+// ageEntities lists the entities of doc that package diff compares, under
+// its section names.
+func ageEntities(doc *prd.Document) []EntityAge {
+	var out []EntityAge
+	for _, r := range doc.Requirements.Functional {
+		out = append(out, EntityAge{Section: "Functional Requirements", ID: r.ID, Title: r.Title, PhaseID: r.PhaseID})
+	}
+	for _, r := range doc.Requirements.NonFunctional {
+		out = append(out, EntityAge{Section: "Non-Functional Requirements", ID: r.ID, Title: r.Title, PhaseID: r.PhaseID})
+	}
+	for _, s := range doc.UserStories {
+		out = append(out, EntityAge{Section: "User Stories", ID: s.ID, Title: s.Title, PhaseID: s.PhaseID})
+	}
+	for _, p := range doc.Personas {
+		out = append(out, EntityAge{Section: "Personas", ID: p.ID, Title: p.Name})
+	}
+	for _, o := range doc.Objectives.OKRs {
+		out = append(out, EntityAge{Section: "Objectives", ID: o.Objective.ID, Title: o.Objective.Title})
+		for _, kr := range slices.Concat(o.Objective.KeyResults, o.KeyResults) {
+			out = append(out, EntityAge{Section: "Key Results", ID: kr.ID, Title: kr.Title})
+		}
+	}
+	for _, p := range doc.Roadmap.Phases {
+		out = append(out, EntityAge{Section: "Roadmap Phases", ID: p.ID, Title: p.Name})
+	}
+	return out
+}
+
+// Stale returns the stale entities, least recently changed first.
+func (r *AgingReport) Stale() []EntityAge {
+	var out []EntityAge
+	for _, e := range r.Entities {
+		if e.Stale {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Updated.Before(out[j].Updated) })
+	return out
+}
+
+// HighChurn returns the high-churn entities, most changed first.
+func (r *AgingReport) HighChurn() []EntityAge {
+	var out []EntityAge
+	for _, e := range r.Entities {
+		if e.HighChurn {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Churn != out[j].Churn {
+			return out[i].Churn > out[j].Churn
+		}
+		return out[i].Changes > out[j].Changes
+	})
+	return out
+}
+
+// ToMarkdown renders the report as a "## Requirements Aging" section with
+// tables of the stale and high-churn entities.
+func (r *AgingReport) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("## Requirements Aging\n\n")
+	if r.Title != "" {
+		sb.WriteString(fmt.Sprintf("**%s**, ", r.Title))
+	}
+	if r.Versions == 1 {
+		sb.WriteString("1 version")
+	} else {
+		sb.WriteString(fmt.Sprintf("%d versions", r.Versions))
+	}
+	if !r.AsOf.IsZero() {
+		sb.WriteString(fmt.Sprintf(", as of %s", r.AsOf.Format("2006-01-02")))
+	}
+	sb.WriteString(".\n\n")
+
+	stale := r.Stale()
+	sb.WriteString("### Stale Requirements\n\n")
+	sb.WriteString(fmt.Sprintf("Requirements and user stories not implemented and unchanged for %d months or more.\n\n", r.StaleMonths))
+	if len(stale) == 0 {
+		sb.WriteString("None.\n\n")
+	} else {
+		sb.WriteString("| ID | Title | Section | Phase | Last Changed | Months |\n")
+		sb.WriteString("|----|-------|---------|-------|--------------|--------|\n")
+		for _, e := range stale {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %d |\n",
+				cell(e.ID), cell(e.Title), e.Section, cell(e.PhaseID), e.Updated.Format("2006-01-02"), monthsBetween(e.Updated, r.AsOf)))
+		}
+		sb.WriteString("\n")
+	}
+
+	churn := r.HighChurn()
+	sb.WriteString("### High-Churn Entities\n\n")
+	sb.WriteString(fmt.Sprintf("Entities changed in %.0f%% or more of the versions since they were added.\n\n", r.ChurnRatio*100))
+	if len(churn) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		sb.WriteString("| ID | Title | Section | Changes | Versions Since Added | Churn |\n")
+		sb.WriteString("|----|-------|---------|---------|----------------------|-------|\n")
+		for _, e := range churn {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d | %.0f%% |\n",
+				cell(e.ID), cell(e.Title), e.Section, e.Changes, e.Versions-1, e.Churn*100))
+		}
+	}
+
+	if len(r.Skipped) > 0 {
+		sb.WriteString("\nSkipped versions:\n\n")
+		for _, s := range r.Skipped {
+			sb.WriteString("- " + s + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// monthsBetween returns the whole months from a to b.
+func monthsBetween(a, b time.Time) int {
+	n := 0
+	for !a.AddDate(0, n+1, 0).After(b) {
+		n++
+	}
+	return n
+}
+
+func cell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func agingVersion(t *testing.T, date, fr1, us1 string, completed bool) Version {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := prd.PhaseStatusPlanned
+	if completed {
+		status = prd.PhaseStatusCompleted
+	}
+	doc := &prd.Document{}
+	doc.Metadata.Title = "Checkout"
+	doc.Requirements.Functional = []prd.FunctionalRequirement{
+		{ID: "FR-1", Title: fr1, PhaseID: "p1"},
+		{ID: "FR-2", Title: "Passkeys", PhaseID: "p2"},
+	}
+	doc.UserStories = []prd.UserStory{{ID: "US-1", Title: us1, PhaseID: "p1"}}
+	doc.Roadmap.Phases = []prd.Phase{{ID: "p1", Status: status}, {ID: "p2"}}
+	return Version{Source: date, Date: d, Document: doc}
+}
+
+func TestAging(t *testing.T) {
+	versions := []Version{
+		agingVersion(t, "2025-01-01", "Guest checkout", "Pay", false),
+		agingVersion(t, "2025-02-01", "Guest checkout v2", "Pay", false),
+		// No semantic changes: not counted.
+		agingVersion(t, "2025-02-15", "Guest checkout v2", "Pay", false),
+		agingVersion(t, "2025-03-01", "Guest checkout v3", "Pay now", false),
+		agingVersion(t, "2025-04-01", "Guest checkout v4", "Pay now", true),
+	}
+	asOf, _ := time.Parse("2006-01-02", "2025-08-01")
+	r := Aging(versions, AgingOptions{AsOf: asOf})
+
+	if r.Versions != 4 {
+		t.Errorf("Versions = %d, want 4", r.Versions)
+	}
+	byID := map[string]EntityAge{}
+	for _, e := range r.Entities {
+		byID[e.ID] = e
+	}
+
+	fr1 := byID["FR-1"]
+	if fr1.Changes != 3 || fr1.Versions != 4 || fr1.Churn != 1 || !fr1.HighChurn || !fr1.Implemented || fr1.Stale {
+		t.Errorf("FR-1 = %+v", fr1)
+	}
+	if fr1.Title != "Guest checkout v4" || fr1.Updated.Format("2006-01-02") != "2025-04-01" {
+		t.Errorf("FR-1 title/updated = %q %s", fr1.Title, fr1.Updated.Format("2006-01-02"))
+	}
+
+	// Unchanged since it was added in January, in an unfinished phase.
+	fr2 := byID["FR-2"]
+	if fr2.Changes != 0 || fr2.HighChurn || fr2.Implemented || !fr2.Stale {
+		t.Errorf("FR-2 = %+v", fr2)
+	}
+
+	// Changed once in three later versions.
+	us1 := byID["US-1"]
+	if us1.Changes != 1 || us1.HighChurn || us1.Stale {
+		t.Errorf("US-1 = %+v", us1)
+	}
+
+	// The phase changed status once, below the minimum number of changes.
+	if p1 := byID["p1"]; p1.Changes != 1 || p1.HighChurn || p1.Stale {
+		t.Errorf("p1 = %+v", p1)
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"**Checkout**, 4 versions, as of 2025-08-01.",
+		"| FR-2 | Passkeys | Functional Requirements | p2 | 2025-01-01 | 7 |",
+		"| FR-1 | Guest checkout v4 | Functional Requirements | 3 | 3 | 100% |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestAgingEmpty(t *testing.T) {
+	r := Aging(nil, AgingOptions{})
+	if len(r.Entities) != 0 || r.StaleMonths != DefaultStaleMonths || r.ChurnRatio != DefaultChurnRatio {
+		t.Errorf("Aging(nil) = %+v", r)
+	}
+}
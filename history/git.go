@@ -0,0 +1,121 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Field and record separators in the git log format.
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Commit is a commit that changed a file.
+type Commit struct {
+	Hash   string
+	Author string
+	Email  string
+	Date   time.Time
+
+	// File is the path of the file as of the commit, relative to the
+	// repository root.
+	File string
+}
+
+// Short returns the abbreviated commit hash.
+func (c Commit) Short() string {
+	return c.Hash[:min(len(c.Hash), 7)]
+}
+
+// Log returns the commits that changed the file at path, oldest first,
+// following renames. It runs git in the file's directory, so path must be
+// inside a git work tree.
+func Log(ctx context.Context, path string) ([]Commit, error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	out, err := git(ctx, dir, "log", "--follow", "--name-only",
+		"--format="+recordSep+"%H"+fieldSep+"%an"+fieldSep+"%ae"+fieldSep+"%cI", "--", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(out), recordSep) {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], fieldSep)
+		if len(fields) != 4 || len(lines) < 2 {
+			continue
+		}
+		c := Commit{Hash: fields[0], Author: fields[1], Email: fields[2], File: strings.TrimSpace(lines[len(lines)-1])}
+		if c.Date, err = time.Parse(time.RFC3339, fields[3]); err != nil {
+			return nil, fmt.Errorf("parsing date of commit %s: %w", c.Short(), err)
+		}
+		commits = append(commits, c)
+	}
+
+	// git log lists the newest commit first.
+	slices.Reverse(commits)
+	return commits, nil
+}
+
+// Show returns the file changed by c as of the commit. path is the file
+// passed to Log.
+func Show(ctx context.Context, path string, c Commit) ([]byte, error) {
+	dir := filepath.Dir(path)
+	return git(ctx, dir, "show", c.Hash+":"+c.File)
+}
+
+// FromGit loads every committed version of the PRD at path, oldest first,
+// following renames. It runs git in the file's directory, so path must be
+// inside a git work tree. Versions that cannot be parsed are returned as
+// skipped; uncommitted changes are not included.
+func FromGit(ctx context.Context, path string, opts Options) ([]Version, []string, error) {
+	commits, err := Log(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var versions []Version
+	var skipped []string
+	for _, c := range commits {
+		source := c.Short() + ":" + c.File
+		data, err := Show(ctx, path, c)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", source, err))
+			continue
+		}
+		doc, err := decode(data, c.File, opts)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", source, err))
+			continue
+		}
+		versions = append(versions, Version{Source: source, Commit: c.Short(), Author: c.Author, Date: c.Date, Document: doc})
+	}
+	if len(versions) == 0 && len(skipped) == 0 {
+		return nil, nil, fmt.Errorf("%s has no committed versions", path)
+	}
+	return versions, skipped, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
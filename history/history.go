@@ -0,0 +1,227 @@
+// Package history builds the revision history of a PRD from its earlier
+// versions, kept either in a git repository or as versioned files in a
+// directory. Each version is compared with the one before it using package
+// diff, giving a changelog of added, modified, and removed entities and of
+// status transitions for every version.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/diff"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// Options configures how versions are loaded.
+type Options struct {
+	// Lenient recovers from field type errors, such as dates in an old
+	// format, instead of skipping the version.
+	Lenient bool
+}
+
+// Version is one loaded version of a PRD.
+type Version struct {
+	// Source is the file path, or "<commit>:<path>" for git versions.
+	Source string
+	Commit string
+	Author string
+
+	// Date is the commit date for git versions and the document's
+	// updatedAt, or createdAt, for files.
+	Date time.Time
+
+	Document *prd.Document
+}
+
+// Transition is a change of status.
+type Transition struct {
+	Section string `json:"section"`
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+// Entry is the changelog of one version.
+type Entry struct {
+	Version string    `json:"version,omitempty"`
+	Commit  string    `json:"commit,omitempty"`
+	Author  string    `json:"author,omitempty"`
+	Date    time.Time `json:"date"`
+	Source  string    `json:"source"`
+
+	// Initial marks the oldest version, which has nothing to compare with.
+	Initial bool `json:"initial,omitempty"`
+
+	Changes     []diff.Change `json:"changes,omitempty"`
+	Transitions []Transition  `json:"transitions,omitempty"`
+}
+
+// History is the changelog of a PRD, newest version first.
+type History struct {
+	Title   string  `json:"title,omitempty"`
+	Entries []Entry `json:"entries"`
+
+	// Skipped lists versions that could not be loaded, with the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Build compares each version with the one before it. versions must be in
+// chronological order. Versions without semantic changes, such as
+// formatting-only commits, are left out.
+func Build(versions []Version) *History {
+	h := &History{}
+	for i, v := range versions {
+		e := Entry{
+			Version: v.Document.Metadata.Version,
+			Commit:  v.Commit,
+			Author:  v.Author,
+			Date:    v.Date,
+			Source:  v.Source,
+		}
+		if i == 0 {
+			e.Initial = true
+		} else {
+			report := diff.PRD(versions[i-1].Document, v.Document)
+			if report.Empty() {
+				continue
+			}
+			e.Changes = report.Changes
+			e.Transitions = transitions(report)
+		}
+		h.Entries = append(h.Entries, e)
+	}
+	if n := len(versions); n > 0 {
+		h.Title = versions[n-1].Document.Metadata.Title
+	}
+	for i, j := 0, len(h.Entries)-1; i < j; i, j = i+1, j-1 {
+		h.Entries[i], h.Entries[j] = h.Entries[j], h.Entries[i]
+	}
+	return h
+}
+
+// transitions collects the status changes in a diff report.
+func transitions(r *diff.Report) []Transition {
+	var out []Transition
+	for _, f := range r.Metadata {
+		if f.Field == "status" {
+			out = append(out, Transition{Section: "Document", From: f.Old, To: f.New})
+		}
+	}
+	for _, c := range r.Changes {
+		for _, f := range c.Fields {
+			if f.Field == "status" {
+				out = append(out, Transition{Section: c.Section, ID: c.ID, Title: c.Title, From: f.Old, To: f.New})
+			}
+		}
+	}
+	return out
+}
+
+// FromDir loads every PRD (*.prd.json, *.prd.yaml) directly inside dir and
+// orders them by metadata version, then by date and file name. Files that
+// cannot be loaded are returned as skipped.
+func FromDir(dir string, opts Options) ([]Version, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading directory: %w", err)
+	}
+	var versions []Version
+	var skipped []string
+	for _, de := range entries {
+		name := strings.ToLower(de.Name())
+		if de.IsDir() || !(strings.HasSuffix(name, ".prd.json") || strings.HasSuffix(name, ".prd.yaml") || strings.HasSuffix(name, ".prd.yml")) {
+			continue
+		}
+		path := filepath.Join(dir, de.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading file: %w", err)
+		}
+		doc, err := decode(data, path, opts)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		date := doc.Metadata.UpdatedAt
+		if date.IsZero() {
+			date = doc.Metadata.CreatedAt
+		}
+		versions = append(versions, Version{Source: path, Date: date, Document: doc})
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, b := versions[i], versions[j]
+		if c := CompareVersions(a.Document.Metadata.Version, b.Document.Metadata.Version); c != 0 {
+			return c < 0
+		}
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.Source < b.Source
+	})
+	return versions, skipped, nil
+}
+
+// CompareVersions compares dotted version strings such as "1.10.0" and
+// "v1.9", numerically where both parts are numbers. It returns -1, 0, or 1.
+func CompareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(strings.ToLower(a), "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(strings.ToLower(b), "v"), ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		nx, errx := strconv.Atoi(x)
+		ny, erry := strconv.Atoi(y)
+		switch {
+		case errx == nil && erry == nil:
+			if nx != ny {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// decode parses a JSON or YAML PRD; name selects the format.
+func decode(data []byte, name string, opts Options) (*prd.Document, error) {
+	if yamlconv.FormatFromPath(name) == yamlconv.FormatYAML {
+		converted, err := yamlconv.ToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing YAML: %w", err)
+		}
+		data = converted
+	}
+	var doc prd.Document
+	if opts.Lenient {
+		if _, err := lenient.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing JSON: %w", err)
+		}
+		return &doc, nil
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	return &doc, nil
+}
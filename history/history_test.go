@@ -0,0 +1,70 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFromDirAndBuild(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"checkout-v10.prd.json": `{"metadata":{"title":"Checkout","version":"1.10.0","status":"approved"},
+			"requirements":{"functional":[{"id":"FR-1","title":"One-page checkout","priority":"must"},{"id":"FR-2","title":"Passkeys"}]}}`,
+		"checkout-v9.prd.json": `{"metadata":{"title":"Checkout","version":"1.9.0","status":"review"},
+			"requirements":{"functional":[{"id":"FR-1","title":"One-page checkout","priority":"should"}]}}`,
+		"checkout-v1.prd.yaml": "metadata:\n  title: Checkout\n  version: 1.0.0\n  status: draft\n" +
+			"requirements:\n  functional:\n    - id: FR-1\n      title: One-page checkout\n      priority: should\n",
+		"broken.prd.json": `{"metadata":`,
+		"notes.md":        "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, skipped, err := FromDir(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 || len(skipped) != 1 || versions[2].Document.Metadata.Version != "1.10.0" {
+		t.Fatalf("versions = %d, skipped = %v", len(versions), skipped)
+	}
+
+	h := Build(versions)
+	if len(h.Entries) != 3 || h.Entries[0].Version != "1.10.0" || !h.Entries[2].Initial {
+		t.Fatalf("entries = %+v", h.Entries)
+	}
+	md := h.ToMarkdown()
+	for _, want := range []string{
+		"### 1.10.0\n\n**Added**\n\n- Functional Requirements: **FR-2** Passkeys\n",
+		"**Modified**\n\n- Functional Requirements: **FR-1** One-page checkout (`priority`)\n",
+		"- Document — review → approved\n",
+		"### 1.9.0\n\n**Status transitions**\n\n- Document — draft → review\n",
+		"### 1.0.0\n\nInitial version.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.9.0", "1.10.0", -1},
+		{"v2", "1.10.0", 1},
+		{"1.0", "1.0.0", -1},
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0-rc1", "1.0.0-rc2", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
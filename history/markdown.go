@@ -0,0 +1,125 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/diff"
+)
+
+// ToMarkdown renders the history as a "## Revision History" section with
+// one subsection per version, newest first.
+func (h *History) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("## Revision History\n\n")
+	if len(h.Entries) == 0 {
+		sb.WriteString("No earlier versions found.\n")
+		return sb.String()
+	}
+	for _, e := range h.Entries {
+		sb.WriteString("### " + e.heading() + "\n\n")
+		if by := e.attribution(); by != "" {
+			sb.WriteString("*" + by + "*\n\n")
+		}
+		if e.Initial {
+			sb.WriteString("Initial version.\n\n")
+			continue
+		}
+		if len(e.Changes) == 0 && len(e.Transitions) == 0 {
+			sb.WriteString("Metadata changes only.\n\n")
+		}
+		for _, k := range []struct {
+			kind  diff.Kind
+			label string
+		}{{diff.Added, "Added"}, {diff.Changed, "Modified"}, {diff.Removed, "Removed"}} {
+			var items []string
+			for _, c := range e.Changes {
+				if c.Kind == k.kind {
+					items = append(items, changeItem(c))
+				}
+			}
+			writeList(&sb, k.label, items)
+		}
+		var items []string
+		for _, t := range e.Transitions {
+			items = append(items, transitionItem(t))
+		}
+		writeList(&sb, "Status transitions", items)
+	}
+	return sb.String()
+}
+
+// heading names the entry by version and date, e.g. "1.2.0 (2026-03-01)".
+func (e Entry) heading() string {
+	label := e.Version
+	if label == "" {
+		label = e.Commit
+	}
+	if label == "" {
+		label = e.Source
+	}
+	if !e.Date.IsZero() {
+		label += " (" + e.Date.Format("2006-01-02") + ")"
+	}
+	return label
+}
+
+func (e Entry) attribution() string {
+	switch {
+	case e.Commit != "" && e.Author != "":
+		return fmt.Sprintf("Commit %s by %s", e.Commit, e.Author)
+	case e.Commit != "":
+		return "Commit " + e.Commit
+	}
+	return ""
+}
+
+// changeItem describes a change as "Section: **ID** Title", with the
+// changed fields of modified entities.
+func changeItem(c diff.Change) string {
+	s := c.Section + ": " + entityLabel(c.ID, c.Title)
+	if len(c.Fields) > 0 {
+		fields := make([]string, len(c.Fields))
+		for i, f := range c.Fields {
+			fields[i] = "`" + f.Field + "`"
+		}
+		s += " (" + strings.Join(fields, ", ") + ")"
+	}
+	return s
+}
+
+func transitionItem(t Transition) string {
+	s := t.Section
+	if label := entityLabel(t.ID, t.Title); label != "" {
+		s += ": " + label
+	}
+	from, to := t.From, t.To
+	if from == "" {
+		from = "none"
+	}
+	if to == "" {
+		to = "none"
+	}
+	return s + " — " + from + " → " + to
+}
+
+func entityLabel(id, title string) string {
+	switch {
+	case id != "" && title != "":
+		return "**" + id + "** " + title
+	case id != "":
+		return "**" + id + "**"
+	}
+	return title
+}
+
+func writeList(sb *strings.Builder, label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	sb.WriteString("**" + label + "**\n\n")
+	for _, item := range items {
+		sb.WriteString("- " + item + "\n")
+	}
+	sb.WriteString("\n")
+}
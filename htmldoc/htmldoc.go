@@ -0,0 +1,696 @@
+// Package htmldoc builds the standalone HTML pages produced by the document
+// renderers. A page is a single file with its stylesheet embedded, a table
+// of contents, collapsible sections with anchor links, and print styles that
+// expand every section.
+//
+// Renderers describe a page with Page and fill each section with a Builder,
+// which escapes all text it is given. Builders also record their content as
+// Blocks, so other formats such as DOCX can lay out the same page.
+package htmldoc
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/richtext"
+)
+
+//go:embed style.css
+var styleCSS string
+
+// katexCDN is the KaTeX dist directory that pages with math load by default.
+// The page template pins the subresource integrity hashes of its files, so
+// they change together.
+const katexCDN = "https://cdn.jsdelivr.net/npm/katex@0.16.11/dist"
+
+// Options contains rendering options common to the HTML renderers.
+type Options struct {
+	// CustomCSS is appended after the built-in stylesheet, so its rules
+	// take precedence.
+	CustomCSS string
+
+	// IncludeComments shows unresolved review comments as margin notes.
+	IncludeComments bool
+
+	// LinkGlossary links the first use of each glossary term to its entry
+	// (default: true).
+	LinkGlossary *bool
+
+	// KaTeXURL is the base URL of a local or self-hosted KaTeX dist
+	// directory for pages with math (default: KaTeX 0.16.11 from jsdelivr).
+	KaTeXURL string
+}
+
+// GlossaryLinks reports whether the options link glossary terms.
+func (o *Options) GlossaryLinks() bool {
+	return o == nil || o.LinkGlossary == nil || *o.LinkGlossary
+}
+
+// Field is a labeled value shown in the page header or a definition list.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Section is a top-level, collapsible section of a page.
+type Section struct {
+	ID     string
+	Title  string
+	Body   template.HTML
+	Blocks []Block
+	Notes  []Note
+}
+
+// BlockKind identifies the Builder method that wrote a Block.
+type BlockKind string
+
+const (
+	BlockHeading   BlockKind = "heading"
+	BlockParagraph BlockKind = "paragraph"
+	BlockLabeled   BlockKind = "labeled"
+	BlockList      BlockKind = "list"
+	BlockFields    BlockKind = "fields"
+	BlockTable     BlockKind = "table"
+	BlockLink      BlockKind = "link"
+	BlockLinks     BlockKind = "links"
+	BlockGallery   BlockKind = "gallery"
+)
+
+// Block is one piece of section content, unescaped. Only the fields used by
+// its kind are set: Text for headings, paragraphs, and links; Label and Text
+// for labeled paragraphs; Label and Items for lists; Fields; Header and Rows
+// for tables; Href for links; and Label and Fields for link lists, with each
+// field's Label as the link text and Value as its href, empty when unlinked;
+// and Figures for galleries.
+type Block struct {
+	Kind    BlockKind
+	Label   string
+	Text    string
+	Items   []string
+	Fields  []Field
+	Header  []string
+	Rows    [][]string
+	Href    string
+	Figures []Figure
+}
+
+// Figure is an image in a gallery, with a caption and optional links shown
+// below it, such as the flows a wireframe belongs to. Each link's Label is
+// its text and Value its href; LinksLabel introduces them.
+type Figure struct {
+	Src        string
+	Title      string
+	Caption    string
+	LinksLabel string
+	Links      []Field
+}
+
+// Note is a review comment shown in the margin beside a section, with the
+// replies in its thread.
+type Note struct {
+	ID      string
+	Author  string
+	Date    string
+	Path    string
+	Text    string
+	Replies []Note
+}
+
+// Page is a standalone HTML document.
+type Page struct {
+	Kind      string // Document type shown above the title, e.g. "Product Requirements"
+	Title     string
+	Summary   string                // Optional lead paragraph
+	Banner    string                // Optional notice shown prominently above the title, e.g. supersession
+	Meta      []Field               // Header fields; empty values are skipped
+	Links     []common.ExternalLink // Document's external refs, shown below Meta
+	Sections  []Section
+	Notes     []Note // Notes for parts of the document with no section
+	CustomCSS string
+
+	// KaTeXURL, when set, loads KaTeX for math from this dist directory
+	// instead of the CDN, e.g. "katex" for a copy next to the page.
+	KaTeXURL string
+
+	// Glossary, when set, has the first use of each term in the sections
+	// other than "Glossary" linked to the term's entry, as written by
+	// Builder.Glossary.
+	Glossary []common.GlossaryTerm
+
+	ids map[string]int
+}
+
+// NewPage returns an empty page.
+func NewPage(kind, title string) *Page {
+	return &Page{Kind: kind, Title: title}
+}
+
+// AddSection appends a section with the builder's content. Sections with no
+// content are skipped.
+func (p *Page) AddSection(title string, b *Builder) {
+	if b == nil || b.Len() == 0 {
+		return
+	}
+	p.Sections = append(p.Sections, Section{ID: p.uniqueID(title), Title: title, Body: b.HTML(), Blocks: b.blocks})
+}
+
+// AddComments attaches the unresolved comment threads as margin notes.
+// sections maps the top-level field of a comment path, such as "risks", to
+// the title of the section that renders it. Threads without a rendered
+// section are listed below the page header.
+func (p *Page) AddComments(comments []common.Comment, sections map[string]string) {
+	for _, t := range common.UnresolvedThreads(comments) {
+		n := noteFromComment(t.Comment)
+		for _, r := range t.Replies {
+			n.Replies = append(n.Replies, noteFromComment(r))
+		}
+		i := slices.IndexFunc(p.Sections, func(s Section) bool {
+			return s.Title == sections[common.CommentPathRoot(t.Path)]
+		})
+		if i < 0 {
+			p.Notes = append(p.Notes, n)
+			continue
+		}
+		p.Sections[i].Notes = append(p.Sections[i].Notes, n)
+	}
+}
+
+func noteFromComment(c common.Comment) Note {
+	return Note{ID: c.ID, Author: c.Author, Date: Date(c.CreatedAt), Path: c.Path, Text: c.Text}
+}
+
+func (p *Page) uniqueID(title string) string {
+	if p.ids == nil {
+		p.ids = map[string]int{}
+	}
+	return uniqueSlug(p.ids, title)
+}
+
+var pageTmpl = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+{{.CSS}}</style>
+{{- if .CustomCSS}}
+<style>
+{{.CustomCSS}}
+</style>
+{{- end}}
+{{- if .Math}}
+{{- if .KaTeXURL}}
+<link rel="stylesheet" href="{{.KaTeXURL}}/katex.min.css">
+<script defer src="{{.KaTeXURL}}/katex.min.js"></script>
+<script defer src="{{.KaTeXURL}}/contrib/auto-render.min.js"
+{{- else}}
+<link rel="stylesheet" href="{{.KaTeXCDN}}/katex.min.css" integrity="sha384-nB0miv6/jRmo5UMMR1wu3Gz6NLsoTkbqJghGIsx//Rlm+ZU03BU6SQNC66uf4l5+" crossorigin="anonymous">
+<script defer src="{{.KaTeXCDN}}/katex.min.js" integrity="sha384-7zkQWkzuo3B5mTepMUcHkMB5jZaolc2xDwL6VFqjFALcbeS9Ggm/Yr2r3Dy4lfFg" crossorigin="anonymous"></script>
+<script defer src="{{.KaTeXCDN}}/contrib/auto-render.min.js" integrity="sha384-43gviWU0YVjaDtb/GhzOouOXtZMP/7XUzwPTstBeZFe/+rCMvRwr4yROQP43s0Xk" crossorigin="anonymous"
+{{- end}}
+  onload="document.querySelectorAll('.math').forEach(function (e) { renderMathInElement(e, {throwOnError: false}); });"></script>
+{{- end}}
+</head>
+<body>
+<header class="doc-header">
+{{- if .Banner}}
+<p class="doc-banner" role="note">{{.Banner}}</p>
+{{- end}}
+{{- if .Kind}}
+<p class="doc-kind">{{.Kind}}</p>
+{{- end}}
+<h1>{{.Title}}</h1>
+{{- if .Summary}}
+<p class="doc-summary">{{.Summary}}</p>
+{{- end}}
+{{- if .Meta}}
+<dl class="doc-meta">
+{{- range .Meta}}
+<div><dt>{{.Label}}</dt><dd>{{.Value}}</dd></div>
+{{- end}}
+</dl>
+{{- end}}
+{{- if .Links}}
+<p class="doc-links"><strong>External links:</strong> {{range $i, $l := .Links}}{{if $i}}, {{end}}{{if $l.URL}}<a href="{{$l.URL}}">{{$l.Text}}</a>{{else}}{{$l.Text}}{{end}}{{end}}</p>
+{{- end}}
+</header>
+{{- if .Notes}}
+<aside class="notes notes-page" aria-label="Review comments">
+{{- range .Notes}}{{template "note" .}}{{end}}
+</aside>
+{{- end}}
+{{- if .Sections}}
+<nav class="toc" aria-label="Contents">
+<h2>Contents</h2>
+<ol>
+{{- range .Sections}}
+<li><a href="#{{.ID}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</nav>
+{{- end}}
+<main>
+{{- range .Sections}}
+<section id="{{.ID}}">
+<details open>
+<summary><h2>{{.Title}} <a class="anchor" href="#{{.ID}}" aria-label="Link to {{.Title}}">#</a></h2></summary>
+{{- if .Notes}}
+<aside class="notes" aria-label="Review comments">
+{{- range .Notes}}{{template "note" .}}{{end}}
+</aside>
+{{- end}}
+{{.Body}}
+</details>
+</section>
+{{- end}}
+</main>
+<script>
+window.addEventListener("beforeprint", function () {
+  document.querySelectorAll("details").forEach(function (d) { d.open = true; });
+});
+</script>
+</body>
+</html>
+{{- define "note"}}
+<div class="note" id="comment-{{.ID}}">
+<p class="note-meta">{{.ID}} · {{.Author}}{{if .Date}} · {{.Date}}{{end}}{{if .Path}} · <code>{{.Path}}</code>{{end}}</p>
+<p>{{.Text}}</p>
+{{- range .Replies}}
+<div class="note-reply">
+<p class="note-meta">{{.ID}} · {{.Author}}{{if .Date}} · {{.Date}}{{end}}</p>
+<p>{{.Text}}</p>
+</div>
+{{- end}}
+</div>
+{{- end}}
+`))
+
+// Render returns the page as a complete HTML document.
+func (p *Page) Render() ([]byte, error) {
+	var meta []Field
+	for _, f := range p.Meta {
+		if strings.TrimSpace(f.Value) != "" {
+			meta = append(meta, f)
+		}
+	}
+	data := struct {
+		*Page
+		Meta      []Field
+		Sections  []Section
+		Math      bool
+		KaTeXURL  string
+		KaTeXCDN  string
+		CSS       template.CSS
+		CustomCSS template.CSS
+	}{
+		Page:      p,
+		Meta:      meta,
+		Sections:  p.linkedSections(),
+		Math:      p.hasMath(),
+		KaTeXURL:  strings.TrimSuffix(p.KaTeXURL, "/"),
+		KaTeXCDN:  katexCDN,
+		CSS:       template.CSS(styleCSS),    //nolint:gosec // embedded stylesheet
+		CustomCSS: template.CSS(p.CustomCSS), //nolint:gosec // supplied by the document author
+	}
+	var buf bytes.Buffer
+	if err := pageTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering HTML page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// glossaryLinkSkip are the elements whose text never gets glossary links.
+var glossaryLinkSkip = map[string]bool{
+	"a": true, "code": true, "pre": true, "dt": true, "th": true, "summary": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var tagPattern = regexp.MustCompile(`<(/?)([a-zA-Z0-9]+)[^>]*>`)
+
+// linkedSections returns the sections with the first use of each glossary
+// term linked to its entry, leaving p unchanged.
+func (p *Page) linkedSections() []Section {
+	l := common.NewGlossaryLinker(p.Glossary)
+	if l.Done() {
+		return p.Sections
+	}
+	link := func(m, anchor string) string { return `<a class="term" href="#` + anchor + `">` + m + "</a>" }
+	sections := slices.Clone(p.Sections)
+	for i, sec := range sections {
+		if sec.Title == "Glossary" {
+			continue
+		}
+		body := string(sec.Body)
+		var sb strings.Builder
+		var skipped []string // open elements whose text is skipped
+		last := 0
+		for _, m := range tagPattern.FindAllStringSubmatchIndex(body, -1) {
+			if len(skipped) == 0 {
+				sb.WriteString(l.Link(body[last:m[0]], link))
+			} else {
+				sb.WriteString(body[last:m[0]])
+			}
+			tag, name := body[m[0]:m[1]], strings.ToLower(body[m[4]:m[5]])
+			sb.WriteString(tag)
+			last = m[1]
+			switch {
+			case m[3] > m[2]: // closing tag
+				if n := len(skipped); n > 0 && skipped[n-1] == name {
+					skipped = skipped[:n-1]
+				}
+			case len(skipped) > 0 && name == skipped[len(skipped)-1],
+				glossaryLinkSkip[name], strings.Contains(tag, `class="math `):
+				skipped = append(skipped, name)
+			}
+		}
+		sb.WriteString(l.Link(body[last:], link))
+		sections[i].Body = template.HTML(sb.String()) //nolint:gosec // links added to escaped HTML
+		if l.Done() {
+			break
+		}
+	}
+	return sections
+}
+
+// hasMath reports whether a section has math, for which the page loads
+// KaTeX to typeset it. Without network access the TeX source is shown.
+func (p *Page) hasMath() bool {
+	for _, s := range p.Sections {
+		if strings.Contains(string(s.Body), `class="math `) {
+			return true
+		}
+	}
+	return false
+}
+
+// Builder accumulates the escaped HTML of one section. Methods given only
+// empty content write nothing, so optional fields can be passed without
+// checks.
+type Builder struct {
+	sb     strings.Builder
+	blocks []Block
+	prefix string
+	ids    map[string]int
+}
+
+// NewBuilder returns a builder whose subheading anchors start with prefix.
+func NewBuilder(prefix string) *Builder {
+	return &Builder{prefix: Slug(prefix), ids: map[string]int{}}
+}
+
+// Len returns the number of bytes written.
+func (b *Builder) Len() int {
+	return b.sb.Len()
+}
+
+// Blocks returns the content written so far.
+func (b *Builder) Blocks() []Block {
+	return b.blocks
+}
+
+// HTML returns the content written so far.
+func (b *Builder) HTML() template.HTML {
+	return template.HTML(b.sb.String()) //nolint:gosec // built from escaped text
+}
+
+// Heading writes a subheading with an anchor link.
+func (b *Builder) Heading(text string) {
+	if text == "" {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockHeading, Text: text})
+	id := uniqueSlug(b.ids, b.prefix+"-"+text)
+	fmt.Fprintf(&b.sb, "<h3 id=\"%s\">%s <a class=\"anchor\" href=\"#%s\" aria-label=\"Link to %s\">#</a></h3>\n",
+		id, esc(text), id, esc(text))
+}
+
+// Paragraph writes text as a paragraph, keeping its line breaks.
+func (b *Builder) Paragraph(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockParagraph, Text: text})
+	b.sb.WriteString(string(richtext.HTML(text)))
+}
+
+// Labeled writes a paragraph introduced by a bold label.
+func (b *Builder) Labeled(label, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockLabeled, Label: label, Text: text})
+	if richtext.HasBlocks(text) {
+		b.sb.WriteString("<p><strong>" + esc(label) + ":</strong></p>\n" + string(richtext.HTML(text)))
+		return
+	}
+	b.sb.WriteString("<p><strong>" + esc(label) + ":</strong> " + string(richtext.Inline(text)) + "</p>\n")
+}
+
+// List writes a bulleted list, optionally introduced by a bold label.
+func (b *Builder) List(label string, items []string) {
+	var kept, lis []string
+	for _, it := range items {
+		if strings.TrimSpace(it) != "" {
+			kept = append(kept, it)
+			lis = append(lis, "<li>"+string(richtext.Inline(it))+"</li>")
+		}
+	}
+	if len(lis) == 0 {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockList, Label: label, Items: kept})
+	if label != "" {
+		b.sb.WriteString("<p><strong>" + esc(label) + ":</strong></p>\n")
+	}
+	b.sb.WriteString("<ul>\n" + strings.Join(lis, "\n") + "\n</ul>\n")
+}
+
+// Fields writes a definition list of the fields that have values.
+func (b *Builder) Fields(fields ...Field) {
+	var kept []Field
+	var rows []string
+	for _, f := range fields {
+		if strings.TrimSpace(f.Value) != "" {
+			kept = append(kept, f)
+			rows = append(rows, "<div><dt>"+esc(f.Label)+"</dt><dd>"+string(richtext.Inline(f.Value))+"</dd></div>")
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockFields, Fields: kept})
+	b.sb.WriteString("<dl class=\"fields\">\n" + strings.Join(rows, "\n") + "\n</dl>\n")
+}
+
+// Table writes a table. Nothing is written when there are no rows.
+func (b *Builder) Table(header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockTable, Header: header, Rows: rows})
+	b.sb.WriteString("<div class=\"table-wrap\"><table>\n<thead><tr>")
+	for _, h := range header {
+		b.sb.WriteString("<th>" + esc(h) + "</th>")
+	}
+	b.sb.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range rows {
+		b.sb.WriteString("<tr>")
+		for _, cell := range row {
+			b.sb.WriteString("<td>" + cellHTML(cell) + "</td>")
+		}
+		b.sb.WriteString("</tr>\n")
+	}
+	b.sb.WriteString("</tbody>\n</table></div>\n")
+}
+
+// Glossary writes terms as a definition list, with acronyms after the term.
+// Each term's <dt> has its anchor from common.GlossaryAnchors.
+func (b *Builder) Glossary(terms []common.GlossaryTerm) {
+	var kept []Field
+	var rows []string
+	anchors := common.GlossaryAnchors(terms)
+	for i, g := range terms {
+		if strings.TrimSpace(g.Definition) == "" {
+			continue
+		}
+		term := g.Term
+		if g.Acronym != "" {
+			term += " (" + g.Acronym + ")"
+		}
+		kept = append(kept, Field{Label: term, Value: g.Definition})
+		rows = append(rows, "<div><dt id=\""+anchors[i]+"\">"+esc(term)+"</dt><dd>"+string(richtext.Inline(g.Definition))+"</dd></div>")
+	}
+	if len(rows) == 0 {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockFields, Fields: kept})
+	b.sb.WriteString("<dl class=\"fields\">\n" + strings.Join(rows, "\n") + "\n</dl>\n")
+}
+
+// Link writes a paragraph linking to href, labeled with text. Hrefs with a
+// scheme other than http, https, or mailto are written as plain text.
+func (b *Builder) Link(text, href string) {
+	if href == "" {
+		return
+	}
+	if text == "" {
+		text = href
+	}
+	if !safeURL(href) {
+		b.Paragraph(text + " (" + href + ")")
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockLink, Text: text, Href: href})
+	b.sb.WriteString("<p><a href=\"" + esc(href) + "\">" + esc(text) + "</a></p>\n")
+}
+
+// Gallery writes figures as a grid of thumbnails, each linking to its full
+// image. Figures whose Src is not an http, https, relative, or data:image
+// URL are skipped.
+func (b *Builder) Gallery(figures []Figure) {
+	var kept []Figure
+	var items []string
+	for _, f := range figures {
+		if f.Src == "" || !safeURL(f.Src) && !strings.HasPrefix(f.Src, "data:image/") {
+			continue
+		}
+		kept = append(kept, f)
+		var sb strings.Builder
+		sb.WriteString("<figure><a href=\"" + esc(f.Src) + "\"><img src=\"" + esc(f.Src) + "\" alt=\"" + esc(f.Title) + "\" loading=\"lazy\"></a>")
+		sb.WriteString("<figcaption><strong>" + esc(f.Title) + "</strong>")
+		if f.Caption != "" {
+			sb.WriteString("<br>" + esc(f.Caption))
+		}
+		var links []string
+		for _, l := range f.Links {
+			if l.Value == "" || !safeURL(l.Value) {
+				links = append(links, esc(l.Label))
+				continue
+			}
+			links = append(links, "<a href=\""+esc(l.Value)+"\">"+esc(l.Label)+"</a>")
+		}
+		if len(links) > 0 {
+			label := ""
+			if f.LinksLabel != "" {
+				label = esc(f.LinksLabel) + ": "
+			}
+			sb.WriteString("<br><span class=\"figure-links\">" + label + strings.Join(links, ", ") + "</span>")
+		}
+		sb.WriteString("</figcaption></figure>")
+		items = append(items, sb.String())
+	}
+	if len(kept) == 0 {
+		return
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockGallery, Figures: kept})
+	b.sb.WriteString("<div class=\"gallery\">\n" + strings.Join(items, "\n") + "\n</div>\n")
+}
+
+// ExternalRefs writes refs as a paragraph of comma-separated links
+// introduced by a bold label, usually the ID of the entity they belong to.
+// urlTemplates links refs that are IDs; see common.ExternalRefs.Links.
+func (b *Builder) ExternalRefs(label string, refs common.ExternalRefs, urlTemplates map[string]string) {
+	if len(refs) == 0 {
+		return
+	}
+	var fields []Field
+	var parts []string
+	for _, l := range refs.Links(urlTemplates) {
+		if l.URL == "" || !safeURL(l.URL) {
+			fields = append(fields, Field{Label: l.Text})
+			parts = append(parts, esc(l.Text))
+			continue
+		}
+		fields = append(fields, Field{Label: l.Text, Value: l.URL})
+		parts = append(parts, "<a href=\""+esc(l.URL)+"\">"+esc(l.Text)+"</a>")
+	}
+	b.blocks = append(b.blocks, Block{Kind: BlockLinks, Label: label, Fields: fields})
+	b.sb.WriteString("<p><strong>" + esc(label) + ":</strong> " + strings.Join(parts, ", ") + "</p>\n")
+}
+
+// safeURL reports whether href is relative or uses an http, https, or mailto
+// scheme.
+func safeURL(href string) bool {
+	scheme, _, found := strings.Cut(href, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(scheme)) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// Slug converts text to an anchor ID: lowercase letters, digits, and
+// hyphens.
+func Slug(text string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			hyphen = false
+		case sb.Len() > 0 && !hyphen:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	s := strings.TrimSuffix(sb.String(), "-")
+	if s == "" {
+		return "section"
+	}
+	return s
+}
+
+// uniqueSlug returns Slug(text), suffixed with a counter if it was returned
+// before.
+func uniqueSlug(seen map[string]int, text string) string {
+	id := Slug(text)
+	seen[id]++
+	if n := seen[id]; n > 1 {
+		return id + "-" + strconv.Itoa(n)
+	}
+	return id
+}
+
+// People joins the names of ps for display.
+func People(ps []common.Person) string {
+	names := make([]string, 0, len(ps))
+	for _, p := range ps {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Date formats t as YYYY-MM-DD, or returns "" for the zero time.
+func Date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// cellHTML returns the escaped HTML of a table cell, with its code and
+// math blocks laid out as in a paragraph.
+func cellHTML(s string) string {
+	if richtext.HasBlocks(s) {
+		return string(richtext.HTML(s))
+	}
+	return string(richtext.Inline(s))
+}
+
+func esc(s string) string {
+	return template.HTMLEscapeString(s)
+}
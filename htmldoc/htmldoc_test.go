@@ -0,0 +1,229 @@
+package htmldoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+func TestPageRender(t *testing.T) {
+	page := NewPage("Product Requirements", "Checkout <v2>")
+	page.Meta = []Field{{Label: "ID", Value: "PRD-1"}, {Label: "Status", Value: ""}}
+	page.CustomCSS = "h1 { color: teal; }"
+
+	b := NewBuilder("summary")
+	b.Paragraph("Carts & <script>alert(1)</script>")
+	b.Heading("Risks")
+	b.Heading("Risks")
+	page.AddSection("Summary", b)
+	page.AddSection("Summary", NewBuilder("empty"))
+	b2 := NewBuilder("other")
+	b2.List("", []string{"one"})
+	page.AddSection("Summary", b2)
+
+	out, err := page.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<title>Checkout &lt;v2&gt;</title>",
+		"Carts &amp; &lt;script&gt;alert(1)&lt;/script&gt;",
+		`<section id="summary">`,
+		`<section id="summary-2">`,
+		`<a href="#summary-2">Summary</a>`,
+		`id="summary-risks"`,
+		`id="summary-risks-2"`,
+		"<details open>",
+		"@media print",
+		"h1 { color: teal; }",
+		"<dt>ID</dt><dd>PRD-1</dd>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>alert") || strings.Contains(html, "<dt>Status</dt>") {
+		t.Error("page should escape text and skip empty meta fields")
+	}
+	if strings.Count(html, "<section ") != 2 {
+		t.Errorf("empty sections should be skipped, got %d sections", strings.Count(html, "<section "))
+	}
+}
+
+func TestPageRenderCodeAndMath(t *testing.T) {
+	page := NewPage("TRD", "Pricing")
+	b := NewBuilder("design")
+	b.Paragraph("Cost is $c = n \\cdot p$ per seat:\n```go\nif a < b {}\n```")
+	b.Table([]string{"ID", "Formula"}, [][]string{{"F1", "$$\ne^{i\\pi}\n$$"}})
+	page.AddSection("Design", b)
+
+	out, err := page.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		`<span class="math inline">\(c = n \cdot p\)</span> per seat:</p>`,
+		`<pre><code class="language-go">if a &lt; b {}</code></pre>`,
+		`<td><div class="math display">\[e^{i\pi}\]</div>`,
+		`katex@0.16.11/dist/katex.min.js" integrity="sha384-`,
+		`auto-render.min.js" integrity="sha384-`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+
+	page.KaTeXURL = "assets/katex/"
+	if out, err = page.Render(); err != nil {
+		t.Fatal(err)
+	}
+	html = string(out)
+	if !strings.Contains(html, `<script defer src="assets/katex/katex.min.js"></script>`) ||
+		!strings.Contains(html, `href="assets/katex/katex.min.css"`) || strings.Contains(html, "jsdelivr") {
+		t.Errorf("page should load KaTeX from the local copy:\n%s", html)
+	}
+
+	plain := NewPage("PRD", "Prices")
+	b = NewBuilder("summary")
+	b.Paragraph("Plans cost $5 to $10.")
+	plain.AddSection("Summary", b)
+	if out, _ = plain.Render(); strings.Contains(string(out), "katex") {
+		t.Error("page without math should not load KaTeX")
+	}
+}
+
+func TestPageGlossaryLinks(t *testing.T) {
+	terms := []common.GlossaryTerm{{Term: "Tenant", Definition: "A customer account"}, {Term: "Quota", Acronym: "QT", Definition: "A limit"}}
+	page := NewPage("PRD", "Limits")
+	b := NewBuilder("summary")
+	b.Heading("Tenant limits")
+	b.Paragraph("Each tenant has a quota. Another tenant may share it.")
+	b.Table([]string{"Tenant"}, [][]string{{"`QT` per tenant"}})
+	page.AddSection("Summary", b)
+	g := NewBuilder("glossary")
+	g.Glossary(terms)
+	page.AddSection("Glossary", g)
+	page.Glossary = terms
+
+	out, err := page.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		`Each <a class="term" href="#term-tenant">tenant</a> has a <a class="term" href="#term-quota">quota</a>. Another tenant`,
+		`<dt id="term-tenant">Tenant</dt><dd>A customer account</dd>`,
+		`<dt id="term-quota">Quota (QT)</dt>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+	if strings.Count(html, `class="term"`) != 2 {
+		t.Errorf("expected only the first uses to be linked:\n%s", html)
+	}
+	if strings.Contains(string(page.Sections[0].Body), `class="term"`) {
+		t.Error("Render should not change the page's sections")
+	}
+}
+
+func TestPageAddComments(t *testing.T) {
+	page := NewPage("Product Requirements", "Checkout")
+	b := NewBuilder("risks")
+	b.Paragraph("Fraud")
+	page.AddSection("Risks", b)
+	page.AddComments([]common.Comment{
+		{ID: "C-1", Author: "alice", Path: "risks[0]", Text: "Owner <tbd>?"},
+		{ID: "C-2", Author: "bob", Path: "risks[0]", Text: "Me", InReplyTo: "C-1"},
+		{ID: "C-3", Author: "carol", Path: "glossary", Text: "Add SLO"},
+		{ID: "C-4", Author: "dana", Path: "risks", Text: "Done", Resolved: true},
+	}, map[string]string{"risks": "Risks", "glossary": "Glossary"})
+
+	if len(page.Sections[0].Notes) != 1 || len(page.Sections[0].Notes[0].Replies) != 1 {
+		t.Errorf("section notes = %+v", page.Sections[0].Notes)
+	}
+	if len(page.Notes) != 1 || page.Notes[0].ID != "C-3" {
+		t.Errorf("comments without a rendered section belong to the page, got %+v", page.Notes)
+	}
+	out, err := page.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	if !strings.Contains(html, `id="comment-C-1"`) || !strings.Contains(html, "Owner &lt;tbd&gt;?") || strings.Contains(html, "C-4") {
+		t.Errorf("unexpected notes:\n%s", html)
+	}
+}
+
+func TestBuilderLink(t *testing.T) {
+	b := NewBuilder("x")
+	b.Link("Docs", "https://example.com/a?b=1&c=2")
+	b.Link("Bad", "javascript:alert(1)")
+	b.Link("Local", "diagrams/c4.png")
+	got := string(b.HTML())
+	for _, want := range []string{
+		`<a href="https://example.com/a?b=1&amp;c=2">Docs</a>`,
+		"<p>Bad (javascript:alert(1))</p>",
+		`<a href="diagrams/c4.png">Local</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("links missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestBuilderGallery(t *testing.T) {
+	b := NewBuilder("x")
+	b.Gallery([]Figure{
+		{Src: "img/home.png", Title: "WF-1 Home", Caption: "Landing <page>", LinksLabel: "Flows",
+			Links: []Field{{Label: "IF-1", Value: "#flow-if-1"}, {Label: "IF-9"}}},
+		{Src: "javascript:alert(1)", Title: "Bad"},
+		{Src: "data:image/png;base64,AAAA", Title: "Inline"},
+	})
+	got := string(b.HTML())
+	for _, want := range []string{
+		`<div class="gallery">`,
+		`<a href="img/home.png"><img src="img/home.png" alt="WF-1 Home" loading="lazy"></a>`,
+		`<strong>WF-1 Home</strong><br>Landing &lt;page&gt;`,
+		`<span class="figure-links">Flows: <a href="#flow-if-1">IF-1</a>, IF-9</span>`,
+		`<img src="data:image/png;base64,AAAA"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("gallery missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "javascript") {
+		t.Errorf("unsafe figure rendered:\n%s", got)
+	}
+	if blocks := b.Blocks(); len(blocks) != 1 || blocks[0].Kind != BlockGallery || len(blocks[0].Figures) != 2 {
+		t.Errorf("Blocks = %+v", blocks)
+	}
+}
+
+func TestBuilderExternalRefs(t *testing.T) {
+	b := NewBuilder("x")
+	b.ExternalRefs("FR-001", common.ExternalRefs{"jira": "PROJ-1", "confluence": "42"},
+		map[string]string{"jira": "https://acme.atlassian.net/browse/{id}"})
+	want := `<p><strong>FR-001:</strong> confluence 42, <a href="https://acme.atlassian.net/browse/PROJ-1">PROJ-1</a></p>`
+	if got := string(b.HTML()); !strings.Contains(got, want) {
+		t.Errorf("ExternalRefs = %s, want %s", got, want)
+	}
+	if blocks := b.Blocks(); len(blocks) != 1 || blocks[0].Kind != BlockLinks || len(blocks[0].Fields) != 2 {
+		t.Errorf("Blocks = %+v", blocks)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	for in, want := range map[string]string{
+		"Assumptions & Constraints": "assumptions-constraints",
+		"  Go-to-Market ":           "go-to-market",
+		"!!!":                       "section",
+	} {
+		if got := Slug(in); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package ide writes the project files that make planning documents
+// easier to edit by hand: associations from document file names to their
+// JSON Schemas, so the editor completes and checks fields as they are
+// typed, and snippets that insert common entities with their required
+// fields.
+package ide
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/grokify/structured-plan/schema"
+)
+
+// DefaultVSCodeSchemaDir is where SetupVSCode writes schemas, relative to
+// the workspace.
+const DefaultVSCodeSchemaDir = ".vscode/schemas"
+
+// VSCodeSnippetsFile is the name of the snippets file SetupVSCode writes
+// in .vscode.
+const VSCodeSnippetsFile = "splan.code-snippets"
+
+// SchemaAssociation maps the files of a document type to its schema.
+type SchemaAssociation struct {
+	DocType   string   // prd, mrd, trd, okr, v2mom, or roadmap
+	Schema    string   // schema file name, such as prd.schema.json
+	FileMatch []string // JSON file patterns
+	YAMLMatch []string // YAML file patterns
+}
+
+// SchemaAssociations returns the document types with a schema.
+func SchemaAssociations() []SchemaAssociation {
+	var assocs []SchemaAssociation
+	for _, docType := range []string{"prd", "mrd", "trd", "okr", "v2mom", "roadmap"} {
+		assocs = append(assocs, SchemaAssociation{
+			DocType:   docType,
+			Schema:    docType + ".schema.json",
+			FileMatch: []string{"*." + docType + ".json"},
+			YAMLMatch: []string{"*." + docType + ".yaml", "*." + docType + ".yml"},
+		})
+	}
+	return assocs
+}
+
+// VSCodeOptions configures SetupVSCode.
+type VSCodeOptions struct {
+	// Dir is the workspace root, the folder opened in VS Code.
+	Dir string
+
+	// SchemaDir is where schemas are written, relative to Dir. It
+	// defaults to DefaultVSCodeSchemaDir. Point it at a directory of
+	// schemas kept in the repository to share them with other tools.
+	SchemaDir string
+}
+
+// VSCodeResult reports what SetupVSCode did.
+type VSCodeResult struct {
+	// Files lists the files written or found unchanged.
+	Files []schema.FileResult
+
+	// Warnings lists the files left untouched and why, such as a
+	// settings.json with comments.
+	Warnings []string
+}
+
+// SetupVSCode writes the schemas, maps the document files to them in
+// .vscode/settings.json, and writes .vscode/splan.code-snippets. Settings
+// other than the schema mappings are kept, in their order. Settings that
+// MergeVSCodeSettings cannot update, such as ones with comments, are left
+// untouched and reported in the result's Warnings. Files whose content is
+// unchanged are not rewritten, so running it again is safe.
+//
+// The "json.schemas" mappings are used by VS Code itself; the
+// "yaml.schemas" mappings need the Red Hat YAML extension.
+func SetupVSCode(opts VSCodeOptions) (*VSCodeResult, error) {
+	schemaDir := opts.SchemaDir
+	if schemaDir == "" {
+		schemaDir = DefaultVSCodeSchemaDir
+	}
+	if filepath.IsAbs(schemaDir) {
+		return nil, fmt.Errorf("schema directory %s must be relative to the workspace", schemaDir)
+	}
+
+	gen := schema.NewGenerator()
+	gen.Incremental = true
+	files, err := gen.GenerateAllFiles(filepath.Join(opts.Dir, schemaDir))
+	if err != nil {
+		return nil, err
+	}
+	res := &VSCodeResult{Files: files}
+
+	settingsPath := filepath.Join(opts.Dir, ".vscode", "settings.json")
+	existing, err := os.ReadFile(settingsPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", settingsPath, err)
+	}
+	if settings, err := MergeVSCodeSettings(existing, filepath.ToSlash(schemaDir)); err != nil {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("%s left untouched: %v; add the schema mappings by hand", settingsPath, err))
+	} else {
+		result, err := writeIfChanged(settingsPath, settings)
+		if err != nil {
+			return nil, err
+		}
+		res.Files = append(res.Files, result)
+	}
+
+	snippets, err := VSCodeSnippets()
+	if err != nil {
+		return nil, err
+	}
+	result, err := writeIfChanged(filepath.Join(opts.Dir, ".vscode", VSCodeSnippetsFile), snippets)
+	if err != nil {
+		return nil, err
+	}
+	res.Files = append(res.Files, result)
+	return res, nil
+}
+
+// MergeVSCodeSettings adds the schema mappings to the VS Code settings
+// JSON in data, which may be empty, for schemas in schemaDir relative to
+// the workspace. Mappings to other schemas are kept. Settings with
+// comments or trailing commas are rejected rather than rewritten without
+// them.
+func MergeVSCodeSettings(data []byte, schemaDir string) ([]byte, error) {
+	keys, values, err := decodeObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("settings must be a JSON object without comments: %w", err)
+	}
+
+	ours := map[string]bool{}
+	var jsonSchemas []any
+	yamlSchemas := map[string]any{}
+	for _, a := range SchemaAssociations() {
+		url := "./" + path.Join(schemaDir, a.Schema)
+		ours[url] = true
+		jsonSchemas = append(jsonSchemas, map[string]any{"fileMatch": a.FileMatch, "url": url})
+		yamlSchemas[url] = a.YAMLMatch
+	}
+
+	if raw, ok := values["json.schemas"]; ok {
+		var existing []map[string]any
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return nil, fmt.Errorf("json.schemas must be an array of objects: %w", err)
+		}
+		var kept []any
+		for _, m := range existing {
+			if url, _ := m["url"].(string); !ours[url] {
+				kept = append(kept, m)
+			}
+		}
+		jsonSchemas = append(kept, jsonSchemas...)
+	}
+	if raw, ok := values["yaml.schemas"]; ok {
+		var existing map[string]any
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return nil, fmt.Errorf("yaml.schemas must be an object: %w", err)
+		}
+		for url, match := range existing {
+			if !ours[url] {
+				yamlSchemas[url] = match
+			}
+		}
+	}
+
+	for _, m := range []struct {
+		key   string
+		value any
+	}{{"json.schemas", jsonSchemas}, {"yaml.schemas", yamlSchemas}} {
+		key := m.key
+		raw, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = raw
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(values[key])
+	}
+	buf.WriteByte('}')
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// decodeObject decodes the members of a JSON object, keeping their order.
+// Empty data is an empty object.
+func decodeObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	values := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, values, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("got %v, want an object", tok)
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, dup := values[key]; !dup {
+			keys = append(keys, key)
+		}
+		values[key] = raw
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, nil, fmt.Errorf("unexpected data after the settings object")
+	}
+	return keys, values, nil
+}
+
+// writeIfChanged writes data to path unless the file already holds it.
+func writeIfChanged(path string, data []byte) (schema.FileResult, error) {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return schema.FileResult{Path: path}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return schema.FileResult{}, fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return schema.FileResult{}, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return schema.FileResult{Path: path, Written: true}, nil
+}
@@ -0,0 +1,172 @@
+package ide
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+var (
+	placeholder = regexp.MustCompile(`\$\{\d+:([^}]*)\}`)
+	choice      = regexp.MustCompile(`\$\{\d+\|([^,|]*)[^}]*\}`)
+)
+
+func TestSnippetsDecode(t *testing.T) {
+	targets := map[string]any{
+		"PRD persona":                    &prd.Persona{},
+		"PRD user story":                 &prd.UserStory{},
+		"PRD acceptance criterion":       &prd.AcceptanceCriterion{},
+		"PRD functional requirement":     &prd.FunctionalRequirement{},
+		"PRD non-functional requirement": &prd.NonFunctionalRequirement{},
+		"Risk":                           &prd.Risk{},
+		"Roadmap phase":                  &roadmap.Phase{},
+		"Roadmap deliverable":            &roadmap.Deliverable{},
+		"OKR objective":                  &okr.Objective{},
+		"OKR key result":                 &okr.KeyResult{},
+		"V2MOM method":                   &v2mom.Method{},
+		"V2MOM measure":                  &v2mom.Measure{},
+		"V2MOM obstacle":                 &v2mom.Obstacle{},
+	}
+	prefixes := map[string]bool{}
+	for _, s := range Snippets() {
+		target, ok := targets[s.Name]
+		if !ok {
+			t.Errorf("snippet %q has no decode target in this test", s.Name)
+			continue
+		}
+		if prefixes[s.Prefix] {
+			t.Errorf("duplicate prefix %q", s.Prefix)
+		}
+		prefixes[s.Prefix] = true
+
+		// Expand the snippet with its defaults and first choices
+		body := strings.Join(s.Body, "\n")
+		body = choice.ReplaceAllString(placeholder.ReplaceAllString(body, "$1"), "$1")
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(target); err != nil {
+			t.Errorf("snippet %q does not decode: %v\n%s", s.Name, err, body)
+		}
+	}
+
+	data, err := VSCodeSnippets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(data) || !bytes.Contains(data, []byte("P95 < 200ms")) {
+		t.Errorf("snippets file:\n%s", data)
+	}
+}
+
+func TestMergeVSCodeSettings(t *testing.T) {
+	existing := `{
+  "editor.tabSize": 2,
+  "json.schemas": [
+    {"fileMatch": ["*.app.json"], "url": "./app.schema.json"},
+    {"fileMatch": ["old.json"], "url": "./.vscode/schemas/prd.schema.json"}
+  ],
+  "files.trimTrailingWhitespace": true
+}`
+	out, err := MergeVSCodeSettings([]byte(existing), DefaultVSCodeSchemaDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		JSONSchemas []struct {
+			FileMatch []string `json:"fileMatch"`
+			URL       string   `json:"url"`
+		} `json:"json.schemas"`
+		YAMLSchemas map[string][]string `json:"yaml.schemas"`
+	}
+	if err := json.Unmarshal(out, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(settings.JSONSchemas); n != 7 || settings.JSONSchemas[0].URL != "./app.schema.json" {
+		t.Fatalf("json.schemas = %+v", settings.JSONSchemas)
+	}
+	if s := settings.JSONSchemas[1]; s.URL != "./.vscode/schemas/prd.schema.json" || s.FileMatch[0] != "*.prd.json" {
+		t.Errorf("prd mapping = %+v", s)
+	}
+	if s := settings.JSONSchemas[3]; s.URL != "./.vscode/schemas/trd.schema.json" || s.FileMatch[0] != "*.trd.json" {
+		t.Errorf("trd mapping = %+v", s)
+	}
+	if m := settings.YAMLSchemas["./.vscode/schemas/v2mom.schema.json"]; len(m) != 2 || m[0] != "*.v2mom.yaml" {
+		t.Errorf("yaml.schemas = %v", settings.YAMLSchemas)
+	}
+	text := string(out)
+	if a, b := strings.Index(text, "editor.tabSize"), strings.Index(text, "files.trimTrailingWhitespace"); a < 0 || b < a || !strings.Contains(text, `"yaml.schemas"`) {
+		t.Errorf("settings order not kept:\n%s", text)
+	}
+
+	if _, err := MergeVSCodeSettings([]byte("{\n  // tabs\n  \"editor.tabSize\": 2\n}"), DefaultVSCodeSchemaDir); err == nil {
+		t.Error("settings with comments should be rejected")
+	}
+}
+
+func TestSetupVSCode(t *testing.T) {
+	dir := t.TempDir()
+	results, err := SetupVSCode(VSCodeOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Files) != 8 || len(results.Warnings) != 0 {
+		t.Fatalf("results = %+v", results)
+	}
+	for _, name := range []string{".vscode/schemas/prd.schema.json", ".vscode/settings.json", ".vscode/" + VSCodeSnippetsFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	results, err = SetupVSCode(VSCodeOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results.Files {
+		if r.Written {
+			t.Errorf("%s rewritten although unchanged", r.Path)
+		}
+	}
+
+	if _, err := SetupVSCode(VSCodeOptions{Dir: dir, SchemaDir: "/abs"}); err == nil {
+		t.Error("absolute schema directory should be rejected")
+	}
+}
+
+func TestSetupVSCodeKeepsSettingsWithComments(t *testing.T) {
+	dir := t.TempDir()
+	settings := []byte("{\n  // tabs\n  \"editor.tabSize\": 2,\n}\n")
+	if err := os.MkdirAll(filepath.Join(dir, ".vscode"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".vscode", "settings.json"), settings, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := SetupVSCode(VSCodeOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Warnings) != 1 || !strings.Contains(results.Warnings[0], "settings.json") {
+		t.Errorf("warnings = %q", results.Warnings)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, ".vscode", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, settings) {
+		t.Errorf("settings rewritten:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".vscode", VSCodeSnippetsFile)); err != nil {
+		t.Error(err)
+	}
+}
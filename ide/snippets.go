@@ -0,0 +1,248 @@
+package ide
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Snippet is an editor snippet that inserts one entity of a planning
+// document as a JSON object, with tab stops for its fields.
+type Snippet struct {
+	Name        string   // unique name, such as "PRD user story"
+	Prefix      string   // trigger text, such as "splan-story"
+	Description string   // where the entity goes in the document
+	Body        []string // lines, in VS Code snippet syntax
+}
+
+// Snippets returns the snippets for the common entities of PRD, roadmap,
+// OKR, and V2MOM documents. Each has the entity's required fields.
+func Snippets() []Snippet {
+	return []Snippet{
+		{
+			Name:        "PRD persona",
+			Prefix:      "splan-persona",
+			Description: "Persona, for the personas array of a PRD",
+			Body: []string{
+				`{`,
+				`	"id": "${1:persona-1}",`,
+				`	"name": "${2:Name}",`,
+				`	"role": "${3:Role}",`,
+				`	"description": "${4:Background and context}",`,
+				`	"goals": ["${5:Goal}"],`,
+				`	"painPoints": ["${6:Pain point}"],`,
+				`	"isPrimary": ${7|false,true|}`,
+				`}`,
+			},
+		},
+		{
+			Name:        "PRD user story",
+			Prefix:      "splan-story",
+			Description: "User story, for the userStories array of a PRD",
+			Body: []string{
+				`{`,
+				`	"id": "${1:US-001}",`,
+				`	"personaId": "${2:persona-1}",`,
+				`	"title": "${3:Title}",`,
+				`	"asA": "${4:role}",`,
+				`	"iWant": "${5:to do something}",`,
+				`	"soThat": "${6:I get a benefit}",`,
+				`	"acceptanceCriteria": [`,
+				`		{"id": "${7:AC-001}", "description": "${8:Criterion}"}`,
+				`	],`,
+				`	"priority": "${9|high,critical,medium,low|}",`,
+				`	"phaseId": "${10:phase-1}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "PRD acceptance criterion",
+			Prefix:      "splan-ac",
+			Description: "Given/when/then acceptance criterion, for a user story or requirement",
+			Body: []string{
+				`{`,
+				`	"id": "${1:AC-001}",`,
+				`	"description": "${2:Criterion}",`,
+				`	"given": "${3:a precondition}",`,
+				`	"when": "${4:an action}",`,
+				`	"then": "${5:an expected result}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "PRD functional requirement",
+			Prefix:      "splan-fr",
+			Description: "Functional requirement, for requirements.functional in a PRD",
+			Body: []string{
+				`{`,
+				`	"id": "${1:FR-001}",`,
+				`	"title": "${2:Title}",`,
+				`	"description": "${3:What the system must do}",`,
+				`	"category": "${4:Category}",`,
+				`	"priority": "${5|must,should,could,wont|}",`,
+				`	"userStoryIds": ["${6:US-001}"],`,
+				`	"acceptanceCriteria": [`,
+				`		{"id": "${7:AC-001}", "description": "${8:Criterion}"}`,
+				`	],`,
+				`	"phaseId": "${9:phase-1}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "PRD non-functional requirement",
+			Prefix:      "splan-nfr",
+			Description: "Non-functional requirement, for requirements.nonFunctional in a PRD",
+			Body: []string{
+				`{`,
+				`	"id": "${1:NFR-001}",`,
+				`	"category": "${2|performance,scalability,reliability,availability,security,observability,usability,compliance|}",`,
+				`	"title": "${3:Title}",`,
+				`	"description": "${4:Description}",`,
+				`	"metric": "${5:What is measured}",`,
+				`	"target": "${6:P95 < 200ms}",`,
+				`	"priority": "${7|must,should,could,wont|}",`,
+				`	"phaseId": "${8:phase-1}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "Risk",
+			Prefix:      "splan-risk",
+			Description: "Risk, for the risks array of a PRD, MRD, or TRD",
+			Body: []string{
+				`{`,
+				`	"id": "${1:RISK-001}",`,
+				`	"description": "${2:What could go wrong}",`,
+				`	"probability": "${3|medium,low,high|}",`,
+				`	"impact": "${4|medium,low,high,critical|}",`,
+				`	"mitigation": "${5:How it is reduced}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "Roadmap phase",
+			Prefix:      "splan-phase",
+			Description: "Phase, for roadmap.phases in a PRD or a roadmap",
+			Body: []string{
+				`{`,
+				`	"id": "${1:phase-1}",`,
+				`	"name": "${2:MVP}",`,
+				`	"type": "${3|milestone,generic,quarter,month,sprint|}",`,
+				`	"goals": ["${4:Goal}"],`,
+				`	"deliverables": [],`,
+				`	"successCriteria": ["${5:Criterion}"]`,
+				`}`,
+			},
+		},
+		{
+			Name:        "Roadmap deliverable",
+			Prefix:      "splan-deliverable",
+			Description: "Deliverable, for the deliverables array of a roadmap phase",
+			Body: []string{
+				`{`,
+				`	"id": "${1:D-001}",`,
+				`	"title": "${2:Title}",`,
+				`	"description": "${3:Description}",`,
+				`	"type": "${4|feature,documentation,infrastructure,integration,milestone,rollout|}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "OKR objective",
+			Prefix:      "splan-objective",
+			Description: "Objective with a key result, for the objectives array of an OKR document",
+			Body: []string{
+				`{`,
+				`	"id": "${1:O1}",`,
+				`	"title": "${2:Objective}",`,
+				`	"owner": "${3:Owner}",`,
+				`	"keyResults": [`,
+				`		{"id": "${4:KR1}", "title": "${5:Key result}", "baseline": "${6:0}", "target": "${7:100}"}`,
+				`	]`,
+				`}`,
+			},
+		},
+		{
+			Name:        "OKR key result",
+			Prefix:      "splan-kr",
+			Description: "Key result, for the keyResults array of an OKR objective or PRD OKR",
+			Body: []string{
+				`{`,
+				`	"id": "${1:KR1}",`,
+				`	"title": "${2:Key result}",`,
+				`	"metric": "${3:What is measured}",`,
+				`	"baseline": "${4:0}",`,
+				`	"target": "${5:100}",`,
+				`	"unit": "${6:%}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "V2MOM method",
+			Prefix:      "splan-method",
+			Description: "Method with a measure, for the methods array of a V2MOM",
+			Body: []string{
+				`{`,
+				`	"id": "${1:M1}",`,
+				`	"name": "${2:Method}",`,
+				`	"priority": "${3|P0,P1,P2,P3|}",`,
+				`	"owner": "${4:Owner}",`,
+				`	"measures": [`,
+				`		{"id": "${5:MS1}", "name": "${6:Measure}", "baseline": "${7:0}", "target": "${8:100}"}`,
+				`	]`,
+				`}`,
+			},
+		},
+		{
+			Name:        "V2MOM measure",
+			Prefix:      "splan-measure",
+			Description: "Measure, for the measures array of a V2MOM or a method",
+			Body: []string{
+				`{`,
+				`	"id": "${1:MS1}",`,
+				`	"name": "${2:Measure}",`,
+				`	"baseline": "${3:0}",`,
+				`	"target": "${4:100}",`,
+				`	"unit": "${5:%}"`,
+				`}`,
+			},
+		},
+		{
+			Name:        "V2MOM obstacle",
+			Prefix:      "splan-obstacle",
+			Description: "Obstacle, for the obstacles array of a V2MOM or a method",
+			Body: []string{
+				`{`,
+				`	"id": "${1:OB1}",`,
+				`	"name": "${2:Obstacle}",`,
+				`	"severity": "${3|Medium,Low,High,Critical|}",`,
+				`	"likelihood": "${4|Medium,Low,High|}",`,
+				`	"mitigation": "${5:How it is overcome}"`,
+				`}`,
+			},
+		},
+	}
+}
+
+// VSCodeSnippets returns the snippets as a VS Code .code-snippets file,
+// scoped to JSON files.
+func VSCodeSnippets() ([]byte, error) {
+	type vscodeSnippet struct {
+		Scope       string   `json:"scope"`
+		Prefix      string   `json:"prefix"`
+		Description string   `json:"description"`
+		Body        []string `json:"body"`
+	}
+	file := map[string]vscodeSnippet{}
+	for _, s := range Snippets() {
+		file[s.Name] = vscodeSnippet{Scope: "json,jsonc", Prefix: s.Prefix, Description: s.Description, Body: s.Body}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return nil, fmt.Errorf("marshaling snippets: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,430 @@
+package ids
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// DefaultPatterns maps a document type to JSONPath expressions selecting
+// items and the pattern new IDs of those items follow.
+//
+// A pattern is literal text with placeholders in braces:
+//
+//   - {n} is the item's number, one more than the highest number in use
+//     with the same prefix; {n:3} pads it to three digits.
+//   - {parent} is the ID of the nearest enclosing item with an ID, such as
+//     the user story of an acceptance criterion.
+//   - {field} is the value of a member of the item, such as {personaId};
+//     {field:upper} and {field:lower} change its case.
+var DefaultPatterns = map[string]map[string]string{
+	"prd": {
+		"$.personas[*]":                          "persona-{n}",
+		"$.userStories[*]":                       "US-{n:3}",
+		"$.userStories[*].acceptanceCriteria[*]": "{parent}-AC-{n}",
+		"$.requirements.functional[*]":           "FR-{n:3}",
+		"$.requirements.nonFunctional[*]":        "NFR-{n:3}",
+		"$.roadmap.phases[*]":                    "phase-{n}",
+		"$.roadmap.phases[*].deliverables[*]":    "{parent}-d-{n}",
+		"$.risks[*]":                             "r-{n}",
+	},
+}
+
+// Config holds the ID patterns of the "ids" section of the project
+// configuration file.
+type Config struct {
+	// Patterns maps a document type to JSONPath expressions and patterns,
+	// as DefaultPatterns. Setting the patterns of a type replaces its
+	// defaults.
+	Patterns map[string]map[string]string `json:"patterns,omitempty"`
+}
+
+// DefaultConfig returns the built-in patterns.
+func DefaultConfig() Config {
+	cfg := Config{Patterns: make(map[string]map[string]string, len(DefaultPatterns))}
+	for t, patterns := range DefaultPatterns {
+		cfg.Patterns[t] = make(map[string]string, len(patterns))
+		for path, pattern := range patterns {
+			cfg.Patterns[t][path] = pattern
+		}
+	}
+	return cfg
+}
+
+// LoadConfig reads the "ids" section of the YAML or JSON project
+// configuration at path over the defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading ID config: %w", err)
+	}
+	if data, err = yamlconv.ToJSON(data); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var f struct {
+		IDs *json.RawMessage `json:"ids"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if f.IDs != nil {
+		if err := json.Unmarshal(*f.IDs, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing %s: ids: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// AssignOptions configure Assign.
+type AssignOptions struct {
+	// Patterns maps JSONPath expressions selecting items to the pattern of
+	// their IDs, as a type's entry in DefaultPatterns.
+	Patterns map[string]string
+
+	// Renumber also renumbers the items whose IDs follow their pattern, in
+	// document order, closing the gaps left by deleted items.
+	Renumber bool
+
+	// References are the members holding IDs, as a string or a list of
+	// strings, that are updated when an ID is renumbered.
+	References []string
+}
+
+// Assignment is the result of Assign.
+type Assignment struct {
+	// Patch applies the changes.
+	Patch jsonpatch.Patch
+
+	// Changes are the IDs assigned and renumbered, in document order.
+	Changes []Change
+
+	// References is the number of references updated.
+	References int
+}
+
+// pattern is a parsed ID pattern.
+type pattern struct {
+	source string
+	parts  []patternPart
+}
+
+type patternPart struct {
+	literal string
+	name    string // "n", "parent", or a member name; "" for a literal
+	format  string // width of n, or upper or lower
+}
+
+var placeholder = regexp.MustCompile(`\{([A-Za-z][A-Za-z0-9_]*)(?::([A-Za-z0-9]+))?\}`)
+
+func parsePattern(source string) (*pattern, error) {
+	p := &pattern{source: source}
+	numbered := false
+	last := 0
+	for _, m := range placeholder.FindAllStringSubmatchIndex(source, -1) {
+		if m[0] > last {
+			p.parts = append(p.parts, patternPart{literal: source[last:m[0]]})
+		}
+		part := patternPart{name: source[m[2]:m[3]]}
+		if m[4] >= 0 {
+			part.format = source[m[4]:m[5]]
+		}
+		switch {
+		case part.name == "n":
+			if numbered {
+				return nil, fmt.Errorf("ID pattern %q: {n} appears twice", source)
+			}
+			numbered = true
+			if part.format != "" {
+				if w, err := strconv.Atoi(part.format); err != nil || w < 1 || w > 9 {
+					return nil, fmt.Errorf("ID pattern %q: invalid width %q", source, part.format)
+				}
+			}
+		case part.format != "" && part.format != "upper" && part.format != "lower":
+			return nil, fmt.Errorf("ID pattern %q: unknown format %q (valid: upper, lower)", source, part.format)
+		}
+		p.parts = append(p.parts, part)
+		last = m[1]
+	}
+	if last < len(source) {
+		p.parts = append(p.parts, patternPart{literal: source[last:]})
+	}
+	if strings.ContainsAny(strings.Join(literals(p.parts), ""), "{}") {
+		return nil, fmt.Errorf("ID pattern %q: unbalanced braces", source)
+	}
+	if !numbered {
+		return nil, fmt.Errorf("ID pattern %q has no {n}", source)
+	}
+	return p, nil
+}
+
+func literals(parts []patternPart) []string {
+	var s []string
+	for _, part := range parts {
+		s = append(s, part.literal)
+	}
+	return s
+}
+
+// render returns the text of the pattern before and after {n} for an item,
+// and the width of n, or ok false if a member it names is missing.
+func (p *pattern) render(item *query.Object, parent string) (prefix, suffix string, width int, ok bool) {
+	var sb strings.Builder
+	for _, part := range p.parts {
+		var s string
+		switch part.name {
+		case "":
+			s = part.literal
+		case "n":
+			prefix = sb.String()
+			sb.Reset()
+			width, _ = strconv.Atoi(part.format)
+			continue
+		case "parent":
+			s = parent
+		default:
+			s, _ = item.Fields[part.name].(string)
+		}
+		if part.name != "" && s == "" {
+			return "", "", 0, false
+		}
+		switch part.format {
+		case "upper":
+			s = strings.ToUpper(s)
+		case "lower":
+			s = strings.ToLower(s)
+		}
+		sb.WriteString(s)
+	}
+	return prefix, sb.String(), width, true
+}
+
+// item is an item selected by a pattern.
+type item struct {
+	obj     *query.Object
+	pointer string
+	pattern *pattern
+}
+
+// Assign returns a patch giving the items a pattern selects that have no ID
+// one following the pattern, and the changes it makes. Existing IDs are
+// kept unless Renumber is set, in which case the IDs that follow the
+// pattern are renumbered from 1 in document order and the references to
+// them updated. An ID that would repeat one used elsewhere in the document
+// gets the next free number instead.
+func Assign(data []byte, opts AssignOptions) (*Assignment, error) {
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(opts.Patterns))
+	for path := range opts.Patterns {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var items []item
+	seen := map[string]bool{}
+	for _, path := range paths {
+		p, err := parsePattern(opts.Patterns[path])
+		if err != nil {
+			return nil, err
+		}
+		sel, err := query.Compile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range sel.Select(root) {
+			obj, ok := n.Value.(*query.Object)
+			if !ok || seen[n.Pointer()] {
+				continue
+			}
+			seen[n.Pointer()] = true
+			items = append(items, item{obj: obj, pointer: n.Pointer(), pattern: p})
+		}
+	}
+	// Parents come before the items nested in them, so {parent} is final
+	// when it is rendered.
+	sort.SliceStable(items, func(i, j int) bool {
+		return strings.Count(items[i].pointer, "/") < strings.Count(items[j].pointer, "/")
+	})
+
+	defs := Definitions(root)
+	count := make(map[string]int, len(defs))
+	pointers := make(map[string]string, len(defs)) // object pointer -> ID
+	for _, d := range defs {
+		count[d.ID]++
+		pointers[d.Pointer] = d.ID
+	}
+
+	a := &Assignment{Patch: jsonpatch.Patch{}}
+	final := make(map[string]string, len(pointers)) // object pointer -> new ID
+	used := map[string]bool{}                       // IDs kept or given out
+	renumbered := map[string]bool{}                 // object pointers renumbered
+	if opts.Renumber {
+		for _, it := range items {
+			old := pointers[it.pointer]
+			if prefix, suffix, _, ok := it.pattern.render(it.obj, parentID(it.pointer, pointers)); ok && old != "" && matchesNumbered(old, prefix, suffix) {
+				renumbered[it.pointer] = true
+			}
+		}
+	}
+	for _, d := range defs {
+		if !renumbered[d.Pointer] {
+			used[d.ID] = true
+		}
+	}
+
+	next := map[[2]string]int{}
+	for _, it := range items {
+		old := pointers[it.pointer]
+		if old != "" && !renumbered[it.pointer] {
+			continue
+		}
+		prefix, suffix, width, ok := it.pattern.render(it.obj, parentID(it.pointer, final, pointers))
+		if !ok {
+			continue
+		}
+		key := [2]string{prefix, suffix}
+		n, started := next[key]
+		if !started && !opts.Renumber {
+			n = highest(used, prefix, suffix)
+		}
+		var id string
+		for {
+			n++
+			if id = fmt.Sprintf("%s%0*d%s", prefix, width, n, suffix); !used[id] {
+				break
+			}
+		}
+		next[key] = n
+		used[id] = true
+		final[it.pointer] = id
+		if id == old {
+			continue
+		}
+		path := it.pointer + "/id"
+		if old == "" {
+			a.Patch = append(a.Patch, jsonpatch.Add(path, id))
+		} else {
+			a.Patch = append(a.Patch, jsonpatch.Test(path, old), jsonpatch.Replace(path, id))
+		}
+		a.Changes = append(a.Changes, Change{Pointer: it.pointer, Old: old, New: id})
+	}
+	position := map[string]int{}
+	walkObjects(root, "", func(pointer string) { position[pointer] = len(position) })
+	sort.SliceStable(a.Changes, func(i, j int) bool {
+		return position[a.Changes[i].Pointer] < position[a.Changes[j].Pointer]
+	})
+
+	// Update the references to renumbered IDs defined once, so each
+	// reference names one item.
+	renamed := map[string]string{}
+	for _, c := range a.Changes {
+		if c.Old != "" && count[c.Old] == 1 {
+			renamed[c.Old] = c.New
+		}
+	}
+	if len(renamed) > 0 {
+		walkReferences(root, opts.References, func(_, id, pointer string) {
+			if id2, ok := renamed[id]; ok {
+				a.Patch = append(a.Patch, jsonpatch.Test(pointer, id), jsonpatch.Replace(pointer, id2))
+				a.References++
+			}
+		})
+	}
+	return a, nil
+}
+
+// parentID returns the ID of the nearest enclosing item of the object at
+// pointer, looking it up in each of ids in turn.
+func parentID(pointer string, ids ...map[string]string) string {
+	for p := pointer; p != ""; {
+		p = p[:strings.LastIndexByte(p, '/')]
+		for _, m := range ids {
+			if id, ok := m[p]; ok && id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// matchesNumbered reports whether id is prefix, a number, and suffix.
+func matchesNumbered(id, prefix, suffix string) bool {
+	if !strings.HasPrefix(id, prefix) || !strings.HasSuffix(id, suffix) || len(id) <= len(prefix)+len(suffix) {
+		return false
+	}
+	_, err := strconv.ParseUint(id[len(prefix):len(id)-len(suffix)], 10, 64)
+	return err == nil
+}
+
+// highest returns the highest number of the IDs in ids that are prefix, a
+// number, and suffix.
+func highest(ids map[string]bool, prefix, suffix string) int {
+	h := 0
+	for id := range ids {
+		if matchesNumbered(id, prefix, suffix) {
+			n, _ := strconv.Atoi(id[len(prefix) : len(id)-len(suffix)])
+			h = max(h, n)
+		}
+	}
+	return h
+}
+
+// walkObjects calls fn with the pointer of each object in the document, in
+// document order.
+func walkObjects(v any, pointer string, fn func(pointer string)) {
+	switch x := v.(type) {
+	case *query.Object:
+		fn(pointer)
+		for _, k := range x.Keys {
+			walkObjects(x.Fields[k], pointer+jsonpatch.Pointer(k), fn)
+		}
+	case []any:
+		for i, e := range x {
+			walkObjects(e, pointer+"/"+strconv.Itoa(i), fn)
+		}
+	}
+}
+
+// walkReferences calls fn with each ID held by a member of the document
+// named in fields, the member name, and the pointer to the ID.
+func walkReferences(root any, fields []string, fn func(key, id, pointer string)) {
+	var visit func(v any, pointer string)
+	visit = func(v any, pointer string) {
+		switch x := v.(type) {
+		case *query.Object:
+			for _, k := range x.Keys {
+				p := pointer + jsonpatch.Pointer(k)
+				if slices.Contains(fields, k) {
+					switch ref := x.Fields[k].(type) {
+					case string:
+						fn(k, ref, p)
+					case []any:
+						for i, e := range ref {
+							if id, ok := e.(string); ok {
+								fn(k, id, p+"/"+strconv.Itoa(i))
+							}
+						}
+					}
+				}
+				visit(x.Fields[k], p)
+			}
+		case []any:
+			for i, e := range x {
+				visit(e, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	visit(root, "")
+}
@@ -0,0 +1,199 @@
+// Package ids checks that the IDs of a planning document are unambiguous
+// and renumbers the ones that are not.
+//
+// Every object with an "id" member defines an ID. An ID is scoped to the
+// nearest enclosing object that has an ID of its own, so the acceptance
+// criteria of different user stories may each be numbered from ac-1, while
+// personas, user stories, and requirements share the document scope. Two
+// definitions of an ID in the same scope are a duplicate when they belong
+// to the same collection, such as two functional requirements, and a
+// conflict when they belong to different ones, such as a persona and a
+// user story, since a reference to the ID could mean either.
+//
+// It also checks referential integrity: that the members holding IDs of
+// other items, such as the personaId of a user story or the alignedWith of
+// an objective, name an item that exists.
+//
+// The package works on the JSON form of a document, so it applies to
+// every document type alike.
+package ids
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/validation"
+)
+
+// Definition is an object with an ID.
+type Definition struct {
+	ID string
+
+	// Pointer is the JSON pointer to the object.
+	Pointer string
+
+	// Collection is the pointer with array indexes dropped, such as
+	// "/requirements/functional", identifying the kind of object.
+	Collection string
+
+	// Scope is the pointer to the nearest enclosing object with an ID, or
+	// "" for the document scope.
+	Scope string
+}
+
+// Definitions returns the objects with an ID in a decoded JSON document,
+// in document order.
+func Definitions(root any) []Definition {
+	var defs []Definition
+	var visit func(v any, pointer, collection, scope string)
+	visit = func(v any, pointer, collection, scope string) {
+		switch x := v.(type) {
+		case *query.Object:
+			if id, ok := x.Fields["id"].(string); ok && id != "" {
+				defs = append(defs, Definition{ID: id, Pointer: pointer, Collection: collection, Scope: scope})
+				scope = pointer
+			}
+			for _, k := range x.Keys {
+				token := jsonpatch.Pointer(k)
+				visit(x.Fields[k], pointer+token, collection+token, scope)
+			}
+		case []any:
+			for i, e := range x {
+				visit(e, pointer+"/"+strconv.Itoa(i), collection, scope)
+			}
+		}
+	}
+	visit(root, "", "", "")
+	return defs
+}
+
+// Clash is a definition of an ID already defined earlier in its scope.
+type Clash struct {
+	Definition
+
+	// First is the earlier definition.
+	First Definition
+}
+
+// Conflicting reports whether the two definitions belong to different
+// collections.
+func (c Clash) Conflicting() bool {
+	return c.Collection != c.First.Collection
+}
+
+// Clashes returns the definitions that repeat an ID defined earlier in the
+// same scope, in document order.
+func Clashes(defs []Definition) []Clash {
+	first := map[[2]string]Definition{}
+	var clashes []Clash
+	for _, d := range defs {
+		key := [2]string{d.Scope, d.ID}
+		if f, ok := first[key]; ok {
+			clashes = append(clashes, Clash{Definition: d, First: f})
+			continue
+		}
+		first[key] = d
+	}
+	return clashes
+}
+
+// Check reports the duplicate and conflicting IDs of a JSON document as
+// errors pointing at the later definition.
+func Check(data []byte) ([]validation.Issue, error) {
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	var issues []validation.Issue
+	for _, c := range Clashes(Definitions(root)) {
+		is := validation.Issue{
+			RuleID:   validation.RuleDuplicateID,
+			Severity: validation.SeverityError,
+			Pointer:  c.Pointer + "/id",
+			Message:  fmt.Sprintf("duplicate ID %q (first defined at %s)", c.ID, c.First.Pointer),
+		}
+		if c.Conflicting() {
+			is.RuleID = validation.RuleConflictingID
+			is.Message = fmt.Sprintf("ID %q is also used at %s, so references to it are ambiguous", c.ID, c.First.Pointer)
+		}
+		issues = append(issues, is)
+	}
+	return issues, nil
+}
+
+// Change is an ID assigned by Assign or renumbered by Renumber or Assign.
+type Change struct {
+	Pointer string // The object whose ID changed
+	Old     string // "" for an assigned ID
+	New     string
+}
+
+// numbered splits an ID such as "FR-007" into its prefix and number.
+var numbered = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// Renumber returns a patch giving every clashing definition of a JSON
+// document a new ID, and the changes it makes. The earliest definition of
+// an ID keeps it, so existing references continue to point at it. A
+// numbered ID gets the next number after the highest in use with its
+// prefix, at the same width, e.g. FR-013 after FR-012; other IDs get a
+// numeric suffix, e.g. "checkout-2". The result depends only on the
+// document, so running it twice gives the same IDs.
+func Renumber(data []byte) (jsonpatch.Patch, []Change, error) {
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	defs := Definitions(root)
+	used := make(map[string]bool, len(defs))
+	for _, d := range defs {
+		used[d.ID] = true
+	}
+
+	patch := jsonpatch.Patch{}
+	var changes []Change
+	for _, c := range Clashes(defs) {
+		id := nextID(c.ID, used)
+		used[id] = true
+		path := c.Pointer + "/id"
+		patch = append(patch, jsonpatch.Test(path, c.ID), jsonpatch.Replace(path, id))
+		changes = append(changes, Change{Pointer: c.Pointer, Old: c.ID, New: id})
+	}
+	return patch, changes, nil
+}
+
+// nextID returns an unused ID derived from id.
+func nextID(id string, used map[string]bool) string {
+	m := numbered.FindStringSubmatch(id)
+	if m == nil {
+		for n := 2; ; n++ {
+			if next := fmt.Sprintf("%s-%d", id, n); !used[next] {
+				return next
+			}
+		}
+	}
+	prefix, width := m[1], len(m[2])
+	highest := 0
+	for u := range used {
+		if um := numbered.FindStringSubmatch(u); um != nil && um[1] == prefix {
+			n, _ := strconv.Atoi(um[2])
+			highest = max(highest, n)
+		}
+	}
+	for n := highest + 1; ; n++ {
+		if next := prefix + fmt.Sprintf("%0*d", width, n); !used[next] {
+			return next
+		}
+	}
+}
+
+// String describes the change, for reports.
+func (c Change) String() string {
+	if c.Old == "" {
+		return fmt.Sprintf("%s: assign %q", strings.TrimPrefix(c.Pointer, "/"), c.New)
+	}
+	return fmt.Sprintf("%s: renumber %q to %q", strings.TrimPrefix(c.Pointer, "/"), c.Old, c.New)
+}
@@ -0,0 +1,243 @@
+package ids
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/validation"
+)
+
+const testDoc = `{
+	"metadata": {"id": "PRD-1"},
+	"personas": [{"id": "p-1"}, {"id": "p-2"}],
+	"userStories": [
+		{"id": "US-001", "acceptanceCriteria": [{"id": "ac-1"}, {"id": "ac-2"}]},
+		{"id": "US-002", "acceptanceCriteria": [{"id": "ac-1"}, {"id": "ac-1"}]},
+		{"id": "p-2"}
+	],
+	"requirements": {
+		"functional": [{"id": "FR-009"}, {"id": "FR-010"}, {"id": "FR-009"}],
+		"nonFunctional": [{"id": "FR-010"}]
+	}
+}`
+
+func TestCheck(t *testing.T) {
+	issues, err := Check([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, is := range issues {
+		got = append(got, is.RuleID+" "+is.Pointer)
+	}
+	want := []string{
+		"duplicate-id /userStories/1/acceptanceCriteria/1/id",
+		"conflicting-id /userStories/2/id",
+		"duplicate-id /requirements/functional/2/id",
+		"conflicting-id /requirements/nonFunctional/0/id",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if msg := issues[1].Message; msg != `ID "p-2" is also used at /personas/1, so references to it are ambiguous` {
+		t.Errorf("conflict message = %q", msg)
+	}
+}
+
+func TestRenumber(t *testing.T) {
+	patch, changes, err := Renumber([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`userStories/1/acceptanceCriteria/1: renumber "ac-1" to "ac-3"`,
+		`userStories/2: renumber "p-2" to "p-3"`,
+		`requirements/functional/2: renumber "FR-009" to "FR-011"`,
+		`requirements/nonFunctional/0: renumber "FR-010" to "FR-012"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	var doc any
+	if err := json.Unmarshal([]byte(testDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := patch.Apply(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, err := Check(data); err != nil || len(issues) != 0 {
+		t.Errorf("issues after renumbering = %v, %v", issues, err)
+	}
+	if _, again, _ := Renumber(data); len(again) != 0 {
+		t.Errorf("renumbering twice changed %v", again)
+	}
+}
+
+func TestNextID(t *testing.T) {
+	used := map[string]bool{"checkout": true, "checkout-2": true, "US-7": true, "US-012": true, "v1.2": true}
+	for id, want := range map[string]string{
+		"checkout": "checkout-3",
+		"US-7":     "US-13",
+		"US-012":   "US-013",
+		"v1.2":     "v1.3",
+	} {
+		if got := nextID(id, used); got != want {
+			t.Errorf("nextID(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestCheckReferences(t *testing.T) {
+	doc := `{
+		"personas": [{"id": "p-1"}],
+		"userStories": [
+			{"id": "US-1", "personaId": "p-1", "phaseId": "phase-9"},
+			{"id": "US-2", "personaId": "", "dependencies": ["US-1", "US-7"]}
+		],
+		"requirements": {"functional": [{"id": "FR-1", "userStoryIds": ["US-1", "US-3"]}]},
+		"objectives": {"okrs": [{"objective": {"id": "O-1", "alignedWith": ["CO-1", "CO-2"]}}]},
+		"provenance": {"links": [{"itemId": "FR-1", "kind": "requirement", "sourceIds": ["sm-1"]}]}
+	}`
+
+	summarize := func(issues []validation.Issue) string {
+		var got []string
+		for _, is := range issues {
+			got = append(got, is.RuleID+" "+is.Pointer)
+		}
+		return strings.Join(got, "\n")
+	}
+
+	issues, err := CheckReferences([]byte(doc), ReferencesFor("prd", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"broken-reference /userStories/0/phaseId",
+		"broken-reference /userStories/1/dependencies/1",
+		"broken-reference /requirements/functional/0/userStoryIds/1",
+	}, "\n")
+	if got := summarize(issues); got != want {
+		t.Errorf("issues:\n%s\nwant:\n%s", got, want)
+	}
+	if msg := issues[0].Message; msg != `/userStories/0/phaseId: "phase-9" does not match any ID in the document` {
+		t.Errorf("message = %q", msg)
+	}
+
+	// With the IDs of its workspace, references that may cross documents
+	// are checked too.
+	issues, err = CheckReferences([]byte(doc), ReferencesFor("prd", map[string]bool{"CO-1": true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summarize(issues); !strings.Contains(got, "broken-reference /objectives/okrs/0/objective/alignedWith/1") ||
+		!strings.HasSuffix(got, "broken-reference /provenance/links/0/sourceIds/0") || len(issues) != 5 {
+		t.Errorf("issues with workspace:\n%s", got)
+	}
+	if msg := issues[3].Message; !strings.HasSuffix(msg, `"CO-2" does not match any ID in the document or its workspace`) {
+		t.Errorf("external message = %q", msg)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	doc := `{
+		"personas": [{"id": "dev", "name": "Developer"}],
+		"userStories": [
+			{"id": "US-DEV-01", "personaId": "dev", "acceptanceCriteria": [{"id": "US-DEV-01-AC-1"}, {}]},
+			{"personaId": "dev", "acceptanceCriteria": [{}]},
+			{"id": "story-x", "personaId": "dev"}
+		],
+		"requirements": {
+			"functional": [
+				{"id": "FR-002", "userStoryIds": ["US-DEV-01"]},
+				{"id": "FR-005"},
+				{"userStoryIds": ["story-x"]}
+			]
+		}
+	}`
+	opts := AssignOptions{
+		Patterns: map[string]string{
+			"$.userStories[*]":                       "US-{personaId:upper}-{n:2}",
+			"$.userStories[*].acceptanceCriteria[*]": "{parent}-AC-{n}",
+			"$.requirements.functional[*]":           "FR-{n:3}",
+		},
+		References: ReferenceFields["prd"],
+	}
+
+	tests := []struct {
+		renumber   bool
+		want       []string
+		references int
+	}{
+		{false, []string{
+			`userStories/0/acceptanceCriteria/1: assign "US-DEV-01-AC-2"`,
+			`userStories/1: assign "US-DEV-02"`,
+			`userStories/1/acceptanceCriteria/0: assign "US-DEV-02-AC-1"`,
+			`requirements/functional/2: assign "FR-006"`,
+		}, 0},
+		{true, []string{
+			`userStories/0/acceptanceCriteria/1: assign "US-DEV-01-AC-2"`,
+			`userStories/1: assign "US-DEV-02"`,
+			`userStories/1/acceptanceCriteria/0: assign "US-DEV-02-AC-1"`,
+			`requirements/functional/0: renumber "FR-002" to "FR-001"`,
+			`requirements/functional/1: renumber "FR-005" to "FR-002"`,
+			`requirements/functional/2: assign "FR-003"`,
+		}, 0},
+	}
+	for _, tt := range tests {
+		opts.Renumber = tt.renumber
+		a, err := Assign([]byte(doc), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, c := range a.Changes {
+			got = append(got, c.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") || a.References != tt.references {
+			t.Errorf("renumber=%v: changes:\n%s\nwant:\n%s\nreferences = %d", tt.renumber, strings.Join(got, "\n"), strings.Join(tt.want, "\n"), a.References)
+		}
+	}
+
+	// Renumbering a story updates the requirements that name it, and
+	// keeps the IDs that do not follow the pattern.
+	opts.Patterns = map[string]string{"$.userStories[*]": "US-{n:2}"}
+	renumbered := `{
+		"userStories": [{"id": "US-03"}, {"id": "US-07"}, {"id": "login"}],
+		"requirements": {"functional": [{"id": "FR-1", "userStoryIds": ["US-07", "login"]}]}
+	}`
+	a, err := Assign([]byte(renumbered), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v any
+	if err := json.Unmarshal([]byte(renumbered), &v); err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := a.Patch.Apply(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(fixed)
+	want := `{"requirements":{"functional":[{"id":"FR-1","userStoryIds":["US-02","login"]}]},"userStories":[{"id":"US-01"},{"id":"US-02"},{"id":"login"}]}`
+	if string(data) != want || a.References != 1 {
+		t.Errorf("renumbered = %s (%d references), want %s", data, a.References, want)
+	}
+
+	for _, bad := range []string{"US", "US-{n}-{n}", "US-{n:x}", "US-{name:title}-{n}", "US-{n"} {
+		opts.Patterns = map[string]string{"$.userStories[*]": bad}
+		if _, err := Assign([]byte(doc), opts); err == nil {
+			t.Errorf("Assign with pattern %q succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ids
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/validation"
+)
+
+// ReferenceFields maps a document type to the members holding IDs of other
+// items in the same document, as a string or a list of strings: the
+// personas and phases of user stories, the user stories of requirements,
+// appendix references, dependencies between stories and between phases, and
+// the themes and phases of roadmap epics and milestones. The dependencies of
+// TRD components are left out, since they often name outside services.
+var ReferenceFields = map[string][]string{
+	"prd": {
+		"personaId", "userStoryIds", "phaseId", "appendixRefs", "relatedIds",
+		"nfrIds", "criteriaIds", "uncoveredFrIds", "problemId", "selectedSolutionId",
+		"chosenOptionId", "keyResultId", "dependencies",
+	},
+	"mrd":     {"relatedIds", "chosenOptionId"},
+	"trd":     {"relatedIds", "chosenOptionId"},
+	"roadmap": {"phaseId", "phaseIds", "themeId", "dependencies"},
+	"v2mom":   {"methodId"},
+}
+
+// ExternalReferenceFields maps a document type to the members that may
+// name items defined in another document of the workspace, such as the
+// company objectives an OKR objective is aligned with, the roadmap
+// phases its key results target, or the MRD items a PRD item was derived
+// from.
+var ExternalReferenceFields = map[string][]string{
+	"prd":   {"alignedWith", "parentId", "sourceIds"},
+	"okr":   {"alignedWith", "parentId", "parentOkrId", "companyOkrIds", "phaseId"},
+	"v2mom": {"parentId"},
+}
+
+// ReferenceOptions configure CheckReferences.
+type ReferenceOptions struct {
+	// Fields are the members that must name an ID defined in the document.
+	Fields []string
+
+	// External are the members that must name an ID defined in the
+	// document or in Known. They are not checked if Known is nil.
+	External []string
+
+	// Known are the IDs defined outside the document, such as in the other
+	// documents of its workspace.
+	Known map[string]bool
+}
+
+// ReferencesFor returns the reference fields of a document type, checking
+// external references against known.
+func ReferencesFor(docType string, known map[string]bool) ReferenceOptions {
+	return ReferenceOptions{
+		Fields:   ReferenceFields[docType],
+		External: ExternalReferenceFields[docType],
+		Known:    known,
+	}
+}
+
+// CheckReferences reports the references of a JSON document that name an
+// ID it does not define, as errors pointing at the reference and naming it
+// in the message. In a list, such as userStoryIds, the pointer includes the
+// index of the ID.
+func CheckReferences(data []byte, opts ReferenceOptions) ([]validation.Issue, error) {
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	defined := map[string]bool{}
+	for _, d := range Definitions(root) {
+		defined[d.ID] = true
+	}
+
+	var issues []validation.Issue
+	check := func(id, pointer string, external bool) {
+		if id == "" || defined[id] || (external && opts.Known[id]) {
+			return
+		}
+		where := "the document"
+		if external {
+			where = "the document or its workspace"
+		}
+		issues = append(issues, validation.Issue{
+			RuleID:   validation.RuleBrokenReference,
+			Severity: validation.SeverityError,
+			Pointer:  pointer,
+			Message:  fmt.Sprintf("%s: %q does not match any ID in %s", pointer, id, where),
+		})
+	}
+
+	fields := opts.Fields
+	if opts.Known != nil {
+		fields = append(slices.Clone(fields), opts.External...)
+	}
+	walkReferences(root, fields, func(key, id, pointer string) {
+		check(id, pointer, opts.Known != nil && slices.Contains(opts.External, key))
+	})
+	return issues, nil
+}
@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// docxBlocks reads the body of a .docx file. Heading levels come from the
+// paragraph styles, "heading 1" to "heading 6" and "title", resolved through
+// styles.xml so renamed style IDs still work. Paragraphs with numbering are
+// list items.
+func docxBlocks(data []byte) ([]block, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading docx: %w", err)
+	}
+	document, err := zipFile(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	styles := map[string]string{}
+	if s, err := zipFile(zr, "word/styles.xml"); err == nil {
+		styles = docxStyleNames(s)
+	}
+	return (&docxReader{styles: styles}).read(document)
+}
+
+func zipFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading docx: %w", err)
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// docxStyleNames maps style IDs to lowercase style names.
+func docxStyleNames(data []byte) map[string]string {
+	var styles struct {
+		Styles []struct {
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Val string `xml:"val,attr"`
+			} `xml:"name"`
+		} `xml:"style"`
+	}
+	names := map[string]string{}
+	if xml.Unmarshal(data, &styles) == nil {
+		for _, s := range styles.Styles {
+			names[s.ID] = strings.ToLower(s.Name.Val)
+		}
+	}
+	return names
+}
+
+// wordNS is the WordprocessingML namespace. Elements of other namespaces,
+// such as the paragraphs of DrawingML text boxes, are skipped.
+const wordNS = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+type docxReader struct {
+	styles map[string]string
+	blocks []block
+
+	// Current paragraph
+	text      strings.Builder
+	style     string
+	list      bool
+	runBold   bool
+	boldRuns  int
+	plainRuns int
+
+	// Current table and cell. Nested tables are flattened into the cell
+	// of the outer table.
+	table [][]string
+	cell  []string
+}
+
+func (r *docxReader) read(data []byte) ([]block, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0 // Table nesting depth
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return r.blocks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading docx: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				r.text.Reset()
+				r.style, r.list, r.boldRuns, r.plainRuns = "", false, 0, 0
+			case "pStyle":
+				r.style = attr(t, "val")
+			case "numPr":
+				r.list = true
+			case "r":
+				r.runBold = false
+			case "b":
+				v := attr(t, "val")
+				r.runBold = v == "" || v == "1" || v == "true" || v == "on"
+			case "t":
+				var s string
+				if err := dec.DecodeElement(&s, &t); err != nil {
+					return nil, fmt.Errorf("reading docx: %w", err)
+				}
+				r.text.WriteString(s)
+				if strings.TrimSpace(s) != "" {
+					if r.runBold {
+						r.boldRuns++
+					} else {
+						r.plainRuns++
+					}
+				}
+			case "tab", "br", "cr":
+				r.text.WriteString(" ")
+			case "tbl":
+				depth++
+				if depth == 1 {
+					r.table = nil
+				}
+			case "tr":
+				if depth == 1 {
+					r.table = append(r.table, nil)
+				}
+			case "tc":
+				if depth == 1 {
+					r.cell = nil
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				r.paragraph(depth > 0)
+			case "tc":
+				if depth == 1 {
+					last := len(r.table) - 1
+					r.table[last] = append(r.table[last], strings.Join(r.cell, " "))
+				}
+			case "tbl":
+				depth--
+				if depth == 0 {
+					r.blocks = append(r.blocks, block{kind: blockTable, rows: r.table})
+				}
+			}
+		}
+	}
+}
+
+// paragraph ends the current paragraph, adding it to the current table
+// cell or as a block.
+func (r *docxReader) paragraph(inTable bool) {
+	text := cleanText(r.text.String())
+	if text == "" {
+		return
+	}
+	if inTable {
+		r.cell = append(r.cell, text)
+		return
+	}
+	b := block{kind: blockParagraph, text: text, bold: r.boldRuns > 0 && r.plainRuns == 0}
+	name := r.styles[r.style]
+	if name == "" {
+		name = strings.ToLower(r.style)
+	}
+	switch {
+	case name == "title":
+		b.kind = blockTitle
+	case strings.HasPrefix(name, "heading"):
+		level, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(name, "heading")))
+		if err == nil && level >= 1 {
+			b.kind, b.level = blockHeading, min(level, 6)
+		}
+	case r.list || strings.HasPrefix(name, "list"):
+		b.kind = blockListItem
+	}
+	r.blocks = append(r.blocks, b)
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,273 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// boldClass matches CSS rules that make a class bold, as in the style
+// sheet of a Google Docs HTML export: ".c4{font-weight:700}".
+var boldClass = regexp.MustCompile(`\.([\w-]+)\s*\{[^}]*font-weight:\s*(?:700|bold)`)
+
+// htmlBlocks reads an HTML document, or the first .html file of a zip such
+// as a Google Docs "Web page (.html, zipped)" download. Google Docs marks
+// the title with a "title" class and bold text with classes defined in its
+// style sheet; both are recognized. Comment and footnote text is dropped.
+func htmlBlocks(data []byte) ([]block, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		var err error
+		if data, err = htmlFromZip(data); err != nil {
+			return nil, err
+		}
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	return (&htmlReader{boldClasses: map[string]bool{}}).read(dec)
+}
+
+func htmlFromZip(data []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading zip: %w", err)
+	}
+	for _, f := range zr.File {
+		if ext := strings.ToLower(path.Ext(f.Name)); ext == ".html" || ext == ".htm" {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	}
+	return nil, fmt.Errorf("zip has no .html file")
+}
+
+type htmlReader struct {
+	boldClasses map[string]bool
+	blocks      []block
+
+	// Open elements, innermost last, and whether each makes text bold.
+	stack []htmlElement
+
+	// Current block
+	cur        *block
+	text       strings.Builder
+	boldText   int
+	plainText  int
+	drop       bool
+	table      [][]string
+	tableDepth int
+	cell       []string
+}
+
+type htmlElement struct {
+	name string
+	bold bool
+}
+
+// skipped are elements whose content is not document text.
+var skipped = map[string]bool{"head": true, "style": true, "script": true, "title": true, "sup": true}
+
+func (r *htmlReader) read(dec *xml.Decoder) ([]block, error) {
+	skip := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			r.flush()
+			return r.blocks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading html: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if skipped[name] {
+				if name == "style" {
+					r.styleSheet(dec)
+					continue
+				}
+				skip++
+				r.stack = append(r.stack, htmlElement{name: name})
+				continue
+			}
+			r.stack = append(r.stack, htmlElement{name: name, bold: r.isBold(name, t)})
+			if skip == 0 {
+				r.start(name, t)
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			// Pop up to the matching element, tolerating unclosed tags.
+			for i := len(r.stack) - 1; i >= 0; i-- {
+				if r.stack[i].name != name {
+					continue
+				}
+				for _, e := range r.stack[i:] {
+					if skipped[e.name] {
+						skip--
+					} else if skip == 0 {
+						r.end(e.name)
+					}
+				}
+				r.stack = r.stack[:i]
+				break
+			}
+		case xml.CharData:
+			if skip == 0 {
+				r.addText(string(t))
+			}
+		}
+	}
+}
+
+// styleSheet reads the content of a style element for bold classes.
+func (r *htmlReader) styleSheet(dec *xml.Decoder) {
+	var css strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			css.Write(t)
+		case xml.EndElement:
+			for _, m := range boldClass.FindAllStringSubmatch(css.String(), -1) {
+				r.boldClasses[m[1]] = true
+			}
+			return
+		}
+	}
+}
+
+func (r *htmlReader) isBold(name string, e xml.StartElement) bool {
+	if name == "b" || name == "strong" {
+		return true
+	}
+	for _, class := range strings.Fields(attr(e, "class")) {
+		if r.boldClasses[class] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *htmlReader) start(name string, e xml.StartElement) {
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.open(block{kind: blockHeading, level: int(name[1] - '0')})
+	case "p":
+		if r.cur != nil && r.cur.kind == blockListItem {
+			return // Paragraph inside a list item
+		}
+		b := block{kind: blockParagraph}
+		for _, class := range strings.Fields(attr(e, "class")) {
+			if class == "title" {
+				b.kind = blockTitle
+			}
+		}
+		r.open(b)
+	case "li":
+		r.open(block{kind: blockListItem})
+	case "div", "ul", "ol", "blockquote", "pre":
+		r.flush()
+	case "table":
+		r.flush()
+		r.tableDepth++
+		if r.tableDepth == 1 {
+			r.table = nil
+		}
+	case "tr":
+		if r.tableDepth == 1 {
+			r.table = append(r.table, nil)
+		}
+	case "td", "th":
+		if r.tableDepth == 1 {
+			r.cell = nil
+		}
+	case "br":
+		r.text.WriteString(" ")
+	case "a":
+		// Google Docs comment and footnote text links back to its anchor.
+		if id := attr(e, "id"); strings.HasPrefix(id, "cmnt") || strings.HasPrefix(id, "ftnt") {
+			r.drop = true
+		}
+	}
+}
+
+func (r *htmlReader) end(name string) {
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6", "p", "li", "div":
+		if name == "p" && r.cur != nil && r.cur.kind == blockListItem {
+			return
+		}
+		r.flush()
+	case "td", "th":
+		r.flush()
+		if r.tableDepth == 1 && len(r.table) > 0 {
+			last := len(r.table) - 1
+			r.table[last] = append(r.table[last], strings.Join(r.cell, " "))
+		}
+	case "table":
+		r.flush()
+		r.tableDepth--
+		if r.tableDepth == 0 {
+			r.blocks = append(r.blocks, block{kind: blockTable, rows: r.table})
+		}
+	}
+}
+
+// open flushes the current block and starts b.
+func (r *htmlReader) open(b block) {
+	r.flush()
+	r.cur = &b
+}
+
+func (r *htmlReader) addText(s string) {
+	if strings.TrimSpace(s) == "" {
+		if r.text.Len() > 0 {
+			r.text.WriteString(" ")
+		}
+		return
+	}
+	if r.cur == nil {
+		r.cur = &block{kind: blockParagraph}
+	}
+	r.text.WriteString(s)
+	bold := false
+	for _, e := range r.stack {
+		bold = bold || e.bold
+	}
+	if bold {
+		r.boldText++
+	} else {
+		r.plainText++
+	}
+}
+
+// flush ends the current block, adding it to the current table cell or as
+// a block.
+func (r *htmlReader) flush() {
+	b, text, drop := r.cur, cleanText(r.text.String()), r.drop
+	bold := r.boldText > 0 && r.plainText == 0
+	r.cur, r.drop, r.boldText, r.plainText = nil, false, 0, 0
+	r.text.Reset()
+	if b == nil || text == "" || drop {
+		return
+	}
+	if r.tableDepth > 0 {
+		r.cell = append(r.cell, text)
+		return
+	}
+	b.text, b.bold = text, bold
+	r.blocks = append(r.blocks, *b)
+}
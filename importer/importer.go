@@ -0,0 +1,171 @@
+// Package importer converts documents exported from word processors, Word
+// .docx files and Google Docs HTML, to markdown, and imports them as PRDs
+// with the PRD markdown importer.
+//
+// Conversion keeps only the structure the PRD importer reads: headings,
+// paragraphs, list items, bold labels, and tables. Images, comments, and
+// character formatting other than bold are dropped.
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Format is the format of a document to import.
+type Format string
+
+// Import formats.
+const (
+	FormatMarkdown Format = "markdown"
+	FormatDOCX     Format = "docx"
+	FormatHTML     Format = "html"
+)
+
+// FormatFromPath returns the format for a file extension: .docx, .html or
+// .htm (also a .zip of a Google Docs HTML export), and markdown otherwise.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".docx":
+		return FormatDOCX
+	case ".html", ".htm", ".zip":
+		return FormatHTML
+	}
+	return FormatMarkdown
+}
+
+// ParseFormat parses a format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatMarkdown, FormatDOCX, FormatHTML:
+		return f, nil
+	case "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown import format: %s (expected markdown, docx, or html)", s)
+}
+
+// ToMarkdown converts data in the given format to markdown.
+func ToMarkdown(data []byte, format Format) ([]byte, error) {
+	var blocks []block
+	var err error
+	switch format {
+	case FormatMarkdown:
+		return data, nil
+	case FormatDOCX:
+		blocks, err = docxBlocks(data)
+	case FormatHTML:
+		blocks, err = htmlBlocks(data)
+	default:
+		return nil, fmt.Errorf("unknown import format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(writeMarkdown(blocks)), nil
+}
+
+// PRD converts data to markdown and imports it as a PRD.
+func PRD(data []byte, format Format, opts prd.ImportOptions) (*prd.Document, *prd.ImportReport, error) {
+	md, err := ToMarkdown(data, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, report := prd.ImportMarkdownWithOptions(md, opts)
+	return doc, report, nil
+}
+
+type blockKind int
+
+const (
+	blockHeading blockKind = iota
+	blockTitle
+	blockParagraph
+	blockListItem
+	blockTable
+)
+
+// block is a unit of converted content.
+type block struct {
+	kind  blockKind
+	level int // Heading level
+	text  string
+	bold  bool // The whole paragraph is bold
+	rows  [][]string
+}
+
+// writeMarkdown writes blocks as markdown. A title becomes the only level 1
+// heading, and headings move down a level to make room for it.
+func writeMarkdown(blocks []block) string {
+	shift := 0
+	for _, b := range blocks {
+		if b.kind == blockTitle {
+			shift = 1
+			break
+		}
+	}
+
+	var sb strings.Builder
+	titled := false
+	for i, b := range blocks {
+		if i > 0 && !(b.kind == blockListItem && blocks[i-1].kind == blockListItem) {
+			sb.WriteString("\n")
+		}
+		switch b.kind {
+		case blockTitle:
+			if titled {
+				sb.WriteString(b.text + "\n")
+				continue
+			}
+			titled = true
+			sb.WriteString("# " + b.text + "\n")
+		case blockHeading:
+			sb.WriteString(strings.Repeat("#", min(b.level+shift, 6)) + " " + b.text + "\n")
+		case blockListItem:
+			sb.WriteString("- " + b.text + "\n")
+		case blockTable:
+			writeTable(&sb, b.rows)
+		default:
+			if b.bold {
+				sb.WriteString("**" + b.text + "**\n")
+			} else {
+				sb.WriteString(b.text + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// writeTable writes rows as a markdown table with the first row as the
+// header. Pipes in cells are replaced by slashes so they do not split
+// cells.
+func writeTable(sb *strings.Builder, rows [][]string) {
+	cols := 0
+	for _, r := range rows {
+		cols = max(cols, len(r))
+	}
+	if cols == 0 {
+		return
+	}
+	for i, r := range rows {
+		cells := make([]string, cols)
+		for j := range cells {
+			if j < len(r) {
+				cells[j] = strings.ReplaceAll(r[j], "|", "/")
+			}
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat("---|", cols) + "\n")
+		}
+	}
+}
+
+// cleanText collapses runs of whitespace, non-breaking spaces included,
+// to single spaces.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// googleDocsHTML is shaped like a Google Docs "Web page" export.
+const googleDocsHTML = `<html><head><meta content="text/html; charset=UTF-8" http-equiv="content-type">
+<style type="text/css">ol.lst-kix_a>li{counter-increment:lst}.c4{font-weight:700}.c2{color:#000000}</style></head>
+<body class="c9 doc-content">
+<p class="c3 title" id="h.t"><span class="c2">Checkout&nbsp;Redesign</span></p>
+<h1 class="c1" id="h.a"><span>Background</span></h1>
+<p class="c0"><span>Cart abandonment is 38%.</span><sup><a href="#cmnt1" id="cmnt_ref1">[a]</a></sup></p>
+<h1 class="c1"><span>Personas</span></h1>
+<h2><span>Shopper</span></h2>
+<p class="c0"><span class="c4">Goals:</span></p>
+<ul class="c5 lst-kix_a"><li class="c0"><span>Pay quickly</span></li></ul>
+<h1><span>Requirements and User Stories</span></h1>
+<table class="c7"><tr><td><p><span>ID</span></p></td><td><p><span>Title</span></p></td><td><p><span>Priority</span></p></td></tr>
+<tr><td><p><span>PAY-1</span></p></td><td><p><span>Saved cards | wallets</span></p></td><td><p><span>Must</span></p></td></tr></table>
+<h1><span>Launch Plan</span></h1>
+<p><span>Roll out to 10%<br>of traffic.</span></p>
+<div><p><a href="#cmnt_ref1" id="cmnt1">[a]</a><span>Source?</span></p></div>
+</body></html>`
+
+func TestHTMLToMarkdown(t *testing.T) {
+	md, err := ToMarkdown([]byte(googleDocsHTML), FormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(md)
+	for _, want := range []string{
+		"# Checkout Redesign\n",
+		"## Background\n\nCart abandonment is 38%.\n",
+		"### Shopper\n\n**Goals:**\n\n- Pay quickly\n",
+		"| ID | Title | Priority |\n|---|---|---|\n| PAY-1 | Saved cards / wallets | Must |\n",
+		"Roll out to 10% of traffic.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"[a]", "Source?", "counter-increment"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("unexpected %q in:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestPRDFromHTML(t *testing.T) {
+	var asked []string
+	opts := prd.ImportOptions{
+		Mapping: map[string]prd.ImportTarget{"launch plan": prd.ImportRoadmap},
+		Resolve: func(heading string, candidates []prd.ImportTarget) prd.ImportTarget {
+			asked = append(asked, heading)
+			return prd.ImportRequirements
+		},
+	}
+	doc, report, err := PRD([]byte(googleDocsHTML), FormatHTML, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Metadata.Title != "Checkout Redesign" || doc.ExecutiveSummary.ProblemStatement != "Cart abandonment is 38%." {
+		t.Errorf("metadata = %+v, summary = %+v", doc.Metadata, doc.ExecutiveSummary)
+	}
+	if len(doc.Personas) != 1 || len(doc.Personas[0].Goals) != 1 {
+		t.Errorf("personas = %+v", doc.Personas)
+	}
+	if len(asked) != 1 || asked[0] != "Requirements and User Stories" {
+		t.Errorf("resolver asked about %v", asked)
+	}
+	if fr := doc.Requirements.Functional; len(fr) != 1 || fr[0].ID != "PAY-1" {
+		t.Errorf("functional = %+v", fr)
+	}
+	if len(report.Ambiguous) != 1 || report.Ambiguous[0].Chosen != prd.ImportRequirements {
+		t.Errorf("ambiguous = %+v", report.Ambiguous)
+	}
+	if len(report.Unmapped) != 0 {
+		t.Errorf("unmapped = %+v", report.Unmapped)
+	}
+}
+
+const docxDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Search</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="H1x"/></w:pPr><w:r><w:t>Problem</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Users cannot </w:t></w:r><w:r><w:t>find orders.</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="H1x"/></w:pPr><w:r><w:t>Out of Scope</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Fuzzy search</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t>Notes</w:t></w:r></w:p>
+<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Owner:</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Term</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Definition</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>SKU</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Stock</w:t></w:r></w:p><w:p><w:r><w:t>keeping unit</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+</w:body></w:document>`
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:style w:type="paragraph" w:styleId="H1x"><w:name w:val="heading 1"/></w:style>
+</w:styles>`
+
+func TestDOCXToMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"word/document.xml": docxDocument, "word/styles.xml": docxStyles} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := ToMarkdown(buf.Bytes(), FormatDOCX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Search
+
+## Problem
+
+Users cannot find orders.
+
+## Out of Scope
+
+- Fuzzy search
+
+### Notes
+
+**Owner:**
+
+| Term | Definition |
+|---|---|
+| SKU | Stock keeping unit |
+`
+	if string(md) != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", md, want)
+	}
+
+	if _, err := ToMarkdown([]byte("not a zip"), FormatDOCX); err == nil {
+		t.Error("ToMarkdown of an invalid docx succeeded")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]Format{
+		"spec.docx": FormatDOCX, "Spec.HTML": FormatHTML, "export.zip": FormatHTML, "legacy.md": FormatMarkdown,
+	} {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
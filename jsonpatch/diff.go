@@ -0,0 +1,109 @@
+package jsonpatch
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Diff returns a patch that turns the decoded JSON document from into to.
+// Members are added, removed, or replaced where they differ; arrays that
+// only grew get their new elements appended, and other changed arrays are
+// replaced whole.
+func Diff(from, to any) Patch {
+	return Rebase(from, from, to)
+}
+
+// Rebase returns a patch that makes to doc the changes that turn base into
+// changed. base is the form doc was read as, such as the JSON encoding of
+// the struct it was decoded into, which may lack members doc has or have
+// defaults doc leaves out. The patch leaves the members of doc that base
+// and changed agree on alone, so members base lacks survive, and an array
+// replaced whole keeps the doc element of each element left unchanged.
+func Rebase(doc, base, changed any) Patch {
+	p := Patch{}
+	rebase(&p, nil, clone(doc), clone(base), clone(changed))
+	return p
+}
+
+// rebase appends to p the operations making the changes from base to
+// changed to doc, which is at the path given by tokens.
+func rebase(p *Patch, tokens []any, doc, base, changed any) {
+	if reflect.DeepEqual(base, changed) {
+		return
+	}
+	at := func(t any) []any { return append(slices.Clone(tokens), t) }
+
+	switch c := changed.(type) {
+	case map[string]any:
+		d, dok := doc.(map[string]any)
+		b, bok := base.(map[string]any)
+		if !dok || !bok {
+			break
+		}
+		for _, k := range sortedKeys(c) {
+			bv, inBase := b[k]
+			if inBase && reflect.DeepEqual(bv, c[k]) {
+				continue
+			}
+			if dv, inDoc := d[k]; inDoc && inBase {
+				rebase(p, at(k), dv, bv, c[k])
+				continue
+			}
+			*p = append(*p, Add(Pointer(at(k)...), c[k]))
+		}
+		for _, k := range sortedKeys(b) {
+			if _, kept := c[k]; kept {
+				continue
+			}
+			if _, inDoc := d[k]; inDoc {
+				*p = append(*p, Remove(Pointer(at(k)...)))
+			}
+		}
+		return
+
+	case []any:
+		d, dok := doc.([]any)
+		b, bok := base.([]any)
+		if !dok || !bok || len(d) != len(b) {
+			break
+		}
+		if len(c) == len(b) {
+			for i := range c {
+				rebase(p, at(i), d[i], b[i], c[i])
+			}
+			return
+		}
+		if len(c) > len(b) && reflect.DeepEqual(b, c[:len(b)]) {
+			for _, e := range c[len(b):] {
+				*p = append(*p, Add(Pointer(at("-")...), e))
+			}
+			return
+		}
+		// Keep the doc element of each unchanged element, wherever it
+		// moved.
+		used := make([]bool, len(b))
+		merged := make([]any, len(c))
+		for i, e := range c {
+			merged[i] = e
+			for j := range b {
+				if !used[j] && reflect.DeepEqual(b[j], e) {
+					used[j] = true
+					merged[i] = d[j]
+					break
+				}
+			}
+		}
+		*p = append(*p, Replace(Pointer(tokens...), merged))
+		return
+	}
+	*p = append(*p, Replace(Pointer(tokens...), changed))
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,361 @@
+// Package jsonpatch implements the add, remove, replace, and test
+// operations of JSON Patch (RFC 6902), enough to carry mechanical fixes
+// from a quality report to the document they apply to.
+//
+// Patches apply to decoded JSON values (map[string]any, []any, and
+// scalars). ApplyTo applies a patch to any JSON-encodable value by
+// round-tripping it through JSON. Diff and Rebase compute patches between
+// decoded values.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation names.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpTest    = "test"
+)
+
+// Operation is one JSON Patch operation. Path is a JSON pointer (RFC 6901).
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON omits the value of remove operations. Other operations keep
+// it even when it is empty or null, as RFC 6902 requires.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == OpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type plain Operation
+	return json.Marshal(plain(o))
+}
+
+// Patch is a sequence of operations applied in order.
+type Patch []Operation
+
+// Add returns an add operation.
+func Add(path string, value any) Operation { return Operation{Op: OpAdd, Path: path, Value: value} }
+
+// Remove returns a remove operation.
+func Remove(path string) Operation { return Operation{Op: OpRemove, Path: path} }
+
+// Replace returns a replace operation.
+func Replace(path string, value any) Operation {
+	return Operation{Op: OpReplace, Path: path, Value: value}
+}
+
+// Test returns a test operation, which fails the patch unless the value at
+// path equals value.
+func Test(path string, value any) Operation { return Operation{Op: OpTest, Path: path, Value: value} }
+
+// Pointer builds a JSON pointer from reference tokens, escaping "~" and
+// "/". Tokens may be strings or ints.
+func Pointer(tokens ...any) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		s := fmt.Sprint(t)
+		s = strings.ReplaceAll(s, "~", "~0")
+		s = strings.ReplaceAll(s, "/", "~1")
+		sb.WriteString("/" + s)
+	}
+	return sb.String()
+}
+
+// Apply applies the patch to a decoded JSON document and returns the
+// result. The patch is applied atomically: on error, doc is returned
+// unchanged along with the error.
+func (p Patch) Apply(doc any) (any, error) {
+	doc = clone(doc)
+	for i, op := range p {
+		var err error
+		doc, err = apply(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// SkipMissingTests returns the patch without the test operations whose
+// path is not in doc by the time they are reached, such as a test that a
+// member is empty where the document leaves the member out. The other
+// operations are kept whether or not they apply.
+func (p Patch) SkipMissingTests(doc any) Patch {
+	doc = clone(doc)
+	out := make(Patch, 0, len(p))
+	for _, op := range p {
+		if op.Op == OpTest {
+			if _, err := Get(doc, op.Path); err != nil {
+				continue
+			}
+		}
+		out = append(out, op)
+		if next, err := apply(doc, op); err == nil {
+			doc = next
+		}
+	}
+	return out
+}
+
+// ApplyTo applies the patch to v, which must be a pointer to a value that
+// encodes to and decodes from JSON.
+func (p Patch) ApplyTo(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling document: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decoding document: %w", err)
+	}
+	patched, err := p.Apply(doc)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(patched)
+	if err != nil {
+		return fmt.Errorf("marshaling patched document: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding patched document: %w", err)
+	}
+	return nil
+}
+
+// Get returns the value at path in a decoded JSON document.
+func Get(doc any, path string) (any, error) {
+	tokens, err := ParsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(doc, tokens)
+}
+
+func apply(doc any, op Operation) (any, error) {
+	tokens, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	value := normalize(op.Value)
+
+	if len(tokens) == 0 {
+		switch op.Op {
+		case OpAdd, OpReplace:
+			return value, nil
+		case OpTest:
+			return doc, test(doc, value)
+		case OpRemove:
+			return nil, fmt.Errorf("cannot remove the document root")
+		}
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+
+	parent, err := resolve(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+
+	switch c := parent.(type) {
+	case map[string]any:
+		current, exists := c[last]
+		switch op.Op {
+		case OpAdd:
+			c[last] = value
+		case OpReplace:
+			if !exists {
+				return nil, fmt.Errorf("member %q does not exist", last)
+			}
+			c[last] = value
+		case OpRemove:
+			if !exists {
+				return nil, fmt.Errorf("member %q does not exist", last)
+			}
+			delete(c, last)
+		case OpTest:
+			if !exists {
+				return nil, fmt.Errorf("member %q does not exist", last)
+			}
+			return doc, test(current, value)
+		default:
+			return nil, fmt.Errorf("unknown operation %q", op.Op)
+		}
+		return doc, nil
+
+	case []any:
+		// Arrays are replaced in their parent, since adding and removing
+		// elements changes the slice.
+		var updated []any
+		switch op.Op {
+		case OpAdd:
+			i := len(c)
+			if last != "-" {
+				if i, err = index(last, len(c)+1); err != nil {
+					return nil, err
+				}
+			}
+			updated = append(append(append([]any{}, c[:i]...), value), c[i:]...)
+		case OpReplace, OpRemove, OpTest:
+			i, err := index(last, len(c))
+			if err != nil {
+				return nil, err
+			}
+			switch op.Op {
+			case OpTest:
+				return doc, test(c[i], value)
+			case OpReplace:
+				c[i] = value
+				return doc, nil
+			}
+			updated = append(append([]any{}, c[:i]...), c[i+1:]...)
+		default:
+			return nil, fmt.Errorf("unknown operation %q", op.Op)
+		}
+		return set(doc, tokens[:len(tokens)-1], updated)
+	}
+	return nil, fmt.Errorf("%s is not an object or array", Pointer(anySlice(tokens[:len(tokens)-1])...))
+}
+
+func test(got, want any) error {
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("test failed: value is %s", compact(got))
+	}
+	return nil
+}
+
+// resolve returns the value at the path given by tokens.
+func resolve(doc any, tokens []string) (any, error) {
+	cur := doc
+	for n, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", Pointer(anySlice(tokens[:n+1])...))
+			}
+			cur = v
+		case []any:
+			i, err := index(t, len(c))
+			if err != nil {
+				return nil, err
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("%s is not an object or array", Pointer(anySlice(tokens[:n])...))
+		}
+	}
+	return cur, nil
+}
+
+// set replaces the value at the path given by tokens and returns the
+// updated document.
+func set(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := resolve(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch c := parent.(type) {
+	case map[string]any:
+		c[last] = value
+	case []any:
+		i, err := index(last, len(c))
+		if err != nil {
+			return nil, err
+		}
+		c[i] = value
+	}
+	return doc, nil
+}
+
+// ParsePointer returns the unescaped reference tokens of a JSON pointer.
+func ParsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// index parses an array index, which must be below limit.
+func index(token string, limit int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// normalize converts a Go value to its decoded JSON form, so values built
+// in code compare equal to values read from a document.
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func clone(v any) any {
+	switch c := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(c))
+		for k, e := range c {
+			m[k] = clone(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(c))
+		for i, e := range c {
+			s[i] = clone(e)
+		}
+		return s
+	}
+	return v
+}
+
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func anySlice(tokens []string) []any {
+	out := make([]any, len(tokens))
+	for i, t := range tokens {
+		out[i] = t
+	}
+	return out
+}
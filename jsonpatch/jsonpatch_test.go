@@ -0,0 +1,158 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestApply(t *testing.T) {
+	doc := decode(t, `{"id":"","tags":["a","b"],"items":[{"id":"x"}]}`)
+	patch := Patch{
+		Test("/id", ""),
+		Add("/id", "PRD-1"),
+		Add("/tags/-", "c"),
+		Remove("/tags/0"),
+		Replace("/items/0/id", "y"),
+		Add(Pointer("a/b~c"), 1),
+	}
+	got, err := patch.Apply(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decode(t, `{"id":"PRD-1","tags":["b","c"],"items":[{"id":"y"}],"a/b~c":1}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if doc.(map[string]any)["id"] != "" {
+		t.Error("Apply modified its input")
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	doc := decode(t, `{"tags":["a"]}`)
+	for _, op := range []Operation{
+		Add("/missing/id", "x"),
+		Replace("/missing", "x"),
+		Remove("/tags/1"),
+		Test("/tags/0", "b"),
+		Add("/tags/01", "b"),
+		Add("tags", "b"),
+	} {
+		if _, err := (Patch{op}).Apply(doc); err == nil {
+			t.Errorf("%s %s: expected error", op.Op, op.Path)
+		}
+	}
+}
+
+func TestApplyAtomic(t *testing.T) {
+	doc := decode(t, `{"id":"PRD-1","status":""}`)
+	_, err := Patch{Add("/status", "draft"), Test("/id", "")}.Apply(doc)
+	if err == nil || !strings.Contains(err.Error(), "operation 1 (test /id)") {
+		t.Fatalf("err = %v", err)
+	}
+	if doc.(map[string]any)["status"] != "" {
+		t.Error("failed patch modified the document")
+	}
+}
+
+func TestApplyTo(t *testing.T) {
+	type item struct {
+		ID   string   `json:"id"`
+		Tags []string `json:"tags,omitempty"`
+	}
+	v := item{Tags: []string{"A", "A"}}
+	if err := (Patch{Test("/id", ""), Add("/id", "x"), Replace("/tags", []string{"a"})}).ApplyTo(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID != "x" || !reflect.DeepEqual(v.Tags, []string{"a"}) {
+		t.Errorf("got %+v", v)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := json.Marshal(Patch{Remove("/a"), Add("/b", nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"op":"remove","path":"/a"},{"op":"add","path":"/b","value":null}]`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestSkipMissingTests(t *testing.T) {
+	doc := decode(t, `{"items":[{"title":"a"},{"id":"","title":"b"}]}`)
+	p := Patch{
+		Test("/items/0/id", ""), Add("/items/0/id", "x"),
+		Test("/items/1/id", ""), Add("/items/1/id", "y"),
+		Test("/items/0/id", "x"),
+	}
+	got := p.SkipMissingTests(doc)
+	if len(got) != 4 || got[0].Op != OpAdd || got[1].Path != "/items/1/id" || got[3].Op != OpTest {
+		t.Fatalf("SkipMissingTests = %+v", got)
+	}
+	if _, err := got.Apply(doc); err != nil {
+		t.Errorf("Apply: %v", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc := decode(t, `{"items":[{"id":"x"}]}`)
+	if v, err := Get(doc, "/items/0/id"); err != nil || v != "x" {
+		t.Errorf("Get = %v, %v", v, err)
+	}
+	if _, err := Get(doc, "/items/1"); err == nil {
+		t.Error("expected error for a missing element")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := decode(t, `{"id":"PRD-1","tags":["a"],"items":[{"id":"x"},{"id":"y"}],"old":true}`)
+	to := decode(t, `{"id":"PRD-2","tags":["a","b"],"items":[{"id":"y"}],"new":{"n":1}}`)
+	patch := Diff(from, to)
+	got, err := patch.Apply(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, to) {
+		t.Errorf("got %v, want %v", got, to)
+	}
+	if len(Diff(to, to)) != 0 {
+		t.Error("Diff of equal documents should be empty")
+	}
+}
+
+func TestRebase(t *testing.T) {
+	// doc has members base lacks, base has defaults doc leaves out.
+	doc := decode(t, `{"id":"PRD-1","legacy":{"a":1},"items":[{"id":"x","extra":1},{"id":"y","extra":2}]}`)
+	base := decode(t, `{"id":"PRD-1","okrs":{},"items":[{"id":"x"},{"id":"y"}]}`)
+	changed := decode(t, `{"id":"PRD-1","okrs":{},"items":[{"id":"y"}],"comments":[{"text":"a & b"}]}`)
+	got, err := Rebase(doc, base, changed).Apply(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decode(t, `{"id":"PRD-1","legacy":{"a":1},"items":[{"id":"y","extra":2}],"comments":[{"text":"a & b"}]}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	changed = decode(t, `{"id":"PRD-1","okrs":{},"items":[{"id":"x2"},{"id":"y"}]}`)
+	got, err = Rebase(doc, base, changed).Apply(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = decode(t, `{"id":"PRD-1","legacy":{"a":1},"items":[{"id":"x2","extra":1},{"id":"y","extra":2}]}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
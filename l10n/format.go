@@ -0,0 +1,49 @@
+package l10n
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a translation file format.
+type Format string
+
+const (
+	FormatXLIFF Format = "xliff"
+	FormatPO    Format = "po"
+)
+
+// FormatFromPath infers the translation file format from its extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xliff", ".xlf":
+		return FormatXLIFF, nil
+	case ".po", ".pot":
+		return FormatPO, nil
+	}
+	return "", fmt.Errorf("unrecognized translation file extension %q (use .xliff, .xlf, or .po)", filepath.Ext(path))
+}
+
+// Write writes units in the given format.
+func Write(w io.Writer, format Format, units []Unit, opts FileOptions) error {
+	switch format {
+	case FormatXLIFF:
+		return WriteXLIFF(w, units, opts)
+	case FormatPO:
+		return WritePO(w, units, opts)
+	}
+	return fmt.Errorf("unknown translation format %q", format)
+}
+
+// Read reads units in the given format.
+func Read(r io.Reader, format Format) ([]Unit, error) {
+	switch format {
+	case FormatXLIFF:
+		return ReadXLIFF(r)
+	case FormatPO:
+		return ReadPO(r)
+	}
+	return nil, fmt.Errorf("unknown translation format %q", format)
+}
@@ -0,0 +1,244 @@
+// Package l10n extracts the user-facing narrative text of a planning
+// document for translation and applies translations back to produce a
+// localized copy.
+//
+// Strings are identified by stable keys built from the JSON path, using
+// element IDs rather than array positions wherever possible, for example
+// "requirements.functional[FR-001].description". Keys therefore survive
+// reordering and insertion of requirements, and a translation file from an
+// earlier revision can be re-applied to a later one.
+//
+// Extraction works on any document type. Identifiers, enumerations (status,
+// priority, ...), dates, URLs, tags, and people are not narrative and are
+// left untouched.
+package l10n
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unit is a single translatable string.
+type Unit struct {
+	// Key is the stable identifier of the string within the document.
+	Key string `json:"key"`
+
+	// Source is the original text.
+	Source string `json:"source"`
+
+	// Target is the translated text, empty if not yet translated.
+	Target string `json:"target,omitempty"`
+}
+
+// skipKeys are object members whose values are never narrative text.
+var skipKeys = map[string]bool{
+	"$schema":     true,
+	"id":          true,
+	"status":      true,
+	"priority":    true,
+	"type":        true,
+	"version":     true,
+	"email":       true,
+	"url":         true,
+	"tags":        true,
+	"severity":    true,
+	"probability": true,
+	"likelihood":  true,
+	"impact":      true,
+	"effort":      true,
+	"confidence":  true,
+	"format":      true,
+	"unit":        true,
+	"phaseType":   true,
+	"dueDate":     true,
+	"quarter":     true,
+	"fiscalYear":  true,
+	"period":      true,
+	"periodType":  true,
+	"structure":   true,
+	"terminology": true,
+
+	// People and links.
+	"author":        true,
+	"authors":       true,
+	"reviewers":     true,
+	"approvers":     true,
+	"contributors":  true,
+	"owner":         true,
+	"stakeholders":  true,
+	"externalLinks": true,
+	"links":         true,
+}
+
+// skipSuffixes mark camelCase keys that hold identifiers, dates, or links,
+// such as "parentId", "relatedIds", "createdAt", "startDate", "appendixRefs".
+var skipSuffixes = []string{"Id", "ID", "Ids", "IDs", "At", "Date", "URL", "Url", "Ref", "Refs"}
+
+var (
+	enumPattern = regexp.MustCompile(`^[a-z0-9]+([_.\-][a-z0-9]+)*$`)
+	codePattern = regexp.MustCompile(`^[A-Z0-9]+([_.\-][A-Z0-9]+)*$`)
+	datePattern = regexp.MustCompile(`^\d{4}-\d{2}(-\d{2})?([T ]|$)`)
+)
+
+func skipKey(key string) bool {
+	if skipKeys[key] {
+		return true
+	}
+	for _, s := range skipSuffixes {
+		if len(key) > len(s) && strings.HasSuffix(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNarrative reports whether a string value reads as prose rather than an
+// enumeration value, code, date, or URL.
+func isNarrative(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" || !strings.ContainsFunc(s, isLetter) {
+		return false
+	}
+	if enumPattern.MatchString(s) || codePattern.MatchString(s) || datePattern.MatchString(s) {
+		return false
+	}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "mailto:") {
+		return false
+	}
+	return true
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 0x7f
+}
+
+// Extract returns the translatable strings of a JSON document, in document
+// order.
+func Extract(data []byte) ([]Unit, error) {
+	root, err := parseTree(data)
+	if err != nil {
+		return nil, err
+	}
+	var units []Unit
+	walk(root, "", func(key string, n *node) {
+		units = append(units, Unit{Key: key, Source: n.scalar.(string)})
+	})
+	return units, nil
+}
+
+// ApplyResult summarizes an Apply call.
+type ApplyResult struct {
+	// Applied counts strings replaced with their translation.
+	Applied int
+
+	// Untranslated lists keys with no translation; the source text is kept.
+	Untranslated []string
+
+	// Stale lists keys whose source text changed since extraction; the
+	// current text is kept so outdated translations are never applied.
+	Stale []string
+
+	// Unknown lists translation keys not found in the document.
+	Unknown []string
+}
+
+// Apply replaces each translatable string in the JSON document with the
+// Target of the unit sharing its key, and returns the translated document.
+// Units whose Source no longer matches the document are reported as stale
+// and skipped. Field order is preserved.
+func Apply(data []byte, units []Unit) ([]byte, *ApplyResult, error) {
+	root, err := parseTree(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byKey := make(map[string]Unit, len(units))
+	for _, u := range units {
+		byKey[u.Key] = u
+	}
+
+	result := &ApplyResult{}
+	seen := map[string]bool{}
+	walk(root, "", func(key string, n *node) {
+		seen[key] = true
+		u, ok := byKey[key]
+		switch {
+		case !ok || u.Target == "":
+			result.Untranslated = append(result.Untranslated, key)
+		case u.Source != n.scalar.(string):
+			result.Stale = append(result.Stale, key)
+		default:
+			n.scalar = u.Target
+			result.Applied++
+		}
+	})
+	for _, u := range units {
+		if !seen[u.Key] {
+			result.Unknown = append(result.Unknown, u.Key)
+		}
+	}
+
+	out, err := root.marshal()
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding translated document: %w", err)
+	}
+	return out, result, nil
+}
+
+// walk calls fn for every translatable string node, with its stable key.
+func walk(n *node, path string, fn func(key string, n *node)) {
+	switch n.kind {
+	case '{':
+		for _, f := range n.fields {
+			if skipKey(f.key) {
+				continue
+			}
+			child := f.key
+			if path != "" {
+				child = path + "." + f.key
+			}
+			walk(f.value, child, fn)
+		}
+	case '[':
+		ids := elementIDs(n)
+		for i, item := range n.items {
+			seg := strconv.Itoa(i)
+			if ids != nil {
+				seg = ids[i]
+			}
+			walk(item, path+"["+seg+"]", fn)
+		}
+	default:
+		if s, ok := n.scalar.(string); ok && isNarrative(s) {
+			fn(path, n)
+		}
+	}
+}
+
+// elementIDs returns the "id" of every element of an array of objects, or
+// nil if any element lacks a unique id, in which case positions are used.
+func elementIDs(n *node) []string {
+	if len(n.items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(n.items))
+	seen := make(map[string]bool, len(n.items))
+	for i, item := range n.items {
+		if item.kind != '{' {
+			return nil
+		}
+		idNode := item.get("id")
+		if idNode == nil {
+			return nil
+		}
+		id, ok := idNode.scalar.(string)
+		if !ok || id == "" || seen[id] || strings.ContainsAny(id, "[]") {
+			return nil
+		}
+		seen[id] = true
+		ids[i] = id
+	}
+	return ids
+}
@@ -0,0 +1,219 @@
+package l10n
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+  "$schema": "https://example.com/prd.schema.json",
+  "metadata": {
+    "id": "PRD-001",
+    "title": "Checkout Redesign",
+    "status": "draft",
+    "createdAt": "2025-01-01T00:00:00Z",
+    "authors": [{"name": "Jane Doe", "email": "jane@example.com"}],
+    "tags": ["checkout"]
+  },
+  "executiveSummary": {
+    "problemStatement": "Checkout abandonment is \"too high\".\nWe lose 20% of carts.",
+    "expectedOutcomes": ["Fewer abandoned carts", "Faster checkout"]
+  },
+  "requirements": {
+    "functional": [
+      {"id": "FR-002", "title": "Guest checkout", "priority": "must", "estimate": 3},
+      {"id": "FR-001", "title": "Saved cards", "description": "Returning users pay in one click", "phaseId": "p1"}
+    ]
+  },
+  "roadmap": {"phases": [{"id": "p1", "name": "MVP", "goals": ["Launch guest checkout"], "startDate": "2025-02-01"}]},
+  "risks": [{"description": "PCI scope grows", "probability": "low", "notes": "P0"}]
+}`
+
+func TestExtract(t *testing.T) {
+	units, err := Extract([]byte(testDoc))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	got := map[string]string{}
+	var keys []string
+	for _, u := range units {
+		got[u.Key] = u.Source
+		keys = append(keys, u.Key)
+	}
+	want := map[string]string{
+		"metadata.title":                              "Checkout Redesign",
+		"executiveSummary.problemStatement":           "Checkout abandonment is \"too high\".\nWe lose 20% of carts.",
+		"executiveSummary.expectedOutcomes[0]":        "Fewer abandoned carts",
+		"executiveSummary.expectedOutcomes[1]":        "Faster checkout",
+		"requirements.functional[FR-002].title":       "Guest checkout",
+		"requirements.functional[FR-001].title":       "Saved cards",
+		"requirements.functional[FR-001].description": "Returning users pay in one click",
+		"roadmap.phases[p1].goals[0]":                 "Launch guest checkout",
+		"risks[0].description":                        "PCI scope grows",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() =\n%v\nwant\n%v", got, want)
+	}
+	if keys[0] != "metadata.title" || keys[len(keys)-1] != "risks[0].description" {
+		t.Errorf("units should be in document order: %v", keys)
+	}
+}
+
+func TestApply(t *testing.T) {
+	units := []Unit{
+		{Key: "metadata.title", Source: "Checkout Redesign", Target: "Refonte du paiement"},
+		{Key: "requirements.functional[FR-001].title", Source: "Saved cards", Target: "Cartes enregistrées"},
+		{Key: "requirements.functional[FR-002].title", Source: "Guest checkout (old)", Target: "Paiement invité"},
+		{Key: "roadmap.phases[p9].name", Source: "Beta", Target: "Bêta"},
+	}
+
+	out, result, err := Apply([]byte(testDoc), units)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if result.Applied != 2 {
+		t.Errorf("Applied = %d, want 2", result.Applied)
+	}
+	if !reflect.DeepEqual(result.Stale, []string{"requirements.functional[FR-002].title"}) {
+		t.Errorf("Stale = %v", result.Stale)
+	}
+	if !reflect.DeepEqual(result.Unknown, []string{"roadmap.phases[p9].name"}) {
+		t.Errorf("Unknown = %v", result.Unknown)
+	}
+	if len(result.Untranslated) != 6 {
+		t.Errorf("Untranslated = %v", result.Untranslated)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("translated document is not valid JSON: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{`"Refonte du paiement"`, `"Cartes enregistrées"`, `"Guest checkout"`, `"estimate": 3`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("translated document missing %s", want)
+		}
+	}
+	// Field order is preserved.
+	if strings.Index(s, `"$schema"`) > strings.Index(s, `"metadata"`) ||
+		strings.Index(s, `"FR-002"`) > strings.Index(s, `"FR-001"`) {
+		t.Errorf("field order changed:\n%s", s)
+	}
+}
+
+func TestXLIFFRoundTrip(t *testing.T) {
+	units := []Unit{
+		{Key: "a.title", Source: "Fish & <chips>"},
+		{Key: "b[x].description", Source: "Line one\nLine two", Target: "Ligne un\nLigne deux"},
+	}
+	var buf bytes.Buffer
+	if err := WriteXLIFF(&buf, units, FileOptions{Original: "doc.prd.json", TargetLang: "fr"}); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, want := range []string{`<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">`, `source-language="en"`, `target-language="fr"`, `<trans-unit id="a.title">`, "Fish &amp; &lt;chips&gt;"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("XLIFF missing %q:\n%s", want, s)
+		}
+	}
+
+	got, err := ReadXLIFF(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, units) {
+		t.Errorf("round trip = %+v, want %+v", got, units)
+	}
+}
+
+func TestPORoundTrip(t *testing.T) {
+	units := []Unit{
+		{Key: "a.title", Source: `Say "hi"`},
+		{Key: "a.title2", Source: `Say "hi"`, Target: `Dis "salut"`},
+		{Key: "b.body", Source: "Line one\nLine two\n", Target: "Ligne un\nLigne deux\n"},
+	}
+	var buf bytes.Buffer
+	if err := WritePO(&buf, units, FileOptions{Original: "doc.prd.json", TargetLang: "fr"}); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, want := range []string{"msgctxt \"a.title\"\nmsgid \"Say \\\"hi\\\"\"\nmsgstr \"\"\n", "msgid \"\"\n\"Line one\\n\"\n\"Line two\\n\"\n", `"Language: fr\n"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("PO missing %q:\n%s", want, s)
+		}
+	}
+
+	got, err := ReadPO(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, units) {
+		t.Errorf("round trip = %+v, want %+v", got, units)
+	}
+
+	if _, err := ReadPO(strings.NewReader("msgctxt \"k\"\nbogus\n")); err == nil {
+		t.Error("expected error for malformed PO")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]Format{"s.xliff": FormatXLIFF, "s.XLF": FormatXLIFF, "fr.po": FormatPO} {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v", path, got, err)
+		}
+	}
+	if _, err := FormatFromPath("s.json"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func TestExampleRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("../examples/agent-control-plane.prd.json")
+	if err != nil {
+		t.Skip("example not available")
+	}
+	units, err := Extract(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) < 50 {
+		t.Errorf("expected many translatable strings, got %d", len(units))
+	}
+	seen := map[string]bool{}
+	for i := range units {
+		if seen[units[i].Key] {
+			t.Errorf("duplicate key %q", units[i].Key)
+		}
+		seen[units[i].Key] = true
+		units[i].Target = "[fr] " + units[i].Source
+	}
+
+	out, result, err := Apply(data, units)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Applied != len(units) || len(result.Stale) != 0 || len(result.Untranslated) != 0 {
+		t.Errorf("Apply() result = %+v", result)
+	}
+
+	// Non-narrative fields are untouched.
+	var before, after struct {
+		Metadata struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"metadata"`
+	}
+	_ = json.Unmarshal(data, &before)
+	if err := json.Unmarshal(out, &after); err != nil {
+		t.Fatal(err)
+	}
+	if before != after {
+		t.Errorf("metadata changed: %+v -> %+v", before, after)
+	}
+}
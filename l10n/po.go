@@ -0,0 +1,112 @@
+package l10n
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WritePO writes units as a gettext PO file. Unit keys are stored in
+// msgctxt so that identical source strings remain separately translatable.
+func WritePO(w io.Writer, units []Unit, opts FileOptions) error {
+	bw := bufio.NewWriter(w)
+
+	// Header entry.
+	fmt.Fprintf(bw, "# Translations for %s\n", opts.Original)
+	bw.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	bw.WriteString("\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	fmt.Fprintf(bw, "\"X-Source-Language: %s\\n\"\n", opts.sourceLang())
+	if opts.TargetLang != "" {
+		fmt.Fprintf(bw, "\"Language: %s\\n\"\n", opts.TargetLang)
+	}
+
+	for _, u := range units {
+		bw.WriteString("\n")
+		fmt.Fprintf(bw, "msgctxt %s\n", poQuote(u.Key))
+		fmt.Fprintf(bw, "msgid %s\n", poQuote(u.Source))
+		fmt.Fprintf(bw, "msgstr %s\n", poQuote(u.Target))
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing PO: %w", err)
+	}
+	return nil
+}
+
+// ReadPO reads the entries of a gettext PO file. Entries without msgctxt,
+// including the header, are ignored since they carry no document key.
+func ReadPO(r io.Reader) ([]Unit, error) {
+	var (
+		units   []Unit
+		cur     Unit
+		hasCtxt bool
+		field   *string
+	)
+	flush := func() {
+		if hasCtxt {
+			units = append(units, cur)
+		}
+		cur, hasCtxt, field = Unit{}, false, nil
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for sc.Scan() {
+		lineNum++
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "msgctxt "):
+			flush()
+			hasCtxt = true
+			field = &cur.Key
+			line = strings.TrimPrefix(line, "msgctxt ")
+		case strings.HasPrefix(line, "msgid "):
+			if field != &cur.Key {
+				flush()
+			}
+			field = &cur.Source
+			line = strings.TrimPrefix(line, "msgid ")
+		case strings.HasPrefix(line, "msgstr "):
+			field = &cur.Target
+			line = strings.TrimPrefix(line, "msgstr ")
+		case strings.HasPrefix(line, `"`):
+			// Continuation of the previous field.
+		default:
+			return nil, fmt.Errorf("parsing PO line %d: unexpected %q", lineNum, line)
+		}
+		if field == nil {
+			return nil, fmt.Errorf("parsing PO line %d: string outside an entry", lineNum)
+		}
+		s, err := strconv.Unquote(line)
+		if err != nil {
+			return nil, fmt.Errorf("parsing PO line %d: %w", lineNum, err)
+		}
+		*field += s
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading PO: %w", err)
+	}
+	flush()
+	return units, nil
+}
+
+// poQuote quotes s as a PO string, splitting multi-line text after each
+// newline the way gettext tools do.
+func poQuote(s string) string {
+	if !strings.Contains(s, "\n") || strings.Index(s, "\n") == len(s)-1 {
+		return strconv.Quote(s)
+	}
+	var sb strings.Builder
+	sb.WriteString(`""`)
+	for _, part := range strings.SplitAfter(s, "\n") {
+		if part != "" {
+			sb.WriteString("\n" + strconv.Quote(part))
+		}
+	}
+	return sb.String()
+}
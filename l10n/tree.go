@@ -0,0 +1,141 @@
+package l10n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// node is an order-preserving JSON value, so that a translated copy of a
+// document keeps the same field order as the original.
+type node struct {
+	fields []field // object members, in source order
+	items  []*node // array elements
+	scalar any     // string, json.Number, bool, or nil
+	kind   byte    // '{', '[', or 0 for scalars
+}
+
+type field struct {
+	key   string
+	value *node
+}
+
+func parseTree(data []byte) (*node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := decodeNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("parsing JSON: unexpected data after top-level value")
+	}
+	return n, nil
+}
+
+func decodeNode(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n := &node{kind: '{'}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				value, err := decodeNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.fields = append(n.fields, field{key: key, value: value})
+			}
+			_, err := dec.Token() // '}'
+			return n, err
+		case '[':
+			n := &node{kind: '['}
+			for dec.More() {
+				item, err := decodeNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, item)
+			}
+			_, err := dec.Token() // ']'
+			return n, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %q", t)
+	default:
+		return &node{scalar: t}, nil
+	}
+}
+
+// get returns the value of an object member, or nil.
+func (n *node) get(key string) *node {
+	for _, f := range n.fields {
+		if f.key == key {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// marshal encodes the tree with two-space indentation.
+func (n *node) marshal() ([]byte, error) {
+	var compact bytes.Buffer
+	if err := n.encode(&compact); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (n *node) encode(buf *bytes.Buffer) error {
+	switch n.kind {
+	case '{':
+		buf.WriteByte('{')
+		for i, f := range n.fields {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(f.key)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := f.value.encode(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case '[':
+		buf.WriteByte('[')
+		for i, item := range n.items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := item.encode(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(n.scalar)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
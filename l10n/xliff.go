@@ -0,0 +1,101 @@
+package l10n
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// FileOptions describes the document being translated.
+type FileOptions struct {
+	// Original is the source document name recorded in the file.
+	Original string
+
+	// SourceLang and TargetLang are BCP 47 language tags (e.g., "en", "fr-CA").
+	// SourceLang defaults to "en".
+	SourceLang string
+	TargetLang string
+}
+
+func (o FileOptions) sourceLang() string {
+	if o.SourceLang == "" {
+		return "en"
+	}
+	return o.SourceLang
+}
+
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Version string      `xml:"version,attr"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string           `xml:"original,attr"`
+	SourceLanguage string           `xml:"source-language,attr"`
+	TargetLanguage string           `xml:"target-language,attr,omitempty"`
+	Datatype       string           `xml:"datatype,attr"`
+	Units          []xliffTransUnit `xml:"body>trans-unit"`
+}
+
+type xliffTransUnit struct {
+	ID     string  `xml:"id,attr"`
+	Source string  `xml:"source"`
+	Target *string `xml:"target"`
+}
+
+// WriteXLIFF writes units as an XLIFF 1.2 file. Unit keys become trans-unit
+// IDs; units with a Target include a target element.
+func WriteXLIFF(w io.Writer, units []Unit, opts FileOptions) error {
+	file := xliffFile{
+		Original:       opts.Original,
+		SourceLanguage: opts.sourceLang(),
+		TargetLanguage: opts.TargetLang,
+		Datatype:       "plaintext",
+		Units:          make([]xliffTransUnit, 0, len(units)),
+	}
+	for _, u := range units {
+		tu := xliffTransUnit{ID: u.Key, Source: u.Source}
+		if u.Target != "" {
+			target := u.Target
+			tu.Target = &target
+		}
+		file.Units = append(file.Units, tu)
+	}
+	doc := xliffDoc{Version: "1.2", Xmlns: xliffNamespace, Files: []xliffFile{file}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing XLIFF: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("writing XLIFF: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("writing XLIFF: %w", err)
+	}
+	return nil
+}
+
+// ReadXLIFF reads the trans-units of an XLIFF 1.2 file.
+func ReadXLIFF(r io.Reader) ([]Unit, error) {
+	var doc xliffDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parsing XLIFF: %w", err)
+	}
+	var units []Unit
+	for _, f := range doc.Files {
+		for _, tu := range f.Units {
+			u := Unit{Key: tu.ID, Source: tu.Source}
+			if tu.Target != nil {
+				u.Target = *tu.Target
+			}
+			units = append(units, u)
+		}
+	}
+	return units, nil
+}
@@ -0,0 +1,432 @@
+// Package lenient decodes JSON documents without failing on per-field type
+// errors.
+//
+// Hand-edited planning documents frequently contain small mistakes such as a
+// date written as "2025-03-01" instead of RFC 3339, or a story point estimate
+// quoted as a string. encoding/json rejects the whole document in those cases.
+// Unmarshal instead decodes every field it can, coerces values that have an
+// unambiguous interpretation, drops the rest, and returns a Report listing
+// each recovery by JSON Pointer so the source can be fixed.
+//
+// Syntax errors are still fatal: the input must be well-formed JSON.
+package lenient
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Action describes how a field error was recovered.
+type Action string
+
+const (
+	// ActionCoerced means the value was converted to the expected type.
+	ActionCoerced Action = "coerced"
+	// ActionDropped means the value could not be converted and was left at
+	// its zero value.
+	ActionDropped Action = "dropped"
+)
+
+// Issue is a single recovered field error.
+type Issue struct {
+	Path    string `json:"path"` // JSON Pointer (RFC 6901) to the value
+	Action  Action `json:"action"`
+	Message string `json:"message"`
+}
+
+// Report lists the recoveries made while decoding a document.
+type Report struct {
+	Issues []Issue `json:"issues,omitempty"`
+}
+
+// HasIssues reports whether any field needed recovery.
+func (r *Report) HasIssues() bool {
+	return r != nil && len(r.Issues) > 0
+}
+
+// Count returns the number of issues with the given action.
+func (r *Report) Count(action Action) int {
+	if r == nil {
+		return 0
+	}
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// String returns a human-readable summary of the report.
+func (r *Report) String() string {
+	if !r.HasIssues() {
+		return "No recovery needed"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Recovered %d field(s): %d coerced, %d dropped\n",
+		len(r.Issues), r.Count(ActionCoerced), r.Count(ActionDropped))
+	for _, issue := range r.Issues {
+		path := issue.Path
+		if path == "" {
+			path = "/"
+		}
+		fmt.Fprintf(&sb, "  %s: %s (%s)\n", path, issue.Action, issue.Message)
+	}
+	return sb.String()
+}
+
+func (r *Report) add(path string, action Action, format string, args ...any) {
+	r.Issues = append(r.Issues, Issue{Path: path, Action: action, Message: fmt.Sprintf(format, args...)})
+}
+
+// Unmarshal decodes data into v, which must be a non-nil pointer. Field-level
+// type errors are recorded in the returned Report rather than aborting the
+// decode. When data decodes cleanly with encoding/json, the result is
+// identical and the report is empty.
+func Unmarshal(data []byte, v any) (*Report, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, errors.New("lenient: Unmarshal requires a non-nil pointer")
+	}
+
+	report := &Report{}
+
+	// Fast path: most documents are valid.
+	if err := json.Unmarshal(data, v); err == nil {
+		return report, nil
+	}
+
+	var tree any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("parsing JSON: invalid character after top-level value")
+	}
+
+	// Discard anything the failed fast path may have written.
+	rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	decodeValue(tree, rv.Elem(), "", report)
+	return report, nil
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// timeLayouts are tried, in order, when a date fails RFC 3339 parsing.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"01/02/2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+func decodeValue(raw any, v reflect.Value, path string, r *Report) {
+	// Try the standard decoder on this subtree first so that everything
+	// below the first failing node keeps encoding/json semantics.
+	b, err := json.Marshal(raw)
+	if err != nil {
+		r.add(path, ActionDropped, "%v", err)
+		return
+	}
+	tmp := reflect.New(v.Type())
+	directErr := json.Unmarshal(b, tmp.Interface())
+	if directErr == nil {
+		v.Set(tmp.Elem())
+		return
+	}
+
+	t := v.Type()
+	if t == timeType {
+		decodeTime(raw, v, path, r, directErr)
+		return
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		r.add(path, ActionDropped, "%s", cleanError(directErr))
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			r.add(path, ActionDropped, "expected object, got %s", describe(raw))
+			return
+		}
+		fields := structFields(t)
+		for _, key := range sortedKeys(obj) {
+			idx, ok := fields.lookup(key)
+			if !ok {
+				continue // unknown keys are ignored, as in encoding/json
+			}
+			decodeValue(obj[key], v.FieldByIndex(idx), path+"/"+escapePointer(key), r)
+		}
+
+	case reflect.Slice:
+		arr, ok := raw.([]any)
+		if !ok {
+			r.add(path, ActionDropped, "expected array, got %s", describe(raw))
+			return
+		}
+		s := reflect.MakeSlice(t, len(arr), len(arr))
+		for i := range arr {
+			decodeValue(arr[i], s.Index(i), path+"/"+strconv.Itoa(i), r)
+		}
+		v.Set(s)
+
+	case reflect.Array:
+		arr, ok := raw.([]any)
+		if !ok {
+			r.add(path, ActionDropped, "expected array, got %s", describe(raw))
+			return
+		}
+		for i := 0; i < len(arr) && i < v.Len(); i++ {
+			decodeValue(arr[i], v.Index(i), path+"/"+strconv.Itoa(i), r)
+		}
+
+	case reflect.Map:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			r.add(path, ActionDropped, "expected object, got %s", describe(raw))
+			return
+		}
+		if t.Key().Kind() != reflect.String {
+			r.add(path, ActionDropped, "%s", cleanError(directErr))
+			return
+		}
+		m := reflect.MakeMapWithSize(t, len(obj))
+		for _, key := range sortedKeys(obj) {
+			ev := reflect.New(t.Elem()).Elem()
+			decodeValue(obj[key], ev, path+"/"+escapePointer(key), r)
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), ev)
+		}
+		v.Set(m)
+
+	case reflect.Pointer:
+		if raw == nil {
+			return
+		}
+		ev := reflect.New(t.Elem())
+		decodeValue(raw, ev.Elem(), path, r)
+		v.Set(ev)
+
+	default:
+		decodeScalar(raw, v, path, r, directErr)
+	}
+}
+
+func decodeTime(raw any, v reflect.Value, path string, r *Report, directErr error) {
+	s, ok := raw.(string)
+	if !ok {
+		r.add(path, ActionDropped, "expected date string, got %s", describe(raw))
+		return
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			v.Set(reflect.ValueOf(t))
+			r.add(path, ActionCoerced, "parsed date %q using layout %q", s, layout)
+			return
+		}
+	}
+	r.add(path, ActionDropped, "%s", cleanError(directErr))
+}
+
+// decodeScalar converts between strings, numbers, and booleans where the
+// intent is unambiguous, e.g. "5" for an int or 3 for a string.
+func decodeScalar(raw any, v reflect.Value, path string, r *Report, directErr error) {
+	switch v.Kind() {
+	case reflect.String:
+		switch x := raw.(type) {
+		case json.Number:
+			v.SetString(x.String())
+			r.add(path, ActionCoerced, "converted number %s to string", x)
+			return
+		case bool:
+			v.SetString(strconv.FormatBool(x))
+			r.add(path, ActionCoerced, "converted boolean %t to string", x)
+			return
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f, ok := numericValue(raw); ok && f == math.Trunc(f) &&
+			f >= math.MinInt64 && f < math.MaxInt64 && !v.OverflowInt(int64(f)) {
+			v.SetInt(int64(f))
+			r.add(path, ActionCoerced, "converted %s to integer %d", describe(raw), int64(f))
+			return
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f, ok := numericValue(raw); ok && f == math.Trunc(f) &&
+			f >= 0 && f < math.MaxUint64 && !v.OverflowUint(uint64(f)) {
+			v.SetUint(uint64(f))
+			r.add(path, ActionCoerced, "converted %s to integer %d", describe(raw), uint64(f))
+			return
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if f, ok := numericValue(raw); ok && !v.OverflowFloat(f) {
+			v.SetFloat(f)
+			r.add(path, ActionCoerced, "converted %s to number %g", describe(raw), f)
+			return
+		}
+
+	case reflect.Bool:
+		if s, ok := raw.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				v.SetBool(b)
+				r.add(path, ActionCoerced, "converted string %q to boolean", s)
+				return
+			}
+		}
+	}
+
+	r.add(path, ActionDropped, "%s", cleanError(directErr))
+}
+
+// numericValue returns raw as a float64 if it is a JSON number or a string
+// containing one.
+func numericValue(raw any) (float64, bool) {
+	var s string
+	switch x := raw.(type) {
+	case json.Number:
+		s = x.String()
+	case string:
+		s = strings.TrimSpace(x)
+	default:
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+func describe(raw any) string {
+	switch x := raw.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(x)
+	case json.Number:
+		return "number " + x.String()
+	case bool:
+		return "boolean " + strconv.FormatBool(x)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", raw)
+	}
+}
+
+// cleanError strips the "json: " prefix from encoding/json errors.
+func cleanError(err error) string {
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
+
+func escapePointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldSet maps JSON member names to struct field indexes.
+type fieldSet struct {
+	exact map[string][]int
+	names []string
+}
+
+// lookup finds the field for key, falling back to a case-insensitive match
+// as encoding/json does.
+func (fs *fieldSet) lookup(key string) ([]int, bool) {
+	if idx, ok := fs.exact[key]; ok {
+		return idx, true
+	}
+	for _, name := range fs.names {
+		if strings.EqualFold(name, key) {
+			return fs.exact[name], true
+		}
+	}
+	return nil, false
+}
+
+var fieldCache sync.Map // map[reflect.Type]*fieldSet
+
+func structFields(t reflect.Type) *fieldSet {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(*fieldSet)
+	}
+	fs := &fieldSet{exact: map[string][]int{}}
+	collectFields(t, nil, fs)
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+func collectFields(t reflect.Type, prefix []int, fs *fieldSet) {
+	var embedded []int
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Untagged embedded structs are promoted after direct fields so that
+		// shallower fields take precedence. Embedded pointers are skipped
+		// because FieldByIndex cannot traverse nil pointers.
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, i)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, exists := fs.exact[name]; exists {
+			continue
+		}
+		fs.exact[name] = append(append([]int(nil), prefix...), i)
+		fs.names = append(fs.names, name)
+	}
+
+	for _, i := range embedded {
+		collectFields(t.Field(i).Type, append(append([]int(nil), prefix...), i), fs)
+	}
+}
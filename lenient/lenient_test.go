@@ -0,0 +1,167 @@
+package lenient
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sampleDoc struct {
+	Title     string            `json:"title"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Points    *int              `json:"points,omitempty"`
+	Score     float64           `json:"score"`
+	Done      bool              `json:"done"`
+	Items     []sampleItem      `json:"items"`
+	Labels    map[string]string `json:"labels"`
+	Content   any               `json:"content"`
+}
+
+type sampleItem struct {
+	ID       string `json:"id"`
+	Estimate int    `json:"estimate"`
+}
+
+func TestUnmarshalValidDocument(t *testing.T) {
+	data := []byte(`{"title": "T", "createdAt": "2025-01-02T03:04:05Z", "items": [{"id": "a", "estimate": 3}]}`)
+
+	var want sampleDoc
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got sampleDoc
+	report, err := Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if report.HasIssues() {
+		t.Errorf("expected no issues, got %v", report.Issues)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshalRecoversFieldErrors(t *testing.T) {
+	data := []byte(`{
+		"title": 42,
+		"createdAt": "2025-03-01",
+		"points": "5",
+		"score": "not a number",
+		"done": "true",
+		"items": [{"id": "a", "estimate": "8"}, {"id": "b", "estimate": 2}, "bogus"],
+		"labels": {"team": "core", "size": 3},
+		"content": {"free": ["form", 1]}
+	}`)
+
+	var doc sampleDoc
+	report, err := Unmarshal(data, &doc)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if doc.Title != "42" {
+		t.Errorf("Title = %q, want %q", doc.Title, "42")
+	}
+	if !doc.CreatedAt.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt = %v", doc.CreatedAt)
+	}
+	if doc.Points == nil || *doc.Points != 5 {
+		t.Errorf("Points = %v, want 5", doc.Points)
+	}
+	if doc.Score != 0 {
+		t.Errorf("Score = %v, want 0", doc.Score)
+	}
+	if !doc.Done {
+		t.Error("Done should be coerced to true")
+	}
+	if len(doc.Items) != 3 || doc.Items[0].Estimate != 8 || doc.Items[1].ID != "b" {
+		t.Errorf("Items = %+v", doc.Items)
+	}
+	if doc.Labels["team"] != "core" || doc.Labels["size"] != "3" {
+		t.Errorf("Labels = %v", doc.Labels)
+	}
+	if doc.Content == nil {
+		t.Error("Content should be preserved")
+	}
+
+	wantIssues := map[string]Action{
+		"/title":            ActionCoerced,
+		"/createdAt":        ActionCoerced,
+		"/points":           ActionCoerced,
+		"/score":            ActionDropped,
+		"/done":             ActionCoerced,
+		"/items/0/estimate": ActionCoerced,
+		"/items/2":          ActionDropped,
+		"/labels/size":      ActionCoerced,
+	}
+	got := map[string]Action{}
+	for _, issue := range report.Issues {
+		got[issue.Path] = issue.Action
+	}
+	if !reflect.DeepEqual(got, wantIssues) {
+		t.Errorf("issues = %v, want %v", got, wantIssues)
+	}
+
+	s := report.String()
+	if !strings.Contains(s, "Recovered 8 field(s): 6 coerced, 2 dropped") {
+		t.Errorf("unexpected report summary:\n%s", s)
+	}
+}
+
+func TestUnmarshalSyntaxError(t *testing.T) {
+	var doc sampleDoc
+	for _, input := range []string{`{"title": `, `{"title": "a"} trailing`, ``} {
+		if _, err := Unmarshal([]byte(input), &doc); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestUnmarshalRequiresPointer(t *testing.T) {
+	if _, err := Unmarshal([]byte(`{}`), sampleDoc{}); err == nil {
+		t.Error("expected error for non-pointer")
+	}
+}
+
+func TestEscapePointer(t *testing.T) {
+	if got := escapePointer("a/b~c"); got != "a~1b~0c" {
+		t.Errorf("escapePointer() = %q", got)
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	f.Add([]byte(`{"title": "T", "items": [{"id": "a", "estimate": 3}]}`))
+	f.Add([]byte(`{"createdAt": "01/02/2025", "points": "1e3", "labels": {"a": 1}}`))
+	f.Add([]byte(`{"items": {"not": "array"}, "score": true}`))
+	f.Add([]byte(`{"points": 1e400, "done": "yes"}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var strict sampleDoc
+		strictErr := json.Unmarshal(data, &strict)
+
+		var doc sampleDoc
+		report, err := Unmarshal(data, &doc)
+		if strictErr == nil {
+			if err != nil {
+				t.Fatalf("strict decode succeeded but lenient failed: %v", err)
+			}
+			if report.HasIssues() {
+				t.Fatalf("strict decode succeeded but lenient reported issues: %v", report.Issues)
+			}
+			if !reflect.DeepEqual(doc, strict) {
+				t.Fatalf("lenient result differs from strict decode")
+			}
+		}
+		if err == nil {
+			// A recovered document must always re-encode.
+			if _, err := json.Marshal(doc); err != nil {
+				t.Fatalf("recovered document does not marshal: %v", err)
+			}
+		}
+	})
+}
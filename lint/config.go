@@ -0,0 +1,177 @@
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/grokify/structured-plan/ids"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// ConfigFiles are the project configuration files searched for, in order.
+var ConfigFiles = []string{".splan.yaml", ".splan.yml"}
+
+// Severity is the severity a rule reports with, or SeverityOff to disable
+// it.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+// Config is the "lint" section of the project configuration.
+type Config struct {
+	Rules Rules `json:"rules"`
+}
+
+// Rules configures each rule. Settings not given keep their defaults.
+type Rules struct {
+	IDFormat          IDFormatConfig          `json:"id-format"`
+	DescriptionLength DescriptionLengthConfig `json:"description-length"`
+	Placeholder       PlaceholderConfig       `json:"placeholder-text"`
+	DuplicateID       RuleConfig              `json:"duplicate-id"`
+	BrokenReference   ReferenceConfig         `json:"broken-reference"`
+}
+
+// RuleConfig holds the settings shared by all rules.
+type RuleConfig struct {
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// IDFormatConfig configures the id-format rule.
+type IDFormatConfig struct {
+	RuleConfig
+
+	// Patterns maps a document type, or "*" for every type, to JSONPath
+	// expressions selecting IDs and the regular expression the IDs must
+	// match. Setting the patterns of a type replaces its defaults.
+	Patterns map[string]map[string]string `json:"patterns,omitempty"`
+}
+
+// DescriptionLengthConfig configures the description-length rule.
+type DescriptionLengthConfig struct {
+	RuleConfig
+
+	// Max is the longest description allowed, in characters.
+	Max int `json:"max,omitempty"`
+
+	// Fields are the member names checked. Defaults to "description".
+	Fields []string `json:"fields,omitempty"`
+}
+
+// PlaceholderConfig configures the placeholder-text rule.
+type PlaceholderConfig struct {
+	RuleConfig
+
+	// Terms are the words not allowed in any text. They are matched as
+	// whole words, case-sensitively.
+	Terms []string `json:"terms,omitempty"`
+}
+
+// ReferenceConfig configures the broken-reference rule.
+type ReferenceConfig struct {
+	RuleConfig
+
+	// Fields maps a document type, or "*" for every type, to the member
+	// names holding IDs of other items in the same document, as a string
+	// or a list of strings. Setting the fields of a type replaces its
+	// defaults.
+	Fields map[string][]string `json:"fields,omitempty"`
+}
+
+// DefaultConfig returns the built-in rule settings.
+func DefaultConfig() Config {
+	return Config{Rules: Rules{
+		IDFormat: IDFormatConfig{
+			RuleConfig: RuleConfig{Severity: SeverityWarning},
+			Patterns: map[string]map[string]string{
+				"prd": {
+					"$.requirements.functional[*].id":    `^FR-([A-Z0-9]+-)*[0-9]+$`,
+					"$.requirements.nonFunctional[*].id": `^NFR-([A-Z0-9]+-)*[0-9]+$`,
+				},
+			},
+		},
+		DescriptionLength: DescriptionLengthConfig{
+			RuleConfig: RuleConfig{Severity: SeverityWarning},
+			Max:        1000,
+			Fields:     []string{"description"},
+		},
+		Placeholder: PlaceholderConfig{
+			RuleConfig: RuleConfig{Severity: SeverityWarning},
+			Terms:      []string{"TBD", "TODO", "FIXME", "XXX"},
+		},
+		DuplicateID: RuleConfig{Severity: SeverityError},
+		BrokenReference: ReferenceConfig{
+			RuleConfig: RuleConfig{Severity: SeverityError},
+			Fields:     referenceFields(),
+		},
+	}}
+}
+
+// referenceFields returns a copy of the reference fields the validate
+// commands check, so a configuration can replace them per type.
+func referenceFields() map[string][]string {
+	fields := make(map[string][]string, len(ids.ReferenceFields))
+	for t, f := range ids.ReferenceFields {
+		fields[t] = slices.Clone(f)
+	}
+	return fields
+}
+
+// configFile is the project configuration file.
+type configFile struct {
+	Lint *json.RawMessage `json:"lint"`
+}
+
+// LoadConfig reads the "lint" section of the YAML or JSON project
+// configuration at path over the defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading lint config: %w", err)
+	}
+	if data, err = yamlconv.ToJSON(data); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var f configFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if f.Lint != nil {
+		if err := json.Unmarshal(*f.Lint, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing %s: lint: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// FindConfig looks for a project configuration in dir and its parent
+// directories and returns its path, or "" if there is none.
+func FindConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range ConfigFiles {
+			file := filepath.Join(dir, name)
+			if _, err := os.Stat(file); err == nil {
+				return file, nil
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
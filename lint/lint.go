@@ -0,0 +1,358 @@
+// Package lint checks planning documents against style rules that go
+// beyond schema validation: ID naming conventions, description length,
+// leftover placeholder text, duplicate IDs, and references to IDs that do
+// not exist.
+//
+// Rules work on the decoded JSON of any document type, so the same
+// configuration applies to PRDs, MRDs, TRDs, OKRs, V2MOMs, and roadmaps.
+// Each rule's severity and settings come from the "lint" section of the
+// project configuration file, .splan.yaml.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/validation"
+)
+
+// Rule IDs.
+const (
+	RuleIDFormat          = "id-format"
+	RuleDescriptionLength = "description-length"
+	RulePlaceholder       = "placeholder-text"
+	RuleDuplicateID       = validation.RuleDuplicateID
+	RuleBrokenReference   = validation.RuleBrokenReference
+)
+
+func init() {
+	validation.DescribeRule(RuleIDFormat, "An ID does not follow the naming convention for its collection.")
+	validation.DescribeRule(RuleDescriptionLength, "A description is longer than the configured maximum.")
+	validation.DescribeRule(RulePlaceholder, "Text contains a placeholder such as TBD or TODO.")
+}
+
+// Rule is a lint check.
+type Rule interface {
+	// ID identifies the rule in reports and in the configuration.
+	ID() string
+
+	// Check reports the problems found in a document.
+	Check(doc *Document, report func(pointer, message string))
+}
+
+// Document is a decoded document being linted.
+type Document struct {
+	// Type is the document type, such as "prd".
+	Type string
+
+	// Root is the document decoded with query.Decode.
+	Root any
+
+	ids []definition
+}
+
+// definition is an "id" member and the identified object it is scoped to.
+type definition struct {
+	id      string
+	pointer string // The object holding the id
+	scope   string // The nearest enclosing object with an id, or ""
+}
+
+// walk calls fn for each member and element below v, depth first, in
+// document order. key is the member name, or "" for array elements.
+func walk(v any, pointer string, fn func(key string, v any, pointer string)) {
+	switch x := v.(type) {
+	case *query.Object:
+		for _, k := range x.Keys {
+			p := pointer + "/" + escapePointer(k)
+			fn(k, x.Fields[k], p)
+			walk(x.Fields[k], p, fn)
+		}
+	case []any:
+		for i, e := range x {
+			p := pointer + "/" + strconv.Itoa(i)
+			fn("", e, p)
+			walk(e, p, fn)
+		}
+	}
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// definitions returns the "id" members of the document's objects.
+func (d *Document) definitions() []definition {
+	if d.ids != nil {
+		return d.ids
+	}
+	d.ids = []definition{}
+	var visit func(v any, pointer, scope string)
+	visit = func(v any, pointer, scope string) {
+		switch x := v.(type) {
+		case *query.Object:
+			if id, ok := x.Fields["id"].(string); ok && id != "" {
+				d.ids = append(d.ids, definition{id: id, pointer: pointer, scope: scope})
+				scope = pointer
+			}
+			for _, k := range x.Keys {
+				visit(x.Fields[k], pointer+"/"+escapePointer(k), scope)
+			}
+		case []any:
+			for i, e := range x {
+				visit(e, pointer+"/"+strconv.Itoa(i), scope)
+			}
+		}
+	}
+	visit(d.Root, "", "")
+	return d.ids
+}
+
+// Linter runs a configured set of rules.
+type Linter struct {
+	rules      []Rule
+	severities map[string]validation.Severity
+}
+
+// New returns a linter for cfg. It fails if a severity is unknown or a
+// pattern or path does not compile.
+func New(cfg Config) (*Linter, error) {
+	r := cfg.Rules
+	idFormat, err := newIDFormat(r.IDFormat)
+	if err != nil {
+		return nil, err
+	}
+	placeholder, err := newPlaceholder(r.Placeholder)
+	if err != nil {
+		return nil, err
+	}
+	l := &Linter{severities: map[string]validation.Severity{}}
+	for _, c := range []struct {
+		rule     Rule
+		severity Severity
+	}{
+		{idFormat, r.IDFormat.Severity},
+		{descriptionLength(r.DescriptionLength), r.DescriptionLength.Severity},
+		{placeholder, r.Placeholder.Severity},
+		{duplicateID{}, r.DuplicateID.Severity},
+		{brokenReference(r.BrokenReference), r.BrokenReference.Severity},
+	} {
+		if err := l.Add(c.rule, c.severity); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Add adds a rule with the given severity. Rules with SeverityOff are not
+// run.
+func (l *Linter) Add(rule Rule, severity Severity) error {
+	switch severity {
+	case SeverityOff:
+		return nil
+	case SeverityError, SeverityWarning:
+	case "":
+		severity = SeverityWarning
+	default:
+		return fmt.Errorf("%s: unknown severity %q (valid: error, warning, off)", rule.ID(), severity)
+	}
+	l.rules = append(l.rules, rule)
+	l.severities[rule.ID()] = validation.Severity(severity)
+	return nil
+}
+
+// Lint checks a JSON document of the given type. Issues are returned in
+// rule order, and by position within each rule.
+func (l *Linter) Lint(docType string, data []byte) ([]validation.Issue, error) {
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{Type: docType, Root: root}
+	var issues []validation.Issue
+	for _, rule := range l.rules {
+		rule.Check(doc, func(pointer, message string) {
+			issues = append(issues, validation.Issue{
+				RuleID:   rule.ID(),
+				Severity: l.severities[rule.ID()],
+				Pointer:  pointer,
+				Message:  message,
+			})
+		})
+	}
+	return issues, nil
+}
+
+// idPattern is an ID naming convention.
+type idPattern struct {
+	docType string
+	path    *query.Path
+	re      *regexp.Regexp
+}
+
+type idFormat struct {
+	patterns []idPattern
+}
+
+func newIDFormat(cfg IDFormatConfig) (*idFormat, error) {
+	r := &idFormat{}
+	types := make([]string, 0, len(cfg.Patterns))
+	for t := range cfg.Patterns {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		paths := make([]string, 0, len(cfg.Patterns[t]))
+		for p := range cfg.Patterns[t] {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			path, err := query.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", RuleIDFormat, err)
+			}
+			re, err := regexp.Compile(cfg.Patterns[t][p])
+			if err != nil {
+				return nil, fmt.Errorf("%s: pattern for %s: %w", RuleIDFormat, p, err)
+			}
+			r.patterns = append(r.patterns, idPattern{docType: t, path: path, re: re})
+		}
+	}
+	return r, nil
+}
+
+func (r *idFormat) ID() string { return RuleIDFormat }
+
+func (r *idFormat) Check(doc *Document, report func(pointer, message string)) {
+	for _, p := range r.patterns {
+		if p.docType != "*" && p.docType != doc.Type {
+			continue
+		}
+		for _, n := range p.path.Select(doc.Root) {
+			id, ok := n.Value.(string)
+			if ok && !p.re.MatchString(id) {
+				report(n.Pointer(), fmt.Sprintf("ID %q does not match %s", id, p.re))
+			}
+		}
+	}
+}
+
+type descriptionLength DescriptionLengthConfig
+
+func (r descriptionLength) ID() string { return RuleDescriptionLength }
+
+func (r descriptionLength) Check(doc *Document, report func(pointer, message string)) {
+	if r.Max <= 0 {
+		return
+	}
+	walk(doc.Root, "", func(key string, v any, pointer string) {
+		s, ok := v.(string)
+		if !ok || key == "" || !containsFold(r.Fields, key) {
+			return
+		}
+		if n := utf8.RuneCountInString(s); n > r.Max {
+			report(pointer, fmt.Sprintf("%s is %d characters, more than %d", key, n, r.Max))
+		}
+	})
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(x string) bool { return strings.EqualFold(x, s) })
+}
+
+type placeholder struct {
+	re *regexp.Regexp
+}
+
+func newPlaceholder(cfg PlaceholderConfig) (*placeholder, error) {
+	if len(cfg.Terms) == 0 {
+		return &placeholder{}, nil
+	}
+	terms := make([]string, len(cfg.Terms))
+	for i, t := range cfg.Terms {
+		terms[i] = regexp.QuoteMeta(t)
+	}
+	re, err := regexp.Compile(`(^|[^\pL\pN_])(` + strings.Join(terms, "|") + `)($|[^\pL\pN_])`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", RulePlaceholder, err)
+	}
+	return &placeholder{re: re}, nil
+}
+
+func (r *placeholder) ID() string { return RulePlaceholder }
+
+func (r *placeholder) Check(doc *Document, report func(pointer, message string)) {
+	if r.re == nil {
+		return
+	}
+	walk(doc.Root, "", func(key string, v any, pointer string) {
+		s, ok := v.(string)
+		if !ok {
+			return
+		}
+		if m := r.re.FindStringSubmatch(s); m != nil {
+			report(pointer, fmt.Sprintf("contains placeholder text %q", m[2]))
+		}
+	})
+}
+
+// duplicateID reports IDs defined twice in the same scope. Items nested in
+// an identified item, such as the acceptance criteria of a user story, are
+// scoped to it, so "ac-1" may appear once per story.
+type duplicateID struct{}
+
+func (duplicateID) ID() string { return RuleDuplicateID }
+
+func (duplicateID) Check(doc *Document, report func(pointer, message string)) {
+	first := map[[2]string]string{}
+	for _, d := range doc.definitions() {
+		key := [2]string{d.scope, d.id}
+		if p, ok := first[key]; ok {
+			report(d.pointer+"/id", fmt.Sprintf("duplicate ID %q (first defined at %s)", d.id, p))
+			continue
+		}
+		first[key] = d.pointer
+	}
+}
+
+type brokenReference ReferenceConfig
+
+func (r brokenReference) ID() string { return RuleBrokenReference }
+
+func (r brokenReference) Check(doc *Document, report func(pointer, message string)) {
+	fields := append(slices.Clone(r.Fields["*"]), r.Fields[doc.Type]...)
+	if len(fields) == 0 {
+		return
+	}
+	defined := map[string]bool{}
+	for _, d := range doc.definitions() {
+		defined[d.id] = true
+	}
+	check := func(key, id, pointer string) {
+		if id != "" && !defined[id] {
+			report(pointer, fmt.Sprintf("%s %q does not match any ID in the document", key, id))
+		}
+	}
+	walk(doc.Root, "", func(key string, v any, pointer string) {
+		if key == "" || !containsFold(fields, key) {
+			return
+		}
+		switch x := v.(type) {
+		case string:
+			check(key, x, pointer)
+		case []any:
+			for i, e := range x {
+				if id, ok := e.(string); ok {
+					check(key, id, pointer+"/"+strconv.Itoa(i))
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/validation"
+)
+
+const testPRD = `{
+	"metadata": {"id": "PRD-1", "title": "Checkout"},
+	"personas": [{"id": "p-1", "name": "Shopper"}],
+	"userStories": [
+		{"id": "US-001", "personaId": "p-1", "acceptanceCriteria": [{"id": "ac-1"}, {"id": "ac-2"}]},
+		{"id": "US-002", "personaId": "p-9", "acceptanceCriteria": [{"id": "ac-1"}, {"id": "ac-1"}]}
+	],
+	"requirements": {
+		"functional": [
+			{"id": "FR-001", "description": "Guest checkout", "userStoryIds": ["US-001", "US-404"]},
+			{"id": "fr-2", "description": "Saved cards, details TBD."},
+			{"id": "FR-001", "description": "Split payments"}
+		],
+		"nonFunctional": [{"id": "NFR-SEC-1", "description": "TODOs are fine; TODO is not"}]
+	}
+}`
+
+func lintTest(t *testing.T, cfg Config, docType, doc string) []validation.Issue {
+	t.Helper()
+	l, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := l.Lint(docType, []byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issues
+}
+
+func summarize(issues []validation.Issue) string {
+	var lines []string
+	for _, is := range issues {
+		lines = append(lines, string(is.Severity)+" "+is.RuleID+" "+is.Pointer)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLintDefaults(t *testing.T) {
+	got := summarize(lintTest(t, DefaultConfig(), "prd", testPRD))
+	want := strings.Join([]string{
+		"warning id-format /requirements/functional/1/id",
+		"warning placeholder-text /requirements/functional/1/description",
+		"warning placeholder-text /requirements/nonFunctional/0/description",
+		"error duplicate-id /userStories/1/acceptanceCriteria/1/id",
+		"error duplicate-id /requirements/functional/2/id",
+		"error broken-reference /userStories/1/personaId",
+		"error broken-reference /requirements/functional/0/userStoryIds/1",
+	}, "\n")
+	if got != want {
+		t.Errorf("issues:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Reference fields are per document type.
+	if issues := lintTest(t, DefaultConfig(), "okr", testPRD); strings.Contains(summarize(issues), RuleBrokenReference) {
+		t.Errorf("PRD reference fields checked in an OKR:\n%s", summarize(issues))
+	}
+}
+
+func TestLintConfigured(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Rules.IDFormat.Severity = SeverityError
+	cfg.Rules.IDFormat.Patterns = map[string]map[string]string{
+		"*": {"$..acceptanceCriteria[*].id": `^AC-\d+$`},
+	}
+	cfg.Rules.DescriptionLength.Max = 14
+	cfg.Rules.Placeholder.Terms = nil
+	cfg.Rules.DuplicateID.Severity = SeverityOff
+	cfg.Rules.BrokenReference.Severity = SeverityWarning
+
+	issues := lintTest(t, cfg, "prd", testPRD)
+	got := summarize(issues)
+	for _, want := range []string{
+		"error id-format /userStories/0/acceptanceCriteria/0/id",
+		"warning description-length /requirements/functional/1/description",
+		"warning broken-reference /userStories/1/personaId",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	for _, absent := range []string{RuleDuplicateID, RulePlaceholder, "/requirements/functional/0/description"} {
+		if strings.Contains(got, absent) {
+			t.Errorf("unexpected %q in:\n%s", absent, got)
+		}
+	}
+	for _, is := range issues {
+		if is.Pointer == "/requirements/functional/1/description" && is.Message != "description is 25 characters, more than 14" {
+			t.Errorf("message = %q", is.Message)
+		}
+	}
+
+	cfg.Rules.Placeholder.Severity = "fatal"
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+	cfg = DefaultConfig()
+	cfg.Rules.IDFormat.Patterns = map[string]map[string]string{"prd": {"$.a": "("}}
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "docs", "prds")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := `
+lint:
+  rules:
+    description-length:
+      max: 200
+    duplicate-id:
+      severity: warning
+    id-format:
+      patterns:
+        prd:
+          $.requirements.functional[*].id: ^FR-[0-9]{3}$
+    broken-reference:
+      fields:
+        okr: [phaseId]
+`
+	if err := os.WriteFile(filepath.Join(dir, ".splan.yaml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := FindConfig(sub)
+	if err != nil || filepath.Base(path) != ".splan.yaml" {
+		t.Fatalf("FindConfig = %q, %v", path, err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := cfg.Rules
+	if r.DescriptionLength.Max != 200 || r.DescriptionLength.Severity != SeverityWarning {
+		t.Errorf("description-length = %+v", r.DescriptionLength)
+	}
+	if r.DuplicateID.Severity != SeverityWarning || r.BrokenReference.Severity != SeverityError {
+		t.Errorf("severities = %s, %s", r.DuplicateID.Severity, r.BrokenReference.Severity)
+	}
+	if len(r.IDFormat.Patterns["prd"]) != 1 {
+		t.Errorf("prd patterns should replace the defaults: %v", r.IDFormat.Patterns["prd"])
+	}
+	if len(r.BrokenReference.Fields["okr"]) != 1 || len(r.BrokenReference.Fields["prd"]) == 0 {
+		t.Errorf("reference fields = %v", r.BrokenReference.Fields)
+	}
+	if len(r.Placeholder.Terms) != 4 {
+		t.Errorf("placeholder terms = %v", r.Placeholder.Terms)
+	}
+
+	if path, err := FindConfig(t.TempDir()); err != nil || path != "" {
+		t.Errorf("FindConfig without a config = %q, %v", path, err)
+	}
+}
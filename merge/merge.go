@@ -0,0 +1,41 @@
+// Package merge combines multiple planning documents into one.
+//
+// Three strategies are provided:
+//
+//   - Maps merges decoded JSON objects in memory (map[string]any).
+//   - Stream merges JSON files without holding array contents in memory,
+//     spooling array elements to a temporary file as they are read.
+//   - Structs merges concrete document structs (e.g. prd.Document) using
+//     reflection, which is far more compact than generic maps.
+//
+// All strategies share the same semantics: objects are merged recursively,
+// arrays are concatenated, and any other value from a later document
+// replaces the earlier one.
+package merge
+
+// Maps recursively merges b into a and returns a. Nested objects are merged,
+// arrays are concatenated, and any other value in b replaces the value in a.
+func Maps(a, b map[string]any) map[string]any {
+	for k, v := range b {
+		if va, ok := a[k]; ok {
+			// Both have this key - check types for merging
+			switch vaTyped := va.(type) {
+			case map[string]any:
+				// Both are maps - recursively merge
+				if vMap, ok := v.(map[string]any); ok {
+					a[k] = Maps(vaTyped, vMap)
+					continue
+				}
+			case []any:
+				// Both are arrays - concatenate them
+				if vSlice, ok := v.([]any); ok {
+					a[k] = append(vaTyped, vSlice...)
+					continue
+				}
+			}
+		}
+		// Key doesn't exist in a, or types don't match - use b's value
+		a[k] = v
+	}
+	return a
+}
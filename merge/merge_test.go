@@ -0,0 +1,200 @@
+package merge
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaps(t *testing.T) {
+	a := map[string]any{
+		"title": "base",
+		"tags":  []any{"a"},
+		"meta":  map[string]any{"owner": "x", "version": "1"},
+	}
+	b := map[string]any{
+		"title": "override",
+		"tags":  []any{"b"},
+		"meta":  map[string]any{"version": "2"},
+	}
+	got := Maps(a, b)
+	want := map[string]any{
+		"title": "override",
+		"tags":  []any{"a", "b"},
+		"meta":  map[string]any{"owner": "x", "version": "2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Maps() = %v, want %v", got, want)
+	}
+}
+
+func TestStreamMatchesMaps(t *testing.T) {
+	inputs := []string{
+		`{"metadata": {"id": "PRD-1", "tags": ["x"]}, "requirements": {"functional": [{"id": "FR-1", "n": 1.50}]}, "kind": [1]}`,
+		`{"metadata": {"title": "T <&>"}, "requirements": {"functional": [{"id": "FR-2"}, {"id": "FR-3", "nested": {"b": 1, "a": [true, null]}}]}, "kind": {"replaced": true}}`,
+		`{"metadata": {"tags": ["y", "z"]}, "empty": [], "obj": {}}`,
+	}
+
+	s, err := NewStreamer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var expected map[string]any
+	for _, in := range inputs {
+		if err := s.Add(strings.NewReader(in)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(in), &m); err != nil {
+			t.Fatal(err)
+		}
+		if expected == nil {
+			expected = m
+		} else {
+			expected = Maps(expected, m)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := s.Encode(&buf); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("streamed output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("streamed merge = %v, want %v", got, expected)
+	}
+
+	// Object keys are sorted and indented like json.MarshalIndent.
+	if !strings.HasPrefix(buf.String(), "{\n  \"empty\": [],\n  \"kind\": {") {
+		t.Errorf("unexpected formatting:\n%s", buf.String())
+	}
+}
+
+func TestStreamRejectsNonObject(t *testing.T) {
+	s, err := NewStreamer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Add(strings.NewReader(`[1, 2]`)); err == nil {
+		t.Error("expected error for top-level array")
+	}
+}
+
+func TestStreamFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	if err := os.WriteFile(a, []byte(`{"items": [1, 2]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`{"items": [3]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Stream(&buf, a, b); err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	want := "{\n  \"items\": [\n    1,\n    2,\n    3\n  ]\n}"
+	if buf.String() != want {
+		t.Errorf("Stream() = %q, want %q", buf.String(), want)
+	}
+}
+
+type testDoc struct {
+	Title   string            `json:"title"`
+	Count   int               `json:"count"`
+	Updated time.Time         `json:"updated"`
+	Tags    []string          `json:"tags"`
+	Meta    *testMeta         `json:"meta"`
+	Labels  map[string]string `json:"labels"`
+	Extra   any               `json:"extra"`
+}
+
+type testMeta struct {
+	Owner   string `json:"owner"`
+	Version string `json:"version"`
+}
+
+func TestStructs(t *testing.T) {
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	dst := &testDoc{
+		Title:   "base",
+		Count:   1,
+		Updated: t1,
+		Tags:    []string{"a"},
+		Labels:  map[string]string{"team": "core"},
+		Extra:   map[string]any{"k1": "v1"},
+	}
+	src := &testDoc{
+		Updated: t2,
+		Tags:    []string{"b"},
+		Meta:    &testMeta{Owner: "alice"},
+		Labels:  map[string]string{"area": "search"},
+		Extra:   map[string]any{"k2": "v2"},
+	}
+	if err := Structs(dst, src); err != nil {
+		t.Fatalf("Structs() error = %v", err)
+	}
+
+	if dst.Title != "base" || dst.Count != 1 {
+		t.Errorf("zero values in src should not override: got %q/%d", dst.Title, dst.Count)
+	}
+	if !dst.Updated.Equal(t2) {
+		t.Errorf("Updated = %v, want %v", dst.Updated, t2)
+	}
+	if !reflect.DeepEqual(dst.Tags, []string{"a", "b"}) {
+		t.Errorf("Tags = %v", dst.Tags)
+	}
+	if dst.Meta == nil || dst.Meta.Owner != "alice" || dst.Meta == src.Meta {
+		t.Errorf("Meta should be a merged copy, got %+v", dst.Meta)
+	}
+	if len(dst.Labels) != 2 {
+		t.Errorf("Labels = %v", dst.Labels)
+	}
+	if extra, ok := dst.Extra.(map[string]any); !ok || len(extra) != 2 {
+		t.Errorf("Extra = %v", dst.Extra)
+	}
+
+	if err := Structs(dst, &testMeta{}); err == nil {
+		t.Error("expected type mismatch error")
+	}
+}
+
+func TestTypedFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	if err := os.WriteFile(a, []byte(`{"title": "A", "tags": ["x"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`{"count": 2, "tags": ["y"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := TypedFiles[testDoc](a, b)
+	if err != nil {
+		t.Fatalf("TypedFiles() error = %v", err)
+	}
+	if doc.Title != "A" || doc.Count != 2 || len(doc.Tags) != 2 {
+		t.Errorf("unexpected merge result: %+v", doc)
+	}
+
+	if _, err := TypedFiles[testDoc](filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
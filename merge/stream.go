@@ -0,0 +1,295 @@
+package merge
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Streamer merges JSON documents incrementally. Objects and scalar values are
+// kept in memory, but array elements are compacted and spooled to a temporary
+// file as they are decoded, so memory use is bounded by the document
+// structure rather than by the number of array elements. This makes it
+// suitable for aggregated datasets with hundreds of thousands of requirements
+// or stories.
+//
+// A Streamer must be closed to remove its spool file.
+type Streamer struct {
+	root  *node
+	spool *os.File
+	sw    *bufio.Writer
+	off   int64
+	buf   bytes.Buffer
+}
+
+type nodeKind int
+
+const (
+	valueNode nodeKind = iota
+	objectNode
+	arrayNode
+)
+
+// node is one value in the merged tree.
+type node struct {
+	kind   nodeKind
+	fields map[string]*node // objectNode
+	value  json.RawMessage  // valueNode
+	chunks []chunk          // arrayNode
+}
+
+// chunk is a contiguous run of newline-delimited, compacted array elements
+// in the spool file, contributed by a single input document.
+type chunk struct {
+	off   int64
+	size  int64
+	count int
+}
+
+// NewStreamer creates a Streamer backed by a temporary spool file.
+func NewStreamer() (*Streamer, error) {
+	f, err := os.CreateTemp("", "splan-merge-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("creating spool file: %w", err)
+	}
+	return &Streamer{spool: f, sw: bufio.NewWriterSize(f, 64*1024)}, nil
+}
+
+// Close removes the spool file.
+func (s *Streamer) Close() error {
+	name := s.spool.Name()
+	err := s.spool.Close()
+	if rmErr := os.Remove(name); rmErr != nil && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// Add decodes the JSON object read from r and merges it into the result.
+func (s *Streamer) Add(r io.Reader) error {
+	dec := json.NewDecoder(bufio.NewReaderSize(r, 64*1024))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return errors.New("top-level value must be a JSON object")
+	}
+	root := s.root
+	if root == nil || root.kind != objectNode {
+		root = &node{kind: objectNode, fields: map[string]*node{}}
+	}
+	if err := s.decodeObject(dec, root); err != nil {
+		return err
+	}
+	s.root = root
+	return nil
+}
+
+// AddFile opens path and merges its contents into the result.
+func (s *Streamer) AddFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.Add(f)
+}
+
+// Encode writes the merged document to w as indented JSON. Object keys are
+// sorted, matching encoding/json's output for maps.
+func (s *Streamer) Encode(w io.Writer) error {
+	if err := s.sw.Flush(); err != nil {
+		return fmt.Errorf("flushing spool file: %w", err)
+	}
+	bw := bufio.NewWriterSize(w, 64*1024)
+	if s.root == nil {
+		if _, err := bw.WriteString("null"); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+	if err := s.encodeNode(bw, s.root, ""); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// decodeObject reads object members up to and including the closing brace,
+// merging each into n.
+func (s *Streamer) decodeObject(dec *json.Decoder, n *node) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", tok)
+		}
+		child, err := s.decodeValue(dec, n.fields[key])
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		n.fields[key] = child
+	}
+	_, err := dec.Token() // closing '}'
+	return err
+}
+
+// decodeValue reads the next value and merges it into existing, returning the
+// resulting node. Mismatched kinds are replaced, as in Maps.
+func (s *Streamer) decodeValue(dec *json.Decoder, existing *node) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		raw, err := json.Marshal(tok)
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: valueNode, value: raw}, nil
+	}
+
+	switch delim {
+	case '{':
+		n := existing
+		if n == nil || n.kind != objectNode {
+			n = &node{kind: objectNode, fields: map[string]*node{}}
+		}
+		return n, s.decodeObject(dec, n)
+	case '[':
+		n := existing
+		if n == nil || n.kind != arrayNode {
+			n = &node{kind: arrayNode}
+		}
+		c := chunk{off: s.off}
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			s.buf.Reset()
+			if err := json.Compact(&s.buf, raw); err != nil {
+				return nil, err
+			}
+			s.buf.WriteByte('\n')
+			written, err := s.sw.Write(s.buf.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("writing spool file: %w", err)
+			}
+			s.off += int64(written)
+			c.count++
+		}
+		if _, err := dec.Token(); err != nil { // closing ']'
+			return nil, err
+		}
+		if c.count > 0 {
+			c.size = s.off - c.off
+			n.chunks = append(n.chunks, c)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %q", delim)
+	}
+}
+
+func (s *Streamer) encodeNode(w *bufio.Writer, n *node, indent string) error {
+	switch n.kind {
+	case objectNode:
+		if len(n.fields) == 0 {
+			_, err := w.WriteString("{}")
+			return err
+		}
+		keys := make([]string, 0, len(n.fields))
+		for k := range n.fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		inner := indent + "  "
+		w.WriteString("{\n")
+		for i, k := range keys {
+			if i > 0 {
+				w.WriteString(",\n")
+			}
+			name, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			w.WriteString(inner)
+			w.Write(name)
+			w.WriteString(": ")
+			if err := s.encodeNode(w, n.fields[k], inner); err != nil {
+				return err
+			}
+		}
+		w.WriteString("\n")
+		w.WriteString(indent)
+		_, err := w.WriteString("}")
+		return err
+
+	case arrayNode:
+		if len(n.chunks) == 0 {
+			_, err := w.WriteString("[]")
+			return err
+		}
+		inner := indent + "  "
+		w.WriteString("[\n")
+		first := true
+		for _, c := range n.chunks {
+			r := bufio.NewReader(io.NewSectionReader(s.spool, c.off, c.size))
+			for i := 0; i < c.count; i++ {
+				line, err := r.ReadBytes('\n')
+				if err != nil {
+					return fmt.Errorf("reading spool file: %w", err)
+				}
+				if !first {
+					w.WriteString(",\n")
+				}
+				first = false
+				w.WriteString(inner)
+				s.buf.Reset()
+				if err := json.Indent(&s.buf, line[:len(line)-1], inner, "  "); err != nil {
+					return err
+				}
+				w.Write(s.buf.Bytes())
+			}
+		}
+		w.WriteString("\n")
+		w.WriteString(indent)
+		_, err := w.WriteString("]")
+		return err
+
+	default:
+		_, err := w.Write(n.value)
+		return err
+	}
+}
+
+// Stream merges the JSON files at paths, in order, and writes the result to
+// w. Array elements are spooled to disk rather than held in memory.
+func Stream(w io.Writer, paths ...string) error {
+	s, err := NewStreamer()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	for _, path := range paths {
+		if err := s.AddFile(path); err != nil {
+			return fmt.Errorf("merging file %s: %w", path, err)
+		}
+	}
+	return s.Encode(w)
+}
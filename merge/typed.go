@@ -0,0 +1,149 @@
+package merge
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Structs merges src into dst, which must be non-nil pointers to the same
+// type. Struct fields are merged recursively, slices are concatenated, maps
+// are merged by key, and non-zero scalar values in src replace those in dst.
+//
+// Unlike Maps, a typed merge cannot distinguish an absent field from a zero
+// value, so a later document cannot reset a field to false, 0, or "".
+// Types that marshal themselves (such as time.Time) are treated as scalars.
+func Structs(dst, src any) error {
+	dv := reflect.ValueOf(dst)
+	sv := reflect.ValueOf(src)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return errors.New("destination must be a non-nil pointer")
+	}
+	if sv.Kind() != reflect.Pointer || sv.IsNil() {
+		return errors.New("source must be a non-nil pointer")
+	}
+	if dv.Type() != sv.Type() {
+		return fmt.Errorf("type mismatch: %s and %s", dv.Type(), sv.Type())
+	}
+	mergeValue(dv.Elem(), sv.Elem())
+	return nil
+}
+
+func mergeValue(dst, src reflect.Value) {
+	if isAtomic(dst.Type()) {
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+		return
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			if !dst.Field(i).CanSet() {
+				continue
+			}
+			mergeValue(dst.Field(i), src.Field(i))
+		}
+
+	case reflect.Slice:
+		if src.Len() > 0 {
+			dst.Set(reflect.AppendSlice(dst, src))
+		}
+
+	case reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			cur := dst.MapIndex(iter.Key())
+			if !cur.IsValid() {
+				dst.SetMapIndex(iter.Key(), iter.Value())
+				continue
+			}
+			// Map elements are not addressable, so merge into a copy.
+			merged := reflect.New(dst.Type().Elem()).Elem()
+			merged.Set(cur)
+			mergeValue(merged, iter.Value())
+			dst.SetMapIndex(iter.Key(), merged)
+		}
+
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			// Copy rather than alias so later merges don't mutate src.
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		mergeValue(dst.Elem(), src.Elem())
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		dm, dok := dst.Interface().(map[string]any)
+		sm, sok := src.Interface().(map[string]any)
+		if dok && sok {
+			dst.Set(reflect.ValueOf(Maps(dm, sm)))
+			return
+		}
+		dst.Set(src)
+
+	default:
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
+
+// isAtomic reports whether values of t should be replaced wholesale rather
+// than merged field by field.
+func isAtomic(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// TypedFiles decodes each JSON file at paths into a T and merges them, in
+// order, using Structs. Files are decoded directly from disk, so only the
+// merged struct and the document currently being read are held in memory.
+func TypedFiles[T any](paths ...string) (*T, error) {
+	var merged T
+	for _, path := range paths {
+		var doc T
+		if err := decodeFile(path, &doc); err != nil {
+			return nil, err
+		}
+		if err := Structs(&merged, &doc); err != nil {
+			return nil, fmt.Errorf("merging file %s: %w", path, err)
+		}
+	}
+	return &merged, nil
+}
+
+func decodeFile(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", path, err)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("unmarshaling json from file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package notify posts a JSON summary of a splan run to a webhook, so chat
+// tools and internal services can follow validation, scoring, and
+// generation in CI.
+//
+// The payload carries a one-line "text" field, which Slack and Microsoft
+// Teams incoming webhooks display as the message, alongside structured
+// fields for other consumers. When a secret is configured, the body is
+// signed with HMAC-SHA256 in the X-Splan-Signature header, in the form
+// "sha256=<hex>", so receivers can check where it came from.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Header names set on each delivery.
+const (
+	SignatureHeader = "X-Splan-Signature"
+	EventHeader     = "X-Splan-Event"
+)
+
+// Run statuses.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Event summarizes one splan command run.
+type Event struct {
+	// Text is a one-line summary for chat tools. Send fills it in when empty.
+	Text string `json:"text"`
+
+	// Command is the full command path, e.g. "splan requirements prd validate".
+	Command string    `json:"command"`
+	Files   []string  `json:"files,omitempty"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+	Version string    `json:"version,omitempty"`
+
+	// Errors lists validation problems.
+	Errors []string `json:"errors,omitempty"`
+
+	// Score and Passed are set by scoring commands.
+	Score  *float64 `json:"score,omitempty"`
+	Passed *bool    `json:"passed,omitempty"`
+
+	// Outputs lists the files a generate command wrote.
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// Summary returns a one-line description of e, e.g.
+// "✅ splan requirements prd validate succeeded: checkout.prd.json".
+func (e Event) Summary() string {
+	icon := "✅"
+	if e.Status != StatusSucceeded {
+		icon = "❌"
+	}
+	s := icon + " " + e.Command + " " + e.Status
+	if len(e.Files) > 0 {
+		s += ": " + strings.Join(e.Files, ", ")
+	}
+	if e.Score != nil {
+		s += fmt.Sprintf(" (score %.2f)", *e.Score)
+	}
+	if len(e.Errors) > 0 {
+		s += fmt.Sprintf(" (%d errors)", len(e.Errors))
+	} else if e.Error != "" {
+		s += " — " + e.Error
+	}
+	return s
+}
+
+// Webhook delivers events to a URL.
+type Webhook struct {
+	URL string
+
+	// Secret, when set, is the HMAC-SHA256 key used to sign each body.
+	Secret string
+
+	// Attempts is the number of tries before giving up; Backoff is the
+	// delay before the second try, doubled for each try after it. They
+	// default to 3 and one second.
+	Attempts int
+	Backoff  time.Duration
+
+	HTTPClient *http.Client
+}
+
+// NewWebhook returns a webhook for url with default retries.
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{
+		URL:        url,
+		Secret:     secret,
+		Attempts:   3,
+		Backoff:    time.Second,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Sign returns the signature header value for body: "sha256=" and the hex
+// HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts e as JSON. Network errors, 429 Too Many Requests, and 5xx
+// responses are retried with exponential backoff; other failures return
+// at once.
+func (w *Webhook) Send(ctx context.Context, e Event) error {
+	if e.Text == "" {
+		e.Text = e.Summary()
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling notification: %w", err)
+	}
+
+	attempts := max(w.Attempts, 1)
+	delay := w.Backoff
+	var lastErr error
+	for i := range attempts {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		retry, err := w.post(ctx, e, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return fmt.Errorf("posting notification to webhook: %w", lastErr)
+}
+
+// post makes one delivery and reports whether a failure may be retried.
+func (w *Webhook) post(ctx context.Context, e Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, e.Command)
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var calls int
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if sig := r.Header.Get(SignatureHeader); sig != Sign("s3cret", body) {
+			http.Error(w, "bad signature "+sig, http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, "s3cret")
+	w.Backoff = time.Millisecond
+	score := 8.5
+	err := w.Send(context.Background(), Event{
+		Command: "splan requirements prd score",
+		Files:   []string{"checkout.prd.json"},
+		Status:  StatusSucceeded,
+		Score:   &score,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want a retry after the 503", calls)
+	}
+	if want := "✅ splan requirements prd score succeeded: checkout.prd.json (score 8.50)"; got.Text != want {
+		t.Errorf("text = %q, want %q", got.Text, want)
+	}
+}
+
+func TestWebhookNoRetryOnClientError(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, "")
+	w.Backoff = time.Millisecond
+	if err := w.Send(context.Background(), Event{Command: "splan", Status: StatusFailed}); err == nil || calls != 1 {
+		t.Errorf("Send = %v after %d calls, want an error after one", err, calls)
+	}
+}
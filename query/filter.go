@@ -0,0 +1,468 @@
+package query
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// logical is a filter expression that is true or false for the current
+// node.
+type logical interface {
+	test(root, current any) bool
+}
+
+type orExpr []logical
+
+func (e orExpr) test(root, current any) bool {
+	for _, x := range e {
+		if x.test(root, current) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logical
+
+func (e andExpr) test(root, current any) bool {
+	for _, x := range e {
+		if !x.test(root, current) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct{ expr logical }
+
+func (e notExpr) test(root, current any) bool {
+	return !e.expr.test(root, current)
+}
+
+// existsExpr is true when its query selects at least one node.
+type existsExpr struct{ query *filterQuery }
+
+func (e existsExpr) test(root, current any) bool {
+	return len(e.query.nodes(root, current)) > 0
+}
+
+// funcTest is a function used as a test, such as match() or search().
+type funcTest struct{ call *funcCall }
+
+func (e funcTest) test(root, current any) bool {
+	v, ok := e.call.value(root, current)
+	b, isBool := v.(bool)
+	return ok && isBool && b
+}
+
+type compareExpr struct {
+	op          string
+	left, right comparable
+}
+
+func (e compareExpr) test(root, current any) bool {
+	l, lok := e.left.value(root, current)
+	r, rok := e.right.value(root, current)
+	switch e.op {
+	case "==":
+		return equal(l, lok, r, rok)
+	case "!=":
+		return !equal(l, lok, r, rok)
+	case "<":
+		return less(l, lok, r, rok)
+	case "<=":
+		return less(l, lok, r, rok) || equal(l, lok, r, rok)
+	case ">":
+		return less(r, rok, l, lok)
+	case ">=":
+		return less(r, rok, l, lok) || equal(l, lok, r, rok)
+	}
+	return false
+}
+
+// equal compares two values; a missing value equals only another missing
+// value.
+func equal(a any, aok bool, b any, bok bool) bool {
+	if !aok || !bok {
+		return aok == bok
+	}
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		return ok && x == y
+	case string:
+		y, ok := b.(string)
+		return ok && x == y
+	case bool:
+		y, ok := b.(bool)
+		return ok && x == y
+	case nil:
+		return b == nil
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], true, y[i], true) {
+				return false
+			}
+		}
+		return true
+	case *Object:
+		y, ok := b.(*Object)
+		if !ok || len(x.Keys) != len(y.Keys) {
+			return false
+		}
+		for k, v := range x.Fields {
+			w, found := y.Fields[k]
+			if !found || !equal(v, true, w, true) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// less orders numbers and strings; other values are not ordered.
+func less(a any, aok bool, b any, bok bool) bool {
+	if !aok || !bok {
+		return false
+	}
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		return ok && x < y
+	case string:
+		y, ok := b.(string)
+		return ok && x < y
+	}
+	return false
+}
+
+// comparable produces a single value, or no value, for the current node.
+type comparable interface {
+	value(root, current any) (any, bool)
+}
+
+type literal struct{ v any }
+
+func (l literal) value(root, current any) (any, bool) {
+	return l.v, true
+}
+
+// filterQuery is a query relative to the current node (@) or the root ($).
+type filterQuery struct {
+	relative bool
+	segments []segment
+}
+
+func (q *filterQuery) nodes(root, current any) []Node {
+	start := root
+	if q.relative {
+		start = current
+	}
+	return evalSegments(q.segments, root, []Node{{Location: "@", Value: start}})
+}
+
+// value returns the query's value if it selects exactly one node.
+func (q *filterQuery) value(root, current any) (any, bool) {
+	nodes := q.nodes(root, current)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].Value, true
+}
+
+type funcCall struct {
+	name string
+	args []any          // comparable or *filterQuery
+	re   *regexp.Regexp // match() or search() with a literal pattern
+}
+
+func (f *funcCall) value(root, current any) (any, bool) {
+	arg := func(i int) (any, bool) {
+		if c, ok := f.args[i].(comparable); ok {
+			return c.value(root, current)
+		}
+		return nil, false
+	}
+	switch f.name {
+	case "length":
+		v, ok := arg(0)
+		if !ok {
+			return nil, false
+		}
+		switch x := v.(type) {
+		case string:
+			return float64(utf8.RuneCountInString(x)), true
+		case []any:
+			return float64(len(x)), true
+		case *Object:
+			return float64(len(x.Keys)), true
+		}
+		return nil, false
+	case "count":
+		return float64(len(f.args[0].(*filterQuery).nodes(root, current))), true
+	case "value":
+		return f.args[0].(*filterQuery).value(root, current)
+	case "match", "search":
+		v, ok1 := arg(0)
+		pattern, ok2 := arg(1)
+		s, isString := v.(string)
+		expr, isPattern := pattern.(string)
+		if !ok1 || !ok2 || !isString || !isPattern {
+			return false, true
+		}
+		re := f.re
+		if re == nil {
+			var err error
+			if re, err = compilePattern(f.name, expr); err != nil {
+				return false, true
+			}
+		}
+		return re.MatchString(s), true
+	}
+	return nil, false
+}
+
+// functions lists the filter functions with their number of arguments and
+// whether they return a boolean (a test) rather than a value.
+var functions = map[string]struct {
+	args int
+	test bool
+}{
+	"length": {1, false},
+	"count":  {1, false},
+	"value":  {1, false},
+	"match":  {2, true},
+	"search": {2, true},
+}
+
+// logicalOr parses a || b || ...
+func (p *parser) logicalOr() (logical, error) {
+	var terms orExpr
+	for {
+		t, err := p.logicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+		p.skipSpace()
+		if !p.accept("||") {
+			break
+		}
+		p.skipSpace()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+// logicalAnd parses a && b && ...
+func (p *parser) logicalAnd() (logical, error) {
+	var terms andExpr
+	for {
+		t, err := p.basicExpr()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+		p.skipSpace()
+		if !p.accept("&&") {
+			break
+		}
+		p.skipSpace()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+// basicExpr parses a negation, a parenthesized expression, a comparison,
+// or a test.
+func (p *parser) basicExpr() (logical, error) {
+	p.skipSpace()
+	if p.peek("!") && !p.peek("!=") {
+		p.pos++
+		p.skipSpace()
+		e, err := p.basicExpr()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+	if p.accept("(") {
+		e, err := p.logicalOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.accept(")") {
+			return nil, p.errorf("expected )")
+		}
+		return e, nil
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.accept(op) {
+			continue
+		}
+		p.skipSpace()
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		l, lok := left.(comparable)
+		r, rok := right.(comparable)
+		if !lok || !rok {
+			return nil, p.errorf("%s needs values on both sides", op)
+		}
+		return compareExpr{op: op, left: l, right: r}, nil
+	}
+
+	switch x := left.(type) {
+	case *filterQuery:
+		return existsExpr{x}, nil
+	case *funcCall:
+		if functions[x.name].test {
+			return funcTest{x}, nil
+		}
+		return nil, p.errorf("%s() must be compared with a value", x.name)
+	}
+	return nil, p.errorf("expected a comparison or a test")
+}
+
+// operand parses a literal, a query, or a function call.
+func (p *parser) operand() (any, error) {
+	switch {
+	case p.peek("@") || p.peek("$"):
+		relative := p.src[p.pos] == '@'
+		p.pos++
+		segments, err := p.segments()
+		if err != nil {
+			return nil, err
+		}
+		return &filterQuery{relative: relative, segments: segments}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return literal{s}, nil
+	case p.accept("true"):
+		return literal{true}, nil
+	case p.accept("false"):
+		return literal{false}, nil
+	case p.accept("null"):
+		return literal{nil}, nil
+	}
+
+	if n, ok := p.number(); ok {
+		return literal{n}, nil
+	}
+
+	start := p.pos
+	name := p.memberName()
+	if name != "" && p.accept("(") {
+		return p.functionCall(name)
+	}
+	p.pos = start
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	return nil, p.errorf("unexpected %q", firstToken(p.src[p.pos:]))
+}
+
+func (p *parser) functionCall(name string) (*funcCall, error) {
+	def, ok := functions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %s()", name)
+	}
+	call := &funcCall{name: name}
+	for {
+		p.skipSpace()
+		if p.accept(")") {
+			break
+		}
+		if len(call.args) > 0 && !p.accept(",") {
+			return nil, p.errorf("expected , or ) in %s()", name)
+		}
+		p.skipSpace()
+		arg, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+	if len(call.args) != def.args {
+		return nil, p.errorf("%s() takes %d argument(s)", name, def.args)
+	}
+	if name == "count" || name == "value" {
+		if _, ok := call.args[0].(*filterQuery); !ok {
+			return nil, p.errorf("%s() takes a query", name)
+		}
+	}
+	if l, ok := call.args[len(call.args)-1].(literal); ok && def.test {
+		pattern, _ := l.v.(string)
+		re, err := compilePattern(name, pattern)
+		if err != nil {
+			return nil, p.errorf("invalid pattern in %s(): %v", name, err)
+		}
+		call.re = re
+	}
+	return call, nil
+}
+
+// compilePattern compiles the regular expression of match(), which must
+// match the whole string, or search(), which may match part of it.
+func compilePattern(function, pattern string) (*regexp.Regexp, error) {
+	if function == "match" {
+		pattern = "^(?:" + pattern + ")$"
+	}
+	return regexp.Compile(pattern)
+}
+
+func (p *parser) number() (float64, bool) {
+	start := p.pos
+	p.accept("-")
+	digits := func() {
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+	}
+	digits()
+	if p.accept(".") {
+		digits()
+	}
+	if p.accept("e") || p.accept("E") {
+		if !p.accept("+") {
+			p.accept("-")
+		}
+		digits()
+	}
+	n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil || math.IsInf(n, 0) {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+func firstToken(s string) string {
+	if i := strings.IndexAny(s, " )]"); i > 0 {
+		return s[:i]
+	}
+	return s
+}
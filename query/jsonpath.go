@@ -0,0 +1,509 @@
+// Package query selects values from planning documents with JSONPath
+// expressions (RFC 9535), so that requirements, key results, or risks can
+// be extracted from any document without knowing its Go types.
+//
+// Supported syntax:
+//
+//	$                      the document root
+//	.name  ['name']        an object member
+//	[0]  [-1]              an array element, counting from the end if negative
+//	[1:3]  [::2]           an array slice
+//	*  [*]                 every member or element
+//	..name  ..*            descendants at any depth
+//	[a,b]                  a union of selectors
+//	[?@.priority == 'must'] a filter over members or elements
+//
+// Filters compare with ==, !=, <, <=, >, and >=, combine with &&, ||, !, and
+// parentheses, and test that a query matches with a bare query such as
+// [?@.tags]. The functions length(), count(), match(), search(), and value()
+// are available. Filters written in the older [?(...)] form also work.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Node is a value selected by a path, with its normalized location in the
+// document, e.g. $['requirements']['functional'][0].
+type Node struct {
+	Location string
+	Value    any
+}
+
+// Pointer returns the node's location as a JSON pointer (RFC 6901), e.g.
+// /requirements/functional/0. The root is "".
+func (n Node) Pointer() string {
+	var sb strings.Builder
+	loc := strings.TrimPrefix(n.Location, "$")
+	for len(loc) > 1 && loc[0] == '[' {
+		sb.WriteByte('/')
+		if loc[1] != '\'' {
+			end := strings.IndexByte(loc, ']')
+			sb.WriteString(loc[1:end])
+			loc = loc[end+1:]
+			continue
+		}
+		i := 2
+		for ; i < len(loc) && loc[i] != '\''; i++ {
+			c := loc[i]
+			if c == '\\' {
+				i++
+				c = loc[i]
+			}
+			switch c {
+			case '~':
+				sb.WriteString("~0")
+			case '/':
+				sb.WriteString("~1")
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		loc = loc[i+2:]
+	}
+	return sb.String()
+}
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type selectorKind int
+
+const (
+	selectName selectorKind = iota
+	selectWildcard
+	selectIndex
+	selectSlice
+	selectFilter
+)
+
+type selector struct {
+	kind             selectorKind
+	name             string
+	index            int
+	start, end, step *int
+	filter           logical
+}
+
+// Compile parses a JSONPath expression. For convenience the leading $ may be
+// omitted: "requirements.functional" is read as "$.requirements.functional".
+func Compile(expr string) (*Path, error) {
+	src := strings.TrimSpace(expr)
+	switch {
+	case src == "":
+		return nil, fmt.Errorf("empty expression")
+	case src[0] == '.' || src[0] == '[':
+		src = "$" + src
+	case src[0] != '$':
+		src = "$." + src
+	}
+	p := &parser{src: src, pos: 1}
+	segments, err := p.segments()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q at offset %d", expr, p.src[p.pos:], p.pos)
+	}
+	return &Path{expr: expr, segments: segments}, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid.
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the expression the path was compiled from.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Select returns the nodes the path selects from a document decoded with
+// Decode, in document order.
+func (p *Path) Select(doc any) []Node {
+	return evalSegments(p.segments, doc, []Node{{Location: "$", Value: doc}})
+}
+
+// Values returns the values the path selects from a document.
+func (p *Path) Values(doc any) []any {
+	nodes := p.Select(doc)
+	values := make([]any, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.Value
+	}
+	return values
+}
+
+func evalSegments(segments []segment, root any, nodes []Node) []Node {
+	for _, seg := range segments {
+		var next []Node
+		for _, n := range nodes {
+			if seg.descendant {
+				for _, d := range descendants(n) {
+					next = append(next, seg.apply(root, d)...)
+				}
+			} else {
+				next = append(next, seg.apply(root, n)...)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants returns n and everything nested in it, depth first.
+func descendants(n Node) []Node {
+	out := []Node{n}
+	for _, c := range children(n) {
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+func children(n Node) []Node {
+	switch v := n.Value.(type) {
+	case *Object:
+		out := make([]Node, 0, len(v.Keys))
+		for _, k := range v.Keys {
+			out = append(out, Node{Location: n.Location + nameLocation(k), Value: v.Fields[k]})
+		}
+		return out
+	case []any:
+		out := make([]Node, 0, len(v))
+		for i, e := range v {
+			out = append(out, Node{Location: fmt.Sprintf("%s[%d]", n.Location, i), Value: e})
+		}
+		return out
+	}
+	return nil
+}
+
+func nameLocation(name string) string {
+	return "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "']"
+}
+
+func (seg segment) apply(root any, n Node) []Node {
+	var out []Node
+	for _, sel := range seg.selectors {
+		out = append(out, sel.apply(root, n)...)
+	}
+	return out
+}
+
+func (sel selector) apply(root any, n Node) []Node {
+	switch sel.kind {
+	case selectName:
+		if obj, ok := n.Value.(*Object); ok {
+			if v, ok := obj.Get(sel.name); ok {
+				return []Node{{Location: n.Location + nameLocation(sel.name), Value: v}}
+			}
+		}
+	case selectWildcard:
+		return children(n)
+	case selectIndex:
+		if arr, ok := n.Value.([]any); ok {
+			i := sel.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []Node{{Location: fmt.Sprintf("%s[%d]", n.Location, i), Value: arr[i]}}
+			}
+		}
+	case selectSlice:
+		if arr, ok := n.Value.([]any); ok {
+			var out []Node
+			for _, i := range sliceIndexes(len(arr), sel.start, sel.end, sel.step) {
+				out = append(out, Node{Location: fmt.Sprintf("%s[%d]", n.Location, i), Value: arr[i]})
+			}
+			return out
+		}
+	case selectFilter:
+		var out []Node
+		for _, c := range children(n) {
+			if sel.filter.test(root, c.Value) {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// sliceIndexes returns the indexes an array slice selects, following the
+// bounds rules of RFC 9535.
+func sliceIndexes(length int, start, end, step *int) []int {
+	s := 1
+	if step != nil {
+		s = *step
+	}
+	if s == 0 {
+		return nil
+	}
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + length
+		}
+		return i
+	}
+	var lower, upper int
+	if s > 0 {
+		lo, hi := 0, length
+		if start != nil {
+			lo = normalize(*start)
+		}
+		if end != nil {
+			hi = normalize(*end)
+		}
+		lower, upper = min(max(lo, 0), length), min(max(hi, 0), length)
+		var out []int
+		for i := lower; i < upper; i += s {
+			out = append(out, i)
+		}
+		return out
+	}
+	hi, lo := length-1, -length-1
+	if start != nil {
+		hi = normalize(*start)
+	}
+	if end != nil {
+		lo = normalize(*end)
+	}
+	upper, lower = min(max(hi, -1), length-1), min(max(lo, -1), length-1)
+	var out []int
+	for i := upper; i > lower; i += s {
+		out = append(out, i)
+	}
+	return out
+}
+
+// parser is a recursive descent parser for JSONPath expressions.
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *parser) accept(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at offset %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+// segments parses the segments that follow $ or @.
+func (p *parser) segments() ([]segment, error) {
+	var segments []segment
+	for {
+		start := p.pos
+		p.skipSpace()
+		switch {
+		case p.accept(".."):
+			seg, err := p.dotSegment()
+			if err != nil {
+				return nil, err
+			}
+			seg.descendant = true
+			segments = append(segments, seg)
+		case p.accept("."):
+			seg, err := p.dotSegment()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+		case p.peek("["):
+			seg, err := p.bracketSegment()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+		default:
+			p.pos = start
+			return segments, nil
+		}
+	}
+}
+
+// dotSegment parses the part after "." or "..": a name, *, or a bracket.
+func (p *parser) dotSegment() (segment, error) {
+	if p.accept("*") {
+		return segment{selectors: []selector{{kind: selectWildcard}}}, nil
+	}
+	if p.peek("[") {
+		return p.bracketSegment()
+	}
+	name := p.memberName()
+	if name == "" {
+		return segment{}, p.errorf("expected a member name")
+	}
+	return segment{selectors: []selector{{kind: selectName, name: name}}}, nil
+}
+
+func (p *parser) memberName() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) && r < utf8.RuneSelf {
+			break
+		}
+		if p.pos == start && unicode.IsDigit(r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos]
+}
+
+// bracketSegment parses [selector, selector, ...].
+func (p *parser) bracketSegment() (segment, error) {
+	p.accept("[")
+	var seg segment
+	for {
+		p.skipSpace()
+		sel, err := p.selector()
+		if err != nil {
+			return segment{}, err
+		}
+		seg.selectors = append(seg.selectors, sel)
+		p.skipSpace()
+		if p.accept("]") {
+			return seg, nil
+		}
+		if !p.accept(",") {
+			return segment{}, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *parser) selector() (selector, error) {
+	switch {
+	case p.accept("*"):
+		return selector{kind: selectWildcard}, nil
+	case p.peek("'") || p.peek(`"`):
+		name, err := p.stringLiteral()
+		if err != nil {
+			return selector{}, err
+		}
+		return selector{kind: selectName, name: name}, nil
+	case p.accept("?"):
+		p.skipSpace()
+		filter, err := p.logicalOr()
+		if err != nil {
+			return selector{}, err
+		}
+		return selector{kind: selectFilter, filter: filter}, nil
+	}
+
+	// An index or a slice
+	var bounds [3]*int
+	part := 0
+	for {
+		p.skipSpace()
+		if n, ok := p.integer(); ok {
+			bounds[part] = &n
+		}
+		p.skipSpace()
+		if part < 2 && p.accept(":") {
+			part++
+			continue
+		}
+		break
+	}
+	if part == 0 {
+		if bounds[0] == nil {
+			return selector{}, p.errorf("expected a selector")
+		}
+		return selector{kind: selectIndex, index: *bounds[0]}, nil
+	}
+	return selector{kind: selectSlice, start: bounds[0], end: bounds[1], step: bounds[2]}, nil
+}
+
+func (p *parser) integer() (int, bool) {
+	start := p.pos
+	p.accept("-")
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+func (p *parser) stringLiteral() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\\' && p.pos+1 < len(p.src):
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'u':
+				if p.pos+4 >= len(p.src) {
+					return "", p.errorf("invalid \\u escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid \\u escape")
+				}
+				sb.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				sb.WriteByte(e)
+			}
+			p.pos++
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
@@ -0,0 +1,200 @@
+package query
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats.
+const (
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+	FormatTable = "table"
+)
+
+// maxTableCell is the width table cells are truncated to.
+const maxTableCell = 60
+
+// Result is a value selected from a document, with the file it came from.
+type Result struct {
+	File  string
+	Value any
+}
+
+// WriteOptions configures how results are written.
+type WriteOptions struct {
+	// Fields are the columns of CSV and table output. A field may be a
+	// dotted path into nested objects, e.g. "owner.name". By default the
+	// columns are the members of the selected objects, in document order,
+	// or a single "value" column for other values.
+	Fields []string
+
+	// WithFile adds a "file" column to CSV and table output, for results
+	// from several documents.
+	WithFile bool
+}
+
+// Write writes results in the given format: FormatJSON writes an indented
+// array of values, FormatCSV and FormatTable one row per value.
+func Write(w io.Writer, format string, results []Result, opts WriteOptions) error {
+	switch format {
+	case FormatJSON:
+		values := make([]any, len(results))
+		for i, r := range results {
+			values[i] = r.Value
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(values)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		header, rows := tabulate(results, opts, false)
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("writing CSV header: %w", err)
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return fmt.Errorf("writing CSV rows: %w", err)
+		}
+		return nil
+	case FormatTable:
+		header, rows := tabulate(results, opts, true)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		rules := make([]string, len(header))
+		for i, h := range header {
+			rules[i] = strings.Repeat("-", len(h))
+		}
+		fmt.Fprintln(tw, strings.Join(rules, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown output format %q (valid: json, csv, table)", format)
+}
+
+// tabulate lays results out as a header and rows of cells.
+func tabulate(results []Result, opts WriteOptions, truncate bool) ([]string, [][]string) {
+	fields := opts.Fields
+	whole := false
+	if len(fields) == 0 {
+		if fields = columns(results); fields == nil {
+			fields, whole = []string{"value"}, true
+		}
+	}
+	header := slices.Clone(fields)
+	if opts.WithFile {
+		header = append([]string{"file"}, header...)
+	}
+
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		var row []string
+		if opts.WithFile {
+			row = append(row, r.File)
+		}
+		for _, f := range fields {
+			cell := ""
+			if whole {
+				cell = formatCell(r.Value)
+			} else if v, ok := field(r.Value, f); ok {
+				cell = formatCell(v)
+			}
+			if truncate {
+				cell = truncateCell(cell)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// columns returns the members of the selected objects in order of first
+// appearance, or nil if any result is not an object or none has members,
+// in which case each result is written whole in one "value" column.
+func columns(results []Result) []string {
+	var cols []string
+	seen := map[string]bool{}
+	for _, r := range results {
+		obj, ok := r.Value.(*Object)
+		if !ok {
+			return nil
+		}
+		for _, k := range obj.Keys {
+			if !seen[k] {
+				seen[k] = true
+				cols = append(cols, k)
+			}
+		}
+	}
+	return cols
+}
+
+// field returns the value at a dotted path, or the value itself for the
+// "value" column of non-object results.
+func field(v any, path string) (any, bool) {
+	obj, ok := v.(*Object)
+	if !ok {
+		return v, path == "value"
+	}
+	for _, name := range strings.Split(path, ".") {
+		if obj == nil {
+			return nil, false
+		}
+		if v, ok = obj.Get(name); !ok {
+			return nil, false
+		}
+		obj, _ = v.(*Object)
+	}
+	return v, true
+}
+
+// formatCell renders a value for a CSV or table cell. Lists of scalars are
+// joined with "; "; other nested values are written as JSON.
+func formatCell(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case []any:
+		parts := make([]string, 0, len(x))
+		for _, e := range x {
+			switch e.(type) {
+			case *Object, []any:
+				return compactJSON(x)
+			}
+			parts = append(parts, formatCell(e))
+		}
+		return strings.Join(parts, "; ")
+	}
+	return compactJSON(v)
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func truncateCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxTableCell {
+		return string(r[:maxTableCell-1]) + "…"
+	}
+	return s
+}
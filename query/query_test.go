@@ -0,0 +1,209 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"metadata": {"id": "PRD-1", "title": "Checkout", "owner": {"name": "Ana"}},
+	"requirements": {
+		"functional": [
+			{"id": "FR-001", "title": "Guest checkout", "priority": "must", "tags": ["payments", "ux"], "points": 5},
+			{"id": "FR-002", "title": "Saved cards", "priority": "should", "points": 3},
+			{"id": "FR-003", "title": "Split payments", "priority": "must", "points": 8, "acceptanceCriteria": [{"id": "ac-1"}, {"id": "ac-2"}]},
+			{"id": "FR-010", "title": "Gift cards", "priority": "could"}
+		]
+	},
+	"risks": [{"id": "R-1", "impact": "high"}],
+	"roadmap": {"phases": [{"risks": [{"id": "R-2", "impact": "low"}]}]}
+}`
+
+func decodeTest(t *testing.T) any {
+	t.Helper()
+	doc, err := Decode([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func ids(values []any) string {
+	var out []string
+	for _, v := range values {
+		switch x := v.(type) {
+		case *Object:
+			id, _ := x.Get("id")
+			out = append(out, id.(string))
+		case string:
+			out = append(out, x)
+		default:
+			b, _ := json.Marshal(x)
+			out = append(out, string(b))
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func TestSelect(t *testing.T) {
+	doc := decodeTest(t)
+	tests := []struct {
+		expr, want string
+	}{
+		{"$.metadata.id", "PRD-1"},
+		{"metadata.owner.name", "Ana"},
+		{"$['metadata']['title']", "Checkout"},
+		{"requirements.functional[0]", "FR-001"},
+		{"requirements.functional[-1]", "FR-010"},
+		{"requirements.functional[1:3]", "FR-002,FR-003"},
+		{"requirements.functional[::2]", "FR-001,FR-003"},
+		{"requirements.functional[::-1].id", "FR-010,FR-003,FR-002,FR-001"},
+		{"requirements.functional[0,2].id", "FR-001,FR-003"},
+		{"requirements.functional[*].points", "5,3,8"},
+		{"$..risks[*]", "R-1,R-2"},
+		{"$..risks[?@.impact == 'low']", "R-2"},
+		{"requirements.functional[?@.priority == 'must']", "FR-001,FR-003"},
+		{`requirements.functional[?@.priority == "must" && @.points > 5]`, "FR-003"},
+		{"requirements.functional[?@.priority == 'could' || @.points <= 3]", "FR-002,FR-010"},
+		{"requirements.functional[?!@.points]", "FR-010"},
+		{"requirements.functional[?(@.tags)]", "FR-001"},
+		{"requirements.functional[?length(@.acceptanceCriteria) == 2]", "FR-003"},
+		{"requirements.functional[?count(@.tags[*]) > 1]", "FR-001"},
+		{"requirements.functional[?match(@.id, 'FR-00[12]')]", "FR-001,FR-002"},
+		{"requirements.functional[?search(@.title, 'card')]", "FR-002,FR-010"},
+		{"requirements.functional[?@.points > $.requirements.functional[1].points]", "FR-001,FR-003"},
+		{"requirements.functional[?@.missing == @.alsoMissing]", "FR-001,FR-002,FR-003,FR-010"},
+		{"requirements.nonFunctional", ""},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := ids(p.Values(doc)); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSelectLocations(t *testing.T) {
+	nodes := MustCompile("$..risks[0].id").Select(decodeTest(t))
+	if len(nodes) != 2 {
+		t.Fatalf("got %d nodes", len(nodes))
+	}
+	if want := "$['roadmap']['phases'][0]['risks'][0]['id']"; nodes[1].Location != want {
+		t.Errorf("Location = %s, want %s", nodes[1].Location, want)
+	}
+	if want := "/roadmap/phases/0/risks/0/id"; nodes[1].Pointer() != want {
+		t.Errorf("Pointer = %s, want %s", nodes[1].Pointer(), want)
+	}
+
+	doc, err := Decode([]byte(`{"a/b": {"it's~": [true]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := MustCompile("$['a/b']['it\\'s~'][0]").Select(doc)[0].Pointer(); got != "/a~1b/it's~0/0" {
+		t.Errorf("escaped Pointer = %s", got)
+	}
+	if got := MustCompile("$").Select(doc)[0].Pointer(); got != "" {
+		t.Errorf("root Pointer = %q", got)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"$.a[",
+		"$.a[?@.b ==]",
+		"$.a[?@.b == 'x'",
+		"$.a[?'x']",
+		"$.a[?nope(@.b)]",
+		"$.a[?length(@.b)]",
+		"$.a[?match(@.b, '[')]",
+		"$.a b",
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) should fail", expr)
+		}
+	}
+}
+
+func TestDecodeKeepsKeyOrder(t *testing.T) {
+	doc, err := Decode([]byte(`{"z": 1, "a": {"y": true, "b": null}, "m": [1, "x"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"z":1,"a":{"y":true,"b":null},"m":[1,"x"]}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+	if _, err := Decode([]byte(`{} {}`)); err == nil {
+		t.Error("expected an error for trailing data")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	values := MustCompile("requirements.functional[0:2]").Values(decodeTest(t))
+	results := make([]Result, len(values))
+	for i, v := range values {
+		results[i] = Result{File: "a.prd.json", Value: v}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, results, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,title,priority,tags,points\n" +
+		"FR-001,Guest checkout,must,payments; ux,5\n" +
+		"FR-002,Saved cards,should,,3\n"
+	if buf.String() != want {
+		t.Errorf("CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, FormatTable, results, WriteOptions{Fields: []string{"id", "priority"}, WithFile: true}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[0] != "file        id      priority" || lines[2] != "a.prd.json  FR-001  must" {
+		t.Errorf("table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	scalars := []Result{{Value: "PRD-1"}, {Value: 2.5}}
+	if err := Write(&buf, FormatCSV, scalars, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "value\nPRD-1\n2.5\n"; buf.String() != want {
+		t.Errorf("scalar CSV = %q, want %q", buf.String(), want)
+	}
+
+	// Objects mixed with scalars are written whole as JSON.
+	buf.Reset()
+	mixed := []Result{{Value: "PRD-1"}, {Value: MustCompile("metadata").Values(decodeTest(t))[0]}}
+	if err := Write(&buf, FormatCSV, mixed, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "value\nPRD-1\n\"{\"\"id\"\":\"\"PRD-1\"\",\"\"title\"\":\"\"Checkout\"\",\"\"owner\"\":{\"\"name\"\":\"\"Ana\"\"}}\"\n"; buf.String() != want {
+		t.Errorf("mixed CSV = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	nested := []Result{{Value: MustCompile("metadata").Values(decodeTest(t))[0]}}
+	if err := Write(&buf, FormatCSV, nested, WriteOptions{Fields: []string{"id", "owner.name", "owner"}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,owner.name,owner\nPRD-1,Ana,\"{\"\"name\"\":\"\"Ana\"\"}\"\n"; buf.String() != want {
+		t.Errorf("nested CSV = %q, want %q", buf.String(), want)
+	}
+
+	if err := Write(&buf, "xml", results, WriteOptions{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,158 @@
+// Package builtin registers the renderers of the built-in document types
+// in render.Default when imported:
+//
+//	import _ "github.com/grokify/structured-plan/render/builtin"
+//
+// Document types are named as in file names such as checkout.prd.json:
+// prd, mrd, trd, roadmap, v2mom, and okr. Evaluation reports, as written
+// by the score commands, have the type evaluation.
+package builtin
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	okrrender "github.com/grokify/structured-plan/goals/okr/render"
+	okrmarp "github.com/grokify/structured-plan/goals/okr/render/marp"
+	v2momrender "github.com/grokify/structured-plan/goals/v2mom/render"
+	v2momhtml "github.com/grokify/structured-plan/goals/v2mom/render/html"
+	v2mommarp "github.com/grokify/structured-plan/goals/v2mom/render/marp"
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/render"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	mrdhtml "github.com/grokify/structured-plan/requirements/mrd/render/html"
+	mrdmarp "github.com/grokify/structured-plan/requirements/mrd/render/marp"
+	"github.com/grokify/structured-plan/requirements/prd"
+	prdrender "github.com/grokify/structured-plan/requirements/prd/render"
+	prdhtml "github.com/grokify/structured-plan/requirements/prd/render/html"
+	prdmarp "github.com/grokify/structured-plan/requirements/prd/render/marp"
+	"github.com/grokify/structured-plan/requirements/prd/render/terminal"
+	"github.com/grokify/structured-plan/requirements/prd/render/threatmodel"
+	"github.com/grokify/structured-plan/requirements/trd"
+	trdhtml "github.com/grokify/structured-plan/requirements/trd/render/html"
+	"github.com/grokify/structured-plan/roadmap"
+	roadmapmarp "github.com/grokify/structured-plan/roadmap/render/marp"
+)
+
+func init() {
+	if err := Register(render.Default); err != nil {
+		panic(err)
+	}
+}
+
+// Register adds the built-in renderers to reg.
+func Register(reg *render.Registry) error {
+	renderers := map[string][]render.Renderer{
+		"prd": {
+			render.For(render.FormatMarkdown, ".md", func(doc *prd.Document, opts render.Options) ([]byte, error) {
+				mo := prd.DefaultMarkdownOptions()
+				mo.LinkGlossary = opts.LinkGlossary
+				mo.IncludeRoadmapDiagrams = opts.Diagrams
+				return []byte(doc.ToMarkdown(mo)), nil
+			}),
+			render.Adapt(prdmarp.NewPRDRenderer(), prdOptions),
+			render.Adapt(prdhtml.New(), prdOptions),
+			render.Adapt(threatmodel.NewOTMRenderer(), prdOptions),
+			render.Adapt(threatmodel.NewThreatDragonRenderer(), prdOptions),
+		},
+		"mrd": {
+			render.For(render.FormatMarkdown, ".md", func(doc *mrd.Document, opts render.Options) ([]byte, error) {
+				mo := mrd.DefaultMarkdownOptions()
+				mo.LinkGlossary = opts.LinkGlossary
+				return []byte(doc.ToMarkdown(mo)), nil
+			}),
+			render.Adapt(mrdmarp.New(), common),
+			render.For(render.FormatHTML, ".html", func(doc *mrd.Document, opts render.Options) ([]byte, error) {
+				return mrdhtml.New().Render(doc, htmlOptions(opts))
+			}),
+		},
+		"trd": {
+			render.For(render.FormatMarkdown, ".md", func(doc *trd.Document, opts render.Options) ([]byte, error) {
+				mo := trd.DefaultMarkdownOptions()
+				mo.LinkGlossary = opts.LinkGlossary
+				return []byte(doc.ToMarkdown(mo)), nil
+			}),
+			render.For(render.FormatHTML, ".html", func(doc *trd.Document, opts render.Options) ([]byte, error) {
+				return trdhtml.New().Render(doc, htmlOptions(opts))
+			}),
+		},
+		"roadmap": {
+			render.For(render.FormatMarkdown, ".md", func(doc *roadmap.Document, opts render.Options) ([]byte, error) {
+				mo := roadmap.DefaultMarkdownOptions()
+				mo.IncludeDiagrams = opts.Diagrams
+				return []byte(doc.ToMarkdown(mo)), nil
+			}),
+			render.Adapt(roadmapmarp.New(), common),
+		},
+		"v2mom": {
+			render.Adapt(v2mommarp.New(), v2momOptions),
+			render.Adapt(v2momhtml.New(), v2momOptions),
+		},
+		"okr": {
+			render.Adapt(okrmarp.New(), okrOptions),
+		},
+		"evaluation": {
+			render.For(render.FormatTerminal, ".txt", func(report *evaluation.EvaluationReport, _ render.Options) ([]byte, error) {
+				var buf bytes.Buffer
+				if err := terminal.New(&buf).Render(report); err != nil {
+					return nil, err
+				}
+				return buf.Bytes(), nil
+			}),
+		},
+	}
+	for docType, rs := range renderers {
+		for _, r := range rs {
+			if err := reg.Register(docType, r); err != nil {
+				return fmt.Errorf("registering built-in renderers: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// common returns the common options, with the default theme if none is
+// set, for renderers that take them as they are.
+func common(opts render.Options) *render.Options {
+	opts.Theme = opts.ThemeName()
+	return &opts
+}
+
+// prdOptions returns the PRD render options: the defaults, with the common
+// options given.
+func prdOptions(opts render.Options) *prdrender.Options {
+	po := prdrender.DefaultOptions()
+	po.Theme = opts.ThemeName()
+	po.CustomCSS = opts.CustomCSS
+	po.KaTeXURL = opts.KaTeXURL
+	po.IncludeComments = opts.IncludeComments
+	po.LinkGlossary = opts.LinkGlossary
+	po.Sections = opts.Sections
+	return po
+}
+
+func v2momOptions(opts render.Options) *v2momrender.Options {
+	vo := v2momrender.DefaultOptions()
+	vo.Theme = opts.ThemeName()
+	vo.CustomCSS = opts.CustomCSS
+	vo.KaTeXURL = opts.KaTeXURL
+	return vo
+}
+
+func okrOptions(opts render.Options) *okrrender.Options {
+	oo := okrrender.DefaultOptions()
+	oo.Theme = opts.ThemeName()
+	oo.CustomCSS = opts.CustomCSS
+	return oo
+}
+
+func htmlOptions(opts render.Options) *htmldoc.Options {
+	return &htmldoc.Options{
+		CustomCSS:       opts.CustomCSS,
+		KaTeXURL:        opts.KaTeXURL,
+		IncludeComments: opts.IncludeComments,
+		LinkGlossary:    opts.LinkGlossary,
+	}
+}
@@ -0,0 +1,89 @@
+package builtin
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	"github.com/grokify/structured-plan/render"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+func TestRegistered(t *testing.T) {
+	want := map[string]string{
+		"prd":     "html,markdown,marp,otm,threat-dragon",
+		"mrd":     "html,markdown,marp",
+		"trd":     "html,markdown",
+		"roadmap": "markdown,marp",
+		"v2mom":   "html,marp",
+		"okr":     "marp",
+
+		"evaluation": "terminal",
+	}
+	for docType, formats := range want {
+		if got := strings.Join(render.Default.Formats(docType), ","); got != formats {
+			t.Errorf("%s formats = %s, want %s", docType, got, formats)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		docType, format string
+		doc             any
+		want            string
+	}{
+		{"prd", render.FormatMarp, &prd.Document{Metadata: prd.Metadata{Title: "Checkout"}}, "marp: true"},
+		{"prd", render.FormatMarkdown, &prd.Document{Metadata: prd.Metadata{Title: "Checkout"}}, "Checkout"},
+		{"mrd", render.FormatHTML, &mrd.Document{Metadata: mrd.Metadata{Title: "Payments Market"}}, "<html"},
+		{"roadmap", render.FormatMarp, &roadmap.Document{Metadata: roadmap.Metadata{Title: "Platform"}}, "# Platform"},
+		{"evaluation", render.FormatTerminal, &evaluation.EvaluationReport{ReviewType: "prd", Metadata: evaluation.ReportMetadata{Document: "checkout.prd.json"}}, "PRD EVALUATION"},
+	}
+	for _, tt := range tests {
+		out, err := render.Default.Render(tt.docType, tt.format, tt.doc, render.Options{Theme: render.ThemeCorporate})
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.docType, tt.format, err)
+			continue
+		}
+		if !strings.Contains(string(out), tt.want) {
+			t.Errorf("%s %s output missing %q", tt.docType, tt.format, tt.want)
+		}
+	}
+
+	data, err := os.ReadFile("../../examples/agent-platform.roadmap.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rm roadmap.Document
+	if err := json.Unmarshal(data, &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, diagrams := range []bool{false, true} {
+		out, err := render.Default.Render("roadmap", render.FormatMarkdown, &rm, render.Options{Diagrams: diagrams})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(out), "```mermaid"); got != diagrams {
+			t.Errorf("roadmap markdown with Diagrams %v has diagrams = %v", diagrams, got)
+		}
+	}
+
+	// The common options reach the options of typed renderers.
+	out, err := render.Default.Render("prd", render.FormatHTML, &prd.Document{Metadata: prd.Metadata{Title: "Checkout"}},
+		render.Options{CustomCSS: ".brand { color: teal; }"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), ".brand { color: teal; }") {
+		t.Error("prd html output missing the custom CSS")
+	}
+
+	if _, err := render.Default.Render("mrd", render.FormatMarp, &prd.Document{}, render.Options{}); err == nil {
+		t.Error("rendering a PRD as an MRD should fail")
+	}
+}
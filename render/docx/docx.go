@@ -0,0 +1,508 @@
+// Package docx renders PRD, MRD, and TRD documents as Word (.docx) files.
+//
+// The documents are laid out with the same sections as the HTML renderers:
+// each section becomes a Heading 1, subheadings become Heading 2, and tables
+// use the Table Grid style with a repeating header row. A reference .docx can
+// be supplied as a template to take its styles, theme, fonts, and page setup.
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	mrdhtml "github.com/grokify/structured-plan/requirements/mrd/render/html"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+	prdhtml "github.com/grokify/structured-plan/requirements/prd/render/html"
+	"github.com/grokify/structured-plan/requirements/trd"
+	trdhtml "github.com/grokify/structured-plan/requirements/trd/render/html"
+)
+
+//go:embed styles.xml
+var stylesXML string
+
+//go:embed numbering.xml
+var numberingXML string
+
+// Options contains DOCX rendering options.
+type Options struct {
+	// Template is the content of a reference .docx file. Its styles, theme,
+	// fonts, and page size and margins replace the built-in ones; styles the
+	// renderer needs but the template lacks are added from the built-in set.
+	// Headers, footers, and body content of the template are not used.
+	Template []byte
+}
+
+// Renderer renders requirements documents as .docx files.
+type Renderer struct{}
+
+// New creates a new DOCX renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "docx"
+}
+
+// FileExtension returns the file extension for DOCX output.
+func (r *Renderer) FileExtension() string {
+	return ".docx"
+}
+
+// RenderPRD converts a PRD to a .docx file. prdOpts selects the sections as
+// for the other PRD renderers; nil uses render.DefaultOptions.
+func (r *Renderer) RenderPRD(doc *prd.Document, prdOpts *render.Options, opts *Options) ([]byte, error) {
+	return r.RenderPage(prdhtml.New().Page(doc, prdOpts), opts)
+}
+
+// RenderMRD converts an MRD to a .docx file.
+func (r *Renderer) RenderMRD(doc *mrd.Document, opts *Options) ([]byte, error) {
+	return r.RenderPage(mrdhtml.New().Page(doc, nil), opts)
+}
+
+// RenderTRD converts a TRD to a .docx file.
+func (r *Renderer) RenderTRD(doc *trd.Document, opts *Options) ([]byte, error) {
+	return r.RenderPage(trdhtml.New().Page(doc, nil), opts)
+}
+
+// RenderPage converts a laid-out page to a .docx file.
+func (r *Renderer) RenderPage(page *htmldoc.Page, opts *Options) ([]byte, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	parts := map[string]string{
+		"word/styles.xml":    stylesXML,
+		"word/numbering.xml": numberingXML,
+	}
+	sectPr := defaultSectPr
+	if len(opts.Template) > 0 {
+		var err error
+		if sectPr, err = applyTemplate(parts, opts.Template); err != nil {
+			return nil, err
+		}
+	}
+
+	var w bodyWriter
+	w.page(page)
+
+	rels := []relationship{
+		{ID: "rIdStyles", Type: relStyles, Target: "styles.xml"},
+		{ID: "rIdNumbering", Type: relNumbering, Target: "numbering.xml"},
+	}
+	if _, ok := parts["word/theme/theme1.xml"]; ok {
+		rels = append(rels, relationship{ID: "rIdTheme", Type: relTheme, Target: "theme/theme1.xml"})
+	}
+	if _, ok := parts["word/fontTable.xml"]; ok {
+		rels = append(rels, relationship{ID: "rIdFontTable", Type: relFontTable, Target: "fontTable.xml"})
+	}
+	for i, href := range w.links {
+		rels = append(rels, relationship{ID: linkID(i), Type: relHyperlink, Target: href, External: true})
+	}
+
+	parts["_rels/.rels"] = packageRels
+	parts["docProps/core.xml"] = xmlHeader + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		"<dc:title>" + esc(page.Title) + "</dc:title><dc:subject>" + esc(page.Kind) + "</dc:subject></cp:coreProperties>"
+	parts["word/_rels/document.xml.rels"] = relationshipsXML(rels)
+	parts["word/document.xml"] = xmlHeader + `<w:document xmlns:w="` + nsW + `" xmlns:r="` + nsR + `"><w:body>` +
+		w.sb.String() + sectPr + "</w:body></w:document>"
+	parts["[Content_Types].xml"] = contentTypes(parts)
+
+	return writeZip(parts)
+}
+
+const (
+	xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	nsW       = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	nsR       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+	relStyles    = nsR + "/styles"
+	relNumbering = nsR + "/numbering"
+	relTheme     = nsR + "/theme"
+	relFontTable = nsR + "/fontTable"
+	relHyperlink = nsR + "/hyperlink"
+
+	// defaultSectPr is US Letter with one-inch margins.
+	defaultSectPr = `<w:sectPr><w:pgSz w:w="12240" w:h="15840"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr>`
+
+	// textWidth is the width in twentieths of a point between default
+	// margins, used to size table columns before Word autofits them.
+	textWidth = 9360
+)
+
+const packageRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="` + nsR + `/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`</Relationships>`
+
+// partOrder is the order of parts in the package. Word expects
+// [Content_Types].xml first.
+var partOrder = []string{
+	"[Content_Types].xml", "_rels/.rels", "docProps/core.xml", "word/document.xml", "word/_rels/document.xml.rels",
+	"word/styles.xml", "word/numbering.xml", "word/theme/theme1.xml", "word/fontTable.xml",
+}
+
+// partContentTypes are the content types of the parts not covered by the
+// rels and xml defaults.
+var partContentTypes = map[string]string{
+	"word/document.xml":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml",
+	"word/styles.xml":       "application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml",
+	"word/numbering.xml":    "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml",
+	"word/theme/theme1.xml": "application/vnd.openxmlformats-officedocument.theme+xml",
+	"word/fontTable.xml":    "application/vnd.openxmlformats-officedocument.wordprocessingml.fontTable+xml",
+	"docProps/core.xml":     "application/vnd.openxmlformats-package.core-properties+xml",
+}
+
+func contentTypes(parts map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	sb.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	sb.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	for _, name := range partOrder {
+		if _, ok := parts[name]; ok && partContentTypes[name] != "" {
+			sb.WriteString(`<Override PartName="/` + name + `" ContentType="` + partContentTypes[name] + `"/>`)
+		}
+	}
+	sb.WriteString("</Types>")
+	return sb.String()
+}
+
+type relationship struct {
+	ID       string
+	Type     string
+	Target   string
+	External bool
+}
+
+func relationshipsXML(rels []relationship) string {
+	var sb strings.Builder
+	sb.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, rel := range rels {
+		sb.WriteString(`<Relationship Id="` + rel.ID + `" Type="` + rel.Type + `" Target="` + esc(rel.Target) + `"`)
+		if rel.External {
+			sb.WriteString(` TargetMode="External"`)
+		}
+		sb.WriteString("/>")
+	}
+	sb.WriteString("</Relationships>")
+	return sb.String()
+}
+
+func linkID(i int) string {
+	return "rIdLink" + strconv.Itoa(i+1)
+}
+
+// writeZip packages parts in partOrder.
+func writeZip(parts map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range partOrder {
+		content, ok := parts[name]
+		if !ok {
+			continue
+		}
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return nil, fmt.Errorf("writing %s: %w", name, err)
+		}
+		if _, err := io.WriteString(f, content); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("writing DOCX: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	stylePattern = regexp.MustCompile(`(?s)<w:style\b[^>]*w:styleId="([^"]+)"[^>]*>.*?</w:style>`)
+	pgSzPattern  = regexp.MustCompile(`<w:pgSz\b[^>]*/>`)
+	pgMarPattern = regexp.MustCompile(`<w:pgMar\b[^>]*/>`)
+)
+
+const stylesEndMark = "</w:styles>"
+
+// applyTemplate replaces the built-in parts with those of the reference
+// .docx and returns the section properties for its page setup.
+func applyTemplate(parts map[string]string, template []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(template), int64(len(template)))
+	if err != nil {
+		return "", fmt.Errorf("reading DOCX template: %w", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/styles.xml", "word/theme/theme1.xml", "word/fontTable.xml", "word/document.xml":
+		default:
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("reading DOCX template %s: %w", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return "", fmt.Errorf("reading DOCX template %s: %w", f.Name, err)
+		}
+		files[f.Name] = string(data)
+	}
+
+	styles, ok := files["word/styles.xml"]
+	if !ok || !strings.Contains(styles, stylesEndMark) {
+		return "", fmt.Errorf("DOCX template has no word/styles.xml")
+	}
+	var missing strings.Builder
+	for _, m := range stylePattern.FindAllStringSubmatch(stylesXML, -1) {
+		if !strings.Contains(styles, `w:styleId="`+m[1]+`"`) {
+			missing.WriteString(m[0])
+		}
+	}
+	i := strings.LastIndex(styles, stylesEndMark)
+	parts["word/styles.xml"] = styles[:i] + missing.String() + styles[i:]
+	for _, name := range []string{"word/theme/theme1.xml", "word/fontTable.xml"} {
+		// Parts that reference embedded fonts or images need relationships
+		// that are not copied, so they are left out.
+		if content, ok := files[name]; ok && !strings.Contains(content, "r:id=") && !strings.Contains(content, "r:embed=") {
+			parts[name] = content
+		}
+	}
+
+	body := files["word/document.xml"]
+	pgSz, pgMar := lastMatch(pgSzPattern, body), lastMatch(pgMarPattern, body)
+	if pgSz == "" || pgMar == "" {
+		return defaultSectPr, nil
+	}
+	return "<w:sectPr>" + pgSz + pgMar + "</w:sectPr>", nil
+}
+
+func lastMatch(re *regexp.Regexp, s string) string {
+	all := re.FindAllString(s, -1)
+	if len(all) == 0 {
+		return ""
+	}
+	return all[len(all)-1]
+}
+
+// bodyWriter accumulates the WordprocessingML body of a document.
+type bodyWriter struct {
+	sb    strings.Builder
+	links []string
+}
+
+func (w *bodyWriter) page(p *htmldoc.Page) {
+	w.paragraph("Title", run{text: p.Title})
+	if p.Kind != "" {
+		w.paragraph("Subtitle", run{text: p.Kind})
+	}
+	if p.Banner != "" {
+		w.sb.WriteString(`<w:p><w:pPr><w:pBdr><w:left w:val="single" w:sz="24" w:space="4" w:color="F0B429"/></w:pBdr><w:shd w:val="clear" w:color="auto" w:fill="FFFBEA"/></w:pPr>`)
+		w.run(run{text: p.Banner, bold: true})
+		w.sb.WriteString("</w:p>")
+	}
+	for _, f := range p.Meta {
+		if strings.TrimSpace(f.Value) != "" {
+			w.paragraph("", run{text: f.Label + ": ", bold: true}, run{text: f.Value})
+		}
+	}
+	if len(p.Links) > 0 {
+		fields := make([]htmldoc.Field, len(p.Links))
+		for i, l := range p.Links {
+			fields[i] = htmldoc.Field{Label: l.Text, Value: l.URL}
+		}
+		w.linkList("External links", fields)
+	}
+	if p.Summary != "" {
+		w.paragraph("", run{text: p.Summary, italic: true})
+	}
+	for _, s := range p.Sections {
+		w.paragraph("Heading1", run{text: s.Title})
+		for _, b := range s.Blocks {
+			w.block(b)
+		}
+	}
+}
+
+func (w *bodyWriter) block(b htmldoc.Block) {
+	switch b.Kind {
+	case htmldoc.BlockHeading:
+		w.paragraph("Heading2", run{text: b.Text})
+	case htmldoc.BlockParagraph:
+		w.paragraph("", run{text: b.Text})
+	case htmldoc.BlockLabeled:
+		w.paragraph("", run{text: b.Label + ": ", bold: true}, run{text: b.Text})
+	case htmldoc.BlockList:
+		if b.Label != "" {
+			w.paragraph("", run{text: b.Label + ":", bold: true})
+		}
+		for _, it := range b.Items {
+			w.sb.WriteString(`<w:p><w:pPr><w:pStyle w:val="ListBullet"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr>`)
+			w.run(run{text: it})
+			w.sb.WriteString("</w:p>")
+		}
+	case htmldoc.BlockFields:
+		for _, f := range b.Fields {
+			w.paragraph("", run{text: f.Label + ": ", bold: true}, run{text: f.Value})
+		}
+	case htmldoc.BlockTable:
+		w.table(b.Header, b.Rows)
+	case htmldoc.BlockLink:
+		w.links = append(w.links, b.Href)
+		w.sb.WriteString(`<w:p><w:hyperlink r:id="` + linkID(len(w.links)-1) + `">`)
+		w.run(run{text: b.Text, style: "Hyperlink"})
+		w.sb.WriteString("</w:hyperlink></w:p>")
+	case htmldoc.BlockLinks:
+		w.linkList(b.Label, b.Fields)
+	case htmldoc.BlockGallery:
+		// Images are not embedded; each figure links to its image.
+		for _, f := range b.Figures {
+			runs := []run{{text: f.Title, bold: true}}
+			if f.Caption != "" {
+				runs = append(runs, run{text: ": " + f.Caption})
+			}
+			w.paragraph("", runs...)
+			if !strings.HasPrefix(f.Src, "data:") {
+				w.linkList("Image", []htmldoc.Field{{Label: f.Src, Value: f.Src}})
+			}
+			if len(f.Links) == 0 {
+				continue
+			}
+			links := slices.Clone(f.Links)
+			for i, l := range links {
+				// In-page anchors have no target in Word.
+				if strings.HasPrefix(l.Value, "#") {
+					links[i].Value = ""
+				}
+			}
+			label := f.LinksLabel
+			if label == "" {
+				label = "Links"
+			}
+			w.linkList(label, links)
+		}
+	}
+}
+
+// linkList writes a paragraph of comma-separated hyperlinks introduced by a
+// bold label. Fields without a Value are written as plain text.
+func (w *bodyWriter) linkList(label string, links []htmldoc.Field) {
+	w.sb.WriteString("<w:p>")
+	w.run(run{text: label + ": ", bold: true})
+	for i, l := range links {
+		if i > 0 {
+			w.run(run{text: ", "})
+		}
+		if l.Value == "" {
+			w.run(run{text: l.Label})
+			continue
+		}
+		w.links = append(w.links, l.Value)
+		w.sb.WriteString(`<w:hyperlink r:id="` + linkID(len(w.links)-1) + `">`)
+		w.run(run{text: l.Label, style: "Hyperlink"})
+		w.sb.WriteString("</w:hyperlink>")
+	}
+	w.sb.WriteString("</w:p>")
+}
+
+type run struct {
+	text   string
+	bold   bool
+	italic bool
+	style  string
+}
+
+func (w *bodyWriter) paragraph(style string, runs ...run) {
+	w.sb.WriteString("<w:p>")
+	if style != "" {
+		w.sb.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	for _, r := range runs {
+		w.run(r)
+	}
+	w.sb.WriteString("</w:p>")
+}
+
+// run writes text as a run, turning line breaks into <w:br/>.
+func (w *bodyWriter) run(r run) {
+	w.sb.WriteString("<w:r>")
+	if r.bold || r.italic || r.style != "" {
+		w.sb.WriteString("<w:rPr>")
+		if r.style != "" {
+			w.sb.WriteString(`<w:rStyle w:val="` + r.style + `"/>`)
+		}
+		if r.bold {
+			w.sb.WriteString("<w:b/>")
+		}
+		if r.italic {
+			w.sb.WriteString("<w:i/>")
+		}
+		w.sb.WriteString("</w:rPr>")
+	}
+	for i, line := range strings.Split(r.text, "\n") {
+		if i > 0 {
+			w.sb.WriteString("<w:br/>")
+		}
+		w.sb.WriteString(`<w:t xml:space="preserve">` + esc(line) + "</w:t>")
+	}
+	w.sb.WriteString("</w:r>")
+}
+
+// table writes a full-width table whose header row repeats on each page,
+// followed by an empty paragraph so consecutive tables stay separate.
+func (w *bodyWriter) table(header []string, rows [][]string) {
+	cols := len(header)
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	if cols == 0 {
+		return
+	}
+	w.sb.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="5000" w:type="pct"/>` +
+		`<w:tblLook w:val="04A0" w:firstRow="1" w:lastRow="0" w:firstColumn="0" w:lastColumn="0" w:noHBand="0" w:noVBand="1"/></w:tblPr><w:tblGrid>`)
+	for range cols {
+		w.sb.WriteString(`<w:gridCol w:w="` + strconv.Itoa(textWidth/cols) + `"/>`)
+	}
+	w.sb.WriteString("</w:tblGrid>")
+	if len(header) > 0 {
+		w.row(header, cols, true)
+	}
+	for _, row := range rows {
+		w.row(row, cols, false)
+	}
+	w.sb.WriteString("</w:tbl><w:p/>")
+}
+
+func (w *bodyWriter) row(cells []string, cols int, header bool) {
+	w.sb.WriteString("<w:tr>")
+	if header {
+		w.sb.WriteString("<w:trPr><w:tblHeader/></w:trPr>")
+	}
+	for i := range cols {
+		var text string
+		if i < len(cells) {
+			text = cells[i]
+		}
+		w.sb.WriteString(`<w:tc><w:tcPr><w:tcW w:w="0" w:type="auto"/></w:tcPr><w:p>`)
+		w.run(run{text: text, bold: header})
+		w.sb.WriteString("</w:p></w:tc>")
+	}
+	w.sb.WriteString("</w:tr>")
+}
+
+func esc(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
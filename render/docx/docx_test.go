@@ -0,0 +1,184 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func TestRenderMRD(t *testing.T) {
+	doc := &mrd.Document{
+		Metadata:         mrd.Metadata{ID: "MRD-1", Title: "Checkout <EU>"},
+		ExecutiveSummary: mrd.ExecutiveSummary{MarketOpportunity: "Line one\nLine two", KeyFindings: []string{"Mobile first"}},
+		Risks:            []mrd.Risk{{ID: "R-1", Description: "Fees & FX"}},
+	}
+	out, err := New().RenderMRD(doc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := readParts(t, out)
+	body := parts["word/document.xml"]
+	for _, want := range []string{
+		`<w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">Checkout &lt;EU&gt;</w:t>`,
+		`<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Risks</w:t>`,
+		`Line one</w:t><w:br/><w:t xml:space="preserve">Line two`,
+		`<w:numId w:val="1"/>`,
+		`<w:tblHeader/>`,
+		`Fees &amp; FX`,
+		`<w:pgSz w:w="12240" w:h="15840"/>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("document.xml missing %q", want)
+		}
+	}
+	if !strings.Contains(parts["[Content_Types].xml"], `PartName="/word/numbering.xml"`) {
+		t.Error("content types should list numbering.xml")
+	}
+	if _, ok := parts["word/theme/theme1.xml"]; ok {
+		t.Error("built-in output has no theme")
+	}
+}
+
+func TestRenderSixPager(t *testing.T) {
+	view := &prd.SixPagerView{
+		Title:        "Checkout",
+		PressRelease: prd.PressReleaseSection{Headline: "Introducing Checkout", Quote: prd.Quote{Speaker: "Ana", Text: "Faster"}},
+		FAQ:          prd.FAQSection{InternalFAQs: []prd.FAQ{{Question: "Why now?", Answer: "Cart abandonment"}}},
+	}
+	out, err := New().RenderSixPager(view, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := readParts(t, out)["word/document.xml"]
+	for _, want := range []string{"Press Release", "Introducing Checkout", "“Faster” — Ana", "Internal Questions", "Why now?"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("document.xml missing %q", want)
+		}
+	}
+	if strings.Contains(body, "Customer Questions") || strings.Contains(body, "Success Metrics") {
+		t.Error("empty FAQ groups and sections should be left out")
+	}
+}
+
+func TestRenderPRFAQ(t *testing.T) {
+	view := &prd.PRFAQView{
+		Title:      "Checkout",
+		LaunchDate: "June 30, 2026",
+		PressRelease: prd.PressReleaseSection{
+			Headline:      "Introducing Checkout",
+			Summary:       "Pay in one click",
+			CustomerQuote: prd.Quote{Speaker: "Store owner", Role: "interview", Text: "I lose sales at payment", Source: "EV-2"},
+		},
+		FAQ: prd.FAQSection{CustomerFAQs: []prd.FAQ{{Question: "Is my card stored?", Answer: "Only a token"}}},
+	}
+	out, err := New().RenderPRFAQ(view, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := readParts(t, out)["word/document.xml"]
+	for _, want := range []string{"June 30, 2026:", "Pay in one click", "— Store owner, interview (EV-2)", "Customer Questions", "Is my card stored?"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("document.xml missing %q", want)
+		}
+	}
+	if strings.Contains(body, "Customer Problem") {
+		t.Error("a PR/FAQ has only the press release and FAQ")
+	}
+}
+
+func TestRenderPageLinks(t *testing.T) {
+	page := htmldoc.NewPage("Technical Requirements", "API")
+	b := htmldoc.NewBuilder("refs")
+	b.Link("Spec", "https://example.com/spec?a=1&b=2")
+	page.AddSection("References", b)
+	out, err := New().RenderPage(page, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := readParts(t, out)
+	if !strings.Contains(parts["word/document.xml"], `<w:hyperlink r:id="rIdLink1">`) {
+		t.Error("document.xml missing hyperlink")
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], `Id="rIdLink1" Type="`+relHyperlink+`" Target="https://example.com/spec?a=1&amp;b=2" TargetMode="External"`) {
+		t.Errorf("rels = %s", parts["word/_rels/document.xml.rels"])
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"word/styles.xml":       `<w:styles xmlns:w="` + nsW + `"><w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:rPr><w:color w:val="C00000"/></w:rPr></w:style></w:styles>`,
+		"word/document.xml":     `<w:document xmlns:w="` + nsW + `"><w:body><w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134"/></w:sectPr></w:body></w:document>`,
+		"word/theme/theme1.xml": `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Corporate"/>`,
+	} {
+		f, _ := zw.Create(name)
+		_, _ = io.WriteString(f, content)
+	}
+	_ = zw.Close()
+
+	page := htmldoc.NewPage("Market Requirements", "Template")
+	out, err := New().RenderPage(page, &Options{Template: buf.Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := readParts(t, out)
+	styles := parts["word/styles.xml"]
+	if !strings.Contains(styles, "C00000") || strings.Count(styles, `w:styleId="Heading1"`) != 1 {
+		t.Error("template styles should be kept")
+	}
+	if !strings.Contains(styles, `w:styleId="TableGrid"`) {
+		t.Error("styles missing from the template should be added")
+	}
+	if !strings.Contains(parts["word/document.xml"], `<w:pgSz w:w="11906" w:h="16838"/>`) {
+		t.Error("page setup should come from the template")
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], "theme/theme1.xml") {
+		t.Error("template theme should be related")
+	}
+
+	if _, err := New().RenderPage(page, &Options{Template: []byte("not a zip")}); err == nil {
+		t.Error("invalid template should fail")
+	}
+}
+
+// readParts unzips a .docx and checks that every part is well-formed XML.
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zr.File[0].Name != "[Content_Types].xml" {
+		t.Errorf("first part = %s", zr.File[0].Name)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+
+		dec := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := dec.Token(); err != nil {
+				if !errors.Is(err, io.EOF) {
+					t.Errorf("%s is not well-formed: %v", f.Name, err)
+				}
+				break
+			}
+		}
+	}
+	return parts
+}
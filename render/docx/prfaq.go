@@ -0,0 +1,21 @@
+package docx
+
+import (
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// RenderPRFAQ converts a PR/FAQ view of a PRD to a .docx file, with the
+// press release and FAQ of the 6-pager.
+func (r *Renderer) RenderPRFAQ(view *prd.PRFAQView, opts *Options) ([]byte, error) {
+	page := htmldoc.NewPage("PR/FAQ", view.Title)
+	page.Meta = []htmldoc.Field{
+		{Label: "PRD", Value: view.PRDID},
+		{Label: "Version", Value: view.Version},
+		{Label: "Author", Value: view.Author},
+		{Label: "Date", Value: view.Date},
+	}
+	page.AddSection("Press Release", pressReleaseBuilder(view.PressRelease, view.LaunchDate))
+	page.AddSection("Frequently Asked Questions", faqBuilder(view.FAQ))
+	return r.RenderPage(page, opts)
+}
@@ -0,0 +1,162 @@
+package docx
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// RenderSixPager converts a 6-pager view of a PRD to a .docx file, with
+// the same sections as prd.RenderSixPagerMarkdown.
+func (r *Renderer) RenderSixPager(view *prd.SixPagerView, opts *Options) ([]byte, error) {
+	return r.RenderPage(sixPagerPage(view), opts)
+}
+
+func sixPagerPage(view *prd.SixPagerView) *htmldoc.Page {
+	page := htmldoc.NewPage("6-Pager", view.Title)
+	page.Meta = []htmldoc.Field{
+		{Label: "PRD", Value: view.PRDID},
+		{Label: "Version", Value: view.Version},
+		{Label: "Author", Value: view.Author},
+		{Label: "Date", Value: view.Date},
+	}
+
+	page.AddSection("Press Release", pressReleaseBuilder(view.PressRelease, ""))
+	page.AddSection("Frequently Asked Questions", faqBuilder(view.FAQ))
+
+	cp := view.CustomerProblem
+	b := htmldoc.NewBuilder("customer-problem")
+	b.Paragraph(cp.Statement)
+	b.Labeled("Impact", cp.Impact)
+	if len(cp.Personas) > 0 {
+		b.Heading("Who Is Affected")
+		for _, p := range cp.Personas {
+			b.List(fmt.Sprintf("%s (%s)", p.Name, p.Role), p.PainPoints)
+		}
+	}
+	if len(cp.CurrentAlternatives) > 0 {
+		b.Heading("Current Alternatives")
+		for _, alt := range cp.CurrentAlternatives {
+			b.List(alt.Name, alt.Weaknesses)
+		}
+	}
+	var evidence [][]string
+	for _, e := range cp.Evidence {
+		evidence = append(evidence, []string{e.Type, e.Strength, e.Description})
+	}
+	b.Table([]string{"Evidence", "Strength", "Finding"}, evidence)
+	page.AddSection("Customer Problem", b)
+
+	sol := view.Solution
+	b = htmldoc.NewBuilder("solution")
+	b.Paragraph(sol.Overview)
+	b.Labeled("How It Works", sol.HowItWorks)
+	var features [][]string
+	for _, f := range sol.KeyFeatures {
+		features = append(features, []string{f.Name, f.Description})
+	}
+	b.Table([]string{"Key Feature", "Description"}, features)
+	b.List("Differentiators", sol.Differentiators)
+	b.List("In Scope", sol.Scope.InScope)
+	b.List("Out of Scope", sol.Scope.OutOfScope)
+	page.AddSection("Solution", b)
+
+	sm := view.SuccessMetrics
+	b = htmldoc.NewBuilder("success-metrics")
+	var metrics [][]string
+	if sm.PrimaryMetric.Name != "" {
+		metrics = append(metrics, metricRow(sm.PrimaryMetric, "Primary"))
+	}
+	for _, m := range sm.SecondaryMetrics {
+		metrics = append(metrics, metricRow(m, "Secondary"))
+	}
+	for _, m := range sm.Guardrails {
+		metrics = append(metrics, metricRow(m, "Guardrail"))
+	}
+	b.Table([]string{"Metric", "Kind", "Baseline", "Target", "Measurement"}, metrics)
+	b.List("Business Goals", sm.BusinessGoals)
+	page.AddSection("Success Metrics", b)
+
+	tl := view.Timeline
+	b = htmldoc.NewBuilder("timeline")
+	for _, p := range tl.Phases {
+		heading := p.Name
+		if p.Status != "" {
+			heading += " [" + p.Status + "]"
+		}
+		b.Heading(heading)
+		b.Paragraph(p.Description)
+		b.List("Deliverables", p.Deliverables)
+	}
+	b.List("Dependencies", tl.Dependencies)
+	var risks [][]string
+	for _, r := range tl.Risks {
+		risks = append(risks, []string{r.Description, r.Impact, r.Mitigation})
+	}
+	b.Table([]string{"Risk", "Impact", "Mitigation"}, risks)
+	b.Labeled("Team Needs", tl.TeamNeeds)
+	page.AddSection("Timeline & Resources", b)
+
+	return page
+}
+
+// pressReleaseBuilder lays out a press release, dated launchDate if it is
+// set.
+func pressReleaseBuilder(pr prd.PressReleaseSection, launchDate string) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("press-release")
+	b.Heading(pr.Headline)
+	b.Paragraph(pr.Subheadline)
+	if launchDate != "" {
+		b.Labeled(launchDate, pr.Summary)
+	} else {
+		b.Paragraph(pr.Summary)
+	}
+	b.Labeled("The Problem", pr.ProblemSolved)
+	b.Labeled("The Solution", pr.Solution)
+	b.Paragraph(quote(pr.Quote))
+	b.Paragraph(quote(pr.CustomerQuote))
+	b.List("Key Benefits", pr.Benefits)
+	b.Paragraph(pr.CallToAction)
+	return b
+}
+
+func faqBuilder(faq prd.FAQSection) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("faq")
+	for _, group := range []struct {
+		heading string
+		faqs    []prd.FAQ
+	}{
+		{"Customer Questions", faq.CustomerFAQs},
+		{"Internal Questions", faq.InternalFAQs},
+		{"Technical Questions", faq.TechnicalFAQs},
+	} {
+		if len(group.faqs) == 0 {
+			continue
+		}
+		b.Heading(group.heading)
+		for _, faq := range group.faqs {
+			b.Labeled("Q", faq.Question)
+			b.Labeled("A", faq.Answer)
+		}
+	}
+	return b
+}
+
+func quote(q prd.Quote) string {
+	if q.Text == "" {
+		return ""
+	}
+	s := fmt.Sprintf("“%s” — %s", q.Text, q.Speaker)
+	if q.Role != "" {
+		s += ", " + q.Role
+	}
+	if q.Source != "" {
+		s += " (" + q.Source + ")"
+	}
+	return s
+}
+
+func metricRow(m prd.MetricSnapshot, kind string) []string {
+	return []string{m.Name, kind, m.Baseline, m.Target, m.Measurement}
+}
@@ -0,0 +1,308 @@
+// Package profile defines render profiles: named bundles of the generation
+// settings for one audience, such as executives or customers, so that the
+// right variant of a document is produced with --profile alone.
+//
+// A profile selects the top-level sections to render, drops the items
+// tagged above its confidentiality limit, and sets the description
+// truncation, status icon set, output format, and optional parts of the
+// Markdown, and can mask TRD secrets. The built-in profiles are exec, engineering, sales, and
+// external; a workspace manifest can redefine them or add its own.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/grokify/structured-plan/query"
+)
+
+// Output formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatDOCX     = "docx"
+)
+
+// Status icon sets.
+const (
+	IconsEmoji = "emoji"
+	IconsText  = "text"
+	IconsNone  = "none"
+)
+
+// Confidentiality levels, from least to most sensitive. Items are
+// classified by a tag of the level's name.
+const (
+	Public       = "public"
+	Internal     = "internal"
+	Confidential = "confidential"
+	Restricted   = "restricted"
+)
+
+var levels = []string{Public, Internal, Confidential, Restricted}
+
+// Profile is a named bundle of render settings. Unset fields leave the
+// command's defaults and flags in effect.
+type Profile struct {
+	Description string `json:"description,omitempty"`
+
+	// Sections are the top-level sections to render, by JSON name, such as
+	// "executiveSummary" and "roadmap". Empty means all sections.
+	// ExcludeSections are left out in either case. The metadata is always
+	// kept.
+	Sections        []string `json:"sections,omitempty"`
+	ExcludeSections []string `json:"excludeSections,omitempty"`
+
+	// MaxClassification is the most sensitive confidentiality level shown.
+	// Items tagged with a higher level are left out, and a document whose
+	// metadata is tagged with one is refused. Untagged items are shown.
+	MaxClassification string `json:"maxClassification,omitempty"`
+
+	// DescriptionMaxLen truncates descriptions in tables (0 = no limit).
+	DescriptionMaxLen *int `json:"descriptionMaxLen,omitempty"`
+
+	// Icons is the status icon set: emoji, text, or none.
+	Icons string `json:"icons,omitempty"`
+
+	// Format is the output format: markdown, html, or docx.
+	Format string `json:"format,omitempty"`
+
+	// TOC, Frontmatter, Swimlane, and Mermaid turn the table of contents,
+	// Pandoc frontmatter, roadmap swimlane table, and roadmap diagrams of
+	// the Markdown on or off.
+	TOC         *bool `json:"toc,omitempty"`
+	Frontmatter *bool `json:"frontmatter,omitempty"`
+	Swimlane    *bool `json:"swimlane,omitempty"`
+	Mermaid     *bool `json:"mermaid,omitempty"`
+
+	// Redact masks the sources and defaults of secret TRD configuration
+	// entries, as --redact does.
+	Redact bool `json:"redact,omitempty"`
+}
+
+func intPtr(n int) *int    { return &n }
+func boolPtr(b bool) *bool { return &b }
+
+// Builtin returns the built-in profiles by name.
+func Builtin() map[string]Profile {
+	return map[string]Profile{
+		"exec": {
+			Description: "Executive summary: goals, roadmap, and risks, with short descriptions",
+			Sections: []string{"executiveSummary", "objectives", "productGoals", "goals", "problem", "market",
+				"solution", "roadmap", "risks", "decisions", "openItems", "resourcing"},
+			MaxClassification: Confidential,
+			DescriptionMaxLen: intPtr(120),
+			Icons:             IconsEmoji,
+			Format:            FormatMarkdown,
+			TOC:               boolPtr(false),
+			Swimlane:          boolPtr(true),
+			Mermaid:           boolPtr(false),
+		},
+		"engineering": {
+			Description:       "Full detail for the delivery team, with roadmap diagrams",
+			ExcludeSections:   []string{"market"},
+			MaxClassification: Restricted,
+			DescriptionMaxLen: intPtr(0),
+			Icons:             IconsText,
+			Format:            FormatMarkdown,
+			TOC:               boolPtr(true),
+			Swimlane:          boolPtr(true),
+			Mermaid:           boolPtr(true),
+		},
+		"sales": {
+			Description: "Customer problem, solution, and roadmap for the field, without internal items",
+			Sections: []string{"executiveSummary", "objectives", "personas", "userStories", "problem", "market",
+				"solution", "roadmap", "outOfScope", "glossary"},
+			MaxClassification: Internal,
+			DescriptionMaxLen: intPtr(200),
+			Icons:             IconsEmoji,
+			Format:            FormatMarkdown,
+			TOC:               boolPtr(true),
+			Swimlane:          boolPtr(true),
+			Mermaid:           boolPtr(false),
+		},
+		"external": {
+			Description: "Public page for customers and partners, with only items tagged public or untagged",
+			Sections: []string{"executiveSummary", "personas", "userStories", "solution", "roadmap",
+				"outOfScope", "glossary"},
+			MaxClassification: Public,
+			DescriptionMaxLen: intPtr(200),
+			Icons:             IconsNone,
+			Format:            FormatHTML,
+			TOC:               boolPtr(true),
+			Swimlane:          boolPtr(false),
+			Mermaid:           boolPtr(false),
+			Redact:            true,
+		},
+	}
+}
+
+// Lookup returns the profile called name from configured, such as the
+// render profiles of a workspace manifest, or else from the built-in
+// profiles, and whether there is one.
+func Lookup(name string, configured map[string]Profile) (Profile, bool) {
+	if name == "" {
+		return Profile{}, false
+	}
+	if p, ok := configured[name]; ok {
+		return p, true
+	}
+	p, ok := Builtin()[name]
+	return p, ok
+}
+
+// Names returns the names of the built-in and configured profiles, sorted.
+func Names(configured map[string]Profile) []string {
+	var names []string
+	for name := range Builtin() {
+		names = append(names, name)
+	}
+	for name := range configured {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks the enumerated fields of the profile.
+func (p Profile) Validate() error {
+	if p.MaxClassification != "" && level(p.MaxClassification) < 0 {
+		return fmt.Errorf("unknown maxClassification %q (use %s)", p.MaxClassification, strings.Join(levels, ", "))
+	}
+	switch p.Icons {
+	case "", IconsEmoji, IconsText, IconsNone:
+	default:
+		return fmt.Errorf("unknown icons %q (use emoji, text, or none)", p.Icons)
+	}
+	switch p.Format {
+	case "", FormatMarkdown, FormatHTML, FormatDOCX:
+	default:
+		return fmt.Errorf("unknown format %q (use markdown, html, or docx)", p.Format)
+	}
+	if p.DescriptionMaxLen != nil && *p.DescriptionMaxLen < 0 {
+		return fmt.Errorf("descriptionMaxLen must not be negative")
+	}
+	return nil
+}
+
+// level returns the rank of a confidentiality level, or -1.
+func level(name string) int {
+	return slices.Index(levels, strings.ToLower(name))
+}
+
+// classification returns the highest confidentiality level among tags, or
+// -1 if none is a level.
+func classification(tags []string) int {
+	highest := -1
+	for _, t := range tags {
+		highest = max(highest, level(t))
+	}
+	return highest
+}
+
+// Shows reports whether the profile renders the top-level section named
+// section.
+func (p Profile) Shows(section string) bool {
+	if len(p.Sections) > 0 && !slices.Contains(p.Sections, section) {
+		return false
+	}
+	return !slices.Contains(p.ExcludeSections, section)
+}
+
+// Apply returns the JSON document data with only the profile's sections
+// and without the items classified above its confidentiality limit. It
+// returns an error if the document itself is classified above the limit.
+// If the profile selects nothing, data is returned unchanged.
+func (p Profile) Apply(data []byte) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if len(p.Sections) == 0 && len(p.ExcludeSections) == 0 && p.MaxClassification == "" {
+		return data, nil
+	}
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := root.(*query.Object)
+	if !ok {
+		return data, nil
+	}
+
+	limit := len(levels) - 1
+	if p.MaxClassification != "" {
+		limit = level(p.MaxClassification)
+	}
+	if meta, ok := doc.Fields["metadata"].(*query.Object); ok {
+		if c := classification(stringsOf(meta.Fields["tags"])); c > limit {
+			return nil, fmt.Errorf("document is classified %s, above the %s limit of the profile", levels[c], levels[limit])
+		}
+	}
+
+	keys := doc.Keys[:0]
+	for _, k := range doc.Keys {
+		if k != "metadata" {
+			if !p.Shows(k) {
+				delete(doc.Fields, k)
+				continue
+			}
+			doc.Fields[k] = prune(doc.Fields[k], limit)
+		}
+		keys = append(keys, k)
+	}
+	doc.Keys = keys
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	return out, nil
+}
+
+// prune returns v without the objects tagged with a level above limit.
+func prune(v any, limit int) any {
+	switch x := v.(type) {
+	case *query.Object:
+		keys := x.Keys[:0]
+		for _, k := range x.Keys {
+			if above(x.Fields[k], limit) {
+				delete(x.Fields, k)
+				continue
+			}
+			x.Fields[k] = prune(x.Fields[k], limit)
+			keys = append(keys, k)
+		}
+		x.Keys = keys
+		return x
+	case []any:
+		out := make([]any, 0, len(x))
+		for _, e := range x {
+			if !above(e, limit) {
+				out = append(out, prune(e, limit))
+			}
+		}
+		return out
+	}
+	return v
+}
+
+func above(v any, limit int) bool {
+	obj, ok := v.(*query.Object)
+	return ok && classification(stringsOf(obj.Fields["tags"])) > limit
+}
+
+func stringsOf(v any) []string {
+	list, _ := v.([]any)
+	out := make([]string, 0, len(list))
+	for _, e := range list {
+		if s, ok := e.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+)
+
+const doc = `{
+  "metadata": {"id": "PRD-1", "tags": ["internal"]},
+  "executiveSummary": {"problemStatement": "Slow checkout"},
+  "market": {"overview": "Growing"},
+  "userStories": [
+    {"id": "US-1", "title": "Pay", "tags": ["public"]},
+    {"id": "US-2", "title": "Refund", "tags": ["confidential", "billing"]},
+    {"id": "US-3", "title": "Browse"}
+  ]
+}`
+
+func TestApply(t *testing.T) {
+	p := Profile{Sections: []string{"executiveSummary", "userStories"}, MaxClassification: Internal}
+	out, err := p.Apply([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	for _, want := range []string{`"metadata"`, `"executiveSummary"`, `"US-1"`, `"US-3"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Apply() missing %s: %s", want, got)
+		}
+	}
+	for _, unwanted := range []string{`"market"`, `"US-2"`} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Apply() kept %s: %s", unwanted, got)
+		}
+	}
+
+	out, err = Profile{ExcludeSections: []string{"userStories"}}.Apply([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "userStories") || !strings.Contains(string(out), "market") {
+		t.Errorf("Apply() with ExcludeSections = %s", out)
+	}
+}
+
+func TestApplyRefusesClassifiedDocument(t *testing.T) {
+	_, err := Profile{MaxClassification: Public}.Apply([]byte(doc))
+	if err == nil || !strings.Contains(err.Error(), "classified internal") {
+		t.Errorf("Apply() error = %v, want document classified internal", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	configured := map[string]Profile{
+		"exec":  {Icons: IconsNone},
+		"board": {Format: FormatDOCX},
+	}
+	if p, ok := Lookup("exec", configured); !ok || p.Icons != IconsNone {
+		t.Errorf("Lookup(exec) = %+v, %v; want the configured profile", p, ok)
+	}
+	if p, ok := Lookup("external", configured); !ok || p.MaxClassification != Public || p.Format != FormatHTML || !p.Redact {
+		t.Errorf("Lookup(external) = %+v, %v; want the built-in profile", p, ok)
+	}
+	if _, ok := Lookup("beta", configured); ok {
+		t.Error("Lookup(beta) found a profile")
+	}
+	if got := strings.Join(Names(configured), ","); got != "board,engineering,exec,external,sales" {
+		t.Errorf("Names() = %s", got)
+	}
+	for name, p := range Builtin() {
+		if err := p.Validate(); err != nil {
+			t.Errorf("built-in profile %s: %v", name, err)
+		}
+	}
+	if err := (Profile{Icons: "ascii"}).Validate(); err == nil {
+		t.Error("Validate() accepted icons ascii")
+	}
+}
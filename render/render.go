@@ -0,0 +1,239 @@
+// Package render is the output framework shared by all document types. A
+// Renderer turns a document into one output format, such as Markdown, Marp
+// slides, or HTML, and a Registry makes the renderers of each document
+// type discoverable by name, so commands and tools need no per-type output
+// plumbing.
+//
+// Renderers for the built-in document types are registered in the Default
+// registry by importing package render/builtin. A new document type
+// registers its own:
+//
+//	render.Register("runbook", render.For("markdown", ".md",
+//		func(doc *runbook.Document, opts render.Options) ([]byte, error) {
+//			return []byte(doc.ToMarkdown()), nil
+//		}))
+package render
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Output formats of the built-in renderers.
+const (
+	FormatMarkdown = "markdown"
+	FormatMarp     = "marp"
+	FormatHTML     = "html"
+	FormatTerminal = "terminal"
+)
+
+// Slide themes.
+const (
+	ThemeDefault   = "default"
+	ThemeCorporate = "corporate"
+	ThemeMinimal   = "minimal"
+)
+
+// Themes returns the slide theme names.
+func Themes() []string {
+	return []string{ThemeDefault, ThemeCorporate, ThemeMinimal}
+}
+
+// ValidateTheme returns an error if name is not a slide theme. The empty
+// name selects the default theme.
+func ValidateTheme(name string) error {
+	if name == "" || slices.Contains(Themes(), name) {
+		return nil
+	}
+	return fmt.Errorf("unknown theme %q (use %s)", name, strings.Join(Themes(), ", "))
+}
+
+// Options are the rendering options common to all renderers. Renderers
+// ignore the options that do not apply to their format.
+type Options struct {
+	// Theme is the slide theme (default: "default").
+	Theme string
+
+	// Sections selects the slides or sections to include, by the names
+	// the renderer documents (empty = all).
+	Sections []string
+
+	// CustomCSS is added to the stylesheet of HTML output.
+	CustomCSS string
+
+	// KaTeXURL is the base URL of a local KaTeX dist directory for math in
+	// HTML output (default: the jsdelivr CDN).
+	KaTeXURL string
+
+	// IncludeComments shows unresolved review comments in HTML output.
+	IncludeComments bool
+
+	// LinkGlossary links the first use of each glossary term to its entry
+	// (default: true).
+	LinkGlossary *bool
+
+	// Diagrams adds Mermaid diagrams, such as a roadmap Gantt chart, to
+	// Markdown output.
+	Diagrams bool
+}
+
+// ThemeName returns the theme, or the default theme if none is set.
+func (o Options) ThemeName() string {
+	if o.Theme == "" {
+		return ThemeDefault
+	}
+	return o.Theme
+}
+
+// Renderer renders documents to one output format.
+type Renderer interface {
+	// Format returns the output format name (e.g., "marp").
+	Format() string
+	// FileExtension returns the file extension for this format (e.g., ".md").
+	FileExtension() string
+	// Render converts a document to the output format.
+	Render(doc any, opts Options) ([]byte, error)
+}
+
+// TypedRenderer renders documents of type *T to one output format, with
+// options of type *O. The renderers of each document type implement it,
+// with options of their own that repeat the fields of Options they use,
+// and For or Adapt registers them.
+type TypedRenderer[T, O any] interface {
+	// Format returns the output format name (e.g., "marp").
+	Format() string
+	// FileExtension returns the file extension for this format (e.g., ".md").
+	FileExtension() string
+	// Render converts a document to the output format.
+	Render(doc *T, opts *O) ([]byte, error)
+}
+
+// Adapt returns a Renderer for a TypedRenderer. options maps the common
+// options onto the renderer's own.
+func Adapt[T, O any](r TypedRenderer[T, O], options func(Options) *O) Renderer {
+	return For(r.Format(), r.FileExtension(), func(doc *T, opts Options) ([]byte, error) {
+		return r.Render(doc, options(opts))
+	})
+}
+
+// For returns a Renderer of documents of type *T that calls render. It
+// returns an error for documents of any other type.
+func For[T any](format, ext string, render func(doc *T, opts Options) ([]byte, error)) Renderer {
+	return &funcRenderer[T]{format: format, ext: ext, render: render}
+}
+
+type funcRenderer[T any] struct {
+	format, ext string
+	render      func(*T, Options) ([]byte, error)
+}
+
+func (r *funcRenderer[T]) Format() string        { return r.format }
+func (r *funcRenderer[T]) FileExtension() string { return r.ext }
+
+func (r *funcRenderer[T]) Render(doc any, opts Options) ([]byte, error) {
+	d, ok := doc.(*T)
+	if !ok || d == nil {
+		return nil, fmt.Errorf("%s renderer: unsupported document %T (expected %T)", r.format, doc, d)
+	}
+	if err := ValidateTheme(opts.Theme); err != nil {
+		return nil, err
+	}
+	return r.render(d, opts)
+}
+
+// Registry holds renderers by document type, such as "prd", and format.
+// It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	renderers map[string]map[string]Renderer
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{renderers: map[string]map[string]Renderer{}}
+}
+
+// Register adds the renderer of a document type. It returns an error if
+// the type already has a renderer for the format.
+func (reg *Registry) Register(docType string, r Renderer) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	formats, ok := reg.renderers[docType]
+	if !ok {
+		formats = map[string]Renderer{}
+		reg.renderers[docType] = formats
+	}
+	if _, dup := formats[r.Format()]; dup {
+		return fmt.Errorf("%s already has a %s renderer", docType, r.Format())
+	}
+	formats[r.Format()] = r
+	return nil
+}
+
+// Lookup returns the renderer of a document type for a format. The error
+// names the formats the type does have.
+func (reg *Registry) Lookup(docType, format string) (Renderer, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	formats, ok := reg.renderers[docType]
+	if !ok {
+		return nil, fmt.Errorf("no renderers for document type %q (known: %s)", docType, strings.Join(reg.docTypes(), ", "))
+	}
+	r, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("no %s renderer for %s (use %s)", format, docType, strings.Join(sortedKeys(formats), ", "))
+	}
+	return r, nil
+}
+
+// DocTypes returns the document types with renderers, sorted.
+func (reg *Registry) DocTypes() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.docTypes()
+}
+
+func (reg *Registry) docTypes() []string {
+	return sortedKeys(reg.renderers)
+}
+
+// Formats returns the formats a document type can be rendered to, sorted.
+func (reg *Registry) Formats(docType string) []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return sortedKeys(reg.renderers[docType])
+}
+
+// Render renders doc of the given type to a format.
+func (reg *Registry) Render(docType, format string, doc any, opts Options) ([]byte, error) {
+	r, err := reg.Lookup(docType, format)
+	if err != nil {
+		return nil, err
+	}
+	return r.Render(doc, opts)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Default is the registry of the package-level functions.
+var Default = NewRegistry()
+
+// Register adds a renderer to the Default registry.
+func Register(docType string, r Renderer) error {
+	return Default.Register(docType, r)
+}
+
+// Lookup returns a renderer from the Default registry.
+func Lookup(docType, format string) (Renderer, error) {
+	return Default.Lookup(docType, format)
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+type note struct{ Text string }
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	md := For(FormatMarkdown, ".md", func(n *note, opts Options) ([]byte, error) {
+		return []byte("# " + n.Text + " (" + opts.ThemeName() + ")"), nil
+	})
+	if err := reg.Register("note", md); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("note", md); err == nil {
+		t.Error("duplicate registration should fail")
+	}
+
+	out, err := reg.Render("note", FormatMarkdown, &note{Text: "Hi"}, Options{})
+	if err != nil || string(out) != "# Hi (default)" {
+		t.Errorf("Render() = %q, %v", out, err)
+	}
+	if _, err := reg.Render("note", FormatMarkdown, &note{}, Options{Theme: "neon"}); err == nil || !strings.Contains(err.Error(), `unknown theme "neon"`) {
+		t.Errorf("bad theme error = %v", err)
+	}
+	if _, err := reg.Render("note", FormatMarkdown, "not a note", Options{}); err == nil || !strings.Contains(err.Error(), "unsupported document string") {
+		t.Errorf("wrong type error = %v", err)
+	}
+	if _, err := reg.Lookup("note", FormatHTML); err == nil || !strings.Contains(err.Error(), "use markdown") {
+		t.Errorf("missing format error = %v", err)
+	}
+	if _, err := reg.Lookup("memo", FormatHTML); err == nil || !strings.Contains(err.Error(), "known: note") {
+		t.Errorf("missing type error = %v", err)
+	}
+	if got := strings.Join(reg.Formats("note"), ","); got != "markdown" {
+		t.Errorf("Formats() = %s", got)
+	}
+	if got := strings.Join(reg.DocTypes(), ","); got != "note" {
+		t.Errorf("DocTypes() = %s", got)
+	}
+}
@@ -13,6 +13,12 @@ type Person = common.Person
 // Approver is an alias for common.Approver for backwards compatibility.
 type Approver = common.Approver
 
+// Review is an alias for common.Review.
+type Review = common.Review
+
+// ExternalRefs is an alias for common.ExternalRefs.
+type ExternalRefs = common.ExternalRefs
+
 // Status is an alias for common.Status for backwards compatibility.
 type Status = common.Status
 
@@ -22,6 +28,9 @@ type GlossaryTerm = common.GlossaryTerm
 // CustomSection is an alias for common.CustomSection for backwards compatibility.
 type CustomSection = common.CustomSection
 
+// Comment is an alias for common.Comment.
+type Comment = common.Comment
+
 // Status constants re-exported from common for backward compatibility.
 const (
 	StatusDraft      = common.StatusDraft
@@ -47,6 +56,9 @@ type Document struct {
 	Assumptions    []Assumption    `json:"assumptions,omitempty"`
 	Glossary       []GlossaryTerm  `json:"glossary,omitempty"`
 	CustomSections []CustomSection `json:"customSections,omitempty"`
+
+	// Comments are review comments on parts of the document.
+	Comments []Comment `json:"comments,omitempty"`
 }
 
 // Note: Status type and constants are defined in common/ and aliased above.
@@ -63,6 +75,58 @@ type Metadata struct {
 	Reviewers []Person   `json:"reviewers,omitempty"`
 	Approvers []Approver `json:"approvers,omitempty"`
 	Tags      []string   `json:"tags,omitempty"`
+
+	// Team is the team that owns the document.
+	Team string `json:"team,omitempty"`
+
+	// Reviews tracks requested reviews and their state. While Status is
+	// approved, every role in RequiredReviewRoles must have signed off.
+	Reviews             []Review `json:"reviews,omitempty"`
+	RequiredReviewRoles []string `json:"requiredReviewRoles,omitempty"`
+
+	// ReviewedAt is when the document was last approved. It is required
+	// while Status is approved.
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+
+	// Supersedes lists the IDs of documents this one replaces. SupersededBy
+	// is the ID of the document that replaces this one; a deprecated
+	// document must name it.
+	Supersedes   []string `json:"supersedes,omitempty"`
+	SupersededBy string   `json:"supersededBy,omitempty"`
+
+	// ExternalRefs links this document to other systems, e.g. its
+	// Confluence page. ExternalRefURLs maps a system to a link template
+	// containing "{id}", used to link refs on the document and its
+	// entities that are IDs rather than URLs.
+	ExternalRefs    ExternalRefs      `json:"externalRefs,omitempty"`
+	ExternalRefURLs map[string]string `json:"externalRefUrls,omitempty"`
+
+	// Snippets records the content expanded from the workspace snippet
+	// library, for detecting local edits to it.
+	Snippets []common.SnippetRef `json:"snippets,omitempty"`
+
+	// Profile and Flags describe the kind of product the document is for,
+	// such as an enterprise product with a UI. Conditions maps a top-level
+	// section or an item ID to the condition under which it applies, such
+	// as "profile == enterprise" or "has_ui"; sections and items whose
+	// condition is false are left out when the document is rendered or
+	// checked.
+	Profile    string            `json:"profile,omitempty"`
+	Flags      map[string]bool   `json:"flags,omitempty"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// Lifecycle returns the fields checked by the document lifecycle policy.
+func (m Metadata) Lifecycle() common.Lifecycle {
+	return common.Lifecycle{
+		Status:              m.Status,
+		Reviewers:           m.Reviewers,
+		Approvers:           m.Approvers,
+		Reviews:             m.Reviews,
+		RequiredReviewRoles: m.RequiredReviewRoles,
+		ReviewedAt:          m.ReviewedAt,
+		SupersededBy:        m.SupersededBy,
+	}
 }
 
 // ExecutiveSummary provides high-level market overview.
@@ -174,6 +238,9 @@ type MarketRequirement struct {
 	Segments    []string `json:"segments,omitempty"`   // Which segments need this
 	Personas    []string `json:"personas,omitempty"`   // Which personas need this
 	Tags        []string `json:"tags,omitempty"`       // For filtering by topic/domain
+
+	// ExternalRefs links this requirement to other systems, e.g. its Jira issue.
+	ExternalRefs ExternalRefs `json:"externalRefs,omitempty"`
 }
 
 // Priority represents requirement priority.
@@ -0,0 +1,25 @@
+package mrd
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// ReadFileYAML reads a Document from a YAML file. Field names are the same
+// as in JSON.
+func ReadFileYAML(path string) (*Document, error) {
+	var doc Document
+	if err := yamlconv.ReadFile(path, &doc); err != nil {
+		return nil, fmt.Errorf("reading MRD YAML: %w", err)
+	}
+	return &doc, nil
+}
+
+// WriteFileYAML writes the Document to a YAML file.
+func (d *Document) WriteFileYAML(path string) error {
+	if err := yamlconv.WriteFile(path, d); err != nil {
+		return fmt.Errorf("writing MRD YAML: %w", err)
+	}
+	return nil
+}
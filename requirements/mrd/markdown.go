@@ -15,6 +15,9 @@ type MarkdownOptions struct {
 	SansFont           string
 	MonoFont           string
 	FontFamily         string
+	// LinkGlossary links the first use of each glossary term to its entry
+	// (default: true)
+	LinkGlossary *bool
 }
 
 // DefaultMarkdownOptions returns default options.
@@ -40,6 +43,10 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	// Title
 	sb.WriteString(fmt.Sprintf("# %s\n\n", d.Metadata.Title))
 
+	if notice := common.SupersededNotice("MRD", d.Metadata.SupersededBy); notice != "" {
+		sb.WriteString(fmt.Sprintf("> **%s**\n\n", notice))
+	}
+
 	// Document info table
 	sb.WriteString("| Field | Value |\n")
 	sb.WriteString("|-------|-------|\n")
@@ -52,10 +59,22 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	if len(d.Metadata.Authors) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Author(s)** | %s |\n", common.FormatPeopleMarkdown(d.Metadata.Authors)))
 	}
+	if d.Metadata.Team != "" {
+		sb.WriteString(fmt.Sprintf("| **Team** | %s |\n", d.Metadata.Team))
+	}
 
 	if len(d.Metadata.Tags) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Tags** | %s |\n", strings.Join(d.Metadata.Tags, ", ")))
 	}
+	if len(d.Metadata.Supersedes) > 0 {
+		sb.WriteString(fmt.Sprintf("| **Supersedes** | %s |\n", strings.Join(d.Metadata.Supersedes, ", ")))
+	}
+	if d.Metadata.SupersededBy != "" {
+		sb.WriteString(fmt.Sprintf("| **Superseded By** | %s |\n", d.Metadata.SupersededBy))
+	}
+	if len(d.Metadata.ExternalRefs) > 0 {
+		sb.WriteString(fmt.Sprintf("| **External Links** | %s |\n", common.FormatExternalRefsMarkdown(d.Metadata.ExternalRefs, d.Metadata.ExternalRefURLs)))
+	}
 	sb.WriteString("\n---\n\n")
 
 	// Executive Summary
@@ -303,6 +322,9 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 			if len(req.Segments) > 0 {
 				sb.WriteString(fmt.Sprintf("**Target Segments:** %s\n\n", strings.Join(req.Segments, ", ")))
 			}
+			if len(req.ExternalRefs) > 0 {
+				sb.WriteString(fmt.Sprintf("**Links:** %s\n\n", common.FormatExternalRefsMarkdown(req.ExternalRefs, d.Metadata.ExternalRefURLs)))
+			}
 		}
 	}
 
@@ -469,16 +491,20 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString(fmt.Sprintf("## %d. Glossary\n\n", sectionNum))
 		sb.WriteString("| Term | Definition |\n")
 		sb.WriteString("|------|------------|\n")
-		for _, g := range d.Glossary {
+		anchors := common.GlossaryAnchors(d.Glossary)
+		for i, g := range d.Glossary {
 			term := g.Term
 			if g.Acronym != "" {
 				term = fmt.Sprintf("%s (%s)", g.Term, g.Acronym)
 			}
-			sb.WriteString(fmt.Sprintf("| %s | %s |\n", term, g.Definition))
+			sb.WriteString(fmt.Sprintf("| <a id=\"%s\"></a>%s | %s |\n", anchors[i], term, g.Definition))
 		}
 		sb.WriteString("\n")
 	}
 
+	if opts.LinkGlossary == nil || *opts.LinkGlossary {
+		return common.LinkGlossaryMarkdown(sb.String(), d.Glossary)
+	}
 	return sb.String()
 }
 
@@ -0,0 +1,285 @@
+// Package html provides a standalone HTML renderer for MRD documents.
+package html
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/mrd"
+)
+
+// Renderer renders an MRD as a single self-contained HTML page.
+type Renderer struct{}
+
+// New creates a new MRD HTML renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "html"
+}
+
+// FileExtension returns the file extension for HTML output.
+func (r *Renderer) FileExtension() string {
+	return ".html"
+}
+
+// Render converts an MRD to a standalone HTML page.
+func (r *Renderer) Render(doc *mrd.Document, opts *htmldoc.Options) ([]byte, error) {
+	return r.Page(doc, opts).Render()
+}
+
+// Page lays out an MRD as a page without rendering it, so other formats
+// can reuse the sections.
+func (r *Renderer) Page(doc *mrd.Document, opts *htmldoc.Options) *htmldoc.Page {
+	if opts == nil {
+		opts = &htmldoc.Options{}
+	}
+	m := doc.Metadata
+	page := htmldoc.NewPage("Market Requirements", m.Title)
+	page.Summary = doc.Positioning.Tagline
+	page.CustomCSS = opts.CustomCSS
+	page.KaTeXURL = opts.KaTeXURL
+	page.Meta = []htmldoc.Field{
+		{Label: "ID", Value: m.ID},
+		{Label: "Version", Value: m.Version},
+		{Label: "Status", Value: string(m.Status)},
+		{Label: "Authors", Value: htmldoc.People(m.Authors)},
+		{Label: "Team", Value: m.Team},
+		{Label: "Updated", Value: htmldoc.Date(m.UpdatedAt)},
+		{Label: "Supersedes", Value: strings.Join(m.Supersedes, ", ")},
+	}
+	page.Banner = common.SupersededNotice("MRD", m.SupersededBy)
+	page.Links = m.ExternalRefs.Links(m.ExternalRefURLs)
+
+	page.AddSection("Executive Summary", executiveSummary(doc))
+	page.AddSection("Market Overview", marketOverview(doc))
+	page.AddSection("Target Market", targetMarket(doc))
+	page.AddSection("Competitive Landscape", competition(doc))
+	page.AddSection("Market Requirements", marketRequirements(doc))
+	page.AddSection("Positioning", positioning(doc))
+	page.AddSection("Go-to-Market", goToMarket(doc))
+	page.AddSection("Success Metrics", successMetrics(doc))
+	page.AddSection("Risks", risks(doc))
+	page.AddSection("Assumptions", assumptions(doc))
+	glossary := htmldoc.NewBuilder("glossary")
+	glossary.Glossary(doc.Glossary)
+	page.AddSection("Glossary", glossary)
+	if opts.GlossaryLinks() {
+		page.Glossary = doc.Glossary
+	}
+
+	if opts.IncludeComments {
+		page.AddComments(doc.Comments, commentSections)
+	}
+	return page
+}
+
+// commentSections maps the top-level MRD fields that comment paths start
+// with to the sections that render them.
+var commentSections = map[string]string{
+	"executiveSummary":     "Executive Summary",
+	"marketOverview":       "Market Overview",
+	"targetMarket":         "Target Market",
+	"competitiveLandscape": "Competitive Landscape",
+	"marketRequirements":   "Market Requirements",
+	"positioning":          "Positioning",
+	"goToMarket":           "Go-to-Market",
+	"successMetrics":       "Success Metrics",
+	"risks":                "Risks",
+	"assumptions":          "Assumptions",
+	"glossary":             "Glossary",
+}
+
+func executiveSummary(doc *mrd.Document) *htmldoc.Builder {
+	es := doc.ExecutiveSummary
+	b := htmldoc.NewBuilder("summary")
+	b.Labeled("Market opportunity", es.MarketOpportunity)
+	b.Labeled("Proposed offering", es.ProposedOffering)
+	b.List("Key findings", es.KeyFindings)
+	b.Labeled("Recommendation", es.Recommendation)
+	return b
+}
+
+func marketOverview(doc *mrd.Document) *htmldoc.Builder {
+	mo := doc.MarketOverview
+	b := htmldoc.NewBuilder("market")
+	var sizes [][]string
+	for _, s := range []struct {
+		label string
+		size  mrd.MarketSize
+	}{{"TAM", mo.TAM}, {"SAM", mo.SAM}, {"SOM", mo.SOM}} {
+		if s.size.Value == "" {
+			continue
+		}
+		var year string
+		if s.size.Year != 0 {
+			year = strconv.Itoa(s.size.Year)
+		}
+		sizes = append(sizes, []string{s.label, s.size.Value, year, s.size.Source})
+	}
+	b.Table([]string{"Measure", "Value", "Year", "Source"}, sizes)
+	b.Fields(
+		htmldoc.Field{Label: "Growth rate", Value: mo.GrowthRate},
+		htmldoc.Field{Label: "Market stage", Value: mo.MarketStage},
+	)
+	var trends [][]string
+	for _, t := range mo.Trends {
+		trends = append(trends, []string{t.Name, t.Description, t.Impact, t.Timeframe})
+	}
+	if len(trends) > 0 {
+		b.Heading("Trends")
+		b.Table([]string{"Trend", "Description", "Impact", "Timeframe"}, trends)
+	}
+	b.List("Drivers", mo.Drivers)
+	b.List("Barriers", mo.Barriers)
+	return b
+}
+
+func targetMarket(doc *mrd.Document) *htmldoc.Builder {
+	tm := doc.TargetMarket
+	b := htmldoc.NewBuilder("segment")
+	for _, s := range slices.Concat(tm.PrimarySegments, tm.SecondarySegments) {
+		b.Heading(s.Name)
+		b.Paragraph(s.Description)
+		b.Fields(
+			htmldoc.Field{Label: "Size", Value: s.Size},
+			htmldoc.Field{Label: "Growth", Value: s.Growth},
+		)
+		b.List("Needs", s.Needs)
+		b.List("Challenges", s.Challenges)
+	}
+	var personas [][]string
+	for _, p := range tm.BuyerPersonas {
+		personas = append(personas, []string{p.Name, p.Title, p.BuyingRole, strings.Join(p.PainPoints, "; ")})
+	}
+	if len(personas) > 0 {
+		b.Heading("Buyer Personas")
+		b.Table([]string{"Name", "Title", "Buying Role", "Pain Points"}, personas)
+	}
+	b.Fields(
+		htmldoc.Field{Label: "Verticals", Value: strings.Join(tm.Verticals, ", ")},
+		htmldoc.Field{Label: "Geographic focus", Value: strings.Join(tm.GeographicFocus, ", ")},
+		htmldoc.Field{Label: "Company size", Value: strings.Join(tm.CompanySize, ", ")},
+	)
+	return b
+}
+
+func competition(doc *mrd.Document) *htmldoc.Builder {
+	cl := doc.CompetitiveLandscape
+	b := htmldoc.NewBuilder("competition")
+	b.Paragraph(cl.Overview)
+	var rows [][]string
+	for _, c := range cl.Competitors {
+		rows = append(rows, []string{c.Name, c.Category, strings.Join(c.Strengths, "; "), strings.Join(c.Weaknesses, "; "), c.ThreatLevel})
+	}
+	b.Table([]string{"Competitor", "Category", "Strengths", "Weaknesses", "Threat"}, rows)
+	b.Labeled("Market position", cl.MarketPosition)
+	b.List("Differentiators", cl.Differentiators)
+	b.List("Competitive gaps", cl.CompetitiveGaps)
+	return b
+}
+
+func marketRequirements(doc *mrd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("requirements")
+	var rows [][]string
+	for _, r := range doc.MarketRequirements {
+		rows = append(rows, []string{r.ID, r.Title, r.Description, string(r.Priority), r.Source})
+	}
+	b.Table([]string{"ID", "Title", "Description", "Priority", "Source"}, rows)
+	for _, r := range doc.MarketRequirements {
+		b.ExternalRefs(r.ID, r.ExternalRefs, doc.Metadata.ExternalRefURLs)
+	}
+	return b
+}
+
+func positioning(doc *mrd.Document) *htmldoc.Builder {
+	p := doc.Positioning
+	b := htmldoc.NewBuilder("positioning")
+	b.Paragraph(p.Statement)
+	b.Fields(
+		htmldoc.Field{Label: "Target audience", Value: p.TargetAudience},
+		htmldoc.Field{Label: "Category", Value: p.Category},
+	)
+	b.List("Key benefits", p.KeyBenefits)
+	b.List("Differentiators", p.Differentiators)
+	b.List("Proof points", p.ProofPoints)
+	return b
+}
+
+func goToMarket(doc *mrd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("gtm")
+	g := doc.GoToMarket
+	if g == nil {
+		return b
+	}
+	b.Fields(
+		htmldoc.Field{Label: "Launch strategy", Value: g.LaunchStrategy},
+		htmldoc.Field{Label: "Launch timing", Value: g.LaunchTiming},
+		htmldoc.Field{Label: "Channels", Value: strings.Join(g.DistributionChannels, ", ")},
+		htmldoc.Field{Label: "Partners", Value: g.PartnerStrategy},
+		htmldoc.Field{Label: "Marketing", Value: g.MarketingStrategy},
+		htmldoc.Field{Label: "Sales", Value: g.SalesStrategy},
+	)
+	if ps := g.PricingStrategy; ps != nil {
+		b.Heading("Pricing")
+		b.Fields(
+			htmldoc.Field{Label: "Model", Value: ps.Model},
+			htmldoc.Field{Label: "Positioning", Value: ps.Positioning},
+		)
+		b.Paragraph(ps.Rationale)
+		var tiers [][]string
+		for _, t := range ps.Tiers {
+			tiers = append(tiers, []string{t.Name, t.Price, t.Billing, t.TargetBuyer})
+		}
+		b.Table([]string{"Tier", "Price", "Billing", "Target Buyer"}, tiers)
+	}
+	var milestones [][]string
+	for _, ms := range g.Milestones {
+		milestones = append(milestones, []string{ms.Name, htmldoc.Date(ms.TargetDate), ms.Status})
+	}
+	if len(milestones) > 0 {
+		b.Heading("Milestones")
+		b.Table([]string{"Milestone", "Target Date", "Status"}, milestones)
+	}
+	return b
+}
+
+func successMetrics(doc *mrd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("metrics")
+	var rows [][]string
+	for _, sm := range doc.SuccessMetrics {
+		rows = append(rows, []string{sm.ID, sm.Name, sm.Metric, sm.Target, sm.Timeframe})
+	}
+	b.Table([]string{"ID", "Name", "Metric", "Target", "Timeframe"}, rows)
+	return b
+}
+
+func risks(doc *mrd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("risks")
+	var rows [][]string
+	for _, r := range doc.Risks {
+		rows = append(rows, []string{r.ID, r.Description, r.Probability, r.Impact, r.Mitigation})
+	}
+	b.Table([]string{"ID", "Risk", "Probability", "Impact", "Mitigation"}, rows)
+	return b
+}
+
+func assumptions(doc *mrd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("assumptions")
+	var rows [][]string
+	for _, a := range doc.Assumptions {
+		validated := "no"
+		if a.Validated {
+			validated = "yes"
+		}
+		rows = append(rows, []string{a.ID, a.Description, a.Risk, validated})
+	}
+	b.Table([]string{"ID", "Assumption", "Risk if wrong", "Validated"}, rows)
+	return b
+}
@@ -0,0 +1,428 @@
+// Package marp provides a Marp markdown renderer for MRD documents.
+// Marp is a presentation ecosystem that converts Markdown to slides.
+// See https://marp.app/ for more information.
+package marp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	sdmarp "github.com/grokify/structureddocs/marp"
+
+	"github.com/grokify/structured-plan/render"
+	"github.com/grokify/structured-plan/requirements/mrd"
+)
+
+// Options configures MRD slides. They are the common render options, of
+// which the slides use the theme.
+type Options = render.Options
+
+// DefaultOptions returns the default slide options.
+func DefaultOptions() *Options {
+	return &Options{Theme: render.ThemeDefault}
+}
+
+// Renderer renders MRD documents as Marp slides.
+type Renderer struct{}
+
+// New creates a new MRD Marp renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "marp"
+}
+
+// FileExtension returns the file extension for Marp output.
+func (r *Renderer) FileExtension() string {
+	return ".md"
+}
+
+// Render converts an MRD to Marp markdown slides: the opportunity, market
+// sizing, target segments, competitive landscape, positioning, market
+// requirements, success metrics, and risks. Slides with nothing to show
+// are left out.
+func (r *Renderer) Render(doc *mrd.Document, opts *Options) ([]byte, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	data := &templateData{
+		MRD:         doc,
+		Theme:       sdmarp.GetTheme(opts.Theme),
+		Date:        time.Now().Format("January 2, 2006"),
+		Competitive: competitiveGrid(doc.CompetitiveLandscape.Competitors),
+	}
+
+	mo := doc.MarketOverview
+	cl := doc.CompetitiveLandscape
+	slides := []struct {
+		name string
+		tmpl *template.Template
+		show bool
+	}{
+		{"title", titleSlideTmpl, true},
+		{"opportunity", opportunitySlideTmpl, doc.ExecutiveSummary.MarketOpportunity != "" || doc.ExecutiveSummary.ProposedOffering != ""},
+		{"market sizing", marketSlideTmpl, mo.TAM.Value != "" || mo.SAM.Value != "" || mo.SOM.Value != ""},
+		{"segments", segmentsSlideTmpl, len(doc.TargetMarket.PrimarySegments) > 0},
+		{"competitive landscape", competitionSlideTmpl, len(cl.Competitors) > 0},
+		{"positioning", positioningSlideTmpl, doc.Positioning.Statement != ""},
+		{"requirements", requirementsSlideTmpl, len(doc.MarketRequirements) > 0},
+		{"metrics", metricsSlideTmpl, len(doc.SuccessMetrics) > 0},
+		{"risks", risksSlideTmpl, len(doc.Risks) > 0},
+	}
+
+	var buf bytes.Buffer
+	if err := frontMatterTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering front matter: %w", err)
+	}
+	for _, slide := range slides {
+		if !slide.show {
+			continue
+		}
+		if err := slide.tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("rendering %s slide: %w", slide.name, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// templateData holds data for MRD template rendering.
+type templateData struct {
+	MRD         *mrd.Document
+	Theme       sdmarp.ThemeConfig
+	Date        string
+	Competitive grid
+}
+
+// grid is the competitive landscape as a quadrant table: competitors by
+// threat level (rows) and category (columns).
+type grid struct {
+	Columns []string
+	Rows    []gridRow
+}
+
+type gridRow struct {
+	Label string
+	Cells []string // Competitor names, joined with <br>
+}
+
+// Threat levels and categories in table order. Competitors with another
+// value are placed under "Other", or "Unrated" for a missing threat level.
+var (
+	threatLevels = []string{"High", "Medium", "Low"}
+	categories   = []string{"Direct", "Indirect", "Substitute"}
+)
+
+// competitiveGrid lays out competitors by threat level and category,
+// keeping only the rows and columns that have competitors.
+func competitiveGrid(competitors []mrd.Competitor) grid {
+	classify := func(value string, known []string, fallback string) string {
+		for _, k := range known {
+			if strings.EqualFold(strings.TrimSpace(value), k) {
+				return k
+			}
+		}
+		return fallback
+	}
+	names := make(map[[2]string][]string)
+	usedRows := make(map[string]bool)
+	usedCols := make(map[string]bool)
+	for _, c := range competitors {
+		row := classify(c.ThreatLevel, threatLevels, "Unrated")
+		col := classify(c.Category, categories, "Other")
+		names[[2]string{row, col}] = append(names[[2]string{row, col}], escapeCell(c.Name))
+		usedRows[row], usedCols[col] = true, true
+	}
+
+	var g grid
+	for _, col := range append(categories, "Other") {
+		if usedCols[col] {
+			g.Columns = append(g.Columns, col)
+		}
+	}
+	for _, row := range append(threatLevels, "Unrated") {
+		if !usedRows[row] {
+			continue
+		}
+		r := gridRow{Label: row}
+		for _, col := range g.Columns {
+			r.Cells = append(r.Cells, strings.Join(names[[2]string{row, col}], "<br>"))
+		}
+		g.Rows = append(g.Rows, r)
+	}
+	return g
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+var funcMap = mergeFuncMaps(sdmarp.CommonFuncMap, template.FuncMap{
+	"cell": escapeCell,
+	"priorityLabel": func(p mrd.Priority) string {
+		switch p {
+		case mrd.PriorityMust:
+			return "Must Have"
+		case mrd.PriorityShould:
+			return "Should Have"
+		case mrd.PriorityCould:
+			return "Could Have"
+		case mrd.PriorityWont:
+			return "Won't Have"
+		default:
+			return string(p)
+		}
+	},
+})
+
+// mergeFuncMaps merges multiple template.FuncMaps into one.
+// Later maps override earlier ones for duplicate keys.
+func mergeFuncMaps(maps ...template.FuncMap) template.FuncMap {
+	result := make(template.FuncMap)
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+var frontMatterTmpl = template.Must(template.New("frontMatter").Parse(`---
+marp: true
+theme: {{.Theme.Name}}
+paginate: true
+{{- if .MRD.Metadata.Title}}
+header: "MRD | {{.MRD.Metadata.Title}}"
+{{- end}}
+footer: "{{.MRD.Metadata.ID}}{{if .MRD.Metadata.Version}} | v{{.MRD.Metadata.Version}}{{end}}"
+style: |
+  section {
+    font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+  }
+  section.title {
+    text-align: center;
+    background: linear-gradient(135deg, {{.Theme.PrimaryBgColor}} 0%, {{.Theme.AccentColor}} 100%);
+    color: {{.Theme.PrimaryTextColor}};
+  }
+  section.title h1 {
+    font-size: 2.5em;
+    color: {{.Theme.PrimaryTextColor}};
+  }
+  section.positioning {
+    background: linear-gradient(135deg, {{.Theme.PrimaryBgColor}} 0%, {{.Theme.AccentColor}} 100%);
+    color: {{.Theme.PrimaryTextColor}};
+  }
+  section.positioning h2 {
+    color: {{.Theme.PrimaryTextColor}};
+  }
+  .sizing {
+    display: flex;
+    gap: 1em;
+  }
+  .sizing div {
+    flex: 1;
+    text-align: center;
+    background: #f7fafc;
+    border-top: 4px solid {{.Theme.AccentColor}};
+    border-radius: 8px;
+    padding: 0.5em;
+  }
+  .sizing strong {
+    display: block;
+    font-size: 1.8em;
+  }
+  table {
+    font-size: 0.85em;
+    width: 100%;
+  }
+  th {
+    background: #f7fafc;
+  }
+  blockquote {
+    font-size: 1.2em;
+    border-left: 4px solid {{.Theme.AccentColor}};
+    padding-left: 1em;
+    font-style: italic;
+  }
+---
+
+`))
+
+var titleSlideTmpl = template.Must(template.New("titleSlide").Parse(`<!-- _class: title -->
+
+# {{.MRD.Metadata.Title}}
+
+**Market Requirements Document**
+
+{{- range $i, $a := .MRD.Metadata.Authors}}
+{{- if eq $i 0}}
+**Author:** {{$a.Name}}{{if $a.Role}} ({{$a.Role}}){{end}}
+{{- end}}
+{{- end}}
+**Version:** {{.MRD.Metadata.Version}} | **Status:** {{.MRD.Metadata.Status}}
+**Date:** {{.Date}}
+
+---
+
+`))
+
+var opportunitySlideTmpl = template.Must(template.New("opportunitySlide").Parse(`## The Opportunity
+
+{{- with .MRD.ExecutiveSummary}}
+{{- if .MarketOpportunity}}
+
+> {{.MarketOpportunity}}
+{{- end}}
+{{- if .ProposedOffering}}
+
+**Offering:** {{.ProposedOffering}}
+{{- end}}
+{{- if .KeyFindings}}
+
+### Key Findings
+
+{{range .KeyFindings -}}
+- {{.}}
+{{end}}
+{{- end}}
+{{- if .Recommendation}}
+
+**Recommendation:** {{.Recommendation}}
+{{- end}}
+{{- end}}
+
+---
+
+`))
+
+var marketSlideTmpl = template.Must(template.New("marketSlide").Parse(`## Market Sizing
+
+<div class="sizing">
+{{- with .MRD.MarketOverview}}
+{{- if .TAM.Value}}
+<div><strong>{{.TAM.Value}}</strong>TAM{{if .TAM.Year}} ({{.TAM.Year}}){{end}}</div>
+{{- end}}
+{{- if .SAM.Value}}
+<div><strong>{{.SAM.Value}}</strong>SAM{{if .SAM.Year}} ({{.SAM.Year}}){{end}}</div>
+{{- end}}
+{{- if .SOM.Value}}
+<div><strong>{{.SOM.Value}}</strong>SOM{{if .SOM.Year}} ({{.SOM.Year}}){{end}}</div>
+{{- end}}
+</div>
+{{- if or .GrowthRate .MarketStage}}
+
+{{if .GrowthRate}}**Growth:** {{.GrowthRate}}{{end}}{{if and .GrowthRate .MarketStage}} | {{end}}{{if .MarketStage}}**Stage:** {{.MarketStage}}{{end}}
+{{- end}}
+{{- if .Trends}}
+
+### Trends
+
+{{range .Trends -}}
+- **{{.Name}}**{{if .Impact}} ({{.Impact}} impact){{end}}: {{.Description}}
+{{end}}
+{{- end}}
+{{- end}}
+
+---
+
+`))
+
+var segmentsSlideTmpl = template.Must(template.New("segmentsSlide").Funcs(funcMap).Parse(`## Target Segments
+
+| Segment | Size | Growth | Key Need |
+|---------|------|--------|----------|
+{{- range .MRD.TargetMarket.PrimarySegments}}
+| **{{cell .Name}}** | {{if .Size}}{{cell .Size}}{{else}}-{{end}} | {{if .Growth}}{{cell .Growth}}{{else}}-{{end}} | {{if .Needs}}{{cell (index .Needs 0)}}{{else}}-{{end}} |
+{{- end}}
+
+---
+
+`))
+
+var competitionSlideTmpl = template.Must(template.New("competitionSlide").Funcs(funcMap).Parse(`## Competitive Landscape
+
+| Threat | {{range .Competitive.Columns}}{{.}} | {{end}}
+|--------|{{range .Competitive.Columns}}--------|{{end}}
+{{- range .Competitive.Rows}}
+| **{{.Label}}** | {{range .Cells}}{{.}} | {{end}}
+{{- end}}
+{{- with .MRD.CompetitiveLandscape}}
+{{- if .Differentiators}}
+
+**Our differentiators:** {{join .Differentiators "; "}}
+{{- end}}
+{{- end}}
+
+---
+
+`))
+
+var positioningSlideTmpl = template.Must(template.New("positioningSlide").Parse(`<!-- _class: positioning -->
+
+## Positioning
+
+{{- with .MRD.Positioning}}
+
+> {{.Statement}}
+{{- if .Tagline}}
+
+**{{.Tagline}}**
+{{- end}}
+{{- if .KeyBenefits}}
+
+{{range .KeyBenefits -}}
+- {{.}}
+{{end}}
+{{- end}}
+{{- if .ProofPoints}}
+
+**Proof points:** {{range $i, $p := .ProofPoints}}{{if $i}}; {{end}}{{$p}}{{end}}
+{{- end}}
+{{- end}}
+
+---
+
+`))
+
+var requirementsSlideTmpl = template.Must(template.New("requirementsSlide").Funcs(funcMap).Parse(`## Market Requirements
+
+| ID | Requirement | Priority |
+|----|-------------|----------|
+{{- range $i, $r := .MRD.MarketRequirements}}
+{{- if lt $i 8}}
+| {{$r.ID}} | {{cell (truncate $r.Title 50)}} | {{priorityLabel $r.Priority}} |
+{{- end}}
+{{- end}}
+
+---
+
+`))
+
+var metricsSlideTmpl = template.Must(template.New("metricsSlide").Funcs(funcMap).Parse(`## Success Metrics
+
+| Metric | Target | Timeframe |
+|--------|--------|-----------|
+{{- range .MRD.SuccessMetrics}}
+| {{cell .Name}} | {{cell .Target}} | {{if .Timeframe}}{{cell .Timeframe}}{{else}}-{{end}} |
+{{- end}}
+
+---
+
+`))
+
+var risksSlideTmpl = template.Must(template.New("risksSlide").Funcs(funcMap).Parse(`## Risks & Mitigations
+
+| Risk | Impact | Mitigation |
+|------|--------|------------|
+{{- range .MRD.Risks}}
+| {{cell .Description}} | **{{.Impact}}** | {{if .Mitigation}}{{cell (truncate .Mitigation 50)}}{{else}}-{{end}} |
+{{- end}}
+
+---
+
+`))
@@ -0,0 +1,58 @@
+package marp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+)
+
+func TestRender(t *testing.T) {
+	doc := &mrd.Document{
+		Metadata: mrd.Metadata{ID: "mrd-1", Title: "Agent Governance", Version: "1.0.0"},
+		MarketOverview: mrd.MarketOverview{
+			TAM:        mrd.MarketSize{Value: "$9.5B", Year: 2030},
+			SOM:        mrd.MarketSize{Value: "$250M"},
+			GrowthRate: "46% CAGR",
+		},
+		CompetitiveLandscape: mrd.CompetitiveLandscape{
+			Competitors: []mrd.Competitor{
+				{Name: "Vault", Category: "Indirect", ThreatLevel: "Medium"},
+				{Name: "Acme", Category: "direct", ThreatLevel: "High"},
+				{Name: "Beta", Category: "Direct", ThreatLevel: "high"},
+				{Name: "Gamma"},
+			},
+		},
+		Positioning: mrd.Positioning{Statement: "For platform teams who govern agents.", Tagline: "Zero trust for agents"},
+	}
+
+	out, err := New().Render(doc, nil)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	content := string(out)
+
+	for _, want := range []string{
+		"marp: true",
+		"## Market Sizing",
+		"<div><strong>$9.5B</strong>TAM (2030)</div>",
+		"<div><strong>$250M</strong>SOM</div>",
+		"**Growth:** 46% CAGR",
+		"| Threat | Direct | Indirect | Other | ",
+		"| **High** | Acme<br>Beta |  |  | ",
+		"| **Medium** |  | Vault |  | ",
+		"| **Unrated** |  |  | Gamma | ",
+		"<!-- _class: positioning -->",
+		"> For platform teams who govern agents.",
+		"**Zero trust for agents**",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	for _, absent := range []string{"## Target Segments", "## Market Requirements", "## Risks"} {
+		if strings.Contains(content, absent) {
+			t.Errorf("output has empty slide %q", absent)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package prd
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// accessibilityPattern matches text that addresses accessibility.
+	accessibilityPattern = regexp.MustCompile(`(?i)\b(accessib\w*|a11y|wcag|section 508|en 301 549|aria|screen[- ]?readers?|keyboard|assistive|alt text|captions?|colou?r contrast|contrast ratio|focus (order|indicators?|visible|management))\b`)
+
+	// userFacingPattern matches functional requirements with a user interface.
+	userFacingPattern = regexp.MustCompile(`(?i)\b(ui|ux|user interface|frontend|front-end|web|mobile|screens?|pages?|forms?|buttons?|dialogs?|modals?|dashboards?|displays?|views?|click|menus?|wizards?|onboarding)\b`)
+)
+
+// AccessibilityReport summarizes how a declared accessibility standard is
+// carried through to requirements.
+type AccessibilityReport struct {
+	// Standard is the declared standard, e.g. "WCAG 2.2 AA".
+	Standard string `json:"standard"`
+
+	// NFRIDs lists non-functional requirements that address accessibility.
+	NFRIDs []string `json:"nfrIds,omitempty"`
+
+	// CriteriaIDs lists acceptance criteria, on functional requirements or
+	// user stories, that address accessibility.
+	CriteriaIDs []string `json:"criteriaIds,omitempty"`
+
+	// UserFacingFRs counts functional requirements with a user interface.
+	UserFacingFRs int `json:"userFacingFrs"`
+
+	// UncoveredFRIDs lists user-facing functional requirements with no
+	// accessibility consideration.
+	UncoveredFRIDs []string `json:"uncoveredFrIds,omitempty"`
+}
+
+// FRCoverage returns the fraction of user-facing functional requirements
+// that consider accessibility, or 1 if there are none.
+func (r *AccessibilityReport) FRCoverage() float64 {
+	if r.UserFacingFRs == 0 {
+		return 1
+	}
+	return float64(r.UserFacingFRs-len(r.UncoveredFRIDs)) / float64(r.UserFacingFRs)
+}
+
+// CheckAccessibility checks that a declared accessibility standard is backed
+// by NFRs and acceptance criteria, and finds user-facing functional
+// requirements that do not consider accessibility. It returns nil if the
+// document declares no standard.
+func CheckAccessibility(doc *Document) *AccessibilityReport {
+	if doc.UXRequirements == nil || strings.TrimSpace(doc.UXRequirements.Accessibility.Standard) == "" {
+		return nil
+	}
+	report := &AccessibilityReport{Standard: doc.UXRequirements.Accessibility.Standard}
+
+	for _, nfr := range doc.Requirements.NonFunctional {
+		if nfr.Category == NFRAccessibility ||
+			mentionsAccessibility(nfr.Title, nfr.Description, nfr.Metric, nfr.Target) ||
+			hasTag(nfr.Tags, "accessibility") {
+			report.NFRIDs = append(report.NFRIDs, nfr.ID)
+		}
+	}
+
+	for _, fr := range doc.Requirements.Functional {
+		criteria := accessibilityCriteria(fr.AcceptanceCriteria)
+		report.CriteriaIDs = append(report.CriteriaIDs, criteria...)
+
+		if !isUserFacing(fr) {
+			continue
+		}
+		report.UserFacingFRs++
+		if len(criteria) == 0 &&
+			!mentionsAccessibility(fr.Title, fr.Description, fr.Notes) &&
+			!hasTag(fr.Tags, "accessibility") {
+			report.UncoveredFRIDs = append(report.UncoveredFRIDs, fr.ID)
+		}
+	}
+
+	for _, story := range doc.UserStories {
+		report.CriteriaIDs = append(report.CriteriaIDs, accessibilityCriteria(story.AcceptanceCriteria)...)
+	}
+
+	return report
+}
+
+func accessibilityCriteria(criteria []AcceptanceCriterion) []string {
+	var ids []string
+	for _, ac := range criteria {
+		if mentionsAccessibility(ac.Description, ac.Given, ac.When, ac.Then) {
+			ids = append(ids, ac.ID)
+		}
+	}
+	return ids
+}
+
+// isUserFacing reports whether a functional requirement describes behavior
+// users interact with directly.
+func isUserFacing(fr FunctionalRequirement) bool {
+	return userFacingPattern.MatchString(fr.Category) ||
+		userFacingPattern.MatchString(strings.Join(fr.Tags, " ")) ||
+		userFacingPattern.MatchString(fr.Title+" "+fr.Description)
+}
+
+func mentionsAccessibility(texts ...string) bool {
+	for _, text := range texts {
+		if accessibilityPattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package prd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func accessibilityTestDoc() *Document {
+	return &Document{
+		UXRequirements: &UXRequirements{
+			Accessibility: AccessibilitySpec{Standard: "WCAG 2.2 AA"},
+		},
+		Requirements: Requirements{
+			Functional: []FunctionalRequirement{
+				{ID: "FR-001", Title: "Checkout form", Description: "Users enter payment details"},
+				{ID: "FR-002", Title: "Settings page", Tags: []string{"accessibility"}},
+				{ID: "FR-003", Title: "Nightly batch export", Description: "Variable-length records are written to S3"},
+				{ID: "FR-004", Title: "Order history", Category: "UI", AcceptanceCriteria: []AcceptanceCriterion{
+					{ID: "AC-004-1", Description: "All controls are reachable by keyboard"},
+				}},
+			},
+		},
+		UserStories: []UserStory{
+			{ID: "US-001", AcceptanceCriteria: []AcceptanceCriterion{
+				{ID: "AC-US-1", Then: "the error is announced by screen readers"},
+			}},
+		},
+	}
+}
+
+func TestCheckAccessibility(t *testing.T) {
+	if CheckAccessibility(&Document{}) != nil {
+		t.Error("expected nil report without a declared standard")
+	}
+
+	doc := accessibilityTestDoc()
+	report := CheckAccessibility(doc)
+	if report == nil {
+		t.Fatal("expected report")
+	}
+	if len(report.NFRIDs) != 0 {
+		t.Errorf("NFRIDs = %v, want none", report.NFRIDs)
+	}
+	if !reflect.DeepEqual(report.CriteriaIDs, []string{"AC-004-1", "AC-US-1"}) {
+		t.Errorf("CriteriaIDs = %v", report.CriteriaIDs)
+	}
+	if report.UserFacingFRs != 3 {
+		t.Errorf("UserFacingFRs = %d, want 3", report.UserFacingFRs)
+	}
+	if !reflect.DeepEqual(report.UncoveredFRIDs, []string{"FR-001"}) {
+		t.Errorf("UncoveredFRIDs = %v", report.UncoveredFRIDs)
+	}
+
+	doc.Requirements.NonFunctional = []NonFunctionalRequirement{
+		{ID: "NFR-001", Category: NFRAccessibility, Title: "Conformance"},
+		{ID: "NFR-002", Category: NFRUsability, Title: "Color contrast ratio of at least 4.5:1"},
+		{ID: "NFR-003", Category: NFRPerformance, Title: "Fast pages"},
+	}
+	report = CheckAccessibility(doc)
+	if !reflect.DeepEqual(report.NFRIDs, []string{"NFR-001", "NFR-002"}) {
+		t.Errorf("NFRIDs = %v", report.NFRIDs)
+	}
+}
+
+func TestValidateAccessibility(t *testing.T) {
+	result := Validate(accessibilityTestDoc())
+
+	var got []string
+	for _, w := range result.Warnings {
+		if strings.HasPrefix(w.Field, "ux_requirements.accessibility") || strings.Contains(w.Message, "accessibility consideration") {
+			got = append(got, w.Field+": "+w.Message)
+		}
+	}
+	want := []string{
+		"ux_requirements.accessibility: WCAG 2.2 AA declared but no accessibility non-functional requirement defined",
+		"requirements.functional[0]: User-facing requirement FR-001 has no accessibility consideration",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("accessibility warnings =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestScoreUXCoverageAccessibility(t *testing.T) {
+	doc := accessibilityTestDoc()
+	partial := scoreUXCoverage(doc).Score
+
+	doc.Requirements.NonFunctional = []NonFunctionalRequirement{{ID: "NFR-001", Category: NFRAccessibility}}
+	doc.Requirements.Functional[0].Description += " with visible focus indicators"
+	full := scoreUXCoverage(doc).Score
+
+	if full != 2.0 {
+		t.Errorf("fully covered accessibility score = %v, want 2.0", full)
+	}
+	if partial >= full {
+		t.Errorf("partial coverage score %v should be below full %v", partial, full)
+	}
+}
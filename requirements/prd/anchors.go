@@ -0,0 +1,248 @@
+package prd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// headingIDPattern matches an explicit heading ID such as "{#sec-5-1-core}".
+var headingIDPattern = regexp.MustCompile(`\s*\{#([^}\s]+)\}\s*$`)
+
+// linkPattern matches the target of an in-document link such as
+// "[Core](#sec-5-1-core)".
+var linkPattern = regexp.MustCompile(`\]\(#([^)\s]+)\)`)
+
+// anchorTagPattern matches an HTML anchor such as `<a id="term-slo">`, which
+// glossary entries carry.
+var anchorTagPattern = regexp.MustCompile(`<a id="([^"]+)">`)
+
+// headingID returns the ID given to a heading: "sec-" followed by the
+// heading text as lowercase words joined by hyphens, so "5.1 Core" becomes
+// "sec-5-1-core".
+func headingID(text string) string {
+	return "sec-" + anchorSlug(text)
+}
+
+// anchorSlug lowercases s and joins its runs of letters and digits with
+// hyphens.
+func anchorSlug(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+			hyphen = false
+		case sb.Len() > 0 && !hyphen:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// githubSlug returns the anchor GitHub generates for a heading without an
+// explicit ID.
+func githubSlug(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			sb.WriteByte('-')
+		case r == '-', r == '_', unicode.IsLetter(r), unicode.IsDigit(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// markdownHeading is an ATX heading in a Markdown document.
+type markdownHeading struct {
+	line  int
+	level int
+	text  string
+	id    string // explicit ID, if any
+}
+
+// markdownHeadings returns the headings of md, split into lines, outside
+// the frontmatter and fenced code blocks.
+func markdownHeadings(lines []string) []markdownHeading {
+	var headings []markdownHeading
+	frontmatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case frontmatter:
+			if i > 0 && trimmed == "---" {
+				frontmatter = false
+			}
+			continue
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			continue
+		}
+		level := 0
+		for level < len(line) && level < 6 && line[level] == '#' {
+			level++
+		}
+		if level == 0 || level >= len(line) || line[level] != ' ' {
+			continue
+		}
+		h := markdownHeading{line: i, level: level, text: strings.TrimSpace(line[level:])}
+		if m := headingIDPattern.FindStringSubmatchIndex(h.text); m != nil {
+			h.id = h.text[m[2]:m[3]]
+			h.text = h.text[:m[0]]
+		}
+		headings = append(headings, h)
+	}
+	return headings
+}
+
+// addHeadingIDs gives every section heading of md without an explicit ID
+// one made from its text, such as "## 5. Functional Requirements
+// {#sec-5-functional-requirements}". The IDs depend only on the headings,
+// so they are the same each time a document is generated, and they do not
+// rely on the renderer's own slug rules: Pandoc, for one, drops the leading
+// section number. A heading whose ID is taken, such as a second "Overview",
+// is qualified by its parent's ID.
+func addHeadingIDs(md string) string {
+	lines := strings.SplitAfter(md, "\n")
+	headings := markdownHeadings(lines)
+	used := make(map[string]bool, len(headings))
+	for _, h := range headings {
+		if h.id != "" {
+			used[h.id] = true
+		}
+	}
+
+	var parents [7]string // ID of the current heading at each level
+	for _, h := range headings {
+		if h.level == 1 {
+			continue
+		}
+		id := h.id
+		if id == "" {
+			id = headingID(h.text)
+			if used[id] {
+				for l := h.level - 1; l >= 2; l-- {
+					if parents[l] != "" {
+						id = parents[l] + "-" + anchorSlug(h.text)
+						break
+					}
+				}
+			}
+			base := id
+			for n := 2; used[id]; n++ {
+				id = base + "-" + strconv.Itoa(n)
+			}
+			used[id] = true
+			line := lines[h.line]
+			eol := line[len(strings.TrimRight(line, "\r\n")):]
+			lines[h.line] = strings.TrimRight(line, "\r\n") + " {#" + id + "}" + eol
+		}
+		parents[h.level] = id
+		for l := h.level + 1; l < len(parents); l++ {
+			parents[l] = ""
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// MarkdownAnchors returns the anchors of the headings in md: the explicit
+// ID of each heading that has one, and GitHub's slug of the others. The IDs
+// of HTML anchors, such as those of glossary entries, are included too.
+func MarkdownAnchors(md string) map[string]bool {
+	anchors := make(map[string]bool)
+	for _, m := range anchorTagPattern.FindAllStringSubmatch(md, -1) {
+		anchors[m[1]] = true
+	}
+	for _, h := range markdownHeadings(strings.SplitAfter(md, "\n")) {
+		if h.id != "" {
+			anchors[h.id] = true
+		} else {
+			anchors[githubSlug(h.text)] = true
+		}
+	}
+	return anchors
+}
+
+// BrokenAnchors returns the targets of the in-document links of md, such
+// as table of contents entries and appendix references, that match no
+// heading, in order of first use.
+func BrokenAnchors(md string) []string {
+	anchors := MarkdownAnchors(md)
+	seen := make(map[string]bool)
+	var broken []string
+	for _, m := range linkPattern.FindAllStringSubmatch(md, -1) {
+		if target := m[1]; !anchors[target] && !seen[target] {
+			seen[target] = true
+			broken = append(broken, target)
+		}
+	}
+	return broken
+}
+
+// LinkIssue describes an in-document link or appendix reference that does
+// not resolve.
+type LinkIssue struct {
+	Field   string `json:"field"` // e.g., "securityModel.appendixRefs[0]"; empty for a generated link
+	Target  string `json:"target"`
+	Message string `json:"message"`
+}
+
+// ValidateLinks checks that the appendix references of the document name
+// one of its appendices, and that the in-document links of its generated
+// Markdown, such as the table of contents, resolve to a heading.
+func (d *Document) ValidateLinks() []LinkIssue {
+	appendices := make(map[string]bool, len(d.Appendices))
+	for _, a := range d.Appendices {
+		appendices[a.ID] = true
+	}
+
+	var issues []LinkIssue
+	reported := make(map[string]bool)
+	checkRefs := func(field string, refs []string) {
+		for i, ref := range refs {
+			if appendices[ref] {
+				continue
+			}
+			issues = append(issues, LinkIssue{
+				Field:   fmt.Sprintf("%s.appendixRefs[%d]", field, i),
+				Target:  ref,
+				Message: fmt.Sprintf("Reference to undefined appendix: %s", ref),
+			})
+			reported["appendix-"+toSlug(ref)] = true
+		}
+	}
+	if d.SecurityModel != nil {
+		checkRefs("securityModel", d.SecurityModel.AppendixRefs)
+	}
+	for i, fr := range d.Requirements.Functional {
+		checkRefs(fmt.Sprintf("requirements.functional[%d]", i), fr.AppendixRefs)
+	}
+	for i, nfr := range d.Requirements.NonFunctional {
+		checkRefs(fmt.Sprintf("requirements.nonFunctional[%d]", i), nfr.AppendixRefs)
+	}
+	for i, risk := range d.Risks {
+		checkRefs(fmt.Sprintf("risks[%d]", i), risk.AppendixRefs)
+	}
+
+	for _, target := range BrokenAnchors(d.ToMarkdown(DefaultMarkdownOptions())) {
+		if !reported[target] {
+			issues = append(issues, LinkIssue{
+				Target:  target,
+				Message: fmt.Sprintf("Generated Markdown links to #%s, which matches no heading", target),
+			})
+		}
+	}
+	return issues
+}
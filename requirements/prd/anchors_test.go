@@ -0,0 +1,124 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddHeadingIDs(t *testing.T) {
+	md := strings.Join([]string{
+		"---",
+		"## Not a heading",
+		"---",
+		"# Title",
+		"## 5. Functional Requirements",
+		"### 5.1 Core",
+		"### Overview",
+		"## Technical Architecture",
+		"### Overview",
+		"### Overview",
+		"## Appendices",
+		"### Appendix A: Data {#appendix-data}",
+		"```",
+		"## Not a heading",
+		"```",
+		"#hashtag",
+		"",
+	}, "\n")
+	want := strings.Join([]string{
+		"---",
+		"## Not a heading",
+		"---",
+		"# Title",
+		"## 5. Functional Requirements {#sec-5-functional-requirements}",
+		"### 5.1 Core {#sec-5-1-core}",
+		"### Overview {#sec-overview}",
+		"## Technical Architecture {#sec-technical-architecture}",
+		"### Overview {#sec-technical-architecture-overview}",
+		"### Overview {#sec-technical-architecture-overview-2}",
+		"## Appendices {#sec-appendices}",
+		"### Appendix A: Data {#appendix-data}",
+		"```",
+		"## Not a heading",
+		"```",
+		"#hashtag",
+		"",
+	}, "\n")
+	got := addHeadingIDs(md)
+	if got != want {
+		t.Errorf("addHeadingIDs =\n%s\nwant\n%s", got, want)
+	}
+	if again := addHeadingIDs(got); again != got {
+		t.Errorf("addHeadingIDs is not idempotent:\n%s", again)
+	}
+}
+
+func TestBrokenAnchors(t *testing.T) {
+	md := strings.Join([]string{
+		"## 1. Summary {#sec-1-summary}",
+		"## Plain Heading",
+		"[ok](#sec-1-summary) [plain](#plain-heading) [missing](#sec-2-goals)",
+		"[again](#sec-2-goals) [external](https://example.com/#x)",
+	}, "\n")
+	got := BrokenAnchors(md)
+	if len(got) != 1 || got[0] != "sec-2-goals" {
+		t.Errorf("BrokenAnchors = %v, want [sec-2-goals]", got)
+	}
+}
+
+func TestValidateLinks(t *testing.T) {
+	doc := Document{
+		Requirements: Requirements{
+			Functional: []FunctionalRequirement{{ID: "FR-1", AppendixRefs: []string{"data-model", "missing"}}},
+		},
+		SecurityModel:  &SecurityModel{AppendixRefs: []string{"threats"}},
+		Appendices:     []Appendix{{ID: "data-model", Title: "Data Model"}},
+		CustomSections: []CustomSection{{Title: "Deployment Options"}, {Title: "Pricing / Cost Model"}},
+	}
+	doc.Resourcing = []Allocation{{Role: "Engineer", Count: 1}}
+
+	md := doc.ToMarkdown(DefaultMarkdownOptions())
+	if broken := BrokenAnchors(md); len(broken) != 1 || broken[0] != "appendix-threats" {
+		t.Errorf("BrokenAnchors = %v, want only the unresolved security reference", broken)
+	}
+
+	issues := doc.ValidateLinks()
+	if len(issues) != 2 {
+		t.Fatalf("ValidateLinks = %+v, want 2 issues", issues)
+	}
+	if issues[0].Field != "securityModel.appendixRefs[0]" || issues[0].Target != "threats" {
+		t.Errorf("issues[0] = %+v", issues[0])
+	}
+	if issues[1].Field != "requirements.functional[0].appendixRefs[1]" || issues[1].Target != "missing" {
+		t.Errorf("issues[1] = %+v", issues[1])
+	}
+
+	result := Validate(&doc)
+	found := false
+	for _, e := range result.Errors {
+		if e.Field == "securityModel.appendixRefs[0]" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Validate errors = %+v, want the unresolved appendix reference", result.Errors)
+	}
+}
+
+func TestOmitSections(t *testing.T) {
+	doc := newLargeDocument(3)
+	md := doc.ToMarkdown(MarkdownOptions{OmitSections: []string{"objectives", "personas", "userStories", "requirements"}})
+	for _, unwanted := range []string{"Objectives and Goals", "Personas", "User Stories", "Functional Requirements"} {
+		if strings.Contains(md, ". "+unwanted+" {#") {
+			t.Errorf("ToMarkdown kept the %s heading", unwanted)
+		}
+	}
+	for _, want := range []string{"## 1. Executive Summary {#sec-1-executive-summary}", "## 2. Roadmap {#sec-2-roadmap}", "2. [Roadmap](#sec-2-roadmap)"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown missing %q", want)
+		}
+	}
+	if broken := BrokenAnchors(md); len(broken) > 0 {
+		t.Errorf("BrokenAnchors = %v", broken)
+	}
+}
@@ -0,0 +1,39 @@
+package prd
+
+import (
+	"strings"
+
+	"github.com/grokify/structured-plan/budget"
+)
+
+// CostItems returns the cost estimates on roadmap phases and technology
+// stack entries. Technology costs without a phase are unscheduled.
+func (d *Document) CostItems() []budget.Item {
+	items := budget.PhaseItems(d.Roadmap.Phases)
+	if d.TechArchitecture == nil {
+		return items
+	}
+	ts := d.TechArchitecture.TechnologyStack
+	for _, group := range [][]Technology{ts.Frontend, ts.Backend, ts.Database, ts.Infrastructure, ts.DevOps, ts.Monitoring} {
+		for _, t := range group {
+			if !t.Cost.IsZero() {
+				items = append(items, budget.Item{Kind: budget.KindTechnology, Name: t.Name, Cost: *t.Cost})
+			}
+		}
+	}
+	return items
+}
+
+// Budget rolls up the document's cost estimates by roadmap phase and
+// quarter.
+func (d *Document) Budget() *budget.Report {
+	return budget.Rollup(d.CostItems(), d.Roadmap.Phases)
+}
+
+func (d *Document) generateBudget(r *budget.Report) string {
+	var sb strings.Builder
+	sb.WriteString("## Budget\n\n")
+	sb.WriteString(r.ToMarkdown())
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
@@ -0,0 +1,37 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+func TestDocumentBudget(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	doc := Document{
+		Roadmap: Roadmap{Phases: []roadmap.Phase{
+			{ID: "p1", Name: "MVP", StartDate: &start, EndDate: &end, Cost: &Cost{Build: 50000}},
+		}},
+		TechArchitecture: &TechnicalArchitecture{
+			TechnologyStack: TechnologyStack{
+				Backend:  []Technology{{Name: "Postgres", Cost: &Cost{Run: 400, PhaseID: "p1"}}},
+				Frontend: []Technology{{Name: "React"}},
+			},
+		},
+	}
+
+	items := doc.CostItems()
+	if len(items) != 2 || items[0].Kind != "phase" || items[1].Name != "Postgres" {
+		t.Fatalf("CostItems() = %+v", items)
+	}
+
+	md := doc.ToMarkdown(MarkdownOptions{})
+	for _, want := range []string{"[Budget](#sec-budget)", "## Budget {#sec-budget}\n\n", "| MVP | 2026-01-01 to 2026-03-31 | 50,000 | 400 |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q", want)
+		}
+	}
+}
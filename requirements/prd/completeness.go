@@ -819,11 +819,24 @@ func (d *Document) checkUXRequirements() SectionScore {
 		points += 1
 	}
 
-	if d.UXRequirements.Accessibility.Standard != "" {
+	// Journey maps count as a bonus, so PRDs without them are not marked down
+	if len(d.UXRequirements.JourneyMaps) > 0 {
 		points += 0.5
+		if covered := d.JourneyPersonaCoverage(); covered < len(d.Personas) {
+			score.Suggestions = append(score.Suggestions,
+				fmt.Sprintf("Add journey maps for the %d personas without one", len(d.Personas)-covered))
+		}
 	}
 
-	score.Score = (points / maxPoints) * 100
+	if report := CheckAccessibility(d); report != nil {
+		points += 0.5
+		if len(report.NFRIDs) == 0 || len(report.UncoveredFRIDs) > 0 {
+			score.Suggestions = append(score.Suggestions,
+				fmt.Sprintf("Carry %s into accessibility NFRs and acceptance criteria for user-facing requirements", report.Standard))
+		}
+	}
+
+	score.Score = minFloat(points/maxPoints, 1) * 100
 	score.Status = getStatus(score.Score)
 
 	return score
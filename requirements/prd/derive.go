@@ -0,0 +1,325 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+)
+
+// TODOPrefix starts every placeholder written by DeriveFromMRD, so that
+// unfinished sections can be found with a text search.
+const TODOPrefix = "TODO:"
+
+func todo(what string) string {
+	return TODOPrefix + " " + what
+}
+
+// Provenance link kinds written by DeriveFromMRD.
+const (
+	ProvenancePersona     = "persona"
+	ProvenanceObjective   = "objective"
+	ProvenanceKeyResult   = "keyResult"
+	ProvenanceRequirement = "requirement"
+	ProvenanceOutOfScope  = "outOfScope"
+	ProvenanceAlternative = "alternative"
+	ProvenanceRisk        = "risk"
+	ProvenanceAssumption  = "assumption"
+)
+
+// DeriveFromMRD returns a starter PRD for the offering described by m.
+// Buyer personas become personas, positioning and success metrics become an
+// objective with key results, market requirements become functional
+// requirement stubs (or out-of-scope entries when marked "wont"), and
+// competitors, risks, assumptions, and the glossary are carried over. Every
+// derived item is linked back to its MRD IDs under Provenance. Fields the MRD
+// cannot supply are set to TODO placeholders; the second result lists their
+// JSON paths.
+func DeriveFromMRD(m *mrd.Document) (*Document, []string) {
+	var todos []string
+	placeholder := func(path, what string) string {
+		todos = append(todos, path)
+		return todo(what)
+	}
+	prov := &Provenance{Source: SourceRef{
+		Type:    "mrd",
+		ID:      m.Metadata.ID,
+		Title:   m.Metadata.Title,
+		Version: m.Metadata.Version,
+	}}
+	link := func(itemID, kind string, sourceIDs ...string) {
+		var ids []string
+		for _, id := range sourceIDs {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			prov.Links = append(prov.Links, ProvenanceLink{ItemID: itemID, Kind: kind, SourceIDs: ids})
+		}
+	}
+
+	now := time.Now()
+	pos := m.Positioning
+	d := &Document{
+		Metadata: Metadata{
+			ID:        deriveID(m.Metadata.ID),
+			Title:     deriveTitle(m.Metadata.Title),
+			Version:   "0.1.0",
+			Status:    StatusDraft,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Authors:   []Person{{Name: placeholder("metadata.authors", "product manager")}},
+			Tags:      m.Metadata.Tags,
+		},
+		ExecutiveSummary: ExecutiveSummary{
+			ProblemStatement: m.ExecutiveSummary.MarketOpportunity,
+			ProposedSolution: m.ExecutiveSummary.ProposedOffering,
+			ExpectedOutcomes: append([]string{}, pos.KeyBenefits...),
+			TargetAudience:   pos.TargetAudience,
+			ValueProposition: pos.Statement,
+		},
+		Requirements: Requirements{
+			Functional:    []FunctionalRequirement{},
+			NonFunctional: []NonFunctionalRequirement{},
+		},
+		Glossary:   m.Glossary,
+		Provenance: prov,
+	}
+	if d.Metadata.Title == "" {
+		d.Metadata.Title = placeholder("metadata.title", "product name")
+	}
+	if d.ExecutiveSummary.ProblemStatement == "" {
+		d.ExecutiveSummary.ProblemStatement = placeholder("executiveSummary.problemStatement", "the user problem being solved")
+	}
+	if d.ExecutiveSummary.ProposedSolution == "" {
+		d.ExecutiveSummary.ProposedSolution = placeholder("executiveSummary.proposedSolution", "the product being proposed")
+	}
+
+	for i, bp := range m.TargetMarket.BuyerPersonas {
+		id := bp.ID
+		if id == "" {
+			id = fmt.Sprintf("persona-%d", i+1)
+		}
+		d.Personas = append(d.Personas, Persona{
+			ID:                id,
+			Name:              bp.Name,
+			Role:              bp.Title,
+			Description:       bp.Description,
+			Goals:             append([]string{}, bp.Goals...),
+			PainPoints:        append([]string{}, bp.PainPoints...),
+			Motivations:       bp.BuyingCriteria,
+			PreferredChannels: bp.InformationSources,
+			IsPrimary:         i == 0,
+			Tags:              bp.Tags,
+		})
+		link(id, ProvenancePersona, bp.ID)
+	}
+	if len(d.Personas) == 0 {
+		d.Personas = []Persona{{
+			ID:         "persona-1",
+			Name:       placeholder("personas", "primary persona"),
+			Role:       todo("job title"),
+			Goals:      []string{},
+			PainPoints: []string{},
+			IsPrimary:  true,
+		}}
+	}
+	primary := d.Personas[0]
+
+	obj := Objective{
+		ID:          "OBJ-1",
+		Title:       pos.Tagline,
+		Description: pos.Statement,
+		Rationale:   m.ExecutiveSummary.Recommendation,
+		Category:    pos.Category,
+		KeyResults:  []KeyResult{},
+	}
+	if obj.Title == "" {
+		obj.Title = placeholder("objectives.okrs[0].objective.title", "product objective")
+	}
+	var krs []KeyResult
+	for i, sm := range m.SuccessMetrics {
+		kr := KeyResult{
+			ID:                fmt.Sprintf("KR-%d", i+1),
+			Title:             sm.Name,
+			Description:       sm.Description,
+			Metric:            sm.Metric,
+			Target:            sm.Target,
+			MeasurementMethod: sm.MeasurementMethod,
+			Tags:              sm.Tags,
+		}
+		if kr.Target == "" {
+			kr.Target = placeholder(fmt.Sprintf("objectives.okrs[0].keyResults[%d].target", i), "target value")
+		}
+		krs = append(krs, kr)
+		link(kr.ID, ProvenanceKeyResult, sm.ID)
+	}
+	if len(krs) == 0 {
+		krs = []KeyResult{{
+			ID:     "KR-1",
+			Title:  placeholder("objectives.okrs[0].keyResults", "measurable key result"),
+			Target: todo("target value"),
+		}}
+	}
+	d.Objectives.OKRs = []OKR{{Objective: obj, KeyResults: krs}}
+	metricIDs := make([]string, 0, len(m.SuccessMetrics))
+	for _, sm := range m.SuccessMetrics {
+		metricIDs = append(metricIDs, sm.ID)
+	}
+	link(obj.ID, ProvenanceObjective, metricIDs...)
+
+	d.Roadmap.Phases = []Phase{{
+		ID:              "phase-1",
+		Name:            placeholder("roadmap.phases", "first release, e.g. MVP"),
+		Type:            PhaseTypeMilestone,
+		Goals:           []string{},
+		Deliverables:    []Deliverable{},
+		SuccessCriteria: []string{},
+	}}
+
+	for _, mr := range m.MarketRequirements {
+		if mr.Priority == mrd.PriorityWont {
+			d.OutOfScope = append(d.OutOfScope, strings.TrimSpace(mr.ID+": "+mr.Title))
+			link(fmt.Sprintf("outOfScope[%d]", len(d.OutOfScope)-1), ProvenanceOutOfScope, mr.ID)
+			continue
+		}
+		id := fmt.Sprintf("FR-%d", len(d.Requirements.Functional)+1)
+		d.Requirements.Functional = append(d.Requirements.Functional, FunctionalRequirement{
+			ID:                 id,
+			Title:              mr.Title,
+			Description:        mr.Description,
+			Category:           mr.Category,
+			Priority:           MoSCoW(mr.Priority),
+			UserStoryIDs:       []string{},
+			AcceptanceCriteria: []AcceptanceCriterion{},
+			PhaseID:            "phase-1",
+			Tags:               mr.Tags,
+			Notes:              deriveRequirementNotes(mr),
+		})
+		link(id, ProvenanceRequirement, mr.ID)
+	}
+	if len(d.OutOfScope) == 0 {
+		d.OutOfScope = []string{placeholder("outOfScope", "capabilities deliberately excluded")}
+	}
+
+	d.UserStories = []UserStory{{
+		ID:                 "US-1",
+		PersonaID:          primary.ID,
+		Title:              placeholder("userStories", "first user story"),
+		AsA:                primary.Role,
+		IWant:              todo("desired capability"),
+		SoThat:             todo("benefit"),
+		AcceptanceCriteria: []AcceptanceCriterion{},
+		Priority:           PriorityHigh,
+		PhaseID:            "phase-1",
+	}}
+	if d.UserStories[0].AsA == "" {
+		d.UserStories[0].AsA = todo("persona role")
+	}
+
+	cl := m.CompetitiveLandscape
+	if len(cl.Competitors) > 0 || len(cl.Differentiators) > 0 || len(pos.Differentiators) > 0 {
+		d.Market = &MarketDefinition{Differentiation: dedupeStrings(cl.Differentiators, pos.Differentiators)}
+		for _, c := range cl.Competitors {
+			d.Market.Alternatives = append(d.Market.Alternatives, Alternative{
+				ID:          c.ID,
+				Name:        c.Name,
+				Type:        AlternativeCompetitor,
+				Description: c.Description,
+				Strengths:   c.Strengths,
+				Weaknesses:  c.Weaknesses,
+			})
+			link(c.ID, ProvenanceAlternative, c.ID)
+		}
+	}
+
+	for _, r := range m.Risks {
+		d.Risks = append(d.Risks, Risk{
+			ID:          r.ID,
+			Description: r.Description,
+			Probability: RiskProbability(strings.ToLower(r.Probability)),
+			Impact:      RiskImpact(strings.ToLower(r.Impact)),
+			Mitigation:  r.Mitigation,
+			Status:      RiskStatusOpen,
+			Category:    r.Category,
+			Tags:        r.Tags,
+		})
+		link(r.ID, ProvenanceRisk, r.ID)
+	}
+
+	if len(m.Assumptions) > 0 {
+		d.Assumptions = &AssumptionsConstraints{Constraints: []Constraint{}}
+		for _, a := range m.Assumptions {
+			d.Assumptions.Assumptions = append(d.Assumptions.Assumptions, Assumption{
+				ID: a.ID, Description: a.Description, Rationale: a.Rationale, Risk: a.Risk, Validated: a.Validated,
+			})
+			link(a.ID, ProvenanceAssumption, a.ID)
+		}
+	}
+
+	return d, todos
+}
+
+// deriveID derives a PRD ID from an MRD ID, e.g. "MRD-2026-001" becomes
+// "PRD-2026-001" and "mrd-agents" becomes "prd-agents".
+func deriveID(mrdID string) string {
+	switch {
+	case mrdID == "":
+		return "PRD-001"
+	case strings.HasPrefix(mrdID, "MRD"):
+		return "PRD" + mrdID[3:]
+	case strings.HasPrefix(mrdID, "mrd"):
+		return "prd" + mrdID[3:]
+	}
+	return mrdID + "-PRD"
+}
+
+// deriveTitle replaces a trailing "Market Requirements" in an MRD title, e.g.
+// "Payments Market Requirements" becomes "Payments Product Requirements".
+func deriveTitle(mrdTitle string) string {
+	if mrdTitle == "" {
+		return ""
+	}
+	for _, suffix := range []string{"Market Requirements Document", "Market Requirements", "MRD"} {
+		if t, ok := strings.CutSuffix(mrdTitle, suffix); ok {
+			mrdTitle = strings.TrimSpace(t)
+			break
+		}
+	}
+	return mrdTitle + " Product Requirements"
+}
+
+// deriveRequirementNotes keeps the market context of a requirement that has
+// no PRD field of its own.
+func deriveRequirementNotes(mr mrd.MarketRequirement) string {
+	var parts []string
+	if len(mr.Personas) > 0 {
+		parts = append(parts, "Personas: "+strings.Join(mr.Personas, ", ")+".")
+	}
+	if len(mr.Segments) > 0 {
+		parts = append(parts, "Segments: "+strings.Join(mr.Segments, ", ")+".")
+	}
+	if mr.Source != "" {
+		parts = append(parts, "Source: "+mr.Source+".")
+	}
+	if mr.Validation != "" {
+		parts = append(parts, "Validation: "+mr.Validation+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+func dedupeStrings(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, list := range lists {
+		for _, s := range list {
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
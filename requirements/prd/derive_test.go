@@ -0,0 +1,157 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+)
+
+func TestDeriveFromMRD(t *testing.T) {
+	m := &mrd.Document{
+		Metadata: mrd.Metadata{ID: "MRD-2026-004", Title: "Payments Market Requirements", Version: "1.2.0"},
+		ExecutiveSummary: mrd.ExecutiveSummary{
+			MarketOpportunity: "SMBs lose 30% of carts at checkout",
+			ProposedOffering:  "One-click checkout",
+		},
+		TargetMarket: mrd.TargetMarket{BuyerPersonas: []mrd.BuyerPersona{
+			{ID: "bp-cfo", Name: "Finance Fiona", Title: "CFO", BuyingCriteria: []string{"TCO"}},
+			{ID: "bp-dev", Name: "Developer Dan", Title: "Engineer"},
+		}},
+		CompetitiveLandscape: mrd.CompetitiveLandscape{
+			Competitors:     []mrd.Competitor{{ID: "comp-stripe", Name: "Stripe", Strengths: []string{"APIs"}}},
+			Differentiators: []string{"Lower fees"},
+		},
+		MarketRequirements: []mrd.MarketRequirement{
+			{ID: "MR-1", Title: "Saved cards", Priority: mrd.PriorityMust, Personas: []string{"bp-cfo"}, Source: "Interviews"},
+			{ID: "MR-2", Title: "Crypto payments", Priority: mrd.PriorityWont},
+			{ID: "MR-3", Title: "Refund API", Priority: mrd.PriorityShould},
+		},
+		Positioning: mrd.Positioning{
+			Statement:       "For SMBs who lose sales at checkout",
+			Tagline:         "Checkout in one click",
+			KeyBenefits:     []string{"Higher conversion"},
+			Differentiators: []string{"Lower fees", "No setup"},
+		},
+		SuccessMetrics: []mrd.SuccessMetric{
+			{ID: "SM-1", Name: "Conversion", Metric: "checkout conversion", Target: "+10%"},
+			{ID: "SM-2", Name: "Merchants"},
+		},
+		Risks:       []mrd.Risk{{ID: "R-1", Description: "Price war", Probability: "High", Impact: "Medium"}},
+		Assumptions: []mrd.Assumption{{ID: "A-1", Description: "SMBs trust new providers"}},
+	}
+
+	d, todos := DeriveFromMRD(m)
+
+	if d.Metadata.ID != "PRD-2026-004" || d.Metadata.Title != "Payments Product Requirements" || d.Metadata.Status != StatusDraft {
+		t.Errorf("metadata = %+v", d.Metadata)
+	}
+	if d.ExecutiveSummary.ProblemStatement != "SMBs lose 30% of carts at checkout" || len(d.ExecutiveSummary.ExpectedOutcomes) != 1 {
+		t.Errorf("executive summary = %+v", d.ExecutiveSummary)
+	}
+	if len(d.Personas) != 2 || !d.Personas[0].IsPrimary || d.Personas[1].IsPrimary || d.Personas[0].Role != "CFO" || d.Personas[0].Motivations[0] != "TCO" {
+		t.Errorf("personas = %+v", d.Personas)
+	}
+
+	okrs := d.Objectives.OKRs
+	if len(okrs) != 1 || okrs[0].Objective.Title != "Checkout in one click" || len(okrs[0].KeyResults) != 2 {
+		t.Fatalf("okrs = %+v", okrs)
+	}
+	if !strings.HasPrefix(okrs[0].KeyResults[1].Target, TODOPrefix) {
+		t.Errorf("missing target should be a placeholder: %+v", okrs[0].KeyResults[1])
+	}
+
+	fr := d.Requirements.Functional
+	if len(fr) != 2 || fr[0].Priority != MoSCoWMust || fr[1].Priority != MoSCoWShould || fr[0].PhaseID != "phase-1" {
+		t.Fatalf("functional = %+v", fr)
+	}
+	if fr[0].Notes != "Personas: bp-cfo. Source: Interviews." {
+		t.Errorf("notes = %q", fr[0].Notes)
+	}
+	if len(d.OutOfScope) != 1 || d.OutOfScope[0] != "MR-2: Crypto payments" {
+		t.Errorf("out of scope = %v", d.OutOfScope)
+	}
+
+	if d.Market == nil || len(d.Market.Alternatives) != 1 || strings.Join(d.Market.Differentiation, ",") != "Lower fees,No setup" {
+		t.Errorf("market = %+v", d.Market)
+	}
+	if len(d.Risks) != 1 || d.Risks[0].Probability != RiskProbabilityHigh || d.Risks[0].Impact != RiskImpactMedium {
+		t.Errorf("risks = %+v", d.Risks)
+	}
+	if d.Assumptions == nil || len(d.Assumptions.Assumptions) != 1 {
+		t.Errorf("assumptions = %+v", d.Assumptions)
+	}
+
+	p := d.Provenance
+	if p.Source.Type != "mrd" || p.Source.ID != "MRD-2026-004" || p.Source.Version != "1.2.0" {
+		t.Errorf("source = %+v", p.Source)
+	}
+	for item, want := range map[string]string{
+		"bp-cfo":        "bp-cfo",
+		"FR-1":          "MR-1",
+		"FR-2":          "MR-3",
+		"outOfScope[0]": "MR-2",
+		"KR-2":          "SM-2",
+		"comp-stripe":   "comp-stripe",
+		"R-1":           "R-1",
+	} {
+		if got := p.SourcesFor(item); len(got) != 1 || got[0] != want {
+			t.Errorf("SourcesFor(%q) = %v, want [%s]", item, got, want)
+		}
+	}
+	if got := p.SourcesFor("OBJ-1"); strings.Join(got, ",") != "SM-1,SM-2" {
+		t.Errorf("SourcesFor(OBJ-1) = %v", got)
+	}
+
+	if result := Validate(d); !result.Valid {
+		t.Errorf("derived PRD should validate: %+v", result.Errors)
+	}
+	for _, want := range []string{"metadata.authors", "roadmap.phases", "userStories", "objectives.okrs[0].keyResults[1].target"} {
+		found := false
+		for _, path := range todos {
+			found = found || path == want
+		}
+		if !found {
+			t.Errorf("todos missing %q: %v", want, todos)
+		}
+	}
+}
+
+func TestDeriveFromMRDMinimal(t *testing.T) {
+	d, todos := DeriveFromMRD(&mrd.Document{Metadata: mrd.Metadata{ID: "agents"}})
+	if d.Metadata.ID != "agents-PRD" {
+		t.Errorf("ID = %q", d.Metadata.ID)
+	}
+	if len(d.Personas) != 1 || !strings.HasPrefix(d.Personas[0].Name, TODOPrefix) || d.UserStories[0].PersonaID != d.Personas[0].ID {
+		t.Errorf("personas = %+v, stories = %+v", d.Personas, d.UserStories)
+	}
+	if !strings.HasPrefix(d.OutOfScope[0], TODOPrefix) || !strings.HasPrefix(d.Objectives.OKRs[0].Objective.Title, TODOPrefix) {
+		t.Errorf("expected placeholders: %+v", d)
+	}
+	if d.Market != nil || d.Assumptions != nil || len(d.Provenance.Links) != 0 {
+		t.Error("optional sections should be omitted when the MRD has nothing for them")
+	}
+	if len(todos) < 8 {
+		t.Errorf("todos = %v", todos)
+	}
+}
+
+func TestDeriveID(t *testing.T) {
+	for in, want := range map[string]string{
+		"MRD-2026-001": "PRD-2026-001",
+		"mrd-agents":   "prd-agents",
+		"agents":       "agents-PRD",
+		"":             "PRD-001",
+	} {
+		if got := deriveID(in); got != want {
+			t.Errorf("deriveID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestProvenanceSourcesForNil(t *testing.T) {
+	var p *Provenance
+	if p.SourcesFor("FR-1") != nil {
+		t.Error("nil provenance should have no sources")
+	}
+}
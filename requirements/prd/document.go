@@ -2,8 +2,10 @@
 package prd
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/grokify/structured-plan/assets"
 	"github.com/grokify/structured-plan/common"
 	"github.com/grokify/structured-plan/goals"
 	"github.com/grokify/structured-plan/goals/okr"
@@ -15,6 +17,12 @@ type Person = common.Person
 // Approver is an alias for common.Approver for backwards compatibility.
 type Approver = common.Approver
 
+// Review is an alias for common.Review.
+type Review = common.Review
+
+// ExternalRefs is an alias for common.ExternalRefs.
+type ExternalRefs = common.ExternalRefs
+
 // OKR type aliases from goals/okr for backward compatibility.
 // These allow existing PRD code to continue using prd.OKR, prd.Objective, etc.
 type (
@@ -93,6 +101,9 @@ type (
 
 	// NonGoal represents an explicit out-of-scope item.
 	NonGoal = common.NonGoal
+
+	// Comment is a review comment on part of the document.
+	Comment = common.Comment
 )
 
 // Goals type aliases from goals package for backward compatibility.
@@ -139,6 +150,10 @@ type Document struct {
 	Risks            []Risk                  `json:"risks,omitempty"`
 	Glossary         []GlossaryTerm          `json:"glossary,omitempty"`
 
+	// FAQs are questions answered by the authors for the PR/FAQ and
+	// 6-pager, shown ahead of the ones synthesized from the rest of the PRD.
+	FAQs []FAQEntry `json:"faqs,omitempty"`
+
 	// Custom sections for project-specific needs
 	CustomSections []CustomSection `json:"customSections,omitempty"`
 
@@ -170,6 +185,10 @@ type Document struct {
 	// Goals contains alignment with strategic goals (V2MOM, OKR).
 	Goals *GoalsAlignment `json:"goals,omitempty"`
 
+	// Provenance links derived items back to the upstream document, such as
+	// the MRD this PRD was started from.
+	Provenance *Provenance `json:"provenance,omitempty"`
+
 	// CurrentState documents the existing state before the proposed solution.
 	CurrentState *CurrentState `json:"currentState,omitempty"`
 
@@ -179,6 +198,13 @@ type Document struct {
 
 	// Appendices contains supplementary information and domain-specific data.
 	Appendices []Appendix `json:"appendices,omitempty"`
+
+	// Resourcing is the headcount plan: roles allocated to roadmap phases.
+	Resourcing []Allocation `json:"resourcing,omitempty"`
+
+	// Comments holds review threads on parts of the PRD. See common.Comment
+	// for how paths and replies are recorded.
+	Comments []Comment `json:"comments,omitempty"`
 }
 
 // Status constants re-exported from common for backward compatibility.
@@ -270,6 +296,58 @@ type Metadata struct {
 
 	// SemanticVersioning indicates the Version field follows Semantic Versioning (semver.org).
 	SemanticVersioning bool `json:"semanticVersioning,omitempty"`
+
+	// Team is the team that owns the document.
+	Team string `json:"team,omitempty"`
+
+	// Reviews tracks requested reviews and their state. While Status is
+	// approved, every role in RequiredReviewRoles must have signed off.
+	Reviews             []Review `json:"reviews,omitempty"`
+	RequiredReviewRoles []string `json:"requiredReviewRoles,omitempty"`
+
+	// ReviewedAt is when the document was last approved. It is required
+	// while Status is approved.
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+
+	// Supersedes lists the IDs of documents this one replaces. SupersededBy
+	// is the ID of the document that replaces this one; a deprecated
+	// document must name it.
+	Supersedes   []string `json:"supersedes,omitempty"`
+	SupersededBy string   `json:"supersededBy,omitempty"`
+
+	// ExternalRefs links this document to other systems, e.g. its
+	// Confluence page. ExternalRefURLs maps a system to a link template
+	// containing "{id}", used to link refs on the document and its
+	// entities that are IDs rather than URLs.
+	ExternalRefs    ExternalRefs      `json:"externalRefs,omitempty"`
+	ExternalRefURLs map[string]string `json:"externalRefUrls,omitempty"`
+
+	// Snippets records the content expanded from the workspace snippet
+	// library, for detecting local edits to it.
+	Snippets []common.SnippetRef `json:"snippets,omitempty"`
+
+	// Profile and Flags describe the kind of product the document is for,
+	// such as an enterprise product with a UI. Conditions maps a top-level
+	// section or an item ID to the condition under which it applies, such
+	// as "profile == enterprise" or "has_ui"; sections and items whose
+	// condition is false are left out when the document is rendered or
+	// checked.
+	Profile    string            `json:"profile,omitempty"`
+	Flags      map[string]bool   `json:"flags,omitempty"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// Lifecycle returns the fields checked by the document lifecycle policy.
+func (m Metadata) Lifecycle() common.Lifecycle {
+	return common.Lifecycle{
+		Status:              m.Status,
+		Reviewers:           m.Reviewers,
+		Approvers:           m.Approvers,
+		Reviews:             m.Reviews,
+		RequiredReviewRoles: m.RequiredReviewRoles,
+		ReviewedAt:          m.ReviewedAt,
+		SupersededBy:        m.SupersededBy,
+	}
 }
 
 // ExecutiveSummary provides high-level product overview.
@@ -308,3 +386,35 @@ func (d *Document) GetProductGoals() *Goals {
 func (d *Document) HasProductGoals() bool {
 	return d.ProductGoals != nil || len(d.Objectives.OKRs) > 0
 }
+
+// AssetRefs returns the document fields that may reference local files such
+// as diagrams, wireframes, and persona images, for bundling with generated
+// output.
+func (d *Document) AssetRefs() []assets.Ref {
+	var refs []assets.Ref
+	add := func(field string, value *string) {
+		if *value != "" {
+			refs = append(refs, assets.Ref{Field: field, Value: value})
+		}
+	}
+	for i := range d.Personas {
+		add(fmt.Sprintf("personas[%d].imageUrl", i), &d.Personas[i].ImageURL)
+	}
+	if d.TechArchitecture != nil {
+		add("technicalArchitecture.systemDiagram", &d.TechArchitecture.SystemDiagram)
+	}
+	if d.UXRequirements != nil {
+		for i := range d.UXRequirements.Wireframes {
+			add(fmt.Sprintf("uxRequirements.wireframes[%d].url", i), &d.UXRequirements.Wireframes[i].URL)
+		}
+		for i := range d.UXRequirements.InteractionFlows {
+			add(fmt.Sprintf("uxRequirements.interactionFlows[%d].diagramUrl", i), &d.UXRequirements.InteractionFlows[i].DiagramURL)
+		}
+	}
+	if d.CurrentState != nil {
+		for i := range d.CurrentState.Diagrams {
+			add(fmt.Sprintf("currentState.diagrams[%d].url", i), &d.CurrentState.Diagrams[i].URL)
+		}
+	}
+	return refs
+}
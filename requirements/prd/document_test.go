@@ -534,6 +534,96 @@ func TestMarkdownGenerationWithSwimlaneTable(t *testing.T) {
 	}
 }
 
+func TestMarkdownGenerationWithRoadmapDiagrams(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	doc := &Document{
+		Metadata: Metadata{ID: "prd-diagrams", Title: "Diagrams"},
+		Roadmap: Roadmap{Phases: []Phase{
+			{ID: "phase-1", Name: "MVP", StartDate: &start, EndDate: &end},
+			{ID: "phase-2", Name: "Beta", Dependencies: []string{"phase-1"}},
+		}},
+		Assumptions: &AssumptionsConstraints{Dependencies: []Dependency{
+			{ID: "DEP-1", Name: "Payments API", NeededBy: "phase-2"},
+		}},
+	}
+
+	md := doc.ToMarkdown(MarkdownOptions{IncludeRoadmapDiagrams: true})
+	for _, want := range []string{
+		"### 7.1 Timeline",
+		"```mermaid\ngantt\n",
+		"    MVP :2026-01-01, 2026-03-31\n",
+		"### 7.2 Dependency Graph",
+		"```mermaid\nflowchart LR\n",
+		"    phase_phase_1 --> phase_phase_2\n",
+		`    dep_DEP_1[/"Payments API"/] -.-> phase_phase_2`,
+		"### 7.3 Phase Details",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q", want)
+		}
+	}
+
+	if md := doc.ToMarkdown(MarkdownOptions{}); strings.Contains(md, "```mermaid") || strings.Contains(md, "Phase Details") {
+		t.Error("expected no diagrams or overview subsections by default")
+	}
+}
+
+func TestMarkdownGenerationWithCodeAndMath(t *testing.T) {
+	doc := &Document{
+		Metadata: Metadata{ID: "prd-math", Title: "Math"},
+		Requirements: Requirements{Functional: []FunctionalRequirement{
+			{ID: "FR-001", Title: "Login", Description: "In | out", Category: "Auth", Priority: MoSCoWMust},
+			{ID: "FR-002", Title: "Scoring", Description: "Rank results by\n$$\ns = \\frac{a}{b}\n$$\nusing `score()`.",
+				Category: "Auth", Priority: MoSCoWShould, PhaseID: "phase-1"},
+		}},
+	}
+
+	md := doc.ToMarkdown(MarkdownOptions{DescriptionMaxLen: 10})
+	for _, want := range []string{
+		"| FR-001 | Login | In \\| out | must |",
+		"#### FR-002: Scoring",
+		"**Priority:** should | **Phase:** phase-1\n\nRank results by\n$$\ns = \\frac{a}{b}\n$$\nusing `score()`.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "| FR-002 |") {
+		t.Error("requirement with a math block should not be a table row")
+	}
+}
+
+func TestMarkdownGenerationGlossaryLinks(t *testing.T) {
+	doc := &Document{
+		Metadata:         Metadata{ID: "prd-glossary", Title: "Glossary", Tags: []string{"slo", "billing"}},
+		ExecutiveSummary: ExecutiveSummary{ProblemStatement: "Every tenant needs an SLO."},
+		Glossary: []GlossaryTerm{
+			{Term: "Tenant", Definition: "A customer account"},
+			{Term: "Service Level Objective", Acronym: "SLO", Definition: "A reliability target"},
+		},
+	}
+
+	md := doc.ToMarkdown(MarkdownOptions{})
+	for _, want := range []string{
+		"| **Tags** | slo, billing |",
+		"Every [tenant](#term-tenant) needs an [SLO](#term-service-level-objective).",
+		`| <a id="term-tenant"></a>**Tenant** | A customer account |`,
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q", want)
+		}
+	}
+	if broken := BrokenAnchors(md); len(broken) > 0 {
+		t.Errorf("BrokenAnchors = %v", broken)
+	}
+
+	off := false
+	if md := doc.ToMarkdown(MarkdownOptions{LinkGlossary: &off}); strings.Contains(md, "](#term-") {
+		t.Error("expected no glossary links when disabled")
+	}
+}
+
 // TestValidation tests document validation logic.
 func TestValidation(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,288 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// ListSeparator separates the items of list fields while they are edited.
+const ListSeparator = ";"
+
+// FieldKind is how a field's value is edited.
+type FieldKind int
+
+const (
+	// FieldText is a single line of text.
+	FieldText FieldKind = iota
+	// FieldList is a list of strings, edited as one line with the items
+	// separated by ListSeparator.
+	FieldList
+	// FieldCriteria is a list of acceptance criteria, edited as their
+	// descriptions separated by ListSeparator.
+	FieldCriteria
+)
+
+// Field is an editable field of an entity.
+type Field struct {
+	Label string
+
+	// Path is the JSON pointer of the field relative to its entity, e.g.
+	// "/iWant".
+	Path string
+
+	Kind FieldKind
+
+	// Choices are the allowed values of an enumerated field. Selecting the
+	// field steps through them instead of editing text.
+	Choices []string
+
+	// Required fields cannot be cleared.
+	Required bool
+}
+
+// Section is a part of the PRD the editor can change: a list of entities
+// such as the user stories, or a single object such as the summary.
+type Section struct {
+	Name string
+
+	// Pointer is the JSON pointer of the section's array. Single sections
+	// have their field paths rooted at the document instead.
+	Pointer string
+
+	// IssuePath is the validation field path of the section's array, e.g.
+	// "user_stories".
+	IssuePath string
+
+	// IDPrefix starts the IDs of added entities, e.g. "us-".
+	IDPrefix string
+
+	Single bool
+	Fields []Field
+
+	expanded bool
+}
+
+// DefaultSections returns the sections the editor offers: the document
+// summary, personas, user stories, and functional and non-functional
+// requirements.
+func DefaultSections() []*Section {
+	moscow := []string{string(prd.MoSCoWMust), string(prd.MoSCoWShould), string(prd.MoSCoWCould), string(prd.MoSCoWWont)}
+	return []*Section{
+		{Name: "Summary", Single: true, Fields: []Field{
+			{Label: "Title", Path: "/metadata/title", Required: true},
+			{Label: "Version", Path: "/metadata/version"},
+			{Label: "Problem statement", Path: "/executiveSummary/problemStatement"},
+			{Label: "Proposed solution", Path: "/executiveSummary/proposedSolution"},
+			{Label: "Expected outcomes", Path: "/executiveSummary/expectedOutcomes", Kind: FieldList},
+			{Label: "Target audience", Path: "/executiveSummary/targetAudience"},
+		}},
+		{Name: "Personas", Pointer: "/personas", IssuePath: "personas", IDPrefix: "persona-", Fields: []Field{
+			{Label: "ID", Path: "/id", Required: true},
+			{Label: "Name", Path: "/name", Required: true},
+			{Label: "Role", Path: "/role"},
+			{Label: "Description", Path: "/description"},
+			{Label: "Goals", Path: "/goals", Kind: FieldList},
+			{Label: "Pain points", Path: "/painPoints", Kind: FieldList},
+		}},
+		{Name: "User Stories", Pointer: "/userStories", IssuePath: "user_stories", IDPrefix: "us-", Fields: []Field{
+			{Label: "ID", Path: "/id", Required: true},
+			{Label: "Title", Path: "/title", Required: true},
+			{Label: "Persona ID", Path: "/personaId"},
+			{Label: "As a", Path: "/asA"},
+			{Label: "I want", Path: "/iWant"},
+			{Label: "So that", Path: "/soThat"},
+			{Label: "Priority", Path: "/priority", Choices: []string{
+				string(common.PriorityCritical), string(common.PriorityHigh), string(common.PriorityMedium), string(common.PriorityLow),
+			}},
+			{Label: "Phase ID", Path: "/phaseId"},
+			{Label: "Acceptance criteria", Path: "/acceptanceCriteria", Kind: FieldCriteria},
+		}},
+		{Name: "Functional Requirements", Pointer: "/requirements/functional", IssuePath: "requirements.functional", IDPrefix: "fr-", Fields: []Field{
+			{Label: "ID", Path: "/id", Required: true},
+			{Label: "Title", Path: "/title", Required: true},
+			{Label: "Description", Path: "/description"},
+			{Label: "Category", Path: "/category"},
+			{Label: "Priority", Path: "/priority", Choices: moscow},
+			{Label: "User story IDs", Path: "/userStoryIds", Kind: FieldList},
+			{Label: "Phase ID", Path: "/phaseId"},
+			{Label: "Acceptance criteria", Path: "/acceptanceCriteria", Kind: FieldCriteria},
+			{Label: "Tags", Path: "/tags", Kind: FieldList},
+		}},
+		{Name: "Non-Functional Requirements", Pointer: "/requirements/nonFunctional", IssuePath: "requirements.non_functional", IDPrefix: "nfr-", Fields: []Field{
+			{Label: "ID", Path: "/id", Required: true},
+			{Label: "Category", Path: "/category", Choices: []string{
+				string(prd.NFRPerformance), string(prd.NFRScalability), string(prd.NFRReliability), string(prd.NFRAvailability),
+				string(prd.NFRSecurity), string(prd.NFRMultiTenancy), string(prd.NFRObservability), string(prd.NFRMaintainability),
+				string(prd.NFRUsability), string(prd.NFRCompatibility), string(prd.NFRCompliance), string(prd.NFRDisasterRecovery),
+				string(prd.NFRCostEfficiency), string(prd.NFRPortability), string(prd.NFRTestability), string(prd.NFRExtensibility),
+				string(prd.NFRInteroperability), string(prd.NFRLocalization), string(prd.NFRAccessibility),
+			}},
+			{Label: "Title", Path: "/title", Required: true},
+			{Label: "Description", Path: "/description"},
+			{Label: "Metric", Path: "/metric"},
+			{Label: "Target", Path: "/target"},
+			{Label: "Priority", Path: "/priority", Choices: moscow},
+			{Label: "Phase ID", Path: "/phaseId"},
+		}},
+	}
+}
+
+// format renders a field's JSON value as the text that is edited.
+func (f *Field) format(v any) string {
+	switch f.Kind {
+	case FieldList, FieldCriteria:
+		items, _ := v.([]any)
+		var parts []string
+		for _, it := range items {
+			if f.Kind == FieldCriteria {
+				c, _ := it.(map[string]any)
+				it = c["description"]
+			}
+			if s, ok := it.(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ListSeparator+" ")
+	}
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parse converts edited text to the field's JSON value. old is the current
+// value; acceptance criteria whose description is unchanged keep their ID
+// and Given/When/Then steps.
+func (f *Field) parse(text string, old any) (any, error) {
+	text = strings.TrimSpace(text)
+	if f.Required && text == "" {
+		return nil, fmt.Errorf("%s is required", f.Label)
+	}
+	if f.Kind == FieldText {
+		return text, nil
+	}
+
+	items := []any{}
+	var kept []any
+	var ids []string
+	if f.Kind == FieldCriteria {
+		kept, _ = old.([]any)
+		for _, c := range kept {
+			if c, ok := c.(map[string]any); ok {
+				if id, ok := c["id"].(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	for _, part := range strings.Split(text, ListSeparator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if f.Kind == FieldList {
+			items = append(items, part)
+			continue
+		}
+		criterion := criterionWithDescription(kept, part)
+		if criterion == nil {
+			id := nextID(ids, "ac-1")
+			ids = append(ids, id)
+			criterion = map[string]any{"id": id, "description": part}
+		}
+		items = append(items, criterion)
+	}
+	return items, nil
+}
+
+func criterionWithDescription(criteria []any, description string) any {
+	for _, c := range criteria {
+		if c, ok := c.(map[string]any); ok && c["description"] == description {
+			return c
+		}
+	}
+	return nil
+}
+
+// next returns the choice after value, wrapping around.
+func (f *Field) next(value string) string {
+	for i, c := range f.Choices {
+		if c == value {
+			return f.Choices[(i+1)%len(f.Choices)]
+		}
+	}
+	return f.Choices[0]
+}
+
+// nextID returns the ID after the highest numbered ID in ids, keeping its
+// prefix and zero padding: "FR-009" follows "FR-008". def is returned when
+// no ID ends in a number.
+func nextID(ids []string, def string) string {
+	best, bestN := "", -1
+	for _, id := range ids {
+		digits := len(id) - len(strings.TrimRightFunc(id, unicode.IsDigit))
+		if digits == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(id[len(id)-digits:])
+		if err == nil && n > bestN {
+			best, bestN = id, n
+		}
+	}
+	if best == "" {
+		return def
+	}
+	digits := len(best) - len(strings.TrimRightFunc(best, unicode.IsDigit))
+	prefix := best[:len(best)-digits]
+	return fmt.Sprintf("%s%0*d", prefix, digits, bestN+1)
+}
+
+// issuePath converts a JSON pointer relative to base into a validation
+// field path: "/personaId" under "user_stories[2]" becomes
+// "user_stories[2].persona_id".
+func issuePath(base, pointer string) string {
+	path := base
+	for _, t := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if t == "" {
+			continue
+		}
+		if path != "" {
+			path += "."
+		}
+		path += snakeCase(t)
+	}
+	return path
+}
+
+func snakeCase(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// under reports whether the validation field path is path or one of its
+// children.
+func under(field, path string) bool {
+	if !strings.HasPrefix(field, path) {
+		return false
+	}
+	rest := field[len(path):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
@@ -0,0 +1,510 @@
+// Package editor provides an interactive terminal editor for PRDs: browse
+// the document's sections, add, edit, and delete personas, user stories,
+// and requirements through forms, and see validation issues next to the
+// fields they are about as you type.
+//
+// Like the review in the render/tui package, the Model follows the
+// model-update-view pattern: Update applies one key press and View renders
+// the whole screen, so both can be tested without a terminal. Edits are
+// applied to the document's JSON form as JSON Patch operations and decoded
+// back into the typed document, which is revalidated after every change.
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/termui"
+)
+
+// Issue is a validation error or warning.
+type Issue struct {
+	Field   string
+	Message string
+	Error   bool
+}
+
+// Model is the state of an editing session.
+type Model struct {
+	// Doc is the document being edited. It is replaced, not modified in
+	// place, by each change.
+	Doc      *prd.Document
+	Sections []*Section
+
+	data   any // Doc's JSON form
+	issues []Issue
+
+	list termui.List
+
+	// The entity whose form is open, or nil in the section list.
+	form      *Section
+	index     int
+	field     int
+	formTop   int
+	editing   bool
+	input     []rune
+	inputPos  int
+	status    string
+	dirty     bool
+	quitArmed bool
+	delArmed  bool
+}
+
+// New starts an editing session for doc.
+func New(doc *prd.Document) (*Model, error) {
+	m := &Model{Sections: DefaultSections()}
+	if err := m.load(doc); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// load makes doc the current document and revalidates it.
+func (m *Model) load(doc *prd.Document) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshaling document: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("decoding document: %w", err)
+	}
+	m.Doc, m.data = doc, v
+
+	r := prd.Validate(doc)
+	m.issues = m.issues[:0]
+	for _, e := range r.Errors {
+		m.issues = append(m.issues, Issue{Field: e.Field, Message: e.Message, Error: true})
+	}
+	for _, w := range r.Warnings {
+		m.issues = append(m.issues, Issue{Field: w.Field, Message: w.Message})
+	}
+	return nil
+}
+
+// apply applies patch to the document. The patched JSON is decoded into a
+// new document so that fields of removed or moved entities do not linger.
+func (m *Model) apply(patch jsonpatch.Patch) error {
+	patched, err := patch.Apply(m.data)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(patched)
+	if err != nil {
+		return fmt.Errorf("marshaling patched document: %w", err)
+	}
+	var doc prd.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decoding patched document: %w", err)
+	}
+	if err := m.load(&doc); err != nil {
+		return err
+	}
+	m.dirty = true
+	return nil
+}
+
+// Dirty reports whether the document changed since the last save.
+func (m *Model) Dirty() bool {
+	return m.dirty
+}
+
+// Saved records that the document was saved, with a status message.
+func (m *Model) Saved(message string) {
+	m.dirty = false
+	m.status = message
+}
+
+// SetStatus sets the message shown at the bottom of the screen.
+func (m *Model) SetStatus(message string) {
+	m.status = message
+}
+
+// Issues returns the document's validation errors, then its warnings.
+func (m *Model) Issues() []Issue {
+	return m.issues
+}
+
+// issuesUnder returns the issues about path or its children.
+func (m *Model) issuesUnder(path string) []Issue {
+	var issues []Issue
+	for _, is := range m.issues {
+		if under(is.Field, path) {
+			issues = append(issues, is)
+		}
+	}
+	return issues
+}
+
+// sectionIssues returns the issues about a section: about its array, or
+// for a single section about its fields.
+func (m *Model) sectionIssues(s *Section) []Issue {
+	if !s.Single {
+		return m.issuesUnder(s.IssuePath)
+	}
+	var issues []Issue
+	for i := range s.Fields {
+		issues = append(issues, m.issuesUnder(issuePath("", s.Fields[i].Path))...)
+	}
+	return issues
+}
+
+// entities returns the entities of a list section.
+func (m *Model) entities(s *Section) []any {
+	v, err := jsonpatch.Get(m.data, s.Pointer)
+	if err != nil {
+		return nil
+	}
+	items, _ := v.([]any)
+	return items
+}
+
+// entityPointer returns the JSON pointer of an entity; fields of single
+// sections are rooted at the document.
+func (s *Section) entityPointer(index int) string {
+	if s.Single {
+		return ""
+	}
+	return s.Pointer + "/" + strconv.Itoa(index)
+}
+
+// entityIssuePath returns the validation field path of an entity.
+func (s *Section) entityIssuePath(index int) string {
+	if s.Single {
+		return ""
+	}
+	return fmt.Sprintf("%s[%d]", s.IssuePath, index)
+}
+
+// entityLabel describes an entity by its ID and title or name.
+func entityLabel(e any) string {
+	obj, _ := e.(map[string]any)
+	id, _ := obj["id"].(string)
+	name, _ := obj["title"].(string)
+	if name == "" {
+		name, _ = obj["name"].(string)
+	}
+	switch {
+	case id == "":
+		return name
+	case name == "":
+		return id
+	}
+	return id + "  " + name
+}
+
+// value returns the current JSON value of a field of the open form.
+func (m *Model) value(f *Field) any {
+	v, err := jsonpatch.Get(m.data, m.form.entityPointer(m.index)+f.Path)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// row is a line of the section list: a section, or one of its entities.
+type row struct {
+	section *Section
+	index   int // -1 on a section row
+}
+
+func (m *Model) rows() []row {
+	var rows []row
+	for _, s := range m.Sections {
+		rows = append(rows, row{section: s, index: -1})
+		if !s.expanded || s.Single {
+			continue
+		}
+		for i := range m.entities(s) {
+			rows = append(rows, row{section: s, index: i})
+		}
+	}
+	return rows
+}
+
+// Current returns the section and entity index under the cursor; index is
+// -1 on a section row.
+func (m *Model) Current() (*Section, int) {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return nil, -1
+	}
+	r := rows[max(0, min(m.list.Cursor, len(rows)-1))]
+	return r.section, r.index
+}
+
+// Update applies a key press. It returns termui.ActionSave when the user
+// asks to write Doc.
+func (m *Model) Update(k termui.Key) termui.Action {
+	if k.Type == termui.KeyCtrlC {
+		return termui.ActionQuit
+	}
+	if m.editing {
+		m.updateInput(k)
+		return termui.ActionNone
+	}
+
+	k = k.Navigation()
+	if !(k.Type == termui.KeyRune && k.Rune == 'q') {
+		m.quitArmed = false
+	}
+	if !(k.Type == termui.KeyRune && k.Rune == 'd') {
+		m.delArmed = false
+	}
+	m.status = ""
+
+	if k.Type == termui.KeyRune {
+		switch k.Rune {
+		case 'q':
+			if m.dirty && !m.quitArmed {
+				m.quitArmed = true
+				m.status = "Unsaved changes: press w to save, or q again to discard them"
+				return termui.ActionNone
+			}
+			return termui.ActionQuit
+		case 'w':
+			if !m.dirty {
+				m.status = "No changes to save"
+				return termui.ActionNone
+			}
+			return termui.ActionSave
+		case 'a':
+			m.add()
+			return termui.ActionNone
+		case 'd':
+			m.delete()
+			return termui.ActionNone
+		}
+	}
+
+	if m.form != nil {
+		m.updateForm(k)
+	} else {
+		m.updateList(k)
+	}
+	return termui.ActionNone
+}
+
+func (m *Model) updateList(k termui.Key) {
+	rows := m.rows()
+	switch k.Type {
+	case termui.KeyUp:
+		m.list.Cursor = max(0, m.list.Cursor-1)
+	case termui.KeyDown:
+		m.list.Cursor = min(len(rows)-1, m.list.Cursor+1)
+	case termui.KeyHome:
+		m.list.Cursor = 0
+	case termui.KeyEnd:
+		m.list.Cursor = len(rows) - 1
+	case termui.KeyEnter, termui.KeyRight:
+		s, i := m.Current()
+		switch {
+		case s == nil:
+		case s.Single || i >= 0:
+			m.open(s, max(i, 0), 0)
+		case k.Type == termui.KeyRight:
+			s.expanded = true
+		default:
+			s.expanded = !s.expanded
+		}
+	case termui.KeyLeft, termui.KeyBackspace, termui.KeyEsc:
+		if s, i := m.Current(); s != nil {
+			s.expanded = false
+			if i >= 0 {
+				m.moveTo(s, -1)
+			}
+		}
+	}
+}
+
+func (m *Model) updateForm(k termui.Key) {
+	switch k.Type {
+	case termui.KeyUp:
+		m.field = max(0, m.field-1)
+	case termui.KeyDown:
+		m.field = min(len(m.form.Fields)-1, m.field+1)
+	case termui.KeyHome:
+		m.field = 0
+	case termui.KeyEnd:
+		m.field = len(m.form.Fields) - 1
+	case termui.KeyEnter, termui.KeyRight:
+		f := &m.form.Fields[m.field]
+		if len(f.Choices) > 0 {
+			m.commit(f, f.next(f.format(m.value(f))))
+			return
+		}
+		m.editing = true
+		m.input = []rune(f.format(m.value(f)))
+		m.inputPos = len(m.input)
+	case termui.KeyLeft, termui.KeyBackspace, termui.KeyEsc:
+		m.close()
+	}
+}
+
+// updateInput applies a key press to the field being edited.
+func (m *Model) updateInput(k termui.Key) {
+	m.status = ""
+	switch k.Type {
+	case termui.KeyRune:
+		m.input = append(m.input[:m.inputPos], append([]rune{k.Rune}, m.input[m.inputPos:]...)...)
+		m.inputPos++
+	case termui.KeyBackspace:
+		if m.inputPos > 0 {
+			m.input = append(m.input[:m.inputPos-1], m.input[m.inputPos:]...)
+			m.inputPos--
+		}
+	case termui.KeyDelete:
+		if m.inputPos < len(m.input) {
+			m.input = append(m.input[:m.inputPos], m.input[m.inputPos+1:]...)
+		}
+	case termui.KeyLeft:
+		m.inputPos = max(0, m.inputPos-1)
+	case termui.KeyRight:
+		m.inputPos = min(len(m.input), m.inputPos+1)
+	case termui.KeyHome:
+		m.inputPos = 0
+	case termui.KeyEnd:
+		m.inputPos = len(m.input)
+	case termui.KeyCtrlU:
+		m.input, m.inputPos = nil, 0
+	case termui.KeyEsc:
+		m.editing = false
+	case termui.KeyEnter:
+		f := &m.form.Fields[m.field]
+		if m.commit(f, string(m.input)) {
+			m.editing = false
+		}
+	}
+}
+
+// commit sets a field of the open form from text and reports whether it
+// was accepted. The field's validation issues, if any, are shown in the
+// status line.
+func (m *Model) commit(f *Field, text string) bool {
+	old := m.value(f)
+	v, err := f.parse(text, old)
+	if err != nil {
+		m.status = err.Error()
+		return false
+	}
+	if oldJSON, newJSON := mustJSON(old), mustJSON(v); oldJSON == newJSON ||
+		(old == nil && (newJSON == `""` || newJSON == "[]")) {
+		return true
+	}
+	pointer := m.form.entityPointer(m.index) + f.Path
+	if err := m.apply(jsonpatch.Patch{jsonpatch.Add(pointer, v)}); err != nil {
+		m.status = "Update failed: " + err.Error()
+		return false
+	}
+	if issues := m.issuesUnder(issuePath(m.form.entityIssuePath(m.index), f.Path)); len(issues) > 0 {
+		m.status = f.Label + ": " + issues[0].Message
+	} else {
+		m.status = "Updated " + f.Label
+	}
+	return true
+}
+
+func mustJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// add appends a new entity with the next free ID to the current section
+// and opens its form.
+func (m *Model) add() {
+	s := m.form
+	if s == nil {
+		s, _ = m.Current()
+	}
+	if s == nil || s.Single {
+		m.status = "Select a list section to add to"
+		return
+	}
+	items := m.entities(s)
+	var ids []string
+	for _, e := range items {
+		if obj, ok := e.(map[string]any); ok {
+			if id, ok := obj["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	id := nextID(ids, s.IDPrefix+"1")
+	entity := map[string]any{"id": id}
+
+	op := jsonpatch.Add(s.Pointer+"/-", entity)
+	if v, err := jsonpatch.Get(m.data, s.Pointer); err != nil || v == nil {
+		op = jsonpatch.Add(s.Pointer, []any{entity})
+	}
+	if err := m.apply(jsonpatch.Patch{op}); err != nil {
+		m.status = "Add failed: " + err.Error()
+		return
+	}
+	s.expanded = true
+	m.open(s, len(items), 1)
+	m.status = "Added " + id
+}
+
+// delete removes the entity under the cursor or in the open form. The
+// first press asks for confirmation.
+func (m *Model) delete() {
+	s, i := m.form, m.index
+	if s == nil {
+		s, i = m.Current()
+	}
+	if s == nil || s.Single || i < 0 {
+		m.status = "Select an entity to delete"
+		return
+	}
+	label := entityLabel(m.entities(s)[i])
+	if !m.delArmed {
+		m.delArmed = true
+		m.status = "Press d again to delete " + label
+		return
+	}
+	m.delArmed = false
+	if err := m.apply(jsonpatch.Patch{jsonpatch.Remove(s.entityPointer(i))}); err != nil {
+		m.status = "Delete failed: " + err.Error()
+		return
+	}
+	m.close()
+	if n := len(m.entities(s)); n > 0 {
+		m.moveTo(s, min(i, n-1))
+	} else {
+		m.moveTo(s, -1)
+	}
+	m.status = "Deleted " + label
+}
+
+func (m *Model) open(s *Section, index, field int) {
+	m.form, m.index, m.field, m.formTop = s, index, min(field, len(s.Fields)-1), 0
+	m.editing = false
+}
+
+// close returns from the open form to the section list, with the cursor on
+// the entity.
+func (m *Model) close() {
+	if m.form == nil {
+		return
+	}
+	s, i := m.form, m.index
+	m.form, m.editing = nil, false
+	if s.Single {
+		i = -1
+	}
+	m.moveTo(s, i)
+}
+
+// moveTo puts the cursor on the row of an entity, or of the section when
+// index is -1.
+func (m *Model) moveTo(s *Section, index int) {
+	for i, r := range m.rows() {
+		if r.section == s && r.index == index {
+			m.list.Cursor = i
+			return
+		}
+	}
+}
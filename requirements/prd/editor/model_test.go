@@ -0,0 +1,225 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/termui"
+)
+
+func testDocument() *prd.Document {
+	return &prd.Document{
+		Metadata: prd.Metadata{ID: "PRD-1", Title: "Checkout", Status: common.StatusDraft},
+		Personas: []prd.Persona{{ID: "persona-1", Name: "Shopper"}},
+		UserStories: []prd.UserStory{
+			{ID: "US-001", Title: "Guest checkout", PersonaID: "persona-1", Priority: common.PriorityHigh},
+			{ID: "US-002", Title: "Saved cards", PersonaID: "persona-1", Epic: "Payments"},
+		},
+	}
+}
+
+func newModel(t *testing.T) *Model {
+	t.Helper()
+	m, err := New(testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func press(m *Model, keys ...termui.Key) termui.Action {
+	var a termui.Action
+	for _, k := range keys {
+		a = m.Update(k)
+	}
+	return a
+}
+
+func typeText(m *Model, s string) {
+	for _, r := range s {
+		m.Update(termui.RuneKey(r))
+	}
+}
+
+// openSection puts the cursor on the named section.
+func openSection(t *testing.T, m *Model, name string) *Section {
+	t.Helper()
+	for i, r := range m.rows() {
+		if r.index < 0 && r.section.Name == name {
+			m.list.Cursor = i
+			return r.section
+		}
+	}
+	t.Fatalf("no section %q", name)
+	return nil
+}
+
+// selectField moves the form cursor to the labeled field.
+func selectField(t *testing.T, m *Model, label string) {
+	t.Helper()
+	for i, f := range m.form.Fields {
+		if f.Label == label {
+			m.field = i
+			return
+		}
+	}
+	t.Fatalf("no field %q", label)
+}
+
+var (
+	enter = termui.Key{Type: termui.KeyEnter}
+	esc   = termui.Key{Type: termui.KeyEsc}
+)
+
+func TestEditField(t *testing.T) {
+	m := newModel(t)
+	openSection(t, m, "User Stories")
+	press(m, enter, termui.Key{Type: termui.KeyDown}, enter) // expand, first story, open
+	if m.form == nil || m.index != 0 {
+		t.Fatalf("form = %v, index = %d", m.form, m.index)
+	}
+
+	selectField(t, m, "Persona ID")
+	press(m, enter, termui.Key{Type: termui.KeyCtrlU})
+	typeText(m, "persona-9")
+	press(m, enter)
+	if m.editing || m.Doc.UserStories[0].PersonaID != "persona-9" || !m.Dirty() {
+		t.Fatalf("persona = %q, editing = %v", m.Doc.UserStories[0].PersonaID, m.editing)
+	}
+	if !strings.Contains(m.status, "undefined persona") {
+		t.Errorf("status = %q, want the validation issue", m.status)
+	}
+	if view := m.View(100, 30); !strings.Contains(view, "! Reference to undefined persona: persona-9") {
+		t.Errorf("issue not shown under the field:\n%s", view)
+	}
+
+	// Fields the editor does not show survive edits.
+	selectField(t, m, "Title")
+	press(m, enter, termui.Key{Type: termui.KeyHome})
+	typeText(m, "Fast ")
+	press(m, enter)
+	if got := m.Doc.UserStories[0].Title; got != "Fast Guest checkout" {
+		t.Errorf("title = %q", got)
+	}
+	if m.Doc.UserStories[1].Epic != "Payments" {
+		t.Error("epic of another story was lost")
+	}
+
+	// Required fields cannot be cleared; escape cancels.
+	press(m, enter, termui.Key{Type: termui.KeyCtrlU}, enter)
+	if !m.editing || m.status != "Title is required" {
+		t.Errorf("editing = %v, status = %q", m.editing, m.status)
+	}
+	press(m, esc)
+	if m.editing || m.Doc.UserStories[0].Title != "Fast Guest checkout" {
+		t.Errorf("cancel kept editing or changed the title")
+	}
+
+	// Choices cycle.
+	selectField(t, m, "Priority")
+	press(m, enter)
+	if got := m.Doc.UserStories[0].Priority; got != common.PriorityMedium {
+		t.Errorf("priority = %q", got)
+	}
+
+	// Acceptance criteria keep their IDs and steps.
+	selectField(t, m, "Acceptance criteria")
+	press(m, enter)
+	typeText(m, "Pays without an account; Gets a receipt")
+	press(m, enter)
+	m.Doc.UserStories[0].AcceptanceCriteria[0].Given = "a cart"
+	if err := m.load(m.Doc); err != nil {
+		t.Fatal(err)
+	}
+	press(m, enter, termui.Key{Type: termui.KeyEnd})
+	typeText(m, "; Can save the card")
+	press(m, enter)
+	ac := m.Doc.UserStories[0].AcceptanceCriteria
+	if len(ac) != 3 || ac[0].ID != "ac-1" || ac[0].Given != "a cart" || ac[2].ID != "ac-3" {
+		t.Errorf("acceptance criteria = %+v", ac)
+	}
+}
+
+func TestAddDelete(t *testing.T) {
+	m := newModel(t)
+	s := openSection(t, m, "User Stories")
+	press(m, termui.RuneKey('a'))
+	if len(m.Doc.UserStories) != 3 || m.Doc.UserStories[2].ID != "US-003" {
+		t.Fatalf("stories = %+v", m.Doc.UserStories)
+	}
+	if m.form != s || m.index != 2 || s.Fields[m.field].Label != "Title" {
+		t.Errorf("added story's form not opened at its title")
+	}
+	press(m, enter)
+	typeText(m, "Split payments")
+	press(m, enter, esc)
+
+	if sec, i := m.Current(); sec != s || i != 2 {
+		t.Fatalf("cursor on %v %d after closing the form", sec, i)
+	}
+	press(m, termui.RuneKey('d'))
+	if len(m.Doc.UserStories) != 3 || !strings.Contains(m.status, "again") {
+		t.Fatalf("first d deleted, status = %q", m.status)
+	}
+	press(m, termui.RuneKey('d'))
+	if len(m.Doc.UserStories) != 2 || m.status != "Deleted US-003  Split payments" {
+		t.Errorf("stories = %d, status = %q", len(m.Doc.UserStories), m.status)
+	}
+
+	// Adding to an empty list creates it.
+	openSection(t, m, "Non-Functional Requirements")
+	press(m, termui.RuneKey('a'))
+	if nfr := m.Doc.Requirements.NonFunctional; len(nfr) != 1 || nfr[0].ID != "nfr-1" {
+		t.Errorf("nfrs = %+v", nfr)
+	}
+}
+
+func TestSaveAndQuit(t *testing.T) {
+	m := newModel(t)
+	if a := press(m, termui.RuneKey('w')); a != termui.ActionNone || m.status != "No changes to save" {
+		t.Errorf("save without changes = %v, %q", a, m.status)
+	}
+	openSection(t, m, "Summary")
+	press(m, enter)
+	selectField(t, m, "Problem statement")
+	press(m, enter)
+	typeText(m, "Carts are abandoned")
+	press(m, enter)
+	if m.Doc.ExecutiveSummary.ProblemStatement != "Carts are abandoned" {
+		t.Fatalf("problem = %q", m.Doc.ExecutiveSummary.ProblemStatement)
+	}
+
+	if a := press(m, termui.RuneKey('q')); a != termui.ActionNone {
+		t.Error("quit with unsaved changes did not ask")
+	}
+	if a := press(m, termui.RuneKey('w')); a != termui.ActionSave {
+		t.Errorf("save = %v", a)
+	}
+	m.Saved("Saved")
+	if m.Dirty() {
+		t.Error("dirty after save")
+	}
+	if a := press(m, termui.RuneKey('q')); a != termui.ActionQuit {
+		t.Errorf("quit = %v", a)
+	}
+}
+
+func TestNextID(t *testing.T) {
+	for _, tc := range []struct {
+		ids  []string
+		want string
+	}{
+		{[]string{"FR-008", "FR-009"}, "FR-010"},
+		{[]string{"us-2", "us-10", "epic"}, "us-11"},
+		{nil, "fr-1"},
+	} {
+		if got := nextID(tc.ids, "fr-1"); got != tc.want {
+			t.Errorf("nextID(%q) = %q, want %q", tc.ids, got, tc.want)
+		}
+	}
+	if got := issuePath("user_stories[1]", "/acceptanceCriteria"); got != "user_stories[1].acceptance_criteria" {
+		t.Errorf("issuePath = %q", got)
+	}
+}
@@ -0,0 +1,17 @@
+package editor
+
+import (
+	"os"
+
+	"github.com/grokify/structured-plan/termui"
+)
+
+// ErrNotTerminal is returned by Run when input or output is not a terminal.
+var ErrNotTerminal = termui.ErrNotTerminal
+
+// Run shows m on the terminal until the user quits. save is called when
+// the user saves; it should write m.Doc and call m.Saved. On return the
+// terminal is restored.
+func Run(in, out *os.File, m *Model, save func() error) error {
+	return termui.Run(in, out, m, save)
+}
@@ -0,0 +1,218 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/termui"
+)
+
+const (
+	headerLines = 3
+	footerLines = 3
+	labelWidth  = 20
+
+	listHelp = "↑/↓ move  enter open  a add  d delete  w save  q quit"
+	formHelp = "↑/↓ move  enter edit  ← back  a add  d delete  w save  q quit"
+	editHelp = "enter apply  esc cancel  ←/→ move  ctrl-u clear"
+)
+
+// View renders the screen for a terminal of the given size.
+func (m *Model) View(width, height int) string {
+	width = max(width, 40)
+	body := max(height-headerLines-footerLines, 3)
+
+	lines := m.header(width)
+	if m.form != nil {
+		lines = append(lines, m.formBody(body)...)
+	} else {
+		lines = append(lines, m.listBody(body)...)
+	}
+
+	lines = append(lines, strings.Repeat("─", width), m.info())
+	switch {
+	case m.status != "":
+		lines = append(lines, m.status)
+	case m.editing:
+		lines = append(lines, editHelp)
+	case m.form != nil:
+		lines = append(lines, formHelp)
+	default:
+		lines = append(lines, listHelp)
+	}
+	for i, l := range lines {
+		lines[i] = termui.Truncate(l, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) header(width int) []string {
+	title := "PRD EDITOR"
+	if t := m.Doc.Metadata.Title; t != "" {
+		title += " — " + t
+	}
+	if id := m.Doc.Metadata.ID; id != "" {
+		title += " (" + id + ")"
+	}
+	summary := "Valid"
+	if errs, warns := countIssues(m.issues); errs+warns > 0 {
+		summary = fmt.Sprintf("%d errors, %d warnings", errs, warns)
+	}
+	if m.dirty {
+		summary += " · unsaved"
+	}
+	return []string{title, summary, strings.Repeat("─", width)}
+}
+
+func countIssues(issues []Issue) (errs, warns int) {
+	for _, is := range issues {
+		if is.Error {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	return errs, warns
+}
+
+// issueMarks summarizes issues as "✗ 2 ! 1", or "" when there are none.
+func issueMarks(issues []Issue) string {
+	errs, warns := countIssues(issues)
+	var parts []string
+	if errs > 0 {
+		parts = append(parts, fmt.Sprintf("✗ %d", errs))
+	}
+	if warns > 0 {
+		parts = append(parts, fmt.Sprintf("! %d", warns))
+	}
+	return strings.Join(parts, " ")
+}
+
+func issueLine(is Issue) string {
+	if is.Error {
+		return "✗ " + is.Message
+	}
+	return "! " + is.Message
+}
+
+// listBody renders the visible part of the section list, scrolled to keep
+// the cursor in view.
+func (m *Model) listBody(height int) []string {
+	rows := m.rows()
+	return m.list.Lines(len(rows), height, func(i int) string {
+		r := rows[i]
+		if r.index < 0 {
+			return m.sectionLine(r.section)
+		}
+		line := fmt.Sprintf("      %-40s", entityLabel(m.entities(r.section)[r.index]))
+		if marks := issueMarks(m.issuesUnder(r.section.entityIssuePath(r.index))); marks != "" {
+			line += "  " + marks
+		}
+		return line
+	})
+}
+
+func (m *Model) sectionLine(s *Section) string {
+	arrow := "▸"
+	if s.expanded {
+		arrow = "▾"
+	}
+	name := s.Name
+	if s.Single {
+		arrow = " "
+	} else {
+		name = fmt.Sprintf("%s (%d)", s.Name, len(m.entities(s)))
+	}
+	line := fmt.Sprintf("%s %-34s", arrow, name)
+	if marks := issueMarks(m.sectionIssues(s)); marks != "" {
+		line += "  " + marks
+	}
+	return line
+}
+
+// info describes the current row or field in one line below the body.
+func (m *Model) info() string {
+	if m.form != nil {
+		f := &m.form.Fields[m.field]
+		switch {
+		case len(f.Choices) > 0:
+			return "Values: " + strings.Join(f.Choices, ", ")
+		case f.Kind != FieldText:
+			return "Separate items with \"" + ListSeparator + "\""
+		}
+		return ""
+	}
+	s, i := m.Current()
+	if s == nil {
+		return ""
+	}
+	// Issues about the section itself, e.g. an empty list.
+	for _, is := range m.sectionIssues(s) {
+		if i < 0 && (s.Single || is.Field == s.IssuePath) {
+			return issueLine(is)
+		}
+	}
+	if i < 0 && !s.Single && len(m.entities(s)) == 0 {
+		return "Press a to add the first entry"
+	}
+	return ""
+}
+
+// formBody renders the fields of the open entity with their validation
+// issues, scrolled to keep the selected field in view.
+func (m *Model) formBody(height int) []string {
+	s := m.form
+	title := s.Name
+	if !s.Single {
+		title += " › " + entityLabel(m.entities(s)[m.index])
+	}
+	lines := []string{title, ""}
+
+	base := s.entityIssuePath(m.index)
+	shown := make(map[Issue]bool)
+	selected := 0
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		marker := "  "
+		if i == m.field {
+			marker = "> "
+			selected = len(lines)
+		}
+		value := f.format(m.value(f))
+		if i == m.field && m.editing {
+			value = string(m.input[:m.inputPos]) + "▏" + string(m.input[m.inputPos:])
+		}
+		lines = append(lines, fmt.Sprintf("%s%-*s %s", marker, labelWidth, f.Label, value))
+		for _, is := range m.issuesUnder(issuePath(base, f.Path)) {
+			shown[is] = true
+			lines = append(lines, strings.Repeat(" ", labelWidth+3)+issueLine(is))
+		}
+	}
+	if !s.Single {
+		var other []string
+		for _, is := range m.issuesUnder(base) {
+			if !shown[is] {
+				other = append(other, "  "+issueLine(is))
+			}
+		}
+		if len(other) > 0 {
+			lines = append(append(lines, "", "Other issues:"), other...)
+		}
+	}
+
+	if selected < m.formTop {
+		m.formTop = selected
+	}
+	if selected >= m.formTop+height {
+		m.formTop = selected - height + 1
+	}
+	m.formTop = max(0, min(m.formTop, len(lines)-1))
+	lines = lines[m.formTop:]
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
@@ -71,7 +71,7 @@ func StandardCategories() []EvaluationCategory {
 		{
 			ID:          "ux_coverage",
 			Name:        "UX Coverage",
-			Description: "Design principles, wireframes, interaction flows, and accessibility",
+			Description: "Design principles, wireframes, interaction flows, journey maps, and accessibility",
 			Weight:      0.05,
 			Owner:       "ux-journey",
 		},
@@ -111,8 +111,8 @@ func GenerateEvaluationTemplate(doc *Document, filename string) *evaluation.Eval
 	report.Metadata.GeneratedAt = time.Now().UTC()
 	report.Metadata.GeneratedBy = "structured-requirements"
 
-	// Add standard categories
-	for _, cat := range StandardCategories() {
+	// Add standard and registered categories
+	for _, cat := range Categories() {
 		report.AddCategory(evaluation.CategoryScore{
 			Category:      cat.ID,
 			Score:         0, // To be filled by LLM judge
@@ -160,7 +160,7 @@ func GenerateEvaluationTemplateWithWeights(doc *Document, filename string, weigh
 // Useful for providing context to LLM judges.
 func CategoryDescriptions() map[string]string {
 	descs := make(map[string]string)
-	for _, cat := range StandardCategories() {
+	for _, cat := range Categories() {
 		descs[cat.ID] = cat.Description
 	}
 	return descs
@@ -170,17 +170,17 @@ func CategoryDescriptions() map[string]string {
 // Useful for assigning findings to responsible teams.
 func CategoryOwners() map[string]string {
 	owners := make(map[string]string)
-	for _, cat := range StandardCategories() {
+	for _, cat := range Categories() {
 		owners[cat.ID] = cat.Owner
 	}
 	return owners
 }
 
 // GetCategoriesFromDocument extracts the list of categories that should be evaluated
-// based on what's present in the document. This includes standard categories and
-// any custom sections defined in the PRD.
+// based on what's present in the document. This includes standard and registered
+// categories and any custom sections defined in the PRD.
 func GetCategoriesFromDocument(doc *Document) []EvaluationCategory {
-	categories := StandardCategories()
+	categories := Categories()
 
 	// Add custom sections
 	for _, section := range doc.CustomSections {
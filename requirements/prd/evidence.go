@@ -0,0 +1,152 @@
+package prd
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultEvidenceMaxAge is the age after which evidence is considered stale.
+const DefaultEvidenceMaxAge = 365 * 24 * time.Hour
+
+// EvidenceItem is a piece of evidence with the problem it supports.
+type EvidenceItem struct {
+	Evidence
+
+	// ProblemID identifies the supported problem; empty for a problem
+	// without an ID.
+	ProblemID string `json:"problemId,omitempty"`
+
+	// Field is the JSON path of the evidence, e.g. "problem.evidence[0]".
+	Field string `json:"field"`
+
+	// AgeDays is the evidence age in days, or -1 if undated.
+	AgeDays int `json:"ageDays"`
+}
+
+// EvidenceIssue describes a problem with a piece of evidence or with the
+// evidence base as a whole.
+type EvidenceIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// EvidenceOptions configure CheckEvidence.
+type EvidenceOptions struct {
+	// Now is the reference time for freshness. Defaults to time.Now().
+	Now time.Time
+
+	// MaxAge is the age after which evidence is stale. Defaults to
+	// DefaultEvidenceMaxAge.
+	MaxAge time.Duration
+}
+
+// EvidenceReport summarizes the evidence behind a document's problem
+// definitions.
+type EvidenceReport struct {
+	Items      []EvidenceItem           `json:"items"`
+	ByStrength map[EvidenceStrength]int `json:"byStrength"`
+	ByType     map[EvidenceType]int     `json:"byType"`
+	Stale      int                      `json:"stale"`
+	Issues     []EvidenceIssue          `json:"issues,omitempty"`
+}
+
+// quantitativeEvidence lists evidence types expected to report a sample size.
+var quantitativeEvidence = map[EvidenceType]bool{
+	EvidenceSurvey:        true,
+	EvidenceAnalytics:     true,
+	EvidenceSupportTicket: true,
+}
+
+// AllEvidence returns the evidence of the problem definition and its
+// secondary problems, in document order.
+func (d *Document) AllEvidence() []EvidenceItem {
+	if d.Problem == nil {
+		return nil
+	}
+	var items []EvidenceItem
+	var collect func(p *ProblemDefinition, path string)
+	collect = func(p *ProblemDefinition, path string) {
+		for i, e := range p.Evidence {
+			items = append(items, EvidenceItem{
+				Evidence:  e,
+				ProblemID: p.ID,
+				Field:     fmt.Sprintf("%s.evidence[%d]", path, i),
+				AgeDays:   -1,
+			})
+		}
+		for i := range p.SecondaryProblems {
+			collect(&p.SecondaryProblems[i], fmt.Sprintf("%s.secondaryProblems[%d]", path, i))
+		}
+	}
+	collect(d.Problem, "problem")
+	return items
+}
+
+// CheckEvidence checks each piece of evidence for a source, strength, date,
+// freshness, and (for quantitative types) a sample size, and checks that the
+// evidence base is not made up only of weak evidence or assumptions.
+func CheckEvidence(doc *Document, opts EvidenceOptions) *EvidenceReport {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.MaxAge == 0 {
+		opts.MaxAge = DefaultEvidenceMaxAge
+	}
+
+	report := &EvidenceReport{
+		Items:      doc.AllEvidence(),
+		ByStrength: map[EvidenceStrength]int{},
+		ByType:     map[EvidenceType]int{},
+	}
+	issue := func(field, format string, args ...any) {
+		report.Issues = append(report.Issues, EvidenceIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if doc.Problem != nil && len(report.Items) == 0 {
+		issue("problem.evidence", "Problem has no supporting evidence")
+	}
+
+	for i := range report.Items {
+		item := &report.Items[i]
+		report.ByStrength[item.Strength]++
+		report.ByType[item.Type]++
+
+		if item.Source == "" {
+			issue(item.Field, "Evidence has no source")
+		}
+		if item.Strength == "" {
+			issue(item.Field, "Evidence strength not rated")
+		}
+		if quantitativeEvidence[item.Type] && item.SampleSize <= 0 {
+			issue(item.Field, "%s evidence has no sample size", item.Type)
+		}
+
+		if item.Date == "" {
+			if item.Type != EvidenceAssumption {
+				issue(item.Field, "Evidence is undated")
+			}
+			continue
+		}
+		date, err := parseDate(item.Date)
+		if err != nil {
+			issue(item.Field, "Invalid date %q: expected YYYY-MM-DD", item.Date)
+			continue
+		}
+		item.AgeDays = int(opts.Now.Sub(date).Hours() / 24)
+		if opts.Now.Sub(date) > opts.MaxAge {
+			report.Stale++
+			issue(item.Field, "Evidence from %s is stale (%d days old)", item.Date, item.AgeDays)
+		}
+	}
+
+	if n := len(report.Items); n > 0 {
+		if report.ByStrength[StrengthHigh]+report.ByStrength[StrengthMedium] == 0 {
+			issue("problem.evidence", "No medium or high strength evidence")
+		}
+		if assumptions := report.ByType[EvidenceAssumption]; assumptions*2 > n {
+			issue("problem.evidence", "%d of %d evidence items are unvalidated assumptions", assumptions, n)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,94 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckEvidence(t *testing.T) {
+	doc := &Document{Problem: &ProblemDefinition{
+		ID: "PROB-1",
+		Evidence: []Evidence{
+			{ID: "EV-1", Type: EvidenceSurvey, Source: "NPS survey", SampleSize: 1200, Strength: StrengthHigh, Date: "2026-08-01"},
+			{ID: "EV-2", Type: EvidenceAnalytics, Source: "Dashboards", Strength: StrengthMedium, Date: "2024-01-10"},
+			{ID: "EV-3", Type: EvidenceAssumption, Source: "PM intuition", Strength: StrengthLow},
+		},
+		SecondaryProblems: []ProblemDefinition{{
+			ID:       "PROB-2",
+			Evidence: []Evidence{{ID: "EV-4", Type: EvidenceInterview, Date: "Sept"}},
+		}},
+	}}
+
+	report := CheckEvidence(doc, EvidenceOptions{Now: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)})
+
+	if len(report.Items) != 4 || report.Items[3].ProblemID != "PROB-2" ||
+		report.Items[3].Field != "problem.secondaryProblems[0].evidence[0]" {
+		t.Fatalf("Items = %+v", report.Items)
+	}
+	if report.Items[0].AgeDays != 61 || report.Items[2].AgeDays != -1 {
+		t.Errorf("AgeDays = %d, %d", report.Items[0].AgeDays, report.Items[2].AgeDays)
+	}
+	if report.Stale != 1 || report.ByStrength[StrengthMedium] != 1 || report.ByType[EvidenceAssumption] != 1 {
+		t.Errorf("Stale = %d, ByStrength = %v, ByType = %v", report.Stale, report.ByStrength, report.ByType)
+	}
+
+	var got []string
+	for _, issue := range report.Issues {
+		got = append(got, issue.Field+": "+issue.Message)
+	}
+	want := []string{
+		"problem.evidence[1]: analytics evidence has no sample size",
+		"problem.evidence[1]: Evidence from 2024-01-10 is stale (995 days old)",
+		"problem.secondaryProblems[0].evidence[0]: Evidence has no source",
+		"problem.secondaryProblems[0].evidence[0]: Evidence strength not rated",
+		`problem.secondaryProblems[0].evidence[0]: Invalid date "Sept": expected YYYY-MM-DD`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Issues =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckEvidenceDistribution(t *testing.T) {
+	doc := &Document{Problem: &ProblemDefinition{Evidence: []Evidence{
+		{Type: EvidenceAssumption, Source: "a", Strength: StrengthLow},
+		{Type: EvidenceAssumption, Source: "b", Strength: StrengthLow},
+		{Type: EvidenceInterview, Source: "c", Strength: StrengthLow, Date: "2026-01-01"},
+	}}}
+	report := CheckEvidence(doc, EvidenceOptions{Now: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)})
+
+	var messages []string
+	for _, issue := range report.Issues {
+		messages = append(messages, issue.Message)
+	}
+	want := []string{"No medium or high strength evidence", "2 of 3 evidence items are unvalidated assumptions"}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Issues = %v", messages)
+	}
+
+	empty := CheckEvidence(&Document{Problem: &ProblemDefinition{Statement: "x"}}, EvidenceOptions{})
+	if len(empty.Issues) != 1 || empty.Issues[0].Message != "Problem has no supporting evidence" {
+		t.Errorf("empty evidence issues = %+v", empty.Issues)
+	}
+}
+
+func TestEvidenceAppendixMarkdown(t *testing.T) {
+	doc := &Document{Problem: &ProblemDefinition{ID: "PROB-1", Evidence: []Evidence{
+		{ID: "EV-1", Type: EvidenceSurvey, Source: "Survey", SampleSize: 300, Strength: StrengthHigh,
+			Date: "2026-01-01", Links: []string{"https://example.com/a", "https://example.com/b"}},
+	}}}
+	md := doc.ToMarkdown(DefaultMarkdownOptions())
+
+	for _, want := range []string{
+		"[Evidence Appendix](#sec-evidence-appendix)",
+		"## Evidence Appendix",
+		"| EV-1 | PROB-1 | survey | Survey |  | 300 | high | 2026-01-01 | [1](https://example.com/a), [2](https://example.com/b) |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+	if strings.Contains((&Document{}).ToMarkdown(DefaultMarkdownOptions()), "Evidence Appendix") {
+		t.Error("evidence appendix rendered without evidence")
+	}
+}
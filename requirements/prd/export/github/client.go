@@ -0,0 +1,269 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// DefaultBaseURL is the GitHub REST API endpoint for github.com.
+const DefaultBaseURL = "https://api.github.com"
+
+// Client syncs issues with one repository through the GitHub REST API.
+type Client struct {
+	// BaseURL is the API endpoint; GitHub Enterprise Server uses
+	// https://HOST/api/v3.
+	BaseURL string
+	Token   string
+
+	// Owner and Repo name the repository, e.g. "acme" and "checkout".
+	Owner string
+	Repo  string
+
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for the repository "owner/name". An empty
+// baseURL uses DefaultBaseURL.
+func NewClient(baseURL, token, repository string) (*Client, error) {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("repository must be owner/name, got %q", repository)
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		Owner:      owner,
+		Repo:       repo,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// SyncResult lists the PRD IDs whose issues were created, updated, or
+// already up to date, and the milestones created.
+type SyncResult struct {
+	Created    []string
+	Updated    []string
+	Unchanged  []string
+	Milestones []string
+}
+
+// remoteIssue is the part of a GitHub issue Sync compares.
+type remoteIssue struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	PullRequest any    `json:"pull_request"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Number int `json:"number"`
+	} `json:"milestone"`
+}
+
+type remoteMilestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// issueRequest is the body of the create and update issue endpoints.
+type issueRequest struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Labels    []string `json:"labels"`
+	Milestone *int     `json:"milestone"`
+}
+
+// Sync creates an issue for each entity whose marker is not found in the
+// repository's issues, open or closed, and updates the issues that differ.
+// Milestones missing for the entities' phases are created; GitHub creates
+// missing labels itself. The issue URL is recorded in each entity's
+// externalRefs under "github", so the caller should save doc afterwards —
+// also when Sync fails part way. With dryRun, nothing is written and the
+// result lists what would change.
+func (c *Client) Sync(ctx context.Context, doc *prd.Document, dryRun bool) (SyncResult, error) {
+	var res SyncResult
+	issues, milestones := Issues(doc)
+
+	existing := map[string]remoteIssue{}
+	err := c.list(ctx, "/issues?state=all", func(raw json.RawMessage) error {
+		var ri remoteIssue
+		if err := json.Unmarshal(raw, &ri); err != nil {
+			return err
+		}
+		if m := FindMarker(ri.Body); m != "" && ri.PullRequest == nil {
+			existing[m] = ri
+		}
+		return nil
+	})
+	if err != nil {
+		return res, fmt.Errorf("listing issues: %w", err)
+	}
+
+	milestoneNumbers := map[string]int{}
+	err = c.list(ctx, "/milestones?state=all", func(raw json.RawMessage) error {
+		var m remoteMilestone
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return err
+		}
+		milestoneNumbers[m.Title] = m.Number
+		return nil
+	})
+	if err != nil {
+		return res, fmt.Errorf("listing milestones: %w", err)
+	}
+	for _, m := range milestones {
+		if _, ok := milestoneNumbers[m.Title]; ok {
+			continue
+		}
+		res.Milestones = append(res.Milestones, m.Title)
+		if dryRun {
+			continue
+		}
+		body := map[string]any{"title": m.Title, "description": m.Description}
+		if m.DueOn != nil {
+			body["due_on"] = m.DueOn.UTC().Format(time.RFC3339)
+		}
+		var created remoteMilestone
+		if err := c.do(ctx, http.MethodPost, "/milestones", body, &created); err != nil {
+			return res, fmt.Errorf("creating milestone %q: %w", m.Title, err)
+		}
+		milestoneNumbers[m.Title] = created.Number
+	}
+
+	for _, is := range issues {
+		req := issueRequest{Title: is.Title, Body: is.Body, Labels: is.Labels}
+		if req.Labels == nil {
+			req.Labels = []string{}
+		}
+		if n, ok := milestoneNumbers[is.Milestone]; ok {
+			req.Milestone = &n
+		}
+		ri, found := existing[is.Marker]
+		switch {
+		case found && !differs(ri, req):
+			res.Unchanged = append(res.Unchanged, is.SourceID)
+		case found:
+			res.Updated = append(res.Updated, is.SourceID)
+			if !dryRun {
+				if err := c.do(ctx, http.MethodPatch, "/issues/"+strconv.Itoa(ri.Number), req, &ri); err != nil {
+					return res, fmt.Errorf("updating issue #%d for %s: %w", ri.Number, is.SourceID, err)
+				}
+			}
+		default:
+			res.Created = append(res.Created, is.SourceID)
+			if !dryRun {
+				if err := c.do(ctx, http.MethodPost, "/issues", req, &ri); err != nil {
+					return res, fmt.Errorf("creating issue for %s: %w", is.SourceID, err)
+				}
+			}
+		}
+		if !dryRun && ri.HTMLURL != "" {
+			is.refs.Set(common.ExternalSystemGitHub, ri.HTMLURL)
+		}
+	}
+	return res, nil
+}
+
+// differs reports whether updating ri with req would change it.
+func differs(ri remoteIssue, req issueRequest) bool {
+	if ri.Title != req.Title || strings.TrimSpace(ri.Body) != strings.TrimSpace(req.Body) {
+		return true
+	}
+	current := make([]string, len(ri.Labels))
+	for i, l := range ri.Labels {
+		current[i] = l.Name
+	}
+	want := slices.Clone(req.Labels)
+	slices.Sort(current)
+	slices.Sort(want)
+	if !slices.Equal(current, want) {
+		return true
+	}
+	switch {
+	case ri.Milestone == nil:
+		return req.Milestone != nil
+	case req.Milestone == nil:
+		return true
+	}
+	return ri.Milestone.Number != *req.Milestone
+}
+
+// list calls fn with each item of a paginated repository collection.
+func (c *Client) list(ctx context.Context, path string, fn func(json.RawMessage) error) error {
+	const perPage = 100
+	for page := 1; ; page++ {
+		var items []json.RawMessage
+		p := path + "&per_page=" + strconv.Itoa(perPage) + "&page=" + strconv.Itoa(page)
+		if err := c.do(ctx, http.MethodGet, p, nil, &items); err != nil {
+			return err
+		}
+		for _, it := range items {
+			if err := fn(it); err != nil {
+				return err
+			}
+		}
+		if len(items) < perPage {
+			return nil
+		}
+	}
+}
+
+// do sends a request for a path under the repository, with body as JSON
+// if given, and decodes the response into out, if given.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	url := c.BaseURL + "/repos/" + c.Owner + "/" + c.Repo + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("github returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,177 @@
+// Package github exports PRD user stories and functional requirements as
+// GitHub issues through the GitHub REST API.
+//
+// Each story or requirement becomes one issue, labelled with its tags and
+// assigned to a milestone named after its roadmap phase. The issue body
+// ends with a hidden marker holding the PRD and entity IDs, e.g.
+// <!-- splan:prd PRD-2026-001/FR-001 -->, which is how a later sync finds
+// the issue again: matching issues are updated, never duplicated.
+package github
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Issue is a GitHub issue built from one PRD entity.
+type Issue struct {
+	// SourceID is the ID of the PRD entity, e.g. "US-001".
+	SourceID string
+
+	// Marker identifies the entity in the issue body; see Marker.
+	Marker string
+
+	Title  string
+	Body   string
+	Labels []string
+
+	// Milestone is the title of the milestone for the entity's phase, or
+	// empty when it has none.
+	Milestone string
+
+	refs *common.ExternalRefs
+}
+
+// Milestone is a milestone built from a roadmap phase.
+type Milestone struct {
+	Title       string
+	Description string
+	DueOn       *time.Time
+}
+
+var markerPattern = regexp.MustCompile(`<!-- splan:prd (\S+) -->`)
+
+// Marker returns the hidden comment that identifies an entity in an issue
+// body. The PRD ID is included so several PRDs can share a repository.
+func Marker(docID, entityID string) string {
+	key := entityID
+	if docID != "" {
+		key = docID + "/" + entityID
+	}
+	return "<!-- splan:prd " + key + " -->"
+}
+
+// FindMarker returns the marker in body, or "" if there is none.
+func FindMarker(body string) string {
+	return markerPattern.FindString(body)
+}
+
+// Issues builds one issue per user story and functional requirement, in
+// document order, and the milestones for the phases they belong to. The
+// issues keep a reference to doc so Sync can record their URLs.
+func Issues(doc *prd.Document) ([]Issue, []Milestone) {
+	phases := make(map[string]*prd.Phase, len(doc.Roadmap.Phases))
+	for i := range doc.Roadmap.Phases {
+		phases[doc.Roadmap.Phases[i].ID] = &doc.Roadmap.Phases[i]
+	}
+	var milestones []Milestone
+	seen := map[string]bool{}
+	milestone := func(phaseID string) string {
+		m := Milestone{Title: phaseID}
+		if p := phases[phaseID]; p != nil {
+			if p.Name != "" {
+				m.Title = p.Name
+			}
+			m.Description = strings.Join(p.Goals, "\n")
+			m.DueOn = p.EndDate
+		}
+		if !seen[m.Title] {
+			seen[m.Title] = true
+			milestones = append(milestones, m)
+		}
+		return m.Title
+	}
+	docID := doc.Metadata.ID
+
+	issues := make([]Issue, 0, len(doc.UserStories)+len(doc.Requirements.Functional))
+	for i := range doc.UserStories {
+		us := &doc.UserStories[i]
+		var body strings.Builder
+		body.WriteString(us.Story() + ".\n")
+		writeAcceptanceCriteria(&body, us.AcceptanceCriteria)
+		writeFooter(&body, docID, us.ID, string(us.Priority), us.PhaseID)
+		is := Issue{
+			SourceID: us.ID,
+			Marker:   Marker(docID, us.ID),
+			Title:    title(us.ID, us.Title, us.IWant),
+			Labels:   us.Tags,
+			refs:     &us.ExternalRefs,
+		}
+		if us.PhaseID != "" {
+			is.Milestone = milestone(us.PhaseID)
+		}
+		is.Body = body.String()
+		issues = append(issues, is)
+	}
+	for i := range doc.Requirements.Functional {
+		r := &doc.Requirements.Functional[i]
+		var body strings.Builder
+		body.WriteString(r.Description + "\n")
+		writeAcceptanceCriteria(&body, r.AcceptanceCriteria)
+		writeFooter(&body, docID, r.ID, string(r.Priority), r.PhaseID)
+		is := Issue{
+			SourceID: r.ID,
+			Marker:   Marker(docID, r.ID),
+			Title:    title(r.ID, r.Title, r.Description),
+			Labels:   r.Tags,
+			refs:     &r.ExternalRefs,
+		}
+		if r.PhaseID != "" {
+			is.Milestone = milestone(r.PhaseID)
+		}
+		is.Body = body.String()
+		issues = append(issues, is)
+	}
+	return issues, milestones
+}
+
+// title returns "ID: title", falling back to the first line of fallback.
+func title(id, t, fallback string) string {
+	t = strings.TrimSpace(t)
+	if t == "" {
+		t, _, _ = strings.Cut(strings.TrimSpace(fallback), "\n")
+	}
+	return id + ": " + t
+}
+
+// writeAcceptanceCriteria writes criteria as a markdown task list, with
+// Given/When/Then steps nested under each criterion.
+func writeAcceptanceCriteria(sb *strings.Builder, criteria []prd.AcceptanceCriterion) {
+	if len(criteria) == 0 {
+		return
+	}
+	sb.WriteString("\n### Acceptance Criteria\n\n")
+	for _, ac := range criteria {
+		sb.WriteString("- [ ] ")
+		if ac.ID != "" {
+			sb.WriteString("**" + ac.ID + "** ")
+		}
+		sb.WriteString(ac.Description + "\n")
+		for _, step := range [][2]string{{"Given", ac.Given}, {"When", ac.When}, {"Then", ac.Then}} {
+			if step[1] != "" {
+				sb.WriteString("  - *" + step[0] + "* " + step[1] + "\n")
+			}
+		}
+	}
+}
+
+func writeFooter(sb *strings.Builder, docID, id, priority, phaseID string) {
+	sb.WriteString("\n---\n")
+	var parts []string
+	if docID != "" {
+		parts = append(parts, "PRD: "+docID)
+	}
+	parts = append(parts, "ID: "+id)
+	if priority != "" {
+		parts = append(parts, "Priority: "+priority)
+	}
+	if phaseID != "" {
+		parts = append(parts, "Phase: "+phaseID)
+	}
+	sb.WriteString("<sub>" + strings.Join(parts, " · ") + "</sub>\n\n")
+	sb.WriteString(Marker(docID, id) + "\n")
+}
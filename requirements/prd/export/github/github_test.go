@@ -0,0 +1,149 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func testDocument() *prd.Document {
+	doc := &prd.Document{
+		Metadata: prd.Metadata{ID: "PRD-1"},
+		UserStories: []prd.UserStory{{
+			ID: "US-001", Title: "Sign in with SSO", AsA: "user", IWant: "to sign in with SSO", SoThat: "I have one password",
+			PhaseID: "phase-1", Tags: []string{"auth"},
+			AcceptanceCriteria: []prd.AcceptanceCriterion{{ID: "AC-1", Description: "Google login works", Given: "a Google account"}},
+		}},
+		Requirements: prd.Requirements{Functional: []prd.FunctionalRequirement{{
+			ID: "FR-001", Title: "OIDC support", Description: "Support OIDC providers", PhaseID: "phase-1",
+		}}},
+	}
+	doc.Roadmap.Phases = append(doc.Roadmap.Phases, prd.Phase{ID: "phase-1", Name: "MVP"})
+	return doc
+}
+
+func TestIssues(t *testing.T) {
+	issues, milestones := Issues(testDocument())
+	if len(issues) != 2 || len(milestones) != 1 || milestones[0].Title != "MVP" {
+		t.Fatalf("issues = %d, milestones = %+v", len(issues), milestones)
+	}
+	us := issues[0]
+	if us.Title != "US-001: Sign in with SSO" || us.Milestone != "MVP" {
+		t.Errorf("story issue = %+v", us)
+	}
+	for _, want := range []string{"- [ ] **AC-1** Google login works", "  - *Given* a Google account", "<!-- splan:prd PRD-1/US-001 -->"} {
+		if !strings.Contains(us.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, us.Body)
+		}
+	}
+	if got := FindMarker("text\n" + Marker("", "FR-9") + "\n"); got != "<!-- splan:prd FR-9 -->" {
+		t.Errorf("FindMarker = %q", got)
+	}
+}
+
+// fakeRepo is an in-memory GitHub repository serving the endpoints Sync uses.
+type fakeRepo struct {
+	mu         sync.Mutex
+	issues     []map[string]any
+	milestones []map[string]any
+	writes     int
+}
+
+func (f *fakeRepo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/repos/acme/shop")
+	var body map[string]any
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	issue := func(n int, req map[string]any) map[string]any {
+		var labels []map[string]any
+		for _, l := range req["labels"].([]any) {
+			labels = append(labels, map[string]any{"name": l})
+		}
+		is := map[string]any{"number": n, "title": req["title"], "body": req["body"], "labels": labels,
+			"html_url": fmt.Sprintf("https://github.com/acme/shop/issues/%d", n)}
+		if m, ok := req["milestone"].(float64); ok {
+			is["milestone"] = map[string]any{"number": m}
+		}
+		return is
+	}
+	switch {
+	case r.Method == http.MethodGet && path == "/issues":
+		if r.URL.Query().Get("page") != "1" {
+			_ = json.NewEncoder(w).Encode([]any{})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.issues)
+	case r.Method == http.MethodGet && path == "/milestones":
+		_ = json.NewEncoder(w).Encode(f.milestones)
+	case r.Method == http.MethodPost && path == "/milestones":
+		f.writes++
+		m := map[string]any{"number": len(f.milestones) + 1, "title": body["title"]}
+		f.milestones = append(f.milestones, m)
+		_ = json.NewEncoder(w).Encode(m)
+	case r.Method == http.MethodPost && path == "/issues":
+		f.writes++
+		is := issue(len(f.issues)+1, body)
+		f.issues = append(f.issues, is)
+		_ = json.NewEncoder(w).Encode(is)
+	case r.Method == http.MethodPatch && strings.HasPrefix(path, "/issues/"):
+		f.writes++
+		var n int
+		_, _ = fmt.Sscanf(path, "/issues/%d", &n)
+		f.issues[n-1] = issue(n, body)
+		_ = json.NewEncoder(w).Encode(f.issues[n-1])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSync(t *testing.T) {
+	repo := &fakeRepo{}
+	srv := httptest.NewServer(repo)
+	defer srv.Close()
+	c, err := NewClient(srv.URL, "token", "acme/shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := testDocument()
+	res, err := c.Sync(context.Background(), doc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Created) != 2 || len(res.Milestones) != 1 {
+		t.Errorf("first sync = %+v", res)
+	}
+	if got := doc.Requirements.Functional[0].ExternalRefs["github"]; got != "https://github.com/acme/shop/issues/2" {
+		t.Errorf("requirement github ref = %q", got)
+	}
+
+	// A fresh copy of the PRD, without recorded refs, still matches the
+	// existing issues by their markers.
+	doc = testDocument()
+	doc.Requirements.Functional[0].Title = "OIDC and SAML support"
+	writes := repo.writes
+	res, err = c.Sync(context.Background(), doc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Created) != 0 || len(res.Updated) != 1 || len(res.Unchanged) != 1 || len(res.Milestones) != 0 {
+		t.Errorf("second sync = %+v", res)
+	}
+	if repo.writes != writes+1 || len(repo.issues) != 2 {
+		t.Errorf("second sync made %d writes, repo has %d issues", repo.writes-writes, len(repo.issues))
+	}
+
+	if _, err := NewClient("", "", "acme"); err == nil {
+		t.Error("NewClient should reject a repository without an owner")
+	}
+}
@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Client pushes issues to a Jira site through the REST API (v2).
+type Client struct {
+	// BaseURL is the site URL, e.g. https://acme.atlassian.net.
+	BaseURL string
+
+	// User and Token authenticate requests. With a User, they are sent as
+	// basic auth, as Jira Cloud expects for API tokens; without one, Token
+	// is sent as a bearer personal access token (Jira Data Center).
+	User  string
+	Token string
+
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for the Jira site at baseURL.
+func NewClient(baseURL, user, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		User:       user,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SyncResult lists the PRD IDs whose issues were created or updated.
+type SyncResult struct {
+	Created []string
+	Updated []string
+}
+
+// Sync creates an issue for each entity without a Jira key and updates the
+// issues of entities that have one. The keys of created issues are recorded
+// in the entities' externalRefs, and a browse link template for the site is
+// added to the document's externalRefUrls if it has none, so the caller
+// should save doc afterwards — also when Sync fails part way, as the issues
+// created before the failure are recorded.
+func (c *Client) Sync(ctx context.Context, doc *prd.Document, opts Options) (SyncResult, error) {
+	var res SyncResult
+	if opts.ProjectKey == "" {
+		return res, fmt.Errorf("a Jira project key is required")
+	}
+	if doc.Metadata.ExternalRefURLs[common.ExternalSystemJira] == "" {
+		if doc.Metadata.ExternalRefURLs == nil {
+			doc.Metadata.ExternalRefURLs = map[string]string{}
+		}
+		doc.Metadata.ExternalRefURLs[common.ExternalSystemJira] = c.BaseURL + "/browse/{id}"
+	}
+	for _, is := range Issues(doc, opts) {
+		if is.Key != "" {
+			// Project and issue type are fixed once an issue exists.
+			fields := is.Fields
+			fields.Project, fields.IssueType = nil, nil
+			if err := c.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(is.Key), Issue{Fields: fields}, nil); err != nil {
+				return res, fmt.Errorf("updating %s for %s: %w", is.Key, is.SourceID, err)
+			}
+			res.Updated = append(res.Updated, is.SourceID)
+			continue
+		}
+		var created struct {
+			Key string `json:"key"`
+		}
+		if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", is, &created); err != nil {
+			return res, fmt.Errorf("creating issue for %s: %w", is.SourceID, err)
+		}
+		is.refs.Set(common.ExternalSystemJira, created.Key)
+		res.Created = append(res.Created, is.SourceID)
+	}
+	return res, nil
+}
+
+// do sends body as JSON and decodes the response into out, if given.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.User != "" {
+		req.SetBasicAuth(c.User, c.Token)
+	} else if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("jira returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
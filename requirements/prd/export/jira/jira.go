@@ -0,0 +1,269 @@
+// Package jira exports PRD user stories and functional requirements as Jira
+// issues, either as files for Jira's importers or directly through the Jira
+// REST API (v2).
+//
+// Each story or requirement becomes one issue. Priority is mapped to Jira's
+// default priority scheme, acceptance criteria and the roadmap phase are
+// written into the description, and tags become labels. The issue key is
+// recorded in the entity's externalRefs under "jira", so a later sync
+// updates the same issue instead of creating another.
+package jira
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Default issue types.
+const (
+	DefaultStoryType       = "Story"
+	DefaultRequirementType = "Task"
+)
+
+// Options configures how PRD entities map to issues.
+type Options struct {
+	// ProjectKey is the Jira project issues are created in, e.g. "PROJ".
+	ProjectKey string
+
+	// StoryType and RequirementType are the issue types used for user
+	// stories and functional requirements. They default to Story and Task.
+	StoryType       string
+	RequirementType string
+}
+
+// Issue is a Jira issue built from one PRD entity.
+type Issue struct {
+	// SourceID is the ID of the PRD entity, e.g. "US-001".
+	SourceID string `json:"-"`
+
+	// Key is the Jira key recorded in the entity's externalRefs, if the
+	// issue was created before.
+	Key string `json:"-"`
+
+	Fields Fields `json:"fields"`
+
+	refs *common.ExternalRefs
+}
+
+// Fields are the issue fields sent to Jira.
+type Fields struct {
+	Project     *Ref     `json:"project,omitempty"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	IssueType   *Ref     `json:"issuetype,omitempty"`
+	Priority    *Ref     `json:"priority,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// Ref refers to a Jira object by key or name.
+type Ref struct {
+	Key  string `json:"key,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Issues builds one issue per user story and functional requirement, in
+// document order. The issues keep a reference to doc so Sync can record
+// the keys of created issues.
+func Issues(doc *prd.Document, opts Options) []Issue {
+	if opts.StoryType == "" {
+		opts.StoryType = DefaultStoryType
+	}
+	if opts.RequirementType == "" {
+		opts.RequirementType = DefaultRequirementType
+	}
+	var project *Ref
+	if opts.ProjectKey != "" {
+		project = &Ref{Key: opts.ProjectKey}
+	}
+	phases := make(map[string]string, len(doc.Roadmap.Phases))
+	for _, p := range doc.Roadmap.Phases {
+		phases[p.ID] = p.Name
+	}
+
+	issues := make([]Issue, 0, len(doc.UserStories)+len(doc.Requirements.Functional))
+	for i := range doc.UserStories {
+		us := &doc.UserStories[i]
+		var desc strings.Builder
+		desc.WriteString(us.Story() + ".\n")
+		writeAcceptanceCriteria(&desc, us.AcceptanceCriteria)
+		writeFooter(&desc, us.ID, us.PhaseID, phases)
+		issues = append(issues, Issue{
+			SourceID: us.ID,
+			Key:      us.ExternalRefs[common.ExternalSystemJira],
+			Fields: Fields{
+				Project:     project,
+				Summary:     summary(us.Title, us.IWant),
+				Description: desc.String(),
+				IssueType:   &Ref{Name: opts.StoryType},
+				Priority:    priority(string(us.Priority)),
+				Labels:      labels(us.Tags, us.PhaseID),
+			},
+			refs: &us.ExternalRefs,
+		})
+	}
+	for i := range doc.Requirements.Functional {
+		r := &doc.Requirements.Functional[i]
+		var desc strings.Builder
+		desc.WriteString(r.Description + "\n")
+		writeAcceptanceCriteria(&desc, r.AcceptanceCriteria)
+		writeFooter(&desc, r.ID, r.PhaseID, phases)
+		issues = append(issues, Issue{
+			SourceID: r.ID,
+			Key:      r.ExternalRefs[common.ExternalSystemJira],
+			Fields: Fields{
+				Project:     project,
+				Summary:     summary(r.Title, r.Description),
+				Description: desc.String(),
+				IssueType:   &Ref{Name: opts.RequirementType},
+				Priority:    priority(string(r.Priority)),
+				Labels:      labels(r.Tags, r.PhaseID),
+			},
+			refs: &r.ExternalRefs,
+		})
+	}
+	return issues
+}
+
+// MarshalJSON returns issues in the request format of Jira's bulk create
+// endpoint, POST /rest/api/2/issue/bulk.
+func MarshalJSON(issues []Issue) ([]byte, error) {
+	data, err := json.MarshalIndent(struct {
+		IssueUpdates []Issue `json:"issueUpdates"`
+	}{issues}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling Jira issues: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// MarshalCSV returns issues as a CSV file for Jira's CSV importer. Labels
+// are written as repeated Labels columns, which the importer combines.
+// Issue Key is set for issues created before so they can be matched.
+func MarshalCSV(issues []Issue) ([]byte, error) {
+	maxLabels := 0
+	for _, is := range issues {
+		maxLabels = max(maxLabels, len(is.Fields.Labels))
+	}
+	header := []string{"PRD ID", "Issue Key", "Summary", "Issue Type", "Priority", "Description"}
+	for range maxLabels {
+		header = append(header, "Labels")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("writing CSV: %w", err)
+	}
+	for _, is := range issues {
+		row := []string{is.SourceID, is.Key, is.Fields.Summary, refName(is.Fields.IssueType), refName(is.Fields.Priority), is.Fields.Description}
+		labels := make([]string, maxLabels)
+		copy(labels, is.Fields.Labels)
+		if err := w.Write(append(row, labels...)); err != nil {
+			return nil, fmt.Errorf("writing CSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("writing CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// priority maps a MoSCoW or common priority to Jira's default priorities.
+func priority(p string) *Ref {
+	var name string
+	switch strings.ToLower(p) {
+	case "critical":
+		name = "Highest"
+	case "must", "high":
+		name = "High"
+	case "should", "medium":
+		name = "Medium"
+	case "could", "low":
+		name = "Low"
+	case "wont":
+		name = "Lowest"
+	default:
+		return nil
+	}
+	return &Ref{Name: name}
+}
+
+// labels converts tags and the phase ID to Jira labels, which cannot
+// contain spaces. The phase is labelled "phase:<id>".
+func labels(tags []string, phaseID string) []string {
+	var out []string
+	for _, t := range tags {
+		if l := label(t); l != "" {
+			out = append(out, l)
+		}
+	}
+	if l := label(phaseID); l != "" {
+		out = append(out, "phase:"+l)
+	}
+	return out
+}
+
+func label(s string) string {
+	return strings.Join(strings.Fields(s), "-")
+}
+
+// summary returns title, or the first line of fallback when there is no
+// title, limited to Jira's 255 character summary.
+func summary(title, fallback string) string {
+	s := strings.TrimSpace(title)
+	if s == "" {
+		s, _, _ = strings.Cut(strings.TrimSpace(fallback), "\n")
+	}
+	if r := []rune(s); len(r) > 255 {
+		s = string(r[:254]) + "…"
+	}
+	return s
+}
+
+// writeAcceptanceCriteria writes criteria as a Jira wiki markup list, with
+// Given/When/Then steps nested under each criterion.
+func writeAcceptanceCriteria(sb *strings.Builder, criteria []prd.AcceptanceCriterion) {
+	if len(criteria) == 0 {
+		return
+	}
+	sb.WriteString("\nh3. Acceptance Criteria\n")
+	for _, ac := range criteria {
+		sb.WriteString("# ")
+		if ac.ID != "" {
+			sb.WriteString(ac.ID + ": ")
+		}
+		sb.WriteString(ac.Description + "\n")
+		for _, step := range [][2]string{{"Given", ac.Given}, {"When", ac.When}, {"Then", ac.Then}} {
+			if step[1] != "" {
+				sb.WriteString("## *" + step[0] + "* " + step[1] + "\n")
+			}
+		}
+	}
+}
+
+func writeFooter(sb *strings.Builder, id, phaseID string, phases map[string]string) {
+	sb.WriteString("\n----\nPRD: " + id)
+	if phaseID != "" {
+		sb.WriteString(" · Phase: ")
+		if name := phases[phaseID]; name != "" {
+			sb.WriteString(name + " (" + phaseID + ")")
+		} else {
+			sb.WriteString(phaseID)
+		}
+	}
+	sb.WriteString("\n")
+}
+
+func refName(r *Ref) string {
+	if r == nil {
+		return ""
+	}
+	return r.Name
+}
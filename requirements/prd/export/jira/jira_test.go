@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func testDocument() *prd.Document {
+	doc := &prd.Document{
+		UserStories: []prd.UserStory{{
+			ID: "US-001", Title: "Sign in with SSO", AsA: "user", IWant: "to sign in with SSO", SoThat: "I have one password",
+			Priority: prd.PriorityHigh, PhaseID: "phase-1", Tags: []string{"auth", "single sign-on"},
+			AcceptanceCriteria: []prd.AcceptanceCriterion{{ID: "AC-1", Description: "Google login works", Given: "a Google account", Then: "the user is signed in"}},
+		}},
+		Requirements: prd.Requirements{Functional: []prd.FunctionalRequirement{{
+			ID: "FR-001", Title: "OIDC support", Description: "Support OIDC providers", Priority: prd.MoSCoWMust,
+			ExternalRefs: common.ExternalRefs{"jira": "PROJ-7"},
+		}}},
+	}
+	doc.Roadmap.Phases = append(doc.Roadmap.Phases, prd.Phase{ID: "phase-1", Name: "MVP"})
+	return doc
+}
+
+func TestIssues(t *testing.T) {
+	issues := Issues(testDocument(), Options{ProjectKey: "PROJ"})
+	if len(issues) != 2 {
+		t.Fatalf("len(issues) = %d, want 2", len(issues))
+	}
+	us := issues[0].Fields
+	if us.IssueType.Name != "Story" || us.Priority.Name != "High" || us.Project.Key != "PROJ" {
+		t.Errorf("story fields = %+v", us)
+	}
+	if got := strings.Join(us.Labels, ","); got != "auth,single-sign-on,phase:phase-1" {
+		t.Errorf("labels = %q", got)
+	}
+	for _, want := range []string{"h3. Acceptance Criteria", "# AC-1: Google login works", "## *Given* a Google account", "Phase: MVP (phase-1)"} {
+		if !strings.Contains(us.Description, want) {
+			t.Errorf("description missing %q:\n%s", want, us.Description)
+		}
+	}
+	if fr := issues[1]; fr.Key != "PROJ-7" || fr.Fields.IssueType.Name != "Task" {
+		t.Errorf("requirement issue = %+v", fr)
+	}
+
+	data, err := MarshalCSV(issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(string(data), "\n"); header != "PRD ID,Issue Key,Summary,Issue Type,Priority,Description,Labels,Labels,Labels" {
+		t.Errorf("CSV header = %q", header)
+	}
+}
+
+func TestSync(t *testing.T) {
+	var created, updated int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "me@acme.com" || token != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body Issue
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			created++
+			_, _ = w.Write([]byte(`{"id":"10001","key":"PROJ-8"}`))
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/PROJ-"):
+			if body.Fields.Project != nil || body.Fields.IssueType != nil {
+				http.Error(w, "project and issue type cannot change", http.StatusBadRequest)
+				return
+			}
+			updated++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	doc := testDocument()
+	c := NewClient(srv.URL, "me@acme.com", "secret")
+	res, err := c.Sync(context.Background(), doc, Options{ProjectKey: "PROJ"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 1 || updated != 1 || len(res.Created) != 1 || len(res.Updated) != 1 {
+		t.Errorf("created %d, updated %d, result %+v", created, updated, res)
+	}
+	if got := doc.UserStories[0].ExternalRefs["jira"]; got != "PROJ-8" {
+		t.Errorf("story jira ref = %q, want PROJ-8", got)
+	}
+	if got := doc.Metadata.ExternalRefURLs["jira"]; got != srv.URL+"/browse/{id}" {
+		t.Errorf("jira URL template = %q", got)
+	}
+
+	// A second sync updates both issues and creates none.
+	res, err = c.Sync(context.Background(), doc, Options{ProjectKey: "PROJ"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 1 || len(res.Updated) != 2 {
+		t.Errorf("second sync: created %d, result %+v", created, res)
+	}
+}
@@ -0,0 +1,159 @@
+package prd
+
+import "sort"
+
+// FeedbackSentiment is the overall tone of a piece of customer feedback.
+type FeedbackSentiment string
+
+const (
+	SentimentPositive FeedbackSentiment = "positive"
+	SentimentNeutral  FeedbackSentiment = "neutral"
+	SentimentNegative FeedbackSentiment = "negative"
+	SentimentMixed    FeedbackSentiment = "mixed"
+)
+
+// FeedbackRef references customer feedback held in another system, such as a
+// support ticket, sales call, or research interview.
+type FeedbackRef struct {
+	// Source is the system holding the feedback (e.g., "zendesk", "gong",
+	// "productboard", "interview").
+	Source string `json:"source"`
+
+	// ExternalID is the ticket, call, or interview identifier in Source.
+	ExternalID string `json:"externalId"`
+
+	// URL links to the feedback record.
+	URL string `json:"url,omitempty"`
+
+	// Summary paraphrases or quotes the feedback.
+	Summary string `json:"summary,omitempty"`
+
+	// Sentiment is the tone of the feedback.
+	Sentiment FeedbackSentiment `json:"sentiment,omitempty"`
+
+	// Customer identifies the account or segment, if shareable.
+	Customer string `json:"customer,omitempty"`
+
+	// Date is when the feedback was received (YYYY-MM-DD).
+	Date string `json:"date,omitempty"`
+}
+
+// RequirementFeedback summarizes the feedback linked to one requirement.
+type RequirementFeedback struct {
+	ID        string                    `json:"id"`
+	Title     string                    `json:"title"`
+	Kind      string                    `json:"kind"` // "functional" or "non-functional"
+	Priority  MoSCoW                    `json:"priority,omitempty"`
+	Count     int                       `json:"count"`
+	Sentiment map[FeedbackSentiment]int `json:"sentiment,omitempty"`
+}
+
+// FeedbackReport shows how requirements, problems, and personas are backed
+// by customer feedback.
+type FeedbackReport struct {
+	Requirements []RequirementFeedback `json:"requirements"`
+
+	// Unsupported lists the IDs of requirements with no linked feedback,
+	// must-have requirements first.
+	Unsupported []string `json:"unsupported,omitempty"`
+
+	ProblemFeedback int `json:"problemFeedback"`
+	PersonaFeedback int `json:"personaFeedback"`
+
+	// Sources counts feedback references by source system across the
+	// document.
+	Sources map[string]int `json:"sources,omitempty"`
+
+	// Sentiment counts feedback references by sentiment across the document.
+	Sentiment map[FeedbackSentiment]int `json:"sentiment,omitempty"`
+}
+
+// Coverage returns the fraction of requirements with linked feedback.
+func (r *FeedbackReport) Coverage() float64 {
+	if len(r.Requirements) == 0 {
+		return 0
+	}
+	return float64(len(r.Requirements)-len(r.Unsupported)) / float64(len(r.Requirements))
+}
+
+// FeedbackCoverage reports the customer feedback linked to each requirement
+// and lists requirements with none.
+func FeedbackCoverage(doc *Document) *FeedbackReport {
+	report := &FeedbackReport{
+		Sources:   map[string]int{},
+		Sentiment: map[FeedbackSentiment]int{},
+	}
+	tally := func(refs []FeedbackRef) map[FeedbackSentiment]int {
+		var counts map[FeedbackSentiment]int
+		for _, f := range refs {
+			report.Sources[f.Source]++
+			if f.Sentiment != "" {
+				report.Sentiment[f.Sentiment]++
+				if counts == nil {
+					counts = map[FeedbackSentiment]int{}
+				}
+				counts[f.Sentiment]++
+			}
+		}
+		return counts
+	}
+
+	for _, fr := range doc.Requirements.Functional {
+		report.Requirements = append(report.Requirements, RequirementFeedback{
+			ID: fr.ID, Title: fr.Title, Kind: "functional", Priority: fr.Priority,
+			Count: len(fr.Feedback), Sentiment: tally(fr.Feedback),
+		})
+	}
+	for _, nfr := range doc.Requirements.NonFunctional {
+		report.Requirements = append(report.Requirements, RequirementFeedback{
+			ID: nfr.ID, Title: nfr.Title, Kind: "non-functional", Priority: nfr.Priority,
+			Count: len(nfr.Feedback), Sentiment: tally(nfr.Feedback),
+		})
+	}
+
+	var unsupported []RequirementFeedback
+	for _, rf := range report.Requirements {
+		if rf.Count == 0 {
+			unsupported = append(unsupported, rf)
+		}
+	}
+	sort.SliceStable(unsupported, func(i, j int) bool {
+		return priorityRank(unsupported[i].Priority) < priorityRank(unsupported[j].Priority)
+	})
+	for _, rf := range unsupported {
+		report.Unsupported = append(report.Unsupported, rf.ID)
+	}
+
+	if doc.Problem != nil {
+		var count func(p *ProblemDefinition) int
+		count = func(p *ProblemDefinition) int {
+			tally(p.Feedback)
+			n := len(p.Feedback)
+			for i := range p.SecondaryProblems {
+				n += count(&p.SecondaryProblems[i])
+			}
+			return n
+		}
+		report.ProblemFeedback = count(doc.Problem)
+	}
+	for _, p := range doc.Personas {
+		tally(p.Feedback)
+		report.PersonaFeedback += len(p.Feedback)
+	}
+
+	return report
+}
+
+func priorityRank(p MoSCoW) int {
+	switch p {
+	case MoSCoWMust:
+		return 0
+	case MoSCoWShould:
+		return 1
+	case MoSCoWCould:
+		return 2
+	case MoSCoWWont:
+		return 4
+	}
+	return 3
+}
@@ -0,0 +1,59 @@
+package prd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeedbackCoverage(t *testing.T) {
+	doc := &Document{
+		Personas: []Persona{{ID: "p1", Name: "Admin", Feedback: []FeedbackRef{
+			{Source: "interview", ExternalID: "INT-4", Sentiment: SentimentNegative},
+		}}},
+		Problem: &ProblemDefinition{
+			ID:       "PROB-1",
+			Feedback: []FeedbackRef{{Source: "zendesk", ExternalID: "1001"}},
+			SecondaryProblems: []ProblemDefinition{
+				{ID: "PROB-2", Feedback: []FeedbackRef{{Source: "zendesk", ExternalID: "1002"}}},
+			},
+		},
+		Requirements: Requirements{
+			Functional: []FunctionalRequirement{
+				{ID: "FR-1", Title: "Export", Priority: MoSCoWCould},
+				{ID: "FR-2", Title: "SSO", Priority: MoSCoWMust, Feedback: []FeedbackRef{
+					{Source: "gong", ExternalID: "call-7", Sentiment: SentimentNegative},
+					{Source: "zendesk", ExternalID: "1003", Sentiment: SentimentPositive},
+				}},
+				{ID: "FR-3", Title: "Audit log", Priority: MoSCoWMust},
+			},
+			NonFunctional: []NonFunctionalRequirement{
+				{ID: "NFR-1", Title: "Latency", Priority: MoSCoWShould},
+			},
+		},
+	}
+
+	report := FeedbackCoverage(doc)
+
+	if want := []string{"FR-3", "NFR-1", "FR-1"}; !reflect.DeepEqual(report.Unsupported, want) {
+		t.Errorf("Unsupported = %v, want %v", report.Unsupported, want)
+	}
+	if got := report.Coverage(); got != 0.25 {
+		t.Errorf("Coverage() = %v, want 0.25", got)
+	}
+	fr2 := report.Requirements[1]
+	if fr2.Count != 2 || fr2.Sentiment[SentimentNegative] != 1 || fr2.Sentiment[SentimentPositive] != 1 {
+		t.Errorf("FR-2 feedback = %+v", fr2)
+	}
+	if report.Requirements[3].Kind != "non-functional" {
+		t.Errorf("NFR kind = %q", report.Requirements[3].Kind)
+	}
+	if report.ProblemFeedback != 2 || report.PersonaFeedback != 1 {
+		t.Errorf("ProblemFeedback = %d, PersonaFeedback = %d", report.ProblemFeedback, report.PersonaFeedback)
+	}
+	if want := map[string]int{"zendesk": 3, "gong": 1, "interview": 1}; !reflect.DeepEqual(report.Sources, want) {
+		t.Errorf("Sources = %v, want %v", report.Sources, want)
+	}
+	if report.Sentiment[SentimentNegative] != 2 {
+		t.Errorf("Sentiment = %v", report.Sentiment)
+	}
+}
@@ -75,6 +75,9 @@ func (d Document) filterByTagsInternal(tags []string, matchFunc tagMatchFunc) Do
 	// Filter Risks
 	filtered.Risks = filterSliceByTagsFunc(d.Risks, tags, func(r Risk) []string { return r.Tags }, matchFunc)
 
+	// Filter FAQs
+	filtered.FAQs = filterSliceByTagsFunc(d.FAQs, tags, func(f FAQEntry) []string { return f.Tags }, matchFunc)
+
 	// Extended sections (no tags, keep all)
 	filtered.Problem = d.Problem
 	filtered.Market = d.Market
@@ -0,0 +1,325 @@
+package prd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+)
+
+// Fix rules.
+const (
+	FixRuleMissingID      = "missing-id"
+	FixRuleMissingStatus  = "missing-status"
+	FixRuleTags           = "tags"
+	FixRulePersonaLinking = "persona-link"
+)
+
+// Fix is a mechanical correction for a finding, as a JSON Patch against
+// the document's JSON form.
+type Fix struct {
+	Rule    string          `json:"rule"`
+	Path    string          `json:"path"` // JSON pointer to the entity or field
+	Message string          `json:"message"`
+	Patch   jsonpatch.Patch `json:"patch"`
+}
+
+// FixPatch joins the patches of fixes into one.
+func FixPatch(fixes []Fix) jsonpatch.Patch {
+	patch := jsonpatch.Patch{}
+	for _, f := range fixes {
+		patch = append(patch, f.Patch...)
+	}
+	return patch
+}
+
+// Fixes returns the findings in the document that can be corrected
+// without judgment:
+//
+//   - missing-id: entities without an ID get the next free ID in the
+//     document's numbering, e.g. FR-004; the document itself gets a
+//     generated PRD-YYYY-DDD ID.
+//   - missing-status: the document status defaults to draft and phase
+//     statuses to planned.
+//   - tags: empty and duplicate tags are removed, and tags that only break
+//     the lowercase-hyphen format are rewritten, e.g. "Mobile App" to
+//     "mobile-app".
+//   - persona-link: user stories without a persona ID are linked to the
+//     one persona whose name or role matches their "as a" role.
+//
+// Where the JSON form always includes the changed field, the patch first
+// tests its current value, so a patch saved earlier fails instead of
+// overwriting later edits.
+func (d *Document) Fixes() []Fix {
+	f := &fixer{doc: d, ids: make(map[string]bool), personaIDs: make(map[int]string)}
+	f.collectIDs()
+	f.fixIDs()
+	f.fixStatuses()
+	f.fixTags()
+	f.linkPersonas()
+	return f.fixes
+}
+
+type fixer struct {
+	doc        *Document
+	ids        map[string]bool
+	personaIDs map[int]string // IDs assigned to personas by index
+	fixes      []Fix
+}
+
+func (f *fixer) add(rule, path, message string, ops ...jsonpatch.Operation) {
+	f.fixes = append(f.fixes, Fix{Rule: rule, Path: path, Message: message, Patch: ops})
+}
+
+func (f *fixer) collectIDs() {
+	d := f.doc
+	mark := func(id string) {
+		if id != "" {
+			f.ids[id] = true
+		}
+	}
+	for _, o := range d.Objectives.OKRs {
+		mark(o.Objective.ID)
+		for _, kr := range o.KeyResults {
+			mark(kr.ID)
+		}
+	}
+	for _, p := range d.Personas {
+		mark(p.ID)
+	}
+	for _, s := range d.UserStories {
+		mark(s.ID)
+	}
+	for _, r := range d.Requirements.Functional {
+		mark(r.ID)
+	}
+	for _, r := range d.Requirements.NonFunctional {
+		mark(r.ID)
+	}
+	for _, p := range d.Roadmap.Phases {
+		mark(p.ID)
+	}
+	for _, r := range d.Risks {
+		mark(r.ID)
+	}
+}
+
+// nextID returns the first unused prefix-NNN ID numbered after the highest
+// existing one, e.g. FR-004 after FR-003, and reserves it.
+func (f *fixer) nextID(prefix string, width int) string {
+	pattern := regexp.MustCompile(`^(?i)` + regexp.QuoteMeta(prefix) + `-(\d+)$`)
+	n := 0
+	for id := range f.ids {
+		if m := pattern.FindStringSubmatch(id); m != nil {
+			v, _ := strconv.Atoi(m[1])
+			n = max(n, v)
+		}
+	}
+	for {
+		n++
+		id := fmt.Sprintf("%s-%0*d", prefix, width, n)
+		if !f.ids[id] {
+			f.ids[id] = true
+			return id
+		}
+	}
+}
+
+// setField adds a fix setting an empty field. The field is tested to be
+// empty first, unless omitempty drops it from the JSON form.
+func (f *fixer) setField(rule, path, field string, value any, omitEmpty bool, message string) {
+	var ops []jsonpatch.Operation
+	if !omitEmpty {
+		ops = append(ops, jsonpatch.Test(path+"/"+field, ""))
+	}
+	f.add(rule, path, message, append(ops, jsonpatch.Add(path+"/"+field, value))...)
+}
+
+func (f *fixer) setID(path, label, id string, omitEmpty bool) {
+	f.setField(FixRuleMissingID, path, "id", id, omitEmpty, fmt.Sprintf("%s has no ID; assign %s", label, id))
+}
+
+func (f *fixer) fixIDs() {
+	d := f.doc
+	if d.Metadata.ID == "" {
+		f.setID("/metadata", "Document", GenerateID(), false)
+	}
+	for i, o := range d.Objectives.OKRs {
+		if o.Objective.ID == "" {
+			f.setID(jsonpatch.Pointer("objectives", "okrs", i, "objective"), fmt.Sprintf("Objective %q", o.Objective.Title), f.nextID("O", 1), true)
+		}
+		for j, kr := range o.KeyResults {
+			if kr.ID == "" {
+				f.setID(jsonpatch.Pointer("objectives", "okrs", i, "keyResults", j), fmt.Sprintf("Key result %q", kr.Title), f.nextID("KR", 1), true)
+			}
+		}
+	}
+	for i, p := range d.Personas {
+		if p.ID == "" {
+			f.personaIDs[i] = f.personaID(p)
+			f.setID(jsonpatch.Pointer("personas", i), fmt.Sprintf("Persona %q", p.Name), f.personaIDs[i], false)
+		}
+	}
+	for i, s := range d.UserStories {
+		if s.ID == "" {
+			f.setID(jsonpatch.Pointer("userStories", i), fmt.Sprintf("User story %q", s.Title), f.nextID("US", 3), false)
+		}
+	}
+	for i, r := range d.Requirements.Functional {
+		if r.ID == "" {
+			f.setID(jsonpatch.Pointer("requirements", "functional", i), fmt.Sprintf("Functional requirement %q", r.Title), f.nextID("FR", 3), false)
+		}
+	}
+	for i, r := range d.Requirements.NonFunctional {
+		if r.ID == "" {
+			f.setID(jsonpatch.Pointer("requirements", "nonFunctional", i), fmt.Sprintf("Non-functional requirement %q", r.Title), f.nextID("NFR", 3), false)
+		}
+	}
+	for i, p := range d.Roadmap.Phases {
+		if p.ID == "" {
+			f.setID(jsonpatch.Pointer("roadmap", "phases", i), fmt.Sprintf("Phase %q", p.Name), f.nextID("phase", 1), false)
+		}
+	}
+	for i, r := range d.Risks {
+		if r.ID == "" {
+			f.setID(jsonpatch.Pointer("risks", i), fmt.Sprintf("Risk %q", excerpt(r.Description)), f.nextID("RISK", 3), false)
+		}
+	}
+}
+
+// personaID derives "persona-<name>" from the persona's name, falling back
+// to numbering when the name is empty or the ID is taken.
+func (f *fixer) personaID(p Persona) string {
+	if slug := strings.Trim(toSlug(strings.ReplaceAll(p.Name, "_", " ")), "-"); slug != "" {
+		if id := "persona-" + slug; !f.ids[id] {
+			f.ids[id] = true
+			return id
+		}
+	}
+	return f.nextID("persona", 1)
+}
+
+func (f *fixer) fixStatuses() {
+	d := f.doc
+	if d.Metadata.Status == "" {
+		f.setField(FixRuleMissingStatus, "/metadata", "status", StatusDraft, false, "Document has no status; set it to draft")
+	}
+	for i, p := range d.Roadmap.Phases {
+		if p.Status == "" {
+			f.setField(FixRuleMissingStatus, jsonpatch.Pointer("roadmap", "phases", i), "status", PhaseStatusPlanned, true,
+				fmt.Sprintf("Phase %q has no status; set it to planned", p.Name))
+		}
+	}
+}
+
+func (f *fixer) fixTags() {
+	d := f.doc
+	f.fixTagList(jsonpatch.Pointer("metadata", "tags"), d.Metadata.Tags)
+	for i, p := range d.Personas {
+		f.fixTagList(jsonpatch.Pointer("personas", i, "tags"), p.Tags)
+	}
+	for i, s := range d.UserStories {
+		f.fixTagList(jsonpatch.Pointer("userStories", i, "tags"), s.Tags)
+	}
+	for i, r := range d.Requirements.Functional {
+		f.fixTagList(jsonpatch.Pointer("requirements", "functional", i, "tags"), r.Tags)
+	}
+	for i, r := range d.Requirements.NonFunctional {
+		f.fixTagList(jsonpatch.Pointer("requirements", "nonFunctional", i, "tags"), r.Tags)
+	}
+	for i, p := range d.Roadmap.Phases {
+		f.fixTagList(jsonpatch.Pointer("roadmap", "phases", i, "tags"), p.Tags)
+		for j, del := range p.Deliverables {
+			f.fixTagList(jsonpatch.Pointer("roadmap", "phases", i, "deliverables", j, "tags"), del.Tags)
+		}
+	}
+	for i, r := range d.Risks {
+		f.fixTagList(jsonpatch.Pointer("risks", i, "tags"), r.Tags)
+	}
+}
+
+// fixTagList replaces a tag list containing empty, duplicate, or
+// misformatted tags with its cleaned form.
+func (f *fixer) fixTagList(path string, tags []string) {
+	var cleaned []string
+	seen := make(map[string]bool)
+	var notes []string
+	for _, tag := range tags {
+		fixed := normalizeTag(tag)
+		switch {
+		case fixed == "":
+			notes = append(notes, fmt.Sprintf("remove empty tag %q", tag))
+			continue
+		case ValidateTag(fixed) != nil:
+			fixed = tag // needs a human decision; keep it
+		case fixed != tag:
+			notes = append(notes, fmt.Sprintf("rewrite %q as %q", tag, fixed))
+		}
+		if seen[fixed] {
+			notes = append(notes, fmt.Sprintf("remove duplicate tag %q", fixed))
+			continue
+		}
+		seen[fixed] = true
+		cleaned = append(cleaned, fixed)
+	}
+	if len(notes) == 0 {
+		return
+	}
+	if cleaned == nil {
+		cleaned = []string{}
+	}
+	f.add(FixRuleTags, path, "Tags: "+strings.Join(notes, "; "),
+		jsonpatch.Test(path, tags), jsonpatch.Replace(path, cleaned))
+}
+
+// normalizeTag lowercases tag and joins its words with hyphens.
+func normalizeTag(tag string) string {
+	words := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '\t'
+	})
+	return strings.Join(words, "-")
+}
+
+// linkPersonas sets the persona ID of user stories whose "as a" role names
+// exactly one persona by name or role, including personas given an ID by
+// fixIDs.
+func (f *fixer) linkPersonas() {
+	d := f.doc
+	for i, s := range d.UserStories {
+		if s.PersonaID != "" || strings.TrimSpace(s.AsA) == "" {
+			continue
+		}
+		role := strings.ToLower(strings.TrimSpace(s.AsA))
+		var match string
+		ambiguous := false
+		for j, p := range d.Personas {
+			id := p.ID
+			if id == "" {
+				id = f.personaIDs[j]
+			}
+			if id == "" {
+				continue
+			}
+			if strings.ToLower(p.Name) == role || strings.ToLower(p.Role) == role {
+				if match != "" {
+					ambiguous = true
+				}
+				match = id
+			}
+		}
+		if match == "" || ambiguous {
+			continue
+		}
+		f.setField(FixRulePersonaLinking, jsonpatch.Pointer("userStories", i), "personaId", match, false,
+			fmt.Sprintf("User story %s (as a %s) has no persona; link it to %s", storyLabel(s), s.AsA, match))
+	}
+}
+
+func storyLabel(s UserStory) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return fmt.Sprintf("%q", s.Title)
+}
@@ -0,0 +1,73 @@
+package prd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFixes(t *testing.T) {
+	doc := &Document{
+		Metadata: Metadata{ID: "PRD-1", Title: "Checkout", Tags: []string{"Mobile App", "", "mobile-app"}},
+		Personas: []Persona{{Name: "Guest Shopper", Role: "shopper"}},
+		UserStories: []UserStory{
+			{ID: "US-001", Title: "Pay", AsA: "Shopper"},
+			{Title: "Refund", AsA: "support agent"},
+		},
+		Requirements: Requirements{Functional: []FunctionalRequirement{
+			{ID: "FR-002", Title: "Cards"},
+			{Title: "Wallets", Tags: []string{"payments"}},
+		}},
+		Roadmap: Roadmap{Phases: []Phase{{ID: "phase-1", Name: "MVP"}}},
+	}
+
+	fixes := doc.Fixes()
+	rules := make(map[string]int)
+	for _, f := range fixes {
+		rules[f.Rule]++
+	}
+	want := map[string]int{FixRuleMissingID: 3, FixRuleMissingStatus: 2, FixRuleTags: 1, FixRulePersonaLinking: 1}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("rules = %v, want %v", rules, want)
+	}
+
+	if err := FixPatch(fixes).ApplyTo(doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Requirements.Functional[1].ID; got != "FR-003" {
+		t.Errorf("FR ID = %q, want FR-003", got)
+	}
+	if got := doc.UserStories[1].ID; got != "US-002" {
+		t.Errorf("story ID = %q, want US-002", got)
+	}
+	if got := doc.Personas[0].ID; got != "persona-guest-shopper" {
+		t.Errorf("persona ID = %q", got)
+	}
+	if got := doc.UserStories[0].PersonaID; got != "persona-guest-shopper" {
+		t.Errorf("story persona = %q", got)
+	}
+	if doc.UserStories[1].PersonaID != "" {
+		t.Error("story without a matching persona was linked")
+	}
+	if doc.Metadata.Status != StatusDraft || doc.Roadmap.Phases[0].Status != PhaseStatusPlanned {
+		t.Errorf("statuses = %q, %q", doc.Metadata.Status, doc.Roadmap.Phases[0].Status)
+	}
+	if !reflect.DeepEqual(doc.Metadata.Tags, []string{"mobile-app"}) {
+		t.Errorf("tags = %q", doc.Metadata.Tags)
+	}
+
+	if fixes := doc.Fixes(); len(fixes) != 0 {
+		t.Errorf("fixed document still has fixes: %+v", fixes)
+	}
+}
+
+func TestFixPatchStale(t *testing.T) {
+	doc := &Document{Metadata: Metadata{ID: "PRD-1"}}
+	patch := FixPatch(doc.Fixes())
+	doc.Metadata.Status = StatusInReview
+	if err := patch.ApplyTo(doc); err == nil {
+		t.Error("patch applied over a later edit")
+	}
+	if doc.Metadata.Status != StatusInReview {
+		t.Errorf("status = %q", doc.Metadata.Status)
+	}
+}
@@ -0,0 +1,39 @@
+package prd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grokify/structured-plan/lenient"
+)
+
+// FuzzParseDocument checks that any input which decodes, strictly or
+// leniently, can be validated, scored, and rendered without panicking.
+func FuzzParseDocument(f *testing.F) {
+	// Seeds are kept small; the full example documents stall the mutator.
+	f.Add([]byte(`{"metadata": {"id": "PRD-1", "createdAt": "2025-01-01"}, "userStories": [{"id": "US-1", "storyPoints": "3"}]}`))
+	f.Add([]byte(`{"requirements": {"functional": [{"id": "FR-1", "category": ""}]}, "roadmap": {"phases": [{"id": "P1", "progress": 150}]}}`))
+	f.Add([]byte(`{"appendices": [{"id": "A", "contentTable": {"headers": [], "rows": [["x"]]}}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		exercise := func(doc *Document) {
+			_ = doc.ToMarkdown(DefaultMarkdownOptions())
+			_ = Validate(doc)
+			_ = Score(doc)
+			_ = doc.CheckCompleteness()
+		}
+
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err == nil {
+			exercise(&doc)
+		}
+
+		var lenientDoc Document
+		if _, err := lenient.Unmarshal(data, &lenientDoc); err == nil {
+			exercise(&lenientDoc)
+			if _, err := json.Marshal(&lenientDoc); err != nil {
+				t.Fatalf("recovered document does not marshal: %v", err)
+			}
+		}
+	})
+}
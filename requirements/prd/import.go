@@ -0,0 +1,1063 @@
+package prd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+// ImportReport tells how the sections of an imported markdown PRD were
+// mapped to the document.
+type ImportReport struct {
+	// Mapped lists the headings whose content was mapped to document
+	// fields.
+	Mapped []string `json:"mapped"`
+
+	// Unmapped lists the headings, or content under mapped headings, that
+	// could not be mapped. Unmapped top-level sections are kept as custom
+	// sections with their markdown as content, so nothing is lost and they
+	// can be moved into structured fields by hand.
+	Unmapped []UnmappedSection `json:"unmapped,omitempty"`
+
+	// Ambiguous lists the top-level headings that matched more than one
+	// section, with the section chosen.
+	Ambiguous []AmbiguousHeading `json:"ambiguous,omitempty"`
+
+	// Fixes are the mechanical fixes applied after mapping, such as
+	// assigned IDs and persona links.
+	Fixes []Fix `json:"fixes,omitempty"`
+}
+
+// AmbiguousHeading is a top-level heading that matched more than one PRD
+// section.
+type AmbiguousHeading struct {
+	Heading    string         `json:"heading"`
+	Line       int            `json:"line"`
+	Candidates []ImportTarget `json:"candidates"`
+	Chosen     ImportTarget   `json:"chosen"`
+}
+
+// ImportTarget is a PRD section that a top-level heading can be mapped to.
+type ImportTarget string
+
+// Import targets, in the order headings are matched against them.
+const (
+	ImportSummary       ImportTarget = "summary"
+	ImportNonFunctional ImportTarget = "non-functional"
+	ImportRequirements  ImportTarget = "requirements"
+	ImportUserStories   ImportTarget = "user-stories"
+	ImportPersonas      ImportTarget = "personas"
+	ImportOutOfScope    ImportTarget = "out-of-scope"
+	ImportRoadmap       ImportTarget = "roadmap"
+	ImportRisks         ImportTarget = "risks"
+	ImportAssumptions   ImportTarget = "assumptions"
+	ImportGlossary      ImportTarget = "glossary"
+	ImportProblem       ImportTarget = "problem"
+	ImportSolution      ImportTarget = "solution"
+	ImportGoals         ImportTarget = "goals"
+	ImportCustom        ImportTarget = "custom"
+)
+
+// importRules recognize top-level headings by their normalized text.
+var importRules = []struct {
+	target ImportTarget
+	match  func(key string) bool
+}{
+	{ImportSummary, func(k string) bool {
+		return hasAnyWord(k, "executive summary", "tl dr") || k == "summary" || k == "overview"
+	}},
+	{ImportNonFunctional, func(k string) bool {
+		return hasAnyWord(k, "non functional", "nonfunctional", "nfr", "nfrs", "quality attributes")
+	}},
+	{ImportRequirements, func(k string) bool { return hasAnyWord(k, "functional requirements", "requirements", "features") }},
+	{ImportUserStories, func(k string) bool { return hasAnyWord(k, "user stories", "stories") }},
+	{ImportPersonas, func(k string) bool { return hasAnyWord(k, "personas", "persona", "users", "target users") }},
+	{ImportOutOfScope, func(k string) bool { return hasAnyWord(k, "out of scope", "non goals", "nongoals") }},
+	{ImportRoadmap, func(k string) bool {
+		return hasAnyWord(k, "roadmap", "phases", "milestones", "timeline", "release plan")
+	}},
+	{ImportRisks, func(k string) bool { return hasAnyWord(k, "risks", "risk assessment", "risk") }},
+	{ImportAssumptions, func(k string) bool { return hasAnyWord(k, "assumptions", "constraints", "dependencies") }},
+	{ImportGlossary, func(k string) bool { return hasAnyWord(k, "glossary", "terminology") || k == "definitions" }},
+	{ImportProblem, func(k string) bool { return hasAnyWord(k, "problem", "problem statement", "background") }},
+	{ImportSolution, func(k string) bool { return hasAnyWord(k, "solution", "proposed solution") }},
+	{ImportGoals, func(k string) bool {
+		return hasAnyWord(k, "goals", "objectives", "objectives and goals", "success metrics", "outcomes")
+	}},
+}
+
+// ImportTargets returns the sections a heading can be mapped to.
+func ImportTargets() []ImportTarget {
+	targets := make([]ImportTarget, 0, len(importRules)+1)
+	for _, r := range importRules {
+		targets = append(targets, r.target)
+	}
+	return append(targets, ImportCustom)
+}
+
+// MatchImportTargets returns the sections a top-level heading matches, in
+// match order. "Non-Functional Requirements" matches only non-functional,
+// not requirements.
+func MatchImportTargets(heading string) []ImportTarget {
+	key := normalizeKey(heading)
+	var targets []ImportTarget
+	for _, r := range importRules {
+		if r.match(key) {
+			if r.target == ImportRequirements && len(targets) > 0 && targets[len(targets)-1] == ImportNonFunctional {
+				continue
+			}
+			targets = append(targets, r.target)
+		}
+	}
+	return targets
+}
+
+// ImportOptions control how headings are mapped by ImportMarkdownWithOptions.
+type ImportOptions struct {
+	// Mapping maps top-level headings to sections, overriding detection.
+	// Headings are compared case-insensitively, ignoring punctuation and
+	// section numbers.
+	Mapping map[string]ImportTarget
+
+	// Resolve, if set, is called for top-level headings that match no
+	// section or more than one, with the matching sections as candidates.
+	// It returns the section to map the heading to; an empty target keeps
+	// the default, the first candidate or a custom section.
+	Resolve func(heading string, candidates []ImportTarget) ImportTarget
+}
+
+// UnmappedSection is markdown content the importer could not map.
+type UnmappedSection struct {
+	Heading string `json:"heading"`
+	Line    int    `json:"line"` // 1-based line of the heading
+	Reason  string `json:"reason"`
+}
+
+// mdSection is a markdown heading with the lines up to the next heading
+// and its subsections.
+type mdSection struct {
+	level    int
+	title    string
+	line     int
+	body     []string
+	children []*mdSection
+}
+
+// ImportMarkdown parses a markdown PRD into a Document. It maps YAML front
+// matter, the metadata table under the title, and sections recognized by
+// their headings: executive summary, problem, solution, goals, personas,
+// user stories, functional and non-functional requirements, out of scope,
+// risks, assumptions, constraints, and glossary. Tables are read by their
+// column headers, user stories also from "As a ..., I want ... so that ..."
+// bullets. Heading numbers, {#id} anchors, and glossary links are ignored,
+// so markdown generated by ToMarkdown imports back.
+//
+// After mapping, the document's mechanical fixes are applied: missing IDs
+// are assigned, the status defaults to draft, and user stories are linked
+// to personas by role.
+func ImportMarkdown(src []byte) (*Document, *ImportReport) {
+	return ImportMarkdownWithOptions(src, ImportOptions{})
+}
+
+// ImportMarkdownWithOptions is ImportMarkdown with heading overrides and a
+// resolver for ambiguous or unrecognized headings.
+func ImportMarkdownWithOptions(src []byte, opts ImportOptions) (*Document, *ImportReport) {
+	mapping := make(map[string]ImportTarget, len(opts.Mapping))
+	for heading, target := range opts.Mapping {
+		mapping[normalizeKey(cleanHeading(heading))] = target
+	}
+	doc := &Document{
+		Metadata:         Metadata{Authors: []Person{}},
+		ExecutiveSummary: ExecutiveSummary{ExpectedOutcomes: []string{}},
+		Objectives:       Objectives{OKRs: []OKR{}},
+		Personas:         []Persona{},
+		UserStories:      []UserStory{},
+		Requirements:     Requirements{Functional: []FunctionalRequirement{}, NonFunctional: []NonFunctionalRequirement{}},
+		Roadmap:          Roadmap{Phases: []Phase{}},
+	}
+	im := &importer{doc: doc, report: &ImportReport{Mapped: []string{}}, mapping: mapping, resolve: opts.Resolve}
+	lines := strings.Split(strings.ReplaceAll(common.UnlinkGlossaryMarkdown(string(src)), "\r\n", "\n"), "\n")
+	offset := im.frontMatter(lines)
+	root := parseSections(lines[offset:], offset)
+
+	top := root.children
+	if len(top) == 1 && top[0].level == 1 {
+		title := top[0]
+		if im.doc.Metadata.Title == "" {
+			im.doc.Metadata.Title = title.title
+		}
+		im.metadataTable(title)
+		if intro := mdParagraphs(title.body); intro != "" {
+			im.custom(&mdSection{title: "Introduction", line: title.line, body: []string{intro}}, "text under the title")
+		}
+		top = title.children
+	} else if len(root.body) > 0 {
+		im.metadataTable(root)
+	}
+	for _, s := range top {
+		im.section(s)
+	}
+
+	if im.doc.Metadata.ID == "" && im.doc.Metadata.Title != "" {
+		im.doc.Metadata.ID = "prd-" + slugify(im.doc.Metadata.Title)
+	}
+	fixes := im.doc.Fixes()
+	if err := FixPatch(fixes).ApplyTo(im.doc); err == nil {
+		im.report.Fixes = fixes
+	}
+	return im.doc, im.report
+}
+
+type importer struct {
+	doc     *Document
+	report  *ImportReport
+	mapping map[string]ImportTarget
+	resolve func(heading string, candidates []ImportTarget) ImportTarget
+}
+
+func (im *importer) mapped(s *mdSection) {
+	im.report.Mapped = append(im.report.Mapped, s.title)
+}
+
+func (im *importer) unmapped(s *mdSection, reason string) {
+	im.report.Unmapped = append(im.report.Unmapped, UnmappedSection{Heading: s.title, Line: s.line, Reason: reason})
+}
+
+// frontMatter reads title, author, version, status, and date from YAML
+// front matter and returns the number of lines it spans.
+func (im *importer) frontMatter(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			return i + 1
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		im.metadataField(strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"'`))
+	}
+	return 0
+}
+
+// metadataTable reads a Field | Value table under the title.
+func (im *importer) metadataTable(s *mdSection) {
+	for _, t := range mdTables(s.body) {
+		for _, row := range t {
+			im.metadataField(row.get("field"), row.get("value"))
+		}
+	}
+}
+
+func (im *importer) metadataField(key, value string) {
+	m := &im.doc.Metadata
+	if value == "" {
+		return
+	}
+	switch normalizeKey(key) {
+	case "id":
+		m.ID = value
+	case "title":
+		m.Title = value
+	case "version":
+		m.Version = value
+	case "status":
+		m.Status = Status(strings.ToLower(value))
+	case "author", "authors", "author s", "owner":
+		if len(m.Authors) == 0 {
+			for _, name := range strings.Split(value, ",") {
+				name, _, _ = strings.Cut(strings.TrimSpace(name), " (")
+				if name != "" {
+					m.Authors = append(m.Authors, Person{Name: name})
+				}
+			}
+		}
+	case "tags":
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				m.Tags = append(m.Tags, tag)
+			}
+		}
+	case "date", "created":
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			m.CreatedAt = t
+			if m.UpdatedAt.IsZero() {
+				m.UpdatedAt = t
+			}
+		}
+	case "updated":
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			m.UpdatedAt = t
+		}
+	}
+}
+
+// section maps a top-level section by its heading.
+func (im *importer) section(s *mdSection) {
+	key := normalizeKey(s.title)
+	if key == "table of contents" || key == "contents" {
+		return
+	}
+	target, ok := im.mapping[key]
+	if !ok {
+		target = im.target(s)
+	}
+
+	switch target {
+	case ImportSummary:
+		im.summary(s)
+	case ImportNonFunctional:
+		im.nonFunctional(s, "")
+	case ImportRequirements:
+		im.requirements(s)
+	case ImportUserStories:
+		im.userStories(s)
+	case ImportPersonas:
+		im.personas(s)
+	case ImportOutOfScope:
+		im.doc.OutOfScope = append(im.doc.OutOfScope, mdBullets(s.allLines())...)
+		im.mapped(s)
+	case ImportRoadmap:
+		im.roadmap(s)
+	case ImportRisks:
+		im.risks(s)
+	case ImportAssumptions:
+		im.assumptions(s)
+	case ImportGlossary:
+		im.glossary(s)
+	case ImportProblem:
+		im.doc.ExecutiveSummary.ProblemStatement = joinText(im.doc.ExecutiveSummary.ProblemStatement, s.text())
+		im.mapped(s)
+	case ImportSolution:
+		im.doc.ExecutiveSummary.ProposedSolution = joinText(im.doc.ExecutiveSummary.ProposedSolution, s.text())
+		im.mapped(s)
+	case ImportGoals:
+		im.outcomes(s)
+	default:
+		if _, ok := im.mapping[key]; ok {
+			im.custom(s, "mapped to a custom section")
+		} else {
+			im.custom(s, "no matching PRD section")
+		}
+	}
+}
+
+// target detects the section a heading maps to, asking the resolver when
+// the heading matches no section or more than one.
+func (im *importer) target(s *mdSection) ImportTarget {
+	candidates := MatchImportTargets(s.title)
+	var target ImportTarget
+	if len(candidates) != 1 && im.resolve != nil {
+		target = im.resolve(s.title, candidates)
+	}
+	if target == "" {
+		target = ImportCustom
+		if len(candidates) > 0 {
+			target = candidates[0]
+		}
+	}
+	if len(candidates) > 1 {
+		im.report.Ambiguous = append(im.report.Ambiguous, AmbiguousHeading{
+			Heading: s.title, Line: s.line, Candidates: candidates, Chosen: target,
+		})
+	}
+	return target
+}
+
+// custom keeps s as a custom section with its markdown as content.
+func (im *importer) custom(s *mdSection, reason string) {
+	im.doc.CustomSections = append(im.doc.CustomSections, CustomSection{
+		ID:      slugify(s.title),
+		Title:   s.title,
+		Content: s.text(),
+	})
+	im.unmapped(s, reason+"; kept as a custom section")
+}
+
+func (im *importer) summary(s *mdSection) {
+	es := &im.doc.ExecutiveSummary
+	im.mapped(s)
+	if text := mdParagraphs(s.body); text != "" {
+		es.ProposedSolution = joinText(es.ProposedSolution, text)
+	}
+	for _, c := range s.children {
+		key := normalizeKey(c.title)
+		switch {
+		case hasAnyWord(key, "problem", "problem statement", "background"):
+			es.ProblemStatement = joinText(es.ProblemStatement, c.text())
+		case hasAnyWord(key, "solution", "proposed solution", "approach"):
+			es.ProposedSolution = joinText(es.ProposedSolution, c.text())
+		case hasAnyWord(key, "outcomes", "expected outcomes", "goals"):
+			es.ExpectedOutcomes = append(es.ExpectedOutcomes, bulletsOrText(c)...)
+		case hasAnyWord(key, "audience", "target audience"):
+			es.TargetAudience = joinText(es.TargetAudience, c.text())
+		case hasAnyWord(key, "value proposition", "value"):
+			es.ValueProposition = joinText(es.ValueProposition, c.text())
+		default:
+			im.unmapped(c, "unknown executive summary subsection")
+			continue
+		}
+		im.mapped(c)
+	}
+}
+
+func (im *importer) outcomes(s *mdSection) {
+	items := bulletsOrText(s)
+	if len(items) == 0 {
+		return
+	}
+	im.doc.ExecutiveSummary.ExpectedOutcomes = append(im.doc.ExecutiveSummary.ExpectedOutcomes, items...)
+	im.mapped(s)
+}
+
+func (im *importer) personas(s *mdSection) {
+	im.mapped(s)
+	for _, t := range mdTables(s.body) {
+		for _, row := range t {
+			if name := row.get("name", "persona"); name != "" {
+				im.doc.Personas = append(im.doc.Personas, Persona{
+					Name: name, Role: row.get("role"), Description: row.get("description"),
+					Goals: []string{}, PainPoints: []string{},
+				})
+			}
+		}
+	}
+	for _, c := range s.children {
+		p := Persona{Name: c.title}
+		if name, ok := strings.CutSuffix(p.Name, "(Primary)"); ok {
+			p.Name, p.IsPrimary = strings.TrimSpace(name), true
+		}
+		for _, t := range mdTables(c.body) {
+			for _, row := range t {
+				field, value := row.get("attribute", "field"), row.get("description", "value")
+				switch normalizeKey(field) {
+				case "role":
+					p.Role = value
+				case "description", "background":
+					p.Description = value
+				case "technical proficiency", "proficiency":
+					p.TechnicalProficiency = TechnicalProficiency(strings.ToLower(value))
+				}
+			}
+		}
+		lists := mdLabeledLists(c.body)
+		p.Goals = append([]string{}, lists["goals"]...)
+		p.PainPoints = append(append([]string{}, lists["pain points"]...), lists["frustrations"]...)
+		p.Behaviors = lists["behaviors"]
+		p.Motivations = lists["motivations"]
+		if p.Description == "" {
+			p.Description = mdParagraphs(c.body)
+		}
+		im.doc.Personas = append(im.doc.Personas, p)
+	}
+}
+
+var storyPattern = regexp.MustCompile(`(?i)^as an? (.+?),? I (?:want|need|can) (?:to )?(.+?)(?:,? so that (.+?))?\.?$`)
+
+// parseStory parses "As a <role>, I want <action> so that <benefit>".
+func parseStory(text string) (UserStory, bool) {
+	m := storyPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return UserStory{}, false
+	}
+	return UserStory{Title: capitalize(m[2]), AsA: m[1], IWant: m[2], SoThat: m[3], AcceptanceCriteria: []AcceptanceCriterion{}}, true
+}
+
+func (im *importer) userStories(s *mdSection) {
+	im.mapped(s)
+	var add func(sec *mdSection)
+	add = func(sec *mdSection) {
+		found := false
+		for _, t := range mdTables(sec.body) {
+			for _, row := range t {
+				text := row.get("story", "user story", "description")
+				us, ok := parseStory(text)
+				if !ok {
+					if text == "" {
+						continue
+					}
+					us = UserStory{Title: row.get("title", "story"), IWant: text, AcceptanceCriteria: []AcceptanceCriterion{}}
+				}
+				us.ID = row.get("id")
+				if t := row.get("title"); t != "" {
+					us.Title = t
+				}
+				us.Priority = Priority(strings.ToLower(row.get("priority")))
+				us.PhaseID = row.get("phase")
+				im.doc.UserStories = append(im.doc.UserStories, us)
+				found = true
+			}
+		}
+		for _, b := range mdBullets(sec.body) {
+			id, text := splitID(b)
+			if us, ok := parseStory(text); ok {
+				us.ID = id
+				im.doc.UserStories = append(im.doc.UserStories, us)
+				found = true
+			}
+		}
+		if sec != s && !found && len(sec.children) == 0 {
+			im.unmapped(sec, "no user story table or \"As a ...\" bullets")
+		}
+		for _, c := range sec.children {
+			add(c)
+		}
+	}
+	add(s)
+}
+
+func (im *importer) requirements(s *mdSection) {
+	im.mapped(s)
+	im.functional(s, "")
+	for _, c := range s.children {
+		key := normalizeKey(c.title)
+		switch {
+		case hasAnyWord(key, "non functional", "nonfunctional", "nfr", "nfrs", "non functional requirements"):
+			im.nonFunctional(c, "")
+		case hasAnyWord(key, "functional", "functional requirements"):
+			im.functional(c, "")
+			for _, gc := range c.children {
+				im.functional(gc, gc.title)
+			}
+			im.mapped(c)
+		default:
+			if !im.functional(c, c.title) {
+				im.unmapped(c, "no requirement table or bullets")
+				continue
+			}
+			im.mapped(c)
+		}
+	}
+}
+
+// functional adds the requirements in the tables and bullets of s, with
+// the given category, and reports whether there were any.
+func (im *importer) functional(s *mdSection, category string) bool {
+	n := len(im.doc.Requirements.Functional)
+	for _, t := range mdTables(s.body) {
+		for _, row := range t {
+			r := FunctionalRequirement{
+				ID:          row.get("id"),
+				Title:       row.get("title", "requirement", "name"),
+				Description: row.get("description"),
+				Category:    category,
+				Priority:    MoSCoW(strings.ToLower(row.get("priority"))),
+				PhaseID:     row.get("phase"),
+
+				UserStoryIDs:       []string{},
+				AcceptanceCriteria: []AcceptanceCriterion{},
+			}
+			if r.Title == "" {
+				r.Title = r.Description
+			}
+			if r.Title != "" {
+				im.doc.Requirements.Functional = append(im.doc.Requirements.Functional, r)
+			}
+		}
+	}
+	for _, b := range mdBullets(s.body) {
+		id, text := splitID(b)
+		title, desc, _ := strings.Cut(text, " - ")
+		im.doc.Requirements.Functional = append(im.doc.Requirements.Functional, FunctionalRequirement{
+			ID: id, Title: strings.TrimSpace(title), Description: strings.TrimSpace(desc), Category: category,
+			UserStoryIDs: []string{}, AcceptanceCriteria: []AcceptanceCriterion{},
+		})
+	}
+	return len(im.doc.Requirements.Functional) > n
+}
+
+func (im *importer) nonFunctional(s *mdSection, category NFRCategory) {
+	if category == "" {
+		im.mapped(s)
+	}
+	for _, t := range mdTables(s.body) {
+		for _, row := range t {
+			r := NonFunctionalRequirement{
+				ID:          row.get("id"),
+				Category:    category,
+				Title:       row.get("title", "requirement", "name"),
+				Description: row.get("description"),
+				Metric:      row.get("metric"),
+				Target:      row.get("target"),
+				Priority:    MoSCoW(strings.ToLower(row.get("priority"))),
+				PhaseID:     row.get("phase"),
+			}
+			if c := row.get("category"); c != "" {
+				r.Category = nfrCategory(c)
+			}
+			if r.Title != "" {
+				im.doc.Requirements.NonFunctional = append(im.doc.Requirements.NonFunctional, r)
+			}
+		}
+	}
+	for _, b := range mdBullets(s.body) {
+		id, text := splitID(b)
+		im.doc.Requirements.NonFunctional = append(im.doc.Requirements.NonFunctional, NonFunctionalRequirement{
+			ID: id, Category: category, Title: text,
+		})
+	}
+	for _, c := range s.children {
+		im.nonFunctional(c, nfrCategory(c.title))
+	}
+}
+
+func (im *importer) roadmap(s *mdSection) {
+	im.mapped(s)
+	n := len(im.doc.Roadmap.Phases)
+	var walk func(sec *mdSection)
+	walk = func(sec *mdSection) {
+		for _, c := range sec.children {
+			key := normalizeKey(c.title)
+			if hasAnyWord(key, "overview", "phase details", "swimlane", "summary") {
+				// Grouping headings and the generated swimlane view
+				walk(c)
+				continue
+			}
+			im.doc.Roadmap.Phases = append(im.doc.Roadmap.Phases, importPhase(c))
+		}
+	}
+	walk(s)
+	if len(im.doc.Roadmap.Phases) == n {
+		for _, b := range mdBullets(s.body) {
+			id, name := splitID(b)
+			im.doc.Roadmap.Phases = append(im.doc.Roadmap.Phases, Phase{
+				ID: id, Name: name, Type: PhaseTypeGeneric,
+				Goals: []string{}, Deliverables: []Deliverable{}, SuccessCriteria: []string{},
+			})
+		}
+	}
+}
+
+var inlineLabel = regexp.MustCompile(`^\*\*(.+?):\*\*\s+(.+)$`)
+
+// importPhase reads a phase from its heading, e.g. "phase-1: Foundation",
+// a "**Type:**" line, goal and success criteria lists, and a deliverables
+// table or list.
+func importPhase(s *mdSection) Phase {
+	p := Phase{Name: s.title, Type: PhaseTypeGeneric, Deliverables: []Deliverable{}}
+	if m := leadingID.FindStringSubmatch(s.title); m != nil {
+		p.ID, p.Name = m[1], strings.TrimSpace(m[2])
+	}
+	for _, l := range s.body {
+		if m := inlineLabel.FindStringSubmatch(strings.TrimSpace(l)); m != nil {
+			switch normalizeKey(m[1]) {
+			case "type":
+				p.Type = PhaseType(strings.ToLower(m[2]))
+			case "status":
+				p.Status = PhaseStatus(strings.ToLower(m[2]))
+			}
+		}
+	}
+	lists := mdLabeledLists(s.body)
+	p.Goals = append([]string{}, lists["goals"]...)
+	p.SuccessCriteria = append([]string{}, lists["success criteria"]...)
+	for _, t := range mdTables(s.body) {
+		for _, row := range t {
+			if title := row.get("title", "deliverable"); title != "" {
+				p.Deliverables = append(p.Deliverables, Deliverable{
+					ID: row.get("id"), Title: title, Description: row.get("description"),
+					Type: DeliverableType(strings.ToLower(row.get("type"))), Status: DeliverableStatus(strings.ToLower(row.get("status"))),
+				})
+			}
+		}
+	}
+	for _, d := range lists["deliverables"] {
+		id, title := splitID(d)
+		p.Deliverables = append(p.Deliverables, Deliverable{ID: id, Title: title, Type: DeliverableFeature})
+	}
+	return p
+}
+
+// nfrCategory returns the NFR category named by a heading, e.g.
+// "Disaster Recovery" is disaster_recovery.
+func nfrCategory(title string) NFRCategory {
+	return NFRCategory(strings.ReplaceAll(normalizeKey(title), " ", "_"))
+}
+
+func (im *importer) risks(s *mdSection) {
+	im.mapped(s)
+	for _, t := range mdTables(s.allLines()) {
+		for _, row := range t {
+			r := common.Risk{
+				ID:          row.get("id"),
+				Description: row.get("risk", "description"),
+				Probability: common.RiskProbability(strings.ToLower(row.get("probability", "likelihood"))),
+				Impact:      common.RiskImpact(strings.ToLower(row.get("impact"))),
+				Mitigation:  row.get("mitigation"),
+				Owner:       row.get("owner"),
+				Status:      common.RiskStatus(strings.ToLower(row.get("status"))),
+			}
+			if r.Description != "" {
+				im.doc.Risks = append(im.doc.Risks, r)
+			}
+		}
+	}
+	for _, b := range mdBullets(s.allLines()) {
+		id, text := splitID(b)
+		desc, mitigation, _ := strings.Cut(text, " - ")
+		im.doc.Risks = append(im.doc.Risks, common.Risk{ID: id, Description: strings.TrimSpace(desc), Mitigation: strings.TrimSpace(mitigation)})
+	}
+}
+
+func (im *importer) assumptions(s *mdSection) {
+	if im.doc.Assumptions == nil {
+		im.doc.Assumptions = &AssumptionsConstraints{}
+	}
+	im.mapped(s)
+	im.assumptionsPart(s, normalizeKey(s.title))
+	for _, c := range s.children {
+		key := normalizeKey(c.title)
+		if !hasAnyWord(key, "assumptions", "constraints", "dependencies") {
+			im.unmapped(c, "unknown assumptions and constraints subsection")
+			continue
+		}
+		im.assumptionsPart(c, key)
+		im.mapped(c)
+	}
+}
+
+// assumptionsPart adds the tables of s as assumptions, constraints, or
+// dependencies by their columns, and its list items by its heading key:
+// constraints or dependencies if the key names only those, else
+// assumptions.
+func (im *importer) assumptionsPart(s *mdSection, key string) {
+	ac := im.doc.Assumptions
+	for _, t := range mdTables(s.body) {
+		for _, row := range t {
+			switch {
+			case row["constraint"] != "":
+				ac.Constraints = append(ac.Constraints, common.Constraint{
+					ID: row.get("id"), Type: common.ConstraintType(strings.ToLower(row.get("type"))),
+					Description: row.get("constraint"), Impact: row.get("impact"), Mitigation: row.get("mitigation"),
+				})
+			case row["dependency"] != "":
+				ac.Dependencies = append(ac.Dependencies, Dependency{
+					ID: row.get("id"), Name: row.get("dependency"), Team: row.get("team"), Description: row.get("description"),
+				})
+			case row.get("assumption", "description") != "":
+				ac.Assumptions = append(ac.Assumptions, common.Assumption{
+					ID: row.get("id"), Description: row.get("assumption", "description"), Risk: row.get("risk if invalid", "risk"),
+				})
+			}
+		}
+	}
+	for _, b := range mdBullets(s.body) {
+		id, text := splitID(b)
+		switch {
+		case hasAnyWord(key, "assumptions"):
+			ac.Assumptions = append(ac.Assumptions, common.Assumption{ID: id, Description: text})
+		case hasAnyWord(key, "constraints"):
+			ac.Constraints = append(ac.Constraints, common.Constraint{ID: id, Description: text})
+		default:
+			ac.Dependencies = append(ac.Dependencies, Dependency{ID: id, Name: text})
+		}
+	}
+}
+
+func (im *importer) glossary(s *mdSection) {
+	im.mapped(s)
+	for _, t := range mdTables(s.allLines()) {
+		for _, row := range t {
+			term := row.get("term")
+			acronym := ""
+			if name, abbr, ok := strings.Cut(term, " ("); ok {
+				term, acronym = name, strings.TrimSuffix(abbr, ")")
+			}
+			if term != "" {
+				im.doc.Glossary = append(im.doc.Glossary, common.GlossaryTerm{Term: term, Acronym: acronym, Definition: row.get("definition", "description")})
+			}
+		}
+	}
+	for _, b := range mdBullets(s.allLines()) {
+		if term, def, ok := strings.Cut(b, ":"); ok {
+			im.doc.Glossary = append(im.doc.Glossary, common.GlossaryTerm{Term: strings.TrimSpace(term), Definition: strings.TrimSpace(def)})
+		}
+	}
+}
+
+// parseSections splits lines into a tree of sections by ATX heading,
+// ignoring headings in fenced code blocks. The root holds the lines before
+// the first heading. offset is the line number of lines[0], less one.
+func parseSections(lines []string, offset int) *mdSection {
+	root := &mdSection{}
+	stack := []*mdSection{root}
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		level := headingLevel(line)
+		if fenced || level == 0 {
+			cur := stack[len(stack)-1]
+			cur.body = append(cur.body, line)
+			continue
+		}
+		s := &mdSection{level: level, title: cleanHeading(line[level:]), line: offset + i + 1}
+		for len(stack) > 1 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, s)
+		stack = append(stack, s)
+	}
+	return root
+}
+
+func headingLevel(line string) int {
+	n := 0
+	for n < len(line) && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || n == len(line) || line[n] != ' ' {
+		return 0
+	}
+	return n
+}
+
+var (
+	headingAnchor = regexp.MustCompile(`\s*\{#[^}]*\}\s*$`)
+	headingNumber = regexp.MustCompile(`^\d+(\.\d+)*\.?\s+`)
+)
+
+// cleanHeading strips a heading's {#id} anchor, number, and emphasis.
+func cleanHeading(s string) string {
+	s = headingAnchor.ReplaceAllString(strings.TrimSpace(s), "")
+	s = headingNumber.ReplaceAllString(s, "")
+	return strings.TrimSpace(strings.Trim(s, "*_"))
+}
+
+// allLines returns the body of s and its subsections, headings included.
+func (s *mdSection) allLines() []string {
+	lines := append([]string(nil), s.body...)
+	for _, c := range s.children {
+		lines = append(lines, strings.Repeat("#", c.level)+" "+c.title)
+		lines = append(lines, c.allLines()...)
+	}
+	return lines
+}
+
+// text returns the markdown of s and its subsections, without the
+// horizontal rules that separate generated sections.
+func (s *mdSection) text() string {
+	var kept []string
+	for _, l := range s.allLines() {
+		if strings.TrimSpace(l) != "---" {
+			kept = append(kept, l)
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// mdRow is a table row keyed by normalized column header.
+type mdRow map[string]string
+
+// get returns the first non-empty cell of the given columns.
+func (r mdRow) get(columns ...string) string {
+	for _, c := range columns {
+		if v := r[c]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// mdTables returns the pipe tables in lines as rows keyed by header.
+// Emphasis is stripped from cells.
+func mdTables(lines []string) [][]mdRow {
+	var tables [][]mdRow
+	for i := 0; i+1 < len(lines); i++ {
+		if !isTableLine(lines[i]) || !isSeparatorLine(lines[i+1]) {
+			continue
+		}
+		header := tableCells(lines[i])
+		for j := range header {
+			header[j] = normalizeKey(header[j])
+		}
+		var rows []mdRow
+		for i += 2; i < len(lines) && isTableLine(lines[i]); i++ {
+			row := mdRow{}
+			for j, cell := range tableCells(lines[i]) {
+				if j < len(header) && header[j] != "" {
+					row[header[j]] = strings.TrimSpace(strings.Trim(cell, "*_"))
+				}
+			}
+			rows = append(rows, row)
+		}
+		tables = append(tables, rows)
+	}
+	return tables
+}
+
+func isTableLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+func isSeparatorLine(line string) bool {
+	return isTableLine(line) && strings.Trim(strings.TrimSpace(line), "|-: ") == ""
+}
+
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+var bulletPattern = regexp.MustCompile(`^\s{0,3}(?:[-*+]|\d+[.)])\s+(.*)$`)
+
+// mdBullets returns the top-level list items in lines.
+func mdBullets(lines []string) []string {
+	var items []string
+	for _, l := range lines {
+		if m := bulletPattern.FindStringSubmatch(l); m != nil && strings.TrimSpace(m[1]) != "" {
+			items = append(items, strings.TrimSpace(m[1]))
+		}
+	}
+	return items
+}
+
+var listLabel = regexp.MustCompile(`^\*\*(.+?):?\*\*:?\s*$`)
+
+// mdLabeledLists returns the lists that follow bold labels such as
+// "**Goals:**", keyed by normalized label.
+func mdLabeledLists(lines []string) map[string][]string {
+	lists := map[string][]string{}
+	label := ""
+	for _, l := range lines {
+		if m := listLabel.FindStringSubmatch(strings.TrimSpace(l)); m != nil {
+			label = normalizeKey(m[1])
+			continue
+		}
+		if m := bulletPattern.FindStringSubmatch(l); m != nil && label != "" {
+			lists[label] = append(lists[label], strings.TrimSpace(m[1]))
+		}
+	}
+	return lists
+}
+
+// mdParagraphs returns the text in lines outside tables, lists, labels,
+// and horizontal rules.
+func mdParagraphs(lines []string) string {
+	var paras []string
+	var cur []string
+	flush := func() {
+		if len(cur) > 0 {
+			paras = append(paras, strings.Join(cur, " "))
+			cur = nil
+		}
+	}
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		switch {
+		case t == "" || t == "---":
+			flush()
+		case isTableLine(t) || bulletPattern.MatchString(l) || listLabel.MatchString(t):
+			flush()
+		default:
+			cur = append(cur, t)
+		}
+	}
+	flush()
+	return strings.Join(paras, "\n\n")
+}
+
+// bulletsOrText returns the list items of s, or its text as one item.
+func bulletsOrText(s *mdSection) []string {
+	if items := mdBullets(s.allLines()); len(items) > 0 {
+		return items
+	}
+	if text := s.text(); text != "" {
+		return []string{text}
+	}
+	return nil
+}
+
+var leadingID = regexp.MustCompile(`^\**([A-Za-z]+-[A-Za-z0-9-]*\d)\**[:.]?\s+(.*)$`)
+
+// splitID splits a leading ID such as "FR-001:" or "**US-2**" from a list
+// item.
+func splitID(item string) (id, text string) {
+	if m := leadingID.FindStringSubmatch(item); m != nil {
+		return m[1], strings.TrimSpace(m[2])
+	}
+	return "", item
+}
+
+var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalizeKey lowercases s and collapses everything but letters and
+// digits to single spaces, e.g. "Non-Functional Requirements" becomes
+// "non functional requirements".
+func normalizeKey(s string) string {
+	return strings.TrimSpace(nonAlnum.ReplaceAllString(strings.ToLower(s), " "))
+}
+
+func slugify(s string) string {
+	return strings.ReplaceAll(normalizeKey(s), " ", "-")
+}
+
+// hasAnyWord reports whether key equals one of the phrases or contains it
+// as whole words.
+func hasAnyWord(key string, phrases ...string) bool {
+	padded := " " + key + " "
+	for _, p := range phrases {
+		if key == p || strings.Contains(padded, " "+p+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	return a + "\n\n" + b
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// String summarizes the report, e.g. for a CLI.
+func (r *ImportReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Mapped %d section(s)", len(r.Mapped)))
+	if len(r.Fixes) > 0 {
+		sb.WriteString(fmt.Sprintf(", applied %d fix(es)", len(r.Fixes)))
+	}
+	sb.WriteString("\n")
+	if len(r.Ambiguous) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d heading(s) matched more than one section:\n", len(r.Ambiguous)))
+		for _, a := range r.Ambiguous {
+			candidates := make([]string, len(a.Candidates))
+			for i, c := range a.Candidates {
+				candidates[i] = string(c)
+			}
+			sb.WriteString(fmt.Sprintf("  - line %d: %s (%s; mapped to %s)\n", a.Line, a.Heading, strings.Join(candidates, ", "), a.Chosen))
+		}
+	}
+	if len(r.Unmapped) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d section(s) could not be mapped:\n", len(r.Unmapped)))
+		for _, u := range r.Unmapped {
+			sb.WriteString(fmt.Sprintf("  - line %d: %s (%s)\n", u.Line, u.Heading, u.Reason))
+		}
+	}
+	return sb.String()
+}
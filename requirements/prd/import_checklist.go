@@ -0,0 +1,201 @@
+package prd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ChecklistImportReport tells which checklist items were added to a
+// document as functional requirements.
+type ChecklistImportReport struct {
+	// Added lists the IDs of the requirements added.
+	Added []string `json:"added,omitempty"`
+
+	// Unprioritized lists the added requirements whose item had no
+	// priority marker and that were given the default priority.
+	Unprioritized []string `json:"unprioritized,omitempty"`
+
+	// Skipped lists the items whose title is already a functional
+	// requirement of the document.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ChecklistDefaultPriority is the priority of items without a marker.
+const ChecklistDefaultPriority = MoSCoWShould
+
+var (
+	// checklistItem matches a bullet or numbered list item with an optional
+	// task checkbox: indentation, then the text.
+	checklistItem = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.*\S)\s*$`)
+
+	// priorityMarker matches a priority in parentheses or brackets, such as
+	// "(P0)" or "[must]", or one leading the text, such as "P1:".
+	priorityMarker = regexp.MustCompile(`(?i)[(\[]\s*(p[0-9]|must|should|could|won'?t|critical|high|medium|low)\s*[)\]]|^(p[0-9])\s*[:\-–—]\s*`)
+
+	// titleSeparator splits an item into a title and a description.
+	titleSeparator = regexp.MustCompile(`:\s+|\s+[-–—]\s+`)
+)
+
+// checklistPriorities maps priority markers to MoSCoW priorities.
+var checklistPriorities = map[string]MoSCoW{
+	"p0": MoSCoWMust, "critical": MoSCoWMust, "must": MoSCoWMust, "high": MoSCoWMust,
+	"p1": MoSCoWShould, "should": MoSCoWShould, "medium": MoSCoWShould,
+	"p2": MoSCoWCould, "could": MoSCoWCould, "low": MoSCoWCould,
+	"wont": MoSCoWWont, "won't": MoSCoWWont,
+}
+
+// ImportChecklist adds the items of a Markdown checklist or bulleted
+// feature list to the functional requirements of d as drafts, numbered
+// after the document's highest ID.
+//
+// Each top-level item becomes a requirement, and the items nested under
+// it its acceptance criteria. Text before a colon or dash, such as
+// "Guest checkout: buy without an account", is the title and the rest the
+// description. A marker such as "(P0)", "[must]", or "(high)", or a
+// leading "P1:", sets the priority: P0, critical, and high are must; P1
+// and medium should; P2 and low could; P3 and beyond won't. Items without
+// one get ChecklistDefaultPriority. The nearest heading above an item is
+// its category. Checkboxes, other lines, and code blocks are ignored, as
+// are items whose title is already a requirement.
+func (d *Document) ImportChecklist(r io.Reader) (*ChecklistImportReport, error) {
+	f := &fixer{doc: d, ids: map[string]bool{}}
+	f.collectIDs()
+	existing := map[string]bool{}
+	for _, fr := range d.Requirements.Functional {
+		existing[strings.ToLower(fr.Title)] = true
+	}
+
+	report := &ChecklistImportReport{}
+	var current *FunctionalRequirement
+	var currentIndent int
+	skipIndent := -1 // indentation of a skipped item, whose nested items are skipped too
+	var category, fence string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		d.Requirements.Functional = append(d.Requirements.Functional, *current)
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.ReplaceAll(scanner.Text(), "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			continue
+		}
+		if level := headingLevel(line); level > 0 {
+			flush()
+			category = strings.TrimSpace(strings.TrimRight(line[level:], "# "))
+			continue
+		}
+		m := checklistItem.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent, text := len(m[1]), m[2]
+
+		if skipIndent >= 0 && indent > skipIndent {
+			continue
+		}
+		skipIndent = -1
+		if current != nil && indent > currentIndent {
+			current.AcceptanceCriteria = append(current.AcceptanceCriteria, AcceptanceCriterion{
+				ID:          fmt.Sprintf("AC-%d", len(current.AcceptanceCriteria)+1),
+				Description: strings.TrimSpace(priorityMarker.ReplaceAllString(text, "")),
+			})
+			continue
+		}
+		flush()
+
+		priority, marked := checklistPriority(text)
+		title, description := splitChecklistItem(strings.TrimSpace(priorityMarker.ReplaceAllString(text, "")))
+		if title == "" {
+			continue
+		}
+		if existing[strings.ToLower(title)] {
+			report.Skipped = append(report.Skipped, title)
+			skipIndent = indent
+			continue
+		}
+		existing[strings.ToLower(title)] = true
+
+		id := f.nextID("FR", 3)
+		current = &FunctionalRequirement{
+			ID:          id,
+			Title:       title,
+			Description: description,
+			Category:    category,
+			Priority:    priority,
+
+			UserStoryIDs:       []string{},
+			AcceptanceCriteria: []AcceptanceCriterion{},
+		}
+		currentIndent = indent
+		report.Added = append(report.Added, id)
+		if !marked {
+			report.Unprioritized = append(report.Unprioritized, id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading checklist: %w", err)
+	}
+	flush()
+	return report, nil
+}
+
+// checklistPriority returns the priority of the first marker in text, or
+// the default priority and false if there is none.
+func checklistPriority(text string) (MoSCoW, bool) {
+	m := priorityMarker.FindStringSubmatch(text)
+	if m == nil {
+		return ChecklistDefaultPriority, false
+	}
+	key := strings.ToLower(m[1] + m[2])
+	if p, ok := checklistPriorities[key]; ok {
+		return p, true
+	}
+	// P3 and beyond
+	return MoSCoWWont, true
+}
+
+// splitChecklistItem splits an item into a title and a description at
+// the first colon or spaced dash, if the title is no longer than a
+// heading would be. Bold or italic around the title is removed.
+func splitChecklistItem(text string) (title, description string) {
+	title = text
+	if loc := titleSeparator.FindStringIndex(text); loc != nil && loc[0] > 0 && loc[0] <= 80 {
+		title, description = text[:loc[0]], strings.TrimSpace(text[loc[1]:])
+	}
+	title = strings.TrimSpace(strings.Trim(strings.TrimSpace(title), "*_"))
+	return title, description
+}
+
+// String summarizes the report, e.g. for a CLI.
+func (r *ChecklistImportReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Added %d functional requirement(s)", len(r.Added)))
+	if len(r.Skipped) > 0 {
+		sb.WriteString(fmt.Sprintf(", skipped %d already in the PRD", len(r.Skipped)))
+	}
+	sb.WriteString("\n")
+	if len(r.Unprioritized) > 0 {
+		sb.WriteString(fmt.Sprintf("No priority marker, set to %s: %s\n", ChecklistDefaultPriority, strings.Join(r.Unprioritized, ", ")))
+	}
+	for _, title := range r.Skipped {
+		sb.WriteString(fmt.Sprintf("  - skipped %q\n", title))
+	}
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportChecklist(t *testing.T) {
+	doc := csvDoc()
+	src := `# Launch checklist
+
+## Checkout
+
+- [ ] **Guest checkout**: buy without an account (P0)
+  - [ ] Email is required
+  - [x] Account can be created after purchase
+- [x] Cart (P1)
+  - Items persist across sessions
+* Saved payment methods [could]
+
+## Admin
+
+1. P3: Bulk order export
+2) Order search - find orders by email or ID (high)
+
+` + "```" + `
+- not an item
+` + "```" + `
+`
+	report, err := doc.ImportChecklist(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(report.Added, ","); got != "FR-002,FR-003,FR-004,FR-005" {
+		t.Errorf("Added = %s", got)
+	}
+	if got := strings.Join(report.Skipped, ","); got != "Cart" {
+		t.Errorf("Skipped = %s, want Cart", got)
+	}
+	if len(report.Unprioritized) != 0 {
+		t.Errorf("Unprioritized = %v", report.Unprioritized)
+	}
+
+	fr := doc.Requirements.Functional
+	if len(fr) != 5 {
+		t.Fatalf("functional = %+v", fr)
+	}
+	guest := fr[1]
+	if guest.Title != "Guest checkout" || guest.Description != "buy without an account" ||
+		guest.Priority != MoSCoWMust || guest.Category != "Checkout" {
+		t.Errorf("guest checkout = %+v", guest)
+	}
+	if len(guest.AcceptanceCriteria) != 2 || guest.AcceptanceCriteria[1].ID != "AC-2" ||
+		guest.AcceptanceCriteria[1].Description != "Account can be created after purchase" {
+		t.Errorf("acceptance criteria = %+v", guest.AcceptanceCriteria)
+	}
+	if fr[2].Title != "Saved payment methods" || fr[2].Priority != MoSCoWCould {
+		t.Errorf("saved payment methods = %+v", fr[2])
+	}
+	if fr[3].Title != "Bulk order export" || fr[3].Priority != MoSCoWWont || fr[3].Category != "Admin" {
+		t.Errorf("bulk export = %+v", fr[3])
+	}
+	if fr[4].Title != "Order search" || fr[4].Description != "find orders by email or ID" || fr[4].Priority != MoSCoWMust {
+		t.Errorf("order search = %+v", fr[4])
+	}
+}
+
+func TestImportChecklistDefaultPriority(t *testing.T) {
+	doc := &Document{}
+	report, err := doc.ImportChecklist(strings.NewReader("- Dark mode\n- Export to PDF\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(report.Unprioritized, ","); got != "FR-001,FR-002" {
+		t.Errorf("Unprioritized = %s", got)
+	}
+	if doc.Requirements.Functional[0].Priority != ChecklistDefaultPriority {
+		t.Errorf("priority = %s", doc.Requirements.Functional[0].Priority)
+	}
+	// Empty lists marshal as [], which the schema requires, not null
+	if r := doc.Requirements.Functional[1]; r.UserStoryIDs == nil || r.AcceptanceCriteria == nil {
+		t.Errorf("nil lists in %+v", r)
+	}
+}
@@ -0,0 +1,422 @@
+package prd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CSVSection is the requirements section that CSV rows are imported into.
+type CSVSection string
+
+// CSV import sections.
+const (
+	CSVFunctional    CSVSection = "functional"
+	CSVNonFunctional CSVSection = "non-functional"
+)
+
+// ParseCSVSection parses a section name: functional (fr) or
+// non-functional (nonfunctional, nfr).
+func ParseCSVSection(s string) (CSVSection, error) {
+	switch strings.ReplaceAll(normalizeKey(s), " ", "") {
+	case "functional", "fr", "frs":
+		return CSVFunctional, nil
+	case "nonfunctional", "nfr", "nfrs":
+		return CSVNonFunctional, nil
+	}
+	return "", fmt.Errorf("unknown section %q (expected functional or non-functional)", s)
+}
+
+// NFRCategories returns the non-functional requirement categories.
+func NFRCategories() []NFRCategory {
+	return []NFRCategory{
+		NFRPerformance, NFRScalability, NFRReliability, NFRAvailability, NFRSecurity,
+		NFRMultiTenancy, NFRObservability, NFRMaintainability, NFRUsability, NFRCompatibility,
+		NFRCompliance, NFRDisasterRecovery, NFRCostEfficiency, NFRPortability, NFRTestability,
+		NFRExtensibility, NFRInteroperability, NFRLocalization, NFRAccessibility,
+	}
+}
+
+// CSVImportReport tells which CSV rows were merged into a document and
+// which were rejected.
+type CSVImportReport struct {
+	Section CSVSection `json:"section"`
+
+	// Added and Updated list the IDs of the requirements added and of the
+	// existing requirements changed by a row with their ID.
+	Added   []string `json:"added,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+
+	// Rejected lists the rows that were not imported, with the reason.
+	Rejected []CSVRejection `json:"rejected,omitempty"`
+
+	// Ignored lists the column headers that map to no field.
+	Ignored []string `json:"ignored,omitempty"`
+}
+
+// CSVRejection is a CSV row that was not imported. Row is the line of the
+// row in the file, counting the header as line 1.
+type CSVRejection struct {
+	Row    int    `json:"row"`
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// csvColumns maps requirement fields to the normalized column headers they
+// are read from. Headers are also matched with their spaces removed, so
+// the JSON field names of the document work as headers.
+var csvColumns = map[CSVSection]map[string][]string{
+	CSVFunctional: {
+		"id":                 {"id", "requirement id", "req id", "key"},
+		"title":              {"title", "summary", "name", "requirement"},
+		"description":        {"description", "details"},
+		"category":           {"category"},
+		"priority":           {"priority", "moscow"},
+		"phase":              {"phase", "phase id", "release"},
+		"userStoryIds":       {"user story ids", "user stories", "stories"},
+		"acceptanceCriteria": {"acceptance criteria", "criteria"},
+		"dependencies":       {"dependencies", "depends on"},
+		"tags":               {"tags", "labels"},
+		"notes":              {"notes", "comments"},
+	},
+	CSVNonFunctional: {
+		"id":                {"id", "requirement id", "req id", "key"},
+		"title":             {"title", "summary", "name", "requirement"},
+		"description":       {"description", "details"},
+		"category":          {"category"},
+		"metric":            {"metric"},
+		"target":            {"target"},
+		"measurementMethod": {"measurement method", "measurement"},
+		"priority":          {"priority", "moscow"},
+		"phase":             {"phase", "phase id", "release"},
+		"currentBaseline":   {"current baseline", "baseline"},
+		"notes":             {"notes", "comments"},
+	},
+}
+
+// ImportCSV merges the rows of a CSV file into the functional or
+// non-functional requirements of d. Columns are mapped by their header,
+// such as "Title", "Priority", or "Phase", ignoring case and punctuation.
+// A row whose ID matches a requirement of the section updates the fields
+// of its non-empty cells; other rows add a requirement, numbered after the
+// document's highest ID if the row has none.
+//
+// Rows are rejected, and the document left unchanged by them, if they have
+// no title for a new requirement, a priority other than must, should,
+// could, or won't, an unknown NFR category, or a phase or user story that
+// is not in the document. Phases may be given by ID or name. List cells,
+// such as tags and user story IDs, are separated by semicolons, commas, or
+// line breaks; acceptance criteria by semicolons or line breaks.
+func (d *Document) ImportCSV(r io.Reader, section CSVSection) (*CSVImportReport, error) {
+	columns, ok := csvColumns[section]
+	if !ok {
+		return nil, fmt.Errorf("unknown section %q", section)
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+
+	lookup := map[string]string{}
+	for field, aliases := range columns {
+		lookup[strings.ToLower(field)] = field
+		for _, a := range aliases {
+			lookup[strings.ReplaceAll(a, " ", "")] = field
+		}
+	}
+	report := &CSVImportReport{Section: section}
+	fieldOf := map[int]string{}
+	seen := map[string]bool{}
+	for i, h := range header {
+		field := lookup[strings.ReplaceAll(normalizeKey(strings.TrimPrefix(h, "\ufeff")), " ", "")]
+		if field == "" || seen[field] {
+			report.Ignored = append(report.Ignored, h)
+			continue
+		}
+		fieldOf[i] = field
+		seen[field] = true
+	}
+	if !seen["title"] && !seen["id"] {
+		return nil, fmt.Errorf("CSV header has neither a title nor an ID column")
+	}
+
+	im := &csvImporter{doc: d, fixer: &fixer{doc: d, ids: map[string]bool{}}}
+	im.fixer.collectIDs()
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		row := map[string]string{}
+		for i, v := range record {
+			if f, ok := fieldOf[i]; ok {
+				row[f] = strings.TrimSpace(v)
+			}
+		}
+		if allEmpty(row) {
+			continue
+		}
+		var id string
+		var added bool
+		if section == CSVFunctional {
+			id, added, err = im.functional(row)
+		} else {
+			id, added, err = im.nonFunctional(row)
+		}
+		switch {
+		case err != nil:
+			report.Rejected = append(report.Rejected, CSVRejection{Row: line, ID: row["id"], Reason: err.Error()})
+		case added:
+			report.Added = append(report.Added, id)
+		default:
+			report.Updated = append(report.Updated, id)
+		}
+	}
+	return report, nil
+}
+
+type csvImporter struct {
+	doc   *Document
+	fixer *fixer
+}
+
+func (im *csvImporter) functional(row map[string]string) (string, bool, error) {
+	reqs := im.doc.Requirements.Functional
+	i := slices.IndexFunc(reqs, func(r FunctionalRequirement) bool { return row["id"] != "" && r.ID == row["id"] })
+	r := FunctionalRequirement{UserStoryIDs: []string{}, AcceptanceCriteria: []AcceptanceCriterion{}}
+	if i >= 0 {
+		r = reqs[i]
+	} else if row["title"] == "" {
+		return "", false, fmt.Errorf("no title")
+	}
+
+	priority, err := im.priority(row["priority"])
+	if err != nil {
+		return "", false, err
+	}
+	phase, err := im.phase(row["phase"])
+	if err != nil {
+		return "", false, err
+	}
+	stories := splitList(row["userStoryIds"], ";,\n")
+	for _, id := range stories {
+		if !slices.ContainsFunc(im.doc.UserStories, func(s UserStory) bool { return s.ID == id }) {
+			return "", false, fmt.Errorf("unknown user story %q", id)
+		}
+	}
+
+	if i < 0 {
+		if r.ID, err = im.newID(row["id"], "FR", "functional"); err != nil {
+			return "", false, err
+		}
+	}
+
+	setString(&r.Title, row["title"])
+	setString(&r.Description, row["description"])
+	setString(&r.Category, row["category"])
+	setString(&r.PhaseID, phase)
+	setString(&r.Notes, row["notes"])
+	if priority != "" {
+		r.Priority = priority
+	}
+	if len(stories) > 0 {
+		r.UserStoryIDs = stories
+	}
+	if deps := splitList(row["dependencies"], ";,\n"); len(deps) > 0 {
+		r.Dependencies = deps
+	}
+	if tags := splitList(row["tags"], ";,\n"); len(tags) > 0 {
+		r.Tags = tags
+	}
+	if criteria := splitList(row["acceptanceCriteria"], ";\n"); len(criteria) > 0 {
+		r.AcceptanceCriteria = mergeCriteria(r.ID, r.AcceptanceCriteria, criteria)
+	}
+
+	if i >= 0 {
+		im.doc.Requirements.Functional[i] = r
+		return r.ID, false, nil
+	}
+	im.doc.Requirements.Functional = append(im.doc.Requirements.Functional, r)
+	return r.ID, true, nil
+}
+
+func (im *csvImporter) nonFunctional(row map[string]string) (string, bool, error) {
+	reqs := im.doc.Requirements.NonFunctional
+	i := slices.IndexFunc(reqs, func(r NonFunctionalRequirement) bool { return row["id"] != "" && r.ID == row["id"] })
+	var r NonFunctionalRequirement
+	if i >= 0 {
+		r = reqs[i]
+	} else if row["title"] == "" {
+		return "", false, fmt.Errorf("no title")
+	} else if row["category"] == "" {
+		return "", false, fmt.Errorf("no category")
+	}
+
+	priority, err := im.priority(row["priority"])
+	if err != nil {
+		return "", false, err
+	}
+	phase, err := im.phase(row["phase"])
+	if err != nil {
+		return "", false, err
+	}
+	if row["category"] != "" {
+		category := nfrCategory(row["category"])
+		if !slices.Contains(NFRCategories(), category) {
+			return "", false, fmt.Errorf("unknown category %q", row["category"])
+		}
+		r.Category = category
+	}
+
+	setString(&r.Title, row["title"])
+	setString(&r.Description, row["description"])
+	setString(&r.Metric, row["metric"])
+	setString(&r.Target, row["target"])
+	setString(&r.MeasurementMethod, row["measurementMethod"])
+	setString(&r.PhaseID, phase)
+	setString(&r.CurrentBaseline, row["currentBaseline"])
+	setString(&r.Notes, row["notes"])
+	if priority != "" {
+		r.Priority = priority
+	}
+
+	if i >= 0 {
+		im.doc.Requirements.NonFunctional[i] = r
+		return r.ID, false, nil
+	}
+	if r.ID, err = im.newID(row["id"], "NFR", "non-functional"); err != nil {
+		return "", false, err
+	}
+	im.doc.Requirements.NonFunctional = append(im.doc.Requirements.NonFunctional, r)
+	return r.ID, true, nil
+}
+
+// newID returns the ID of a new requirement: id, or the next ID with the
+// prefix if id is empty. The ID is then taken.
+func (im *csvImporter) newID(id, prefix, section string) (string, error) {
+	if id == "" {
+		id = im.fixer.nextID(prefix, 3)
+	} else if im.fixer.ids[id] {
+		return "", fmt.Errorf("ID %s is already used outside the %s requirements", id, section)
+	}
+	im.fixer.ids[id] = true
+	return id, nil
+}
+
+// priority parses a MoSCoW priority, such as "Must" or "Won't have".
+func (im *csvImporter) priority(s string) (MoSCoW, error) {
+	if s == "" {
+		return "", nil
+	}
+	key := strings.TrimSuffix(normalizeKey(s), " have")
+	switch key {
+	case "must", "should", "could":
+		return MoSCoW(key), nil
+	case "wont", "won t", "will not":
+		return MoSCoWWont, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (expected must, should, could, or won't)", s)
+}
+
+// phase returns the ID of the roadmap phase with the given ID or name.
+func (im *csvImporter) phase(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	for _, p := range im.doc.Roadmap.Phases {
+		if p.ID == s || strings.EqualFold(p.Name, s) {
+			return p.ID, nil
+		}
+	}
+	return "", fmt.Errorf("unknown phase %q", s)
+}
+
+// mergeCriteria returns the acceptance criteria with the descriptions
+// given. A criterion of existing with the same description is kept with its
+// ID; the others get IDs derived from the parent requirement's, as in
+// "FR-001-AC-3", numbered after the highest such ID in existing.
+func mergeCriteria(parent string, existing []AcceptanceCriterion, descriptions []string) []AcceptanceCriterion {
+	prefix := parent + "-AC-"
+	n := 0
+	for _, c := range existing {
+		if rest, ok := strings.CutPrefix(c.ID, prefix); ok {
+			if v, err := strconv.Atoi(rest); err == nil {
+				n = max(n, v)
+			}
+		}
+	}
+	existing = slices.Clone(existing)
+	criteria := make([]AcceptanceCriterion, len(descriptions))
+	for k, desc := range descriptions {
+		if i := slices.IndexFunc(existing, func(c AcceptanceCriterion) bool { return c.Description == desc }); i >= 0 {
+			criteria[k] = existing[i]
+			existing = slices.Delete(existing, i, i+1)
+			continue
+		}
+		n++
+		criteria[k] = AcceptanceCriterion{ID: fmt.Sprintf("%s%d", prefix, n), Description: desc}
+	}
+	return criteria
+}
+
+func setString(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// splitList splits a cell on any of the separator characters, dropping
+// empty items.
+func splitList(s, separators string) []string {
+	var out []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return strings.ContainsRune(separators, r) }) {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func allEmpty(row map[string]string) bool {
+	for _, v := range row {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// String summarizes the report, e.g. for a CLI.
+func (r *CSVImportReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Added %d and updated %d %s requirement(s)", len(r.Added), len(r.Updated), r.Section))
+	if len(r.Rejected) > 0 {
+		sb.WriteString(fmt.Sprintf(", rejected %d row(s)", len(r.Rejected)))
+	}
+	sb.WriteString("\n")
+	if len(r.Ignored) > 0 {
+		sb.WriteString(fmt.Sprintf("Ignored column(s): %s\n", strings.Join(r.Ignored, ", ")))
+	}
+	for _, rej := range r.Rejected {
+		if rej.ID != "" {
+			sb.WriteString(fmt.Sprintf("  - row %d (%s): %s\n", rej.Row, rej.ID, rej.Reason))
+		} else {
+			sb.WriteString(fmt.Sprintf("  - row %d: %s\n", rej.Row, rej.Reason))
+		}
+	}
+	return sb.String()
+}
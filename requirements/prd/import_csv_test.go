@@ -0,0 +1,113 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func csvDoc() *Document {
+	return &Document{
+		UserStories: []UserStory{{ID: "US-001", Title: "Checkout"}},
+		Requirements: Requirements{
+			Functional: []FunctionalRequirement{{ID: "FR-001", Title: "Cart", Priority: MoSCoWMust, PhaseID: "phase-1",
+				AcceptanceCriteria: []AcceptanceCriterion{{ID: "AC-1", Description: "Cart saved"}, {ID: "FR-001-AC-4", Description: "Old"}}}},
+			NonFunctional: []NonFunctionalRequirement{{ID: "NFR-001", Title: "Latency", Category: NFRPerformance}},
+		},
+		Roadmap: Roadmap{Phases: []Phase{{ID: "phase-1", Name: "MVP"}, {ID: "phase-2", Name: "GA"}}},
+	}
+}
+
+func TestImportCSVFunctional(t *testing.T) {
+	doc := csvDoc()
+	src := "ID,Summary,Priority,Phase,User Stories,Acceptance Criteria,Labels,Owner\n" +
+		"FR-001,,Should have,,,Totals shown; Cart saved,,ann\n" +
+		",Payments,Must,GA,US-001,Card accepted; Receipt sent,\"billing, payments\",bob\n" +
+		",Refunds,Urgent,MVP,,,,\n" +
+		",Coupons,could,Beta,,,,\n" +
+		",Wishlist,,,US-404,,,\n" +
+		"FR-009,\"Saved\ncarts\",won't,,,,,\n"
+	report, err := doc.ImportCSV(strings.NewReader(src), CSVFunctional)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(report.Added, ","); got != "FR-002,FR-009" {
+		t.Errorf("Added = %s, want FR-002,FR-009", got)
+	}
+	if got := strings.Join(report.Updated, ","); got != "FR-001" {
+		t.Errorf("Updated = %s, want FR-001", got)
+	}
+	if got := strings.Join(report.Ignored, ","); got != "Owner" {
+		t.Errorf("Ignored = %s, want Owner", got)
+	}
+	var rejected []string
+	for _, r := range report.Rejected {
+		rejected = append(rejected, r.Reason)
+	}
+	want := []string{`invalid priority "Urgent" (expected must, should, could, or won't)`, `unknown phase "Beta"`, `unknown user story "US-404"`}
+	if strings.Join(rejected, "|") != strings.Join(want, "|") {
+		t.Errorf("Rejected = %q, want %q", rejected, want)
+	}
+	if report.Rejected[0].Row != 4 {
+		t.Errorf("Rejected[0].Row = %d, want 4", report.Rejected[0].Row)
+	}
+
+	fr := doc.Requirements.Functional
+	if len(fr) != 3 {
+		t.Fatalf("got %d functional requirements, want 3", len(fr))
+	}
+	if fr[0].Title != "Cart" || fr[0].Priority != MoSCoWShould || fr[0].PhaseID != "phase-1" {
+		t.Errorf("updated FR-001 = %+v", fr[0])
+	}
+	var criteria []string
+	for _, c := range fr[0].AcceptanceCriteria {
+		criteria = append(criteria, c.ID+" "+c.Description)
+	}
+	if got := strings.Join(criteria, ", "); got != "FR-001-AC-5 Totals shown, AC-1 Cart saved" {
+		t.Errorf("FR-001 acceptance criteria = %s, want FR-001-AC-5 Totals shown, AC-1 Cart saved", got)
+	}
+	if p := fr[1]; p.PhaseID != "phase-2" || len(p.AcceptanceCriteria) != 2 || p.AcceptanceCriteria[1] != (AcceptanceCriterion{ID: "FR-002-AC-2", Description: "Receipt sent"}) ||
+		strings.Join(p.Tags, ",") != "billing,payments" || strings.Join(p.UserStoryIDs, ",") != "US-001" {
+		t.Errorf("added FR-002 = %+v", p)
+	}
+	if fr[2].Title != "Saved\ncarts" || fr[2].Priority != MoSCoWWont {
+		t.Errorf("added FR-009 = %+v", fr[2])
+	}
+	if fr[2].UserStoryIDs == nil || fr[2].AcceptanceCriteria == nil {
+		t.Errorf("added FR-009 has nil lists, which encode as null: %+v", fr[2])
+	}
+}
+
+func TestImportCSVNonFunctional(t *testing.T) {
+	doc := csvDoc()
+	src := "title,category,metric,target,priority\n" +
+		"Uptime,Availability,Monthly uptime,99.9%,must\n" +
+		"Speed,fast,,,\n" +
+		"Throughput,,,,\n"
+	report, err := doc.ImportCSV(strings.NewReader(src), CSVNonFunctional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Added) != 1 || report.Added[0] != "NFR-002" || len(report.Rejected) != 2 || report.Rejected[1].Reason != "no category" {
+		t.Fatalf("report = %+v", report)
+	}
+	if nfr := doc.Requirements.NonFunctional[1]; nfr.Category != NFRAvailability || nfr.Target != "99.9%" {
+		t.Errorf("added NFR-002 = %+v", nfr)
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	doc := csvDoc()
+	if _, err := doc.ImportCSV(strings.NewReader("owner,team\nann,core\n"), CSVFunctional); err == nil {
+		t.Error("ImportCSV() without a title or ID column succeeded")
+	}
+	if _, err := doc.ImportCSV(strings.NewReader(""), CSVFunctional); err == nil {
+		t.Error("ImportCSV() of an empty file succeeded")
+	}
+	if _, err := ParseCSVSection("NFR"); err != nil {
+		t.Errorf("ParseCSVSection(NFR) = %v", err)
+	}
+	if _, err := ParseCSVSection("stories"); err == nil {
+		t.Error("ParseCSVSection(stories) succeeded")
+	}
+}
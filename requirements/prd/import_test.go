@@ -0,0 +1,226 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+const legacyPRD = `---
+title: "Checkout Redesign"
+author: "Ana Lopez"
+date: "2024-03-01"
+---
+
+# Checkout Redesign
+
+## Background
+
+Cart abandonment at checkout is 38%.
+
+## Personas
+
+### Shopper (Primary)
+
+**Goals:**
+
+- Pay quickly
+
+**Pain Points:**
+
+- Too many form fields
+
+## User Stories
+
+- As a Shopper, I want to pay with a saved card so that checkout takes one click.
+- US-7: As an admin, I need to refund orders
+
+## Requirements
+
+### Payments
+
+| ID | Title | Priority |
+|----|-------|----------|
+| PAY-1 | Saved cards | Must |
+
+- Apple Pay - Support Apple Pay on iOS
+
+### Non-Functional Requirements
+
+#### Performance
+
+| Title | Target |
+|-------|--------|
+| Checkout latency | p95 < 300ms |
+
+## Launch Plan
+
+Roll out to 10% of traffic first.
+
+## Risks
+
+| Risk | Likelihood | Impact | Mitigation |
+|------|------------|--------|------------|
+| PSP outage | Low | High | Second provider |
+
+## Out of Scope
+
+- Gift cards
+
+` + "```md\n## Not a heading\n```\n"
+
+func TestImportMarkdown(t *testing.T) {
+	doc, report := ImportMarkdown([]byte(legacyPRD))
+
+	m := doc.Metadata
+	if m.Title != "Checkout Redesign" || m.ID != "prd-checkout-redesign" || m.Status != StatusDraft ||
+		len(m.Authors) != 1 || m.Authors[0].Name != "Ana Lopez" || m.CreatedAt.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("metadata = %+v", m)
+	}
+	if doc.ExecutiveSummary.ProblemStatement != "Cart abandonment at checkout is 38%." {
+		t.Errorf("problem statement = %q", doc.ExecutiveSummary.ProblemStatement)
+	}
+
+	if len(doc.Personas) != 1 {
+		t.Fatalf("personas = %+v", doc.Personas)
+	}
+	p := doc.Personas[0]
+	if p.Name != "Shopper" || !p.IsPrimary || p.ID == "" || len(p.Goals) != 1 || p.PainPoints[0] != "Too many form fields" {
+		t.Errorf("persona = %+v", p)
+	}
+
+	if len(doc.UserStories) != 2 {
+		t.Fatalf("user stories = %+v", doc.UserStories)
+	}
+	us := doc.UserStories[0]
+	if us.AsA != "Shopper" || us.IWant != "pay with a saved card" || us.SoThat != "checkout takes one click" || us.PersonaID != p.ID {
+		t.Errorf("story = %+v", us)
+	}
+	if us := doc.UserStories[1]; us.ID != "US-7" || us.AsA != "admin" || us.IWant != "refund orders" {
+		t.Errorf("story with ID = %+v", us)
+	}
+
+	fr := doc.Requirements.Functional
+	if len(fr) != 2 || fr[0].ID != "PAY-1" || fr[0].Priority != MoSCoWMust || fr[0].Category != "Payments" ||
+		fr[1].Title != "Apple Pay" || fr[1].Description != "Support Apple Pay on iOS" || fr[1].ID == "" {
+		t.Errorf("functional = %+v", fr)
+	}
+	nfr := doc.Requirements.NonFunctional
+	if len(nfr) != 1 || nfr[0].Category != NFRPerformance || nfr[0].Target != "p95 < 300ms" {
+		t.Errorf("non-functional = %+v", nfr)
+	}
+
+	if len(doc.Risks) != 1 || doc.Risks[0].Probability != "low" || doc.Risks[0].Mitigation != "Second provider" {
+		t.Errorf("risks = %+v", doc.Risks)
+	}
+	if len(doc.OutOfScope) != 1 || doc.OutOfScope[0] != "Gift cards" {
+		t.Errorf("out of scope = %v", doc.OutOfScope)
+	}
+
+	if len(report.Unmapped) != 1 || report.Unmapped[0].Heading != "Launch Plan" || report.Unmapped[0].Line != 48 {
+		t.Errorf("unmapped = %+v", report.Unmapped)
+	}
+	if len(doc.CustomSections) != 1 || doc.CustomSections[0].Content != "Roll out to 10% of traffic first." {
+		t.Errorf("custom sections = %+v", doc.CustomSections)
+	}
+	if !strings.Contains(report.String(), "line 48: Launch Plan") {
+		t.Errorf("report:\n%s", report)
+	}
+}
+
+func TestImportMarkdownRoundTrip(t *testing.T) {
+	orig := testDocumentForImport()
+	doc, report := ImportMarkdown([]byte(orig.ToMarkdown(DefaultMarkdownOptions())))
+
+	if doc.Metadata.ID != orig.Metadata.ID || doc.Metadata.Title != orig.Metadata.Title {
+		t.Errorf("metadata = %+v", doc.Metadata)
+	}
+	if doc.ExecutiveSummary.ProblemStatement != orig.ExecutiveSummary.ProblemStatement {
+		t.Errorf("problem statement = %q", doc.ExecutiveSummary.ProblemStatement)
+	}
+	if len(doc.Personas) != len(orig.Personas) || doc.Personas[0].Name != orig.Personas[0].Name {
+		t.Errorf("personas = %+v", doc.Personas)
+	}
+	if len(doc.UserStories) != 1 || doc.UserStories[0].ID != "US-001" || doc.UserStories[0].IWant != "search orders by email" {
+		t.Errorf("user stories = %+v", doc.UserStories)
+	}
+	if len(doc.Requirements.Functional) != 1 || doc.Requirements.Functional[0].ID != "FR-001" {
+		t.Errorf("functional = %+v", doc.Requirements.Functional)
+	}
+	if len(doc.Roadmap.Phases) != 1 || doc.Roadmap.Phases[0].ID != "phase-1" || doc.Roadmap.Phases[0].Name != "MVP" {
+		t.Errorf("phases = %+v", doc.Roadmap.Phases)
+	}
+	if len(report.Unmapped) != 0 {
+		t.Errorf("unmapped = %+v", report.Unmapped)
+	}
+}
+
+func testDocumentForImport() *Document {
+	doc := &Document{
+		Metadata: Metadata{ID: "PRD-9", Title: "Search", Version: "1.0.0", Status: StatusDraft},
+		ExecutiveSummary: ExecutiveSummary{
+			ProblemStatement: "Users cannot find orders.",
+			ProposedSolution: "Full-text search.",
+			ExpectedOutcomes: []string{"Fewer support tickets"},
+		},
+		Personas: []Persona{{ID: "p-1", Name: "Support Agent", Role: "Agent", Goals: []string{"Find orders fast"}}},
+		UserStories: []UserStory{{
+			ID: "US-001", PersonaID: "p-1", AsA: "Support Agent", IWant: "to search orders by email", SoThat: "I can help customers",
+			Priority: PriorityHigh, PhaseID: "phase-1",
+		}},
+		Requirements: Requirements{Functional: []FunctionalRequirement{{
+			ID: "FR-001", Title: "Email search", Description: "Search orders by email", Category: "Search", Priority: MoSCoWMust, PhaseID: "phase-1",
+		}}},
+	}
+	doc.Roadmap.Phases = []Phase{{ID: "phase-1", Name: "MVP", Type: PhaseTypeGeneric, Goals: []string{"Ship search"}}}
+	return doc
+}
+
+func TestMatchImportTargets(t *testing.T) {
+	tests := map[string][]ImportTarget{
+		"Non-Functional Requirements":   {ImportNonFunctional},
+		"Functional Requirements":       {ImportRequirements},
+		"Risks and Assumptions":         {ImportRisks, ImportAssumptions},
+		"Goals and Non-Goals":           {ImportOutOfScope, ImportGoals},
+		"3. Launch Plan":                nil,
+		"Personas & Target Users":       {ImportPersonas},
+		"Problem and Proposed Solution": {ImportProblem, ImportSolution},
+	}
+	for heading, want := range tests {
+		got := MatchImportTargets(heading)
+		if strings.Join(targetStrings(got), ",") != strings.Join(targetStrings(want), ",") {
+			t.Errorf("MatchImportTargets(%q) = %v, want %v", heading, got, want)
+		}
+	}
+}
+
+func TestImportMarkdownWithOptions(t *testing.T) {
+	src := "# Plan\n\n## 1. Launch Plan\n\n- Beta in May\n\n## Risks and Assumptions\n\n- PSP outage\n\n## Appendix\n\nNotes.\n"
+	var resolved []string
+	doc, report := ImportMarkdownWithOptions([]byte(src), ImportOptions{
+		Mapping: map[string]ImportTarget{"Launch Plan": ImportOutOfScope, "appendix": ImportCustom},
+		Resolve: func(heading string, candidates []ImportTarget) ImportTarget {
+			resolved = append(resolved, heading)
+			return ""
+		},
+	})
+	if len(doc.OutOfScope) != 1 || doc.OutOfScope[0] != "Beta in May" {
+		t.Errorf("out of scope = %v", doc.OutOfScope)
+	}
+	if len(resolved) != 1 || resolved[0] != "Risks and Assumptions" {
+		t.Errorf("resolved = %v", resolved)
+	}
+	if len(report.Ambiguous) != 1 || report.Ambiguous[0].Chosen != ImportRisks {
+		t.Errorf("ambiguous = %+v", report.Ambiguous)
+	}
+	if len(report.Unmapped) != 1 || report.Unmapped[0].Heading != "Appendix" || !strings.Contains(report.String(), "mapped to risks") {
+		t.Errorf("report:\n%s", report)
+	}
+}
+
+func targetStrings(targets []ImportTarget) []string {
+	s := make([]string, len(targets))
+	for i, t := range targets {
+		s[i] = string(t)
+	}
+	return s
+}
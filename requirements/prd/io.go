@@ -8,6 +8,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/yamlconv"
 )
 
 // DefaultFilename is the standard PRD filename.
@@ -28,6 +31,25 @@ func Load(path string) (*Document, error) {
 	return &doc, nil
 }
 
+// LoadLenient reads a Document from a JSON file, recovering from field-level
+// type errors (bad dates, wrong-typed numbers) instead of failing. The
+// partially loaded document is returned along with a report of every field
+// that was coerced or dropped.
+func LoadLenient(path string) (*Document, *lenient.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading PRD file: %w", err)
+	}
+
+	var doc Document
+	report, err := lenient.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing PRD JSON: %w", err)
+	}
+
+	return &doc, report, nil
+}
+
 // Save writes a Document to a JSON file.
 func Save(doc *Document, path string) error {
 	doc.Metadata.UpdatedAt = time.Now()
@@ -48,6 +70,24 @@ func Save(doc *Document, path string) error {
 	return nil
 }
 
+// ReadFileYAML reads a Document from a YAML file. Field names are the same
+// as in JSON.
+func ReadFileYAML(path string) (*Document, error) {
+	var doc Document
+	if err := yamlconv.ReadFile(path, &doc); err != nil {
+		return nil, fmt.Errorf("reading PRD YAML: %w", err)
+	}
+	return &doc, nil
+}
+
+// WriteFileYAML writes the Document to a YAML file.
+func (d *Document) WriteFileYAML(path string) error {
+	if err := yamlconv.WriteFile(path, d); err != nil {
+		return fmt.Errorf("writing PRD YAML: %w", err)
+	}
+	return nil
+}
+
 // New creates a new Document with required fields initialized.
 func New(id, title string, authors ...Person) *Document {
 	now := time.Now()
@@ -66,9 +106,12 @@ func New(id, title string, authors ...Person) *Document {
 		Objectives: Objectives{
 			OKRs: []OKR{},
 		},
-		Personas:     []Persona{},
-		UserStories:  []UserStory{},
-		Requirements: Requirements{},
+		Personas:    []Persona{},
+		UserStories: []UserStory{},
+		Requirements: Requirements{
+			Functional:    []FunctionalRequirement{},
+			NonFunctional: []NonFunctionalRequirement{},
+		},
 		Roadmap: Roadmap{
 			Phases: []Phase{},
 		},
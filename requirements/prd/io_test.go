@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/grokify/structured-plan/lenient"
 )
 
 func TestNew(t *testing.T) {
@@ -181,6 +183,36 @@ func TestSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestWriteAndReadFileYAML(t *testing.T) {
+	doc := New("PRD-001", "Test PRD", Person{Name: "John Doe"})
+	doc.Requirements.Functional = []FunctionalRequirement{
+		{ID: "FR-001", Title: "Login", Description: "Users sign in\nwith SSO", Priority: MoSCoWMust},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.prd.yaml")
+	if err := doc.WriteFileYAML(path); err != nil {
+		t.Fatalf("WriteFileYAML failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "    - id: FR-001\n") || !strings.Contains(string(data), "description: |-\n") {
+		t.Errorf("unexpected YAML:\n%s", data)
+	}
+
+	loaded, err := ReadFileYAML(path)
+	if err != nil {
+		t.Fatalf("ReadFileYAML failed: %v", err)
+	}
+	if loaded.Metadata.ID != "PRD-001" || !loaded.Metadata.CreatedAt.Equal(doc.Metadata.CreatedAt) {
+		t.Errorf("metadata = %+v", loaded.Metadata)
+	}
+	if len(loaded.Requirements.Functional) != 1 || loaded.Requirements.Functional[0].Description != "Users sign in\nwith SSO" {
+		t.Errorf("requirements = %+v", loaded.Requirements.Functional)
+	}
+}
+
 func TestLoadNonExistentFile(t *testing.T) {
 	_, err := Load("/nonexistent/path/file.json")
 	if err == nil {
@@ -244,3 +276,39 @@ func TestSaveCreatesDirectory(t *testing.T) {
 		t.Error("File was not created in nested directory")
 	}
 }
+
+func TestLoadLenient(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lenient.prd.json")
+	data := `{
+		"metadata": {"id": "PRD-1", "title": "Lenient", "version": "1.0.0", "createdAt": "2025-03-01", "updatedAt": 12},
+		"userStories": [{"id": "US-1", "personaId": "P1", "storyPoints": "5"}]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected strict Load to fail")
+	}
+
+	doc, report, err := LoadLenient(path)
+	if err != nil {
+		t.Fatalf("LoadLenient() error = %v", err)
+	}
+	if doc.Metadata.Title != "Lenient" {
+		t.Errorf("expected title to load, got %q", doc.Metadata.Title)
+	}
+	if doc.Metadata.CreatedAt.IsZero() {
+		t.Error("expected createdAt to be coerced from a date")
+	}
+	if sp := doc.UserStories[0].StoryPoints; sp == nil || *sp != 5 {
+		t.Errorf("expected storyPoints 5, got %v", sp)
+	}
+	if got := report.Count(lenient.ActionDropped); got != 1 {
+		t.Errorf("expected 1 dropped field, got %d: %v", got, report.Issues)
+	}
+	if got := report.Count(lenient.ActionCoerced); got != 2 {
+		t.Errorf("expected 2 coerced fields, got %d: %v", got, report.Issues)
+	}
+}
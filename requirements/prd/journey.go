@@ -0,0 +1,135 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JourneyMap is a persona's journey through the product, stage by stage.
+type JourneyMap struct {
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	PersonaID   string         `json:"personaId"`
+	Description string         `json:"description,omitempty"`
+	Stages      []JourneyStage `json:"stages"`
+}
+
+// JourneyStage is one stage of a journey map, such as "Discover" or
+// "Onboard".
+type JourneyStage struct {
+	Name          string   `json:"name"`
+	Actions       []string `json:"actions,omitempty"`
+	PainPoints    []string `json:"painPoints,omitempty"`
+	Opportunities []string `json:"opportunities,omitempty"`
+
+	// Satisfaction is how the persona feels at this stage, from 1
+	// (frustrated) to 5 (delighted). It is the task score of the Mermaid
+	// journey diagram; 0 means unrated and is drawn as 3.
+	Satisfaction int `json:"satisfaction,omitempty"`
+}
+
+// JourneyMaps returns the journey maps of the UX requirements.
+func (d *Document) JourneyMaps() []JourneyMap {
+	if d.UXRequirements == nil {
+		return nil
+	}
+	return d.UXRequirements.JourneyMaps
+}
+
+// JourneyPersonaCoverage returns the number of personas with at least one
+// journey map.
+func (d *Document) JourneyPersonaCoverage() int {
+	mapped := make(map[string]bool)
+	for _, j := range d.JourneyMaps() {
+		mapped[j.PersonaID] = true
+	}
+	n := 0
+	for _, p := range d.Personas {
+		if mapped[p.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// MermaidJourney returns the journey map as a Mermaid journey diagram, with
+// a section per stage and a task per action, performed by actor.
+func (j JourneyMap) MermaidJourney(actor string) string {
+	var sb strings.Builder
+	sb.WriteString("journey\n")
+	sb.WriteString(fmt.Sprintf("    title %s\n", mermaidText(j.Title)))
+	for _, s := range j.Stages {
+		score := s.Satisfaction
+		if score < 1 || score > 5 {
+			score = 3
+		}
+		sb.WriteString(fmt.Sprintf("    section %s\n", mermaidText(s.Name)))
+		actions := s.Actions
+		if len(actions) == 0 {
+			actions = []string{s.Name}
+		}
+		for _, a := range actions {
+			sb.WriteString(fmt.Sprintf("      %s: %d: %s\n", mermaidText(a), score, mermaidText(actor)))
+		}
+	}
+	return sb.String()
+}
+
+// mermaidText makes s safe for a Mermaid journey line, where colons
+// separate the task, score, and actors and a line break ends the entry.
+func mermaidText(s string) string {
+	s = strings.NewReplacer(":", " -", "\n", " ", "\r", " ", ";", ",", "#", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+func (d *Document) generateJourneyMaps() string {
+	personaNames := make(map[string]string, len(d.Personas))
+	for _, p := range d.Personas {
+		personaNames[p.ID] = p.Name
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## User Journeys\n\n")
+	for _, j := range d.JourneyMaps() {
+		persona := personaNames[j.PersonaID]
+		if persona == "" {
+			persona = j.PersonaID
+		}
+		sb.WriteString(fmt.Sprintf("### %s: %s\n\n", j.ID, j.Title))
+		if persona != "" {
+			sb.WriteString(fmt.Sprintf("**Persona:** %s\n\n", persona))
+		}
+		if j.Description != "" {
+			sb.WriteString(j.Description + "\n\n")
+		}
+		if len(j.Stages) == 0 {
+			continue
+		}
+
+		sb.WriteString("```mermaid\n")
+		sb.WriteString(j.MermaidJourney(persona))
+		sb.WriteString("```\n\n")
+
+		sb.WriteString("| Stage | Actions | Pain Points | Opportunities | Satisfaction |\n")
+		sb.WriteString("|-------|---------|-------------|---------------|:------------:|\n")
+		for _, s := range j.Stages {
+			satisfaction := ""
+			if s.Satisfaction > 0 {
+				satisfaction = fmt.Sprintf("%d/5", s.Satisfaction)
+			}
+			cells := []string{s.Name, journeyCell(s.Actions), journeyCell(s.PainPoints), journeyCell(s.Opportunities), satisfaction}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
+
+// journeyCell lists items in one table cell.
+func journeyCell(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return "• " + strings.Join(items, "<br>• ")
+}
@@ -0,0 +1,85 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func journeyDocument() Document {
+	return Document{
+		Personas: []Persona{{ID: "p1", Name: "Shopper"}, {ID: "p2", Name: "Merchant"}},
+		UXRequirements: &UXRequirements{JourneyMaps: []JourneyMap{{
+			ID:        "JM-1",
+			Title:     "First purchase",
+			PersonaID: "p1",
+			Stages: []JourneyStage{
+				{Name: "Discover", Actions: []string{"Search: shoes", "Compare"}, PainPoints: []string{"Too many results"}, Satisfaction: 2},
+				{Name: "Checkout", Opportunities: []string{"Saved cards"}},
+			},
+		}}},
+	}
+}
+
+func TestMermaidJourney(t *testing.T) {
+	doc := journeyDocument()
+	got := doc.JourneyMaps()[0].MermaidJourney("Shopper")
+	want := strings.Join([]string{
+		"journey",
+		"    title First purchase",
+		"    section Discover",
+		"      Search - shoes: 2: Shopper",
+		"      Compare: 2: Shopper",
+		"    section Checkout",
+		"      Checkout: 3: Shopper",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("MermaidJourney =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateJourneyMaps(t *testing.T) {
+	doc := journeyDocument()
+	md := doc.ToMarkdown(DefaultMarkdownOptions())
+	for _, want := range []string{
+		"[User Journeys](#sec-user-journeys)",
+		"## User Journeys {#sec-user-journeys}",
+		"**Persona:** Shopper",
+		"```mermaid\njourney\n",
+		"| Discover | • Search: shoes<br>• Compare | • Too many results |  | 2/5 |",
+		"| Checkout |  |  | • Saved cards |  |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q", want)
+		}
+	}
+	if broken := BrokenAnchors(md); len(broken) > 0 {
+		t.Errorf("broken anchors: %v", broken)
+	}
+}
+
+func TestJourneyScoring(t *testing.T) {
+	doc := journeyDocument()
+	if got := doc.JourneyPersonaCoverage(); got != 1 {
+		t.Errorf("JourneyPersonaCoverage = %d, want 1", got)
+	}
+	score := scoreUXCoverage(&doc)
+	if score.Score != 1.5 {
+		t.Errorf("ux_coverage = %v, want 1.5", score.Score)
+	}
+	if len(score.References) != 1 || score.References[0].ID != "p2" {
+		t.Errorf("references = %+v, want the unmapped persona", score.References)
+	}
+
+	doc.UXRequirements.JourneyMaps[0].PersonaID = "p9"
+	result := Validate(&doc)
+	found := false
+	for _, w := range result.Warnings {
+		if w.Field == "ux_requirements.journey_maps[0].persona_id" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %+v, want the undefined persona", result.Warnings)
+	}
+}
@@ -0,0 +1,267 @@
+// Package llmeval scores PRDs with an LLM. Each evaluation category is
+// scored from the document sections it covers, and the qualitative scores
+// can be merged with the deterministic scores of prd.ScoreToEvaluationReport
+// into one evaluation report.
+package llmeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// PromptVersion identifies the category prompt, recorded in the judge
+// metadata of reports so scores from different prompts can be told apart.
+const PromptVersion = "1"
+
+// DefaultWeight is the share of the LLM score in a merged category score.
+const DefaultWeight = 0.5
+
+const systemPrompt = `You are a senior product reviewer scoring one quality category of a product requirements document (PRD).
+Score only the category you are given, from 0 (missing or unusable) to 10 (exemplary), judging clarity, specificity, evidence, and internal consistency rather than the mere presence of fields.
+Reply with a single JSON object and nothing else:
+{"score": <number 0-10>, "justification": "<two or three sentences>", "findings": [{"severity": "medium|low|info", "title": "<short>", "description": "<what is wrong>", "recommendation": "<how to fix it>", "evidence": "<JSON pointer or quote>"}]}`
+
+// Evaluator scores PRDs with an LLM provider.
+type Evaluator struct {
+	Provider Provider
+
+	// Weight is the share of the LLM score, from 0 to 1, when merging with
+	// deterministic scores. Zero means DefaultWeight.
+	Weight float64
+}
+
+// New returns an evaluator that scores with p.
+func New(p Provider) *Evaluator {
+	return &Evaluator{Provider: p, Weight: DefaultWeight}
+}
+
+// categoryResult is the reply expected for a category prompt.
+type categoryResult struct {
+	Score         float64 `json:"score"`
+	Justification string  `json:"justification"`
+	Findings      []struct {
+		Severity       string `json:"severity"`
+		Title          string `json:"title"`
+		Description    string `json:"description"`
+		Recommendation string `json:"recommendation"`
+		Evidence       string `json:"evidence"`
+	} `json:"findings"`
+}
+
+// Evaluate scores each standard and registered category of doc that has
+// content, sending the LLM only the sections the category covers.
+// Categories without content are left out of the report.
+func (e *Evaluator) Evaluate(ctx context.Context, doc *prd.Document, filename string) (*evaluation.EvaluationReport, error) {
+	report := evaluation.NewEvaluationReport("prd", filepath.Base(filename))
+	report.Metadata.DocumentID = doc.Metadata.ID
+	report.Metadata.DocumentTitle = doc.Metadata.Title
+	report.Metadata.DocumentVersion = doc.Metadata.Version
+	report.Metadata.GeneratedBy = "srequirements (llm)"
+
+	judge := evaluation.NewJudgeMetadata(e.Provider.Model()).
+		WithProvider(e.Provider.Name()).
+		WithPrompt("prd-category", PromptVersion).
+		WithTemperature(0)
+	judge.SystemPrompt = systemPrompt
+	start := time.Now()
+	var inputTokens, outputTokens int
+
+	for _, cat := range prd.Categories() {
+		sections := Sections(doc, cat.ID)
+		if len(sections) == 0 {
+			continue
+		}
+		prompt, err := categoryPrompt(cat, sections)
+		if err != nil {
+			return nil, err
+		}
+		c, err := e.Provider.Complete(ctx, systemPrompt, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("scoring %s: %w", cat.ID, err)
+		}
+		inputTokens += c.InputTokens
+		outputTokens += c.OutputTokens
+		res, err := parseResult(c.Text)
+		if err != nil {
+			return nil, fmt.Errorf("scoring %s: %w", cat.ID, err)
+		}
+		report.AddCategory(categoryScore(cat, res))
+	}
+
+	judge.WithTokenUsage(inputTokens, outputTokens)
+	judge.SetLatency(time.Since(start))
+	report.SetJudge(judge)
+	report.Finalize(fmt.Sprintf("srequirements prd score --engine=llm --provider %s %s", e.Provider.Name(), filename))
+	return report, nil
+}
+
+// Score scores doc deterministically and with the LLM, and merges the two.
+func (e *Evaluator) Score(ctx context.Context, doc *prd.Document, filename string) (*evaluation.EvaluationReport, error) {
+	llm, err := e.Evaluate(ctx, doc, filename)
+	if err != nil {
+		return nil, err
+	}
+	weight := e.Weight
+	if weight == 0 {
+		weight = DefaultWeight
+	}
+	return Merge(prd.ScoreToEvaluationReport(doc, filename), llm, weight), nil
+}
+
+// Merge merges an LLM report into a deterministic one. Each category score
+// becomes the weighted mean of the two, with weight the LLM share, and the
+// LLM justification and findings are appended. LLM findings are capped at
+// medium severity so they inform the decision without blocking approval
+// on their own. The weighted score, decision, and next steps are
+// recomputed; the deterministic report is not modified.
+func Merge(det, llm *evaluation.EvaluationReport, weight float64) *evaluation.EvaluationReport {
+	weight = math.Max(0, math.Min(1, weight))
+	merged := *det
+	merged.Categories = make([]evaluation.CategoryScore, 0, len(det.Categories))
+	merged.Findings = append([]evaluation.Finding(nil), det.Findings...)
+	merged.Metadata.GeneratedBy = "srequirements (deterministic + llm)"
+	merged.SetJudge(llm.Judge)
+
+	byID := make(map[string]evaluation.CategoryScore, len(llm.Categories))
+	for _, c := range llm.Categories {
+		byID[c.Category] = c
+	}
+	for _, c := range det.Categories {
+		if l, ok := byID[c.Category]; ok {
+			c.Score = round((1-weight)*c.Score + weight*l.Score)
+			c.Justification = strings.TrimSpace(c.Justification + " LLM: " + l.Justification)
+			c.Findings = append(append([]evaluation.Finding(nil), c.Findings...), l.Findings...)
+			for _, f := range l.Findings {
+				if f.Severity == evaluation.SeverityMedium {
+					merged.Findings = append(merged.Findings, f)
+				}
+			}
+			c.ComputeStatus()
+		}
+		merged.Categories = append(merged.Categories, c)
+	}
+
+	merged.Finalize(det.NextSteps.RerunCommand)
+	return &merged
+}
+
+// Sections returns the top-level PRD sections a category covers, keyed by
+// their JSON field names, leaving out empty ones. Registered categories
+// cover the whole document.
+func Sections(doc *prd.Document, category string) map[string]any {
+	sections := map[string]any{}
+	add := func(name string, v any, ok bool) {
+		if ok {
+			sections[name] = v
+		}
+	}
+	switch category {
+	case "problem_definition":
+		add("executiveSummary", doc.ExecutiveSummary, doc.ExecutiveSummary.ProblemStatement != "")
+		add("problem", doc.Problem, doc.Problem != nil)
+		add("currentState", doc.CurrentState, doc.CurrentState != nil)
+	case "solution_fit":
+		add("solution", doc.Solution, doc.Solution != nil)
+		add("executiveSummary", doc.ExecutiveSummary, doc.ExecutiveSummary.ProposedSolution != "")
+	case "user_understanding":
+		add("personas", doc.Personas, len(doc.Personas) > 0)
+		add("userStories", doc.UserStories, len(doc.UserStories) > 0)
+	case "market_awareness":
+		add("market", doc.Market, doc.Market != nil)
+	case "scope_discipline":
+		add("objectives", doc.Objectives, len(doc.Objectives.OKRs) > 0)
+		add("outOfScope", doc.OutOfScope, len(doc.OutOfScope) > 0)
+	case "requirements_quality":
+		add("requirements", doc.Requirements, len(doc.Requirements.Functional) > 0 || len(doc.Requirements.NonFunctional) > 0)
+	case "ux_coverage":
+		add("uxRequirements", doc.UXRequirements, doc.UXRequirements != nil)
+	case "technical_feasibility":
+		add("technicalArchitecture", doc.TechArchitecture, doc.TechArchitecture != nil)
+	case "metrics_quality":
+		add("objectives", doc.Objectives, len(doc.Objectives.OKRs) > 0)
+		add("currentState", doc.CurrentState, doc.CurrentState != nil)
+	case "risk_management":
+		add("risks", doc.Risks, len(doc.Risks) > 0)
+		add("assumptions", doc.Assumptions, doc.Assumptions != nil)
+	default:
+		add("document", doc, true)
+	}
+	return sections
+}
+
+func categoryPrompt(cat prd.EvaluationCategory, sections map[string]any) (string, error) {
+	data, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling %s sections: %w", cat.ID, err)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Category: %s (%s)\n", cat.Name, cat.ID))
+	sb.WriteString(fmt.Sprintf("What it evaluates: %s\n\n", cat.Description))
+	sb.WriteString("PRD sections (JSON):\n")
+	sb.Write(data)
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
+
+// parseResult decodes a category reply, tolerating a Markdown code fence
+// or text around the JSON object.
+func parseResult(text string) (categoryResult, error) {
+	var res categoryResult
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return res, fmt.Errorf("reply has no JSON object: %q", truncate(text, 200))
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &res); err != nil {
+		return res, fmt.Errorf("decoding reply: %w", err)
+	}
+	res.Score = math.Max(0, math.Min(10, res.Score))
+	return res, nil
+}
+
+func categoryScore(cat prd.EvaluationCategory, res categoryResult) evaluation.CategoryScore {
+	cs := evaluation.NewCategoryScore(cat.ID, cat.Weight, round(res.Score), res.Justification)
+	for i, f := range res.Findings {
+		cs.Findings = append(cs.Findings, evaluation.Finding{
+			ID:             fmt.Sprintf("LLM-%s-%d", cat.ID, i+1),
+			Category:       cat.ID,
+			Severity:       severity(f.Severity),
+			Title:          f.Title,
+			Description:    f.Description,
+			Recommendation: f.Recommendation,
+			Evidence:       f.Evidence,
+			Owner:          cat.Owner,
+		})
+	}
+	return cs
+}
+
+// severity maps a reply severity to medium, low, or info.
+func severity(s string) evaluation.Severity {
+	switch evaluation.Severity(strings.ToLower(strings.TrimSpace(s))) {
+	case evaluation.SeverityCritical, evaluation.SeverityHigh, evaluation.SeverityMedium:
+		return evaluation.SeverityMedium
+	case evaluation.SeverityInfo:
+		return evaluation.SeverityInfo
+	}
+	return evaluation.SeverityLow
+}
+
+func round(f float64) float64 {
+	return math.Round(f*10) / 10
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
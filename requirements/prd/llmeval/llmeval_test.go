@@ -0,0 +1,158 @@
+package llmeval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+const reply = "```json\n" + `{"score": 12, "justification": "Clear problem.", "findings": [
+	{"severity": "critical", "title": "No baseline", "recommendation": "Add a baseline"},
+	{"severity": "info", "title": "Well cited"}]}` + "\n```"
+
+func TestProviders(t *testing.T) {
+	tests := []struct {
+		provider string
+		path     string
+		header   string
+		response any
+	}{
+		{ProviderOpenAI, "/v1/chat/completions", "Authorization", map[string]any{
+			"choices": []any{map[string]any{"message": map[string]any{"content": reply}}},
+			"usage":   map[string]any{"prompt_tokens": 100, "completion_tokens": 20},
+		}},
+		{ProviderAnthropic, "/v1/messages", "X-Api-Key", map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": reply}},
+			"usage":   map[string]any{"input_tokens": 100, "output_tokens": 20},
+		}},
+		{ProviderOllama, "/api/chat", "", map[string]any{
+			"message":           map[string]any{"content": reply},
+			"prompt_eval_count": 100, "eval_count": 20,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("path = %s, want %s", r.URL.Path, tt.path)
+				}
+				if tt.header != "" && r.Header.Get(tt.header) == "" {
+					t.Errorf("missing %s header", tt.header)
+				}
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["model"] != "test-model" {
+					t.Errorf("request body = %v (%v)", body, err)
+				}
+				_ = json.NewEncoder(w).Encode(tt.response)
+			}))
+			defer srv.Close()
+
+			p, err := NewProvider(tt.provider, Config{Model: "test-model", Endpoint: srv.URL, APIKey: "key"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := p.Complete(context.Background(), "system", "prompt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Text != reply || c.InputTokens != 100 || c.OutputTokens != 20 {
+				t.Errorf("Complete = %+v", c)
+			}
+		})
+	}
+
+	if _, err := NewProvider(ProviderOpenAI, Config{}); err == nil {
+		t.Error("NewProvider(openai) without an API key succeeded")
+	}
+	if _, err := NewProvider("bard", Config{}); err == nil {
+		t.Error("NewProvider(bard) succeeded")
+	}
+}
+
+// stubProvider replies with the same text to every prompt and records the
+// prompts it received.
+type stubProvider struct {
+	reply   string
+	prompts []string
+}
+
+func (p *stubProvider) Name() string  { return "stub" }
+func (p *stubProvider) Model() string { return "stub-model" }
+
+func (p *stubProvider) Complete(_ context.Context, _, prompt string) (Completion, error) {
+	p.prompts = append(p.prompts, prompt)
+	return Completion{Text: p.reply, InputTokens: 10, OutputTokens: 5}, nil
+}
+
+func TestEvaluateAndMerge(t *testing.T) {
+	doc := &prd.Document{
+		Metadata:         prd.Metadata{ID: "PRD-1", Title: "Checkout"},
+		ExecutiveSummary: prd.ExecutiveSummary{ProblemStatement: "Checkout abandonment is 40%"},
+		Personas:         []prd.Persona{{ID: "p1", Name: "Shopper"}},
+	}
+	stub := &stubProvider{reply: reply}
+	llm, err := New(stub).Evaluate(context.Background(), doc, "checkout.prd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only problem_definition and user_understanding have content.
+	if len(stub.prompts) != 2 || len(llm.Categories) != 2 {
+		t.Fatalf("prompts = %d, categories = %d, want 2", len(stub.prompts), len(llm.Categories))
+	}
+	if !strings.Contains(stub.prompts[0], "Checkout abandonment is 40%") || strings.Contains(stub.prompts[0], "Shopper") {
+		t.Errorf("problem definition prompt:\n%s", stub.prompts[0])
+	}
+	pd := llm.Categories[0]
+	if pd.Category != "problem_definition" || pd.Score != 10 || len(pd.Findings) != 2 {
+		t.Errorf("problem_definition = %+v", pd)
+	}
+	if pd.Findings[0].Severity != evaluation.SeverityMedium || pd.Findings[1].Severity != evaluation.SeverityInfo {
+		t.Errorf("finding severities = %s, %s", pd.Findings[0].Severity, pd.Findings[1].Severity)
+	}
+	if llm.Judge == nil || llm.Judge.ModelProvider != "stub" || llm.Judge.TokensUsed.InputTokens != 20 {
+		t.Errorf("judge = %+v", llm.Judge)
+	}
+
+	det := prd.ScoreToEvaluationReport(doc, "checkout.prd.json")
+	detScores := map[string]float64{}
+	for _, c := range det.Categories {
+		detScores[c.Category] = c.Score
+	}
+	merged := Merge(det, llm, 0.5)
+	for _, c := range merged.Categories {
+		want := detScores[c.Category]
+		if c.Category == "problem_definition" || c.Category == "user_understanding" {
+			want = round((want + 10) / 2)
+		}
+		if c.Score != want {
+			t.Errorf("merged %s = %v, want %v", c.Category, c.Score, want)
+		}
+	}
+	if merged.WeightedScore <= det.WeightedScore {
+		t.Errorf("merged weighted score %v not above deterministic %v", merged.WeightedScore, det.WeightedScore)
+	}
+	if got := len(merged.Findings) - len(det.Findings); got != 2 {
+		t.Errorf("merged report has %d more findings, want the 2 medium LLM findings", got)
+	}
+	if det.Metadata.GeneratedBy == merged.Metadata.GeneratedBy {
+		t.Error("Merge modified the deterministic report")
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	if _, err := parseResult("I cannot score this."); err == nil {
+		t.Error("parseResult without JSON succeeded")
+	}
+	res, err := parseResult(`Here you go: {"score": -3, "justification": "x"}`)
+	if err != nil || res.Score != 0 {
+		t.Errorf("parseResult = %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,256 @@
+package llmeval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by NewProvider.
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
+)
+
+// Default models and endpoints of the providers.
+const (
+	DefaultOpenAIModel    = "gpt-4o-mini"
+	DefaultAnthropicModel = "claude-3-5-haiku-latest"
+	DefaultOllamaModel    = "llama3.1"
+
+	DefaultOpenAIEndpoint    = "https://api.openai.com"
+	DefaultAnthropicEndpoint = "https://api.anthropic.com"
+	DefaultOllamaEndpoint    = "http://localhost:11434"
+)
+
+// Completion is the text a provider returned for a prompt, with the token
+// counts it reported.
+type Completion struct {
+	Text         string
+	InputTokens  int
+	OutputTokens int
+}
+
+// Provider sends a prompt to an LLM and returns its reply. Implementations
+// should ask for a JSON reply where the API supports it.
+type Provider interface {
+	// Name is the provider name, e.g. "openai".
+	Name() string
+
+	// Model is the model prompts are sent to.
+	Model() string
+
+	Complete(ctx context.Context, system, prompt string) (Completion, error)
+}
+
+// Config configures a provider. Empty fields take the provider's default.
+type Config struct {
+	Model    string
+	Endpoint string // Base URL of the API
+	APIKey   string // Not used by Ollama
+
+	HTTPClient *http.Client
+}
+
+// NewProvider returns the provider with the given name: openai, anthropic,
+// or ollama.
+func NewProvider(name string, cfg Config) (Provider, error) {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 120 * time.Second}
+	}
+	switch strings.ToLower(name) {
+	case ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("openai: an API key is required")
+		}
+		return &openAI{client: newClient(cfg, DefaultOpenAIModel, DefaultOpenAIEndpoint)}, nil
+	case ProviderAnthropic:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("anthropic: an API key is required")
+		}
+		return &anthropic{client: newClient(cfg, DefaultAnthropicModel, DefaultAnthropicEndpoint)}, nil
+	case ProviderOllama:
+		return &ollama{client: newClient(cfg, DefaultOllamaModel, DefaultOllamaEndpoint)}, nil
+	}
+	return nil, fmt.Errorf("unknown provider: %s (expected openai, anthropic, or ollama)", name)
+}
+
+type client struct {
+	model    string
+	endpoint string
+	apiKey   string
+	http     *http.Client
+}
+
+func newClient(cfg Config, model, endpoint string) client {
+	if cfg.Model != "" {
+		model = cfg.Model
+	}
+	if cfg.Endpoint != "" {
+		endpoint = cfg.Endpoint
+	}
+	return client{
+		model:    model,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   cfg.APIKey,
+		http:     cfg.HTTPClient,
+	}
+}
+
+func (c client) Model() string { return c.model }
+
+// post sends body as JSON with the given headers and decodes the response
+// into out.
+func (c client) post(ctx context.Context, provider, path string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned %s: %s", provider, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// openAI uses the chat completions API.
+type openAI struct{ client }
+
+func (p *openAI) Name() string { return ProviderOpenAI }
+
+func (p *openAI) Complete(ctx context.Context, system, prompt string) (Completion, error) {
+	body := map[string]any{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+		"temperature":     0,
+		"response_format": map[string]string{"type": "json_object"},
+	}
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + p.apiKey}
+	if err := p.post(ctx, ProviderOpenAI, "/v1/chat/completions", headers, body, &resp); err != nil {
+		return Completion{}, err
+	}
+	if len(resp.Choices) == 0 {
+		return Completion{}, fmt.Errorf("openai returned no choices")
+	}
+	return Completion{
+		Text:         resp.Choices[0].Message.Content,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// anthropic uses the messages API.
+type anthropic struct{ client }
+
+func (p *anthropic) Name() string { return ProviderAnthropic }
+
+func (p *anthropic) Complete(ctx context.Context, system, prompt string) (Completion, error) {
+	body := map[string]any{
+		"model":       p.model,
+		"system":      system,
+		"max_tokens":  2048,
+		"temperature": 0,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{
+		"x-api-key":         p.apiKey,
+		"anthropic-version": "2023-06-01",
+	}
+	if err := p.post(ctx, ProviderAnthropic, "/v1/messages", headers, body, &resp); err != nil {
+		return Completion{}, err
+	}
+	var text strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	return Completion{
+		Text:         text.String(),
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}, nil
+}
+
+// ollama uses the chat API of a local Ollama server.
+type ollama struct{ client }
+
+func (p *ollama) Name() string { return ProviderOllama }
+
+func (p *ollama) Complete(ctx context.Context, system, prompt string) (Completion, error) {
+	body := map[string]any{
+		"model":  p.model,
+		"stream": false,
+		"format": "json",
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+		"options": map[string]any{"temperature": 0},
+	}
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	if err := p.post(ctx, ProviderOllama, "/api/chat", nil, body, &resp); err != nil {
+		return Completion{}, err
+	}
+	return Completion{
+		Text:         resp.Message.Content,
+		InputTokens:  resp.PromptEvalCount,
+		OutputTokens: resp.EvalCount,
+	}, nil
+}
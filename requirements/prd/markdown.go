@@ -2,10 +2,15 @@ package prd
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/grokify/structured-plan/assets"
 	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/richtext"
 )
 
 // MarkdownOptions configures markdown generation.
@@ -28,8 +33,63 @@ type MarkdownOptions struct {
 	IncludeSwimlaneTable bool
 	// RoadmapTableOptions configures the swimlane/roadmap table generation
 	RoadmapTableOptions *RoadmapTableOptions
+	// IncludeRoadmapDiagrams adds a Mermaid Gantt chart and dependency graph
+	// of the roadmap phases, which GitHub and GitLab render in place
+	IncludeRoadmapDiagrams bool
 	// IncludeTOC adds a Table of Contents with internal links (default: true)
 	IncludeTOC *bool
+	// RevisionHistory is a rendered "## Revision History" section, such as
+	// the output of history.History.ToMarkdown, placed after the custom sections
+	RevisionHistory string
+	// Icons is the status icon set: "emoji" (default), "text" for bracketed
+	// words, or "none"
+	Icons string
+	// LinkGlossary links the first use of each glossary term to its entry
+	// (default: true)
+	LinkGlossary *bool
+	// OmitSections lists core sections, by JSON name such as "personas", to
+	// leave out with their headings, such as those a render profile removes.
+	// The sections after them are numbered on from the last one shown.
+	OmitSections []string
+}
+
+// CoreSections are the JSON names of the numbered sections every PRD
+// renders, in order. "requirements" covers both requirements sections.
+var CoreSections = []string{"executiveSummary", "objectives", "personas", "userStories", "requirements", "roadmap"}
+
+// coreHeadings are the numbered headings of the core sections, with the
+// JSON name of the section each renders.
+var coreHeadings = []struct{ section, title string }{
+	{"executiveSummary", "Executive Summary"},
+	{"objectives", "Objectives and Goals"},
+	{"personas", "Personas"},
+	{"userStories", "User Stories"},
+	{"requirements", "Functional Requirements"},
+	{"requirements", "Non-Functional Requirements"},
+	{"roadmap", "Roadmap"},
+}
+
+// sectionNumbers returns the number of each core heading shown by opts,
+// by title, and the number of the first optional section.
+func (opts MarkdownOptions) sectionNumbers() (map[string]int, int) {
+	numbers := make(map[string]int, len(coreHeadings))
+	next := 1
+	for _, h := range coreHeadings {
+		if !slices.Contains(opts.OmitSections, h.section) {
+			numbers[h.title] = next
+			next++
+		}
+	}
+	return numbers, next
+}
+
+// icon returns emoji followed by a space when the options use emoji icons,
+// and an empty string otherwise.
+func (opts MarkdownOptions) icon(emoji string) string {
+	if opts.Icons == "" || opts.Icons == "emoji" {
+		return emoji + " "
+	}
+	return ""
 }
 
 // DefaultDescriptionMaxLen is the default maximum length for description fields in tables.
@@ -53,6 +113,7 @@ func DefaultMarkdownOptions() MarkdownOptions {
 // ToMarkdown converts a PRD Document to markdown format.
 func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	var sb strings.Builder
+	sb.Grow(d.estimateMarkdownSize(opts))
 
 	// YAML Frontmatter
 	if opts.IncludeFrontmatter {
@@ -62,6 +123,10 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	// Title
 	sb.WriteString(fmt.Sprintf("# %s\n\n", d.Metadata.Title))
 
+	if notice := common.SupersededNotice("PRD", d.Metadata.SupersededBy); notice != "" {
+		sb.WriteString(fmt.Sprintf("> **%s**\n\n", notice))
+	}
+
 	// Metadata table
 	sb.WriteString(d.generateMetadataTable())
 
@@ -71,29 +136,59 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString(d.generateTableOfContents(opts))
 	}
 
+	numbers, _ := opts.sectionNumbers()
+
 	// Executive Summary
-	sb.WriteString(d.generateExecutiveSummary())
+	if n := numbers["Executive Summary"]; n > 0 {
+		sb.WriteString(d.generateExecutiveSummary(n))
+	}
 
 	// Objectives
-	sb.WriteString(d.generateObjectives())
+	if n := numbers["Objectives and Goals"]; n > 0 {
+		sb.WriteString(d.generateObjectives(n))
+	}
 
 	// Personas
-	sb.WriteString(d.generatePersonas())
+	if n := numbers["Personas"]; n > 0 {
+		sb.WriteString(d.generatePersonas(n))
+	}
 
 	// User Stories
-	sb.WriteString(d.generateUserStories())
+	if n := numbers["User Stories"]; n > 0 {
+		d.writeUserStories(&sb, n)
+	}
 
 	// Requirements
-	sb.WriteString(d.generateRequirements(opts))
+	if n := numbers["Functional Requirements"]; n > 0 {
+		d.writeRequirements(&sb, opts, n)
+	}
 
 	// Roadmap
-	sb.WriteString(d.generateRoadmap(opts))
+	if n := numbers["Roadmap"]; n > 0 {
+		d.writeRoadmap(&sb, opts, n)
+	}
 
 	// Optional sections
+	if len(d.JourneyMaps()) > 0 {
+		sb.WriteString(d.generateJourneyMaps())
+	}
+
+	if len(d.Wireframes()) > 0 || len(d.InteractionFlows()) > 0 {
+		sb.WriteString(d.generateWireframes())
+	}
+
 	if d.TechArchitecture != nil {
 		sb.WriteString(d.generateTechArchitecture())
 	}
 
+	if b := d.Budget(); b.HasCosts() {
+		sb.WriteString(d.generateBudget(b))
+	}
+
+	if len(d.Resourcing) > 0 {
+		sb.WriteString(d.generateResourcing())
+	}
+
 	if d.Assumptions != nil {
 		sb.WriteString(d.generateAssumptions())
 	}
@@ -107,7 +202,7 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	}
 
 	if len(d.OpenItems) > 0 {
-		sb.WriteString(d.generateOpenItems())
+		sb.WriteString(d.generateOpenItems(opts))
 	}
 
 	if d.CurrentState != nil {
@@ -122,6 +217,10 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString(d.generateAppendices())
 	}
 
+	if evidence := d.AllEvidence(); len(evidence) > 0 {
+		sb.WriteString(generateEvidenceAppendix(evidence))
+	}
+
 	if len(d.Glossary) > 0 {
 		sb.WriteString(d.generateGlossary())
 	}
@@ -131,10 +230,18 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString(d.generateCustomSections())
 	}
 
+	if opts.RevisionHistory != "" {
+		sb.WriteString(strings.TrimRight(opts.RevisionHistory, "\n") + "\n\n")
+	}
+
 	// Footer
 	sb.WriteString("\n---\n\n*Generated from structured PRD JSON format*\n")
 
-	return sb.String()
+	md := sb.String()
+	if opts.LinkGlossary == nil || *opts.LinkGlossary {
+		md = common.LinkGlossaryMarkdown(md, d.Glossary)
+	}
+	return addHeadingIDs(md)
 }
 
 func (d *Document) generateFrontmatter(opts MarkdownOptions) string {
@@ -203,10 +310,22 @@ func (d *Document) generateMetadataTable() string {
 	if len(d.Metadata.Authors) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Author(s)** | %s |\n", common.FormatPeopleMarkdown(d.Metadata.Authors)))
 	}
+	if d.Metadata.Team != "" {
+		sb.WriteString(fmt.Sprintf("| **Team** | %s |\n", d.Metadata.Team))
+	}
 
 	if len(d.Metadata.Tags) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Tags** | %s |\n", strings.Join(d.Metadata.Tags, ", ")))
 	}
+	if len(d.Metadata.Supersedes) > 0 {
+		sb.WriteString(fmt.Sprintf("| **Supersedes** | %s |\n", strings.Join(d.Metadata.Supersedes, ", ")))
+	}
+	if d.Metadata.SupersededBy != "" {
+		sb.WriteString(fmt.Sprintf("| **Superseded By** | %s |\n", d.Metadata.SupersededBy))
+	}
+	if len(d.Metadata.ExternalRefs) > 0 {
+		sb.WriteString(fmt.Sprintf("| **External Links** | %s |\n", common.FormatExternalRefsMarkdown(d.Metadata.ExternalRefs, d.Metadata.ExternalRefURLs)))
+	}
 
 	sb.WriteString("\n")
 
@@ -218,74 +337,101 @@ func (d *Document) generateMetadataTable() string {
 	return sb.String()
 }
 
-func (d *Document) generateTableOfContents(_ MarkdownOptions) string {
+func (d *Document) generateTableOfContents(opts MarkdownOptions) string {
 	var sb strings.Builder
 	sb.WriteString("## Table of Contents\n\n")
 
-	// Fixed sections (always present)
-	sb.WriteString("1. [Executive Summary](#1-executive-summary)\n")
-	sb.WriteString("2. [Objectives and Goals](#2-objectives-and-goals)\n")
-	sb.WriteString("3. [Personas](#3-personas)\n")
-	sb.WriteString("4. [User Stories](#4-user-stories)\n")
-	sb.WriteString("5. [Functional Requirements](#5-functional-requirements)\n")
-	sb.WriteString("6. [Non-Functional Requirements](#6-non-functional-requirements)\n")
-	sb.WriteString("7. [Roadmap](#7-roadmap)\n")
+	// Core sections, unless omitted
+	numbers, sectionNum := opts.sectionNumbers()
+	for _, h := range coreHeadings {
+		if n, ok := numbers[h.title]; ok {
+			anchor := headingID(fmt.Sprintf("%d. %s", n, h.title))
+			sb.WriteString(fmt.Sprintf("%d. [%s](#%s)\n", n, h.title, anchor))
+		}
+	}
+
+	// Optional sections
+
+	if len(d.JourneyMaps()) > 0 {
+		sb.WriteString(fmt.Sprintf("%d. [User Journeys](#sec-user-journeys)\n", sectionNum))
+		sectionNum++
+	}
 
-	// Optional sections - track section number
-	sectionNum := 8
+	if len(d.Wireframes()) > 0 || len(d.InteractionFlows()) > 0 {
+		sb.WriteString(fmt.Sprintf("%d. [Wireframes and Flows](#sec-wireframes-and-flows)\n", sectionNum))
+		sectionNum++
+	}
 
 	if d.TechArchitecture != nil {
-		sb.WriteString(fmt.Sprintf("%d. [Technical Architecture](#technical-architecture)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Technical Architecture](#sec-technical-architecture)\n", sectionNum))
+		sectionNum++
+	}
+
+	if d.Budget().HasCosts() {
+		sb.WriteString(fmt.Sprintf("%d. [Budget](#sec-budget)\n", sectionNum))
+		sectionNum++
+	}
+
+	if len(d.Resourcing) > 0 {
+		sb.WriteString(fmt.Sprintf("%d. [Resourcing](#sec-resourcing)\n", sectionNum))
 		sectionNum++
 	}
 
 	if d.Assumptions != nil {
-		sb.WriteString(fmt.Sprintf("%d. [Assumptions and Constraints](#assumptions-and-constraints)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Assumptions and Constraints](#sec-assumptions-and-constraints)\n", sectionNum))
 		sectionNum++
 	}
 
 	if len(d.OutOfScope) > 0 {
-		sb.WriteString(fmt.Sprintf("%d. [Out of Scope](#out-of-scope)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Out of Scope](#sec-out-of-scope)\n", sectionNum))
 		sectionNum++
 	}
 
 	if len(d.Risks) > 0 {
-		sb.WriteString(fmt.Sprintf("%d. [Risk Assessment](#risk-assessment)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Risk Assessment](#sec-risk-assessment)\n", sectionNum))
 		sectionNum++
 	}
 
 	if len(d.OpenItems) > 0 {
-		sb.WriteString(fmt.Sprintf("%d. [Open Items](#open-items)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Open Items](#sec-open-items)\n", sectionNum))
 		sectionNum++
 	}
 
 	if d.CurrentState != nil {
-		sb.WriteString(fmt.Sprintf("%d. [Current State](#current-state)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Current State](#sec-current-state)\n", sectionNum))
 		sectionNum++
 	}
 
 	if d.SecurityModel != nil {
-		sb.WriteString(fmt.Sprintf("%d. [Security Model](#security-model)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Security Model](#sec-security-model)\n", sectionNum))
 		sectionNum++
 	}
 
 	if len(d.Appendices) > 0 {
-		sb.WriteString(fmt.Sprintf("%d. [Appendices](#appendices)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Appendices](#sec-appendices)\n", sectionNum))
+		sectionNum++
+	}
+
+	if len(d.AllEvidence()) > 0 {
+		sb.WriteString(fmt.Sprintf("%d. [Evidence Appendix](#sec-evidence-appendix)\n", sectionNum))
 		sectionNum++
 	}
 
 	if len(d.Glossary) > 0 {
-		sb.WriteString(fmt.Sprintf("%d. [Glossary](#glossary)\n", sectionNum))
+		sb.WriteString(fmt.Sprintf("%d. [Glossary](#sec-glossary)\n", sectionNum))
 		sectionNum++
 	}
 
 	// Custom sections
 	for _, cs := range d.CustomSections {
-		slug := toSlug(cs.Title)
-		sb.WriteString(fmt.Sprintf("%d. [%s](#%s)\n", sectionNum, cs.Title, slug))
+		sb.WriteString(fmt.Sprintf("%d. [%s](#%s)\n", sectionNum, cs.Title, headingID(cs.Title)))
 		sectionNum++
 	}
 
+	if opts.RevisionHistory != "" {
+		sb.WriteString(fmt.Sprintf("%d. [Revision History](#sec-revision-history)\n", sectionNum))
+	}
+
 	sb.WriteString("\n---\n\n")
 	return sb.String()
 }
@@ -304,18 +450,18 @@ func toSlug(s string) string {
 	return result.String()
 }
 
-func (d *Document) generateExecutiveSummary() string {
+func (d *Document) generateExecutiveSummary(n int) string {
 	var sb strings.Builder
-	sb.WriteString("## 1. Executive Summary\n\n")
+	sb.WriteString(fmt.Sprintf("## %d. Executive Summary\n\n", n))
 
-	sb.WriteString("### 1.1 Problem Statement\n\n")
+	sb.WriteString(fmt.Sprintf("### %d.1 Problem Statement\n\n", n))
 	sb.WriteString(d.ExecutiveSummary.ProblemStatement + "\n\n")
 
-	sb.WriteString("### 1.2 Proposed Solution\n\n")
+	sb.WriteString(fmt.Sprintf("### %d.2 Proposed Solution\n\n", n))
 	sb.WriteString(d.ExecutiveSummary.ProposedSolution + "\n\n")
 
 	if len(d.ExecutiveSummary.ExpectedOutcomes) > 0 {
-		sb.WriteString("### 1.3 Expected Outcomes\n\n")
+		sb.WriteString(fmt.Sprintf("### %d.3 Expected Outcomes\n\n", n))
 		for _, outcome := range d.ExecutiveSummary.ExpectedOutcomes {
 			sb.WriteString(fmt.Sprintf("- %s\n", outcome))
 		}
@@ -323,12 +469,12 @@ func (d *Document) generateExecutiveSummary() string {
 	}
 
 	if d.ExecutiveSummary.TargetAudience != "" {
-		sb.WriteString("### 1.4 Target Audience\n\n")
+		sb.WriteString(fmt.Sprintf("### %d.4 Target Audience\n\n", n))
 		sb.WriteString(d.ExecutiveSummary.TargetAudience + "\n\n")
 	}
 
 	if d.ExecutiveSummary.ValueProposition != "" {
-		sb.WriteString("### 1.5 Value Proposition\n\n")
+		sb.WriteString(fmt.Sprintf("### %d.5 Value Proposition\n\n", n))
 		sb.WriteString(d.ExecutiveSummary.ValueProposition + "\n\n")
 	}
 
@@ -336,23 +482,23 @@ func (d *Document) generateExecutiveSummary() string {
 	return sb.String()
 }
 
-func (d *Document) generateObjectives() string {
+func (d *Document) generateObjectives(n int) string {
 	var sb strings.Builder
-	sb.WriteString("## 2. Objectives and Goals\n\n")
+	sb.WriteString(fmt.Sprintf("## %d. Objectives and Goals\n\n", n))
 
 	if len(d.Objectives.OKRs) > 0 {
-		sb.WriteString(d.generateOKRs())
+		sb.WriteString(d.generateOKRs(n))
 	}
 
 	sb.WriteString("---\n\n")
 	return sb.String()
 }
 
-func (d *Document) generateOKRs() string {
+func (d *Document) generateOKRs(n int) string {
 	var sb strings.Builder
 
 	// Objectives overview - quick scan of all objectives
-	sb.WriteString("### 2.1 Objectives Overview\n\n")
+	sb.WriteString(fmt.Sprintf("### %d.1 Objectives Overview\n\n", n))
 	for i, okr := range d.Objectives.OKRs {
 		obj := okr.Objective
 		timeframe := ""
@@ -364,7 +510,7 @@ func (d *Document) generateOKRs() string {
 	sb.WriteString("\n")
 
 	// Detailed OKRs with Key Results
-	sb.WriteString("### 2.2 OKRs (Objectives and Key Results)\n\n")
+	sb.WriteString(fmt.Sprintf("### %d.2 OKRs (Objectives and Key Results)\n\n", n))
 
 	for i, okr := range d.Objectives.OKRs {
 		obj := okr.Objective
@@ -402,8 +548,21 @@ func (d *Document) generateOKRs() string {
 
 		// Key Results table
 		sb.WriteString("**Key Results:**\n\n")
-		sb.WriteString("| KR | Description | Baseline | Target | Current | Confidence |\n")
-		sb.WriteString("|----|-------------|----------|--------|---------|------------|\n")
+		// Show computed progress when any key result's values can be measured
+		showProgress := false
+		for _, kr := range okr.KeyResults {
+			if _, err := kr.ComputeProgress(); err == nil {
+				showProgress = true
+				break
+			}
+		}
+		if showProgress {
+			sb.WriteString("| KR | Description | Baseline | Target | Current | Progress | Confidence |\n")
+			sb.WriteString("|----|-------------|----------|--------|---------|----------|------------|\n")
+		} else {
+			sb.WriteString("| KR | Description | Baseline | Target | Current | Confidence |\n")
+			sb.WriteString("|----|-------------|----------|--------|---------|------------|\n")
+		}
 
 		for j, kr := range okr.KeyResults {
 			baseline := kr.Baseline
@@ -419,17 +578,18 @@ func (d *Document) generateOKRs() string {
 				confidence = kr.Confidence
 			}
 
-			// Format with unit if present
-			target := kr.Target
-			if kr.Unit != "" && target != "-" {
-				target = fmt.Sprintf("%s %s", kr.Target, kr.Unit)
-			}
+			target := keyResultTarget(kr)
 
 			// Use Title if set, otherwise fall back to Description for backward compatibility
 			krTitle := kr.Title
 			if krTitle == "" {
 				krTitle = kr.Description
 			}
+			if showProgress {
+				sb.WriteString(fmt.Sprintf("| KR%d.%d | %s | %s | %s | %s | %s | %s |\n",
+					i+1, j+1, krTitle, baseline, target, current, keyResultProgress(kr), confidence))
+				continue
+			}
 			sb.WriteString(fmt.Sprintf("| KR%d.%d | %s | %s | %s | %s | %s |\n",
 				i+1, j+1, krTitle, baseline, target, current, confidence))
 		}
@@ -461,16 +621,48 @@ func (d *Document) generateOKRs() string {
 	return sb.String()
 }
 
-func (d *Document) generatePersonas() string {
+// keyResultTarget formats the commit and stretch targets with the key
+// result's unit, if present.
+func keyResultTarget(kr KeyResult) string {
+	target, stretch := kr.Target, kr.StretchTarget
+	if kr.Unit != "" {
+		if target != "" {
+			target = fmt.Sprintf("%s %s", target, kr.Unit)
+		}
+		if stretch != "" {
+			stretch = fmt.Sprintf("%s %s", stretch, kr.Unit)
+		}
+	}
+	return okr.FormatTarget(target, stretch)
+}
+
+// keyResultProgress renders a key result's computed progress as a text bar
+// with a percentage, marking metrics that have moved away from their target.
+func keyResultProgress(kr KeyResult) string {
+	p, err := kr.ComputeProgress()
+	if err != nil {
+		return "-"
+	}
+	cell := fmt.Sprintf("%s %.0f%%", okr.ProgressBar(p.Percent, 10), p.Percent*100)
+	if p.HasStretch {
+		cell += " (" + p.Attainment + ")"
+	}
+	if p.Regressed {
+		cell += " ⚠ regressed"
+	}
+	return cell
+}
+
+func (d *Document) generatePersonas(n int) string {
 	var sb strings.Builder
-	sb.WriteString("## 3. Personas\n\n")
+	sb.WriteString(fmt.Sprintf("## %d. Personas\n\n", n))
 
 	for i, p := range d.Personas {
 		primary := ""
 		if p.IsPrimary {
 			primary = " (Primary)"
 		}
-		sb.WriteString(fmt.Sprintf("### 3.%d %s%s\n\n", i+1, p.Name, primary))
+		sb.WriteString(fmt.Sprintf("### %d.%d %s%s\n\n", n, i+1, p.Name, primary))
 
 		sb.WriteString("| Attribute | Description |\n")
 		sb.WriteString("|-----------|-------------|\n")
@@ -502,98 +694,166 @@ func (d *Document) generatePersonas() string {
 	return sb.String()
 }
 
-func (d *Document) generateUserStories() string {
-	var sb strings.Builder
-	sb.WriteString("## 4. User Stories\n\n")
+// writeUserStories writes the user stories section directly into sb.
+// Stories are grouped by persona in a single pass so large documents do not
+// pay for a map of copied slices.
+func (d *Document) writeUserStories(sb *strings.Builder, n int) {
+	sb.WriteString("## ")
+	sb.WriteString(strconv.Itoa(n))
+	sb.WriteString(". User Stories\n\n")
 
-	// Group by persona
-	personaStories := make(map[string][]UserStory)
-	for _, us := range d.UserStories {
-		personaStories[us.PersonaID] = append(personaStories[us.PersonaID], us)
+	// Group story indexes by persona
+	personaStories := make(map[string][]int, len(d.Personas))
+	for i := range d.UserStories {
+		pid := d.UserStories[i].PersonaID
+		personaStories[pid] = append(personaStories[pid], i)
 	}
 
 	sectionNum := 1
 	for _, p := range d.Personas {
-		stories, ok := personaStories[p.ID]
-		if !ok || len(stories) == 0 {
+		indexes := personaStories[p.ID]
+		if len(indexes) == 0 {
 			continue
 		}
 
-		sb.WriteString(fmt.Sprintf("### 4.%d %s Stories\n\n", sectionNum, p.Name))
+		sb.WriteString("### ")
+		sb.WriteString(strconv.Itoa(n))
+		sb.WriteString(".")
+		sb.WriteString(strconv.Itoa(sectionNum))
+		sb.WriteString(" ")
+		sb.WriteString(p.Name)
+		sb.WriteString(" Stories\n\n")
 		sb.WriteString("| ID | Story | Priority | Phase |\n")
 		sb.WriteString("|------|----------------------------------------|----------|-------|\n")
-		for _, us := range stories {
-			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
-				us.ID, us.Story(), us.Priority, us.PhaseID))
+		for _, idx := range indexes {
+			us := &d.UserStories[idx]
+			sb.WriteString("| ")
+			sb.WriteString(us.ID)
+			d.writeExternalRefs(sb, us.ExternalRefs)
+			sb.WriteString(" | As a ")
+			sb.WriteString(us.AsA)
+			sb.WriteString(", I want ")
+			sb.WriteString(us.IWant)
+			sb.WriteString(" so that ")
+			sb.WriteString(us.SoThat)
+			writeTableCells(sb, string(us.Priority), us.PhaseID)
 		}
 		sb.WriteString("\n")
 		sectionNum++
 	}
 
 	sb.WriteString("---\n\n")
-	return sb.String()
 }
 
-func (d *Document) generateRequirements(opts MarkdownOptions) string {
-	var sb strings.Builder
+// nfrCategoryDisplayNames maps NFR categories to their section headings.
+var nfrCategoryDisplayNames = map[NFRCategory]string{
+	NFRPerformance:      "Performance",
+	NFRScalability:      "Scalability",
+	NFRReliability:      "Reliability",
+	NFRAvailability:     "Availability",
+	NFRSecurity:         "Security",
+	NFRMultiTenancy:     "Multi-Tenancy",
+	NFRObservability:    "Observability",
+	NFRMaintainability:  "Maintainability",
+	NFRUsability:        "Usability",
+	NFRCompatibility:    "Compatibility",
+	NFRCompliance:       "Compliance",
+	NFRDisasterRecovery: "Disaster Recovery",
+	NFRCostEfficiency:   "Cost Efficiency",
+}
 
+// writeRequirements writes the functional and non-functional requirements
+// sections, numbered n and n+1, directly into sb.
+func (d *Document) writeRequirements(sb *strings.Builder, opts MarkdownOptions, n int) {
 	// Functional Requirements
-	sb.WriteString("## 5. Functional Requirements\n\n")
+	sb.WriteString("## ")
+	sb.WriteString(strconv.Itoa(n))
+	sb.WriteString(". Functional Requirements\n\n")
 
-	// Group by category
-	categories := make(map[string][]FunctionalRequirement)
-	for _, fr := range d.Requirements.Functional {
-		categories[fr.Category] = append(categories[fr.Category], fr)
+	// Group requirement indexes by category
+	categories := make(map[string][]int)
+	for i := range d.Requirements.Functional {
+		cat := d.Requirements.Functional[i].Category
+		categories[cat] = append(categories[cat], i)
 	}
 
 	// Sort category names for consistent ordering
-	var categoryNames []string
+	categoryNames := make([]string, 0, len(categories))
 	for cat := range categories {
 		categoryNames = append(categoryNames, cat)
 	}
 	sort.Strings(categoryNames)
 
-	sectionNum := 1
-	for _, cat := range categoryNames {
-		reqs := categories[cat]
-		sb.WriteString(fmt.Sprintf("### 5.%d %s\n\n", sectionNum, cat))
-		sb.WriteString("| ID | Title | Description | Priority | Phase |\n")
-		sb.WriteString("|------|-----------------|--------------------------------------------|----------|-------|\n")
-		for _, r := range reqs {
-			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
-				r.ID, r.Title, truncate(r.Description, opts.DescriptionMaxLen), r.Priority, r.PhaseID))
+	for i, cat := range categoryNames {
+		sb.WriteString("### ")
+		sb.WriteString(strconv.Itoa(n))
+		sb.WriteString(".")
+		sb.WriteString(strconv.Itoa(i + 1))
+		sb.WriteString(" ")
+		sb.WriteString(cat)
+		sb.WriteString("\n\n")
+		// Requirements whose descriptions have code or math blocks get
+		// subsections, since the blocks cannot be kept in a table row.
+		var rows, detailed []int
+		for _, idx := range categories[cat] {
+			if richtext.HasBlocks(d.Requirements.Functional[idx].Description) {
+				detailed = append(detailed, idx)
+			} else {
+				rows = append(rows, idx)
+			}
+		}
+		if len(rows) > 0 {
+			sb.WriteString("| ID | Title | Description | Priority | Phase |\n")
+			sb.WriteString("|------|-----------------|--------------------------------------------|----------|-------|\n")
+			for _, idx := range rows {
+				r := &d.Requirements.Functional[idx]
+				sb.WriteString("| ")
+				sb.WriteString(r.ID)
+				d.writeExternalRefs(sb, r.ExternalRefs)
+				writeTableCells(sb, richtext.TableCell(r.Title), richtext.TableCell(truncate(r.Description, opts.DescriptionMaxLen)), string(r.Priority), r.PhaseID)
+			}
+			sb.WriteString("\n")
+		}
+		for _, idx := range detailed {
+			r := &d.Requirements.Functional[idx]
+			sb.WriteString("#### ")
+			sb.WriteString(r.ID)
+			sb.WriteString(": ")
+			sb.WriteString(r.Title)
+			sb.WriteString("\n\n")
+			var facts []string
+			if r.Priority != "" {
+				facts = append(facts, "**Priority:** "+string(r.Priority))
+			}
+			if r.PhaseID != "" {
+				facts = append(facts, "**Phase:** "+r.PhaseID)
+			}
+			if len(r.ExternalRefs) > 0 {
+				facts = append(facts, "**Refs:** "+common.FormatExternalRefsMarkdown(r.ExternalRefs, d.Metadata.ExternalRefURLs))
+			}
+			if len(facts) > 0 {
+				sb.WriteString(strings.Join(facts, " | "))
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString(strings.TrimSpace(r.Description))
+			sb.WriteString("\n\n")
 		}
-		sb.WriteString("\n")
-		sectionNum++
 	}
 
 	// Non-Functional Requirements
-	sb.WriteString("## 6. Non-Functional Requirements\n\n")
-
-	// Group by category
-	nfrCategories := make(map[NFRCategory][]NonFunctionalRequirement)
-	for _, nfr := range d.Requirements.NonFunctional {
-		nfrCategories[nfr.Category] = append(nfrCategories[nfr.Category], nfr)
-	}
-
-	nfrCategoryDisplayNames := map[NFRCategory]string{
-		NFRPerformance:      "Performance",
-		NFRScalability:      "Scalability",
-		NFRReliability:      "Reliability",
-		NFRAvailability:     "Availability",
-		NFRSecurity:         "Security",
-		NFRMultiTenancy:     "Multi-Tenancy",
-		NFRObservability:    "Observability",
-		NFRMaintainability:  "Maintainability",
-		NFRUsability:        "Usability",
-		NFRCompatibility:    "Compatibility",
-		NFRCompliance:       "Compliance",
-		NFRDisasterRecovery: "Disaster Recovery",
-		NFRCostEfficiency:   "Cost Efficiency",
+	sb.WriteString("## ")
+	sb.WriteString(strconv.Itoa(n + 1))
+	sb.WriteString(". Non-Functional Requirements\n\n")
+
+	// Group requirement indexes by category
+	nfrCategories := make(map[NFRCategory][]int)
+	for i := range d.Requirements.NonFunctional {
+		cat := d.Requirements.NonFunctional[i].Category
+		nfrCategories[cat] = append(nfrCategories[cat], i)
 	}
 
 	// Sort NFR category keys for consistent ordering
-	var nfrCategoryKeys []NFRCategory
+	nfrCategoryKeys := make([]NFRCategory, 0, len(nfrCategories))
 	for cat := range nfrCategories {
 		nfrCategoryKeys = append(nfrCategoryKeys, cat)
 	}
@@ -601,35 +861,44 @@ func (d *Document) generateRequirements(opts MarkdownOptions) string {
 		return string(nfrCategoryKeys[i]) < string(nfrCategoryKeys[j])
 	})
 
-	sectionNum = 1
-	for _, cat := range nfrCategoryKeys {
-		reqs := nfrCategories[cat]
+	for i, cat := range nfrCategoryKeys {
 		catName := nfrCategoryDisplayNames[cat]
 		if catName == "" {
 			catName = string(cat)
 		}
-		sb.WriteString(fmt.Sprintf("### 6.%d %s\n\n", sectionNum, catName))
+		sb.WriteString("### ")
+		sb.WriteString(strconv.Itoa(n + 1))
+		sb.WriteString(".")
+		sb.WriteString(strconv.Itoa(i + 1))
+		sb.WriteString(" ")
+		sb.WriteString(catName)
+		sb.WriteString("\n\n")
 		sb.WriteString("| ID | Title | Target | Priority | Phase |\n")
 		sb.WriteString("|----|-------|--------|----------|-------|\n")
-		for _, r := range reqs {
-			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
-				r.ID, r.Title, r.Target, r.Priority, r.PhaseID))
+		for _, idx := range nfrCategories[cat] {
+			r := &d.Requirements.NonFunctional[idx]
+			sb.WriteString("| ")
+			sb.WriteString(r.ID)
+			d.writeExternalRefs(sb, r.ExternalRefs)
+			writeTableCells(sb, r.Title, r.Target, string(r.Priority), r.PhaseID)
 		}
 		sb.WriteString("\n")
-		sectionNum++
 	}
 
 	sb.WriteString("---\n\n")
-	return sb.String()
 }
 
-func (d *Document) generateRoadmap(opts MarkdownOptions) string {
-	var sb strings.Builder
-	sb.WriteString("## 7. Roadmap\n\n")
+// writeRoadmap writes the roadmap section, numbered n, directly into sb.
+func (d *Document) writeRoadmap(sb *strings.Builder, opts MarkdownOptions, n int) {
+	sb.WriteString(fmt.Sprintf("## %d. Roadmap\n\n", n))
+
+	// Overview subsections ahead of the phase details, numbered from n.1
+	sub := 1
 
 	// Swimlane table view (phases as columns, deliverable types as rows)
 	if opts.IncludeSwimlaneTable && len(d.Roadmap.Phases) > 0 {
-		sb.WriteString("### 7.1 Roadmap Overview (Swimlane View)\n\n")
+		sb.WriteString(fmt.Sprintf("### %d.%d Roadmap Overview (Swimlane View)\n\n", n, sub))
+		sub++
 		tableOpts := DefaultRoadmapTableOptions()
 		if opts.RoadmapTableOptions != nil {
 			tableOpts = *opts.RoadmapTableOptions
@@ -638,30 +907,69 @@ func (d *Document) generateRoadmap(opts MarkdownOptions) string {
 		if len(d.Objectives.OKRs) > 0 {
 			tableOpts.IncludeOKRs = true
 		}
+		switch opts.Icons {
+		case "text":
+			tableOpts.TextStatus = true
+		case "none":
+			tableOpts.IncludeStatus = false
+		}
 		sb.WriteString(d.ToSwimlaneTableWithOKRs(tableOpts))
 		sb.WriteString("\n")
-		if tableOpts.IncludeStatus {
+		if tableOpts.IncludeStatus && !tableOpts.TextStatus {
 			sb.WriteString("**Legend:**\n\n")
 			sb.WriteString(StatusLegend())
 			sb.WriteString("\n")
 		}
-		sb.WriteString("### 7.2 Phase Details\n\n")
 	}
 
-	for _, phase := range d.Roadmap.Phases {
-		sb.WriteString(fmt.Sprintf("### %s: %s\n\n", phase.ID, phase.Name))
+	if opts.IncludeRoadmapDiagrams {
+		if gantt := d.Roadmap.MermaidGantt(""); gantt != "" {
+			sb.WriteString(fmt.Sprintf("### %d.%d Timeline\n\n", n, sub))
+			sb.WriteString("```mermaid\n" + gantt + "```\n\n")
+			sub++
+		}
+		var deps []Dependency
+		if d.Assumptions != nil {
+			deps = d.Assumptions.Dependencies
+		}
+		if graph := d.Roadmap.MermaidDependencyGraph(deps); graph != "" {
+			sb.WriteString(fmt.Sprintf("### %d.%d Dependency Graph\n\n", n, sub))
+			sb.WriteString("```mermaid\n" + graph + "```\n\n")
+			sub++
+		}
+	}
+
+	if sub > 1 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Phase Details\n\n", n, sub))
+	}
+
+	for i := range d.Roadmap.Phases {
+		phase := &d.Roadmap.Phases[i]
+		sb.WriteString("### ")
+		sb.WriteString(phase.ID)
+		sb.WriteString(": ")
+		sb.WriteString(phase.Name)
+		sb.WriteString("\n\n")
+
+		sb.WriteString("**Type:** ")
+		sb.WriteString(string(phase.Type))
+		sb.WriteString("\n\n")
 
-		sb.WriteString(fmt.Sprintf("**Type:** %s\n\n", phase.Type))
+		if phase.StartDate != nil || phase.EndDate != nil {
+			sb.WriteString("**Schedule:** ")
+			sb.WriteString(phaseWindow(phase))
+			sb.WriteString("\n\n")
+		}
 
 		if len(phase.Dependencies) > 0 {
-			sb.WriteString(fmt.Sprintf("**Dependencies:** %s\n\n", strings.Join(phase.Dependencies, ", ")))
+			sb.WriteString("**Dependencies:** ")
+			sb.WriteString(strings.Join(phase.Dependencies, ", "))
+			sb.WriteString("\n\n")
 		}
 
 		if len(phase.Goals) > 0 {
 			sb.WriteString("**Goals:**\n\n")
-			for _, g := range phase.Goals {
-				sb.WriteString(fmt.Sprintf("- %s\n", g))
-			}
+			writeBulletList(sb, phase.Goals)
 			sb.WriteString("\n")
 		}
 
@@ -669,25 +977,84 @@ func (d *Document) generateRoadmap(opts MarkdownOptions) string {
 			sb.WriteString("**Deliverables:**\n\n")
 			sb.WriteString("| ID | Title | Type | Status |\n")
 			sb.WriteString("|----|-------|------|--------|\n")
-			for _, del := range phase.Deliverables {
-				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
-					del.ID, del.Title, del.Type, del.Status))
+			for j := range phase.Deliverables {
+				del := &phase.Deliverables[j]
+				sb.WriteString("| ")
+				sb.WriteString(del.ID)
+				writeTableCells(sb, del.Title, string(del.Type), string(del.Status))
 			}
 			sb.WriteString("\n")
 		}
 
 		if len(phase.SuccessCriteria) > 0 {
 			sb.WriteString("**Success Criteria:**\n\n")
-			for _, sc := range phase.SuccessCriteria {
-				sb.WriteString(fmt.Sprintf("- %s\n", sc))
-			}
+			writeBulletList(sb, phase.SuccessCriteria)
 			sb.WriteString("\n")
 		}
 
 		sb.WriteString("---\n\n")
 	}
+}
 
-	return sb.String()
+// writeExternalRefs appends an entity's external refs as links after its ID
+// in a table cell. It writes nothing when there are none.
+func (d *Document) writeExternalRefs(sb *strings.Builder, refs ExternalRefs) {
+	if len(refs) == 0 {
+		return
+	}
+	sb.WriteString(" (")
+	sb.WriteString(common.FormatExternalRefsMarkdown(refs, d.Metadata.ExternalRefURLs))
+	sb.WriteString(")")
+}
+
+// writeTableCells appends the remaining cells of a markdown table row whose
+// first cell has already been written, and terminates the row. It avoids
+// fmt.Sprintf, which dominates rendering time for tables with thousands of rows.
+func writeTableCells(sb *strings.Builder, cells ...string) {
+	for _, c := range cells {
+		sb.WriteString(" | ")
+		sb.WriteString(c)
+	}
+	sb.WriteString(" |\n")
+}
+
+// writeBulletList writes each item as a markdown bullet.
+func writeBulletList(sb *strings.Builder, items []string) {
+	for _, item := range items {
+		sb.WriteString("- ")
+		sb.WriteString(item)
+		sb.WriteString("\n")
+	}
+}
+
+// estimateMarkdownSize returns a rough byte count for the rendered document,
+// used to preallocate the output builder. It only counts the sections that
+// scale with document size; the remainder is absorbed by a fixed allowance.
+func (d *Document) estimateMarkdownSize(opts MarkdownOptions) int {
+	size := 16 * 1024
+	for i := range d.UserStories {
+		us := &d.UserStories[i]
+		size += 48 + len(us.ID) + len(us.AsA) + len(us.IWant) + len(us.SoThat) + len(us.Priority) + len(us.PhaseID)
+	}
+	for i := range d.Requirements.Functional {
+		r := &d.Requirements.Functional[i]
+		descLen := len(r.Description)
+		if opts.DescriptionMaxLen > 0 && descLen > opts.DescriptionMaxLen {
+			descLen = opts.DescriptionMaxLen
+		}
+		size += 20 + len(r.ID) + len(r.Title) + descLen + len(r.Priority) + len(r.PhaseID)
+	}
+	for i := range d.Requirements.NonFunctional {
+		r := &d.Requirements.NonFunctional[i]
+		size += 20 + len(r.ID) + len(r.Title) + len(r.Target) + len(r.Priority) + len(r.PhaseID)
+	}
+	for i := range d.Roadmap.Phases {
+		for j := range d.Roadmap.Phases[i].Deliverables {
+			del := &d.Roadmap.Phases[i].Deliverables[j]
+			size += 16 + len(del.ID) + len(del.Title) + len(del.Type) + len(del.Status)
+		}
+	}
+	return size
 }
 
 func (d *Document) generateTechArchitecture() string {
@@ -699,6 +1066,11 @@ func (d *Document) generateTechArchitecture() string {
 		sb.WriteString(d.TechArchitecture.Overview + "\n\n")
 	}
 
+	if d.TechArchitecture.SystemDiagram != "" {
+		sb.WriteString("### System Diagram\n\n")
+		sb.WriteString(assets.Markdown("System Diagram", d.TechArchitecture.SystemDiagram) + "\n\n")
+	}
+
 	if len(d.TechArchitecture.IntegrationPoints) > 0 {
 		sb.WriteString("### Integration Points\n\n")
 		sb.WriteString("| ID | Name | Type | Description | Auth Method |\n")
@@ -741,13 +1113,12 @@ func (d *Document) generateAssumptions() string {
 	}
 
 	if len(d.Assumptions.Dependencies) > 0 {
-		sb.WriteString("### Dependencies\n\n")
-		sb.WriteString("| ID | Name | Type | Status |\n")
-		sb.WriteString("|----|------|------|--------|\n")
-		for _, dep := range d.Assumptions.Dependencies {
-			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
-				dep.ID, dep.Name, dep.Type, dep.Status))
+		phaseNames := make(map[string]string, len(d.Roadmap.Phases))
+		for _, p := range d.Roadmap.Phases {
+			phaseNames[p.ID] = p.Name
 		}
+		sb.WriteString("### Dependencies\n\n")
+		sb.WriteString(common.FormatDependencyTable(d.Assumptions.Dependencies, phaseNames))
 		sb.WriteString("\n")
 	}
 
@@ -782,7 +1153,7 @@ func (d *Document) generateRisks() string {
 	return sb.String()
 }
 
-func (d *Document) generateOpenItems() string {
+func (d *Document) generateOpenItems(opts MarkdownOptions) string {
 	var sb strings.Builder
 	sb.WriteString("## Open Items\n\n")
 	sb.WriteString("*The following items require decisions. Please review the options and tradeoffs.*\n\n")
@@ -792,17 +1163,17 @@ func (d *Document) generateOpenItems() string {
 		statusBadge := ""
 		switch item.Status {
 		case OpenItemStatusOpen:
-			statusBadge = "🔴 Open"
+			statusBadge = opts.icon("🔴") + "Open"
 		case OpenItemStatusInDiscussion:
-			statusBadge = "🟡 In Discussion"
+			statusBadge = opts.icon("🟡") + "In Discussion"
 		case OpenItemStatusBlocked:
-			statusBadge = "⛔ Blocked"
+			statusBadge = opts.icon("⛔") + "Blocked"
 		case OpenItemStatusResolved:
-			statusBadge = "✅ Resolved"
+			statusBadge = opts.icon("✅") + "Resolved"
 		case OpenItemStatusDeferred:
-			statusBadge = "⏸️ Deferred"
+			statusBadge = opts.icon("⏸️") + "Deferred"
 		default:
-			statusBadge = "🔴 Open"
+			statusBadge = opts.icon("🔴") + "Open"
 		}
 
 		sb.WriteString(fmt.Sprintf("### %d. %s\n\n", i+1, item.Title))
@@ -831,7 +1202,7 @@ func (d *Document) generateOpenItems() string {
 			for _, opt := range item.Options {
 				recommended := ""
 				if opt.Recommended {
-					recommended = "⭐ Yes"
+					recommended = opts.icon("⭐") + "Yes"
 				}
 				sb.WriteString(fmt.Sprintf("| **%s** | %s | %s | %s | %s |\n",
 					opt.Title, opt.Description, opt.Effort, opt.Risk, recommended))
@@ -843,20 +1214,20 @@ func (d *Document) generateOpenItems() string {
 				if len(opt.Pros) > 0 || len(opt.Cons) > 0 {
 					sb.WriteString(fmt.Sprintf("**%s**", opt.Title))
 					if opt.Recommended {
-						sb.WriteString(" ⭐ *Recommended*")
+						sb.WriteString(" " + opts.icon("⭐") + "*Recommended*")
 					}
 					sb.WriteString("\n\n")
 
 					if len(opt.Pros) > 0 {
 						sb.WriteString("*Pros:*\n")
 						for _, pro := range opt.Pros {
-							sb.WriteString(fmt.Sprintf("- ✅ %s\n", pro))
+							sb.WriteString(fmt.Sprintf("- %s%s\n", opts.icon("✅"), pro))
 						}
 					}
 					if len(opt.Cons) > 0 {
 						sb.WriteString("\n*Cons:*\n")
 						for _, con := range opt.Cons {
-							sb.WriteString(fmt.Sprintf("- ⚠️ %s\n", con))
+							sb.WriteString(fmt.Sprintf("- %s%s\n", opts.icon("⚠️"), con))
 						}
 					}
 					if opt.RecommendationRationale != "" {
@@ -1218,6 +1589,29 @@ func (d *Document) generateAppendices() string {
 }
 
 // indexToLetter converts a 0-based index to a letter (A, B, C, ..., Z, AA, AB, ...).
+func generateEvidenceAppendix(items []EvidenceItem) string {
+	var sb strings.Builder
+	sb.WriteString("## Evidence Appendix\n\n")
+	sb.WriteString("| ID | Problem | Type | Source | Summary | Sample | Strength | Date | Links |\n")
+	sb.WriteString("|----|---------|------|--------|---------|--------|----------|------|-------|\n")
+	for _, e := range items {
+		sample := "-"
+		if e.SampleSize > 0 {
+			sample = strconv.Itoa(e.SampleSize)
+		}
+		links := make([]string, len(e.Links))
+		for i, link := range e.Links {
+			links[i] = fmt.Sprintf("[%d](%s)", i+1, link)
+		}
+		sb.WriteString("| ")
+		sb.WriteString(e.ID)
+		writeTableCells(&sb, e.ProblemID, string(e.Type), e.Source, e.Summary,
+			sample, string(e.Strength), e.Date, strings.Join(links, ", "))
+	}
+	sb.WriteString("\n---\n\n")
+	return sb.String()
+}
+
 func indexToLetter(i int) string {
 	if i < 26 {
 		return string(rune('A' + i))
@@ -1232,14 +1626,15 @@ func (d *Document) generateGlossary() string {
 
 	sb.WriteString("| Term | Definition |\n")
 	sb.WriteString("|------|------------|\n")
-	for _, term := range d.Glossary {
+	anchors := common.GlossaryAnchors(d.Glossary)
+	for i, term := range d.Glossary {
 		var name string
 		if term.Acronym != "" {
 			name = fmt.Sprintf("**%s** (%s)", term.Term, term.Acronym)
 		} else {
 			name = fmt.Sprintf("**%s**", term.Term)
 		}
-		sb.WriteString(fmt.Sprintf("| %s | %s |\n", name, term.Definition))
+		sb.WriteString(fmt.Sprintf("| <a id=\"%s\"></a>%s | %s |\n", anchors[i], name, term.Definition))
 	}
 	sb.WriteString("\n---\n\n")
 
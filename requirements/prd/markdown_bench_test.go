@@ -0,0 +1,119 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// newLargeDocument builds a synthetic PRD with n functional requirements,
+// n user stories, and n/10 non-functional requirements spread across
+// personas, categories, and phases.
+func newLargeDocument(n int) *Document {
+	doc := New("PRD-BENCH-001", "Benchmark PRD", Person{Name: "Bench Author"})
+	doc.ExecutiveSummary = ExecutiveSummary{
+		ProblemStatement: "Large documents render slowly.",
+		ProposedSolution: "Render them faster.",
+		ExpectedOutcomes: []string{"Sub-second rendering"},
+	}
+
+	const numPersonas = 8
+	const numPhases = 6
+	categories := []string{"Authentication", "Billing", "Reporting", "Search", "Notifications", "Admin"}
+	nfrCategories := []NFRCategory{NFRPerformance, NFRSecurity, NFRReliability, NFRScalability}
+
+	for i := 0; i < numPersonas; i++ {
+		doc.Personas = append(doc.Personas, Persona{
+			ID:          fmt.Sprintf("PER-%d", i+1),
+			Name:        fmt.Sprintf("Persona %d", i+1),
+			Role:        "User",
+			Description: "A representative user",
+			Goals:       []string{"Get work done"},
+			PainPoints:  []string{"Slow tools"},
+		})
+	}
+
+	for i := 0; i < numPhases; i++ {
+		doc.Roadmap.Phases = append(doc.Roadmap.Phases, Phase{
+			ID:   fmt.Sprintf("PHASE-%d", i+1),
+			Name: fmt.Sprintf("Phase %d", i+1),
+			Type: PhaseTypeQuarter,
+		})
+	}
+
+	doc.UserStories = make([]UserStory, 0, n)
+	doc.Requirements.Functional = make([]FunctionalRequirement, 0, n)
+	for i := 0; i < n; i++ {
+		phaseID := fmt.Sprintf("PHASE-%d", i%numPhases+1)
+		doc.UserStories = append(doc.UserStories, UserStory{
+			ID:        fmt.Sprintf("US-%05d", i+1),
+			PersonaID: fmt.Sprintf("PER-%d", i%numPersonas+1),
+			Title:     fmt.Sprintf("Story %d", i+1),
+			AsA:       "user",
+			IWant:     "to complete a task quickly",
+			SoThat:    "I can move on to the next one",
+			Priority:  PriorityHigh,
+			PhaseID:   phaseID,
+		})
+		doc.Requirements.Functional = append(doc.Requirements.Functional, FunctionalRequirement{
+			ID:          fmt.Sprintf("FR-%05d", i+1),
+			Title:       fmt.Sprintf("Requirement %d", i+1),
+			Description: strings.Repeat("The system shall do something useful. ", 3),
+			Category:    categories[i%len(categories)],
+			Priority:    MoSCoWMust,
+			PhaseID:     phaseID,
+		})
+		if i%10 == 0 {
+			doc.Requirements.NonFunctional = append(doc.Requirements.NonFunctional, NonFunctionalRequirement{
+				ID:       fmt.Sprintf("NFR-%05d", i/10+1),
+				Category: nfrCategories[(i/10)%len(nfrCategories)],
+				Title:    fmt.Sprintf("NFR %d", i/10+1),
+				Target:   "p99 < 200ms",
+				Priority: MoSCoWShould,
+				PhaseID:  phaseID,
+			})
+		}
+	}
+
+	for i := range doc.Roadmap.Phases {
+		for j := 0; j < n/numPhases/10; j++ {
+			doc.Roadmap.Phases[i].Deliverables = append(doc.Roadmap.Phases[i].Deliverables, Deliverable{
+				ID:     fmt.Sprintf("DEL-%d-%d", i+1, j+1),
+				Title:  fmt.Sprintf("Deliverable %d", j+1),
+				Type:   DeliverableFeature,
+				Status: DeliverableNotStarted,
+			})
+		}
+	}
+
+	return doc
+}
+
+func benchmarkToMarkdown(b *testing.B, n int) {
+	doc := newLargeDocument(n)
+	opts := DefaultMarkdownOptions()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = doc.ToMarkdown(opts)
+	}
+}
+
+func BenchmarkToMarkdown100(b *testing.B)   { benchmarkToMarkdown(b, 100) }
+func BenchmarkToMarkdown1000(b *testing.B)  { benchmarkToMarkdown(b, 1000) }
+func BenchmarkToMarkdown5000(b *testing.B)  { benchmarkToMarkdown(b, 5000) }
+func BenchmarkToMarkdown20000(b *testing.B) { benchmarkToMarkdown(b, 20000) }
+
+func TestToMarkdownLargeDocument(t *testing.T) {
+	doc := newLargeDocument(5000)
+	md := doc.ToMarkdown(DefaultMarkdownOptions())
+
+	for _, want := range []string{"| FR-05000 |", "| US-05000 |", "| NFR-00500 |", "### 5.6 Search"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q", want)
+		}
+	}
+	if got := strings.Count(md, "| FR-"); got != 5000 {
+		t.Errorf("expected 5000 functional requirement rows, got %d", got)
+	}
+}
@@ -0,0 +1,228 @@
+package prd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OnePagerView is a one-page executive brief of a PRD, for leadership
+// reviews: the problem, the solution, the top key results and risks, and
+// the next milestone. Like the 6-pager, it is a deterministic
+// transformation from PRD data.
+type OnePagerView struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Owner   string `json:"owner"`
+	PRDID   string `json:"prdId"`
+
+	Problem       string              `json:"problem"`
+	Solution      string              `json:"solution"`
+	KeyResults    []OnePagerKeyResult `json:"keyResults"`
+	Risks         []RiskSummary       `json:"risks"`
+	NextMilestone *OnePagerMilestone  `json:"nextMilestone,omitempty"`
+}
+
+// OnePagerKeyResult is a key result with its objective.
+type OnePagerKeyResult struct {
+	Objective string `json:"objective"`
+	KeyResult string `json:"keyResult"`
+	Baseline  string `json:"baseline,omitempty"`
+	Target    string `json:"target"`
+}
+
+// OnePagerMilestone is the end of the first roadmap phase that is not yet
+// completed.
+type OnePagerMilestone struct {
+	Phase        string   `json:"phase"`
+	Date         string   `json:"date,omitempty"` // YYYY-MM-DD
+	Status       string   `json:"status,omitempty"`
+	Deliverables []string `json:"deliverables,omitempty"`
+}
+
+// onePagerLimit is the number of key results and risks in a one-pager.
+const onePagerLimit = 3
+
+// GenerateOnePagerView creates a one-page executive brief from a PRD.
+//
+// The key results are taken in turn from each objective, so that the top
+// three span the objectives. The risks are the open ones with the highest
+// impact, then probability. The next milestone is the end of the first
+// phase, in roadmap order, that is neither completed nor cancelled.
+func GenerateOnePagerView(doc *Document) *OnePagerView {
+	view := &OnePagerView{
+		Title:   doc.Metadata.Title,
+		Version: doc.Metadata.Version,
+		Status:  string(doc.Metadata.Status),
+		PRDID:   doc.Metadata.ID,
+	}
+	if len(doc.Metadata.Authors) > 0 {
+		view.Owner = doc.Metadata.Authors[0].Name
+	}
+
+	view.Problem = doc.ExecutiveSummary.ProblemStatement
+	if doc.Problem != nil && doc.Problem.Statement != "" {
+		view.Problem = doc.Problem.Statement
+	}
+	view.Solution = doc.ExecutiveSummary.ProposedSolution
+	if doc.Solution != nil {
+		if selected := doc.Solution.SelectedSolution(); selected != nil && selected.Description != "" {
+			view.Solution = selected.Description
+		}
+	}
+
+	// Key results, one from each objective in turn
+	for i := 0; len(view.KeyResults) < onePagerLimit; i++ {
+		added := false
+		for _, okr := range doc.Objectives.OKRs {
+			if i >= len(okr.KeyResults) || len(view.KeyResults) >= onePagerLimit {
+				continue
+			}
+			kr := okr.KeyResults[i]
+			view.KeyResults = append(view.KeyResults, OnePagerKeyResult{
+				Objective: okr.Objective.Description,
+				KeyResult: kr.Description,
+				Baseline:  kr.Baseline,
+				Target:    kr.Target,
+			})
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+
+	// Open risks by impact, then probability
+	var open []Risk
+	for _, r := range doc.Risks {
+		if r.Status != RiskStatusMitigated && r.Status != RiskStatusClosed {
+			open = append(open, r)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		if a, b := riskImpactRank(open[i].Impact), riskImpactRank(open[j].Impact); a != b {
+			return a > b
+		}
+		return riskProbabilityRank(open[i].Probability) > riskProbabilityRank(open[j].Probability)
+	})
+	for _, r := range open[:min(len(open), onePagerLimit)] {
+		view.Risks = append(view.Risks, RiskSummary{
+			Description: r.Description,
+			Impact:      string(r.Impact),
+			Mitigation:  r.Mitigation,
+		})
+	}
+
+	for _, p := range doc.Roadmap.Phases {
+		if p.Status == PhaseStatusCompleted || p.Status == PhaseStatusCancelled {
+			continue
+		}
+		m := &OnePagerMilestone{Phase: p.Name, Status: string(p.Status)}
+		if p.EndDate != nil {
+			m.Date = p.EndDate.Format("2006-01-02")
+		}
+		for _, d := range p.Deliverables {
+			m.Deliverables = append(m.Deliverables, d.Title)
+		}
+		view.NextMilestone = m
+		break
+	}
+
+	return view
+}
+
+func riskImpactRank(i RiskImpact) int {
+	switch i {
+	case RiskImpactCritical:
+		return 4
+	case RiskImpactHigh:
+		return 3
+	case RiskImpactMedium:
+		return 2
+	case RiskImpactLow:
+		return 1
+	}
+	return 0
+}
+
+func riskProbabilityRank(p RiskProbability) int {
+	switch p {
+	case RiskProbabilityHigh:
+		return 3
+	case RiskProbabilityMedium:
+		return 2
+	case RiskProbabilityLow:
+		return 1
+	}
+	return 0
+}
+
+// RenderOnePagerMarkdown generates markdown output for the one-pager view.
+func RenderOnePagerMarkdown(view *OnePagerView) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s: Executive Brief\n\n", view.Title))
+	var meta []string
+	for _, f := range [][2]string{{"Status", view.Status}, {"Owner", view.Owner}, {"Version", view.Version}} {
+		if f[1] != "" {
+			meta = append(meta, fmt.Sprintf("**%s:** %s", f[0], f[1]))
+		}
+	}
+	if len(meta) > 0 {
+		sb.WriteString(strings.Join(meta, " | ") + "\n\n")
+	}
+
+	sb.WriteString("## Problem\n\n")
+	sb.WriteString(orTBD(view.Problem) + "\n\n")
+
+	sb.WriteString("## Solution\n\n")
+	sb.WriteString(orTBD(view.Solution) + "\n\n")
+
+	if len(view.KeyResults) > 0 {
+		sb.WriteString("## Key Results\n\n")
+		sb.WriteString("| Objective | Key Result | Baseline | Target |\n")
+		sb.WriteString("|-----------|------------|----------|--------|\n")
+		for _, kr := range view.KeyResults {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				kr.Objective, kr.KeyResult, orDash(kr.Baseline), orDash(kr.Target)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(view.Risks) > 0 {
+		sb.WriteString("## Top Risks\n\n")
+		sb.WriteString("| Risk | Impact | Mitigation |\n")
+		sb.WriteString("|------|--------|------------|\n")
+		for _, r := range view.Risks {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n",
+				r.Description, orDash(r.Impact), orDash(r.Mitigation)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if m := view.NextMilestone; m != nil {
+		sb.WriteString("## Next Milestone\n\n")
+		sb.WriteString("**" + m.Phase + "**")
+		if m.Date != "" {
+			sb.WriteString(", due " + m.Date)
+		}
+		if m.Status != "" {
+			sb.WriteString(" (" + strings.ReplaceAll(m.Status, "_", " ") + ")")
+		}
+		sb.WriteString("\n\n")
+		if len(m.Deliverables) > 0 {
+			writeBulletList(&sb, m.Deliverables)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func orTBD(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "_To be defined._"
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateOnePagerView(t *testing.T) {
+	due := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	doc := &Document{
+		Metadata: Metadata{ID: "PRD-5", Title: "Checkout", Version: "1.2.0", Status: StatusInReview, Authors: []Person{{Name: "Jane Smith"}}},
+		ExecutiveSummary: ExecutiveSummary{
+			ProblemStatement: "Carts are abandoned",
+			ProposedSolution: "One-click checkout",
+		},
+		Problem: &ProblemDefinition{Statement: "40% of carts are abandoned at payment"},
+		Objectives: Objectives{OKRs: []OKR{
+			{Objective: Objective{Description: "Convert more carts"}, KeyResults: []KeyResult{
+				{Description: "Checkout conversion", Baseline: "60%", Target: "75%"},
+				{Description: "Payment errors", Target: "< 1%"},
+			}},
+			{Objective: Objective{Description: "Grow repeat buyers"}, KeyResults: []KeyResult{
+				{Description: "Repeat purchase rate", Target: "30%"},
+				{Description: "Saved cards", Target: "50%"},
+			}},
+		}},
+		Risks: []Risk{
+			{Description: "Chargebacks", Impact: RiskImpactMedium, Probability: RiskProbabilityHigh},
+			{Description: "PCI scope", Impact: RiskImpactCritical, Probability: RiskProbabilityLow, Mitigation: "Tokenize cards"},
+			{Description: "Vendor outage", Impact: RiskImpactHigh, Status: RiskStatusMitigated},
+			{Description: "Fraud", Impact: RiskImpactMedium, Probability: RiskProbabilityMedium},
+			{Description: "Latency", Impact: RiskImpactLow},
+		},
+		Roadmap: Roadmap{Phases: []Phase{
+			{ID: "ph-1", Name: "Alpha", Status: PhaseStatusCompleted},
+			{ID: "ph-2", Name: "Beta", Status: PhaseStatusInProgress, EndDate: &due,
+				Deliverables: []Deliverable{{Title: "Saved cards"}}},
+			{ID: "ph-3", Name: "GA"},
+		}},
+	}
+
+	view := GenerateOnePagerView(doc)
+	if view.Problem != "40% of carts are abandoned at payment" || view.Solution != "One-click checkout" || view.Owner != "Jane Smith" {
+		t.Errorf("problem/solution/owner = %q/%q/%q", view.Problem, view.Solution, view.Owner)
+	}
+
+	var krs []string
+	for _, kr := range view.KeyResults {
+		krs = append(krs, kr.KeyResult)
+	}
+	if got := strings.Join(krs, ","); got != "Checkout conversion,Repeat purchase rate,Payment errors" {
+		t.Errorf("key results = %s", got)
+	}
+
+	var risks []string
+	for _, r := range view.Risks {
+		risks = append(risks, r.Description)
+	}
+	if got := strings.Join(risks, ","); got != "PCI scope,Chargebacks,Fraud" {
+		t.Errorf("risks = %s", got)
+	}
+
+	if m := view.NextMilestone; m == nil || m.Phase != "Beta" || m.Date != "2026-06-30" || len(m.Deliverables) != 1 {
+		t.Errorf("next milestone = %+v", view.NextMilestone)
+	}
+
+	md := RenderOnePagerMarkdown(view)
+	for _, want := range []string{
+		"# Checkout: Executive Brief",
+		"**Status:** in_review | **Owner:** Jane Smith | **Version:** 1.2.0",
+		"| Convert more carts | Checkout conversion | 60% | 75% |",
+		"| PCI scope | critical | Tokenize cards |",
+		"**Beta**, due 2026-06-30 (in progress)",
+		"- Saved cards",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestGenerateOnePagerViewMinimal(t *testing.T) {
+	view := GenerateOnePagerView(&Document{Metadata: Metadata{Title: "Empty"}})
+	if view.NextMilestone != nil || view.KeyResults != nil || view.Risks != nil {
+		t.Errorf("empty PRD should give an empty brief: %+v", view)
+	}
+	md := RenderOnePagerMarkdown(view)
+	if !strings.Contains(md, "## Problem\n\n_To be defined._") || strings.Contains(md, "## Key Results") {
+		t.Errorf("markdown =\n%s", md)
+	}
+}
@@ -1,5 +1,7 @@
 package prd
 
+import "github.com/grokify/structured-plan/common"
+
 // AssumptionsConstraints contains assumptions and constraints.
 type AssumptionsConstraints struct {
 	Assumptions  []Assumption `json:"assumptions"`
@@ -7,16 +9,11 @@ type AssumptionsConstraints struct {
 	Dependencies []Dependency `json:"dependencies,omitempty"`
 }
 
-// Dependency represents an external dependency.
-type Dependency struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Type        string `json:"type,omitempty"` // API, Service, Team, Vendor
-	Owner       string `json:"owner,omitempty"`
-	Status      string `json:"status,omitempty"` // Available, Pending, Blocked
-	DueDate     string `json:"dueDate,omitempty"`
-}
+// Dependency is an alias for common.Dependency for backwards compatibility.
+type Dependency = common.Dependency
+
+// Cost is an alias for common.Cost.
+type Cost = common.Cost
 
 // TechnicalArchitecture contains technical design information.
 type TechnicalArchitecture struct {
@@ -59,6 +56,7 @@ type Technology struct {
 	Purpose      string   `json:"purpose,omitempty"`
 	Rationale    string   `json:"rationale,omitempty"`
 	Alternatives []string `json:"alternatives,omitempty"` // Considered alternatives
+	Cost         *Cost    `json:"cost,omitempty"`
 }
 
 // UXRequirements contains UX/UI requirements.
@@ -69,6 +67,9 @@ type UXRequirements struct {
 	Accessibility    AccessibilitySpec `json:"accessibility,omitempty"`
 	BrandGuidelines  string            `json:"brandGuidelines,omitempty"` // URL or path
 	DesignSystem     string            `json:"designSystem,omitempty"`    // URL or path
+
+	// JourneyMaps describe how personas move through the product.
+	JourneyMaps []JourneyMap `json:"journeyMaps,omitempty"`
 }
 
 // Wireframe represents a wireframe or mockup.
@@ -78,6 +79,9 @@ type Wireframe struct {
 	Description string `json:"description,omitempty"`
 	URL         string `json:"url"`              // Link to wireframe
 	Status      string `json:"status,omitempty"` // Draft, Approved
+
+	// Flows are the IDs of the interaction flows the wireframe belongs to.
+	Flows []string `json:"flows,omitempty"`
 }
 
 // InteractionFlow represents a user interaction flow.
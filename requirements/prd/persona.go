@@ -29,6 +29,7 @@ type Persona struct {
 	IsPrimary            bool                 `json:"isPrimary,omitempty"`  // Is this the primary persona?
 	LibraryRef           string               `json:"libraryRef,omitempty"` // Reference to persona in library (for tracking origin)
 	Tags                 []string             `json:"tags,omitempty"`       // For filtering by topic/domain
+	Feedback             []FeedbackRef        `json:"feedback,omitempty"`   // Customer feedback informing this persona
 }
 
 // Demographics contains optional demographic information.
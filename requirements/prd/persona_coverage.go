@@ -0,0 +1,272 @@
+package prd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// UnscheduledPhaseName is the coverage column of work with no phase.
+const UnscheduledPhaseName = "Unscheduled"
+
+// PersonaCoverage is a heatmap of how many user stories and functional
+// requirements serve each persona in each roadmap phase.
+type PersonaCoverage struct {
+	// Phases are the columns, in roadmap order, followed by an unscheduled
+	// column with an empty ID when some work has no phase, or one not on
+	// the roadmap.
+	Phases []CoveragePhase `json:"phases"`
+
+	// EarlyPhases is the number of leading phases counted as early: the
+	// first half of the roadmap, rounded up.
+	EarlyPhases int `json:"earlyPhases"`
+
+	Personas []PersonaCoverageRow `json:"personas"`
+}
+
+// CoveragePhase is a column of a persona coverage heatmap.
+type CoveragePhase struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// PersonaCoverageRow is the work serving one persona, with a cell for each
+// phase.
+type PersonaCoverageRow struct {
+	PersonaID string         `json:"personaId"`
+	Name      string         `json:"name"`
+	Primary   bool           `json:"primary,omitempty"`
+	Cells     []CoverageCell `json:"cells"`
+
+	// NeglectedEarly is set when no story or requirement serves the
+	// persona in the early phases.
+	NeglectedEarly bool `json:"neglectedEarly,omitempty"`
+}
+
+// CoverageCell counts the work serving a persona in a phase.
+type CoverageCell struct {
+	UserStories  int `json:"userStories"`
+	Requirements int `json:"requirements"`
+}
+
+// Total returns the number of stories and requirements in the cell.
+func (c CoverageCell) Total() int {
+	return c.UserStories + c.Requirements
+}
+
+// Total returns the number of stories and requirements serving the
+// persona across all phases.
+func (r PersonaCoverageRow) Total() int {
+	var n int
+	for _, c := range r.Cells {
+		n += c.Total()
+	}
+	return n
+}
+
+// PersonaCoverage counts the user stories and functional requirements
+// serving each persona in each roadmap phase. A story serves its persona
+// in its phase. A requirement serves the personas of its user stories, in
+// its own phase or, if it has none, in the phases of those stories.
+func (d *Document) PersonaCoverage() *PersonaCoverage {
+	cov := &PersonaCoverage{}
+	column := map[string]int{}
+	for _, p := range d.Roadmap.Phases {
+		column[p.ID] = len(cov.Phases)
+		cov.Phases = append(cov.Phases, CoveragePhase{ID: p.ID, Name: firstNonEmpty(p.Name, p.ID)})
+	}
+	cov.EarlyPhases = (len(cov.Phases) + 1) / 2
+	unscheduled := -1
+	columnOf := func(phaseID string) int {
+		if i, ok := column[phaseID]; ok {
+			return i
+		}
+		if unscheduled < 0 {
+			unscheduled = len(cov.Phases)
+			cov.Phases = append(cov.Phases, CoveragePhase{Name: UnscheduledPhaseName})
+			for i := range cov.Personas {
+				cov.Personas[i].Cells = append(cov.Personas[i].Cells, CoverageCell{})
+			}
+		}
+		return unscheduled
+	}
+
+	row := map[string]int{}
+	for _, p := range d.Personas {
+		row[p.ID] = len(cov.Personas)
+		cov.Personas = append(cov.Personas, PersonaCoverageRow{
+			PersonaID: p.ID,
+			Name:      firstNonEmpty(p.Name, p.ID),
+			Primary:   p.IsPrimary,
+			Cells:     make([]CoverageCell, len(cov.Phases)),
+		})
+	}
+
+	stories := map[string]UserStory{}
+	for _, s := range d.UserStories {
+		stories[s.ID] = s
+		if r, ok := row[s.PersonaID]; ok {
+			cov.Personas[r].Cells[columnOf(s.PhaseID)].UserStories++
+		}
+	}
+	for _, fr := range d.Requirements.Functional {
+		counted := map[[2]int]bool{}
+		for _, id := range fr.UserStoryIDs {
+			s, ok := stories[id]
+			if !ok {
+				continue
+			}
+			r, ok := row[s.PersonaID]
+			if !ok {
+				continue
+			}
+			c := columnOf(firstNonEmpty(fr.PhaseID, s.PhaseID))
+			if !counted[[2]int{r, c}] {
+				counted[[2]int{r, c}] = true
+				cov.Personas[r].Cells[c].Requirements++
+			}
+		}
+	}
+
+	if cov.EarlyPhases > 0 {
+		for i := range cov.Personas {
+			neglected := true
+			for _, c := range cov.Personas[i].Cells[:cov.EarlyPhases] {
+				if c.Total() > 0 {
+					neglected = false
+				}
+			}
+			cov.Personas[i].NeglectedEarly = neglected
+		}
+	}
+	return cov
+}
+
+// Scheduled reports whether any story or requirement serving a persona is
+// in a roadmap phase, so that early neglect means something.
+func (c *PersonaCoverage) Scheduled() bool {
+	for _, r := range c.Personas {
+		for i, cell := range r.Cells {
+			if c.Phases[i].ID != "" && cell.Total() > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Neglected returns the personas no story or requirement serves in the
+// early phases.
+func (c *PersonaCoverage) Neglected() []PersonaCoverageRow {
+	var rows []PersonaCoverageRow
+	for _, r := range c.Personas {
+		if r.NeglectedEarly {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// ToMarkdown renders the heatmap as a table of personas by phase, each
+// cell giving stories / requirements. Cells are shaded by their share of
+// the busiest cell, and empty early cells are marked red.
+func (c *PersonaCoverage) ToMarkdown() string {
+	if len(c.Personas) == 0 || len(c.Phases) == 0 {
+		return "No personas or roadmap phases to map.\n"
+	}
+	var sb strings.Builder
+	busiest := 0
+	for _, r := range c.Personas {
+		for _, cell := range r.Cells {
+			busiest = max(busiest, cell.Total())
+		}
+	}
+
+	sb.WriteString("| Persona |")
+	for i, p := range c.Phases {
+		name := p.Name
+		if i < c.EarlyPhases {
+			name += " (early)"
+		}
+		sb.WriteString(" " + name + " |")
+	}
+	sb.WriteString(" Total |\n|---------|")
+	sb.WriteString(strings.Repeat("---|", len(c.Phases)+1) + "\n")
+	for _, r := range c.Personas {
+		name := r.Name
+		if r.Primary {
+			name = "**" + name + "** (primary)"
+		}
+		if r.NeglectedEarly {
+			name += " ⚠️"
+		}
+		sb.WriteString("| " + name + " |")
+		for i, cell := range r.Cells {
+			sb.WriteString(fmt.Sprintf(" %s %d / %d |", heat(cell.Total(), busiest, i < c.EarlyPhases), cell.UserStories, cell.Requirements))
+		}
+		sb.WriteString(fmt.Sprintf(" %d |\n", r.Total()))
+	}
+	sb.WriteString("\nCells are user stories / functional requirements. 🟩 most work, 🟨 some, ⬜ none, 🟥 none in an early phase.\n")
+
+	if neglected := c.Neglected(); len(neglected) > 0 {
+		var early []string
+		for _, p := range c.Phases[:c.EarlyPhases] {
+			early = append(early, p.Name)
+		}
+		sb.WriteString(fmt.Sprintf("\n**Neglected in early phases** (%s):\n\n", strings.Join(early, ", ")))
+		for _, r := range neglected {
+			if r.Total() == 0 {
+				sb.WriteString(fmt.Sprintf("- %s: no stories or requirements at all\n", r.Name))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("- %s: first served in %s\n", r.Name, c.firstServed(r)))
+		}
+	}
+	return sb.String()
+}
+
+// firstServed returns the name of the first phase with work for the row.
+func (c *PersonaCoverage) firstServed(r PersonaCoverageRow) string {
+	for i, cell := range r.Cells {
+		if cell.Total() > 0 {
+			return c.Phases[i].Name
+		}
+	}
+	return ""
+}
+
+// heat returns the shade of a cell with n items when the busiest cell has
+// busiest.
+func heat(n, busiest int, early bool) string {
+	switch {
+	case n == 0 && early:
+		return "🟥"
+	case n == 0:
+		return "⬜"
+	case n*3 > busiest*2:
+		return "🟩"
+	default:
+		return "🟨"
+	}
+}
+
+// WriteCSV writes the heatmap as CSV, one row per persona and phase.
+func (c *PersonaCoverage) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"persona_id", "persona", "phase_id", "phase", "early", "user_stories", "requirements", "total"}}
+	for _, r := range c.Personas {
+		for i, cell := range r.Cells {
+			records = append(records, []string{
+				r.PersonaID, r.Name, c.Phases[i].ID, c.Phases[i].Name,
+				strconv.FormatBool(i < c.EarlyPhases),
+				strconv.Itoa(cell.UserStories), strconv.Itoa(cell.Requirements), strconv.Itoa(cell.Total()),
+			})
+		}
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("writing coverage CSV: %w", err)
+	}
+	return nil
+}
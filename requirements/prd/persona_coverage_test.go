@@ -0,0 +1,106 @@
+package prd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func personaCoverageDoc() *Document {
+	return &Document{
+		Personas: []Persona{
+			{ID: "per-dev", Name: "Developer", IsPrimary: true, PainPoints: []string{"Slow builds"}},
+			{ID: "per-admin", Name: "Admin"},
+			{ID: "per-auditor", Name: "Auditor"},
+		},
+		Roadmap: Roadmap{Phases: []Phase{
+			{ID: "ph-1", Name: "Alpha"},
+			{ID: "ph-2", Name: "Beta"},
+			{ID: "ph-3", Name: "GA"},
+		}},
+		UserStories: []UserStory{
+			{ID: "US-1", PersonaID: "per-dev", PhaseID: "ph-1"},
+			{ID: "US-2", PersonaID: "per-dev", PhaseID: "ph-1"},
+			{ID: "US-3", PersonaID: "per-admin", PhaseID: "ph-3"},
+			{ID: "US-4", PersonaID: "per-admin"},
+		},
+		Requirements: Requirements{Functional: []FunctionalRequirement{
+			{ID: "FR-1", UserStoryIDs: []string{"US-1", "US-2"}},          // Counted once for Developer in Alpha
+			{ID: "FR-2", UserStoryIDs: []string{"US-1"}, PhaseID: "ph-2"}, // Its own phase wins
+			{ID: "FR-3", UserStoryIDs: []string{"US-3", "US-missing"}},
+		}},
+	}
+}
+
+func TestPersonaCoverage(t *testing.T) {
+	cov := personaCoverageDoc().PersonaCoverage()
+
+	var phases []string
+	for _, p := range cov.Phases {
+		phases = append(phases, p.Name)
+	}
+	if got := strings.Join(phases, ","); got != "Alpha,Beta,GA,Unscheduled" {
+		t.Errorf("phases = %s", got)
+	}
+	if cov.EarlyPhases != 2 {
+		t.Errorf("EarlyPhases = %d, want 2", cov.EarlyPhases)
+	}
+
+	dev, admin, auditor := cov.Personas[0], cov.Personas[1], cov.Personas[2]
+	if dev.Cells[0] != (CoverageCell{UserStories: 2, Requirements: 1}) || dev.Cells[1] != (CoverageCell{Requirements: 1}) {
+		t.Errorf("developer cells = %+v", dev.Cells)
+	}
+	if admin.Cells[2] != (CoverageCell{UserStories: 1, Requirements: 1}) || admin.Cells[3] != (CoverageCell{UserStories: 1}) {
+		t.Errorf("admin cells = %+v", admin.Cells)
+	}
+	if dev.NeglectedEarly || !admin.NeglectedEarly || !auditor.NeglectedEarly || auditor.Total() != 0 {
+		t.Errorf("neglected dev/admin/auditor = %v/%v/%v", dev.NeglectedEarly, admin.NeglectedEarly, auditor.NeglectedEarly)
+	}
+	if !cov.Scheduled() || len(cov.Neglected()) != 2 {
+		t.Errorf("Scheduled = %v, Neglected = %d", cov.Scheduled(), len(cov.Neglected()))
+	}
+
+	md := cov.ToMarkdown()
+	for _, want := range []string{
+		"| Persona | Alpha (early) | Beta (early) | GA | Unscheduled | Total |",
+		"| **Developer** (primary) | 🟩 2 / 1 | 🟨 0 / 1 | ⬜ 0 / 0 | ⬜ 0 / 0 | 4 |",
+		"| Admin ⚠️ | 🟥 0 / 0 |",
+		"- Admin: first served in GA",
+		"- Auditor: no stories or requirements at all",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := cov.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	csv := buf.String()
+	if !strings.Contains(csv, "per-dev,Developer,ph-1,Alpha,true,2,1,3\n") || !strings.Contains(csv, "per-admin,Admin,,Unscheduled,false,1,0,1\n") {
+		t.Errorf("CSV =\n%s", csv)
+	}
+}
+
+func TestScoreUserUnderstandingNeglectedPersonas(t *testing.T) {
+	doc := personaCoverageDoc()
+	score := scoreUserUnderstanding(doc)
+	var lost []string
+	for _, ref := range score.References {
+		if ref.Reason == "neglected in early phases" {
+			lost = append(lost, ref.ID+" "+ref.Pointer)
+		}
+	}
+	if strings.Join(lost, ",") != "per-admin /personas/1,per-auditor /personas/2" {
+		t.Errorf("neglected references = %v", lost)
+	}
+
+	// Serving every persona early restores the point.
+	doc.UserStories = append(doc.UserStories,
+		UserStory{ID: "US-5", PersonaID: "per-admin", PhaseID: "ph-2"},
+		UserStory{ID: "US-6", PersonaID: "per-auditor", PhaseID: "ph-1"})
+	if fixed := scoreUserUnderstanding(doc); fixed.Score != score.Score+1 {
+		t.Errorf("score = %.1f, want %.1f", fixed.Score, score.Score+1)
+	}
+}
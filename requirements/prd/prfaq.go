@@ -2,6 +2,8 @@ package prd
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -11,20 +13,81 @@ import (
 // on the press release and frequently asked questions.
 type PRFAQView struct {
 	// Metadata
-	Title   string `json:"title"`
-	Version string `json:"version"`
-	Author  string `json:"author"`
-	Date    string `json:"date"`
-	PRDID   string `json:"prdId"`
+	Title      string `json:"title"`
+	Version    string `json:"version"`
+	Author     string `json:"author"`
+	Date       string `json:"date"`
+	LaunchDate string `json:"launchDate,omitempty"` // Date of the press release
+	PRDID      string `json:"prdId"`
 
 	// The two main sections
 	PressRelease PressReleaseSection `json:"pressRelease"`
 	FAQ          FAQSection          `json:"faq"`
 }
 
+// FAQEntry is a question the PRD authors answer for the PR/FAQ and
+// 6-pager.
+type FAQEntry struct {
+	ID       string   `json:"id,omitempty"`
+	Group    string   `json:"group,omitempty"` // customer (default), internal, or technical
+	Question string   `json:"question"`
+	Answer   string   `json:"answer"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// Customer quote sources of a PR/FAQ.
+const (
+	QuoteSourcePersona  = "persona"
+	QuoteSourceEvidence = "evidence"
+)
+
+// PRFAQOptions controls how a PR/FAQ is written.
+type PRFAQOptions struct {
+	// LaunchDate dates the press release. When zero, it is the latest end
+	// date on the roadmap, and the press release is undated without one.
+	LaunchDate time.Time
+
+	// QuoteSource is where the customer quote comes from. With
+	// QuoteSourcePersona (the default), it is written from a template for
+	// the primary persona. With QuoteSourceEvidence, it is a customer's
+	// own words from the feedback or evidence on the problem, and is left
+	// out if there are none.
+	QuoteSource string
+
+	// OmitQuotes leaves out both press release quotes.
+	OmitQuotes bool
+
+	// FAQGroups selects the FAQ groups, by the names in FAQGroups (nil =
+	// all, empty = none).
+	FAQGroups []string
+
+	// FAQTags keeps only the FAQs with at least one of the tags. Authored
+	// FAQs carry their own tags; synthesized ones carry those of the
+	// persona or risk they come from.
+	FAQTags []string
+}
+
+// Validate returns an error if an FAQ group or the quote source is unknown.
+func (o PRFAQOptions) Validate() error {
+	if err := (SixPagerOptions{FAQGroups: o.FAQGroups}).Validate(); err != nil {
+		return err
+	}
+	switch o.QuoteSource {
+	case "", QuoteSourcePersona, QuoteSourceEvidence:
+		return nil
+	}
+	return fmt.Errorf("unknown quote source %q (use %s or %s)", o.QuoteSource, QuoteSourcePersona, QuoteSourceEvidence)
+}
+
 // GeneratePRFAQView creates an Amazon-style PR/FAQ view from a PRD.
 // This reuses the press release and FAQ generation logic from the 6-pager.
 func GeneratePRFAQView(doc *Document) *PRFAQView {
+	return GeneratePRFAQViewWithOptions(doc, PRFAQOptions{})
+}
+
+// GeneratePRFAQViewWithOptions creates a PR/FAQ view from a PRD with the
+// launch date, quotes, and FAQs the options select.
+func GeneratePRFAQViewWithOptions(doc *Document, opts PRFAQOptions) *PRFAQView {
 	view := &PRFAQView{
 		Title:   doc.Metadata.Title,
 		Version: doc.Metadata.Version,
@@ -36,13 +99,97 @@ func GeneratePRFAQView(doc *Document) *PRFAQView {
 		view.Author = doc.Metadata.Authors[0].Name
 	}
 
+	launch := opts.LaunchDate
+	if launch.IsZero() {
+		for _, p := range doc.Roadmap.Phases {
+			if p.EndDate != nil && p.EndDate.After(launch) {
+				launch = *p.EndDate
+			}
+		}
+	}
+	if !launch.IsZero() {
+		view.LaunchDate = launch.Format("January 2, 2006")
+	}
+
 	// Reuse the generation functions from 6-pager
 	view.PressRelease = generatePressRelease(doc)
 	view.FAQ = generateFAQ(doc)
 
+	switch {
+	case opts.OmitQuotes:
+		view.PressRelease.Quote = Quote{}
+		view.PressRelease.CustomerQuote = Quote{}
+	case opts.QuoteSource == QuoteSourceEvidence:
+		view.PressRelease.CustomerQuote = evidenceQuote(doc)
+	}
+
+	view.FAQ.keepGroups(opts.FAQGroups)
+	if len(opts.FAQTags) > 0 {
+		for _, faqs := range []*[]FAQ{&view.FAQ.CustomerFAQs, &view.FAQ.InternalFAQs, &view.FAQ.TechnicalFAQs} {
+			*faqs = filterSliceByTagsFunc(*faqs, opts.FAQTags, func(f FAQ) []string { return f.Tags }, hasAnyTag)
+		}
+	}
+
 	return view
 }
 
+// evidenceQuote returns a customer's own words on the problem: the first
+// feedback with a summary on the problem or, primary first, a persona;
+// else the strongest interview, survey, or support ticket evidence. It
+// returns an empty quote if there is none.
+func evidenceQuote(doc *Document) Quote {
+	feedbackQuote := func(fb FeedbackRef, role string) Quote {
+		return Quote{
+			Speaker: firstNonEmpty(fb.Customer, "A customer"),
+			Role:    role,
+			Text:    fb.Summary,
+			Source:  strings.TrimSpace(fb.Source + " " + fb.ExternalID),
+		}
+	}
+
+	if doc.Problem != nil {
+		for _, fb := range doc.Problem.Feedback {
+			if fb.Summary != "" {
+				return feedbackQuote(fb, "")
+			}
+		}
+	}
+	personas := slices.Clone(doc.Personas)
+	sort.SliceStable(personas, func(i, j int) bool { return personas[i].IsPrimary && !personas[j].IsPrimary })
+	for _, p := range personas {
+		for _, fb := range p.Feedback {
+			if fb.Summary != "" {
+				return feedbackQuote(fb, p.Role)
+			}
+		}
+	}
+
+	if doc.Problem == nil {
+		return Quote{}
+	}
+	rank := map[EvidenceStrength]int{StrengthHigh: 3, StrengthMedium: 2, StrengthLow: 1}
+	var best *Evidence
+	for i, e := range doc.Problem.Evidence {
+		switch e.Type {
+		case EvidenceInterview, EvidenceSurvey, EvidenceSupportTicket:
+		default:
+			continue
+		}
+		if e.Summary != "" && (best == nil || rank[e.Strength] > rank[best.Strength]) {
+			best = &doc.Problem.Evidence[i]
+		}
+	}
+	if best == nil {
+		return Quote{}
+	}
+	return Quote{
+		Speaker: best.Source,
+		Role:    strings.ReplaceAll(string(best.Type), "_", " "),
+		Text:    best.Summary,
+		Source:  best.ID,
+	}
+}
+
 // RenderPRFAQMarkdown generates markdown output for the PR/FAQ view.
 func RenderPRFAQMarkdown(view *PRFAQView) string {
 	var sb strings.Builder
@@ -60,7 +207,12 @@ func RenderPRFAQMarkdown(view *PRFAQView) string {
 		sb.WriteString(fmt.Sprintf("*%s*\n\n", view.PressRelease.Subheadline))
 	}
 
-	if view.PressRelease.Summary != "" {
+	switch {
+	case view.LaunchDate != "" && view.PressRelease.Summary != "":
+		sb.WriteString(fmt.Sprintf("**%s** — %s\n\n", view.LaunchDate, view.PressRelease.Summary))
+	case view.LaunchDate != "":
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", view.LaunchDate))
+	case view.PressRelease.Summary != "":
 		sb.WriteString(view.PressRelease.Summary + "\n\n")
 	}
 
@@ -70,21 +222,8 @@ func RenderPRFAQMarkdown(view *PRFAQView) string {
 	sb.WriteString("#### The Solution\n\n")
 	sb.WriteString(view.PressRelease.Solution + "\n\n")
 
-	if view.PressRelease.Quote.Text != "" {
-		sb.WriteString(fmt.Sprintf("> \"%s\"\n>\n> — %s", view.PressRelease.Quote.Text, view.PressRelease.Quote.Speaker))
-		if view.PressRelease.Quote.Role != "" {
-			sb.WriteString(fmt.Sprintf(", %s", view.PressRelease.Quote.Role))
-		}
-		sb.WriteString("\n\n")
-	}
-
-	if view.PressRelease.CustomerQuote.Text != "" {
-		sb.WriteString(fmt.Sprintf("> \"%s\"\n>\n> — %s", view.PressRelease.CustomerQuote.Text, view.PressRelease.CustomerQuote.Speaker))
-		if view.PressRelease.CustomerQuote.Role != "" {
-			sb.WriteString(fmt.Sprintf(", %s", view.PressRelease.CustomerQuote.Role))
-		}
-		sb.WriteString("\n\n")
-	}
+	writeQuote(&sb, view.PressRelease.Quote)
+	writeQuote(&sb, view.PressRelease.CustomerQuote)
 
 	if len(view.PressRelease.Benefits) > 0 {
 		sb.WriteString("#### Key Benefits\n\n")
@@ -98,9 +237,11 @@ func RenderPRFAQMarkdown(view *PRFAQView) string {
 		sb.WriteString(fmt.Sprintf("**%s**\n\n", view.PressRelease.CallToAction))
 	}
 
+	// FAQ Section, left out when no questions are selected
+	if len(view.FAQ.CustomerFAQs)+len(view.FAQ.InternalFAQs)+len(view.FAQ.TechnicalFAQs) == 0 {
+		return sb.String()
+	}
 	sb.WriteString("---\n\n")
-
-	// FAQ Section
 	sb.WriteString("## Frequently Asked Questions\n\n")
 
 	if len(view.FAQ.CustomerFAQs) > 0 {
@@ -132,3 +273,19 @@ func RenderPRFAQMarkdown(view *PRFAQView) string {
 
 	return sb.String()
 }
+
+// writeQuote writes a press release quote as a blockquote, citing its
+// source when it is not written from a template.
+func writeQuote(sb *strings.Builder, q Quote) {
+	if q.Text == "" {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("> \"%s\"\n>\n> — %s", q.Text, q.Speaker))
+	if q.Role != "" {
+		sb.WriteString(fmt.Sprintf(", %s", q.Role))
+	}
+	if q.Source != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", q.Source))
+	}
+	sb.WriteString("\n\n")
+}
@@ -3,6 +3,7 @@ package prd
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGeneratePRFAQView(t *testing.T) {
@@ -233,3 +234,91 @@ func TestPRFAQViewComparedToSixPager(t *testing.T) {
 		t.Error("PR/FAQ and 6-pager should have same internal FAQs")
 	}
 }
+
+func TestGeneratePRFAQViewWithOptions(t *testing.T) {
+	ga := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	beta := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	doc := &Document{
+		Metadata: Metadata{ID: "PRD-004", Title: "Fast Checkout", Authors: []Person{{Name: "Alice Smith"}}},
+		ExecutiveSummary: ExecutiveSummary{
+			ProblemStatement: "Checkout is slow",
+			ProposedSolution: "One-click checkout",
+		},
+		Problem: &ProblemDefinition{
+			Statement: "Checkout takes five steps",
+			Evidence: []Evidence{
+				{ID: "EV-1", Type: EvidenceAnalytics, Source: "Dashboard", Summary: "40% drop-off", Strength: StrengthHigh},
+				{ID: "EV-2", Type: EvidenceInterview, Source: "Store owner", Summary: "I lose sales at payment", Strength: StrengthMedium},
+				{ID: "EV-3", Type: EvidenceSurvey, Source: "Q1 survey", Summary: "Too many steps", Strength: StrengthLow},
+			},
+		},
+		Personas: []Persona{
+			{ID: "P-1", Name: "Shopper", Role: "Buyer", IsPrimary: true, Goals: []string{"Pay fast"}, PainPoints: []string{"Slow checkout"}, Tags: []string{"mobile"}},
+		},
+		Risks: []Risk{
+			{ID: "R-1", Description: "Fraud rises", Impact: RiskImpactHigh, Mitigation: "Risk scoring", Tags: []string{"security"}},
+		},
+		FAQs: []FAQEntry{
+			{Group: FAQGroupCustomer, Question: "Does it store my card?", Answer: "Only a token.", Tags: []string{"security"}},
+			{Group: FAQGroupInternal, Question: "Who owns payments?", Answer: "The payments team."},
+		},
+		Roadmap: Roadmap{Phases: []Phase{
+			{ID: "ga", Name: "GA", EndDate: &ga},
+			{ID: "beta", Name: "Beta", EndDate: &beta},
+		}},
+	}
+
+	// Defaults: launch on the last roadmap date, authored FAQs first
+	view := GeneratePRFAQView(doc)
+	if view.LaunchDate != "September 30, 2026" {
+		t.Errorf("LaunchDate = %q", view.LaunchDate)
+	}
+	if faqs := view.FAQ.CustomerFAQs; len(faqs) == 0 || faqs[0].Question != "Does it store my card?" {
+		t.Errorf("authored FAQ should lead the customer FAQs: %+v", faqs)
+	}
+	if faqs := view.FAQ.InternalFAQs; len(faqs) == 0 || faqs[0].Question != "Who owns payments?" {
+		t.Errorf("authored FAQ should lead the internal FAQs: %+v", faqs)
+	}
+	if q := view.PressRelease.CustomerQuote; q.Speaker != "Shopper" || q.Source != "" {
+		t.Errorf("default customer quote should come from the persona template: %+v", q)
+	}
+
+	view = GeneratePRFAQViewWithOptions(doc, PRFAQOptions{
+		LaunchDate:  time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC),
+		QuoteSource: QuoteSourceEvidence,
+		FAQTags:     []string{"security"},
+	})
+	if view.LaunchDate != "January 15, 2027" {
+		t.Errorf("LaunchDate = %q", view.LaunchDate)
+	}
+	if q := view.PressRelease.CustomerQuote; q.Text != "I lose sales at payment" || q.Speaker != "Store owner" || q.Source != "EV-2" {
+		t.Errorf("customer quote should be the strongest interview or survey evidence: %+v", q)
+	}
+	var questions []string
+	for _, f := range append(view.FAQ.CustomerFAQs, view.FAQ.InternalFAQs...) {
+		questions = append(questions, f.Question)
+	}
+	if got := strings.Join(questions, " | "); got != "Does it store my card? | What if Fraud rises?" {
+		t.Errorf("FAQs tagged security = %s", got)
+	}
+
+	md := RenderPRFAQMarkdown(view)
+	for _, want := range []string{
+		"**January 15, 2027** — One-click checkout",
+		"> — Store owner, interview (EV-2)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	view = GeneratePRFAQViewWithOptions(doc, PRFAQOptions{FAQGroups: []string{}, OmitQuotes: true})
+	md = RenderPRFAQMarkdown(view)
+	if strings.Contains(md, "Frequently Asked Questions") || strings.Contains(md, "> —") {
+		t.Errorf("no FAQs or quotes expected:\n%s", md)
+	}
+
+	if err := (PRFAQOptions{QuoteSource: "made-up"}).Validate(); err == nil {
+		t.Error("unknown quote source should be invalid")
+	}
+}
@@ -23,12 +23,18 @@ type ProblemDefinition struct {
 	// AffectedSegments are user segments affected by this problem.
 	AffectedSegments []string `json:"affectedSegments,omitempty"`
 
+	// Feedback links customer feedback describing this problem.
+	Feedback []FeedbackRef `json:"feedback,omitempty"`
+
 	// SecondaryProblems are related or secondary problems.
 	SecondaryProblems []ProblemDefinition `json:"secondaryProblems,omitempty"`
 }
 
 // Evidence supports a problem statement or claim.
 type Evidence struct {
+	// ID is an optional identifier for referencing the evidence (e.g., EV-001).
+	ID string `json:"id,omitempty"`
+
 	// Type categorizes the evidence source.
 	Type EvidenceType `json:"type"`
 
@@ -44,8 +50,12 @@ type Evidence struct {
 	// Strength indicates how strong the evidence is.
 	Strength EvidenceStrength `json:"strength,omitempty"`
 
-	// Date is when the evidence was collected.
+	// Date is when the evidence was collected (YYYY-MM-DD).
 	Date string `json:"date,omitempty"`
+
+	// Links point to the underlying material, such as research reports,
+	// dashboards, or interview notes.
+	Links []string `json:"links,omitempty"`
 }
 
 // EvidenceType categorizes evidence sources.
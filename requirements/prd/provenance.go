@@ -0,0 +1,41 @@
+package prd
+
+// Provenance records the upstream document a PRD was derived from and which
+// of its items each PRD item came from.
+type Provenance struct {
+	// Source identifies the upstream document.
+	Source SourceRef `json:"source"`
+
+	// Links maps PRD item IDs to the source item IDs they were derived from.
+	Links []ProvenanceLink `json:"links,omitempty"`
+}
+
+// SourceRef identifies an upstream planning document.
+type SourceRef struct {
+	Type    string `json:"type"` // mrd, prd, okr, etc.
+	ID      string `json:"id"`
+	Title   string `json:"title,omitempty"`
+	Path    string `json:"path,omitempty"` // Relative path to the source file
+	Version string `json:"version,omitempty"`
+}
+
+// ProvenanceLink ties one PRD item to the source items it was derived from.
+type ProvenanceLink struct {
+	ItemID    string   `json:"itemId"`
+	Kind      string   `json:"kind"` // persona, objective, keyResult, requirement, outOfScope, etc.
+	SourceIDs []string `json:"sourceIds"`
+}
+
+// SourcesFor returns the source item IDs recorded for the PRD item with the
+// given ID, or nil if the item has no provenance.
+func (p *Provenance) SourcesFor(itemID string) []string {
+	if p == nil {
+		return nil
+	}
+	for _, l := range p.Links {
+		if l.ItemID == itemID {
+			return l.SourceIDs
+		}
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+// Package html provides a standalone HTML renderer for PRD documents. The
+// output is a single file with embedded styles, collapsible sections, anchor
+// links, and a print stylesheet, suitable for sharing without a toolchain.
+package html
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/assets"
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+)
+
+// Renderer implements the render.Renderer interface for HTML output.
+type Renderer struct{}
+
+// New creates a new PRD HTML renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "html"
+}
+
+// FileExtension returns the file extension for HTML output.
+func (r *Renderer) FileExtension() string {
+	return ".html"
+}
+
+// Render converts a PRD to a standalone HTML page.
+func (r *Renderer) Render(doc *prd.Document, opts *render.Options) ([]byte, error) {
+	return r.Page(doc, opts).Render()
+}
+
+// Page lays out a PRD as a page without rendering it, so other formats
+// can reuse the sections.
+func (r *Renderer) Page(doc *prd.Document, opts *render.Options) *htmldoc.Page {
+	if opts == nil {
+		opts = render.DefaultOptions()
+	}
+	m := doc.Metadata
+	page := htmldoc.NewPage("Product Requirements", m.Title)
+	page.Summary = doc.ExecutiveSummary.ValueProposition
+	page.CustomCSS = opts.CustomCSS
+	page.KaTeXURL = opts.KaTeXURL
+	page.Meta = []htmldoc.Field{
+		{Label: "ID", Value: m.ID},
+		{Label: "Version", Value: m.Version},
+		{Label: "Status", Value: string(m.Status)},
+		{Label: "Authors", Value: htmldoc.People(m.Authors)},
+		{Label: "Team", Value: m.Team},
+		{Label: "Updated", Value: htmldoc.Date(m.UpdatedAt)},
+		{Label: "Supersedes", Value: strings.Join(m.Supersedes, ", ")},
+	}
+	page.Banner = common.SupersededNotice("PRD", m.SupersededBy)
+	page.Links = m.ExternalRefs.Links(m.ExternalRefURLs)
+
+	page.AddSection("Executive Summary", executiveSummary(doc))
+	if opts.IncludeGoals {
+		page.AddSection("Objectives", objectives(doc))
+	}
+	page.AddSection("Personas", personas(doc))
+	page.AddSection("User Stories", userStories(doc))
+	page.AddSection("User Journeys", journeys(doc))
+	page.AddSection("Wireframes and Flows", wireframes(doc))
+	if opts.IncludeRequirements {
+		page.AddSection("Requirements", requirements(doc))
+	}
+	if opts.IncludeRoadmap {
+		page.AddSection("Roadmap", roadmap(doc))
+	}
+	if opts.IncludeRisks {
+		page.AddSection("Risks", risks(doc))
+	}
+	page.AddSection("Assumptions & Constraints", assumptions(doc))
+	if len(doc.OutOfScope) > 0 {
+		b := htmldoc.NewBuilder("out-of-scope")
+		b.List("", doc.OutOfScope)
+		page.AddSection("Out of Scope", b)
+	}
+	page.AddSection("Technical Architecture", architecture(doc))
+	glossary := htmldoc.NewBuilder("glossary")
+	glossary.Glossary(doc.Glossary)
+	page.AddSection("Glossary", glossary)
+	if opts.LinkGlossary == nil || *opts.LinkGlossary {
+		page.Glossary = doc.Glossary
+	}
+
+	if opts.IncludeComments {
+		page.AddComments(doc.Comments, commentSections)
+	}
+	return page
+}
+
+// commentSections maps the top-level PRD fields that comment paths start
+// with to the sections that render them.
+var commentSections = map[string]string{
+	"executiveSummary":      "Executive Summary",
+	"objectives":            "Objectives",
+	"personas":              "Personas",
+	"userStories":           "User Stories",
+	"uxRequirements":        "User Journeys",
+	"requirements":          "Requirements",
+	"roadmap":               "Roadmap",
+	"risks":                 "Risks",
+	"assumptions":           "Assumptions & Constraints",
+	"outOfScope":            "Out of Scope",
+	"technicalArchitecture": "Technical Architecture",
+	"glossary":              "Glossary",
+}
+
+func executiveSummary(doc *prd.Document) *htmldoc.Builder {
+	es := doc.ExecutiveSummary
+	b := htmldoc.NewBuilder("summary")
+	b.Labeled("Problem", es.ProblemStatement)
+	b.Labeled("Proposed solution", es.ProposedSolution)
+	b.Labeled("Target audience", es.TargetAudience)
+	b.List("Expected outcomes", es.ExpectedOutcomes)
+	return b
+}
+
+func objectives(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("objective")
+	for _, o := range doc.Objectives.OKRs {
+		obj := o.Objective
+		b.Heading(strings.TrimSpace(obj.ID + " " + obj.Title))
+		b.Paragraph(obj.Description)
+		b.Labeled("Rationale", obj.Rationale)
+		var rows [][]string
+		for _, kr := range slices.Concat(obj.KeyResults, o.KeyResults) {
+			rows = append(rows, []string{kr.ID, kr.Title, kr.Metric, kr.Baseline, kr.TargetLabel()})
+		}
+		b.Table([]string{"ID", "Key Result", "Metric", "Baseline", "Target"}, rows)
+	}
+	return b
+}
+
+func personas(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("persona")
+	for _, p := range doc.Personas {
+		title := p.Name
+		if p.IsPrimary {
+			title += " (primary)"
+		}
+		b.Heading(title)
+		b.Labeled("Role", p.Role)
+		b.Paragraph(p.Description)
+		b.List("Goals", p.Goals)
+		b.List("Pain points", p.PainPoints)
+		if p.Quote != "" {
+			b.Paragraph("“" + p.Quote + "”")
+		}
+	}
+	return b
+}
+
+func journeys(doc *prd.Document) *htmldoc.Builder {
+	names := make(map[string]string, len(doc.Personas))
+	for _, p := range doc.Personas {
+		names[p.ID] = p.Name
+	}
+	b := htmldoc.NewBuilder("journey")
+	for _, j := range doc.JourneyMaps() {
+		b.Heading(strings.TrimSpace(j.ID + " " + j.Title))
+		persona := names[j.PersonaID]
+		if persona == "" {
+			persona = j.PersonaID
+		}
+		b.Labeled("Persona", persona)
+		b.Paragraph(j.Description)
+		var rows [][]string
+		for _, s := range j.Stages {
+			satisfaction := ""
+			if s.Satisfaction > 0 {
+				satisfaction = fmt.Sprintf("%d/5", s.Satisfaction)
+			}
+			rows = append(rows, []string{s.Name, strings.Join(s.Actions, "; "), strings.Join(s.PainPoints, "; "),
+				strings.Join(s.Opportunities, "; "), satisfaction})
+		}
+		b.Table([]string{"Stage", "Actions", "Pain points", "Opportunities", "Satisfaction"}, rows)
+	}
+	return b
+}
+
+// wireframes renders image wireframes as a gallery, other wireframes as
+// links, and the interaction flows the figures link to.
+func wireframes(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("flow")
+	anchors := make(map[string]string)
+	for _, f := range doc.InteractionFlows() {
+		anchors[f.ID] = "#" + htmldoc.Slug("flow-"+strings.TrimSpace(f.ID+" "+f.Title))
+	}
+	var figures []htmldoc.Figure
+	for _, w := range doc.Wireframes() {
+		title := strings.TrimSpace(w.ID + " " + w.Title)
+		if !assets.IsImage(w.URL) {
+			b.Link(title, w.URL)
+			continue
+		}
+		caption := w.Description
+		if w.Status != "" {
+			caption = strings.TrimSpace(caption + " (" + w.Status + ")")
+		}
+		fig := htmldoc.Figure{Src: w.URL, Title: title, Caption: caption, LinksLabel: "Flows"}
+		for _, id := range w.Flows {
+			fig.Links = append(fig.Links, htmldoc.Field{Label: id, Value: anchors[id]})
+		}
+		figures = append(figures, fig)
+	}
+	b.Gallery(figures)
+	for _, f := range doc.InteractionFlows() {
+		b.Heading(strings.TrimSpace(f.ID + " " + f.Title))
+		b.Paragraph(f.Description)
+		b.List("Steps", f.Steps)
+		b.Link("Diagram", f.DiagramURL)
+	}
+	return b
+}
+
+func userStories(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("story")
+	var rows [][]string
+	for _, us := range doc.UserStories {
+		rows = append(rows, []string{us.ID, us.Title, us.Story(), string(us.Priority), us.PhaseID})
+	}
+	b.Table([]string{"ID", "Title", "Story", "Priority", "Phase"}, rows)
+	for _, us := range doc.UserStories {
+		b.ExternalRefs(us.ID, us.ExternalRefs, doc.Metadata.ExternalRefURLs)
+	}
+	return b
+}
+
+func requirements(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("requirements")
+	var fr [][]string
+	for _, r := range doc.Requirements.Functional {
+		fr = append(fr, []string{r.ID, r.Title, r.Description, string(r.Priority), r.PhaseID})
+	}
+	if len(fr) > 0 {
+		b.Heading("Functional")
+		b.Table([]string{"ID", "Title", "Description", "Priority", "Phase"}, fr)
+		for _, r := range doc.Requirements.Functional {
+			b.ExternalRefs(r.ID, r.ExternalRefs, doc.Metadata.ExternalRefURLs)
+		}
+	}
+	var nfr [][]string
+	for _, r := range doc.Requirements.NonFunctional {
+		nfr = append(nfr, []string{r.ID, string(r.Category), r.Title, r.Target, string(r.Priority)})
+	}
+	if len(nfr) > 0 {
+		b.Heading("Non-Functional")
+		b.Table([]string{"ID", "Category", "Title", "Target", "Priority"}, nfr)
+		for _, r := range doc.Requirements.NonFunctional {
+			b.ExternalRefs(r.ID, r.ExternalRefs, doc.Metadata.ExternalRefURLs)
+		}
+	}
+	return b
+}
+
+func roadmap(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("phase")
+	for _, p := range doc.Roadmap.Phases {
+		b.Heading(p.Name)
+		var window string
+		if p.StartDate != nil && p.EndDate != nil {
+			window = p.StartDate.Format("2006-01-02") + " to " + p.EndDate.Format("2006-01-02")
+		}
+		var progress string
+		if p.Progress != nil {
+			progress = strconv.Itoa(*p.Progress) + "%"
+		}
+		b.Fields(
+			htmldoc.Field{Label: "ID", Value: p.ID},
+			htmldoc.Field{Label: "Type", Value: string(p.Type)},
+			htmldoc.Field{Label: "Status", Value: string(p.Status)},
+			htmldoc.Field{Label: "Dates", Value: window},
+			htmldoc.Field{Label: "Progress", Value: progress},
+		)
+		b.List("Goals", p.Goals)
+		var rows [][]string
+		for _, d := range p.Deliverables {
+			rows = append(rows, []string{d.ID, d.Title, string(d.Type), string(d.Status)})
+		}
+		b.Table([]string{"ID", "Deliverable", "Type", "Status"}, rows)
+		b.List("Success criteria", p.SuccessCriteria)
+	}
+	return b
+}
+
+func risks(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("risks")
+	var rows [][]string
+	for _, r := range doc.Risks {
+		rows = append(rows, []string{r.ID, r.Description, string(r.Probability), string(r.Impact), r.Mitigation, string(r.Status)})
+	}
+	b.Table([]string{"ID", "Risk", "Probability", "Impact", "Mitigation", "Status"}, rows)
+	return b
+}
+
+func assumptions(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("assumptions")
+	if doc.Assumptions == nil {
+		return b
+	}
+	var rows [][]string
+	for _, a := range doc.Assumptions.Assumptions {
+		validated := "no"
+		if a.Validated {
+			validated = "yes"
+		}
+		rows = append(rows, []string{a.ID, a.Description, a.Risk, validated})
+	}
+	if len(rows) > 0 {
+		b.Heading("Assumptions")
+		b.Table([]string{"ID", "Assumption", "Risk if wrong", "Validated"}, rows)
+	}
+	rows = nil
+	for _, c := range doc.Assumptions.Constraints {
+		rows = append(rows, []string{c.ID, string(c.Type), c.Description, c.Impact})
+	}
+	if len(rows) > 0 {
+		b.Heading("Constraints")
+		b.Table([]string{"ID", "Type", "Constraint", "Impact"}, rows)
+	}
+	return b
+}
+
+func architecture(doc *prd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("architecture")
+	ta := doc.TechArchitecture
+	if ta == nil {
+		return b
+	}
+	b.Paragraph(ta.Overview)
+	b.Link("System diagram", ta.SystemDiagram)
+	b.Link("Data model", ta.DataModel)
+	var rows [][]string
+	for _, i := range ta.IntegrationPoints {
+		rows = append(rows, []string{i.Name, i.Type, i.Description})
+	}
+	if len(rows) > 0 {
+		b.Heading("Integrations")
+		b.Table([]string{"Name", "Type", "Description"}, rows)
+	}
+	b.Labeled("Security", ta.SecurityDesign)
+	b.Labeled("Scalability", ta.ScalabilityDesign)
+	return b
+}
@@ -0,0 +1,96 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+)
+
+func testPRD() *prd.Document {
+	return &prd.Document{
+		Metadata: prd.Metadata{ID: "PRD-1", Title: "Checkout", Version: "1.0.0", Status: prd.StatusDraft},
+		ExecutiveSummary: prd.ExecutiveSummary{
+			ProblemStatement: "Carts are abandoned",
+			ProposedSolution: "One-click <checkout>",
+		},
+		Personas: []prd.Persona{{ID: "p1", Name: "Shopper Sam", Role: "Buyer", IsPrimary: true}},
+		UserStories: []prd.UserStory{
+			{ID: "US-1", Title: "Pay fast", AsA: "buyer", IWant: "to pay in one click", SoThat: "I finish quickly"},
+		},
+		Requirements: prd.Requirements{
+			Functional: []prd.FunctionalRequirement{{ID: "FR-1", Title: "Saved cards", Priority: prd.MoSCoWMust}},
+		},
+		Roadmap: prd.Roadmap{Phases: []prd.Phase{{ID: "phase-1", Name: "MVP"}}},
+		Risks:   []prd.Risk{{ID: "R-1", Description: "Fraud"}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	out, err := New().Render(testPRD(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<h1>Checkout</h1>",
+		"One-click &lt;checkout&gt;",
+		`<section id="personas">`,
+		"Shopper Sam (primary)",
+		"As a buyer, I want to pay in one click",
+		"<td>FR-1</td><td>Saved cards</td>",
+		`<section id="roadmap">`,
+		`<section id="risks">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(html, `id="glossary"`) {
+		t.Error("empty glossary should be omitted")
+	}
+}
+
+func TestRenderOptions(t *testing.T) {
+	opts := render.DefaultOptions()
+	opts.IncludeRisks = false
+	opts.IncludeRequirements = false
+	opts.CustomCSS = "body { max-width: 60rem; }"
+	out, err := New().Render(testPRD(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	if strings.Contains(html, `id="risks"`) || strings.Contains(html, `id="requirements"`) {
+		t.Error("excluded sections should not be rendered")
+	}
+	if !strings.Contains(html, "body { max-width: 60rem; }") {
+		t.Error("custom CSS should be embedded")
+	}
+}
+
+func TestRenderOnePager(t *testing.T) {
+	out, err := New().RenderOnePager(prd.GenerateOnePagerView(testPRD()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<section id="problem">`,
+		"One-click &lt;checkout&gt;",
+		`<section id="top-risks">`,
+		"<td>Fraud</td>",
+		`<section id="next-milestone">`,
+		"MVP",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(html, `id="key-results"`) {
+		t.Error("empty key results should be omitted")
+	}
+}
@@ -0,0 +1,68 @@
+package html
+
+import (
+	"strings"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+)
+
+// RenderOnePager converts a one-pager view of a PRD to a standalone HTML
+// page.
+func (r *Renderer) RenderOnePager(view *prd.OnePagerView, opts *render.Options) ([]byte, error) {
+	return r.OnePagerPage(view, opts).Render()
+}
+
+// OnePagerPage lays out a one-pager view with the same sections as
+// prd.RenderOnePagerMarkdown.
+func (r *Renderer) OnePagerPage(view *prd.OnePagerView, opts *render.Options) *htmldoc.Page {
+	if opts == nil {
+		opts = render.DefaultOptions()
+	}
+	page := htmldoc.NewPage("Executive Brief", view.Title)
+	page.CustomCSS = opts.CustomCSS
+	page.KaTeXURL = opts.KaTeXURL
+	page.Meta = []htmldoc.Field{
+		{Label: "ID", Value: view.PRDID},
+		{Label: "Status", Value: view.Status},
+		{Label: "Owner", Value: view.Owner},
+		{Label: "Version", Value: view.Version},
+	}
+
+	b := htmldoc.NewBuilder("problem")
+	b.Paragraph(view.Problem)
+	page.AddSection("Problem", b)
+
+	b = htmldoc.NewBuilder("solution")
+	b.Paragraph(view.Solution)
+	page.AddSection("Solution", b)
+
+	b = htmldoc.NewBuilder("key-results")
+	var krs [][]string
+	for _, kr := range view.KeyResults {
+		krs = append(krs, []string{kr.Objective, kr.KeyResult, kr.Baseline, kr.Target})
+	}
+	b.Table([]string{"Objective", "Key Result", "Baseline", "Target"}, krs)
+	page.AddSection("Key Results", b)
+
+	b = htmldoc.NewBuilder("risks")
+	var risks [][]string
+	for _, r := range view.Risks {
+		risks = append(risks, []string{r.Description, r.Impact, r.Mitigation})
+	}
+	b.Table([]string{"Risk", "Impact", "Mitigation"}, risks)
+	page.AddSection("Top Risks", b)
+
+	if m := view.NextMilestone; m != nil {
+		b = htmldoc.NewBuilder("next-milestone")
+		b.Fields(
+			htmldoc.Field{Label: "Phase", Value: m.Phase},
+			htmldoc.Field{Label: "Due", Value: m.Date},
+			htmldoc.Field{Label: "Status", Value: strings.ReplaceAll(m.Status, "_", " ")},
+		)
+		b.List("Deliverables", m.Deliverables)
+		page.AddSection("Next Milestone", b)
+	}
+	return page
+}
@@ -6,6 +6,8 @@ package marp
 import (
 	"bytes"
 	"fmt"
+	"slices"
+	"strings"
 	"text/template"
 	"time"
 
@@ -15,6 +17,27 @@ import (
 	"github.com/grokify/structured-plan/requirements/prd/render"
 )
 
+// Slide names, for render.Options.Sections.
+const (
+	SlideTitle        = "title"
+	SlideProblem      = "problem"
+	SlideSolution     = "solution"
+	SlidePersonas     = "personas"
+	SlideOKRs         = "okrs"
+	SlideMetrics      = "metrics"
+	SlideRequirements = "requirements"
+	SlideRoadmap      = "roadmap"
+	SlideSwimlane     = "swimlane"
+	SlideRisks        = "risks"
+	SlideGoals        = "goals"
+	SlideSummary      = "summary"
+)
+
+// Slides are the slide names in deck order. A slide with nothing to show,
+// such as risks for a PRD without risks, is left out even when selected.
+var Slides = []string{SlideTitle, SlideProblem, SlideSolution, SlidePersonas, SlideOKRs, SlideMetrics,
+	SlideRequirements, SlideRoadmap, SlideSwimlane, SlideRisks, SlideGoals, SlideSummary}
+
 // PRDRenderer implements the render.Renderer interface for PRD Marp output.
 type PRDRenderer struct{}
 
@@ -48,41 +71,6 @@ func (r *PRDRenderer) Render(doc *prd.Document, opts *render.Options) ([]byte, e
 		HasRisks: opts.IncludeRisks && len(doc.Risks) > 0,
 	}
 
-	var buf bytes.Buffer
-
-	// Render front matter
-	if err := prdFrontMatterTmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("rendering front matter: %w", err)
-	}
-
-	// Render title slide
-	if err := prdTitleSlideTmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("rendering title slide: %w", err)
-	}
-
-	// Render problem slide
-	if err := prdProblemSlideTmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("rendering problem slide: %w", err)
-	}
-
-	// Render solution slide
-	if err := prdSolutionSlideTmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("rendering solution slide: %w", err)
-	}
-
-	// Render personas slide
-	if len(doc.Personas) > 0 {
-		if err := prdPersonasSlideTmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("rendering personas slide: %w", err)
-		}
-	}
-
-	// Render objectives slide
-	if err := prdObjectivesSlideTmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("rendering objectives slide: %w", err)
-	}
-
-	// Render success metrics slide (from OKR Key Results)
 	hasKeyResults := false
 	for _, okr := range doc.Objectives.OKRs {
 		if len(okr.KeyResults) > 0 {
@@ -90,48 +78,63 @@ func (r *PRDRenderer) Render(doc *prd.Document, opts *render.Options) ([]byte, e
 			break
 		}
 	}
-	if hasKeyResults {
-		if err := prdMetricsSlideTmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("rendering metrics slide: %w", err)
-		}
+	if opts.IncludeRoadmap && hasDeliverables(doc) {
+		tableOpts := prd.DefaultRoadmapTableOptions()
+		tableOpts.IncludeOKRs = len(doc.Objectives.OKRs) > 0
+		data.Swimlane = doc.ToSwimlaneTableWithGoals(tableOpts)
 	}
 
-	// Render requirements slides
-	if opts.IncludeRequirements && (len(doc.Requirements.Functional) > 0 || len(doc.Requirements.NonFunctional) > 0) {
-		if err := prdRequirementsSlideTmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("rendering requirements slide: %w", err)
-		}
+	slides := []struct {
+		name string
+		tmpl *template.Template
+		show bool
+	}{
+		{SlideTitle, prdTitleSlideTmpl, true},
+		{SlideProblem, prdProblemSlideTmpl, true},
+		{SlideSolution, prdSolutionSlideTmpl, true},
+		{SlidePersonas, prdPersonasSlideTmpl, len(doc.Personas) > 0},
+		{SlideOKRs, prdObjectivesSlideTmpl, true},
+		{SlideMetrics, prdMetricsSlideTmpl, hasKeyResults},
+		{SlideRequirements, prdRequirementsSlideTmpl, opts.IncludeRequirements && (len(doc.Requirements.Functional) > 0 || len(doc.Requirements.NonFunctional) > 0)},
+		{SlideRoadmap, prdRoadmapSlideTmpl, opts.IncludeRoadmap && len(doc.Roadmap.Phases) > 0},
+		{SlideSwimlane, prdSwimlaneSlideTmpl, data.Swimlane != ""},
+		{SlideRisks, prdRisksSlideTmpl, data.HasRisks},
+		{SlideGoals, prdGoalsSlideTmpl, data.HasGoals},
+		{SlideSummary, prdSummarySlideTmpl, true},
 	}
-
-	// Render roadmap slide
-	if opts.IncludeRoadmap && len(doc.Roadmap.Phases) > 0 {
-		if err := prdRoadmapSlideTmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("rendering roadmap slide: %w", err)
+	for _, name := range opts.Sections {
+		if !slices.Contains(Slides, name) {
+			return nil, fmt.Errorf("unknown slide %q (use %s)", name, strings.Join(Slides, ", "))
 		}
 	}
 
-	// Render risks slide
-	if data.HasRisks {
-		if err := prdRisksSlideTmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("rendering risks slide: %w", err)
-		}
+	var buf bytes.Buffer
+	if err := prdFrontMatterTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering front matter: %w", err)
 	}
-
-	// Render goals alignment slide
-	if data.HasGoals {
-		if err := prdGoalsSlideTmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("rendering goals slide: %w", err)
+	for _, slide := range slides {
+		if !slide.show || (len(opts.Sections) > 0 && !slices.Contains(opts.Sections, slide.name)) {
+			continue
+		}
+		if err := slide.tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("rendering %s slide: %w", slide.name, err)
 		}
-	}
-
-	// Render summary slide
-	if err := prdSummarySlideTmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("rendering summary slide: %w", err)
 	}
 
 	return buf.Bytes(), nil
 }
 
+// hasDeliverables reports whether a roadmap phase has deliverables, which
+// the swimlane slide lays out.
+func hasDeliverables(doc *prd.Document) bool {
+	for _, phase := range doc.Roadmap.Phases {
+		if len(phase.Deliverables) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // prdTemplateData holds data for PRD template rendering.
 type prdTemplateData struct {
 	PRD      *prd.Document
@@ -140,6 +143,7 @@ type prdTemplateData struct {
 	Date     string
 	HasGoals bool
 	HasRisks bool
+	Swimlane string // Swimlane table of the roadmap, if it has deliverables
 }
 
 // prdFuncMap merges structureddocs CommonFuncMap with PRD-specific functions.
@@ -159,6 +163,12 @@ var prdFuncMap = mergeFuncMaps(sdmarp.CommonFuncMap, template.FuncMap{
 			return s
 		}
 	},
+	"date": func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format("Jan 2, 2006")
+	},
 	"riskImpactColor": func(impact string) string {
 		switch impact {
 		case "critical":
@@ -228,6 +238,9 @@ style: |
     border-radius: 8px;
     margin: 0.5em 0;
   }
+  section.swimlane table {
+    font-size: 0.6em;
+  }
   blockquote {
     font-size: 1.2em;
     border-left: 4px solid {{.Theme.AccentColor}};
@@ -383,12 +396,12 @@ var prdRequirementsSlideTmpl = template.Must(template.New("prdRequirementsSlide"
 
 `))
 
-var prdRoadmapSlideTmpl = template.Must(template.New("prdRoadmapSlide").Parse(`## Roadmap
+var prdRoadmapSlideTmpl = template.Must(template.New("prdRoadmapSlide").Funcs(prdFuncMap).Parse(`## Roadmap
 
 | Phase | Timeline | Key Deliverables |
 |-------|----------|------------------|
 {{- range .PRD.Roadmap.Phases}}
-| **{{.Name}}** | {{if .StartDate}}{{.StartDate}}{{end}}{{if .EndDate}} - {{.EndDate}}{{end}} | {{if .Goals}}{{index .Goals 0}}{{end}} |
+| **{{.Name}}** | {{if .StartDate}}{{date .StartDate}}{{end}}{{if .EndDate}} - {{date .EndDate}}{{end}} | {{if .Goals}}{{index .Goals 0}}{{end}} |
 {{- end}}
 
 {{- if gt (len .PRD.Roadmap.Phases) 0}}
@@ -406,6 +419,15 @@ var prdRoadmapSlideTmpl = template.Must(template.New("prdRoadmapSlide").Parse(`#
 
 `))
 
+var prdSwimlaneSlideTmpl = template.Must(template.New("prdSwimlaneSlide").Parse(`<!-- _class: swimlane -->
+
+## Roadmap Swimlane
+
+{{.Swimlane}}
+---
+
+`))
+
 var prdRisksSlideTmpl = template.Must(template.New("prdRisksSlide").Funcs(prdFuncMap).Parse(`## Risks & Mitigations
 
 | Risk | Impact | Mitigation |
@@ -425,7 +447,7 @@ var prdGoalsSlideTmpl = template.Must(template.New("prdGoalsSlide").Parse(`## Go
 {{range .PRD.Goals.OKR.Objectives -}}
 **Objective:** {{.Title}}
 {{range .KeyResults -}}
-- KR: {{.Title}} (Target: {{.Target}})
+- KR: {{.Title}} (Target: {{.TargetLabel}})
 {{end}}
 {{end}}
 {{- end}}
@@ -472,7 +472,7 @@ var okrKeyResultsSlideTmpl = template.Must(template.New("okrKeyResultsSlide").Fu
 |-----------|------------|--------|-------|
 {{- range $oi, $obj := .PRD.Goals.OKR.Objectives}}
 {{- range $ki, $kr := $obj.KeyResults}}
-| O{{add $oi 1}} | {{truncate $kr.Title 25}} | {{if $kr.Target}}{{$kr.Target}}{{else}}-{{end}} | {{scorePercent $kr.Score}} |
+| O{{add $oi 1}} | {{truncate $kr.Title 25}} | {{if $kr.Target}}{{$kr.TargetLabel}}{{else}}-{{end}} | {{scorePercent $kr.EffectiveScore}} |
 {{- end}}
 {{- end}}
 
@@ -108,6 +108,37 @@ func TestPRDRenderer_RenderWithOptions(t *testing.T) {
 	}
 }
 
+func TestPRDRenderer_RenderSections(t *testing.T) {
+	doc := createTestPRD()
+	doc.Roadmap = prd.Roadmap{Phases: []prd.Phase{{ID: "phase-1", Name: "MVP", Deliverables: []prd.Deliverable{
+		{ID: "D-1", Title: "Login page", Type: prd.DeliverableFeature},
+	}}}}
+	r := NewPRDRenderer()
+
+	opts := render.DefaultOptions()
+	opts.Sections = []string{SlideProblem, SlideSwimlane}
+	output, err := r.Render(doc, opts)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	content := string(output)
+	for _, want := range []string{"marp: true", "## The Problem", "## Roadmap Swimlane", "Login page"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected slides to contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"# Test PRD", "## The Solution", "## Summary"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("expected slides not to contain %q", unwanted)
+		}
+	}
+
+	opts.Sections = []string{"appendix"}
+	if _, err := r.Render(doc, opts); err == nil || !strings.Contains(err.Error(), `unknown slide "appendix"`) {
+		t.Errorf("Render() with unknown slide error = %v", err)
+	}
+}
+
 func TestPRDRenderer_RenderWithPersonas(t *testing.T) {
 	doc := createTestPRD()
 	doc.Personas = []prd.Persona{
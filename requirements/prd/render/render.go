@@ -2,19 +2,16 @@
 // to various output formats including Marp slides.
 package render
 
-import "github.com/grokify/structured-plan/requirements/prd"
-
-// Renderer defines the interface for output format renderers.
-type Renderer interface {
-	// Format returns the output format name (e.g., "marp").
-	Format() string
-	// FileExtension returns the file extension for this format (e.g., ".md").
-	FileExtension() string
-	// Render converts a PRD document to the output format.
-	Render(doc *prd.Document, opts *Options) ([]byte, error)
-}
+import (
+	shared "github.com/grokify/structured-plan/render"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Renderer defines the interface for PRD output format renderers.
+type Renderer = shared.TypedRenderer[prd.Document, Options]
 
-// Options contains rendering options common to all renderers.
+// Options contains the rendering options of PRD renderers. The registry
+// fills the fields that are also render.Options from the common options.
 type Options struct {
 	// Theme name (renderer-specific, e.g., "default", "corporate", "minimal")
 	Theme string
@@ -40,6 +37,22 @@ type Options struct {
 	// Custom CSS (for Marp/HTML renderers)
 	CustomCSS string
 
+	// KaTeXURL is the base URL of a local KaTeX dist directory for math
+	// (HTML renderer; default: the jsdelivr CDN)
+	KaTeXURL string
+
+	// IncludeComments shows unresolved review comments as margin notes
+	// (HTML renderer)
+	IncludeComments bool
+
+	// LinkGlossary links the first use of each glossary term to its entry
+	// (HTML renderer; default: true)
+	LinkGlossary *bool
+
+	// Sections selects the slides to include by name, such as "problem"
+	// and "roadmap" (Marp renderer; empty = all)
+	Sections []string
+
 	// Additional metadata (renderer-specific)
 	Metadata map[string]string
 }
@@ -47,7 +60,7 @@ type Options struct {
 // DefaultOptions returns sensible default rendering options.
 func DefaultOptions() *Options {
 	return &Options{
-		Theme:               "default",
+		Theme:               shared.ThemeDefault,
 		IncludeGoals:        true,
 		IncludeRoadmap:      true,
 		IncludeRisks:        true,
@@ -61,7 +74,7 @@ func DefaultOptions() *Options {
 // ExecutiveOptions returns options for executive-focused slides (fewer details).
 func ExecutiveOptions() *Options {
 	return &Options{
-		Theme:               "corporate",
+		Theme:               shared.ThemeCorporate,
 		IncludeGoals:        true,
 		IncludeRoadmap:      true,
 		IncludeRisks:        true,
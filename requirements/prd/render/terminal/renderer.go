@@ -1,4 +1,5 @@
-// Package terminal provides terminal rendering for PRD evaluation reports.
+// Package terminal provides terminal rendering for PRD and V2MOM evaluation
+// reports.
 package terminal
 
 import (
@@ -7,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	"github.com/grokify/structured-plan/requirements/prd"
 )
 
 const boxWidth = 78 // Inner width between border characters
@@ -67,6 +70,29 @@ func (r *Renderer) Render(report *evaluation.EvaluationReport) error {
 		b.WriteString("\n")
 	}
 
+	// Entities behind the category scores
+	if hasReferences(report) {
+		b.WriteString(separator())
+		b.WriteString("\n")
+		b.WriteString(paddedLine("SCORE EXPLANATIONS (+ earned, - lost)"))
+		b.WriteString("\n")
+		b.WriteString(separator())
+		b.WriteString("\n")
+		for _, cs := range report.Categories {
+			if len(cs.Findings) == 0 {
+				continue
+			}
+			b.WriteString(paddedLine(fmt.Sprintf("  %s (%.1f)", CategoryDisplayName(cs.Category), cs.Score)))
+			b.WriteString("\n")
+			for _, f := range cs.Findings {
+				for _, line := range formatReference(f) {
+					b.WriteString(paddedLine(line))
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+
 	// Findings by severity
 	if len(report.Findings) > 0 {
 		b.WriteString(separator())
@@ -133,7 +159,7 @@ func (r *Renderer) Render(report *evaluation.EvaluationReport) error {
 }
 
 func formatCategoryLine(cs evaluation.CategoryScore) string {
-	name := categoryDisplayName(cs.Category)
+	name := CategoryDisplayName(cs.Category)
 	if len(name) > 24 {
 		name = name[:21] + "..."
 	}
@@ -147,7 +173,39 @@ func formatCategoryLine(cs evaluation.CategoryScore) string {
 		name, icon, statusText, cs.Score, cs.MaxScore, justification)
 }
 
-func categoryDisplayName(category string) string {
+// hasReferences reports whether any category carries score references,
+// which the PRD scorer attaches as category findings.
+func hasReferences(report *evaluation.EvaluationReport) bool {
+	for _, cs := range report.Categories {
+		if len(cs.Findings) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// formatReference renders a score reference as two lines: the reason with
+// the entity ID, then the JSON pointer with the excerpt. Info findings
+// earned points; the rest lost them.
+func formatReference(f evaluation.Finding) []string {
+	sign := "-"
+	if f.Severity == evaluation.SeverityInfo {
+		sign = "+"
+	}
+	first := fmt.Sprintf("    %s %s", sign, f.Title)
+	if f.ID != "" {
+		first += " (" + f.ID + ")"
+	}
+	second := "      " + f.Evidence
+	if f.Description != "" {
+		second += fmt.Sprintf(": %q", f.Description)
+	}
+	return []string{truncate(first, boxWidth-2), truncate(second, boxWidth-2)}
+}
+
+// CategoryDisplayName returns the title of a scoring category, e.g.
+// "Problem Definition" for problem_definition.
+func CategoryDisplayName(category string) string {
 	names := map[string]string{
 		"problem_definition":    "Problem Definition",
 		"user_understanding":    "User Understanding",
@@ -159,10 +217,19 @@ func categoryDisplayName(category string) string {
 		"technical_feasibility": "Technical Feasibility",
 		"metrics_quality":       "Metrics Quality",
 		"risk_management":       "Risk Management",
+
+		// V2MOM categories
+		"measurable_key_results": "Measurable Key Results",
+		"value_alignment":        "Value Alignment",
+		"obstacle_mitigation":    "Obstacle Mitigation",
+		"smart_measures":         "SMART Measures",
 	}
 	if name, ok := names[category]; ok {
 		return name
 	}
+	if name := prd.CategoryName(category); name != "" {
+		return name
+	}
 	// Handle custom sections
 	if strings.HasPrefix(category, "custom:") {
 		return strings.TrimPrefix(category, "custom:")
@@ -187,10 +254,11 @@ func finalMessage(report *evaluation.EvaluationReport) string {
 }
 
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	r := []rune(s)
+	if len(r) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return string(r[:maxLen-3]) + "..."
 }
 
 // Box drawing functions
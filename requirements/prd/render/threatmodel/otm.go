@@ -0,0 +1,271 @@
+package threatmodel
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+)
+
+// OTMVersion is the Open Threat Model specification version produced.
+const OTMVersion = "0.2.0"
+
+// OTMRenderer implements render.Renderer for Open Threat Model JSON.
+//
+// The PRD describes the product as a whole, so it becomes a single OTM
+// component placed in the first trust zone, with every key threat attached
+// to it. Trust boundaries become trust zones, assets become OTM assets, and
+// threat actors, which OTM has no element for, are recorded in the project
+// attributes. PRD severity sets threat impact; likelihood, asset CIA, and
+// trust zone ratings are not captured by the PRD and default to 50.
+type OTMRenderer struct{}
+
+// NewOTMRenderer creates a new Open Threat Model renderer.
+func NewOTMRenderer() *OTMRenderer {
+	return &OTMRenderer{}
+}
+
+// Format returns the output format name.
+func (r *OTMRenderer) Format() string {
+	return "otm"
+}
+
+// FileExtension returns the file extension for OTM output.
+func (r *OTMRenderer) FileExtension() string {
+	return ".otm.json"
+}
+
+// Render converts the PRD security model to OTM JSON.
+func (r *OTMRenderer) Render(doc *prd.Document, opts *render.Options) ([]byte, error) {
+	otm, err := NewOTM(doc)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(otm, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling OTM: %w", err)
+	}
+	return data, nil
+}
+
+// OTM is an Open Threat Model document.
+type OTM struct {
+	OTMVersion  string          `json:"otmVersion"`
+	Project     OTMProject      `json:"project"`
+	Assets      []OTMAsset      `json:"assets,omitempty"`
+	TrustZones  []OTMTrustZone  `json:"trustZones"`
+	Components  []OTMComponent  `json:"components"`
+	Threats     []OTMThreat     `json:"threats,omitempty"`
+	Mitigations []OTMMitigation `json:"mitigations,omitempty"`
+}
+
+// OTMProject describes the modeled project.
+type OTMProject struct {
+	Name        string         `json:"name"`
+	ID          string         `json:"id"`
+	Description string         `json:"description,omitempty"`
+	Owner       string         `json:"owner,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// OTMAsset is a valuable resource to protect.
+type OTMAsset struct {
+	Name        string       `json:"name"`
+	ID          string       `json:"id"`
+	Description string       `json:"description,omitempty"`
+	Risk        OTMAssetRisk `json:"risk"`
+}
+
+// OTMAssetRisk holds 0-100 confidentiality, integrity, and availability ratings.
+type OTMAssetRisk struct {
+	Confidentiality int    `json:"confidentiality"`
+	Integrity       int    `json:"integrity"`
+	Availability    int    `json:"availability"`
+	Comment         string `json:"comment,omitempty"`
+}
+
+// OTMTrustZone is a region of uniform trust.
+type OTMTrustZone struct {
+	ID   string           `json:"id"`
+	Name string           `json:"name"`
+	Risk OTMTrustZoneRisk `json:"risk"`
+}
+
+// OTMTrustZoneRisk holds the 0-100 trust rating of a zone.
+type OTMTrustZoneRisk struct {
+	TrustRating int `json:"trustRating"`
+}
+
+// OTMComponent is an element of the modeled system.
+type OTMComponent struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Type        string              `json:"type"`
+	Description string              `json:"description,omitempty"`
+	Parent      OTMParent           `json:"parent"`
+	Threats     []OTMThreatInstance `json:"threats,omitempty"`
+}
+
+// OTMParent places a component in a trust zone.
+type OTMParent struct {
+	TrustZone string `json:"trustZone"`
+}
+
+// OTMThreatInstance attaches a threat to a component.
+type OTMThreatInstance struct {
+	Threat      string                  `json:"threat"`
+	State       string                  `json:"state"`
+	Mitigations []OTMMitigationInstance `json:"mitigations,omitempty"`
+}
+
+// OTMMitigationInstance records the state of a mitigation for a threat.
+type OTMMitigationInstance struct {
+	Mitigation string `json:"mitigation"`
+	State      string `json:"state"`
+}
+
+// OTMThreat is a threat definition.
+type OTMThreat struct {
+	Name        string        `json:"name"`
+	ID          string        `json:"id"`
+	Description string        `json:"description,omitempty"`
+	Categories  []string      `json:"categories,omitempty"`
+	Risk        OTMThreatRisk `json:"risk"`
+	Tags        []string      `json:"tags,omitempty"`
+}
+
+// OTMThreatRisk holds 0-100 likelihood and impact ratings.
+type OTMThreatRisk struct {
+	Likelihood        int    `json:"likelihood"`
+	LikelihoodComment string `json:"likelihoodComment,omitempty"`
+	Impact            int    `json:"impact"`
+	ImpactComment     string `json:"impactComment,omitempty"`
+}
+
+// OTMMitigation is a mitigation definition.
+type OTMMitigation struct {
+	Name          string `json:"name"`
+	ID            string `json:"id"`
+	Description   string `json:"description,omitempty"`
+	RiskReduction int    `json:"riskReduction"`
+}
+
+// OTM threat and mitigation instance states.
+const (
+	OTMThreatExposed         = "EXPOSED"
+	OTMThreatMitigated       = "MITIGATED"
+	OTMMitigationRequired    = "REQUIRED"
+	OTMMitigationImplemented = "IMPLEMENTED"
+)
+
+// NewOTM builds an Open Threat Model from the PRD security model.
+func NewOTM(doc *prd.Document) (*OTM, error) {
+	sm := doc.SecurityModel
+	if sm == nil {
+		return nil, ErrNoSecurityModel
+	}
+	tm := sm.ThreatModel
+
+	projectID := doc.Metadata.ID
+	if projectID == "" {
+		projectID = slug(projectName(doc))
+	}
+	otm := &OTM{
+		OTMVersion: OTMVersion,
+		Project: OTMProject{
+			Name:        projectName(doc),
+			ID:          projectID,
+			Description: sm.Overview,
+			Owner:       projectOwner(doc),
+			Tags:        doc.Metadata.Tags,
+		},
+		TrustZones: []OTMTrustZone{},
+		Components: []OTMComponent{},
+	}
+	if len(tm.ThreatActors) > 0 {
+		otm.Project.Attributes = map[string]any{"threatActors": tm.ThreatActors}
+	}
+
+	for _, a := range tm.Assets {
+		otm.Assets = append(otm.Assets, OTMAsset{
+			Name: a,
+			ID:   "asset-" + slug(a),
+			Risk: OTMAssetRisk{
+				Confidentiality: 50,
+				Integrity:       50,
+				Availability:    50,
+				Comment:         "Default rating; not specified in the PRD",
+			},
+		})
+	}
+
+	for _, b := range tm.TrustBoundaries {
+		otm.TrustZones = append(otm.TrustZones, OTMTrustZone{
+			ID:   "tz-" + slug(b),
+			Name: b,
+			Risk: OTMTrustZoneRisk{TrustRating: 50},
+		})
+	}
+	if len(otm.TrustZones) == 0 {
+		otm.TrustZones = append(otm.TrustZones, OTMTrustZone{
+			ID:   "tz-default",
+			Name: "Default",
+			Risk: OTMTrustZoneRisk{TrustRating: 50},
+		})
+	}
+
+	component := OTMComponent{
+		ID:          "component-" + slug(projectID),
+		Name:        projectName(doc),
+		Type:        "generic-service",
+		Description: sm.Overview,
+		Parent:      OTMParent{TrustZone: otm.TrustZones[0].ID},
+	}
+
+	for i, t := range tm.KeyThreats {
+		id := threatID(i, t)
+		threat := OTMThreat{
+			Name:        t.Threat,
+			ID:          id,
+			Description: t.Threat,
+			Risk: OTMThreatRisk{
+				Likelihood:        50,
+				LikelihoodComment: "Not specified in the PRD",
+				Impact:            severityScore(t.Severity),
+			},
+			Tags: t.RelatedIDs,
+		}
+		if t.Severity != "" {
+			threat.Risk.ImpactComment = "PRD severity: " + t.Severity
+		}
+		if c := strideCategory(t.Category); c != "" {
+			threat.Categories = []string{c}
+		} else if t.Category != "" {
+			threat.Categories = []string{t.Category}
+		}
+		otm.Threats = append(otm.Threats, threat)
+
+		instance := OTMThreatInstance{Threat: id, State: OTMThreatExposed}
+		if t.Mitigation != "" {
+			mid := "mitigation-" + id
+			otm.Mitigations = append(otm.Mitigations, OTMMitigation{
+				Name:          t.Mitigation,
+				ID:            mid,
+				Description:   t.Mitigation,
+				RiskReduction: 50,
+			})
+			state := OTMMitigationRequired
+			if isMitigated(t.Status) {
+				state = OTMMitigationImplemented
+				instance.State = OTMThreatMitigated
+			}
+			instance.Mitigations = []OTMMitigationInstance{{Mitigation: mid, State: state}}
+		}
+		component.Threats = append(component.Threats, instance)
+	}
+
+	otm.Components = append(otm.Components, component)
+	return otm, nil
+}
@@ -0,0 +1,330 @@
+package threatmodel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+)
+
+// ThreatDragonVersion is the OWASP Threat Dragon model version produced.
+const ThreatDragonVersion = "2.2.0"
+
+// ThreatDragonRenderer implements render.Renderer for OWASP Threat Dragon
+// v2 JSON models.
+//
+// The export contains one STRIDE diagram. The product is a process holding
+// every key threat, threat actors are actors on the left, assets are data
+// stores on the right, and each trust boundary is a boundary box around the
+// process. Elements are laid out on a simple grid as a starting point; the
+// PRD has no data flows, so none are drawn.
+type ThreatDragonRenderer struct{}
+
+// NewThreatDragonRenderer creates a new OWASP Threat Dragon renderer.
+func NewThreatDragonRenderer() *ThreatDragonRenderer {
+	return &ThreatDragonRenderer{}
+}
+
+// Format returns the output format name.
+func (r *ThreatDragonRenderer) Format() string {
+	return "threat-dragon"
+}
+
+// FileExtension returns the file extension for Threat Dragon output.
+func (r *ThreatDragonRenderer) FileExtension() string {
+	return ".threat-dragon.json"
+}
+
+// Render converts the PRD security model to a Threat Dragon model.
+func (r *ThreatDragonRenderer) Render(doc *prd.Document, opts *render.Options) ([]byte, error) {
+	model, err := NewThreatDragonModel(doc)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling Threat Dragon model: %w", err)
+	}
+	return data, nil
+}
+
+// TDModel is an OWASP Threat Dragon v2 model.
+type TDModel struct {
+	Version string    `json:"version"`
+	Summary TDSummary `json:"summary"`
+	Detail  TDDetail  `json:"detail"`
+}
+
+// TDSummary describes the model.
+type TDSummary struct {
+	Title       string `json:"title"`
+	Owner       string `json:"owner"`
+	Description string `json:"description"`
+	ID          int    `json:"id"`
+}
+
+// TDDetail holds the model's diagrams.
+type TDDetail struct {
+	Contributors []TDContributor `json:"contributors"`
+	Diagrams     []TDDiagram     `json:"diagrams"`
+	DiagramTop   int             `json:"diagramTop"`
+	Reviewer     string          `json:"reviewer"`
+	ThreatTop    int             `json:"threatTop"`
+}
+
+// TDContributor is a model contributor.
+type TDContributor struct {
+	Name string `json:"name"`
+}
+
+// TDDiagram is a single threat model diagram.
+type TDDiagram struct {
+	ID          int      `json:"id"`
+	Title       string   `json:"title"`
+	DiagramType string   `json:"diagramType"`
+	Placeholder string   `json:"placeholder"`
+	Thumbnail   string   `json:"thumbnail"`
+	Version     string   `json:"version"`
+	Cells       []TDCell `json:"cells"`
+}
+
+// TDCell is a diagram element.
+type TDCell struct {
+	ID       string         `json:"id"`
+	Shape    string         `json:"shape"`
+	Position TDPosition     `json:"position"`
+	Size     TDSize         `json:"size"`
+	Attrs    map[string]any `json:"attrs"`
+	Visible  bool           `json:"visible"`
+	ZIndex   int            `json:"zIndex"`
+	Data     map[string]any `json:"data"`
+}
+
+// TDPosition is a cell position.
+type TDPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// TDSize is a cell size.
+type TDSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// TDThreat is a threat attached to a diagram element.
+type TDThreat struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Mitigation  string `json:"mitigation"`
+	ModelType   string `json:"modelType"`
+	New         bool   `json:"new"`
+	Number      int    `json:"number"`
+	Score       string `json:"score"`
+}
+
+// Layout constants for the generated diagram.
+const (
+	tdColumnActors  = 40
+	tdColumnProcess = 400
+	tdColumnStores  = 760
+	tdRowTop        = 80
+	tdRowSpacing    = 120
+	tdBoundaryInset = 30
+)
+
+// NewThreatDragonModel builds a Threat Dragon model from the PRD security model.
+func NewThreatDragonModel(doc *prd.Document) (*TDModel, error) {
+	sm := doc.SecurityModel
+	if sm == nil {
+		return nil, ErrNoSecurityModel
+	}
+	tm := sm.ThreatModel
+	name := projectName(doc)
+	key := doc.Metadata.ID + "/" + name
+
+	threats := make([]TDThreat, 0, len(tm.KeyThreats))
+	hasOpen := false
+	for i, t := range tm.KeyThreats {
+		status := "Open"
+		if isMitigated(t.Status) {
+			status = "Mitigated"
+		} else {
+			hasOpen = true
+		}
+		threatType := strideCategory(t.Category)
+		if threatType == "" {
+			threatType = t.Category
+		}
+		threats = append(threats, TDThreat{
+			ID:          stableUUID(key + "/threat/" + threatID(i, t)),
+			Title:       threatID(i, t),
+			Status:      status,
+			Severity:    tdSeverity(t.Severity),
+			Type:        threatType,
+			Description: t.Threat,
+			Mitigation:  t.Mitigation,
+			ModelType:   "STRIDE",
+			Number:      i + 1,
+		})
+	}
+
+	var cells []TDCell
+	z := 1
+
+	// Boundaries are drawn first so they sit beneath the process.
+	processY := tdRowTop + tdBoundaryInset*len(tm.TrustBoundaries)
+	for i, b := range tm.TrustBoundaries {
+		inset := tdBoundaryInset * (len(tm.TrustBoundaries) - i)
+		cells = append(cells, TDCell{
+			ID:       stableUUID(key + "/boundary/" + b),
+			Shape:    "trust-boundary-box",
+			Position: TDPosition{X: tdColumnProcess - inset, Y: processY - inset},
+			Size:     TDSize{Width: 160 + 2*inset, Height: 80 + 2*inset},
+			Attrs:    map[string]any{"label": map[string]any{"text": b}},
+			Visible:  true,
+			ZIndex:   z,
+			Data: map[string]any{
+				"type":            "tm.Boundary",
+				"name":            b,
+				"description":     "",
+				"isTrustBoundary": true,
+				"hasOpenThreats":  false,
+			},
+		})
+		z++
+	}
+
+	cells = append(cells, TDCell{
+		ID:       stableUUID(key + "/process"),
+		Shape:    "process",
+		Position: TDPosition{X: tdColumnProcess, Y: processY},
+		Size:     TDSize{Width: 160, Height: 80},
+		Attrs:    map[string]any{"text": map[string]any{"text": name}, "body": tdBodyAttrs(hasOpen)},
+		Visible:  true,
+		ZIndex:   z,
+		Data: map[string]any{
+			"type":                   "tm.Process",
+			"name":                   name,
+			"description":            sm.Overview,
+			"outOfScope":             false,
+			"reasonOutOfScope":       "",
+			"hasOpenThreats":         hasOpen,
+			"handlesCardPayment":     false,
+			"handlesGoodsOrServices": false,
+			"isWebApplication":       false,
+			"privilegeLevel":         "",
+			"threats":                threats,
+		},
+	})
+	z++
+
+	for i, actor := range tm.ThreatActors {
+		cells = append(cells, TDCell{
+			ID:       stableUUID(key + "/actor/" + actor),
+			Shape:    "actor",
+			Position: TDPosition{X: tdColumnActors, Y: tdRowTop + i*tdRowSpacing},
+			Size:     TDSize{Width: 160, Height: 80},
+			Attrs:    map[string]any{"text": map[string]any{"text": actor}, "body": tdBodyAttrs(false)},
+			Visible:  true,
+			ZIndex:   z,
+			Data: map[string]any{
+				"type":                   "tm.Actor",
+				"name":                   actor,
+				"description":            "Threat actor",
+				"outOfScope":             false,
+				"reasonOutOfScope":       "",
+				"hasOpenThreats":         false,
+				"providesAuthentication": false,
+				"threats":                []TDThreat{},
+			},
+		})
+		z++
+	}
+
+	for i, asset := range tm.Assets {
+		cells = append(cells, TDCell{
+			ID:       stableUUID(key + "/asset/" + asset),
+			Shape:    "store",
+			Position: TDPosition{X: tdColumnStores, Y: tdRowTop + i*tdRowSpacing},
+			Size:     TDSize{Width: 160, Height: 80},
+			Attrs:    map[string]any{"text": map[string]any{"text": asset}, "topLine": tdBodyAttrs(false), "bottomLine": tdBodyAttrs(false)},
+			Visible:  true,
+			ZIndex:   z,
+			Data: map[string]any{
+				"type":              "tm.Store",
+				"name":              asset,
+				"description":       "Asset",
+				"outOfScope":        false,
+				"reasonOutOfScope":  "",
+				"isALog":            false,
+				"storesCredentials": false,
+				"isEncrypted":       false,
+				"isSigned":          false,
+				"hasOpenThreats":    false,
+				"threats":           []TDThreat{},
+			},
+		})
+		z++
+	}
+
+	var contributors []TDContributor
+	for _, a := range doc.Metadata.Authors {
+		contributors = append(contributors, TDContributor{Name: a.Name})
+	}
+	if contributors == nil {
+		contributors = []TDContributor{}
+	}
+
+	return &TDModel{
+		Version: ThreatDragonVersion,
+		Summary: TDSummary{
+			Title:       name,
+			Owner:       projectOwner(doc),
+			Description: sm.Overview,
+		},
+		Detail: TDDetail{
+			Contributors: contributors,
+			Diagrams: []TDDiagram{{
+				ID:          0,
+				Title:       name,
+				DiagramType: "STRIDE",
+				Placeholder: "New STRIDE diagram description",
+				Thumbnail:   "./public/content/images/thumbnail.stride.jpg",
+				Version:     ThreatDragonVersion,
+				Cells:       cells,
+			}},
+			DiagramTop: 1,
+			ThreatTop:  len(threats),
+		},
+	}, nil
+}
+
+// tdSeverity maps a PRD severity to a Threat Dragon severity.
+func tdSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	}
+	return "TBD"
+}
+
+// tdBodyAttrs outlines elements with open threats in red, as Threat Dragon does.
+func tdBodyAttrs(hasOpenThreats bool) map[string]any {
+	if hasOpenThreats {
+		return map[string]any{"stroke": "red", "strokeWidth": 2.5, "strokeDasharray": nil}
+	}
+	return map[string]any{"stroke": "#333333", "strokeWidth": 1.0, "strokeDasharray": nil}
+}
@@ -0,0 +1,117 @@
+// Package threatmodel exports the PRD security threat model to formats used
+// by dedicated threat modeling tools, so security teams can continue the
+// model in their own tooling:
+//
+//   - Open Threat Model (OTM) JSON, see https://github.com/iriusrisk/OpenThreatModel
+//   - OWASP Threat Dragon v2 JSON, see https://owasp.org/www-project-threat-dragon/
+//
+// Assets, threat actors, trust boundaries, and key threats with their
+// mitigations are carried over. How PRD fields map onto each format, and
+// which values are defaulted, is documented on each renderer.
+package threatmodel
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// ErrNoSecurityModel is returned when the PRD has no security model to export.
+var ErrNoSecurityModel = errors.New("PRD has no securityModel section")
+
+// strideCategories are the STRIDE threat categories, in STRIDE order.
+var strideCategories = []string{
+	"Spoofing",
+	"Tampering",
+	"Repudiation",
+	"Information disclosure",
+	"Denial of service",
+	"Elevation of privilege",
+}
+
+// strideCategory returns the STRIDE category named in a free-form PRD
+// category such as "STRIDE: tampering", or "" if none matches.
+func strideCategory(category string) string {
+	c := strings.ToLower(category)
+	for _, s := range strideCategories {
+		if strings.Contains(c, strings.ToLower(s)) {
+			return s
+		}
+	}
+	return ""
+}
+
+// severityScore maps a PRD severity to a 0-100 rating.
+func severityScore(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 100
+	case "high":
+		return 75
+	case "medium":
+		return 50
+	case "low":
+		return 25
+	}
+	return 50
+}
+
+// isMitigated reports whether a PRD threat mitigation status means the
+// mitigation is in place.
+func isMitigated(status string) bool {
+	switch strings.ToLower(status) {
+	case "implemented", "verified", "mitigated":
+		return true
+	}
+	return false
+}
+
+// threatID returns the threat's ID, or a positional ID if it has none.
+func threatID(i int, t prd.SecurityThreat) string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return fmt.Sprintf("threat-%d", i+1)
+}
+
+// slug converts a display name into an identifier.
+func slug(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// stableUUID derives a UUID-formatted identifier from name so that repeated
+// exports of the same PRD produce the same IDs.
+func stableUUID(name string) string {
+	h := sha256.Sum256([]byte(name))
+	h[6] = (h[6] & 0x0f) | 0x80 // version 8 (custom)
+	h[8] = (h[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+func projectName(doc *prd.Document) string {
+	if doc.Metadata.Title != "" {
+		return doc.Metadata.Title
+	}
+	return doc.Metadata.ID
+}
+
+func projectOwner(doc *prd.Document) string {
+	if len(doc.Metadata.Authors) > 0 {
+		return doc.Metadata.Authors[0].Name
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package threatmodel
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/prd/render"
+)
+
+func createTestPRD() *prd.Document {
+	return &prd.Document{
+		Metadata: prd.Metadata{
+			ID:      "PRD-001",
+			Title:   "Payments API",
+			Authors: []prd.Person{{Name: "Jane Doe"}},
+			Tags:    []string{"payments"},
+		},
+		SecurityModel: &prd.SecurityModel{
+			Overview: "Zero trust payment processing",
+			ThreatModel: prd.ThreatModel{
+				Assets:          []string{"Cardholder data", "API keys"},
+				ThreatActors:    []string{"External attacker", "Malicious insider"},
+				TrustBoundaries: []string{"Internet", "Private VPC"},
+				KeyThreats: []prd.SecurityThreat{
+					{ID: "T-1", Threat: "Stolen API keys used to impersonate merchants", Category: "STRIDE: Spoofing", Mitigation: "Short-lived tokens", Severity: "high", Status: "implemented"},
+					{Threat: "Card data exfiltration", Category: "Information Disclosure", Mitigation: "Field-level encryption", Severity: "critical", Status: "planned"},
+					{Threat: "Unlogged refunds", Category: "audit", Severity: "medium"},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		r      render.Renderer
+		format string
+		ext    string
+	}{
+		{NewOTMRenderer(), "otm", ".otm.json"},
+		{NewThreatDragonRenderer(), "threat-dragon", ".threat-dragon.json"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Format(); got != tt.format {
+			t.Errorf("Format() = %q, want %q", got, tt.format)
+		}
+		if got := tt.r.FileExtension(); got != tt.ext {
+			t.Errorf("FileExtension() = %q, want %q", got, tt.ext)
+		}
+
+		out, err := tt.r.Render(createTestPRD(), nil)
+		if err != nil {
+			t.Fatalf("%s Render() error = %v", tt.format, err)
+		}
+		if !json.Valid(out) {
+			t.Errorf("%s output is not valid JSON", tt.format)
+		}
+
+		if _, err := tt.r.Render(&prd.Document{}, nil); !errors.Is(err, ErrNoSecurityModel) {
+			t.Errorf("%s Render() without security model error = %v", tt.format, err)
+		}
+	}
+}
+
+func TestNewOTM(t *testing.T) {
+	otm, err := NewOTM(createTestPRD())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if otm.OTMVersion != OTMVersion || otm.Project.ID != "PRD-001" || otm.Project.Owner != "Jane Doe" {
+		t.Errorf("unexpected project: %+v", otm.Project)
+	}
+	if len(otm.Assets) != 2 || otm.Assets[0].ID != "asset-cardholder-data" {
+		t.Errorf("Assets = %+v", otm.Assets)
+	}
+	if len(otm.TrustZones) != 2 || otm.TrustZones[1].ID != "tz-private-vpc" {
+		t.Errorf("TrustZones = %+v", otm.TrustZones)
+	}
+	if actors, ok := otm.Project.Attributes["threatActors"].([]string); !ok || len(actors) != 2 {
+		t.Errorf("threat actors not recorded: %v", otm.Project.Attributes)
+	}
+
+	if len(otm.Threats) != 3 || len(otm.Mitigations) != 2 {
+		t.Fatalf("got %d threats, %d mitigations", len(otm.Threats), len(otm.Mitigations))
+	}
+	if otm.Threats[0].Categories[0] != "Spoofing" || otm.Threats[0].Risk.Impact != 75 {
+		t.Errorf("threat 0 = %+v", otm.Threats[0])
+	}
+	if otm.Threats[1].ID != "threat-2" || otm.Threats[1].Categories[0] != "Information disclosure" {
+		t.Errorf("threat 1 = %+v", otm.Threats[1])
+	}
+	if otm.Threats[2].Categories[0] != "audit" {
+		t.Errorf("non-STRIDE category should pass through, got %v", otm.Threats[2].Categories)
+	}
+
+	comp := otm.Components[0]
+	if comp.Parent.TrustZone != "tz-internet" || len(comp.Threats) != 3 {
+		t.Fatalf("component = %+v", comp)
+	}
+	if comp.Threats[0].State != OTMThreatMitigated || comp.Threats[0].Mitigations[0].State != OTMMitigationImplemented {
+		t.Errorf("implemented mitigation should mark threat mitigated: %+v", comp.Threats[0])
+	}
+	if comp.Threats[1].State != OTMThreatExposed || comp.Threats[1].Mitigations[0].State != OTMMitigationRequired {
+		t.Errorf("planned mitigation should leave threat exposed: %+v", comp.Threats[1])
+	}
+	if comp.Threats[2].Mitigations != nil {
+		t.Errorf("threat without mitigation should have none: %+v", comp.Threats[2])
+	}
+}
+
+func TestNewOTMDefaultTrustZone(t *testing.T) {
+	doc := createTestPRD()
+	doc.SecurityModel.ThreatModel.TrustBoundaries = nil
+	otm, err := NewOTM(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(otm.TrustZones) != 1 || otm.Components[0].Parent.TrustZone != "tz-default" {
+		t.Errorf("expected default trust zone, got %+v", otm.TrustZones)
+	}
+}
+
+func TestNewThreatDragonModel(t *testing.T) {
+	model, err := NewThreatDragonModel(createTestPRD())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Version != ThreatDragonVersion || model.Summary.Title != "Payments API" {
+		t.Errorf("unexpected summary: %+v", model.Summary)
+	}
+
+	cells := model.Detail.Diagrams[0].Cells
+	shapes := map[string]int{}
+	var process TDCell
+	for _, c := range cells {
+		shapes[c.Shape]++
+		if c.Shape == "process" {
+			process = c
+		}
+	}
+	want := map[string]int{"trust-boundary-box": 2, "process": 1, "actor": 2, "store": 2}
+	for shape, n := range want {
+		if shapes[shape] != n {
+			t.Errorf("%d %s cells, want %d", shapes[shape], shape, n)
+		}
+	}
+
+	threats, ok := process.Data["threats"].([]TDThreat)
+	if !ok || len(threats) != 3 {
+		t.Fatalf("process threats = %v", process.Data["threats"])
+	}
+	if threats[0].Status != "Mitigated" || threats[0].Severity != "High" || threats[0].Type != "Spoofing" {
+		t.Errorf("threat 0 = %+v", threats[0])
+	}
+	if threats[1].Status != "Open" || threats[1].Severity != "Critical" || threats[2].Severity != "Medium" {
+		t.Errorf("threats = %+v", threats)
+	}
+	if process.Data["hasOpenThreats"] != true || model.Detail.ThreatTop != 3 {
+		t.Error("process should report open threats")
+	}
+
+	// IDs are stable across exports.
+	again, _ := NewThreatDragonModel(createTestPRD())
+	if again.Detail.Diagrams[0].Cells[0].ID != cells[0].ID {
+		t.Error("cell IDs should be deterministic")
+	}
+}
+
+func TestStableUUID(t *testing.T) {
+	id := stableUUID("x")
+	if len(id) != 36 || id[14] != '8' || id != stableUUID("x") || id == stableUUID("y") {
+		t.Errorf("stableUUID() = %q", id)
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import "github.com/grokify/structured-plan/termui"
+
+// Key is a key press, decoded from terminal input.
+type Key int
+
+// Keys the review responds to. Arrow keys and their vi equivalents decode
+// to the same Key.
+const (
+	KeyUnknown Key = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyBack
+	KeyResolve
+	KeyEarned
+	KeySave
+	KeyQuit
+	KeyCtrlC
+)
+
+// DecodeKeys decodes raw terminal input into key presses, as
+// termui.DecodeKeys does, and maps them to the keys of the review.
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+	for _, k := range termui.DecodeKeys(input) {
+		keys = append(keys, reviewKey(k))
+	}
+	return keys
+}
+
+// reviewKey maps a terminal key press to the key of the review it stands
+// for.
+func reviewKey(k termui.Key) Key {
+	k = k.Navigation()
+	switch k.Type {
+	case termui.KeyUp:
+		return KeyUp
+	case termui.KeyDown:
+		return KeyDown
+	case termui.KeyLeft:
+		return KeyLeft
+	case termui.KeyRight:
+		return KeyRight
+	case termui.KeyHome:
+		return KeyHome
+	case termui.KeyEnd:
+		return KeyEnd
+	case termui.KeyEnter:
+		return KeyEnter
+	case termui.KeyBackspace, termui.KeyEsc:
+		return KeyBack
+	case termui.KeyCtrlC:
+		return KeyCtrlC
+	case termui.KeyRune:
+		switch k.Rune {
+		case ' ':
+			return KeyEnter
+		case 'r':
+			return KeyResolve
+		case 'e':
+			return KeyEarned
+		case 'w':
+			return KeySave
+		case 'q':
+			return KeyQuit
+		}
+	}
+	return KeyUnknown
+}
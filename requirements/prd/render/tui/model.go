@@ -0,0 +1,415 @@
+// Package tui provides an interactive terminal review of PRD evaluation
+// reports: browse the scoring categories, read their findings inline,
+// inspect the JSON each finding points at, and mark findings resolved.
+//
+// The Model follows the model-update-view pattern: Update applies one key
+// press and View renders the whole screen, so both can be tested without a
+// terminal. Run shows a Model on the terminal with the termui package,
+// which the editor package uses too.
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/requirements/prd/render/terminal"
+	"github.com/grokify/structured-plan/termui"
+)
+
+// ResolutionPrefix starts the text of the comments that record findings
+// resolved during a review.
+const ResolutionPrefix = "Resolved in review: "
+
+// Item is a finding shown under its category.
+type Item struct {
+	Finding evaluation.Finding
+
+	// Pointer is the JSON pointer of the entity the finding is about, or
+	// empty for category-level findings.
+	Pointer string
+
+	// Resolved is true when the finding is marked resolved.
+	Resolved bool
+
+	saved bool // resolved by a comment in the document
+}
+
+// Resolvable reports whether the item can be marked resolved. Only
+// findings about a specific entity can be; category-level findings go away
+// when the category score improves.
+func (it *Item) Resolvable() bool {
+	return it.Pointer != ""
+}
+
+// CommentPath returns the comment path of the item's entity, e.g.
+// "requirements.functional[2]" for /requirements/functional/2.
+func (it *Item) CommentPath() string {
+	return commentPath(it.Pointer)
+}
+
+// ResolutionText returns the text of the comment recording that the item
+// was resolved.
+func (it *Item) ResolutionText() string {
+	text := ResolutionPrefix + it.Finding.Title
+	if it.Finding.ID != "" {
+		text += " (" + it.Finding.ID + ")"
+	}
+	return text
+}
+
+// Section is a scoring category with its findings.
+type Section struct {
+	Name     string
+	Category evaluation.CategoryScore
+
+	// Items are the findings that cost points: the report's findings for
+	// the category, then the entities that lost points.
+	Items []*Item
+
+	// Earned are the entities that earned points, shown on request.
+	Earned []*Item
+
+	expanded bool
+}
+
+// Open returns the number of unresolved findings in the section.
+func (s *Section) Open() int {
+	n := 0
+	for _, it := range s.Items {
+		if !it.Resolved {
+			n++
+		}
+	}
+	return n
+}
+
+// Model is the state of a review session.
+type Model struct {
+	Report   *evaluation.EvaluationReport
+	Sections []*Section
+
+	doc        any // the document's JSON form, for showing finding values
+	showEarned bool
+	list       termui.List
+	detail     *Item
+	detailTop  int
+	status     string
+	dirty      bool
+	quitArmed  bool
+}
+
+// New builds a review of report for doc, the document it was scored from.
+// Findings that already have a matching resolved comment in comments start
+// out resolved.
+func New(report *evaluation.EvaluationReport, doc any, comments []common.Comment) (*Model, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	m := &Model{Report: report}
+	if err := json.Unmarshal(data, &m.doc); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+
+	resolved := make(map[string]bool)
+	for _, c := range comments {
+		if c.Resolved && strings.HasPrefix(c.Text, ResolutionPrefix) {
+			resolved[c.Path+"\x00"+c.Text] = true
+		}
+	}
+
+	for _, cs := range report.Categories {
+		s := &Section{Name: terminal.CategoryDisplayName(cs.Category), Category: cs}
+		for _, f := range report.Findings {
+			if f.Category == cs.Category {
+				s.Items = append(s.Items, &Item{Finding: f})
+			}
+		}
+		for _, f := range cs.Findings {
+			it := &Item{Finding: f, Pointer: f.Evidence}
+			if f.Severity == evaluation.SeverityInfo {
+				s.Earned = append(s.Earned, it)
+				continue
+			}
+			if resolved[it.CommentPath()+"\x00"+it.ResolutionText()] {
+				it.Resolved, it.saved = true, true
+			}
+			s.Items = append(s.Items, it)
+		}
+		// Open the categories with open findings.
+		s.expanded = s.Open() > 0
+		m.Sections = append(m.Sections, s)
+	}
+	return m, nil
+}
+
+// Dirty reports whether resolutions changed since the last save.
+func (m *Model) Dirty() bool {
+	return m.dirty
+}
+
+// Saved records that the resolutions were saved, with a status message.
+func (m *Model) Saved(message string) {
+	for _, s := range m.Sections {
+		for _, it := range s.Items {
+			it.saved = it.Resolved
+		}
+	}
+	m.dirty = false
+	m.status = message
+}
+
+// SetStatus sets the message shown at the bottom of the screen.
+func (m *Model) SetStatus(message string) {
+	m.status = message
+}
+
+// ApplyResolutions updates comments to match the resolved findings: a
+// resolved comment is added for each newly resolved finding, and the
+// comment of each finding marked open again is removed. It returns the
+// updated comments and the number of findings added and reopened.
+func (m *Model) ApplyResolutions(comments []common.Comment, author string, at time.Time) ([]common.Comment, int, int, error) {
+	added, reopened := 0, 0
+	for _, s := range m.Sections {
+		for _, it := range s.Items {
+			switch {
+			case it.Resolved && !it.saved:
+				var c common.Comment
+				var err error
+				comments, c, err = common.AddComment(comments, common.Comment{
+					Author: author, CreatedAt: at, Path: it.CommentPath(), Text: it.ResolutionText(),
+				})
+				if err != nil {
+					return nil, 0, 0, err
+				}
+				if _, err := common.ResolveComment(comments, c.ID, author, at); err != nil {
+					return nil, 0, 0, err
+				}
+				added++
+			case !it.Resolved && it.saved:
+				var kept []common.Comment
+				for _, c := range comments {
+					if c.Resolved && c.Path == it.CommentPath() && c.Text == it.ResolutionText() {
+						continue
+					}
+					kept = append(kept, c)
+				}
+				comments = kept
+				reopened++
+			}
+		}
+	}
+	return comments, added, reopened, nil
+}
+
+// row is a line of the section list: a section, or one of its items.
+type row struct {
+	section *Section
+	item    *Item
+}
+
+func (m *Model) rows() []row {
+	var rows []row
+	for _, s := range m.Sections {
+		rows = append(rows, row{section: s})
+		if !s.expanded {
+			continue
+		}
+		for _, it := range s.Items {
+			rows = append(rows, row{section: s, item: it})
+		}
+		if m.showEarned {
+			for _, it := range s.Earned {
+				rows = append(rows, row{section: s, item: it})
+			}
+		}
+	}
+	return rows
+}
+
+// Current returns the section and item under the cursor; item is nil on a
+// section row.
+func (m *Model) Current() (*Section, *Item) {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	r := rows[min(m.list.Cursor, len(rows)-1)]
+	return r.section, r.item
+}
+
+// Update applies a key press. It returns termui.ActionSave when the user
+// asks to save the resolutions.
+func (m *Model) Update(k Key) termui.Action {
+	if k != KeyQuit {
+		m.quitArmed = false
+	}
+	m.status = ""
+
+	switch k {
+	case KeyCtrlC:
+		return termui.ActionQuit
+	case KeyQuit:
+		if m.dirty && !m.quitArmed {
+			m.quitArmed = true
+			m.status = "Unsaved resolutions: press w to save, or q again to discard them"
+			return termui.ActionNone
+		}
+		return termui.ActionQuit
+	case KeySave:
+		if !m.dirty {
+			m.status = "No changes to save"
+			return termui.ActionNone
+		}
+		return termui.ActionSave
+	case KeyResolve:
+		m.toggleResolved()
+		return termui.ActionNone
+	case KeyEarned:
+		_, cur := m.Current()
+		m.showEarned = !m.showEarned
+		m.moveTo(cur)
+		return termui.ActionNone
+	}
+
+	if m.detail != nil {
+		switch k {
+		case KeyUp:
+			m.detailTop = max(0, m.detailTop-1)
+		case KeyDown:
+			m.detailTop++
+		case KeyBack, KeyLeft, KeyEnter:
+			m.detail = nil
+		}
+		return termui.ActionNone
+	}
+
+	rows := m.rows()
+	switch k {
+	case KeyUp:
+		m.list.Cursor = max(0, m.list.Cursor-1)
+	case KeyDown:
+		m.list.Cursor = min(len(rows)-1, m.list.Cursor+1)
+	case KeyHome:
+		m.list.Cursor = 0
+	case KeyEnd:
+		m.list.Cursor = len(rows) - 1
+	case KeyEnter, KeyRight:
+		s, it := m.Current()
+		switch {
+		case it != nil:
+			m.detail, m.detailTop = it, 0
+		case s != nil && k == KeyRight:
+			s.expanded = true
+		case s != nil:
+			s.expanded = !s.expanded
+		}
+	case KeyLeft, KeyBack:
+		s, it := m.Current()
+		if s != nil {
+			s.expanded = false
+			if it != nil {
+				m.moveToSection(s)
+			}
+		}
+	}
+	return termui.ActionNone
+}
+
+func (m *Model) toggleResolved() {
+	it := m.detail
+	if it == nil {
+		_, it = m.Current()
+	}
+	switch {
+	case it == nil:
+		m.status = "Select a finding to resolve"
+	case it.Finding.Severity == evaluation.SeverityInfo:
+		m.status = "This entity earned points; there is nothing to resolve"
+	case !it.Resolvable():
+		m.status = "Category findings are resolved by improving the category score"
+	default:
+		it.Resolved = !it.Resolved
+		m.dirty = true
+		for _, s := range m.Sections {
+			for _, other := range s.Items {
+				if other.saved != other.Resolved {
+					return
+				}
+			}
+		}
+		m.dirty = false
+	}
+}
+
+// moveTo puts the cursor on the row of it, if it is visible.
+func (m *Model) moveTo(it *Item) {
+	if it == nil {
+		return
+	}
+	for i, r := range m.rows() {
+		if r.item == it {
+			m.list.Cursor = i
+			return
+		}
+	}
+}
+
+func (m *Model) moveToSection(s *Section) {
+	for i, r := range m.rows() {
+		if r.section == s && r.item == nil {
+			m.list.Cursor = i
+			return
+		}
+	}
+}
+
+// commentPath converts a JSON pointer to a comment path, with array
+// indexes in brackets: /requirements/functional/2/title becomes
+// requirements.functional[2].title.
+func commentPath(pointer string) string {
+	var sb strings.Builder
+	for _, t := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if t == "" {
+			continue
+		}
+		t = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+		if isIndex(t) {
+			sb.WriteString("[" + t + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(t)
+	}
+	return sb.String()
+}
+
+func isIndex(t string) bool {
+	for _, r := range t {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return t != ""
+}
+
+// value returns the JSON at the item's pointer, indented.
+func (m *Model) value(it *Item) (string, error) {
+	v, err := jsonpatch.Get(m.doc, it.Pointer)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/termui"
+)
+
+func testDocument() *prd.Document {
+	return &prd.Document{
+		Metadata: prd.Metadata{ID: "PRD-1", Title: "Checkout"},
+		Requirements: prd.Requirements{Functional: []prd.FunctionalRequirement{
+			{ID: "FR-1", Title: "Passkeys", Priority: "must"},
+		}},
+	}
+}
+
+func newModel(t *testing.T, doc *prd.Document) *Model {
+	t.Helper()
+	m, err := New(prd.ScoreToEvaluationReport(doc, "checkout.prd.json"), doc, doc.Comments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// moveToItem puts the cursor on the first finding with a pointer.
+func moveToItem(t *testing.T, m *Model) *Item {
+	t.Helper()
+	for range m.rows() {
+		if _, it := m.Current(); it != nil && it.Resolvable() {
+			return it
+		}
+		m.Update(KeyDown)
+	}
+	t.Fatal("no resolvable finding")
+	return nil
+}
+
+func TestDecodeKeys(t *testing.T) {
+	got := DecodeKeys([]byte("j\x1b[Ak\r\x1b[1~\x1bOBq\x03"))
+	want := []Key{KeyDown, KeyUp, KeyUp, KeyEnter, KeyHome, KeyDown, KeyQuit, KeyCtrlC}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeKeys = %v, want %v", got, want)
+	}
+	if got := DecodeKeys([]byte("\x1b")); !reflect.DeepEqual(got, []Key{KeyBack}) {
+		t.Errorf("lone escape = %v", got)
+	}
+}
+
+func TestReview(t *testing.T) {
+	doc := testDocument()
+	m := newModel(t, doc)
+
+	it := moveToItem(t, m)
+	if it.CommentPath() != "requirements.functional[0]" {
+		t.Errorf("comment path = %q", it.CommentPath())
+	}
+
+	m.Update(KeyEnter)
+	view := m.View(100, 40)
+	for _, want := range []string{"Path:     /requirements/functional/0", `"title": "Passkeys"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail view missing %q:\n%s", want, view)
+		}
+	}
+	m.Update(KeyBack)
+
+	m.Update(KeyResolve)
+	if !it.Resolved || !m.Dirty() {
+		t.Fatal("finding not marked resolved")
+	}
+	if m.Update(KeyQuit) != termui.ActionNone {
+		t.Error("quit with unsaved resolutions should ask first")
+	}
+	if m.Update(KeySave) != termui.ActionSave {
+		t.Fatal("save not requested")
+	}
+
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	comments, added, reopened, err := m.ApplyResolutions(doc.Comments, "dana", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 || reopened != 0 || len(comments) != 1 || !comments[0].Resolved {
+		t.Fatalf("comments = %+v (added %d, reopened %d)", comments, added, reopened)
+	}
+	doc.Comments = comments
+	m.Saved("saved")
+	if m.Dirty() || m.Update(KeyQuit) != termui.ActionQuit {
+		t.Error("saved review should quit without asking")
+	}
+
+	// A new review picks the resolution up from the comment, and
+	// reopening the finding removes it.
+	m = newModel(t, doc)
+	it = moveToItem(t, m)
+	if !it.Resolved {
+		t.Fatal("resolution not loaded from comments")
+	}
+	m.Update(KeyResolve)
+	comments, added, reopened, err = m.ApplyResolutions(doc.Comments, "dana", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 0 || reopened != 1 || len(comments) != 0 {
+		t.Errorf("comments = %+v (added %d, reopened %d)", comments, added, reopened)
+	}
+}
+
+func TestCommentPath(t *testing.T) {
+	for pointer, want := range map[string]string{
+		"/risks/2":                           "risks[2]",
+		"/executiveSummary/problemStatement": "executiveSummary.problemStatement",
+		"/objectives/okrs/0/keyResults/1":    "objectives.okrs[0].keyResults[1]",
+	} {
+		if got := commentPath(pointer); got != want {
+			t.Errorf("commentPath(%q) = %q, want %q", pointer, got, want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/grokify/structured-plan/termui"
+)
+
+// ErrNotTerminal is returned by Run when input or output is not a terminal.
+var ErrNotTerminal = termui.ErrNotTerminal
+
+// Run shows m on the terminal until the user quits. save is called when
+// the user saves resolutions; it should write them and call m.Saved. On
+// return the terminal is restored.
+func Run(in, out *os.File, m *Model, save func() error) error {
+	return termui.Run(in, out, screen{m}, save)
+}
+
+// screen shows a Model with termui, which sends it terminal key presses.
+type screen struct{ *Model }
+
+func (s screen) Update(k termui.Key) termui.Action {
+	return s.Model.Update(reviewKey(k))
+}
@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/structured-evaluation/evaluation"
+
+	"github.com/grokify/structured-plan/termui"
+)
+
+const (
+	headerLines = 3
+	footerLines = 4
+
+	listHelp   = "↑/↓ move  enter open  ← close  r resolve  e earned points  w save  q quit"
+	detailHelp = "↑/↓ scroll  ← back  r resolve  w save  q quit"
+)
+
+// View renders the screen for a terminal of the given size.
+func (m *Model) View(width, height int) string {
+	width = max(width, 40)
+	body := max(height-headerLines-footerLines, 3)
+
+	lines := m.header(width)
+	var info []string
+	if m.detail != nil {
+		lines = append(lines, m.detailBody(body)...)
+		info = []string{"", ""}
+	} else {
+		lines = append(lines, m.listBody(body)...)
+		info = m.info()
+	}
+
+	lines = append(lines, strings.Repeat("─", width))
+	lines = append(lines, info...)
+	switch {
+	case m.status != "":
+		lines = append(lines, m.status)
+	case m.detail != nil:
+		lines = append(lines, detailHelp)
+	default:
+		lines = append(lines, listHelp)
+	}
+	for i, l := range lines {
+		lines[i] = termui.Truncate(l, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) header(width int) []string {
+	r := m.Report
+	title := "PRD REVIEW"
+	if r.Metadata.DocumentTitle != "" {
+		title += " — " + r.Metadata.DocumentTitle
+	}
+	if r.Metadata.Document != "" {
+		title += " (" + r.Metadata.Document + ")"
+	}
+	open, resolved := 0, 0
+	for _, s := range m.Sections {
+		for _, it := range s.Items {
+			if it.Resolved {
+				resolved++
+			} else {
+				open++
+			}
+		}
+	}
+	summary := fmt.Sprintf("Score %.1f/10 · Decision: %s · %d open findings", r.WeightedScore, r.Decision.Status, open)
+	if resolved > 0 {
+		summary += fmt.Sprintf(", %d resolved", resolved)
+	}
+	if m.dirty {
+		summary += " · unsaved"
+	}
+	return []string{title, summary, strings.Repeat("─", width)}
+}
+
+// listBody renders the visible part of the section list, scrolled to keep
+// the cursor in view.
+func (m *Model) listBody(height int) []string {
+	rows := m.rows()
+	return m.list.Lines(len(rows), height, func(i int) string {
+		if rows[i].item == nil {
+			return sectionLine(rows[i].section, m.showEarned)
+		}
+		return "    " + itemLine(rows[i].item)
+	})
+}
+
+func sectionLine(s *Section, showEarned bool) string {
+	arrow := "▸"
+	if s.expanded {
+		arrow = "▾"
+	}
+	if len(s.Items) == 0 && (!showEarned || len(s.Earned) == 0) {
+		arrow = " "
+	}
+	line := fmt.Sprintf("%s %-24s %-5s %4.1f/%.0f", arrow, s.Name, s.Category.Status, s.Category.Score, s.Category.MaxScore)
+	if n := s.Open(); n > 0 {
+		line += fmt.Sprintf("  %d open", n)
+	}
+	if n := len(s.Items) - s.Open(); n > 0 {
+		line += fmt.Sprintf("  %d resolved", n)
+	}
+	return line
+}
+
+func itemLine(it *Item) string {
+	f := it.Finding
+	mark := "✗"
+	switch {
+	case f.Severity == evaluation.SeverityInfo:
+		mark = "+"
+	case it.Resolved:
+		mark = "✓"
+	}
+	line := mark + " "
+	if !it.Resolvable() {
+		line += "[" + string(f.Severity) + "] "
+	}
+	line += f.Title
+	if f.ID != "" {
+		line += " (" + f.ID + ")"
+	}
+	if f.Description != "" && f.Description != f.Title {
+		line += ": " + f.Description
+	}
+	return line
+}
+
+// info describes the current row in two lines below the list.
+func (m *Model) info() []string {
+	s, it := m.Current()
+	switch {
+	case it != nil && it.Resolvable():
+		return []string{"Path: " + it.Pointer, "Press enter to show the value at the path"}
+	case it != nil:
+		return []string{"Fix: " + it.Finding.Recommendation, effortLine(it.Finding)}
+	case s != nil:
+		return []string{s.Category.Justification, s.Category.Evidence}
+	}
+	return []string{"", ""}
+}
+
+func effortLine(f evaluation.Finding) string {
+	var parts []string
+	if f.Owner != "" {
+		parts = append(parts, "Owner: "+f.Owner)
+	}
+	if f.Effort != "" {
+		parts = append(parts, "Effort: "+f.Effort)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// detailBody renders the finding being inspected and the JSON value at its
+// pointer, scrolled by detailTop.
+func (m *Model) detailBody(height int) []string {
+	it := m.detail
+	f := it.Finding
+	state := "open"
+	if it.Resolved {
+		state = "resolved"
+	}
+	lines := []string{
+		"Finding:  " + f.Title,
+		fmt.Sprintf("Severity: %s · %s", f.Severity, state),
+	}
+	if f.ID != "" {
+		lines = append(lines, "ID:       "+f.ID)
+	}
+	if f.Description != "" && f.Description != f.Title {
+		lines = append(lines, "Excerpt:  "+f.Description)
+	}
+	if f.Recommendation != "" {
+		lines = append(lines, "Fix:      "+f.Recommendation)
+	}
+	if it.Resolvable() {
+		lines = append(lines, "Path:     "+it.Pointer, "Comment:  "+it.CommentPath(), "")
+		if v, err := m.value(it); err != nil {
+			lines = append(lines, "The path no longer exists in the document: "+err.Error())
+		} else {
+			lines = append(lines, strings.Split(v, "\n")...)
+		}
+	}
+
+	m.detailTop = max(0, min(m.detailTop, len(lines)-height))
+	lines = lines[m.detailTop:]
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
@@ -21,8 +21,14 @@ type FunctionalRequirement struct {
 	Tags               []string              `json:"tags,omitempty"` // For filtering by topic/domain
 	Notes              string                `json:"notes,omitempty"`
 
+	// Feedback links customer feedback supporting this requirement.
+	Feedback []FeedbackRef `json:"feedback,omitempty"`
+
 	// AppendixRefs references appendices with additional details for this requirement.
 	AppendixRefs []string `json:"appendixRefs,omitempty"`
+
+	// ExternalRefs links this requirement to other systems, e.g. its Jira issue.
+	ExternalRefs ExternalRefs `json:"externalRefs,omitempty"`
 }
 
 // NFRCategory represents categories of non-functional requirements.
@@ -47,6 +53,7 @@ const (
 	NFRExtensibility    NFRCategory = "extensibility"
 	NFRInteroperability NFRCategory = "interoperability"
 	NFRLocalization     NFRCategory = "localization"
+	NFRAccessibility    NFRCategory = "accessibility"
 )
 
 // NonFunctionalRequirement represents a non-functional requirement.
@@ -74,8 +81,14 @@ type NonFunctionalRequirement struct {
 
 	Tags []string `json:"tags,omitempty"` // For filtering by topic/domain
 
+	// Feedback links customer feedback supporting this requirement.
+	Feedback []FeedbackRef `json:"feedback,omitempty"`
+
 	// AppendixRefs references appendices with additional details for this requirement.
 	AppendixRefs []string `json:"appendixRefs,omitempty"`
+
+	// ExternalRefs links this requirement to other systems, e.g. its Jira issue.
+	ExternalRefs ExternalRefs `json:"externalRefs,omitempty"`
 }
 
 // SLOSpec defines Service Level Objective specifications.
@@ -0,0 +1,55 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (d *Document) generateResourcing() string {
+	var sb strings.Builder
+	sb.WriteString("## Resourcing\n\n")
+
+	if table := d.Roadmap.FormatStaffingTable(d.Resourcing); table != "" {
+		sb.WriteString("### Staffing Summary\n\n")
+		sb.WriteString("*Full-time equivalents (FTE) by role and phase.*\n\n")
+		sb.WriteString(table + "\n")
+	}
+
+	phaseNames := make(map[string]string, len(d.Roadmap.Phases))
+	for _, p := range d.Roadmap.Phases {
+		phaseNames[p.ID] = p.Name
+	}
+
+	sb.WriteString("### Allocations\n\n")
+	sb.WriteString("| Role | Function | Count | Allocation | Phase | Team | Dates | Notes |\n")
+	sb.WriteString("|------|----------|------:|-----------:|-------|------|-------|-------|\n")
+	for _, a := range d.Resourcing {
+		percent := a.Percent
+		if percent == 0 {
+			percent = 100
+		}
+		phase := a.PhaseID
+		if n := phaseNames[phase]; n != "" {
+			phase = n
+		}
+		dates := ""
+		if a.StartDate != "" || a.EndDate != "" {
+			dates = a.StartDate + " to " + a.EndDate
+		}
+		cells := []string{a.Role, string(a.RoleFunction()), fmt.Sprintf("%d", a.Count), fmt.Sprintf("%g%%", percent),
+			phase, a.Team, dates, a.Notes}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	sb.WriteString("\n")
+
+	if issues := d.Roadmap.ValidateResourcing(d.Resourcing); len(issues) > 0 {
+		sb.WriteString("**Warnings:**\n\n")
+		for _, issue := range issues {
+			sb.WriteString("- " + issue.Message + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
@@ -0,0 +1,31 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+func TestGenerateResourcing(t *testing.T) {
+	doc := Document{
+		Roadmap: Roadmap{Phases: []roadmap.Phase{{ID: "p1", Name: "MVP", Deliverables: []Deliverable{{ID: "d1"}}}}},
+		Resourcing: []Allocation{
+			{Role: "Backend Engineer", Count: 3, PhaseID: "p1", Percent: 50, Team: "Platform"},
+		},
+	}
+	md := doc.ToMarkdown(MarkdownOptions{})
+	for _, want := range []string{
+		"[Resourcing](#sec-resourcing)",
+		"### Staffing Summary",
+		"| Backend Engineer | 1.5 |",
+		"| Backend Engineer | engineering | 3 | 50% | MVP | Platform |  |  |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q", want)
+		}
+	}
+	if strings.Contains(md, "**Warnings:**") {
+		t.Errorf("unexpected resourcing warnings in:\n%s", md)
+	}
+}
@@ -28,6 +28,9 @@ type DeliverableType = roadmap.DeliverableType
 // DeliverableStatus represents the status of a deliverable.
 type DeliverableStatus = roadmap.DeliverableStatus
 
+// Allocation assigns people in a role to a roadmap phase.
+type Allocation = roadmap.Allocation
+
 // Constants re-exported for backward compatibility.
 const (
 	// PhaseType constants
@@ -127,7 +127,7 @@ func (d *Document) ToSwimlaneTableWithGoals(opts RoadmapTableOptions) string {
 						item = item[:opts.MaxTitleLen-3] + "..."
 					}
 					if opts.IncludeStatus && del.Status != "" {
-						item = fmt.Sprintf("%s %s", roadmap.StatusIcon(del.Status), item)
+						item = fmt.Sprintf("%s %s", opts.StatusMarker(del.Status), item)
 					}
 					items = append(items, "• "+item)
 				}
@@ -195,7 +195,7 @@ func (d *Document) ToSwimlaneTableWithGoals(opts RoadmapTableOptions) string {
 					// Format: R1: Title → Target
 					label := fmt.Sprintf("%s → %s", r.Title, r.PhaseTarget)
 					if opts.IncludeStatus && r.Status != "" {
-						label = fmt.Sprintf("%s %s", roadmap.PhaseTargetStatusIcon(r.Status), label)
+						label = fmt.Sprintf("%s %s", opts.PhaseTargetStatusMarker(r.Status), label)
 					}
 					items = append(items, "• "+label)
 				}
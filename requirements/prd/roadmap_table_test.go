@@ -128,6 +128,61 @@ func TestToSwimlaneTableNoStatus(t *testing.T) {
 	}
 }
 
+func TestToSwimlaneTableTextStatus(t *testing.T) {
+	roadmap := Roadmap{
+		Phases: []Phase{
+			{
+				ID:   "phase-1",
+				Name: "Phase 1",
+				Deliverables: []Deliverable{
+					{ID: "d1", Title: "Feature A", Type: DeliverableFeature, Status: DeliverableCompleted},
+				},
+			},
+		},
+	}
+
+	opts := DefaultRoadmapTableOptions()
+	opts.TextStatus = true
+	table := roadmap.ToSwimlaneTable(opts)
+
+	if !strings.Contains(table, "• [done] Feature A") || strings.Contains(table, "✅") {
+		t.Errorf("Expected a text status marker instead of an emoji, got:\n%s", table)
+	}
+}
+
+func TestToMarkdownIcons(t *testing.T) {
+	doc := &Document{
+		Metadata: Metadata{Title: "Icons"},
+		Roadmap: Roadmap{Phases: []Phase{{
+			ID: "phase-1", Name: "MVP",
+			Deliverables: []Deliverable{{ID: "d1", Title: "Login", Type: DeliverableFeature, Status: DeliverableCompleted}},
+		}}},
+		OpenItems: []OpenItem{{Title: "Vendor", Status: OpenItemStatusResolved}},
+	}
+
+	for _, tt := range []struct {
+		icons   string
+		want    []string
+		unwants []string
+	}{
+		{"", []string{"✅ Login", "**Status:** ✅ Resolved", "**Legend:**"}, nil},
+		{"text", []string{"[done] Login", "**Status:** Resolved"}, []string{"✅", "**Legend:**"}},
+		{"none", []string{"• Login", "**Status:** Resolved"}, []string{"✅", "[done]", "**Legend:**"}},
+	} {
+		md := doc.ToMarkdown(MarkdownOptions{IncludeSwimlaneTable: true, Icons: tt.icons})
+		for _, want := range tt.want {
+			if !strings.Contains(md, want) {
+				t.Errorf("Icons %q: missing %q", tt.icons, want)
+			}
+		}
+		for _, unwant := range tt.unwants {
+			if strings.Contains(md, unwant) {
+				t.Errorf("Icons %q: unexpected %q", tt.icons, unwant)
+			}
+		}
+	}
+}
+
 func TestToPhaseTable(t *testing.T) {
 	roadmap := Roadmap{
 		Phases: []Phase{
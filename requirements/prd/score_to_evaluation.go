@@ -39,6 +39,7 @@ func ScoreToEvaluationReport(doc *Document, filename string) *evaluation.Evaluat
 			Weight:        cs.Weight,
 			Justification: cs.Justification,
 			Evidence:      cs.Evidence,
+			Findings:      referenceFindings(cs),
 		}
 		evalCategory.ComputeStatus()
 		report.Categories = append(report.Categories, evalCategory)
@@ -70,6 +71,30 @@ func ScoreToEvaluationReport(doc *Document, filename string) *evaluation.Evaluat
 	return report
 }
 
+// referenceFindings carries the category's score references as category
+// findings: info severity for entities that earned points, low for entities
+// that lost them. The reference reason is the title, the excerpt the
+// description, and the JSON pointer the evidence. Category findings do not
+// count toward the report decision.
+func referenceFindings(cs CategoryScore) []evaluation.Finding {
+	var findings []evaluation.Finding
+	for _, ref := range cs.References {
+		severity := evaluation.SeverityInfo
+		if ref.Impact == ImpactLost {
+			severity = evaluation.SeverityLow
+		}
+		findings = append(findings, evaluation.Finding{
+			ID:          ref.ID,
+			Category:    cs.Category,
+			Severity:    severity,
+			Title:       ref.Reason,
+			Description: ref.Excerpt,
+			Evidence:    ref.Pointer,
+		})
+	}
+	return findings
+}
+
 func severityFromString(s string) evaluation.Severity {
 	switch s {
 	case "blocker":
@@ -91,7 +116,7 @@ func generateFixRecommendationForCategory(category string) string {
 		"solution_fit":          "Document solution options with pros/cons and selection rationale",
 		"scope_discipline":      "Define clear objectives and out-of-scope items",
 		"requirements_quality":  "Add functional requirements with acceptance criteria and NFRs",
-		"ux_coverage":           "Add UX requirements including wireframes, flows, and accessibility",
+		"ux_coverage":           "Add UX requirements including wireframes, flows, journey maps, and accessibility",
 		"technical_feasibility": "Document technical architecture with integration points and tech stack",
 		"metrics_quality":       "Define success metrics with targets, baselines, and measurement methods",
 		"risk_management":       "Identify risks with mitigations; document assumptions and constraints",
@@ -99,6 +124,9 @@ func generateFixRecommendationForCategory(category string) string {
 	if rec, ok := recommendations[category]; ok {
 		return rec
 	}
+	if r, ok := registeredScorer(category).(FixRecommender); ok {
+		return r.FixRecommendation()
+	}
 	return "Review and improve this category"
 }
 
@@ -0,0 +1,128 @@
+package prd
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Scorer scores one category of a PRD. Registered scorers take part in
+// Score alongside the built-in categories: their weight counts toward the
+// weighted score, low scores raise blockers and revision triggers, and
+// their results appear in evaluation reports.
+type Scorer interface {
+	// Category describes the category. Its ID must be unique and its
+	// Weight positive; Name, Description, and Owner are used in reports,
+	// evaluation templates, and revision triggers.
+	Category() EvaluationCategory
+
+	// Score scores doc from 0 to 10. The category, weight, and maximum
+	// score of the result are set by the caller.
+	Score(doc *Document) CategoryScore
+}
+
+// FixRecommender is implemented by scorers that suggest how to fix a low
+// score. Without it, findings recommend reviewing the category.
+type FixRecommender interface {
+	FixRecommendation() string
+}
+
+type scorerFunc struct {
+	category EvaluationCategory
+	score    func(doc *Document) CategoryScore
+}
+
+func (s scorerFunc) Category() EvaluationCategory      { return s.category }
+func (s scorerFunc) Score(doc *Document) CategoryScore { return s.score(doc) }
+
+// NewScorer returns a Scorer for category that scores with fn.
+func NewScorer(category EvaluationCategory, fn func(doc *Document) CategoryScore) Scorer {
+	return scorerFunc{category: category, score: fn}
+}
+
+var scorers struct {
+	sync.RWMutex
+	list []Scorer
+}
+
+// RegisterScorer adds a custom category to PRD scoring. It returns an
+// error if the category has no ID, a non-positive weight, or the ID of a
+// built-in or already registered category.
+func RegisterScorer(s Scorer) error {
+	cat := s.Category()
+	if cat.ID == "" {
+		return fmt.Errorf("scorer category has no ID")
+	}
+	if cat.Weight <= 0 {
+		return fmt.Errorf("scorer category %s: weight must be positive", cat.ID)
+	}
+	scorers.Lock()
+	defer scorers.Unlock()
+	if slices.ContainsFunc(StandardCategories(), func(c EvaluationCategory) bool { return c.ID == cat.ID }) ||
+		slices.ContainsFunc(scorers.list, func(r Scorer) bool { return r.Category().ID == cat.ID }) {
+		return fmt.Errorf("scorer category %s is already registered", cat.ID)
+	}
+	scorers.list = append(scorers.list, s)
+	return nil
+}
+
+// UnregisterScorer removes the registered scorer of the category with the
+// given ID. It reports whether one was registered.
+func UnregisterScorer(id string) bool {
+	scorers.Lock()
+	defer scorers.Unlock()
+	n := len(scorers.list)
+	scorers.list = slices.DeleteFunc(scorers.list, func(s Scorer) bool { return s.Category().ID == id })
+	return len(scorers.list) < n
+}
+
+// RegisteredScorers returns the registered scorers in registration order.
+func RegisteredScorers() []Scorer {
+	scorers.RLock()
+	defer scorers.RUnlock()
+	return slices.Clone(scorers.list)
+}
+
+// registeredScorer returns the registered scorer of the category with the
+// given ID, or nil.
+func registeredScorer(id string) Scorer {
+	for _, s := range RegisteredScorers() {
+		if s.Category().ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// Categories returns the standard evaluation categories followed by those
+// of the registered scorers.
+func Categories() []EvaluationCategory {
+	cats := StandardCategories()
+	for _, s := range RegisteredScorers() {
+		cats = append(cats, s.Category())
+	}
+	return cats
+}
+
+// Weights returns the default category weights followed by those of the
+// registered scorers. Scores are normalized by the total weight, so custom
+// weights need not keep the sum at 1.0.
+func Weights() []CategoryWeight {
+	weights := DefaultWeights()
+	for _, s := range RegisteredScorers() {
+		cat := s.Category()
+		weights = append(weights, CategoryWeight{Category: cat.ID, Weight: cat.Weight})
+	}
+	return weights
+}
+
+// CategoryName returns the human-readable name of a standard or registered
+// category, or "" if it is unknown.
+func CategoryName(id string) string {
+	for _, c := range Categories() {
+		if c.ID == id {
+			return c.Name
+		}
+	}
+	return ""
+}
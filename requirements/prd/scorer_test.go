@@ -0,0 +1,94 @@
+package prd
+
+import (
+	"math"
+	"testing"
+)
+
+type complianceScorer struct{}
+
+func (complianceScorer) Category() EvaluationCategory {
+	return EvaluationCategory{
+		ID:          "compliance_readiness",
+		Name:        "Compliance Readiness",
+		Description: "Compliance controls mapped to requirements",
+		Weight:      0.10,
+		Owner:       "compliance",
+	}
+}
+
+func (complianceScorer) Score(doc *Document) CategoryScore {
+	score := CategoryScore{Score: 2, Justification: "No compliance controls"}
+	if doc.SecurityModel != nil && len(doc.SecurityModel.ComplianceControls) > 0 {
+		score.Score, score.Justification = 9, "Compliance controls mapped"
+	}
+	return score
+}
+
+func (complianceScorer) FixRecommendation() string {
+	return "Map compliance controls in securityModel.complianceControls"
+}
+
+func TestRegisterScorer(t *testing.T) {
+	if err := RegisterScorer(complianceScorer{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { UnregisterScorer("compliance_readiness") })
+
+	if err := RegisterScorer(complianceScorer{}); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	builtin := NewScorer(EvaluationCategory{ID: "ux_coverage", Weight: 1}, nil)
+	if err := RegisterScorer(builtin); err == nil {
+		t.Error("expected registering a built-in category to fail")
+	}
+	if err := RegisterScorer(NewScorer(EvaluationCategory{ID: "x"}, nil)); err == nil {
+		t.Error("expected a zero weight to fail")
+	}
+
+	doc := &Document{}
+	result := Score(doc)
+	last := result.CategoryScores[len(result.CategoryScores)-1]
+	if last.Category != "compliance_readiness" || last.Weight != 0.10 || last.Score != 2 || !last.BelowThreshold {
+		t.Errorf("custom category score = %+v", last)
+	}
+	var trigger *RevisionTrigger
+	for i, rt := range result.RevisionTriggers {
+		if rt.Category == "compliance_readiness" {
+			trigger = &result.RevisionTriggers[i]
+		}
+	}
+	if trigger == nil || trigger.Severity != "blocker" || trigger.RecommendedOwner != "compliance" {
+		t.Errorf("revision trigger = %+v", trigger)
+	}
+
+	var total, weighted float64
+	for _, cs := range result.CategoryScores {
+		total += cs.Weight
+		weighted += cs.Score * cs.Weight
+	}
+	if math.Abs(result.WeightedScore-weighted/total) > 1e-9 {
+		t.Errorf("WeightedScore = %v, want %v", result.WeightedScore, weighted/total)
+	}
+
+	report := ScoreToEvaluationReport(doc, "x.prd.json")
+	found := false
+	for _, f := range report.Findings {
+		if f.Category == "compliance_readiness" {
+			found = f.Recommendation == complianceScorer{}.FixRecommendation()
+		}
+	}
+	if !found {
+		t.Errorf("findings = %+v, want the custom fix recommendation", report.Findings)
+	}
+	if CategoryName("compliance_readiness") != "Compliance Readiness" {
+		t.Errorf("CategoryName = %q", CategoryName("compliance_readiness"))
+	}
+	if n := len(GenerateEvaluationTemplate(doc, "x").Categories); n != len(StandardCategories())+1 {
+		t.Errorf("template categories = %d, want the registered category included", n)
+	}
+
+	if !UnregisterScorer("compliance_readiness") || len(Score(doc).CategoryScores) != len(DefaultWeights()) {
+		t.Error("UnregisterScorer did not remove the category")
+	}
+}
@@ -2,6 +2,7 @@ package prd
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -45,6 +46,34 @@ type CategoryScore struct {
 	Justification  string  `json:"justification"`
 	Evidence       string  `json:"evidence"`
 	BelowThreshold bool    `json:"belowThreshold"`
+
+	// References point at the entities that earned or lost points, so a
+	// finding can be traced to a specific requirement or persona.
+	References []ScoreReference `json:"references,omitempty"`
+}
+
+// ReferenceImpact tells whether a referenced entity earned or lost points.
+type ReferenceImpact string
+
+const (
+	ImpactEarned ReferenceImpact = "earned"
+	ImpactLost   ReferenceImpact = "lost"
+)
+
+// ScoreReference points at a document entity that affected a category score.
+type ScoreReference struct {
+	// Pointer is a JSON pointer (RFC 6901) to the entity in the PRD, e.g.
+	// "/requirements/functional/2".
+	Pointer string          `json:"pointer"`
+	ID      string          `json:"id,omitempty"`
+	Impact  ReferenceImpact `json:"impact"`
+
+	// Reason names the check the entity passed or failed, e.g.
+	// "no acceptance criteria".
+	Reason string `json:"reason"`
+
+	// Excerpt is the start of the entity's text.
+	Excerpt string `json:"excerpt,omitempty"`
 }
 
 // ScoringResult contains the complete scoring output.
@@ -63,7 +92,7 @@ func Score(doc *Document) *ScoringResult {
 		CategoryScores: make([]CategoryScore, 0),
 	}
 
-	weights := DefaultWeights()
+	weights := Weights()
 	var totalWeightedScore float64
 	var totalWeight float64
 	revisionID := 1
@@ -146,6 +175,11 @@ func scoreCategory(doc *Document, category string) CategoryScore {
 	case "risk_management":
 		return scoreRiskManagement(doc)
 	default:
+		if s := registeredScorer(category); s != nil {
+			score := s.Score(doc)
+			score.Category = category
+			return score
+		}
 		return CategoryScore{Category: category, Score: 5.0, Justification: "Unknown category"}
 	}
 }
@@ -160,6 +194,7 @@ func scoreProblemDefinition(doc *Document) CategoryScore {
 	if doc.ExecutiveSummary.ProblemStatement != "" {
 		points += 3.0
 		evidence = append(evidence, "Problem statement present in executive summary")
+		score.earned("/executiveSummary/problemStatement", "", "problem statement", doc.ExecutiveSummary.ProblemStatement)
 		hasStatement = true
 	}
 
@@ -168,6 +203,7 @@ func scoreProblemDefinition(doc *Document) CategoryScore {
 		if doc.Problem.Statement != "" && !hasStatement {
 			points += 3.0
 			evidence = append(evidence, "Detailed problem statement present")
+			score.earned("/problem/statement", doc.Problem.ID, "problem statement", doc.Problem.Statement)
 		}
 
 		if doc.Problem.UserImpact != "" {
@@ -180,9 +216,10 @@ func scoreProblemDefinition(doc *Document) CategoryScore {
 			evidence = append(evidence, fmt.Sprintf("%d evidence sources", len(doc.Problem.Evidence)))
 
 			// Bonus for high-strength evidence
-			for _, e := range doc.Problem.Evidence {
+			for i, e := range doc.Problem.Evidence {
 				if e.Strength == StrengthHigh {
 					points += 0.5
+					score.earned(fmt.Sprintf("/problem/evidence/%d", i), e.ID, "high-strength evidence", firstNonEmpty(e.Summary, e.Source))
 					break
 				}
 			}
@@ -226,20 +263,27 @@ func scoreUserUnderstanding(doc *Document) CategoryScore {
 		hasPainPoints := false
 		hasBehaviors := false
 		hasPrimary := false
-		for _, persona := range doc.Personas {
-			if len(persona.PainPoints) > 0 {
+		for i, persona := range doc.Personas {
+			pointer := fmt.Sprintf("/personas/%d", i)
+			if len(persona.PainPoints) > 0 && !hasPainPoints {
 				hasPainPoints = true
+				score.earned(pointer, persona.ID, "pain points documented", persona.PainPoints[0])
 			}
 			if len(persona.Behaviors) > 0 {
 				hasBehaviors = true
 			}
-			if persona.IsPrimary {
+			if persona.IsPrimary && !hasPrimary {
 				hasPrimary = true
+				score.earned(pointer, persona.ID, "primary persona", persona.Name)
 			}
 		}
 		if hasPainPoints {
 			points += 2.0
 			evidence = append(evidence, "Pain points documented")
+		} else {
+			for i, persona := range doc.Personas {
+				score.lost(fmt.Sprintf("/personas/%d", i), persona.ID, "no pain points", persona.Name)
+			}
 		}
 		if hasBehaviors {
 			points += 1.0
@@ -263,6 +307,17 @@ func scoreUserUnderstanding(doc *Document) CategoryScore {
 		evidence = append(evidence, fmt.Sprintf("%d user stories", len(doc.UserStories)))
 	}
 
+	// Personas the early phases do nothing for
+	if cov := doc.PersonaCoverage(); cov.Scheduled() {
+		if neglected := cov.Neglected(); len(neglected) > 0 {
+			points = max(points-1.0, 0)
+			evidence = append(evidence, fmt.Sprintf("%d persona(s) without stories or requirements in early phases", len(neglected)))
+			for _, r := range neglected {
+				score.lost(fmt.Sprintf("/personas/%d", personaIndex(doc, r.PersonaID)), r.PersonaID, "neglected in early phases", r.Name)
+			}
+		}
+	}
+
 	score.Score = minFloat(points, 10.0)
 	score.Evidence = strings.Join(evidence, "; ")
 	score.Justification = generateJustification("user_understanding", score.Score)
@@ -289,9 +344,10 @@ func scoreMarketAwareness(doc *Document) CategoryScore {
 		// Check for different types
 		hasCompetitor := false
 		hasWorkaround := false
-		for _, alt := range doc.Market.Alternatives {
-			if alt.Type == AlternativeCompetitor {
+		for i, alt := range doc.Market.Alternatives {
+			if alt.Type == AlternativeCompetitor && !hasCompetitor {
 				hasCompetitor = true
+				score.earned(fmt.Sprintf("/market/alternatives/%d", i), alt.ID, "competitor analyzed", alt.Name)
 			}
 			if alt.Type == AlternativeWorkaround || alt.Type == AlternativeDoNothing {
 				hasWorkaround = true
@@ -355,6 +411,11 @@ func scoreSolutionFit(doc *Document) CategoryScore {
 	if doc.Solution.SelectedSolutionID != "" {
 		points += 2.0
 		evidence = append(evidence, "Solution selected")
+		for i, opt := range doc.Solution.SolutionOptions {
+			if opt.ID == doc.Solution.SelectedSolutionID {
+				score.earned(fmt.Sprintf("/solution/solutionOptions/%d", i), opt.ID, "selected solution", opt.Name)
+			}
+		}
 	}
 
 	// Check rationale
@@ -445,12 +506,15 @@ func scoreRequirementsQuality(doc *Document) CategoryScore {
 		hasAC := false
 		hasTraceability := false
 		hasPriority := false
-		for _, req := range doc.Requirements.Functional {
-			if len(req.AcceptanceCriteria) > 0 {
+		for i, req := range doc.Requirements.Functional {
+			pointer := fmt.Sprintf("/requirements/functional/%d", i)
+			if len(req.AcceptanceCriteria) > 0 && !hasAC {
 				hasAC = true
+				score.earned(pointer, req.ID, "acceptance criteria present", req.Title)
 			}
-			if len(req.UserStoryIDs) > 0 {
+			if len(req.UserStoryIDs) > 0 && !hasTraceability {
 				hasTraceability = true
+				score.earned(pointer, req.ID, "traced to user stories", req.Title)
 			}
 			if req.Priority != "" {
 				hasPriority = true
@@ -468,6 +532,18 @@ func scoreRequirementsQuality(doc *Document) CategoryScore {
 			points += 1.0
 			evidence = append(evidence, "Priorities assigned")
 		}
+		for i, req := range doc.Requirements.Functional {
+			pointer := fmt.Sprintf("/requirements/functional/%d", i)
+			if !hasAC {
+				score.lost(pointer, req.ID, "no acceptance criteria", req.Title)
+			}
+			if !hasTraceability {
+				score.lost(pointer, req.ID, "not traced to a user story", req.Title)
+			}
+			if !hasPriority {
+				score.lost(pointer, req.ID, "no priority", req.Title)
+			}
+		}
 	}
 
 	// Check NFRs
@@ -532,10 +608,50 @@ func scoreUXCoverage(doc *Document) CategoryScore {
 		evidence = append(evidence, fmt.Sprintf("%d interaction flows", len(doc.UXRequirements.InteractionFlows)))
 	}
 
-	// Check accessibility
-	if doc.UXRequirements.Accessibility.Standard != "" {
-		points += 2.0
-		evidence = append(evidence, "Accessibility requirements defined")
+	// Check journey maps: credit grows with the share of personas mapped
+	if journeys := doc.UXRequirements.JourneyMaps; len(journeys) > 0 {
+		points += 1.0
+		evidence = append(evidence, fmt.Sprintf("%d journey maps", len(journeys)))
+		if len(doc.Personas) > 0 {
+			covered := doc.JourneyPersonaCoverage()
+			points += float64(covered) / float64(len(doc.Personas))
+			if covered < len(doc.Personas) {
+				mapped := make(map[string]bool, len(journeys))
+				for _, j := range journeys {
+					mapped[j.PersonaID] = true
+				}
+				for i, p := range doc.Personas {
+					if !mapped[p.ID] {
+						score.lost(fmt.Sprintf("/personas/%d", i), p.ID, "no journey map", p.Name)
+					}
+				}
+			}
+		}
+	}
+
+	// Check accessibility: a declared standard earns credit only to the
+	// extent it is carried into NFRs, acceptance criteria, and user-facing FRs.
+	if report := CheckAccessibility(doc); report != nil {
+		points += 0.5
+		evidence = append(evidence, fmt.Sprintf("Accessibility standard: %s", report.Standard))
+		if len(report.NFRIDs) > 0 {
+			points += 0.5
+			evidence = append(evidence, fmt.Sprintf("%d accessibility NFRs", len(report.NFRIDs)))
+		}
+		if len(report.CriteriaIDs) > 0 {
+			points += 0.5
+			evidence = append(evidence, fmt.Sprintf("%d accessibility acceptance criteria", len(report.CriteriaIDs)))
+		}
+		points += 0.5 * report.FRCoverage()
+		if len(report.UncoveredFRIDs) > 0 {
+			evidence = append(evidence, fmt.Sprintf("%d of %d user-facing FRs lack accessibility consideration",
+				len(report.UncoveredFRIDs), report.UserFacingFRs))
+			for i, req := range doc.Requirements.Functional {
+				if slices.Contains(report.UncoveredFRIDs, req.ID) {
+					score.lost(fmt.Sprintf("/requirements/functional/%d", i), req.ID, "no accessibility consideration", req.Title)
+				}
+			}
+		}
 	}
 
 	// Check brand guidelines
@@ -624,16 +740,20 @@ func scoreMetricsQuality(doc *Document) CategoryScore {
 		hasTargets := false
 		hasBaseline := false
 		hasMeasurement := false
-		for _, okr := range doc.Objectives.OKRs {
-			for _, kr := range okr.KeyResults {
-				if kr.Target != "" {
+		for i, okr := range doc.Objectives.OKRs {
+			for j, kr := range okr.KeyResults {
+				pointer := fmt.Sprintf("/objectives/okrs/%d/keyResults/%d", i, j)
+				if kr.Target != "" && !hasTargets {
 					hasTargets = true
+					score.earned(pointer, kr.ID, "target defined", kr.Title)
 				}
-				if kr.Baseline != "" {
+				if kr.Baseline != "" && !hasBaseline {
 					hasBaseline = true
+					score.earned(pointer, kr.ID, "baseline documented", kr.Title)
 				}
-				if kr.MeasurementMethod != "" {
+				if kr.MeasurementMethod != "" && !hasMeasurement {
 					hasMeasurement = true
+					score.earned(pointer, kr.ID, "measurement method specified", kr.Title)
 				}
 			}
 		}
@@ -649,6 +769,20 @@ func scoreMetricsQuality(doc *Document) CategoryScore {
 			points += 2.0
 			evidence = append(evidence, "Measurement methods specified")
 		}
+		for i, okr := range doc.Objectives.OKRs {
+			for j, kr := range okr.KeyResults {
+				pointer := fmt.Sprintf("/objectives/okrs/%d/keyResults/%d", i, j)
+				if !hasTargets {
+					score.lost(pointer, kr.ID, "no target", kr.Title)
+				}
+				if !hasBaseline {
+					score.lost(pointer, kr.ID, "no baseline", kr.Title)
+				}
+				if !hasMeasurement {
+					score.lost(pointer, kr.ID, "no measurement method", kr.Title)
+				}
+			}
+		}
 	}
 
 	score.Score = minFloat(points, 10.0)
@@ -670,9 +804,10 @@ func scoreRiskManagement(doc *Document) CategoryScore {
 
 		// Check for validated assumptions
 		hasValidated := false
-		for _, a := range doc.Assumptions.Assumptions {
+		for i, a := range doc.Assumptions.Assumptions {
 			if a.Validated {
 				hasValidated = true
+				score.earned(fmt.Sprintf("/assumptions/assumptions/%d", i), a.ID, "assumption validated", a.Description)
 				break
 			}
 		}
@@ -689,15 +824,20 @@ func scoreRiskManagement(doc *Document) CategoryScore {
 
 		// Check for mitigations
 		hasMitigation := false
-		for _, r := range doc.Risks {
+		for i, r := range doc.Risks {
 			if r.Mitigation != "" {
 				hasMitigation = true
+				score.earned(fmt.Sprintf("/risks/%d", i), r.ID, "mitigation documented", r.Description)
 				break
 			}
 		}
 		if hasMitigation {
 			points += 1.0
 			evidence = append(evidence, "Mitigations documented")
+		} else {
+			for i, r := range doc.Risks {
+				score.lost(fmt.Sprintf("/risks/%d", i), r.ID, "no mitigation", r.Description)
+			}
 		}
 	}
 
@@ -763,6 +903,9 @@ func categoryToOwner(category string) string {
 	if owner, ok := owners[category]; ok {
 		return owner
 	}
+	if s := registeredScorer(category); s != nil && s.Category().Owner != "" {
+		return s.Category().Owner
+	}
 	return "prd-lead"
 }
 
@@ -822,3 +965,62 @@ func hasTechnologyStack(ts TechnologyStack) bool {
 		len(ts.DevOps) > 0 ||
 		len(ts.Monitoring) > 0
 }
+
+// maxLostReferences limits the entities listed for one failed check, so a
+// PRD with fifty requirements and no acceptance criteria stays readable.
+const maxLostReferences = 5
+
+// maxExcerptLen is the length, in characters, of a reference excerpt.
+const maxExcerptLen = 80
+
+// earned records an entity that earned points in the category.
+func (cs *CategoryScore) earned(pointer, id, reason, text string) {
+	cs.References = append(cs.References, ScoreReference{
+		Pointer: pointer, ID: id, Impact: ImpactEarned, Reason: reason, Excerpt: excerpt(text),
+	})
+}
+
+// lost records an entity that failed a check in the category. Only the
+// first maxLostReferences entities are kept for each reason.
+func (cs *CategoryScore) lost(pointer, id, reason, text string) {
+	n := 0
+	for _, ref := range cs.References {
+		if ref.Impact == ImpactLost && ref.Reason == reason {
+			n++
+		}
+	}
+	if n >= maxLostReferences {
+		return
+	}
+	cs.References = append(cs.References, ScoreReference{
+		Pointer: pointer, ID: id, Impact: ImpactLost, Reason: reason, Excerpt: excerpt(text),
+	})
+}
+
+func excerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxExcerptLen {
+		return strings.TrimSpace(string(r[:maxExcerptLen-1])) + "…"
+	}
+	return s
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// personaIndex returns the index of the persona with the ID, or -1.
+func personaIndex(doc *Document, id string) int {
+	for i, p := range doc.Personas {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,6 +1,7 @@
 package prd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -281,3 +282,41 @@ func TestHasTechnologyStack(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreReferences(t *testing.T) {
+	doc := &Document{
+		Personas: []Persona{{ID: "p-1", Name: "Admin", IsPrimary: true}},
+		Requirements: Requirements{Functional: []FunctionalRequirement{
+			{ID: "FR-001", Title: "Single sign-on"},
+			{ID: "FR-002", Title: "Audit log export"},
+		}},
+	}
+	result := Score(doc)
+
+	refs := map[string][]ScoreReference{}
+	for _, cs := range result.CategoryScores {
+		refs[cs.Category] = cs.References
+	}
+
+	want := ScoreReference{Pointer: "/requirements/functional/1", ID: "FR-002", Impact: ImpactLost,
+		Reason: "no acceptance criteria", Excerpt: "Audit log export"}
+	found := false
+	for _, ref := range refs["requirements_quality"] {
+		if ref == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("requirements_quality references = %+v, want %+v", refs["requirements_quality"], want)
+	}
+
+	user := refs["user_understanding"]
+	if len(user) != 2 || user[0].Impact != ImpactEarned || user[0].Reason != "primary persona" ||
+		user[1].Impact != ImpactLost || user[1].Pointer != "/personas/0" {
+		t.Errorf("user_understanding references = %+v", user)
+	}
+
+	if got := excerpt(strings.Repeat("word ", 40)); len([]rune(got)) != maxExcerptLen || !strings.HasSuffix(got, "…") {
+		t.Errorf("excerpt = %q", got)
+	}
+}
@@ -0,0 +1,497 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of items removed by a scope cut.
+const (
+	DroppedPhase                    = "phase"
+	DroppedDeliverable              = "deliverable"
+	DroppedUserStory                = "user story"
+	DroppedFunctionalRequirement    = "functional requirement"
+	DroppedNonFunctionalRequirement = "non-functional requirement"
+	DroppedObjective                = "objective"
+	DroppedKeyResult                = "key result"
+)
+
+// Feasibility of a key result phase target after a scope cut.
+const (
+	FeasibilityReduced     = "reduced"     // Some contributing deliverables were cut
+	FeasibilityUnsupported = "unsupported" // All contributing deliverables were cut
+	FeasibilityCut         = "cut"         // The key result itself was cut
+)
+
+// DroppedItem is an item removed by a scope cut.
+type DroppedItem struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title"`
+	PhaseID string `json:"phaseId,omitempty"`
+	Reason  string `json:"reason"` // "tagged" or "phase dropped"
+}
+
+// ScopeSize counts the planned work in a document or phase.
+type ScopeSize struct {
+	Phases                    int `json:"phases,omitempty"`
+	Deliverables              int `json:"deliverables"`
+	UserStories               int `json:"userStories"`
+	StoryPoints               int `json:"storyPoints"`
+	FunctionalRequirements    int `json:"functionalRequirements"`
+	NonFunctionalRequirements int `json:"nonFunctionalRequirements"`
+	KeyResults                int `json:"keyResults,omitempty"`
+}
+
+// PhaseImpact compares the work scheduled in one phase before and after a
+// scope cut.
+type PhaseImpact struct {
+	PhaseID string    `json:"phaseId"`
+	Name    string    `json:"name"`
+	Dropped bool      `json:"dropped,omitempty"`
+	Before  ScopeSize `json:"before"`
+	After   ScopeSize `json:"after"`
+}
+
+// KeyResultImpact describes a key result phase target that loses
+// contributing deliverables, or a key result that is cut outright.
+type KeyResultImpact struct {
+	ObjectiveTitle string `json:"objectiveTitle"`
+	KeyResultID    string `json:"keyResultId,omitempty"`
+	KeyResultTitle string `json:"keyResultTitle"`
+	PhaseID        string `json:"phaseId,omitempty"`
+	Target         string `json:"target,omitempty"`
+	Before         int    `json:"before"` // Contributing deliverables
+	After          int    `json:"after"`
+	Status         string `json:"status"`
+}
+
+// CategoryDelta compares one scoring category before and after a scope cut.
+type CategoryDelta struct {
+	Category string  `json:"category"`
+	Before   float64 `json:"before"`
+	After    float64 `json:"after"`
+}
+
+// ScoreImpact compares the deterministic score and completeness of a
+// document before and after a scope cut.
+type ScoreImpact struct {
+	Before             float64         `json:"before"`
+	After              float64         `json:"after"`
+	DecisionBefore     string          `json:"decisionBefore"`
+	DecisionAfter      string          `json:"decisionAfter"`
+	CompletenessBefore float64         `json:"completenessBefore"`
+	CompletenessAfter  float64         `json:"completenessAfter"`
+	Categories         []CategoryDelta `json:"categories,omitempty"` // Changed categories only
+}
+
+// ScopeSimulation is a what-if comparison of a document against the same
+// document with tagged items cut.
+type ScopeSimulation struct {
+	Tags       []string          `json:"tags"`
+	Before     ScopeSize         `json:"before"`
+	After      ScopeSize         `json:"after"`
+	Phases     []PhaseImpact     `json:"phases,omitempty"`
+	KeyResults []KeyResultImpact `json:"keyResults,omitempty"`
+	// UnaffectedTargets counts phase targets that keep all of their
+	// contributing deliverables.
+	UnaffectedTargets int           `json:"unaffectedTargets"`
+	Score             ScoreImpact   `json:"score"`
+	Dropped           []DroppedItem `json:"dropped,omitempty"`
+}
+
+// DropByTags returns a new Document with every scope item that has at least
+// one of the specified tags removed; it is the complement of FilterByTags
+// for phases, deliverables, user stories, requirements, and OKRs. A tagged
+// phase is removed with its deliverables and with the user stories and
+// requirements scheduled in it, and a tagged objective with its key
+// results. Personas, risks, and untagged sections are kept.
+func (d Document) DropByTags(tags ...string) Document {
+	reduced, _ := d.dropByTags(tags)
+	return reduced
+}
+
+func (d Document) dropByTags(tags []string) (Document, []DroppedItem) {
+	if len(tags) == 0 {
+		return d, nil
+	}
+	var dropped []DroppedItem
+	droppedPhases := map[string]bool{}
+
+	reduced := d
+	reduced.Roadmap = Roadmap{Phases: make([]Phase, 0, len(d.Roadmap.Phases))}
+	for _, phase := range d.Roadmap.Phases {
+		if hasAnyTag(phase.Tags, tags) {
+			droppedPhases[phase.ID] = true
+			dropped = append(dropped, DroppedItem{Kind: DroppedPhase, ID: phase.ID, Title: phase.Name, Reason: "tagged"})
+			for _, del := range phase.Deliverables {
+				dropped = append(dropped, DroppedItem{Kind: DroppedDeliverable, ID: del.ID, Title: del.Title, PhaseID: phase.ID, Reason: "phase dropped"})
+			}
+			continue
+		}
+		phaseCopy := phase
+		phaseCopy.Deliverables = make([]Deliverable, 0, len(phase.Deliverables))
+		for _, del := range phase.Deliverables {
+			if hasAnyTag(del.Tags, tags) {
+				dropped = append(dropped, DroppedItem{Kind: DroppedDeliverable, ID: del.ID, Title: del.Title, PhaseID: phase.ID, Reason: "tagged"})
+				continue
+			}
+			phaseCopy.Deliverables = append(phaseCopy.Deliverables, del)
+		}
+		reduced.Roadmap.Phases = append(reduced.Roadmap.Phases, phaseCopy)
+	}
+
+	// reason reports why a scheduled item is cut, or "" if it is kept.
+	reason := func(itemTags []string, phaseID string) string {
+		switch {
+		case hasAnyTag(itemTags, tags):
+			return "tagged"
+		case droppedPhases[phaseID]:
+			return "phase dropped"
+		}
+		return ""
+	}
+
+	reduced.UserStories = nil
+	for _, us := range d.UserStories {
+		if r := reason(us.Tags, us.PhaseID); r != "" {
+			dropped = append(dropped, DroppedItem{Kind: DroppedUserStory, ID: us.ID, Title: us.Title, PhaseID: us.PhaseID, Reason: r})
+			continue
+		}
+		reduced.UserStories = append(reduced.UserStories, us)
+	}
+
+	reduced.Requirements.Functional = nil
+	for _, fr := range d.Requirements.Functional {
+		if r := reason(fr.Tags, fr.PhaseID); r != "" {
+			dropped = append(dropped, DroppedItem{Kind: DroppedFunctionalRequirement, ID: fr.ID, Title: fr.Title, PhaseID: fr.PhaseID, Reason: r})
+			continue
+		}
+		reduced.Requirements.Functional = append(reduced.Requirements.Functional, fr)
+	}
+
+	reduced.Requirements.NonFunctional = nil
+	for _, nfr := range d.Requirements.NonFunctional {
+		if r := reason(nfr.Tags, nfr.PhaseID); r != "" {
+			dropped = append(dropped, DroppedItem{Kind: DroppedNonFunctionalRequirement, ID: nfr.ID, Title: nfr.Title, PhaseID: nfr.PhaseID, Reason: r})
+			continue
+		}
+		reduced.Requirements.NonFunctional = append(reduced.Requirements.NonFunctional, nfr)
+	}
+
+	reduced.Objectives.OKRs = nil
+	for _, o := range d.Objectives.OKRs {
+		if hasAnyTag(o.Objective.Tags, tags) {
+			dropped = append(dropped, DroppedItem{Kind: DroppedObjective, ID: o.Objective.ID, Title: o.Objective.Title, Reason: "tagged"})
+			continue
+		}
+		// keep records cut key results only from the list okrKeyResults
+		// reads, so that duplicated lists are not reported twice.
+		keep := func(krs []KeyResult, record bool) []KeyResult {
+			var kept []KeyResult
+			for _, kr := range krs {
+				if hasAnyTag(kr.Tags, tags) {
+					if record {
+						dropped = append(dropped, DroppedItem{Kind: DroppedKeyResult, ID: kr.ID, Title: kr.Title, Reason: "tagged"})
+					}
+					continue
+				}
+				kept = append(kept, kr)
+			}
+			return kept
+		}
+		okrCopy := o
+		okrCopy.KeyResults = keep(o.KeyResults, true)
+		okrCopy.Objective.KeyResults = keep(o.Objective.KeyResults, len(o.KeyResults) == 0)
+		reduced.Objectives.OKRs = append(reduced.Objectives.OKRs, okrCopy)
+	}
+
+	return reduced, dropped
+}
+
+// SimulateDrop compares the document with the result of DropByTags(tags),
+// reporting the change in roadmap size, the key result phase targets that
+// lose contributing deliverables, and the change in score. The deliverables
+// contributing to a phase target are those in the target's phase that share
+// a tag with the key result, or all of the phase's deliverables if none do.
+func (d *Document) SimulateDrop(tags ...string) *ScopeSimulation {
+	reduced, dropped := d.dropByTags(tags)
+	sim := &ScopeSimulation{
+		Tags:    tags,
+		Before:  d.scopeSize(),
+		After:   reduced.scopeSize(),
+		Dropped: dropped,
+	}
+
+	afterPhases := make(map[string]Phase, len(reduced.Roadmap.Phases))
+	for _, p := range reduced.Roadmap.Phases {
+		afterPhases[p.ID] = p
+	}
+	for _, p := range d.Roadmap.Phases {
+		after, ok := afterPhases[p.ID]
+		impact := PhaseImpact{PhaseID: p.ID, Name: p.Name, Dropped: !ok, Before: d.phaseScope(p)}
+		if ok {
+			impact.After = reduced.phaseScope(after)
+		}
+		sim.Phases = append(sim.Phases, impact)
+	}
+
+	for _, o := range d.Objectives.OKRs {
+		objectiveCut := hasAnyTag(o.Objective.Tags, tags)
+		for _, kr := range okrKeyResults(o) {
+			if objectiveCut || hasAnyTag(kr.Tags, tags) {
+				sim.KeyResults = append(sim.KeyResults, KeyResultImpact{
+					ObjectiveTitle: o.Objective.Title, KeyResultID: kr.ID, KeyResultTitle: kr.Title, Status: FeasibilityCut,
+				})
+				continue
+			}
+			for _, pt := range kr.PhaseTargets {
+				before := contributingDeliverables(kr, phaseByID(d.Roadmap.Phases, pt.PhaseID))
+				if len(before) == 0 {
+					continue
+				}
+				after := 0
+				if p, ok := afterPhases[pt.PhaseID]; ok {
+					for _, del := range before {
+						if hasDeliverable(p, del.ID) {
+							after++
+						}
+					}
+				}
+				if after == len(before) {
+					sim.UnaffectedTargets++
+					continue
+				}
+				status := FeasibilityReduced
+				if after == 0 {
+					status = FeasibilityUnsupported
+				}
+				sim.KeyResults = append(sim.KeyResults, KeyResultImpact{
+					ObjectiveTitle: o.Objective.Title, KeyResultID: kr.ID, KeyResultTitle: kr.Title,
+					PhaseID: pt.PhaseID, Target: pt.Target, Before: len(before), After: after, Status: status,
+				})
+			}
+		}
+	}
+
+	scoreBefore, scoreAfter := Score(d), Score(&reduced)
+	sim.Score = ScoreImpact{
+		Before:             scoreBefore.WeightedScore,
+		After:              scoreAfter.WeightedScore,
+		DecisionBefore:     scoreBefore.Decision,
+		DecisionAfter:      scoreAfter.Decision,
+		CompletenessBefore: d.CheckCompleteness().OverallScore,
+		CompletenessAfter:  reduced.CheckCompleteness().OverallScore,
+	}
+	for _, cb := range scoreBefore.CategoryScores {
+		for _, ca := range scoreAfter.CategoryScores {
+			if ca.Category == cb.Category && ca.Score != cb.Score {
+				sim.Score.Categories = append(sim.Score.Categories, CategoryDelta{Category: cb.Category, Before: cb.Score, After: ca.Score})
+			}
+		}
+	}
+
+	return sim
+}
+
+// scopeSize counts the planned work in the whole document.
+func (d *Document) scopeSize() ScopeSize {
+	s := ScopeSize{
+		Phases:                    len(d.Roadmap.Phases),
+		UserStories:               len(d.UserStories),
+		FunctionalRequirements:    len(d.Requirements.Functional),
+		NonFunctionalRequirements: len(d.Requirements.NonFunctional),
+	}
+	for _, p := range d.Roadmap.Phases {
+		s.Deliverables += len(p.Deliverables)
+	}
+	for _, us := range d.UserStories {
+		if us.StoryPoints != nil {
+			s.StoryPoints += *us.StoryPoints
+		}
+	}
+	for _, o := range d.Objectives.OKRs {
+		s.KeyResults += len(okrKeyResults(o))
+	}
+	return s
+}
+
+// phaseScope counts the work scheduled in a phase.
+func (d *Document) phaseScope(p Phase) ScopeSize {
+	s := ScopeSize{Deliverables: len(p.Deliverables)}
+	for _, us := range d.UserStories {
+		if us.PhaseID == p.ID {
+			s.UserStories++
+			if us.StoryPoints != nil {
+				s.StoryPoints += *us.StoryPoints
+			}
+		}
+	}
+	for _, fr := range d.Requirements.Functional {
+		if fr.PhaseID == p.ID {
+			s.FunctionalRequirements++
+		}
+	}
+	for _, nfr := range d.Requirements.NonFunctional {
+		if nfr.PhaseID == p.ID {
+			s.NonFunctionalRequirements++
+		}
+	}
+	return s
+}
+
+// okrKeyResults returns the key results of an OKR from whichever of the
+// nested or objective-level lists is populated.
+func okrKeyResults(o OKR) []KeyResult {
+	if len(o.KeyResults) > 0 {
+		return o.KeyResults
+	}
+	return o.Objective.KeyResults
+}
+
+func phaseByID(phases []Phase, id string) *Phase {
+	for i := range phases {
+		if phases[i].ID == id {
+			return &phases[i]
+		}
+	}
+	return nil
+}
+
+// contributingDeliverables returns the deliverables of p that share a tag
+// with kr, or all of them if none do.
+func contributingDeliverables(kr KeyResult, p *Phase) []Deliverable {
+	if p == nil {
+		return nil
+	}
+	if len(kr.Tags) > 0 {
+		var tagged []Deliverable
+		for _, del := range p.Deliverables {
+			if hasAnyTag(del.Tags, kr.Tags) {
+				tagged = append(tagged, del)
+			}
+		}
+		if len(tagged) > 0 {
+			return tagged
+		}
+	}
+	return p.Deliverables
+}
+
+func hasDeliverable(p Phase, id string) bool {
+	for _, del := range p.Deliverables {
+		if del.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ToMarkdown renders the simulation as markdown comparison tables.
+func (s *ScopeSimulation) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*Dropping items tagged: %s*\n\n", strings.Join(s.Tags, ", ")))
+
+	sb.WriteString("## Scope Size\n\n")
+	sb.WriteString("| Measure | Before | After | Change |\n")
+	sb.WriteString("|---------|-------:|------:|-------:|\n")
+	for _, row := range []struct {
+		name          string
+		before, after int
+	}{
+		{"Phases", s.Before.Phases, s.After.Phases},
+		{"Deliverables", s.Before.Deliverables, s.After.Deliverables},
+		{"User stories", s.Before.UserStories, s.After.UserStories},
+		{"Story points", s.Before.StoryPoints, s.After.StoryPoints},
+		{"Functional requirements", s.Before.FunctionalRequirements, s.After.FunctionalRequirements},
+		{"Non-functional requirements", s.Before.NonFunctionalRequirements, s.After.NonFunctionalRequirements},
+		{"Key results", s.Before.KeyResults, s.After.KeyResults},
+	} {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %s |\n", row.name, row.before, row.after, formatDelta(row.after-row.before)))
+	}
+	sb.WriteString("\n")
+
+	if len(s.Phases) > 0 {
+		sb.WriteString("## Phases\n\n")
+		sb.WriteString("| Phase | Deliverables | User Stories | Story Points | Requirements |\n")
+		sb.WriteString("|-------|-------------:|-------------:|-------------:|-------------:|\n")
+		for _, p := range s.Phases {
+			name := p.Name
+			if p.Dropped {
+				name += " (dropped)"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", name,
+				beforeAfter(p.Before.Deliverables, p.After.Deliverables),
+				beforeAfter(p.Before.UserStories, p.After.UserStories),
+				beforeAfter(p.Before.StoryPoints, p.After.StoryPoints),
+				beforeAfter(p.Before.FunctionalRequirements+p.Before.NonFunctionalRequirements,
+					p.After.FunctionalRequirements+p.After.NonFunctionalRequirements)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Key Result Feasibility\n\n")
+	if len(s.KeyResults) == 0 {
+		sb.WriteString("*No key results are affected.*\n\n")
+	} else {
+		sb.WriteString("| Objective | Key Result | Phase | Target | Deliverables | Status |\n")
+		sb.WriteString("|-----------|------------|-------|--------|-------------:|--------|\n")
+		for _, kr := range s.KeyResults {
+			deliverables := "-"
+			if kr.Status != FeasibilityCut {
+				deliverables = beforeAfter(kr.Before, kr.After)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				kr.ObjectiveTitle, kr.KeyResultTitle, orDash(kr.PhaseID), orDash(kr.Target), deliverables, kr.Status))
+		}
+		sb.WriteString("\n")
+	}
+	if s.UnaffectedTargets > 0 {
+		sb.WriteString(fmt.Sprintf("%d other phase target(s) keep all of their contributing deliverables.\n\n", s.UnaffectedTargets))
+	}
+
+	sb.WriteString("## Score Impact\n\n")
+	sb.WriteString("| Measure | Before | After |\n")
+	sb.WriteString("|---------|-------:|------:|\n")
+	sb.WriteString(fmt.Sprintf("| Weighted score | %.2f | %.2f |\n", s.Score.Before, s.Score.After))
+	sb.WriteString(fmt.Sprintf("| Decision | %s | %s |\n", s.Score.DecisionBefore, s.Score.DecisionAfter))
+	sb.WriteString(fmt.Sprintf("| Completeness | %.0f%% | %.0f%% |\n", s.Score.CompletenessBefore, s.Score.CompletenessAfter))
+	for _, c := range s.Score.Categories {
+		sb.WriteString(fmt.Sprintf("| %s | %.1f | %.1f |\n", c.Category, c.Before, c.After))
+	}
+	sb.WriteString("\n")
+
+	if len(s.Dropped) > 0 {
+		sb.WriteString("## Dropped Items\n\n")
+		sb.WriteString("| Kind | ID | Title | Phase | Reason |\n")
+		sb.WriteString("|------|----|-------|-------|--------|\n")
+		for _, item := range s.Dropped {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				item.Kind, orDash(item.ID), item.Title, orDash(item.PhaseID), item.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func formatDelta(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+func beforeAfter(before, after int) string {
+	if before == after {
+		return fmt.Sprintf("%d", before)
+	}
+	return fmt.Sprintf("%d → %d", before, after)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,138 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func simulationDoc() Document {
+	points := func(n int) *int { return &n }
+	return Document{
+		UserStories: []UserStory{
+			{ID: "us1", Title: "Core", PhaseID: "p1", StoryPoints: points(5)},
+			{ID: "us2", Title: "Nice to have", PhaseID: "p1", StoryPoints: points(3), Tags: []string{"stretch"}},
+			{ID: "us3", Title: "Later", PhaseID: "p2", StoryPoints: points(8)},
+		},
+		Requirements: Requirements{
+			Functional: []FunctionalRequirement{
+				{ID: "fr1", Title: "Login", PhaseID: "p1"},
+				{ID: "fr2", Title: "Export", PhaseID: "p2"},
+			},
+			NonFunctional: []NonFunctionalRequirement{
+				{ID: "nfr1", Title: "Latency", PhaseID: "p1", Tags: []string{"stretch"}},
+			},
+		},
+		Roadmap: Roadmap{Phases: []Phase{
+			{ID: "p1", Name: "MVP", Deliverables: []Deliverable{
+				{ID: "d1", Title: "Auth", Tags: []string{"auth"}},
+				{ID: "d2", Title: "SSO", Tags: []string{"auth", "stretch"}},
+				{ID: "d3", Title: "Docs"},
+			}},
+			{ID: "p2", Name: "Scale", Tags: []string{"stretch"}, Deliverables: []Deliverable{
+				{ID: "d4", Title: "Sharding"},
+			}},
+		}},
+		Objectives: Objectives{OKRs: []OKR{{
+			Objective: Objective{Title: "Grow"},
+			KeyResults: []KeyResult{
+				{ID: "kr1", Title: "Logins", Tags: []string{"auth"}, PhaseTargets: []PhaseTarget{{PhaseID: "p1", Target: "1k"}}},
+				{ID: "kr2", Title: "Throughput", PhaseTargets: []PhaseTarget{{PhaseID: "p2", Target: "10k"}}},
+				{ID: "kr3", Title: "Docs visits", PhaseTargets: []PhaseTarget{{PhaseID: "p1", Target: "500"}}},
+				{ID: "kr4", Title: "Stretch KR", Tags: []string{"stretch"}},
+			},
+		}}},
+	}
+}
+
+func TestDropByTags(t *testing.T) {
+	doc := simulationDoc()
+	reduced := doc.DropByTags("stretch")
+
+	if len(reduced.Roadmap.Phases) != 1 || len(reduced.Roadmap.Phases[0].Deliverables) != 2 {
+		t.Fatalf("phases = %+v", reduced.Roadmap.Phases)
+	}
+	if len(reduced.UserStories) != 1 || reduced.UserStories[0].ID != "us1" {
+		t.Errorf("user stories = %+v", reduced.UserStories)
+	}
+	// fr2 is untagged but scheduled in the dropped phase.
+	if len(reduced.Requirements.Functional) != 1 || len(reduced.Requirements.NonFunctional) != 0 {
+		t.Errorf("requirements = %+v", reduced.Requirements)
+	}
+	if krs := reduced.Objectives.OKRs[0].KeyResults; len(krs) != 3 {
+		t.Errorf("key results = %d, want 3", len(krs))
+	}
+
+	// The original document is unchanged.
+	if len(doc.Roadmap.Phases[0].Deliverables) != 3 || len(doc.UserStories) != 3 {
+		t.Error("DropByTags modified the original document")
+	}
+	if got := doc.DropByTags(); len(got.UserStories) != 3 {
+		t.Error("expected no change without tags")
+	}
+}
+
+func TestSimulateDrop(t *testing.T) {
+	doc := simulationDoc()
+	sim := doc.SimulateDrop("stretch")
+
+	want := ScopeSize{Phases: 2, Deliverables: 4, UserStories: 3, StoryPoints: 16, FunctionalRequirements: 2, NonFunctionalRequirements: 1, KeyResults: 4}
+	if sim.Before != want {
+		t.Errorf("before = %+v, want %+v", sim.Before, want)
+	}
+	want = ScopeSize{Phases: 1, Deliverables: 2, UserStories: 1, StoryPoints: 5, FunctionalRequirements: 1, KeyResults: 3}
+	if sim.After != want {
+		t.Errorf("after = %+v, want %+v", sim.After, want)
+	}
+
+	if len(sim.Phases) != 2 || sim.Phases[0].After.StoryPoints != 5 || !sim.Phases[1].Dropped {
+		t.Errorf("phases = %+v", sim.Phases)
+	}
+
+	statuses := map[string]string{}
+	for _, kr := range sim.KeyResults {
+		statuses[kr.KeyResultID] = kr.Status
+	}
+	// kr1 counts only its auth-tagged deliverables (d1, d2); kr3 counts all
+	// of p1 (d1, d2, d3).
+	wantStatuses := map[string]string{
+		"kr1": FeasibilityReduced,
+		"kr2": FeasibilityUnsupported,
+		"kr3": FeasibilityReduced,
+		"kr4": FeasibilityCut,
+	}
+	for id, status := range wantStatuses {
+		if statuses[id] != status {
+			t.Errorf("%s status = %q, want %q", id, statuses[id], status)
+		}
+	}
+	for _, kr := range sim.KeyResults {
+		if kr.KeyResultID == "kr1" && (kr.Before != 2 || kr.After != 1) {
+			t.Errorf("kr1 deliverables = %d → %d, want 2 → 1", kr.Before, kr.After)
+		}
+	}
+
+	if len(sim.Dropped) != 8 {
+		t.Errorf("dropped = %d items, want 8: %+v", len(sim.Dropped), sim.Dropped)
+	}
+	if sim.Score.DecisionBefore == "" || sim.Score.DecisionAfter == "" {
+		t.Errorf("score = %+v", sim.Score)
+	}
+
+	md := sim.ToMarkdown()
+	for _, s := range []string{"## Scope Size", "| Story points | 16 | 5 | -11 |", "| Scale (dropped) |", "| Grow | Throughput | p2 | 10k | 1 → 0 | unsupported |", "## Dropped Items"} {
+		if !strings.Contains(md, s) {
+			t.Errorf("markdown missing %q", s)
+		}
+	}
+}
+
+func TestSimulateDropUnaffected(t *testing.T) {
+	doc := simulationDoc()
+	sim := doc.SimulateDrop("unused")
+	if len(sim.KeyResults) != 0 || sim.UnaffectedTargets != 3 || len(sim.Dropped) != 0 {
+		t.Errorf("sim = %+v", sim)
+	}
+	if sim.Before != sim.After {
+		t.Errorf("before %+v != after %+v", sim.Before, sim.After)
+	}
+}
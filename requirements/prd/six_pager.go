@@ -2,6 +2,7 @@ package prd
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
@@ -43,6 +44,7 @@ type Quote struct {
 	Speaker string `json:"speaker"`
 	Role    string `json:"role,omitempty"`
 	Text    string `json:"text"`
+	Source  string `json:"source,omitempty"` // Evidence or feedback quoted; empty when written from a template
 }
 
 // FAQSection contains anticipated questions and answers.
@@ -54,8 +56,9 @@ type FAQSection struct {
 
 // FAQ represents a question and answer pair.
 type FAQ struct {
-	Question string `json:"question"`
-	Answer   string `json:"answer"`
+	Question string   `json:"question"`
+	Answer   string   `json:"answer"`
+	Tags     []string `json:"tags,omitempty"` // From the authored FAQ, persona, or risk it comes from
 }
 
 // CustomerProblemSection describes the user pain points.
@@ -148,8 +151,47 @@ type RiskSnapshot struct {
 	Mitigation  string `json:"mitigation"`
 }
 
+// FAQ groups of a 6-pager.
+const (
+	FAQGroupCustomer  = "customer"
+	FAQGroupInternal  = "internal"
+	FAQGroupTechnical = "technical"
+)
+
+// FAQGroups returns the FAQ group names.
+func FAQGroups() []string {
+	return []string{FAQGroupCustomer, FAQGroupInternal, FAQGroupTechnical}
+}
+
+// SixPagerOptions controls which parts of a 6-pager are synthesized.
+type SixPagerOptions struct {
+	// FAQGroups selects the FAQ groups to synthesize, by the names in
+	// FAQGroups (nil = all, empty = none).
+	FAQGroups []string
+
+	// OmitQuotes leaves out the press release quotes, which are written
+	// from templates rather than said by the author or a customer.
+	OmitQuotes bool
+}
+
+// Validate returns an error if an FAQ group is unknown.
+func (o SixPagerOptions) Validate() error {
+	for _, g := range o.FAQGroups {
+		if !slices.Contains(FAQGroups(), g) {
+			return fmt.Errorf("unknown FAQ group %q (use %s)", g, strings.Join(FAQGroups(), ", "))
+		}
+	}
+	return nil
+}
+
 // GenerateSixPagerView creates an Amazon-style 6-pager view from a PRD.
 func GenerateSixPagerView(doc *Document) *SixPagerView {
+	return GenerateSixPagerViewWithOptions(doc, SixPagerOptions{})
+}
+
+// GenerateSixPagerViewWithOptions creates a 6-pager view from a PRD with
+// only the FAQ groups and quotes the options select.
+func GenerateSixPagerViewWithOptions(doc *Document, opts SixPagerOptions) *SixPagerView {
 	view := &SixPagerView{
 		Title:   doc.Metadata.Title,
 		Version: doc.Metadata.Version,
@@ -169,9 +211,32 @@ func GenerateSixPagerView(doc *Document) *SixPagerView {
 	view.SuccessMetrics = generateSuccessMetrics(doc)
 	view.Timeline = generateTimeline(doc)
 
+	view.FAQ.keepGroups(opts.FAQGroups)
+	if opts.OmitQuotes {
+		view.PressRelease.Quote = Quote{}
+		view.PressRelease.CustomerQuote = Quote{}
+	}
+
 	return view
 }
 
+// keepGroups removes the FAQ groups not named in groups. A nil groups
+// keeps them all.
+func (f *FAQSection) keepGroups(groups []string) {
+	if groups == nil {
+		return
+	}
+	if !slices.Contains(groups, FAQGroupCustomer) {
+		f.CustomerFAQs = nil
+	}
+	if !slices.Contains(groups, FAQGroupInternal) {
+		f.InternalFAQs = nil
+	}
+	if !slices.Contains(groups, FAQGroupTechnical) {
+		f.TechnicalFAQs = nil
+	}
+}
+
 func generatePressRelease(doc *Document) PressReleaseSection {
 	pr := PressReleaseSection{}
 
@@ -246,6 +311,19 @@ func generatePressRelease(doc *Document) PressReleaseSection {
 func generateFAQ(doc *Document) FAQSection {
 	faq := FAQSection{}
 
+	// Authored FAQs first
+	for _, entry := range doc.FAQs {
+		item := FAQ{Question: entry.Question, Answer: entry.Answer, Tags: entry.Tags}
+		switch entry.Group {
+		case FAQGroupInternal:
+			faq.InternalFAQs = append(faq.InternalFAQs, item)
+		case FAQGroupTechnical:
+			faq.TechnicalFAQs = append(faq.TechnicalFAQs, item)
+		default:
+			faq.CustomerFAQs = append(faq.CustomerFAQs, item)
+		}
+	}
+
 	// Customer FAQs from personas and user stories
 	if len(doc.Personas) > 0 {
 		for _, persona := range doc.Personas {
@@ -253,6 +331,7 @@ func generateFAQ(doc *Document) FAQSection {
 				faq.CustomerFAQs = append(faq.CustomerFAQs, FAQ{
 					Question: fmt.Sprintf("How does this help someone in a %s role?", persona.Role),
 					Answer:   fmt.Sprintf("For %s, this solution addresses: %s", persona.Role, strings.Join(persona.PainPoints, ", ")),
+					Tags:     persona.Tags,
 				})
 			}
 		}
@@ -309,6 +388,7 @@ func generateFAQ(doc *Document) FAQSection {
 			faq.InternalFAQs = append(faq.InternalFAQs, FAQ{
 				Question: fmt.Sprintf("What if %s?", summarizeSentence(risk.Description, 50)),
 				Answer:   fmt.Sprintf("We've identified this risk and plan to mitigate it by: %s", risk.Mitigation),
+				Tags:     risk.Tags,
 			})
 		}
 	}
@@ -528,6 +608,11 @@ func summarizeSentence(s string, maxLen int) string {
 // RenderSixPagerMarkdown generates markdown output for the 6-pager view.
 func RenderSixPagerMarkdown(view *SixPagerView) string {
 	var sb strings.Builder
+	number := 0
+	section := func(title string) {
+		number++
+		sb.WriteString(fmt.Sprintf("## %d. %s\n\n", number, title))
+	}
 
 	// Title page
 	sb.WriteString(fmt.Sprintf("# %s\n\n", view.Title))
@@ -535,7 +620,7 @@ func RenderSixPagerMarkdown(view *SixPagerView) string {
 	sb.WriteString("---\n\n")
 
 	// Section 1: Press Release
-	sb.WriteString("## 1. Press Release\n\n")
+	section("Press Release")
 	sb.WriteString(fmt.Sprintf("### %s\n\n", view.PressRelease.Headline))
 	if view.PressRelease.Subheadline != "" {
 		sb.WriteString(fmt.Sprintf("*%s*\n\n", view.PressRelease.Subheadline))
@@ -576,37 +661,17 @@ func RenderSixPagerMarkdown(view *SixPagerView) string {
 
 	sb.WriteString("---\n\n")
 
-	// Section 2: FAQ
-	sb.WriteString("## 2. Frequently Asked Questions\n\n")
-
-	if len(view.FAQ.CustomerFAQs) > 0 {
-		sb.WriteString("### Customer Questions\n\n")
-		for _, faq := range view.FAQ.CustomerFAQs {
-			sb.WriteString(fmt.Sprintf("**Q: %s**\n\n", faq.Question))
-			sb.WriteString(fmt.Sprintf("A: %s\n\n", faq.Answer))
-		}
-	}
-
-	if len(view.FAQ.InternalFAQs) > 0 {
-		sb.WriteString("### Internal Questions\n\n")
-		for _, faq := range view.FAQ.InternalFAQs {
-			sb.WriteString(fmt.Sprintf("**Q: %s**\n\n", faq.Question))
-			sb.WriteString(fmt.Sprintf("A: %s\n\n", faq.Answer))
-		}
+	// Section 2: FAQ, left out when no questions were synthesized
+	if faq := view.FAQ; len(faq.CustomerFAQs)+len(faq.InternalFAQs)+len(faq.TechnicalFAQs) > 0 {
+		section("Frequently Asked Questions")
+		writeFAQs(&sb, "Customer Questions", faq.CustomerFAQs)
+		writeFAQs(&sb, "Internal Questions", faq.InternalFAQs)
+		writeFAQs(&sb, "Technical Questions", faq.TechnicalFAQs)
+		sb.WriteString("---\n\n")
 	}
 
-	if len(view.FAQ.TechnicalFAQs) > 0 {
-		sb.WriteString("### Technical Questions\n\n")
-		for _, faq := range view.FAQ.TechnicalFAQs {
-			sb.WriteString(fmt.Sprintf("**Q: %s**\n\n", faq.Question))
-			sb.WriteString(fmt.Sprintf("A: %s\n\n", faq.Answer))
-		}
-	}
-
-	sb.WriteString("---\n\n")
-
 	// Section 3: Customer Problem
-	sb.WriteString("## 3. Customer Problem\n\n")
+	section("Customer Problem")
 
 	sb.WriteString("### Problem Statement\n\n")
 	sb.WriteString(view.CustomerProblem.Statement + "\n\n")
@@ -655,7 +720,7 @@ func RenderSixPagerMarkdown(view *SixPagerView) string {
 	sb.WriteString("---\n\n")
 
 	// Section 4: Solution
-	sb.WriteString("## 4. Solution\n\n")
+	section("Solution")
 
 	sb.WriteString("### Overview\n\n")
 	sb.WriteString(view.Solution.Overview + "\n\n")
@@ -702,7 +767,7 @@ func RenderSixPagerMarkdown(view *SixPagerView) string {
 	sb.WriteString("---\n\n")
 
 	// Section 5: Success Metrics
-	sb.WriteString("## 5. Success Metrics\n\n")
+	section("Success Metrics")
 
 	if view.SuccessMetrics.PrimaryMetric.Name != "" {
 		sb.WriteString("### Primary Metric\n\n")
@@ -737,7 +802,7 @@ func RenderSixPagerMarkdown(view *SixPagerView) string {
 	sb.WriteString("---\n\n")
 
 	// Section 6: Timeline & Resources
-	sb.WriteString("## 6. Timeline & Resources\n\n")
+	section("Timeline & Resources")
 
 	if len(view.Timeline.Phases) > 0 {
 		sb.WriteString("### Phases\n\n")
@@ -784,3 +849,14 @@ func RenderSixPagerMarkdown(view *SixPagerView) string {
 
 	return sb.String()
 }
+
+func writeFAQs(sb *strings.Builder, heading string, faqs []FAQ) {
+	if len(faqs) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("### %s\n\n", heading))
+	for _, faq := range faqs {
+		sb.WriteString(fmt.Sprintf("**Q: %s**\n\n", faq.Question))
+		sb.WriteString(fmt.Sprintf("A: %s\n\n", faq.Answer))
+	}
+}
@@ -276,6 +276,47 @@ func TestRenderSixPagerMarkdown(t *testing.T) {
 	}
 }
 
+func TestGenerateSixPagerViewWithOptions(t *testing.T) {
+	doc := &Document{
+		Metadata: Metadata{ID: "PRD-004", Title: "Options Test", Authors: []Person{{Name: "Jane Smith"}}},
+		ExecutiveSummary: ExecutiveSummary{
+			ProblemStatement: "Test problem statement",
+			ProposedSolution: "Test solution description",
+		},
+		Personas:   []Persona{{ID: "P-1", Name: "Test User", Role: "Developer", IsPrimary: true, Goals: []string{"Ship faster"}}},
+		OutOfScope: []string{"Mobile app"},
+		TechArchitecture: &TechnicalArchitecture{
+			Overview: "A queue-backed service",
+		},
+	}
+
+	full := GenerateSixPagerView(doc)
+	if len(full.FAQ.CustomerFAQs) == 0 || len(full.FAQ.InternalFAQs) == 0 || len(full.FAQ.TechnicalFAQs) == 0 {
+		t.Fatalf("default view should have all FAQ groups: %+v", full.FAQ)
+	}
+	if full.PressRelease.Quote.Text == "" || full.PressRelease.CustomerQuote.Text == "" {
+		t.Fatal("default view should have quotes")
+	}
+
+	view := GenerateSixPagerViewWithOptions(doc, SixPagerOptions{FAQGroups: []string{FAQGroupCustomer}, OmitQuotes: true})
+	if len(view.FAQ.CustomerFAQs) == 0 || view.FAQ.InternalFAQs != nil || view.FAQ.TechnicalFAQs != nil {
+		t.Errorf("only customer FAQs expected: %+v", view.FAQ)
+	}
+	if view.PressRelease.Quote.Text != "" || view.PressRelease.CustomerQuote.Text != "" {
+		t.Error("quotes should be omitted")
+	}
+
+	// No FAQ groups leaves out the FAQ section and renumbers the rest.
+	markdown := RenderSixPagerMarkdown(GenerateSixPagerViewWithOptions(doc, SixPagerOptions{FAQGroups: []string{}}))
+	if strings.Contains(markdown, "Frequently Asked Questions") || !strings.Contains(markdown, "## 2. Customer Problem") {
+		t.Error("FAQ section should be left out and the sections renumbered")
+	}
+
+	if err := (SixPagerOptions{FAQGroups: []string{"partner"}}).Validate(); err == nil {
+		t.Error("unknown FAQ group should be an error")
+	}
+}
+
 func TestSummarizeSentence(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -29,6 +29,9 @@ type UserStory struct {
 	Epic               string                `json:"epic,omitempty"`         // Parent epic
 	Tags               []string              `json:"tags,omitempty"`         // For filtering by topic/domain
 	Notes              string                `json:"notes,omitempty"`
+
+	// ExternalRefs links this story to other systems, e.g. its Jira issue.
+	ExternalRefs ExternalRefs `json:"externalRefs,omitempty"`
 }
 
 // Story returns the full user story string in standard format.
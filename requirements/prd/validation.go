@@ -3,6 +3,11 @@ package prd
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
 )
 
 // tagPattern matches valid kebab-case tags:
@@ -117,6 +122,38 @@ func Validate(doc *Document) *ValidationResult {
 	// Validate tags
 	result.validateTags(doc)
 
+	// Validate authored FAQs
+	result.validateFAQs(doc)
+
+	// Validate accessibility coverage
+	result.validateAccessibility(doc)
+
+	// Validate roadmap schedule
+	result.validateSchedule(doc)
+
+	// Validate external dependencies
+	result.validateDependencies(doc)
+
+	// Validate resourcing plan
+	result.validateResourcing(doc)
+
+	// Validate appendix references and in-document links
+	for _, issue := range doc.ValidateLinks() {
+		field := issue.Field
+		if field == "" {
+			field = "markdown"
+		}
+		result.addError(field, issue.Message)
+	}
+
+	// Validate reviewer sign-off and lifecycle requirements
+	for _, problem := range common.ValidateReviews(doc.Metadata.Status, doc.Metadata.Reviews, doc.Metadata.RequiredReviewRoles) {
+		result.addError("metadata.reviews", problem)
+	}
+	for _, problem := range common.ValidateLifecycle(doc.Metadata.Lifecycle()) {
+		result.addError("metadata.status", problem)
+	}
+
 	return result
 }
 
@@ -176,6 +213,13 @@ func (r *ValidationResult) validateIDs(doc *Document) {
 		checkID(phase.ID, fmt.Sprintf("roadmap.phases[%d].id", i))
 	}
 
+	// Check dependency IDs
+	if doc.Assumptions != nil {
+		for i, dep := range doc.Assumptions.Dependencies {
+			checkID(dep.ID, fmt.Sprintf("assumptions.dependencies[%d].id", i))
+		}
+	}
+
 	// Check problem ID (if present)
 	if doc.Problem != nil && doc.Problem.ID != "" {
 		checkID(doc.Problem.ID, "problem.id")
@@ -195,6 +239,19 @@ func (r *ValidationResult) validateIDs(doc *Document) {
 		}
 	}
 
+	// Check journey map IDs
+	for i, j := range doc.JourneyMaps() {
+		checkID(j.ID, fmt.Sprintf("ux_requirements.journey_maps[%d].id", i))
+	}
+
+	// Check wireframe and interaction flow IDs
+	for i, w := range doc.Wireframes() {
+		checkID(w.ID, fmt.Sprintf("ux_requirements.wireframes[%d].id", i))
+	}
+	for i, f := range doc.InteractionFlows() {
+		checkID(f.ID, fmt.Sprintf("ux_requirements.interaction_flows[%d].id", i))
+	}
+
 	// Check decision IDs
 	if doc.Decisions != nil {
 		for i, d := range doc.Decisions.Records {
@@ -261,6 +318,46 @@ func (r *ValidationResult) validateTraceability(doc *Document) {
 		}
 	}
 
+	// Check journey map references to personas
+	for i, j := range doc.JourneyMaps() {
+		if j.PersonaID != "" && !definedIDs[j.PersonaID] {
+			r.addWarning(
+				fmt.Sprintf("ux_requirements.journey_maps[%d].persona_id", i),
+				fmt.Sprintf("Reference to undefined persona: %s", j.PersonaID),
+			)
+		}
+		for k, stage := range j.Stages {
+			if stage.Satisfaction < 0 || stage.Satisfaction > 5 {
+				r.addWarning(
+					fmt.Sprintf("ux_requirements.journey_maps[%d].stages[%d].satisfaction", i, k),
+					fmt.Sprintf("Satisfaction %d is outside 1-5", stage.Satisfaction),
+				)
+			}
+		}
+	}
+
+	// Check wireframe references to interaction flows
+	flows := make(map[string]bool)
+	for _, f := range doc.InteractionFlows() {
+		flows[f.ID] = true
+	}
+	for i, w := range doc.Wireframes() {
+		if strings.TrimSpace(w.URL) == "" {
+			r.addWarning(
+				fmt.Sprintf("ux_requirements.wireframes[%d].url", i),
+				"Wireframe has no image or link",
+			)
+		}
+		for _, id := range w.Flows {
+			if !flows[id] {
+				r.addWarning(
+					fmt.Sprintf("ux_requirements.wireframes[%d].flows", i),
+					fmt.Sprintf("Reference to undefined interaction flow: %s", id),
+				)
+			}
+		}
+	}
+
 	// Check functional requirement references
 	for i, req := range doc.Requirements.Functional {
 		for _, storyID := range req.UserStoryIDs {
@@ -364,4 +461,144 @@ func (r *ValidationResult) validateTags(doc *Document) {
 	for i, risk := range doc.Risks {
 		checkTags(risk.Tags, fmt.Sprintf("risks[%d].tags", i))
 	}
+
+	// FAQ tags
+	for i, faq := range doc.FAQs {
+		checkTags(faq.Tags, fmt.Sprintf("faqs[%d].tags", i))
+	}
+}
+
+// validateFAQs checks that authored FAQs have a question, an answer, and a
+// known group.
+func (r *ValidationResult) validateFAQs(doc *Document) {
+	for i, faq := range doc.FAQs {
+		location := fmt.Sprintf("faqs[%d]", i)
+		if faq.Question == "" || faq.Answer == "" {
+			r.addError(location, "FAQ needs a question and an answer")
+		}
+		if faq.Group != "" && !slices.Contains(FAQGroups(), faq.Group) {
+			r.addError(location+".group", fmt.Sprintf("Unknown FAQ group '%s' (use %s)", faq.Group, strings.Join(FAQGroups(), ", ")))
+		}
+	}
+}
+
+// validateAccessibility checks that a declared accessibility standard is
+// reflected in NFRs, acceptance criteria, and user-facing requirements.
+func (r *ValidationResult) validateAccessibility(doc *Document) {
+	report := CheckAccessibility(doc)
+	if report == nil {
+		return
+	}
+
+	if len(report.NFRIDs) == 0 {
+		r.addWarning("ux_requirements.accessibility",
+			fmt.Sprintf("%s declared but no accessibility non-functional requirement defined", report.Standard))
+	}
+	if len(report.CriteriaIDs) == 0 {
+		r.addWarning("ux_requirements.accessibility",
+			fmt.Sprintf("%s declared but no acceptance criteria address accessibility", report.Standard))
+	}
+
+	uncovered := make(map[string]bool, len(report.UncoveredFRIDs))
+	for _, id := range report.UncoveredFRIDs {
+		uncovered[id] = true
+	}
+	for i, fr := range doc.Requirements.Functional {
+		if uncovered[fr.ID] {
+			r.addWarning(
+				fmt.Sprintf("requirements.functional[%d]", i),
+				fmt.Sprintf("User-facing requirement %s has no accessibility consideration", fr.ID),
+			)
+		}
+	}
+}
+
+// validateSchedule checks roadmap phase dates against dependencies and
+// swimlane overlaps, and that OKR phase targets fall within their phase.
+func (r *ValidationResult) validateSchedule(doc *Document) {
+	for _, issue := range doc.Roadmap.ValidateSchedule() {
+		r.addWarning("roadmap."+issue.Field, issue.Message)
+	}
+
+	phases := make(map[string]*Phase, len(doc.Roadmap.Phases))
+	for i := range doc.Roadmap.Phases {
+		phases[doc.Roadmap.Phases[i].ID] = &doc.Roadmap.Phases[i]
+	}
+	for i, o := range doc.Objectives.OKRs {
+		for j, kr := range o.KeyResults {
+			for k, pt := range kr.PhaseTargets {
+				field := fmt.Sprintf("objectives.okrs[%d].key_results[%d].phase_targets[%d]", i, j, k)
+				phase, ok := phases[pt.PhaseID]
+				if !ok {
+					r.addWarning(field, fmt.Sprintf("Reference to undefined phase: %s", pt.PhaseID))
+					continue
+				}
+				if pt.DueDate == "" {
+					continue
+				}
+				due, err := parseDate(pt.DueDate)
+				if err != nil {
+					r.addWarning(field+".due_date", fmt.Sprintf("Invalid date %q: expected YYYY-MM-DD", pt.DueDate))
+					continue
+				}
+				if !phase.Contains(due) {
+					r.addWarning(field+".due_date", fmt.Sprintf("Target due %s falls outside phase %s (%s)",
+						pt.DueDate, phase.ID, phaseWindow(phase)))
+				}
+			}
+		}
+	}
+}
+
+// validateDependencies checks external dependencies for an owning team and
+// against the roadmap phases that need them.
+func (r *ValidationResult) validateDependencies(doc *Document) {
+	if doc.Assumptions == nil {
+		return
+	}
+	for i, dep := range doc.Assumptions.Dependencies {
+		if dep.Team == "" && dep.Owner == "" {
+			r.addWarning(fmt.Sprintf("assumptions.dependencies[%d]", i),
+				fmt.Sprintf("Dependency %s has no owning team", dep.ID))
+		}
+	}
+	for _, issue := range doc.Roadmap.ValidateDependencies(doc.Assumptions.Dependencies) {
+		r.addWarning("assumptions."+issue.Field, issue.Message)
+	}
+}
+
+// validateResourcing checks the headcount plan against roadmap phases. It is
+// skipped when no resourcing is declared, so that PRDs without a staffing
+// plan are not warned about unstaffed phases.
+func (r *ValidationResult) validateResourcing(doc *Document) {
+	if len(doc.Resourcing) == 0 {
+		return
+	}
+	for _, issue := range doc.Roadmap.ValidateResourcing(doc.Resourcing) {
+		field := issue.Field
+		if strings.HasPrefix(field, "phases[") {
+			field = "roadmap." + field
+		}
+		r.addWarning(field, issue.Message)
+	}
+}
+
+// parseDate parses an ISO 8601 date or RFC 3339 timestamp.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// phaseWindow formats a phase's dates, with "…" for a missing bound.
+func phaseWindow(p *Phase) string {
+	start, end := "…", "…"
+	if p.StartDate != nil {
+		start = p.StartDate.Format("2006-01-02")
+	}
+	if p.EndDate != nil {
+		end = p.EndDate.Format("2006-01-02")
+	}
+	return start + " to " + end
 }
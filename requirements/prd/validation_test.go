@@ -2,6 +2,7 @@ package prd
 
 import (
 	"testing"
+	"time"
 )
 
 func TestValidateTag(t *testing.T) {
@@ -66,3 +67,126 @@ func TestValidateTags(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSchedulePhaseTargets(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	doc := &Document{
+		Roadmap: Roadmap{Phases: []Phase{{ID: "p1", StartDate: &start, EndDate: &end}}},
+		Objectives: Objectives{OKRs: []OKR{{
+			KeyResults: []KeyResult{{
+				PhaseTargets: []PhaseTarget{
+					{PhaseID: "p1", DueDate: "2026-03-31"},
+					{PhaseID: "p1", DueDate: "2026-05-15"},
+					{PhaseID: "p1", DueDate: "Q2"},
+					{PhaseID: "p9"},
+				},
+			}},
+		}}},
+	}
+
+	result := &ValidationResult{Valid: true}
+	result.validateSchedule(doc)
+
+	want := map[string]string{
+		"objectives.okrs[0].key_results[0].phase_targets[1].due_date": "Target due 2026-05-15 falls outside phase p1 (2026-01-01 to 2026-03-31)",
+		"objectives.okrs[0].key_results[0].phase_targets[2].due_date": `Invalid date "Q2": expected YYYY-MM-DD`,
+		"objectives.okrs[0].key_results[0].phase_targets[3]":          "Reference to undefined phase: p9",
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("warnings = %+v", result.Warnings)
+	}
+	for _, w := range result.Warnings {
+		if want[w.Field] != w.Message {
+			t.Errorf("warning %s = %q, want %q", w.Field, w.Message, want[w.Field])
+		}
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	start := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	doc := &Document{
+		Roadmap: Roadmap{Phases: []Phase{{ID: "p2", StartDate: &start}}},
+		Assumptions: &AssumptionsConstraints{Dependencies: []Dependency{
+			{ID: "D1", Team: "Payments", NeededBy: "p2", DueDate: "2026-05-01"},
+			{ID: "D2", Name: "Vendor API"},
+		}},
+	}
+
+	result := &ValidationResult{Valid: true}
+	result.validateDependencies(doc)
+
+	want := map[string]string{
+		"assumptions.dependencies[1]":         "Dependency D2 has no owning team",
+		"assumptions.dependencies[0].dueDate": "Dependency D1 is due 2026-05-01, after phase p2 starts 2026-04-01",
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("warnings = %+v", result.Warnings)
+	}
+	for _, w := range result.Warnings {
+		if want[w.Field] != w.Message {
+			t.Errorf("warning %s = %q, want %q", w.Field, w.Message, want[w.Field])
+		}
+	}
+}
+
+func TestValidateResourcing(t *testing.T) {
+	doc := &Document{
+		Roadmap: Roadmap{Phases: []Phase{
+			{ID: "p1", Deliverables: []Deliverable{{ID: "d1"}}},
+			{ID: "p2", Deliverables: []Deliverable{{ID: "d2"}}},
+		}},
+	}
+
+	// No resourcing plan: unstaffed phases are not reported.
+	result := &ValidationResult{Valid: true}
+	result.validateResourcing(doc)
+	if len(result.Warnings) != 0 {
+		t.Fatalf("warnings without resourcing = %+v", result.Warnings)
+	}
+
+	doc.Resourcing = []Allocation{
+		{Role: "Frontend Developer", Count: 2, PhaseID: "p1"},
+		{Role: "Engineering Manager", Function: "product", Count: 1, PhaseID: "p2"},
+		{Role: "Designer", Count: 1, PhaseID: "p3"},
+	}
+	result = &ValidationResult{Valid: true}
+	result.validateResourcing(doc)
+
+	want := map[string]string{
+		"resourcing[2].phaseId": "Reference to undefined phase: p3",
+		"roadmap.phases[1]":     "Phase p2 has 1 deliverables but no engineering roles allocated",
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("warnings = %+v", result.Warnings)
+	}
+	for _, w := range result.Warnings {
+		if want[w.Field] != w.Message {
+			t.Errorf("warning %s = %q, want %q", w.Field, w.Message, want[w.Field])
+		}
+	}
+}
+
+func TestValidateFAQs(t *testing.T) {
+	doc := &Document{FAQs: []FAQEntry{
+		{Question: "Is there an API?", Answer: "Yes."},
+		{Group: FAQGroupTechnical, Question: "Which regions?"},
+		{Group: "press", Question: "When?", Answer: "Soon."},
+	}}
+
+	result := &ValidationResult{Valid: true}
+	result.validateFAQs(doc)
+
+	want := map[string]string{
+		"faqs[1]":       "FAQ needs a question and an answer",
+		"faqs[2].group": "Unknown FAQ group 'press' (use customer, internal, technical)",
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("errors = %+v", result.Errors)
+	}
+	for _, e := range result.Errors {
+		if want[e.Field] != e.Message {
+			t.Errorf("error %s = %q, want %q", e.Field, e.Message, want[e.Field])
+		}
+	}
+}
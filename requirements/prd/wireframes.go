@@ -0,0 +1,136 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/assets"
+)
+
+// galleryColumns is the number of wireframe thumbnails per gallery row.
+const galleryColumns = 3
+
+// Wireframes returns the wireframes of the UX requirements.
+func (d *Document) Wireframes() []Wireframe {
+	if d.UXRequirements == nil {
+		return nil
+	}
+	return d.UXRequirements.Wireframes
+}
+
+// InteractionFlows returns the interaction flows of the UX requirements.
+func (d *Document) InteractionFlows() []InteractionFlow {
+	if d.UXRequirements == nil {
+		return nil
+	}
+	return d.UXRequirements.InteractionFlows
+}
+
+// FlowAnchor returns the heading ID of the interaction flow with the given
+// ID in generated markdown.
+func FlowAnchor(id string) string {
+	return "flow-" + anchorSlug(id)
+}
+
+func (d *Document) generateWireframes() string {
+	flows := make(map[string]bool)
+	for _, f := range d.InteractionFlows() {
+		flows[f.ID] = true
+	}
+
+	var images, others []Wireframe
+	for _, w := range d.Wireframes() {
+		if assets.IsImage(w.URL) {
+			images = append(images, w)
+		} else {
+			others = append(others, w)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Wireframes and Flows\n\n")
+
+	if len(images) > 0 {
+		sb.WriteString(strings.Repeat("| ", galleryColumns) + "|\n")
+		sb.WriteString(strings.Repeat("|---", galleryColumns) + "|\n")
+		for i := 0; i < len(images); i += galleryColumns {
+			cells := make([]string, galleryColumns)
+			for j := 0; j < galleryColumns && i+j < len(images); j++ {
+				cells[j] = wireframeCell(images[i+j], flows)
+			}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, w := range others {
+		line := "- " + wireframeTitle(w)
+		if w.URL != "" {
+			line = "- " + assets.Markdown(wireframeTitle(w), w.URL)
+		}
+		if w.Description != "" {
+			line += ": " + w.Description
+		}
+		if links := flowLinks(w.Flows, flows); links != "" {
+			line += " (" + links + ")"
+		}
+		sb.WriteString(line + "\n")
+	}
+	if len(others) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, f := range d.InteractionFlows() {
+		sb.WriteString(fmt.Sprintf("### %s: %s {#%s}\n\n", f.ID, f.Title, FlowAnchor(f.ID)))
+		if f.Description != "" {
+			sb.WriteString(f.Description + "\n\n")
+		}
+		for i, step := range f.Steps {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+		}
+		if len(f.Steps) > 0 {
+			sb.WriteString("\n")
+		}
+		if f.DiagramURL != "" {
+			sb.WriteString(assets.Markdown(f.Title+" diagram", f.DiagramURL) + "\n\n")
+		}
+	}
+
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
+
+// wireframeCell is a gallery cell: the thumbnail linked to the full image,
+// then the title, description, status, and flows.
+func wireframeCell(w Wireframe, flows map[string]bool) string {
+	title := wireframeTitle(w)
+	parts := []string{fmt.Sprintf("[![%s](%s)](%s)", title, w.URL, w.URL), "**" + title + "**"}
+	if w.Description != "" {
+		parts = append(parts, w.Description)
+	}
+	if w.Status != "" {
+		parts = append(parts, "_"+w.Status+"_")
+	}
+	if links := flowLinks(w.Flows, flows); links != "" {
+		parts = append(parts, "Flows: "+links)
+	}
+	return strings.ReplaceAll(strings.Join(parts, "<br>"), "|", "\\|")
+}
+
+func wireframeTitle(w Wireframe) string {
+	return strings.TrimSpace(w.ID + " " + w.Title)
+}
+
+// flowLinks links the IDs of defined flows to their headings; others are
+// listed unlinked.
+func flowLinks(ids []string, flows map[string]bool) string {
+	links := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if flows[id] {
+			links = append(links, fmt.Sprintf("[%s](#%s)", id, FlowAnchor(id)))
+		} else {
+			links = append(links, id)
+		}
+	}
+	return strings.Join(links, ", ")
+}
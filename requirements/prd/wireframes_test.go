@@ -0,0 +1,60 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func wireframeDocument() Document {
+	return Document{
+		UXRequirements: &UXRequirements{
+			Wireframes: []Wireframe{
+				{ID: "WF-1", Title: "Home", URL: "img/home.png", Description: "Landing | hero", Status: "Approved", Flows: []string{"IF-1"}},
+				{ID: "WF-2", Title: "Checkout", URL: "img/checkout.svg", Flows: []string{"IF-1", "IF-9"}},
+				{ID: "WF-3", Title: "Board", URL: "https://figma.com/file/abc"},
+				{ID: "WF-4", Title: "Empty"},
+			},
+			InteractionFlows: []InteractionFlow{{ID: "IF-1", Title: "Purchase", Steps: []string{"Open", "Pay"}}},
+		},
+	}
+}
+
+func TestGenerateWireframes(t *testing.T) {
+	doc := wireframeDocument()
+	md := doc.ToMarkdown(DefaultMarkdownOptions())
+	for _, want := range []string{
+		"[Wireframes and Flows](#sec-wireframes-and-flows)",
+		"## Wireframes and Flows {#sec-wireframes-and-flows}",
+		"| | | |\n|---|---|---|\n",
+		"| [![WF-1 Home](img/home.png)](img/home.png)<br>**WF-1 Home**<br>Landing \\| hero<br>_Approved_<br>Flows: [IF-1](#flow-if-1) | " +
+			"[![WF-2 Checkout](img/checkout.svg)](img/checkout.svg)<br>**WF-2 Checkout**<br>Flows: [IF-1](#flow-if-1), IF-9 |  |\n",
+		"- [WF-3 Board](https://figma.com/file/abc)\n- WF-4 Empty\n",
+		"### IF-1: Purchase {#flow-if-1}\n\n1. Open\n2. Pay\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q", want)
+		}
+	}
+	if broken := BrokenAnchors(md); len(broken) > 0 {
+		t.Errorf("broken anchors: %v", broken)
+	}
+}
+
+func TestValidateWireframes(t *testing.T) {
+	doc := wireframeDocument()
+	result := Validate(&doc)
+	want := map[string]bool{
+		"ux_requirements.wireframes[1].flows": false,
+		"ux_requirements.wireframes[3].url":   false,
+	}
+	for _, w := range result.Warnings {
+		if _, ok := want[w.Field]; ok {
+			want[w.Field] = true
+		}
+	}
+	for field, found := range want {
+		if !found {
+			t.Errorf("warnings = %+v, want %s", result.Warnings, field)
+		}
+	}
+}
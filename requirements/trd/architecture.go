@@ -0,0 +1,135 @@
+package trd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MermaidComponents returns a C4-style Mermaid flowchart of the components
+// inside a box named system, with an arrow from each component to the
+// components it depends on and a labeled arrow for each data flow between
+// components. Dependencies that are not components of the document, such
+// as outside services, and the integrations are drawn outside the box; an
+// inbound integration points at the box and an outbound one away from it.
+// Components are shaped by type: databases and stores as cylinders, queues
+// and streams as hexagons, and libraries as subroutines. If there are no
+// components, MermaidComponents returns "".
+func (a *Architecture) MermaidComponents(system string, integrations []Integration) string {
+	if len(a.Components) == 0 {
+		return ""
+	}
+	ids := make(map[string]string, len(a.Components))
+	for _, c := range a.Components {
+		ids[c.ID] = erdName(c.ID, c.Name)
+	}
+	if system == "" {
+		system = "System"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	sb.WriteString(fmt.Sprintf("    subgraph system[%s]\n", flowchartLabel(system)))
+	for _, c := range a.Components {
+		sb.WriteString("        c_" + ids[c.ID] + componentShape(c.Type, componentLabel(c.Name, c.ID, c.Type, c.Technology)) + "\n")
+	}
+	sb.WriteString("    end\n")
+
+	external := map[string]bool{}
+	var edges []string
+	flows := map[[2]string]bool{}
+	for _, f := range a.DataFlows {
+		from, okFrom := ids[f.Source]
+		to, okTo := ids[f.Destination]
+		if !okFrom || !okTo {
+			continue
+		}
+		flows[[2]string{f.Source, f.Destination}] = true
+		label := f.Name
+		if f.Protocol != "" {
+			if label != "" {
+				label += " "
+			}
+			label += "[" + f.Protocol + "]"
+		}
+		if label == "" {
+			edges = append(edges, fmt.Sprintf("    c_%s --> c_%s\n", from, to))
+		} else {
+			edges = append(edges, fmt.Sprintf("    c_%s -->|%s| c_%s\n", from, flowchartLabel(label), to))
+		}
+	}
+	for _, c := range a.Components {
+		for _, dep := range c.Dependencies {
+			if flows[[2]string{c.ID, dep}] {
+				continue
+			}
+			if to, ok := ids[dep]; ok {
+				edges = append(edges, fmt.Sprintf("    c_%s --> c_%s\n", ids[c.ID], to))
+				continue
+			}
+			node := "x_" + erdName(dep, "external")
+			if !external[node] {
+				external[node] = true
+				sb.WriteString(fmt.Sprintf("    %s([%s])\n", node, flowchartLabel(dep)))
+			}
+			edges = append(edges, fmt.Sprintf("    c_%s --> %s\n", ids[c.ID], node))
+		}
+	}
+	for i, in := range integrations {
+		id := in.ID
+		if id == "" {
+			id = fmt.Sprint(i + 1)
+		}
+		node := "i_" + erdName(id, "integration")
+		sb.WriteString(fmt.Sprintf("    %s([%s])\n", node, flowchartLabel(componentLabel(in.Name, in.ID, in.Type, in.Protocol))))
+		switch strings.ToLower(in.Direction) {
+		case "inbound":
+			edges = append(edges, fmt.Sprintf("    %s --> system\n", node))
+		case "bidirectional":
+			edges = append(edges, fmt.Sprintf("    system <--> %s\n", node))
+		default:
+			edges = append(edges, fmt.Sprintf("    system --> %s\n", node))
+		}
+	}
+	sb.WriteString(strings.Join(edges, ""))
+	return sb.String()
+}
+
+// componentLabel returns a node label of the name, or the ID if there is no
+// name, over the type and technology in brackets, as in C4 diagrams.
+func componentLabel(name, id, kind, tech string) string {
+	if name == "" {
+		name = id
+	}
+	var details []string
+	for _, s := range []string{kind, tech} {
+		if s != "" {
+			details = append(details, s)
+		}
+	}
+	if len(details) == 0 {
+		return name
+	}
+	return name + "<br/>[" + strings.Join(details, ": ") + "]"
+}
+
+// componentShape returns the node shape for a component type, around the
+// quoted label.
+func componentShape(kind, label string) string {
+	label = flowchartLabel(label)
+	k := strings.ToLower(kind)
+	switch {
+	case strings.Contains(k, "database") || strings.Contains(k, "store") || strings.Contains(k, "cache") || k == "db":
+		return "[(" + label + ")]"
+	case strings.Contains(k, "queue") || strings.Contains(k, "stream") || strings.Contains(k, "topic") || strings.Contains(k, "bus"):
+		return "{{" + label + "}}"
+	case strings.Contains(k, "library") || strings.Contains(k, "sdk"):
+		return "[[" + label + "]]"
+	}
+	return "[" + label + "]"
+}
+
+// flowchartLabel quotes s as a Mermaid flowchart label.
+func flowchartLabel(s string) string {
+	s = strings.NewReplacer(`"`, "#quot;", "\n", " ", "\r", " ").Replace(s)
+	return `"` + strings.TrimSpace(s) + `"`
+}
@@ -0,0 +1,79 @@
+package trd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMermaidComponents(t *testing.T) {
+	a := Architecture{
+		Components: []Component{
+			{ID: "api", Name: "API", Type: "Service", Technology: "Go", Dependencies: []string{"db", "queue", "hsm-service"}},
+			{ID: "db", Name: `Orders "DB"`, Type: "Database"},
+			{ID: "queue", Name: "Events", Type: "Message Queue"},
+			{ID: "sdk", Name: "Client SDK", Type: "Library", Dependencies: []string{"api"}},
+		},
+		DataFlows: []DataFlow{
+			{Name: "orders", Source: "api", Destination: "db", Protocol: "SQL"},
+			{Source: "queue", Destination: "api"},
+			{Name: "lost", Source: "api", Destination: "nowhere"},
+		},
+	}
+	integrations := []Integration{
+		{ID: "stripe", Name: "Stripe", Type: "API", Direction: "Outbound", Protocol: "REST"},
+		{ID: "sso", Name: "SSO", Direction: "Inbound"},
+		{Name: "CRM", Direction: "Bidirectional"},
+	}
+	want := `flowchart LR
+    subgraph system["Checkout"]
+        c_api["API<br/>[Service: Go]"]
+        c_db[("Orders #quot;DB#quot;<br/>[Database]")]
+        c_queue{{"Events<br/>[Message Queue]"}}
+        c_sdk[["Client SDK<br/>[Library]"]]
+    end
+    x_hsm_service(["hsm-service"])
+    i_stripe(["Stripe<br/>[API: REST]"])
+    i_sso(["SSO"])
+    i_3(["CRM"])
+    c_api -->|"orders [SQL]"| c_db
+    c_queue --> c_api
+    c_api --> c_queue
+    c_api --> x_hsm_service
+    c_sdk --> c_api
+    system --> i_stripe
+    i_sso --> system
+    system <--> i_3
+`
+	if got := a.MermaidComponents("Checkout", integrations); got != want {
+		t.Errorf("MermaidComponents() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := (&Architecture{}).MermaidComponents("", nil); got != "" {
+		t.Errorf("MermaidComponents() without components = %q, want empty", got)
+	}
+}
+
+func TestMarkdownComponentDiagram(t *testing.T) {
+	doc := Document{Architecture: Architecture{Components: []Component{{ID: "api", Name: "API"}}}}
+	md := doc.ToMarkdown(MarkdownOptions{})
+	if !strings.Contains(md, "### 2.4 Components\n\n```mermaid\nflowchart LR\n") {
+		t.Errorf("expected a component diagram under Components:\n%s", md)
+	}
+}
+
+func TestMarkdownComponentCodeBlock(t *testing.T) {
+	doc := Document{Architecture: Architecture{Components: []Component{
+		{ID: "api", Name: "API", Description: "Serves requests:\n```go\nhttp.ListenAndServe(\":80\", nil)\n```"},
+		{ID: "db", Name: "DB", Description: "Stores a | b"},
+	}}}
+	md := doc.ToMarkdown(MarkdownOptions{})
+	for _, want := range []string{
+		"| api | API |  |  | See below |",
+		"| db | DB |  |  | Stores a \\| b |",
+		"#### api: API\n\nServes requests:\n```go\nhttp.ListenAndServe(\":80\", nil)\n```\n\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package trd
+
+import (
+	"github.com/grokify/structured-plan/budget"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+// CostItems returns the cost estimates on components and technology stack
+// entries.
+func (d *Document) CostItems() []budget.Item {
+	var items []budget.Item
+	for _, c := range d.Architecture.Components {
+		if !c.Cost.IsZero() {
+			items = append(items, budget.Item{Kind: budget.KindComponent, ID: c.ID, Name: c.Name, Cost: *c.Cost})
+		}
+	}
+	ts := d.TechnologyStack
+	for _, group := range [][]Technology{ts.Languages, ts.Frameworks, ts.Databases, ts.MessageQueues,
+		ts.Caching, ts.Infrastructure, ts.Monitoring, ts.CICD, ts.Other} {
+		for _, t := range group {
+			if !t.Cost.IsZero() {
+				items = append(items, budget.Item{Kind: budget.KindTechnology, Name: t.Name, Cost: *t.Cost})
+			}
+		}
+	}
+	return items
+}
+
+// Budget rolls up the document's cost estimates. A TRD has no roadmap of its
+// own, so phaseId references are resolved against phases, typically those of
+// the companion PRD; with no phases all costs are unscheduled.
+func (d *Document) Budget(phases []roadmap.Phase) *budget.Report {
+	return budget.Rollup(d.CostItems(), phases)
+}
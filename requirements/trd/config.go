@@ -0,0 +1,159 @@
+package trd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RedactedValue replaces secret values in a redacted document.
+const RedactedValue = "[REDACTED]"
+
+// ConfigEntry is one environment variable or setting the system reads at
+// runtime.
+type ConfigEntry struct {
+	Name     string `json:"name"` // Environment variable or setting name, e.g. DATABASE_URL
+	Purpose  string `json:"purpose"`
+	Secret   bool   `json:"secret,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Owner    string `json:"owner,omitempty"`   // Team that provisions and rotates the value
+	Source   string `json:"source,omitempty"`  // Where the value comes from, e.g. a vault path
+	Default  string `json:"default,omitempty"` // Never set for secrets
+
+	// IntegrationID is the ID of the integration the entry configures,
+	// such as its credentials or endpoint.
+	IntegrationID string `json:"integrationId,omitempty"`
+}
+
+// requiresConfig reports whether an integration's auth method needs
+// credentials or other configuration.
+func requiresConfig(i Integration) bool {
+	switch strings.ToLower(strings.TrimSpace(i.AuthMethod)) {
+	case "", "none", "n/a":
+		return false
+	}
+	return true
+}
+
+// ValidateConfiguration checks the configuration inventory: names are
+// unique, secrets carry no default value, integration references resolve,
+// and every integration with an auth method has at least one entry. TRDs
+// without an inventory are not checked.
+func (d *Document) ValidateConfiguration() []Issue {
+	if len(d.Configuration) == 0 {
+		return nil
+	}
+	integrations := make(map[string]bool, len(d.Integration))
+	for _, i := range d.Integration {
+		integrations[i.ID] = true
+	}
+
+	var issues []Issue
+	seen := make(map[string]bool)
+	configured := make(map[string]bool)
+	for i, c := range d.Configuration {
+		if c.Name != "" && seen[c.Name] {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("configuration[%d].name", i),
+				Message: fmt.Sprintf("duplicate configuration entry %q", c.Name),
+			})
+		}
+		seen[c.Name] = true
+		if c.Secret && c.Default != "" {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("configuration[%d].default", i),
+				Message: fmt.Sprintf("secret %s must not have a default value", c.Name),
+			})
+		}
+		if c.IntegrationID != "" {
+			if !integrations[c.IntegrationID] {
+				issues = append(issues, Issue{
+					Field:   fmt.Sprintf("configuration[%d].integrationId", i),
+					Message: fmt.Sprintf("%s references unknown integration %q", c.Name, c.IntegrationID),
+				})
+			}
+			configured[c.IntegrationID] = true
+		}
+	}
+
+	for i, intg := range d.Integration {
+		if requiresConfig(intg) && !configured[intg.ID] {
+			issues = append(issues, Issue{
+				Field: fmt.Sprintf("integrations[%d]", i),
+				Message: fmt.Sprintf("integration %s uses %s auth but no configuration entry references it",
+					integrationLabel(intg), intg.AuthMethod),
+			})
+		}
+	}
+	return issues
+}
+
+func integrationLabel(i Integration) string {
+	if i.ID != "" {
+		return i.ID
+	}
+	return fmt.Sprintf("%q", i.Name)
+}
+
+// Redacted returns a copy of the document for sharing outside the team:
+// the source and default of secret configuration entries are replaced by
+// RedactedValue. The receiver is not modified.
+func (d Document) Redacted() Document {
+	if len(d.Configuration) == 0 {
+		return d
+	}
+	config := make([]ConfigEntry, len(d.Configuration))
+	for i, c := range d.Configuration {
+		if c.Secret {
+			if c.Source != "" {
+				c.Source = RedactedValue
+			}
+			if c.Default != "" {
+				c.Default = RedactedValue
+			}
+		}
+		config[i] = c
+	}
+	d.Configuration = config
+	return d
+}
+
+// writeConfiguration writes the configuration inventory as a table, secrets
+// first.
+func (d *Document) writeConfiguration(sb *strings.Builder, sectionNum int) {
+	sb.WriteString(fmt.Sprintf("## %d. Configuration\n\n", sectionNum))
+	secrets := 0
+	for _, c := range d.Configuration {
+		if c.Secret {
+			secrets++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%d settings, %d of them secret.\n\n", len(d.Configuration), secrets))
+	sb.WriteString("| Name | Purpose | Secret | Required | Owner | Source | Default | Integration |\n")
+	sb.WriteString("|------|---------|:------:|:--------:|-------|--------|---------|-------------|\n")
+	for _, c := range d.ConfigurationBySecret() {
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s | %s | %s |\n",
+			c.Name, c.Purpose, yesMark(c.Secret), yesMark(c.Required), c.Owner, c.Source, c.Default, c.IntegrationID))
+	}
+	sb.WriteString("\n---\n\n")
+}
+
+// ConfigurationBySecret returns the configuration entries with secrets
+// first, otherwise in document order.
+func (d *Document) ConfigurationBySecret() []ConfigEntry {
+	entries := make([]ConfigEntry, 0, len(d.Configuration))
+	for _, secret := range []bool{true, false} {
+		for _, c := range d.Configuration {
+			if c.Secret == secret {
+				entries = append(entries, c)
+			}
+		}
+	}
+	return entries
+}
+
+func yesMark(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package trd
+
+import (
+	"strings"
+	"testing"
+)
+
+func configDocument() Document {
+	return Document{
+		Integration: []Integration{
+			{ID: "int-pay", Name: "Payments", AuthMethod: "API Key"},
+			{ID: "int-sso", Name: "SSO", AuthMethod: "OIDC"},
+			{ID: "int-feed", Name: "Public feed", AuthMethod: "None"},
+		},
+		Configuration: []ConfigEntry{
+			{Name: "LOG_LEVEL", Purpose: "Log verbosity", Default: "info"},
+			{Name: "PAY_API_KEY", Purpose: "Payments key", Secret: true, Source: "vault:pay", IntegrationID: "int-pay"},
+			{Name: "DB_PASSWORD", Purpose: "Database password", Secret: true, Default: "hunter2"},
+			{Name: "LOG_LEVEL", Purpose: "Duplicate"},
+			{Name: "CRM_TOKEN", Purpose: "CRM token", Secret: true, IntegrationID: "int-crm"},
+		},
+	}
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	doc := configDocument()
+	got := doc.ValidateConfiguration()
+	want := []string{
+		"configuration[2].default",
+		"configuration[3].name",
+		"configuration[4].integrationId",
+		"integrations[1]",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ValidateConfiguration = %+v, want fields %v", got, want)
+	}
+	for i, f := range want {
+		if got[i].Field != f {
+			t.Errorf("issue %d field = %q, want %q", i, got[i].Field, f)
+		}
+	}
+
+	noInventory := Document{Integration: doc.Integration}
+	if issues := noInventory.ValidateConfiguration(); issues != nil {
+		t.Errorf("ValidateConfiguration without an inventory = %+v", issues)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	doc := configDocument()
+	red := doc.Redacted()
+
+	if c := red.Configuration[1]; c.Source != RedactedValue || c.Default != "" {
+		t.Errorf("redacted secret = %+v", c)
+	}
+	if c := red.Configuration[2]; c.Default != RedactedValue {
+		t.Errorf("redacted secret default = %q", c.Default)
+	}
+	if c := red.Configuration[0]; c.Default != "info" {
+		t.Errorf("non-secret default = %q, want it kept", c.Default)
+	}
+	if doc.Configuration[1].Source != "vault:pay" {
+		t.Error("Redacted modified the original document")
+	}
+
+	md := red.ToMarkdown(MarkdownOptions{})
+	if strings.Contains(md, "hunter2") || strings.Contains(md, "vault:pay") {
+		t.Errorf("redacted markdown leaks secret values:\n%s", md)
+	}
+	if !strings.Contains(md, "5 settings, 3 of them secret.") {
+		t.Errorf("missing configuration summary in:\n%s", md)
+	}
+}
+
+func TestConfigurationBySecret(t *testing.T) {
+	doc := configDocument()
+	var names []string
+	for _, c := range doc.ConfigurationBySecret() {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "PAY_API_KEY,DB_PASSWORD,CRM_TOKEN,LOG_LEVEL,LOG_LEVEL" {
+		t.Errorf("ConfigurationBySecret = %s", got)
+	}
+}
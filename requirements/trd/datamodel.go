@@ -0,0 +1,329 @@
+package trd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/assets"
+)
+
+// Relationship cardinalities, read from the entity on the From side: an
+// Agent that belongs to one Organization is many-to-one.
+const (
+	CardinalityOneToOne   = "one-to-one"
+	CardinalityOneToMany  = "one-to-many"
+	CardinalityManyToOne  = "many-to-one"
+	CardinalityManyToMany = "many-to-many"
+)
+
+// Relationship relates two entities of the data model, each referenced by
+// ID or name.
+type Relationship struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Cardinality string `json:"cardinality,omitempty"` // one-to-one, one-to-many, many-to-one, many-to-many
+	Label       string `json:"label,omitempty"`       // Verb phrase, e.g. "owns"
+	Description string `json:"description,omitempty"`
+}
+
+// ParseRelationship parses an entity's relationship string, such as
+// "Organization (many-to-one)", into a relationship from the entity.
+func ParseRelationship(from, s string) Relationship {
+	r := Relationship{From: from, To: strings.TrimSpace(s)}
+	if i := strings.LastIndex(r.To, "("); i > 0 && strings.HasSuffix(r.To, ")") {
+		r.Cardinality = strings.ToLower(strings.TrimSpace(r.To[i+1 : len(r.To)-1]))
+		r.To = strings.TrimSpace(r.To[:i])
+	}
+	return r
+}
+
+// Entity returns the entity with the given ID or, failing that, name,
+// compared case-insensitively, or nil if there is none.
+func (dm *DataModel) Entity(ref string) *Entity {
+	ref = strings.TrimSpace(ref)
+	for i := range dm.Entities {
+		if strings.EqualFold(dm.Entities[i].ID, ref) {
+			return &dm.Entities[i]
+		}
+	}
+	for i := range dm.Entities {
+		if strings.EqualFold(dm.Entities[i].Name, ref) {
+			return &dm.Entities[i]
+		}
+	}
+	return nil
+}
+
+// AllRelationships returns the relationships of the data model followed by
+// those listed on its entities, which are from the entity's ID.
+func (dm *DataModel) AllRelationships() []Relationship {
+	rels := append([]Relationship(nil), dm.Relationships...)
+	for _, e := range dm.Entities {
+		from := e.ID
+		if from == "" {
+			from = e.Name
+		}
+		for _, s := range e.Relationships {
+			rels = append(rels, ParseRelationship(from, s))
+		}
+	}
+	return rels
+}
+
+// Issue is a problem found by a TRD check, at a dotted field path.
+type Issue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidateDataModel checks that relationships reference defined entities
+// and have a known cardinality, and that entity IDs are unique.
+func (d *Document) ValidateDataModel() []Issue {
+	dm := d.DataModel
+	if dm == nil {
+		return nil
+	}
+	var issues []Issue
+	seen := make(map[string]bool)
+	for i, e := range dm.Entities {
+		id := strings.ToLower(e.ID)
+		if id != "" && seen[id] {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("dataModel.entities[%d].id", i),
+				Message: fmt.Sprintf("duplicate entity ID %q", e.ID),
+			})
+		}
+		seen[id] = true
+		for j, s := range e.Relationships {
+			r := ParseRelationship(e.ID, s)
+			issues = append(issues, dm.checkRelationship(r, fmt.Sprintf("dataModel.entities[%d].relationships[%d]", i, j), false)...)
+		}
+	}
+	for i, r := range dm.Relationships {
+		issues = append(issues, dm.checkRelationship(r, fmt.Sprintf("dataModel.relationships[%d]", i), true)...)
+	}
+	return issues
+}
+
+func (dm *DataModel) checkRelationship(r Relationship, field string, checkFrom bool) []Issue {
+	var issues []Issue
+	if checkFrom && dm.Entity(r.From) == nil {
+		issues = append(issues, Issue{Field: field + ".from", Message: fmt.Sprintf("relationship from undefined entity %q", r.From)})
+	}
+	if dm.Entity(r.To) == nil {
+		to := field
+		if checkFrom {
+			to += ".to"
+		}
+		issues = append(issues, Issue{Field: to, Message: fmt.Sprintf("relationship to undefined entity %q", r.To)})
+	}
+	if r.Cardinality != "" && erdConnector(r.Cardinality) == "" {
+		c := field
+		if checkFrom {
+			c += ".cardinality"
+		}
+		issues = append(issues, Issue{Field: c, Message: fmt.Sprintf("unknown cardinality %q (expected one-to-one, one-to-many, many-to-one, or many-to-many)", r.Cardinality)})
+	}
+	return issues
+}
+
+// erdConnector returns the Mermaid erDiagram connector for a cardinality,
+// or "" if it is unknown. Relationships without a cardinality are drawn as
+// one-to-many.
+func erdConnector(cardinality string) string {
+	switch strings.ToLower(cardinality) {
+	case CardinalityOneToOne:
+		return "||--||"
+	case CardinalityOneToMany, "":
+		return "||--o{"
+	case CardinalityManyToOne:
+		return "}o--||"
+	case CardinalityManyToMany:
+		return "}o--o{"
+	}
+	return ""
+}
+
+// MermaidERD returns the data model as a Mermaid entity-relationship
+// diagram. Relationships to undefined entities are left out.
+func (dm *DataModel) MermaidERD() string {
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+	for _, e := range dm.Entities {
+		sb.WriteString(fmt.Sprintf("    %s {\n", erdName(e.Name, e.ID)))
+		for _, a := range e.Attributes {
+			line := fmt.Sprintf("        %s %s", erdName(a.Type, "string"), erdName(a.Name, "attribute"))
+			if key := attributeKey(a); key != "" {
+				line += " " + key
+			}
+			if a.Description != "" {
+				line += fmt.Sprintf(" %q", strings.ReplaceAll(a.Description, `"`, "'"))
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("    }\n")
+	}
+	for _, r := range dm.AllRelationships() {
+		from, to := dm.Entity(r.From), dm.Entity(r.To)
+		connector := erdConnector(r.Cardinality)
+		if from == nil || to == nil || connector == "" {
+			continue
+		}
+		label := r.Label
+		if label == "" {
+			label = r.Cardinality
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s %s : %q\n", erdName(from.Name, from.ID), connector, erdName(to.Name, to.ID), label))
+	}
+	return sb.String()
+}
+
+// erdName makes s a Mermaid identifier of letters, digits, and underscores,
+// or returns fallback if nothing is left.
+func erdName(s, fallback string) string {
+	var sb strings.Builder
+	underscore := false
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			underscore = false
+		case sb.Len() > 0 && !underscore:
+			sb.WriteByte('_')
+			underscore = true
+		}
+	}
+	name := strings.TrimSuffix(sb.String(), "_")
+	if name == "" {
+		if fallback == "" {
+			return "entity"
+		}
+		return erdName(fallback, "entity")
+	}
+	return name
+}
+
+// attributeKey returns PK, FK, or UK from an attribute's constraints, or
+// PK for an attribute named "id".
+func attributeKey(a Attribute) string {
+	c := strings.ToLower(a.Constraints)
+	switch {
+	case strings.Contains(c, "primary key") || hasWord(c, "pk"):
+		return "PK"
+	case strings.Contains(c, "foreign key") || hasWord(c, "fk") || strings.Contains(c, "references"):
+		return "FK"
+	case hasWord(c, "unique") || hasWord(c, "uk"):
+		return "UK"
+	case strings.EqualFold(a.Name, "id"):
+		return "PK"
+	}
+	return ""
+}
+
+func hasWord(s, word string) bool {
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		if f == word {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDataModel writes the data model section: the overview, an ER
+// diagram, a table per entity, the relationships, data stores, and any
+// other diagrams.
+func (d *Document) writeDataModel(sb *strings.Builder, sectionNum int) {
+	dm := d.DataModel
+	sb.WriteString(fmt.Sprintf("## %d. Data Model\n\n", sectionNum))
+	if dm.Overview != "" {
+		sb.WriteString(dm.Overview + "\n\n")
+	}
+
+	sub := 1
+	if len(dm.Entities) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Entity-Relationship Diagram\n\n", sectionNum, sub))
+		sb.WriteString("```mermaid\n" + dm.MermaidERD() + "```\n\n")
+		sub++
+
+		sb.WriteString(fmt.Sprintf("### %d.%d Entities\n\n", sectionNum, sub))
+		for _, e := range dm.Entities {
+			sb.WriteString(fmt.Sprintf("#### %s\n\n", e.Name))
+			if e.Description != "" {
+				sb.WriteString(e.Description + "\n\n")
+			}
+			if len(e.Attributes) > 0 {
+				sb.WriteString("| Attribute | Type | Required | Constraints | Description |\n")
+				sb.WriteString("|-----------|------|:--------:|-------------|-------------|\n")
+				for _, a := range e.Attributes {
+					required := ""
+					if a.Required {
+						required = "✓"
+					}
+					sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+						a.Name, a.Type, required, a.Constraints, a.Description))
+				}
+				sb.WriteString("\n")
+			}
+		}
+		sub++
+	}
+
+	if rels := dm.AllRelationships(); len(rels) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Relationships\n\n", sectionNum, sub))
+		sb.WriteString("| From | To | Cardinality | Description |\n")
+		sb.WriteString("|------|----|-------------|-------------|\n")
+		for _, r := range rels {
+			desc := r.Description
+			if desc == "" {
+				desc = r.Label
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				dm.entityName(r.From), dm.entityName(r.To), r.Cardinality, desc))
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if len(dm.DataStores) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Data Stores\n\n", sectionNum, sub))
+		sb.WriteString("| Store | Type | Purpose | Replication | Backup |\n")
+		sb.WriteString("|-------|------|---------|-------------|--------|\n")
+		for _, s := range dm.DataStores {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				s.Name, s.Type, s.Purpose, s.Replication, s.Backup))
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if len(dm.Diagrams) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Diagrams\n\n", sectionNum, sub))
+		for _, diag := range dm.Diagrams {
+			if diag.URL == "" {
+				continue
+			}
+			sb.WriteString(assets.Markdown(diag.Title, diag.URL) + "\n\n")
+			if diag.Description != "" {
+				sb.WriteString(diag.Description + "\n\n")
+			}
+		}
+		sub++
+	}
+
+	if dm.Migrations != "" {
+		sb.WriteString(fmt.Sprintf("### %d.%d Migrations\n\n%s\n\n", sectionNum, sub, dm.Migrations))
+	}
+
+	sb.WriteString("---\n\n")
+}
+
+// entityName returns the name of the entity ref refers to, or ref itself
+// if it is undefined.
+func (dm *DataModel) entityName(ref string) string {
+	if e := dm.Entity(ref); e != nil && e.Name != "" {
+		return e.Name
+	}
+	return ref
+}
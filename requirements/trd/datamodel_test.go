@@ -0,0 +1,93 @@
+package trd
+
+import (
+	"strings"
+	"testing"
+)
+
+func dataModelDocument() Document {
+	return Document{DataModel: &DataModel{
+		Entities: []Entity{
+			{ID: "org", Name: "Organization", Attributes: []Attribute{
+				{Name: "id", Type: "UUID", Required: true},
+				{Name: "slug", Type: "VARCHAR(100)", Constraints: "unique", Description: `URL "slug"`},
+			}},
+			{ID: "agent", Name: "Agent", Attributes: []Attribute{
+				{Name: "org_id", Type: "UUID", Constraints: "FK to org"},
+			}, Relationships: []string{"Organization (many-to-one)", "User (many-to-one)"}},
+		},
+		Relationships: []Relationship{
+			{From: "org", To: "agent", Cardinality: "one-to-many", Label: "owns"},
+			{From: "team", To: "org", Cardinality: "some-to-one"},
+		},
+	}}
+}
+
+func TestParseRelationship(t *testing.T) {
+	got := ParseRelationship("agent", " KYA Attestation (One-to-One) ")
+	want := Relationship{From: "agent", To: "KYA Attestation", Cardinality: "one-to-one"}
+	if got != want {
+		t.Errorf("ParseRelationship = %+v, want %+v", got, want)
+	}
+	if got := ParseRelationship("a", "User"); got.To != "User" || got.Cardinality != "" {
+		t.Errorf("ParseRelationship without cardinality = %+v", got)
+	}
+}
+
+func TestMermaidERD(t *testing.T) {
+	doc := dataModelDocument()
+	got := doc.DataModel.MermaidERD()
+	want := strings.Join([]string{
+		"erDiagram",
+		"    Organization {",
+		"        UUID id PK",
+		`        VARCHAR_100 slug UK "URL 'slug'"`,
+		"    }",
+		"    Agent {",
+		"        UUID org_id FK",
+		"    }",
+		`    Organization ||--o{ Agent : "owns"`,
+		`    Agent }o--|| Organization : "many-to-one"`,
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("MermaidERD =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestValidateDataModel(t *testing.T) {
+	doc := dataModelDocument()
+	issues := doc.ValidateDataModel()
+	want := []string{
+		"dataModel.entities[1].relationships[1]",
+		"dataModel.relationships[1].from",
+		"dataModel.relationships[1].cardinality",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("ValidateDataModel = %+v, want %d issues", issues, len(want))
+	}
+	for i, field := range want {
+		if issues[i].Field != field {
+			t.Errorf("issues[%d] = %+v, want field %s", i, issues[i], field)
+		}
+	}
+}
+
+func TestDataModelMarkdown(t *testing.T) {
+	doc := dataModelDocument()
+	md := doc.ToMarkdown(DefaultMarkdownOptions())
+	for _, want := range []string{
+		"## 4. Security Design",
+		"## 6. Deployment",
+		"## 7. Data Model",
+		"### 7.1 Entity-Relationship Diagram\n\n```mermaid\nerDiagram\n",
+		"#### Organization\n\n| Attribute | Type | Required | Constraints | Description |",
+		"| id | UUID | ✓ |  |  |",
+		"| Organization | Agent | one-to-many | owns |",
+		"| Agent | User | many-to-one |  |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q", want)
+		}
+	}
+}
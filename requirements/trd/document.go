@@ -2,8 +2,10 @@
 package trd
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/grokify/structured-plan/assets"
 	"github.com/grokify/structured-plan/common"
 )
 
@@ -13,6 +15,12 @@ type Person = common.Person
 // Approver is an alias for common.Approver for backwards compatibility.
 type Approver = common.Approver
 
+// Review is an alias for common.Review.
+type Review = common.Review
+
+// ExternalRefs is an alias for common.ExternalRefs.
+type ExternalRefs = common.ExternalRefs
+
 // Status is an alias for common.Status for backwards compatibility.
 type Status = common.Status
 
@@ -22,6 +30,15 @@ type GlossaryTerm = common.GlossaryTerm
 // CustomSection is an alias for common.CustomSection for backwards compatibility.
 type CustomSection = common.CustomSection
 
+// Dependency is an alias for common.Dependency.
+type Dependency = common.Dependency
+
+// Cost is an alias for common.Cost.
+type Cost = common.Cost
+
+// Comment is an alias for common.Comment.
+type Comment = common.Comment
+
 // Status constants re-exported from common for backward compatibility.
 const (
 	StatusDraft      = common.StatusDraft
@@ -42,16 +59,25 @@ type Document struct {
 	Performance       Performance      `json:"performance"`
 	Scalability       *Scalability     `json:"scalability,omitempty"`
 	Deployment        Deployment       `json:"deployment"`
+	MigrationPlan     *MigrationPlan   `json:"migrationPlan,omitempty"`
 	Integration       []Integration    `json:"integrations,omitempty"`
+	Configuration     []ConfigEntry    `json:"configuration,omitempty"`
 	Development       *Development     `json:"development,omitempty"`
 	Testing           *Testing         `json:"testing,omitempty"`
 
+	// OperationalReadiness gates launch on operability; see CheckReadiness.
+	OperationalReadiness *OperationalReadiness `json:"operationalReadiness,omitempty"`
+
 	// Optional sections
 	Risks          []Risk          `json:"risks,omitempty"`
 	Constraints    []Constraint    `json:"constraints,omitempty"`
 	Assumptions    []Assumption    `json:"assumptions,omitempty"`
+	Dependencies   []Dependency    `json:"dependencies,omitempty"`
 	Glossary       []GlossaryTerm  `json:"glossary,omitempty"`
 	CustomSections []CustomSection `json:"customSections,omitempty"`
+
+	// Comments are review comments on parts of the document.
+	Comments []Comment `json:"comments,omitempty"`
 }
 
 // Note: Status type and constants are defined in common/ and aliased above.
@@ -69,6 +95,58 @@ type Metadata struct {
 	Approvers        []Approver   `json:"approvers,omitempty"`
 	Tags             []string     `json:"tags,omitempty"`
 	RelatedDocuments []RelatedDoc `json:"relatedDocuments,omitempty"`
+
+	// Team is the team that owns the document.
+	Team string `json:"team,omitempty"`
+
+	// Reviews tracks requested reviews and their state. While Status is
+	// approved, every role in RequiredReviewRoles must have signed off.
+	Reviews             []Review `json:"reviews,omitempty"`
+	RequiredReviewRoles []string `json:"requiredReviewRoles,omitempty"`
+
+	// ReviewedAt is when the document was last approved. It is required
+	// while Status is approved.
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+
+	// Supersedes lists the IDs of documents this one replaces. SupersededBy
+	// is the ID of the document that replaces this one; a deprecated
+	// document must name it.
+	Supersedes   []string `json:"supersedes,omitempty"`
+	SupersededBy string   `json:"supersededBy,omitempty"`
+
+	// ExternalRefs links this document to other systems, e.g. its
+	// Confluence page. ExternalRefURLs maps a system to a link template
+	// containing "{id}", used to link refs on the document and its
+	// entities that are IDs rather than URLs.
+	ExternalRefs    ExternalRefs      `json:"externalRefs,omitempty"`
+	ExternalRefURLs map[string]string `json:"externalRefUrls,omitempty"`
+
+	// Snippets records the content expanded from the workspace snippet
+	// library, for detecting local edits to it.
+	Snippets []common.SnippetRef `json:"snippets,omitempty"`
+
+	// Profile and Flags describe the kind of product the document is for,
+	// such as an enterprise product with a UI. Conditions maps a top-level
+	// section or an item ID to the condition under which it applies, such
+	// as "profile == enterprise" or "has_ui"; sections and items whose
+	// condition is false are left out when the document is rendered or
+	// checked.
+	Profile    string            `json:"profile,omitempty"`
+	Flags      map[string]bool   `json:"flags,omitempty"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// Lifecycle returns the fields checked by the document lifecycle policy.
+func (m Metadata) Lifecycle() common.Lifecycle {
+	return common.Lifecycle{
+		Status:              m.Status,
+		Reviewers:           m.Reviewers,
+		Approvers:           m.Approvers,
+		Reviews:             m.Reviews,
+		RequiredReviewRoles: m.RequiredReviewRoles,
+		ReviewedAt:          m.ReviewedAt,
+		SupersededBy:        m.SupersededBy,
+	}
 }
 
 // RelatedDoc represents a related document reference.
@@ -109,7 +187,12 @@ type Component struct {
 	Dependencies     []string `json:"dependencies,omitempty"` // IDs of dependent components
 	Technology       string   `json:"technology,omitempty"`
 	Owner            string   `json:"owner,omitempty"`
-	Tags             []string `json:"tags,omitempty"` // For filtering by topic/domain
+	Requirements     []string `json:"requirements,omitempty"` // IDs of PRD requirements this component implements
+	Tags             []string `json:"tags,omitempty"`         // For filtering by topic/domain
+	Cost             *Cost    `json:"cost,omitempty"`
+
+	// ExternalRefs links this component to other systems, e.g. its repository.
+	ExternalRefs ExternalRefs `json:"externalRefs,omitempty"`
 }
 
 // Diagram represents an architecture diagram.
@@ -163,10 +246,14 @@ type TechnologyStack struct {
 type Technology struct {
 	Name         string   `json:"name"`
 	Version      string   `json:"version,omitempty"`
+	Vendor       string   `json:"vendor,omitempty"`      // Supplier of a commercial or hosted product, e.g. "AWS"
+	License      string   `json:"license,omitempty"`     // SPDX identifier or expression, e.g. "Apache-2.0", or "Proprietary"
+	SupportTier  string   `json:"supportTier,omitempty"` // e.g. community, commercial, enterprise
 	Purpose      string   `json:"purpose"`
 	Rationale    string   `json:"rationale,omitempty"`
 	Alternatives []string `json:"alternatives,omitempty"`
 	Constraints  []string `json:"constraints,omitempty"`
+	Cost         *Cost    `json:"cost,omitempty"`
 }
 
 // APISpec represents an API specification.
@@ -196,9 +283,14 @@ type APIEndpoint struct {
 
 // DataModel contains data modeling information.
 type DataModel struct {
-	Overview   string      `json:"overview"`
-	Entities   []Entity    `json:"entities,omitempty"`
-	Diagrams   []Diagram   `json:"diagrams,omitempty"`
+	Overview string    `json:"overview"`
+	Entities []Entity  `json:"entities,omitempty"`
+	Diagrams []Diagram `json:"diagrams,omitempty"`
+
+	// Relationships relate entities by ID or name. Entities may also list
+	// their relationships as strings such as "Organization (many-to-one)".
+	Relationships []Relationship `json:"relationships,omitempty"`
+
 	DataStores []DataStore `json:"dataStores,omitempty"`
 	Migrations string      `json:"migrations,omitempty"` // Migration strategy
 }
@@ -394,14 +486,27 @@ type Development struct {
 
 // Testing contains testing strategy.
 type Testing struct {
-	Strategy     string   `json:"strategy"`
-	UnitTests    string   `json:"unitTests,omitempty"`
-	Integration  string   `json:"integrationTests,omitempty"`
-	E2E          string   `json:"e2e_tests,omitempty"`
-	Performance  string   `json:"performanceTests,omitempty"`
-	Security     string   `json:"securityTests,omitempty"`
-	Coverage     string   `json:"coverageRequirements,omitempty"`
-	Environments []string `json:"testEnvironments,omitempty"`
+	Strategy     string     `json:"strategy"`
+	UnitTests    string     `json:"unitTests,omitempty"`
+	Integration  string     `json:"integrationTests,omitempty"`
+	E2E          string     `json:"e2eTests,omitempty"`
+	Performance  string     `json:"performanceTests,omitempty"`
+	Security     string     `json:"securityTests,omitempty"`
+	Coverage     string     `json:"coverageRequirements,omitempty"`
+	Environments []string   `json:"testEnvironments,omitempty"`
+	TestCases    []TestCase `json:"testCases,omitempty"`
+}
+
+// TestCase is a planned test, linked to the components it exercises and the
+// PRD requirements it verifies for traceability.
+type TestCase struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type,omitempty"` // unit, integration, e2e, performance, security
+	Description  string   `json:"description,omitempty"`
+	Components   []string `json:"components,omitempty"`   // IDs of components under test
+	Requirements []string `json:"requirements,omitempty"` // IDs of PRD requirements verified
+	Status       string   `json:"status,omitempty"`       // planned, automated, passing, failing
 }
 
 // Risk represents a technical risk.
@@ -436,3 +541,28 @@ type Assumption struct {
 }
 
 // Note: GlossaryTerm and CustomSection types are defined in common/ and aliased above.
+
+// AssetRefs returns the document fields that may reference local diagram
+// files, for bundling with generated output.
+func (d *Document) AssetRefs() []assets.Ref {
+	var refs []assets.Ref
+	for i := range d.Architecture.Diagrams {
+		if d.Architecture.Diagrams[i].URL != "" {
+			refs = append(refs, assets.Ref{
+				Field: fmt.Sprintf("architecture.diagrams[%d].url", i),
+				Value: &d.Architecture.Diagrams[i].URL,
+			})
+		}
+	}
+	if d.DataModel != nil {
+		for i := range d.DataModel.Diagrams {
+			if d.DataModel.Diagrams[i].URL != "" {
+				refs = append(refs, assets.Ref{
+					Field: fmt.Sprintf("dataModel.diagrams[%d].url", i),
+					Value: &d.DataModel.Diagrams[i].URL,
+				})
+			}
+		}
+	}
+	return refs
+}
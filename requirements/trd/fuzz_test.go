@@ -0,0 +1,28 @@
+package trd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grokify/structured-plan/lenient"
+)
+
+// FuzzParseDocument checks that any input which decodes, strictly or
+// leniently, can be rendered and filtered without panicking.
+func FuzzParseDocument(f *testing.F) {
+	f.Add([]byte(`{"metadata": {"id": "X-1", "createdAt": "2025-01-01"}}`))
+	f.Add([]byte(`{"glossary": [{"term": 1}], "customSections": [{"content": [1, {"a": null}]}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err == nil {
+			_ = doc.ToMarkdown(DefaultMarkdownOptions())
+			_ = doc.FilterByTags("a")
+		}
+
+		var lenientDoc Document
+		if _, err := lenient.Unmarshal(data, &lenientDoc); err == nil {
+			_ = lenientDoc.ToMarkdown(DefaultMarkdownOptions())
+		}
+	})
+}
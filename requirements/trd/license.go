@@ -0,0 +1,106 @@
+package trd
+
+import "strings"
+
+// License categories, from the least to the most restrictive for legal
+// review.
+const (
+	LicensePermissive      = "permissive"
+	LicenseWeakCopyleft    = "weak-copyleft"
+	LicenseCopyleft        = "copyleft"
+	LicenseSourceAvailable = "source-available"
+	LicenseProprietary     = "proprietary"
+	LicenseUnknown         = "unknown"
+)
+
+var licenseRank = map[string]int{
+	LicensePermissive:      0,
+	LicenseWeakCopyleft:    1,
+	LicenseCopyleft:        2,
+	LicenseSourceAvailable: 3,
+	LicenseProprietary:     4,
+	LicenseUnknown:         5,
+}
+
+// LicenseCategory classifies a license given as an SPDX identifier or
+// expression. For "A OR B" the less restrictive choice applies and for
+// "A AND B" the more restrictive one. A Classpath or linking exception
+// ("GPL-2.0-only WITH Classpath-exception-2.0") makes a copyleft license
+// weak. Unrecognized and empty licenses are LicenseUnknown.
+func LicenseCategory(license string) string {
+	license = strings.Trim(strings.TrimSpace(license), "()")
+	if license == "" {
+		return LicenseUnknown
+	}
+	upper := strings.ToUpper(license)
+	if parts := strings.Split(upper, " OR "); len(parts) > 1 {
+		best := LicenseUnknown
+		for _, p := range parts {
+			if c := LicenseCategory(p); licenseRank[c] < licenseRank[best] {
+				best = c
+			}
+		}
+		return best
+	}
+	if parts := strings.Split(upper, " AND "); len(parts) > 1 {
+		worst := LicensePermissive
+		for _, p := range parts {
+			if c := LicenseCategory(p); licenseRank[c] > licenseRank[worst] {
+				worst = c
+			}
+		}
+		return worst
+	}
+
+	id, exception, _ := strings.Cut(upper, " WITH ")
+	c := licenseID(strings.TrimSpace(id))
+	if c == LicenseCopyleft && (strings.Contains(exception, "CLASSPATH") || strings.Contains(exception, "LINKING")) {
+		return LicenseWeakCopyleft
+	}
+	return c
+}
+
+func licenseID(id string) string {
+	has := func(prefixes ...string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(id, p) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("LGPL", "MPL", "EPL", "CDDL", "CPL", "MS-RL"):
+		return LicenseWeakCopyleft
+	case has("AGPL", "GPL", "SSPL", "OSL", "EUPL", "CC-BY-SA", "RPL"):
+		return LicenseCopyleft
+	case has("MIT", "BSD", "0BSD", "APACHE", "ISC", "ZLIB", "UNLICENSE", "CC0", "BSL-1.0", "PSF", "PYTHON",
+		"POSTGRESQL", "X11", "MS-PL", "ARTISTIC", "WTFPL", "UPL", "BLUEOAK"):
+		return LicensePermissive
+	case has("BUSL", "ELASTIC", "SOURCE-AVAILABLE", "SOURCE AVAILABLE", "CONFLUENT", "RSAL", "COMMONS-CLAUSE", "COMMONS CLAUSE"):
+		return LicenseSourceAvailable
+	case has("PROPRIETARY", "COMMERCIAL", "CLOSED", "LICENSEREF-PROPRIETARY", "SAAS"):
+		return LicenseProprietary
+	}
+	return LicenseUnknown
+}
+
+// IsCopyleft reports whether a license category requires sharing changes
+// or derived works under the same license.
+func IsCopyleft(category string) bool {
+	return category == LicenseCopyleft || category == LicenseWeakCopyleft
+}
+
+// SingleVendor reports whether only the technology's vendor can supply or
+// support it: it has a vendor and a proprietary, source-available, or
+// unknown license, so there is no open-source fallback.
+func (t Technology) SingleVendor() bool {
+	if strings.TrimSpace(t.Vendor) == "" {
+		return false
+	}
+	switch LicenseCategory(t.License) {
+	case LicenseProprietary, LicenseSourceAvailable, LicenseUnknown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package trd
+
+import "testing"
+
+func TestLicenseCategory(t *testing.T) {
+	for license, want := range map[string]string{
+		"Apache-2.0":              "permissive",
+		"BSL-1.0":                 "permissive",
+		"MIT OR GPL-3.0-or-later": "permissive",
+		"LGPL-2.1-only":           "weak-copyleft",
+		"GPL-2.0-only WITH Classpath-exception-2.0": "weak-copyleft",
+		"AGPL-3.0":             "copyleft",
+		"MIT AND GPL-2.0-only": "copyleft",
+		"BUSL-1.1":             "source-available",
+		"Proprietary":          "proprietary",
+		"":                     "unknown",
+		"Custom EULA":          "unknown",
+	} {
+		if got := LicenseCategory(license); got != want {
+			t.Errorf("LicenseCategory(%q) = %q, want %q", license, got, want)
+		}
+	}
+}
+
+func TestSingleVendor(t *testing.T) {
+	for _, tc := range []struct {
+		tech Technology
+		want bool
+	}{
+		{Technology{Name: "Aurora", Vendor: "AWS", License: "Proprietary"}, true},
+		{Technology{Name: "Elasticsearch", Vendor: "Elastic", License: "Elastic-2.0"}, true},
+		{Technology{Name: "Snowflake", Vendor: "Snowflake"}, true},
+		{Technology{Name: "PostgreSQL", Vendor: "AWS", License: "PostgreSQL"}, false},
+		{Technology{Name: "Internal SDK", License: "Proprietary"}, false},
+	} {
+		if got := tc.tech.SingleVendor(); got != tc.want {
+			t.Errorf("%s SingleVendor() = %v, want %v", tc.tech.Name, got, tc.want)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/grokify/structured-plan/assets"
 	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/richtext"
 )
 
 // MarkdownOptions configures markdown generation.
@@ -15,6 +17,9 @@ type MarkdownOptions struct {
 	SansFont           string
 	MonoFont           string
 	FontFamily         string
+	// LinkGlossary links the first use of each glossary term to its entry
+	// (default: true)
+	LinkGlossary *bool
 }
 
 // DefaultMarkdownOptions returns default options.
@@ -40,6 +45,10 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	// Title
 	sb.WriteString(fmt.Sprintf("# %s\n\n", d.Metadata.Title))
 
+	if notice := common.SupersededNotice("TRD", d.Metadata.SupersededBy); notice != "" {
+		sb.WriteString(fmt.Sprintf("> **%s**\n\n", notice))
+	}
+
 	// Document info table
 	sb.WriteString("| Field | Value |\n")
 	sb.WriteString("|-------|-------|\n")
@@ -52,10 +61,22 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	if len(d.Metadata.Authors) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Author(s)** | %s |\n", common.FormatPeopleMarkdown(d.Metadata.Authors)))
 	}
+	if d.Metadata.Team != "" {
+		sb.WriteString(fmt.Sprintf("| **Team** | %s |\n", d.Metadata.Team))
+	}
 
 	if len(d.Metadata.Tags) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Tags** | %s |\n", strings.Join(d.Metadata.Tags, ", ")))
 	}
+	if len(d.Metadata.Supersedes) > 0 {
+		sb.WriteString(fmt.Sprintf("| **Supersedes** | %s |\n", strings.Join(d.Metadata.Supersedes, ", ")))
+	}
+	if d.Metadata.SupersededBy != "" {
+		sb.WriteString(fmt.Sprintf("| **Superseded By** | %s |\n", d.Metadata.SupersededBy))
+	}
+	if len(d.Metadata.ExternalRefs) > 0 {
+		sb.WriteString(fmt.Sprintf("| **External Links** | %s |\n", common.FormatExternalRefsMarkdown(d.Metadata.ExternalRefs, d.Metadata.ExternalRefURLs)))
+	}
 	sb.WriteString("\n")
 
 	// Related Documents
@@ -131,18 +152,28 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 
 	if len(d.Architecture.Components) > 0 {
 		sb.WriteString("### 2.4 Components\n\n")
+		sb.WriteString("```mermaid\n" + d.Architecture.MermaidComponents(d.Metadata.Title, d.Integration) + "```\n\n")
 		sb.WriteString("| ID | Component | Type | Technology | Description |\n")
 		sb.WriteString("|----|-----------|------|------------|-------------|\n")
 		for _, c := range d.Architecture.Components {
+			desc := richtext.TableCell(c.Description)
+			if richtext.HasBlocks(c.Description) {
+				desc = "See below"
+			}
 			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
-				c.ID, c.Name, c.Type, c.Technology, c.Description))
+				c.ID, c.Name, c.Type, c.Technology, desc))
 		}
 		sb.WriteString("\n")
 
-		// Component details
+		// Component details, with the descriptions that have code or math
+		// blocks in full
 		for _, c := range d.Architecture.Components {
-			if len(c.Responsibilities) > 0 || len(c.Dependencies) > 0 {
+			blocks := richtext.HasBlocks(c.Description)
+			if blocks || len(c.Responsibilities) > 0 || len(c.Dependencies) > 0 || len(c.Requirements) > 0 || len(c.ExternalRefs) > 0 {
 				sb.WriteString(fmt.Sprintf("#### %s: %s\n\n", c.ID, c.Name))
+				if blocks {
+					sb.WriteString(strings.TrimSpace(c.Description) + "\n\n")
+				}
 				if len(c.Responsibilities) > 0 {
 					sb.WriteString("**Responsibilities:**\n")
 					for _, r := range c.Responsibilities {
@@ -152,6 +183,12 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 				if len(c.Dependencies) > 0 {
 					sb.WriteString(fmt.Sprintf("\n**Dependencies:** %s\n", strings.Join(c.Dependencies, ", ")))
 				}
+				if len(c.Requirements) > 0 {
+					sb.WriteString(fmt.Sprintf("\n**Implements:** %s\n", strings.Join(c.Requirements, ", ")))
+				}
+				if len(c.ExternalRefs) > 0 {
+					sb.WriteString(fmt.Sprintf("\n**Links:** %s\n", common.FormatExternalRefsMarkdown(c.ExternalRefs, d.Metadata.ExternalRefURLs)))
+				}
 				sb.WriteString("\n")
 			}
 		}
@@ -163,7 +200,7 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString("|------|--------|-------------|----------|-------------|\n")
 		for _, df := range d.Architecture.DataFlows {
 			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
-				df.Name, df.Source, df.Destination, df.Protocol, df.Description))
+				df.Name, df.Source, df.Destination, df.Protocol, richtext.TableCell(df.Description)))
 		}
 		sb.WriteString("\n")
 	}
@@ -188,6 +225,23 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		}
 	}
 
+	if len(d.Architecture.Diagrams) > 0 {
+		sb.WriteString("### 2.7 Diagrams\n\n")
+		for _, diag := range d.Architecture.Diagrams {
+			if diag.URL == "" {
+				continue
+			}
+			title := diag.Title
+			if diag.Type != "" {
+				title += " (" + diag.Type + ")"
+			}
+			sb.WriteString(assets.Markdown(title, diag.URL) + "\n\n")
+			if diag.Description != "" {
+				sb.WriteString(diag.Description + "\n\n")
+			}
+		}
+	}
+
 	sb.WriteString("---\n\n")
 
 	// Technology Stack
@@ -234,7 +288,7 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 				sb.WriteString("| Method | Path | Description |\n")
 				sb.WriteString("|--------|------|-------------|\n")
 				for _, ep := range api.Endpoints {
-					sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", ep.Method, ep.Path, ep.Description))
+					sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", ep.Method, ep.Path, richtext.TableCell(ep.Description)))
 				}
 				sb.WriteString("\n")
 			}
@@ -378,6 +432,25 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 	sb.WriteString("---\n\n")
 	sectionNum++
 
+	// Data Model, after the fixed sections so that their numbers do not
+	// depend on whether the document has one
+	if d.DataModel != nil {
+		d.writeDataModel(&sb, sectionNum)
+		sectionNum++
+	}
+
+	// Migration Plan
+	if d.MigrationPlan != nil {
+		d.writeMigrationPlan(&sb, sectionNum)
+		sectionNum++
+	}
+
+	// Operational Readiness
+	if d.OperationalReadiness != nil {
+		d.writeReadiness(&sb, sectionNum)
+		sectionNum++
+	}
+
 	// Integrations
 	if len(d.Integration) > 0 {
 		sb.WriteString(fmt.Sprintf("## %d. Integrations\n\n", sectionNum))
@@ -385,12 +458,18 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString("|------|------|-----------|----------|------|-------------|\n")
 		for _, intg := range d.Integration {
 			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
-				intg.Name, intg.Type, intg.Direction, intg.Protocol, intg.AuthMethod, intg.Description))
+				intg.Name, intg.Type, intg.Direction, intg.Protocol, intg.AuthMethod, richtext.TableCell(intg.Description)))
 		}
 		sb.WriteString("\n---\n\n")
 		sectionNum++
 	}
 
+	// Configuration
+	if len(d.Configuration) > 0 {
+		d.writeConfiguration(&sb, sectionNum)
+		sectionNum++
+	}
+
 	// Testing
 	if d.Testing != nil {
 		sb.WriteString(fmt.Sprintf("## %d. Testing Strategy\n\n", sectionNum))
@@ -420,6 +499,16 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 			sb.WriteString(fmt.Sprintf("**Coverage Requirements:** %s\n\n", d.Testing.Coverage))
 		}
 
+		if len(d.Testing.TestCases) > 0 {
+			sb.WriteString("| ID | Test Case | Type | Components | Requirements | Status |\n")
+			sb.WriteString("|----|-----------|------|------------|--------------|--------|\n")
+			for _, tc := range d.Testing.TestCases {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+					tc.ID, tc.Name, tc.Type, strings.Join(tc.Components, ", "), strings.Join(tc.Requirements, ", "), tc.Status))
+			}
+			sb.WriteString("\n")
+		}
+
 		sb.WriteString("---\n\n")
 		sectionNum++
 	}
@@ -450,6 +539,22 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sectionNum++
 	}
 
+	// External dependencies
+	if len(d.Dependencies) > 0 {
+		sb.WriteString(fmt.Sprintf("## %d. External Dependencies\n\n", sectionNum))
+		sb.WriteString(common.FormatDependencyTable(d.Dependencies, nil))
+		sb.WriteString("\n---\n\n")
+		sectionNum++
+	}
+
+	// Budget
+	if b := d.Budget(nil); b.HasCosts() {
+		sb.WriteString(fmt.Sprintf("## %d. Budget\n\n", sectionNum))
+		sb.WriteString(b.ToMarkdown())
+		sb.WriteString("---\n\n")
+		sectionNum++
+	}
+
 	// Custom sections
 	for _, cs := range d.CustomSections {
 		sb.WriteString(fmt.Sprintf("## %d. %s\n\n", sectionNum, cs.Title))
@@ -466,16 +571,20 @@ func (d *Document) ToMarkdown(opts MarkdownOptions) string {
 		sb.WriteString(fmt.Sprintf("## %d. Glossary\n\n", sectionNum))
 		sb.WriteString("| Term | Definition |\n")
 		sb.WriteString("|------|------------|\n")
-		for _, g := range d.Glossary {
+		anchors := common.GlossaryAnchors(d.Glossary)
+		for i, g := range d.Glossary {
 			term := g.Term
 			if g.Acronym != "" {
 				term = fmt.Sprintf("%s (%s)", g.Term, g.Acronym)
 			}
-			sb.WriteString(fmt.Sprintf("| %s | %s |\n", term, g.Definition))
+			sb.WriteString(fmt.Sprintf("| <a id=\"%s\"></a>%s | %s |\n", anchors[i], term, g.Definition))
 		}
 		sb.WriteString("\n")
 	}
 
+	if opts.LinkGlossary == nil || *opts.LinkGlossary {
+		return common.LinkGlossaryMarkdown(sb.String(), d.Glossary)
+	}
 	return sb.String()
 }
 
@@ -484,15 +593,36 @@ func (d *Document) writeTechSection(sb *strings.Builder, title string, techs []T
 		return
 	}
 	fmt.Fprintf(sb, "### %s\n\n", title)
-	sb.WriteString("| Technology | Version | Purpose | Rationale |\n")
-	sb.WriteString("|------------|---------|---------|----------|\n")
+	if !hasSourcing(techs) {
+		sb.WriteString("| Technology | Version | Purpose | Rationale |\n")
+		sb.WriteString("|------------|---------|---------|----------|\n")
+		for _, t := range techs {
+			fmt.Fprintf(sb, "| %s | %s | %s | %s |\n",
+				t.Name, t.Version, t.Purpose, t.Rationale)
+		}
+		sb.WriteString("\n")
+		return
+	}
+	sb.WriteString("| Technology | Version | Purpose | Rationale | License | Vendor | Support |\n")
+	sb.WriteString("|------------|---------|---------|-----------|---------|--------|---------|\n")
 	for _, t := range techs {
-		fmt.Fprintf(sb, "| %s | %s | %s | %s |\n",
-			t.Name, t.Version, t.Purpose, t.Rationale)
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			t.Name, t.Version, t.Purpose, t.Rationale, t.License, t.Vendor, t.SupportTier)
 	}
 	sb.WriteString("\n")
 }
 
+// hasSourcing reports whether any of techs records a license, vendor, or
+// support tier, which add columns to the technology tables.
+func hasSourcing(techs []Technology) bool {
+	for _, t := range techs {
+		if t.License != "" || t.Vendor != "" || t.SupportTier != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *Document) generateFrontmatter(opts MarkdownOptions) string {
 	var sb strings.Builder
 	sb.WriteString("---\n")
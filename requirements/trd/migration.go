@@ -0,0 +1,153 @@
+package trd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MigrationPlan is the plan for moving existing data and schemas to the new
+// design: the ordered steps, how to roll them back, and the operational
+// impact.
+type MigrationPlan struct {
+	Overview         string          `json:"overview,omitempty"`
+	DataVolume       string          `json:"dataVolume,omitempty"`       // e.g. "1.2 TB, 400M rows"
+	Downtime         string          `json:"downtime,omitempty"`         // Expected downtime, e.g. "none" or "15 minutes"
+	Window           string          `json:"window,omitempty"`           // Maintenance window, e.g. "Sun 02:00-04:00 UTC"
+	RollbackStrategy string          `json:"rollbackStrategy,omitempty"` // Overall approach, e.g. restore from snapshot
+	Steps            []MigrationStep `json:"steps,omitempty"`
+}
+
+// MigrationStep is one step of a migration plan.
+type MigrationStep struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"` // schema, data, backfill, cutover, cleanup
+
+	// Breaking is true for schema changes that existing readers or writers
+	// cannot tolerate, such as dropping or renaming a column. Breaking
+	// steps must declare Rollback steps.
+	Breaking bool `json:"breaking,omitempty"`
+
+	Duration     string   `json:"duration,omitempty"`
+	Owner        string   `json:"owner,omitempty"`
+	Verification string   `json:"verification,omitempty"` // How success is checked
+	Rollback     []string `json:"rollback,omitempty"`     // Ordered rollback steps
+}
+
+// BreakingSteps returns the steps marked breaking.
+func (p *MigrationPlan) BreakingSteps() []MigrationStep {
+	var steps []MigrationStep
+	for _, s := range p.Steps {
+		if s.Breaking {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// ValidateMigrationPlan checks that step IDs are unique and that breaking
+// steps declare rollback steps.
+func (d *Document) ValidateMigrationPlan() []Issue {
+	p := d.MigrationPlan
+	if p == nil {
+		return nil
+	}
+	var issues []Issue
+	seen := make(map[string]bool)
+	for i, s := range p.Steps {
+		if s.ID != "" && seen[s.ID] {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("migrationPlan.steps[%d].id", i),
+				Message: fmt.Sprintf("duplicate migration step ID %q", s.ID),
+			})
+		}
+		seen[s.ID] = true
+		if s.Breaking && len(s.Rollback) == 0 {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("migrationPlan.steps[%d].rollback", i),
+				Message: fmt.Sprintf("breaking step %s declares no rollback steps", stepLabel(s)),
+			})
+		}
+	}
+	return issues
+}
+
+func stepLabel(s MigrationStep) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return fmt.Sprintf("%q", s.Name)
+}
+
+// writeMigrationPlan writes the migration plan as an operations runbook:
+// the impact summary, the ordered steps, and the rollback procedure of each
+// step that has one.
+func (d *Document) writeMigrationPlan(sb *strings.Builder, sectionNum int) {
+	p := d.MigrationPlan
+	sb.WriteString(fmt.Sprintf("## %d. Migration Plan\n\n", sectionNum))
+	if p.Overview != "" {
+		sb.WriteString(p.Overview + "\n\n")
+	}
+
+	sub := 1
+	if p.DataVolume != "" || p.Downtime != "" || p.Window != "" || p.RollbackStrategy != "" {
+		sb.WriteString(fmt.Sprintf("### %d.%d Operational Impact\n\n", sectionNum, sub))
+		sb.WriteString("| Attribute | Value |\n")
+		sb.WriteString("|-----------|-------|\n")
+		for _, row := range [][2]string{
+			{"Data Volume", p.DataVolume},
+			{"Expected Downtime", p.Downtime},
+			{"Maintenance Window", p.Window},
+			{"Rollback Strategy", p.RollbackStrategy},
+		} {
+			if row[1] != "" {
+				sb.WriteString(fmt.Sprintf("| **%s** | %s |\n", row[0], row[1]))
+			}
+		}
+		if n := len(p.BreakingSteps()); n > 0 {
+			sb.WriteString(fmt.Sprintf("| **Breaking Steps** | %d of %d |\n", n, len(p.Steps)))
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if len(p.Steps) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Steps\n\n", sectionNum, sub))
+		sb.WriteString("| # | ID | Step | Type | Breaking | Duration | Owner | Verification |\n")
+		sb.WriteString("|---|----|------|------|:--------:|----------|-------|--------------|\n")
+		for i, s := range p.Steps {
+			breaking := ""
+			if s.Breaking {
+				breaking = "⚠️ Yes"
+			}
+			name := s.Name
+			if s.Description != "" {
+				name += ": " + s.Description
+			}
+			sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s | %s | %s |\n",
+				i+1, s.ID, name, s.Type, breaking, s.Duration, s.Owner, s.Verification))
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	var withRollback []MigrationStep
+	for _, s := range p.Steps {
+		if len(s.Rollback) > 0 {
+			withRollback = append(withRollback, s)
+		}
+	}
+	if len(withRollback) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Rollback Procedures\n\n", sectionNum, sub))
+		for _, s := range withRollback {
+			sb.WriteString(fmt.Sprintf("**%s**\n\n", strings.TrimSpace(s.ID+" "+s.Name)))
+			for i, r := range s.Rollback {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, r))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("---\n\n")
+}
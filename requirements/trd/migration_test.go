@@ -0,0 +1,66 @@
+package trd
+
+import (
+	"strings"
+	"testing"
+)
+
+func migrationDocument() Document {
+	return Document{MigrationPlan: &MigrationPlan{
+		DataVolume:       "1.2 TB",
+		Downtime:         "none",
+		RollbackStrategy: "Restore from the pre-migration snapshot",
+		Steps: []MigrationStep{
+			{ID: "M-1", Name: "Add tenant_id column", Type: "schema"},
+			{ID: "M-2", Name: "Drop legacy_org column", Type: "schema", Breaking: true},
+			{ID: "M-3", Name: "Rename org to tenant", Type: "schema", Breaking: true,
+				Rollback: []string{"Rename tenant to org", "Redeploy the previous release"}},
+			{ID: "M-1", Name: "Backfill tenant_id", Type: "backfill"},
+		},
+	}}
+}
+
+func TestValidateMigrationPlan(t *testing.T) {
+	doc := migrationDocument()
+	got := doc.ValidateMigrationPlan()
+	want := []string{
+		"migrationPlan.steps[1].rollback",
+		"migrationPlan.steps[3].id",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ValidateMigrationPlan = %+v, want fields %v", got, want)
+	}
+	for i, f := range want {
+		if got[i].Field != f {
+			t.Errorf("issue %d field = %q, want %q", i, got[i].Field, f)
+		}
+	}
+
+	if issues := (&Document{}).ValidateMigrationPlan(); issues != nil {
+		t.Errorf("ValidateMigrationPlan without a plan = %+v", issues)
+	}
+}
+
+func TestWriteMigrationPlan(t *testing.T) {
+	doc := migrationDocument()
+	var sb strings.Builder
+	doc.writeMigrationPlan(&sb, 9)
+	got := sb.String()
+	for _, want := range []string{
+		"## 9. Migration Plan",
+		"### 9.1 Operational Impact",
+		"| **Expected Downtime** | none |",
+		"| **Breaking Steps** | 2 of 4 |",
+		"### 9.2 Steps",
+		"| 3 | M-3 | Rename org to tenant | schema | ⚠️ Yes |",
+		"### 9.3 Rollback Procedures",
+		"**M-3 Rename org to tenant**\n\n1. Rename tenant to org\n2. Redeploy the previous release\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("migration plan missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "**M-2") {
+		t.Errorf("step without rollback listed under rollback procedures:\n%s", got)
+	}
+}
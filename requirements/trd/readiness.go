@@ -0,0 +1,330 @@
+package trd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OperationalReadiness describes how the system will be operated once it
+// launches: who is on call, what alerts and dashboards exist, how capacity
+// is planned, and how incidents are handled.
+type OperationalReadiness struct {
+	OnCall       *OnCall       `json:"onCall,omitempty"`
+	Alerts       []Alert       `json:"alerts,omitempty"`
+	Dashboards   []Dashboard   `json:"dashboards,omitempty"`
+	CapacityPlan *CapacityPlan `json:"capacityPlan,omitempty"`
+	Playbooks    []Playbook    `json:"playbooks,omitempty"`
+}
+
+// OnCall identifies who responds to pages for the system.
+type OnCall struct {
+	Owner      string `json:"owner"`                // Team or person accountable for the system
+	Rotation   string `json:"rotation,omitempty"`   // e.g. "weekly, follow-the-sun"
+	Escalation string `json:"escalation,omitempty"` // Escalation policy or path
+	Channel    string `json:"channel,omitempty"`    // Paging or chat channel
+}
+
+// Alert is a production alert.
+type Alert struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Condition string `json:"condition"`          // e.g. "p99 latency > 500ms for 5m"
+	Severity  string `json:"severity,omitempty"` // page, ticket, info
+
+	// PlaybookID is the ID of the playbook responders follow when the
+	// alert fires.
+	PlaybookID string `json:"playbookId,omitempty"`
+}
+
+// Dashboard is a monitoring dashboard for the system.
+type Dashboard struct {
+	Name        string `json:"name"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CapacityPlan records expected load and how the system scales to meet it.
+type CapacityPlan struct {
+	ExpectedLoad   string `json:"expectedLoad,omitempty"`   // e.g. "2k RPS steady state"
+	PeakLoad       string `json:"peakLoad,omitempty"`       // e.g. "10k RPS during sales"
+	Headroom       string `json:"headroom,omitempty"`       // e.g. "3x peak"
+	ScalingTrigger string `json:"scalingTrigger,omitempty"` // e.g. "CPU > 60% for 10m"
+	ReviewCadence  string `json:"reviewCadence,omitempty"`  // e.g. "quarterly"
+}
+
+// Playbook is an incident response playbook.
+type Playbook struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Trigger string   `json:"trigger,omitempty"` // When to use the playbook
+	Owner   string   `json:"owner,omitempty"`
+	Steps   []string `json:"steps,omitempty"` // Ordered response steps
+}
+
+// ReadinessCheck is one item of the operational readiness checklist.
+type ReadinessCheck struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"` // What is missing when the check fails
+}
+
+// ReadinessReport is the result of an operational readiness check.
+type ReadinessReport struct {
+	Score  float64          `json:"score"` // 0-100, the share of checks passed
+	Grade  string           `json:"grade"` // A, B, C, D, F
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+// Passed returns the number of checks that passed.
+func (r ReadinessReport) Passed() int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckReadiness scores the operational readiness of the system against a
+// fixed checklist. Each check carries equal weight; a TRD without an
+// operational readiness section scores zero.
+func (d *Document) CheckReadiness() ReadinessReport {
+	r := d.OperationalReadiness
+	if r == nil {
+		r = &OperationalReadiness{}
+	}
+	var checks []ReadinessCheck
+	check := func(id, name string, passed bool, detail string) {
+		c := ReadinessCheck{ID: id, Name: name, Passed: passed}
+		if !passed {
+			c.Detail = detail
+		}
+		checks = append(checks, c)
+	}
+
+	check("on-call", "On-call owner", r.OnCall != nil && r.OnCall.Owner != "",
+		"name the team or person who is paged for the system")
+	check("escalation", "Escalation path", r.OnCall != nil && r.OnCall.Escalation != "",
+		"describe how incidents escalate beyond the primary on-call")
+	check("alerts", "Alerts", len(r.Alerts) > 0,
+		"define at least one alert on a user-facing symptom")
+
+	var unlinked []string
+	for _, a := range r.Alerts {
+		if a.PlaybookID == "" {
+			unlinked = append(unlinked, alertLabel(a))
+		}
+	}
+	check("alert-playbooks", "Alerts linked to playbooks", len(r.Alerts) > 0 && len(unlinked) == 0,
+		alertPlaybooksDetail(unlinked))
+
+	check("dashboards", "Dashboards", len(r.Dashboards) > 0,
+		"link at least one dashboard that shows the system's health")
+	cp := r.CapacityPlan
+	check("capacity", "Capacity plan", cp != nil && cp.PeakLoad != "" && (cp.Headroom != "" || cp.ScalingTrigger != ""),
+		"state the peak load and the headroom or scaling trigger that covers it")
+
+	withSteps := 0
+	for _, p := range r.Playbooks {
+		if len(p.Steps) > 0 {
+			withSteps++
+		}
+	}
+	check("playbooks", "Incident playbooks", withSteps > 0,
+		"write at least one incident playbook with response steps")
+
+	report := ReadinessReport{Checks: checks}
+	report.Score = float64(report.Passed()) / float64(len(checks)) * 100
+	report.Grade = readinessGrade(report.Score)
+	return report
+}
+
+func alertPlaybooksDetail(unlinked []string) string {
+	if len(unlinked) == 0 {
+		return "define alerts and the playbooks responders follow"
+	}
+	return "set playbookId on " + strings.Join(unlinked, ", ")
+}
+
+func readinessGrade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// FormatReport returns the readiness report as a plain-text checklist.
+func (r ReadinessReport) FormatReport() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Operational Readiness: %.1f%% (Grade: %s)\n", r.Score, r.Grade))
+	sb.WriteString(fmt.Sprintf("Checks passed: %d/%d\n\n", r.Passed(), len(r.Checks)))
+	for _, c := range r.Checks {
+		icon := "✗"
+		if c.Passed {
+			icon = "✓"
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s\n", icon, c.Name))
+		if c.Detail != "" {
+			sb.WriteString(fmt.Sprintf("      %s\n", c.Detail))
+		}
+	}
+	return sb.String()
+}
+
+// ValidateReadiness checks that alert and playbook IDs are unique and that
+// alerts reference playbooks that exist.
+func (d *Document) ValidateReadiness() []Issue {
+	r := d.OperationalReadiness
+	if r == nil {
+		return nil
+	}
+	var issues []Issue
+	playbooks := make(map[string]bool)
+	for i, p := range r.Playbooks {
+		if p.ID != "" && playbooks[p.ID] {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("operationalReadiness.playbooks[%d].id", i),
+				Message: fmt.Sprintf("duplicate playbook ID %q", p.ID),
+			})
+		}
+		playbooks[p.ID] = true
+	}
+	alerts := make(map[string]bool)
+	for i, a := range r.Alerts {
+		if a.ID != "" && alerts[a.ID] {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("operationalReadiness.alerts[%d].id", i),
+				Message: fmt.Sprintf("duplicate alert ID %q", a.ID),
+			})
+		}
+		alerts[a.ID] = true
+		if a.PlaybookID != "" && !playbooks[a.PlaybookID] {
+			issues = append(issues, Issue{
+				Field:   fmt.Sprintf("operationalReadiness.alerts[%d].playbookId", i),
+				Message: fmt.Sprintf("alert %s references unknown playbook %q", alertLabel(a), a.PlaybookID),
+			})
+		}
+	}
+	return issues
+}
+
+func alertLabel(a Alert) string {
+	if a.ID != "" {
+		return a.ID
+	}
+	return fmt.Sprintf("%q", a.Name)
+}
+
+// writeReadiness writes the operational readiness section: the checklist
+// result followed by on-call, alerts, dashboards, capacity, and playbooks.
+func (d *Document) writeReadiness(sb *strings.Builder, sectionNum int) {
+	r := d.OperationalReadiness
+	report := d.CheckReadiness()
+	sb.WriteString(fmt.Sprintf("## %d. Operational Readiness\n\n", sectionNum))
+	sb.WriteString(fmt.Sprintf("**Readiness:** %.0f%% (%d/%d checks passed)\n\n", report.Score, report.Passed(), len(report.Checks)))
+	for _, c := range report.Checks {
+		box := " "
+		if c.Passed {
+			box = "x"
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", box, c.Name))
+	}
+	sb.WriteString("\n")
+
+	sub := 1
+	if oc := r.OnCall; oc != nil {
+		sb.WriteString(fmt.Sprintf("### %d.%d On-Call\n\n", sectionNum, sub))
+		sb.WriteString("| Attribute | Value |\n")
+		sb.WriteString("|-----------|-------|\n")
+		for _, row := range [][2]string{
+			{"Owner", oc.Owner},
+			{"Rotation", oc.Rotation},
+			{"Escalation", oc.Escalation},
+			{"Channel", oc.Channel},
+		} {
+			if row[1] != "" {
+				sb.WriteString(fmt.Sprintf("| **%s** | %s |\n", row[0], row[1]))
+			}
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if len(r.Alerts) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Alerts\n\n", sectionNum, sub))
+		sb.WriteString("| ID | Alert | Condition | Severity | Playbook |\n")
+		sb.WriteString("|----|-------|-----------|----------|----------|\n")
+		for _, a := range r.Alerts {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				a.ID, a.Name, a.Condition, a.Severity, a.PlaybookID))
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if len(r.Dashboards) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Dashboards\n\n", sectionNum, sub))
+		for _, db := range r.Dashboards {
+			name := db.Name
+			if db.URL != "" {
+				name = fmt.Sprintf("[%s](%s)", db.Name, db.URL)
+			}
+			if db.Description != "" {
+				name += " - " + db.Description
+			}
+			sb.WriteString("- " + name + "\n")
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if cp := r.CapacityPlan; cp != nil {
+		sb.WriteString(fmt.Sprintf("### %d.%d Capacity Plan\n\n", sectionNum, sub))
+		sb.WriteString("| Attribute | Value |\n")
+		sb.WriteString("|-----------|-------|\n")
+		for _, row := range [][2]string{
+			{"Expected Load", cp.ExpectedLoad},
+			{"Peak Load", cp.PeakLoad},
+			{"Headroom", cp.Headroom},
+			{"Scaling Trigger", cp.ScalingTrigger},
+			{"Review Cadence", cp.ReviewCadence},
+		} {
+			if row[1] != "" {
+				sb.WriteString(fmt.Sprintf("| **%s** | %s |\n", row[0], row[1]))
+			}
+		}
+		sb.WriteString("\n")
+		sub++
+	}
+
+	if len(r.Playbooks) > 0 {
+		sb.WriteString(fmt.Sprintf("### %d.%d Incident Playbooks\n\n", sectionNum, sub))
+		for _, p := range r.Playbooks {
+			sb.WriteString(fmt.Sprintf("**%s**\n\n", strings.TrimSpace(p.ID+" "+p.Name)))
+			if p.Trigger != "" {
+				sb.WriteString(fmt.Sprintf("*Trigger:* %s\n\n", p.Trigger))
+			}
+			if p.Owner != "" {
+				sb.WriteString(fmt.Sprintf("*Owner:* %s\n\n", p.Owner))
+			}
+			for i, s := range p.Steps {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, s))
+			}
+			if len(p.Steps) > 0 {
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	sb.WriteString("---\n\n")
+}
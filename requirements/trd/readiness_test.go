@@ -0,0 +1,92 @@
+package trd
+
+import (
+	"strings"
+	"testing"
+)
+
+func readinessDocument() Document {
+	return Document{OperationalReadiness: &OperationalReadiness{
+		OnCall: &OnCall{Owner: "Payments SRE"},
+		Alerts: []Alert{
+			{ID: "A-1", Name: "Error rate", Condition: "5xx > 1% for 5m", PlaybookID: "PB-1"},
+			{ID: "A-2", Name: "Queue depth", Condition: "depth > 10k"},
+			{ID: "A-1", Name: "Latency", Condition: "p99 > 500ms", PlaybookID: "PB-9"},
+		},
+		CapacityPlan: &CapacityPlan{PeakLoad: "5k RPS", Headroom: "2x"},
+		Playbooks: []Playbook{
+			{ID: "PB-1", Name: "Error spike", Steps: []string{"Roll back the last deploy"}},
+		},
+	}}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	doc := readinessDocument()
+	report := doc.CheckReadiness()
+
+	failed := map[string]string{}
+	for _, c := range report.Checks {
+		if !c.Passed {
+			failed[c.ID] = c.Detail
+		}
+	}
+	if len(failed) != 3 || failed["escalation"] == "" || failed["dashboards"] == "" {
+		t.Errorf("failed checks = %v", failed)
+	}
+	if d := failed["alert-playbooks"]; d != "set playbookId on A-2" {
+		t.Errorf("alert-playbooks detail = %q", d)
+	}
+	if report.Passed() != 4 || report.Grade != "F" || report.Score < 57 || report.Score > 58 {
+		t.Errorf("report = %.1f%% (%s), %d passed", report.Score, report.Grade, report.Passed())
+	}
+
+	empty := (&Document{}).CheckReadiness()
+	if empty.Score != 0 || empty.Passed() != 0 || len(empty.Checks) != len(report.Checks) {
+		t.Errorf("readiness without a section = %+v", empty)
+	}
+}
+
+func TestValidateReadiness(t *testing.T) {
+	doc := readinessDocument()
+	got := doc.ValidateReadiness()
+	want := []string{
+		"operationalReadiness.alerts[2].id",
+		"operationalReadiness.alerts[2].playbookId",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ValidateReadiness = %+v, want fields %v", got, want)
+	}
+	for i, f := range want {
+		if got[i].Field != f {
+			t.Errorf("issue %d field = %q, want %q", i, got[i].Field, f)
+		}
+	}
+
+	if issues := (&Document{}).ValidateReadiness(); issues != nil {
+		t.Errorf("ValidateReadiness without a section = %+v", issues)
+	}
+}
+
+func TestWriteReadiness(t *testing.T) {
+	doc := readinessDocument()
+	var sb strings.Builder
+	doc.writeReadiness(&sb, 10)
+	got := sb.String()
+	for _, want := range []string{
+		"## 10. Operational Readiness",
+		"**Readiness:** 57% (4/7 checks passed)",
+		"- [x] On-call owner",
+		"- [ ] Dashboards",
+		"### 10.1 On-Call",
+		"| A-1 | Error rate | 5xx > 1% for 5m |  | PB-1 |",
+		"### 10.3 Capacity Plan",
+		"**PB-1 Error spike**\n\n1. Roll back the last deploy",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Dashboards\n\n-") {
+		t.Errorf("empty dashboards subsection written:\n%s", got)
+	}
+}
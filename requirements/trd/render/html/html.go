@@ -0,0 +1,551 @@
+// Package html provides a standalone HTML renderer for TRD documents.
+package html
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// Renderer renders a TRD as a single self-contained HTML page.
+type Renderer struct{}
+
+// New creates a new TRD HTML renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "html"
+}
+
+// FileExtension returns the file extension for HTML output.
+func (r *Renderer) FileExtension() string {
+	return ".html"
+}
+
+// Render converts a TRD to a standalone HTML page.
+func (r *Renderer) Render(doc *trd.Document, opts *htmldoc.Options) ([]byte, error) {
+	return r.Page(doc, opts).Render()
+}
+
+// Page lays out a TRD as a page without rendering it, so other formats
+// can reuse the sections.
+func (r *Renderer) Page(doc *trd.Document, opts *htmldoc.Options) *htmldoc.Page {
+	if opts == nil {
+		opts = &htmldoc.Options{}
+	}
+	m := doc.Metadata
+	page := htmldoc.NewPage("Technical Requirements", m.Title)
+	page.Summary = doc.ExecutiveSummary.Purpose
+	page.CustomCSS = opts.CustomCSS
+	page.KaTeXURL = opts.KaTeXURL
+	page.Meta = []htmldoc.Field{
+		{Label: "ID", Value: m.ID},
+		{Label: "Version", Value: m.Version},
+		{Label: "Status", Value: string(m.Status)},
+		{Label: "Authors", Value: htmldoc.People(m.Authors)},
+		{Label: "Team", Value: m.Team},
+		{Label: "Updated", Value: htmldoc.Date(m.UpdatedAt)},
+		{Label: "Supersedes", Value: strings.Join(m.Supersedes, ", ")},
+	}
+	page.Banner = common.SupersededNotice("TRD", m.SupersededBy)
+	page.Links = m.ExternalRefs.Links(m.ExternalRefURLs)
+
+	page.AddSection("Executive Summary", executiveSummary(doc))
+	page.AddSection("Architecture", architecture(doc))
+	page.AddSection("Technology Stack", technologyStack(doc))
+	page.AddSection("APIs", apis(doc))
+	page.AddSection("Data Model", dataModel(doc))
+	page.AddSection("Security", security(doc))
+	page.AddSection("Performance", performance(doc))
+	page.AddSection("Scalability", scalability(doc))
+	page.AddSection("Deployment", deployment(doc))
+	page.AddSection("Migration Plan", migrationPlan(doc))
+	page.AddSection("Operational Readiness", readiness(doc))
+	page.AddSection("Integrations", integrations(doc))
+	page.AddSection("Configuration", configuration(doc))
+	page.AddSection("Testing", testing(doc))
+	page.AddSection("Risks", risks(doc))
+	page.AddSection("Constraints & Assumptions", constraints(doc))
+	glossary := htmldoc.NewBuilder("glossary")
+	glossary.Glossary(doc.Glossary)
+	page.AddSection("Glossary", glossary)
+	if opts.GlossaryLinks() {
+		page.Glossary = doc.Glossary
+	}
+
+	if opts.IncludeComments {
+		page.AddComments(doc.Comments, commentSections)
+	}
+	return page
+}
+
+// commentSections maps the top-level TRD fields that comment paths start
+// with to the sections that render them.
+var commentSections = map[string]string{
+	"executiveSummary":     "Executive Summary",
+	"architecture":         "Architecture",
+	"technologyStack":      "Technology Stack",
+	"apiSpecifications":    "APIs",
+	"dataModel":            "Data Model",
+	"securityDesign":       "Security",
+	"performance":          "Performance",
+	"scalability":          "Scalability",
+	"deployment":           "Deployment",
+	"migrationPlan":        "Migration Plan",
+	"operationalReadiness": "Operational Readiness",
+	"integrations":         "Integrations",
+	"configuration":        "Configuration",
+	"testing":              "Testing",
+	"risks":                "Risks",
+	"constraints":          "Constraints & Assumptions",
+	"assumptions":          "Constraints & Assumptions",
+	"glossary":             "Glossary",
+}
+
+func executiveSummary(doc *trd.Document) *htmldoc.Builder {
+	es := doc.ExecutiveSummary
+	b := htmldoc.NewBuilder("summary")
+	b.Labeled("Scope", es.Scope)
+	b.Labeled("Technical approach", es.TechnicalApproach)
+	b.List("Key decisions", es.KeyDecisions)
+	b.List("Out of scope", es.OutOfScope)
+	var related [][]string
+	for _, rd := range doc.Metadata.RelatedDocuments {
+		related = append(related, []string{rd.Title, rd.Relationship, rd.URL})
+	}
+	b.Table([]string{"Related Document", "Relationship", "URL"}, related)
+	return b
+}
+
+func architecture(doc *trd.Document) *htmldoc.Builder {
+	a := doc.Architecture
+	b := htmldoc.NewBuilder("architecture")
+	b.Paragraph(a.Overview)
+	b.List("Principles", a.Principles)
+	b.List("Patterns", a.Patterns)
+	var components [][]string
+	for _, c := range a.Components {
+		components = append(components, []string{c.ID, c.Name, c.Type, c.Technology, strings.Join(c.Requirements, ", ")})
+	}
+	if len(components) > 0 {
+		b.Heading("Components")
+		b.Table([]string{"ID", "Component", "Type", "Technology", "Implements"}, components)
+		for _, c := range a.Components {
+			b.ExternalRefs(c.ID, c.ExternalRefs, doc.Metadata.ExternalRefURLs)
+		}
+	}
+	for _, d := range a.Diagrams {
+		b.Link(d.Title, d.URL)
+	}
+	var flows [][]string
+	for _, f := range a.DataFlows {
+		flows = append(flows, []string{f.Name, f.Source, f.Destination, f.Protocol})
+	}
+	if len(flows) > 0 {
+		b.Heading("Data Flows")
+		b.Table([]string{"Flow", "Source", "Destination", "Protocol"}, flows)
+	}
+	for _, adr := range a.ArchDecisions {
+		b.Heading(strings.TrimSpace(adr.ID + " " + adr.Title))
+		b.Fields(
+			htmldoc.Field{Label: "Status", Value: adr.Status},
+			htmldoc.Field{Label: "Date", Value: adr.Date},
+		)
+		b.Labeled("Context", adr.Context)
+		b.Labeled("Decision", adr.Decision)
+		b.List("Consequences", adr.Consequences)
+		b.List("Alternatives", adr.Alternatives)
+	}
+	return b
+}
+
+func technologyStack(doc *trd.Document) *htmldoc.Builder {
+	ts := doc.TechnologyStack
+	b := htmldoc.NewBuilder("stack")
+	var rows [][]string
+	sourcing := false
+	for _, layer := range []struct {
+		name  string
+		techs []trd.Technology
+	}{
+		{"Languages", ts.Languages},
+		{"Frameworks", ts.Frameworks},
+		{"Databases", ts.Databases},
+		{"Message queues", ts.MessageQueues},
+		{"Caching", ts.Caching},
+		{"Infrastructure", ts.Infrastructure},
+		{"Monitoring", ts.Monitoring},
+		{"CI/CD", ts.CICD},
+		{"Other", ts.Other},
+	} {
+		for _, t := range layer.techs {
+			rows = append(rows, []string{layer.name, strings.TrimSpace(t.Name + " " + t.Version), t.Purpose, t.Rationale,
+				t.License, t.Vendor, t.SupportTier})
+			sourcing = sourcing || t.License != "" || t.Vendor != "" || t.SupportTier != ""
+		}
+	}
+	header := []string{"Layer", "Technology", "Purpose", "Rationale", "License", "Vendor", "Support"}
+	if !sourcing {
+		// Leave out the license columns when no technology sets them.
+		header = header[:4]
+		for i := range rows {
+			rows[i] = rows[i][:4]
+		}
+	}
+	b.Table(header, rows)
+	return b
+}
+
+func apis(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("api")
+	for _, api := range doc.APISpecifications {
+		b.Heading(api.Name)
+		b.Paragraph(api.Description)
+		b.Fields(
+			htmldoc.Field{Label: "Type", Value: api.Type},
+			htmldoc.Field{Label: "Version", Value: api.Version},
+			htmldoc.Field{Label: "Base URL", Value: api.BaseURL},
+			htmldoc.Field{Label: "Auth", Value: api.Auth},
+			htmldoc.Field{Label: "Rate limit", Value: api.RateLimit},
+		)
+		b.Link("Specification", api.SpecURL)
+		var rows [][]string
+		for _, e := range api.Endpoints {
+			rows = append(rows, []string{e.Method, e.Path, e.Description})
+		}
+		b.Table([]string{"Method", "Path", "Description"}, rows)
+	}
+	return b
+}
+
+func dataModel(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("data")
+	dm := doc.DataModel
+	if dm == nil {
+		return b
+	}
+	b.Paragraph(dm.Overview)
+	for _, e := range dm.Entities {
+		b.Heading(e.Name)
+		b.Paragraph(e.Description)
+		var rows [][]string
+		for _, a := range e.Attributes {
+			required := ""
+			if a.Required {
+				required = "yes"
+			}
+			rows = append(rows, []string{a.Name, a.Type, required, a.Description})
+		}
+		b.Table([]string{"Attribute", "Type", "Required", "Description"}, rows)
+	}
+	var rels [][]string
+	for _, r := range dm.AllRelationships() {
+		rels = append(rels, []string{entityName(dm, r.From), entityName(dm, r.To), r.Cardinality, cmp.Or(r.Description, r.Label)})
+	}
+	if len(rels) > 0 {
+		b.Heading("Relationships")
+		b.Table([]string{"From", "To", "Cardinality", "Description"}, rels)
+	}
+	var stores [][]string
+	for _, s := range dm.DataStores {
+		stores = append(stores, []string{s.Name, s.Type, s.Purpose})
+	}
+	if len(stores) > 0 {
+		b.Heading("Data Stores")
+		b.Table([]string{"Store", "Type", "Purpose"}, stores)
+	}
+	b.Labeled("Migrations", dm.Migrations)
+	return b
+}
+
+// entityName returns the name of the entity ref refers to, or ref itself.
+func entityName(dm *trd.DataModel, ref string) string {
+	if e := dm.Entity(ref); e != nil && e.Name != "" {
+		return e.Name
+	}
+	return ref
+}
+
+func security(doc *trd.Document) *htmldoc.Builder {
+	sd := doc.SecurityDesign
+	b := htmldoc.NewBuilder("security")
+	b.Paragraph(sd.Overview)
+	var fields []htmldoc.Field
+	if sd.AuthN != nil {
+		fields = append(fields, htmldoc.Field{Label: "Authentication", Value: sd.AuthN.Method})
+	}
+	if sd.AuthZ != nil {
+		fields = append(fields, htmldoc.Field{Label: "Authorization", Value: sd.AuthZ.Model})
+	}
+	if sd.Encryption != nil {
+		fields = append(fields,
+			htmldoc.Field{Label: "Encryption at rest", Value: sd.Encryption.AtRest},
+			htmldoc.Field{Label: "Encryption in transit", Value: sd.Encryption.InTransit},
+		)
+	}
+	fields = append(fields, htmldoc.Field{Label: "Compliance", Value: strings.Join(sd.Compliance, ", ")})
+	b.Fields(fields...)
+	var threats [][]string
+	for _, t := range sd.ThreatModel {
+		threats = append(threats, []string{t.ID, t.Name, t.Category, t.Likelihood, t.Impact, t.Mitigation})
+	}
+	if len(threats) > 0 {
+		b.Heading("Threat Model")
+		b.Table([]string{"ID", "Threat", "Category", "Likelihood", "Impact", "Mitigation"}, threats)
+	}
+	var controls [][]string
+	for _, c := range sd.SecurityControls {
+		controls = append(controls, []string{c.ID, c.Name, c.Category, c.Description})
+	}
+	if len(controls) > 0 {
+		b.Heading("Security Controls")
+		b.Table([]string{"ID", "Control", "Category", "Description"}, controls)
+	}
+	return b
+}
+
+func performance(doc *trd.Document) *htmldoc.Builder {
+	p := doc.Performance
+	b := htmldoc.NewBuilder("performance")
+	b.Paragraph(p.Overview)
+	var rows [][]string
+	for _, r := range p.Requirements {
+		rows = append(rows, []string{r.ID, r.Name, r.Metric, r.Target, r.Priority})
+	}
+	b.Table([]string{"ID", "Requirement", "Metric", "Target", "Priority"}, rows)
+	var benchmarks [][]string
+	for _, bm := range p.Benchmarks {
+		benchmarks = append(benchmarks, []string{bm.Name, bm.Scenario, bm.Result, bm.Date})
+	}
+	if len(benchmarks) > 0 {
+		b.Heading("Benchmarks")
+		b.Table([]string{"Benchmark", "Scenario", "Result", "Date"}, benchmarks)
+	}
+	b.List("Optimizations", p.Optimizations)
+	return b
+}
+
+func scalability(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("scalability")
+	s := doc.Scalability
+	if s == nil {
+		return b
+	}
+	b.Paragraph(s.Overview)
+	b.Fields(
+		htmldoc.Field{Label: "Horizontal", Value: s.HorizontalScale},
+		htmldoc.Field{Label: "Vertical", Value: s.VerticalScale},
+		htmldoc.Field{Label: "Load balancing", Value: s.LoadBalancing},
+		htmldoc.Field{Label: "Auto-scaling", Value: s.AutoScaling},
+	)
+	var limits [][]string
+	for _, l := range s.Limits {
+		limits = append(limits, []string{l.Name, l.Value, l.Rationale})
+	}
+	b.Table([]string{"Limit", "Value", "Rationale"}, limits)
+	return b
+}
+
+func deployment(doc *trd.Document) *htmldoc.Builder {
+	d := doc.Deployment
+	b := htmldoc.NewBuilder("deployment")
+	b.Paragraph(d.Overview)
+	b.Fields(
+		htmldoc.Field{Label: "Strategy", Value: d.Strategy},
+		htmldoc.Field{Label: "Infrastructure", Value: d.Infrastructure},
+		htmldoc.Field{Label: "Regions", Value: strings.Join(d.Regions, ", ")},
+		htmldoc.Field{Label: "High availability", Value: d.HA},
+		htmldoc.Field{Label: "Disaster recovery", Value: d.DR},
+	)
+	var envs [][]string
+	for _, e := range d.Environments {
+		envs = append(envs, []string{e.Name, e.Purpose, e.URL})
+	}
+	b.Table([]string{"Environment", "Purpose", "URL"}, envs)
+	return b
+}
+
+func migrationPlan(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("migration")
+	p := doc.MigrationPlan
+	if p == nil {
+		return b
+	}
+	b.Paragraph(p.Overview)
+	b.Fields(
+		htmldoc.Field{Label: "Data volume", Value: p.DataVolume},
+		htmldoc.Field{Label: "Expected downtime", Value: p.Downtime},
+		htmldoc.Field{Label: "Maintenance window", Value: p.Window},
+		htmldoc.Field{Label: "Rollback strategy", Value: p.RollbackStrategy},
+	)
+	var rows [][]string
+	for i, s := range p.Steps {
+		breaking := ""
+		if s.Breaking {
+			breaking = "Yes"
+		}
+		rows = append(rows, []string{strconv.Itoa(i + 1), s.ID, s.Name, s.Type, breaking, s.Duration, s.Owner, s.Verification})
+	}
+	b.Table([]string{"#", "ID", "Step", "Type", "Breaking", "Duration", "Owner", "Verification"}, rows)
+	for _, s := range p.Steps {
+		if len(s.Rollback) > 0 {
+			b.List(strings.TrimSpace(s.ID+" "+s.Name)+" rollback", s.Rollback)
+		}
+	}
+	return b
+}
+
+func readiness(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("readiness")
+	r := doc.OperationalReadiness
+	if r == nil {
+		return b
+	}
+	report := doc.CheckReadiness()
+	var checks []string
+	for _, c := range report.Checks {
+		mark := "✗ "
+		if c.Passed {
+			mark = "✓ "
+		}
+		checks = append(checks, mark+c.Name)
+	}
+	b.Labeled("Readiness", strconv.FormatFloat(report.Score, 'f', 0, 64)+"% ("+
+		strconv.Itoa(report.Passed())+"/"+strconv.Itoa(len(report.Checks))+" checks passed)")
+	b.List("Checklist", checks)
+	if oc := r.OnCall; oc != nil {
+		b.Fields(
+			htmldoc.Field{Label: "On-call owner", Value: oc.Owner},
+			htmldoc.Field{Label: "Rotation", Value: oc.Rotation},
+			htmldoc.Field{Label: "Escalation", Value: oc.Escalation},
+			htmldoc.Field{Label: "Channel", Value: oc.Channel},
+		)
+	}
+	var alerts [][]string
+	for _, a := range r.Alerts {
+		alerts = append(alerts, []string{a.ID, a.Name, a.Condition, a.Severity, a.PlaybookID})
+	}
+	if len(alerts) > 0 {
+		b.Heading("Alerts")
+		b.Table([]string{"ID", "Alert", "Condition", "Severity", "Playbook"}, alerts)
+	}
+	if len(r.Dashboards) > 0 {
+		b.Heading("Dashboards")
+	}
+	for _, db := range r.Dashboards {
+		if db.URL != "" {
+			b.Link(db.Name, db.URL)
+		} else {
+			b.Paragraph(db.Name)
+		}
+	}
+	if cp := r.CapacityPlan; cp != nil {
+		b.Fields(
+			htmldoc.Field{Label: "Expected load", Value: cp.ExpectedLoad},
+			htmldoc.Field{Label: "Peak load", Value: cp.PeakLoad},
+			htmldoc.Field{Label: "Headroom", Value: cp.Headroom},
+			htmldoc.Field{Label: "Scaling trigger", Value: cp.ScalingTrigger},
+			htmldoc.Field{Label: "Capacity review", Value: cp.ReviewCadence},
+		)
+	}
+	for _, p := range r.Playbooks {
+		b.List(strings.TrimSpace(p.ID+" "+p.Name)+" playbook", p.Steps)
+	}
+	return b
+}
+
+func integrations(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("integrations")
+	var rows [][]string
+	for _, i := range doc.Integration {
+		rows = append(rows, []string{i.Name, i.Type, i.Direction, i.Protocol, i.Frequency})
+	}
+	b.Table([]string{"Integration", "Type", "Direction", "Protocol", "Frequency"}, rows)
+	return b
+}
+
+func configuration(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("configuration")
+	var rows [][]string
+	for _, c := range doc.ConfigurationBySecret() {
+		secret, required := "", ""
+		if c.Secret {
+			secret = "Yes"
+		}
+		if c.Required {
+			required = "Yes"
+		}
+		rows = append(rows, []string{c.Name, c.Purpose, secret, required, c.Owner, c.Source, c.Default, c.IntegrationID})
+	}
+	b.Table([]string{"Name", "Purpose", "Secret", "Required", "Owner", "Source", "Default", "Integration"}, rows)
+	return b
+}
+
+func testing(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("testing")
+	t := doc.Testing
+	if t == nil {
+		return b
+	}
+	b.Paragraph(t.Strategy)
+	b.Fields(
+		htmldoc.Field{Label: "Unit", Value: t.UnitTests},
+		htmldoc.Field{Label: "Integration", Value: t.Integration},
+		htmldoc.Field{Label: "End-to-end", Value: t.E2E},
+		htmldoc.Field{Label: "Performance", Value: t.Performance},
+		htmldoc.Field{Label: "Security", Value: t.Security},
+		htmldoc.Field{Label: "Coverage", Value: t.Coverage},
+		htmldoc.Field{Label: "Environments", Value: strings.Join(t.Environments, ", ")},
+	)
+	var cases [][]string
+	for _, tc := range t.TestCases {
+		cases = append(cases, []string{tc.ID, tc.Name, tc.Type, strings.Join(tc.Components, ", "), strings.Join(tc.Requirements, ", "), tc.Status})
+	}
+	if len(cases) > 0 {
+		b.Heading("Test Cases")
+		b.Table([]string{"ID", "Test", "Type", "Components", "Requirements", "Status"}, cases)
+	}
+	return b
+}
+
+func risks(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("risks")
+	var rows [][]string
+	for _, r := range doc.Risks {
+		rows = append(rows, []string{r.ID, r.Description, r.Probability, r.Impact, r.Mitigation, r.Status})
+	}
+	b.Table([]string{"ID", "Risk", "Probability", "Impact", "Mitigation", "Status"}, rows)
+	return b
+}
+
+func constraints(doc *trd.Document) *htmldoc.Builder {
+	b := htmldoc.NewBuilder("constraints")
+	var rows [][]string
+	for _, c := range doc.Constraints {
+		rows = append(rows, []string{c.ID, c.Type, c.Description, c.Impact})
+	}
+	if len(rows) > 0 {
+		b.Heading("Constraints")
+		b.Table([]string{"ID", "Type", "Constraint", "Impact"}, rows)
+	}
+	rows = nil
+	for _, a := range doc.Assumptions {
+		validated := "no"
+		if a.Validated {
+			validated = "yes"
+		}
+		rows = append(rows, []string{a.ID, a.Description, a.Risk, validated})
+	}
+	if len(rows) > 0 {
+		b.Heading("Assumptions")
+		b.Table([]string{"ID", "Assumption", "Risk if wrong", "Validated"}, rows)
+	}
+	return b
+}
@@ -0,0 +1,291 @@
+package trd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// TODOPrefix starts every placeholder written by ScaffoldFromPRD, so that
+// unfinished sections can be found with a text search.
+const TODOPrefix = "TODO:"
+
+func todo(what string) string {
+	return TODOPrefix + " " + what
+}
+
+// ScaffoldFromPRD returns a skeleton TRD for the product described by p. It
+// carries over the PRD's technical architecture, integration points,
+// performance and scalability NFRs, security model, assumptions,
+// constraints, external dependencies, and glossary, and fills required
+// fields that the PRD cannot supply with TODO placeholders. The second
+// result lists the JSON paths of the sections left as placeholders.
+func ScaffoldFromPRD(p *prd.Document) (*Document, []string) {
+	var todos []string
+	placeholder := func(path, what string) string {
+		todos = append(todos, path)
+		return todo(what)
+	}
+
+	now := time.Now()
+	d := &Document{
+		Metadata: Metadata{
+			ID:        scaffoldID(p.Metadata.ID),
+			Title:     p.Metadata.Title + " Technical Requirements",
+			Version:   "0.1.0",
+			Status:    StatusDraft,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Authors:   []Person{{Name: placeholder("metadata.authors", "engineering lead")}},
+			Tags:      p.Metadata.Tags,
+			RelatedDocuments: []RelatedDoc{{
+				Title:        p.Metadata.Title,
+				Relationship: "implements",
+				Description:  strings.TrimSpace("Product requirements " + p.Metadata.ID),
+			}},
+		},
+		ExecutiveSummary: ExecutiveSummary{
+			Purpose:    p.ExecutiveSummary.ProblemStatement,
+			Scope:      p.ExecutiveSummary.ProposedSolution,
+			OutOfScope: p.OutOfScope,
+		},
+		Glossary: p.Glossary,
+	}
+	if d.ExecutiveSummary.Purpose == "" {
+		d.ExecutiveSummary.Purpose = placeholder("executiveSummary.purpose", "why this system is being built")
+	}
+	if d.ExecutiveSummary.Scope == "" {
+		d.ExecutiveSummary.Scope = placeholder("executiveSummary.scope", "what the system covers")
+	}
+
+	ta := p.TechArchitecture
+	if ta == nil {
+		ta = &prd.TechnicalArchitecture{}
+	}
+	d.ExecutiveSummary.TechnicalApproach = ta.Overview
+	if d.ExecutiveSummary.TechnicalApproach == "" {
+		d.ExecutiveSummary.TechnicalApproach = placeholder("executiveSummary.technicalApproach", "summarize the technical approach")
+	}
+
+	d.Architecture = Architecture{
+		Overview: ta.Overview,
+		Components: []Component{{
+			ID:          "comp-1",
+			Name:        placeholder("architecture.components", "first component"),
+			Description: todo("responsibilities and boundaries"),
+		}},
+	}
+	if d.Architecture.Overview == "" {
+		d.Architecture.Overview = placeholder("architecture.overview", "describe the system architecture")
+	}
+	if ta.SystemDiagram != "" {
+		d.Architecture.Diagrams = []Diagram{{ID: "diagram-system", Title: "System Diagram", URL: ta.SystemDiagram}}
+	}
+	if ta.DataModel != "" {
+		d.DataModel = &DataModel{
+			Overview: placeholder("dataModel.overview", "describe the entities and data stores"),
+			Diagrams: []Diagram{{ID: "diagram-data-model", Title: "Data Model", Type: "ER", URL: ta.DataModel}},
+		}
+	}
+
+	d.TechnologyStack = scaffoldTechnologyStack(ta.TechnologyStack)
+
+	for _, ip := range ta.IntegrationPoints {
+		desc := ip.Description
+		if ip.RateLimit != "" {
+			desc = strings.TrimSpace(desc + " Rate limit: " + ip.RateLimit + ".")
+		}
+		d.Integration = append(d.Integration, Integration{
+			ID:            ip.ID,
+			Name:          ip.Name,
+			Type:          ip.Type,
+			Direction:     placeholder(fmt.Sprintf("integrations[%d].direction", len(d.Integration)), "inbound, outbound, or bidirectional"),
+			Protocol:      ip.Protocol,
+			AuthMethod:    ip.AuthMethod,
+			DataFormat:    ip.DataFormat,
+			Description:   desc,
+			Documentation: ip.Documentation,
+		})
+	}
+
+	d.SecurityDesign = scaffoldSecurityDesign(p.SecurityModel, ta.SecurityDesign, placeholder)
+
+	var availability []string
+	for _, nfr := range p.Requirements.NonFunctional {
+		switch nfr.Category {
+		case prd.NFRPerformance:
+			d.Performance.Requirements = append(d.Performance.Requirements, PerfRequirement{
+				ID:          nfr.ID,
+				Name:        nfr.Title,
+				Metric:      nfr.Metric,
+				Target:      nfr.Target,
+				Priority:    string(nfr.Priority),
+				Measurement: nfr.MeasurementMethod,
+				Tags:        nfr.Tags,
+			})
+		case prd.NFRScalability:
+			if d.Scalability == nil {
+				d.Scalability = &Scalability{}
+			}
+			d.Scalability.Limits = append(d.Scalability.Limits, Limit{Name: nfr.Title, Value: nfr.Target, Rationale: nfr.Description})
+		case prd.NFRAvailability, prd.NFRReliability, prd.NFRDisasterRecovery:
+			availability = append(availability, fmt.Sprintf("%s: %s %s", nfr.ID, nfr.Title, nfr.Target))
+		}
+	}
+	if len(d.Performance.Requirements) == 0 {
+		d.Performance.Requirements = []PerfRequirement{{
+			ID:     "PERF-1",
+			Name:   placeholder("performance.requirements", "performance requirement"),
+			Metric: todo("latency, throughput, etc."),
+			Target: todo("target value"),
+		}}
+	}
+	if ta.ScalabilityDesign != "" || d.Scalability != nil {
+		if d.Scalability == nil {
+			d.Scalability = &Scalability{}
+		}
+		d.Scalability.Overview = ta.ScalabilityDesign
+		if d.Scalability.Overview == "" {
+			d.Scalability.Overview = placeholder("scalability.overview", "how the system scales to the limits below")
+		}
+	}
+
+	d.Deployment = Deployment{
+		Overview: placeholder("deployment.overview", "describe the deployment architecture"),
+		Environments: []Environment{
+			{Name: "Development"},
+			{Name: "Staging"},
+			{Name: "Production"},
+		},
+		HA: strings.Join(availability, "; "),
+	}
+	d.Testing = &Testing{Strategy: placeholder("testing.strategy", "describe the testing strategy")}
+
+	if a := p.Assumptions; a != nil {
+		for _, as := range a.Assumptions {
+			d.Assumptions = append(d.Assumptions, Assumption{
+				ID: as.ID, Description: as.Description, Rationale: as.Rationale, Validated: as.Validated, Risk: as.Risk,
+			})
+		}
+		for _, c := range a.Constraints {
+			d.Constraints = append(d.Constraints, Constraint{
+				ID: c.ID, Type: string(c.Type), Description: c.Description, Impact: c.Impact, Rationale: c.Rationale, Tags: c.Tags,
+			})
+		}
+		d.Dependencies = a.Dependencies
+	}
+
+	return d, todos
+}
+
+// scaffoldID derives a TRD ID from a PRD ID, e.g. "PRD-2026-001" becomes
+// "TRD-2026-001" and "prd-checkout" becomes "trd-checkout".
+func scaffoldID(prdID string) string {
+	switch {
+	case prdID == "":
+		return "TRD-001"
+	case strings.HasPrefix(prdID, "PRD"):
+		return "TRD" + prdID[3:]
+	case strings.HasPrefix(prdID, "prd"):
+		return "trd" + prdID[3:]
+	}
+	return prdID + "-TRD"
+}
+
+// scaffoldTechnologyStack maps PRD technology categories onto the TRD's:
+// frontend and backend choices become frameworks and DevOps becomes CI/CD.
+func scaffoldTechnologyStack(ts prd.TechnologyStack) TechnologyStack {
+	convert := func(techs ...[]prd.Technology) []Technology {
+		var out []Technology
+		for _, group := range techs {
+			for _, t := range group {
+				purpose := t.Purpose
+				if purpose == "" {
+					purpose = todo("purpose")
+				}
+				out = append(out, Technology{
+					Name: t.Name, Version: t.Version, Purpose: purpose,
+					Rationale: t.Rationale, Alternatives: t.Alternatives, Cost: t.Cost,
+				})
+			}
+		}
+		return out
+	}
+	return TechnologyStack{
+		Frameworks:     convert(ts.Frontend, ts.Backend),
+		Databases:      convert(ts.Database),
+		Infrastructure: convert(ts.Infrastructure),
+		CICD:           convert(ts.DevOps),
+		Monitoring:     convert(ts.Monitoring),
+	}
+}
+
+func scaffoldSecurityDesign(sm *prd.SecurityModel, overview string, placeholder func(path, what string) string) SecurityDesign {
+	sd := SecurityDesign{Overview: overview}
+	if sm == nil {
+		if sd.Overview == "" {
+			sd.Overview = placeholder("securityDesign.overview", "describe the security architecture")
+		}
+		return sd
+	}
+	if sm.Overview != "" {
+		sd.Overview = sm.Overview
+	}
+	if sd.Overview == "" {
+		sd.Overview = placeholder("securityDesign.overview", "describe the security architecture")
+	}
+
+	sd.AuthN = &AuthN{Method: placeholder("securityDesign.authentication.method", "OAuth2, SAML, mTLS, API key, etc.")}
+
+	if ac := sm.AccessControl; ac.Model != "" {
+		sd.AuthZ = &AuthZ{Model: ac.Model, Policies: ac.Policies, Details: ac.Description}
+		for _, r := range ac.Roles {
+			sd.AuthZ.Roles = append(sd.AuthZ.Roles, r.Role)
+		}
+		for _, layer := range ac.Layers {
+			for _, c := range layer.Controls {
+				sd.SecurityControls = append(sd.SecurityControls, SecurityControl{
+					ID:          fmt.Sprintf("SC-%d", len(sd.SecurityControls)+1),
+					Name:        c,
+					Category:    layer.Layer,
+					Description: c,
+				})
+			}
+		}
+	}
+
+	if enc := sm.Encryption; enc.AtRest.Method != "" || enc.InTransit.Method != "" {
+		sd.Encryption = &Encryption{
+			AtRest:    enc.AtRest.Method,
+			InTransit: enc.InTransit.Method,
+			KeyMgmt:   enc.AtRest.KeyManagement,
+		}
+		if sd.Encryption.KeyMgmt == "" {
+			sd.Encryption.KeyMgmt = enc.InTransit.KeyManagement
+		}
+	}
+
+	for framework := range sm.ComplianceControls {
+		sd.Compliance = append(sd.Compliance, framework)
+	}
+	sort.Strings(sd.Compliance)
+
+	for i, t := range sm.ThreatModel.KeyThreats {
+		id := t.ID
+		if id == "" {
+			id = fmt.Sprintf("THREAT-%d", i+1)
+		}
+		sd.ThreatModel = append(sd.ThreatModel, Threat{
+			ID:          id,
+			Name:        t.Threat,
+			Category:    t.Category,
+			Description: t.Threat,
+			Impact:      t.Severity,
+			Mitigation:  t.Mitigation,
+		})
+	}
+	return sd
+}
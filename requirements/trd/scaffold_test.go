@@ -0,0 +1,128 @@
+package trd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+func TestScaffoldFromPRD(t *testing.T) {
+	p := &prd.Document{
+		Metadata:         prd.Metadata{ID: "PRD-2026-007", Title: "Payments"},
+		ExecutiveSummary: prd.ExecutiveSummary{ProblemStatement: "Checkout drops 30% of carts"},
+		OutOfScope:       []string{"Crypto"},
+		Requirements: prd.Requirements{NonFunctional: []prd.NonFunctionalRequirement{
+			{ID: "NFR-1", Category: prd.NFRPerformance, Title: "Checkout latency", Metric: "p95", Target: "< 300ms", Priority: prd.MoSCoWMust},
+			{ID: "NFR-2", Category: prd.NFRScalability, Title: "Peak TPS", Target: "2000"},
+			{ID: "NFR-3", Category: prd.NFRAvailability, Title: "Uptime", Target: "99.95%"},
+			{ID: "NFR-4", Category: prd.NFRUsability, Title: "Ignored"},
+		}},
+		TechArchitecture: &prd.TechnicalArchitecture{
+			Overview:      "Event-driven payment service",
+			SystemDiagram: "diagrams/system.drawio",
+			IntegrationPoints: []prd.Integration{
+				{ID: "INT-1", Name: "Stripe", Type: "REST API", Protocol: "HTTPS", RateLimit: "100 rps"},
+			},
+			TechnologyStack: prd.TechnologyStack{
+				Backend:  []prd.Technology{{Name: "Go", Purpose: "Service"}},
+				Database: []prd.Technology{{Name: "PostgreSQL"}},
+				DevOps:   []prd.Technology{{Name: "GitHub Actions", Purpose: "CI"}},
+			},
+		},
+		SecurityModel: &prd.SecurityModel{
+			Overview:           "Zero trust",
+			AccessControl:      prd.AccessControl{Model: "RBAC", Roles: []prd.SecurityRole{{Role: "admin"}}, Layers: []prd.AccessControlLayer{{Layer: "API", Controls: []string{"JWT validation"}}}},
+			Encryption:         prd.EncryptionRequirements{AtRest: prd.EncryptionSpec{Method: "AES-256", KeyManagement: "KMS"}, InTransit: prd.EncryptionSpec{Method: "TLS 1.3"}},
+			ComplianceControls: map[string][]string{"SOC2": {"CC6.1"}, "PCI-DSS": {"3.4"}},
+			ThreatModel:        prd.ThreatModel{KeyThreats: []prd.SecurityThreat{{Threat: "Card skimming", Severity: "high", Mitigation: "Tokenization"}}},
+		},
+		Assumptions: &prd.AssumptionsConstraints{
+			Constraints:  []prd.Constraint{{ID: "C-1", Type: common.ConstraintTechnical, Description: "Must run on AWS"}},
+			Dependencies: []prd.Dependency{{ID: "D-1", Name: "Fraud API"}},
+		},
+	}
+
+	d, todos := ScaffoldFromPRD(p)
+
+	if d.Metadata.ID != "TRD-2026-007" || d.Metadata.Title != "Payments Technical Requirements" {
+		t.Errorf("metadata = %+v", d.Metadata)
+	}
+	if d.ExecutiveSummary.Purpose != "Checkout drops 30% of carts" || d.ExecutiveSummary.TechnicalApproach != "Event-driven payment service" {
+		t.Errorf("executive summary = %+v", d.ExecutiveSummary)
+	}
+	if len(d.Architecture.Diagrams) != 1 || d.Architecture.Diagrams[0].URL != "diagrams/system.drawio" {
+		t.Errorf("diagrams = %+v", d.Architecture.Diagrams)
+	}
+	if len(d.Integration) != 1 || d.Integration[0].Description != "Rate limit: 100 rps." || !strings.HasPrefix(d.Integration[0].Direction, TODOPrefix) {
+		t.Errorf("integrations = %+v", d.Integration)
+	}
+	ts := d.TechnologyStack
+	if len(ts.Frameworks) != 1 || len(ts.CICD) != 1 || len(ts.Databases) != 1 || !strings.HasPrefix(ts.Databases[0].Purpose, TODOPrefix) {
+		t.Errorf("technology stack = %+v", ts)
+	}
+
+	sd := d.SecurityDesign
+	if sd.Overview != "Zero trust" || sd.AuthZ == nil || sd.AuthZ.Roles[0] != "admin" || len(sd.SecurityControls) != 1 {
+		t.Errorf("security design = %+v", sd)
+	}
+	if sd.Encryption == nil || sd.Encryption.KeyMgmt != "KMS" || strings.Join(sd.Compliance, ",") != "PCI-DSS,SOC2" {
+		t.Errorf("encryption/compliance = %+v %v", sd.Encryption, sd.Compliance)
+	}
+	if len(sd.ThreatModel) != 1 || sd.ThreatModel[0].ID != "THREAT-1" || sd.ThreatModel[0].Impact != "high" {
+		t.Errorf("threat model = %+v", sd.ThreatModel)
+	}
+
+	if len(d.Performance.Requirements) != 1 || d.Performance.Requirements[0].Priority != "must" {
+		t.Errorf("performance = %+v", d.Performance)
+	}
+	if d.Scalability == nil || len(d.Scalability.Limits) != 1 || !strings.HasPrefix(d.Scalability.Overview, TODOPrefix) {
+		t.Errorf("scalability = %+v", d.Scalability)
+	}
+	if d.Deployment.HA != "NFR-3: Uptime 99.95%" {
+		t.Errorf("HA = %q", d.Deployment.HA)
+	}
+	if len(d.Constraints) != 1 || d.Constraints[0].Type != "technical" || len(d.Dependencies) != 1 {
+		t.Errorf("constraints/dependencies = %+v %+v", d.Constraints, d.Dependencies)
+	}
+
+	for _, want := range []string{"architecture.components", "integrations[0].direction", "securityDesign.authentication.method", "deployment.overview", "testing.strategy"} {
+		found := false
+		for _, path := range todos {
+			found = found || path == want
+		}
+		if !found {
+			t.Errorf("todos missing %q: %v", want, todos)
+		}
+	}
+}
+
+func TestScaffoldFromPRDMinimal(t *testing.T) {
+	d, todos := ScaffoldFromPRD(&prd.Document{Metadata: prd.Metadata{ID: "checkout", Title: "Checkout"}})
+	if d.Metadata.ID != "checkout-TRD" {
+		t.Errorf("ID = %q", d.Metadata.ID)
+	}
+	if !strings.HasPrefix(d.SecurityDesign.Overview, TODOPrefix) || !strings.HasPrefix(d.Performance.Requirements[0].Name, TODOPrefix) {
+		t.Errorf("expected placeholders: %+v", d)
+	}
+	if d.Scalability != nil || d.DataModel != nil {
+		t.Error("optional sections should be omitted when the PRD has nothing for them")
+	}
+	if len(todos) < 8 {
+		t.Errorf("todos = %v", todos)
+	}
+}
+
+func TestScaffoldID(t *testing.T) {
+	for in, want := range map[string]string{
+		"PRD-2026-001": "TRD-2026-001",
+		"prd-checkout": "trd-checkout",
+		"checkout":     "checkout-TRD",
+		"":             "TRD-001",
+	} {
+		if got := scaffoldID(in); got != want {
+			t.Errorf("scaffoldID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
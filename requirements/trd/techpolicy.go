@@ -0,0 +1,249 @@
+package trd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kinds of technology policy violations.
+const (
+	ViolationDenied            = "denied"
+	ViolationUnapproved        = "unapproved"
+	ViolationDeprecatedVersion = "deprecated_version"
+	ViolationDeniedVendor      = "denied_vendor"
+	ViolationUnapprovedVendor  = "unapproved_vendor"
+)
+
+// TechnologyPolicy is an organization's rules for the technologies a TRD
+// may choose. It is usually shared by all TRDs of a workspace.
+type TechnologyPolicy struct {
+	// Reference identifies the policy in violation reports, such as a URL
+	// or a standards document ID.
+	Reference string `json:"reference,omitempty"`
+
+	// Approved is an allowlist: when it is not empty, technologies that
+	// match no rule are violations. Matching rules also set the versions
+	// that are deprecated.
+	Approved []TechnologyRule `json:"approved,omitempty"`
+
+	// Denied technologies are violations whether or not they are approved.
+	Denied []TechnologyRule `json:"denied,omitempty"`
+
+	// ApprovedVendors is an allowlist of vendors: when it is not empty,
+	// technologies with a vendor not on the list are violations.
+	ApprovedVendors []string `json:"approvedVendors,omitempty"`
+
+	// DeniedVendors are vendors technologies may not come from.
+	DeniedVendors []string `json:"deniedVendors,omitempty"`
+}
+
+// TechnologyRule matches technologies by name, and optionally by the
+// technology stack category they are listed under.
+type TechnologyRule struct {
+	Name string `json:"name"` // Matched case-insensitively
+
+	// Category limits the rule to one technology stack list, by its JSON
+	// name, e.g. "databases" or "messageQueues".
+	Category string `json:"category,omitempty"`
+
+	// MinVersion is the oldest supported version; older versions are
+	// deprecated. Versions are compared by their numeric components.
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// DeprecatedVersions are deprecated versions or version lines, e.g.
+	// "2" matches 2.7 and 2.7.18.
+	DeprecatedVersions []string `json:"deprecatedVersions,omitempty"`
+
+	Reason    string `json:"reason,omitempty"`
+	Reference string `json:"reference,omitempty"` // Overrides the policy's reference
+}
+
+// PolicyViolation is a technology stack entry that breaks a technology
+// policy.
+type PolicyViolation struct {
+	Field      string `json:"field"` // e.g. technologyStack.databases[0]
+	Technology string `json:"technology"`
+	Version    string `json:"version,omitempty"`
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	Reference  string `json:"reference,omitempty"`
+}
+
+// IsError reports whether the violation blocks approval. Deprecated
+// versions are warnings: the technology is allowed, but should be upgraded.
+func (v PolicyViolation) IsError() bool {
+	return v.Kind != ViolationDeprecatedVersion
+}
+
+// StackCategory is one list of a technology stack, with its JSON name.
+type StackCategory struct {
+	Name         string
+	Technologies []Technology
+}
+
+// Categories returns the technology stack's lists in document order.
+func (ts TechnologyStack) Categories() []StackCategory {
+	return []StackCategory{
+		{"languages", ts.Languages},
+		{"frameworks", ts.Frameworks},
+		{"databases", ts.Databases},
+		{"messageQueues", ts.MessageQueues},
+		{"caching", ts.Caching},
+		{"infrastructure", ts.Infrastructure},
+		{"monitoring", ts.Monitoring},
+		{"cicd", ts.CICD},
+		{"other", ts.Other},
+	}
+}
+
+// CheckTechnologyPolicy checks the technology stack against policy and
+// returns the violations in stack order. A nil policy has none.
+func (d *Document) CheckTechnologyPolicy(policy *TechnologyPolicy) []PolicyViolation {
+	if policy == nil {
+		return nil
+	}
+	var violations []PolicyViolation
+	for _, c := range d.TechnologyStack.Categories() {
+		for i, t := range c.Technologies {
+			field := fmt.Sprintf("technologyStack.%s[%d]", c.Name, i)
+			violations = append(violations, policy.check(field, c.Name, t)...)
+		}
+	}
+	return violations
+}
+
+func (p *TechnologyPolicy) check(field, category string, t Technology) []PolicyViolation {
+	var violations []PolicyViolation
+	add := func(kind, message, reference string) {
+		if reference == "" {
+			reference = p.Reference
+		}
+		violations = append(violations, PolicyViolation{
+			Field: field, Technology: t.Name, Version: t.Version,
+			Kind: kind, Message: message, Reference: reference,
+		})
+	}
+
+	if r := matchRule(p.Denied, category, t.Name); r != nil {
+		add(ViolationDenied, withReason(fmt.Sprintf("%s is denied by policy", t.Name), r.Reason), r.Reference)
+	} else if r := matchRule(p.Approved, category, t.Name); r != nil {
+		if message := r.deprecated(t); message != "" {
+			add(ViolationDeprecatedVersion, withReason(message, r.Reason), r.Reference)
+		}
+	} else if len(p.Approved) > 0 {
+		add(ViolationUnapproved, fmt.Sprintf("%s is not on the approved list for %s", t.Name, category), "")
+	}
+
+	if vendor := strings.TrimSpace(t.Vendor); vendor != "" {
+		switch {
+		case containsFold(p.DeniedVendors, vendor):
+			add(ViolationDeniedVendor, fmt.Sprintf("%s comes from denied vendor %s", t.Name, vendor), "")
+		case len(p.ApprovedVendors) > 0 && !containsFold(p.ApprovedVendors, vendor):
+			add(ViolationUnapprovedVendor, fmt.Sprintf("%s comes from unapproved vendor %s", t.Name, vendor), "")
+		}
+	}
+	return violations
+}
+
+func matchRule(rules []TechnologyRule, category, name string) *TechnologyRule {
+	for i, r := range rules {
+		if strings.EqualFold(strings.TrimSpace(r.Name), strings.TrimSpace(name)) &&
+			(r.Category == "" || strings.EqualFold(r.Category, category)) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// deprecated describes why the version of t is deprecated, or returns ""
+// if it is not. Technologies without a version are not checked.
+func (r *TechnologyRule) deprecated(t Technology) string {
+	version := versionNumbers(t.Version)
+	if version == nil {
+		return ""
+	}
+	for _, dv := range r.DeprecatedVersions {
+		if line := versionNumbers(dv); line != nil && hasVersionPrefix(version, line) {
+			return fmt.Sprintf("%s %s is a deprecated version", t.Name, t.Version)
+		}
+	}
+	if floor := versionNumbers(r.MinVersion); floor != nil && compareVersions(version, floor) < 0 {
+		return fmt.Sprintf("%s %s is older than the minimum approved version %s", t.Name, t.Version, r.MinVersion)
+	}
+	return ""
+}
+
+func withReason(message, reason string) string {
+	if reason == "" {
+		return message
+	}
+	return message + ": " + reason
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionNumbers parses the leading numeric components of a version such
+// as "v3.11.2" or "17 LTS". It returns nil if the version has none.
+func versionNumbers(v string) []int {
+	v = strings.TrimLeft(strings.TrimSpace(v), "vV=<>~^ ")
+	var nums []int
+	for _, part := range strings.Split(v, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+		if end < len(part) {
+			break
+		}
+	}
+	return nums
+}
+
+func hasVersionPrefix(version, prefix []int) bool {
+	if len(prefix) > len(version) {
+		return false
+	}
+	for i, n := range prefix {
+		if version[i] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares versions component by component; missing
+// components count as zero.
+func compareVersions(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
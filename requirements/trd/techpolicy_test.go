@@ -0,0 +1,86 @@
+package trd
+
+import (
+	"testing"
+)
+
+func TestCheckTechnologyPolicy(t *testing.T) {
+	doc := Document{TechnologyStack: TechnologyStack{
+		Languages: []Technology{
+			{Name: "Go", Version: "1.24"},
+			{Name: "python", Version: "2.7.18"},
+			{Name: "Java", Version: "v11"},
+		},
+		Databases: []Technology{
+			{Name: "PostgreSQL", Version: "16", Vendor: "AWS"},
+			{Name: "MongoDB", Version: "7.0"},
+		},
+		Caching: []Technology{{Name: "Redis", Vendor: "Acme Cloud"}},
+	}}
+	policy := &TechnologyPolicy{
+		Reference: "ENG-STD-12",
+		Approved: []TechnologyRule{
+			{Name: "Go", MinVersion: "1.22"},
+			{Name: "Python", DeprecatedVersions: []string{"2"}, Reason: "end of life"},
+			{Name: "Java", MinVersion: "17"},
+			{Name: "PostgreSQL", Category: "databases"},
+			{Name: "Redis"},
+		},
+		Denied:          []TechnologyRule{{Name: "MongoDB", Reason: "license", Reference: "LEGAL-4"}},
+		ApprovedVendors: []string{"aws", "GCP"},
+	}
+
+	got := doc.CheckTechnologyPolicy(policy)
+	want := []PolicyViolation{
+		{Field: "technologyStack.languages[1]", Kind: ViolationDeprecatedVersion,
+			Message: "python 2.7.18 is a deprecated version: end of life", Reference: "ENG-STD-12"},
+		{Field: "technologyStack.languages[2]", Kind: ViolationDeprecatedVersion,
+			Message: "Java v11 is older than the minimum approved version 17", Reference: "ENG-STD-12"},
+		{Field: "technologyStack.databases[1]", Kind: ViolationDenied,
+			Message: "MongoDB is denied by policy: license", Reference: "LEGAL-4"},
+		{Field: "technologyStack.caching[0]", Kind: ViolationUnapprovedVendor,
+			Message: "Redis comes from unapproved vendor Acme Cloud", Reference: "ENG-STD-12"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %+v", got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Field != w.Field || g.Kind != w.Kind || g.Message != w.Message || g.Reference != w.Reference {
+			t.Errorf("violation %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if got[0].IsError() || !got[2].IsError() {
+		t.Error("deprecated versions should be warnings and denials errors")
+	}
+
+	// The category of a rule limits where it approves a technology.
+	doc.TechnologyStack.Other = []Technology{{Name: "PostgreSQL"}}
+	got = doc.CheckTechnologyPolicy(policy)
+	if last := got[len(got)-1]; last.Kind != ViolationUnapproved || last.Message != "PostgreSQL is not on the approved list for other" {
+		t.Errorf("last violation = %+v", last)
+	}
+
+	if v := doc.CheckTechnologyPolicy(nil); v != nil {
+		t.Errorf("nil policy violations = %+v", v)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.24", "1.22", 1},
+		{"v3.9", "3.10", -1},
+		{"17 LTS", "17.0", 0},
+		{"2.7.18", "3", -1},
+	} {
+		if got := compareVersions(versionNumbers(tc.a), versionNumbers(tc.b)); got != tc.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+	if versionNumbers("latest") != nil {
+		t.Error("versionNumbers(latest) should be nil")
+	}
+}
@@ -0,0 +1,190 @@
+// Package richtext handles the fenced code blocks and LaTeX math that
+// narrative fields, such as requirement and component descriptions, may
+// contain. Markdown renderers keep them intact by moving entities whose
+// text has blocks out of table rows, and HTML renderers turn them into
+// <pre> elements and KaTeX-ready math spans.
+//
+// Code blocks are fenced with ``` or ~~~ at the start of a line, with an
+// optional language. Display math is delimited by $$ or \[ \], and inline
+// math by \( \) or by $ as in Pandoc: the opening $ must be followed by a
+// non-space and the closing $ preceded by one and not followed by a digit,
+// so prices such as "$5 to $10" stay text.
+package richtext
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Segment kinds.
+const (
+	KindText = "text"
+	KindCode = "code"
+	KindMath = "math"
+)
+
+// Segment is a run of prose, a fenced code block, or a display math block.
+type Segment struct {
+	Kind    string
+	Lang    string // Language of a code block, e.g. "go"
+	Content string // Text, code, or TeX without the delimiters
+}
+
+// Parse splits s into prose, code blocks, and display math blocks. An
+// unclosed fence runs to the end of s.
+func Parse(s string) []Segment {
+	var segs []Segment
+	var text []string
+	flush := func() {
+		if t := strings.Trim(strings.Join(text, "\n"), "\n"); t != "" {
+			segs = append(segs, Segment{Kind: KindText, Content: t})
+		}
+		text = nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~"):
+			flush()
+			fence := line[:3]
+			var body []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				body = append(body, lines[i])
+			}
+			segs = append(segs, Segment{Kind: KindCode, Lang: strings.TrimSpace(line[3:]), Content: strings.Join(body, "\n")})
+		case strings.HasPrefix(line, "$$") || strings.HasPrefix(line, `\[`):
+			flush()
+			open, end := line[:2], "$$"
+			if open == `\[` {
+				end = `\]`
+			}
+			rest := line[2:]
+			if j := strings.Index(rest, end); j >= 0 {
+				segs = append(segs, Segment{Kind: KindMath, Content: strings.TrimSpace(rest[:j])})
+				if after := strings.TrimSpace(rest[j+2:]); after != "" {
+					text = append(text, after)
+				}
+				continue
+			}
+			body := []string{rest}
+			for i++; i < len(lines); i++ {
+				if j := strings.Index(lines[i], end); j >= 0 {
+					body = append(body, lines[i][:j])
+					break
+				}
+				body = append(body, lines[i])
+			}
+			segs = append(segs, Segment{Kind: KindMath, Content: strings.TrimSpace(strings.Join(body, "\n"))})
+		default:
+			text = append(text, lines[i])
+		}
+	}
+	flush()
+	return segs
+}
+
+// HasBlocks reports whether s contains a fenced code block or display
+// math, which cannot be placed in a Markdown table cell.
+func HasBlocks(s string) bool {
+	if !strings.Contains(s, "```") && !strings.Contains(s, "~~~") && !strings.Contains(s, "$$") && !strings.Contains(s, `\[`) {
+		return false
+	}
+	for _, seg := range Parse(s) {
+		if seg.Kind != KindText {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineMath matches \( \) and Pandoc-style $ $ inline math.
+var inlineMath = regexp.MustCompile(`\\\((.+?)\\\)|\$([^\s$](?:[^$\n]*[^\s$])?)\$`)
+
+// HasMath reports whether s contains display or inline math.
+func HasMath(s string) bool {
+	for _, seg := range Parse(s) {
+		if seg.Kind == KindMath || (seg.Kind == KindText && len(inlineMathSpans(seg.Content)) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineMathSpans returns the byte ranges of the inline math in s and of
+// its TeX, skipping $ pairs whose closing $ is followed by a digit.
+func inlineMathSpans(s string) [][4]int {
+	var spans [][4]int
+	for _, m := range inlineMath.FindAllStringSubmatchIndex(s, -1) {
+		if m[2] >= 0 {
+			spans = append(spans, [4]int{m[0], m[1], m[2], m[3]})
+			continue
+		}
+		if m[1] < len(s) && s[m[1]] >= '0' && s[m[1]] <= '9' {
+			continue
+		}
+		spans = append(spans, [4]int{m[0], m[1], m[4], m[5]})
+	}
+	return spans
+}
+
+// TableCell returns s for a Markdown table cell: pipes are escaped, line
+// breaks become <br>, and code and math blocks are flattened onto one line
+// as inline code and inline math.
+func TableCell(s string) string {
+	if !strings.ContainsAny(s, "|\n`~$\\") {
+		return s
+	}
+	var parts []string
+	for _, seg := range Parse(s) {
+		content := strings.Join(strings.Fields(seg.Content), " ")
+		switch seg.Kind {
+		case KindCode:
+			parts = append(parts, "`"+content+"`")
+		case KindMath:
+			parts = append(parts, "$"+content+"$")
+		default:
+			parts = append(parts, strings.ReplaceAll(strings.TrimSpace(seg.Content), "\n", "<br>"))
+		}
+	}
+	return strings.ReplaceAll(strings.Join(parts, " "), "|", `\|`)
+}
+
+// HTML returns s as escaped HTML. Prose becomes paragraphs with line
+// breaks kept, code blocks <pre><code> elements with a language-* class,
+// and math KaTeX-ready spans: \[ \] in a div with class "math display" and
+// \( \) in a span with class "math inline".
+func HTML(s string) template.HTML {
+	var sb strings.Builder
+	for _, seg := range Parse(s) {
+		switch seg.Kind {
+		case KindCode:
+			sb.WriteString("<pre><code")
+			if seg.Lang != "" {
+				sb.WriteString(` class="language-` + template.HTMLEscapeString(strings.Fields(seg.Lang)[0]) + `"`)
+			}
+			sb.WriteString(">" + template.HTMLEscapeString(seg.Content) + "</code></pre>\n")
+		case KindMath:
+			sb.WriteString(`<div class="math display">\[` + template.HTMLEscapeString(seg.Content) + `\]</div>` + "\n")
+		default:
+			sb.WriteString("<p>" + strings.ReplaceAll(string(Inline(seg.Content)), "\n", "<br>\n") + "</p>\n")
+		}
+	}
+	return template.HTML(sb.String()) //nolint:gosec // built from escaped text
+}
+
+// Inline returns a line of prose as escaped HTML with its inline math in
+// KaTeX-ready spans.
+func Inline(s string) template.HTML {
+	var sb strings.Builder
+	last := 0
+	for _, m := range inlineMathSpans(s) {
+		sb.WriteString(template.HTMLEscapeString(s[last:m[0]]))
+		sb.WriteString(`<span class="math inline">\(` + template.HTMLEscapeString(s[m[2]:m[3]]) + `\)</span>`)
+		last = m[1]
+	}
+	sb.WriteString(template.HTMLEscapeString(s[last:]))
+	return template.HTML(sb.String()) //nolint:gosec // built from escaped text
+}
@@ -0,0 +1,74 @@
+package richtext
+
+import (
+	"strings"
+	"testing"
+)
+
+const desc = "Score each lead:\n\n$$\ns = \\sum_i w_i x_i\n$$\n\nThen call:\n\n```go\nscore(lead)\n```\nDone when $s > 0.8$."
+
+func TestParse(t *testing.T) {
+	var got []string
+	for _, seg := range Parse(desc) {
+		got = append(got, seg.Kind+":"+seg.Lang+":"+seg.Content)
+	}
+	want := []string{
+		"text::Score each lead:",
+		`math::s = \sum_i w_i x_i`,
+		"text::Then call:",
+		"code:go:score(lead)",
+		"text::Done when $s > 0.8$.",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Parse() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if segs := Parse(`\[ E = mc^2 \] holds`); len(segs) != 2 || segs[0].Content != "E = mc^2" || segs[1].Content != "holds" {
+		t.Errorf(`Parse(\[...\]) = %+v`, segs)
+	}
+}
+
+func TestHasBlocksAndMath(t *testing.T) {
+	for _, tt := range []struct {
+		s            string
+		blocks, math bool
+	}{
+		{desc, true, true},
+		{"Plans cost $5 to $10 per seat", false, false},
+		{"Latency \\(p_{95}\\) under 200ms", false, true},
+		{"Use `go test` and $x$", false, true},
+		{"Plain text", false, false},
+	} {
+		if got := HasBlocks(tt.s); got != tt.blocks {
+			t.Errorf("HasBlocks(%q) = %v", tt.s, got)
+		}
+		if got := HasMath(tt.s); got != tt.math {
+			t.Errorf("HasMath(%q) = %v", tt.s, got)
+		}
+	}
+}
+
+func TestTableCell(t *testing.T) {
+	got := TableCell("a | b\nline two\n```\nx := 1\ny := 2\n```")
+	want := "a \\| b<br>line two `x := 1 y := 2`"
+	if got != want {
+		t.Errorf("TableCell() = %q, want %q", got, want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	got := string(HTML(desc + "\n<script>"))
+	for _, want := range []string{
+		"<p>Score each lead:</p>",
+		`<div class="math display">\[s = \sum_i w_i x_i\]</div>`,
+		`<pre><code class="language-go">score(lead)</code></pre>`,
+		`<p>Done when <span class="math inline">\(s &gt; 0.8\)</span>.<br>` + "\n&lt;script&gt;</p>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() missing %q:\n%s", want, got)
+		}
+	}
+	if got := string(Inline("$5 to $10")); got != "$5 to $10" {
+		t.Errorf("Inline() = %q", got)
+	}
+}
@@ -0,0 +1,381 @@
+package roadmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Baseline is a snapshot of a roadmap as committed, such as at the start of
+// a quarter, against which the roadmap is later compared.
+type Baseline struct {
+	Name       string    `json:"name,omitempty"` // e.g., "Q2 plan"
+	CapturedAt time.Time `json:"capturedAt"`
+	Roadmap    Document  `json:"roadmap"`
+}
+
+// NewBaseline returns a baseline holding a copy of doc, captured at at.
+func NewBaseline(doc *Document, name string, at time.Time) (*Baseline, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("copying roadmap: %w", err)
+	}
+	b := &Baseline{Name: name, CapturedAt: at}
+	if err := json.Unmarshal(data, &b.Roadmap); err != nil {
+		return nil, fmt.Errorf("copying roadmap: %w", err)
+	}
+	return b, nil
+}
+
+// SlippageReport compares a roadmap against its baseline: the phases that
+// slipped or are late, the deliverables descoped, added, or moved to
+// another phase or due date, and the milestones whose date moved.
+type SlippageReport struct {
+	Title        string    `json:"title,omitempty"`
+	Baseline     string    `json:"baseline,omitempty"` // Baseline name
+	BaselineDate time.Time `json:"baselineDate"`
+	AsOf         time.Time `json:"asOf"`
+
+	Phases        []PhaseSlip `json:"phases,omitempty"`
+	AddedPhases   []string    `json:"addedPhases,omitempty"`   // IDs
+	RemovedPhases []string    `json:"removedPhases,omitempty"` // IDs
+
+	Descoped   []DeliverableChange `json:"descoped,omitempty"`
+	Added      []DeliverableChange `json:"added,omitempty"`
+	Retargeted []DeliverableChange `json:"retargeted,omitempty"` // Moved phase or due date
+
+	Milestones []MilestoneChange `json:"milestones,omitempty"`
+}
+
+// PhaseSlip is a phase whose dates or status differ from the baseline, or
+// that is past its baseline end without being completed. Slips are in days;
+// positive is later than planned.
+type PhaseSlip struct {
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	BaselineStart  *time.Time  `json:"baselineStart,omitempty"`
+	BaselineEnd    *time.Time  `json:"baselineEnd,omitempty"`
+	Start          *time.Time  `json:"start,omitempty"`
+	End            *time.Time  `json:"end,omitempty"`
+	StartSlipDays  int         `json:"startSlipDays,omitempty"`
+	EndSlipDays    int         `json:"endSlipDays,omitempty"`
+	BaselineStatus PhaseStatus `json:"baselineStatus,omitempty"`
+	Status         PhaseStatus `json:"status,omitempty"`
+	Late           bool        `json:"late,omitempty"`
+}
+
+// DeliverableChange is a deliverable added, descoped, or retargeted since
+// the baseline. Due dates are the deliverable's or else its phase's end.
+type DeliverableChange struct {
+	ID              string `json:"id,omitempty"`
+	Title           string `json:"title"`
+	BaselinePhaseID string `json:"baselinePhaseId,omitempty"`
+	PhaseID         string `json:"phaseId,omitempty"`
+	BaselineDue     string `json:"baselineDue,omitempty"`
+	Due             string `json:"due,omitempty"`
+}
+
+// MilestoneChange is a milestone whose date moved or that was removed.
+type MilestoneChange struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	BaselineDate string `json:"baselineDate,omitempty"`
+	Date         string `json:"date,omitempty"`
+	SlipDays     int    `json:"slipDays,omitempty"`
+	Removed      bool   `json:"removed,omitempty"`
+}
+
+// CompareBaseline compares doc against baseline b as of the given date.
+// Phases and milestones are matched by ID, and deliverables by ID, or
+// title when they have none. A phase is late if its end moved later, or if
+// asOf is past its baseline end and it is neither completed nor cancelled.
+func CompareBaseline(b *Baseline, doc *Document, asOf time.Time) *SlippageReport {
+	r := &SlippageReport{
+		Title:        doc.Metadata.Title,
+		Baseline:     b.Name,
+		BaselineDate: b.CapturedAt,
+		AsOf:         asOf,
+	}
+
+	actual := make(map[string]*Phase, len(doc.Phases))
+	for i := range doc.Phases {
+		actual[doc.Phases[i].ID] = &doc.Phases[i]
+	}
+	planned := make(map[string]bool, len(b.Roadmap.Phases))
+	for i := range b.Roadmap.Phases {
+		base := &b.Roadmap.Phases[i]
+		planned[base.ID] = true
+		p, ok := actual[base.ID]
+		if !ok {
+			r.RemovedPhases = append(r.RemovedPhases, base.ID)
+			continue
+		}
+		if slip, changed := comparePhase(base, p, asOf); changed {
+			r.Phases = append(r.Phases, slip)
+		}
+	}
+	for _, p := range doc.Phases {
+		if !planned[p.ID] {
+			r.AddedPhases = append(r.AddedPhases, p.ID)
+		}
+	}
+
+	baseDeliverables := deliverablesByKey(b.Roadmap.Phases)
+	actualDeliverables := deliverablesByKey(doc.Phases)
+	for _, base := range baseDeliverables.order {
+		bd := baseDeliverables.items[base]
+		ad, ok := actualDeliverables.items[base]
+		switch {
+		case !ok:
+			r.Descoped = append(r.Descoped, DeliverableChange{ID: bd.ID, Title: bd.Title, BaselinePhaseID: bd.PhaseID, BaselineDue: bd.Due})
+		case ad.PhaseID != bd.PhaseID || ad.Due != bd.Due:
+			r.Retargeted = append(r.Retargeted, DeliverableChange{
+				ID: ad.ID, Title: ad.Title,
+				BaselinePhaseID: bd.PhaseID, PhaseID: ad.PhaseID,
+				BaselineDue: bd.Due, Due: ad.Due,
+			})
+		}
+	}
+	for _, key := range actualDeliverables.order {
+		if _, ok := baseDeliverables.items[key]; !ok {
+			ad := actualDeliverables.items[key]
+			r.Added = append(r.Added, DeliverableChange{ID: ad.ID, Title: ad.Title, PhaseID: ad.PhaseID, Due: ad.Due})
+		}
+	}
+
+	milestones := make(map[string]Milestone, len(doc.Milestones))
+	for _, m := range doc.Milestones {
+		milestones[m.ID] = m
+	}
+	for _, base := range b.Roadmap.Milestones {
+		m, ok := milestones[base.ID]
+		switch {
+		case !ok:
+			r.Milestones = append(r.Milestones, MilestoneChange{ID: base.ID, Title: base.Title, BaselineDate: base.Date, Removed: true})
+		case m.Date != base.Date:
+			r.Milestones = append(r.Milestones, MilestoneChange{
+				ID: m.ID, Title: m.Title, BaselineDate: base.Date, Date: m.Date,
+				SlipDays: dateSlip(base.Date, m.Date),
+			})
+		}
+	}
+	return r
+}
+
+// comparePhase returns how p differs from its baseline, and whether it
+// does at all.
+func comparePhase(base, p *Phase, asOf time.Time) (PhaseSlip, bool) {
+	slip := PhaseSlip{
+		ID:             p.ID,
+		Name:           p.Name,
+		BaselineStart:  base.StartDate,
+		BaselineEnd:    base.EndDate,
+		Start:          p.StartDate,
+		End:            p.EndDate,
+		StartSlipDays:  daysBetween(base.StartDate, p.StartDate),
+		EndSlipDays:    daysBetween(base.EndDate, p.EndDate),
+		BaselineStatus: base.Status,
+		Status:         p.Status,
+	}
+	done := p.Status == PhaseStatusCompleted || p.Status == PhaseStatusCancelled
+	slip.Late = slip.EndSlipDays > 0 ||
+		(!done && base.EndDate != nil && asOf.After(*base.EndDate))
+	changed := slip.Late || slip.StartSlipDays != 0 || slip.EndSlipDays != 0 || base.Status != p.Status ||
+		(base.StartDate == nil) != (p.StartDate == nil) || (base.EndDate == nil) != (p.EndDate == nil)
+	return slip, changed
+}
+
+func daysBetween(from, to *time.Time) int {
+	if from == nil || to == nil {
+		return 0
+	}
+	return int(to.Sub(*from).Hours() / 24)
+}
+
+func dateSlip(from, to string) int {
+	f, err1 := time.Parse("2006-01-02", from)
+	t, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return daysBetween(&f, &t)
+}
+
+// plannedDeliverable is a deliverable with its phase and effective due
+// date.
+type plannedDeliverable struct {
+	ID, Title, PhaseID, Due string
+}
+
+type deliverableIndex struct {
+	order []string
+	items map[string]plannedDeliverable
+}
+
+// deliverablesByKey indexes the deliverables of phases by ID, or by
+// lowercased title when they have none, in document order.
+func deliverablesByKey(phases []Phase) deliverableIndex {
+	idx := deliverableIndex{items: map[string]plannedDeliverable{}}
+	for _, p := range phases {
+		for _, d := range p.Deliverables {
+			key := "id:" + d.ID
+			if d.ID == "" {
+				key = "title:" + strings.ToLower(strings.TrimSpace(d.Title))
+			}
+			if _, dup := idx.items[key]; dup {
+				continue
+			}
+			due := d.DueDate
+			if due == "" && p.EndDate != nil {
+				due = formatDate(*p.EndDate)
+			}
+			idx.order = append(idx.order, key)
+			idx.items[key] = plannedDeliverable{ID: d.ID, Title: d.Title, PhaseID: p.ID, Due: due}
+		}
+	}
+	return idx
+}
+
+// LatePhases returns the number of late phases.
+func (r *SlippageReport) LatePhases() int {
+	n := 0
+	for _, p := range r.Phases {
+		if p.Late {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty reports whether the roadmap matches its baseline and nothing is
+// late.
+func (r *SlippageReport) Empty() bool {
+	return len(r.Phases) == 0 && len(r.AddedPhases) == 0 && len(r.RemovedPhases) == 0 &&
+		len(r.Descoped) == 0 && len(r.Added) == 0 && len(r.Retargeted) == 0 && len(r.Milestones) == 0
+}
+
+// ToMarkdown renders the report as a slippage summary for program reviews.
+func (r *SlippageReport) ToMarkdown() string {
+	var sb strings.Builder
+	title := "Slippage Report"
+	if r.Title != "" {
+		title += ": " + r.Title
+	}
+	sb.WriteString("# " + title + "\n\n")
+	baseline := "Baseline"
+	if r.Baseline != "" {
+		baseline += " " + r.Baseline
+	}
+	if !r.BaselineDate.IsZero() {
+		baseline += " of " + formatDate(r.BaselineDate)
+	}
+	sb.WriteString(fmt.Sprintf("%s, compared as of %s.\n\n", baseline, formatDate(r.AsOf)))
+	if r.Empty() {
+		sb.WriteString("On plan: no changes since the baseline.\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%d late phase(s), %d deliverable(s) descoped, %d added, %d retargeted, %d milestone(s) moved.\n",
+		r.LatePhases(), len(r.Descoped), len(r.Added), len(r.Retargeted), len(r.Milestones)))
+
+	if len(r.Phases) > 0 {
+		sb.WriteString("\n## Phases\n\n")
+		sb.WriteString("| Phase | Baseline | Current | End Slip | Status |\n")
+		sb.WriteString("|-------|----------|---------|----------|--------|\n")
+		for _, p := range r.Phases {
+			name := p.Name
+			if p.Late {
+				name += " ⚠️ late"
+			}
+			status := string(p.Status)
+			if p.BaselineStatus != p.Status {
+				status = fmt.Sprintf("%s → %s", p.BaselineStatus, p.Status)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				name, window(p.BaselineStart, p.BaselineEnd), window(p.Start, p.End), days(p.EndSlipDays), status))
+		}
+	}
+	if len(r.AddedPhases) > 0 || len(r.RemovedPhases) > 0 {
+		sb.WriteString("\n")
+		if len(r.AddedPhases) > 0 {
+			sb.WriteString("- **Added phases:** " + strings.Join(r.AddedPhases, ", ") + "\n")
+		}
+		if len(r.RemovedPhases) > 0 {
+			sb.WriteString("- **Removed phases:** " + strings.Join(r.RemovedPhases, ", ") + "\n")
+		}
+	}
+
+	writeDeliverables := func(heading string, changes []DeliverableChange, line func(DeliverableChange) string) {
+		if len(changes) == 0 {
+			return
+		}
+		sb.WriteString("\n## " + heading + "\n\n")
+		for _, c := range changes {
+			label := c.Title
+			if c.ID != "" {
+				label = c.ID + ": " + c.Title
+			}
+			sb.WriteString("- " + label + line(c) + "\n")
+		}
+	}
+	writeDeliverables("Descoped Deliverables", r.Descoped, func(c DeliverableChange) string {
+		return fmt.Sprintf(" (was in %s)", c.BaselinePhaseID)
+	})
+	writeDeliverables("Retargeted Deliverables", r.Retargeted, func(c DeliverableChange) string {
+		var parts []string
+		if c.PhaseID != c.BaselinePhaseID {
+			parts = append(parts, fmt.Sprintf("phase %s → %s", c.BaselinePhaseID, c.PhaseID))
+		}
+		if c.Due != c.BaselineDue {
+			parts = append(parts, fmt.Sprintf("due %s → %s", orDash(c.BaselineDue), orDash(c.Due)))
+		}
+		return " (" + strings.Join(parts, ", ") + ")"
+	})
+	writeDeliverables("Added Deliverables", r.Added, func(c DeliverableChange) string {
+		return fmt.Sprintf(" (in %s)", c.PhaseID)
+	})
+
+	if len(r.Milestones) > 0 {
+		sb.WriteString("\n## Milestones\n\n")
+		sb.WriteString("| Milestone | Baseline | Current | Slip |\n")
+		sb.WriteString("|-----------|----------|---------|------|\n")
+		for _, m := range r.Milestones {
+			current, slip := m.Date, days(m.SlipDays)
+			if m.Removed {
+				current, slip = "removed", "-"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", m.Title, orDash(m.BaselineDate), orDash(current), slip))
+		}
+	}
+	return sb.String()
+}
+
+func window(start, end *time.Time) string {
+	date := func(t *time.Time) string {
+		if t == nil {
+			return "?"
+		}
+		return formatDate(*t)
+	}
+	if start == nil && end == nil {
+		return "-"
+	}
+	return date(start) + " – " + date(end)
+}
+
+func days(n int) string {
+	switch {
+	case n > 0:
+		return fmt.Sprintf("+%dd", n)
+	case n < 0:
+		return fmt.Sprintf("%dd", n)
+	}
+	return "0d"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
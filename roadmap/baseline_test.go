@@ -0,0 +1,116 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareBaseline(t *testing.T) {
+	doc := &Document{
+		Metadata: Metadata{ID: "rm", Title: "Platform"},
+		Phases: []Phase{
+			{ID: "p1", Name: "Beta", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"), Status: PhaseStatusInProgress,
+				Deliverables: []Deliverable{{ID: "d1", Title: "Sandbox"}, {ID: "d2", Title: "Scaler"}, {Title: "Docs"}}},
+			{ID: "p2", Name: "GA", StartDate: date("2026-04-01"), EndDate: date("2026-06-30"), Status: PhaseStatusPlanned,
+				Deliverables: []Deliverable{{ID: "d3", Title: "Policy engine", DueDate: "2026-05-15"}}},
+			{ID: "p3", Name: "Scale", StartDate: date("2026-07-01"), EndDate: date("2026-09-30")},
+		},
+		Milestones: []Milestone{
+			{ID: "m1", Title: "Beta open", Date: "2026-02-01"},
+			{ID: "m2", Title: "GA", Date: "2026-06-30"},
+		},
+	}
+	b, err := NewBaseline(doc, "Q1 plan", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Beta slips two weeks and loses the scaler to GA; GA moves its policy
+	// engine and the GA milestone; Scale is dropped and a new phase added.
+	doc.Phases[0].EndDate = date("2026-04-14")
+	doc.Phases[0].Deliverables = []Deliverable{{ID: "d1", Title: "Sandbox"}, {Title: "docs"}}
+	doc.Phases[1].Deliverables = []Deliverable{
+		{ID: "d3", Title: "Policy engine", DueDate: "2026-06-15"},
+		{ID: "d2", Title: "Scaler"},
+		{ID: "d4", Title: "SIEM export"},
+	}
+	doc.Phases[2] = Phase{ID: "p4", Name: "Hardening"}
+	doc.Milestones = []Milestone{{ID: "m2", Title: "GA", Date: "2026-07-14"}}
+
+	r := CompareBaseline(b, doc, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(r.Phases) != 1 || r.Phases[0].ID != "p1" || r.Phases[0].EndSlipDays != 14 || !r.Phases[0].Late {
+		t.Errorf("phases = %+v", r.Phases)
+	}
+	if strings.Join(r.RemovedPhases, ",") != "p3" || strings.Join(r.AddedPhases, ",") != "p4" {
+		t.Errorf("removed = %v, added = %v", r.RemovedPhases, r.AddedPhases)
+	}
+	if len(r.Descoped) != 0 {
+		t.Errorf("descoped = %+v", r.Descoped)
+	}
+	if len(r.Retargeted) != 4 {
+		t.Fatalf("retargeted = %+v", r.Retargeted)
+	}
+	// Sandbox and docs follow their phase end; the scaler moved phase; the
+	// policy engine its due date.
+	if c := r.Retargeted[1]; c.ID != "d2" || c.BaselinePhaseID != "p1" || c.PhaseID != "p2" || c.Due != "2026-06-30" {
+		t.Errorf("scaler = %+v", c)
+	}
+	if c := r.Retargeted[3]; c.ID != "d3" || c.Due != "2026-06-15" {
+		t.Errorf("policy engine = %+v", c)
+	}
+	if c := r.Retargeted[2]; c.Title != "docs" || c.BaselineDue != "2026-03-31" || c.Due != "2026-04-14" {
+		t.Errorf("docs = %+v", c)
+	}
+	if len(r.Added) != 1 || r.Added[0].ID != "d4" {
+		t.Errorf("added = %+v", r.Added)
+	}
+	if len(r.Milestones) != 2 || !r.Milestones[0].Removed || r.Milestones[1].SlipDays != 14 {
+		t.Errorf("milestones = %+v", r.Milestones)
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"# Slippage Report: Platform",
+		"Baseline Q1 plan of 2026-01-02, compared as of 2026-04-01.",
+		"| Beta ⚠️ late | 2026-01-01 – 2026-03-31 | 2026-01-01 – 2026-04-14 | +14d | in_progress |",
+		"- d2: Scaler (phase p1 → p2, due 2026-03-31 → 2026-06-30)",
+		"- d4: SIEM export (in p2)",
+		"| Beta open | 2026-02-01 | removed | - |",
+		"- **Removed phases:** p3",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n%s", want, md)
+		}
+	}
+
+	// The baseline is a copy, unaffected by later edits.
+	if !b.Roadmap.Phases[0].EndDate.Equal(*date("2026-03-31")) {
+		t.Error("baseline shares the roadmap's dates")
+	}
+}
+
+func TestCompareBaselineOnPlan(t *testing.T) {
+	doc := &Document{Phases: []Phase{
+		{ID: "p1", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"), Status: PhaseStatusCompleted},
+		{ID: "p2", StartDate: date("2026-04-01"), EndDate: date("2026-06-30"), Status: PhaseStatusInProgress},
+	}}
+	b, err := NewBaseline(doc, "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := CompareBaseline(b, doc, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	if !r.Empty() {
+		t.Errorf("report = %+v, want empty", r)
+	}
+	if !strings.Contains(r.ToMarkdown(), "On plan") {
+		t.Error("markdown should say on plan")
+	}
+
+	// Past the baseline end without being completed.
+	r = CompareBaseline(b, doc, time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC))
+	if r.LatePhases() != 1 || r.Phases[0].ID != "p2" {
+		t.Errorf("phases = %+v", r.Phases)
+	}
+}
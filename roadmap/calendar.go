@@ -0,0 +1,103 @@
+package roadmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/calendar"
+)
+
+// CalendarEvents returns the roadmap as all-day calendar events: one
+// spanning each phase with a start and an end date, and one on the due
+// date of each deliverable, which defaults to the end date of its phase.
+// Deliverables without a due date in undated phases are left out.
+//
+// Event UIDs are built from uidPrefix and the phase and deliverable IDs, so
+// calendars that subscribe to a re-exported file update the events in place.
+func (r *Roadmap) CalendarEvents(uidPrefix string) []calendar.ICSEvent {
+	if uidPrefix == "" {
+		uidPrefix = "roadmap"
+	}
+	uid := func(parts ...string) string {
+		return strings.ToLower(strings.Join(append([]string{uidPrefix}, parts...), "-")) + "@structured-plan"
+	}
+
+	var events []calendar.ICSEvent
+	for i := range r.Phases {
+		p := &r.Phases[i]
+		phaseID := p.ID
+		if phaseID == "" {
+			phaseID = fmt.Sprintf("phase%d", i+1)
+		}
+		name := p.Name
+		if name == "" {
+			name = phaseID
+		}
+		if p.HasWindow() {
+			events = append(events, calendar.ICSEvent{
+				UID:         uid(phaseID),
+				Summary:     name,
+				Description: phaseDescription(p),
+				Categories:  calendarCategories("Phase", string(p.Status)),
+				Start:       *p.StartDate,
+				End:         *p.EndDate,
+			})
+		}
+		for k, del := range p.Deliverables {
+			var due time.Time
+			if del.DueDate != "" {
+				t, err := time.Parse("2006-01-02", del.DueDate)
+				if err != nil {
+					continue
+				}
+				due = t
+			} else if p.EndDate != nil {
+				due = *p.EndDate
+			} else {
+				continue
+			}
+			delID := del.ID
+			if delID == "" {
+				delID = fmt.Sprintf("deliverable%d", k+1)
+			}
+			kind, label := "Deliverable", "Due"
+			if del.Type == DeliverableMilestone {
+				kind, label = "Milestone", "Milestone"
+			}
+			events = append(events, calendar.ICSEvent{
+				UID:         uid(phaseID, delID),
+				Summary:     fmt.Sprintf("%s: %s (%s)", label, del.Title, name),
+				Description: del.Description,
+				Categories:  calendarCategories(kind, string(del.Status)),
+				Start:       due,
+				End:         due,
+			})
+		}
+	}
+	return events
+}
+
+// phaseDescription lists the goals and deliverables of a phase.
+func phaseDescription(p *Phase) string {
+	var lines []string
+	if len(p.Goals) > 0 {
+		lines = append(lines, "Goals: "+strings.Join(p.Goals, "; "))
+	}
+	if len(p.Deliverables) > 0 {
+		titles := make([]string, len(p.Deliverables))
+		for i, d := range p.Deliverables {
+			titles[i] = d.Title
+		}
+		lines = append(lines, "Deliverables: "+strings.Join(titles, "; "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func calendarCategories(kind, status string) []string {
+	categories := []string{"Roadmap", kind}
+	if status != "" {
+		categories = append(categories, status)
+	}
+	return categories
+}
@@ -0,0 +1,62 @@
+package roadmap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/calendar"
+)
+
+func TestCalendarEvents(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{
+		{
+			ID: "p1", Name: "MVP", Status: PhaseStatusInProgress,
+			StartDate: date("2026-01-01"), EndDate: date("2026-03-31"),
+			Goals: []string{"Launch"},
+			Deliverables: []Deliverable{
+				{ID: "d1", Title: "Login", Status: DeliverableCompleted},
+				{ID: "d2", Title: "Beta", Type: DeliverableMilestone, DueDate: "2026-02-15"},
+			},
+		},
+		// Undated: only deliverables with a due date are kept.
+		{ID: "p2", Name: "GA", Deliverables: []Deliverable{
+			{ID: "d3", Title: "Docs"},
+			{ID: "d4", Title: "Launch", DueDate: "2026-06-01"},
+		}},
+	}}
+
+	events := r.CalendarEvents("PRD-1")
+	var got []string
+	for _, e := range events {
+		got = append(got, e.UID+" "+e.Start.Format("2006-01-02")+".."+e.End.Format("2006-01-02")+" "+e.Summary+" ["+strings.Join(e.Categories, ",")+"]")
+	}
+	want := []string{
+		"prd-1-p1@structured-plan 2026-01-01..2026-03-31 MVP [Roadmap,Phase,in_progress]",
+		"prd-1-p1-d1@structured-plan 2026-03-31..2026-03-31 Due: Login (MVP) [Roadmap,Deliverable,completed]",
+		"prd-1-p1-d2@structured-plan 2026-02-15..2026-02-15 Milestone: Beta (MVP) [Roadmap,Milestone]",
+		"prd-1-p2-d4@structured-plan 2026-06-01..2026-06-01 Due: Launch (GA) [Roadmap,Deliverable]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CalendarEvents() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if events[0].Description != "Goals: Launch\nDeliverables: Login; Beta" {
+		t.Errorf("phase description = %q", events[0].Description)
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.WriteICSEvents(&buf, "MVP Roadmap", events, *date("2026-10-17")); err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+	for _, want := range []string{
+		"X-WR-CALNAME:MVP Roadmap\r\n",
+		"DTSTART;VALUE=DATE:20260101\r\nDTEND;VALUE=DATE:20260401\r\n",
+		"DESCRIPTION:Goals: Launch\\nDeliverables: Login\\; Beta\r\n",
+		"CATEGORIES:Roadmap,Phase,in_progress\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("ICS missing %q:\n%s", want, ics)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package roadmap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+// ValidateDependencies checks external dependencies against the phases that
+// need them:
+//
+//   - NeededBy names a phase in the roadmap;
+//   - the committed due date is a valid date no later than the phase start;
+//   - a phase that is in progress or completed has no undelivered dependency;
+//   - blocked or at-risk dependencies are flagged with the phase they affect.
+//
+// Issue fields are relative to the dependency list, e.g. "dependencies[2]".
+// Dependencies without NeededBy are only checked for a valid due date.
+func (r *Roadmap) ValidateDependencies(deps []common.Dependency) []ScheduleIssue {
+	var issues []ScheduleIssue
+	phases := make(map[string]*Phase, len(r.Phases))
+	for i := range r.Phases {
+		if r.Phases[i].ID != "" {
+			phases[r.Phases[i].ID] = &r.Phases[i]
+		}
+	}
+
+	for i, dep := range deps {
+		field := fmt.Sprintf("dependencies[%d]", i)
+		add := func(field, msg string) {
+			issues = append(issues, ScheduleIssue{PhaseID: dep.NeededBy, Field: field, Message: msg})
+		}
+
+		var due *time.Time
+		if dep.DueDate != "" {
+			t, err := time.Parse("2006-01-02", dep.DueDate)
+			if err != nil {
+				add(field+".dueDate", fmt.Sprintf("Invalid date %q: expected YYYY-MM-DD", dep.DueDate))
+			} else {
+				due = &t
+			}
+		}
+
+		if dep.NeededBy == "" {
+			continue
+		}
+		phase, ok := phases[dep.NeededBy]
+		if !ok {
+			add(field+".neededBy", fmt.Sprintf("Reference to undefined phase: %s", dep.NeededBy))
+			continue
+		}
+
+		resolved := dep.Status.IsResolved()
+		if !resolved && due != nil && phase.StartDate != nil && due.After(*phase.StartDate) {
+			add(field+".dueDate", fmt.Sprintf("Dependency %s is due %s, after phase %s starts %s",
+				dep.ID, dep.DueDate, phase.ID, formatDate(*phase.StartDate)))
+		}
+		switch {
+		case !resolved && (phase.Status == PhaseStatusInProgress || phase.Status == PhaseStatusCompleted):
+			add(field+".status", fmt.Sprintf("Dependency %s is %s but phase %s is already %s",
+				dep.ID, statusLabel(dep.Status), phase.ID, phase.Status))
+		case dep.Status.IsAtRisk():
+			add(field+".status", fmt.Sprintf("Dependency %s from %s is %s; phase %s may slip",
+				dep.ID, teamLabel(dep), statusLabel(dep.Status), phase.ID))
+		}
+	}
+	return issues
+}
+
+func statusLabel(s common.DependencyStatus) string {
+	if s == "" {
+		return "untracked"
+	}
+	return string(s.Normalize())
+}
+
+func teamLabel(dep common.Dependency) string {
+	switch {
+	case dep.Team != "":
+		return dep.Team
+	case dep.Owner != "":
+		return dep.Owner
+	}
+	return "an external team"
+}
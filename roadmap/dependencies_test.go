@@ -0,0 +1,41 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{
+		{ID: "p1", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"), Status: PhaseStatusInProgress},
+		{ID: "p2", StartDate: date("2026-04-01"), EndDate: date("2026-06-30")},
+	}}
+	deps := []common.Dependency{
+		// Delivered on time.
+		{ID: "D1", Team: "Identity", NeededBy: "p2", DueDate: "2026-03-15", Status: "Available"},
+		// Due after the phase that needs it starts.
+		{ID: "D2", Team: "Payments", NeededBy: "p2", DueDate: "2026-05-01", Status: common.DependencyStatusCommitted},
+		// Phase already running without it.
+		{ID: "D3", Team: "Data", NeededBy: "p1", Status: common.DependencyStatusPending},
+		{ID: "D4", Team: "Infra", NeededBy: "p2", Status: "At Risk"},
+		{ID: "D5", NeededBy: "p9"},
+		{ID: "D6", DueDate: "next week"},
+	}
+
+	var got []string
+	for _, issue := range r.ValidateDependencies(deps) {
+		got = append(got, issue.Field+": "+issue.Message)
+	}
+	want := []string{
+		"dependencies[1].dueDate: Dependency D2 is due 2026-05-01, after phase p2 starts 2026-04-01",
+		"dependencies[2].status: Dependency D3 is pending but phase p1 is already in_progress",
+		"dependencies[3].status: Dependency D4 from Infra is at_risk; phase p2 may slip",
+		"dependencies[4].neededBy: Reference to undefined phase: p9",
+		`dependencies[5].dueDate: Invalid date "next week": expected YYYY-MM-DD`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateDependencies() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
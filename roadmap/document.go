@@ -0,0 +1,199 @@
+package roadmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// Level is the planning scope a roadmap document covers.
+type Level string
+
+const (
+	LevelPortfolio Level = "portfolio" // Several products or teams
+	LevelProduct   Level = "product"   // A single product
+)
+
+// Document is a standalone roadmap, independent of any PRD. It groups work
+// into themes and epics, schedules it in phases, and records milestones,
+// external dependencies, and staffing across those phases.
+type Document struct {
+	Schema       string              `json:"$schema,omitempty"`
+	Metadata     Metadata            `json:"metadata"`
+	Vision       string              `json:"vision,omitempty"`
+	Themes       []Theme             `json:"themes,omitempty"`
+	Epics        []Epic              `json:"epics,omitempty"`
+	Phases       []Phase             `json:"phases"`
+	Milestones   []Milestone         `json:"milestones,omitempty"`
+	Dependencies []common.Dependency `json:"dependencies,omitempty"`
+	Resourcing   []Allocation        `json:"resourcing,omitempty"`
+	Risks        []Risk              `json:"risks,omitempty"` // Cross-phase risks
+}
+
+// Metadata contains roadmap document metadata.
+type Metadata struct {
+	ID      string          `json:"id"`
+	Title   string          `json:"title"`
+	Level   Level           `json:"level,omitempty"`
+	Owner   string          `json:"owner,omitempty"`
+	Team    string          `json:"team,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Status  string          `json:"status,omitempty"` // Draft, Active, Archived
+	Authors []common.Person `json:"authors,omitempty"`
+	Tags    []string        `json:"tags,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// Theme is a strategic investment area that epics roll up to.
+type Theme struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+}
+
+// Epic is a large body of work under a theme, delivered across one or more
+// phases.
+type Epic struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	ThemeID     string            `json:"themeId,omitempty"`
+	PhaseIDs    []string          `json:"phaseIds,omitempty"` // Phases the epic is worked in
+	Status      DeliverableStatus `json:"status,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Team        string            `json:"team,omitempty"`
+	Tags        []string          `json:"tags,omitempty"` // For filtering by topic/domain
+}
+
+// Milestone is a dated checkpoint on the roadmap, such as a launch or a
+// decision gate.
+type Milestone struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Date        string            `json:"date,omitempty"`    // YYYY-MM-DD
+	PhaseID     string            `json:"phaseId,omitempty"` // Phase the milestone closes or falls in
+	Description string            `json:"description,omitempty"`
+	Status      DeliverableStatus `json:"status,omitempty"`
+}
+
+// DefaultFilename is the standard roadmap document filename.
+const DefaultFilename = "roadmap.json"
+
+// Roadmap returns the document's phases as a Roadmap, so that the schedule,
+// dependency, and staffing checks shared with PRDs apply to it. The phases
+// are shared, not copied.
+func (d *Document) Roadmap() *Roadmap {
+	return &Roadmap{Phases: d.Phases}
+}
+
+// PhaseNames maps phase IDs to display names.
+func (d *Document) PhaseNames() map[string]string {
+	names := make(map[string]string, len(d.Phases))
+	for _, p := range d.Phases {
+		names[p.ID] = p.Name
+	}
+	return names
+}
+
+// EpicsForTheme returns the epics under the theme with the given ID, in
+// document order. An empty ID returns the epics without a theme.
+func (d *Document) EpicsForTheme(themeID string) []Epic {
+	var epics []Epic
+	for _, e := range d.Epics {
+		if e.ThemeID == themeID {
+			epics = append(epics, e)
+		}
+	}
+	return epics
+}
+
+// ReadFile reads a roadmap document from a JSON file.
+func ReadFile(filepath string) (*Document, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses roadmap document JSON data.
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	return &doc, nil
+}
+
+// ParseLenient parses roadmap document JSON data, recovering from
+// field-level type errors such as malformed dates or quoted numbers. The
+// returned report lists each field that was coerced or dropped.
+func ParseLenient(data []byte) (*Document, *lenient.Report, error) {
+	var doc Document
+	report, err := lenient.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &doc, report, nil
+}
+
+// JSON returns the roadmap document as formatted JSON.
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// WriteFile writes the roadmap document to a JSON file.
+func (d *Document) WriteFile(filepath string) error {
+	data, err := d.JSON()
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+	if err := os.WriteFile(filepath, data, 0600); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
+
+// ReadFileYAML reads a roadmap document from a YAML file. Field names are
+// the same as in JSON.
+func ReadFileYAML(filepath string) (*Document, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return ParseYAML(data)
+}
+
+// ParseYAML parses roadmap document YAML data.
+func ParseYAML(data []byte) (*Document, error) {
+	var doc Document
+	if err := yamlconv.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// YAML returns the roadmap document as YAML.
+func (d *Document) YAML() ([]byte, error) {
+	return yamlconv.Marshal(d)
+}
+
+// WriteFileYAML writes the roadmap document to a YAML file.
+func (d *Document) WriteFileYAML(filepath string) error {
+	data, err := d.YAML()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath, data, 0600); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
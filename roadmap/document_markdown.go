@@ -0,0 +1,325 @@
+package roadmap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+// MarkdownOptions configures roadmap document markdown generation.
+type MarkdownOptions struct {
+	// IncludeSwimlaneTable adds the theme and deliverable swimlane views
+	// ahead of the phase details.
+	IncludeSwimlaneTable bool
+	// Table configures the swimlane tables.
+	Table TableOptions
+	// IncludeDiagrams adds a Mermaid Gantt chart of the dated phases and a
+	// Mermaid graph of the phase and external dependencies.
+	IncludeDiagrams bool
+}
+
+// DefaultMarkdownOptions returns default options.
+func DefaultMarkdownOptions() MarkdownOptions {
+	return MarkdownOptions{
+		IncludeSwimlaneTable: true,
+		Table:                DefaultTableOptions(),
+	}
+}
+
+// ToMarkdown renders the roadmap document as markdown. Sections without
+// content are omitted.
+func (d *Document) ToMarkdown(opts MarkdownOptions) string {
+	var sb strings.Builder
+	r := d.Roadmap()
+
+	title := d.Metadata.Title
+	if title == "" {
+		title = "Roadmap"
+	}
+	sb.WriteString("# " + title + "\n\n")
+
+	sb.WriteString("| Field | Value |\n")
+	sb.WriteString("|-------|-------|\n")
+	for _, row := range [][2]string{
+		{"ID", d.Metadata.ID},
+		{"Level", string(d.Metadata.Level)},
+		{"Owner", d.Metadata.Owner},
+		{"Team", d.Metadata.Team},
+		{"Version", d.Metadata.Version},
+		{"Status", d.Metadata.Status},
+	} {
+		if row[1] != "" {
+			sb.WriteString(fmt.Sprintf("| **%s** | %s |\n", row[0], escapeCell(row[1])))
+		}
+	}
+	if !d.Metadata.UpdatedAt.IsZero() {
+		sb.WriteString(fmt.Sprintf("| **Updated** | %s |\n", formatDate(d.Metadata.UpdatedAt)))
+	}
+	if len(d.Metadata.Authors) > 0 {
+		sb.WriteString(fmt.Sprintf("| **Author(s)** | %s |\n", common.FormatPeopleMarkdown(d.Metadata.Authors)))
+	}
+	if len(d.Metadata.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("| **Tags** | %s |\n", strings.Join(d.Metadata.Tags, ", ")))
+	}
+	sb.WriteString("\n---\n\n")
+
+	if d.Vision != "" {
+		sb.WriteString("## Vision\n\n")
+		sb.WriteString(d.Vision + "\n\n")
+	}
+
+	if len(d.Themes) > 0 {
+		sb.WriteString("## Themes\n\n")
+		sb.WriteString("| ID | Theme | Description | Epics |\n")
+		sb.WriteString("|----|-------|-------------|------:|\n")
+		for _, t := range d.Themes {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d |\n",
+				t.ID, escapeCell(t.Name), escapeCell(t.Description), len(d.EpicsForTheme(t.ID))))
+		}
+		sb.WriteString("\n")
+	}
+
+	if opts.IncludeSwimlaneTable && len(d.Phases) > 0 {
+		themeTable := d.ToThemeSwimlaneTable(opts.Table)
+		deliverableTable := r.ToSwimlaneTable(opts.Table)
+		if themeTable != "" || deliverableTable != "" {
+			sb.WriteString("## Roadmap Overview\n\n")
+			if themeTable != "" {
+				sb.WriteString("### By Theme\n\n")
+				sb.WriteString(themeTable + "\n")
+			}
+			if deliverableTable != "" {
+				sb.WriteString("### By Deliverable Type\n\n")
+				sb.WriteString(deliverableTable + "\n")
+			}
+			if opts.Table.IncludeStatus {
+				sb.WriteString("**Legend:**\n\n")
+				sb.WriteString(StatusLegend() + "\n")
+			}
+		}
+	}
+
+	if opts.IncludeDiagrams {
+		if gantt := d.MermaidGantt(); gantt != "" {
+			sb.WriteString("## Timeline\n\n")
+			sb.WriteString("```mermaid\n" + gantt + "```\n\n")
+		}
+		if graph := d.MermaidDependencyGraph(); graph != "" {
+			sb.WriteString("## Dependency Graph\n\n")
+			sb.WriteString("```mermaid\n" + graph + "```\n\n")
+		}
+	}
+
+	if len(d.Phases) > 0 {
+		sb.WriteString("## Phases\n\n")
+		for i := range d.Phases {
+			d.writePhase(&sb, &d.Phases[i])
+		}
+	}
+
+	if len(d.Milestones) > 0 {
+		names := d.PhaseNames()
+		sb.WriteString("## Milestones\n\n")
+		sb.WriteString("| ID | Milestone | Date | Phase | Status |\n")
+		sb.WriteString("|----|-----------|------|-------|--------|\n")
+		for _, m := range d.Milestones {
+			phase := m.PhaseID
+			if n := names[phase]; n != "" {
+				phase = n
+			}
+			status := string(m.Status)
+			if icon := StatusIcon(m.Status); icon != "" {
+				status = icon + " " + status
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				m.ID, escapeCell(m.Title), m.Date, escapeCell(phase), status))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(d.Dependencies) > 0 {
+		sb.WriteString("## Dependencies\n\n")
+		sb.WriteString(common.FormatDependencyTable(d.Dependencies, d.PhaseNames()))
+		sb.WriteString("\n")
+	}
+
+	if staffing := r.FormatStaffingTable(d.Resourcing); staffing != "" {
+		sb.WriteString("## Staffing\n\n")
+		sb.WriteString(staffing + "\n")
+	}
+
+	risks := append([]Risk(nil), d.Risks...)
+	for _, p := range d.Phases {
+		risks = append(risks, p.Risks...)
+	}
+	if len(risks) > 0 {
+		sb.WriteString("## Risks\n\n")
+		sb.WriteString("| ID | Risk | Probability | Impact | Mitigation | Status |\n")
+		sb.WriteString("|----|------|-------------|--------|------------|--------|\n")
+		for _, rk := range risks {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				rk.ID, escapeCell(rk.Description), rk.Probability, rk.Impact, escapeCell(rk.Mitigation), rk.Status))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// writePhase writes the detail section for one phase, including the epics
+// scheduled in it.
+func (d *Document) writePhase(sb *strings.Builder, p *Phase) {
+	sb.WriteString(fmt.Sprintf("### %s: %s\n\n", p.ID, p.Name))
+
+	if p.Status != "" {
+		sb.WriteString(fmt.Sprintf("**Status:** %s", p.Status))
+		if p.Progress != nil {
+			sb.WriteString(fmt.Sprintf(" (%d%%)", *p.Progress))
+		}
+		sb.WriteString("\n\n")
+	}
+	if p.StartDate != nil || p.EndDate != nil {
+		sb.WriteString("**Schedule:** " + phaseDates(p) + "\n\n")
+	}
+	if len(p.Dependencies) > 0 {
+		sb.WriteString("**Dependencies:** " + strings.Join(p.Dependencies, ", ") + "\n\n")
+	}
+
+	if len(p.Goals) > 0 {
+		sb.WriteString("**Goals:**\n\n")
+		for _, g := range p.Goals {
+			sb.WriteString("- " + g + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	var epics []Epic
+	for _, e := range d.Epics {
+		for _, id := range e.PhaseIDs {
+			if id == p.ID {
+				epics = append(epics, e)
+				break
+			}
+		}
+	}
+	if len(epics) > 0 {
+		sb.WriteString("**Epics:**\n\n")
+		for _, e := range epics {
+			item := e.Title
+			if icon := StatusIcon(e.Status); icon != "" {
+				item = icon + " " + item
+			}
+			sb.WriteString(fmt.Sprintf("- %s (%s)\n", item, e.ID))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(p.Deliverables) > 0 {
+		sb.WriteString("**Deliverables:**\n\n")
+		sb.WriteString("| ID | Title | Type | Status |\n")
+		sb.WriteString("|----|-------|------|--------|\n")
+		for _, del := range p.Deliverables {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", del.ID, escapeCell(del.Title), del.Type, del.Status))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(p.SuccessCriteria) > 0 {
+		sb.WriteString("**Success Criteria:**\n\n")
+		for _, c := range p.SuccessCriteria {
+			sb.WriteString("- " + c + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("---\n\n")
+}
+
+// ToThemeSwimlaneTable generates a markdown table with phases as columns
+// and themes as swimlane rows; each cell lists the theme's epics worked in
+// that phase. Epics without a theme are shown in an "Other" row. It returns
+// an empty string when the document has no epics.
+//
+// Example output:
+//
+//	| Theme       | **Phase 1**<br>Foundation | **Phase 2**<br>Scale |
+//	|-------------|---------------------------|----------------------|
+//	| **Growth**  | • Self-serve signup       | • Referrals          |
+func (d *Document) ToThemeSwimlaneTable(opts TableOptions) string {
+	if len(d.Phases) == 0 || len(d.Epics) == 0 {
+		return ""
+	}
+
+	type lane struct {
+		label string
+		epics []Epic
+	}
+	var lanes []lane
+	for _, t := range d.Themes {
+		epics := d.EpicsForTheme(t.ID)
+		if len(epics) > 0 || opts.IncludeEmptySwimlanes {
+			name := t.Name
+			if name == "" {
+				name = t.ID
+			}
+			lanes = append(lanes, lane{name, epics})
+		}
+	}
+	themes := make(map[string]bool, len(d.Themes))
+	for _, t := range d.Themes {
+		themes[t.ID] = true
+	}
+	var other []Epic
+	for _, e := range d.Epics {
+		if !themes[e.ThemeID] {
+			other = append(other, e)
+		}
+	}
+	if len(other) > 0 {
+		lanes = append(lanes, lane{"Other", other})
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| Theme |")
+	for i, phase := range d.Phases {
+		sb.WriteString(fmt.Sprintf(" **Phase %d**<br>%s |", i+1, phase.Name))
+	}
+	sb.WriteString("\n|----------|")
+	for range d.Phases {
+		sb.WriteString("----------|")
+	}
+	sb.WriteString("\n")
+
+	for _, l := range lanes {
+		sb.WriteString(fmt.Sprintf("| **%s** |", escapeCell(l.label)))
+		for _, phase := range d.Phases {
+			var items []string
+			for _, e := range l.epics {
+				for _, id := range e.PhaseIDs {
+					if id != phase.ID {
+						continue
+					}
+					item := e.Title
+					if opts.MaxTitleLen > 0 && len(item) > opts.MaxTitleLen {
+						item = item[:opts.MaxTitleLen-3] + "..."
+					}
+					if opts.IncludeStatus && e.Status != "" {
+						item = fmt.Sprintf("%s %s", StatusIcon(e.Status), item)
+					}
+					items = append(items, "• "+escapeCell(item))
+					break
+				}
+			}
+			sb.WriteString(" " + strings.Join(items, "<br>") + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// escapeCell escapes pipe characters so s can be used in a table cell.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
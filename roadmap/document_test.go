@@ -0,0 +1,119 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+func testDocument() *Document {
+	return &Document{
+		Metadata: Metadata{ID: "rm-1", Title: "Platform Roadmap", Level: LevelPortfolio},
+		Themes: []Theme{
+			{ID: "t1", Name: "Growth"},
+			{ID: "t2", Name: "Reliability"},
+		},
+		Epics: []Epic{
+			{ID: "e1", Title: "Self-serve signup", ThemeID: "t1", PhaseIDs: []string{"p1"}, Status: DeliverableCompleted},
+			{ID: "e2", Title: "Referrals", ThemeID: "t1", PhaseIDs: []string{"p2"}},
+			{ID: "e3", Title: "Billing | invoicing", PhaseIDs: []string{"p1", "p2"}},
+		},
+		Phases: []Phase{
+			{ID: "p1", Name: "Foundation", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"),
+				Deliverables: []Deliverable{{ID: "d1", Title: "Signup API", Type: DeliverableFeature}}},
+			{ID: "p2", Name: "Scale", StartDate: date("2026-04-01"), EndDate: date("2026-06-30")},
+		},
+		Milestones: []Milestone{
+			{ID: "m1", Title: "Beta", Date: "2026-03-15", PhaseID: "p1"},
+		},
+		Dependencies: []common.Dependency{
+			{ID: "dep-1", Name: "SSO", Team: "Identity", NeededBy: "p2"},
+		},
+	}
+}
+
+func TestDocumentValidate(t *testing.T) {
+	doc := testDocument()
+	errs := doc.Validate()
+	if !IsValid(errs) {
+		t.Fatalf("unexpected errors: %v", Errors(errs))
+	}
+	warnings := Warnings(errs)
+	if len(warnings) != 1 || warnings[0].Path != "themes[1]" {
+		t.Errorf("Warnings = %v, want only themes[1] without epics", warnings)
+	}
+
+	doc.Metadata.Title = ""
+	doc.Epics[1].ThemeID = "t9"
+	doc.Epics[2].PhaseIDs = append(doc.Epics[2].PhaseIDs, "p9")
+	doc.Phases[1].ID = "p1"
+	doc.Milestones[0].Date = "2026-05-01"
+
+	got := map[string]bool{}
+	for _, e := range doc.Validate() {
+		got[e.Path] = e.IsError
+	}
+	for path, isError := range map[string]bool{
+		"metadata.title":       true,
+		"epics[1].themeId":     true,
+		"epics[2].phaseIds[2]": true,
+		"phases[1].id":         true,
+		"milestones[0].date":   false,
+	} {
+		if v, ok := got[path]; !ok || v != isError {
+			t.Errorf("%s: reported=%v isError=%v, want isError=%v", path, ok, v, isError)
+		}
+	}
+}
+
+func TestDocumentValidateEmpty(t *testing.T) {
+	errs := (&Document{}).Validate()
+	var paths []string
+	for _, e := range Errors(errs) {
+		paths = append(paths, e.Path)
+	}
+	if got := strings.Join(paths, ","); got != "metadata.id,metadata.title,phases" {
+		t.Errorf("Errors = %s", got)
+	}
+}
+
+func TestDocumentToMarkdown(t *testing.T) {
+	md := testDocument().ToMarkdown(DefaultMarkdownOptions())
+	for _, want := range []string{
+		"# Platform Roadmap\n",
+		"| **Level** | portfolio |",
+		"| t1 | Growth |  | 2 |",
+		"### By Theme",
+		"| **Growth** | • ✅ Self-serve signup | • Referrals |",
+		"| **Other** | • Billing \\| invoicing | • Billing \\| invoicing |",
+		"### By Deliverable Type",
+		"### p1: Foundation",
+		"**Schedule:** 2026-01-01 to 2026-03-31",
+		"- ✅ Self-serve signup (e1)",
+		"| m1 | Beta | 2026-03-15 | Foundation |  |",
+		"| dep-1 | SSO |  | Identity |  | Scale |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ToMarkdown() missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "## Staffing") || strings.Contains(md, "## Risks") {
+		t.Errorf("ToMarkdown() rendered empty sections:\n%s", md)
+	}
+}
+
+func TestDocumentJSONRoundTrip(t *testing.T) {
+	doc := testDocument()
+	data, err := doc.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Epics) != 3 || parsed.Epics[2].PhaseIDs[1] != "p2" || parsed.Milestones[0].PhaseID != "p1" {
+		t.Errorf("round trip lost data: %+v", parsed)
+	}
+}
@@ -0,0 +1,188 @@
+package roadmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidationError represents a roadmap document validation issue.
+type ValidationError struct {
+	Path    string // JSON path to the problematic field
+	Message string
+	IsError bool // true for errors, false for warnings
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks the roadmap document for structural issues. Missing
+// metadata, missing or duplicate IDs, and references to undefined themes or
+// phases are errors. Schedule conflicts, dependency and staffing issues,
+// themes without epics, and milestones outside their phase are warnings.
+func (d *Document) Validate() []ValidationError {
+	var errs []ValidationError
+	addError := func(path, msg string) {
+		errs = append(errs, ValidationError{Path: path, Message: msg, IsError: true})
+	}
+	addWarning := func(path, msg string) {
+		errs = append(errs, ValidationError{Path: path, Message: msg})
+	}
+
+	if strings.TrimSpace(d.Metadata.ID) == "" {
+		addError("metadata.id", "Roadmap ID is required")
+	}
+	if strings.TrimSpace(d.Metadata.Title) == "" {
+		addError("metadata.title", "Roadmap title is required")
+	}
+	if d.Metadata.Level != "" && d.Metadata.Level != LevelPortfolio && d.Metadata.Level != LevelProduct {
+		addError("metadata.level", fmt.Sprintf("Invalid level: %s (expected: portfolio, product)", d.Metadata.Level))
+	}
+
+	if len(d.Phases) == 0 {
+		addError("phases", "At least one phase is required")
+	}
+	phases := make(map[string]*Phase, len(d.Phases))
+	for i := range d.Phases {
+		p := &d.Phases[i]
+		path := fmt.Sprintf("phases[%d]", i)
+		switch {
+		case p.ID == "":
+			addError(path+".id", "Phase ID is required")
+		case phases[p.ID] != nil:
+			addError(path+".id", fmt.Sprintf("Duplicate phase ID: %s", p.ID))
+		default:
+			phases[p.ID] = p
+		}
+	}
+	for i, p := range d.Phases {
+		for j, dep := range p.Dependencies {
+			if phases[dep] == nil {
+				addError(fmt.Sprintf("phases[%d].dependencies[%d]", i, j), fmt.Sprintf("Reference to undefined phase: %s", dep))
+			}
+		}
+	}
+
+	themes := make(map[string]bool, len(d.Themes))
+	for i, t := range d.Themes {
+		path := fmt.Sprintf("themes[%d]", i)
+		switch {
+		case t.ID == "":
+			addError(path+".id", "Theme ID is required")
+		case themes[t.ID]:
+			addError(path+".id", fmt.Sprintf("Duplicate theme ID: %s", t.ID))
+		default:
+			themes[t.ID] = true
+		}
+		if t.ID != "" && len(d.EpicsForTheme(t.ID)) == 0 {
+			addWarning(path, fmt.Sprintf("Theme %s has no epics", t.ID))
+		}
+	}
+
+	epics := make(map[string]bool, len(d.Epics))
+	for i, e := range d.Epics {
+		path := fmt.Sprintf("epics[%d]", i)
+		switch {
+		case e.ID == "":
+			addError(path+".id", "Epic ID is required")
+		case epics[e.ID]:
+			addError(path+".id", fmt.Sprintf("Duplicate epic ID: %s", e.ID))
+		default:
+			epics[e.ID] = true
+		}
+		if strings.TrimSpace(e.Title) == "" {
+			addError(path+".title", "Epic title is required")
+		}
+		if e.ThemeID != "" && !themes[e.ThemeID] {
+			addError(path+".themeId", fmt.Sprintf("Reference to undefined theme: %s", e.ThemeID))
+		}
+		if len(e.PhaseIDs) == 0 {
+			addWarning(path+".phaseIds", fmt.Sprintf("Epic %s is not scheduled in any phase", e.ID))
+		}
+		for j, id := range e.PhaseIDs {
+			if phases[id] == nil {
+				addError(fmt.Sprintf("%s.phaseIds[%d]", path, j), fmt.Sprintf("Reference to undefined phase: %s", id))
+			}
+		}
+	}
+
+	milestones := make(map[string]bool, len(d.Milestones))
+	for i, m := range d.Milestones {
+		path := fmt.Sprintf("milestones[%d]", i)
+		switch {
+		case m.ID == "":
+			addError(path+".id", "Milestone ID is required")
+		case milestones[m.ID]:
+			addError(path+".id", fmt.Sprintf("Duplicate milestone ID: %s", m.ID))
+		default:
+			milestones[m.ID] = true
+		}
+		if strings.TrimSpace(m.Title) == "" {
+			addError(path+".title", "Milestone title is required")
+		}
+		var date *time.Time
+		if m.Date != "" {
+			t, err := time.Parse("2006-01-02", m.Date)
+			if err != nil {
+				addError(path+".date", fmt.Sprintf("Invalid date %q: expected YYYY-MM-DD", m.Date))
+			} else {
+				date = &t
+			}
+		}
+		if m.PhaseID == "" {
+			continue
+		}
+		phase := phases[m.PhaseID]
+		if phase == nil {
+			addError(path+".phaseId", fmt.Sprintf("Reference to undefined phase: %s", m.PhaseID))
+			continue
+		}
+		if date != nil && !phase.Contains(*date) {
+			addWarning(path+".date", fmt.Sprintf("Milestone %s is dated %s, outside phase %s (%s)", m.ID, m.Date, phase.ID, phaseDates(phase)))
+		}
+	}
+
+	r := d.Roadmap()
+	for _, issue := range r.ValidateSchedule() {
+		addWarning(issue.Field, issue.Message)
+	}
+	for _, issue := range r.ValidateDependencies(d.Dependencies) {
+		addWarning(issue.Field, issue.Message)
+	}
+	if len(d.Resourcing) > 0 {
+		for _, issue := range r.ValidateResourcing(d.Resourcing) {
+			addWarning(issue.Field, issue.Message)
+		}
+	}
+
+	return errs
+}
+
+// Errors returns only error-level validation results.
+func Errors(errs []ValidationError) []ValidationError {
+	var result []ValidationError
+	for _, e := range errs {
+		if e.IsError {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Warnings returns only warning-level validation results.
+func Warnings(errs []ValidationError) []ValidationError {
+	var result []ValidationError
+	for _, e := range errs {
+		if !e.IsError {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// IsValid returns true if there are no error-level validation issues.
+func IsValid(errs []ValidationError) bool {
+	return len(Errors(errs)) == 0
+}
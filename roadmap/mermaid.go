@@ -0,0 +1,201 @@
+package roadmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+// MermaidGantt returns the roadmap as a Mermaid Gantt chart, with a section
+// per phase holding a bar for the phase and one for each deliverable across
+// the phase window. Completed, in-progress, and delayed or blocked items are
+// tagged done, active, and crit. Phases without both a start and an end date
+// are left out; if no phase has dates, MermaidGantt returns "".
+func (r *Roadmap) MermaidGantt(title string) string {
+	return r.mermaidGantt(title, nil)
+}
+
+// MermaidGantt returns the document's phases as a Mermaid Gantt chart, as
+// Roadmap.MermaidGantt does, with its milestones in a final section.
+// Milestones without a date fall on the end date of their phase.
+func (d *Document) MermaidGantt() string {
+	return d.Roadmap().mermaidGantt(d.Metadata.Title, d.Milestones)
+}
+
+func (r *Roadmap) mermaidGantt(title string, milestones []Milestone) string {
+	var sb strings.Builder
+	ends := make(map[string]time.Time, len(r.Phases))
+	for i := range r.Phases {
+		p := &r.Phases[i]
+		if !p.HasWindow() {
+			continue
+		}
+		ends[p.ID] = *p.EndDate
+		window := formatDate(*p.StartDate) + ", " + formatDate(*p.EndDate)
+		sb.WriteString(fmt.Sprintf("    section %s\n", mermaidTask(p.Name)))
+		sb.WriteString(fmt.Sprintf("    %s :%s%s\n", mermaidTask(p.Name), phaseTags(p.Status), window))
+		for _, del := range p.Deliverables {
+			sb.WriteString(fmt.Sprintf("    %s :%s%s\n", mermaidTask(del.Title), deliverableTags(del.Status), window))
+		}
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+
+	var marks []string
+	for _, m := range milestones {
+		date := m.Date
+		if date == "" {
+			end, ok := ends[m.PhaseID]
+			if !ok {
+				continue
+			}
+			date = formatDate(end)
+		}
+		marks = append(marks, fmt.Sprintf("    %s :milestone, %s%s, 0d\n", mermaidTask(m.Title), deliverableTags(m.Status), date))
+	}
+	if len(marks) > 0 {
+		sb.WriteString("    section Milestones\n")
+		sb.WriteString(strings.Join(marks, ""))
+	}
+
+	var out strings.Builder
+	out.WriteString("gantt\n")
+	if title = mermaidTask(title); title != "" {
+		out.WriteString("    title " + title + "\n")
+	}
+	out.WriteString("    dateFormat YYYY-MM-DD\n")
+	out.WriteString(sb.String())
+	return out.String()
+}
+
+// MermaidDependencyGraph returns a Mermaid flowchart of the phases, with an
+// arrow from each phase to the phases that depend on it, and a dotted arrow
+// from each external dependency to the phase that needs it. Dependencies on
+// unknown phases are left out, as validation reports them. If there are no
+// dependencies, MermaidDependencyGraph returns "".
+func (r *Roadmap) MermaidDependencyGraph(deps []common.Dependency) string {
+	nodes := make(map[string]string, len(r.Phases))
+	for _, p := range r.Phases {
+		if p.ID != "" {
+			nodes[p.ID] = mermaidNodeID("phase", p.ID)
+		}
+	}
+
+	var edges []string
+	for _, p := range r.Phases {
+		for _, dep := range p.Dependencies {
+			if from, ok := nodes[dep]; ok && p.ID != "" {
+				edges = append(edges, fmt.Sprintf("    %s --> %s\n", from, nodes[p.ID]))
+			}
+		}
+	}
+	var external []string
+	for i, dep := range deps {
+		to, ok := nodes[dep.NeededBy]
+		if !ok {
+			continue
+		}
+		id := dep.ID
+		if id == "" {
+			id = fmt.Sprint(i + 1)
+		}
+		node := mermaidNodeID("dep", id)
+		label := dep.Name
+		if label == "" {
+			label = dep.ID
+		}
+		var details []string
+		if dep.Team != "" {
+			details = append(details, dep.Team)
+		}
+		if dep.Status != "" {
+			details = append(details, string(dep.Status.Normalize()))
+		}
+		if len(details) > 0 {
+			label += " (" + strings.Join(details, ", ") + ")"
+		}
+		external = append(external, fmt.Sprintf("    %s[/%s/] -.-> %s\n", node, mermaidLabel(label), to))
+	}
+	if len(edges) == 0 && len(external) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, p := range r.Phases {
+		if p.ID == "" {
+			continue
+		}
+		label := p.ID
+		if p.Name != "" && p.Name != p.ID {
+			label += ": " + p.Name
+		}
+		sb.WriteString(fmt.Sprintf("    %s[%s]\n", nodes[p.ID], mermaidLabel(label)))
+	}
+	sb.WriteString(strings.Join(edges, ""))
+	sb.WriteString(strings.Join(external, ""))
+	return sb.String()
+}
+
+// MermaidDependencyGraph returns the document's phase and external
+// dependencies as a Mermaid flowchart, as Roadmap.MermaidDependencyGraph
+// does.
+func (d *Document) MermaidDependencyGraph() string {
+	return d.Roadmap().MermaidDependencyGraph(d.Dependencies)
+}
+
+func phaseTags(s PhaseStatus) string {
+	switch s {
+	case PhaseStatusCompleted:
+		return "done, "
+	case PhaseStatusInProgress:
+		return "active, "
+	case PhaseStatusDelayed:
+		return "crit, "
+	}
+	return ""
+}
+
+func deliverableTags(s DeliverableStatus) string {
+	switch s {
+	case DeliverableCompleted:
+		return "done, "
+	case DeliverableInProgress:
+		return "active, "
+	case DeliverableBlocked:
+		return "crit, "
+	}
+	return ""
+}
+
+// mermaidTask makes s safe for a Gantt task name, which a colon ends, and
+// for a section or title, which a line break ends.
+func mermaidTask(s string) string {
+	s = strings.NewReplacer(":", " -", "\n", " ", "\r", " ", ";", ",", "#", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// mermaidLabel quotes s as a flowchart node label.
+func mermaidLabel(s string) string {
+	s = strings.NewReplacer(`"`, "#quot;", "\n", " ", "\r", " ").Replace(s)
+	return `"` + strings.TrimSpace(s) + `"`
+}
+
+// mermaidNodeID returns a flowchart node ID for an item ID, which may hold
+// characters Mermaid does not allow in IDs. The prefix keeps phase and
+// dependency IDs apart and avoids keywords such as "end".
+func mermaidNodeID(prefix, id string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix + "_")
+	for _, r := range id {
+		if r < 128 && (r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
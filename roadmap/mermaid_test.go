@@ -0,0 +1,74 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+func TestMermaidGantt(t *testing.T) {
+	d := &Document{
+		Metadata: Metadata{Title: "Platform: 2026"},
+		Phases: []Phase{
+			{ID: "p1", Name: "MVP", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"), Status: PhaseStatusCompleted,
+				Deliverables: []Deliverable{{Title: "Auth", Status: DeliverableCompleted}, {Title: "Billing", Status: DeliverableBlocked}}},
+			{ID: "p2", Name: "Later"},
+			{ID: "p3", Name: "GA", StartDate: date("2026-04-01"), EndDate: date("2026-06-30"), Status: PhaseStatusDelayed},
+		},
+		Milestones: []Milestone{
+			{Title: "Beta", Date: "2026-03-15", Status: DeliverableCompleted},
+			{Title: "Launch", PhaseID: "p3"},
+			{Title: "Someday", PhaseID: "p2"},
+		},
+	}
+	want := `gantt
+    title Platform - 2026
+    dateFormat YYYY-MM-DD
+    section MVP
+    MVP :done, 2026-01-01, 2026-03-31
+    Auth :done, 2026-01-01, 2026-03-31
+    Billing :crit, 2026-01-01, 2026-03-31
+    section GA
+    GA :crit, 2026-04-01, 2026-06-30
+    section Milestones
+    Beta :milestone, done, 2026-03-15, 0d
+    Launch :milestone, 2026-06-30, 0d
+`
+	if got := d.MermaidGantt(); got != want {
+		t.Errorf("MermaidGantt() =\n%s\nwant\n%s", got, want)
+	}
+
+	undated := &Roadmap{Phases: []Phase{{ID: "p1", Name: "MVP"}}}
+	if got := undated.MermaidGantt("x"); got != "" {
+		t.Errorf("MermaidGantt() without dates = %q, want empty", got)
+	}
+}
+
+func TestMermaidDependencyGraph(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{
+		{ID: "phase-1", Name: "MVP"},
+		{ID: "end", Name: `The "end"`, Dependencies: []string{"phase-1", "missing"}},
+	}}
+	deps := []common.Dependency{
+		{ID: "D1", Name: "SSO API", Team: "Identity", Status: "At Risk", NeededBy: "end"},
+		{ID: "D2", Name: "Unplanned"},
+	}
+	want := `flowchart LR
+    phase_phase_1["phase-1: MVP"]
+    phase_end["end: The #quot;end#quot;"]
+    phase_phase_1 --> phase_end
+    dep_D1[/"SSO API (Identity, at_risk)"/] -.-> phase_end
+`
+	if got := r.MermaidDependencyGraph(deps); got != want {
+		t.Errorf("MermaidDependencyGraph() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := r.MermaidDependencyGraph(nil); !strings.Contains(got, "phase_phase_1 --> phase_end") || strings.Contains(got, "dep_") {
+		t.Errorf("MermaidDependencyGraph(nil) =\n%s", got)
+	}
+	independent := &Roadmap{Phases: []Phase{{ID: "p1"}, {ID: "p2"}}}
+	if got := independent.MermaidDependencyGraph(deps); got != "" {
+		t.Errorf("MermaidDependencyGraph() without dependencies = %q, want empty", got)
+	}
+}
@@ -0,0 +1,408 @@
+package roadmap
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScheduleSimulationOptions configures SimulateSchedule.
+type ScheduleSimulationOptions struct {
+	// Iterations is the number of simulated schedules (default: 10000).
+	Iterations int
+
+	// Seed seeds the random numbers; the same seed gives the same result
+	// (default: 1).
+	Seed uint64
+
+	// Start is the date work starts (default: the earliest phase start).
+	Start time.Time
+
+	// Deadline is the date the on-time probability is computed for
+	// (default: the latest phase end).
+	Deadline *time.Time
+}
+
+// ScheduleSimulation is the result of a Monte Carlo schedule simulation:
+// the distribution of the completion date and how much each phase
+// contributes to its uncertainty.
+type ScheduleSimulation struct {
+	Iterations int        `json:"iterations"`
+	Start      time.Time  `json:"start"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+
+	// OnTimeProbability is the share of simulated schedules that finish by
+	// the deadline.
+	OnTimeProbability float64 `json:"onTimeProbability"`
+
+	Percentiles  []DatePercentile     `json:"percentiles"`
+	Distribution []DistributionBucket `json:"distribution"` // Weekly chart data
+	Phases       []PhaseRisk          `json:"phases"`
+}
+
+// DatePercentile is the date by which Percent percent of the simulated
+// schedules finish.
+type DatePercentile struct {
+	Percent int       `json:"percent"`
+	Date    time.Time `json:"date"`
+}
+
+// DistributionBucket is the share of simulated schedules finishing in the
+// week starting WeekOf, and by its end.
+type DistributionBucket struct {
+	WeekOf      time.Time `json:"weekOf"`
+	Probability float64   `json:"probability"`
+	Cumulative  float64   `json:"cumulative"`
+}
+
+// PhaseRisk is a phase's simulated finish and its share of the schedule
+// risk.
+type PhaseRisk struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Estimate *Estimate `json:"estimate,omitempty"`
+	// FixedDays is the duration of a phase without an estimate, from its
+	// start and end dates.
+	FixedDays  float64   `json:"fixedDays,omitempty"`
+	MeanFinish time.Time `json:"meanFinish"`
+	P80Finish  time.Time `json:"p80Finish"`
+
+	// Criticality is the share of simulated schedules in which the phase
+	// is on the critical path.
+	Criticality float64 `json:"criticality"`
+
+	// Contribution is the phase's share of the completion date variance,
+	// from the squared correlation of its duration with the completion
+	// date.
+	Contribution float64 `json:"contribution"`
+}
+
+// schedulePercentiles are the completion date percentiles reported.
+var schedulePercentiles = []int{10, 50, 80, 90, 95}
+
+// SimulateSchedule runs a Monte Carlo simulation of the phases' schedule.
+// Each phase with an estimate takes a duration drawn from a triangular
+// distribution over its optimistic, likely, and pessimistic days; other
+// phases take the days between their start and end dates. A phase starts
+// when its dependencies have finished, but not before its own start date,
+// so slips propagate only through declared dependencies.
+//
+// It returns an error if an estimate is inconsistent, the dependencies
+// form a cycle, or no start date is given or found on a phase.
+func SimulateSchedule(phases []Phase, opts ScheduleSimulationOptions) (*ScheduleSimulation, error) {
+	if opts.Iterations <= 0 {
+		opts.Iterations = 10000
+	}
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+	start := opts.Start
+	deadline := opts.Deadline
+	for _, p := range phases {
+		if opts.Start.IsZero() && p.StartDate != nil && (start.IsZero() || p.StartDate.Before(start)) {
+			start = *p.StartDate
+		}
+		if opts.Deadline == nil && p.EndDate != nil && (deadline == nil || p.EndDate.After(*deadline)) {
+			end := *p.EndDate
+			deadline = &end
+		}
+		if e := p.Estimate; e != nil && (e.Optimistic < 0 || e.Optimistic > e.Likely || e.Likely > e.Pessimistic || e.Pessimistic <= 0) {
+			return nil, fmt.Errorf("phase %s: estimate must have 0 <= optimistic <= likely <= pessimistic, and pessimistic > 0", p.ID)
+		}
+	}
+	if start.IsZero() {
+		return nil, fmt.Errorf("no start date: set one on a phase or in the options")
+	}
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("no phases to simulate")
+	}
+	order, preds, err := phaseOrder(phases)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(phases)
+	earliest := make([]float64, n) // Start constraint of each phase, in days after start
+	fixed := make([]float64, n)
+	for i, p := range phases {
+		if p.StartDate != nil {
+			earliest[i] = math.Max(0, dayCount(p.StartDate.Sub(start)))
+		}
+		if p.Estimate == nil && p.HasWindow() {
+			fixed[i] = math.Max(0, dayCount(p.EndDate.Sub(*p.StartDate)))
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(opts.Seed, 0x5eed))
+	iters := opts.Iterations
+	totals := make([]float64, iters)
+	finishes := make([][]float64, n)
+	for i := range finishes {
+		finishes[i] = make([]float64, iters)
+	}
+	critical := make([]int, n)
+	// Sums for the correlation of each phase's duration with the total.
+	var sumY, sumYY float64
+	sumX, sumXX, sumXY := make([]float64, n), make([]float64, n), make([]float64, n)
+
+	duration := make([]float64, n)
+	finish := make([]float64, n)
+	driver := make([]int, n)
+	for it := 0; it < iters; it++ {
+		for _, i := range order {
+			duration[i] = fixed[i]
+			if e := phases[i].Estimate; e != nil {
+				duration[i] = triangular(rng.Float64(), e.Optimistic, e.Likely, e.Pessimistic)
+			}
+			s, d := earliest[i], -1
+			for _, j := range preds[i] {
+				if finish[j] > s {
+					s, d = finish[j], j
+				}
+			}
+			finish[i], driver[i] = s+duration[i], d
+		}
+		last := 0
+		for i := range finish {
+			finishes[i][it] = finish[i]
+			if finish[i] > finish[last] {
+				last = i
+			}
+		}
+		for i := last; i >= 0; i = driver[i] {
+			critical[i]++
+		}
+		y := finish[last]
+		totals[it] = y
+		sumY += y
+		sumYY += y * y
+		for i := range duration {
+			sumX[i] += duration[i]
+			sumXX[i] += duration[i] * duration[i]
+			sumXY[i] += duration[i] * y
+		}
+	}
+
+	sim := &ScheduleSimulation{Iterations: iters, Start: start, Deadline: deadline}
+	date := func(d float64) time.Time {
+		return start.AddDate(0, 0, int(math.Ceil(d-1e-9)))
+	}
+	sort.Float64s(totals)
+	for _, p := range schedulePercentiles {
+		sim.Percentiles = append(sim.Percentiles, DatePercentile{Percent: p, Date: date(percentile(totals, p))})
+	}
+	if deadline != nil {
+		limit := dayCount(deadline.Sub(start))
+		sim.OnTimeProbability = float64(sort.Search(len(totals), func(k int) bool { return totals[k] > limit+1e-9 })) / float64(iters)
+	}
+	sim.Distribution = weeklyDistribution(totals, date)
+
+	fn := float64(iters)
+	varY := sumYY/fn - (sumY/fn)*(sumY/fn)
+	var totalR2 float64
+	r2 := make([]float64, n)
+	for i := range phases {
+		varX := sumXX[i]/fn - (sumX[i]/fn)*(sumX[i]/fn)
+		if varX > 1e-12 && varY > 1e-12 {
+			cov := sumXY[i]/fn - (sumX[i]/fn)*(sumY/fn)
+			r := cov / math.Sqrt(varX*varY)
+			if r > 0 {
+				r2[i] = r * r
+				totalR2 += r2[i]
+			}
+		}
+	}
+	for i, p := range phases {
+		sort.Float64s(finishes[i])
+		var mean float64
+		for _, f := range finishes[i] {
+			mean += f
+		}
+		risk := PhaseRisk{
+			ID:          p.ID,
+			Name:        p.Name,
+			Estimate:    p.Estimate,
+			MeanFinish:  date(mean / fn),
+			P80Finish:   date(percentile(finishes[i], 80)),
+			Criticality: float64(critical[i]) / fn,
+		}
+		if p.Estimate == nil {
+			risk.FixedDays = fixed[i]
+		}
+		if totalR2 > 0 {
+			risk.Contribution = r2[i] / totalR2
+		}
+		sim.Phases = append(sim.Phases, risk)
+	}
+	return sim, nil
+}
+
+// phaseOrder returns the phases in dependency order and the indexes of
+// each phase's dependencies. Unknown dependency IDs are ignored.
+func phaseOrder(phases []Phase) ([]int, [][]int, error) {
+	index := make(map[string]int, len(phases))
+	for i, p := range phases {
+		index[p.ID] = i
+	}
+	preds := make([][]int, len(phases))
+	succs := make([][]int, len(phases))
+	indegree := make([]int, len(phases))
+	for i, p := range phases {
+		for _, id := range p.Dependencies {
+			if j, ok := index[id]; ok && j != i {
+				preds[i] = append(preds[i], j)
+				succs[j] = append(succs[j], i)
+				indegree[i]++
+			}
+		}
+	}
+	var order, queue []int
+	for i, d := range indegree {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		order = append(order, i)
+		for _, k := range succs[i] {
+			if indegree[k]--; indegree[k] == 0 {
+				queue = append(queue, k)
+			}
+		}
+	}
+	if len(order) < len(phases) {
+		var cycle []string
+		for i, d := range indegree {
+			if d > 0 {
+				cycle = append(cycle, phases[i].ID)
+			}
+		}
+		return nil, nil, fmt.Errorf("phase dependencies form a cycle among %s", strings.Join(cycle, ", "))
+	}
+	return order, preds, nil
+}
+
+// triangular maps u in [0, 1) to the triangular distribution with minimum
+// a, mode c, and maximum b.
+func triangular(u, a, c, b float64) float64 {
+	if b <= a {
+		return a
+	}
+	if u < (c-a)/(b-a) {
+		return a + math.Sqrt(u*(b-a)*(c-a))
+	}
+	return b - math.Sqrt((1-u)*(b-a)*(b-c))
+}
+
+func dayCount(d time.Duration) float64 {
+	return d.Hours() / 24
+}
+
+// percentile returns the p-th percentile of sorted values.
+func percentile(sorted []float64, p int) float64 {
+	k := int(math.Ceil(float64(p)/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(k, len(sorted)-1))]
+}
+
+// weeklyDistribution buckets sorted completion offsets by the week of
+// their date, starting on Mondays.
+func weeklyDistribution(sorted []float64, date func(float64) time.Time) []DistributionBucket {
+	weekOf := func(t time.Time) time.Time {
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	}
+	var buckets []DistributionBucket
+	n := float64(len(sorted))
+	for k, d := range sorted {
+		w := weekOf(date(d))
+		if len(buckets) == 0 || !buckets[len(buckets)-1].WeekOf.Equal(w) {
+			for len(buckets) > 0 && buckets[len(buckets)-1].WeekOf.AddDate(0, 0, 7).Before(w) {
+				prev := buckets[len(buckets)-1]
+				buckets = append(buckets, DistributionBucket{WeekOf: prev.WeekOf.AddDate(0, 0, 7), Cumulative: prev.Cumulative})
+			}
+			buckets = append(buckets, DistributionBucket{WeekOf: w})
+		}
+		b := &buckets[len(buckets)-1]
+		b.Probability += 1 / n
+		b.Cumulative = float64(k+1) / n
+	}
+	return buckets
+}
+
+// Percentile returns the date by which percent percent of the simulated
+// schedules finish, if it is reported.
+func (s *ScheduleSimulation) Percentile(percent int) (time.Time, bool) {
+	for _, p := range s.Percentiles {
+		if p.Percent == percent {
+			return p.Date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ToMarkdown renders the completion date percentiles, the phase risk
+// table, and a Mermaid chart of the weekly distribution.
+func (s *ScheduleSimulation) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d simulated schedules starting %s.\n", s.Iterations, formatDate(s.Start)))
+	if s.Deadline != nil {
+		sb.WriteString(fmt.Sprintf("\n**Probability of finishing by %s: %.0f%%**\n", formatDate(*s.Deadline), s.OnTimeProbability*100))
+	}
+
+	sb.WriteString("\n## Completion Date\n\n")
+	sb.WriteString("| Confidence | Finish By |\n")
+	sb.WriteString("|------------|-----------|\n")
+	for _, p := range s.Percentiles {
+		sb.WriteString(fmt.Sprintf("| P%d | %s |\n", p.Percent, formatDate(p.Date)))
+	}
+
+	sb.WriteString("\n## Phase Risk\n\n")
+	sb.WriteString("| Phase | Estimate (days) | Mean Finish | P80 Finish | Critical | Contribution |\n")
+	sb.WriteString("|-------|-----------------|-------------|------------|----------|--------------|\n")
+	for _, p := range s.Phases {
+		estimate := fmt.Sprintf("%g fixed", p.FixedDays)
+		if e := p.Estimate; e != nil {
+			estimate = fmt.Sprintf("%g / %g / %g", e.Optimistic, e.Likely, e.Pessimistic)
+		}
+		name := p.Name
+		if name == "" {
+			name = p.ID
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %.0f%% | %.0f%% |\n",
+			name, estimate, formatDate(p.MeanFinish), formatDate(p.P80Finish), p.Criticality*100, p.Contribution*100))
+	}
+	sb.WriteString("\nEstimates are optimistic / likely / pessimistic. Critical is the share of schedules in which the phase is on the critical path; contribution is its share of the completion date variance.\n")
+
+	if len(s.Distribution) > 1 {
+		var labels, bars, line []string
+		for _, b := range s.Distribution {
+			labels = append(labels, fmt.Sprintf("%q", b.WeekOf.Format("Jan 2")))
+			bars = append(bars, fmt.Sprintf("%.1f", b.Probability*100))
+			line = append(line, fmt.Sprintf("%.1f", b.Cumulative*100))
+		}
+		sb.WriteString("\n## Distribution\n\n")
+		sb.WriteString("```mermaid\nxychart-beta\n")
+		sb.WriteString("    title \"Completion week (bars) and cumulative probability (line)\"\n")
+		sb.WriteString("    x-axis [" + strings.Join(labels, ", ") + "]\n")
+		sb.WriteString("    y-axis \"Probability (%)\" 0 --> 100\n")
+		sb.WriteString("    bar [" + strings.Join(bars, ", ") + "]\n")
+		sb.WriteString("    line [" + strings.Join(line, ", ") + "]\n")
+		sb.WriteString("```\n")
+	}
+	return sb.String()
+}
+
+// ToCSV returns the weekly distribution as chart data: the week, the
+// probability of finishing in it, and of finishing by its end.
+func (s *ScheduleSimulation) ToCSV() string {
+	var sb strings.Builder
+	sb.WriteString("week_of,probability,cumulative\n")
+	for _, b := range s.Distribution {
+		sb.WriteString(fmt.Sprintf("%s,%.4f,%.4f\n", formatDate(b.WeekOf), b.Probability, b.Cumulative))
+	}
+	return sb.String()
+}
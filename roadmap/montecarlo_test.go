@@ -0,0 +1,107 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func simulationPhases() []Phase {
+	return []Phase{
+		{ID: "design", Name: "Design", StartDate: date("2026-01-05"), EndDate: date("2026-01-19")},
+		{ID: "build", Name: "Build", StartDate: date("2026-01-19"), EndDate: date("2026-03-02"), Dependencies: []string{"design"},
+			Estimate: &Estimate{Optimistic: 30, Likely: 42, Pessimistic: 80}},
+		{ID: "docs", Name: "Docs", StartDate: date("2026-01-19"), EndDate: date("2026-02-02"), Dependencies: []string{"design"},
+			Estimate: &Estimate{Optimistic: 10, Likely: 14, Pessimistic: 16}},
+		{ID: "launch", Name: "Launch", StartDate: date("2026-03-02"), EndDate: date("2026-03-09"), Dependencies: []string{"build", "docs"},
+			Estimate: &Estimate{Optimistic: 5, Likely: 7, Pessimistic: 9}},
+	}
+}
+
+func TestSimulateSchedule(t *testing.T) {
+	sim, err := SimulateSchedule(simulationPhases(), ScheduleSimulationOptions{Iterations: 5000})
+	if err != nil {
+		t.Fatalf("SimulateSchedule: %v", err)
+	}
+	if !sim.Start.Equal(*date("2026-01-05")) || !sim.Deadline.Equal(*date("2026-03-09")) {
+		t.Errorf("start, deadline = %s, %s", formatDate(sim.Start), formatDate(*sim.Deadline))
+	}
+
+	// Fastest: 14 + 30 + 5 days; slowest: 14 + 80 + 9 days.
+	p10, _ := sim.Percentile(10)
+	p95, _ := sim.Percentile(95)
+	if p10.Before(*date("2026-02-22")) || p95.After(*date("2026-04-19")) || !p10.Before(p95) {
+		t.Errorf("P10 = %s, P95 = %s", formatDate(p10), formatDate(p95))
+	}
+	// The build estimate is skewed late, so the plan is less likely than not.
+	if sim.OnTimeProbability <= 0 || sim.OnTimeProbability >= 0.5 {
+		t.Errorf("OnTimeProbability = %.2f, want between 0 and 0.5", sim.OnTimeProbability)
+	}
+
+	risk := map[string]PhaseRisk{}
+	for _, p := range sim.Phases {
+		risk[p.ID] = p
+	}
+	// Build drives Launch only when it overruns Launch's planned start.
+	if risk["build"].Criticality < 0.5 || risk["build"].Criticality == 1 || risk["docs"].Criticality != 0 || risk["launch"].Criticality != 1 {
+		t.Errorf("criticality build/docs/launch = %.2f/%.2f/%.2f", risk["build"].Criticality, risk["docs"].Criticality, risk["launch"].Criticality)
+	}
+	if risk["build"].Contribution < 0.9 || risk["design"].Contribution != 0 {
+		t.Errorf("contribution build = %.2f, design = %.2f", risk["build"].Contribution, risk["design"].Contribution)
+	}
+	if risk["design"].FixedDays != 14 {
+		t.Errorf("design FixedDays = %g, want 14", risk["design"].FixedDays)
+	}
+
+	last := sim.Distribution[len(sim.Distribution)-1]
+	if last.Cumulative < 0.9999 {
+		t.Errorf("last cumulative = %f, want 1", last.Cumulative)
+	}
+	for i := 1; i < len(sim.Distribution); i++ {
+		if sim.Distribution[i].WeekOf.Sub(sim.Distribution[i-1].WeekOf) != 7*24*time.Hour {
+			t.Fatalf("buckets %d and %d are not a week apart", i-1, i)
+		}
+	}
+}
+
+func TestSimulateScheduleDeterministic(t *testing.T) {
+	a, _ := SimulateSchedule(simulationPhases(), ScheduleSimulationOptions{Iterations: 500, Seed: 7})
+	b, _ := SimulateSchedule(simulationPhases(), ScheduleSimulationOptions{Iterations: 500, Seed: 7})
+	if a.ToCSV() != b.ToCSV() || a.OnTimeProbability != b.OnTimeProbability {
+		t.Error("same seed gave different results")
+	}
+}
+
+func TestSimulateScheduleErrors(t *testing.T) {
+	cycle := simulationPhases()
+	cycle[0].Dependencies = []string{"launch"}
+	if _, err := SimulateSchedule(cycle, ScheduleSimulationOptions{}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("cycle: err = %v", err)
+	}
+
+	bad := simulationPhases()
+	bad[1].Estimate = &Estimate{Optimistic: 50, Likely: 42, Pessimistic: 80}
+	if _, err := SimulateSchedule(bad, ScheduleSimulationOptions{}); err == nil || !strings.Contains(err.Error(), "build") {
+		t.Errorf("bad estimate: err = %v", err)
+	}
+
+	if _, err := SimulateSchedule([]Phase{{ID: "a"}}, ScheduleSimulationOptions{}); err == nil {
+		t.Error("no start date: want error")
+	}
+}
+
+func TestScheduleSimulationToMarkdown(t *testing.T) {
+	sim, err := SimulateSchedule(simulationPhases(), ScheduleSimulationOptions{Iterations: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := sim.ToMarkdown()
+	for _, want := range []string{"## Completion Date", "| P80 |", "## Phase Risk", "| Design | 14 fixed |", "| Build | 30 / 42 / 80 |", "xychart-beta", "Probability of finishing by 2026-03-09"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+	if !strings.HasPrefix(sim.ToCSV(), "week_of,probability,cumulative\n") {
+		t.Error("CSV header missing")
+	}
+}
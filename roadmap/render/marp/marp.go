@@ -0,0 +1,289 @@
+// Package marp provides a Marp markdown renderer for roadmap documents,
+// with one slide per calendar quarter.
+// See https://marp.app/ for more information.
+package marp
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	sdmarp "github.com/grokify/structureddocs/marp"
+
+	"github.com/grokify/structured-plan/render"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+// Options configures roadmap slides. They are the common render options, of
+// which the slides use the theme.
+type Options = render.Options
+
+// DefaultOptions returns the default slide options.
+func DefaultOptions() *Options {
+	return &Options{Theme: render.ThemeDefault}
+}
+
+// Renderer renders roadmap documents as Marp slides.
+type Renderer struct{}
+
+// New creates a new roadmap Marp renderer.
+func New() *Renderer {
+	return &Renderer{}
+}
+
+// Format returns the output format name.
+func (r *Renderer) Format() string {
+	return "marp"
+}
+
+// FileExtension returns the file extension for Marp output.
+func (r *Renderer) FileExtension() string {
+	return ".md"
+}
+
+// Render converts a roadmap to Marp markdown slides: a title slide with the
+// vision, the themes, one slide per calendar quarter with the phases active
+// in it and the milestones due in it, the phases without dates, and the
+// risks.
+func (r *Renderer) Render(doc *roadmap.Document, opts *Options) ([]byte, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	quarters, unscheduled := Quarters(doc)
+	data := &templateData{
+		Roadmap:     doc,
+		Theme:       sdmarp.GetTheme(opts.Theme),
+		Date:        time.Now().Format("January 2, 2006"),
+		Quarters:    quarters,
+		Unscheduled: unscheduled,
+	}
+
+	var buf bytes.Buffer
+	if err := roadmapTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering roadmap slides: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Quarter is a calendar quarter of the roadmap.
+type Quarter struct {
+	Label      string // e.g. "Q1 2026"
+	Start, End time.Time
+	Phases     []roadmap.Phase     // Phases that overlap the quarter
+	Milestones []roadmap.Milestone // Milestones dated in the quarter
+}
+
+// Quarters returns the calendar quarters from the earliest to the latest
+// dated phase or milestone, skipping quarters with neither, and the phases
+// without a start or end date. A phase that spans several quarters is on
+// each of them.
+func Quarters(doc *roadmap.Document) ([]Quarter, []roadmap.Phase) {
+	var unscheduled []roadmap.Phase
+	byStart := make(map[time.Time]*Quarter)
+	quarter := func(t time.Time) *Quarter {
+		start := quarterStart(t)
+		q, ok := byStart[start]
+		if !ok {
+			q = &Quarter{
+				Label: fmt.Sprintf("Q%d %d", (int(start.Month())-1)/3+1, start.Year()),
+				Start: start,
+				End:   start.AddDate(0, 3, -1),
+			}
+			byStart[start] = q
+		}
+		return q
+	}
+
+	for _, p := range doc.Phases {
+		if p.StartDate == nil || p.EndDate == nil || p.StartDate.IsZero() || p.EndDate.IsZero() {
+			unscheduled = append(unscheduled, p)
+			continue
+		}
+		end := quarterStart(*p.EndDate)
+		for start := quarterStart(*p.StartDate); !start.After(end); start = start.AddDate(0, 3, 0) {
+			q := quarter(start)
+			q.Phases = append(q.Phases, p)
+		}
+	}
+	for _, m := range doc.Milestones {
+		date, err := time.Parse("2006-01-02", m.Date)
+		if err != nil {
+			continue
+		}
+		q := quarter(date)
+		q.Milestones = append(q.Milestones, m)
+	}
+
+	quarters := make([]Quarter, 0, len(byStart))
+	for _, q := range byStart {
+		sort.SliceStable(q.Milestones, func(i, j int) bool { return q.Milestones[i].Date < q.Milestones[j].Date })
+		quarters = append(quarters, *q)
+	}
+	sort.Slice(quarters, func(i, j int) bool { return quarters[i].Start.Before(quarters[j].Start) })
+	return quarters, unscheduled
+}
+
+func quarterStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// templateData holds data for roadmap template rendering.
+type templateData struct {
+	Roadmap     *roadmap.Document
+	Theme       sdmarp.ThemeConfig
+	Date        string
+	Quarters    []Quarter
+	Unscheduled []roadmap.Phase
+}
+
+var funcMap = mergeFuncMaps(sdmarp.CommonFuncMap, template.FuncMap{
+	"cell": func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+	},
+	"date": func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format("Jan 2, 2006")
+	},
+	"str": func(v any) string { return fmt.Sprint(v) },
+	"progress": func(p *int) string {
+		if p == nil {
+			return ""
+		}
+		return fmt.Sprintf(" (%d%%)", *p)
+	},
+})
+
+// mergeFuncMaps merges multiple template.FuncMaps into one.
+// Later maps override earlier ones for duplicate keys.
+func mergeFuncMaps(maps ...template.FuncMap) template.FuncMap {
+	result := make(template.FuncMap)
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+var roadmapTmpl = template.Must(template.New("roadmap").Funcs(funcMap).Parse(`---
+marp: true
+theme: {{.Theme.Name}}
+paginate: true
+{{- if .Roadmap.Metadata.Title}}
+header: "Roadmap | {{.Roadmap.Metadata.Title}}"
+{{- end}}
+footer: "{{.Roadmap.Metadata.ID}}{{if .Roadmap.Metadata.Version}} | v{{.Roadmap.Metadata.Version}}{{end}}"
+style: |
+  section {
+    font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+  }
+  section.title {
+    text-align: center;
+    background: linear-gradient(135deg, {{.Theme.PrimaryBgColor}} 0%, {{.Theme.AccentColor}} 100%);
+    color: {{.Theme.PrimaryTextColor}};
+  }
+  section.title h1 {
+    font-size: 2.5em;
+    color: {{.Theme.PrimaryTextColor}};
+  }
+  section.quarter h2 {
+    border-bottom: 4px solid {{.Theme.AccentColor}};
+  }
+  table {
+    font-size: 0.8em;
+    width: 100%;
+  }
+  th {
+    background: #f7fafc;
+  }
+  blockquote {
+    font-size: 1.2em;
+    border-left: 4px solid {{.Theme.AccentColor}};
+    padding-left: 1em;
+    font-style: italic;
+  }
+---
+
+<!-- _class: title -->
+
+# {{.Roadmap.Metadata.Title}}
+
+**Roadmap**
+{{- with .Roadmap.Metadata}}
+{{- if .Owner}}
+**Owner:** {{.Owner}}{{if .Team}} ({{.Team}}){{end}}
+{{- end}}
+{{- if or .Version .Status}}
+{{if .Version}}**Version:** {{.Version}}{{end}}{{if and .Version .Status}} | {{end}}{{if .Status}}**Status:** {{.Status}}{{end}}
+{{- end}}
+{{- end}}
+**Date:** {{.Date}}
+{{- if .Roadmap.Vision}}
+
+> {{.Roadmap.Vision}}
+{{- end}}
+
+---
+{{- if .Roadmap.Themes}}
+
+## Themes
+
+{{range .Roadmap.Themes -}}
+- **{{.Name}}**{{if .Description}}: {{.Description}}{{end}}
+{{end}}
+---
+{{- end}}
+{{- range .Quarters}}
+
+<!-- _class: quarter -->
+
+## {{.Label}}
+{{- if .Phases}}
+
+| Phase | Dates | Status | Deliverables |
+|-------|-------|--------|--------------|
+{{- range .Phases}}
+| **{{cell .Name}}** | {{date .StartDate}} – {{date .EndDate}} | {{if .Status}}{{statusIcon (str .Status)}} {{.Status}}{{else}}-{{end}}{{progress .Progress}} | {{range $i, $d := .Deliverables}}{{if $i}}<br>{{end}}{{if $d.Status}}{{statusIcon (str $d.Status)}} {{end}}{{cell $d.Title}}{{else}}-{{end}} |
+{{- end}}
+{{- end}}
+{{- if .Milestones}}
+
+**Milestones**
+
+{{range .Milestones -}}
+- {{if .Status}}{{statusIcon (str .Status)}} {{end}}**{{.Date}}** {{.Title}}
+{{end}}
+{{- end}}
+
+---
+{{- end}}
+{{- if .Unscheduled}}
+
+## Unscheduled
+
+| Phase | Status | Deliverables |
+|-------|--------|--------------|
+{{- range .Unscheduled}}
+| **{{cell .Name}}** | {{if .Status}}{{statusIcon (str .Status)}} {{.Status}}{{else}}-{{end}} | {{range $i, $d := .Deliverables}}{{if $i}}<br>{{end}}{{cell $d.Title}}{{else}}-{{end}} |
+{{- end}}
+
+---
+{{- end}}
+{{- if .Roadmap.Risks}}
+
+## Risks & Mitigations
+
+| Risk | Impact | Mitigation |
+|------|--------|------------|
+{{- range .Roadmap.Risks}}
+| {{cell .Description}} | **{{.Impact}}** | {{if .Mitigation}}{{cell (truncate .Mitigation 50)}}{{else}}-{{end}} |
+{{- end}}
+
+---
+{{- end}}
+`))
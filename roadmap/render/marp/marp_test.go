@@ -0,0 +1,84 @@
+package marp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+func date(s string) *time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return &t
+}
+
+func TestQuarters(t *testing.T) {
+	doc := &roadmap.Document{
+		Phases: []roadmap.Phase{
+			{ID: "p1", Name: "Beta", StartDate: date("2026-02-01"), EndDate: date("2026-05-15")},
+			{ID: "p2", Name: "GA", StartDate: date("2026-10-01"), EndDate: date("2026-12-31")},
+			{ID: "p3", Name: "Later"},
+		},
+		Milestones: []roadmap.Milestone{
+			{ID: "m2", Title: "Launch", Date: "2026-12-01"},
+			{ID: "m1", Title: "Preview", Date: "2026-11-01"},
+			{ID: "m3", Title: "Undated"},
+		},
+	}
+
+	quarters, unscheduled := Quarters(doc)
+	var labels []string
+	for _, q := range quarters {
+		labels = append(labels, q.Label)
+	}
+	if got, want := strings.Join(labels, ","), "Q1 2026,Q2 2026,Q4 2026"; got != want {
+		t.Errorf("quarters = %s, want %s", got, want)
+	}
+	if len(quarters[0].Phases) != 1 || len(quarters[1].Phases) != 1 || quarters[1].Phases[0].ID != "p1" {
+		t.Errorf("phase p1 should be on Q1 and Q2")
+	}
+	if ms := quarters[2].Milestones; len(ms) != 2 || ms[0].ID != "m1" {
+		t.Errorf("Q4 milestones = %v, want m1 then m2", ms)
+	}
+	if len(unscheduled) != 1 || unscheduled[0].ID != "p3" {
+		t.Errorf("unscheduled = %v, want p3", unscheduled)
+	}
+}
+
+func TestRender(t *testing.T) {
+	progress := 40
+	doc := &roadmap.Document{
+		Metadata: roadmap.Metadata{ID: "rm-1", Title: "Platform 2026", Version: "1.0"},
+		Vision:   "Ship agents in a week.",
+		Phases: []roadmap.Phase{{
+			ID: "p1", Name: "Beta", StartDate: date("2026-04-01"), EndDate: date("2026-06-30"),
+			Status: roadmap.PhaseStatusInProgress, Progress: &progress,
+			Deliverables: []roadmap.Deliverable{
+				{Title: "Sandbox", Status: roadmap.DeliverableCompleted},
+				{Title: "Scaler"},
+			},
+		}},
+		Milestones: []roadmap.Milestone{{Title: "Beta open", Date: "2026-05-01"}},
+	}
+
+	out, err := New().Render(doc, &Options{Theme: "corporate"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	content := string(out)
+	for _, want := range []string{
+		"marp: true",
+		"> Ship agents in a week.",
+		"## Q2 2026",
+		"| **Beta** | Apr 1, 2026 – Jun 30, 2026 | 🚧 in_progress (40%) | ✅ Sandbox<br>Scaler |",
+		"- **2026-05-01** Beta open",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(content, "## Unscheduled") || strings.Contains(content, "## Themes") {
+		t.Error("output has empty slides")
+	}
+}
@@ -0,0 +1,246 @@
+package roadmap
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RoleFunction is the discipline a role belongs to.
+type RoleFunction string
+
+const (
+	FunctionEngineering RoleFunction = "engineering"
+	FunctionProduct     RoleFunction = "product"
+	FunctionDesign      RoleFunction = "design"
+	FunctionQA          RoleFunction = "qa"
+	FunctionData        RoleFunction = "data"
+	FunctionOperations  RoleFunction = "operations"
+	FunctionOther       RoleFunction = "other"
+)
+
+// Allocation assigns people in a role to a roadmap phase.
+type Allocation struct {
+	Role     string       `json:"role"`               // e.g., "Backend Engineer"
+	Function RoleFunction `json:"function,omitempty"` // Inferred from Role when empty
+	Count    int          `json:"count"`
+	PhaseID  string       `json:"phaseId"`
+
+	// Percent is the share of each person's time allocated, 1-100.
+	// Defaults to 100.
+	Percent float64 `json:"percent,omitempty"`
+
+	Team string `json:"team,omitempty"`
+
+	// StartDate and EndDate narrow the allocation within the phase
+	// (YYYY-MM-DD).
+	StartDate string `json:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty"`
+
+	Notes string `json:"notes,omitempty"`
+}
+
+// engineeringRoleWords identify engineering roles when Function is not set.
+var engineeringRoleWords = []string{"engineer", "developer", "programmer", "sre", "architect", "devops", "swe"}
+
+// RoleFunction returns Function, or a function inferred from Role: roles
+// naming an engineer, developer, architect, SRE, or DevOps are engineering;
+// others are FunctionOther.
+func (a Allocation) RoleFunction() RoleFunction {
+	if a.Function != "" {
+		return RoleFunction(strings.ToLower(string(a.Function)))
+	}
+	role := strings.ToLower(a.Role)
+	for _, w := range engineeringRoleWords {
+		if strings.Contains(role, w) {
+			return FunctionEngineering
+		}
+	}
+	return FunctionOther
+}
+
+// FTE returns the full-time equivalent headcount: Count scaled by Percent.
+func (a Allocation) FTE() float64 {
+	if a.Percent == 0 {
+		return float64(a.Count)
+	}
+	return float64(a.Count) * a.Percent / 100
+}
+
+// ValidateResourcing checks allocations against the roadmap:
+//
+//   - each allocation names a role, a positive count, and a phase in the
+//     roadmap, with a percent between 1 and 100;
+//   - allocation dates are valid, in order, and within the phase dates;
+//   - every phase with deliverables has engineering allocated to it.
+//
+// Issue fields are relative to the allocation list, e.g. "resourcing[2]",
+// except for unstaffed phases, which are reported as "phases[i]".
+func (r *Roadmap) ValidateResourcing(allocs []Allocation) []ScheduleIssue {
+	var issues []ScheduleIssue
+	phases := make(map[string]*Phase, len(r.Phases))
+	for i := range r.Phases {
+		if r.Phases[i].ID != "" {
+			phases[r.Phases[i].ID] = &r.Phases[i]
+		}
+	}
+
+	engineering := map[string]float64{}
+	for i, a := range allocs {
+		field := fmt.Sprintf("resourcing[%d]", i)
+		add := func(field, msg string) {
+			issues = append(issues, ScheduleIssue{PhaseID: a.PhaseID, Field: field, Message: msg})
+		}
+
+		label := a.Role
+		if label == "" {
+			add(field+".role", "Allocation has no role")
+			label = field
+		}
+		if a.Count <= 0 {
+			add(field+".count", fmt.Sprintf("Allocation for %s has count %d; must be positive", label, a.Count))
+		}
+		if a.Percent < 0 || a.Percent > 100 {
+			add(field+".percent", fmt.Sprintf("Allocation for %s is %s%%; must be between 1 and 100", label, formatFTE(a.Percent)))
+		}
+
+		start := parseAllocationDate(a.StartDate, field+".startDate", add)
+		end := parseAllocationDate(a.EndDate, field+".endDate", add)
+		if start != nil && end != nil && end.Before(*start) {
+			add(field+".endDate", fmt.Sprintf("Allocation for %s ends (%s) before it starts (%s)", label, a.EndDate, a.StartDate))
+		}
+
+		if a.PhaseID == "" {
+			add(field+".phaseId", fmt.Sprintf("Allocation for %s has no phase", label))
+			continue
+		}
+		phase, ok := phases[a.PhaseID]
+		if !ok {
+			add(field+".phaseId", fmt.Sprintf("Reference to undefined phase: %s", a.PhaseID))
+			continue
+		}
+		if start != nil && !phase.Contains(*start) {
+			add(field+".startDate", fmt.Sprintf("Allocation for %s starts %s, outside phase %s (%s)", label, a.StartDate, phase.ID, phaseDates(phase)))
+		}
+		if end != nil && !phase.Contains(*end) {
+			add(field+".endDate", fmt.Sprintf("Allocation for %s ends %s, outside phase %s (%s)", label, a.EndDate, phase.ID, phaseDates(phase)))
+		}
+		if a.RoleFunction() == FunctionEngineering {
+			engineering[a.PhaseID] += a.FTE()
+		}
+	}
+
+	for i := range r.Phases {
+		p := &r.Phases[i]
+		if len(p.Deliverables) > 0 && engineering[p.ID] <= 0 && p.Status != PhaseStatusCancelled {
+			issues = append(issues, ScheduleIssue{
+				PhaseID: p.ID,
+				Field:   fmt.Sprintf("phases[%d]", i),
+				Message: fmt.Sprintf("Phase %s has %d deliverables but no engineering roles allocated", p.ID, len(p.Deliverables)),
+			})
+		}
+	}
+	return issues
+}
+
+func parseAllocationDate(s, field string, add func(field, msg string)) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		add(field, fmt.Sprintf("Invalid date %q: expected YYYY-MM-DD", s))
+		return nil
+	}
+	return &t
+}
+
+func phaseDates(p *Phase) string {
+	start, end := "open", "open"
+	if p.StartDate != nil {
+		start = formatDate(*p.StartDate)
+	}
+	if p.EndDate != nil {
+		end = formatDate(*p.EndDate)
+	}
+	return start + " to " + end
+}
+
+// FormatStaffingTable renders allocations as a markdown table of FTE by role
+// and phase, with engineering and overall totals per phase. Allocations for
+// phases not in the roadmap are omitted.
+func (r *Roadmap) FormatStaffingTable(allocs []Allocation) string {
+	var phases []*Phase
+	for i := range r.Phases {
+		p := &r.Phases[i]
+		for _, a := range allocs {
+			if a.PhaseID == p.ID {
+				phases = append(phases, p)
+				break
+			}
+		}
+	}
+	if len(phases) == 0 {
+		return ""
+	}
+
+	var roles []string
+	fte := map[string]map[string]float64{}
+	engineering := map[string]float64{}
+	total := map[string]float64{}
+	shown := make(map[string]bool, len(phases))
+	for _, p := range phases {
+		shown[p.ID] = true
+	}
+	for _, a := range allocs {
+		if !shown[a.PhaseID] {
+			continue
+		}
+		if _, ok := fte[a.Role]; !ok {
+			roles = append(roles, a.Role)
+			fte[a.Role] = map[string]float64{}
+		}
+		fte[a.Role][a.PhaseID] += a.FTE()
+		total[a.PhaseID] += a.FTE()
+		if a.RoleFunction() == FunctionEngineering {
+			engineering[a.PhaseID] += a.FTE()
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| Role |")
+	sep := "|------|"
+	for _, p := range phases {
+		name := p.Name
+		if name == "" {
+			name = p.ID
+		}
+		sb.WriteString(" " + escapeCell(name) + " |")
+		sep += "------:|"
+	}
+	sb.WriteString("\n" + sep + "\n")
+
+	row := func(label string, values map[string]float64) {
+		sb.WriteString("| " + label + " |")
+		for _, p := range phases {
+			sb.WriteString(" " + formatFTE(values[p.ID]) + " |")
+		}
+		sb.WriteString("\n")
+	}
+	for _, role := range roles {
+		row(escapeCell(role), fte[role])
+	}
+	row("*Engineering*", engineering)
+	row("**Total FTE**", total)
+	return sb.String()
+}
+
+// formatFTE formats a headcount with at most two decimals, or "-" for zero.
+func formatFTE(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
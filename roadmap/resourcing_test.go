@@ -0,0 +1,62 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateResourcing(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{
+		{ID: "p1", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"),
+			Deliverables: []Deliverable{{ID: "d1"}}},
+		{ID: "p2", StartDate: date("2026-04-01"), EndDate: date("2026-06-30"),
+			Deliverables: []Deliverable{{ID: "d2"}, {ID: "d3"}}},
+		{ID: "p3", Deliverables: []Deliverable{{ID: "d4"}}, Status: PhaseStatusCancelled},
+	}}
+	allocs := []Allocation{
+		{Role: "Backend Engineer", Count: 2, PhaseID: "p1", Percent: 50},
+		// Designer is not engineering, so p2 is unstaffed.
+		{Role: "Product Designer", Count: 1, PhaseID: "p2", StartDate: "2026-03-01"},
+		{Role: "QA Analyst", Count: 0, PhaseID: "p9", Percent: 120},
+		{Count: 1, PhaseID: "p1", StartDate: "2026-02-01", EndDate: "2026-01-15"},
+		{Role: "PM", Count: 1, StartDate: "soon"},
+	}
+
+	var got []string
+	for _, issue := range r.ValidateResourcing(allocs) {
+		got = append(got, issue.Field+": "+issue.Message)
+	}
+	want := []string{
+		"resourcing[1].startDate: Allocation for Product Designer starts 2026-03-01, outside phase p2 (2026-04-01 to 2026-06-30)",
+		"resourcing[2].count: Allocation for QA Analyst has count 0; must be positive",
+		"resourcing[2].percent: Allocation for QA Analyst is 120%; must be between 1 and 100",
+		"resourcing[2].phaseId: Reference to undefined phase: p9",
+		"resourcing[3].role: Allocation has no role",
+		"resourcing[3].endDate: Allocation for resourcing[3] ends (2026-01-15) before it starts (2026-02-01)",
+		`resourcing[4].startDate: Invalid date "soon": expected YYYY-MM-DD`,
+		"resourcing[4].phaseId: Allocation for PM has no phase",
+		"phases[1]: Phase p2 has 2 deliverables but no engineering roles allocated",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateResourcing() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFormatStaffingTable(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{{ID: "p1", Name: "MVP"}, {ID: "p2", Name: "GA"}, {ID: "p3", Name: "Later"}}}
+	allocs := []Allocation{
+		{Role: "Backend Engineer", Count: 2, PhaseID: "p1"},
+		{Role: "Backend Engineer", Count: 3, PhaseID: "p2", Percent: 50},
+		{Role: "Designer", Count: 1, PhaseID: "p2", Function: FunctionDesign},
+		{Role: "Ghost", Count: 1, PhaseID: "p9"},
+	}
+	want := "| Role | MVP | GA |\n" +
+		"|------|------:|------:|\n" +
+		"| Backend Engineer | 2 | 1.5 |\n" +
+		"| Designer | - | 1 |\n" +
+		"| *Engineering* | 2 | 1.5 |\n" +
+		"| **Total FTE** | 2 | 2.5 |\n"
+	if got := r.FormatStaffingTable(allocs); got != want {
+		t.Errorf("FormatStaffingTable() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -2,7 +2,11 @@
 // Roadmaps can be used standalone or embedded in PRD/MRD/TRD documents.
 package roadmap
 
-import "time"
+import (
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+)
 
 // Roadmap contains the product roadmap with phases.
 type Roadmap struct {
@@ -31,11 +35,22 @@ type Phase struct {
 	Deliverables    []Deliverable `json:"deliverables"`
 	SuccessCriteria []string      `json:"successCriteria"`
 	Dependencies    []string      `json:"dependencies,omitempty"` // Dependent phase IDs
+	Parallel        bool          `json:"parallel,omitempty"`     // Intentionally overlaps other phases
 	Risks           []Risk        `json:"risks,omitempty"`
 	Status          PhaseStatus   `json:"status,omitempty"`
 	Progress        *int          `json:"progress,omitempty"` // 0-100 percentage
+	Estimate        *Estimate     `json:"estimate,omitempty"` // Three-point duration estimate for schedule simulation
 	Tags            []string      `json:"tags,omitempty"`     // For filtering by topic/domain
 	Notes           string        `json:"notes,omitempty"`
+	Cost            *common.Cost  `json:"cost,omitempty"`
+}
+
+// Estimate is a three-point estimate of a phase's duration in calendar
+// days, used by schedule risk simulation.
+type Estimate struct {
+	Optimistic  float64 `json:"optimistic"`
+	Likely      float64 `json:"likely"`
+	Pessimistic float64 `json:"pessimistic"`
 }
 
 // PhaseStatus represents the current status of a phase.
@@ -56,7 +71,8 @@ type Deliverable struct {
 	Description string            `json:"description"`
 	Type        DeliverableType   `json:"type"`
 	Status      DeliverableStatus `json:"status,omitempty"`
-	Tags        []string          `json:"tags,omitempty"` // For filtering by topic/domain
+	DueDate     string            `json:"dueDate,omitempty"` // YYYY-MM-DD; defaults to the phase end date
+	Tags        []string          `json:"tags,omitempty"`    // For filtering by topic/domain
 }
 
 // DeliverableType represents types of deliverables.
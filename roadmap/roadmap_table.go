@@ -11,6 +11,9 @@ import (
 type TableOptions struct {
 	// IncludeStatus adds status indicators to deliverables
 	IncludeStatus bool
+	// TextStatus shows status indicators as bracketed words, such as
+	// "[done]", instead of emoji
+	TextStatus bool
 	// IncludeEmptySwimlanes shows rows even if no deliverables of that type exist
 	IncludeEmptySwimlanes bool
 	// SwimlaneOrder specifies the order of swimlanes (nil = alphabetical)
@@ -120,7 +123,7 @@ func (r *Roadmap) ToSwimlaneTable(opts TableOptions) string {
 						item = item[:opts.MaxTitleLen-3] + "..."
 					}
 					if opts.IncludeStatus && del.Status != "" {
-						item = fmt.Sprintf("%s %s", StatusIcon(del.Status), item)
+						item = fmt.Sprintf("%s %s", opts.StatusMarker(del.Status), item)
 					}
 					// Add bullet point prefix
 					items = append(items, "• "+item)
@@ -164,7 +167,7 @@ func (r *Roadmap) ToPhaseTable(opts TableOptions) string {
 				item = item[:opts.MaxTitleLen-3] + "..."
 			}
 			if opts.IncludeStatus && del.Status != "" {
-				item = fmt.Sprintf("%s %s", StatusIcon(del.Status), item)
+				item = fmt.Sprintf("%s %s", opts.StatusMarker(del.Status), item)
 			}
 			// Add bullet point prefix
 			items = append(items, "• "+item)
@@ -224,6 +227,52 @@ func StatusIcon(status DeliverableStatus) string {
 	}
 }
 
+// StatusText returns a bracketed word for the deliverable status, for
+// output where emoji are unwanted.
+func StatusText(status DeliverableStatus) string {
+	switch status {
+	case DeliverableCompleted:
+		return "[done]"
+	case DeliverableInProgress:
+		return "[in progress]"
+	case DeliverableBlocked:
+		return "[blocked]"
+	case DeliverableNotStarted:
+		return "[not started]"
+	default:
+		return ""
+	}
+}
+
+// StatusMarker returns the status indicator of a deliverable in the icon
+// set of the options.
+func (opts TableOptions) StatusMarker(status DeliverableStatus) string {
+	if opts.TextStatus {
+		return StatusText(status)
+	}
+	return StatusIcon(status)
+}
+
+// PhaseTargetStatusMarker returns the status indicator of a phase target in
+// the icon set of the options.
+func (opts TableOptions) PhaseTargetStatusMarker(status string) string {
+	if opts.TextStatus {
+		switch status {
+		case "achieved":
+			return "[achieved]"
+		case "in_progress":
+			return "[in progress]"
+		case "missed":
+			return "[missed]"
+		case "not_started":
+			return "[not started]"
+		default:
+			return ""
+		}
+	}
+	return PhaseTargetStatusIcon(status)
+}
+
 // PhaseTargetStatusIcon returns an emoji/icon for the phase target status.
 func PhaseTargetStatusIcon(status string) string {
 	switch status {
@@ -0,0 +1,171 @@
+package roadmap
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScheduleIssue describes a scheduling conflict in a roadmap.
+type ScheduleIssue struct {
+	PhaseID string `json:"phaseId"`
+	Field   string `json:"field"` // e.g., "phases[1].startDate"
+	Message string `json:"message"`
+}
+
+// HasWindow reports whether the phase has both a start and an end date.
+func (p *Phase) HasWindow() bool {
+	return p.StartDate != nil && p.EndDate != nil
+}
+
+// Contains reports whether t falls within the phase's start and end dates,
+// inclusive. A missing bound is treated as open.
+func (p *Phase) Contains(t time.Time) bool {
+	if p.StartDate != nil && t.Before(*p.StartDate) {
+		return false
+	}
+	if p.EndDate != nil && t.After(*p.EndDate) {
+		return false
+	}
+	return true
+}
+
+// ValidateSchedule checks phase dates for consistency:
+//
+//   - a phase does not end before it starts;
+//   - a phase does not start before any phase it depends on ends;
+//   - phases with deliverables in the same swimlane (deliverable type) do not
+//     overlap, unless either phase is marked Parallel or one depends on the
+//     other;
+//   - deliverable due dates are YYYY-MM-DD dates within their phase.
+//
+// Phases without dates are skipped. Unknown dependency IDs are not reported
+// here; they are a traceability concern of the containing document.
+func (r *Roadmap) ValidateSchedule() []ScheduleIssue {
+	var issues []ScheduleIssue
+	index := make(map[string]int, len(r.Phases))
+	for i, p := range r.Phases {
+		if p.ID != "" {
+			index[p.ID] = i
+		}
+	}
+
+	for i := range r.Phases {
+		p := &r.Phases[i]
+		if p.HasWindow() && p.EndDate.Before(*p.StartDate) {
+			issues = append(issues, ScheduleIssue{
+				PhaseID: p.ID,
+				Field:   fmt.Sprintf("phases[%d].endDate", i),
+				Message: fmt.Sprintf("Phase %s ends (%s) before it starts (%s)", p.ID, formatDate(*p.EndDate), formatDate(*p.StartDate)),
+			})
+		}
+
+		for k, del := range p.Deliverables {
+			if del.DueDate == "" {
+				continue
+			}
+			field := fmt.Sprintf("phases[%d].deliverables[%d].dueDate", i, k)
+			due, err := time.Parse("2006-01-02", del.DueDate)
+			if err != nil {
+				issues = append(issues, ScheduleIssue{
+					PhaseID: p.ID,
+					Field:   field,
+					Message: fmt.Sprintf("Deliverable %s has invalid due date %q: expected YYYY-MM-DD", del.ID, del.DueDate),
+				})
+			} else if !p.Contains(due) {
+				issues = append(issues, ScheduleIssue{
+					PhaseID: p.ID,
+					Field:   field,
+					Message: fmt.Sprintf("Deliverable %s is due %s, outside phase %s (%s)", del.ID, del.DueDate, p.ID, phaseDates(p)),
+				})
+			}
+		}
+
+		if p.StartDate == nil {
+			continue
+		}
+		for _, depID := range p.Dependencies {
+			j, ok := index[depID]
+			if !ok {
+				continue
+			}
+			dep := &r.Phases[j]
+			if dep.EndDate != nil && p.StartDate.Before(*dep.EndDate) {
+				issues = append(issues, ScheduleIssue{
+					PhaseID: p.ID,
+					Field:   fmt.Sprintf("phases[%d].startDate", i),
+					Message: fmt.Sprintf("Phase %s starts %s, before dependency %s ends %s",
+						p.ID, formatDate(*p.StartDate), dep.ID, formatDate(*dep.EndDate)),
+				})
+			}
+		}
+	}
+
+	for i := range r.Phases {
+		a := &r.Phases[i]
+		for j := i + 1; j < len(r.Phases); j++ {
+			b := &r.Phases[j]
+			if !a.HasWindow() || !b.HasWindow() || a.Parallel || b.Parallel || dependsOn(a, b) || dependsOn(b, a) {
+				continue
+			}
+			if !a.StartDate.Before(*b.EndDate) || !b.StartDate.Before(*a.EndDate) {
+				continue
+			}
+			lane, ok := sharedSwimlane(a, b)
+			if !ok {
+				continue
+			}
+			issues = append(issues, ScheduleIssue{
+				PhaseID: b.ID,
+				Field:   fmt.Sprintf("phases[%d]", j),
+				Message: fmt.Sprintf("Phases %s and %s overlap (%s to %s) with %s deliverables in both; mark one parallel if intended",
+					a.ID, b.ID, formatDate(laterOf(*a.StartDate, *b.StartDate)), formatDate(earlierOf(*a.EndDate, *b.EndDate)), SwimlaneLabel(lane)),
+			})
+		}
+	}
+
+	return issues
+}
+
+func dependsOn(p, other *Phase) bool {
+	for _, id := range p.Dependencies {
+		if id != "" && id == other.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedSwimlane returns the first deliverable type, in phase a's order,
+// that both phases have deliverables for.
+func sharedSwimlane(a, b *Phase) (DeliverableType, bool) {
+	types := make(map[DeliverableType]bool, len(b.Deliverables))
+	for _, d := range b.Deliverables {
+		if d.Type != "" {
+			types[d.Type] = true
+		}
+	}
+	for _, d := range a.Deliverables {
+		if types[d.Type] {
+			return d.Type, true
+		}
+	}
+	return "", false
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
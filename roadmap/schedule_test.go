@@ -0,0 +1,94 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) *time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestValidateSchedule(t *testing.T) {
+	features := []Deliverable{{ID: "d", Title: "Feature", Type: DeliverableFeature}}
+	docs := []Deliverable{{ID: "d", Title: "Docs", Type: DeliverableDocumentation}}
+
+	r := &Roadmap{Phases: []Phase{
+		{ID: "p1", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"), Deliverables: features},
+		// Starts before its dependency ends.
+		{ID: "p2", StartDate: date("2026-03-15"), EndDate: date("2026-06-30"), Dependencies: []string{"p1"}, Deliverables: features},
+		// Overlaps p2 in the Features swimlane without a dependency.
+		{ID: "p3", StartDate: date("2026-06-01"), EndDate: date("2026-08-31"), Deliverables: features},
+		// Overlaps p3 but only in a different swimlane.
+		{ID: "p4", StartDate: date("2026-07-01"), EndDate: date("2026-09-30"), Deliverables: docs},
+		// Intentional overlap.
+		{ID: "p5", StartDate: date("2026-07-01"), EndDate: date("2026-09-30"), Deliverables: features, Parallel: true},
+		// Ends before it starts.
+		{ID: "p6", StartDate: date("2026-12-31"), EndDate: date("2026-10-01")},
+		// No dates.
+		{ID: "p7", Dependencies: []string{"p6"}, Deliverables: features},
+	}}
+
+	issues := r.ValidateSchedule()
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Field+": "+issue.Message)
+	}
+	want := []string{
+		"phases[1].startDate: Phase p2 starts 2026-03-15, before dependency p1 ends 2026-03-31",
+		"phases[5].endDate: Phase p6 ends (2026-10-01) before it starts (2026-12-31)",
+		"phases[2]: Phases p2 and p3 overlap (2026-06-01 to 2026-06-30) with Features deliverables in both; mark one parallel if intended",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateSchedule() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateScheduleSequential(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{
+		{ID: "p1", StartDate: date("2026-01-01"), EndDate: date("2026-03-31")},
+		{ID: "p2", StartDate: date("2026-03-31"), EndDate: date("2026-06-30"), Dependencies: []string{"p1", "unknown"}},
+	}}
+	if issues := r.ValidateSchedule(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestValidateScheduleDueDates(t *testing.T) {
+	r := &Roadmap{Phases: []Phase{
+		{ID: "p1", StartDate: date("2026-01-01"), EndDate: date("2026-03-31"), Deliverables: []Deliverable{
+			{ID: "d1", DueDate: "2026-02-15"},
+			{ID: "d2", DueDate: "2026-04-15"},
+			{ID: "d3", DueDate: "Feb 15"},
+		}},
+	}}
+	var got []string
+	for _, issue := range r.ValidateSchedule() {
+		got = append(got, issue.Field+": "+issue.Message)
+	}
+	want := []string{
+		"phases[0].deliverables[1].dueDate: Deliverable d2 is due 2026-04-15, outside phase p1 (2026-01-01 to 2026-03-31)",
+		`phases[0].deliverables[2].dueDate: Deliverable d3 has invalid due date "Feb 15": expected YYYY-MM-DD`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateSchedule() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPhaseContains(t *testing.T) {
+	p := Phase{StartDate: date("2026-01-01"), EndDate: date("2026-03-31")}
+	for s, want := range map[string]bool{"2025-12-31": false, "2026-01-01": true, "2026-03-31": true, "2026-04-01": false} {
+		if got := p.Contains(*date(s)); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", s, got, want)
+		}
+	}
+	open := Phase{StartDate: date("2026-01-01")}
+	if !open.Contains(*date("2030-01-01")) {
+		t.Error("phase without end date should be open-ended")
+	}
+}
@@ -1,22 +1,42 @@
 package schema
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"sync"
 
 	"github.com/invopop/jsonschema"
 
 	"github.com/grokify/structured-plan/goals/okr"
 	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/requirements/mrd"
 	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/roadmap"
 )
 
 // Generator creates JSON Schema files from Go types.
+//
+// Reflected schemas are cached per Go type, so repeated generation with the
+// same Generator only pays the reflection cost once. A Generator is safe for
+// concurrent use as long as Reflector is not modified after first use.
 type Generator struct {
 	// Reflector is the jsonschema reflector used for generation.
 	Reflector *jsonschema.Reflector
+
+	// Incremental skips writing schema files whose content is unchanged,
+	// comparing SHA-256 hashes of the existing and generated bytes.
+	Incremental bool
+
+	mu    sync.Mutex
+	cache map[reflect.Type]*jsonschema.Schema
 }
 
 // NewGenerator creates a new schema generator with default settings.
@@ -25,13 +45,51 @@ func NewGenerator() *Generator {
 		DoNotReference:             false,
 		ExpandedStruct:             false,
 		RequiredFromJSONSchemaTags: true,
+		Namer:                      qualifiedName,
 	}
 	return &Generator{Reflector: r}
 }
 
+// qualifiedName names the definition of a type by its package and name,
+// such as "common.Risk", so that types of the same name in different
+// packages, such as okr.Risk, each get a definition of their own.
+func qualifiedName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// reflect returns a copy of the cached schema for v's type, reflecting it on
+// first use. The copy is shallow; callers may set top-level metadata such as
+// ID and Title but must not modify nested definitions.
+func (g *Generator) reflect(v any) *jsonschema.Schema {
+	t := reflect.TypeOf(v)
+
+	g.mu.Lock()
+	cached, ok := g.cache[t]
+	g.mu.Unlock()
+
+	if !ok {
+		cached = g.Reflector.Reflect(v)
+		if cached == nil {
+			return nil
+		}
+		g.mu.Lock()
+		if g.cache == nil {
+			g.cache = make(map[reflect.Type]*jsonschema.Schema)
+		}
+		g.cache[t] = cached
+		g.mu.Unlock()
+	}
+
+	schema := *cached
+	return &schema
+}
+
 // GeneratePRDSchema generates JSON Schema for the PRD Document type.
 func (g *Generator) GeneratePRDSchema() (*jsonschema.Schema, error) {
-	schema := g.Reflector.Reflect(&prd.Document{})
+	schema := g.reflect(&prd.Document{})
 	if schema == nil {
 		return nil, fmt.Errorf("failed to generate schema for prd.Document")
 	}
@@ -56,53 +114,132 @@ func (g *Generator) GeneratePRDSchemaJSON() ([]byte, error) {
 
 // WritePRDSchema generates and writes the PRD schema to a file.
 func (g *Generator) WritePRDSchema(path string) error {
+	_, err := g.writePRDSchema(path)
+	return err
+}
+
+func (g *Generator) writePRDSchema(path string) (bool, error) {
 	data, err := g.GeneratePRDSchemaJSON()
 	if err != nil {
-		return fmt.Errorf("generating schema: %w", err)
+		return false, fmt.Errorf("generating schema: %w", err)
 	}
+	return g.writeFile(path, data)
+}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
+// FileResult describes the outcome of writing a single schema file.
+type FileResult struct {
+	// Path is the schema file path.
+	Path string
+	// Written is false when the file was skipped because it was unchanged.
+	Written bool
+}
+
+// GenerateAll generates all schema files to the specified directory.
+func (g *Generator) GenerateAll(dir string) error {
+	_, err := g.GenerateAllFiles(dir)
+	return err
+}
+
+// GenerateAllFiles generates all schema files to the specified directory
+// concurrently and reports which files were written. Results are returned in
+// a fixed order (PRD, MRD, TRD, OKR, V2MOM, Roadmap) regardless of
+// completion order.
+func (g *Generator) GenerateAllFiles(dir string) ([]FileResult, error) {
+	jobs := []struct {
+		name  string
+		file  string
+		write func(string) (bool, error)
+	}{
+		{"PRD", "prd.schema.json", g.writePRDSchema},
+		{"MRD", "mrd.schema.json", g.writeMRDSchema},
+		{"TRD", "trd.schema.json", g.writeTRDSchema},
+		{"OKR", "okr.schema.json", g.writeOKRSchema},
+		{"V2MOM", "v2mom.schema.json", g.writeV2MOMSchema},
+		{"Roadmap", "roadmap.schema.json", g.writeRoadmapSchema},
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	results := make([]FileResult, len(jobs))
+	errs := make([]error, len(jobs))
+
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path := filepath.Join(dir, job.file)
+			written, err := job.write(path)
+			if err != nil {
+				errs[i] = fmt.Errorf("generating %s schema: %w", job.name, err)
+				return
+			}
+			results[i] = FileResult{Path: path, Written: written}
+		}()
 	}
+	wg.Wait()
 
-	return nil
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
-// GenerateAll generates all schema files to the specified directory.
-func (g *Generator) GenerateAll(dir string) error {
-	// Generate PRD schema
-	prdPath := filepath.Join(dir, "prd.schema.json")
-	if err := g.WritePRDSchema(prdPath); err != nil {
-		return fmt.Errorf("generating PRD schema: %w", err)
+// WriteSchema generates the schema for docType ("prd", "mrd", "trd", "okr",
+// "v2mom", or "roadmap") and writes it to path, reporting whether the file
+// was written.
+func (g *Generator) WriteSchema(docType, path string) (FileResult, error) {
+	var write func(string) (bool, error)
+	switch docType {
+	case "prd":
+		write = g.writePRDSchema
+	case "mrd":
+		write = g.writeMRDSchema
+	case "trd":
+		write = g.writeTRDSchema
+	case "okr":
+		write = g.writeOKRSchema
+	case "v2mom":
+		write = g.writeV2MOMSchema
+	case "roadmap":
+		write = g.writeRoadmapSchema
+	default:
+		return FileResult{}, fmt.Errorf("unsupported document type: %s", docType)
+	}
+	written, err := write(path)
+	if err != nil {
+		return FileResult{}, err
 	}
+	return FileResult{Path: path, Written: written}, nil
+}
 
-	// Generate OKR schema
-	okrPath := filepath.Join(dir, "okr.schema.json")
-	if err := g.WriteOKRSchema(okrPath); err != nil {
-		return fmt.Errorf("generating OKR schema: %w", err)
+// writeFile writes data to path, creating parent directories as needed.
+// In incremental mode, the write is skipped if the existing file has the
+// same SHA-256 hash. It reports whether the file was written.
+func (g *Generator) writeFile(path string, data []byte) (bool, error) {
+	if g.Incremental {
+		if existing, err := os.ReadFile(path); err == nil {
+			oldSum := sha256.Sum256(existing)
+			newSum := sha256.Sum256(data)
+			if bytes.Equal(oldSum[:], newSum[:]) {
+				return false, nil
+			}
+		}
 	}
 
-	// Generate V2MOM schema
-	v2momPath := filepath.Join(dir, "v2mom.schema.json")
-	if err := g.WriteV2MOMSchema(v2momPath); err != nil {
-		return fmt.Errorf("generating V2MOM schema: %w", err)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, fmt.Errorf("creating directory: %w", err)
 	}
 
-	// TODO: Add MRD and TRD schema generation when types are ready
-	// mrdPath := filepath.Join(dir, "mrd.schema.json")
-	// trdPath := filepath.Join(dir, "trd.schema.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return false, fmt.Errorf("writing file: %w", err)
+	}
 
-	return nil
+	return true, nil
 }
 
 // GenerateOKRSchema generates JSON Schema for the OKR Document type.
 func (g *Generator) GenerateOKRSchema() (*jsonschema.Schema, error) {
-	schema := g.Reflector.Reflect(&okr.OKRDocument{})
+	schema := g.reflect(&okr.OKRDocument{})
 	if schema == nil {
 		return nil, fmt.Errorf("failed to generate schema for okr.OKRDocument")
 	}
@@ -127,26 +264,21 @@ func (g *Generator) GenerateOKRSchemaJSON() ([]byte, error) {
 
 // WriteOKRSchema generates and writes the OKR schema to a file.
 func (g *Generator) WriteOKRSchema(path string) error {
+	_, err := g.writeOKRSchema(path)
+	return err
+}
+
+func (g *Generator) writeOKRSchema(path string) (bool, error) {
 	data, err := g.GenerateOKRSchemaJSON()
 	if err != nil {
-		return fmt.Errorf("generating schema: %w", err)
-	}
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+		return false, fmt.Errorf("generating schema: %w", err)
 	}
-
-	return nil
+	return g.writeFile(path, data)
 }
 
 // GenerateV2MOMSchema generates JSON Schema for the V2MOM Document type.
 func (g *Generator) GenerateV2MOMSchema() (*jsonschema.Schema, error) {
-	schema := g.Reflector.Reflect(&v2mom.V2MOM{})
+	schema := g.reflect(&v2mom.V2MOM{})
 	if schema == nil {
 		return nil, fmt.Errorf("failed to generate schema for v2mom.V2MOM")
 	}
@@ -171,19 +303,135 @@ func (g *Generator) GenerateV2MOMSchemaJSON() ([]byte, error) {
 
 // WriteV2MOMSchema generates and writes the V2MOM schema to a file.
 func (g *Generator) WriteV2MOMSchema(path string) error {
+	_, err := g.writeV2MOMSchema(path)
+	return err
+}
+
+func (g *Generator) writeV2MOMSchema(path string) (bool, error) {
 	data, err := g.GenerateV2MOMSchemaJSON()
 	if err != nil {
-		return fmt.Errorf("generating schema: %w", err)
+		return false, fmt.Errorf("generating schema: %w", err)
 	}
+	return g.writeFile(path, data)
+}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
+// GenerateRoadmapSchema generates JSON Schema for the standalone roadmap
+// Document type.
+func (g *Generator) GenerateRoadmapSchema() (*jsonschema.Schema, error) {
+	schema := g.reflect(&roadmap.Document{})
+	if schema == nil {
+		return nil, fmt.Errorf("failed to generate schema for roadmap.Document")
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	// Set schema metadata
+	schema.ID = jsonschema.ID(RoadmapSchemaID)
+	schema.Title = "Roadmap Document"
+	schema.Description = "Schema for standalone portfolio and product roadmaps"
+
+	return schema, nil
+}
+
+// GenerateRoadmapSchemaJSON generates JSON Schema for roadmap documents and
+// returns it as JSON bytes.
+func (g *Generator) GenerateRoadmapSchemaJSON() ([]byte, error) {
+	schema, err := g.GenerateRoadmapSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// WriteRoadmapSchema generates and writes the roadmap schema to a file.
+func (g *Generator) WriteRoadmapSchema(path string) error {
+	_, err := g.writeRoadmapSchema(path)
+	return err
+}
+
+func (g *Generator) writeRoadmapSchema(path string) (bool, error) {
+	data, err := g.GenerateRoadmapSchemaJSON()
+	if err != nil {
+		return false, fmt.Errorf("generating schema: %w", err)
+	}
+	return g.writeFile(path, data)
+}
+
+// GenerateMRDSchema generates JSON Schema for the MRD Document type.
+func (g *Generator) GenerateMRDSchema() (*jsonschema.Schema, error) {
+	schema := g.reflect(&mrd.Document{})
+	if schema == nil {
+		return nil, fmt.Errorf("failed to generate schema for mrd.Document")
+	}
+
+	// Set schema metadata
+	schema.ID = jsonschema.ID(MRDSchemaID)
+	schema.Title = "Market Requirements Document"
+	schema.Description = "Schema for Market Requirements Documents (MRDs)"
+
+	return schema, nil
+}
+
+// GenerateMRDSchemaJSON generates JSON Schema for MRD documents and returns
+// it as JSON bytes.
+func (g *Generator) GenerateMRDSchemaJSON() ([]byte, error) {
+	schema, err := g.GenerateMRDSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// WriteMRDSchema generates and writes the MRD schema to a file.
+func (g *Generator) WriteMRDSchema(path string) error {
+	_, err := g.writeMRDSchema(path)
+	return err
+}
+
+func (g *Generator) writeMRDSchema(path string) (bool, error) {
+	data, err := g.GenerateMRDSchemaJSON()
+	if err != nil {
+		return false, fmt.Errorf("generating schema: %w", err)
+	}
+	return g.writeFile(path, data)
+}
+
+// GenerateTRDSchema generates JSON Schema for the TRD Document type.
+func (g *Generator) GenerateTRDSchema() (*jsonschema.Schema, error) {
+	schema := g.reflect(&trd.Document{})
+	if schema == nil {
+		return nil, fmt.Errorf("failed to generate schema for trd.Document")
+	}
+
+	// Set schema metadata
+	schema.ID = jsonschema.ID(TRDSchemaID)
+	schema.Title = "Technical Requirements Document"
+	schema.Description = "Schema for Technical Requirements Documents (TRDs)"
+
+	return schema, nil
+}
+
+// GenerateTRDSchemaJSON generates JSON Schema for TRD documents and returns
+// it as JSON bytes.
+func (g *Generator) GenerateTRDSchemaJSON() ([]byte, error) {
+	schema, err := g.GenerateTRDSchema()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// WriteTRDSchema generates and writes the TRD schema to a file.
+func (g *Generator) WriteTRDSchema(path string) error {
+	_, err := g.writeTRDSchema(path)
+	return err
+}
+
+func (g *Generator) writeTRDSchema(path string) (bool, error) {
+	data, err := g.GenerateTRDSchemaJSON()
+	if err != nil {
+		return false, fmt.Errorf("generating schema: %w", err)
+	}
+	return g.writeFile(path, data)
 }
@@ -1,7 +1,10 @@
 package schema
 
 import (
+	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -54,8 +57,8 @@ func TestGeneratePRDSchemaJSON(t *testing.T) {
 
 	// Verify $ref points to Document definition
 	ref, ok := schema["$ref"].(string)
-	if !ok || ref != "#/$defs/Document" {
-		t.Errorf("expected $ref to be '#/$defs/Document', got %v", schema["$ref"])
+	if !ok || ref != "#/$defs/prd.Document" {
+		t.Errorf("expected $ref to be '#/$defs/prd.Document', got %v", schema["$ref"])
 	}
 }
 
@@ -78,7 +81,7 @@ func TestGeneratorReflectsExtendedSections(t *testing.T) {
 		t.Fatal("$defs is not an object")
 	}
 
-	doc, ok := defs["Document"].(map[string]any)
+	doc, ok := defs["prd.Document"].(map[string]any)
 	if !ok {
 		t.Fatal("$defs/Document is not an object")
 	}
@@ -96,3 +99,135 @@ func TestGeneratorReflectsExtendedSections(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateRequirementsSchemas(t *testing.T) {
+	gen := NewGenerator()
+
+	mrdSchema, err := gen.GenerateMRDSchema()
+	if err != nil {
+		t.Fatalf("GenerateMRDSchema failed: %v", err)
+	}
+	if string(mrdSchema.ID) != MRDSchemaID {
+		t.Errorf("expected ID %q, got %q", MRDSchemaID, mrdSchema.ID)
+	}
+
+	trdSchema, err := gen.GenerateTRDSchema()
+	if err != nil {
+		t.Fatalf("GenerateTRDSchema failed: %v", err)
+	}
+	if string(trdSchema.ID) != TRDSchemaID {
+		t.Errorf("expected ID %q, got %q", TRDSchemaID, trdSchema.ID)
+	}
+
+	// The embedded schemas are regenerated with the types.
+	for _, tt := range []struct {
+		name     string
+		generate func() ([]byte, error)
+		embedded []byte
+	}{
+		{"MRD", gen.GenerateMRDSchemaJSON, MRDSchemaJSON},
+		{"TRD", gen.GenerateTRDSchemaJSON, TRDSchemaJSON},
+	} {
+		data, err := tt.generate()
+		if err != nil {
+			t.Fatalf("generating %s schema: %v", tt.name, err)
+		}
+		if !bytes.Equal(data, tt.embedded) {
+			t.Errorf("embedded %s schema is stale; run splan schema generate -o schema/", tt.name)
+		}
+	}
+}
+
+func TestGeneratorCachesReflection(t *testing.T) {
+	gen := NewGenerator()
+
+	first, err := gen.GeneratePRDSchema()
+	if err != nil {
+		t.Fatalf("GeneratePRDSchema failed: %v", err)
+	}
+	second, err := gen.GeneratePRDSchema()
+	if err != nil {
+		t.Fatalf("GeneratePRDSchema failed: %v", err)
+	}
+
+	if first == second {
+		t.Error("expected distinct top-level schema copies")
+	}
+	if first.Definitions["Document"] != second.Definitions["Document"] {
+		t.Error("expected cached definitions to be shared between calls")
+	}
+
+	// Metadata set on one copy must not leak into the cache.
+	first.Title = "changed"
+	third, _ := gen.GeneratePRDSchema()
+	if third.Title != "Structured PRD" {
+		t.Errorf("expected title 'Structured PRD', got %q", third.Title)
+	}
+}
+
+func TestGenerateAllFilesIncremental(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator()
+	gen.Incremental = true
+
+	results, err := gen.GenerateAllFiles(dir)
+	if err != nil {
+		t.Fatalf("GenerateAllFiles failed: %v", err)
+	}
+	wantFiles := []string{"prd.schema.json", "mrd.schema.json", "trd.schema.json", "okr.schema.json", "v2mom.schema.json", "roadmap.schema.json"}
+	if len(results) != len(wantFiles) {
+		t.Fatalf("expected %d results, got %d", len(wantFiles), len(results))
+	}
+	for i, r := range results {
+		if filepath.Base(r.Path) != wantFiles[i] {
+			t.Errorf("result %d: expected %s, got %s", i, wantFiles[i], r.Path)
+		}
+		if !r.Written {
+			t.Errorf("expected %s to be written on first run", r.Path)
+		}
+	}
+
+	// Second run: nothing changed.
+	results, err = gen.GenerateAllFiles(dir)
+	if err != nil {
+		t.Fatalf("GenerateAllFiles failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Written {
+			t.Errorf("expected %s to be skipped when unchanged", r.Path)
+		}
+	}
+
+	// Modify one file; only that file is rewritten.
+	prdPath := filepath.Join(dir, "prd.schema.json")
+	if err := os.WriteFile(prdPath, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	results, err = gen.GenerateAllFiles(dir)
+	if err != nil {
+		t.Fatalf("GenerateAllFiles failed: %v", err)
+	}
+	if !results[0].Written || results[1].Written || results[2].Written || results[3].Written {
+		t.Errorf("expected only PRD schema to be rewritten, got %+v", results)
+	}
+}
+
+func TestGenerateAllMatchesSingleWrites(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator()
+	if err := gen.GenerateAll(dir); err != nil {
+		t.Fatalf("GenerateAll failed: %v", err)
+	}
+
+	want, err := gen.GeneratePRDSchemaJSON()
+	if err != nil {
+		t.Fatalf("GeneratePRDSchemaJSON failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "prd.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("GenerateAll output differs from GeneratePRDSchemaJSON")
+	}
+}
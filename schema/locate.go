@@ -0,0 +1,161 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// Locate returns the 1-based line and column of the value at the JSON
+// pointer ptr in a JSON or YAML document. A value that is missing, such as
+// a required field, is located at its nearest ancestor that is present. It
+// returns zeros if the document is malformed.
+func Locate(data []byte, format yamlconv.Format, ptr string) (line, column int) {
+	locate := locateJSON
+	if format == yamlconv.FormatYAML {
+		locate = locateYAML
+	}
+	for path := pointerTokens(ptr); len(path) > 0; path = path[:len(path)-1] {
+		if line, column = locate(data, path); line > 0 {
+			return line, column
+		}
+	}
+	return locate(data, nil)
+}
+
+// locateJSON returns the 1-based line and column of the value at path in a
+// JSON document. Object members are located at their property name. It
+// returns zeros if the path is not found or the document is malformed.
+func locateJSON(data []byte, path []string) (line, column int) {
+	type frame struct {
+		array   bool
+		index   int
+		key     string
+		keyOff  int64
+		wantKey bool
+	}
+	var stack []*frame
+
+	matches := func() bool {
+		if len(stack) != len(path) {
+			return false
+		}
+		for i, f := range stack {
+			tok := f.key
+			if f.array {
+				tok = strconv.Itoa(f.index)
+			}
+			if tok != path[i] {
+				return false
+			}
+		}
+		return true
+	}
+	// valueDone advances the parent container past a completed value.
+	valueDone := func() {
+		if len(stack) == 0 {
+			return
+		}
+		if top := stack[len(stack)-1]; top.array {
+			top.index++
+		} else {
+			top.wantKey = true
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		off := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0
+		}
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if top != nil && !top.array && top.wantKey {
+			if key, ok := tok.(string); ok {
+				top.key, top.keyOff, top.wantKey = key, off, false
+				continue
+			}
+		}
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:len(stack)-1]
+			valueDone()
+			continue
+		}
+
+		if matches() {
+			if top != nil && !top.array {
+				off = top.keyOff
+			}
+			return position(data, off)
+		}
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &frame{wantKey: true})
+		case json.Delim('['):
+			stack = append(stack, &frame{array: true})
+		default:
+			valueDone()
+		}
+	}
+}
+
+// position converts the offset of the whitespace or separator preceding a
+// token into the 1-based line and column of the token itself.
+func position(data []byte, off int64) (line, column int) {
+	i := int(off)
+	for i < len(data) && bytes.IndexByte([]byte(" \t\r\n,:"), data[i]) >= 0 {
+		i++
+	}
+	line = 1 + bytes.Count(data[:i], []byte("\n"))
+	lineStart := bytes.LastIndexByte(data[:i], '\n') + 1
+	return line, 1 + utf8.RuneCount(data[lineStart:i])
+}
+
+// locateYAML returns the 1-based line and column of the node at path in a
+// YAML document. Mapping values are located at their key.
+func locateYAML(data []byte, path []string) (line, column int) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0, 0
+	}
+	n := doc.Content[0]
+	at := n
+	for _, tok := range path {
+		for n.Kind == yaml.AliasNode && n.Alias != nil {
+			n = n.Alias
+		}
+		switch n.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == tok {
+					at, next = n.Content[i], n.Content[i+1]
+				}
+			}
+			if next == nil {
+				return 0, 0
+			}
+			n = next
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(n.Content) {
+				return 0, 0
+			}
+			n = n.Content[i]
+			at = n
+		default:
+			return 0, 0
+		}
+	}
+	return at.Line, at.Column
+}
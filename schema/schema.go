@@ -26,10 +26,10 @@ const (
 	// PRDSchemaID is the canonical ID for the PRD schema.
 	PRDSchemaID = "https://github.com/grokify/structured-plan/schema/prd.schema.json"
 
-	// MRDSchemaID is the canonical ID for the MRD schema (placeholder).
+	// MRDSchemaID is the canonical ID for the MRD schema.
 	MRDSchemaID = "https://github.com/grokify/structured-plan/schema/mrd.schema.json"
 
-	// TRDSchemaID is the canonical ID for the TRD schema (placeholder).
+	// TRDSchemaID is the canonical ID for the TRD schema.
 	TRDSchemaID = "https://github.com/grokify/structured-plan/schema/trd.schema.json"
 
 	// OKRSchemaID is the canonical ID for the OKR schema.
@@ -37,6 +37,9 @@ const (
 
 	// V2MOMSchemaID is the canonical ID for the V2MOM schema.
 	V2MOMSchemaID = "https://github.com/grokify/structured-plan/schema/v2mom.schema.json"
+
+	// RoadmapSchemaID is the canonical ID for the standalone roadmap schema.
+	RoadmapSchemaID = "https://github.com/grokify/structured-plan/schema/roadmap.schema.json"
 )
 
 // OKR Schema
@@ -69,17 +72,47 @@ func V2MOMSchemaBytes() []byte {
 	return V2MOMSchemaJSON
 }
 
-// TODO: Add MRD and TRD schemas when created.
-// When mrd.schema.json is added:
-//
-//	//go:embed mrd.schema.json
-//	var MRDSchemaJSON []byte
-//
-//	func MRDSchema() string { return string(MRDSchemaJSON) }
-//
-// When trd.schema.json is added:
-//
-//	//go:embed trd.schema.json
-//	var TRDSchemaJSON []byte
-//
-//	func TRDSchema() string { return string(TRDSchemaJSON) }
+// Roadmap Schema
+
+//go:embed roadmap.schema.json
+var RoadmapSchemaJSON []byte
+
+// RoadmapSchema returns the roadmap JSON Schema as a string.
+func RoadmapSchema() string {
+	return string(RoadmapSchemaJSON)
+}
+
+// RoadmapSchemaBytes returns the roadmap JSON Schema as a byte slice.
+func RoadmapSchemaBytes() []byte {
+	return RoadmapSchemaJSON
+}
+
+// MRD Schema
+
+//go:embed mrd.schema.json
+var MRDSchemaJSON []byte
+
+// MRDSchema returns the MRD JSON Schema as a string.
+func MRDSchema() string {
+	return string(MRDSchemaJSON)
+}
+
+// MRDSchemaBytes returns the MRD JSON Schema as a byte slice.
+func MRDSchemaBytes() []byte {
+	return MRDSchemaJSON
+}
+
+// TRD Schema
+
+//go:embed trd.schema.json
+var TRDSchemaJSON []byte
+
+// TRDSchema returns the TRD JSON Schema as a string.
+func TRDSchema() string {
+	return string(TRDSchemaJSON)
+}
+
+// TRDSchemaBytes returns the TRD JSON Schema as a byte slice.
+func TRDSchemaBytes() []byte {
+	return TRDSchemaJSON
+}
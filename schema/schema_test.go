@@ -36,7 +36,7 @@ func TestPRDSchemaExtendedSections(t *testing.T) {
 		t.Fatal("$defs is not an object")
 	}
 
-	document, ok := defs["Document"].(map[string]any)
+	document, ok := defs["prd.Document"].(map[string]any)
 	if !ok {
 		t.Fatal("$defs.Document is not an object")
 	}
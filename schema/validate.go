@@ -0,0 +1,197 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	jsv "github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// Violation is a JSON Schema validation failure at one location in a
+// document.
+type Violation struct {
+	// Pointer is the JSON pointer to the failing value, e.g.
+	// "/metadata/id". It is empty for the document root.
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+
+	// Line and Column locate the failing value, or its property name for
+	// object members, in the original file. Both are 1-based and zero when
+	// the location could not be determined.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// String formats the violation as "line:column: pointer: message".
+func (v Violation) String() string {
+	ptr := v.Pointer
+	if ptr == "" {
+		ptr = "/"
+	}
+	if v.Line == 0 {
+		return fmt.Sprintf("%s: %s", ptr, v.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", v.Line, v.Column, ptr, v.Message)
+}
+
+// Validator validates documents against a compiled JSON Schema.
+type Validator struct {
+	schema *jsv.Schema
+}
+
+var printer = message.NewPrinter(language.English)
+
+// NewValidator compiles a JSON Schema. Formats such as date-time are
+// asserted, so malformed dates are reported as violations. References to
+// other schema files are not resolved.
+func NewValidator(schemaJSON []byte) (*Validator, error) {
+	doc, err := jsv.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+	url := "schema.json"
+	if m, ok := doc.(map[string]any); ok {
+		if id, ok := m["$id"].(string); ok && id != "" {
+			url = id
+		}
+	}
+
+	c := jsv.NewCompiler()
+	c.AssertFormat()
+	if err := c.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("loading schema: %w", err)
+	}
+	sch, err := c.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("compiling schema: %w", err)
+	}
+	return &Validator{schema: sch}, nil
+}
+
+// ValidatorFor returns a validator for the embedded schema of docType
+// ("prd", "mrd", "trd", "okr", "v2mom", or "roadmap").
+func ValidatorFor(docType string) (*Validator, error) {
+	var data []byte
+	switch docType {
+	case "prd":
+		data = PRDSchemaJSON
+	case "mrd":
+		data = MRDSchemaJSON
+	case "trd":
+		data = TRDSchemaJSON
+	case "okr":
+		data = OKRSchemaJSON
+	case "v2mom":
+		data = V2MOMSchemaJSON
+	case "roadmap":
+		data = RoadmapSchemaJSON
+	default:
+		return nil, fmt.Errorf("no schema for document type: %s", docType)
+	}
+	return NewValidator(data)
+}
+
+// Validate validates a JSON document. It returns an error only if the
+// document is not valid JSON; schema failures are returned as violations,
+// sorted by position in the file.
+func (v *Validator) Validate(data []byte) ([]Violation, error) {
+	violations, err := v.validate(data)
+	if err != nil {
+		return nil, err
+	}
+	for i := range violations {
+		violations[i].Line, violations[i].Column = locateJSON(data, pointerTokens(violations[i].Pointer))
+	}
+	sortViolations(violations)
+	return violations, nil
+}
+
+// ValidateYAML validates a YAML document. Line and column numbers refer to
+// the YAML source.
+func (v *Validator) ValidateYAML(data []byte) ([]Violation, error) {
+	jsonData, err := yamlconv.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := v.validate(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	for i := range violations {
+		violations[i].Line, violations[i].Column = locateYAML(data, pointerTokens(violations[i].Pointer))
+	}
+	sortViolations(violations)
+	return violations, nil
+}
+
+func (v *Validator) validate(data []byte) ([]Violation, error) {
+	inst, err := jsv.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	err = v.schema.Validate(inst)
+	if err == nil {
+		return nil, nil
+	}
+	ve, ok := err.(*jsv.ValidationError)
+	if !ok {
+		return nil, err
+	}
+
+	var violations []Violation
+	seen := map[Violation]bool{}
+	var walk func(e *jsv.ValidationError)
+	walk = func(e *jsv.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, c := range e.Causes {
+				walk(c)
+			}
+			return
+		}
+		vi := Violation{Pointer: pointer(e.InstanceLocation), Message: e.ErrorKind.LocalizedString(printer)}
+		if !seen[vi] {
+			seen[vi] = true
+			violations = append(violations, vi)
+		}
+	}
+	walk(ve)
+	return violations, nil
+}
+
+func sortViolations(violations []Violation) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// pointer encodes instance location tokens as a JSON pointer.
+func pointer(tokens []string) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// pointerTokens decodes a JSON pointer into its reference tokens.
+func pointerTokens(ptr string) []string {
+	if ptr == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, tok := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
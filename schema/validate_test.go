@@ -0,0 +1,250 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+const testSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "id": {"type": "string"},
+    "updated": {"type": "string", "format": "date-time"},
+    "items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {"n": {"type": "integer"}},
+        "additionalProperties": false
+      }
+    }
+  },
+  "required": ["id"]
+}`
+
+func TestValidatorValidate(t *testing.T) {
+	v, err := NewValidator([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+
+	doc := `{
+  "updated": "2026-01-01",
+  "items": [
+    {"n": 1},
+    {"n": "two", "extra": true}
+  ]
+}`
+	violations, err := v.Validate([]byte(doc))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	want := []string{
+		"1:1: /: missing property 'id'",
+		"2:3: /updated: '2026-01-01' is not valid date-time",
+		"5:5: /items/1: additional properties 'extra' not allowed",
+		"5:6: /items/1/n: got string, want integer",
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("got %d violations, want %d: %v", len(violations), len(want), violations)
+	}
+	for i, w := range want {
+		if got := violations[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("violation %d = %q, want prefix %q", i, got, w)
+		}
+	}
+
+	if _, err := v.Validate([]byte(`{"id": `)); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	if violations, _ := v.Validate([]byte(`{"id": "a"}`)); len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+}
+
+func TestValidatorValidateYAML(t *testing.T) {
+	v, err := NewValidator([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := "id: a\nitems:\n  - n: 1\n  - n: x\n"
+	violations, err := v.ValidateYAML([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Pointer != "/items/1/n" ||
+		violations[0].Line != 4 || violations[0].Column != 5 {
+		t.Errorf("violations = %+v", violations)
+	}
+}
+
+func TestValidatorFor(t *testing.T) {
+	for _, docType := range []string{"prd", "mrd", "trd", "okr", "v2mom", "roadmap"} {
+		if _, err := ValidatorFor(docType); err != nil {
+			t.Errorf("ValidatorFor(%q) failed: %v", docType, err)
+		}
+	}
+	if _, err := ValidatorFor("brd"); err == nil {
+		t.Error("expected error for a type without a schema")
+	}
+
+	v, _ := ValidatorFor("okr")
+	violations, err := v.Validate([]byte(`{"objectives": [{"title": "O", "keyResults": [], "progress": "high"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Pointer != "/objectives/0/progress" || violations[0].Line != 1 {
+		t.Errorf("violations = %+v", violations)
+	}
+}
+
+func TestValidatorForPRDRisks(t *testing.T) {
+	v, err := ValidatorFor("prd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := prd.Document{Risks: []common.Risk{{ID: "R-1", Description: "Vendor lock-in",
+		Probability: common.RiskProbabilityLow, Impact: common.RiskImpactHigh, Mitigation: "Abstract the API"}}}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := v.Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vi := range violations {
+		if strings.HasPrefix(vi.Pointer, "/risks") {
+			t.Errorf("violation for a common.Risk: %s", vi)
+		}
+	}
+}
+
+func TestPointerTokens(t *testing.T) {
+	tokens := []string{"a/b", "c~d", "0"}
+	ptr := pointer(tokens)
+	if ptr != "/a~1b/c~0d/0" {
+		t.Errorf("pointer = %q", ptr)
+	}
+	if got := pointerTokens(ptr); strings.Join(got, "|") != strings.Join(tokens, "|") {
+		t.Errorf("pointerTokens = %q", got)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	doc := "{\n  \"metadata\": {\n    \"title\": \"x\"\n  },\n  \"items\": [1, 2]\n}\n"
+	tests := []struct {
+		ptr          string
+		line, column int
+	}{
+		{"/metadata/title", 3, 5},
+		{"/metadata/id", 2, 3},
+		{"/items/1", 5, 16},
+		{"", 1, 1},
+	}
+	for _, tt := range tests {
+		line, column := Locate([]byte(doc), yamlconv.FormatJSON, tt.ptr)
+		if line != tt.line || column != tt.column {
+			t.Errorf("Locate(%q) = %d:%d, want %d:%d", tt.ptr, line, column, tt.line, tt.column)
+		}
+	}
+
+	yamlDoc := "metadata:\n  title: x\n"
+	if line, column := Locate([]byte(yamlDoc), yamlconv.FormatYAML, "/metadata/id"); line != 1 || column != 1 {
+		t.Errorf("Locate YAML = %d:%d, want 1:1", line, column)
+	}
+}
+
+// nullViolations returns the violations reporting a null where the schema
+// expects another type, the signature of a nil slice marshaled without
+// omitempty.
+func nullViolations(t *testing.T, docType string, v any) []Violation {
+	t.Helper()
+	validator, err := ValidatorFor(docType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := validator.Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nulls []Violation
+	for _, vi := range violations {
+		if strings.Contains(vi.Message, "got null") {
+			nulls = append(nulls, vi)
+		}
+	}
+	return nulls
+}
+
+func TestImportMarkdownValidates(t *testing.T) {
+	src := `# Checkout Redesign
+
+## Personas
+
+| Name | Role | Description |
+|------|------|-------------|
+| Dana | Buyer | Buys things |
+
+### Sam (Primary)
+
+**Goals:**
+- Reorder quickly
+
+## User Stories
+
+- As a buyer, I want one-page checkout so that I finish faster.
+
+## Functional Requirements
+
+| ID | Title | Priority |
+|----|-------|----------|
+| FR-1 | One-page checkout | must |
+
+- Saved cards
+
+### Non-Functional Requirements
+
+- NFR-1: Pages load in under a second
+
+## Roadmap
+
+### phase-1: Launch
+
+**Goals:**
+- Ship checkout
+`
+	doc, _ := prd.ImportMarkdown([]byte(src))
+	for _, vi := range nullViolations(t, "prd", doc) {
+		t.Errorf("imported document: %s", vi)
+	}
+}
+
+func TestDeriveFromMRDValidates(t *testing.T) {
+	data, err := os.ReadFile("../examples/agent-platform.mrd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := &mrd.Document{}
+	if err := json.Unmarshal(data, full); err != nil {
+		t.Fatal(err)
+	}
+	for name, m := range map[string]*mrd.Document{"example": full, "minimal": {}} {
+		doc, _ := prd.DeriveFromMRD(m)
+		for _, vi := range nullViolations(t, "prd", doc) {
+			t.Errorf("PRD derived from %s MRD: %s", name, vi)
+		}
+	}
+}
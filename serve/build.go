@@ -0,0 +1,166 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/workspace"
+)
+
+// Search index files written next to the site's index.html.
+const (
+	SearchIndexJSON = "search-index.json"
+	SearchIndexJS   = "search-index.js"
+)
+
+// BuildResult summarizes a static build.
+type BuildResult struct {
+	Pages   int // HTML pages written, including index.html
+	Entries int // search index entries
+	Failed  int // documents that could not be rendered
+}
+
+// Build writes the pages the server shows as a static site under out:
+// index.html with the workspace dashboard, and a page for each document at
+// its path with .json replaced by .html. Links between pages are relative,
+// so the site can be published anywhere or opened from disk.
+//
+// Build also writes a full-text index of the documents, their sections,
+// requirements, and glossary terms, as SearchIndexJSON for lunr or
+// Meilisearch, and as SearchIndexJS for the search box in the sidebar,
+// which works offline, including from file:// URLs.
+func (s *Server) Build(out string) (*BuildResult, error) {
+	docs, err := Catalog(s.root)
+	if err != nil {
+		return nil, err
+	}
+	res := &BuildResult{}
+	entries := []Entry{}
+
+	for _, d := range docs {
+		url := sitePath(d.Path)
+		prefix := strings.Repeat("../", strings.Count(d.Path, "/"))
+		page, decoded, err := documentPage(filepath.Join(s.root, filepath.FromSlash(d.Path)), d.Kind, s.opts.Lenient)
+		if err != nil {
+			page = htmldoc.NewPage(string(d.Kind), d.Label())
+			page.Banner = fmt.Sprintf("%s cannot be rendered: %v", d.Path, err)
+			res.Failed++
+		} else {
+			entries = append(entries, indexDocument(d, page, decoded, url)...)
+		}
+		html, err := page.Render()
+		if err != nil {
+			return nil, err
+		}
+		if err := writeSitePage(out, url, html, docs, d, prefix); err != nil {
+			return nil, err
+		}
+		res.Pages++
+	}
+
+	ws, err := workspace.Scan(s.root, workspace.Options{Lenient: s.opts.Lenient})
+	if err != nil {
+		return nil, err
+	}
+	var dashboard bytes.Buffer
+	if err := ws.WriteHTML(&dashboard); err != nil {
+		return nil, err
+	}
+	if err := writeSitePage(out, "index.html", dashboard.Bytes(), docs, nil, ""); err != nil {
+		return nil, err
+	}
+	res.Pages++
+
+	index, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling search index: %w", err)
+	}
+	if err := writeSiteFile(out, SearchIndexJSON, append(index, '\n')); err != nil {
+		return nil, err
+	}
+	script := "window.splanSearchIndex = " + string(index) + ";\n"
+	if err := writeSiteFile(out, SearchIndexJS, []byte(script)); err != nil {
+		return nil, err
+	}
+	res.Entries = len(entries)
+	return res, nil
+}
+
+// sitePath returns the path of a document's page, e.g. "plans/a.prd.html"
+// for "plans/a.prd.json".
+func sitePath(docPath string) string {
+	return strings.TrimSuffix(docPath, path.Ext(docPath)) + ".html"
+}
+
+// writeSitePage writes a page with the sidebar and search script added.
+// prefix leads from the page's directory back to the site root.
+func writeSitePage(out, name string, page []byte, docs []*Document, current *Document, prefix string) error {
+	links := siteLinks{
+		home:   prefix + "index.html",
+		doc:    func(p string) string { return prefix + sitePath(p) },
+		search: prefix,
+	}
+	page, err := withNav(page, docs, current, links)
+	if err != nil {
+		return err
+	}
+	scripts := fmt.Sprintf("<script src=\"%s%s\"></script>\n<script>\n%ssplanSearch(%q);\n</script>\n", prefix, SearchIndexJS, searchScript, prefix)
+	return writeSiteFile(out, name, insertBefore(page, "</body>", scripts))
+}
+
+func writeSiteFile(out, name string, data []byte) error {
+	file := filepath.Join(out, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	return nil
+}
+
+// searchScript matches every query term against the titles and text of the
+// index entries, ranking title matches first.
+const searchScript = `function splanSearch(base) {
+  var input = document.getElementById("splan-search");
+  var list = document.getElementById("splan-search-results");
+  var index = window.splanSearchIndex;
+  if (!input || !list || !index) { return; }
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    list.textContent = "";
+    if (!terms.length) { return; }
+    var hits = [];
+    index.forEach(function (e) {
+      var title = e.title.toLowerCase(), text = (e.text || "").toLowerCase(), score = 0;
+      for (var i = 0; i < terms.length; i++) {
+        if (title.indexOf(terms[i]) >= 0) { score += 3; }
+        else if (text.indexOf(terms[i]) >= 0) { score += 1; }
+        else { return; }
+      }
+      hits.push({ entry: e, score: score });
+    });
+    hits.sort(function (a, b) { return b.score - a.score; });
+    hits.slice(0, 20).forEach(function (h) {
+      var li = document.createElement("li"), a = document.createElement("a"), small = document.createElement("small");
+      a.href = base + h.entry.url;
+      a.textContent = h.entry.title;
+      small.textContent = h.entry.document + " · " + h.entry.type;
+      li.appendChild(a);
+      li.appendChild(small);
+      list.appendChild(li);
+    });
+    if (!hits.length) {
+      var none = document.createElement("li");
+      none.textContent = "No results";
+      list.appendChild(none);
+    }
+  });
+}
+`
@@ -0,0 +1,141 @@
+package serve
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/structured-plan/workspace"
+)
+
+// Document is a planning document in the served directory, with the
+// documents it is linked to.
+type Document struct {
+	Path  string // Slash-separated, relative to the served directory
+	Kind  workspace.Kind
+	ID    string
+	Title string
+
+	// References and ReferencedBy are the paths of the documents this one
+	// mentions by ID or relative path, and of those that mention it.
+	References   []string
+	ReferencedBy []string
+
+	// Error is set when the file is not valid JSON or YAML.
+	Error string
+
+	values map[string]bool // every string value in the document
+}
+
+// Label returns the title, falling back to the file name.
+func (d *Document) Label() string {
+	if d.Title != "" {
+		return d.Title
+	}
+	return path.Base(d.Path)
+}
+
+// Catalog lists the planning documents under root and links the documents
+// that reference each other. A document references another when any of
+// its string values is the other's metadata ID, such as a supersedes entry
+// or a provenance source, or the other's path relative to either the
+// document or root.
+func Catalog(root string) ([]*Document, error) {
+	paths, err := workspace.Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]*Document, 0, len(paths))
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil, err
+		}
+		kind, _ := workspace.KindFromPath(p)
+		d := &Document{Path: filepath.ToSlash(rel), Kind: kind, values: make(map[string]bool)}
+		d.load(p)
+		docs = append(docs, d)
+	}
+
+	for _, d := range docs {
+		for _, other := range docs {
+			if other != d && d.mentions(other) {
+				d.References = append(d.References, other.Path)
+				other.ReferencedBy = append(other.ReferencedBy, d.Path)
+			}
+		}
+	}
+	return docs, nil
+}
+
+// Find returns the document with the given path, or nil.
+func Find(docs []*Document, p string) *Document {
+	for _, d := range docs {
+		if d.Path == p {
+			return d
+		}
+	}
+	return nil
+}
+
+func (d *Document) load(file string) {
+	data, err := workspace.ReadJSON(file)
+	if err != nil {
+		d.Error = err.Error()
+		return
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		d.Error = err.Error()
+		return
+	}
+	obj, _ := v.(map[string]any)
+	meta, _ := obj["metadata"].(map[string]any)
+	d.ID = firstString(meta["id"], obj["id"])
+	d.Title = firstString(meta["title"], meta["name"], obj["title"], obj["name"])
+	collectStrings(v, d.values)
+	delete(d.values, d.ID)
+}
+
+func (d *Document) mentions(other *Document) bool {
+	if other.ID != "" && d.values[other.ID] {
+		return true
+	}
+	if d.values[other.Path] {
+		return true
+	}
+	dir := path.Dir(d.Path)
+	for v := range d.values {
+		if strings.HasSuffix(v, ".json") && !strings.Contains(v, "://") && path.Join(dir, v) == other.Path {
+			return true
+		}
+	}
+	return false
+}
+
+func collectStrings(v any, out map[string]bool) {
+	switch t := v.(type) {
+	case string:
+		if s := strings.TrimSpace(t); s != "" {
+			out[s] = true
+		}
+	case []any:
+		for _, e := range t {
+			collectStrings(e, out)
+		}
+	case map[string]any:
+		for _, e := range t {
+			collectStrings(e, out)
+		}
+	}
+}
+
+func firstString(values ...any) string {
+	for _, v := range values {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+	v2momrender "github.com/grokify/structured-plan/goals/v2mom/render"
+	v2momhtml "github.com/grokify/structured-plan/goals/v2mom/render/html"
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	mrdhtml "github.com/grokify/structured-plan/requirements/mrd/render/html"
+	"github.com/grokify/structured-plan/requirements/prd"
+	prdrender "github.com/grokify/structured-plan/requirements/prd/render"
+	prdhtml "github.com/grokify/structured-plan/requirements/prd/render/html"
+	"github.com/grokify/structured-plan/requirements/trd"
+	trdhtml "github.com/grokify/structured-plan/requirements/trd/render/html"
+	"github.com/grokify/structured-plan/roadmap"
+	"github.com/grokify/structured-plan/workspace"
+)
+
+// renderDocument renders the document at file as a standalone HTML page
+// with unresolved review comments as margin notes.
+func renderDocument(file string, kind workspace.Kind, lenientMode bool) ([]byte, error) {
+	page, _, err := documentPage(file, kind, lenientMode)
+	if err != nil {
+		return nil, err
+	}
+	return page.Render()
+}
+
+// documentPage loads the document at file and lays it out as a page. It
+// also returns the decoded document. OKR and roadmap documents, which have
+// no HTML renderer, get a summary page.
+func documentPage(file string, kind workspace.Kind, lenientMode bool) (*htmldoc.Page, any, error) {
+	switch kind {
+	case workspace.KindPRD:
+		var doc prd.Document
+		if err := decodeFile(file, &doc, lenientMode); err != nil {
+			return nil, nil, err
+		}
+		opts := prdrender.DefaultOptions()
+		opts.IncludeComments = true
+		return prdhtml.New().Page(&doc, opts), &doc, nil
+	case workspace.KindMRD:
+		var doc mrd.Document
+		if err := decodeFile(file, &doc, lenientMode); err != nil {
+			return nil, nil, err
+		}
+		return mrdhtml.New().Page(&doc, &htmldoc.Options{IncludeComments: true}), &doc, nil
+	case workspace.KindTRD:
+		var doc trd.Document
+		if err := decodeFile(file, &doc, lenientMode); err != nil {
+			return nil, nil, err
+		}
+		return trdhtml.New().Page(&doc, &htmldoc.Options{IncludeComments: true}), &doc, nil
+	case workspace.KindV2MOM:
+		var doc v2mom.V2MOM
+		if err := decodeFile(file, &doc, lenientMode); err != nil {
+			return nil, nil, err
+		}
+		return v2momhtml.New().Page(&doc, v2momrender.DefaultOptions()), &doc, nil
+	case workspace.KindOKR:
+		var doc okr.OKRDocument
+		if err := decodeFile(file, &doc, lenientMode); err != nil {
+			return nil, nil, err
+		}
+		return okrPage(&doc), &doc, nil
+	case workspace.KindRoadmap:
+		var doc roadmap.Document
+		if err := decodeFile(file, &doc, lenientMode); err != nil {
+			return nil, nil, err
+		}
+		return roadmapPage(&doc), &doc, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported document type %q", kind)
+}
+
+func decodeFile(file string, v any, lenientMode bool) error {
+	data, err := workspace.ReadJSON(file)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	if lenientMode {
+		if _, err := lenient.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("parsing JSON: %w", err)
+		}
+	} else if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing JSON: %w", err)
+	}
+	return workspace.ApplyDefaults(file, v)
+}
+
+func okrPage(doc *okr.OKRDocument) *htmldoc.Page {
+	page := htmldoc.NewPage("OKRs", "OKRs")
+	if m := doc.Metadata; m != nil {
+		if m.Name != "" {
+			page.Title = m.Name
+		}
+		page.Meta = []htmldoc.Field{
+			{Label: "ID", Value: m.ID},
+			{Label: "Owner", Value: m.Owner},
+			{Label: "Team", Value: m.Team},
+			{Label: "Period", Value: m.Period},
+			{Label: "Status", Value: m.Status},
+			{Label: "Updated", Value: htmldoc.Date(m.UpdatedAt)},
+		}
+	}
+	page.Summary = doc.Theme
+	for _, o := range doc.Objectives {
+		b := htmldoc.NewBuilder(o.Title)
+		b.Paragraph(o.Description)
+		b.Fields(htmldoc.Field{Label: "ID", Value: o.ID}, htmldoc.Field{Label: "Owner", Value: o.Owner},
+			htmldoc.Field{Label: "Status", Value: o.Status}, htmldoc.Field{Label: "Timeframe", Value: o.Timeframe})
+		var rows [][]string
+		for _, kr := range o.KeyResults {
+			rows = append(rows, []string{kr.ID, kr.Title, kr.Baseline, kr.TargetLabel(), kr.Current, kr.Status})
+		}
+		b.Table([]string{"ID", "Key Result", "Baseline", "Target", "Current", "Status"}, rows)
+		page.AddSection(o.Title, b)
+	}
+	return page
+}
+
+func roadmapPage(doc *roadmap.Document) *htmldoc.Page {
+	m := doc.Metadata
+	page := htmldoc.NewPage("Roadmap", m.Title)
+	page.Summary = doc.Vision
+	page.Meta = []htmldoc.Field{
+		{Label: "ID", Value: m.ID},
+		{Label: "Owner", Value: m.Owner},
+		{Label: "Team", Value: m.Team},
+		{Label: "Version", Value: m.Version},
+		{Label: "Status", Value: m.Status},
+		{Label: "Updated", Value: htmldoc.Date(m.UpdatedAt)},
+	}
+
+	if len(doc.Themes) > 0 {
+		b := htmldoc.NewBuilder("themes")
+		var rows [][]string
+		for _, t := range doc.Themes {
+			rows = append(rows, []string{t.ID, t.Name, t.Owner, t.Description})
+		}
+		b.Table([]string{"ID", "Theme", "Owner", "Description"}, rows)
+		page.AddSection("Themes", b)
+	}
+
+	b := htmldoc.NewBuilder("phases")
+	var rows [][]string
+	for _, p := range doc.Phases {
+		var dates []string
+		if p.StartDate != nil {
+			dates = append(dates, htmldoc.Date(*p.StartDate))
+		}
+		if p.EndDate != nil {
+			dates = append(dates, htmldoc.Date(*p.EndDate))
+		}
+		rows = append(rows, []string{p.ID, p.Name, string(p.Status), strings.Join(dates, " – "), strings.Join(p.Goals, "; ")})
+	}
+	b.Table([]string{"ID", "Phase", "Status", "Dates", "Goals"}, rows)
+	page.AddSection("Phases", b)
+
+	if len(doc.Epics) > 0 {
+		b := htmldoc.NewBuilder("epics")
+		var rows [][]string
+		for _, e := range doc.Epics {
+			rows = append(rows, []string{e.ID, e.Title, e.ThemeID, strings.Join(e.PhaseIDs, ", "), string(e.Status), e.Owner})
+		}
+		b.Table([]string{"ID", "Epic", "Theme", "Phases", "Status", "Owner"}, rows)
+		page.AddSection("Epics", b)
+	}
+
+	if len(doc.Milestones) > 0 {
+		b := htmldoc.NewBuilder("milestones")
+		var rows [][]string
+		for _, ms := range doc.Milestones {
+			rows = append(rows, []string{ms.ID, ms.Title, ms.Date, ms.PhaseID, string(ms.Status)})
+		}
+		b.Table([]string{"ID", "Milestone", "Date", "Phase", "Status"}, rows)
+		page.AddSection("Milestones", b)
+	}
+	return page
+}
@@ -0,0 +1,151 @@
+package serve
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// Entry is one searchable item of a static site: a document, one of its
+// sections, a requirement, or a glossary term. Entries are flat, with an
+// ID of letters, digits, hyphens, and underscores, so the index can be
+// loaded into lunr or imported into Meilisearch as it is.
+type Entry struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"` // relative to the site root
+	Type     string `json:"type"`
+	Kind     string `json:"kind"`
+	Document string `json:"document"`
+	Title    string `json:"title"`
+	Text     string `json:"text,omitempty"`
+}
+
+// Entry types.
+const (
+	EntryDocument    = "document"
+	EntrySection     = "section"
+	EntryRequirement = "requirement"
+	EntryGlossary    = "glossary"
+)
+
+// indexDocument returns the search entries for a document laid out as
+// page, published at url.
+func indexDocument(d *Document, page *htmldoc.Page, decoded any, url string) []Entry {
+	base := Entry{Kind: string(d.Kind), Document: d.Label()}
+	entry := func(typ, key, anchor, title, text string) Entry {
+		e := base
+		e.ID = entryID(d.Path + "--" + key)
+		e.Type, e.Title, e.Text = typ, title, strings.TrimSpace(text)
+		e.URL = url
+		if anchor != "" {
+			e.URL += "#" + anchor
+		}
+		return e
+	}
+
+	var meta []string
+	for _, f := range page.Meta {
+		meta = append(meta, f.Value)
+	}
+	entries := []Entry{entry(EntryDocument, "doc", "", d.Label(), page.Summary+" "+strings.Join(meta, " "))}
+	for _, s := range page.Sections {
+		entries = append(entries, entry(EntrySection, s.ID, s.ID, s.Title, blocksText(s.Blocks)))
+	}
+
+	requirement := func(id, title string, text ...string) {
+		if id == "" {
+			return
+		}
+		entries = append(entries, entry(EntryRequirement, id, sectionWith(page, id), strings.TrimSpace(id+" "+title), strings.Join(text, " ")))
+	}
+	var glossary []common.GlossaryTerm
+	switch doc := decoded.(type) {
+	case *prd.Document:
+		for _, r := range doc.Requirements.Functional {
+			requirement(r.ID, r.Title, r.Description, r.Category)
+		}
+		for _, r := range doc.Requirements.NonFunctional {
+			requirement(r.ID, r.Title, r.Description, r.Metric, r.Target)
+		}
+		glossary = doc.Glossary
+	case *mrd.Document:
+		for _, r := range doc.MarketRequirements {
+			requirement(r.ID, r.Title, r.Description, r.Category)
+		}
+		glossary = doc.Glossary
+	case *trd.Document:
+		for _, r := range doc.Performance.Requirements {
+			requirement(r.ID, r.Name, r.Metric, r.Target)
+		}
+		glossary = doc.Glossary
+	}
+	for _, g := range glossary {
+		title := g.Term
+		if g.Acronym != "" {
+			title += " (" + g.Acronym + ")"
+		}
+		entries = append(entries, entry(EntryGlossary, "term-"+g.Term, sectionWith(page, g.Term), title, g.Definition+" "+g.Context))
+	}
+
+	// Duplicate requirement IDs or terms would replace each other in
+	// Meilisearch.
+	seen := make(map[string]int)
+	for i, e := range entries {
+		if seen[e.ID]++; seen[e.ID] > 1 {
+			entries[i].ID += "-" + strconv.Itoa(seen[e.ID])
+		}
+	}
+	return entries
+}
+
+// blocksText flattens the text of a section's blocks.
+func blocksText(blocks []htmldoc.Block) string {
+	var parts []string
+	add := func(s ...string) {
+		for _, v := range s {
+			if v = strings.TrimSpace(v); v != "" {
+				parts = append(parts, v)
+			}
+		}
+	}
+	for _, b := range blocks {
+		add(b.Label, b.Text)
+		add(b.Items...)
+		for _, f := range b.Fields {
+			add(f.Label, f.Value)
+		}
+		add(b.Header...)
+		for _, row := range b.Rows {
+			add(row...)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// sectionWith returns the ID of the first section of page mentioning s, or
+// "" if there is none.
+func sectionWith(page *htmldoc.Page, s string) string {
+	for _, sec := range page.Sections {
+		if strings.Contains(blocksText(sec.Blocks), s) {
+			return sec.ID
+		}
+	}
+	return ""
+}
+
+// entryID replaces the characters Meilisearch does not allow in document
+// IDs with hyphens.
+func entryID(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, s)
+}
@@ -0,0 +1,386 @@
+// Package serve runs a local preview server for a directory of planning
+// documents. Documents are rendered as HTML on each request, with a sidebar
+// listing every document in the directory and linking the documents each
+// one references or is referenced by. Open pages reload when a document
+// changes on disk. Build writes the same pages as a static site with an
+// offline search index.
+package serve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/workspace"
+)
+
+// DefaultPollInterval is how often the server checks documents for changes.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// Options configures a Server.
+type Options struct {
+	// Lenient recovers from field-level type errors when loading documents.
+	Lenient bool
+
+	// PollInterval is how often documents are checked for changes.
+	// Zero uses DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Server serves the planning documents in a directory.
+type Server struct {
+	root  string
+	start string // document shown at "/", when serving a single file
+	opts  Options
+
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+// New returns a server for path, a directory or a single planning
+// document. For a document, the sidebar lists the documents in its
+// directory and "/" opens the document.
+func New(p string, opts Options) (*Server, error) {
+	if opts.PollInterval == 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{root: p, opts: opts, clients: make(map[chan struct{}]bool)}
+	if !info.IsDir() {
+		if _, ok := workspace.KindFromPath(p); !ok {
+			return nil, fmt.Errorf("%s is not a planning document: name it *.<kind>.json or *.<kind>.yaml, where <kind> is prd, mrd, trd, okr, v2mom, or roadmap", p)
+		}
+		s.root, s.start = filepath.Dir(p), filepath.Base(p)
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler:
+//
+//	/              the workspace dashboard, or the document being served
+//	/doc/{path}    a document rendered as HTML
+//	/_events       server-sent events announcing changes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.serveIndex)
+	mux.HandleFunc("GET /doc/{path...}", s.serveDocument)
+	mux.HandleFunc("GET /_events", s.serveEvents)
+	return mux
+}
+
+// Watch polls the documents for changes until ctx is done, telling open
+// pages to reload when a document is added, removed, or modified.
+func (s *Server) Watch(ctx context.Context) {
+	last := s.snapshot()
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if current := s.snapshot(); current != last {
+				last = current
+				s.broadcast()
+			}
+		}
+	}
+}
+
+// snapshot summarizes the names, sizes, and modification times of the
+// documents, so any change to them changes the result.
+func (s *Server) snapshot() string {
+	paths, err := workspace.Discover(s.root)
+	if err != nil {
+		return err.Error()
+	}
+	var sb strings.Builder
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&sb, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return sb.String()
+}
+
+func (s *Server) broadcast() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		select {
+		case c <- struct{}{}:
+		default: // a reload is already pending
+		}
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	c := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[c] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, "retry: 1000\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if s.start != "" {
+		http.Redirect(w, r, "/doc/"+s.start, http.StatusFound)
+		return
+	}
+	docs, err := Catalog(s.root)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ws, err := workspace.Scan(s.root, workspace.Options{Lenient: s.opts.Lenient})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var page bytes.Buffer
+	if err := ws.WriteHTML(&page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.write(w, http.StatusOK, page.Bytes(), docs, nil)
+}
+
+func (s *Server) serveDocument(w http.ResponseWriter, r *http.Request) {
+	docs, err := Catalog(s.root)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	doc := Find(docs, path.Clean(r.PathValue("path")))
+	if doc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	page, err := renderDocument(filepath.Join(s.root, filepath.FromSlash(doc.Path)), doc.Kind, s.opts.Lenient)
+	status := http.StatusOK
+	if err != nil {
+		// Keep the sidebar and reload script, so the page recovers when
+		// the file is fixed.
+		errPage := htmldoc.NewPage(string(doc.Kind), doc.Label())
+		errPage.Banner = fmt.Sprintf("%s cannot be rendered: %v", doc.Path, err)
+		if page, err = errPage.Render(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		status = http.StatusUnprocessableEntity
+	}
+	s.write(w, status, page, docs, doc)
+}
+
+// write sends page with the sidebar, its styles, and the reload script
+// added.
+func (s *Server) write(w http.ResponseWriter, status int, page []byte, docs []*Document, current *Document) {
+	page, err := withNav(page, docs, current, serverLinks)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page = insertBefore(page, "</body>", reloadScript)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_, _ = w.Write(page)
+}
+
+// withNav adds the sidebar and its styles to page.
+func withNav(page []byte, docs []*Document, current *Document, links siteLinks) ([]byte, error) {
+	var nav bytes.Buffer
+	if err := navTemplate.Execute(&nav, navData(docs, current, links)); err != nil {
+		return nil, fmt.Errorf("rendering navigation: %w", err)
+	}
+	page = insertBefore(page, "</head>", navStyle)
+	return insertAfter(page, "<body>", nav.String()), nil
+}
+
+func insertBefore(page []byte, marker, s string) []byte {
+	i := bytes.LastIndex(page, []byte(marker))
+	if i < 0 {
+		return page
+	}
+	return append(page[:i:i], append([]byte(s), page[i:]...)...)
+}
+
+func insertAfter(page []byte, marker, s string) []byte {
+	i := bytes.Index(page, []byte(marker))
+	if i < 0 {
+		return append([]byte(s), page...)
+	}
+	i += len(marker)
+	return append(page[:i:i], append([]byte(s), page[i:]...)...)
+}
+
+type navGroup struct {
+	Label string
+	Docs  []navLink
+}
+
+type navLink struct {
+	Href    string
+	Path    string
+	Label   string
+	Error   string
+	Current bool
+}
+
+// siteLinks maps the sidebar's links to URLs, which differ between the
+// server and a static build.
+type siteLinks struct {
+	home string
+	doc  func(path string) string
+
+	// search is the URL prefix of the search index files next to the
+	// home page, or "" for no search box.
+	search string
+}
+
+var serverLinks = siteLinks{
+	home: "/",
+	doc:  func(p string) string { return "/doc/" + p },
+}
+
+func navData(docs []*Document, current *Document, links siteLinks) any {
+	byPath := make(map[string]*Document, len(docs))
+	for _, d := range docs {
+		byPath[d.Path] = d
+	}
+	link := func(d *Document) navLink {
+		return navLink{Href: links.doc(d.Path), Path: d.Path, Label: d.Label(), Error: d.Error, Current: d == current}
+	}
+	related := func(paths []string) []navLink {
+		var out []navLink
+		for _, p := range paths {
+			out = append(out, link(byPath[p]))
+		}
+		return out
+	}
+
+	var groups []navGroup
+	for _, k := range workspace.Kinds {
+		g := navGroup{Label: kindLabels[k]}
+		for _, d := range docs {
+			if d.Kind == k {
+				g.Docs = append(g.Docs, link(d))
+			}
+		}
+		if len(g.Docs) > 0 {
+			groups = append(groups, g)
+		}
+	}
+
+	data := struct {
+		Home         string
+		Search       bool
+		Groups       []navGroup
+		References   []navLink
+		ReferencedBy []navLink
+	}{Home: links.home, Search: links.search != "", Groups: groups}
+	if current != nil {
+		data.References = related(current.References)
+		data.ReferencedBy = related(current.ReferencedBy)
+	}
+	return data
+}
+
+var kindLabels = map[workspace.Kind]string{
+	workspace.KindPRD:     "Product Requirements",
+	workspace.KindMRD:     "Market Requirements",
+	workspace.KindTRD:     "Technical Requirements",
+	workspace.KindOKR:     "OKRs",
+	workspace.KindV2MOM:   "V2MOMs",
+	workspace.KindRoadmap: "Roadmaps",
+}
+
+var navTemplate = template.Must(template.New("nav").Parse(`
+<nav class="splan-nav" aria-label="Planning documents">
+<p class="splan-nav-home"><a href="{{.Home}}">All documents</a></p>
+{{- if .Search}}
+<div class="splan-search">
+<input type="search" id="splan-search" placeholder="Search" aria-label="Search documents" autocomplete="off">
+<ol id="splan-search-results"></ol>
+</div>
+{{- end}}
+{{- if or .References .ReferencedBy}}
+<div class="splan-nav-related">
+{{- if .References}}
+<h2>References</h2>
+<ul>{{range .References}}<li><a href="{{.Href}}">{{.Label}}</a></li>{{end}}</ul>
+{{- end}}
+{{- if .ReferencedBy}}
+<h2>Referenced by</h2>
+<ul>{{range .ReferencedBy}}<li><a href="{{.Href}}">{{.Label}}</a></li>{{end}}</ul>
+{{- end}}
+</div>
+{{- end}}
+{{- range .Groups}}
+<h2>{{.Label}}</h2>
+<ul>{{range .Docs}}<li{{if .Current}} class="current"{{end}}><a href="{{.Href}}" title="{{.Path}}">{{.Label}}</a>{{if .Error}} <span class="splan-nav-error" title="{{.Error}}">!</span>{{end}}</li>{{end}}</ul>
+{{- end}}
+</nav>
+`))
+
+const navStyle = `<style>
+.splan-nav { position: fixed; top: 0; left: 0; bottom: 0; width: 16rem; overflow-y: auto; padding: 1rem; background: #f5f7fa; border-right: 1px solid #d9e2ec; font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+.splan-nav h2 { display: block; margin: 1rem 0 0.25rem; font-size: 0.75rem; color: #616e7c; text-transform: uppercase; letter-spacing: 0.05em; }
+.splan-nav ul { list-style: none; margin: 0; padding: 0; }
+.splan-nav li { margin: 0.15rem 0; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.splan-nav li.current a { font-weight: 600; color: inherit; }
+.splan-nav-related { border-bottom: 1px solid #d9e2ec; padding-bottom: 0.75rem; }
+.splan-nav-error { color: #c53030; font-weight: 700; }
+.splan-search input { width: 100%; padding: 0.3rem 0.5rem; border: 1px solid #d9e2ec; border-radius: 4px; font: inherit; }
+.splan-search ol { list-style: none; margin: 0.5rem 0 0; padding: 0; }
+.splan-search li { white-space: normal; margin: 0 0 0.5rem; }
+.splan-search small { display: block; color: #616e7c; }
+body { margin-left: 17rem; }
+@media (max-width: 60rem) {
+  .splan-nav { position: static; width: auto; border-right: none; border-bottom: 1px solid #d9e2ec; }
+  body { margin-left: auto; }
+}
+@media print { .splan-nav { display: none; } body { margin-left: auto; } }
+</style>
+`
+
+const reloadScript = `<script>
+new EventSource("/_events").onmessage = function () { location.reload(); };
+</script>
+`
@@ -0,0 +1,284 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, dir, "checkout.prd.json", `{
+		"metadata": {"id": "PRD-1", "title": "Checkout", "version": "1.0", "status": "draft"},
+		"executiveSummary": {"problemStatement": "Slow checkout"},
+		"provenance": {"source": {"type": "mrd", "id": "MRD-1"}}
+	}`)
+	writeFile(t, dir, "market/payments.mrd.json", `{
+		"metadata": {"id": "MRD-1", "title": "Payments Market", "version": "1.0", "status": "draft"}
+	}`)
+	writeFile(t, dir, "plan.roadmap.json", `{
+		"metadata": {"id": "RM-1", "title": "Plan"},
+		"phases": [{"id": "p1", "name": "Launch", "goals": ["Ship checkout"], "deliverables": [{"id": "d1", "title": "Checkout", "description": "checkout.prd.json", "type": "feature"}]}]
+	}`)
+	writeFile(t, dir, "broken.okr.json", `{"metadata":`)
+	return dir
+}
+
+func TestCatalog(t *testing.T) {
+	docs, err := Catalog(testDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prd := Find(docs, "checkout.prd.json")
+	mrd := Find(docs, "market/payments.mrd.json")
+	if prd == nil || mrd == nil {
+		t.Fatalf("documents not found: %v", docs)
+	}
+	if prd.ID != "PRD-1" || prd.Label() != "Checkout" {
+		t.Errorf("PRD = %q %q", prd.ID, prd.Label())
+	}
+	if !reflect.DeepEqual(prd.References, []string{"market/payments.mrd.json"}) {
+		t.Errorf("PRD references = %v", prd.References)
+	}
+	if !reflect.DeepEqual(prd.ReferencedBy, []string{"plan.roadmap.json"}) {
+		t.Errorf("PRD referenced by = %v", prd.ReferencedBy)
+	}
+	if !reflect.DeepEqual(mrd.ReferencedBy, []string{"checkout.prd.json"}) {
+		t.Errorf("MRD referenced by = %v", mrd.ReferencedBy)
+	}
+	broken := Find(docs, "broken.okr.json")
+	if broken == nil || broken.Error == "" || broken.Label() != "broken.okr.json" {
+		t.Errorf("broken document = %+v", broken)
+	}
+}
+
+func get(t *testing.T, h http.Handler, target string) (*http.Response, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	resp := rec.Result()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestHandler(t *testing.T) {
+	srv, err := New(testDir(t), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := srv.Handler()
+
+	resp, body := get(t, h, "/")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `class="splan-nav"`) {
+		t.Errorf("GET / = %d\n%s", resp.StatusCode, body)
+	}
+
+	resp, body = get(t, h, "/doc/checkout.prd.json")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET PRD = %d\n%s", resp.StatusCode, body)
+	}
+	for _, want := range []string{
+		"Slow checkout",
+		`<h2>References</h2>`,
+		`<a href="/doc/market/payments.mrd.json">Payments Market</a>`,
+		`<h2>Referenced by</h2>`,
+		`<li class="current"><a href="/doc/checkout.prd.json"`,
+		`new EventSource("/_events")`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("PRD page missing %q", want)
+		}
+	}
+
+	for _, target := range []string{"/doc/market/payments.mrd.json", "/doc/plan.roadmap.json"} {
+		if resp, body := get(t, h, target); resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s = %d\n%s", target, resp.StatusCode, body)
+		}
+	}
+
+	resp, body = get(t, h, "/doc/broken.okr.json")
+	if resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(body, "cannot be rendered") {
+		t.Errorf("GET broken = %d\n%s", resp.StatusCode, body)
+	}
+
+	for _, target := range []string{"/doc/missing.prd.json", "/doc/../checkout.prd.json"} {
+		if resp, _ := get(t, h, target); resp.StatusCode == http.StatusOK {
+			t.Errorf("GET %s = %d, want not found", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	dir := testDir(t)
+	srv, err := New(filepath.Join(dir, "checkout.prd.json"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := get(t, srv.Handler(), "/")
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/doc/checkout.prd.json" {
+		t.Errorf("GET / = %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	if _, err := New(filepath.Join(dir, "notes.txt"), Options{}); err == nil {
+		t.Error("New(missing file) succeeded")
+	}
+	writeFile(t, dir, "notes.json", `{}`)
+	if _, err := New(filepath.Join(dir, "notes.json"), Options{}); err == nil {
+		t.Error("New(notes.json) succeeded")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := testDir(t)
+	srv, err := New(dir, Options{PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Watch(ctx)
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/_events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	events := make(chan string)
+	go func() {
+		buf := make([]byte, 256)
+		var got strings.Builder
+		for {
+			n, err := resp.Body.Read(buf)
+			got.Write(buf[:n])
+			if strings.Contains(got.String(), "data: reload") || err != nil {
+				events <- got.String()
+				return
+			}
+		}
+	}()
+	// Keep changing the document, in case the first write lands before
+	// Watch takes its initial snapshot.
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		select {
+		case got := <-events:
+			if !strings.Contains(got, "data: reload") {
+				t.Errorf("events = %q", got)
+			}
+			return
+		case <-ticker.C:
+			writeFile(t, dir, "new.trd.json", fmt.Sprintf(`{"metadata": {"id": "TRD-%d"}}`, i))
+		case <-timeout:
+			t.Fatal("no reload event after changing a document")
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := testDir(t)
+	writeFile(t, dir, "checkout.prd.json", `{
+		"metadata": {"id": "PRD-1", "title": "Checkout", "version": "1.0", "status": "draft"},
+		"executiveSummary": {"problemStatement": "Slow checkout"},
+		"provenance": {"source": {"type": "mrd", "id": "MRD-1"}},
+		"requirements": {"functional": [{"id": "FR-1", "title": "Wallet payments", "description": "Pay with a saved wallet"}]},
+		"glossary": [{"term": "Wallet", "definition": "A stored payment method"}]
+	}`)
+	srv, err := New(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	res, err := srv.Build(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pages != 5 || res.Failed != 1 {
+		t.Errorf("Build() = %+v, want 5 pages with 1 failed", res)
+	}
+
+	page, err := os.ReadFile(filepath.Join(out, "market", "payments.mrd.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<a href="../index.html">All documents</a>`,
+		`<a href="../checkout.prd.html">Checkout</a>`,
+		`<script src="../search-index.js"></script>`,
+		`splanSearch("../");`,
+		`id="splan-search"`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("MRD page missing %q", want)
+		}
+	}
+	if strings.Contains(string(page), "EventSource") {
+		t.Error("static page has the reload script")
+	}
+	if _, err := os.Stat(filepath.Join(out, "index.html")); err != nil {
+		t.Error(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, SearchIndexJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]Entry)
+	for _, e := range entries {
+		if _, dup := byID[e.ID]; dup {
+			t.Errorf("duplicate entry ID %q", e.ID)
+		}
+		if strings.Trim(e.ID, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_") != "" {
+			t.Errorf("entry ID %q has characters Meilisearch rejects", e.ID)
+		}
+		byID[e.ID] = e
+	}
+	fr := byID["checkout-prd-json--FR-1"]
+	if fr.Type != EntryRequirement || fr.Title != "FR-1 Wallet payments" || fr.Document != "Checkout" ||
+		!strings.HasPrefix(fr.URL, "checkout.prd.html#") || fr.Text != "Pay with a saved wallet" {
+		t.Errorf("requirement entry = %+v", fr)
+	}
+	if g := byID["checkout-prd-json--term-Wallet"]; g.Type != EntryGlossary || !strings.Contains(g.Text, "stored payment") {
+		t.Errorf("glossary entry = %+v", g)
+	}
+	if d := byID["market-payments-mrd-json--doc"]; d.Type != EntryDocument || d.URL != "market/payments.mrd.html" {
+		t.Errorf("document entry = %+v", d)
+	}
+
+	script, err := os.ReadFile(filepath.Join(out, SearchIndexJS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(script), "window.splanSearchIndex = [") {
+		t.Errorf("%s starts %.40q", SearchIndexJS, script)
+	}
+}
@@ -0,0 +1,545 @@
+// Package snippets expands reusable content, such as a standard set of
+// non-functional requirements, a security model, or boilerplate
+// assumptions, into the planning documents that reference it by ID, and
+// detects expanded content that was edited since.
+//
+// A document references a snippet with an object whose only member is
+// "$snippet":
+//
+//	"nonFunctional": [
+//	  {"$snippet": "standard-nfrs"},
+//	  {"id": "NFR-100", "category": "performance", ...}
+//	]
+//
+// In a list, a snippet whose content is a list adds its items in place of
+// the reference; any other content replaces the reference.
+package snippets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/validation"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// RefKey is the member of a snippet reference.
+const RefKey = "$snippet"
+
+// Rule IDs of the issues Check reports.
+const (
+	RuleDrift    = "snippet-drift"
+	RuleOutdated = "snippet-outdated"
+	RuleUnknown  = "snippet-unknown"
+)
+
+func init() {
+	validation.DescribeRule(RuleDrift, "Content expanded from a snippet was edited in the document.")
+	validation.DescribeRule(RuleOutdated, "A snippet changed in the library after it was expanded into the document.")
+	validation.DescribeRule(RuleUnknown, "Content was expanded from a snippet that is no longer in the library.")
+}
+
+// Snippet is reusable document content.
+type Snippet struct {
+	ID          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Content is the JSON value a reference expands to.
+	Content json.RawMessage `json:"content"`
+}
+
+// Library is a set of snippets.
+type Library struct {
+	Snippets []Snippet `json:"snippets"`
+}
+
+// Load reads a YAML or JSON snippet library.
+func Load(path string) (*Library, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snippet library: %w", err)
+	}
+	lib, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return lib, nil
+}
+
+// Parse parses a YAML or JSON snippet library. Every snippet must have an
+// ID, unique in the library, and content.
+func Parse(data []byte) (*Library, error) {
+	data, err := yamlconv.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var lib Library
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return nil, fmt.Errorf("parsing snippet library: %w", err)
+	}
+	seen := map[string]bool{}
+	for i, s := range lib.Snippets {
+		switch {
+		case s.ID == "":
+			return nil, fmt.Errorf("snippets[%d]: id is required", i)
+		case seen[s.ID]:
+			return nil, fmt.Errorf("snippets[%d]: duplicate snippet %q", i, s.ID)
+		case len(bytes.TrimSpace(s.Content)) == 0 || bytes.Equal(bytes.TrimSpace(s.Content), []byte("null")):
+			return nil, fmt.Errorf("snippets[%d]: snippet %q has no content", i, s.ID)
+		}
+		seen[s.ID] = true
+	}
+	return &lib, nil
+}
+
+// Add adds the snippets of other, which must not repeat an ID.
+func (l *Library) Add(other *Library) error {
+	for _, s := range other.Snippets {
+		if _, ok := l.Get(s.ID); ok {
+			return fmt.Errorf("duplicate snippet %q", s.ID)
+		}
+		l.Snippets = append(l.Snippets, s)
+	}
+	return nil
+}
+
+// Get returns the snippet with the ID.
+func (l *Library) Get(id string) (*Snippet, bool) {
+	if l == nil {
+		return nil, false
+	}
+	for i := range l.Snippets {
+		if l.Snippets[i].ID == id {
+			return &l.Snippets[i], true
+		}
+	}
+	return nil, false
+}
+
+// Digest returns the SHA-256 digest of the snippet content.
+func (s *Snippet) Digest() (string, error) {
+	var v any
+	if err := json.Unmarshal(s.Content, &v); err != nil {
+		return "", fmt.Errorf("snippet %q: %w", s.ID, err)
+	}
+	return digest(v), nil
+}
+
+// digest returns the SHA-256 digest of a decoded JSON value. Members with
+// empty values are left out, so content that went through a document type
+// whose fields are written even when empty keeps its digest.
+func digest(v any) string {
+	data, _ := json.Marshal(compact(v))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// compact returns v without the object members that are null, false, 0,
+// or empty.
+func compact(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			if e = compact(e); !isEmpty(e) {
+				out[k] = e
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = compact(e)
+		}
+		return out
+	}
+	return v
+}
+
+func isEmpty(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !x
+	case float64:
+		return x == 0
+	case string:
+		return x == ""
+	case []any:
+		return len(x) == 0
+	case map[string]any:
+		return len(x) == 0
+	}
+	return false
+}
+
+// HasRefs reports whether a JSON document may reference snippets.
+func HasRefs(data []byte) bool {
+	return bytes.Contains(data, []byte(`"`+RefKey+`"`))
+}
+
+// Expand returns the JSON document data with its snippet references
+// replaced by the snippet content, and the expansions made. Each expansion
+// is also recorded in the document's metadata.snippets, so Check can tell
+// when the content is edited. References inside snippet content are not
+// expanded.
+func Expand(data []byte, lib *Library) ([]byte, []common.SnippetRef, error) {
+	if !HasRefs(data) {
+		return data, nil, nil
+	}
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var refs []common.SnippetRef
+	root, err = expand(root, "", lib, &refs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(refs) == 0 {
+		return data, nil, nil
+	}
+
+	doc, ok := root.(*query.Object)
+	if !ok {
+		return nil, nil, fmt.Errorf("the document is not a JSON object")
+	}
+	meta, ok := doc.Fields["metadata"].(*query.Object)
+	if !ok {
+		meta = &query.Object{Fields: map[string]any{}}
+		doc.Keys = append([]string{"metadata"}, doc.Keys...)
+		doc.Fields["metadata"] = meta
+	}
+	recorded, _ := meta.Fields["snippets"].([]any)
+	if _, ok := meta.Fields["snippets"]; !ok {
+		meta.Keys = append(meta.Keys, "snippets")
+	}
+	for _, r := range refs {
+		recorded = append(recorded, r)
+	}
+	meta.Fields["snippets"] = recorded
+
+	out, err := json.Marshal(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling expanded document: %w", err)
+	}
+	return out, refs, nil
+}
+
+// expand returns v with the snippet references under it expanded.
+func expand(v any, pointer string, lib *Library, refs *[]common.SnippetRef) (any, error) {
+	switch x := v.(type) {
+	case *query.Object:
+		id, ok, err := reference(x, pointer)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			content, ref, err := resolve(id, pointer, lib)
+			if err != nil {
+				return nil, err
+			}
+			*refs = append(*refs, ref)
+			return content, nil
+		}
+		for _, k := range x.Keys {
+			e, err := expand(x.Fields[k], pointer+jsonpatch.Pointer(k), lib, refs)
+			if err != nil {
+				return nil, err
+			}
+			x.Fields[k] = e
+		}
+		return x, nil
+	case []any:
+		out := make([]any, 0, len(x))
+		for i, e := range x {
+			obj, _ := e.(*query.Object)
+			id, ok, err := reference(obj, pointer+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				e, err := expand(e, pointer+"/"+strconv.Itoa(len(out)), lib, refs)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, e)
+				continue
+			}
+			at := pointer + "/" + strconv.Itoa(len(out))
+			content, ref, err := resolve(id, at, lib)
+			if err != nil {
+				return nil, err
+			}
+			if items, ok := content.([]any); ok {
+				ref.Count = len(items)
+				out = append(out, items...)
+			} else {
+				out = append(out, content)
+			}
+			*refs = append(*refs, ref)
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// reference returns the snippet ID of a reference object.
+func reference(obj *query.Object, pointer string) (string, bool, error) {
+	if obj == nil {
+		return "", false, nil
+	}
+	v, ok := obj.Fields[RefKey]
+	if !ok {
+		return "", false, nil
+	}
+	id, isString := v.(string)
+	if !isString || id == "" || len(obj.Keys) != 1 {
+		return "", false, fmt.Errorf("%s: a snippet reference must be an object with only a %q string", pointerOrRoot(pointer), RefKey)
+	}
+	return id, true, nil
+}
+
+// resolve returns the content of the snippet, decoded afresh so each
+// expansion is a separate value, and its record.
+func resolve(id, pointer string, lib *Library) (any, common.SnippetRef, error) {
+	s, ok := lib.Get(id)
+	if !ok {
+		return nil, common.SnippetRef{}, fmt.Errorf("%s: unknown snippet %q", pointerOrRoot(pointer), id)
+	}
+	content, err := query.Decode(s.Content)
+	if err != nil {
+		return nil, common.SnippetRef{}, fmt.Errorf("snippet %q: %w", id, err)
+	}
+	d, err := s.Digest()
+	if err != nil {
+		return nil, common.SnippetRef{}, err
+	}
+	return content, common.SnippetRef{ID: id, Pointer: pointer, Digest: d}, nil
+}
+
+// Collapse returns the JSON document data with the content of the
+// expansions refs, as returned by Expand, replaced by their snippet
+// references again and their records removed from metadata.snippets. It
+// undoes Expand for commands that read a document, change it, and write it
+// back. Content that was edited since it was expanded is kept, with its
+// record, so Check reports it. Items inserted before the content of a list
+// snippet are allowed for.
+func Collapse(data []byte, refs []common.SnippetRef) ([]byte, error) {
+	if len(refs) == 0 {
+		return data, nil
+	}
+	root, err := query.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	collapsed := map[common.SnippetRef]bool{}
+	// Later expansions come first, so the pointers of earlier ones still
+	// hold when a list snippet's items are replaced by one reference.
+	for i := len(refs) - 1; i >= 0; i-- {
+		r := refs[i]
+		if r.Pointer == "" {
+			continue
+		}
+		var ok bool
+		if root, ok = collapse(root, strings.Split(r.Pointer[1:], "/"), r); ok {
+			collapsed[r] = true
+		}
+	}
+	if len(collapsed) == 0 {
+		return data, nil
+	}
+
+	if doc, ok := root.(*query.Object); ok {
+		if meta, ok := doc.Fields["metadata"].(*query.Object); ok {
+			forgetRecords(meta, collapsed)
+		}
+	}
+	out, err := json.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling collapsed document: %w", err)
+	}
+	return out, nil
+}
+
+// collapse returns v with the content r describes, at the path given by
+// tokens, replaced by a reference to its snippet, and whether it was.
+func collapse(v any, tokens []string, r common.SnippetRef) (any, bool) {
+	token := strings.ReplaceAll(strings.ReplaceAll(tokens[0], "~1", "/"), "~0", "~")
+	if len(tokens) > 1 {
+		var done bool
+		switch x := v.(type) {
+		case *query.Object:
+			if e, ok := x.Fields[token]; ok {
+				x.Fields[token], done = collapse(e, tokens[1:], r)
+			}
+		case []any:
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(x) {
+				x[i], done = collapse(x[i], tokens[1:], r)
+			}
+		}
+		return v, done
+	}
+
+	ref := &query.Object{Keys: []string{RefKey}, Fields: map[string]any{RefKey: r.ID}}
+	switch x := v.(type) {
+	case *query.Object:
+		if e, ok := x.Fields[token]; ok && digest(plain(e)) == r.Digest {
+			x.Fields[token] = ref
+			return x, true
+		}
+	case []any:
+		at, err := strconv.Atoi(token)
+		if err != nil {
+			return v, false
+		}
+		n := max(r.Count, 1)
+		starts := []int{at}
+		for start := 0; start+n <= len(x); start++ {
+			starts = append(starts, start)
+		}
+		for _, start := range starts {
+			if start < 0 || start+n > len(x) {
+				continue
+			}
+			var content any = x[start]
+			if r.Count > 0 {
+				content = x[start : start+n]
+			}
+			if digest(plain(content)) == r.Digest {
+				out := append(append(x[:start:start], ref), x[start+n:]...)
+				return out, true
+			}
+		}
+	}
+	return v, false
+}
+
+// plain returns v, decoded with query.Decode, as json.Unmarshal decodes
+// it, which digest expects.
+func plain(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// forgetRecords removes the records of the collapsed expansions from the
+// metadata, and its snippets member if none are left.
+func forgetRecords(meta *query.Object, collapsed map[common.SnippetRef]bool) {
+	recorded, _ := meta.Fields["snippets"].([]any)
+	kept := recorded[:0]
+	for _, e := range recorded {
+		var r common.SnippetRef
+		if data, err := json.Marshal(e); err == nil && json.Unmarshal(data, &r) == nil && collapsed[r] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if len(kept) > 0 {
+		meta.Fields["snippets"] = kept
+		return
+	}
+	delete(meta.Fields, "snippets")
+	meta.Keys = slices.DeleteFunc(meta.Keys, func(k string) bool { return k == "snippets" })
+}
+
+// Check compares the content recorded in the metadata.snippets of a JSON
+// document with the record and with the library. It warns of content
+// edited in the document, of snippets changed in the library since they
+// were expanded, and of snippets no longer in the library. List items
+// moved within their list as a block are not reported.
+func Check(data []byte, lib *Library) ([]validation.Issue, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var meta struct {
+		Metadata struct {
+			Snippets []common.SnippetRef `json:"snippets"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+
+	var issues []validation.Issue
+	warn := func(rule, pointer, format string, args ...any) {
+		issues = append(issues, validation.Issue{
+			RuleID:   rule,
+			Severity: validation.SeverityWarning,
+			Pointer:  pointer,
+			Message:  fmt.Sprintf("%s: "+format, append([]any{pointer}, args...)...),
+		})
+	}
+	for _, r := range meta.Metadata.Snippets {
+		if !unchanged(doc, r) {
+			warn(RuleDrift, r.Pointer, "content from snippet %q was edited; update the snippet or remove its entry from metadata.snippets", r.ID)
+			continue
+		}
+		s, ok := lib.Get(r.ID)
+		if !ok {
+			warn(RuleUnknown, r.Pointer, "snippet %q is not in the library", r.ID)
+			continue
+		}
+		if d, err := s.Digest(); err == nil && d != r.Digest {
+			warn(RuleOutdated, r.Pointer, "snippet %q changed in the library since it was expanded", r.ID)
+		}
+	}
+	return issues, nil
+}
+
+// unchanged reports whether the content a record describes still has its
+// digest. The items a snippet added to a list are also looked for
+// elsewhere in the list, in case items were inserted before them.
+func unchanged(doc any, r common.SnippetRef) bool {
+	if r.Count == 0 {
+		v, err := jsonpatch.Get(doc, r.Pointer)
+		return err == nil && digest(v) == r.Digest
+	}
+	i := len(r.Pointer) - 1
+	for i >= 0 && r.Pointer[i] != '/' {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	parent, err := jsonpatch.Get(doc, r.Pointer[:i])
+	if err != nil {
+		return false
+	}
+	list, ok := parent.([]any)
+	if !ok {
+		return false
+	}
+	for start := 0; start+r.Count <= len(list); start++ {
+		if digest(list[start:start+r.Count]) == r.Digest {
+			return true
+		}
+	}
+	return false
+}
+
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+	return pointer
+}
@@ -0,0 +1,167 @@
+package snippets
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testLibrary = `
+snippets:
+  - id: nfrs
+    title: Standard NFRs
+    content:
+      - {id: NFR-SEC-1, category: security}
+      - {id: NFR-AV-1, category: availability}
+  - id: security
+    content: {overview: Zero trust}
+`
+
+func TestParse(t *testing.T) {
+	lib, err := Parse([]byte(testLibrary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := lib.Get("nfrs"); !ok || s.Title != "Standard NFRs" {
+		t.Errorf("Get(nfrs) = %+v, %v", s, ok)
+	}
+	for _, bad := range []string{
+		`{"snippets": [{"content": 1}]}`,
+		`{"snippets": [{"id": "a", "content": 1}, {"id": "a", "content": 2}]}`,
+		`{"snippets": [{"id": "a"}]}`,
+	} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%s) succeeded", bad)
+		}
+	}
+	if err := lib.Add(&Library{Snippets: []Snippet{{ID: "nfrs"}}}); err == nil {
+		t.Error("expected an error adding a duplicate snippet")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	lib, err := Parse([]byte(testLibrary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := `{"metadata": {"id": "PRD-1"}, "securityModel": {"$snippet": "security"},
+		"requirements": {"nonFunctional": [{"id": "NFR-1"}, {"$snippet": "nfrs"}, {"id": "NFR-2"}]}}`
+	data, refs, err := Expand([]byte(doc), lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"metadata":{"id":"PRD-1","snippets":[` +
+		`{"id":"security","pointer":"/securityModel","digest":"` + refs[0].Digest + `"},` +
+		`{"id":"nfrs","pointer":"/requirements/nonFunctional/1","count":2,"digest":"` + refs[1].Digest + `"}]},` +
+		`"securityModel":{"overview":"Zero trust"},` +
+		`"requirements":{"nonFunctional":[{"id":"NFR-1"},{"id":"NFR-SEC-1","category":"security"},{"id":"NFR-AV-1","category":"availability"},{"id":"NFR-2"}]}}`
+	if string(data) != want {
+		t.Errorf("expanded:\n%s\nwant:\n%s", data, want)
+	}
+
+	if same, refs, err := Expand([]byte(`{"a": "$snippets"}`), nil); err != nil || len(refs) != 0 || string(same) != `{"a": "$snippets"}` {
+		t.Errorf("document without references = %s, %v, %v", same, refs, err)
+	}
+	for _, bad := range []string{
+		`{"a": [{"$snippet": "missing"}]}`,
+		`{"a": {"$snippet": "nfrs", "id": "x"}}`,
+		`{"a": {"$snippet": 1}}`,
+	} {
+		if _, _, err := Expand([]byte(bad), lib); err == nil {
+			t.Errorf("Expand(%s) succeeded", bad)
+		}
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	lib, err := Parse([]byte(testLibrary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := `{"metadata":{"id":"PRD-1"},"securityModel":{"$snippet":"security"},` +
+		`"requirements":{"nonFunctional":[{"id":"NFR-1"},{"$snippet":"nfrs"},{"id":"NFR-2"}]}}`
+	data, refs, err := Expand([]byte(doc), lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Collapse(data, refs); err != nil || string(got) != doc {
+		t.Errorf("collapsed:\n%s\nwant:\n%s (%v)", got, doc, err)
+	}
+
+	// Empty members written by a typed round trip, and items inserted
+	// before the snippet's, still collapse; edited content stays expanded
+	// with its record.
+	roundTrip := strings.NewReplacer(
+		`{"id":"NFR-1"}`, `{"id":"NFR-0","category":""},{"id":"NFR-1"}`,
+		`"category":"security"}`, `"category":"security","description":""}`,
+		`"overview":"Zero trust"`, `"overview":"Zero trust, mostly"`,
+	).Replace(string(data))
+	got, err := Collapse([]byte(roundTrip), refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"metadata":{"id":"PRD-1","snippets":[` +
+		`{"id":"security","pointer":"/securityModel","digest":"` + refs[0].Digest + `"}]},` +
+		`"securityModel":{"overview":"Zero trust, mostly"},` +
+		`"requirements":{"nonFunctional":[{"id":"NFR-0","category":""},{"id":"NFR-1"},{"$snippet":"nfrs"},{"id":"NFR-2"}]}}`
+	if string(got) != want {
+		t.Errorf("collapsed after edits:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	lib, err := Parse([]byte(testLibrary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := Expand([]byte(`{"requirements": {"nonFunctional": [{"$snippet": "nfrs"}]}, "securityModel": {"$snippet": "security"}}`), lib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	edit := func(fn func(nfrs []any, sm map[string]any)) []byte {
+		var d map[string]any
+		_ = json.Unmarshal(data, &d)
+		fn(d["requirements"].(map[string]any)["nonFunctional"].([]any), d["securityModel"].(map[string]any))
+		out, _ := json.Marshal(d)
+		return out
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		lib  *Library
+		want []string
+	}{
+		{"unchanged", data, lib, nil},
+		// Empty members, as written by the document types, and items
+		// inserted before the snippet's are not edits.
+		{"empty members and moved items", edit(func(nfrs []any, sm map[string]any) {
+			nfrs[0].(map[string]any)["description"] = ""
+			sm["threatModel"] = map[string]any{}
+		}), lib, nil},
+		{"edited item", edit(func(nfrs []any, _ map[string]any) {
+			nfrs[1].(map[string]any)["category"] = "reliability"
+		}), lib, []string{RuleDrift}},
+		{"edited value", edit(func(_ []any, sm map[string]any) {
+			sm["overview"] = "Perimeter"
+		}), lib, []string{RuleDrift}},
+		{"outdated and unknown", data, &Library{Snippets: []Snippet{{ID: "nfrs", Content: json.RawMessage(`[]`)}}}, []string{RuleOutdated, RuleUnknown}},
+	}
+	for _, tt := range tests {
+		issues, err := Check(tt.data, tt.lib)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, is := range issues {
+			got = append(got, is.RuleID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: issues = %v, want %v", tt.name, issues, tt.want)
+		}
+	}
+}
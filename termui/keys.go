@@ -0,0 +1,143 @@
+package termui
+
+import "unicode/utf8"
+
+// KeyType is the kind of a key press.
+type KeyType int
+
+// Key types. Printable characters are KeyRune; everything else a view
+// responds to has its own type.
+const (
+	KeyUnknown KeyType = iota
+	KeyRune
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyBackspace
+	KeyDelete
+	KeyEsc
+	KeyCtrlU
+	KeyCtrlC
+)
+
+// Key is a key press, decoded from terminal input.
+type Key struct {
+	Type KeyType
+	Rune rune // the character of a KeyRune
+}
+
+// RuneKey returns the key press of a printable character.
+func RuneKey(r rune) Key {
+	return Key{Type: KeyRune, Rune: r}
+}
+
+// DecodeKeys decodes raw terminal input into key presses. Escape sequences
+// for the arrow, Home, End, and Delete keys are recognized in both their
+// CSI and SS3 forms; other input is decoded as UTF-8 text.
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+	for i := 0; i < len(input); {
+		b := input[i]
+		if b == 0x1b {
+			if i+2 < len(input) && (input[i+1] == '[' || input[i+1] == 'O') {
+				n, t := escapeSequence(input[i+2:])
+				keys = append(keys, Key{Type: t})
+				i += 2 + n
+				continue
+			}
+			keys = append(keys, Key{Type: KeyEsc})
+			i++
+			continue
+		}
+		if b < 0x20 || b == 0x7f {
+			keys = append(keys, controlKey(b))
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRune(input[i:])
+		if r != utf8.RuneError {
+			keys = append(keys, RuneKey(r))
+		}
+		i += n
+	}
+	return keys
+}
+
+// escapeSequence decodes the rest of an escape sequence after "ESC [" or
+// "ESC O" and returns its length.
+func escapeSequence(seq []byte) (int, KeyType) {
+	// Parameter bytes, e.g. "1~" for Home, end at a final byte in @..~.
+	n := 0
+	for n < len(seq) && (seq[n] < 0x40 || seq[n] > 0x7e) {
+		n++
+	}
+	if n == len(seq) {
+		return n, KeyUnknown
+	}
+	params, final := string(seq[:n]), seq[n]
+	switch final {
+	case 'A':
+		return n + 1, KeyUp
+	case 'B':
+		return n + 1, KeyDown
+	case 'C':
+		return n + 1, KeyRight
+	case 'D':
+		return n + 1, KeyLeft
+	case 'H':
+		return n + 1, KeyHome
+	case 'F':
+		return n + 1, KeyEnd
+	case '~':
+		switch params {
+		case "1", "7":
+			return n + 1, KeyHome
+		case "4", "8":
+			return n + 1, KeyEnd
+		case "3":
+			return n + 1, KeyDelete
+		}
+	}
+	return n + 1, KeyUnknown
+}
+
+func controlKey(b byte) Key {
+	switch b {
+	case '\r', '\n':
+		return Key{Type: KeyEnter}
+	case 0x7f, 0x08:
+		return Key{Type: KeyBackspace}
+	case 0x15:
+		return Key{Type: KeyCtrlU}
+	case 0x03:
+		return Key{Type: KeyCtrlC}
+	}
+	return Key{Type: KeyUnknown}
+}
+
+// Navigation maps the vi movement keys to the arrow keys they stand for
+// outside of text entry: h, j, k, and l, g for Home, and G for End.
+func (k Key) Navigation() Key {
+	if k.Type != KeyRune {
+		return k
+	}
+	switch k.Rune {
+	case 'k':
+		return Key{Type: KeyUp}
+	case 'j':
+		return Key{Type: KeyDown}
+	case 'h':
+		return Key{Type: KeyLeft}
+	case 'l':
+		return Key{Type: KeyRight}
+	case 'g':
+		return Key{Type: KeyHome}
+	case 'G':
+		return Key{Type: KeyEnd}
+	}
+	return k
+}
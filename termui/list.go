@@ -0,0 +1,43 @@
+package termui
+
+// List is the selection and scroll position of a list of rows.
+type List struct {
+	Cursor int // the selected row
+	Offset int // the first row shown
+}
+
+// Lines renders the rows of a list of n rows that fit in height lines,
+// scrolled to keep the cursor in view. row draws row i; the selected row
+// is marked with "> " and the others are indented to match. Lines short
+// of height are empty.
+func (l *List) Lines(n, height int, row func(i int) string) []string {
+	l.Cursor = max(0, min(l.Cursor, n-1))
+	if l.Cursor < l.Offset {
+		l.Offset = l.Cursor
+	}
+	if l.Cursor >= l.Offset+height {
+		l.Offset = l.Cursor - height + 1
+	}
+
+	var lines []string
+	for i := l.Offset; i < n && i < l.Offset+height; i++ {
+		marker := "  "
+		if i == l.Cursor {
+			marker = "> "
+		}
+		lines = append(lines, marker+row(i))
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// Truncate shortens s to width runes, marking the cut with "…".
+func Truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,84 @@
+// Package termui runs the full-screen terminal views of splan, such as the
+// PRD editor and the interactive review: it switches the terminal to raw
+// mode, redraws the view after each key press, and decodes the input into
+// key presses. Views render plain lines; List scrolls them and Truncate
+// fits them to the terminal width.
+package termui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by Run when input or output is not a terminal.
+var ErrNotTerminal = errors.New("an interactive terminal is needed")
+
+// Action tells the caller of Update what to do next.
+type Action int
+
+const (
+	// ActionNone needs no action beyond redrawing.
+	ActionNone Action = iota
+	// ActionSave asks the caller to save.
+	ActionSave
+	// ActionQuit ends the session.
+	ActionQuit
+)
+
+// Screen is a view that Run shows and sends key presses to.
+type Screen interface {
+	// View renders the screen for a terminal of the given size.
+	View(width, height int) string
+	// Update applies a key press.
+	Update(k Key) Action
+	// SetStatus sets the message shown at the bottom of the screen.
+	SetStatus(message string)
+}
+
+// Run shows s on the terminal until it asks to quit. save is called when
+// s asks to save; an error it returns is shown as the status. On return
+// the terminal is restored.
+func Run(in, out *os.File, s Screen, save func() error) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return ErrNotTerminal
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("entering raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(int(in.Fd()), state) }()
+
+	// Alternate screen with the cursor hidden; a view that takes text
+	// input draws its own cursor.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	buf := make([]byte, 256)
+	for {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		frame := strings.ReplaceAll(s.View(width, height), "\n", "\x1b[K\r\n")
+		fmt.Fprint(out, "\x1b[H"+frame+"\x1b[K\x1b[J")
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return fmt.Errorf("reading input: %w", err)
+		}
+		for _, k := range DecodeKeys(buf[:n]) {
+			switch s.Update(k) {
+			case ActionSave:
+				if err := save(); err != nil {
+					s.SetStatus("Save failed: " + err.Error())
+				}
+			case ActionQuit:
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package termui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	enter, esc := Key{Type: KeyEnter}, Key{Type: KeyEsc}
+	got := DecodeKeys([]byte("aé\x1b[A\r\x7f\x1b[3~\x1bOH\x15\x03\x1b"))
+	want := []Key{RuneKey('a'), RuneKey('é'), {Type: KeyUp}, enter, {Type: KeyBackspace},
+		{Type: KeyDelete}, {Type: KeyHome}, {Type: KeyCtrlU}, {Type: KeyCtrlC}, esc}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeKeys = %v, want %v", got, want)
+	}
+	if got := RuneKey('j').Navigation(); got != (Key{Type: KeyDown}) {
+		t.Errorf("j navigates to %v", got)
+	}
+}
+
+func TestListLines(t *testing.T) {
+	row := func(i int) string { return string(rune('a' + i)) }
+	l := List{Cursor: 4}
+	if got := l.Lines(6, 3, row); strings.Join(got, "|") != "  c|  d|> e" || l.Offset != 2 {
+		t.Errorf("Lines = %q, offset %d", got, l.Offset)
+	}
+	l = List{Cursor: 9}
+	if got := l.Lines(2, 3, row); strings.Join(got, "|") != "  a|> b|" || l.Cursor != 1 || l.Offset != 0 {
+		t.Errorf("Lines = %q, cursor %d, offset %d", got, l.Cursor, l.Offset)
+	}
+	if got := Truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("Truncate = %q", got)
+	}
+}
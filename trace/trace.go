@@ -0,0 +1,425 @@
+// Package trace builds a traceability matrix across the market, product, and
+// technical requirements documents of a product.
+//
+// The PRD's functional requirements are the spine of the matrix. Each is
+// traced upstream to the MRD market requirements it was derived from, using
+// the PRD's provenance links, and downstream to its user stories, the TRD
+// components that implement it, and the TRD test cases that verify it,
+// either directly or through a component under test. Items that do not
+// connect to any requirement are reported as orphans.
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// Item levels, from upstream to downstream.
+const (
+	LevelMarket      = "market"
+	LevelRequirement = "requirement"
+	LevelStory       = "story"
+	LevelComponent   = "component"
+	LevelTest        = "test"
+)
+
+// Gaps reported for a requirement missing a link at one level.
+const (
+	GapMarket    = "no market requirement"
+	GapStory     = "no user stories"
+	GapComponent = "no components"
+	GapTest      = "no tests"
+)
+
+const gapSeparator = "; "
+
+// Ref identifies an item in one of the traced documents.
+type Ref struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// Row traces one PRD functional requirement.
+type Row struct {
+	Requirement        Ref      `json:"requirement"`
+	Priority           string   `json:"priority,omitempty"`
+	MarketRequirements []Ref    `json:"marketRequirements,omitempty"`
+	UserStories        []Ref    `json:"userStories,omitempty"`
+	Components         []Ref    `json:"components,omitempty"`
+	Tests              []Ref    `json:"tests,omitempty"`
+	Gaps               []string `json:"gaps,omitempty"`
+}
+
+// Orphan is an item that does not trace to any PRD requirement.
+type Orphan struct {
+	Level  string `json:"level"`
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Matrix is a traceability matrix for one PRD and, optionally, its MRD and
+// TRD.
+type Matrix struct {
+	PRD      string   `json:"prd"`
+	MRD      string   `json:"mrd,omitempty"`
+	TRD      string   `json:"trd,omitempty"`
+	Rows     []Row    `json:"rows"`
+	Orphans  []Orphan `json:"orphans,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+
+	market bool // market requirements are traced
+}
+
+// Build traces the functional requirements of p. The MRD m and TRD t may be
+// nil; without them the matrix omits their levels, except that market
+// requirement IDs recorded in p's provenance are still listed.
+func Build(p *prd.Document, m *mrd.Document, t *trd.Document) *Matrix {
+	mx := &Matrix{PRD: p.Metadata.ID}
+	warnf := func(format string, args ...any) {
+		mx.Warnings = append(mx.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	rowIndex := map[string]int{}
+	for _, fr := range p.Requirements.Functional {
+		rowIndex[fr.ID] = len(mx.Rows)
+		mx.Rows = append(mx.Rows, Row{Requirement: Ref{ID: fr.ID, Title: fr.Title}, Priority: string(fr.Priority)})
+	}
+	nfrs := map[string]bool{}
+	for _, nfr := range p.Requirements.NonFunctional {
+		nfrs[nfr.ID] = true
+	}
+
+	// Upstream: market requirements, via provenance.
+	mrTitles := map[string]string{}
+	if m != nil {
+		mx.MRD = m.Metadata.ID
+		for _, mr := range m.MarketRequirements {
+			mrTitles[mr.ID] = mr.Title
+		}
+	}
+	mx.market = m != nil
+	accounted := map[string]bool{}
+	if pv := p.Provenance; pv != nil && pv.Source.Type == "mrd" {
+		mx.market = true
+		if m != nil && pv.Source.ID != "" && pv.Source.ID != m.Metadata.ID {
+			warnf("PRD %s was derived from MRD %s, not %s", p.Metadata.ID, pv.Source.ID, m.Metadata.ID)
+		}
+		for _, l := range pv.Links {
+			i, ok := rowIndex[l.ItemID]
+			for _, id := range l.SourceIDs {
+				if m != nil && ok {
+					if _, known := mrTitles[id]; !known {
+						warnf("requirement %s traces to undefined market requirement: %s", l.ItemID, id)
+					}
+				}
+				if ok {
+					mx.Rows[i].MarketRequirements = appendRef(mx.Rows[i].MarketRequirements, Ref{ID: id, Title: mrTitles[id]})
+				}
+				if ok || l.Kind == prd.ProvenanceOutOfScope {
+					accounted[id] = true
+				}
+			}
+		}
+	}
+	if m != nil {
+		for _, mr := range m.MarketRequirements {
+			if !accounted[mr.ID] {
+				mx.addOrphan(LevelMarket, mr.ID, mr.Title, "not derived into any PRD requirement or out-of-scope entry")
+			}
+		}
+	}
+
+	// User stories.
+	stories := map[string]string{}
+	for _, s := range p.UserStories {
+		stories[s.ID] = s.Title
+	}
+	linkedStories := map[string]bool{}
+	for _, fr := range p.Requirements.Functional {
+		row := &mx.Rows[rowIndex[fr.ID]]
+		for _, id := range fr.UserStoryIDs {
+			title, ok := stories[id]
+			if !ok {
+				warnf("requirement %s references undefined user story: %s", fr.ID, id)
+				continue
+			}
+			row.UserStories = appendRef(row.UserStories, Ref{ID: id, Title: title})
+			linkedStories[id] = true
+		}
+	}
+	for _, s := range p.UserStories {
+		if !linkedStories[s.ID] {
+			mx.addOrphan(LevelStory, s.ID, s.Title, "not linked from any functional requirement")
+		}
+	}
+
+	// Downstream: components and test cases.
+	if t != nil {
+		mx.TRD = t.Metadata.ID
+		known := func(owner, id string) bool {
+			if _, ok := rowIndex[id]; ok || nfrs[id] {
+				return true
+			}
+			warnf("%s references undefined PRD requirement: %s", owner, id)
+			return false
+		}
+
+		compReqs := map[string][]string{}
+		for _, c := range t.Architecture.Components {
+			ref := Ref{ID: c.ID, Title: c.Name}
+			traced := false
+			for _, id := range c.Requirements {
+				if !known("component "+c.ID, id) {
+					continue
+				}
+				traced = true
+				compReqs[c.ID] = append(compReqs[c.ID], id)
+				if i, ok := rowIndex[id]; ok {
+					mx.Rows[i].Components = appendRef(mx.Rows[i].Components, ref)
+				}
+			}
+			if !traced {
+				mx.addOrphan(LevelComponent, c.ID, c.Name, "implements no PRD requirement")
+			}
+		}
+
+		if t.Testing != nil {
+			for _, tc := range t.Testing.TestCases {
+				ref := Ref{ID: tc.ID, Title: tc.Name}
+				var reqs []string
+				for _, id := range tc.Requirements {
+					if known("test "+tc.ID, id) {
+						reqs = append(reqs, id)
+					}
+				}
+				for _, cid := range tc.Components {
+					cr, ok := compReqs[cid]
+					if !ok && !hasComponent(t, cid) {
+						warnf("test %s references undefined component: %s", tc.ID, cid)
+					}
+					reqs = append(reqs, cr...)
+				}
+				for _, id := range reqs {
+					if i, ok := rowIndex[id]; ok {
+						mx.Rows[i].Tests = appendRef(mx.Rows[i].Tests, ref)
+					}
+				}
+				if len(reqs) == 0 {
+					mx.addOrphan(LevelTest, tc.ID, tc.Name, "verifies no PRD requirement")
+				}
+			}
+		}
+	}
+
+	for i := range mx.Rows {
+		row := &mx.Rows[i]
+		if mx.market && len(row.MarketRequirements) == 0 {
+			row.Gaps = append(row.Gaps, GapMarket)
+		}
+		if len(row.UserStories) == 0 {
+			row.Gaps = append(row.Gaps, GapStory)
+		}
+		if t != nil && len(row.Components) == 0 {
+			row.Gaps = append(row.Gaps, GapComponent)
+		}
+		if t != nil && len(row.Tests) == 0 {
+			row.Gaps = append(row.Gaps, GapTest)
+		}
+	}
+	return mx
+}
+
+func (mx *Matrix) addOrphan(level, id, title, reason string) {
+	mx.Orphans = append(mx.Orphans, Orphan{Level: level, ID: id, Title: title, Reason: reason})
+}
+
+// Gaps returns the number of requirements missing a link at one or more
+// levels.
+func (mx *Matrix) Gaps() int {
+	n := 0
+	for _, r := range mx.Rows {
+		if len(r.Gaps) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// ToMarkdown renders the matrix with a coverage summary, followed by the
+// orphaned items and warnings.
+func (mx *Matrix) ToMarkdown() string {
+	var sb strings.Builder
+	if len(mx.Rows) == 0 {
+		sb.WriteString("*The PRD has no functional requirements to trace.*\n\n")
+	} else {
+		sb.WriteString(mx.coverage())
+
+		sb.WriteString("## Matrix\n\n")
+		var header []string
+		if mx.market {
+			header = append(header, "Market Requirement")
+		}
+		header = append(header, "Requirement", "Priority", "User Stories")
+		if mx.TRD != "" {
+			header = append(header, "Components", "Tests")
+		}
+		header = append(header, "Gaps")
+		sb.WriteString("| " + strings.Join(header, " | ") + " |\n")
+		sb.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+		for _, r := range mx.Rows {
+			var cells []string
+			if mx.market {
+				cells = append(cells, refLabels(r.MarketRequirements))
+			}
+			cells = append(cells, refLabel(r.Requirement), r.Priority, joinIDs(r.UserStories, ", "))
+			if mx.TRD != "" {
+				cells = append(cells, joinIDs(r.Components, ", "), joinIDs(r.Tests, ", "))
+			}
+			cells = append(cells, strings.Join(r.Gaps, gapSeparator))
+			for i, c := range cells {
+				cells[i] = escape(c)
+			}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(mx.Orphans) > 0 {
+		sb.WriteString("## Orphaned Items\n\n")
+		sb.WriteString("| Level | Item | Reason |\n")
+		sb.WriteString("|-------|------|--------|\n")
+		for _, o := range mx.Orphans {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", o.Level, escape(refLabel(Ref{ID: o.ID, Title: o.Title})), o.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(mx.Warnings) > 0 {
+		sb.WriteString("**Warnings:**\n\n")
+		for _, w := range mx.Warnings {
+			sb.WriteString("- " + w + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// coverage summarizes how many requirements are linked at each level.
+func (mx *Matrix) coverage() string {
+	var market, stories, components, tests int
+	for _, r := range mx.Rows {
+		if len(r.MarketRequirements) > 0 {
+			market++
+		}
+		if len(r.UserStories) > 0 {
+			stories++
+		}
+		if len(r.Components) > 0 {
+			components++
+		}
+		if len(r.Tests) > 0 {
+			tests++
+		}
+	}
+	total := len(mx.Rows)
+	var sb strings.Builder
+	sb.WriteString("| Link | Requirements Covered |\n")
+	sb.WriteString("|------|---------------------:|\n")
+	line := func(label string, n int) {
+		sb.WriteString(fmt.Sprintf("| %s | %d of %d (%.0f%%) |\n", label, n, total, 100*float64(n)/float64(total)))
+	}
+	if mx.market {
+		line("Market requirement", market)
+	}
+	line("User story", stories)
+	if mx.TRD != "" {
+		line("Component", components)
+		line("Test", tests)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// WriteCSV writes one line per requirement followed by one line per orphaned
+// item. Linked IDs are separated by semicolons.
+func (mx *Matrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"level", "market_requirements", "requirement_id", "requirement_title", "priority", "user_stories", "components", "tests", "gaps"}}
+	for _, r := range mx.Rows {
+		records = append(records, []string{
+			LevelRequirement, joinIDs(r.MarketRequirements, ";"), r.Requirement.ID, r.Requirement.Title, r.Priority,
+			joinIDs(r.UserStories, ";"), joinIDs(r.Components, ";"), joinIDs(r.Tests, ";"), strings.Join(r.Gaps, gapSeparator),
+		})
+	}
+	for _, o := range mx.Orphans {
+		record := []string{o.Level, "", "", "", "", "", "", "", "orphan: " + o.Reason}
+		switch o.Level {
+		case LevelMarket:
+			record[1] = o.ID
+		case LevelStory:
+			record[5] = o.ID
+		case LevelComponent:
+			record[6] = o.ID
+		case LevelTest:
+			record[7] = o.ID
+		}
+		records = append(records, record)
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("writing traceability CSV: %w", err)
+	}
+	return nil
+}
+
+func hasComponent(t *trd.Document, id string) bool {
+	for _, c := range t.Architecture.Components {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// appendRef appends ref unless an item with the same ID is already listed.
+func appendRef(refs []Ref, ref Ref) []Ref {
+	for _, r := range refs {
+		if r.ID == ref.ID {
+			return refs
+		}
+	}
+	return append(refs, ref)
+}
+
+func refLabel(r Ref) string {
+	if r.Title == "" {
+		return r.ID
+	}
+	return r.ID + ": " + r.Title
+}
+
+func refLabels(refs []Ref) string {
+	labels := make([]string, len(refs))
+	for i, r := range refs {
+		labels[i] = refLabel(r)
+	}
+	return strings.Join(labels, ", ")
+}
+
+func joinIDs(refs []Ref, sep string) string {
+	ids := make([]string, len(refs))
+	for i, r := range refs {
+		ids[i] = r.ID
+	}
+	return strings.Join(ids, sep)
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
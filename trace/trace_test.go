@@ -0,0 +1,159 @@
+package trace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+func testDocuments() (*prd.Document, *mrd.Document, *trd.Document) {
+	m := &mrd.Document{
+		Metadata: mrd.Metadata{ID: "MRD-1"},
+		MarketRequirements: []mrd.MarketRequirement{
+			{ID: "MR-1", Title: "Saved cards"},
+			{ID: "MR-2", Title: "Crypto"},
+			{ID: "MR-3", Title: "Invoicing"},
+		},
+	}
+	p := &prd.Document{
+		Metadata: prd.Metadata{ID: "PRD-1"},
+		UserStories: []prd.UserStory{
+			{ID: "US-1", Title: "Save a card"},
+			{ID: "US-2", Title: "Stray story"},
+		},
+		Requirements: prd.Requirements{
+			Functional: []prd.FunctionalRequirement{
+				{ID: "FR-1", Title: "Card vault", Priority: prd.MoSCoWMust, UserStoryIDs: []string{"US-1"}},
+				{ID: "FR-2", Title: "Refunds", Priority: prd.MoSCoWShould, UserStoryIDs: []string{"US-9"}},
+			},
+			NonFunctional: []prd.NonFunctionalRequirement{{ID: "NFR-1", Title: "Latency"}},
+		},
+		Provenance: &prd.Provenance{
+			Source: prd.SourceRef{Type: "mrd", ID: "MRD-1"},
+			Links: []prd.ProvenanceLink{
+				{ItemID: "FR-1", Kind: prd.ProvenanceRequirement, SourceIDs: []string{"MR-1"}},
+				{ItemID: "outOfScope[0]", Kind: prd.ProvenanceOutOfScope, SourceIDs: []string{"MR-2"}},
+			},
+		},
+	}
+	t := &trd.Document{
+		Metadata: trd.Metadata{ID: "TRD-1"},
+		Architecture: trd.Architecture{Components: []trd.Component{
+			{ID: "vault", Name: "Vault Service", Requirements: []string{"FR-1"}},
+			{ID: "cache", Name: "Cache", Requirements: []string{"NFR-1"}},
+			{ID: "legacy", Name: "Legacy Adapter"},
+			{ID: "ghost", Name: "Ghost", Requirements: []string{"FR-404"}},
+		}},
+		Testing: &trd.Testing{TestCases: []trd.TestCase{
+			{ID: "T-1", Name: "Vault round trip", Components: []string{"vault"}},
+			{ID: "T-2", Name: "Refund flow", Requirements: []string{"FR-2"}},
+			{ID: "T-3", Name: "Smoke", Components: []string{"nowhere"}},
+		}},
+	}
+	return p, m, t
+}
+
+func TestBuild(t *testing.T) {
+	p, m, d := testDocuments()
+	mx := Build(p, m, d)
+
+	if mx.PRD != "PRD-1" || mx.MRD != "MRD-1" || mx.TRD != "TRD-1" || len(mx.Rows) != 2 {
+		t.Fatalf("matrix = %+v", mx)
+	}
+	fr1, fr2 := mx.Rows[0], mx.Rows[1]
+	if joinIDs(fr1.MarketRequirements, ",") != "MR-1" || fr1.MarketRequirements[0].Title != "Saved cards" {
+		t.Errorf("FR-1 market = %+v", fr1.MarketRequirements)
+	}
+	if joinIDs(fr1.UserStories, ",") != "US-1" || joinIDs(fr1.Components, ",") != "vault" || joinIDs(fr1.Tests, ",") != "T-1" {
+		t.Errorf("FR-1 = %+v", fr1)
+	}
+	if len(fr1.Gaps) != 0 {
+		t.Errorf("FR-1 gaps = %v", fr1.Gaps)
+	}
+	if joinIDs(fr2.Tests, ",") != "T-2" || strings.Join(fr2.Gaps, gapSeparator) != "no market requirement; no user stories; no components" {
+		t.Errorf("FR-2 = %+v", fr2)
+	}
+	if mx.Gaps() != 1 {
+		t.Errorf("Gaps() = %d", mx.Gaps())
+	}
+
+	var orphans []string
+	for _, o := range mx.Orphans {
+		orphans = append(orphans, o.Level+":"+o.ID)
+	}
+	if got := strings.Join(orphans, ","); got != "market:MR-3,story:US-2,component:legacy,component:ghost,test:T-3" {
+		t.Errorf("orphans = %s", got)
+	}
+
+	wantWarnings := []string{
+		"requirement FR-2 references undefined user story: US-9",
+		"component ghost references undefined PRD requirement: FR-404",
+		"test T-3 references undefined component: nowhere",
+	}
+	if strings.Join(mx.Warnings, "\n") != strings.Join(wantWarnings, "\n") {
+		t.Errorf("warnings = %q", mx.Warnings)
+	}
+}
+
+func TestBuildPRDOnly(t *testing.T) {
+	p, _, _ := testDocuments()
+	p.Provenance = nil
+	mx := Build(p, nil, nil)
+	if strings.Join(mx.Rows[0].Gaps, gapSeparator) != "" || strings.Join(mx.Rows[1].Gaps, gapSeparator) != GapStory {
+		t.Errorf("gaps = %v, %v", mx.Rows[0].Gaps, mx.Rows[1].Gaps)
+	}
+	md := mx.ToMarkdown()
+	for _, unwanted := range []string{"Market Requirement", "Components"} {
+		if strings.Contains(md, unwanted) {
+			t.Errorf("markdown should omit %q without an MRD or TRD:\n%s", unwanted, md)
+		}
+	}
+}
+
+func TestBuildMismatchedMRD(t *testing.T) {
+	p, m, _ := testDocuments()
+	m.Metadata.ID = "MRD-2"
+	mx := Build(p, m, nil)
+	if len(mx.Warnings) == 0 || mx.Warnings[0] != "PRD PRD-1 was derived from MRD MRD-1, not MRD-2" {
+		t.Errorf("warnings = %q", mx.Warnings)
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	p, m, d := testDocuments()
+	md := Build(p, m, d).ToMarkdown()
+	for _, want := range []string{
+		"| Market requirement | 1 of 2 (50%) |",
+		"| Market Requirement | Requirement | Priority | User Stories | Components | Tests | Gaps |",
+		"| MR-1: Saved cards | FR-1: Card vault | must | US-1 | vault | T-1 |  |",
+		"## Orphaned Items",
+		"| component | legacy: Legacy Adapter | implements no PRD requirement |",
+		"**Warnings:**",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	p, m, d := testDocuments()
+	var buf bytes.Buffer
+	if err := Build(p, m, d).WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("CSV lines = %d:\n%s", len(lines), buf.String())
+	}
+	if lines[1] != "requirement,MR-1,FR-1,Card vault,must,US-1,vault,T-1," {
+		t.Errorf("FR-1 line = %q", lines[1])
+	}
+	if lines[3] != "market,MR-3,,,,,,,orphan: not derived into any PRD requirement or out-of-scope entry" {
+		t.Errorf("orphan line = %q", lines[3])
+	}
+}
@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SARIF 2.1.0 identifiers.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Tool identifies the program that produced a report in SARIF output.
+type Tool struct {
+	Name           string
+	Version        string
+	InformationURI string
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIF encodes the report as a SARIF 2.1.0 log with a single run by tool.
+// Each issue becomes a result located in its file by line and column, when
+// known, and by its JSON pointer as a logical location.
+func (r *Report) SARIF(tool Tool) ([]byte, error) {
+	ruleIndex := map[string]int{}
+	var rules []sarifRule
+	for _, f := range r.Files {
+		for _, is := range f.Issues {
+			if _, ok := ruleIndex[is.RuleID]; !ok {
+				ruleIndex[is.RuleID] = -1
+				rules = append(rules, sarifRule{ID: is.RuleID, ShortDescription: sarifMessage{Text: ruleDescription(is.RuleID)}})
+			}
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	for i, rule := range rules {
+		ruleIndex[rule.ID] = i
+	}
+	if rules == nil {
+		rules = []sarifRule{}
+	}
+
+	results := []sarifResult{}
+	for _, f := range r.Files {
+		for _, is := range f.Issues {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Path)},
+			}}
+			if is.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: is.Line, StartColumn: is.Column}
+			}
+			if is.Pointer != "" {
+				loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: is.Pointer, Kind: "member"}}
+			}
+			results = append(results, sarifResult{
+				RuleID:    is.RuleID,
+				RuleIndex: ruleIndex[is.RuleID],
+				Level:     string(is.Severity),
+				Message:   sarifMessage{Text: is.Message},
+				Locations: []sarifLocation{loc},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Version: SARIFVersion,
+		Schema:  SARIFSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           tool.Name,
+				Version:        tool.Version,
+				InformationURI: tool.InformationURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling SARIF: %w", err)
+	}
+	return data, nil
+}
+
+func ruleDescription(id string) string {
+	if d, ok := ruleDescriptions[id]; ok {
+		return d
+	}
+	if docType, path, ok := strings.Cut(id, "/"); ok {
+		return fmt.Sprintf("%s validation of %s.", strings.ToUpper(docType), path)
+	}
+	return id
+}
@@ -0,0 +1,153 @@
+// Package validation collects the results of the validate commands as
+// structured issues, so CI systems and code review tools can read them as
+// JSON or SARIF instead of parsing the plaintext report.
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Severity is how serious an issue is. Errors fail validation; warnings do
+// not.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs shared by the document types. Checks of the OKR, V2MOM, and
+// roadmap validators are identified by the path they check instead; see
+// PathRule.
+const (
+	RuleRequiredField   = "required-field"
+	RuleReviews         = "review-approvals"
+	RuleLifecycle       = "lifecycle"
+	RuleSchema          = "schema"
+	RuleDocument        = "document"
+	RuleLink            = "link"
+	RuleAsset           = "asset"
+	RuleDuplicateID     = "duplicate-id"
+	RuleConflictingID   = "conflicting-id"
+	RuleBrokenReference = "broken-reference"
+)
+
+// ruleDescriptions describe the shared rules in SARIF output.
+var ruleDescriptions = map[string]string{
+	RuleRequiredField:   "A required field is missing or empty.",
+	RuleReviews:         "The reviews recorded do not satisfy the document status.",
+	RuleLifecycle:       "The document status is inconsistent with its lifecycle.",
+	RuleSchema:          "The document does not conform to its JSON Schema.",
+	RuleDocument:        "The document could not be read or parsed.",
+	RuleLink:            "An appendix reference, data model relationship, or in-document link does not resolve.",
+	RuleAsset:           "A referenced local file, such as a wireframe or diagram, does not exist.",
+	RuleDuplicateID:     "Two items in the same collection and scope have the same ID.",
+	RuleConflictingID:   "Items in different sections have the same ID, so references to it are ambiguous.",
+	RuleBrokenReference: "A reference field names an ID that is not defined in the document or its workspace.",
+}
+
+// DescribeRule sets the SARIF description of a rule defined outside this
+// package. It is meant to be called from an init function.
+func DescribeRule(id, description string) {
+	ruleDescriptions[id] = description
+}
+
+// Issue is one validation finding in a document.
+type Issue struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+
+	// Pointer is the JSON pointer to the value the issue is about, e.g.
+	// "/metadata/id". It is empty for the document root.
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+
+	// Line and Column locate the value in the file. Both are 1-based and
+	// zero when unknown.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// File is the validation result for one file.
+type File struct {
+	Path   string  `json:"path"`
+	Type   string  `json:"type,omitempty"`
+	Valid  bool    `json:"valid"`
+	Issues []Issue `json:"issues"`
+}
+
+// NewFile returns the result for a file with the given issues. The file is
+// valid if none of them is an error.
+func NewFile(path, docType string, issues []Issue) File {
+	if issues == nil {
+		issues = []Issue{}
+	}
+	f := File{Path: path, Type: docType, Valid: true, Issues: issues}
+	for _, is := range issues {
+		if is.Severity == SeverityError {
+			f.Valid = false
+		}
+	}
+	return f
+}
+
+// Report is the validation result for a set of files.
+type Report struct {
+	Valid bool   `json:"valid"`
+	Files []File `json:"files"`
+}
+
+// NewReport returns a report for the given files.
+func NewReport(files []File) *Report {
+	if files == nil {
+		files = []File{}
+	}
+	r := &Report{Valid: true, Files: files}
+	for _, f := range files {
+		if !f.Valid {
+			r.Valid = false
+		}
+	}
+	return r
+}
+
+// JSON encodes the report as indented JSON.
+func (r *Report) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling report: %w", err)
+	}
+	return data, nil
+}
+
+var indexPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// PathPointer converts a dotted validator path such as
+// "methods[0].measures" to the JSON pointer "/methods/0/measures".
+func PathPointer(path string) string {
+	if path == "" {
+		return ""
+	}
+	path = indexPattern.ReplaceAllString(path, ".$1")
+	var sb strings.Builder
+	for _, tok := range strings.Split(path, ".") {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// PathRule returns the rule ID for a check of the given document type at a
+// dotted validator path. Array indexes are dropped, so the same check on
+// every element shares one rule: "okr" and "objectives[2].keyResults" give
+// "okr/objectives.keyResults". An empty path gives "okr/document".
+func PathRule(docType, path string) string {
+	path = indexPattern.ReplaceAllString(path, "")
+	if path == "" {
+		path = "document"
+	}
+	return docType + "/" + path
+}
@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPathPointer(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"objectives":                "/objectives",
+		"methods[0].measures":       "/methods/0/measures",
+		"phases[1].dependencies[2]": "/phases/1/dependencies/2",
+		"a/b.c~d":                   "/a~1b/c~0d",
+	}
+	for path, want := range tests {
+		if got := PathPointer(path); got != want {
+			t.Errorf("PathPointer(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestPathRule(t *testing.T) {
+	if got := PathRule("okr", "objectives[2].keyResults[0].target"); got != "okr/objectives.keyResults.target" {
+		t.Errorf("PathRule = %q", got)
+	}
+	if got := PathRule("v2mom", ""); got != "v2mom/document" {
+		t.Errorf("PathRule empty = %q", got)
+	}
+}
+
+func TestReport(t *testing.T) {
+	warn := NewFile("a.okr.json", "okr", []Issue{{RuleID: "okr/metadata.name", Severity: SeverityWarning, Pointer: "/metadata/name", Message: "name is recommended"}})
+	if !warn.Valid {
+		t.Error("file with only warnings should be valid")
+	}
+	clean := NewFile("b.prd.json", "prd", nil)
+	if clean.Issues == nil {
+		t.Error("issues should encode as an empty array")
+	}
+	fail := NewFile("c.prd.json", "prd", []Issue{{RuleID: RuleRequiredField, Severity: SeverityError, Pointer: "/metadata/id", Message: "metadata.id is required", Line: 2, Column: 3}})
+	if fail.Valid {
+		t.Error("file with an error should be invalid")
+	}
+
+	if r := NewReport([]File{warn, clean}); !r.Valid {
+		t.Error("report without errors should be valid")
+	}
+	r := NewReport([]File{warn, clean, fail})
+	if r.Valid {
+		t.Error("report with an error should be invalid")
+	}
+
+	data, err := r.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Files) != 3 || decoded.Files[2].Issues[0].Pointer != "/metadata/id" {
+		t.Errorf("decoded report = %+v", decoded)
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	r := NewReport([]File{
+		NewFile("docs/a.okr.json", "okr", []Issue{{RuleID: "okr/metadata.name", Severity: SeverityWarning, Pointer: "/metadata/name", Message: "name is recommended"}}),
+		NewFile("docs/c.prd.json", "prd", []Issue{
+			{RuleID: RuleRequiredField, Severity: SeverityError, Pointer: "/metadata/id", Message: "metadata.id is required", Line: 2, Column: 3},
+			{RuleID: RuleDocument, Severity: SeverityError, Message: "parsing JSON"},
+		}),
+	})
+	data, err := r.SARIF(Tool{Name: "splan", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != SARIFVersion || len(log.Runs) != 1 {
+		t.Fatalf("log = %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "splan" || run.Tool.Driver.Version != "1.0.0" {
+		t.Errorf("driver = %+v", run.Tool.Driver)
+	}
+	var ids []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+		if rule.ShortDescription.Text == "" {
+			t.Errorf("rule %s has no description", rule.ID)
+		}
+	}
+	if len(ids) != 3 || ids[0] != RuleDocument || ids[1] != "okr/metadata.name" || ids[2] != RuleRequiredField {
+		t.Errorf("rules = %v", ids)
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("results = %d, want 3", len(run.Results))
+	}
+	for _, res := range run.Results {
+		if run.Tool.Driver.Rules[res.RuleIndex].ID != res.RuleID {
+			t.Errorf("result %s has rule index %d", res.RuleID, res.RuleIndex)
+		}
+	}
+	first := run.Results[0]
+	if first.Level != "warning" || first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "docs/a.okr.json" {
+		t.Errorf("first result = %+v", first)
+	}
+	located := run.Results[1].Locations[0]
+	if located.PhysicalLocation.Region == nil || located.PhysicalLocation.Region.StartLine != 2 ||
+		located.LogicalLocations[0].FullyQualifiedName != "/metadata/id" {
+		t.Errorf("located result = %+v", located)
+	}
+	if run.Results[2].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("result without a line should have no region")
+	}
+}
@@ -0,0 +1,75 @@
+package wizard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// MRD asks for an MRD's title, authors, market opportunity and offering,
+// market size, target segments, competitors, first market requirement, and
+// positioning. title is the default title.
+func MRD(p *Prompter, title string) *mrd.Document {
+	now := time.Now()
+	p.Section("Document")
+	doc := &mrd.Document{Metadata: mrd.Metadata{
+		Title:     p.Ask("Title", orTODO(title, "market or offering name")),
+		ID:        p.Ask("ID", prd.GenerateIDWithPrefix("MRD")),
+		Version:   "1.0.0",
+		Status:    mrd.StatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}}
+	doc.Metadata.Authors = p.askAuthors()
+
+	p.Section("Executive summary")
+	doc.ExecutiveSummary.MarketOpportunity = orTODO(p.Ask("Market opportunity", ""), "the opportunity in the market")
+	doc.ExecutiveSummary.ProposedOffering = orTODO(p.Ask("Proposed offering", ""), "what we will offer")
+	doc.ExecutiveSummary.KeyFindings = p.AskList("Key findings")
+
+	p.Section("Market size")
+	doc.MarketOverview.TAM.Value = orTODO(p.Ask("Total addressable market, e.g. $9.5B", ""), "total addressable market")
+	doc.MarketOverview.SAM.Value = p.Ask("Serviceable addressable market", "")
+	doc.MarketOverview.SOM.Value = p.Ask("Serviceable obtainable market", "")
+	doc.MarketOverview.GrowthRate = p.Ask("Growth rate, e.g. 12% CAGR", "")
+
+	p.Section("Primary segments")
+	for i := 1; i == 1 || p.Confirm("Add another segment?", false); i++ {
+		doc.TargetMarket.PrimarySegments = append(doc.TargetMarket.PrimarySegments, mrd.MarketSegment{
+			ID:          fmt.Sprintf("seg-%d", i),
+			Name:        orTODO(p.Ask(fmt.Sprintf("Segment %d name", i), ""), "segment name"),
+			Description: p.Ask("  Description", ""),
+			Needs:       p.AskList("  Needs"),
+		})
+	}
+
+	p.Section("Competitors")
+	for i := 1; i == 1 || p.Confirm("Add another competitor?", false); i++ {
+		doc.CompetitiveLandscape.Competitors = append(doc.CompetitiveLandscape.Competitors, mrd.Competitor{
+			ID:         fmt.Sprintf("comp-%d", i),
+			Name:       orTODO(p.Ask(fmt.Sprintf("Competitor %d name", i), ""), "competitor name"),
+			Strengths:  p.AskList("  Strengths"),
+			Weaknesses: p.AskList("  Weaknesses"),
+		})
+	}
+
+	p.Section("First market requirement")
+	doc.MarketRequirements = []mrd.MarketRequirement{{
+		ID:          "mr-1",
+		Title:       orTODO(p.Ask("Title", ""), "what the market needs"),
+		Description: p.Ask("Description", ""),
+		Priority: mrd.Priority(p.AskValid("Priority (must, should, could, wont)", string(mrd.PriorityMust), oneOf([]string{
+			string(mrd.PriorityMust), string(mrd.PriorityShould), string(mrd.PriorityCould), string(mrd.PriorityWont),
+		}))),
+	}}
+
+	p.Section("Positioning")
+	doc.Positioning.Statement = orTODO(p.Ask("Positioning statement", ""), "for whom, what, and why it is different")
+	doc.Positioning.TargetAudience = p.Ask("Target audience", "")
+	doc.Positioning.KeyBenefits = p.AskList("Key benefits")
+	doc.Positioning.Differentiators = []string{}
+	doc.SuccessMetrics = []mrd.SuccessMetric{}
+	return doc
+}
@@ -0,0 +1,133 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// PRD asks for a PRD's title, authors, problem and solution, personas,
+// first user story, first objective and key result, and first roadmap
+// phase. title is the default title.
+func PRD(p *Prompter, title string) *prd.Document {
+	p.Section("Document")
+	title = p.AskValid("Title", orTODO(title, "product name"), minLength(5))
+	id := p.Ask("ID", prd.GenerateID())
+	doc := prd.New(id, title, p.askAuthors()...)
+
+	p.Section("Executive summary")
+	doc.ExecutiveSummary.ProblemStatement = orTODO(p.Ask("Problem statement", ""), "the problem this product solves")
+	doc.ExecutiveSummary.ProposedSolution = orTODO(p.Ask("Proposed solution", ""), "how the product solves it")
+	doc.ExecutiveSummary.ExpectedOutcomes = p.AskList("Expected outcomes")
+
+	p.Section("Personas")
+	for i := 1; i == 1 || p.Confirm("Add another persona?", false); i++ {
+		doc.Personas = append(doc.Personas, prd.Persona{
+			ID:         fmt.Sprintf("persona-%d", i),
+			Name:       orTODO(p.Ask(fmt.Sprintf("Persona %d name", i), ""), "persona name"),
+			Role:       p.Ask("  Role", ""),
+			Goals:      p.AskList("  Goals"),
+			PainPoints: p.AskList("  Pain points"),
+			IsPrimary:  i == 1,
+		})
+	}
+
+	p.Section("First user story")
+	story := prd.UserStory{
+		ID:        "us-1",
+		PersonaID: doc.Personas[0].ID,
+		AsA:       p.Ask("As a", doc.Personas[0].Role),
+		IWant:     orTODO(p.Ask("I want", ""), "what the user wants to do"),
+		SoThat:    orTODO(p.Ask("So that", ""), "why it matters to them"),
+		Priority:  prd.PriorityHigh,
+		PhaseID:   "phase-1",
+
+		AcceptanceCriteria: []prd.AcceptanceCriterion{},
+	}
+	story.AsA = orTODO(story.AsA, "user role")
+	story.Title = p.Ask("Title", story.IWant)
+	for i, c := range p.AskList("Acceptance criteria") {
+		story.AcceptanceCriteria = append(story.AcceptanceCriteria, prd.AcceptanceCriterion{ID: fmt.Sprintf("ac-%d", i+1), Description: c})
+	}
+	doc.UserStories = []prd.UserStory{story}
+
+	p.Section("First objective")
+	objective := prd.Objective{
+		ID:    "obj-1",
+		Title: orTODO(p.Ask("Objective", ""), "what the product should achieve"),
+	}
+	objective.KeyResults = []prd.KeyResult{{
+		ID:       "kr-1",
+		Title:    orTODO(p.Ask("Key result", ""), "how achieving the objective is measured"),
+		Metric:   p.Ask("  Metric", ""),
+		Baseline: p.Ask("  Baseline", ""),
+		Target:   p.Ask("  Target", ""),
+	}}
+	doc.Objectives.OKRs = []prd.OKR{{Objective: objective, KeyResults: []prd.KeyResult{}}}
+
+	p.Section("First roadmap phase")
+	phase := prd.Phase{
+		ID:     "phase-1",
+		Name:   p.Ask("Name", "MVP"),
+		Type:   prd.PhaseType(p.AskValid("Type (generic, quarter, month, sprint, milestone)", string(prd.PhaseTypeMilestone), oneOf(phaseTypes))),
+		Status: prd.PhaseStatusPlanned,
+
+		Deliverables: []prd.Deliverable{},
+	}
+	phase.StartDate = askDate(p, "Start date (YYYY-MM-DD)")
+	phase.EndDate = askDate(p, "End date (YYYY-MM-DD)")
+	phase.Goals = p.AskList("Goals")
+	for i, d := range p.AskList("Deliverables") {
+		phase.Deliverables = append(phase.Deliverables, prd.Deliverable{
+			ID:    fmt.Sprintf("del-%d", i+1),
+			Title: d,
+			Type:  prd.DeliverableFeature,
+		})
+	}
+	phase.SuccessCriteria = p.AskList("Success criteria")
+	doc.Roadmap.Phases = []prd.Phase{phase}
+	return doc
+}
+
+var phaseTypes = []string{
+	string(prd.PhaseTypeGeneric), string(prd.PhaseTypeQuarter), string(prd.PhaseTypeMonth),
+	string(prd.PhaseTypeSprint), string(prd.PhaseTypeMilestone),
+}
+
+func minLength(n int) func(string) error {
+	return func(s string) error {
+		if len(s) < n {
+			return fmt.Errorf("must be at least %d characters", n)
+		}
+		return nil
+	}
+}
+
+func oneOf(values []string) func(string) error {
+	return func(s string) error {
+		for _, v := range values {
+			if s == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of: %s", strings.Join(values, ", "))
+	}
+}
+
+// askDate asks for an optional date.
+func askDate(p *Prompter, question string) *time.Time {
+	s := p.AskValid(question, "", func(s string) error {
+		_, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return fmt.Errorf("expected a date such as 2026-01-31")
+		}
+		return nil
+	})
+	if s == "" {
+		return nil
+	}
+	t, _ := time.Parse(time.DateOnly, s)
+	return &t
+}
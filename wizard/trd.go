@@ -0,0 +1,68 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// TRD asks for a TRD's title, authors, purpose and scope, architecture and
+// components, security overview, first performance requirement, and
+// deployment environments. title is the default title.
+func TRD(p *Prompter, title string) *trd.Document {
+	now := time.Now()
+	p.Section("Document")
+	doc := &trd.Document{Metadata: trd.Metadata{
+		Title:     p.Ask("Title", orTODO(title, "system name")),
+		ID:        p.Ask("ID", prd.GenerateIDWithPrefix("TRD")),
+		Version:   "1.0.0",
+		Status:    trd.StatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}}
+	doc.Metadata.Authors = p.askAuthors()
+
+	p.Section("Executive summary")
+	doc.ExecutiveSummary.Purpose = orTODO(p.Ask("Purpose", ""), "why this system is being built")
+	doc.ExecutiveSummary.Scope = orTODO(p.Ask("Scope", ""), "what the system covers")
+	doc.ExecutiveSummary.TechnicalApproach = orTODO(p.Ask("Technical approach", ""), "summarize the technical approach")
+
+	p.Section("Architecture")
+	doc.Architecture.Overview = orTODO(p.Ask("Overview", ""), "describe the architecture")
+	for i := 1; i == 1 || p.Confirm("Add another component?", false); i++ {
+		doc.Architecture.Components = append(doc.Architecture.Components, trd.Component{
+			ID:          fmt.Sprintf("comp-%d", i),
+			Name:        orTODO(p.Ask(fmt.Sprintf("Component %d name", i), ""), "component name"),
+			Description: orTODO(p.Ask("  Description", ""), "responsibilities and boundaries"),
+			Type:        p.Ask("  Type, e.g. Service, Database, Queue", ""),
+			Technology:  p.Ask("  Technology", ""),
+		})
+	}
+
+	p.Section("Security")
+	doc.SecurityDesign.Overview = orTODO(p.Ask("Overview", ""), "describe the security model")
+	doc.SecurityDesign.Compliance = p.AskList("Compliance frameworks, e.g. SOC2")
+
+	p.Section("First performance requirement")
+	doc.Performance.Requirements = []trd.PerfRequirement{{
+		ID:     "perf-1",
+		Name:   orTODO(p.Ask("Name", ""), "performance requirement"),
+		Metric: p.Ask("Metric", "Latency"),
+		Target: orTODO(p.Ask("Target, e.g. < 200ms p99", ""), "target value"),
+	}}
+
+	p.Section("Deployment")
+	doc.Deployment.Overview = p.Ask("Overview", "")
+	for _, name := range strings.Split(p.Ask("Environments, comma-separated", "Development, Staging, Production"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			doc.Deployment.Environments = append(doc.Deployment.Environments, trd.Environment{Name: name})
+		}
+	}
+	if len(doc.Deployment.Environments) == 0 {
+		doc.Deployment.Environments = []trd.Environment{{Name: "Production"}}
+	}
+	return doc
+}
@@ -0,0 +1,72 @@
+package wizard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grokify/structured-plan/goals/v2mom"
+)
+
+// V2MOM asks for a V2MOM's name, owner, vision, values, methods with their
+// measures, and obstacles. name is the default name. With the flat
+// structure measures are collected at the V2MOM level; otherwise each
+// method has its own.
+func V2MOM(p *Prompter, name, structure string) *v2mom.V2MOM {
+	now := time.Now()
+	p.Section("Document")
+	v := &v2mom.V2MOM{
+		Schema: "../schema/v2mom.schema.json",
+		Metadata: &v2mom.Metadata{
+			Name:        p.Ask("Name", name),
+			Author:      orTODO(p.Ask("Author", ""), "author"),
+			Team:        p.Ask("Team", ""),
+			FiscalYear:  p.Ask("Fiscal year", fmt.Sprintf("FY%d", now.Year())),
+			Quarter:     p.Ask("Quarter (Q1-Q4, H1, H2, Annual)", "Annual"),
+			Version:     "1.0.0",
+			Status:      v2mom.StatusDraft,
+			Structure:   structure,
+			Terminology: v2mom.TerminologyV2MOM,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+	}
+
+	p.Section("Vision and values")
+	v.Vision = orTODO(p.Ask("Vision", ""), "what you want to achieve")
+	for i, value := range p.AskList("Values, most important first") {
+		v.Values = append(v.Values, v2mom.Value{Name: value, Priority: i + 1})
+	}
+	if len(v.Values) == 0 {
+		v.Values = []v2mom.Value{{Name: todo("what matters most"), Priority: 1}}
+	}
+
+	p.Section("Methods")
+	for i := 1; i == 1 || p.Confirm("Add another method?", false); i++ {
+		m := v2mom.Method{
+			ID:       fmt.Sprintf("method-%d", i),
+			Name:     orTODO(p.Ask(fmt.Sprintf("Method %d name", i), ""), "how you will achieve the vision"),
+			Owner:    p.Ask("  Owner", ""),
+			Priority: v2mom.PriorityP0,
+			Status:   "Not Started",
+		}
+		for j, measure := range p.AskList("  Measures") {
+			mm := v2mom.Measure{ID: fmt.Sprintf("m%d-kr%d", i, j+1), Name: measure, Status: "Not Started"}
+			if structure == v2mom.StructureFlat {
+				mm.ID = fmt.Sprintf("measure-%d", len(v.Measures)+1)
+				v.Measures = append(v.Measures, mm)
+			} else {
+				m.Measures = append(m.Measures, mm)
+			}
+		}
+		v.Methods = append(v.Methods, m)
+	}
+	if structure == v2mom.StructureFlat && len(v.Measures) == 0 {
+		v.Measures = []v2mom.Measure{{ID: "measure-1", Name: todo("how success is measured"), Status: "Not Started"}}
+	}
+
+	p.Section("Obstacles")
+	for i, obstacle := range p.AskList("What could prevent success?") {
+		v.Obstacles = append(v.Obstacles, v2mom.Obstacle{ID: fmt.Sprintf("obs-%d", i+1), Name: obstacle, Status: "Identified"})
+	}
+	return v
+}
@@ -0,0 +1,173 @@
+// Package wizard builds new planning documents from answers to a short
+// series of questions, for "init --interactive" commands.
+//
+// Every question has a default, so a wizard run on empty input, such as a
+// closed stdin, produces a skeleton document. Required content that is not
+// answered is filled with a TODO placeholder, so the result passes
+// validation and the gaps can be found with a text search.
+package wizard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+)
+
+// TODOPrefix starts every placeholder the wizards write.
+const TODOPrefix = "TODO:"
+
+func todo(what string) string {
+	return TODOPrefix + " " + what
+}
+
+// Prompter asks questions on out and reads the answers, one per line, from
+// in. Once in is exhausted every question takes its default.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	eof bool
+}
+
+// NewPrompter returns a Prompter reading answers from in and writing
+// questions to out.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// line reads the next answer, trimmed. It returns "" at the end of input.
+func (p *Prompter) line() string {
+	if p.eof {
+		return ""
+	}
+	s, err := p.in.ReadString('\n')
+	if err != nil {
+		p.eof = true
+	}
+	return strings.TrimSpace(s)
+}
+
+// Section prints a heading that introduces the next group of questions.
+func (p *Prompter) Section(title string) {
+	fmt.Fprintf(p.out, "\n%s\n", title)
+}
+
+// Ask asks a question and returns the answer, or def when the answer is
+// empty.
+func (p *Prompter) Ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if s := p.line(); s != "" {
+		return s
+	}
+	return def
+}
+
+// AskValid asks a question until check accepts the answer. An empty answer
+// returns def without checking it.
+func (p *Prompter) AskValid(question, def string, check func(string) error) string {
+	for {
+		s := p.Ask(question, def)
+		if s == def {
+			return s
+		}
+		err := check(s)
+		if err == nil {
+			return s
+		}
+		fmt.Fprintf(p.out, "  %v\n", err)
+		if p.eof {
+			return def
+		}
+	}
+}
+
+// AskList asks for a list, one item per line, ended by an empty line.
+func (p *Prompter) AskList(question string) []string {
+	fmt.Fprintf(p.out, "%s (one per line, empty line to finish):\n", question)
+	items := []string{}
+	for {
+		fmt.Fprint(p.out, "  - ")
+		s := p.line()
+		if s == "" {
+			if p.eof {
+				fmt.Fprintln(p.out)
+			}
+			return items
+		}
+		items = append(items, s)
+	}
+}
+
+// Confirm asks a yes/no question.
+func (p *Prompter) Confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, hint)
+		switch strings.ToLower(p.line()) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		fmt.Fprintln(p.out, "  Please answer y or n.")
+	}
+}
+
+// orTODO returns s, or a TODO placeholder describing what is missing.
+func orTODO(s, what string) string {
+	if s == "" {
+		return todo(what)
+	}
+	return s
+}
+
+// askAuthors asks for the document authors as "Name <email>" or "Name".
+// Without an answer the author is a placeholder.
+func (p *Prompter) askAuthors() []common.Person {
+	var authors []common.Person
+	for _, a := range p.AskList("Authors, as Name <email>") {
+		person := common.Person{Name: a}
+		if i := strings.Index(a, "<"); i > 0 && strings.HasSuffix(a, ">") {
+			person = common.Person{Name: strings.TrimSpace(a[:i]), Email: strings.TrimSpace(a[i+1 : len(a)-1])}
+		}
+		authors = append(authors, person)
+	}
+	if len(authors) == 0 {
+		authors = []common.Person{{Name: todo("author")}}
+	}
+	return authors
+}
+
+// FileName returns the default file name for a new document: the title as
+// a lowercase, hyphenated slug with the document kind as a suffix, as in
+// "checkout-redesign.prd.json".
+func FileName(title, kind string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			hyphen = false
+		case sb.Len() > 0 && !hyphen:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	name := strings.TrimSuffix(sb.String(), "-")
+	if name == "" || strings.HasPrefix(strings.ToLower(title), strings.ToLower(TODOPrefix)) {
+		name = "untitled"
+	}
+	return name + "." + kind + ".json"
+}
@@ -0,0 +1,155 @@
+package wizard
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/schema"
+)
+
+func prompter(answers ...string) *Prompter {
+	return NewPrompter(strings.NewReader(strings.Join(answers, "\n")), io.Discard)
+}
+
+func TestPrompter(t *testing.T) {
+	var out strings.Builder
+	p := NewPrompter(strings.NewReader("\nyes\nmaybe\nn\none\n two \n\nbad\ngood\n"), &out)
+	if got := p.Ask("Name", "Ada"); got != "Ada" {
+		t.Errorf("Ask default = %q", got)
+	}
+	if !p.Confirm("Continue?", false) {
+		t.Error("Confirm yes = false")
+	}
+	if p.Confirm("Again?", true) {
+		t.Error("Confirm after maybe, n = true")
+	}
+	if got := p.AskList("Items"); len(got) != 2 || got[1] != "two" {
+		t.Errorf("AskList = %q", got)
+	}
+	check := func(s string) error {
+		if s == "bad" {
+			return errors.New("try again")
+		}
+		return nil
+	}
+	if got := p.AskValid("Value", "", check); got != "good" {
+		t.Errorf("AskValid = %q", got)
+	}
+	if got := p.Ask("After end", "def"); got != "def" {
+		t.Errorf("Ask at end of input = %q", got)
+	}
+	for _, want := range []string{"Name [Ada]: ", "Continue? [y/N]: ", "Please answer y or n.", "  try again\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPRD(t *testing.T) {
+	doc := PRD(prompter(
+		"Checkout Redesign", "PRD-1", "Ada Lovelace <ada@example.com>", "",
+		"Carts are abandoned", "One-page checkout", "",
+		"Guest Shopper", "Shopper", "Pay fast", "", "", "n",
+		"", "pay without an account", "I finish quickly", "", "",
+		"Convert more", "Conversion", "Rate", "2%", "3%",
+		"Beta", "sprint", "2026-02-30", "2026-03-01", "",
+	), "")
+
+	if doc.Metadata.Title != "Checkout Redesign" || doc.Metadata.ID != "PRD-1" {
+		t.Errorf("metadata = %+v", doc.Metadata)
+	}
+	if a := doc.Metadata.Authors; len(a) != 1 || a[0].Name != "Ada Lovelace" || a[0].Email != "ada@example.com" {
+		t.Errorf("authors = %+v", a)
+	}
+	if len(doc.Personas) != 1 || doc.Personas[0].Goals[0] != "Pay fast" {
+		t.Errorf("personas = %+v", doc.Personas)
+	}
+	if s := doc.UserStories[0]; s.AsA != "Shopper" || s.Title != "pay without an account" || s.PersonaID != "persona-1" {
+		t.Errorf("story = %+v", s)
+	}
+	if kr := doc.Objectives.OKRs[0].Objective.KeyResults[0]; kr.Target != "3%" {
+		t.Errorf("key result = %+v", kr)
+	}
+	phase := doc.Roadmap.Phases[0]
+	if phase.Name != "Beta" || phase.Type != prd.PhaseTypeSprint || phase.StartDate == nil || phase.StartDate.Day() != 1 {
+		t.Errorf("phase = %+v", phase)
+	}
+	if phase.EndDate != nil {
+		t.Errorf("end date = %v, want none", phase.EndDate)
+	}
+	if strings.Contains(doc.ExecutiveSummary.ProblemStatement, TODOPrefix) {
+		t.Error("answered question left a placeholder")
+	}
+}
+
+func TestSkeletons(t *testing.T) {
+	doc := PRD(prompter(), "")
+	if r := prd.Validate(doc); !r.Valid {
+		t.Errorf("skeleton PRD errors = %+v", r.Errors)
+	}
+	if !strings.HasPrefix(doc.Metadata.Title, TODOPrefix) || len(doc.Personas) != 1 || len(doc.UserStories) != 1 {
+		t.Errorf("skeleton PRD = %+v", doc.Metadata)
+	}
+
+	m := MRD(prompter(), "Agent Platform")
+	if m.Metadata.Title != "Agent Platform" || m.MarketOverview.TAM.Value == "" ||
+		len(m.TargetMarket.PrimarySegments) != 1 || len(m.CompetitiveLandscape.Competitors) != 1 ||
+		len(m.MarketRequirements) != 1 || m.Positioning.Statement == "" {
+		t.Errorf("skeleton MRD = %+v", m)
+	}
+
+	d := TRD(prompter(), "")
+	if len(d.Architecture.Components) != 1 || d.SecurityDesign.Overview == "" ||
+		len(d.Performance.Requirements) != 1 || len(d.Deployment.Environments) != 3 {
+		t.Errorf("skeleton TRD = %+v", d)
+	}
+
+	v := V2MOM(prompter(), "FY2027", v2mom.StructureFlat)
+	for _, e := range v.Validate(v2mom.DefaultValidationOptions()) {
+		if e.Severity == "error" {
+			t.Errorf("skeleton V2MOM error: %s: %s", e.Path, e.Message)
+		}
+	}
+}
+
+func TestSkeletonsMatchSchema(t *testing.T) {
+	docs := map[string]any{
+		"prd": PRD(prompter(), ""),
+		"mrd": MRD(prompter(), ""),
+		"trd": TRD(prompter(), ""),
+	}
+	for docType, doc := range docs {
+		v, err := schema.ValidatorFor(docType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		violations, err := v.Validate(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, vi := range violations {
+			t.Errorf("skeleton %s: %s", docType, vi)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	for title, want := range map[string]string{
+		"Checkout Redesign!": "checkout-redesign.prd.json",
+		"TODO: product name": "untitled.prd.json",
+		"":                   "untitled.prd.json",
+	} {
+		if got := FileName(title, "prd"); got != want {
+			t.Errorf("FileName(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
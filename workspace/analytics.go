@@ -0,0 +1,583 @@
+package workspace
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/directory"
+	"github.com/grokify/structured-plan/history"
+	"github.com/grokify/structured-plan/htmldoc"
+)
+
+// NoTeam labels documents whose team is not known.
+const NoTeam = "(no team)"
+
+// Analytics tables, as accepted by WriteCSV.
+const (
+	TableTeams     = "teams"
+	TableAuthors   = "authors"
+	TableDocuments = "documents"
+)
+
+// AnalyticsTables lists the tables of an analytics report.
+var AnalyticsTables = []string{TableTeams, TableAuthors, TableDocuments}
+
+// AnalyticsOptions configure BuildAnalytics.
+type AnalyticsOptions struct {
+	Options
+
+	// Git reads the commits of each document for its contributors and the
+	// date it was first approved. Without it, only the document metadata is
+	// used.
+	Git bool
+}
+
+// DocumentActivity is the authorship and activity of one document.
+type DocumentActivity struct {
+	Path   string `json:"path"`
+	Kind   Kind   `json:"kind"`
+	ID     string `json:"id,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Team   string `json:"team,omitempty"`
+	Status string `json:"status,omitempty"`
+
+	// Authors are the authors, author, or owner named in the metadata.
+	Authors []string `json:"authors,omitempty"`
+
+	// Score is the 0-100 health score of the workspace dashboard.
+	Score    float64 `json:"score"`
+	HasScore bool    `json:"hasScore"`
+
+	// DraftAt is when the document was started: its createdAt or its first
+	// commit, whichever is earlier. ApprovedAt is the first commit with an
+	// approved or active status, or reviewedAt without one.
+	DraftAt        time.Time  `json:"draftAt,omitempty"`
+	ApprovedAt     *time.Time `json:"approvedAt,omitempty"`
+	DaysToApproval *float64   `json:"daysToApproval,omitempty"`
+
+	Commits      int           `json:"commits"`
+	Contributors []Contributor `json:"contributors,omitempty"`
+	LastActivity time.Time     `json:"lastActivity,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Contributor is a person who committed changes to a document.
+type Contributor struct {
+	Name       string    `json:"name"`
+	Commits    int       `json:"commits"`
+	LastCommit time.Time `json:"lastCommit"`
+}
+
+// TeamActivity aggregates the documents of a team.
+type TeamActivity struct {
+	Team         string  `json:"team"`
+	Documents    int     `json:"documents"`
+	Scored       int     `json:"scored"`
+	AverageScore float64 `json:"averageScore"`
+	Approved     int     `json:"approved"`
+
+	// AverageDaysToApproval and MedianDaysToApproval cover the approved
+	// documents with a known draft date; they are nil if there are none.
+	AverageDaysToApproval *float64 `json:"averageDaysToApproval,omitempty"`
+	MedianDaysToApproval  *float64 `json:"medianDaysToApproval,omitempty"`
+
+	Commits      int `json:"commits"`
+	Contributors int `json:"contributors"`
+}
+
+// AuthorActivity aggregates the documents a person wrote or changed.
+type AuthorActivity struct {
+	Author string `json:"author"`
+	Team   string `json:"team,omitempty"`
+
+	// Documents counts the documents naming the person as an author;
+	// Contributed counts those they committed to. LastActivity is the
+	// latest change to a document they wrote or their latest commit.
+	Documents    int       `json:"documents"`
+	Contributed  int       `json:"contributed"`
+	Commits      int       `json:"commits"`
+	LastActivity time.Time `json:"lastActivity,omitempty"`
+}
+
+// Analytics reports who writes the documents of a workspace, how often
+// they change, and how long they take to be approved. It is computed from
+// the documents and their git history only; nothing is sent anywhere.
+type Analytics struct {
+	Root        string             `json:"root"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Git         bool               `json:"git"`
+	Teams       []TeamActivity     `json:"teams"`
+	Authors     []AuthorActivity   `json:"authors"`
+	Documents   []DocumentActivity `json:"documents"`
+
+	// Warnings name the documents whose history could not be read.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// activityMetadata holds the metadata members analytics read, across
+// document kinds. Dates are parsed leniently, since an unparsable date
+// should not hide a document from the report.
+type activityMetadata struct {
+	Status     string          `json:"status"`
+	Team       string          `json:"team"`
+	CreatedAt  string          `json:"createdAt"`
+	ReviewedAt string          `json:"reviewedAt"`
+	Authors    []common.Person `json:"authors"`
+	Author     string          `json:"author"`
+	Owner      string          `json:"owner"`
+}
+
+func readActivityMetadata(data []byte) (activityMetadata, error) {
+	var doc struct {
+		Metadata activityMetadata `json:"metadata"`
+	}
+	err := json.Unmarshal(data, &doc)
+	return doc.Metadata, err
+}
+
+// approvedStatus reports whether a status marks a document as agreed:
+// approved for requirements documents, active for goals and roadmaps.
+func approvedStatus(status string) bool {
+	return strings.EqualFold(status, string(common.StatusApproved)) || strings.EqualFold(status, "active")
+}
+
+// BuildAnalytics computes authorship and activity analytics for the
+// documents at paths. Names are matched to the people directory of the
+// workspace, if it has one, which also supplies the team of documents
+// that do not set one. Documents that cannot be loaded are listed with
+// their error and left out of the team and author totals.
+func BuildAnalytics(ctx context.Context, root string, paths []string, opts AnalyticsOptions) (*Analytics, error) {
+	opts.Options = opts.withDefaults()
+	a := &Analytics{Root: root, GeneratedAt: opts.Now, Git: opts.Git}
+	dirs := map[string]*directory.Directory{}
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		d, err := DirectoryFor(path)
+		if err != nil {
+			return nil, err
+		}
+		e, warning := documentActivity(ctx, path, d, opts)
+		if warning != "" {
+			a.Warnings = append(a.Warnings, warning)
+		}
+		if rel, err := filepath.Rel(root, path); err == nil {
+			e.Path = filepath.ToSlash(rel)
+		}
+		dirs[e.Path] = d
+		a.Documents = append(a.Documents, e)
+	}
+	a.Teams = teamActivity(a.Documents)
+	a.Authors = authorActivity(a.Documents, dirs)
+	return a, nil
+}
+
+func documentActivity(ctx context.Context, path string, d *directory.Directory, opts AnalyticsOptions) (DocumentActivity, string) {
+	s := SummarizeFile(path, opts.Options)
+	e := DocumentActivity{
+		Path: path, Kind: s.Kind, ID: s.ID, Title: s.Title, Status: s.Status,
+		Score: s.Score, HasScore: s.HasScore, LastActivity: s.UpdatedAt, Error: s.Error,
+	}
+	if e.Error != "" {
+		return e, ""
+	}
+	data, err := ReadJSON(path)
+	if err != nil {
+		e.Error = err.Error()
+		return e, ""
+	}
+	m, err := readActivityMetadata(data)
+	if err != nil {
+		e.Error = err.Error()
+		return e, ""
+	}
+
+	var names []string
+	for _, p := range m.Authors {
+		names = append(names, cmp.Or(p.Name, p.Email))
+	}
+	names = append(names, m.Author, m.Owner)
+	for _, name := range names {
+		if name = canonicalName(d, name); name != "" && !slices.Contains(e.Authors, name) {
+			e.Authors = append(e.Authors, name)
+		}
+	}
+	e.Team = m.Team
+	if e.Team == "" && d != nil && len(e.Authors) > 0 {
+		if p, ok := d.Person(e.Authors[0]); ok {
+			e.Team = p.Team
+		}
+	}
+
+	e.DraftAt = parseActivityTime(m.CreatedAt)
+	var warning string
+	if opts.Git {
+		if err := e.addHistory(ctx, path, d); err != nil {
+			warning = fmt.Sprintf("%s: git history unavailable: %v", path, err)
+		}
+	}
+	if e.ApprovedAt == nil && approvedStatus(m.Status) {
+		if t := parseActivityTime(m.ReviewedAt); !t.IsZero() {
+			e.ApprovedAt = &t
+		}
+	}
+	if e.ApprovedAt != nil && !e.DraftAt.IsZero() {
+		days := math.Round(max(0, e.ApprovedAt.Sub(e.DraftAt).Hours()/24)*10) / 10
+		e.DaysToApproval = &days
+	}
+	return e, warning
+}
+
+// addHistory reads the commits of the document for its contributors, its
+// first commit, and the first commit in which it was approved.
+func (e *DocumentActivity) addHistory(ctx context.Context, path string, d *directory.Directory) error {
+	commits, err := history.Log(ctx, path)
+	if err != nil {
+		return err
+	}
+	approved := false
+	for _, c := range commits {
+		e.Commits++
+		name := canonicalName(d, c.Author, c.Email)
+		i := slices.IndexFunc(e.Contributors, func(c Contributor) bool { return c.Name == name })
+		if i < 0 {
+			i = len(e.Contributors)
+			e.Contributors = append(e.Contributors, Contributor{Name: name})
+		}
+		e.Contributors[i].Commits++
+		e.Contributors[i].LastCommit = c.Date
+		if e.DraftAt.IsZero() || c.Date.Before(e.DraftAt) {
+			e.DraftAt = c.Date
+		}
+		if c.Date.After(e.LastActivity) {
+			e.LastActivity = c.Date
+		}
+		if approved {
+			continue
+		}
+		data, err := history.Show(ctx, path, c)
+		if err != nil {
+			continue
+		}
+		if m, err := readActivityMetadata(data); err == nil && approvedStatus(m.Status) {
+			date := c.Date
+			e.ApprovedAt = &date
+			approved = true
+		}
+	}
+	return nil
+}
+
+// canonicalName returns the directory name of the first of names the
+// directory knows, or the first non-empty name.
+func canonicalName(d *directory.Directory, names ...string) string {
+	if d != nil {
+		for _, name := range names {
+			if p, ok := d.Person(name); ok {
+				return p.Name
+			}
+		}
+	}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func parseActivityTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func teamActivity(docs []DocumentActivity) []TeamActivity {
+	byTeam := map[string]*TeamActivity{}
+	contributors := map[string]map[string]bool{}
+	days := map[string][]float64{}
+	for _, e := range docs {
+		if e.Error != "" {
+			continue
+		}
+		team := cmp.Or(e.Team, NoTeam)
+		t, ok := byTeam[team]
+		if !ok {
+			t = &TeamActivity{Team: team}
+			byTeam[team] = t
+			contributors[team] = map[string]bool{}
+		}
+		t.Documents++
+		if e.HasScore {
+			t.AverageScore += e.Score
+			t.Scored++
+		}
+		if e.ApprovedAt != nil {
+			t.Approved++
+		}
+		if e.DaysToApproval != nil {
+			days[team] = append(days[team], *e.DaysToApproval)
+		}
+		t.Commits += e.Commits
+		for _, c := range e.Contributors {
+			contributors[team][c.Name] = true
+		}
+	}
+
+	teams := make([]TeamActivity, 0, len(byTeam))
+	for team, t := range byTeam {
+		if t.Scored > 0 {
+			t.AverageScore /= float64(t.Scored)
+		}
+		if len(days[team]) > 0 {
+			mean, median := meanMedian(days[team])
+			t.AverageDaysToApproval, t.MedianDaysToApproval = &mean, &median
+		}
+		t.Contributors = len(contributors[team])
+		teams = append(teams, *t)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if teams[i].Documents != teams[j].Documents {
+			return teams[i].Documents > teams[j].Documents
+		}
+		return teams[i].Team < teams[j].Team
+	})
+	return teams
+}
+
+func authorActivity(docs []DocumentActivity, dirs map[string]*directory.Directory) []AuthorActivity {
+	byAuthor := map[string]*AuthorActivity{}
+	get := func(name, path string) *AuthorActivity {
+		a, ok := byAuthor[name]
+		if !ok {
+			a = &AuthorActivity{Author: name}
+			if d := dirs[path]; d != nil {
+				if p, ok := d.Person(name); ok {
+					a.Team = p.Team
+				}
+			}
+			byAuthor[name] = a
+		}
+		return a
+	}
+	for _, e := range docs {
+		if e.Error != "" {
+			continue
+		}
+		for _, name := range e.Authors {
+			a := get(name, e.Path)
+			a.Documents++
+			if e.LastActivity.After(a.LastActivity) {
+				a.LastActivity = e.LastActivity
+			}
+		}
+		for _, c := range e.Contributors {
+			a := get(c.Name, e.Path)
+			a.Contributed++
+			a.Commits += c.Commits
+			if c.LastCommit.After(a.LastActivity) {
+				a.LastActivity = c.LastCommit
+			}
+		}
+	}
+
+	authors := make([]AuthorActivity, 0, len(byAuthor))
+	for _, a := range byAuthor {
+		authors = append(authors, *a)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Documents != authors[j].Documents {
+			return authors[i].Documents > authors[j].Documents
+		}
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return authors[i].Author < authors[j].Author
+	})
+	return authors
+}
+
+func meanMedian(values []float64) (mean, median float64) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= float64(len(sorted))
+	if n := len(sorted); n%2 == 1 {
+		median = sorted[n/2]
+	} else {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return mean, median
+}
+
+// ToText renders the team and author tables for the terminal.
+func (a *Analytics) ToText() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Authorship Analytics: %s (%d documents, %d teams, %d authors)\n", a.Root, len(a.Documents), len(a.Teams), len(a.Authors)))
+	if !a.Git {
+		sb.WriteString("Git history not read; commits and contributors are not counted.\n")
+	}
+	if len(a.Documents) == 0 {
+		sb.WriteString("\nNo documents found.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("\nTeams\n\n")
+	writeTextTable(&sb, a.tableRows(TableTeams))
+	sb.WriteString("\nAuthors\n\n")
+	writeTextTable(&sb, a.tableRows(TableAuthors))
+	for _, w := range a.Warnings {
+		sb.WriteString("\nWarning: " + w)
+	}
+	if len(a.Warnings) > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// WriteCSV writes one of AnalyticsTables as CSV.
+func (a *Analytics) WriteCSV(w io.Writer, table string) error {
+	if !slices.Contains(AnalyticsTables, table) {
+		return fmt.Errorf("unknown table %q (valid: %s)", table, strings.Join(AnalyticsTables, ", "))
+	}
+	cw := csv.NewWriter(w)
+	for i, row := range a.tableRows(table) {
+		if err := cw.Write(row); err != nil {
+			if i == 0 {
+				return fmt.Errorf("writing CSV header: %w", err)
+			}
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Page returns the analytics as a standalone HTML page with the team,
+// author, and document tables.
+func (a *Analytics) Page() *htmldoc.Page {
+	page := htmldoc.NewPage("Authorship Analytics", "Authorship Analytics")
+	source := "Document metadata and git history"
+	if !a.Git {
+		source = "Document metadata"
+	}
+	page.Meta = []htmldoc.Field{
+		{Label: "Root", Value: a.Root},
+		{Label: "Documents", Value: strconv.Itoa(len(a.Documents))},
+		{Label: "Source", Value: source},
+		{Label: "Generated", Value: htmldoc.Date(a.GeneratedAt)},
+	}
+	for _, table := range AnalyticsTables {
+		rows := a.tableRows(table)
+		b := htmldoc.NewBuilder(table)
+		b.Table(rows[0], rows[1:])
+		page.AddSection(strings.ToUpper(table[:1])+table[1:], b)
+	}
+	if len(a.Warnings) > 0 {
+		b := htmldoc.NewBuilder("warnings")
+		b.List("", a.Warnings)
+		page.AddSection("Warnings", b)
+	}
+	return page
+}
+
+// tableRows returns a table of the report, header first.
+func (a *Analytics) tableRows(table string) [][]string {
+	var rows [][]string
+	switch table {
+	case TableTeams:
+		rows = append(rows, []string{"Team", "Documents", "Average Score", "Approved", "Average Days to Approval", "Median Days to Approval", "Commits", "Contributors"})
+		for _, t := range a.Teams {
+			rows = append(rows, []string{t.Team, strconv.Itoa(t.Documents), optionalFloat(t.AverageScore, t.Scored > 0),
+				strconv.Itoa(t.Approved), formatDays(t.AverageDaysToApproval), formatDays(t.MedianDaysToApproval),
+				strconv.Itoa(t.Commits), strconv.Itoa(t.Contributors)})
+		}
+	case TableAuthors:
+		rows = append(rows, []string{"Author", "Team", "Documents", "Contributed", "Commits", "Last Activity"})
+		for _, au := range a.Authors {
+			rows = append(rows, []string{au.Author, au.Team, strconv.Itoa(au.Documents), strconv.Itoa(au.Contributed),
+				strconv.Itoa(au.Commits), activityDate(au.LastActivity)})
+		}
+	case TableDocuments:
+		rows = append(rows, []string{"Path", "Kind", "ID", "Title", "Team", "Status", "Authors", "Score",
+			"Draft", "Approved", "Days to Approval", "Commits", "Contributors", "Last Activity", "Error"})
+		for _, e := range a.Documents {
+			var approved string
+			if e.ApprovedAt != nil {
+				approved = activityDate(*e.ApprovedAt)
+			}
+			names := make([]string, len(e.Contributors))
+			for i, c := range e.Contributors {
+				names[i] = c.Name
+			}
+			rows = append(rows, []string{e.Path, string(e.Kind), e.ID, e.Title, e.Team, e.Status, strings.Join(e.Authors, "; "),
+				optionalFloat(e.Score, e.HasScore), activityDate(e.DraftAt), approved, formatDays(e.DaysToApproval), strconv.Itoa(e.Commits),
+				strings.Join(names, "; "), activityDate(e.LastActivity), e.Error})
+		}
+	}
+	return rows
+}
+
+func optionalFloat(v float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+func formatDays(days *float64) string {
+	if days == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*days, 'f', 1, 64)
+}
+
+func activityDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+// writeTextTable writes rows as columns padded to their widest cell.
+func writeTextTable(sb *strings.Builder, rows [][]string) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	for r, row := range rows {
+		var cells []string
+		for i, cell := range row {
+			cells = append(cells, cell+strings.Repeat(" ", widths[i]-len([]rune(cell))))
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(cells, "  "), " ") + "\n")
+		if r == 0 {
+			total := 2 * (len(widths) - 1)
+			for _, w := range widths {
+				total += w
+			}
+			sb.WriteString(strings.Repeat("-", total) + "\n")
+		}
+	}
+}
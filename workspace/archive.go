@@ -0,0 +1,561 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/assets"
+	"github.com/grokify/structured-plan/bundle"
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/jsonpatch"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/lint"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/snippets"
+	"github.com/grokify/structured-plan/validation"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// ArchiveType is the bundle type of a workspace archive.
+const ArchiveType = "workspace"
+
+// ExternalDir is the archive directory holding files that the workspace
+// references from outside its root.
+const ExternalDir = "external"
+
+// ExportOptions configure Export.
+type ExportOptions struct {
+	// Now is the time the archive is made. Defaults to time.Now().
+	Now time.Time
+
+	// Lenient recovers from field-level type errors when a document's
+	// asset references are read.
+	Lenient bool
+}
+
+// Export packages a workspace into an archive: the manifest, every
+// planning document, the shared files the manifest lists, persona
+// libraries, the lint configuration, the people directory file, and the
+// local files the PRDs and TRDs reference.
+//
+// The workspace root is the directory of the manifest found from root, or
+// root itself if there is none. Files are stored at their path relative to
+// the root. Files referenced from outside it are stored under external/,
+// and the references to them in the manifest and documents are rewritten,
+// so the archive is complete on its own. Referenced files that do not exist
+// are recorded as skipped.
+func Export(root string, opts ExportOptions) (*bundle.Bundle, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	m, err := FindManifest(root)
+	if err != nil {
+		return nil, err
+	}
+	if m != nil {
+		root = m.Dir
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	e := &exporter{
+		root:    root,
+		opts:    opts,
+		b:       bundle.New(root, ArchiveType, opts.Now),
+		paths:   map[string]string{},
+		taken:   map[string]bool{},
+		written: map[string]bool{},
+	}
+
+	docs, err := Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	kinds := make(map[string]Kind, len(docs))
+	for _, p := range docs {
+		kinds[p], _ = KindFromPath(p)
+	}
+	if m != nil {
+		for _, d := range m.Documents {
+			kinds[m.Source(d)] = d.Kind
+		}
+	}
+	sources := make([]string, 0, len(kinds))
+	for p := range kinds {
+		sources = append(sources, p)
+	}
+	sort.Strings(sources)
+	for _, p := range sources {
+		if err := e.addDocument(p, kinds[p]); err != nil {
+			return nil, err
+		}
+	}
+
+	shared, err := sharedFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range shared {
+		if _, err := e.addFile(bundle.KindShared, p, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if m != nil {
+		if err := e.addManifest(m); err != nil {
+			return nil, err
+		}
+	}
+	return e.b, nil
+}
+
+// sharedFiles returns the persona libraries under root and its lint
+// configuration.
+func sharedFiles(root string) ([]string, error) {
+	var files []string
+	for _, name := range lint.ConfigFiles {
+		p := filepath.Join(root, name)
+		if _, err := os.Stat(p); err == nil {
+			files = append(files, p)
+		}
+	}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == prd.DefaultPersonaLibraryFilename {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning workspace %s: %w", root, err)
+	}
+	return files, nil
+}
+
+type exporter struct {
+	root    string
+	opts    ExportOptions
+	b       *bundle.Bundle
+	paths   map[string]string // source file -> archive path
+	taken   map[string]bool   // archive paths under ExternalDir
+	written map[string]bool   // archive paths added
+}
+
+// place returns the archive path of a source file, and whether it is
+// outside the workspace root.
+func (e *exporter) place(src string) (string, bool) {
+	if p, ok := e.paths[src]; ok {
+		return p, strings.HasPrefix(p, ExternalDir+"/")
+	}
+	if rel, err := filepath.Rel(e.root, src); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		p := filepath.ToSlash(rel)
+		e.paths[src] = p
+		return p, false
+	}
+	name := assets.Normalize(filepath.Base(src))
+	p := path.Join(ExternalDir, name)
+	for n := 2; e.taken[p]; n++ {
+		ext := path.Ext(name)
+		p = path.Join(ExternalDir, fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), n, ext))
+	}
+	e.taken[p] = true
+	e.paths[src] = p
+	return p, true
+}
+
+// addFile adds a file once, returning its archive path. A missing file is
+// recorded as skipped, with reference describing where it is referenced,
+// and "" is returned.
+func (e *exporter) addFile(kind, src, reference string) (string, error) {
+	p, _ := e.place(src)
+	if e.written[p] {
+		return p, nil
+	}
+	data, err := os.ReadFile(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		reason := "file not found: " + src
+		if reference != "" {
+			reason = reference + ": " + reason
+		}
+		e.b.Skip(kind, reason)
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("reading %s: %w", src, err)
+	}
+	if err := e.b.Add(kind, p, data); err != nil {
+		return "", err
+	}
+	e.written[p] = true
+	return p, nil
+}
+
+// addDocument adds a document and the local files it references. A
+// document referencing files outside the workspace is rewritten to point
+// at their copies under external/; the rest of its source is kept as is.
+func (e *exporter) addDocument(src string, kind Kind) error {
+	docPath, _ := e.place(src)
+	data, err := os.ReadFile(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		e.b.Skip(bundle.KindDocument, "file not found: "+src)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading %s: %w", src, err)
+	}
+
+	var doc interface{ AssetRefs() []assets.Ref }
+	switch kind {
+	case KindPRD:
+		doc = &prd.Document{}
+	case KindTRD:
+		doc = &trd.Document{}
+	}
+	if doc != nil {
+		format := yamlconv.FormatFromPath(src)
+		source := data
+		if format == yamlconv.FormatYAML {
+			if source, err = yamlconv.ToJSON(data); err != nil {
+				return fmt.Errorf("parsing %s: %w", src, err)
+			}
+		}
+		expanded, refs, err := ExpandSnippets(src, source)
+		if err != nil {
+			return err
+		}
+		if e.opts.Lenient {
+			_, err = lenient.Unmarshal(expanded, doc)
+		} else {
+			err = json.Unmarshal(expanded, doc)
+		}
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", src, err)
+		}
+		var patch jsonpatch.Patch
+		for _, ref := range doc.AssetRefs() {
+			if !assets.IsLocal(*ref.Value) {
+				continue
+			}
+			file := filepath.FromSlash(*ref.Value)
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(filepath.Dir(src), file)
+			}
+			p, err := e.addFile(bundle.KindAsset, filepath.Clean(file), docPath+": "+ref.Field)
+			if err != nil {
+				return err
+			}
+			if p == "" {
+				continue
+			}
+			if link := relPath(path.Dir(docPath), p); link != path.Clean(filepath.ToSlash(*ref.Value)) {
+				patch = append(patch, jsonpatch.Replace(validation.PathPointer(ref.Field), link))
+			}
+		}
+		if len(patch) > 0 {
+			if data, err = patchSource(data, format, source, expanded, refs, patch); err != nil {
+				return fmt.Errorf("rewriting %s: %w", src, err)
+			}
+		}
+	}
+
+	if err := e.b.Add(bundle.KindDocument, docPath, data); err != nil {
+		return err
+	}
+	e.written[docPath] = true
+	return nil
+}
+
+// patchSource applies patch, a patch of the document with its snippet
+// references expanded, to the document's source data and returns the
+// result. Only the patched values change: member order, members the
+// document types do not know, and YAML comments are kept, and content
+// expanded from snippets is written as snippet references again. source is
+// data as JSON and expanded is source with its snippets expanded.
+func patchSource(data []byte, format yamlconv.Format, source, expanded []byte, refs []common.SnippetRef, patch jsonpatch.Patch) ([]byte, error) {
+	var doc, original any
+	if err := json.Unmarshal(expanded, &doc); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(source, &original); err != nil {
+		return nil, err
+	}
+	patched, err := patch.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+	collapsed, err := json.Marshal(patched)
+	if err != nil {
+		return nil, err
+	}
+	if collapsed, err = snippets.Collapse(collapsed, refs); err != nil {
+		return nil, err
+	}
+	var want any
+	if err := json.Unmarshal(collapsed, &want); err != nil {
+		return nil, err
+	}
+	return yamlconv.Patch(data, format, jsonpatch.Diff(original, want), nil)
+}
+
+// addManifest adds the manifest, with the paths of documents and shared
+// files outside the workspace rewritten to their copies.
+func (e *exporter) addManifest(m *Manifest) error {
+	out := *m
+	out.Documents = append([]ManifestDocument(nil), m.Documents...)
+	for i, d := range out.Documents {
+		if p, external := e.place(m.Source(d)); external {
+			out.Documents[i] = ManifestDocument{Path: p, Kind: d.Kind}
+		}
+	}
+
+	out.Shared = nil
+	for _, s := range m.Shared {
+		p, err := e.addFile(bundle.KindShared, m.resolve(s), "shared")
+		if err != nil {
+			return err
+		}
+		if p == "" {
+			p = s
+		}
+		out.Shared = append(out.Shared, p)
+	}
+
+	out.Snippets = nil
+	for _, s := range m.Snippets {
+		p, err := e.addFile(bundle.KindShared, m.resolve(s), "snippets")
+		if err != nil {
+			return err
+		}
+		if p == "" {
+			p = s
+		}
+		out.Snippets = append(out.Snippets, p)
+	}
+
+	if dc := m.Directory; dc != nil && dc.File != "" {
+		p, err := e.addFile(bundle.KindShared, m.resolve(dc.File), "directory")
+		if err != nil {
+			return err
+		}
+		if p != "" {
+			copied := *dc
+			copied.File = p
+			out.Directory = &copied
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+	return e.b.Add(bundle.KindWorkspaceManifest, ManifestFile, append(data, '\n'))
+}
+
+// resolve returns the path of a file named in the manifest.
+func (m *Manifest) resolve(p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(m.Dir, filepath.FromSlash(p))
+}
+
+// relPath returns the slash-separated path of target relative to dir, both
+// relative to the archive directory.
+func relPath(dir, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// ImportOptions configure Import.
+type ImportOptions struct {
+	// Force overwrites existing files.
+	Force bool
+
+	// Merge adds the archive's documents and shared files to the manifest
+	// of the workspace the target directory belongs to, with their paths
+	// rewritten relative to it, instead of writing the archive's manifest.
+	Merge bool
+}
+
+// ImportResult reports what Import wrote.
+type ImportResult struct {
+	// Files are the files written.
+	Files []string
+
+	// Manifest is the manifest written or, with Merge, updated. It is ""
+	// if the archive has no manifest and Merge is not set.
+	Manifest string
+
+	// Documents is the number of documents imported.
+	Documents int
+
+	// NotMerged lists the settings of the archive's manifest, such as
+	// "defaults", that Merge left out because they would apply to the
+	// whole target workspace.
+	NotMerged []string
+}
+
+// Import extracts a workspace archive read with bundle.Read into dir. It
+// fails without writing anything if a file already exists, unless Force is
+// set.
+func Import(b *bundle.Bundle, dir string, opts ImportOptions) (*ImportResult, error) {
+	if b.Manifest.Type != ArchiveType {
+		return nil, fmt.Errorf("not a workspace archive (type %q)", b.Manifest.Type)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *Manifest
+	if opts.Merge {
+		if target, err = FindManifest(dir); err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return nil, fmt.Errorf("no %s in %s or its parent directories to merge into", ManifestFile, dir)
+		}
+	}
+
+	var files []bundle.Entry
+	var conflicts []string
+	for _, f := range b.Manifest.Files {
+		if opts.Merge && f.Path == ManifestFile {
+			continue
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if _, err := os.Stat(dst); err == nil && !opts.Force {
+			conflicts = append(conflicts, dst)
+		}
+		files = append(files, f)
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%d file(s) already exist, e.g. %s (use force to overwrite)", len(conflicts), conflicts[0])
+	}
+
+	result := &ImportResult{}
+	for _, f := range files {
+		data, _ := b.File(f.Path)
+		dst := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+			return nil, fmt.Errorf("creating directory: %w", err)
+		}
+		if err := os.WriteFile(dst, data, 0600); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", dst, err)
+		}
+		result.Files = append(result.Files, dst)
+		switch {
+		case f.Kind == bundle.KindDocument:
+			result.Documents++
+		case f.Kind == bundle.KindWorkspaceManifest:
+			result.Manifest = dst
+		}
+	}
+
+	if opts.Merge {
+		if err := mergeManifest(b, dir, target, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// mergeManifest adds the documents and shared files of the archive's
+// manifest, imported into dir, to target.
+func mergeManifest(b *bundle.Bundle, dir string, target *Manifest, result *ImportResult) error {
+	result.Manifest = filepath.Join(target.Dir, ManifestFile)
+	data, ok := b.File(ManifestFile)
+	if !ok {
+		return nil
+	}
+	var src Manifest
+	if err := json.Unmarshal(data, &src); err != nil {
+		return fmt.Errorf("parsing archive manifest: %w", err)
+	}
+
+	prefix, err := filepath.Rel(target.Dir, dir)
+	if err != nil {
+		return err
+	}
+	prefix = filepath.ToSlash(prefix)
+	rewrite := func(p string) string {
+		if p == "" || path.IsAbs(p) {
+			return p
+		}
+		return path.Join(prefix, p)
+	}
+
+	listed := make(map[string]bool, len(target.Documents))
+	for _, d := range target.Documents {
+		listed[path.Clean(filepath.ToSlash(d.Path))] = true
+	}
+	for _, d := range src.Documents {
+		d.Path, d.Markdown, d.HTML = rewrite(d.Path), rewrite(d.Markdown), rewrite(d.HTML)
+		if !listed[d.Path] {
+			target.Documents = append(target.Documents, d)
+			listed[d.Path] = true
+		}
+	}
+	for _, s := range src.Shared {
+		if s = rewrite(s); !contains(target.Shared, s) {
+			target.Shared = append(target.Shared, s)
+		}
+	}
+	for _, s := range src.Snippets {
+		if s = rewrite(s); !contains(target.Snippets, s) {
+			target.Snippets = append(target.Snippets, s)
+		}
+	}
+
+	for _, setting := range []struct {
+		name string
+		set  bool
+	}{
+		{"defaults", src.Defaults != nil},
+		{"technologyPolicy", src.TechnologyPolicy != nil},
+		{"planning", src.Planning != nil},
+		{"directory", src.Directory != nil},
+	} {
+		if setting.set {
+			result.NotMerged = append(result.NotMerged, setting.name)
+		}
+	}
+
+	out, err := json.MarshalIndent(target, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+	if err := os.WriteFile(result.Manifest, append(out, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
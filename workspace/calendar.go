@@ -0,0 +1,74 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-plan/calendar"
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+)
+
+// BuildPlanningCalendar loads the OKRs and V2MOMs at paths and builds the
+// planning calendar for the periods in their metadata, plus any periods
+// given. Documents without a period are skipped; a period that cannot be
+// parsed is an error naming the document.
+func BuildPlanningCalendar(paths []string, cfg calendar.Config, periods []calendar.Period, opts Options) (*calendar.Calendar, error) {
+	docs := map[string][]calendar.Document{}
+	for _, path := range paths {
+		kind, ok := KindFromPath(path)
+		if !ok {
+			continue
+		}
+		var period string
+		var doc calendar.Document
+		switch kind {
+		case KindOKR:
+			var d okr.OKRDocument
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			if d.Metadata == nil {
+				continue
+			}
+			period = d.Metadata.Period
+			doc = calendar.Document{Title: documentLabel(d.Metadata.Name, documentLabel(d.Metadata.ID, path)), Owner: d.Metadata.Owner}
+		case KindV2MOM:
+			var d v2mom.V2MOM
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			if d.Metadata == nil {
+				continue
+			}
+			period = v2momPeriod(d.Metadata)
+			doc = calendar.Document{Title: documentLabel(d.Metadata.Name, documentLabel(d.Metadata.ID, path)), Owner: d.Metadata.Author}
+		default:
+			continue
+		}
+		if period == "" {
+			continue
+		}
+		p, err := cfg.ParsePeriod(period)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		doc.Kind = string(kind)
+		doc.Path = path
+		docs[p.Name()] = append(docs[p.Name()], doc)
+	}
+	return calendar.New(cfg, periods, docs)
+}
+
+// v2momPeriod combines a V2MOM's fiscal year and quarter into a period
+// name such as "FY2026 Q2".
+func v2momPeriod(m *v2mom.Metadata) string {
+	if m.FiscalYear == "" {
+		return ""
+	}
+	q := strings.TrimSpace(m.Quarter)
+	if q == "" || strings.EqualFold(q, "annual") {
+		return m.FiscalYear
+	}
+	return m.FiscalYear + " " + q
+}
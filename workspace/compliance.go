@@ -0,0 +1,324 @@
+package workspace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// FrameworkLevel is the control name used when a document claims a
+// compliance framework without listing individual controls, as TRD
+// SecurityDesign.Compliance does.
+const FrameworkLevel = "(framework-level)"
+
+// ComplianceClaim records that a document claims a control under a
+// compliance framework.
+type ComplianceClaim struct {
+	Framework string `json:"framework"`
+	Control   string `json:"control"`
+	Document  string `json:"document"`
+	Kind      Kind   `json:"kind"`
+	Path      string `json:"path"`
+}
+
+// ComplianceDocument identifies a document that contributed claims.
+type ComplianceDocument struct {
+	Label string `json:"label"`
+	Title string `json:"title,omitempty"`
+	Kind  Kind   `json:"kind"`
+	Path  string `json:"path"`
+}
+
+// ComplianceMatrix is a control-by-framework view of compliance claims
+// across documents, for audit preparation.
+type ComplianceMatrix struct {
+	Frameworks []string             `json:"frameworks"`
+	Controls   []string             `json:"controls"`
+	Documents  []ComplianceDocument `json:"documents"`
+	Claims     []ComplianceClaim    `json:"claims"`
+}
+
+// preferredFrameworks are listed first, in this order, when present.
+var preferredFrameworks = []string{"SOC2", "GDPR", "HIPAA", "PCI-DSS", "ISO 27001"}
+
+// NormalizeFramework maps common spellings of a framework name to a single
+// form so that "SOC 2 Type II" and "soc2" land in the same column.
+// Unrecognized names are returned trimmed but otherwise unchanged.
+func NormalizeFramework(name string) string {
+	key := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name))
+	switch {
+	case strings.HasPrefix(key, "SOC2"):
+		return "SOC2"
+	case key == "GDPR":
+		return "GDPR"
+	case key == "HIPAA":
+		return "HIPAA"
+	case strings.HasPrefix(key, "PCIDSS"), key == "PCI":
+		return "PCI-DSS"
+	case strings.HasPrefix(key, "ISO27001"):
+		return "ISO 27001"
+	case key == "CCPA":
+		return "CCPA"
+	case key == "FEDRAMP":
+		return "FedRAMP"
+	}
+	return strings.TrimSpace(name)
+}
+
+// BuildComplianceMatrix loads the PRD and TRD documents at paths and
+// collects their compliance claims: PRD SecurityModel.ComplianceControls
+// and TRD SecurityDesign.Compliance. Other document kinds are ignored.
+func BuildComplianceMatrix(paths []string, opts Options) (*ComplianceMatrix, error) {
+	var claims []ComplianceClaim
+	var docs []ComplianceDocument
+	for _, path := range paths {
+		kind, ok := KindFromPath(path)
+		if !ok {
+			continue
+		}
+		var (
+			doc ComplianceDocument
+			c   []ComplianceClaim
+		)
+		switch kind {
+		case KindPRD:
+			var d prd.Document
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			doc = ComplianceDocument{Label: documentLabel(d.Metadata.ID, path), Title: d.Metadata.Title, Kind: kind, Path: path}
+			c = prdComplianceClaims(&d, doc)
+		case KindTRD:
+			var d trd.Document
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			doc = ComplianceDocument{Label: documentLabel(d.Metadata.ID, path), Title: d.Metadata.Title, Kind: kind, Path: path}
+			c = trdComplianceClaims(&d, doc)
+		default:
+			continue
+		}
+		if len(c) > 0 {
+			docs = append(docs, doc)
+			claims = append(claims, c...)
+		}
+	}
+	return NewComplianceMatrix(docs, claims), nil
+}
+
+// NewComplianceMatrix builds a matrix from claims. Frameworks are ordered
+// with well-known frameworks first; controls are sorted by name.
+func NewComplianceMatrix(docs []ComplianceDocument, claims []ComplianceClaim) *ComplianceMatrix {
+	m := &ComplianceMatrix{Documents: docs, Claims: claims}
+
+	frameworks := map[string]bool{}
+	controls := map[string]bool{}
+	for _, c := range claims {
+		frameworks[c.Framework] = true
+		controls[c.Control] = true
+	}
+
+	for _, f := range preferredFrameworks {
+		if frameworks[f] {
+			m.Frameworks = append(m.Frameworks, f)
+			delete(frameworks, f)
+		}
+	}
+	var rest []string
+	for f := range frameworks {
+		rest = append(rest, f)
+	}
+	sort.Strings(rest)
+	m.Frameworks = append(m.Frameworks, rest...)
+
+	for c := range controls {
+		if c != FrameworkLevel {
+			m.Controls = append(m.Controls, c)
+		}
+	}
+	sort.Strings(m.Controls)
+	if controls[FrameworkLevel] {
+		m.Controls = append(m.Controls, FrameworkLevel)
+	}
+	return m
+}
+
+// Cell returns the labels of documents claiming control under framework.
+func (m *ComplianceMatrix) Cell(control, framework string) []string {
+	var labels []string
+	seen := map[string]bool{}
+	for _, c := range m.Claims {
+		if c.Control == control && c.Framework == framework && !seen[c.Document] {
+			seen[c.Document] = true
+			labels = append(labels, c.Document)
+		}
+	}
+	return labels
+}
+
+// ToMarkdown renders the matrix as markdown: a per-document framework
+// coverage table followed by the control-by-framework matrix.
+func (m *ComplianceMatrix) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Compliance Control Matrix\n\n")
+
+	if len(m.Claims) == 0 {
+		sb.WriteString("*No compliance controls are claimed by the scanned documents.*\n")
+		return sb.String()
+	}
+
+	sb.WriteString("## Framework Coverage\n\n")
+	sb.WriteString("*Number of controls each document claims per framework. ✓ marks a framework-level claim with no listed controls.*\n\n")
+	sb.WriteString("| Document | Type |")
+	for _, f := range m.Frameworks {
+		sb.WriteString(" " + escapeCell(f) + " |")
+	}
+	sb.WriteString("\n|----------|------|")
+	for range m.Frameworks {
+		sb.WriteString("------|")
+	}
+	sb.WriteString("\n")
+	for _, d := range m.Documents {
+		name := d.Label
+		if d.Title != "" {
+			name += " " + d.Title
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s |", escapeCell(name), strings.ToUpper(string(d.Kind))))
+		for _, f := range m.Frameworks {
+			sb.WriteString(" " + m.coverage(d.Label, f) + " |")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Controls\n\n")
+	sb.WriteString("| Control |")
+	for _, f := range m.Frameworks {
+		sb.WriteString(" " + escapeCell(f) + " |")
+	}
+	sb.WriteString("\n|---------|")
+	for range m.Frameworks {
+		sb.WriteString("------|")
+	}
+	sb.WriteString("\n")
+	for _, ctrl := range m.Controls {
+		name := escapeCell(ctrl)
+		if ctrl == FrameworkLevel {
+			name = "*" + name + "*"
+		}
+		sb.WriteString("| " + name + " |")
+		for _, f := range m.Frameworks {
+			sb.WriteString(" " + escapeCell(strings.Join(m.Cell(ctrl, f), ", ")) + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// WriteCSV writes the control-by-framework matrix as CSV. Each cell lists
+// the claiming documents separated by "; ".
+func (m *ComplianceMatrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"Control"}, m.Frameworks...)); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+	for _, ctrl := range m.Controls {
+		row := make([]string, 0, len(m.Frameworks)+1)
+		row = append(row, ctrl)
+		for _, f := range m.Frameworks {
+			row = append(row, strings.Join(m.Cell(ctrl, f), "; "))
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (m *ComplianceMatrix) coverage(label, framework string) string {
+	controls := 0
+	frameworkLevel := false
+	for _, c := range m.Claims {
+		if c.Document != label || c.Framework != framework {
+			continue
+		}
+		if c.Control == FrameworkLevel {
+			frameworkLevel = true
+		} else {
+			controls++
+		}
+	}
+	switch {
+	case controls > 0:
+		return fmt.Sprintf("%d", controls)
+	case frameworkLevel:
+		return "✓"
+	}
+	return ""
+}
+
+func prdComplianceClaims(d *prd.Document, doc ComplianceDocument) []ComplianceClaim {
+	if d.SecurityModel == nil {
+		return nil
+	}
+	names := make([]string, 0, len(d.SecurityModel.ComplianceControls))
+	for name := range d.SecurityModel.ComplianceControls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var claims []ComplianceClaim
+	for _, name := range names {
+		controls := d.SecurityModel.ComplianceControls[name]
+		framework := NormalizeFramework(name)
+		if len(controls) == 0 {
+			claims = append(claims, newClaim(framework, FrameworkLevel, doc))
+			continue
+		}
+		for _, ctrl := range controls {
+			if ctrl = strings.TrimSpace(ctrl); ctrl != "" {
+				claims = append(claims, newClaim(framework, ctrl, doc))
+			}
+		}
+	}
+	return claims
+}
+
+func trdComplianceClaims(d *trd.Document, doc ComplianceDocument) []ComplianceClaim {
+	var claims []ComplianceClaim
+	for _, framework := range d.SecurityDesign.Compliance {
+		if strings.TrimSpace(framework) == "" {
+			continue
+		}
+		claims = append(claims, newClaim(NormalizeFramework(framework), FrameworkLevel, doc))
+	}
+	return claims
+}
+
+func newClaim(framework, control string, doc ComplianceDocument) ComplianceClaim {
+	return ComplianceClaim{
+		Framework: framework,
+		Control:   control,
+		Document:  doc.Label,
+		Kind:      doc.Kind,
+		Path:      doc.Path,
+	}
+}
+
+func documentLabel(id, path string) string {
+	if id != "" {
+		return id
+	}
+	return path
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,170 @@
+package workspace
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// WriteHTML renders the workspace as a self-contained HTML dashboard with a
+// sortable table of document health. The page has no external assets.
+func (w *Workspace) WriteHTML(out io.Writer) error {
+	if err := dashboardTemplate.Execute(out, w); err != nil {
+		return fmt.Errorf("rendering dashboard: %w", err)
+	}
+	return nil
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"upper": strings.ToUpper,
+	"score": func(s Summary) string {
+		if !s.HasScore {
+			return "—"
+		}
+		return fmt.Sprintf("%.0f", s.Score)
+	},
+	"scoreSort": func(s Summary) string {
+		if !s.HasScore {
+			return "-1"
+		}
+		return fmt.Sprintf("%.2f", s.Score)
+	},
+	"updated": func(s Summary) string {
+		if s.UpdatedAt.IsZero() {
+			return "—"
+		}
+		return s.UpdatedAt.Format("2006-01-02")
+	},
+	"ageSort": func(s Summary) int {
+		if s.UpdatedAt.IsZero() {
+			return 1 << 30
+		}
+		return s.AgeDays
+	},
+	"gradeClass": func(grade string) string {
+		switch grade {
+		case "A", "B":
+			return "good"
+		case "C":
+			return "warn"
+		case "D", "F":
+			return "bad"
+		}
+		return ""
+	},
+	"kinds":    func() []Kind { return Kinds },
+	"fresh":    func() Freshness { return FreshnessCurrent },
+	"aging":    func() Freshness { return FreshnessAging },
+	"stale":    func() Freshness { return FreshnessStale },
+	"noUpdate": func() Freshness { return FreshnessUnknown },
+}).Parse(dashboardHTML))
+
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Workspace Health Dashboard</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #656d76; margin-bottom: 1.5rem; }
+.cards { display: flex; flex-wrap: wrap; gap: 0.75rem; margin-bottom: 1.5rem; }
+.card { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.5rem 1rem; min-width: 6rem; }
+.card .n { font-size: 1.5rem; font-weight: 600; }
+.card .l { color: #656d76; font-size: 0.85rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 0.4rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; white-space: nowrap; }
+th[aria-sort="ascending"]::after { content: " \25B2"; }
+th[aria-sort="descending"]::after { content: " \25BC"; }
+td.num { text-align: right; }
+.path { color: #656d76; font-size: 0.8rem; }
+.good { color: #1a7f37; font-weight: 600; }
+.warn { color: #9a6700; font-weight: 600; }
+.bad { color: #cf222e; font-weight: 600; }
+.badge { border-radius: 1rem; padding: 0.1rem 0.5rem; font-size: 0.8rem; }
+.badge.current { background: #dafbe1; }
+.badge.aging { background: #fff8c5; }
+.badge.stale { background: #ffebe9; }
+.badge.unknown { background: #eaeef2; }
+.error { color: #cf222e; font-size: 0.8rem; }
+</style>
+</head>
+<body>
+<h1>Workspace Health Dashboard</h1>
+<div class="meta">{{.Root}} &middot; {{len .Documents}} documents &middot; generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</div>
+
+<div class="cards">
+{{- range kinds}}{{$n := $.Count .}}{{if $n}}
+<div class="card"><div class="n">{{$n}}</div><div class="l">{{upper (printf "%s" .)}}</div></div>
+{{- end}}{{end}}
+<div class="card"><div class="n good">{{.CountFreshness fresh}}</div><div class="l">Current</div></div>
+<div class="card"><div class="n warn">{{.CountFreshness aging}}</div><div class="l">Aging</div></div>
+<div class="card"><div class="n bad">{{.CountFreshness stale}}</div><div class="l">Stale</div></div>
+</div>
+
+<table id="documents">
+<thead>
+<tr>
+<th data-type="text">Document</th>
+<th data-type="text">Type</th>
+<th data-type="text">Status</th>
+<th data-type="number">Score</th>
+<th data-type="text">Grade</th>
+<th data-type="text">Decision</th>
+<th data-type="number">Updated</th>
+<th data-type="text">Freshness</th>
+<th data-type="number">Open Items</th>
+<th data-type="number">Open Risks</th>
+<th data-type="number">Roadmap</th>
+</tr>
+</thead>
+<tbody>
+{{- range .Documents}}
+<tr>
+<td data-sort="{{.Title}}">{{if .Title}}{{.Title}}{{else}}{{.Path}}{{end}}{{if .ID}} ({{.ID}}){{end}}<div class="path">{{.Path}}</div>{{if .Error}}<div class="error">{{.Error}}</div>{{end}}</td>
+<td>{{upper (printf "%s" .Kind)}}</td>
+<td>{{.Status}}</td>
+<td class="num" data-sort="{{scoreSort .}}">{{score .}}</td>
+<td class="{{gradeClass .Grade}}">{{.Grade}}</td>
+<td>{{.Decision}}</td>
+<td data-sort="{{ageSort .}}">{{updated .}}{{if not .UpdatedAt.IsZero}} <span class="path">({{.AgeDays}}d)</span>{{end}}</td>
+<td><span class="badge {{.Freshness}}">{{.Freshness}}</span></td>
+<td class="num">{{.OpenItems}}</td>
+<td class="num">{{.OpenRisks}}</td>
+<td data-sort="{{if .Roadmap.Total}}{{printf "%.2f" .Roadmap.PercentComplete}}{{else}}-1{{end}}">{{.Roadmap}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+
+<script>
+(function () {
+  var table = document.getElementById("documents");
+  var headers = table.tHead.rows[0].cells;
+  function value(row, i, type) {
+    var cell = row.cells[i];
+    var v = cell.hasAttribute("data-sort") ? cell.getAttribute("data-sort") : cell.textContent.trim();
+    return type === "number" ? parseFloat(v) || 0 : v.toLowerCase();
+  }
+  Array.prototype.forEach.call(headers, function (th, i) {
+    th.addEventListener("click", function () {
+      var asc = th.getAttribute("aria-sort") !== "ascending";
+      var type = th.getAttribute("data-type");
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = value(a, i, type), y = value(b, i, type);
+        return (x < y ? -1 : x > y ? 1 : 0) * (asc ? 1 : -1);
+      });
+      Array.prototype.forEach.call(headers, function (h) { h.removeAttribute("aria-sort"); });
+      th.setAttribute("aria-sort", asc ? "ascending" : "descending");
+      rows.forEach(function (r) { body.appendChild(r); });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`
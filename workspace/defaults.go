@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"path/filepath"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/directory"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+// Defaults are metadata shared by the PRDs, MRDs, and TRDs of a workspace.
+// A document inherits each default it leaves empty; a value set in the
+// document replaces the default rather than adding to it.
+type Defaults struct {
+	Authors []common.Person `json:"authors,omitempty"`
+	Team    string          `json:"team,omitempty"`
+	Tags    []string        `json:"tags,omitempty"`
+
+	// Fonts are used by the generate commands unless set with flags.
+	Fonts Fonts `json:"fonts,omitempty"`
+
+	// ComplianceFrameworks are claimed by a PRD security model without
+	// compliance controls and by a TRD security design without compliance
+	// frameworks. MRDs have no compliance section.
+	ComplianceFrameworks []string `json:"complianceFrameworks,omitempty"`
+}
+
+// Fonts are the Pandoc fonts of generated Markdown.
+type Fonts struct {
+	MainFont   string `json:"mainFont,omitempty"`
+	SansFont   string `json:"sansFont,omitempty"`
+	MonoFont   string `json:"monoFont,omitempty"`
+	FontFamily string `json:"fontFamily,omitempty"`
+}
+
+// Apply fills the empty metadata of doc, a *prd.Document, *mrd.Document, or
+// *trd.Document, from the defaults. Other documents are left unchanged.
+func (d *Defaults) Apply(doc any) {
+	if d == nil {
+		return
+	}
+	switch doc := doc.(type) {
+	case *prd.Document:
+		d.applyMetadata(&doc.Metadata.Authors, &doc.Metadata.Team, &doc.Metadata.Tags)
+		if sm := doc.SecurityModel; sm != nil && len(sm.ComplianceControls) == 0 && len(d.ComplianceFrameworks) > 0 {
+			sm.ComplianceControls = make(map[string][]string, len(d.ComplianceFrameworks))
+			for _, f := range d.ComplianceFrameworks {
+				sm.ComplianceControls[f] = nil
+			}
+		}
+	case *mrd.Document:
+		d.applyMetadata(&doc.Metadata.Authors, &doc.Metadata.Team, &doc.Metadata.Tags)
+	case *trd.Document:
+		d.applyMetadata(&doc.Metadata.Authors, &doc.Metadata.Team, &doc.Metadata.Tags)
+		if len(doc.SecurityDesign.Compliance) == 0 && len(d.ComplianceFrameworks) > 0 {
+			doc.SecurityDesign.Compliance = append([]string(nil), d.ComplianceFrameworks...)
+		}
+	}
+}
+
+func (d *Defaults) applyMetadata(authors *[]common.Person, team *string, tags *[]string) {
+	if len(*authors) == 0 && len(d.Authors) > 0 {
+		*authors = append([]common.Person(nil), d.Authors...)
+	}
+	if *team == "" {
+		*team = d.Team
+	}
+	if len(*tags) == 0 && len(d.Tags) > 0 {
+		*tags = append([]string(nil), d.Tags...)
+	}
+}
+
+// DefaultsFor returns the defaults of the workspace manifest found from the
+// directory of the document at path, or nil if there is no manifest or it
+// sets no defaults.
+func DefaultsFor(path string) (*Defaults, error) {
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.Defaults, nil
+}
+
+// ApplyDefaults fills the empty metadata of doc, read from path, from the
+// defaults of its workspace manifest. If the manifest sets a people
+// directory, the authors, reviewers, and approvers of PRDs, MRDs, and TRDs
+// and the authors of roadmaps are then enriched from it.
+func ApplyDefaults(path string, doc any) error {
+	switch doc.(type) {
+	case *prd.Document, *mrd.Document, *trd.Document, *roadmap.Document:
+	default:
+		return nil
+	}
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil {
+		return err
+	}
+	m.Defaults.Apply(doc)
+	dir, err := m.OpenDirectory()
+	if err != nil {
+		return err
+	}
+	enrich(dir, doc)
+	return nil
+}
+
+// enrich fills the email and role of the people named in doc's metadata.
+func enrich(dir *directory.Directory, doc any) {
+	if dir == nil {
+		return
+	}
+	switch doc := doc.(type) {
+	case *prd.Document:
+		enrichMetadata(dir, doc.Metadata.Authors, doc.Metadata.Reviewers, doc.Metadata.Approvers)
+	case *mrd.Document:
+		enrichMetadata(dir, doc.Metadata.Authors, doc.Metadata.Reviewers, doc.Metadata.Approvers)
+	case *trd.Document:
+		enrichMetadata(dir, doc.Metadata.Authors, doc.Metadata.Reviewers, doc.Metadata.Approvers)
+	case *roadmap.Document:
+		dir.EnrichAll(doc.Metadata.Authors)
+	}
+}
+
+func enrichMetadata(dir *directory.Directory, authors, reviewers []common.Person, approvers []common.Approver) {
+	dir.EnrichAll(authors)
+	dir.EnrichAll(reviewers)
+	dir.EnrichApprovers(approvers)
+}
@@ -0,0 +1,245 @@
+package workspace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/roadmap"
+)
+
+// UnassignedTeam is the team name used for dependencies with no team or
+// owner.
+const UnassignedTeam = "(unassigned)"
+
+// DependencyEntry is an external dependency declared by one document.
+type DependencyEntry struct {
+	common.Dependency
+
+	Document string `json:"document"`
+	Kind     Kind   `json:"kind"`
+	Path     string `json:"path"`
+
+	// Phase is the name of the NeededBy phase and PhaseStart its start date
+	// (YYYY-MM-DD), when the declaring document has a roadmap.
+	Phase      string `json:"phase,omitempty"`
+	PhaseStart string `json:"phaseStart,omitempty"`
+
+	// Issues are schedule problems found by validating the dependency
+	// against the document's roadmap.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// TeamName returns the owning team, falling back to the owner and then
+// UnassignedTeam.
+func (e DependencyEntry) TeamName() string {
+	switch {
+	case e.Team != "":
+		return e.Team
+	case e.Owner != "":
+		return e.Owner
+	}
+	return UnassignedTeam
+}
+
+// DependencyReport lists external dependencies across documents, grouped by
+// owning team, so that teams can see everything asked of them.
+type DependencyReport struct {
+	Teams   []string          `json:"teams"`
+	Entries []DependencyEntry `json:"entries"`
+}
+
+// BuildDependencyReport loads the PRD and TRD documents at paths and collects
+// their external dependencies: PRD assumptions.dependencies and TRD
+// dependencies. PRD dependencies are validated against the PRD roadmap.
+// Other document kinds are ignored.
+func BuildDependencyReport(paths []string, opts Options) (*DependencyReport, error) {
+	var entries []DependencyEntry
+	for _, path := range paths {
+		kind, ok := KindFromPath(path)
+		if !ok {
+			continue
+		}
+		switch kind {
+		case KindPRD:
+			var d prd.Document
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			if d.Assumptions != nil {
+				entries = append(entries, prdDependencies(&d, documentLabel(d.Metadata.ID, path), path)...)
+			}
+		case KindTRD:
+			var d trd.Document
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			label := documentLabel(d.Metadata.ID, path)
+			for _, dep := range d.Dependencies {
+				entries = append(entries, DependencyEntry{Dependency: dep, Document: label, Kind: kind, Path: path})
+			}
+		}
+	}
+	return NewDependencyReport(entries), nil
+}
+
+// NewDependencyReport builds a report from entries. Teams are sorted by name
+// with UnassignedTeam last; entries are ordered by team, then by the date
+// they are needed.
+func NewDependencyReport(entries []DependencyEntry) *DependencyReport {
+	r := &DependencyReport{Entries: entries}
+	teams := map[string]bool{}
+	for _, e := range entries {
+		teams[e.TeamName()] = true
+	}
+	for t := range teams {
+		if t != UnassignedTeam {
+			r.Teams = append(r.Teams, t)
+		}
+	}
+	sort.Strings(r.Teams)
+	if teams[UnassignedTeam] {
+		r.Teams = append(r.Teams, UnassignedTeam)
+	}
+
+	rank := make(map[string]int, len(r.Teams))
+	for i, t := range r.Teams {
+		rank[t] = i
+	}
+	sort.SliceStable(r.Entries, func(i, j int) bool {
+		a, b := r.Entries[i], r.Entries[j]
+		if ra, rb := rank[a.TeamName()], rank[b.TeamName()]; ra != rb {
+			return ra < rb
+		}
+		return neededDate(a) < neededDate(b)
+	})
+	return r
+}
+
+// ForTeam returns the entries owned by team.
+func (r *DependencyReport) ForTeam(team string) []DependencyEntry {
+	var entries []DependencyEntry
+	for _, e := range r.Entries {
+		if e.TeamName() == team {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// AtRisk returns the number of dependencies that are blocked, at risk, or
+// have schedule issues.
+func (r *DependencyReport) AtRisk() int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Status.IsAtRisk() || len(e.Issues) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// ToMarkdown renders the report as markdown with one table per team.
+func (r *DependencyReport) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# External Dependencies\n\n")
+
+	if len(r.Entries) == 0 {
+		sb.WriteString("*No external dependencies are declared by the scanned documents.*\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%d dependencies on %d teams; %d need attention.\n\n", len(r.Entries), len(r.Teams), r.AtRisk()))
+	for _, team := range r.Teams {
+		sb.WriteString("## " + escapeCell(team) + "\n\n")
+		sb.WriteString("| Document | ID | Deliverable | Needed By | Due | Status | Contact | Issues |\n")
+		sb.WriteString("|----------|----|-------------|-----------|-----|--------|---------|--------|\n")
+		for _, e := range r.ForTeam(team) {
+			deliverable := e.Deliverable
+			if deliverable == "" {
+				deliverable = e.Name
+			}
+			status := string(e.Status.Normalize())
+			if e.Status.IsAtRisk() {
+				status = "**" + status + "**"
+			}
+			cells := []string{e.Document, e.ID, deliverable, neededByLabel(e), e.DueDate, status, e.Contact, strings.Join(e.Issues, "; ")}
+			for i, c := range cells {
+				cells[i] = escapeCell(c)
+			}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// WriteCSV writes one row per dependency.
+func (r *DependencyReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Team", "Document", "ID", "Name", "Deliverable", "Needed By", "Phase Start", "Due", "Status", "Contact", "Issues"}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+	for _, e := range r.Entries {
+		row := []string{e.TeamName(), e.Document, e.ID, e.Name, e.Deliverable, e.NeededBy, e.PhaseStart,
+			e.DueDate, string(e.Status.Normalize()), e.Contact, strings.Join(e.Issues, "; ")}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func prdDependencies(d *prd.Document, label, path string) []DependencyEntry {
+	deps := d.Assumptions.Dependencies
+	phases := make(map[string]*roadmap.Phase, len(d.Roadmap.Phases))
+	for i := range d.Roadmap.Phases {
+		phases[d.Roadmap.Phases[i].ID] = &d.Roadmap.Phases[i]
+	}
+
+	entries := make([]DependencyEntry, 0, len(deps))
+	for i, dep := range deps {
+		e := DependencyEntry{Dependency: dep, Document: label, Kind: KindPRD, Path: path}
+		for _, issue := range d.Roadmap.ValidateDependencies(deps[i : i+1]) {
+			e.Issues = append(e.Issues, issue.Message)
+		}
+		if p, ok := phases[dep.NeededBy]; ok {
+			e.Phase = p.Name
+			if p.StartDate != nil {
+				e.PhaseStart = p.StartDate.Format("2006-01-02")
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// neededDate returns the date an entry is needed, for sorting. Entries with
+// no date sort last.
+func neededDate(e DependencyEntry) string {
+	switch {
+	case e.PhaseStart != "":
+		return e.PhaseStart
+	case e.DueDate != "":
+		return e.DueDate
+	}
+	return "9999"
+}
+
+func neededByLabel(e DependencyEntry) string {
+	label := e.NeededBy
+	if e.Phase != "" {
+		label = e.Phase
+	}
+	if e.PhaseStart != "" {
+		label += " (" + e.PhaseStart + ")"
+	}
+	return label
+}
@@ -0,0 +1,44 @@
+package workspace
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/grokify/structured-plan/directory"
+)
+
+var (
+	directoriesMu sync.Mutex
+	directories   = map[string]*directory.Directory{}
+)
+
+// DirectoryFor returns the people directory of the workspace manifest found
+// from the directory of the document at path, or nil if there is no
+// manifest or it sets no directory. A directory is loaded once per manifest,
+// so a directory command runs once however many documents are read.
+func DirectoryFor(path string) (*directory.Directory, error) {
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil || m.Directory == nil {
+		return nil, err
+	}
+	return m.OpenDirectory()
+}
+
+// OpenDirectory loads the manifest's people directory, or returns nil if it
+// sets none.
+func (m *Manifest) OpenDirectory() (*directory.Directory, error) {
+	if m.Directory == nil {
+		return nil, nil
+	}
+	directoriesMu.Lock()
+	defer directoriesMu.Unlock()
+	if d, ok := directories[m.Dir]; ok {
+		return d, nil
+	}
+	d, err := directory.Open(*m.Directory, m.Dir)
+	if err != nil {
+		return nil, err
+	}
+	directories[m.Dir] = d
+	return d, nil
+}
@@ -0,0 +1,374 @@
+package workspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// Goal levels compared for duplicates. V2MOM methods are treated as
+// objectives and their measures as key results.
+const (
+	GoalObjective = "objective"
+	GoalKeyResult = "key result"
+)
+
+// DefaultSimilarityThreshold is the title similarity, from 0 to 1, at or
+// above which two goals are reported as possible duplicates.
+const DefaultSimilarityThreshold = 0.8
+
+// GoalEntry is an objective or key result declared by one document.
+type GoalEntry struct {
+	Level string `json:"level"`
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+
+	// ObjectiveID is the objective's own ID, or the parent objective's ID
+	// for a key result; Objective is the parent objective's title.
+	ObjectiveID string `json:"objectiveId,omitempty"`
+	Objective   string `json:"objective,omitempty"`
+
+	// Links are the objective IDs the objective, or a key result's parent
+	// objective, already aligns with through parentId or alignedWith.
+	Links []string `json:"links,omitempty"`
+
+	Document string `json:"document"`
+	Kind     Kind   `json:"kind"`
+	Path     string `json:"path"`
+}
+
+// GoalDuplicate is a pair of goals in different documents that share an ID
+// or have highly similar titles.
+type GoalDuplicate struct {
+	Level string `json:"level"`
+
+	// Canonical is the goal to keep: the one in an OKR document, then a
+	// V2MOM, then a PRD. Duplicate is the one to replace with a link.
+	Canonical GoalEntry `json:"canonical"`
+	Duplicate GoalEntry `json:"duplicate"`
+
+	SameID     bool    `json:"sameId,omitempty"`
+	Similarity float64 `json:"similarity"`
+	Suggestion string  `json:"suggestion"`
+}
+
+// GoalDuplicateReport lists possible duplicate goals across documents.
+type GoalDuplicateReport struct {
+	Goals      int             `json:"goals"` // Objectives and key results compared
+	Documents  int             `json:"documents"`
+	Threshold  float64         `json:"threshold"`
+	Duplicates []GoalDuplicate `json:"duplicates"`
+}
+
+// BuildGoalDuplicateReport loads the PRD, OKR, and V2MOM documents at paths
+// and reports objectives and key results that appear to be defined more
+// than once. Other document kinds are ignored.
+func BuildGoalDuplicateReport(paths []string, opts Options, threshold float64) (*GoalDuplicateReport, error) {
+	entries, err := CollectGoals(paths, opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewGoalDuplicateReport(entries, threshold), nil
+}
+
+// CollectGoals loads the objectives and key results of the PRD, OKR, and
+// V2MOM documents at paths.
+func CollectGoals(paths []string, opts Options) ([]GoalEntry, error) {
+	var entries []GoalEntry
+	for _, path := range paths {
+		kind, ok := KindFromPath(path)
+		if !ok {
+			continue
+		}
+		switch kind {
+		case KindPRD:
+			var d prd.Document
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			label := documentLabel(d.Metadata.ID, path)
+			for _, o := range d.Objectives.OKRs {
+				krs := o.KeyResults
+				if len(krs) == 0 {
+					krs = o.Objective.KeyResults
+				}
+				entries = append(entries, objectiveGoals(o.Objective, krs, label, kind, path)...)
+			}
+		case KindOKR:
+			var d okr.OKRDocument
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			id := ""
+			if d.Metadata != nil {
+				id = d.Metadata.ID
+			}
+			label := documentLabel(id, path)
+			for _, o := range d.Objectives {
+				entries = append(entries, objectiveGoals(o, o.KeyResults, label, kind, path)...)
+			}
+		case KindV2MOM:
+			var d v2mom.V2MOM
+			if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+				return nil, fmt.Errorf("loading %s: %w", path, err)
+			}
+			id := ""
+			if d.Metadata != nil {
+				id = d.Metadata.ID
+			}
+			label := documentLabel(id, path)
+			for _, m := range d.Methods {
+				entries = append(entries, GoalEntry{Level: GoalObjective, ID: m.ID, Title: m.Name, ObjectiveID: m.ID,
+					Document: label, Kind: kind, Path: path})
+				for _, ms := range m.Measures {
+					entries = append(entries, GoalEntry{Level: GoalKeyResult, ID: ms.ID, Title: ms.Name, ObjectiveID: m.ID,
+						Objective: m.Name, Document: label, Kind: kind, Path: path})
+				}
+			}
+			for _, ms := range d.Measures {
+				entries = append(entries, GoalEntry{Level: GoalKeyResult, ID: ms.ID, Title: ms.Name,
+					Document: label, Kind: kind, Path: path})
+			}
+		}
+	}
+	return entries, nil
+}
+
+func objectiveGoals(o okr.Objective, krs []okr.KeyResult, label string, kind Kind, path string) []GoalEntry {
+	var links []string
+	if o.ParentID != "" {
+		links = append(links, o.ParentID)
+	}
+	links = append(links, o.AlignedWith...)
+
+	entries := []GoalEntry{{Level: GoalObjective, ID: o.ID, Title: o.Title, ObjectiveID: o.ID, Links: links,
+		Document: label, Kind: kind, Path: path}}
+	for _, kr := range krs {
+		entries = append(entries, GoalEntry{Level: GoalKeyResult, ID: kr.ID, Title: kr.Title, ObjectiveID: o.ID,
+			Objective: o.Title, Links: links, Document: label, Kind: kind, Path: path})
+	}
+	return entries
+}
+
+// NewGoalDuplicateReport compares every pair of same-level goals from
+// different documents. A pair is reported if the goals share an ID or
+// their title similarity is at least threshold, unless the goals, or the
+// objectives of key results, are already aligned with each other. A
+// threshold of zero uses DefaultSimilarityThreshold.
+func NewGoalDuplicateReport(entries []GoalEntry, threshold float64) *GoalDuplicateReport {
+	if threshold <= 0 {
+		threshold = DefaultSimilarityThreshold
+	}
+	r := &GoalDuplicateReport{Goals: len(entries), Threshold: threshold, Duplicates: []GoalDuplicate{}}
+	docs := map[string]bool{}
+	for _, e := range entries {
+		docs[e.Path] = true
+	}
+	r.Documents = len(docs)
+
+	for i := 0; i < len(entries); i++ {
+		for j := i + 1; j < len(entries); j++ {
+			a, b := entries[i], entries[j]
+			if a.Level != b.Level || a.Path == b.Path || aligned(a, b) {
+				continue
+			}
+			sameID := a.ID != "" && strings.EqualFold(a.ID, b.ID)
+			sim := TitleSimilarity(a.Title, b.Title)
+			if !sameID && sim < threshold {
+				continue
+			}
+			if kindRank(b.Kind) < kindRank(a.Kind) {
+				a, b = b, a
+			}
+			d := GoalDuplicate{Level: a.Level, Canonical: a, Duplicate: b, SameID: sameID, Similarity: sim}
+			d.Suggestion = d.suggest(threshold)
+			r.Duplicates = append(r.Duplicates, d)
+		}
+	}
+
+	sort.SliceStable(r.Duplicates, func(i, j int) bool {
+		a, b := r.Duplicates[i], r.Duplicates[j]
+		if a.Level != b.Level {
+			return a.Level == GoalObjective
+		}
+		return a.Similarity > b.Similarity
+	})
+	return r
+}
+
+// ForLevel returns the duplicates at level.
+func (r *GoalDuplicateReport) ForLevel(level string) []GoalDuplicate {
+	var dups []GoalDuplicate
+	for _, d := range r.Duplicates {
+		if d.Level == level {
+			dups = append(dups, d)
+		}
+	}
+	return dups
+}
+
+// ToMarkdown renders the report as markdown with one table per level.
+func (r *GoalDuplicateReport) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Duplicate Goals\n\n")
+
+	if len(r.Duplicates) == 0 {
+		sb.WriteString(fmt.Sprintf("*No duplicates found among %d objectives and key results in %d documents.*\n", r.Goals, r.Documents))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%d possible duplicates among %d objectives and key results in %d documents (title similarity %.0f%% or more, or a shared ID).\n\n",
+		len(r.Duplicates), r.Goals, r.Documents, r.Threshold*100))
+	for _, section := range []struct{ level, heading string }{
+		{GoalObjective, "Objectives"},
+		{GoalKeyResult, "Key Results"},
+	} {
+		dups := r.ForLevel(section.level)
+		if len(dups) == 0 {
+			continue
+		}
+		sb.WriteString("## " + section.heading + "\n\n")
+		sb.WriteString("| Keep | Document | Duplicate | Document | Match | Suggestion |\n")
+		sb.WriteString("|------|----------|-----------|----------|-------|------------|\n")
+		for _, d := range dups {
+			cells := []string{goalLabel(d.Canonical), d.Canonical.Document, goalLabel(d.Duplicate), d.Duplicate.Document, d.matchLabel(), d.Suggestion}
+			for i, c := range cells {
+				cells[i] = escapeCell(c)
+			}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (d GoalDuplicate) matchLabel() string {
+	pct := fmt.Sprintf("%.0f%% similar", d.Similarity*100)
+	if d.SameID {
+		return "same ID, " + pct
+	}
+	return pct
+}
+
+func (d GoalDuplicate) suggest(threshold float64) string {
+	c, dup := d.Canonical, d.Duplicate
+	if d.SameID && d.Similarity < threshold {
+		return fmt.Sprintf("ID %s names different goals; rename one so alignment links are unambiguous", c.ID)
+	}
+	if d.Level == GoalKeyResult {
+		if c.ObjectiveID == "" || dup.Kind == KindV2MOM {
+			return fmt.Sprintf("Track this key result in %s only and link the objectives in %s to it", c.Document, dup.Document)
+		}
+		return fmt.Sprintf("Track this key result in %s only; in %s, add alignedWith: [%q] to %q instead", c.Document, dup.Document, c.ObjectiveID, dup.Objective)
+	}
+	switch {
+	case c.ID == "":
+		return fmt.Sprintf("Give the objective in %s an ID, then link the copy in %s to it", c.Document, dup.Document)
+	case dup.Kind == KindV2MOM:
+		return fmt.Sprintf("Keep the objective in %s and reference %s from the method in %s", c.Document, c.ID, dup.Document)
+	}
+	return fmt.Sprintf("Keep the objective in %s; in %s, replace the copy with alignedWith: [%q]", c.Document, dup.Document, c.ID)
+}
+
+func goalLabel(e GoalEntry) string {
+	if e.ID == "" {
+		return e.Title
+	}
+	return e.ID + ": " + e.Title
+}
+
+// aligned reports whether either goal's objective already links to the
+// other's.
+func aligned(a, b GoalEntry) bool {
+	linked := func(links []string, id string) bool {
+		if id == "" {
+			return false
+		}
+		for _, l := range links {
+			if strings.EqualFold(l, id) {
+				return true
+			}
+		}
+		return false
+	}
+	return linked(a.Links, b.ObjectiveID) || linked(b.Links, a.ObjectiveID)
+}
+
+// kindRank orders document kinds by preference as the home of a shared goal:
+// dedicated goal documents before PRDs.
+func kindRank(k Kind) int {
+	switch k {
+	case KindOKR:
+		return 0
+	case KindV2MOM:
+		return 1
+	case KindPRD:
+		return 2
+	}
+	return 3
+}
+
+// titleStopWords are ignored when comparing titles.
+var titleStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "by": true, "for": true, "in": true,
+	"of": true, "on": true, "the": true, "to": true, "with": true,
+}
+
+// TitleSimilarity returns the Sørensen–Dice coefficient of the character
+// bigrams of two titles, from 0 (nothing in common) to 1 (identical). Case,
+// punctuation, and common stop words are ignored.
+func TitleSimilarity(a, b string) float64 {
+	na, nb := normalizeTitle(a), normalizeTitle(b)
+	if na == nb {
+		if na == "" {
+			return 0
+		}
+		return 1
+	}
+	ba, bb := bigrams(na), bigrams(nb)
+	if len(ba) == 0 || len(bb) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(ba))
+	for _, g := range ba {
+		counts[g]++
+	}
+	shared := 0
+	for _, g := range bb {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ba)+len(bb))
+}
+
+func normalizeTitle(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, w := range words {
+		if !titleStopWords[w] {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return nil
+	}
+	grams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		grams = append(grams, string(runes[i:i+2]))
+	}
+	return grams
+}
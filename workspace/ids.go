@@ -0,0 +1,67 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/grokify/structured-plan/ids"
+	"github.com/grokify/structured-plan/query"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+var (
+	definedIDsMu sync.Mutex
+	definedIDs   = map[string]map[string]bool{}
+)
+
+// IDsFor returns the IDs defined in the workspace of the manifest found
+// from the directory of the document at path, or nil if there is no
+// manifest. The IDs are collected once per manifest.
+func IDsFor(path string) (map[string]bool, error) {
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.DefinedIDs()
+}
+
+// DefinedIDs returns the IDs defined in the documents under the manifest's
+// directory and the documents it lists, such as the objectives other
+// documents align with. Documents that cannot be read or parsed are
+// skipped; validating them reports the problem.
+func (m *Manifest) DefinedIDs() (map[string]bool, error) {
+	definedIDsMu.Lock()
+	defer definedIDsMu.Unlock()
+	if known, ok := definedIDs[m.Dir]; ok {
+		return known, nil
+	}
+
+	paths, err := Discover(m.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range m.Documents {
+		paths = append(paths, m.Source(d))
+	}
+
+	known := map[string]bool{}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		if data, err = yamlconv.ToJSON(data); err != nil {
+			continue
+		}
+		root, err := query.Decode(data)
+		if err != nil {
+			continue
+		}
+		for _, d := range ids.Definitions(root) {
+			known[d.ID] = true
+		}
+	}
+	definedIDs[m.Dir] = known
+	return known, nil
+}
@@ -0,0 +1,202 @@
+package workspace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// Risks a technology is listed under in a LicenseReport.
+const (
+	RiskCopyleft       = "copyleft"
+	RiskSingleVendor   = "single-vendor"
+	RiskUnknownLicense = "unknown-license"
+)
+
+// TechnologyUse is a technology chosen in one TRD's technology stack.
+type TechnologyUse struct {
+	trd.Technology
+
+	// Category is the technology stack list, e.g. "databases", and
+	// LicenseCategory the classification of the license.
+	Category        string `json:"category"`
+	LicenseCategory string `json:"licenseCategory"`
+
+	Document string `json:"document"`
+	Path     string `json:"path"`
+}
+
+// LicenseReport lists the technologies across TRDs that need procurement
+// or legal review: copyleft licenses, dependencies only one vendor can
+// supply, and technologies whose license is missing or not recognized.
+type LicenseReport struct {
+	Documents    int `json:"documents"`    // TRDs scanned
+	Technologies int `json:"technologies"` // Technology stack entries scanned
+
+	Copyleft       []TechnologyUse `json:"copyleft"`
+	SingleVendor   []TechnologyUse `json:"singleVendor"`
+	UnknownLicense []TechnologyUse `json:"unknownLicense"`
+}
+
+// BuildLicenseReport loads the TRDs at paths and classifies the licenses
+// and vendors of their technology stacks. Other document kinds are ignored.
+func BuildLicenseReport(paths []string, opts Options) (*LicenseReport, error) {
+	r := &LicenseReport{}
+	for _, path := range paths {
+		if kind, ok := KindFromPath(path); !ok || kind != KindTRD {
+			continue
+		}
+		var d trd.Document
+		if _, err := decodeFile(path, &d, opts.Lenient); err != nil {
+			return nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+		r.Documents++
+		label := documentLabel(d.Metadata.ID, path)
+		for _, c := range d.TechnologyStack.Categories() {
+			for _, t := range c.Technologies {
+				r.add(TechnologyUse{
+					Technology:      t,
+					Category:        c.Name,
+					LicenseCategory: trd.LicenseCategory(t.License),
+					Document:        label,
+					Path:            path,
+				})
+			}
+		}
+	}
+	return r, nil
+}
+
+func (r *LicenseReport) add(u TechnologyUse) {
+	r.Technologies++
+	switch {
+	case trd.IsCopyleft(u.LicenseCategory):
+		r.Copyleft = append(r.Copyleft, u)
+	case u.SingleVendor():
+		r.SingleVendor = append(r.SingleVendor, u)
+	case u.LicenseCategory == trd.LicenseUnknown:
+		r.UnknownLicense = append(r.UnknownLicense, u)
+	}
+}
+
+// licenseRow is a technology with the documents that use it.
+type licenseRow struct {
+	TechnologyUse
+	documents []string
+}
+
+// groupUses merges uses of the same technology version, license, and
+// vendor across documents, sorted by technology name.
+func groupUses(uses []TechnologyUse) []licenseRow {
+	var rows []licenseRow
+	index := map[string]int{}
+	for _, u := range uses {
+		key := strings.ToLower(u.Name + "\x00" + u.Version + "\x00" + u.License + "\x00" + u.Vendor)
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, licenseRow{TechnologyUse: u})
+		}
+		if !slices.Contains(rows[i].documents, u.Document) {
+			rows[i].documents = append(rows[i].documents, u.Document)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return strings.ToLower(rows[i].Name) < strings.ToLower(rows[j].Name)
+	})
+	return rows
+}
+
+// ToMarkdown renders the report as markdown, with one table per risk.
+func (r *LicenseReport) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Technology License and Vendor Review\n\n")
+	fmt.Fprintf(&sb, "Scanned %d technology entries in %d TRDs: %d copyleft, %d single-vendor, %d with unknown licenses.\n\n",
+		r.Technologies, r.Documents, len(groupUses(r.Copyleft)), len(groupUses(r.SingleVendor)), len(groupUses(r.UnknownLicense)))
+
+	sb.WriteString("## Copyleft Licenses\n\n")
+	if rows := groupUses(r.Copyleft); len(rows) == 0 {
+		sb.WriteString("*None.*\n\n")
+	} else {
+		sb.WriteString("*Changes or derived works may have to be released under the same license.*\n\n")
+		sb.WriteString("| Technology | License | Type | Used by |\n")
+		sb.WriteString("|------------|---------|------|---------|\n")
+		for _, row := range rows {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", escapeCell(technologyName(row.Technology)),
+				escapeCell(row.License), row.LicenseCategory, escapeCell(strings.Join(row.documents, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Single-Vendor Dependencies\n\n")
+	if rows := groupUses(r.SingleVendor); len(rows) == 0 {
+		sb.WriteString("*None.*\n\n")
+	} else {
+		sb.WriteString("*Only the vendor can supply or support these; there is no open-source fallback.*\n\n")
+		sb.WriteString("| Technology | Vendor | License | Support | Used by |\n")
+		sb.WriteString("|------------|--------|---------|---------|---------|\n")
+		for _, row := range rows {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", escapeCell(technologyName(row.Technology)),
+				escapeCell(row.Vendor), escapeCell(orNone(row.License)), escapeCell(row.SupportTier),
+				escapeCell(strings.Join(row.documents, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Unknown Licenses\n\n")
+	if rows := groupUses(r.UnknownLicense); len(rows) == 0 {
+		sb.WriteString("*None.*\n")
+	} else {
+		sb.WriteString("*Record an SPDX license identifier so these can be reviewed.*\n\n")
+		sb.WriteString("| Technology | License | Used by |\n")
+		sb.WriteString("|------------|---------|---------|\n")
+		for _, row := range rows {
+			fmt.Fprintf(&sb, "| %s | %s | %s |\n", escapeCell(technologyName(row.Technology)),
+				escapeCell(orNone(row.License)), escapeCell(strings.Join(row.documents, ", ")))
+		}
+	}
+	return sb.String()
+}
+
+// WriteCSV writes one row per flagged technology use, for import into a
+// procurement or legal tracker.
+func (r *LicenseReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Risk", "Technology", "Version", "Category", "License", "License Type", "Vendor", "Support Tier", "Document", "Path"}); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+	for _, group := range []struct {
+		risk string
+		uses []TechnologyUse
+	}{
+		{RiskCopyleft, r.Copyleft},
+		{RiskSingleVendor, r.SingleVendor},
+		{RiskUnknownLicense, r.UnknownLicense},
+	} {
+		for _, u := range group.uses {
+			row := []string{group.risk, u.Name, u.Version, u.Category, u.License, u.LicenseCategory, u.Vendor, u.SupportTier, u.Document, u.Path}
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("writing CSV row: %w", err)
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func technologyName(t trd.Technology) string {
+	return strings.TrimSpace(t.Name + " " + t.Version)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,172 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/structured-plan/calendar"
+	"github.com/grokify/structured-plan/directory"
+	"github.com/grokify/structured-plan/render/profile"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+// ManifestFile is the name of the workspace manifest, which lists the
+// documents of a workspace and where their generated output is written.
+const ManifestFile = "splan.workspace.json"
+
+// Manifest lists the documents of a workspace.
+type Manifest struct {
+	Documents []ManifestDocument `json:"documents"`
+
+	// Defaults are inherited by the PRDs, MRDs, and TRDs in the manifest's
+	// directory and its subdirectories, whether or not they are listed.
+	Defaults *Defaults `json:"defaults,omitempty"`
+
+	// TechnologyPolicy is checked against the technology stacks of the
+	// TRDs in the manifest's directory and its subdirectories.
+	TechnologyPolicy *trd.TechnologyPolicy `json:"technologyPolicy,omitempty"`
+
+	// Planning is the fiscal year and planning cycle used by the planning
+	// calendar.
+	Planning *calendar.Config `json:"planning,omitempty"`
+
+	// Directory is the people directory that document owners and authors
+	// are checked against and enriched from.
+	Directory *directory.Config `json:"directory,omitempty"`
+
+	// Shared are files the documents rely on that are not documents
+	// themselves, such as a glossary, relative to the manifest. They are
+	// included when the workspace is exported, along with any persona
+	// library (personas.json) and lint configuration (.splan.yaml).
+	Shared []string `json:"shared,omitempty"`
+
+	// Snippets are snippet library files, relative to the manifest, whose
+	// snippets the documents of the workspace reference by ID.
+	Snippets []string `json:"snippets,omitempty"`
+
+	// RenderProfiles are named render settings for generating documents of
+	// the workspace for an audience, selected with --profile. They replace
+	// built-in profiles of the same name.
+	RenderProfiles map[string]profile.Profile `json:"renderProfiles,omitempty"`
+
+	// Dir is the directory containing the manifest. Document paths are
+	// relative to it.
+	Dir string `json:"-"`
+}
+
+// ManifestDocument is a document listed in a manifest.
+type ManifestDocument struct {
+	// Path is the source document, such as "checkout.prd.json".
+	Path string `json:"path"`
+
+	// Kind is the document type. It defaults to the type in the filename.
+	Kind Kind `json:"type,omitempty"`
+
+	// Markdown and HTML are the generated outputs. They default to Path
+	// with a .md or .html extension, as written by the generate commands.
+	Markdown string `json:"markdown,omitempty"`
+	HTML     string `json:"html,omitempty"`
+}
+
+// LoadManifest reads the manifest at file.
+func LoadManifest(file string) (*Manifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", file, err)
+	}
+	m.Dir = filepath.Dir(file)
+	for i, d := range m.Documents {
+		if d.Path == "" {
+			return nil, fmt.Errorf("manifest %s: documents[%d] has no path", file, i)
+		}
+		if d.Kind == "" {
+			k, ok := KindFromPath(d.Path)
+			if !ok {
+				return nil, fmt.Errorf("manifest %s: cannot infer the type of %s (set \"type\")", file, d.Path)
+			}
+			m.Documents[i].Kind = k
+		}
+	}
+	return &m, nil
+}
+
+// FindManifest looks for a manifest in dir and its parent directories, as
+// go.mod is found. It returns nil if there is none.
+func FindManifest(dir string) (*Manifest, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		file := filepath.Join(dir, ManifestFile)
+		if _, err := os.Stat(file); err == nil {
+			return LoadManifest(file)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// TechnologyPolicyFor returns the technology policy of the workspace
+// manifest found from the directory of the document at path, or nil if
+// there is no manifest or it sets no policy.
+func TechnologyPolicyFor(path string) (*trd.TechnologyPolicy, error) {
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.TechnologyPolicy, nil
+}
+
+// RenderProfilesFor returns the render profiles of the workspace manifest
+// found from the directory of the document at path, or nil if there is no
+// manifest or it defines none.
+func RenderProfilesFor(path string) (map[string]profile.Profile, error) {
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.RenderProfiles, nil
+}
+
+// Source returns the path of the document's source file.
+func (m *Manifest) Source(d ManifestDocument) string {
+	return filepath.Join(m.Dir, d.Path)
+}
+
+// MarkdownOutput returns the path of the document's generated Markdown.
+func (m *Manifest) MarkdownOutput(d ManifestDocument) string {
+	if d.Markdown != "" {
+		return filepath.Join(m.Dir, d.Markdown)
+	}
+	return withExt(m.Source(d), ".md")
+}
+
+// HTMLOutput returns the path of the document's generated HTML page.
+func (m *Manifest) HTMLOutput(d ManifestDocument) string {
+	if d.HTML != "" {
+		return filepath.Join(m.Dir, d.HTML)
+	}
+	return withExt(m.Source(d), ".html")
+}
+
+func withExt(path, ext string) string {
+	switch old := filepath.Ext(path); strings.ToLower(old) {
+	case ".json", ".yaml", ".yml":
+		return strings.TrimSuffix(path, old) + ext
+	}
+	return path + ext
+}
@@ -0,0 +1,271 @@
+package workspace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/requirements/prd"
+)
+
+// DefaultPortfolioGaps is the number of gaps listed per product.
+const DefaultPortfolioGaps = 3
+
+// gapThreshold is the category score below which a category counts as a
+// gap. It matches the score at which PRD scoring raises revision triggers.
+const gapThreshold = 7.0
+
+// Gap is a weak PRD scoring category.
+type Gap struct {
+	Category      string  `json:"category"`
+	Score         float64 `json:"score"`
+	Justification string  `json:"justification"`
+}
+
+// PortfolioEntry is one PRD's place in a portfolio report.
+type PortfolioEntry struct {
+	// Rank is the 1-based position by weighted score; 0 for documents that
+	// could not be loaded.
+	Rank  int    `json:"rank"`
+	Path  string `json:"path"`
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title,omitempty"`
+
+	// WeightedScore is the 0-10 PRD quality score; Grade is its letter
+	// grade on the same scale as completeness grades.
+	WeightedScore float64 `json:"weightedScore"`
+	Grade         string  `json:"grade,omitempty"`
+	Decision      string  `json:"decision,omitempty"`
+	Blockers      int     `json:"blockers"`
+
+	// Gaps are the lowest-scoring categories below 7, weakest first.
+	Gaps []Gap `json:"gaps,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Portfolio ranks the PRDs of a workspace by quality score.
+type Portfolio struct {
+	Root        string           `json:"root"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+	Entries     []PortfolioEntry `json:"entries"`
+}
+
+// BuildPortfolio scores the PRDs at paths and ranks them by weighted score,
+// highest first. Other document kinds are ignored. Documents that cannot be
+// loaded are listed last with their error instead of failing the report.
+// gaps limits the gaps listed per product; 0 uses DefaultPortfolioGaps.
+func BuildPortfolio(root string, paths []string, gaps int, opts Options) *Portfolio {
+	opts = opts.withDefaults()
+	if gaps <= 0 {
+		gaps = DefaultPortfolioGaps
+	}
+	p := &Portfolio{Root: root, GeneratedAt: opts.Now}
+	for _, path := range paths {
+		if kind, ok := KindFromPath(path); !ok || kind != KindPRD {
+			continue
+		}
+		e := PortfolioEntry{Path: path}
+		var doc prd.Document
+		if _, err := decodeFile(path, &doc, opts.Lenient); err != nil {
+			e.Error = err.Error()
+			p.Entries = append(p.Entries, e)
+			continue
+		}
+		e.ID = doc.Metadata.ID
+		e.Title = doc.Metadata.Title
+		result := prd.Score(&doc)
+		e.WeightedScore = result.WeightedScore
+		e.Grade = portfolioGrade(result.WeightedScore)
+		e.Decision = result.Decision
+		e.Blockers = len(result.Blockers)
+		e.Gaps = weakestCategories(result.CategoryScores, gaps)
+		p.Entries = append(p.Entries, e)
+	}
+
+	sort.SliceStable(p.Entries, func(i, j int) bool {
+		a, b := p.Entries[i], p.Entries[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		if a.WeightedScore != b.WeightedScore {
+			return a.WeightedScore > b.WeightedScore
+		}
+		return a.label() < b.label()
+	})
+	for i := range p.Entries {
+		if p.Entries[i].Error == "" {
+			p.Entries[i].Rank = i + 1
+		}
+	}
+	return p
+}
+
+// AverageScore returns the mean weighted score of the scored PRDs.
+func (p *Portfolio) AverageScore() float64 {
+	var sum float64
+	n := 0
+	for _, e := range p.Entries {
+		if e.Error == "" {
+			sum += e.WeightedScore
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// ToText renders the portfolio as a plain-text table for the terminal.
+func (p *Portfolio) ToText() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("PRD Portfolio: %s (%d documents, average score %.1f)\n\n", p.Root, len(p.Entries), p.AverageScore()))
+	if len(p.Entries) == 0 {
+		sb.WriteString("No PRDs found.\n")
+		return sb.String()
+	}
+
+	width := len("Product")
+	for _, e := range p.Entries {
+		width = max(width, len([]rune(e.label())))
+	}
+	width = min(width, 40)
+	sb.WriteString(fmt.Sprintf("%4s  %-*s  %5s  %5s  %-12s  %s\n", "Rank", width, "Product", "Score", "Grade", "Decision", "Biggest Gaps"))
+	sb.WriteString(strings.Repeat("-", 4+2+width+2+5+2+5+2+12+2+12) + "\n")
+	for _, e := range p.Entries {
+		label := truncateRunes(e.label(), width)
+		if e.Error != "" {
+			sb.WriteString(fmt.Sprintf("%4s  %-*s  error: %s\n", "-", width, label, e.Error))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%4d  %-*s  %5.1f  %5s  %-12s  %s\n", e.Rank, width, label, e.WeightedScore, e.Grade, e.Decision, formatGaps(e.Gaps)))
+	}
+	return sb.String()
+}
+
+// WriteCSV writes one row per PRD, with the gaps joined by "; ".
+func (p *Portfolio) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Rank", "ID", "Title", "Path", "Weighted Score", "Grade", "Decision", "Blockers", "Biggest Gaps", "Error"}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+	for _, e := range p.Entries {
+		rank := ""
+		if e.Rank > 0 {
+			rank = strconv.Itoa(e.Rank)
+		}
+		row := []string{rank, e.ID, e.Title, e.Path, strconv.FormatFloat(e.WeightedScore, 'f', 2, 64),
+			e.Grade, e.Decision, strconv.Itoa(e.Blockers), formatGaps(e.Gaps), e.Error}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Page returns the portfolio as a standalone HTML page: a ranking table
+// followed by each product's gaps with the scoring justification.
+func (p *Portfolio) Page() *htmldoc.Page {
+	page := htmldoc.NewPage("PRD Portfolio", "Portfolio Report")
+	page.Meta = []htmldoc.Field{
+		{Label: "Root", Value: p.Root},
+		{Label: "Documents", Value: strconv.Itoa(len(p.Entries))},
+		{Label: "Average score", Value: fmt.Sprintf("%.1f", p.AverageScore())},
+		{Label: "Generated", Value: htmldoc.Date(p.GeneratedAt)},
+	}
+
+	ranking := htmldoc.NewBuilder("ranking")
+	var rows [][]string
+	for _, e := range p.Entries {
+		if e.Error != "" {
+			rows = append(rows, []string{"—", e.label(), "", "", "error: " + e.Error, ""})
+			continue
+		}
+		rows = append(rows, []string{strconv.Itoa(e.Rank), e.label(), fmt.Sprintf("%.1f", e.WeightedScore), e.Grade, e.Decision, formatGaps(e.Gaps)})
+	}
+	ranking.Table([]string{"Rank", "Product", "Score", "Grade", "Decision", "Biggest Gaps"}, rows)
+	page.AddSection("Ranking", ranking)
+
+	gaps := htmldoc.NewBuilder("gaps")
+	for _, e := range p.Entries {
+		if len(e.Gaps) == 0 {
+			continue
+		}
+		gaps.Heading(e.label())
+		var items []string
+		for _, g := range e.Gaps {
+			items = append(items, fmt.Sprintf("%s (%.1f): %s", g.Category, g.Score, g.Justification))
+		}
+		gaps.List("", items)
+	}
+	page.AddSection("Gaps by Product", gaps)
+	return page
+}
+
+// label names the entry by title, ID, or path, in that order of preference.
+func (e PortfolioEntry) label() string {
+	switch {
+	case e.Title != "":
+		return e.Title
+	case e.ID != "":
+		return e.ID
+	}
+	return e.Path
+}
+
+// weakestCategories returns up to n categories scoring below gapThreshold,
+// lowest first.
+func weakestCategories(scores []prd.CategoryScore, n int) []Gap {
+	var gaps []Gap
+	for _, cs := range scores {
+		if cs.Score < gapThreshold {
+			gaps = append(gaps, Gap{Category: cs.Category, Score: cs.Score, Justification: cs.Justification})
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].Score < gaps[j].Score })
+	if len(gaps) > n {
+		gaps = gaps[:n]
+	}
+	return gaps
+}
+
+func formatGaps(gaps []Gap) string {
+	parts := make([]string, len(gaps))
+	for i, g := range gaps {
+		parts[i] = fmt.Sprintf("%s (%.1f)", g.Category, g.Score)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// portfolioGrade converts a 0-10 weighted score to a letter grade using the
+// completeness grade bands (A from 90%, F below 60%).
+func portfolioGrade(score float64) string {
+	switch {
+	case score >= 9:
+		return "A"
+	case score >= 8:
+		return "B"
+	case score >= 7:
+		return "C"
+	case score >= 6:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,72 @@
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/snippets"
+)
+
+var (
+	snippetLibrariesMu sync.Mutex
+	snippetLibraries   = map[string]*snippets.Library{}
+)
+
+// SnippetsFor returns the snippet library of the workspace manifest found
+// from the directory of the document at path, or nil if there is no
+// manifest or it lists no snippet files.
+func SnippetsFor(path string) (*snippets.Library, error) {
+	m, err := FindManifest(filepath.Dir(path))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.SnippetLibrary()
+}
+
+// SnippetLibrary loads the snippet files of the manifest as one library,
+// or returns nil if it lists none. The files are read once per manifest.
+func (m *Manifest) SnippetLibrary() (*snippets.Library, error) {
+	if len(m.Snippets) == 0 {
+		return nil, nil
+	}
+	snippetLibrariesMu.Lock()
+	defer snippetLibrariesMu.Unlock()
+	if lib, ok := snippetLibraries[m.Dir]; ok {
+		return lib, nil
+	}
+	lib := &snippets.Library{}
+	for _, f := range m.Snippets {
+		l, err := snippets.Load(m.resolve(f))
+		if err != nil {
+			return nil, err
+		}
+		if err := lib.Add(l); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+	}
+	snippetLibraries[m.Dir] = lib
+	return lib, nil
+}
+
+// ExpandSnippets returns the JSON document read from path with its snippet
+// references expanded from the workspace snippet library, and the
+// expansions made.
+func ExpandSnippets(path string, data []byte) ([]byte, []common.SnippetRef, error) {
+	if !snippets.HasRefs(data) {
+		return data, nil, nil
+	}
+	lib, err := SnippetsFor(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, refs, err := snippets.Expand(data, lib)
+	if err != nil {
+		if lib == nil {
+			return nil, nil, fmt.Errorf("%s: %w (no workspace manifest lists a snippet library)", path, err)
+		}
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return out, refs, nil
+}
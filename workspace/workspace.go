@@ -0,0 +1,524 @@
+// Package workspace scans a directory tree of structured planning documents
+// and summarizes their health: quality score, freshness, unresolved items,
+// and roadmap progress.
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-plan/common"
+	"github.com/grokify/structured-plan/conditions"
+	"github.com/grokify/structured-plan/goals/okr"
+	"github.com/grokify/structured-plan/goals/v2mom"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/roadmap"
+	"github.com/grokify/structured-plan/yamlconv"
+)
+
+// Kind identifies a structured planning document type.
+type Kind string
+
+const (
+	KindPRD     Kind = "prd"
+	KindMRD     Kind = "mrd"
+	KindTRD     Kind = "trd"
+	KindOKR     Kind = "okr"
+	KindV2MOM   Kind = "v2mom"
+	KindRoadmap Kind = "roadmap"
+)
+
+// Kinds lists the supported document kinds in display order.
+var Kinds = []Kind{KindPRD, KindMRD, KindTRD, KindOKR, KindV2MOM, KindRoadmap}
+
+// KindFromPath infers the document kind from a filename such as
+// "checkout.prd.json" or "checkout.prd.yaml". It returns false for files
+// that are not recognized.
+func KindFromPath(path string) (Kind, bool) {
+	name := strings.ToLower(filepath.Base(path))
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		base, ok := strings.CutSuffix(name, ext)
+		if !ok {
+			continue
+		}
+		for _, k := range Kinds {
+			if strings.HasSuffix(base, "."+string(k)) {
+				return k, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Freshness buckets documents by time since their last update.
+type Freshness string
+
+const (
+	FreshnessCurrent Freshness = "current"
+	FreshnessAging   Freshness = "aging"
+	FreshnessStale   Freshness = "stale"
+	FreshnessUnknown Freshness = "unknown"
+)
+
+// Default freshness thresholds.
+const (
+	DefaultAgingAfter = 30 * 24 * time.Hour
+	DefaultStaleAfter = 90 * 24 * time.Hour
+)
+
+// Options configures a workspace scan.
+type Options struct {
+	// Now is the reference time for freshness. Defaults to time.Now().
+	Now time.Time
+
+	// AgingAfter and StaleAfter set the freshness thresholds.
+	// Zero values use DefaultAgingAfter and DefaultStaleAfter.
+	AgingAfter time.Duration
+	StaleAfter time.Duration
+
+	// Lenient recovers from field-level type errors when loading documents.
+	Lenient bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	if o.AgingAfter == 0 {
+		o.AgingAfter = DefaultAgingAfter
+	}
+	if o.StaleAfter == 0 {
+		o.StaleAfter = DefaultStaleAfter
+	}
+	return o
+}
+
+// RoadmapStatus counts roadmap phases (or V2MOM projects) by status.
+type RoadmapStatus struct {
+	Total      int `json:"total"`
+	Completed  int `json:"completed"`
+	InProgress int `json:"inProgress"`
+	Planned    int `json:"planned"`
+	Delayed    int `json:"delayed"`
+	Cancelled  int `json:"cancelled"`
+}
+
+// PercentComplete returns the share of non-cancelled items that are complete.
+func (r RoadmapStatus) PercentComplete() float64 {
+	active := r.Total - r.Cancelled
+	if active <= 0 {
+		return 0
+	}
+	return float64(r.Completed) / float64(active) * 100
+}
+
+// String returns a compact description such as "2/5 complete, 1 delayed".
+func (r RoadmapStatus) String() string {
+	if r.Total == 0 {
+		return ""
+	}
+	s := fmt.Sprintf("%d/%d complete", r.Completed, r.Total-r.Cancelled)
+	if r.InProgress > 0 {
+		s += fmt.Sprintf(", %d in progress", r.InProgress)
+	}
+	if r.Delayed > 0 {
+		s += fmt.Sprintf(", %d delayed", r.Delayed)
+	}
+	return s
+}
+
+// Summary describes the health of a single document.
+type Summary struct {
+	Path   string `json:"path"`
+	Kind   Kind   `json:"kind"`
+	ID     string `json:"id,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+
+	// Score is a 0-100 health score: completeness for requirements documents
+	// and key result or measure progress for goals documents. HasScore is
+	// false for kinds that have no scoring model.
+	Score    float64 `json:"score"`
+	Grade    string  `json:"grade,omitempty"`
+	HasScore bool    `json:"hasScore"`
+
+	// Decision is the PRD quality scoring decision (approve, revise, ...).
+	Decision string `json:"decision,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	AgeDays   int       `json:"ageDays"`
+	Freshness Freshness `json:"freshness"`
+
+	// OpenItems counts unresolved PRD decisions.
+	OpenItems int `json:"openItems"`
+
+	// OpenRisks counts risks or obstacles that are not closed or accepted.
+	OpenRisks int `json:"openRisks"`
+
+	Roadmap RoadmapStatus `json:"roadmap"`
+
+	// Recovered counts fields coerced or dropped in lenient mode.
+	Recovered int `json:"recovered,omitempty"`
+
+	// Error is set when the document could not be loaded.
+	Error string `json:"error,omitempty"`
+}
+
+// Workspace is the result of scanning a directory of planning documents.
+type Workspace struct {
+	Root        string    `json:"root"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Documents   []Summary `json:"documents"`
+}
+
+// Count returns the number of documents of the given kind.
+func (w *Workspace) Count(k Kind) int {
+	n := 0
+	for _, d := range w.Documents {
+		if d.Kind == k {
+			n++
+		}
+	}
+	return n
+}
+
+// CountFreshness returns the number of documents in the given freshness bucket.
+func (w *Workspace) CountFreshness(f Freshness) int {
+	n := 0
+	for _, d := range w.Documents {
+		if d.Freshness == f {
+			n++
+		}
+	}
+	return n
+}
+
+// Discover walks root and returns the paths of recognized planning
+// documents, sorted. Hidden directories are skipped.
+func Discover(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := KindFromPath(path); ok {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning workspace %s: %w", root, err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Scan discovers and summarizes every planning document under root.
+// Documents that fail to load are included with Error set rather than
+// aborting the scan.
+func Scan(root string, opts Options) (*Workspace, error) {
+	opts = opts.withDefaults()
+	paths, err := Discover(root)
+	if err != nil {
+		return nil, err
+	}
+	ws := &Workspace{
+		Root:        root,
+		GeneratedAt: opts.Now,
+		Documents:   make([]Summary, 0, len(paths)),
+	}
+	for _, path := range paths {
+		s := SummarizeFile(path, opts)
+		if rel, err := filepath.Rel(root, path); err == nil {
+			s.Path = filepath.ToSlash(rel)
+		}
+		ws.Documents = append(ws.Documents, s)
+	}
+	return ws, nil
+}
+
+// SummarizeFile loads the document at path and summarizes it. The kind is
+// inferred from the filename.
+func SummarizeFile(path string, opts Options) Summary {
+	opts = opts.withDefaults()
+	kind, ok := KindFromPath(path)
+	s := Summary{Path: path, Kind: kind, Freshness: FreshnessUnknown}
+	if !ok {
+		s.Error = "unrecognized document type"
+		return s
+	}
+
+	var err error
+	switch kind {
+	case KindPRD:
+		var doc prd.Document
+		if s.Recovered, err = decodeFile(path, &doc, opts.Lenient); err == nil {
+			summarizePRD(&s, &doc)
+		}
+	case KindMRD:
+		var doc mrd.Document
+		if s.Recovered, err = decodeFile(path, &doc, opts.Lenient); err == nil {
+			summarizeMRD(&s, &doc)
+		}
+	case KindTRD:
+		var doc trd.Document
+		if s.Recovered, err = decodeFile(path, &doc, opts.Lenient); err == nil {
+			summarizeTRD(&s, &doc)
+		}
+	case KindOKR:
+		var doc okr.OKRDocument
+		if s.Recovered, err = decodeFile(path, &doc, opts.Lenient); err == nil {
+			summarizeOKR(&s, &doc)
+		}
+	case KindV2MOM:
+		var doc v2mom.V2MOM
+		if s.Recovered, err = decodeFile(path, &doc, opts.Lenient); err == nil {
+			summarizeV2MOM(&s, &doc)
+		}
+	case KindRoadmap:
+		var doc roadmap.Document
+		if s.Recovered, err = decodeFile(path, &doc, opts.Lenient); err == nil {
+			summarizeRoadmap(&s, &doc)
+		}
+	}
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+
+	s.setFreshness(opts)
+	return s
+}
+
+// ReadJSON reads a JSON or YAML document file as JSON.
+func ReadJSON(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if yamlconv.FormatFromPath(path) == yamlconv.FormatYAML {
+		return yamlconv.ToJSON(data)
+	}
+	return data, nil
+}
+
+// decodeFile reads a JSON or YAML document into v and applies the
+// workspace defaults. In lenient mode it returns the number of fields that
+// were coerced or dropped.
+func decodeFile(path string, v any, lenientMode bool) (int, error) {
+	data, err := ReadJSON(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading file: %w", err)
+	}
+	if data, _, err = ExpandSnippets(path, data); err != nil {
+		return 0, err
+	}
+	if data, _, err = conditions.Apply(data, conditions.Context{}); err != nil {
+		return 0, err
+	}
+	recovered := 0
+	if !lenientMode {
+		if err := json.Unmarshal(data, v); err != nil {
+			return 0, fmt.Errorf("parsing JSON: %w", err)
+		}
+	} else {
+		report, err := lenient.Unmarshal(data, v)
+		if err != nil {
+			return 0, fmt.Errorf("parsing JSON: %w", err)
+		}
+		recovered = len(report.Issues)
+	}
+	if err := ApplyDefaults(path, v); err != nil {
+		return recovered, err
+	}
+	return recovered, nil
+}
+
+func (s *Summary) setFreshness(opts Options) {
+	if s.UpdatedAt.IsZero() {
+		s.Freshness = FreshnessUnknown
+		return
+	}
+	age := opts.Now.Sub(s.UpdatedAt)
+	if age < 0 {
+		age = 0
+	}
+	s.AgeDays = int(age / (24 * time.Hour))
+	switch {
+	case age >= opts.StaleAfter:
+		s.Freshness = FreshnessStale
+	case age >= opts.AgingAfter:
+		s.Freshness = FreshnessAging
+	default:
+		s.Freshness = FreshnessCurrent
+	}
+}
+
+func summarizePRD(s *Summary, doc *prd.Document) {
+	s.ID = doc.Metadata.ID
+	s.Title = doc.Metadata.Title
+	s.Status = string(doc.Metadata.Status)
+	s.UpdatedAt = doc.Metadata.UpdatedAt
+
+	completeness := doc.CheckCompleteness()
+	s.Score = completeness.OverallScore
+	s.Grade = completeness.Grade
+	s.HasScore = true
+	s.Decision = prd.Score(doc).Decision
+
+	for _, item := range doc.OpenItems {
+		switch item.Status {
+		case common.OpenItemStatusResolved, common.OpenItemStatusDeferred:
+		default:
+			s.OpenItems++
+		}
+	}
+	for _, r := range doc.Risks {
+		if isOpen(string(r.Status)) {
+			s.OpenRisks++
+		}
+	}
+	s.Roadmap = phaseStatus(doc.Roadmap.Phases)
+}
+
+func summarizeMRD(s *Summary, doc *mrd.Document) {
+	s.ID = doc.Metadata.ID
+	s.Title = doc.Metadata.Title
+	s.Status = string(doc.Metadata.Status)
+	s.UpdatedAt = doc.Metadata.UpdatedAt
+	// MRD risks carry no status, so every listed risk counts as open.
+	s.OpenRisks = len(doc.Risks)
+}
+
+func summarizeTRD(s *Summary, doc *trd.Document) {
+	s.ID = doc.Metadata.ID
+	s.Title = doc.Metadata.Title
+	s.Status = string(doc.Metadata.Status)
+	s.UpdatedAt = doc.Metadata.UpdatedAt
+	for _, r := range doc.Risks {
+		if isOpen(r.Status) {
+			s.OpenRisks++
+		}
+	}
+}
+
+func summarizeOKR(s *Summary, doc *okr.OKRDocument) {
+	if doc.Metadata != nil {
+		s.ID = doc.Metadata.ID
+		s.Title = doc.Metadata.Name
+		s.Status = doc.Metadata.Status
+		s.UpdatedAt = doc.Metadata.UpdatedAt
+	}
+	if len(doc.Objectives) > 0 {
+		progress := doc.CalculateOverallProgress()
+		s.Score = progress * 100
+		s.Grade = okr.ScoreGrade(progress)
+		s.HasScore = true
+	}
+	for _, r := range doc.AllRisks() {
+		if isOpen(r.Status) {
+			s.OpenRisks++
+		}
+	}
+}
+
+func summarizeV2MOM(s *Summary, doc *v2mom.V2MOM) {
+	if doc.Metadata != nil {
+		s.ID = doc.Metadata.ID
+		s.Title = doc.Metadata.Name
+		s.Status = doc.Metadata.Status
+		s.UpdatedAt = doc.Metadata.UpdatedAt
+	}
+	if measures := doc.AllMeasures(); len(measures) > 0 {
+		var total float64
+		for _, m := range measures {
+			total += m.Progress
+		}
+		progress := total / float64(len(measures))
+		s.Score = progress * 100
+		s.Grade = okr.ScoreGrade(progress)
+		s.HasScore = true
+	}
+	for _, o := range doc.AllObstacles() {
+		if isOpen(o.Status) {
+			s.OpenRisks++
+		}
+	}
+	for _, p := range doc.Projects {
+		s.Roadmap.Total++
+		switch strings.ToLower(strings.ReplaceAll(p.Status, "_", " ")) {
+		case "completed":
+			s.Roadmap.Completed++
+		case "in progress":
+			s.Roadmap.InProgress++
+		case "cancelled":
+			s.Roadmap.Cancelled++
+		default:
+			s.Roadmap.Planned++
+		}
+	}
+}
+
+func summarizeRoadmap(s *Summary, doc *roadmap.Document) {
+	s.ID = doc.Metadata.ID
+	s.Title = doc.Metadata.Title
+	s.Status = doc.Metadata.Status
+	s.UpdatedAt = doc.Metadata.UpdatedAt
+
+	risks := append([]roadmap.Risk(nil), doc.Risks...)
+	for _, p := range doc.Phases {
+		risks = append(risks, p.Risks...)
+	}
+	for _, r := range risks {
+		if isOpen(r.Status) {
+			s.OpenRisks++
+		}
+	}
+	s.Roadmap = phaseStatus(doc.Phases)
+}
+
+// isOpen reports whether a free-form risk or obstacle status still needs
+// attention. An empty status is treated as open.
+func isOpen(status string) bool {
+	switch strings.ToLower(status) {
+	case string(common.RiskStatusClosed), string(common.RiskStatusAccepted),
+		string(common.RiskStatusMitigated), "resolved":
+		return false
+	}
+	return true
+}
+
+func phaseStatus(phases []roadmap.Phase) RoadmapStatus {
+	var r RoadmapStatus
+	for _, p := range phases {
+		r.Total++
+		switch p.Status {
+		case roadmap.PhaseStatusCompleted:
+			r.Completed++
+		case roadmap.PhaseStatusInProgress:
+			r.InProgress++
+		case roadmap.PhaseStatusDelayed:
+			r.Delayed++
+		case roadmap.PhaseStatusCancelled:
+			r.Cancelled++
+		default:
+			r.Planned++
+		}
+	}
+	return r
+}
@@ -0,0 +1,967 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/structured-plan/bundle"
+	"github.com/grokify/structured-plan/requirements/prd"
+	"github.com/grokify/structured-plan/requirements/trd"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKindFromPath(t *testing.T) {
+	tests := map[string]Kind{
+		"a/checkout.prd.json":  KindPRD,
+		"Market.MRD.json":      KindMRD,
+		"x.trd.json":           KindTRD,
+		"team.okr.json":        KindOKR,
+		"company.v2mom.json":   KindV2MOM,
+		"2026.roadmap.json":    KindRoadmap,
+		"checkout.prd.md":      "",
+		"prd.json":             "",
+		"notes.json":           "",
+		"nested/dir/.okr.json": KindOKR,
+		"checkout.prd.yaml":    KindPRD,
+		"team.OKR.yml":         KindOKR,
+		"prd.yaml":             "",
+		"checkout.yaml.prd":    "",
+	}
+	for path, want := range tests {
+		got, ok := KindFromPath(path)
+		if got != want || ok != (want != "") {
+			t.Errorf("KindFromPath(%q) = %q, %v; want %q", path, got, ok, want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "checkout.prd.json", `{
+		"metadata": {"id": "PRD-1", "title": "Checkout", "status": "draft", "updatedAt": "2025-06-01T00:00:00Z"},
+		"openItems": [{"id": "OI-1", "status": "open"}, {"id": "OI-2", "status": "resolved"}],
+		"risks": [{"id": "R-1", "status": "open"}, {"id": "R-2", "status": "closed"}],
+		"roadmap": {"phases": [
+			{"id": "p1", "status": "completed"},
+			{"id": "p2", "status": "in_progress"},
+			{"id": "p3", "status": "delayed"},
+			{"id": "p4", "status": "cancelled"}
+		]}
+	}`)
+	writeFile(t, dir, "goals/team.okr.json", `{
+		"metadata": {"name": "Team OKRs", "updatedAt": "2025-01-01T00:00:00Z"},
+		"objectives": [{"title": "O1", "keyResults": [{"title": "KR1", "score": 0.8}, {"title": "KR2", "score": 0.6}]}],
+		"risks": [{"title": "R", "status": "Mitigating"}]
+	}`)
+	writeFile(t, dir, "goals/company.v2mom.json", `{
+		"metadata": {"name": "Company"},
+		"vision": "v",
+		"measures": [{"name": "M", "progress": 0.95}],
+		"projects": [{"id": "a", "name": "A", "status": "Completed"}, {"id": "b", "name": "B", "status": "In Progress"}]
+	}`)
+	writeFile(t, dir, "platform.roadmap.json", `{
+		"metadata": {"id": "RM-1", "title": "Platform", "status": "Active"},
+		"phases": [
+			{"id": "p1", "status": "completed", "risks": [{"id": "R-1", "status": "Resolved"}]},
+			{"id": "p2", "risks": [{"id": "R-2"}]}
+		],
+		"risks": [{"id": "R-3", "status": "Mitigating"}]
+	}`)
+	writeFile(t, dir, "broken.trd.json", `{not json`)
+	writeFile(t, dir, ".hidden/skip.prd.json", `{}`)
+	writeFile(t, dir, "README.md", `# ignored`)
+
+	now := time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)
+	ws, err := Scan(dir, Options{Now: now})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	byPath := map[string]Summary{}
+	for _, s := range ws.Documents {
+		byPath[s.Path] = s
+	}
+	if len(byPath) != 5 {
+		t.Fatalf("expected 5 documents, got %v", ws.Documents)
+	}
+
+	p := byPath["checkout.prd.json"]
+	if p.Title != "Checkout" || p.Status != "draft" || !p.HasScore || p.Grade == "" || p.Decision == "" {
+		t.Errorf("unexpected PRD summary: %+v", p)
+	}
+	if p.AgeDays != 10 || p.Freshness != FreshnessCurrent {
+		t.Errorf("PRD freshness = %d days %s", p.AgeDays, p.Freshness)
+	}
+	if p.OpenItems != 1 || p.OpenRisks != 1 {
+		t.Errorf("PRD open items/risks = %d/%d, want 1/1", p.OpenItems, p.OpenRisks)
+	}
+	want := RoadmapStatus{Total: 4, Completed: 1, InProgress: 1, Delayed: 1, Cancelled: 1}
+	if p.Roadmap != want {
+		t.Errorf("PRD roadmap = %+v, want %+v", p.Roadmap, want)
+	}
+	if got := p.Roadmap.String(); got != "1/3 complete, 1 in progress, 1 delayed" {
+		t.Errorf("Roadmap.String() = %q", got)
+	}
+
+	o := byPath["goals/team.okr.json"]
+	if o.Score != 70 || o.Grade != "B" || o.Freshness != FreshnessStale || o.OpenRisks != 1 {
+		t.Errorf("unexpected OKR summary: %+v", o)
+	}
+
+	v := byPath["goals/company.v2mom.json"]
+	if v.Grade != "A" || v.Freshness != FreshnessUnknown || v.Roadmap.Completed != 1 || v.Roadmap.InProgress != 1 {
+		t.Errorf("unexpected V2MOM summary: %+v", v)
+	}
+
+	r := byPath["platform.roadmap.json"]
+	if r.Title != "Platform" || r.HasScore || r.OpenRisks != 2 || r.Roadmap.Completed != 1 || r.Roadmap.Planned != 1 {
+		t.Errorf("unexpected roadmap summary: %+v", r)
+	}
+
+	if b := byPath["broken.trd.json"]; b.Error == "" {
+		t.Error("expected load error for broken.trd.json")
+	}
+
+	if ws.Count(KindPRD) != 1 || ws.CountFreshness(FreshnessStale) != 1 {
+		t.Errorf("unexpected counts")
+	}
+}
+
+func TestScanYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "checkout.prd.yaml", `metadata:
+  id: PRD-1
+  title: Checkout
+  updatedAt: 2025-06-01T00:00:00Z
+risks:
+  - id: R-1
+    status: open
+`)
+	writeFile(t, dir, "platform.roadmap.yml", "metadata:\n  title: Platform\nphases:\n  - id: p1\n    status: completed\n")
+
+	ws, err := Scan(dir, Options{Now: time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.Documents) != 2 {
+		t.Fatalf("documents = %+v", ws.Documents)
+	}
+	for _, d := range ws.Documents {
+		if d.Error != "" {
+			t.Errorf("%s: %s", d.Path, d.Error)
+		}
+	}
+	if p := ws.Documents[0]; p.Title != "Checkout" || p.AgeDays != 10 || p.OpenRisks != 1 || !p.HasScore {
+		t.Errorf("PRD summary = %+v", p)
+	}
+	if r := ws.Documents[1]; r.Title != "Platform" || r.Roadmap.Completed != 1 {
+		t.Errorf("roadmap summary = %+v", r)
+	}
+}
+
+func TestScanLenient(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.prd.json", `{"metadata": {"title": "A", "updatedAt": "2025-06-01"}}`)
+
+	ws, err := Scan(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Documents[0].Error == "" {
+		t.Error("strict scan should fail on a bare date")
+	}
+
+	ws, err = Scan(dir, Options{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := ws.Documents[0]; d.Error != "" || d.Recovered != 1 || d.UpdatedAt.IsZero() {
+		t.Errorf("lenient scan = %+v", d)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	ws := &Workspace{
+		Root:        "docs",
+		GeneratedAt: time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC),
+		Documents: []Summary{
+			{Path: "a.prd.json", Kind: KindPRD, Title: "<script>x</script>", Score: 82, Grade: "B", HasScore: true, Freshness: FreshnessCurrent, Roadmap: RoadmapStatus{Total: 2, Completed: 1}},
+			{Path: "b.mrd.json", Kind: KindMRD, Freshness: FreshnessUnknown, Error: "parsing JSON: bad"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ws.WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<title>Workspace Health Dashboard</title>",
+		`id="documents"`,
+		"&lt;script&gt;x&lt;/script&gt;",
+		`data-sort="82.00"`,
+		"1/2 complete",
+		"parsing JSON: bad",
+		`<div class="l">PRD</div>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>x</script>") {
+		t.Error("document titles must be escaped")
+	}
+}
+
+func TestNormalizeFramework(t *testing.T) {
+	tests := map[string]string{
+		"SOC 2 Type II": "SOC2",
+		"soc2":          "SOC2",
+		"gdpr":          "GDPR",
+		"PCI DSS":       "PCI-DSS",
+		"ISO-27001":     "ISO 27001",
+		" NIST CSF ":    "NIST CSF",
+	}
+	for in, want := range tests {
+		if got := NormalizeFramework(in); got != want {
+			t.Errorf("NormalizeFramework(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildComplianceMatrix(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.prd.json", `{
+		"metadata": {"id": "PRD-A", "title": "Alpha"},
+		"securityModel": {"complianceControls": {
+			"SOC 2": ["CC6.1", "CC7.2"],
+			"GDPR": ["Art. 32"],
+			"HIPAA": []
+		}}
+	}`)
+	writeFile(t, dir, "b.prd.json", `{
+		"metadata": {"id": "PRD-B"},
+		"securityModel": {"complianceControls": {"SOC2": ["CC6.1"], "NIST CSF": ["PR.AC-1"]}}
+	}`)
+	writeFile(t, dir, "c.trd.json", `{
+		"metadata": {"id": "TRD-C"},
+		"securityDesign": {"compliance": ["SOC 2 Type II", "GDPR"]}
+	}`)
+	writeFile(t, dir, "d.prd.json", `{"metadata": {"id": "PRD-D"}}`)
+	writeFile(t, dir, "e.okr.json", `{"objectives": []}`)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := BuildComplianceMatrix(paths, Options{})
+	if err != nil {
+		t.Fatalf("BuildComplianceMatrix() error = %v", err)
+	}
+
+	wantFrameworks := []string{"SOC2", "GDPR", "HIPAA", "NIST CSF"}
+	if strings.Join(m.Frameworks, ",") != strings.Join(wantFrameworks, ",") {
+		t.Errorf("Frameworks = %v, want %v", m.Frameworks, wantFrameworks)
+	}
+	if len(m.Documents) != 3 {
+		t.Errorf("expected 3 contributing documents, got %v", m.Documents)
+	}
+	if got := m.Cell("CC6.1", "SOC2"); strings.Join(got, ",") != "PRD-A,PRD-B" {
+		t.Errorf("Cell(CC6.1, SOC2) = %v", got)
+	}
+	if got := m.Cell(FrameworkLevel, "HIPAA"); strings.Join(got, ",") != "PRD-A" {
+		t.Errorf("Cell(framework-level, HIPAA) = %v", got)
+	}
+	if m.Controls[len(m.Controls)-1] != FrameworkLevel {
+		t.Errorf("framework-level row should be last, got %v", m.Controls)
+	}
+
+	md := m.ToMarkdown()
+	for _, want := range []string{
+		"| Document | Type | SOC2 | GDPR | HIPAA | NIST CSF |",
+		"| PRD-A Alpha | PRD | 2 | 1 | ✓ |  |",
+		"| TRD-C | TRD | ✓ | ✓ |  |  |",
+		"| CC6.1 | PRD-A, PRD-B |  |  |  |",
+		"| *(framework-level)* | TRD-C | TRD-C | PRD-A |  |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n%s", want, md)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "Control,SOC2,GDPR,HIPAA,NIST CSF" {
+		t.Errorf("CSV header = %q", lines[0])
+	}
+	if !strings.Contains(buf.String(), "CC6.1,PRD-A; PRD-B,,,\n") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestComplianceMatrixEmpty(t *testing.T) {
+	m := NewComplianceMatrix(nil, nil)
+	if !strings.Contains(m.ToMarkdown(), "No compliance controls") {
+		t.Error("expected empty-matrix note")
+	}
+}
+
+func TestBuildDependencyReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.prd.json", `{
+		"metadata": {"id": "PRD-A"},
+		"roadmap": {"phases": [{"id": "p1", "name": "Beta", "startDate": "2026-03-01T00:00:00Z"}]},
+		"assumptions": {"dependencies": [
+			{"id": "D1", "name": "SSO", "team": "Identity", "deliverable": "SAML endpoint", "neededBy": "p1", "dueDate": "2026-04-01", "status": "committed", "contact": "idp@example.com"},
+			{"id": "D2", "name": "Vendor API"}
+		]}
+	}`)
+	writeFile(t, dir, "b.trd.json", `{
+		"metadata": {"id": "TRD-B"},
+		"dependencies": [{"id": "D9", "name": "Token service", "team": "Identity", "status": "blocked", "dueDate": "2026-02-01"}]
+	}`)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := BuildDependencyReport(paths, Options{})
+	if err != nil {
+		t.Fatalf("BuildDependencyReport() error = %v", err)
+	}
+
+	if strings.Join(r.Teams, ",") != "Identity,"+UnassignedTeam {
+		t.Errorf("Teams = %v", r.Teams)
+	}
+	identity := r.ForTeam("Identity")
+	if len(identity) != 2 || identity[0].ID != "D9" || identity[1].ID != "D1" {
+		t.Fatalf("Identity entries = %+v", identity)
+	}
+	if identity[1].Phase != "Beta" || identity[1].PhaseStart != "2026-03-01" || len(identity[1].Issues) != 1 {
+		t.Errorf("D1 entry = %+v", identity[1])
+	}
+	if r.AtRisk() != 2 {
+		t.Errorf("AtRisk() = %d, want 2", r.AtRisk())
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"## Identity",
+		"| TRD-B | D9 | Token service |  | 2026-02-01 | **blocked** |  |  |",
+		"| PRD-A | D1 | SAML endpoint | Beta (2026-03-01) | 2026-04-01 | committed | idp@example.com | Dependency D1 is due 2026-04-01, after phase p1 starts 2026-03-01 |",
+		"## " + UnassignedTeam,
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n%s", want, md)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Team,Document,ID,Name,Deliverable,Needed By,Phase Start,Due,Status,Contact,Issues\nIdentity,TRD-B,D9,") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	if got := TitleSimilarity("Improve Onboarding!", "improve the onboarding"); got != 1 {
+		t.Errorf("normalized titles should match exactly, got %v", got)
+	}
+	if got := TitleSimilarity("Improve onboarding conversion", "Improve the onboarding conversion rate"); got < DefaultSimilarityThreshold {
+		t.Errorf("similar titles scored %v", got)
+	}
+	if got := TitleSimilarity("Reduce churn", "Increase revenue"); got > 0.5 {
+		t.Errorf("different titles scored %v", got)
+	}
+	if got := TitleSimilarity("", "the"); got != 0 {
+		t.Errorf("empty titles scored %v", got)
+	}
+}
+
+func TestBuildGoalDuplicateReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "team.okr.json", `{
+		"metadata": {"id": "OKR-TEAM", "name": "Team OKRs"},
+		"objectives": [
+			{"id": "O1", "title": "Improve onboarding conversion", "keyResults": [{"id": "KR1", "title": "Raise trial-to-paid conversion to 20%"}]},
+			{"id": "O2", "title": "Harden the platform", "keyResults": []}
+		]
+	}`)
+	writeFile(t, dir, "a.prd.json", `{
+		"metadata": {"id": "PRD-A"},
+		"objectives": {"okrs": [
+			{"objective": {"id": "PO1", "title": "Improve the onboarding conversion rate"},
+			 "keyResults": [{"id": "PKR1", "title": "Raise trial to paid conversion to 20%"}]},
+			{"objective": {"id": "PO2", "title": "Harden platform", "alignedWith": ["O2"]}, "keyResults": []},
+			{"objective": {"id": "O1", "title": "Expand to EMEA"}, "keyResults": []}
+		]}
+	}`)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := BuildGoalDuplicateReport(paths, Options{}, 0)
+	if err != nil {
+		t.Fatalf("BuildGoalDuplicateReport() error = %v", err)
+	}
+	if r.Goals != 7 || r.Documents != 2 || r.Threshold != DefaultSimilarityThreshold {
+		t.Errorf("report = %+v", r)
+	}
+
+	// PO2 is already aligned with O2, so only two objective pairs remain.
+	objectives := r.ForLevel(GoalObjective)
+	if len(objectives) != 2 {
+		t.Fatalf("objective duplicates = %+v", objectives)
+	}
+	for _, d := range objectives {
+		if d.Canonical.Kind != KindOKR || d.Duplicate.Kind != KindPRD {
+			t.Errorf("canonical should be the OKR document: %+v", d)
+		}
+	}
+	if d := objectives[0]; d.Duplicate.ID != "PO1" || d.SameID ||
+		d.Suggestion != `Keep the objective in OKR-TEAM; in PRD-A, replace the copy with alignedWith: ["O1"]` {
+		t.Errorf("similar title duplicate = %+v", d)
+	}
+	if d := objectives[1]; !d.SameID || !strings.HasPrefix(d.Suggestion, "ID O1 names different goals") {
+		t.Errorf("same ID duplicate = %+v", d)
+	}
+
+	krs := r.ForLevel(GoalKeyResult)
+	if len(krs) != 1 || krs[0].Canonical.ID != "KR1" || !strings.Contains(krs[0].Suggestion, `add alignedWith: ["O1"] to "Improve the onboarding conversion rate"`) {
+		t.Errorf("key result duplicates = %+v", krs)
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{"## Objectives", "| O1: Improve onboarding conversion | OKR-TEAM | PO1: Improve the onboarding conversion rate | PRD-A |", "## Key Results"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n%s", want, md)
+		}
+	}
+
+	if empty := NewGoalDuplicateReport(nil, 0.9); !strings.Contains(empty.ToMarkdown(), "No duplicates found") {
+		t.Errorf("unexpected empty report:\n%s", empty.ToMarkdown())
+	}
+}
+
+func TestBuildPortfolio(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("../examples/agent-control-plane.prd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "full.prd.json", string(data))
+	writeFile(t, dir, "empty.prd.json", `{"metadata":{"id":"PRD-2","title":"Empty"}}`)
+	writeFile(t, dir, "broken.prd.json", `{`)
+	writeFile(t, dir, "other.trd.json", `{}`)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := BuildPortfolio(dir, paths, 2, Options{})
+	if len(p.Entries) != 3 {
+		t.Fatalf("entries = %d, want 3 PRDs", len(p.Entries))
+	}
+	first, second, last := p.Entries[0], p.Entries[1], p.Entries[2]
+	if first.Rank != 1 || second.Title != "Empty" || first.WeightedScore <= second.WeightedScore {
+		t.Errorf("ranking = %+v, %+v", first, second)
+	}
+	if len(second.Gaps) != 2 || second.Grade != "F" {
+		t.Errorf("empty PRD = %+v", second)
+	}
+	if last.Rank != 0 || last.Error == "" {
+		t.Errorf("broken PRD should be listed last without a rank: %+v", last)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Errorf("CSV has %d lines, want header and 3 rows", lines)
+	}
+	if out, err := p.Page().Render(); err != nil || !bytes.Contains(out, []byte("Gaps by Product")) {
+		t.Errorf("Page().Render() = %v", err)
+	}
+}
+
+func TestBuildAnalytics(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ManifestFile, `{"documents": [], "directory": {"file": "people.yaml"}}`)
+	writeFile(t, dir, "people.yaml", `people:
+  - {name: Ana Lopez, email: ana@example.com, team: Payments}
+  - {name: Bo Chen, email: bo@example.com}
+`)
+	writeFile(t, dir, "checkout.prd.json", `{"metadata": {"id": "PRD-1", "title": "Checkout", "status": "approved",
+		"createdAt": "2026-01-01T00:00:00Z", "reviewedAt": "2026-01-11T00:00:00Z",
+		"authors": [{"name": "ana lopez", "email": "ana@example.com"}]}}`)
+	writeFile(t, dir, "refunds.prd.json", `{"metadata": {"id": "PRD-2", "title": "Refunds", "status": "draft",
+		"authors": [{"name": "Bo Chen"}, {"name": "Ana Lopez"}], "team": "Payments"}}`)
+	writeFile(t, dir, "goals/q3.okr.json", `{"metadata": {"owner": "Cy", "status": "Active", "createdAt": "2026-03-01T00:00:00Z"}}`)
+	writeFile(t, dir, "broken.trd.json", `{`)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := BuildAnalytics(context.Background(), dir, paths, AnalyticsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a.Documents) != 4 || a.Documents[0].Error == "" {
+		t.Fatalf("documents = %+v", a.Documents)
+	}
+	checkout := a.Documents[1]
+	if checkout.Path != "checkout.prd.json" || checkout.Team != "Payments" || checkout.DaysToApproval == nil || *checkout.DaysToApproval != 10 {
+		t.Errorf("checkout = %+v", checkout)
+	}
+	if len(checkout.Authors) != 1 || checkout.Authors[0] != "Ana Lopez" {
+		t.Errorf("authors = %v, want the directory name", checkout.Authors)
+	}
+
+	// The OKR has no team and no approval date.
+	if len(a.Teams) != 2 || a.Teams[0].Team != "Payments" || a.Teams[1].Team != NoTeam {
+		t.Fatalf("teams = %+v", a.Teams)
+	}
+	payments := a.Teams[0]
+	if payments.Documents != 2 || payments.Approved != 1 || payments.AverageDaysToApproval == nil || *payments.MedianDaysToApproval != 10 {
+		t.Errorf("payments = %+v", payments)
+	}
+	if a.Teams[1].AverageDaysToApproval != nil {
+		t.Errorf("days to approval without an approval date = %v", *a.Teams[1].AverageDaysToApproval)
+	}
+	if len(a.Authors) != 3 || a.Authors[0].Author != "Ana Lopez" || a.Authors[0].Documents != 2 || a.Authors[0].Team != "Payments" {
+		t.Errorf("authors = %+v", a.Authors)
+	}
+
+	for _, table := range AnalyticsTables {
+		var buf bytes.Buffer
+		if err := a.WriteCSV(&buf, table); err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines < 3 {
+			t.Errorf("%s CSV has %d lines", table, lines)
+		}
+	}
+	if err := a.WriteCSV(&bytes.Buffer{}, "people"); err == nil {
+		t.Error("expected an error for an unknown table")
+	}
+	if out, err := a.Page().Render(); err != nil || !bytes.Contains(out, []byte("Authors")) {
+		t.Errorf("Page().Render() = %v", err)
+	}
+	if !strings.Contains(a.ToText(), "Git history not read") {
+		t.Errorf("text report does not say git history was not read:\n%s", a.ToText())
+	}
+}
+
+func TestFindManifest(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ManifestFile, `{"documents": [
+		{"path": "market.mrd.json"},
+		{"path": "specs/product.trd.json", "markdown": "out/trd.md", "html": "site/trd.html"}
+	]}`)
+	writeFile(t, root, "specs/sub/product.prd.json", `{}`)
+
+	m, err := FindManifest(filepath.Join(root, "specs", "sub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m.Documents) != 2 {
+		t.Fatalf("manifest = %+v", m)
+	}
+	mrd, trd := m.Documents[0], m.Documents[1]
+	if mrd.Kind != KindMRD || trd.Kind != KindTRD {
+		t.Errorf("kinds = %s, %s", mrd.Kind, trd.Kind)
+	}
+	if got := m.MarkdownOutput(mrd); got != filepath.Join(m.Dir, "market.mrd.md") {
+		t.Errorf("MarkdownOutput = %s", got)
+	}
+	if got := m.HTMLOutput(trd); got != filepath.Join(m.Dir, "site", "trd.html") {
+		t.Errorf("HTMLOutput = %s", got)
+	}
+
+	if m, err := FindManifest(t.TempDir()); err != nil || m != nil {
+		t.Errorf("FindManifest without manifest = %v, %v", m, err)
+	}
+
+	bad := t.TempDir()
+	writeFile(t, bad, ManifestFile, `{"documents": [{"path": "notes.json"}]}`)
+	if _, err := FindManifest(bad); err == nil {
+		t.Error("expected an error for a document of unknown type")
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ManifestFile, `{
+		"defaults": {
+			"authors": [{"name": "Jane Doe"}],
+			"team": "Payments",
+			"tags": ["checkout"],
+			"fonts": {"mainFont": "Inter"},
+			"complianceFrameworks": ["SOC2", "PCI-DSS"]
+		},
+		"documents": []
+	}`)
+	writeFile(t, root, "specs/checkout.prd.json", `{"metadata": {"tags": ["payments"]}, "securityModel": {}}`)
+	writeFile(t, root, "specs/checkout.trd.json", `{"metadata": {"team": "Platform"}}`)
+
+	var p prd.Document
+	if _, err := decodeFile(filepath.Join(root, "specs", "checkout.prd.json"), &p, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Metadata.Authors) != 1 || p.Metadata.Authors[0].Name != "Jane Doe" || p.Metadata.Team != "Payments" {
+		t.Errorf("PRD metadata = %+v", p.Metadata)
+	}
+	if len(p.Metadata.Tags) != 1 || p.Metadata.Tags[0] != "payments" {
+		t.Errorf("PRD tags = %v, want the document's own", p.Metadata.Tags)
+	}
+	if _, ok := p.SecurityModel.ComplianceControls["PCI-DSS"]; !ok || len(p.SecurityModel.ComplianceControls) != 2 {
+		t.Errorf("PRD compliance = %v", p.SecurityModel.ComplianceControls)
+	}
+
+	var tr trd.Document
+	if _, err := decodeFile(filepath.Join(root, "specs", "checkout.trd.json"), &tr, false); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Metadata.Team != "Platform" || len(tr.SecurityDesign.Compliance) != 2 {
+		t.Errorf("TRD = %+v, %v", tr.Metadata, tr.SecurityDesign.Compliance)
+	}
+
+	d, err := DefaultsFor(filepath.Join(root, "specs", "checkout.prd.json"))
+	if err != nil || d == nil || d.Fonts.MainFont != "Inter" {
+		t.Errorf("DefaultsFor = %+v, %v", d, err)
+	}
+
+	outside := t.TempDir()
+	writeFile(t, outside, "a.prd.json", `{}`)
+	var plain prd.Document
+	if _, err := decodeFile(filepath.Join(outside, "a.prd.json"), &plain, false); err != nil || plain.Metadata.Team != "" {
+		t.Errorf("document outside a workspace = %+v, %v", plain.Metadata, err)
+	}
+}
+
+func TestApplyDefaultsDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ManifestFile, `{
+		"defaults": {"authors": [{"name": "Jane Doe"}]},
+		"directory": {"file": "people.yaml"},
+		"documents": []
+	}`)
+	writeFile(t, root, "people.yaml", `people:
+  - name: Jane Doe
+    email: jane@example.com
+    role: Product Manager
+  - name: Sam Lee
+    email: sam@example.com
+    role: Engineering Lead
+`)
+	writeFile(t, root, "specs/checkout.prd.json", `{"metadata": {"approvers": [{"name": "Sam Lee", "approved": true}]}}`)
+
+	var p prd.Document
+	if _, err := decodeFile(filepath.Join(root, "specs", "checkout.prd.json"), &p, false); err != nil {
+		t.Fatal(err)
+	}
+	if a := p.Metadata.Authors; len(a) != 1 || a[0].Email != "jane@example.com" || a[0].Role != "Product Manager" {
+		t.Errorf("authors = %+v", a)
+	}
+	if a := p.Metadata.Approvers; len(a) != 1 || a[0].Email != "sam@example.com" || !a[0].Approved {
+		t.Errorf("approvers = %+v", a)
+	}
+
+	d, err := DirectoryFor(filepath.Join(root, "specs", "checkout.prd.json"))
+	if err != nil || d == nil || len(d.People) != 2 {
+		t.Errorf("DirectoryFor = %+v, %v", d, err)
+	}
+}
+
+func TestTechnologyPolicyFor(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ManifestFile, `{
+		"technologyPolicy": {"reference": "ENG-STD-12", "denied": [{"name": "MongoDB"}]},
+		"documents": []
+	}`)
+	p, err := TechnologyPolicyFor(filepath.Join(root, "specs", "checkout.trd.json"))
+	if err != nil || p == nil || p.Reference != "ENG-STD-12" || len(p.Denied) != 1 {
+		t.Errorf("TechnologyPolicyFor = %+v, %v", p, err)
+	}
+	if p, err := TechnologyPolicyFor(filepath.Join(t.TempDir(), "a.trd.json")); err != nil || p != nil {
+		t.Errorf("outside a workspace = %+v, %v", p, err)
+	}
+}
+
+func TestBuildLicenseReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.trd.json", `{
+		"metadata": {"id": "TRD-A"},
+		"technologyStack": {
+			"languages": [{"name": "Go", "license": "BSD-3-Clause"}],
+			"databases": [
+				{"name": "MySQL", "version": "8.0", "license": "GPL-2.0-only"},
+				{"name": "Aurora", "vendor": "AWS", "license": "Proprietary", "supportTier": "enterprise"}
+			],
+			"caching": [{"name": "Redis", "license": "RSALv2 OR SSPL-1.0"}],
+			"other": [{"name": "Internal SDK"}]
+		}
+	}`)
+	writeFile(t, dir, "b.trd.json", `{
+		"metadata": {"id": "TRD-B"},
+		"technologyStack": {"databases": [{"name": "mysql", "version": "8.0", "license": "GPL-2.0-only"}]}
+	}`)
+	writeFile(t, dir, "c.prd.json", `{"metadata": {"id": "PRD-C"}}`)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := BuildLicenseReport(paths, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Documents != 2 || r.Technologies != 6 {
+		t.Errorf("scanned %d documents, %d technologies", r.Documents, r.Technologies)
+	}
+	if len(r.Copyleft) != 3 || r.Copyleft[2].LicenseCategory != "copyleft" {
+		t.Errorf("copyleft = %+v", r.Copyleft)
+	}
+	if len(r.SingleVendor) != 1 || r.SingleVendor[0].Name != "Aurora" {
+		t.Errorf("single vendor = %+v", r.SingleVendor)
+	}
+	if len(r.UnknownLicense) != 1 || r.UnknownLicense[0].Name != "Internal SDK" {
+		t.Errorf("unknown = %+v", r.UnknownLicense)
+	}
+
+	md := r.ToMarkdown()
+	for _, want := range []string{
+		"Scanned 6 technology entries in 2 TRDs: 2 copyleft, 1 single-vendor, 1 with unknown licenses.",
+		"| MySQL 8.0 | GPL-2.0-only | copyleft | TRD-A, TRD-B |",
+		"| Redis | RSALv2 OR SSPL-1.0 | copyleft | TRD-A |",
+		"| Aurora | AWS | Proprietary | enterprise | TRD-A |",
+		"| Internal SDK | (none) | TRD-A |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 6 || !strings.HasPrefix(lines[4], "single-vendor,Aurora,") {
+		t.Errorf("CSV = %q", lines)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "plans")
+	writeFile(t, root, ManifestFile, `{
+		"documents": [{"path": "specs/checkout.prd.json"}],
+		"shared": ["../common/glossary.md"],
+		"defaults": {"approvers": [{"name": "Ana"}]}
+	}`)
+	checkout := `{
+  "metadata": {
+    "title": "Checkout",
+    "id": "PRD-1"
+  },
+  "x-review": {
+    "by": "Ana"
+  },
+  "personas": [
+    {
+      "id": "p-1",
+      "name": "Buyer",
+      "imageUrl": "../../common/buyer.png"
+    }
+  ],
+  "technicalArchitecture": {
+    "systemDiagram": "missing.png"
+  }
+}
+`
+	writeFile(t, root, "specs/checkout.prd.json", checkout)
+	writeFile(t, root, "goals/team.okr.json", `{"objectives": []}`)
+	writeFile(t, root, "specs/personas.json", `[]`)
+	writeFile(t, root, ".hidden/skip.prd.json", `{}`)
+	writeFile(t, base, "common/glossary.md", "# Glossary")
+	writeFile(t, base, "common/buyer.png", "PNG")
+
+	b, err := Export(filepath.Join(root, "specs"), ExportOptions{Now: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range b.Manifest.Files {
+		paths = append(paths, f.Kind+" "+f.Path)
+	}
+	want := []string{
+		"document goals/team.okr.json",
+		"asset external/buyer.png",
+		"document specs/checkout.prd.json",
+		"shared specs/personas.json",
+		"shared external/glossary.md",
+		"manifest " + ManifestFile,
+	}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Errorf("files:\n%s\nwant:\n%s", strings.Join(paths, "\n"), strings.Join(want, "\n"))
+	}
+	if len(b.Manifest.Skipped) != 1 || !strings.Contains(b.Manifest.Skipped[0].Reason, "technicalArchitecture.systemDiagram") {
+		t.Errorf("skipped = %+v", b.Manifest.Skipped)
+	}
+	if b.FileName() != "plans-20261017T000000Z.zip" {
+		t.Errorf("FileName = %q", b.FileName())
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := bundle.Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(base, "copy")
+	result, err := Import(read, dest, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 6 || result.Documents != 2 || result.Manifest != filepath.Join(dest, ManifestFile) {
+		t.Errorf("result = %+v", result)
+	}
+	m, err := LoadManifest(filepath.Join(dest, ManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Shared) != 1 || m.Shared[0] != "external/glossary.md" || m.Defaults == nil {
+		t.Errorf("imported manifest = %+v", m)
+	}
+	var doc prd.Document
+	data, err := os.ReadFile(filepath.Join(dest, "specs/checkout.prd.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Personas[0].ImageURL; got != "../external/buyer.png" {
+		t.Errorf("rewritten image = %q", got)
+	}
+	if got := doc.TechArchitecture.SystemDiagram; got != "missing.png" {
+		t.Errorf("missing asset reference = %q", got)
+	}
+	// Only the rewritten reference changes; member order and unknown members are kept
+	if want := strings.Replace(checkout, "../../common/buyer.png", "../external/buyer.png", 1); string(data) != want {
+		t.Errorf("exported document:\n%s\nwant:\n%s", data, want)
+	}
+
+	if _, err := Import(read, dest, ImportOptions{}); err == nil || !strings.Contains(err.Error(), "already exist") {
+		t.Errorf("importing over existing files: err = %v", err)
+	}
+	if _, err := Import(read, dest, ImportOptions{Force: true}); err != nil {
+		t.Errorf("importing with Force: %v", err)
+	}
+}
+
+func TestImportMerge(t *testing.T) {
+	base := t.TempDir()
+	b := bundle.New("plans", ArchiveType, time.Now())
+	for name, data := range map[string]string{
+		"specs/checkout.prd.json": `{}`,
+		"glossary.md":             "# Glossary",
+	} {
+		if err := b.Add(bundle.KindDocument, name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Add(bundle.KindWorkspaceManifest, ManifestFile, []byte(`{
+		"documents": [{"path": "specs/checkout.prd.json", "type": "prd", "markdown": "out/checkout.md"}],
+		"shared": ["glossary.md"],
+		"planning": {"fiscalYearStartMonth": 2}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Import(b, filepath.Join(base, "team"), ImportOptions{Merge: true}); err == nil {
+		t.Error("expected an error merging without a workspace manifest")
+	}
+
+	writeFile(t, base, ManifestFile, `{"documents": [{"path": "other.prd.json"}]}`)
+	result, err := Import(b, filepath.Join(base, "teams/payments"), ImportOptions{Merge: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 2 || len(result.NotMerged) != 1 || result.NotMerged[0] != "planning" {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(base, "teams/payments", ManifestFile)); err == nil {
+		t.Error("merge wrote the archive's manifest")
+	}
+
+	m, err := LoadManifest(filepath.Join(base, ManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Documents) != 2 || m.Documents[1].Path != "teams/payments/specs/checkout.prd.json" || m.Documents[1].Markdown != "teams/payments/out/checkout.md" {
+		t.Errorf("documents = %+v", m.Documents)
+	}
+	if len(m.Shared) != 1 || m.Shared[0] != "teams/payments/glossary.md" || m.Planning != nil {
+		t.Errorf("merged manifest = %+v", m)
+	}
+
+	// Merging again leaves the manifest as it is.
+	if _, err := Import(b, filepath.Join(base, "teams/payments"), ImportOptions{Merge: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := LoadManifest(filepath.Join(base, ManifestFile)); len(again.Documents) != 2 || len(again.Shared) != 1 {
+		t.Errorf("merging twice = %+v", again)
+	}
+}
+
+func TestIDsFor(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ManifestFile, `{"documents": [{"path": "../shared/company.okr.json"}]}`)
+	writeFile(t, dir, "goals/team.okr.json", `{"metadata": {"id": "OKR-T"}, "objectives": [{"id": "O-1"}]}`)
+	writeFile(t, dir, "broken.prd.json", `{`)
+	writeFile(t, filepath.Dir(dir), "shared/company.okr.json", `{"objectives": [{"id": "CO-1", "keyResults": [{"id": "CKR-1"}]}]}`)
+
+	known, err := IDsFor(filepath.Join(dir, "goals/team.okr.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"OKR-T", "O-1", "CO-1", "CKR-1"} {
+		if !known[id] {
+			t.Errorf("ID %s not found in %v", id, known)
+		}
+	}
+
+	if known, err := IDsFor(filepath.Join(t.TempDir(), "a.okr.json")); err != nil || known != nil {
+		t.Errorf("without a manifest = %v, %v", known, err)
+	}
+}
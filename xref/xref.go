@@ -0,0 +1,375 @@
+// Package xref links the IDs a document mentions to the generated documents
+// that define them. When a PRD names a TRD component or an MRD market
+// requirement listed in the workspace manifest, the generated Markdown and
+// HTML link the ID to the component or requirement in the generated TRD or
+// MRD instead of showing plain text.
+package xref
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/structured-plan/htmldoc"
+	"github.com/grokify/structured-plan/lenient"
+	"github.com/grokify/structured-plan/requirements/mrd"
+	"github.com/grokify/structured-plan/requirements/trd"
+	"github.com/grokify/structured-plan/workspace"
+)
+
+// Target is an item that IDs link to.
+type Target struct {
+	ID    string
+	Title string
+	Kind  workspace.Kind
+
+	// Markdown and HTML are the generated files that define the item, and
+	// MarkdownAnchor and HTMLAnchor its anchors in them.
+	Markdown       string
+	MarkdownAnchor string
+	HTML           string
+	HTMLAnchor     string
+}
+
+// Links maps IDs to their targets.
+type Links struct {
+	targets map[string]Target
+}
+
+// New returns an empty set of links.
+func New() *Links {
+	return &Links{targets: make(map[string]Target)}
+}
+
+// Load returns the links to the MRD market requirements and TRD components
+// of the documents in m. Documents that have not been created yet are
+// skipped.
+func Load(m *workspace.Manifest, lenientMode bool) (*Links, error) {
+	l := New()
+	for _, d := range m.Documents {
+		if d.Kind != workspace.KindMRD && d.Kind != workspace.KindTRD {
+			continue
+		}
+		data, err := os.ReadFile(m.Source(d))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", d.Path, err)
+		}
+		md, page := m.MarkdownOutput(d), m.HTMLOutput(d)
+		if d.Kind == workspace.KindMRD {
+			var doc mrd.Document
+			if err := decode(data, &doc, lenientMode); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", d.Path, err)
+			}
+			l.AddMRD(&doc, md, page)
+		} else {
+			var doc trd.Document
+			if err := decode(data, &doc, lenientMode); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", d.Path, err)
+			}
+			l.AddTRD(&doc, md, page)
+		}
+	}
+	return l, nil
+}
+
+func decode(data []byte, v any, lenientMode bool) error {
+	if lenientMode {
+		_, err := lenient.Unmarshal(data, v)
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// AddMRD adds the market requirements of doc, generated as the Markdown
+// file md and the HTML page page. Each links to its details heading in the
+// Markdown and to the Market Requirements section of the page.
+func (l *Links) AddMRD(doc *mrd.Document, md, page string) {
+	for _, r := range doc.MarketRequirements {
+		l.Add(Target{
+			ID:             r.ID,
+			Title:          r.Title,
+			Kind:           workspace.KindMRD,
+			Markdown:       md,
+			MarkdownAnchor: markdownAnchor(r.ID + ": " + r.Title),
+			HTML:           page,
+			HTMLAnchor:     htmldoc.Slug("Market Requirements"),
+		})
+	}
+}
+
+// AddTRD adds the architecture components of doc, generated as the
+// Markdown file md and the HTML page page. A component links to its details
+// heading in the Markdown, which is only written for components with
+// responsibilities, dependencies, requirements, or links, and otherwise to
+// the Components table.
+func (l *Links) AddTRD(doc *trd.Document, md, page string) {
+	for _, c := range doc.Architecture.Components {
+		anchor := markdownAnchor("2.4 Components")
+		if len(c.Responsibilities) > 0 || len(c.Dependencies) > 0 || len(c.Requirements) > 0 || len(c.ExternalRefs) > 0 {
+			anchor = markdownAnchor(c.ID + ": " + c.Name)
+		}
+		l.Add(Target{
+			ID:             c.ID,
+			Title:          c.Name,
+			Kind:           workspace.KindTRD,
+			Markdown:       md,
+			MarkdownAnchor: anchor,
+			HTML:           page,
+			HTMLAnchor:     htmldoc.Slug("architecture-Components"),
+		})
+	}
+}
+
+// Add adds a target. IDs that are not linked are ignored: those with
+// characters other than letters, digits, hyphens, and underscores, and
+// plain words such as "cache", which would match ordinary prose.
+func (l *Links) Add(t Target) {
+	if linkable(t.ID) {
+		l.targets[t.ID] = t
+	}
+}
+
+// Len returns the number of IDs linked.
+func (l *Links) Len() int {
+	return len(l.targets)
+}
+
+// Target returns the target of id.
+func (l *Links) Target(id string) (Target, bool) {
+	t, ok := l.targets[id]
+	return t, ok
+}
+
+func linkable(id string) bool {
+	if id == "" {
+		return false
+	}
+	plain := true
+	for _, r := range id {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '_':
+			plain = false
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		default:
+			return false
+		}
+	}
+	return !plain
+}
+
+// markdownAnchor returns the anchor GitHub and Pandoc give a heading:
+// lowercase, without punctuation other than hyphens and underscores, and
+// with spaces replaced by hyphens.
+func markdownAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			sb.WriteByte('-')
+		case r == '-', r == '_', r >= 'a' && r <= 'z', r >= '0' && r <= '9', r > 0x7f:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// href returns the link from the file from to target#anchor.
+func href(from, target, anchor string) string {
+	rel := target
+	if absFrom, err := filepath.Abs(from); err == nil {
+		if absTarget, err := filepath.Abs(target); err == nil {
+			if r, err := filepath.Rel(filepath.Dir(absFrom), absTarget); err == nil {
+				rel = r
+			}
+		}
+	}
+	return filepath.ToSlash(rel) + "#" + anchor
+}
+
+// linkIDs replaces the IDs in text with links written by link. An ID
+// matches a whole run of letters, digits, hyphens, and underscores that is
+// not part of a path, URL, or email address.
+func (l *Links) linkIDs(text string, link func(Target) string) string {
+	var sb strings.Builder
+	i := 0
+	for i < len(text) {
+		if !idChar(text[i]) {
+			sb.WriteByte(text[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(text) && idChar(text[j]) {
+			j++
+		}
+		word := text[i:j]
+		t, ok := l.targets[word]
+		if ok && (i == 0 || !strings.ContainsRune("/#.:@=", rune(text[i-1]))) &&
+			(j == len(text) || !strings.ContainsRune("/@=", rune(text[j]))) {
+			sb.WriteString(link(t))
+		} else {
+			sb.WriteString(word)
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+func idChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '-' || b == '_'
+}
+
+// Markdown links the IDs in the Markdown document md, to be written to the
+// file from, to the targets' generated Markdown. IDs in frontmatter,
+// headings, code, and existing links are left alone.
+func (l *Links) Markdown(md, from string) string {
+	if len(l.targets) == 0 {
+		return md
+	}
+	link := func(t Target) string {
+		return "[" + t.ID + "](" + href(from, t.Markdown, t.MarkdownAnchor) + ")"
+	}
+
+	lines := strings.SplitAfter(md, "\n")
+	frontmatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case frontmatter:
+			if i > 0 && trimmed == "---" {
+				frontmatter = false
+			}
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case strings.HasPrefix(trimmed, "#"):
+		default:
+			lines[i] = l.markdownLine(line, link)
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// markdownLine links the IDs in one line of Markdown outside code spans,
+// links, and autolinks.
+func (l *Links) markdownLine(line string, link func(Target) string) string {
+	var sb strings.Builder
+	plain := 0
+	flush := func(end int) {
+		sb.WriteString(l.linkIDs(line[plain:end], link))
+	}
+	for i := 0; i < len(line); i++ {
+		end := -1
+		switch line[i] {
+		case '`':
+			if k := strings.IndexByte(line[i+1:], '`'); k >= 0 {
+				end = i + k + 2
+			}
+		case '<':
+			if k := strings.IndexByte(line[i:], '>'); k >= 0 {
+				end = i + k + 1
+			}
+		case '[':
+			if k := strings.Index(line[i:], "]("); k >= 0 {
+				if m := strings.IndexByte(line[i+k:], ')'); m >= 0 {
+					end = i + k + m + 1
+				}
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		flush(i)
+		sb.WriteString(line[i:end])
+		plain = end
+		i = end - 1
+	}
+	flush(len(line))
+	return sb.String()
+}
+
+// skipElements are the HTML elements whose text is not linked.
+var skipElements = map[string]bool{
+	"a": true, "code": true, "pre": true, "script": true, "style": true, "head": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// HTML links the IDs in the HTML page, to be written to the file from, to
+// the targets' generated HTML. IDs in headings, code, and existing links
+// are left alone.
+func (l *Links) HTML(page []byte, from string) []byte {
+	if len(l.targets) == 0 {
+		return page
+	}
+	link := func(t Target) string {
+		return fmt.Sprintf(`<a class="xref" href="%s" title="%s">%s</a>`,
+			html.EscapeString(href(from, t.HTML, t.HTMLAnchor)), html.EscapeString(t.Title), t.ID)
+	}
+
+	s := string(page)
+	var sb strings.Builder
+	skip := map[string]int{}
+	skipping := 0
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			lt = len(s)
+		}
+		if skipping == 0 {
+			sb.WriteString(l.linkIDs(s[:lt], link))
+		} else {
+			sb.WriteString(s[:lt])
+		}
+		s = s[lt:]
+		if s == "" {
+			break
+		}
+		gt := strings.IndexByte(s, '>')
+		if gt < 0 {
+			sb.WriteString(s)
+			break
+		}
+		tag := s[:gt+1]
+		sb.WriteString(tag)
+		s = s[gt+1:]
+
+		name, closing := tagName(tag)
+		if !skipElements[name] || strings.HasSuffix(tag, "/>") {
+			continue
+		}
+		if closing {
+			if skip[name] > 0 {
+				skip[name]--
+				skipping--
+			}
+		} else {
+			skip[name]++
+			skipping++
+		}
+	}
+	return []byte(sb.String())
+}
+
+// tagName returns the lowercase element name of an HTML tag such as
+// `<a href="...">` or `</a>`, and whether it is a closing tag.
+func tagName(tag string) (string, bool) {
+	tag = strings.TrimPrefix(tag, "<")
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+	end := strings.IndexFunc(tag, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end < 0 {
+		end = len(tag)
+	}
+	return strings.ToLower(tag[:end]), closing
+}
@@ -0,0 +1,191 @@
+package xref
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/requirements/mrd"
+	mrdhtml "github.com/grokify/structured-plan/requirements/mrd/render/html"
+	"github.com/grokify/structured-plan/requirements/trd"
+	trdhtml "github.com/grokify/structured-plan/requirements/trd/render/html"
+	"github.com/grokify/structured-plan/workspace"
+)
+
+func testDocuments() (*mrd.Document, *trd.Document) {
+	m := &mrd.Document{MarketRequirements: []mrd.MarketRequirement{
+		{ID: "MR-1", Title: "Saved cards"},
+		{ID: "MR-2", Title: "Refunds (partial)"},
+	}}
+	t := &trd.Document{Architecture: trd.Architecture{Components: []trd.Component{
+		{ID: "card-vault", Name: "Card Vault", Requirements: []string{"FR-1"}},
+		{ID: "edge-cache", Name: "Edge Cache"},
+		{ID: "gateway", Name: "Gateway"},
+	}}}
+	return m, t
+}
+
+func testLinks() *Links {
+	m, t := testDocuments()
+	l := New()
+	l.AddMRD(m, "docs/market.mrd.md", "site/market.mrd.html")
+	l.AddTRD(t, "docs/tech/product.trd.md", "site/product.trd.html")
+	return l
+}
+
+// markdownAnchors returns the anchors of the headings in md.
+func markdownAnchors(md string) map[string]bool {
+	anchors := map[string]bool{}
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "#") {
+			anchors[markdownAnchor(strings.TrimLeft(line, "# "))] = true
+		}
+	}
+	return anchors
+}
+
+func TestAnchors(t *testing.T) {
+	m, tr := testDocuments()
+	l := testLinks()
+	if l.Len() != 4 {
+		t.Errorf("Len = %d, want 4 (plain word IDs are not linked)", l.Len())
+	}
+
+	mrdAnchors := markdownAnchors(m.ToMarkdown(mrd.DefaultMarkdownOptions()))
+	trdAnchors := markdownAnchors(tr.ToMarkdown(trd.DefaultMarkdownOptions()))
+	mrdPage, err := mrdhtml.New().Render(m, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trdPage, err := trdhtml.New().Render(tr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"MR-1", "MR-2", "card-vault", "edge-cache"} {
+		target, ok := l.Target(id)
+		if !ok {
+			t.Fatalf("no target for %s", id)
+		}
+		anchors, page := mrdAnchors, mrdPage
+		if target.Kind == workspace.KindTRD {
+			anchors, page = trdAnchors, trdPage
+		}
+		if !anchors[target.MarkdownAnchor] {
+			t.Errorf("%s: Markdown has no heading with anchor %q", id, target.MarkdownAnchor)
+		}
+		if !strings.Contains(string(page), `id="`+target.HTMLAnchor+`"`) {
+			t.Errorf("%s: HTML has no element with id %q", id, target.HTMLAnchor)
+		}
+	}
+	if target, _ := l.Target("card-vault"); target.MarkdownAnchor != "card-vault-card-vault" {
+		t.Errorf("card-vault anchor = %q", target.MarkdownAnchor)
+	}
+	if target, _ := l.Target("edge-cache"); target.MarkdownAnchor != "24-components" {
+		t.Errorf("edge-cache anchor = %q", target.MarkdownAnchor)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	l := testLinks()
+	md := strings.Join([]string{
+		"---",
+		"title: MR-1",
+		"---",
+		"# Checkout MR-1",
+		"",
+		"Stores cards in card-vault (see MR-1, MR-10).",
+		"| FR-1 | Uses `card-vault` and edge-cache | [MR-2](other.md) |",
+		"See https://example.com/MR-1 and docs/card-vault/README.md.",
+		"```",
+		"card-vault",
+		"```",
+		"gateway and MR-2.",
+		"",
+	}, "\n")
+	got := l.Markdown(md, "docs/product.prd.md")
+
+	want := strings.Join([]string{
+		"---",
+		"title: MR-1",
+		"---",
+		"# Checkout MR-1",
+		"",
+		"Stores cards in [card-vault](tech/product.trd.md#card-vault-card-vault) (see [MR-1](market.mrd.md#mr-1-saved-cards), MR-10).",
+		"| FR-1 | Uses `card-vault` and [edge-cache](tech/product.trd.md#24-components) | [MR-2](other.md) |",
+		"See https://example.com/MR-1 and docs/card-vault/README.md.",
+		"```",
+		"card-vault",
+		"```",
+		"gateway and [MR-2](market.mrd.md#mr-2-refunds-partial).",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("Markdown =\n%s\nwant\n%s", got, want)
+	}
+
+	if New().Markdown(md, "x.md") != md {
+		t.Error("empty links should leave Markdown unchanged")
+	}
+}
+
+func TestHTML(t *testing.T) {
+	l := testLinks()
+	page := `<html><head><title>MR-1</title></head><body>` +
+		`<h2>MR-1 <a class="anchor" href="#x">#</a></h2>` +
+		`<p>Uses card-vault for MR-1.</p>` +
+		`<td><code>MR-2</code></td><td><a href="y">edge-cache</a> and edge-cache</td>` +
+		`<br/>MR-2</body></html>`
+	got := string(l.HTML([]byte(page), "site/product.prd.html"))
+
+	want := `<html><head><title>MR-1</title></head><body>` +
+		`<h2>MR-1 <a class="anchor" href="#x">#</a></h2>` +
+		`<p>Uses <a class="xref" href="product.trd.html#architecture-components" title="Card Vault">card-vault</a> for ` +
+		`<a class="xref" href="market.mrd.html#market-requirements" title="Saved cards">MR-1</a>.</p>` +
+		`<td><code>MR-2</code></td><td><a href="y">edge-cache</a> and ` +
+		`<a class="xref" href="product.trd.html#architecture-components" title="Edge Cache">edge-cache</a></td>` +
+		`<br/><a class="xref" href="market.mrd.html#market-requirements" title="Refunds (partial)">MR-2</a></body></html>`
+	if got != want {
+		t.Errorf("HTML =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(workspace.ManifestFile, `{"documents": [
+		{"path": "market.mrd.json", "html": "site/market.html"},
+		{"path": "product.trd.json"},
+		{"path": "product.prd.json"},
+		{"path": "missing.trd.json"}
+	]}`)
+	write("market.mrd.json", `{"marketRequirements": [{"id": "MR-1", "title": "Saved cards"}]}`)
+	write("product.trd.json", `{"architecture": {"components": [{"id": "card-vault", "name": "Card Vault"}]}}`)
+
+	m, err := workspace.FindManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := Load(m, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", l.Len())
+	}
+	mr, _ := l.Target("MR-1")
+	if mr.Markdown != filepath.Join(dir, "market.mrd.md") || mr.HTML != filepath.Join(dir, "site", "market.html") {
+		t.Errorf("MR-1 target = %+v", mr)
+	}
+
+	write("product.trd.json", `{"architecture": `)
+	if _, err := Load(m, false); err == nil {
+		t.Error("expected an error for a malformed TRD")
+	}
+}
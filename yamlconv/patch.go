@@ -0,0 +1,371 @@
+package yamlconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+)
+
+// Patch applies the add, remove, and replace operations of a JSON Patch to
+// the source of a JSON or YAML document and returns the updated source.
+// Only the values the patch touches change: key order, members the patch
+// does not name, and YAML comments are kept, as are the number formats
+// and indentation of JSON sources. Values the patch adds are written in
+// the style FromJSON uses. The members of the objects it adds follow the
+// order of the object at the same path in order, a JSON document such as
+// the encoding of the struct the patch was computed from, when order is
+// not nil and has one; otherwise they are sorted.
+func Patch(data []byte, format Format, p jsonpatch.Patch, order []byte) ([]byte, error) {
+	root, err := parseSource(data, format)
+	if err != nil {
+		return nil, err
+	}
+	var orderRoot *yaml.Node
+	if order != nil {
+		if orderRoot, err = parseSource(order, FormatJSON); err != nil {
+			return nil, err
+		}
+	}
+	for i, op := range p {
+		if err := patchNode(root, orderRoot, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == FormatYAML {
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(root); err != nil {
+			return nil, fmt.Errorf("encoding YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("encoding YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	var compact bytes.Buffer
+	if err := writeJSON(&compact, root, true); err != nil {
+		return nil, err
+	}
+	if err := json.Indent(&buf, compact.Bytes(), "", sourceIndent(data)); err != nil {
+		return nil, fmt.Errorf("encoding JSON: %w", err)
+	}
+	if bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// parseSource parses a JSON or YAML document into a document node.
+func parseSource(data []byte, format Format) (*yaml.Node, error) {
+	if format == FormatYAML {
+		var doc yaml.Node
+		if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parsing YAML: %w", err)
+		}
+		if doc.Kind == 0 {
+			doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}}}
+		}
+		return &doc, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := readNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{n}}, nil
+}
+
+// sourceIndent returns the indentation of the first indented line of a
+// JSON document, or two spaces.
+func sourceIndent(data []byte) string {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line := data[i+1:]
+		if n := len(line) - len(bytes.TrimLeft(line, " \t")); n > 0 {
+			return string(line[:n])
+		}
+	}
+	return "  "
+}
+
+// patchNode applies one operation to the document node root. The objects
+// it adds take their member order from the document node order, if any.
+func patchNode(root, order *yaml.Node, op jsonpatch.Operation) error {
+	tokens, err := jsonpatch.ParsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+	var value *yaml.Node
+	switch op.Op {
+	case jsonpatch.OpAdd, jsonpatch.OpReplace:
+		if value, err = valueNode(op.Value); err != nil {
+			return err
+		}
+	case jsonpatch.OpRemove:
+	default:
+		return fmt.Errorf("unsupported operation %q", op.Op)
+	}
+
+	if len(tokens) == 0 {
+		if value == nil {
+			return fmt.Errorf("cannot remove the document root")
+		}
+		if order != nil {
+			arrange(value, order.Content[0])
+		}
+		root.Content = []*yaml.Node{keepComments(value, root.Content[0])}
+		return nil
+	}
+	parent := root.Content[0]
+	for n, t := range tokens[:len(tokens)-1] {
+		if parent = child(parent, t); parent == nil {
+			return fmt.Errorf("%s does not exist", jsonpatch.Pointer(anySlice(tokens[:n+1])...))
+		}
+	}
+	parent = resolveAlias(parent)
+	last := tokens[len(tokens)-1]
+	var like *yaml.Node // parent in order
+	if order != nil {
+		like = lookup(order, tokens[:len(tokens)-1])
+	}
+	if value != nil && like != nil {
+		at := last
+		if last == "-" && parent.Kind == yaml.SequenceNode {
+			at = strconv.Itoa(len(parent.Content))
+		}
+		arrange(value, child(like, at))
+	}
+
+	switch parent.Kind {
+	case yaml.MappingNode:
+		i := memberIndex(parent, last)
+		switch {
+		case value == nil && i < 0:
+			return fmt.Errorf("member %q does not exist", last)
+		case value == nil:
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+		case i >= 0:
+			parent.Content[i+1] = keepComments(value, parent.Content[i+1])
+		case op.Op == jsonpatch.OpReplace && child(parent, last) == nil:
+			return fmt.Errorf("member %q does not exist", last)
+		default:
+			// A member inherited through a merge key is overridden by an
+			// explicit one.
+			if len(parent.Content) == 0 {
+				parent.Style &^= yaml.FlowStyle
+			}
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last}
+			at := memberPosition(parent, like, last)
+			parent.Content = slices.Insert(parent.Content, at, key, value)
+		}
+		if len(parent.Content) == 0 {
+			parent.Style = yaml.FlowStyle
+		}
+		return nil
+
+	case yaml.SequenceNode:
+		n := len(parent.Content)
+		i := n
+		if op.Op != jsonpatch.OpAdd || last != "-" {
+			limit := n
+			if op.Op == jsonpatch.OpAdd {
+				limit++
+			}
+			if i, err = elementIndex(last, limit); err != nil {
+				return err
+			}
+		}
+		switch op.Op {
+		case jsonpatch.OpAdd:
+			parent.Content = append(parent.Content[:i], append([]*yaml.Node{value}, parent.Content[i:]...)...)
+			if n == 0 {
+				parent.Style &^= yaml.FlowStyle
+			}
+		case jsonpatch.OpReplace:
+			parent.Content[i] = keepComments(value, parent.Content[i])
+		case jsonpatch.OpRemove:
+			parent.Content = append(parent.Content[:i], parent.Content[i+1:]...)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is not an object or array", jsonpatch.Pointer(anySlice(tokens[:len(tokens)-1])...))
+}
+
+// valueNode returns v as a node, in the style FromJSON writes.
+func valueNode(v any) (*yaml.Node, error) {
+	var b bytes.Buffer
+	if err := writeValue(&b, v); err != nil {
+		return nil, fmt.Errorf("marshaling value: %w", err)
+	}
+	dec := json.NewDecoder(&b)
+	dec.UseNumber()
+	return readNode(dec)
+}
+
+// lookup returns the node at the path given by tokens in the document
+// node root, or nil.
+func lookup(root *yaml.Node, tokens []string) *yaml.Node {
+	n := root.Content[0]
+	for _, t := range tokens {
+		if n = child(n, t); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// memberPosition returns the index in parent.Content at which to add the
+// member key: after the last member that precedes it in like, the same
+// mapping in the order document, else before the first that follows it,
+// else at the end.
+func memberPosition(parent, like *yaml.Node, key string) int {
+	rank := memberRanks(like)
+	r, ok := rank[key]
+	if !ok {
+		return len(parent.Content)
+	}
+	at := -1
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		ri, ok := rank[parent.Content[i].Value]
+		switch {
+		case ok && ri < r:
+			at = i + 2
+		case ok && at < 0:
+			at = i
+		}
+	}
+	if at < 0 {
+		return len(parent.Content)
+	}
+	return at
+}
+
+// memberRanks returns the position of each key of the mapping n, which
+// may be nil.
+func memberRanks(n *yaml.Node) map[string]int {
+	rank := map[string]int{}
+	if n != nil && n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			rank[n.Content[i].Value] = i
+		}
+	}
+	return rank
+}
+
+// arrange orders the members of the mappings in n as in the node like,
+// which has the same shape. Members like lacks follow in their order.
+func arrange(n, like *yaml.Node) {
+	if like == nil || n.Kind != like.Kind {
+		return
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		rank := memberRanks(like)
+		pairs := make([][2]*yaml.Node, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{n.Content[i], n.Content[i+1]})
+		}
+		slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+			ra, aok := rank[a[0].Value]
+			rb, bok := rank[b[0].Value]
+			switch {
+			case aok && bok:
+				return ra - rb
+			case aok:
+				return -1
+			case bok:
+				return 1
+			}
+			return 0
+		})
+		n.Content = n.Content[:0]
+		for _, p := range pairs {
+			n.Content = append(n.Content, p[0], p[1])
+			arrange(p[1], child(like, p[0].Value))
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			if i < len(like.Content) {
+				arrange(c, like.Content[i])
+			}
+		}
+	}
+}
+
+// child returns the member or element of n named by token, or nil.
+func child(n *yaml.Node, token string) *yaml.Node {
+	n = resolveAlias(n)
+	switch n.Kind {
+	case yaml.MappingNode:
+		pairs, err := mappingPairs(n)
+		if err != nil {
+			return nil
+		}
+		for _, p := range pairs {
+			if p.key == token {
+				return p.value
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := elementIndex(token, len(n.Content)); err == nil {
+			return n.Content[i]
+		}
+	}
+	return nil
+}
+
+// memberIndex returns the index in n.Content of the last explicit key
+// named key, which is the one that takes effect, or -1.
+func memberIndex(n *yaml.Node, key string) int {
+	for i := len(n.Content) - 2; i >= 0; i -= 2 {
+		if k := resolveAlias(n.Content[i]); k.Kind == yaml.ScalarNode && k.ShortTag() != "!!merge" && k.Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// keepComments moves the comments of the node a value replaces to the
+// value.
+func keepComments(value, old *yaml.Node) *yaml.Node {
+	value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	return value
+}
+
+// elementIndex parses an array index, which must be below limit.
+func elementIndex(token string, limit int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+func anySlice(tokens []string) []any {
+	out := make([]any, len(tokens))
+	for i, t := range tokens {
+		out[i] = t
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package yamlconv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-plan/jsonpatch"
+)
+
+func TestPatchJSON(t *testing.T) {
+	in := `{
+    "id": "PRD-1",
+    "weight": 1.50,
+    "legacy": {"note": "R&D <beta>"},
+    "tags": ["a"],
+    "empty": []
+}
+`
+	patch := jsonpatch.Patch{
+		jsonpatch.Replace("/id", "PRD-2"),
+		jsonpatch.Add("/status", "draft"),
+		jsonpatch.Add("/tags/-", "b & c"),
+		jsonpatch.Add("/empty/-", 1),
+		jsonpatch.Add("/comments", []any{map[string]any{"author": "pat", "text": "ok"}}),
+	}
+	got, err := Patch([]byte(in), FormatJSON, patch, []byte(`{"id":"","status":"","weight":1,"tags":[],"empty":[],"comments":[{"text":"ok","author":"pat"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+    "id": "PRD-2",
+    "status": "draft",
+    "weight": 1.50,
+    "legacy": {
+        "note": "R&D <beta>"
+    },
+    "tags": [
+        "a",
+        "b & c"
+    ],
+    "empty": [
+        1
+    ],
+    "comments": [
+        {
+            "text": "ok",
+            "author": "pat"
+        }
+    ]
+}
+`
+	if string(got) != want {
+		t.Errorf("Patch() =\n%s\nwant\n%s", got, want)
+	}
+
+	for _, op := range []jsonpatch.Operation{
+		jsonpatch.Replace("/missing", "x"),
+		jsonpatch.Add("/missing/id", "x"),
+		jsonpatch.Remove("/tags/3"),
+		jsonpatch.Test("/id", "PRD-1"),
+	} {
+		if _, err := Patch([]byte(in), FormatJSON, jsonpatch.Patch{op}, nil); err == nil {
+			t.Errorf("%s %s: expected error", op.Op, op.Path)
+		}
+	}
+}
+
+func TestPatchYAML(t *testing.T) {
+	in := `# Checkout PRD
+metadata:
+  id: PRD-1 # assigned by the PMO
+  status: draft
+# Requirements follow.
+requirements:
+  - id: FR-1
+    title: Pay
+legacy: kept
+`
+	patch := jsonpatch.Patch{
+		jsonpatch.Replace("/metadata/status", "approved"),
+		jsonpatch.Add("/requirements/-", map[string]any{"id": "FR-2", "title": "Refund"}),
+		jsonpatch.Remove("/legacy"),
+	}
+	got, err := Patch([]byte(in), FormatYAML, patch, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Checkout PRD",
+		"id: PRD-1 # assigned by the PMO",
+		"status: approved",
+		"# Requirements follow.",
+		"  - id: FR-2\n    title: Refund",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("Patch() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), "legacy") {
+		t.Errorf("Patch() kept removed member:\n%s", got)
+	}
+}
@@ -0,0 +1,373 @@
+// Package yamlconv reads and writes planning documents as YAML.
+//
+// Documents are defined by their JSON encoding: struct tags, custom
+// unmarshalers, lenient parsing, and the JSON schemas all operate on JSON.
+// Rather than maintain parallel YAML tags, YAML input is converted to
+// equivalent JSON before decoding, and output is encoded as JSON and then
+// converted to YAML. Key order is preserved in both directions, and YAML
+// timestamps such as 2026-01-01 are kept as strings so that date fields
+// round-trip unchanged.
+package yamlconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a document file format.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// FormatFromPath returns FormatYAML for .yaml and .yml files and FormatJSON
+// otherwise.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// ParseFormat parses a format name. An empty name or "auto" returns "",
+// meaning the format should be detected from the file name.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return "", nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown format %q (valid: auto, json, yaml)", name)
+}
+
+// ToJSON converts a YAML document to JSON. Anchors, aliases, and merge keys
+// are expanded. Mapping keys must be scalars.
+func ToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return []byte("null"), nil
+		}
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, &doc, false); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FromJSON converts a JSON document to YAML, preserving key order. Strings
+// containing newlines are written as literal blocks.
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := readNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, fmt.Errorf("encoding YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes YAML data into v using v's JSON encoding.
+func Unmarshal(data []byte, v any) error {
+	j, err := ToJSON(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, v); err != nil {
+		return fmt.Errorf("decoding YAML: %w", err)
+	}
+	return nil
+}
+
+// Marshal encodes v as YAML using its JSON encoding.
+func Marshal(v any) ([]byte, error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling JSON: %w", err)
+	}
+	return FromJSON(j)
+}
+
+// ReadFile reads the YAML file at path into v.
+func ReadFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	return Unmarshal(data, v)
+}
+
+// WriteFile writes v to path as YAML.
+func WriteFile(path string, v any) error {
+	data, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes n to buf as compact JSON. With exact set, numbers that
+// are valid JSON keep their text, so 1.50 is not rewritten as 1.5.
+func writeJSON(buf *bytes.Buffer, n *yaml.Node, exact bool) error {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, n.Content[0], exact)
+	case yaml.AliasNode:
+		return writeJSON(buf, n.Alias, exact)
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, c := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, c, exact); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		pairs, err := mappingPairs(n)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i, p := range pairs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeValue(buf, p.key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSON(buf, p.value, exact); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.ScalarNode:
+		return writeScalar(buf, n, exact)
+	}
+	return fmt.Errorf("line %d: unsupported YAML node", n.Line)
+}
+
+func writeScalar(buf *bytes.Buffer, n *yaml.Node, exact bool) error {
+	var v any
+	switch n.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		v = b
+	case "!!int":
+		var i int64
+		if err := n.Decode(&i); err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		v = i
+	case "!!float":
+		if exact && json.Valid([]byte(n.Value)) {
+			buf.WriteString(n.Value)
+			return nil
+		}
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("line %d: %s cannot be represented in JSON", n.Line, n.Value)
+		}
+		v = f
+	default:
+		// Strings, timestamps, and binary values keep their source text.
+		v = n.Value
+	}
+	if err := writeValue(buf, v); err != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	return nil
+}
+
+// writeValue writes v to buf as JSON, leaving &, <, and > unescaped.
+func writeValue(buf *bytes.Buffer, v any) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+	return nil
+}
+
+type pair struct {
+	key   string
+	value *yaml.Node
+}
+
+// mappingPairs returns the key/value pairs of a mapping in order, expanding
+// merge keys. Explicit keys take precedence over merged ones.
+func mappingPairs(n *yaml.Node) ([]pair, error) {
+	var pairs []pair
+	index := map[string]int{}
+	var merged []*yaml.Node
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind == yaml.AliasNode {
+			k = k.Alias
+		}
+		if k.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: mapping keys must be scalars", k.Line)
+		}
+		if k.ShortTag() == "!!merge" {
+			merged = append(merged, v)
+			continue
+		}
+		if j, ok := index[k.Value]; ok {
+			pairs[j].value = v
+			continue
+		}
+		index[k.Value] = len(pairs)
+		pairs = append(pairs, pair{key: k.Value, value: v})
+	}
+
+	for _, m := range merged {
+		if m.Kind == yaml.AliasNode {
+			m = m.Alias
+		}
+		sources := []*yaml.Node{m}
+		if m.Kind == yaml.SequenceNode {
+			sources = m.Content
+		}
+		for _, src := range sources {
+			if src.Kind == yaml.AliasNode {
+				src = src.Alias
+			}
+			if src.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("line %d: merge value must be a mapping", src.Line)
+			}
+			inherited, err := mappingPairs(src)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range inherited {
+				if _, ok := index[p.key]; !ok {
+					index[p.key] = len(pairs)
+					pairs = append(pairs, p)
+				}
+			}
+		}
+	}
+	return pairs, nil
+}
+
+// readNode reads one JSON value from dec as a YAML node.
+func readNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				c, err := readNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, c)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			if len(n.Content) == 0 {
+				n.Style = yaml.FlowStyle
+			}
+			return n, nil
+		}
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for dec.More() {
+			kt, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := kt.(string)
+			v, err := readNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		if len(n.Content) == 0 {
+			n.Style = yaml.FlowStyle
+		}
+		return n, nil
+	case string:
+		n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+		if strings.Contains(strings.TrimRight(t, "\n"), "\n") {
+			n.Style = yaml.LiteralStyle
+		}
+		return n, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(string(t), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(t)}, nil
+	case bool:
+		v := "false"
+		if t {
+			v = "true"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: v}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
@@ -0,0 +1,125 @@
+package yamlconv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToJSON(t *testing.T) {
+	in := `
+defaults: &owner
+  owner: Platform Team
+  status: draft
+metadata:
+  id: PRD-001
+  createdAt: 2026-01-15T09:30:00Z
+  version: 1.10
+  tags: [checkout, mvp]
+risks:
+  - <<: *owner
+    id: R1
+    status: open
+    probability: 0x10
+    accepted: yes
+    notes: ~
+description: |
+  Line one
+  Line two
+`
+	got, err := ToJSON([]byte(in))
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	want := `{"defaults":{"owner":"Platform Team","status":"draft"},` +
+		`"metadata":{"id":"PRD-001","createdAt":"2026-01-15T09:30:00Z","version":1.1,"tags":["checkout","mvp"]},` +
+		`"risks":[{"id":"R1","status":"open","probability":16,"accepted":"yes","notes":null,"owner":"Platform Team"}],` +
+		`"description":"Line one\nLine two\n"}`
+	if string(got) != want {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := ToJSON([]byte("limit: .inf")); err == nil {
+		t.Error("expected error for infinity")
+	}
+	if _, err := ToJSON([]byte("a: [")); err == nil {
+		t.Error("expected error for malformed YAML")
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	in := `{"id":"PRD-001","version":"1.0","date":"2026-01-15","enabled":"true","count":3,"ratio":0.5,"tags":[],"empty":{},"body":"a\nb","none":null}`
+	got, err := FromJSON([]byte(in))
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	want := `id: PRD-001
+version: "1.0"
+date: "2026-01-15"
+enabled: "true"
+count: 3
+ratio: 0.5
+tags: []
+empty: {}
+body: |-
+  a
+  b
+none: null
+`
+	if string(got) != want {
+		t.Errorf("FromJSON() =\n%s\nwant\n%s", got, want)
+	}
+
+	back, err := ToJSON(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(back) != in {
+		t.Errorf("round trip =\n%s\nwant\n%s", back, in)
+	}
+}
+
+func TestReadWriteFile(t *testing.T) {
+	type doc struct {
+		Title     string    `json:"title"`
+		CreatedAt time.Time `json:"createdAt"`
+		Tags      []string  `json:"tags,omitempty"`
+	}
+	in := doc{Title: "Checkout", CreatedAt: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), Tags: []string{"mvp"}}
+
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	if err := WriteFile(path, in); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "title: Checkout\ncreatedAt: \"2026-01-15T00:00:00Z\"\n") {
+		t.Errorf("unexpected YAML:\n%s", data)
+	}
+
+	var out doc
+	if err := ReadFile(path, &out); err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("ReadFile() = %+v, want %+v", out, in)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for path, want := range map[string]Format{"a.prd.yaml": FormatYAML, "A.YML": FormatYAML, "a.prd.json": FormatJSON, "a": FormatJSON} {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+	for name, want := range map[string]Format{"": "", "auto": "", "JSON": FormatJSON, "yml": FormatYAML} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseFormat("toml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}